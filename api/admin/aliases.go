@@ -0,0 +1,56 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// AliasStore persists the aliases given to chains or VMs through the admin
+// API, so that they're restored when the node restarts. Aliases from the
+// genesis aren't stored. AliasStore is thread-safe.
+type AliasStore struct {
+	// Maps an alias to the ID it was given to
+	db database.Database
+}
+
+// NewAliasStore returns the aliases stored in [db]
+func NewAliasStore(db database.Database) *AliasStore {
+	return &AliasStore{db: db}
+}
+
+// Put stores that [alias] was given to [id]
+func (s *AliasStore) Put(id ids.ID, alias string) error {
+	return s.db.Put([]byte(alias), id[:])
+}
+
+// Has returns true if [alias] is stored
+func (s *AliasStore) Has(alias string) (bool, error) {
+	return s.db.Has([]byte(alias))
+}
+
+// Delete removes [alias] from the store
+func (s *AliasStore) Delete(alias string) error {
+	return s.db.Delete([]byte(alias))
+}
+
+// Aliases returns the stored aliases of each ID
+func (s *AliasStore) Aliases() (map[ids.ID][]string, error) {
+	iter := s.db.NewIterator()
+	defer iter.Release()
+
+	aliases := make(map[ids.ID][]string)
+	for iter.Next() {
+		alias := string(iter.Key())
+		id, err := ids.ToID(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse ID of alias %s: %w", alias, err)
+		}
+		aliases[id] = append(aliases[id], alias)
+	}
+	return aliases, iter.Error()
+}
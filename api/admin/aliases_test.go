@@ -0,0 +1,42 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestAliasStore(t *testing.T) {
+	assert := assert.New(t)
+	db := memdb.New()
+	store := NewAliasStore(db)
+
+	id1 := ids.GenerateTestID()
+	id2 := ids.GenerateTestID()
+	assert.NoError(store.Put(id1, "a"))
+	assert.NoError(store.Put(id1, "b"))
+	assert.NoError(store.Put(id2, "c"))
+
+	has, err := store.Has("a")
+	assert.NoError(err)
+	assert.True(has)
+	has, err = store.Has("d")
+	assert.NoError(err)
+	assert.False(has)
+
+	assert.NoError(store.Delete("a"))
+
+	// The aliases are kept in the database
+	aliases, err := NewAliasStore(db).Aliases()
+	assert.NoError(err)
+	assert.Equal(map[ids.ID][]string{
+		id1: {"b"},
+		id2: {"c"},
+	}, aliases)
+}
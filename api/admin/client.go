@@ -7,7 +7,10 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/rpc"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
 )
 
 // Client for the Avalanche Platform Info API Endpoint
@@ -50,6 +53,34 @@ func (c *Client) LockProfile() (bool, error) {
 	return res.Success, err
 }
 
+// EnableProfiling enables the pprof endpoints for [duration], rounded down to
+// the second. If [duration] is 0, they are enabled for 10 minutes. Returns
+// the time that they will be disabled at.
+func (c *Client) EnableProfiling(duration time.Duration) (time.Time, error) {
+	res := &ProfilingStatusReply{}
+	err := c.requester.SendRequest("enableProfiling", &EnableProfilingArgs{
+		Duration: cjson.Uint64(duration / time.Second),
+	}, res)
+	if err != nil || res.EnabledUntil == nil {
+		return time.Time{}, err
+	}
+	return *res.EnabledUntil, nil
+}
+
+// DisableProfiling disables the pprof endpoints
+func (c *Client) DisableProfiling() (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("disableProfiling", struct{}{}, res)
+	return res.Success, err
+}
+
+// GetProfilingStatus returns whether the pprof endpoints are enabled
+func (c *Client) GetProfilingStatus() (*ProfilingStatusReply, error) {
+	res := &ProfilingStatusReply{}
+	err := c.requester.SendRequest("getProfilingStatus", struct{}{}, res)
+	return res, err
+}
+
 // Alias ...
 func (c *Client) Alias(endpoint, alias string) (bool, error) {
 	res := &api.SuccessResponse{}
@@ -79,9 +110,190 @@ func (c *Client) GetChainAliases(chain string) ([]string, error) {
 	return res.Aliases, err
 }
 
+// RemoveChainAlias removes [alias], which was given to a chain by AliasChain
+func (c *Client) RemoveChainAlias(alias string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("removeChainAlias", &RemoveAliasArgs{
+		Alias: alias,
+	}, res)
+	return res.Success, err
+}
+
+// AliasVM gives [vm] the alias [alias]
+func (c *Client) AliasVM(vm, alias string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("aliasVM", &AliasVMArgs{
+		VM:    vm,
+		Alias: alias,
+	}, res)
+	return res.Success, err
+}
+
+// RemoveVMAlias removes [alias], which was given to a VM by AliasVM
+func (c *Client) RemoveVMAlias(alias string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("removeVMAlias", &RemoveAliasArgs{
+		Alias: alias,
+	}, res)
+	return res.Success, err
+}
+
+// GetVMAliases returns the aliases of [vm]
+func (c *Client) GetVMAliases(vm string) ([]string, error) {
+	res := &GetVMAliasesReply{}
+	err := c.requester.SendRequest("getVMAliases", &GetVMAliasesArgs{
+		VM: vm,
+	}, res)
+	return res.Aliases, err
+}
+
 // Stacktrace ...
 func (c *Client) Stacktrace() (bool, error) {
 	res := &api.SuccessResponse{}
 	err := c.requester.SendRequest("stacktrace", struct{}{}, res)
 	return res.Success, err
 }
+
+// SetLoggerLevel sets the levels of the logger named [loggerName], or of every
+// logger if [loggerName] is empty. Empty levels aren't changed.
+func (c *Client) SetLoggerLevel(loggerName, logLevel, displayLevel string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("setLoggerLevel", &SetLoggerLevelArgs{
+		LoggerName:   loggerName,
+		LogLevel:     logLevel,
+		DisplayLevel: displayLevel,
+	}, res)
+	return res.Success, err
+}
+
+// GetLoggerLevel returns the levels of the logger named [loggerName], or of
+// every logger if [loggerName] is empty
+func (c *Client) GetLoggerLevel(loggerName string) (map[string]LoggerLevels, error) {
+	res := &GetLoggerLevelReply{}
+	err := c.requester.SendRequest("getLoggerLevel", &GetLoggerLevelArgs{
+		LoggerName: loggerName,
+	}, res)
+	return res.LoggerLevels, err
+}
+
+// ExportChainSnapshot writes a snapshot of the state of [chain] to a new file
+// in the node's snapshot directory
+func (c *Client) ExportChainSnapshot(chain string) (*ExportChainSnapshotReply, error) {
+	res := &ExportChainSnapshotReply{}
+	err := c.requester.SendRequest("exportChainSnapshot", &ExportChainSnapshotArgs{
+		Chain: chain,
+	}, res)
+	return res, err
+}
+
+// ImportChainSnapshot stages [file], a snapshot in the node's snapshot
+// directory, to replace the state of [chain] when the node restarts
+func (c *Client) ImportChainSnapshot(chain, file string) (*ImportChainSnapshotReply, error) {
+	res := &ImportChainSnapshotReply{}
+	err := c.requester.SendRequest("importChainSnapshot", &ImportChainSnapshotArgs{
+		Chain: chain,
+		File:  file,
+	}, res)
+	return res, err
+}
+
+// DumpConsensusState returns a snapshot of the state of [chain]'s consensus
+// engine
+func (c *Client) DumpConsensusState(chain string) (interface{}, error) {
+	res := &DumpConsensusStateReply{}
+	err := c.requester.SendRequest("dumpConsensusState", &DumpConsensusStateArgs{
+		Chain: chain,
+	}, res)
+	return res.State, err
+}
+
+// GetMempool returns the transactions of [chain] that haven't been issued to
+// consensus yet
+func (c *Client) GetMempool(chain string) (*GetMempoolReply, error) {
+	res := &GetMempoolReply{}
+	err := c.requester.SendRequest("getMempool", &GetMempoolArgs{
+		Chain: chain,
+	}, res)
+	return res, err
+}
+
+// EvictTx removes [txID] from the mempool of [chain]
+func (c *Client) EvictTx(chain string, txID ids.ID) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("evictTx", &MempoolTxArgs{
+		Chain: chain,
+		TxID:  txID,
+	}, res)
+	return res.Success, err
+}
+
+// RegossipTx gossips the processing container of [chain] that includes [txID]
+// to the network again
+func (c *Client) RegossipTx(chain string, txID ids.ID) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("regossipTx", &MempoolTxArgs{
+		Chain: chain,
+		TxID:  txID,
+	}, res)
+	return res.Success, err
+}
+
+// CompactDatabase starts compacting the node's database. If [chain] is
+// non-empty, only the state of [chain] is compacted.
+func (c *Client) CompactDatabase(chain string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("compactDatabase", &CompactDatabaseArgs{
+		Chain: chain,
+	}, res)
+	return res.Success, err
+}
+
+// GetCompactionStatus returns the status of the compactions of the node's
+// database
+func (c *Client) GetCompactionStatus() (*GetCompactionStatusReply, error) {
+	res := &GetCompactionStatusReply{}
+	err := c.requester.SendRequest("getCompactionStatus", struct{}{}, res)
+	return res, err
+}
+
+// BackupDatabase writes a backup of the node's database to its backup target
+func (c *Client) BackupDatabase() (*BackupDatabaseReply, error) {
+	res := &BackupDatabaseReply{}
+	err := c.requester.SendRequest("backupDatabase", struct{}{}, res)
+	return res, err
+}
+
+// VerifyDatabase scans the database of [chain] for corrupted data and checks
+// the consistency of its state. If [repair], repairs what can be repaired.
+func (c *Client) VerifyDatabase(chain string, repair bool) (*VerifyDatabaseReply, error) {
+	res := &VerifyDatabaseReply{}
+	err := c.requester.SendRequest("verifyDatabase", &VerifyDatabaseArgs{
+		Chain:  chain,
+		Repair: repair,
+	}, res)
+	return res, err
+}
+
+// DiagnosticsBundle writes a bundle of diagnostics of the node to attach to
+// bug reports
+func (c *Client) DiagnosticsBundle() (*DiagnosticsBundleReply, error) {
+	res := &DiagnosticsBundleReply{}
+	err := c.requester.SendRequest("diagnosticsBundle", struct{}{}, res)
+	return res, err
+}
+
+// ReloadConfig reloads the part of the node's config that can be changed while
+// the node is running
+func (c *Client) ReloadConfig() (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("reloadConfig", struct{}{}, res)
+	return res.Success, err
+}
+
+// Restart hands the node's sockets over to a new node process and shuts the
+// node down
+func (c *Client) Restart() (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("restart", struct{}{}, res)
+	return res.Success, err
+}
@@ -0,0 +1,314 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+const (
+	// Max number of bytes of the end of each log file that are bundled
+	maxBundledLogBytes = 4 * 1024 * 1024
+
+	// How long to wait for the lock of a chain before its state dump is
+	// skipped. The lock of a stuck chain may never be released.
+	bundleLockTimeout = 5 * time.Second
+)
+
+var errLockTimeout = errors.New("timed out waiting for the chain's lock")
+
+// DiagnosticsConfig is where diagnostics bundles are collected from and
+// written to
+type DiagnosticsConfig struct {
+	// Directory that bundles are written to
+	Dir string
+	// Directory of the node's log files
+	LogDir string
+	// Settings the node was started with, with secrets redacted
+	Settings map[string]interface{}
+	// Metrics of the node. May be nil.
+	Metrics prometheus.Gatherer
+}
+
+// DiagnosticsBundleReply describes the written bundle
+type DiagnosticsBundleReply struct {
+	// Path of the file the bundle was written to
+	Path string `json:"path"`
+	// Parts of the bundle that couldn't be collected
+	Errors []string `json:"errors"`
+}
+
+// DiagnosticsBundle writes a gzipped tarball to attach to bug reports. It
+// holds the stacks of all goroutines, a heap profile, the end of each log
+// file, the state of every chain's consensus engine, the node's settings with
+// secrets redacted, and the current value of every metric.
+//
+// Parts that can't be collected are skipped and listed in the bundle's
+// errors.txt, so that a bundle can still be taken of a node that's stuck.
+func (service *Admin) DiagnosticsBundle(_ *http.Request, _ *struct{}, reply *DiagnosticsBundleReply) error {
+	service.log.Info("Admin: DiagnosticsBundle called")
+
+	if err := os.MkdirAll(service.diagnostics.Dir, perms.ReadWriteExecute); err != nil {
+		return fmt.Errorf("couldn't create diagnostics directory: %w", err)
+	}
+	created := time.Now().UTC()
+	bundlePath := filepath.Join(
+		service.diagnostics.Dir,
+		fmt.Sprintf("diagnostics-%s.tar.gz", created.Format("20060102-150405")),
+	)
+	f, err := os.OpenFile(bundlePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perms.ReadWrite)
+	if err != nil {
+		return fmt.Errorf("couldn't create diagnostics bundle: %w", err)
+	}
+
+	gz := gzip.NewWriter(f)
+	b := &bundle{
+		tw:      tar.NewWriter(gz),
+		created: created,
+	}
+	err = service.writeBundle(b)
+	if closeErr := b.tw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(bundlePath)
+		return fmt.Errorf("couldn't write diagnostics bundle: %w", err)
+	}
+
+	reply.Path = bundlePath
+	reply.Errors = b.errs
+	return nil
+}
+
+// bundle adds files to a tarball
+type bundle struct {
+	tw      *tar.Writer
+	created time.Time
+	// Parts of the bundle that couldn't be collected
+	errs []string
+}
+
+// add the file [name] with [contents] to the bundle
+func (b *bundle) add(name string, contents []byte) error {
+	err := b.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    perms.ReadWrite,
+		Size:    int64(len(contents)),
+		ModTime: b.created,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = b.tw.Write(contents)
+	return err
+}
+
+// collect adds the file [name] with the contents returned by [f] to the
+// bundle. If [f] fails, the file is skipped and its error is recorded.
+func (b *bundle) collect(name string, f func(io.Writer) error) error {
+	buf := bytes.Buffer{}
+	if err := f(&buf); err != nil {
+		b.errs = append(b.errs, fmt.Sprintf("%s: %s", name, err))
+		return nil
+	}
+	return b.add(name, buf.Bytes())
+}
+
+// writeBundle adds every part of the diagnostics to [b]. Only fails if the
+// bundle can't be written.
+func (service *Admin) writeBundle(b *bundle) error {
+	// Take the stacks first, before collecting the rest changes them
+	if err := b.collect("goroutines.txt", func(w io.Writer) error {
+		return pprof.Lookup("goroutine").WriteTo(w, 2)
+	}); err != nil {
+		return err
+	}
+	if err := b.collect("heap.profile", func(w io.Writer) error {
+		runtime.GC() // Get up-to-date statistics
+		return pprof.Lookup("heap").WriteTo(w, 0)
+	}); err != nil {
+		return err
+	}
+	if err := b.collect("settings.json", func(w io.Writer) error {
+		return writeJSON(w, service.diagnostics.Settings)
+	}); err != nil {
+		return err
+	}
+	if err := b.collect("metrics.txt", service.writeMetrics); err != nil {
+		return err
+	}
+	if err := service.bundleStateDumps(b); err != nil {
+		return err
+	}
+	if err := service.bundleLogs(b); err != nil {
+		return err
+	}
+
+	if len(b.errs) == 0 {
+		return nil
+	}
+	errs := bytes.Buffer{}
+	for _, err := range b.errs {
+		errs.WriteString(err)
+		errs.WriteString("\n")
+	}
+	return b.add("errors.txt", errs.Bytes())
+}
+
+// writeJSON writes [v] to [w] as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeMetrics writes the current value of every metric to [w] in the text
+// format that Prometheus scrapes
+func (service *Admin) writeMetrics(w io.Writer) error {
+	if service.diagnostics.Metrics == nil {
+		return errors.New("metrics aren't collected")
+	}
+	// Gather returns the metrics it could gather along with its error
+	metricFamilies, gatherErr := service.diagnostics.Metrics.Gather()
+	encoder := expfmt.NewEncoder(w, expfmt.FmtText)
+	for _, metricFamily := range metricFamilies {
+		if err := encoder.Encode(metricFamily); err != nil {
+			return err
+		}
+	}
+	return gatherErr
+}
+
+// bundleStateDumps adds the state of the consensus engine of each chain to
+// [b], named after the chain's primary alias
+func (service *Admin) bundleStateDumps(b *bundle) error {
+	service.enginesLock.RLock()
+	engines := make(map[ids.ID]common.Engine, len(service.engines))
+	for chainID, engine := range service.engines {
+		engines[chainID] = engine
+	}
+	service.enginesLock.RUnlock()
+
+	for chainID, engine := range engines {
+		name, err := service.chainManager.PrimaryAlias(chainID)
+		if err != nil {
+			name = chainID.String()
+		}
+		if err := b.collect(path.Join("consensus", name+".json"), func(w io.Writer) error {
+			state, err := dumpStateWithTimeout(engine, bundleLockTimeout)
+			if err != nil {
+				return err
+			}
+			return writeJSON(w, state)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type stateDump struct {
+	state interface{}
+	err   error
+}
+
+// dumpStateWithTimeout returns the state of [engine]. Fails if the lock of its
+// chain can't be grabbed within [timeout].
+func dumpStateWithTimeout(engine common.Engine, timeout time.Duration) (interface{}, error) {
+	dumper, ok := engine.(common.StateDumper)
+	if !ok {
+		return nil, errNoStateDump
+	}
+
+	// Buffered so that the state can be dumped after the timeout without
+	// blocking forever
+	result := make(chan stateDump, 1)
+	go func() {
+		ctx := engine.Context()
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		state, err := dumper.DumpState()
+		result <- stateDump{state: state, err: err}
+	}()
+
+	select {
+	case dump := <-result:
+		return dump.state, dump.err
+	case <-time.After(timeout):
+		return nil, errLockTimeout
+	}
+}
+
+// bundleLogs adds the end of each of the node's current log files to [b].
+// Rotated log files are skipped.
+func (service *Admin) bundleLogs(b *bundle) error {
+	logFiles, err := filepath.Glob(filepath.Join(service.diagnostics.LogDir, "*.log"))
+	if err != nil {
+		b.errs = append(b.errs, fmt.Sprintf("logs: %s", err))
+		return nil
+	}
+	for _, logFile := range logFiles {
+		logFile := logFile
+		name := path.Join("logs", filepath.Base(logFile))
+		if err := b.collect(name, func(w io.Writer) error {
+			return tailFile(w, logFile, maxBundledLogBytes)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tailFile writes up to the last [maxBytes] bytes of the file at [filename]
+// to [w]
+func tailFile(w io.Writer, filename string, maxBytes int64) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if offset := info.Size() - maxBytes; offset > 0 {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	_, err = io.CopyN(w, f, maxBytes)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,162 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// aliasManager is a chain manager that knows the primary aliases of chains
+type aliasManager struct {
+	chains.MockManager
+
+	aliases map[ids.ID]string
+}
+
+func (m aliasManager) PrimaryAlias(chainID ids.ID) (string, error) {
+	return m.aliases[chainID], nil
+}
+
+// readBundle returns the contents of the files in the bundle at [path] by
+// their name
+func readBundle(t *testing.T, path string) map[string]string {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = string(contents)
+	}
+}
+
+func TestDiagnosticsBundle(t *testing.T) {
+	assert := assert.New(t)
+
+	logDir := t.TempDir()
+	assert.NoError(ioutil.WriteFile(filepath.Join(logDir, "main.log"), []byte("started"), 0o600))
+	assert.NoError(ioutil.WriteFile(filepath.Join(logDir, "main.log.1"), []byte("rotated"), 0o600))
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "test_counter",
+		Help: "counter for testing",
+	})
+	assert.NoError(registry.Register(counter))
+	counter.Inc()
+
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	engine := &stateDumperEngine{state: "dumped"}
+	engine.ContextF = func() *snow.Context { return ctx }
+	otherCtx := snow.DefaultContextTest()
+	otherCtx.ChainID = ids.GenerateTestID()
+
+	service := &Admin{
+		log: logging.NoLog{},
+		chainManager: aliasManager{
+			aliases: map[ids.ID]string{
+				ctx.ChainID:      "X",
+				otherCtx.ChainID: "P",
+			},
+		},
+		engines: make(map[ids.ID]common.Engine),
+		diagnostics: DiagnosticsConfig{
+			Dir:      filepath.Join(t.TempDir(), "diagnostics"),
+			LogDir:   logDir,
+			Settings: map[string]interface{}{"db-encryption-key": "<redacted>"},
+			Metrics:  registry,
+		},
+	}
+	service.RegisterChain("X", ctx, engine)
+	service.RegisterChain("P", otherCtx, &common.EngineTest{})
+
+	reply := DiagnosticsBundleReply{}
+	assert.NoError(service.DiagnosticsBundle(nil, nil, &reply))
+	assert.True(strings.HasPrefix(reply.Path, service.diagnostics.Dir))
+
+	// The engine that can't dump its state is skipped
+	assert.Len(reply.Errors, 1)
+	assert.Contains(reply.Errors[0], "consensus/P.json")
+
+	files := readBundle(t, reply.Path)
+	assert.Contains(files["goroutines.txt"], "goroutine")
+	assert.NotEmpty(files["heap.profile"])
+	assert.Contains(files["settings.json"], `"db-encryption-key": "<redacted>"`)
+	assert.Contains(files["metrics.txt"], "test_counter 1")
+	assert.Equal("\"dumped\"\n", files["consensus/X.json"])
+	assert.NotContains(files, "consensus/P.json")
+	assert.Equal("started", files["logs/main.log"])
+	assert.NotContains(files, "logs/main.log.1")
+	assert.Contains(files["errors.txt"], "consensus/P.json")
+}
+
+func TestDumpStateWithTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := snow.DefaultContextTest()
+	engine := &stateDumperEngine{state: "dumped"}
+	engine.ContextF = func() *snow.Context { return ctx }
+
+	state, err := dumpStateWithTimeout(engine, time.Second)
+	assert.NoError(err)
+	assert.Equal("dumped", state)
+
+	// A stuck chain doesn't block the dump forever
+	ctx.Lock.Lock()
+	_, err = dumpStateWithTimeout(engine, 10*time.Millisecond)
+	assert.ErrorIs(err, errLockTimeout)
+	ctx.Lock.Unlock()
+}
+
+func TestTailFile(t *testing.T) {
+	assert := assert.New(t)
+
+	filename := filepath.Join(t.TempDir(), "test.log")
+	assert.NoError(ioutil.WriteFile(filename, []byte("0123456789"), 0o600))
+
+	buf := bytes.Buffer{}
+	assert.NoError(tailFile(&buf, filename, 4))
+	assert.Equal("6789", buf.String())
+
+	buf.Reset()
+	assert.NoError(tailFile(&buf, filename, 100))
+	assert.Equal("0123456789", buf.String())
+}
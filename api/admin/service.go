@@ -5,18 +5,27 @@ package admin
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
-
-	"github.com/gorilla/rpc/v2"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/openapi"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database/backup"
+	"github.com/ava-labs/avalanchego/database/compaction"
+	"github.com/ava-labs/avalanchego/database/verify"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/ava-labs/avalanchego/utils/profiler"
+	"github.com/ava-labs/avalanchego/vms"
 
 	cjson "github.com/ava-labs/avalanchego/utils/json"
 )
@@ -26,35 +35,110 @@ const (
 
 	// Name of file that stacktraces are written to
 	stacktraceFile = "stacktrace.txt"
+
+	// How long the profiling endpoints are enabled for if no duration is
+	// given
+	defaultProfilingDuration = 10 * time.Minute
 )
 
-var errAliasTooLong = errors.New("alias length is too long")
+var (
+	errAliasTooLong    = errors.New("alias length is too long")
+	errNotRuntimeAlias = errors.New("alias wasn't added through the admin API")
+	errUnknownChain    = errors.New("unknown chain")
+	errNoStateDump     = errors.New("chain's engine doesn't support dumping its state")
+	errNoMempool       = errors.New("chain doesn't expose its mempool")
+	errNoRegossip      = errors.New("chain's engine doesn't support re-gossiping transactions")
+	errTxNotPending    = errors.New("transaction isn't in the mempool")
+	errNoLevels        = errors.New("need to specify either the log level or the display level")
+	errTxNotIssued     = errors.New("transaction isn't in a processing container")
+
+	errProfilingTooLong = errors.New("profiling duration is too long")
+
+	_ chains.Registrant = &Admin{}
+)
 
 // Admin is the API service for node admin management
 type Admin struct {
 	log          logging.Logger
+	logFactory   logging.Factory
 	profiler     profiler.Profiler
+	profilerGate *profiler.Gate
 	chainManager chains.Manager
+	vmManager    vms.Manager
 	httpServer   *server.Server
+
+	// Aliases added through this API
+	chainAliases *AliasStore
+	vmAliases    *AliasStore
+
+	// Compacts the node's database
+	compactor *compaction.Compactor
+
+	// Where diagnostics bundles are collected from
+	diagnostics DiagnosticsConfig
+
+	// Reloads the reloadable part of the node's config
+	reloadConfig func() error
+
+	// Hands the node's sockets over to a new node process
+	restart func() error
+
+	// Chain ID --> the chain's consensus engine
+	enginesLock sync.RWMutex
+	engines     map[ids.ID]common.Engine
 }
 
 // NewService returns a new admin API service
-func NewService(log logging.Logger, chainManager chains.Manager, httpServer *server.Server, profileDir string) (*common.HTTPHandler, error) {
-	newServer := rpc.NewServer()
+func NewService(
+	log logging.Logger,
+	logFactory logging.Factory,
+	chainManager chains.Manager,
+	vmManager vms.Manager,
+	httpServer *server.Server,
+	profileDir string,
+	profilerGate *profiler.Gate,
+	chainAliases *AliasStore,
+	vmAliases *AliasStore,
+	compactor *compaction.Compactor,
+	diagnostics DiagnosticsConfig,
+	reloadConfig func() error,
+	restart func() error,
+) (*common.HTTPHandler, error) {
+	newServer := openapi.NewServer()
 	codec := cjson.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := newServer.RegisterService(&Admin{
+	service := &Admin{
 		log:          log,
+		logFactory:   logFactory,
 		chainManager: chainManager,
+		vmManager:    vmManager,
 		httpServer:   httpServer,
 		profiler:     profiler.New(profileDir),
-	}, "admin"); err != nil {
+		profilerGate: profilerGate,
+		chainAliases: chainAliases,
+		vmAliases:    vmAliases,
+		compactor:    compactor,
+		diagnostics:  diagnostics,
+		reloadConfig: reloadConfig,
+		restart:      restart,
+		engines:      make(map[ids.ID]common.Engine),
+	}
+	if err := newServer.RegisterService(service, "admin"); err != nil {
 		return nil, err
 	}
+	chainManager.AddRegistrant(service)
 	return &common.HTTPHandler{Handler: newServer}, nil
 }
 
+// RegisterChain implements the chains.Registrant interface
+func (service *Admin) RegisterChain(_ string, ctx *snow.Context, engine common.Engine) {
+	service.enginesLock.Lock()
+	defer service.enginesLock.Unlock()
+
+	service.engines[ctx.ChainID] = engine
+}
+
 // StartCPUProfiler starts a cpu profile writing to the specified file
 func (service *Admin) StartCPUProfiler(_ *http.Request, _ *struct{}, reply *api.SuccessResponse) error {
 	service.log.Info("Admin: StartCPUProfiler called")
@@ -86,6 +170,65 @@ func (service *Admin) LockProfile(_ *http.Request, _ *struct{}, reply *api.Succe
 	return service.profiler.LockProfile()
 }
 
+// EnableProfilingArgs are the arguments for calling EnableProfiling
+type EnableProfilingArgs struct {
+	// Number of seconds to enable the profiling endpoints for. Defaults to 10
+	// minutes.
+	Duration cjson.Uint64 `json:"duration"`
+}
+
+// ProfilingStatusReply is whether the profiling endpoints are enabled
+type ProfilingStatusReply struct {
+	Enabled bool `json:"enabled"`
+	// Time that the profiling endpoints will be disabled at, if they are
+	// enabled
+	EnabledUntil *time.Time `json:"enabledUntil,omitempty"`
+}
+
+// EnableProfiling enables the pprof endpoints under /ext/pprof, which serve
+// CPU, heap, and lock profiles and execution traces, for a limited duration.
+// If they are already enabled, they are disabled after the new duration
+// instead.
+func (service *Admin) EnableProfiling(_ *http.Request, args *EnableProfilingArgs, reply *ProfilingStatusReply) error {
+	service.log.Info("Admin: EnableProfiling called with Duration: %d", args.Duration)
+
+	if maxDuration := uint64(profiler.MaxGateDuration / time.Second); uint64(args.Duration) > maxDuration {
+		return fmt.Errorf("%w: %d seconds > %d seconds", errProfilingTooLong, args.Duration, maxDuration)
+	}
+	duration := time.Duration(args.Duration) * time.Second
+	if args.Duration == 0 {
+		duration = defaultProfilingDuration
+	}
+	enabledUntil, err := service.profilerGate.Enable(duration)
+	if err != nil {
+		return err
+	}
+	reply.Enabled = true
+	reply.EnabledUntil = &enabledUntil
+	return nil
+}
+
+// DisableProfiling disables the pprof endpoints
+func (service *Admin) DisableProfiling(_ *http.Request, _ *struct{}, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: DisableProfiling called")
+
+	service.profilerGate.Disable()
+	reply.Success = true
+	return nil
+}
+
+// GetProfilingStatus returns whether the pprof endpoints are enabled
+func (service *Admin) GetProfilingStatus(_ *http.Request, _ *struct{}, reply *ProfilingStatusReply) error {
+	service.log.Info("Admin: GetProfilingStatus called")
+
+	enabledUntil, enabled := service.profilerGate.EnabledUntil()
+	reply.Enabled = enabled
+	if enabled {
+		reply.EnabledUntil = &enabledUntil
+	}
+	return nil
+}
+
 // AliasArgs are the arguments for calling Alias
 type AliasArgs struct {
 	Endpoint string `json:"endpoint"`
@@ -110,7 +253,8 @@ type AliasChainArgs struct {
 	Alias string `json:"alias"`
 }
 
-// AliasChain attempts to alias a chain to a new name
+// AliasChain attempts to alias a chain to a new name. The alias is kept when
+// the node restarts.
 func (service *Admin) AliasChain(_ *http.Request, args *AliasChainArgs, reply *api.SuccessResponse) error {
 	service.log.Info("Admin: AliasChain called with Chain: %s, Alias: %s", args.Chain, args.Alias)
 
@@ -125,9 +269,47 @@ func (service *Admin) AliasChain(_ *http.Request, args *AliasChainArgs, reply *a
 	if err := service.chainManager.Alias(chainID, args.Alias); err != nil {
 		return err
 	}
+	if err := service.httpServer.AddAliasesWithReadLock("bc/"+chainID.String(), "bc/"+args.Alias); err != nil {
+		// Don't leave the alias resolving to an ID that isn't served at it
+		_ = service.chainManager.RemoveAlias(args.Alias)
+		return err
+	}
+	if err := service.chainAliases.Put(chainID, args.Alias); err != nil {
+		return fmt.Errorf("couldn't persist alias: %w", err)
+	}
+	reply.Success = true
+	return nil
+}
+
+// RemoveAliasArgs are the arguments for removing a chain or VM alias
+type RemoveAliasArgs struct {
+	Alias string `json:"alias"`
+}
+
+// RemoveChainAlias removes an alias that was given to a chain by AliasChain.
+// The chain's API is no longer served at the alias.
+func (service *Admin) RemoveChainAlias(_ *http.Request, args *RemoveAliasArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: RemoveChainAlias called with Alias: %s", args.Alias)
 
+	if err := service.checkRuntimeAlias(service.chainAliases, args.Alias); err != nil {
+		return err
+	}
+	chainID, err := service.chainManager.Lookup(args.Alias)
+	if err != nil {
+		return err
+	}
+
+	if err := service.chainManager.RemoveAlias(args.Alias); err != nil {
+		return err
+	}
+	if err := service.httpServer.RemoveAliasesWithReadLock("bc/"+chainID.String(), "bc/"+args.Alias); err != nil {
+		return err
+	}
+	if err := service.chainAliases.Delete(args.Alias); err != nil {
+		return fmt.Errorf("couldn't remove persisted alias: %w", err)
+	}
 	reply.Success = true
-	return service.httpServer.AddAliasesWithReadLock("bc/"+chainID.String(), "bc/"+args.Alias)
+	return nil
 }
 
 // GetChainAliasesArgs are the arguments for calling GetChainAliases
@@ -144,7 +326,7 @@ type GetChainAliasesReply struct {
 func (service *Admin) GetChainAliases(r *http.Request, args *GetChainAliasesArgs, reply *GetChainAliasesReply) error {
 	service.log.Info("Admin: GetChainAliases called with Chain: %s", args.Chain)
 
-	id, err := ids.FromString(args.Chain)
+	id, err := address.ParseID(args.Chain)
 	if err != nil {
 		return err
 	}
@@ -153,6 +335,102 @@ func (service *Admin) GetChainAliases(r *http.Request, args *GetChainAliasesArgs
 	return nil
 }
 
+// AliasVMArgs are the arguments for calling AliasVM
+type AliasVMArgs struct {
+	VM    string `json:"vm"`
+	Alias string `json:"alias"`
+}
+
+// AliasVM attempts to alias a VM to a new name. The alias is kept when the
+// node restarts.
+func (service *Admin) AliasVM(_ *http.Request, args *AliasVMArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: AliasVM called with VM: %s, Alias: %s", args.VM, args.Alias)
+
+	if len(args.Alias) > maxAliasLength {
+		return errAliasTooLong
+	}
+	vmID, err := service.vmManager.Lookup(args.VM)
+	if err != nil {
+		return err
+	}
+
+	if err := service.vmManager.Alias(vmID, args.Alias); err != nil {
+		return err
+	}
+	if err := service.httpServer.AddAliasesWithReadLock("vm/"+vmID.String(), "vm/"+args.Alias); err != nil {
+		// Don't leave the alias resolving to an ID that isn't served at it
+		_ = service.vmManager.RemoveAlias(args.Alias)
+		return err
+	}
+	if err := service.vmAliases.Put(vmID, args.Alias); err != nil {
+		return fmt.Errorf("couldn't persist alias: %w", err)
+	}
+	reply.Success = true
+	return nil
+}
+
+// RemoveVMAlias removes an alias that was given to a VM by AliasVM. The VM's
+// static API is no longer served at the alias.
+func (service *Admin) RemoveVMAlias(_ *http.Request, args *RemoveAliasArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: RemoveVMAlias called with Alias: %s", args.Alias)
+
+	if err := service.checkRuntimeAlias(service.vmAliases, args.Alias); err != nil {
+		return err
+	}
+	vmID, err := service.vmManager.Lookup(args.Alias)
+	if err != nil {
+		return err
+	}
+
+	if err := service.vmManager.RemoveAlias(args.Alias); err != nil {
+		return err
+	}
+	if err := service.httpServer.RemoveAliasesWithReadLock("vm/"+vmID.String(), "vm/"+args.Alias); err != nil {
+		return err
+	}
+	if err := service.vmAliases.Delete(args.Alias); err != nil {
+		return fmt.Errorf("couldn't remove persisted alias: %w", err)
+	}
+	reply.Success = true
+	return nil
+}
+
+// GetVMAliasesArgs are the arguments for calling GetVMAliases
+type GetVMAliasesArgs struct {
+	VM string `json:"vm"`
+}
+
+// GetVMAliasesReply are the aliases of the given VM
+type GetVMAliasesReply struct {
+	Aliases []string `json:"aliases"`
+}
+
+// GetVMAliases returns the aliases of the VM
+func (service *Admin) GetVMAliases(_ *http.Request, args *GetVMAliasesArgs, reply *GetVMAliasesReply) error {
+	service.log.Info("Admin: GetVMAliases called with VM: %s", args.VM)
+
+	vmID, err := service.vmManager.Lookup(args.VM)
+	if err != nil {
+		return err
+	}
+	reply.Aliases = service.vmManager.Aliases(vmID)
+	return nil
+}
+
+// checkRuntimeAlias returns an error if [alias] wasn't added through this API,
+// so that aliases the node relies on, such as the ones from the genesis,
+// can't be removed
+func (service *Admin) checkRuntimeAlias(store *AliasStore, alias string) error {
+	isRuntimeAlias, err := store.Has(alias)
+	if err != nil {
+		return fmt.Errorf("couldn't look up persisted alias: %w", err)
+	}
+	if !isRuntimeAlias {
+		return fmt.Errorf("%w: %s", errNotRuntimeAlias, alias)
+	}
+	return nil
+}
+
 // Stacktrace returns the current global stacktrace
 func (service *Admin) Stacktrace(_ *http.Request, _ *struct{}, reply *api.SuccessResponse) error {
 	service.log.Info("Admin: Stacktrace called")
@@ -161,3 +439,496 @@ func (service *Admin) Stacktrace(_ *http.Request, _ *struct{}, reply *api.Succes
 	stacktrace := []byte(logging.Stacktrace{Global: true}.String())
 	return perms.WriteFile(stacktraceFile, stacktrace, perms.ReadWrite)
 }
+
+// SetLoggerLevelArgs are the arguments for calling SetLoggerLevel
+type SetLoggerLevelArgs struct {
+	// Name of the logger to change. If empty, every logger is changed.
+	// Chains log to a logger named after the primary alias of the chain.
+	LoggerName   string `json:"loggerName"`
+	LogLevel     string `json:"logLevel"`
+	DisplayLevel string `json:"displayLevel"`
+}
+
+// SetLoggerLevel changes the levels of a logger until the node restarts. This
+// allows verbose logging to be enabled for just the subsystem that a problem
+// is being chased in. Levels that are left empty aren't changed.
+func (service *Admin) SetLoggerLevel(_ *http.Request, args *SetLoggerLevelArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: SetLoggerLevel called with LoggerName: %q, LogLevel: %q, DisplayLevel: %q",
+		args.LoggerName,
+		args.LogLevel,
+		args.DisplayLevel,
+	)
+
+	if args.LogLevel == "" && args.DisplayLevel == "" {
+		return errNoLevels
+	}
+	// Parse both levels before changing either, so that a bad level doesn't
+	// leave the logger half changed
+	var logLevel, displayLevel logging.Level
+	if args.LogLevel != "" {
+		level, err := logging.ToLevel(args.LogLevel)
+		if err != nil {
+			return err
+		}
+		logLevel = level
+	}
+	if args.DisplayLevel != "" {
+		level, err := logging.ToLevel(args.DisplayLevel)
+		if err != nil {
+			return err
+		}
+		displayLevel = level
+	}
+
+	if args.LogLevel != "" {
+		if err := service.logFactory.SetLogLevel(args.LoggerName, logLevel); err != nil {
+			return err
+		}
+	}
+	if args.DisplayLevel != "" {
+		if err := service.logFactory.SetDisplayLevel(args.LoggerName, displayLevel); err != nil {
+			return err
+		}
+	}
+	reply.Success = true
+	return nil
+}
+
+// GetLoggerLevelArgs are the arguments for calling GetLoggerLevel
+type GetLoggerLevelArgs struct {
+	// Name of the logger. If empty, the levels of every logger are returned.
+	LoggerName string `json:"loggerName"`
+}
+
+// LoggerLevels are the levels of a logger
+type LoggerLevels struct {
+	LogLevel     string `json:"logLevel"`
+	DisplayLevel string `json:"displayLevel"`
+}
+
+// GetLoggerLevelReply are the levels of the requested loggers
+type GetLoggerLevelReply struct {
+	// Logger name --> the levels of the logger
+	LoggerLevels map[string]LoggerLevels `json:"loggerLevels"`
+}
+
+// GetLoggerLevel returns the levels of a logger, or of every logger
+func (service *Admin) GetLoggerLevel(_ *http.Request, args *GetLoggerLevelArgs, reply *GetLoggerLevelReply) error {
+	service.log.Info("Admin: GetLoggerLevel called with LoggerName: %q", args.LoggerName)
+
+	levels, err := service.logFactory.GetLogLevels(args.LoggerName)
+	if err != nil {
+		return err
+	}
+	reply.LoggerLevels = make(map[string]LoggerLevels, len(levels))
+	for name, level := range levels {
+		reply.LoggerLevels[name] = LoggerLevels{
+			LogLevel:     strings.TrimSpace(level.LogLevel.String()),
+			DisplayLevel: strings.TrimSpace(level.DisplayLevel.String()),
+		}
+	}
+	return nil
+}
+
+// ExportChainSnapshotArgs are the arguments for calling ExportChainSnapshot
+type ExportChainSnapshotArgs struct {
+	Chain string `json:"chain"`
+}
+
+// ExportChainSnapshotReply describes the exported snapshot
+type ExportChainSnapshotReply struct {
+	ChainID ids.ID `json:"chainID"`
+	// Path of the file the snapshot was written to
+	Path string `json:"path"`
+	// Number of key/value pairs in the snapshot
+	NumKeys cjson.Uint64 `json:"numKeys"`
+}
+
+// ExportChainSnapshot writes a consistent snapshot of the state of a chain,
+// including its containers, transactions and VM state, to a new file in the
+// snapshot directory
+func (service *Admin) ExportChainSnapshot(_ *http.Request, args *ExportChainSnapshotArgs, reply *ExportChainSnapshotReply) error {
+	service.log.Info("Admin: ExportChainSnapshot called with Chain: %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	path, numKeys, err := service.chainManager.ExportSnapshot(chainID)
+	if err != nil {
+		return err
+	}
+	reply.ChainID = chainID
+	reply.Path = path
+	reply.NumKeys = cjson.Uint64(numKeys)
+	return nil
+}
+
+// ImportChainSnapshotArgs are the arguments for calling ImportChainSnapshot
+type ImportChainSnapshotArgs struct {
+	Chain string `json:"chain"`
+	// Name of the snapshot's file in the snapshot directory
+	File string `json:"file"`
+}
+
+// ImportChainSnapshotReply describes the staged snapshot
+type ImportChainSnapshotReply struct {
+	ChainID ids.ID `json:"chainID"`
+	// Number of key/value pairs in the snapshot
+	NumKeys cjson.Uint64 `json:"numKeys"`
+}
+
+// ImportChainSnapshot checks a snapshot in the snapshot directory and stages
+// it to replace the state of a chain when the node restarts. The chain then
+// bootstraps from the snapshot's state.
+func (service *Admin) ImportChainSnapshot(_ *http.Request, args *ImportChainSnapshotArgs, reply *ImportChainSnapshotReply) error {
+	service.log.Info("Admin: ImportChainSnapshot called with Chain: %s, File: %s", args.Chain, args.File)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	numKeys, err := service.chainManager.ImportSnapshot(chainID, args.File)
+	if err != nil {
+		return err
+	}
+	reply.ChainID = chainID
+	reply.NumKeys = cjson.Uint64(numKeys)
+	return nil
+}
+
+// BackupDatabaseReply describes the backup that was written
+type BackupDatabaseReply struct {
+	// Name of the backup in the backup target
+	Name string `json:"name"`
+	// Time the backup was taken
+	Time time.Time `json:"time"`
+	// Number of key/value pairs in the backup
+	NumKeys cjson.Uint64 `json:"numKeys"`
+	// Hex encoded SHA-256 hash of the backup's data
+	Checksum string `json:"checksum"`
+	// Accepted frontier of each chain when the backup was taken
+	Chains []backup.ChainFrontier `json:"chains"`
+}
+
+// BackupDatabase writes a backup of the node's database, which holds the
+// state of every chain, to the backup target. The chains keep running while
+// the backup is written. The backup can be restored with --db-restore-backup
+// when the node starts.
+func (service *Admin) BackupDatabase(_ *http.Request, _ *struct{}, reply *BackupDatabaseReply) error {
+	service.log.Info("Admin: BackupDatabase called")
+
+	name, manifest, err := service.chainManager.Backup()
+	if err != nil {
+		return err
+	}
+	reply.Name = name
+	reply.Time = manifest.Time
+	reply.NumKeys = cjson.Uint64(manifest.NumKeys)
+	reply.Checksum = manifest.Checksum
+	reply.Chains = manifest.Chains
+	return nil
+}
+
+// VerifyDatabaseArgs are the arguments for calling VerifyDatabase
+type VerifyDatabaseArgs struct {
+	Chain string `json:"chain"`
+	// If true, the issues that can be repaired are repaired
+	Repair bool `json:"repair"`
+}
+
+// VerifyDatabaseReply is the result of the verification of a chain's state
+type VerifyDatabaseReply struct {
+	ChainID ids.ID `json:"chainID"`
+	// Report of the scan of the chain's database for corrupted data, followed
+	// by the report of each consistency check of the chain's state
+	Reports []*verify.Report `json:"reports"`
+}
+
+// VerifyDatabase scans the database of a chain for corrupted data and checks
+// the consistency of the chain's state, optionally repairing what it can. The
+// chain doesn't process anything while it's verified.
+func (service *Admin) VerifyDatabase(_ *http.Request, args *VerifyDatabaseArgs, reply *VerifyDatabaseReply) error {
+	service.log.Info("Admin: VerifyDatabase called with Chain: %s, Repair: %t", args.Chain, args.Repair)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	reports, err := service.chainManager.VerifyDB(chainID, args.Repair)
+	if err != nil {
+		return err
+	}
+	reply.ChainID = chainID
+	reply.Reports = reports
+	return nil
+}
+
+// DumpConsensusStateArgs are the arguments for calling DumpConsensusState
+type DumpConsensusStateArgs struct {
+	Chain string `json:"chain"`
+}
+
+// DumpConsensusStateReply is the state of a chain's consensus engine
+type DumpConsensusStateReply struct {
+	ChainID ids.ID      `json:"chainID"`
+	State   interface{} `json:"state"`
+}
+
+// DumpConsensusState returns a snapshot of the state of the consensus engine
+// of the chain, including the processing containers, the preferences, the
+// frontier, the outstanding polls and the blocked operations
+func (service *Admin) DumpConsensusState(_ *http.Request, args *DumpConsensusStateArgs, reply *DumpConsensusStateReply) error {
+	service.log.Info("Admin: DumpConsensusState called with Chain: %s", args.Chain)
+
+	chainID, engine, err := service.getEngine(args.Chain)
+	if err != nil {
+		return err
+	}
+	dumper, ok := engine.(common.StateDumper)
+	if !ok {
+		return fmt.Errorf("%w: %s", errNoStateDump, chainID)
+	}
+
+	ctx := engine.Context()
+	ctx.Lock.Lock()
+	state, err := dumper.DumpState()
+	ctx.Lock.Unlock()
+	if err != nil {
+		return fmt.Errorf("couldn't dump state of chain %s: %w", chainID, err)
+	}
+
+	reply.ChainID = chainID
+	reply.State = state
+	return nil
+}
+
+// getEngine returns the ID and the consensus engine of the chain with ID or
+// alias [chain]
+func (service *Admin) getEngine(chain string) (ids.ID, common.Engine, error) {
+	chainID, err := service.chainManager.Lookup(chain)
+	if err != nil {
+		return ids.ID{}, nil, err
+	}
+
+	service.enginesLock.RLock()
+	engine, ok := service.engines[chainID]
+	service.enginesLock.RUnlock()
+	if !ok {
+		return ids.ID{}, nil, fmt.Errorf("%w: %s", errUnknownChain, chain)
+	}
+	return chainID, engine, nil
+}
+
+// getMempool returns the mempool of the chain with ID or alias [chain]. The
+// mempool of the engine is preferred over the mempool of the VM.
+func (service *Admin) getMempool(chain string) (ids.ID, common.Engine, common.Mempool, error) {
+	chainID, engine, err := service.getEngine(chain)
+	if err != nil {
+		return ids.ID{}, nil, nil, err
+	}
+	if mempool, ok := engine.(common.Mempool); ok {
+		return chainID, engine, mempool, nil
+	}
+	if mempool, ok := engine.GetVM().(common.Mempool); ok {
+		return chainID, engine, mempool, nil
+	}
+	return ids.ID{}, nil, nil, fmt.Errorf("%w: %s", errNoMempool, chainID)
+}
+
+// GetMempoolArgs are the arguments for calling GetMempool
+type GetMempoolArgs struct {
+	Chain string `json:"chain"`
+}
+
+// GetMempoolReply are the transactions of a chain that haven't been issued to
+// consensus yet
+type GetMempoolReply struct {
+	ChainID ids.ID       `json:"chainID"`
+	NumTxs  cjson.Uint64 `json:"numTxs"`
+	// Total size of the transactions, in bytes
+	Size cjson.Uint64       `json:"size"`
+	Txs  []common.MempoolTx `json:"txs"`
+}
+
+// GetMempool returns the transactions of the chain that haven't been issued to
+// consensus yet
+func (service *Admin) GetMempool(_ *http.Request, args *GetMempoolArgs, reply *GetMempoolReply) error {
+	service.log.Info("Admin: GetMempool called with Chain: %s", args.Chain)
+
+	chainID, engine, mempool, err := service.getMempool(args.Chain)
+	if err != nil {
+		return err
+	}
+
+	ctx := engine.Context()
+	ctx.Lock.Lock()
+	txs := mempool.MempoolTxs()
+	ctx.Lock.Unlock()
+
+	reply.ChainID = chainID
+	reply.NumTxs = cjson.Uint64(len(txs))
+	reply.Txs = txs
+	for _, tx := range txs {
+		reply.Size += cjson.Uint64(tx.Size)
+	}
+	return nil
+}
+
+// MempoolTxArgs are the arguments for calling methods that act on a
+// transaction of a chain
+type MempoolTxArgs struct {
+	Chain string `json:"chain"`
+	TxID  ids.ID `json:"txID"`
+}
+
+// EvictTx removes the transaction from the mempool of the chain, so that it
+// won't be issued to consensus
+func (service *Admin) EvictTx(_ *http.Request, args *MempoolTxArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: EvictTx called with Chain: %s, TxID: %s", args.Chain, args.TxID)
+
+	chainID, engine, mempool, err := service.getMempool(args.Chain)
+	if err != nil {
+		return err
+	}
+
+	ctx := engine.Context()
+	ctx.Lock.Lock()
+	evicted := mempool.EvictTx(args.TxID)
+	ctx.Lock.Unlock()
+	if !evicted {
+		return fmt.Errorf("%w: %s of chain %s", errTxNotPending, args.TxID, chainID)
+	}
+
+	reply.Success = true
+	return nil
+}
+
+// RegossipTx gossips the processing container that includes the transaction
+// to the network again
+func (service *Admin) RegossipTx(_ *http.Request, args *MempoolTxArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: RegossipTx called with Chain: %s, TxID: %s", args.Chain, args.TxID)
+
+	chainID, engine, err := service.getEngine(args.Chain)
+	if err != nil {
+		return err
+	}
+	regossiper, ok := engine.(common.TxRegossiper)
+	if !ok {
+		return fmt.Errorf("%w: %s", errNoRegossip, chainID)
+	}
+
+	ctx := engine.Context()
+	ctx.Lock.Lock()
+	gossiped, err := regossiper.RegossipTx(args.TxID)
+	ctx.Lock.Unlock()
+	if err != nil {
+		return fmt.Errorf("couldn't gossip tx %s of chain %s: %w", args.TxID, chainID, err)
+	}
+	if !gossiped {
+		return fmt.Errorf("%w: %s of chain %s", errTxNotIssued, args.TxID, chainID)
+	}
+
+	reply.Success = true
+	return nil
+}
+
+// CompactDatabaseArgs are the arguments for calling CompactDatabase
+type CompactDatabaseArgs struct {
+	// If provided, only the state of this chain is compacted
+	Chain string `json:"chain"`
+}
+
+// CompactDatabase starts compacting the node's database, or only the state of
+// a chain. Compactions reclaim the space of deleted and overwritten values and
+// reduce the number of files that reads need to check. The compaction runs in
+// the background; its progress is reported by GetCompactionStatus.
+func (service *Admin) CompactDatabase(_ *http.Request, args *CompactDatabaseArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: CompactDatabase called with Chain: %s", args.Chain)
+
+	var prefix []byte
+	if args.Chain != "" {
+		chainID, err := service.chainManager.Lookup(args.Chain)
+		if err != nil {
+			return err
+		}
+		// The state of a chain is stored under its ID
+		prefix = chainID[:]
+	}
+	if err := service.compactor.CompactAsync(prefix); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// GetCompactionStatusReply is the response from calling GetCompactionStatus
+type GetCompactionStatusReply struct {
+	// True if a compaction is in progress
+	Compacting bool `json:"compacting"`
+	// Time the last compaction finished, if there was one
+	LastCompaction *time.Time `json:"lastCompaction,omitempty"`
+	// Duration of the last compaction, in nanoseconds
+	LastDuration cjson.Uint64 `json:"lastDuration"`
+	// Error of the last compaction, if it failed
+	LastError string `json:"lastError,omitempty"`
+	// Estimated number of bytes that compactions need to write before the
+	// database is compacted. Omitted if the database doesn't report it.
+	CompactionDebt *cjson.Uint64 `json:"compactionDebt,omitempty"`
+	// Number of sorted runs that a read may need to check. Omitted if the
+	// database doesn't report it.
+	ReadAmplification *cjson.Uint64 `json:"readAmplification,omitempty"`
+}
+
+// GetCompactionStatus returns whether the node's database is being compacted,
+// how the last compaction went and how much compaction the database is behind
+// on
+func (service *Admin) GetCompactionStatus(_ *http.Request, _ *struct{}, reply *GetCompactionStatusReply) error {
+	service.log.Info("Admin: GetCompactionStatus called")
+
+	status := service.compactor.Status()
+	reply.Compacting = status.Compacting
+	if !status.LastCompaction.IsZero() {
+		lastCompaction := status.LastCompaction.UTC()
+		reply.LastCompaction = &lastCompaction
+	}
+	reply.LastDuration = cjson.Uint64(status.LastDuration)
+	if status.LastErr != nil {
+		reply.LastError = status.LastErr.Error()
+	}
+	if status.Stats != nil {
+		debt := cjson.Uint64(status.Stats.Debt)
+		readAmplification := cjson.Uint64(status.Stats.ReadAmplification)
+		reply.CompactionDebt = &debt
+		reply.ReadAmplification = &readAmplification
+	}
+	return nil
+}
+
+// ReloadConfig reads the node's config file and flags again and applies the
+// part of the config that can be changed while the node is running: the log
+// levels, the API rate limits, the outbound connection throttling and the
+// gossip sizes. The node's peers aren't dropped.
+func (service *Admin) ReloadConfig(_ *http.Request, _ *struct{}, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: ReloadConfig called")
+
+	if err := service.reloadConfig(); err != nil {
+		return fmt.Errorf("couldn't reload config: %w", err)
+	}
+	reply.Success = true
+	return nil
+}
+
+// Restart starts a new node process with the same executable and arguments,
+// hands the node's listening sockets over to it and shuts this node down, so
+// that an upgraded binary can be run without refusing connections. The new
+// process starts once this one has exited.
+func (service *Admin) Restart(_ *http.Request, _ *struct{}, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: Restart called")
+
+	if err := service.restart(); err != nil {
+		return fmt.Errorf("couldn't restart: %w", err)
+	}
+	reply.Success = true
+	return nil
+}
@@ -0,0 +1,377 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database/compaction"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/profiler"
+	"github.com/ava-labs/avalanchego/vms"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+type stateDumperEngine struct {
+	common.EngineTest
+
+	state interface{}
+	err   error
+}
+
+func (e *stateDumperEngine) DumpState() (interface{}, error) { return e.state, e.err }
+
+func TestDumpConsensusState(t *testing.T) {
+	assert := assert.New(t)
+
+	service := &Admin{
+		log:          logging.NoLog{},
+		chainManager: chains.MockManager{},
+		engines:      make(map[ids.ID]common.Engine),
+	}
+
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	engine := &stateDumperEngine{state: "state"}
+	engine.ContextF = func() *snow.Context { return ctx }
+	service.RegisterChain("chain", ctx, engine)
+
+	reply := DumpConsensusStateReply{}
+	err := service.DumpConsensusState(nil, &DumpConsensusStateArgs{Chain: ctx.ChainID.String()}, &reply)
+	assert.NoError(err)
+	assert.Equal(ctx.ChainID, reply.ChainID)
+	assert.Equal("state", reply.State)
+
+	engine.err = errors.New("dump failed")
+	err = service.DumpConsensusState(nil, &DumpConsensusStateArgs{Chain: ctx.ChainID.String()}, &reply)
+	assert.ErrorIs(err, engine.err)
+
+	err = service.DumpConsensusState(nil, &DumpConsensusStateArgs{Chain: ids.GenerateTestID().String()}, &reply)
+	assert.ErrorIs(err, errUnknownChain)
+
+	// Engines that can't dump their state should be reported
+	otherCtx := snow.DefaultContextTest()
+	otherCtx.ChainID = ids.GenerateTestID()
+	service.RegisterChain("other", otherCtx, &common.EngineTest{})
+	err = service.DumpConsensusState(nil, &DumpConsensusStateArgs{Chain: otherCtx.ChainID.String()}, &reply)
+	assert.ErrorIs(err, errNoStateDump)
+}
+
+// testMempool is a mempool of transactions with the given IDs
+type testMempool struct {
+	txs []ids.ID
+}
+
+func (m *testMempool) MempoolTxs() []common.MempoolTx {
+	txs := make([]common.MempoolTx, len(m.txs))
+	for i, txID := range m.txs {
+		txs[i] = common.MempoolTx{ID: txID, Size: 10}
+	}
+	return txs
+}
+
+func (m *testMempool) EvictTx(txID ids.ID) bool {
+	for i, pendingTxID := range m.txs {
+		if pendingTxID == txID {
+			m.txs = append(m.txs[:i], m.txs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type mempoolEngine struct {
+	common.EngineTest
+	testMempool
+
+	// Txs that are in processing containers
+	processing ids.Set
+}
+
+func (e *mempoolEngine) RegossipTx(txID ids.ID) (bool, error) {
+	return e.processing.Contains(txID), nil
+}
+
+type mempoolVM struct {
+	common.TestVM
+	testMempool
+}
+
+func TestMempool(t *testing.T) {
+	assert := assert.New(t)
+
+	service := &Admin{
+		log:          logging.NoLog{},
+		chainManager: chains.MockManager{},
+		engines:      make(map[ids.ID]common.Engine),
+	}
+
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	pendingTxID := ids.GenerateTestID()
+	processingTxID := ids.GenerateTestID()
+	engine := &mempoolEngine{
+		testMempool: testMempool{txs: []ids.ID{pendingTxID}},
+		processing:  ids.Set{},
+	}
+	engine.processing.Add(processingTxID)
+	engine.ContextF = func() *snow.Context { return ctx }
+	service.RegisterChain("chain", ctx, engine)
+
+	reply := GetMempoolReply{}
+	assert.NoError(service.GetMempool(nil, &GetMempoolArgs{Chain: ctx.ChainID.String()}, &reply))
+	assert.Equal(ctx.ChainID, reply.ChainID)
+	assert.EqualValues(1, reply.NumTxs)
+	assert.EqualValues(10, reply.Size)
+	assert.Equal(pendingTxID, reply.Txs[0].ID)
+
+	// Only processing txs can be gossiped again
+	regossipArgs := &MempoolTxArgs{Chain: ctx.ChainID.String(), TxID: processingTxID}
+	assert.NoError(service.RegossipTx(nil, regossipArgs, &api.SuccessResponse{}))
+	regossipArgs.TxID = pendingTxID
+	assert.ErrorIs(service.RegossipTx(nil, regossipArgs, &api.SuccessResponse{}), errTxNotIssued)
+
+	// Only pending txs can be evicted
+	evictArgs := &MempoolTxArgs{Chain: ctx.ChainID.String(), TxID: pendingTxID}
+	assert.NoError(service.EvictTx(nil, evictArgs, &api.SuccessResponse{}))
+	assert.ErrorIs(service.EvictTx(nil, evictArgs, &api.SuccessResponse{}), errTxNotPending)
+	assert.NoError(service.GetMempool(nil, &GetMempoolArgs{Chain: ctx.ChainID.String()}, &reply))
+	assert.EqualValues(0, reply.NumTxs)
+
+	// The mempool of the VM is used if the engine doesn't have one
+	vmCtx := snow.DefaultContextTest()
+	vmCtx.ChainID = ids.GenerateTestID()
+	vm := &mempoolVM{testMempool: testMempool{txs: []ids.ID{pendingTxID}}}
+	vmEngine := &common.EngineTest{}
+	vmEngine.ContextF = func() *snow.Context { return vmCtx }
+	vmEngine.GetVMF = func() common.VM { return vm }
+	service.RegisterChain("vmChain", vmCtx, vmEngine)
+
+	assert.NoError(service.GetMempool(nil, &GetMempoolArgs{Chain: vmCtx.ChainID.String()}, &reply))
+	assert.EqualValues(1, reply.NumTxs)
+	err := service.RegossipTx(nil, &MempoolTxArgs{Chain: vmCtx.ChainID.String(), TxID: pendingTxID}, &api.SuccessResponse{})
+	assert.ErrorIs(err, errNoRegossip)
+
+	// Chains without a mempool should be reported
+	otherCtx := snow.DefaultContextTest()
+	otherCtx.ChainID = ids.GenerateTestID()
+	otherEngine := &common.EngineTest{}
+	otherEngine.GetVMF = func() common.VM { return &common.TestVM{} }
+	service.RegisterChain("other", otherCtx, otherEngine)
+	err = service.GetMempool(nil, &GetMempoolArgs{Chain: otherCtx.ChainID.String()}, &reply)
+	assert.ErrorIs(err, errNoMempool)
+}
+
+func TestProfiling(t *testing.T) {
+	assert := assert.New(t)
+
+	service := &Admin{
+		log:          logging.NoLog{},
+		profilerGate: profiler.NewGate(logging.NoLog{}),
+	}
+	defer service.profilerGate.Disable()
+
+	status := ProfilingStatusReply{}
+	assert.NoError(service.GetProfilingStatus(nil, nil, &status))
+	assert.False(status.Enabled)
+	assert.Nil(status.EnabledUntil)
+
+	err := service.EnableProfiling(nil, &EnableProfilingArgs{Duration: cjson.Uint64(2 * profiler.MaxGateDuration / time.Second)}, &status)
+	assert.ErrorIs(err, errProfilingTooLong)
+
+	// Without a duration, profiling is enabled for the default duration
+	start := time.Now()
+	assert.NoError(service.EnableProfiling(nil, &EnableProfilingArgs{}, &status))
+	assert.True(status.Enabled)
+	assert.False(status.EnabledUntil.Before(start.Add(defaultProfilingDuration)))
+
+	status = ProfilingStatusReply{}
+	assert.NoError(service.GetProfilingStatus(nil, nil, &status))
+	assert.True(status.Enabled)
+	assert.NotNil(status.EnabledUntil)
+
+	reply := api.SuccessResponse{}
+	assert.NoError(service.DisableProfiling(nil, nil, &reply))
+	assert.True(reply.Success)
+
+	status = ProfilingStatusReply{}
+	assert.NoError(service.GetProfilingStatus(nil, nil, &status))
+	assert.False(status.Enabled)
+}
+
+func TestRemoveAliasesNotAddedAtRuntime(t *testing.T) {
+	assert := assert.New(t)
+
+	vmManager := vms.NewManager(nil, logging.NoLog{})
+	vmID := ids.GenerateTestID()
+	assert.NoError(vmManager.RegisterFactory(vmID, &testFactory{}))
+	assert.NoError(vmManager.Alias(vmID, "genesisVM"))
+
+	service := &Admin{
+		log:          logging.NoLog{},
+		chainManager: chains.MockManager{},
+		vmManager:    vmManager,
+		chainAliases: NewAliasStore(memdb.New()),
+		vmAliases:    NewAliasStore(memdb.New()),
+	}
+
+	reply := api.SuccessResponse{}
+	err := service.RemoveChainAlias(nil, &RemoveAliasArgs{Alias: "X"}, &reply)
+	assert.ErrorIs(err, errNotRuntimeAlias)
+	err = service.RemoveVMAlias(nil, &RemoveAliasArgs{Alias: "genesisVM"}, &reply)
+	assert.ErrorIs(err, errNotRuntimeAlias)
+	assert.False(reply.Success)
+
+	aliasesReply := GetVMAliasesReply{}
+	assert.NoError(service.GetVMAliases(nil, &GetVMAliasesArgs{VM: "genesisVM"}, &aliasesReply))
+	assert.Equal([]string{vmID.String(), "genesisVM"}, aliasesReply.Aliases)
+}
+
+// testFactory creates VMs that don't have a static API
+type testFactory struct{}
+
+func (f *testFactory) New(*snow.Context) (interface{}, error) { return nil, nil }
+
+func TestCompactDatabase(t *testing.T) {
+	assert := assert.New(t)
+
+	compactor, err := compaction.New(logging.NoLog{}, memdb.New(), compaction.Config{}, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	service := &Admin{
+		log:          logging.NoLog{},
+		chainManager: chains.MockManager{},
+		compactor:    compactor,
+	}
+
+	reply := api.SuccessResponse{}
+	err = service.CompactDatabase(nil, &CompactDatabaseArgs{Chain: ids.GenerateTestID().String()}, &reply)
+	assert.NoError(err)
+	assert.True(reply.Success)
+
+	status := GetCompactionStatusReply{}
+	assert.Eventually(func() bool {
+		assert.NoError(service.GetCompactionStatus(nil, nil, &status))
+		return status.LastCompaction != nil
+	}, time.Second, time.Millisecond)
+	assert.False(status.Compacting)
+	assert.Empty(status.LastError)
+	// memdb doesn't report its compaction stats
+	assert.Nil(status.CompactionDebt)
+}
+
+func TestSetLoggerLevel(t *testing.T) {
+	assert := assert.New(t)
+
+	config, err := logging.DefaultConfig()
+	assert.NoError(err)
+	config.Directory = t.TempDir()
+	config.LogLevel = logging.Info
+	config.DisplayLevel = logging.Info
+	logFactory := logging.NewFactory(config)
+	defer logFactory.Close()
+
+	_, err = logFactory.Make("network")
+	assert.NoError(err)
+	_, err = logFactory.MakeChain("X")
+	assert.NoError(err)
+
+	service := &Admin{
+		log:        logging.NoLog{},
+		logFactory: logFactory,
+	}
+
+	// Only the levels that are given are changed
+	reply := api.SuccessResponse{}
+	assert.NoError(service.SetLoggerLevel(nil, &SetLoggerLevelArgs{LoggerName: "X", LogLevel: "verbo"}, &reply))
+	assert.True(reply.Success)
+
+	levelsReply := GetLoggerLevelReply{}
+	assert.NoError(service.GetLoggerLevel(nil, &GetLoggerLevelArgs{}, &levelsReply))
+	assert.Equal(map[string]LoggerLevels{
+		"network": {LogLevel: "INFO", DisplayLevel: "INFO"},
+		"X":       {LogLevel: "VERBO", DisplayLevel: "INFO"},
+	}, levelsReply.LoggerLevels)
+
+	// Without a logger name, every logger is changed
+	assert.NoError(service.SetLoggerLevel(nil, &SetLoggerLevelArgs{DisplayLevel: "warn"}, &reply))
+	levelsReply = GetLoggerLevelReply{}
+	assert.NoError(service.GetLoggerLevel(nil, &GetLoggerLevelArgs{LoggerName: "network"}, &levelsReply))
+	assert.Equal(map[string]LoggerLevels{
+		"network": {LogLevel: "INFO", DisplayLevel: "WARN"},
+	}, levelsReply.LoggerLevels)
+
+	err = service.SetLoggerLevel(nil, &SetLoggerLevelArgs{LoggerName: "X"}, &reply)
+	assert.ErrorIs(err, errNoLevels)
+	err = service.SetLoggerLevel(nil, &SetLoggerLevelArgs{LoggerName: "X", LogLevel: "debug", DisplayLevel: "loud"}, &reply)
+	assert.Error(err)
+	err = service.SetLoggerLevel(nil, &SetLoggerLevelArgs{LoggerName: "Y", LogLevel: "debug"}, &reply)
+	assert.Error(err)
+
+	// A bad display level doesn't change the log level either
+	levelsReply = GetLoggerLevelReply{}
+	assert.NoError(service.GetLoggerLevel(nil, &GetLoggerLevelArgs{LoggerName: "X"}, &levelsReply))
+	assert.Equal("VERBO", levelsReply.LoggerLevels["X"].LogLevel)
+}
+
+func TestReloadConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	reloaded := false
+	service := &Admin{
+		log: logging.NoLog{},
+		reloadConfig: func() error {
+			reloaded = true
+			return nil
+		},
+	}
+
+	reply := api.SuccessResponse{}
+	assert.NoError(service.ReloadConfig(nil, nil, &reply))
+	assert.True(reply.Success)
+	assert.True(reloaded)
+
+	errReload := errors.New("invalid config")
+	service.reloadConfig = func() error { return errReload }
+	reply = api.SuccessResponse{}
+	err := service.ReloadConfig(nil, nil, &reply)
+	assert.ErrorIs(err, errReload)
+	assert.False(reply.Success)
+}
+
+func TestRestart(t *testing.T) {
+	assert := assert.New(t)
+
+	restarted := false
+	service := &Admin{
+		log: logging.NoLog{},
+		restart: func() error {
+			restarted = true
+			return nil
+		},
+	}
+
+	reply := api.SuccessResponse{}
+	assert.NoError(service.Restart(nil, nil, &reply))
+	assert.True(reply.Success)
+	assert.True(restarted)
+
+	errRestart := errors.New("no sockets")
+	service.restart = func() error { return errRestart }
+	reply = api.SuccessResponse{}
+	err := service.Restart(nil, nil, &reply)
+	assert.ErrorIs(err, errRestart)
+	assert.False(reply.Success)
+}
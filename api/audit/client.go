@@ -0,0 +1,37 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+)
+
+// Client for the Avalanche Audit API Endpoint
+type Client struct {
+	requester rpc.EndpointRequester
+}
+
+// NewClient returns a new Audit API Client
+func NewClient(uri string, requestTimeout time.Duration) *Client {
+	return &Client{
+		requester: rpc.NewEndpointRequester(uri, "/ext/audit", "audit", requestTimeout),
+	}
+}
+
+// GetEntries returns up to [numToFetch] entries of the audit log, starting at
+// [startIndex], that were recorded for calls to [endpoint] and [method]. An
+// empty [endpoint] or [method] matches every call.
+func (c *Client) GetEntries(startIndex, numToFetch uint64, endpoint, method string) (*GetEntriesReply, error) {
+	res := &GetEntriesReply{}
+	err := c.requester.SendRequest("getEntries", &GetEntriesArgs{
+		StartIndex: json.Uint64(startIndex),
+		NumToFetch: json.Uint64(numToFetch),
+		Endpoint:   endpoint,
+		Method:     method,
+	}, res)
+	return res, err
+}
@@ -0,0 +1,153 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/api/openapi"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	_ http.Handler       = &handler{}
+	_ openapi.Documented = &documentedHandler{}
+)
+
+// request is the part of a JSON-RPC request that is recorded. The
+// parameters are only inspected for a username, so that secrets such as
+// passwords are never written to the log.
+type request struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type userParams struct {
+	Username string `json:"username"`
+}
+
+// response is the part of a JSON-RPC response that is recorded
+type response struct {
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// handler records every call made to the wrapped handler in an audit log
+type handler struct {
+	log      logging.Logger
+	auditLog *Log
+	endpoint string
+	handler  http.Handler
+}
+
+// documentedHandler is a handler whose wrapped handler is documented
+type documentedHandler struct {
+	*handler
+	documented openapi.Documented
+}
+
+func (h *documentedHandler) Services() []openapi.Service {
+	return h.documented.Services()
+}
+
+// WrapHandler returns a handler that records every call made to [h], which is
+// served at [endpoint], in [auditLog]. If [h] is documented, so is the
+// returned handler.
+func WrapHandler(log logging.Logger, auditLog *Log, endpoint string, h http.Handler) http.Handler {
+	wrapped := &handler{
+		log:      log,
+		auditLog: auditLog,
+		endpoint: endpoint,
+		handler:  h,
+	}
+	if documented, ok := h.(openapi.Documented); ok {
+		return &documentedHandler{
+			handler:    wrapped,
+			documented: documented,
+		}
+	}
+	return wrapped
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	entry := Entry{
+		Endpoint: h.endpoint,
+		Caller:   r.RemoteAddr,
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		entry.Error = err.Error()
+		h.record(entry)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	entry.Method, entry.Username = parseRequest(body)
+
+	recorder := &responseRecorder{
+		ResponseWriter: w,
+		statusCode:     http.StatusOK,
+	}
+	h.handler.ServeHTTP(recorder, r)
+
+	resp := response{}
+	switch {
+	case json.Unmarshal(recorder.body.Bytes(), &resp) == nil && resp.Error != nil:
+		entry.Error = resp.Error.Message
+	case recorder.statusCode >= http.StatusBadRequest:
+		entry.Error = http.StatusText(recorder.statusCode)
+	}
+	h.record(entry)
+}
+
+func (h *handler) record(entry Entry) {
+	if err := h.auditLog.Record(entry); err != nil {
+		h.log.Error("couldn't record call to %s.%s in the audit log: %s", entry.Endpoint, entry.Method, err)
+	}
+}
+
+// parseRequest returns the method of the JSON-RPC request [body] and the
+// username it was made with, if any. Returns empty strings for the parts that
+// can't be parsed.
+func parseRequest(body []byte) (string, string) {
+	req := request{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", ""
+	}
+
+	// Parameters are either an object or a list holding a single object
+	params := req.Params
+	var paramsList []json.RawMessage
+	if err := json.Unmarshal(params, &paramsList); err == nil && len(paramsList) > 0 {
+		params = paramsList[0]
+	}
+	user := userParams{}
+	if err := json.Unmarshal(params, &user); err != nil {
+		return req.Method, ""
+	}
+	return req.Method, user.Username
+}
+
+// responseRecorder writes the response it's given to the wrapped writer and
+// keeps a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,111 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api/openapi"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestHandlerRecordsCalls(t *testing.T) {
+	assert := assert.New(t)
+
+	auditLog, err := NewLog(memdb.New())
+	assert.NoError(err)
+
+	var receivedBody []byte
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, err = ioutil.ReadAll(r.Body)
+		assert.NoError(err)
+		if bytes.Contains(receivedBody, []byte("wrong")) {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","error":{"code":-32000,"message":"incorrect password"},"id":1}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":{"success":true},"id":1}`))
+	})
+	h := WrapHandler(logging.NoLog{}, auditLog, "keystore", inner)
+	_, documented := h.(openapi.Documented)
+	assert.False(documented)
+
+	body := `{"jsonrpc":"2.0","method":"keystore.createUser","params":{"username":"bob","password":"secret"},"id":1}`
+	req := httptest.NewRequest(http.MethodPost, "/ext/keystore", bytes.NewBufferString(body))
+	req.RemoteAddr = "1.2.3.4:5678"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(body, string(receivedBody))
+	assert.Contains(w.Body.String(), `"success":true`)
+
+	body = `{"jsonrpc":"2.0","method":"keystore.exportUser","params":[{"username":"alice","password":"wrong"}],"id":1}`
+	req = httptest.NewRequest(http.MethodPost, "/ext/keystore", bytes.NewBufferString(body))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodPost, "/ext/keystore", bytes.NewBufferString("not json"))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries, err := auditLog.Entries(0, MaxFetchedEntries)
+	assert.NoError(err)
+	assert.Len(entries, 3)
+
+	assert.Equal("keystore", entries[0].Endpoint)
+	assert.Equal("keystore.createUser", entries[0].Method)
+	assert.Equal("1.2.3.4:5678", entries[0].Caller)
+	assert.Equal("bob", entries[0].Username)
+	assert.Empty(entries[0].Error)
+
+	assert.Equal("keystore.exportUser", entries[1].Method)
+	assert.Equal("alice", entries[1].Username)
+	assert.Equal("incorrect password", entries[1].Error)
+
+	assert.Empty(entries[2].Method)
+	assert.Empty(entries[2].Username)
+
+	// Passwords are never recorded
+	for _, entry := range entries {
+		assert.NotContains(entry.Username, "secret")
+		assert.NotContains(entry.Error, "secret")
+	}
+}
+
+func TestHandlerKeepsDocumentation(t *testing.T) {
+	assert := assert.New(t)
+
+	auditLog, err := NewLog(memdb.New())
+	assert.NoError(err)
+
+	service, err := NewService(logging.NoLog{}, auditLog)
+	assert.NoError(err)
+	h := WrapHandler(logging.NoLog{}, auditLog, "audit", service.Handler)
+	documented, ok := h.(openapi.Documented)
+	assert.True(ok)
+	assert.Len(documented.Services(), 1)
+
+	body := `{"jsonrpc":"2.0","method":"audit.getEntries","params":{"startIndex":"0","numToFetch":"10"},"id":1}`
+	req := httptest.NewRequest(http.MethodPost, "/ext/audit", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Contains(w.Body.String(), `"numEntries":"0"`)
+
+	body = `{"jsonrpc":"2.0","method":"audit.getEntries","params":{"startIndex":"0","numToFetch":"0"},"id":1}`
+	req = httptest.NewRequest(http.MethodPost, "/ext/audit", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Calls to the audit API are audited too
+	entries, err := auditLog.Entries(0, MaxFetchedEntries)
+	assert.NoError(err)
+	assert.Len(entries, 2)
+	assert.Equal("audit.getEntries", entries[0].Method)
+	assert.Empty(entries[0].Error)
+	assert.Equal(errNumToFetchInvalid.Error(), entries[1].Error)
+}
@@ -0,0 +1,80 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const usernameField = "username"
+
+// UnaryInterceptor returns a gRPC interceptor that records calls to the
+// services in [endpoints] in [auditLog]. [endpoints] maps the full name of a
+// service, such as "keystoreproto.Keystore", to the endpoint its calls are
+// recorded under. Calls to other services aren't recorded. As with JSON-RPC
+// calls, only the username of a request is recorded, never its other fields.
+func UnaryInterceptor(log logging.Logger, auditLog *Log, endpoints map[string]string) grpc.UnaryServerInterceptor {
+	h := &handler{
+		log:      log,
+		auditLog: auditLog,
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (interface{}, error) {
+		method := strings.TrimPrefix(info.FullMethod, "/")
+		service := method
+		if i := strings.LastIndex(method, "/"); i >= 0 {
+			service = method[:i]
+		}
+		endpoint, audited := endpoints[service]
+		if !audited {
+			return next(ctx, req)
+		}
+
+		entry := Entry{
+			Endpoint: endpoint,
+			Method:   method,
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			entry.Caller = p.Addr.String()
+		}
+		if msg, ok := req.(proto.Message); ok {
+			entry.Username = requestUsername(msg.ProtoReflect())
+		}
+
+		resp, err := next(ctx, req)
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		h.record(entry)
+		return resp, err
+	}
+}
+
+// requestUsername returns the username field of [msg], or of a message that
+// [msg] holds, or an empty string if it doesn't have one
+func requestUsername(msg protoreflect.Message) string {
+	fields := msg.Descriptor().Fields()
+	if field := fields.ByName(usernameField); field != nil && field.Kind() == protoreflect.StringKind {
+		return msg.Get(field).String()
+	}
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if field.Kind() != protoreflect.MessageKind || field.IsList() || field.IsMap() || !msg.Has(field) {
+			continue
+		}
+		inner := msg.Get(field).Message()
+		innerField := inner.Descriptor().Fields().ByName(usernameField)
+		if innerField != nil && innerField.Kind() == protoreflect.StringKind {
+			return inner.Get(innerField).String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/ava-labs/avalanchego/api/keystore/keystoreproto"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestInterceptorRecordsCalls(t *testing.T) {
+	assert := assert.New(t)
+
+	auditLog, err := NewLog(memdb.New())
+	assert.NoError(err)
+
+	interceptor := UnaryInterceptor(logging.NoLog{}, auditLog, map[string]string{
+		"keystoreproto.Keystore": "keystore",
+	})
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 5678},
+	})
+	errWrongPassword := errors.New("incorrect password")
+	call := func(method string, req interface{}, callErr error) {
+		_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, interface{}) (interface{}, error) {
+			return nil, callErr
+		})
+		assert.Equal(callErr, err)
+	}
+
+	call("/keystoreproto.Keystore/CreateUser", &keystoreproto.CreateUserRequest{
+		User: &keystoreproto.UserPass{Username: "bob", Password: "secret"},
+	}, nil)
+	call("/keystoreproto.Keystore/ExportUser", &keystoreproto.ExportUserRequest{
+		User: &keystoreproto.UserPass{Username: "alice", Password: "secret"},
+	}, errWrongPassword)
+	call("/keystoreproto.Keystore/ListUsers", &keystoreproto.ListUsersRequest{}, nil)
+	// Services that aren't audited aren't recorded
+	call("/healthproto.Health/Health", nil, nil)
+
+	entries, err := auditLog.Entries(0, MaxFetchedEntries)
+	assert.NoError(err)
+	assert.Len(entries, 3)
+
+	assert.Equal("keystore", entries[0].Endpoint)
+	assert.Equal("keystoreproto.Keystore/CreateUser", entries[0].Method)
+	assert.Equal("1.2.3.4:5678", entries[0].Caller)
+	assert.Equal("bob", entries[0].Username)
+	assert.Empty(entries[0].Error)
+
+	assert.Equal("alice", entries[1].Username)
+	assert.Equal(errWrongPassword.Error(), entries[1].Error)
+
+	assert.Equal("keystoreproto.Keystore/ListUsers", entries[2].Method)
+	assert.Empty(entries[2].Username)
+
+	// Passwords are never recorded
+	for _, entry := range entries {
+		assert.NotContains(entry.Username, "secret")
+	}
+}
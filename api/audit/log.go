@@ -0,0 +1,159 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+const (
+	codecVersion = uint16(0)
+
+	// MaxFetchedEntries is the maximum number of entries that can be fetched
+	// at a time
+	MaxFetchedEntries = 1024
+)
+
+var (
+	// Maps to the index of the next entry
+	nextIndexKey = []byte{0x00}
+	entryPrefix  = []byte{0x01}
+
+	errNumToFetchInvalid = fmt.Errorf("numToFetch must be in [1,%d]", MaxFetchedEntries)
+	errNoEntry           = errors.New("no entry at the given index")
+)
+
+// Entry is the record of an API call
+type Entry struct {
+	// Position of the entry in the log, starting from 0
+	Index uint64 `serialize:"true"`
+	// Unix time, in nanoseconds, that the call finished at
+	Timestamp int64 `serialize:"true"`
+	// Endpoint that was called, relative to /ext
+	Endpoint string `serialize:"true"`
+	// JSON-RPC method that was called, or empty if the request couldn't be
+	// parsed
+	Method string `serialize:"true"`
+	// Network address of the caller
+	Caller string `serialize:"true"`
+	// Keystore user that the call was made on behalf of, if any
+	Username string `serialize:"true"`
+	// Error returned by the call, or empty if the call succeeded
+	Error string `serialize:"true"`
+}
+
+// Log is an append-only log of API calls. Entries can't be modified or
+// removed once they're recorded. Log is thread-safe.
+type Log struct {
+	codec codec.Manager
+	clock timer.Clock
+
+	lock sync.RWMutex
+	// Index of the next entry
+	nextIndex uint64
+	db        *versiondb.Database
+	entries   database.Database
+}
+
+// NewLog returns the audit log stored in [db]
+func NewLog(db database.Database) (*Log, error) {
+	c := codec.NewDefaultManager()
+	if err := c.RegisterCodec(codecVersion, linearcodec.NewDefault()); err != nil {
+		return nil, err
+	}
+	vdb := versiondb.New(db)
+	l := &Log{
+		codec:   c,
+		db:      vdb,
+		entries: prefixdb.New(entryPrefix, vdb),
+	}
+
+	nextIndex, err := database.GetUInt64(vdb, nextIndexKey)
+	switch {
+	case err == database.ErrNotFound:
+		// Nothing was recorded in previous runs
+	case err != nil:
+		return nil, fmt.Errorf("couldn't get next index from database: %w", err)
+	default:
+		l.nextIndex = nextIndex
+	}
+	return l, nil
+}
+
+// Record appends [entry] to the log. Its index and timestamp are set by the
+// log.
+func (l *Log) Record(entry Entry) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	entry.Index = l.nextIndex
+	entry.Timestamp = l.clock.Time().UnixNano()
+	entryBytes, err := l.codec.Marshal(codecVersion, &entry)
+	if err != nil {
+		return fmt.Errorf("couldn't serialize entry: %w", err)
+	}
+	if err := l.entries.Put(database.PackUInt64(entry.Index), entryBytes); err != nil {
+		return fmt.Errorf("couldn't put entry %d: %w", entry.Index, err)
+	}
+	if err := database.PutUInt64(l.db, nextIndexKey, entry.Index+1); err != nil {
+		return fmt.Errorf("couldn't put next index: %w", err)
+	}
+	if err := l.db.Commit(); err != nil {
+		l.db.Abort()
+		return fmt.Errorf("couldn't commit entry %d: %w", entry.Index, err)
+	}
+	l.nextIndex++
+	return nil
+}
+
+// Len returns the number of entries in the log
+func (l *Log) Len() uint64 {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.nextIndex
+}
+
+// Entries returns the entries at [startIndex], [startIndex+1], ... ,
+// [startIndex+numToFetch-1]. If the log runs out of entries, the entries
+// fetched before running out are returned.
+func (l *Log) Entries(startIndex, numToFetch uint64) ([]Entry, error) {
+	if numToFetch == 0 || numToFetch > MaxFetchedEntries {
+		return nil, errNumToFetchInvalid
+	}
+
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	if startIndex >= l.nextIndex {
+		return nil, nil
+	}
+	if remaining := l.nextIndex - startIndex; numToFetch > remaining {
+		numToFetch = remaining
+	}
+	entries := make([]Entry, numToFetch)
+	for i := range entries {
+		index := startIndex + uint64(i)
+		entryBytes, err := l.entries.Get(database.PackUInt64(index))
+		if err == database.ErrNotFound {
+			return nil, fmt.Errorf("%w: %d", errNoEntry, index)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get entry %d: %w", index, err)
+		}
+		if _, err := l.codec.Unmarshal(entryBytes, &entries[i]); err != nil {
+			return nil, fmt.Errorf("couldn't parse entry %d: %w", index, err)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,57 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func TestLog(t *testing.T) {
+	assert := assert.New(t)
+	db := memdb.New()
+
+	l, err := NewLog(db)
+	assert.NoError(err)
+	assert.EqualValues(0, l.Len())
+	entries, err := l.Entries(0, 1)
+	assert.NoError(err)
+	assert.Empty(entries)
+
+	assert.NoError(l.Record(Entry{Endpoint: "admin", Method: "admin.alias", Caller: "127.0.0.1"}))
+	assert.NoError(l.Record(Entry{Endpoint: "keystore", Method: "keystore.createUser", Username: "bob", Error: "user already exists"}))
+	assert.EqualValues(2, l.Len())
+
+	// Entries fetched past the end of the log are left out
+	entries, err = l.Entries(0, 10)
+	assert.NoError(err)
+	assert.Len(entries, 2)
+	assert.EqualValues(0, entries[0].Index)
+	assert.Equal("admin.alias", entries[0].Method)
+	assert.Equal("127.0.0.1", entries[0].Caller)
+	assert.NotZero(entries[0].Timestamp)
+	assert.EqualValues(1, entries[1].Index)
+	assert.Equal("bob", entries[1].Username)
+	assert.Equal("user already exists", entries[1].Error)
+
+	_, err = l.Entries(0, 0)
+	assert.ErrorIs(err, errNumToFetchInvalid)
+	_, err = l.Entries(0, MaxFetchedEntries+1)
+	assert.ErrorIs(err, errNumToFetchInvalid)
+
+	// Entries recorded by a previous run are kept
+	l, err = NewLog(db)
+	assert.NoError(err)
+	assert.EqualValues(2, l.Len())
+	assert.NoError(l.Record(Entry{Endpoint: "auth", Method: "auth.newToken"}))
+	entries, err = l.Entries(1, 2)
+	assert.NoError(err)
+	assert.Len(entries, 2)
+	assert.Equal("keystore.createUser", entries[0].Method)
+	assert.EqualValues(2, entries[1].Index)
+	assert.Equal("auth.newToken", entries[1].Method)
+}
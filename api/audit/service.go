@@ -0,0 +1,96 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api/openapi"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// Audit is the API service for querying the audit log
+type Audit struct {
+	log      logging.Logger
+	auditLog *Log
+}
+
+// NewService returns a new audit API service that serves [auditLog]
+func NewService(log logging.Logger, auditLog *Log) (*common.HTTPHandler, error) {
+	newServer := openapi.NewServer()
+	codec := json.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	service := &Audit{
+		log:      log,
+		auditLog: auditLog,
+	}
+	if err := newServer.RegisterService(service, "audit"); err != nil {
+		return nil, err
+	}
+	return &common.HTTPHandler{LockOptions: common.NoLock, Handler: newServer}, nil
+}
+
+// FormattedEntry is an entry of the audit log formatted for the API
+type FormattedEntry struct {
+	Index     json.Uint64 `json:"index"`
+	Timestamp time.Time   `json:"timestamp"`
+	Endpoint  string      `json:"endpoint"`
+	Method    string      `json:"method"`
+	Caller    string      `json:"caller"`
+	Username  string      `json:"username,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// GetEntriesArgs are the arguments for calling GetEntries
+type GetEntriesArgs struct {
+	StartIndex json.Uint64 `json:"startIndex"`
+	NumToFetch json.Uint64 `json:"numToFetch"`
+	// If non-empty, only entries of calls to this endpoint are returned
+	Endpoint string `json:"endpoint"`
+	// If non-empty, only entries of calls to this method are returned
+	Method string `json:"method"`
+}
+
+// GetEntriesReply is the response from calling GetEntries
+type GetEntriesReply struct {
+	Entries []FormattedEntry `json:"entries"`
+	// Number of entries in the log
+	NumEntries json.Uint64 `json:"numEntries"`
+}
+
+// GetEntries returns the entries at [startIndex], [startIndex+1], ... ,
+// [startIndex+numToFetch-1] that match the given filters. If the log runs
+// out of entries, the entries fetched before running out are returned.
+func (service *Audit) GetEntries(_ *http.Request, args *GetEntriesArgs, reply *GetEntriesReply) error {
+	service.log.Debug("Audit: GetEntries called with startIndex %d, numToFetch %d", args.StartIndex, args.NumToFetch)
+
+	entries, err := service.auditLog.Entries(uint64(args.StartIndex), uint64(args.NumToFetch))
+	if err != nil {
+		return err
+	}
+	reply.Entries = make([]FormattedEntry, 0, len(entries))
+	for _, entry := range entries {
+		if args.Endpoint != "" && entry.Endpoint != args.Endpoint {
+			continue
+		}
+		if args.Method != "" && entry.Method != args.Method {
+			continue
+		}
+		reply.Entries = append(reply.Entries, FormattedEntry{
+			Index:     json.Uint64(entry.Index),
+			Timestamp: time.Unix(0, entry.Timestamp).UTC(),
+			Endpoint:  entry.Endpoint,
+			Method:    entry.Method,
+			Caller:    entry.Caller,
+			Username:  entry.Username,
+			Error:     entry.Error,
+		})
+	}
+	reply.NumEntries = json.Uint64(service.auditLog.Len())
+	return nil
+}
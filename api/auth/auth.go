@@ -1,10 +1,13 @@
 package auth
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"path"
 	"strings"
@@ -13,8 +16,11 @@ import (
 
 	jwt "github.com/dgrijalva/jwt-go"
 
-	"github.com/gorilla/rpc/v2"
-
+	"github.com/ava-labs/avalanchego/api/openapi"
+	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/password"
 	"github.com/ava-labs/avalanchego/utils/timer"
@@ -31,11 +37,21 @@ const (
 
 	// defaultTokenLifespan is how long a token lives before it expires
 	defaultTokenLifespan = time.Hour * 12
+	// maxTokenLifespan is the longest lifespan a token can be created with
+	maxTokenLifespan = time.Hour * 24 * 365
 
 	maxEndpoints = 128
+	maxMethods   = 128
+	maxChains    = 128
 )
 
 var (
+	// Before the salt was persisted on its own, the password hash was
+	// persisted under [passwordKey]
+	passwordKey   = []byte("password")
+	saltKey       = []byte("salt")
+	revokedPrefix = []byte("revoked")
+
 	errNoToken               = errors.New("auth token not provided")
 	errAuthHeaderNotParsable = fmt.Errorf(
 		"couldn't parse auth token. Header \"%s\" should be \"%sTOKEN.GOES.HERE\"",
@@ -50,6 +66,10 @@ var (
 	errNoPassword                  = errors.New("no password")
 	errNoEndpoints                 = errors.New("must name at least one endpoint")
 	errTooManyEndpoints            = fmt.Errorf("can only name at most %d endpoints", maxEndpoints)
+	errTooManyMethods              = fmt.Errorf("can only name at most %d methods", maxMethods)
+	errTooManyChains               = fmt.Errorf("can only name at most %d chains", maxChains)
+	errTokenLifespanTooLong        = fmt.Errorf("token lifespan can be at most %s", maxTokenLifespan)
+	errUnknownMethod               = errors.New("couldn't parse the API method of the request")
 
 	_ Auth = &auth{}
 )
@@ -58,16 +78,21 @@ type Auth interface {
 	// Create and return a new token that allows access to each API endpoint for
 	// [duration] such that the API's path ends with an element of [endpoints].
 	// If one of the elements of [endpoints] is "*", all APIs are accessible.
-	NewToken(pw string, duration time.Duration, endpoints []string) (string, error)
+	// If [methods] isn't empty, the token only allows calling the JSON-RPC
+	// methods in [methods]. An element "service.*" allows calling every method
+	// of the service.
+	// If [chains] isn't empty, the token only allows access to the APIs of the
+	// chains in [chains], which are chain IDs or aliases, among the APIs of
+	// chains.
+	NewToken(pw string, duration time.Duration, endpoints, methods, chains []string) (string, error)
 
 	// Revokes [token]; it will not be accepted as authorization for future API
-	// calls. If the token is invalid, this is a no-op.  If a token is revoked
-	// and then the password is changed, and then changed back to the current
-	// password, the token will be un-revoked. Therefore, passwords shouldn't be
-	// re-used before previously revoked tokens have expired.
+	// calls. If the token is invalid, this is a no-op. Revoked tokens are
+	// persisted until they expire.
 	RevokeToken(pw, token string) error
 
-	// Authenticates [token] for access to [url].
+	// Authenticates [token] for access to [url]. Doesn't check the methods or
+	// the chains the token allows access to.
 	AuthenticateToken(token, url string) error
 
 	// Change the password required to create and revoke tokens.
@@ -75,7 +100,9 @@ type Auth interface {
 	// [newPW] is the new password. It can't be the empty string and it can't be
 	//         unreasonably long.
 	// Changing the password makes tokens issued under a previous password
-	// invalid.
+	// invalid. If the password is then changed back, those tokens are valid
+	// again. Therefore, passwords shouldn't be re-used before the tokens
+	// issued under them have expired.
 	ChangePassword(oldPW, newPW string) error
 
 	// Create the API endpoint for this auth handler.
@@ -94,31 +121,96 @@ type auth struct {
 	endpoint string
 
 	lock sync.RWMutex
-	// Can be changed via API call.
+	// Can be changed via API call. Tokens are signed with the hash, so it's
+	// never persisted.
 	password password.Hash
 	// Set of token IDs that have been revoked
 	revoked map[string]struct{}
+	// Stores the salt of the password hash
+	db database.Database
+	// Revoked token ID --> Unix time the token expires at
+	revokedDB database.Database
 }
 
-func New(log logging.Logger, endpoint, pw string) (Auth, error) {
+// New returns a new Auth that persists the salt of its password hash and the
+// revoked tokens in [db], so that tokens remain valid, or revoked, across
+// restarts as long as the password doesn't change. The hash that tokens are
+// signed with is derived from [pw] and isn't persisted.
+func New(log logging.Logger, endpoint, pw string, db database.Database) (Auth, error) {
 	a := &auth{
-		log:      log,
-		endpoint: endpoint,
-		revoked:  make(map[string]struct{}),
+		log:       log,
+		endpoint:  endpoint,
+		revoked:   make(map[string]struct{}),
+		db:        db,
+		revokedDB: prefixdb.New(revokedPrefix, db),
+	}
+	if err := a.loadPassword(pw); err != nil {
+		return nil, err
 	}
-	return a, a.password.Set(pw)
+	return a, a.loadRevoked()
 }
 
+// NewFromHash returns a new Auth that keeps the revoked tokens in memory
 func NewFromHash(log logging.Logger, endpoint string, pw password.Hash) Auth {
 	return &auth{
-		log:      log,
-		endpoint: endpoint,
-		password: pw,
-		revoked:  make(map[string]struct{}),
+		log:       log,
+		endpoint:  endpoint,
+		password:  pw,
+		revoked:   make(map[string]struct{}),
+		db:        memdb.New(),
+		revokedDB: memdb.New(),
 	}
 }
 
-func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (string, error) {
+// loadPassword sets the password hash to the hash of [pw] with the persisted
+// salt. If no salt was persisted yet, a new one is.
+func (a *auth) loadPassword(pw string) error {
+	// The password hash must not be left in the database
+	if err := a.db.Delete(passwordKey); err != nil {
+		return err
+	}
+
+	salt, err := a.db.Get(saltKey)
+	switch {
+	case err == database.ErrNotFound:
+	case err != nil:
+		return err
+	case len(salt) == len(a.password.Salt):
+		copy(a.password.Salt[:], salt)
+		a.password.SetWithSalt(pw)
+		return nil
+	}
+
+	if err := a.password.Set(pw); err != nil {
+		return err
+	}
+	return a.db.Put(saltKey, a.password.Salt[:])
+}
+
+// loadRevoked loads the revoked tokens that haven't expired yet from the
+// database, and removes the ones that have expired
+func (a *auth) loadRevoked() error {
+	it := a.revokedDB.NewIterator()
+	defer it.Release()
+
+	now := a.clock.Unix()
+	for it.Next() {
+		expiresAt, err := database.ParseUInt64(it.Value())
+		if err != nil {
+			return err
+		}
+		if expiresAt <= now {
+			if err := a.revokedDB.Delete(it.Key()); err != nil {
+				return err
+			}
+			continue
+		}
+		a.revoked[string(it.Key())] = struct{}{}
+	}
+	return it.Error()
+}
+
+func (a *auth) NewToken(pw string, duration time.Duration, endpoints, methods, chains []string) (string, error) {
 	if pw == "" {
 		return "", errNoPassword
 	}
@@ -127,6 +219,15 @@ func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (
 	} else if l > maxEndpoints {
 		return "", errTooManyEndpoints
 	}
+	if len(methods) > maxMethods {
+		return "", errTooManyMethods
+	}
+	if len(chains) > maxChains {
+		return "", errTooManyChains
+	}
+	if duration > maxTokenLifespan {
+		return "", errTokenLifespanTooLong
+	}
 
 	a.lock.RLock()
 	defer a.lock.RUnlock()
@@ -160,6 +261,8 @@ func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (
 	} else {
 		claims.Endpoints = endpoints
 	}
+	claims.Methods = methods
+	claims.Chains = chains
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims)
 	return token.SignedString(a.password.Password[:]) // Sign the token and return its string repr.
 }
@@ -194,17 +297,27 @@ func (a *auth) RevokeToken(tokenStr, pw string) error {
 	if !ok {
 		return fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
 	}
+	if err := database.PutUInt64(a.revokedDB, []byte(claims.Id), uint64(claims.ExpiresAt)); err != nil {
+		return fmt.Errorf("failed to persist the revoked token due to %w", err)
+	}
 	a.revoked[claims.Id] = struct{}{}
 	return nil
 }
 
 func (a *auth) AuthenticateToken(tokenStr, url string) error {
+	_, err := a.authenticate(tokenStr, url)
+	return err
+}
+
+// authenticate authenticates [tokenStr] for access to [url] and returns its
+// claims
+func (a *auth) authenticate(tokenStr, url string) (*endpointClaims, error) {
 	a.lock.RLock()
 	defer a.lock.RUnlock()
 
 	token, err := jwt.ParseWithClaims(tokenStr, &endpointClaims{}, a.getTokenKey)
 	if err != nil { // Probably because signature wrong
-		return err
+		return nil, err
 	}
 
 	// Make sure this token gives access to the requested endpoint
@@ -212,20 +325,20 @@ func (a *auth) AuthenticateToken(tokenStr, url string) error {
 	if !ok {
 		// Error is intentionally dropped here as there is nothing left to do
 		// with it.
-		return fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
+		return nil, fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
 	}
 
 	_, revoked := a.revoked[claims.Id]
 	if revoked {
-		return errTokenRevoked
+		return nil, errTokenRevoked
 	}
 
 	for _, endpoint := range claims.Endpoints {
 		if endpoint == "*" || strings.HasSuffix(url, endpoint) {
-			return nil
+			return claims, nil
 		}
 	}
-	return errTokenInsufficientPermission
+	return nil, errTokenInsufficientPermission
 }
 
 func (a *auth) ChangePassword(oldPW, newPW string) error {
@@ -242,18 +355,14 @@ func (a *auth) ChangePassword(oldPW, newPW string) error {
 	if err := password.IsValid(newPW, password.OK); err != nil {
 		return err
 	}
-	if err := a.password.Set(newPW); err != nil {
-		return err
-	}
-
-	// All the revoked tokens are now invalid; no need to mark specifically as
-	// revoked.
-	a.revoked = make(map[string]struct{})
+	// The salt is kept, so that the tokens issued under the password that
+	// the node is restarted with are valid again after the restart
+	a.password.SetWithSalt(newPW)
 	return nil
 }
 
 func (a *auth) CreateHandler() (http.Handler, error) {
-	server := rpc.NewServer()
+	server := openapi.NewServer()
 	codec := cjson.NewCodec()
 	server.RegisterCodec(codec, "application/json")
 	server.RegisterCodec(codec, "application/json;charset=UTF-8")
@@ -286,15 +395,70 @@ func (a *auth) WrapHandler(h http.Handler) http.Handler {
 		// Returns actual auth token. Slice guaranteed to not go OOB
 		tokenStr := rawHeader[len(headerValStart):]
 
-		if err := a.AuthenticateToken(tokenStr, r.URL.Path); err != nil {
+		claims, err := a.authenticate(tokenStr, r.URL.Path)
+		if err != nil {
 			writeUnauthorizedResponse(w, err)
 			return
 		}
 
-		h.ServeHTTP(w, r)
+		if len(claims.Methods) != 0 {
+			methods, err := readMethods(r)
+			if err != nil {
+				writeUnauthorizedResponse(w, err)
+				return
+			}
+			for _, method := range methods {
+				if !claims.allowsMethod(method) {
+					writeUnauthorizedResponse(w, errTokenInsufficientPermission)
+					return
+				}
+			}
+		}
+
+		// The API server checks the chains the token allows access to, since
+		// it resolves the chain of the request
+		r = server.WithChainScope(r, claims.Chains)
+		// The token was verified, so its client can be rate limited by it
+		h.ServeHTTP(w, server.WithClientID(r, "token:"+claims.Id))
 	})
 }
 
+// readMethods returns the JSON-RPC methods called by [r], which is either a
+// single request or a batch of requests. The body of [r] is replaced so that
+// it can be read again.
+func readMethods(r *http.Request) ([]string, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errUnknownMethod, err)
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	type methodRequest struct {
+		Method string `json:"method"`
+	}
+	var requests []methodRequest
+	if trimmed := bytes.TrimLeft(body, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(body, &requests); err != nil {
+			return nil, errUnknownMethod
+		}
+	} else {
+		request := methodRequest{}
+		if err := json.Unmarshal(body, &request); err != nil {
+			return nil, errUnknownMethod
+		}
+		requests = append(requests, request)
+	}
+
+	methods := make([]string, len(requests))
+	for i, request := range requests {
+		if request.Method == "" {
+			return nil, errUnknownMethod
+		}
+		methods[i] = request.Method
+	}
+	return methods, nil
+}
+
 // getTokenKey returns the key to use when making and parsing tokens
 func (a *auth) getTokenKey(t *jwt.Token) (interface{}, error) {
 	if t.Method != jwt.SigningMethodHS256 {
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -15,6 +16,9 @@ import (
 
 	jwt "github.com/dgrijalva/jwt-go"
 
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/password"
 )
@@ -37,10 +41,10 @@ var dummyHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request)
 func TestNewTokenWrongPassword(t *testing.T) {
 	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
 
-	_, err := auth.NewToken("", defaultTokenLifespan, []string{"endpoint1, endpoint2"})
+	_, err := auth.NewToken("", defaultTokenLifespan, []string{"endpoint1, endpoint2"}, nil, nil)
 	assert.Error(t, err, "should have failed because password is wrong")
 
-	_, err = auth.NewToken("notThePassword", defaultTokenLifespan, []string{"endpoint1, endpoint2"})
+	_, err = auth.NewToken("notThePassword", defaultTokenLifespan, []string{"endpoint1, endpoint2"}, nil, nil)
 	assert.Error(t, err, "should have failed because password is wrong")
 }
 
@@ -52,7 +56,7 @@ func TestNewTokenHappyPath(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"endpoint1", "endpoint2", "endpoint3"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	// Parse the token
@@ -76,7 +80,7 @@ func TestTokenHasWrongSig(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"endpoint1", "endpoint2", "endpoint3"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	// Try to parse the token using the wrong password
@@ -129,7 +133,7 @@ func TestRevokeToken(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	err = auth.RevokeToken(tokenStr, testPassword)
@@ -142,7 +146,7 @@ func TestWrapHandlerHappyPath(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -161,7 +165,7 @@ func TestWrapHandlerRevokedToken(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	err = auth.RevokeToken(tokenStr, testPassword)
@@ -187,7 +191,7 @@ func TestWrapHandlerExpiredToken(t *testing.T) {
 
 	// Make a token that expired well in the past
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -223,7 +227,7 @@ func TestWrapHandlerUnauthorizedEndpoint(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	unauthorizedEndpoints := []string{"/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/info/foo"}
@@ -245,7 +249,7 @@ func TestWrapHandlerAuthEndpoint(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/info/foo"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -261,7 +265,7 @@ func TestWrapHandlerAccessAll(t *testing.T) {
 
 	// Make a token that allows access to all endpoints
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/foo/info"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"})
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -286,7 +290,7 @@ func TestWrapHandlerMutatedRevokedToken(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil, nil)
 	assert.NoError(t, err)
 
 	err = auth.RevokeToken(tokenStr, testPassword)
@@ -341,3 +345,147 @@ func TestWrapHandlerInvalidSigningMethod(t *testing.T) {
 		assert.Regexp(t, unAuthorizedResponseRegex, rr.Body.String())
 	}
 }
+
+func TestWrapHandlerMethods(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+
+	// Make a token that allows calling every info method and one keystore
+	// method
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, []string{"info.*", "keystore.listUsers"}, nil)
+	assert.NoError(t, err)
+
+	var body []byte
+	wrappedHandler := auth.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err = ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+	}))
+
+	tests := []struct {
+		method string
+		code   int
+	}{
+		{method: "info.getNodeID", code: http.StatusOK},
+		{method: "info.GetNodeID", code: http.StatusOK},
+		{method: "keystore.listUsers", code: http.StatusOK},
+		{method: "keystore.exportUser", code: http.StatusUnauthorized},
+		{method: "admin.stopCPUProfiler", code: http.StatusUnauthorized},
+		{method: "", code: http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		reqBody := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q,"params":{}}`, test.method)
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/info", strings.NewReader(reqBody))
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+		rr := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rr, req)
+		assert.Equal(t, test.code, rr.Code, test.method)
+		if test.code == http.StatusOK {
+			// The handler should still be able to read the request
+			assert.Equal(t, reqBody, string(body))
+		}
+	}
+
+	// Every request of a batch must be allowed
+	batchTests := []struct {
+		body string
+		code int
+	}{
+		{body: `[{"jsonrpc":"2.0","id":1,"method":"info.getNodeID"},{"jsonrpc":"2.0","id":2,"method":"keystore.listUsers"}]`, code: http.StatusOK},
+		{body: `[{"jsonrpc":"2.0","id":1,"method":"info.getNodeID"},{"jsonrpc":"2.0","id":2,"method":"keystore.exportUser"}]`, code: http.StatusUnauthorized},
+		{body: `[{"jsonrpc":"2.0","id":1,"method":"info.getNodeID"},{"jsonrpc":"2.0","id":2}]`, code: http.StatusUnauthorized},
+	}
+	for _, test := range batchTests {
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/info", strings.NewReader(test.body))
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+		rr := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rr, req)
+		assert.Equal(t, test.code, rr.Code, test.body)
+	}
+}
+
+func TestNewTokenLifespanTooLong(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+
+	_, err := auth.NewToken(testPassword, maxTokenLifespan+time.Second, []string{"*"}, nil, nil)
+	assert.Equal(t, errTokenLifespanTooLong, err)
+}
+
+func TestRevokedTokensPersisted(t *testing.T) {
+	db := memdb.New()
+	auth1, err := New(logging.NoLog{}, "auth", testPassword, db)
+	assert.NoError(t, err)
+
+	tokenStr, err := auth1.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, auth1.RevokeToken(tokenStr, testPassword))
+
+	validTokenStr, err := auth1.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil, nil)
+	assert.NoError(t, err)
+
+	// Tokens should still be valid, or revoked, after a restart
+	auth2, err := New(logging.NoLog{}, "auth", testPassword, db)
+	assert.NoError(t, err)
+	assert.Equal(t, errTokenRevoked, auth2.AuthenticateToken(tokenStr, "/ext/info"))
+	assert.NoError(t, auth2.AuthenticateToken(validTokenStr, "/ext/info"))
+
+	// Revoked tokens are forgotten once they expire
+	auth3 := &auth{
+		revoked:   make(map[string]struct{}),
+		revokedDB: prefixdb.New(revokedPrefix, db),
+	}
+	auth3.clock.Set(time.Now().Add(2 * defaultTokenLifespan))
+	assert.NoError(t, auth3.loadRevoked())
+	assert.Empty(t, auth3.revoked)
+	size, err := database.Size(auth3.revokedDB)
+	assert.NoError(t, err)
+	assert.Zero(t, size)
+
+	// Changing the password on restart invalidates all the tokens
+	auth4, err := New(logging.NoLog{}, "auth", "another password", db)
+	assert.NoError(t, err)
+	assert.Error(t, auth4.AuthenticateToken(validTokenStr, "/ext/info"))
+}
+
+func TestPasswordHashNotPersisted(t *testing.T) {
+	db := memdb.New()
+
+	// A password hash persisted by a previous version is removed
+	assert.NoError(t, db.Put(passwordKey, make([]byte, 48)))
+
+	a, err := New(logging.NoLog{}, "auth", testPassword, db)
+	assert.NoError(t, err)
+	tokenStr, err := a.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil, nil)
+	assert.NoError(t, err)
+
+	has, err := db.Has(passwordKey)
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	// Only the salt is persisted, which doesn't allow signing tokens
+	it := db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		assert.Equal(t, saltKey, it.Key())
+		_, err := jwt.ParseWithClaims(tokenStr, &endpointClaims{}, func(*jwt.Token) (interface{}, error) {
+			return it.Value(), nil
+		})
+		assert.Error(t, err)
+	}
+	assert.NoError(t, it.Error())
+}
+
+func TestNewTokenChains(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+
+	_, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil, make([]string, maxChains+1))
+	assert.Equal(t, errTooManyChains, err)
+
+	// The chains are checked by the API server once it resolves the chain of
+	// the request, so they only need to be in the token
+	chains := []string{"X", "C"}
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil, chains)
+	assert.NoError(t, err)
+
+	claims, err := auth.authenticate(tokenStr, "/ext/bc/P")
+	assert.NoError(t, err)
+	assert.Equal(t, chains, claims.Chains)
+}
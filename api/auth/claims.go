@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"strings"
+
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
@@ -12,4 +14,33 @@ type endpointClaims struct {
 	// If endpoints has an element "*", allows access to all API endpoints
 	// In this case, "*" should be the only element of [endpoints]
 	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Each element is a JSON-RPC method, such as "info.getNodeID", or all the
+	// methods of a service, such as "info.*", that the token allows calling.
+	// If methods is empty, all methods of the allowed endpoints may be called.
+	Methods []string `json:"methods,omitempty"`
+
+	// Each element is the ID or an alias of a chain whose APIs the token
+	// allows access to. If chains is empty, the APIs of all chains may be
+	// accessed.
+	Chains []string `json:"chains,omitempty"`
+}
+
+// allowsMethod returns true if these claims allow calling [method]
+func (c *endpointClaims) allowsMethod(method string) bool {
+	if len(c.Methods) == 0 {
+		return true
+	}
+	service := method
+	if i := strings.Index(method, "."); i >= 0 {
+		service = method[:i]
+	}
+	for _, allowed := range c.Methods {
+		// The API server capitalizes the first letter of the method name, so
+		// method names aren't case sensitive
+		if allowed == "*" || strings.EqualFold(allowed, method) || strings.EqualFold(allowed, service+".*") {
+			return true
+		}
+	}
+	return false
 }
@@ -2,8 +2,10 @@ package auth
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/utils/json"
 )
 
 // service that serves the Auth API functionality.
@@ -23,17 +25,35 @@ type NewTokenArgs struct {
 	// allows access to all API endpoints. [Endpoints] must have between 1 and
 	// [maxEndpoints] elements
 	Endpoints []string `json:"endpoints"`
+	// JSON-RPC methods that may be called with this token e.g. if methods is
+	// ["info.*", "keystore.listUsers"] then the token holder can call every
+	// method of the info API and keystore.listUsers. If [Methods] is empty,
+	// every method of the allowed endpoints may be called.
+	Methods []string `json:"methods"`
+	// Chains whose APIs may be accessed with this token, by ID or alias e.g.
+	// if chains is ["X"] then the token holder can access the X-Chain API but
+	// not the P-Chain API. APIs that don't belong to a chain aren't affected.
+	// If [Chains] is empty, the APIs of every chain may be accessed.
+	Chains []string `json:"chains"`
+	// Number of seconds until the token expires. If 0, the token expires in
+	// [defaultTokenLifespan].
+	Lifespan json.Uint64 `json:"lifespan"`
 }
 
 type Token struct {
-	Token string `json:"token"` // The new token. Expires in [TokenLifespan].
+	Token string `json:"token"` // The new token
 }
 
 func (s *service) NewToken(_ *http.Request, args *NewTokenArgs, reply *Token) error {
 	s.auth.log.Info("Auth: NewToken called")
 
+	lifespan := defaultTokenLifespan
+	if args.Lifespan != 0 {
+		lifespan = time.Duration(args.Lifespan) * time.Second
+	}
+
 	var err error
-	reply.Token, err = s.auth.NewToken(args.Password.Password, defaultTokenLifespan, args.Endpoints)
+	reply.Token, err = s.auth.NewToken(args.Password.Password, lifespan, args.Endpoints, args.Methods, args.Chains)
 	return err
 }
 
@@ -63,6 +63,14 @@ type JSONSpendHeader struct {
 	JSONChangeAddr
 }
 
+// BLSKeyRegistration is the hex encoded registration of a node's BLS key
+type BLSKeyRegistration struct {
+	Certificate       string `json:"certificate"`
+	PublicKey         string `json:"publicKey"`
+	ProofOfPossession string `json:"proofOfPossession"`
+	Signature         string `json:"signature"`
+}
+
 // GetTxArgs ...
 type GetTxArgs struct {
 	TxID     ids.ID              `json:"txID"`
@@ -115,3 +123,45 @@ type GetUTXOsReply struct {
 	// Encoding specifies the encoding format the UTXOs are returned in
 	Encoding formatting.Encoding `json:"encoding"`
 }
+
+// GetPendingTransfersArgs are the arguments of GetPendingTransfers. Returns the
+// cross-chain transfers that reference at least one address in [Addresses].
+// The UTXOs that were exported to this chain are only returned from the chains
+// in [SourceChains].
+type GetPendingTransfersArgs struct {
+	Addresses    []string            `json:"addresses"`
+	SourceChains []string            `json:"sourceChains"`
+	Encoding     formatting.Encoding `json:"encoding"`
+}
+
+// PendingExport is an accepted export of UTXOs from this chain that couldn't
+// be written to shared memory yet. It's retried until it's written, or until
+// it failed too many times, in which case its status is Failed.
+type PendingExport struct {
+	TxID             ids.ID `json:"txID"`
+	DestinationChain ids.ID `json:"destinationChain"`
+	// The exported UTXOs
+	UTXOs []string `json:"utxos"`
+	// Queued if the export is still retried, or Failed if it isn't anymore
+	Status string `json:"status"`
+	// Number of times that writing the UTXOs to shared memory was attempted
+	Attempts json.Uint32 `json:"attempts"`
+	// Error of the last attempt
+	LastError string `json:"lastError"`
+}
+
+// PendingImport are the UTXOs that were exported to this chain from
+// [SourceChain] and haven't been imported yet
+type PendingImport struct {
+	SourceChain ids.ID   `json:"sourceChain"`
+	UTXOs       []string `json:"utxos"`
+}
+
+// GetPendingTransfersReply defines the GetPendingTransfers replies returned
+// from the API
+type GetPendingTransfersReply struct {
+	Exports []PendingExport `json:"exports"`
+	Imports []PendingImport `json:"imports"`
+	// Encoding specifies the encoding format the UTXOs are returned in
+	Encoding formatting.Encoding `json:"encoding"`
+}
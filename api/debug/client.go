@@ -0,0 +1,33 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package debug
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+)
+
+// Client for the Avalanche Debug API Endpoint
+type Client struct {
+	requester rpc.EndpointRequester
+}
+
+// NewClient returns a new Debug API Client
+func NewClient(uri string, requestTimeout time.Duration) *Client {
+	return &Client{
+		requester: rpc.NewEndpointRequester(uri, "/ext/debug", "debug", requestTimeout),
+	}
+}
+
+// TraceContainer returns the recorded lifecycle of [containerID] in [chain]
+func (c *Client) TraceContainer(chain string, containerID ids.ID) (*TraceContainerReply, error) {
+	res := &TraceContainerReply{}
+	err := c.requester.SendRequest("traceContainer", &TraceContainerArgs{
+		Chain:       chain,
+		ContainerID: containerID,
+	}, res)
+	return res, err
+}
@@ -0,0 +1,115 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/api/openapi"
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+var (
+	errUnknownChain     = errors.New("unknown chain")
+	errNoTracing        = errors.New("chain's engine doesn't trace containers")
+	errUnknownContainer = errors.New("no trace of container")
+
+	_ chains.Registrant = &Debug{}
+)
+
+// Debug is the API service for inspecting how the node processes containers
+type Debug struct {
+	log          logging.Logger
+	chainManager chains.Manager
+
+	// Chain ID --> the chain's consensus engine
+	enginesLock sync.RWMutex
+	engines     map[ids.ID]common.Engine
+}
+
+// NewService returns a new debug API service
+func NewService(log logging.Logger, chainManager chains.Manager) (*common.HTTPHandler, error) {
+	newServer := openapi.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	service := &Debug{
+		log:          log,
+		chainManager: chainManager,
+		engines:      make(map[ids.ID]common.Engine),
+	}
+	if err := newServer.RegisterService(service, "debug"); err != nil {
+		return nil, err
+	}
+	chainManager.AddRegistrant(service)
+	return &common.HTTPHandler{Handler: newServer}, nil
+}
+
+// RegisterChain implements the chains.Registrant interface
+func (service *Debug) RegisterChain(_ string, ctx *snow.Context, engine common.Engine) {
+	service.enginesLock.Lock()
+	defer service.enginesLock.Unlock()
+
+	service.engines[ctx.ChainID] = engine
+}
+
+// TraceContainerArgs are the arguments for calling TraceContainer
+type TraceContainerArgs struct {
+	Chain       string `json:"chain"`
+	ContainerID ids.ID `json:"containerID"`
+}
+
+// TraceContainerReply is the recorded lifecycle of a container
+type TraceContainerReply struct {
+	ChainID ids.ID `json:"chainID"`
+	// Events in the lifecycle of the container, oldest first
+	Trace *common.Trace `json:"trace"`
+	// Human readable description of the events
+	Text string `json:"text"`
+}
+
+// TraceContainer returns the recorded lifecycle of a container of the chain:
+// when and from whom it was received, the dependencies it waited on, when it
+// was issued, the polls it received votes in, and its decision
+func (service *Debug) TraceContainer(_ *http.Request, args *TraceContainerArgs, reply *TraceContainerReply) error {
+	service.log.Info("Debug: TraceContainer called with Chain: %s, ContainerID: %s", args.Chain, args.ContainerID)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+
+	service.enginesLock.RLock()
+	engine, ok := service.engines[chainID]
+	service.enginesLock.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnknownChain, args.Chain)
+	}
+	tracer, ok := engine.(common.ContainerTracer)
+	if !ok {
+		return fmt.Errorf("%w: %s", errNoTracing, chainID)
+	}
+
+	ctx := engine.Context()
+	ctx.Lock.Lock()
+	trace, ok := tracer.TraceContainer(args.ContainerID)
+	ctx.Lock.Unlock()
+	if !ok {
+		return fmt.Errorf("%w %s in chain %s", errUnknownContainer, args.ContainerID, chainID)
+	}
+
+	reply.ChainID = chainID
+	reply.Trace = trace
+	reply.Text = trace.String()
+	return nil
+}
@@ -0,0 +1,66 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package debug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type tracerEngine struct {
+	common.EngineTest
+
+	tracer *common.Tracer
+}
+
+func (e *tracerEngine) TraceContainer(containerID ids.ID) (*common.Trace, bool) {
+	return e.tracer.Get(containerID)
+}
+
+func TestTraceContainer(t *testing.T) {
+	assert := assert.New(t)
+
+	service := &Debug{
+		log:          logging.NoLog{},
+		chainManager: chains.MockManager{},
+		engines:      make(map[ids.ID]common.Engine),
+	}
+
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	engine := &tracerEngine{tracer: common.NewTracer(1, 1)}
+	engine.ContextF = func() *snow.Context { return ctx }
+	service.RegisterChain("chain", ctx, engine)
+
+	containerID := ids.GenerateTestID()
+	engine.tracer.Record(containerID, "received", ids.GenerateTestShortID(), "")
+
+	reply := TraceContainerReply{}
+	err := service.TraceContainer(nil, &TraceContainerArgs{Chain: ctx.ChainID.String(), ContainerID: containerID}, &reply)
+	assert.NoError(err)
+	assert.Equal(ctx.ChainID, reply.ChainID)
+	assert.Equal(containerID, reply.Trace.ContainerID)
+	assert.Len(reply.Trace.Events, 1)
+	assert.Contains(reply.Text, "received from NodeID-")
+
+	err = service.TraceContainer(nil, &TraceContainerArgs{Chain: ctx.ChainID.String(), ContainerID: ids.GenerateTestID()}, &reply)
+	assert.ErrorIs(err, errUnknownContainer)
+
+	err = service.TraceContainer(nil, &TraceContainerArgs{Chain: ids.GenerateTestID().String(), ContainerID: containerID}, &reply)
+	assert.ErrorIs(err, errUnknownChain)
+
+	// Engines that don't trace containers should be reported
+	otherCtx := snow.DefaultContextTest()
+	otherCtx.ChainID = ids.GenerateTestID()
+	service.RegisterChain("other", otherCtx, &common.EngineTest{})
+	err = service.TraceContainer(nil, &TraceContainerArgs{Chain: otherCtx.ChainID.String(), ContainerID: containerID}, &reply)
+	assert.ErrorIs(err, errNoTracing)
+}
@@ -0,0 +1,221 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+// ChainLookup returns the ID of the chain with the given alias
+type ChainLookup interface {
+	Lookup(alias string) (ids.ID, error)
+}
+
+// resolver resolves the fields of the Query type
+type resolver struct {
+	version    version.Application
+	nodeID     ids.ShortID
+	networkID  uint32
+	chains     ChainLookup
+	validators validators.Manager
+	indexer    indexer.Indexer
+}
+
+type nodeResolver struct {
+	r *resolver
+}
+
+func (r *resolver) Node() *nodeResolver { return &nodeResolver{r: r} }
+
+func (n *nodeResolver) NodeID() string {
+	return address.FormatNodeID(n.r.nodeID)
+}
+
+func (n *nodeResolver) NetworkID() Uint64 { return Uint64(n.r.networkID) }
+
+func (n *nodeResolver) Version() string { return n.r.version.String() }
+
+type validatorResolver struct {
+	vdr validators.Validator
+}
+
+func (v *validatorResolver) NodeID() string {
+	return v.vdr.ID().String()
+}
+
+func (v *validatorResolver) Weight() Uint64 { return Uint64(v.vdr.Weight()) }
+
+type validatorsArgs struct {
+	SubnetID *string
+}
+
+// Validators returns the current validators of the given subnet, or of the
+// primary network if no subnet is given
+func (r *resolver) Validators(args validatorsArgs) ([]*validatorResolver, error) {
+	subnetID := constants.PrimaryNetworkID
+	if args.SubnetID != nil {
+		var err error
+		subnetID, err = address.ParseID(*args.SubnetID)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse subnetID: %w", err)
+		}
+	}
+
+	vdrs, ok := r.validators.GetValidators(subnetID)
+	if !ok {
+		return nil, fmt.Errorf("subnet %s has no validators", subnetID)
+	}
+	vdrList := vdrs.List()
+	resolvers := make([]*validatorResolver, len(vdrList))
+	for i, vdr := range vdrList {
+		resolvers[i] = &validatorResolver{vdr: vdr}
+	}
+	return resolvers, nil
+}
+
+type containerResolver struct {
+	container indexer.Container
+	index     uint64
+}
+
+func (c *containerResolver) ID() string { return c.container.ID.String() }
+
+func (c *containerResolver) Index() Uint64 { return Uint64(c.index) }
+
+func (c *containerResolver) Timestamp() graphql.Time {
+	return graphql.Time{Time: time.Unix(0, c.container.Timestamp)}
+}
+
+type bytesArgs struct {
+	Encoding string
+}
+
+func (c *containerResolver) Bytes(args bytesArgs) (string, error) {
+	encoding := formatting.Hex
+	if args.Encoding == "CB58" {
+		encoding = formatting.CB58
+	}
+	return formatting.Encode(encoding, c.container.Bytes)
+}
+
+type indexArgs struct {
+	Chain string
+	Type  string
+}
+
+type containerArgs struct {
+	Chain string
+	Type  string
+	Index *Uint64
+	ID    *string
+}
+
+// Container returns the container with the given index or ID
+func (r *resolver) Container(args containerArgs) (*containerResolver, error) {
+	index, err := r.getIndex(args.Chain, args.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	var container indexer.Container
+	switch {
+	case args.Index != nil && args.ID != nil:
+		return nil, fmt.Errorf("only one of index and id can be given")
+	case args.Index != nil:
+		container, err = index.GetContainerByIndex(uint64(*args.Index))
+	case args.ID != nil:
+		containerID, parseErr := address.ParseID(*args.ID)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		container, err = index.GetContainerByID(containerID)
+	default:
+		return nil, fmt.Errorf("one of index and id must be given")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newContainerResolver(index, container)
+}
+
+type containersArgs struct {
+	Chain      string
+	Type       string
+	StartIndex Uint64
+	NumToFetch Uint64
+}
+
+// Containers returns up to [NumToFetch] containers starting at [StartIndex]
+func (r *resolver) Containers(args containersArgs) ([]*containerResolver, error) {
+	index, err := r.getIndex(args.Chain, args.Type)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := index.GetContainerRange(uint64(args.StartIndex), uint64(args.NumToFetch))
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*containerResolver, len(containers))
+	for i, container := range containers {
+		resolvers[i] = &containerResolver{
+			container: container,
+			index:     uint64(args.StartIndex) + uint64(i),
+		}
+	}
+	return resolvers, nil
+}
+
+// LastAccepted returns the most recently accepted container
+func (r *resolver) LastAccepted(args indexArgs) (*containerResolver, error) {
+	index, err := r.getIndex(args.Chain, args.Type)
+	if err != nil {
+		return nil, err
+	}
+	container, err := index.GetLastAccepted()
+	if err != nil {
+		return nil, err
+	}
+	return newContainerResolver(index, container)
+}
+
+func newContainerResolver(index indexer.Index, container indexer.Container) (*containerResolver, error) {
+	i, err := index.GetIndex(container.ID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get index: %w", err)
+	}
+	return &containerResolver{
+		container: container,
+		index:     i,
+	}, nil
+}
+
+// getIndex returns the index of the [containerType] containers of the chain
+// with ID or alias [chain]
+func (r *resolver) getIndex(chain string, containerType string) (indexer.Index, error) {
+	chainID, err := ids.FromString(chain)
+	if err != nil {
+		chainID, err = r.chains.Lookup(chain)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't find chain %s: %w", chain, err)
+		}
+	}
+	index, ok := r.indexer.GetIndex(chainID, strings.ToLower(containerType))
+	if !ok {
+		return nil, fmt.Errorf("%s containers of chain %s aren't indexed", strings.ToLower(containerType), chain)
+	}
+	return index, nil
+}
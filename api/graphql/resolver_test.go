@@ -0,0 +1,167 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+var errNotFound = errors.New("not found")
+
+// testIndex is an in-memory index of [containers]
+type testIndex struct {
+	containers []indexer.Container
+}
+
+func (i *testIndex) Accept(*snow.Context, ids.ID, []byte) error { return nil }
+
+func (i *testIndex) AcceptBatch(*snow.Context, ids.ID, []byte) (database.Batch, error) {
+	return nil, nil
+}
+
+func (i *testIndex) GetContainerByIndex(index uint64) (indexer.Container, error) {
+	if index >= uint64(len(i.containers)) {
+		return indexer.Container{}, errNotFound
+	}
+	return i.containers[index], nil
+}
+
+func (i *testIndex) GetContainerRange(startIndex, numToFetch uint64) ([]indexer.Container, error) {
+	if startIndex >= uint64(len(i.containers)) {
+		return nil, errNotFound
+	}
+	end := startIndex + numToFetch
+	if end > uint64(len(i.containers)) {
+		end = uint64(len(i.containers))
+	}
+	return i.containers[startIndex:end], nil
+}
+
+func (i *testIndex) GetLastAccepted() (indexer.Container, error) {
+	return i.GetContainerByIndex(uint64(len(i.containers) - 1))
+}
+
+func (i *testIndex) GetIndex(containerID ids.ID) (uint64, error) {
+	for index, container := range i.containers {
+		if container.ID == containerID {
+			return uint64(index), nil
+		}
+	}
+	return 0, errNotFound
+}
+
+func (i *testIndex) GetContainerByID(containerID ids.ID) (indexer.Container, error) {
+	index, err := i.GetIndex(containerID)
+	if err != nil {
+		return indexer.Container{}, err
+	}
+	return i.containers[index], nil
+}
+
+func (i *testIndex) Close() error { return nil }
+
+// testIndexer indexes the txs of a single chain
+type testIndexer struct {
+	chainID ids.ID
+	index   indexer.Index
+}
+
+func (i *testIndexer) RegisterChain(string, *snow.Context, common.Engine) {}
+
+func (i *testIndexer) GetIndex(chainID ids.ID, containerType string) (indexer.Index, bool) {
+	return i.index, chainID == i.chainID && containerType == indexer.TxContainers
+}
+
+func (i *testIndexer) Close() error { return nil }
+
+func exec(t *testing.T, r *resolver, query string) string {
+	s, err := newSchema(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := s.Exec(context.Background(), query, "", nil)
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(responseJSON)
+}
+
+func TestQueryNodeAndValidators(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID := ids.GenerateTestShortID()
+	vdrs := validators.NewManager()
+	assert.NoError(vdrs.AddWeight(constants.PrimaryNetworkID, ids.NodeIDFromShortID(nodeID), 5))
+
+	r := &resolver{
+		version:    version.Current,
+		nodeID:     nodeID,
+		networkID:  12345,
+		validators: vdrs,
+	}
+	nodeIDStr := address.FormatNodeID(nodeID)
+
+	response := exec(t, r, `{ node { nodeID networkID } validators { nodeID weight } }`)
+	assert.JSONEq(fmt.Sprintf(
+		`{"data":{"node":{"nodeID":%q,"networkID":"12345"},"validators":[{"nodeID":%q,"weight":"5"}]}}`,
+		nodeIDStr,
+		nodeIDStr,
+	), response)
+
+	// Only the selected fields are returned
+	response = exec(t, r, `{ validators { weight } }`)
+	assert.JSONEq(`{"data":{"validators":[{"weight":"5"}]}}`, response)
+}
+
+func TestQueryContainers(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	aliaser := &ids.Aliaser{}
+	aliaser.Initialize()
+	assert.NoError(aliaser.Alias(chainID, "X"))
+
+	index := &testIndex{containers: []indexer.Container{
+		{ID: ids.GenerateTestID(), Bytes: []byte{1}, Timestamp: 1},
+		{ID: ids.GenerateTestID(), Bytes: []byte{2}, Timestamp: 2},
+		{ID: ids.GenerateTestID(), Bytes: []byte{3}, Timestamp: 3},
+	}}
+	r := &resolver{
+		chains:  aliaser,
+		indexer: &testIndexer{chainID: chainID, index: index},
+	}
+
+	response := exec(t, r, fmt.Sprintf(`{ container(chain: "X", type: TX, id: %q) { id index } }`, index.containers[1].ID))
+	assert.JSONEq(fmt.Sprintf(`{"data":{"container":{"id":%q,"index":"1"}}}`, index.containers[1].ID), response)
+
+	response = exec(t, r, fmt.Sprintf(`{ container(chain: %q, type: TX, index: "2") { id } }`, chainID))
+	assert.JSONEq(fmt.Sprintf(`{"data":{"container":{"id":%q}}}`, index.containers[2].ID), response)
+
+	response = exec(t, r, `{ containers(chain: "X", type: TX, startIndex: 1, numToFetch: "5") { index } }`)
+	assert.JSONEq(`{"data":{"containers":[{"index":"1"},{"index":"2"}]}}`, response)
+
+	response = exec(t, r, `{ lastAccepted(chain: "X", type: TX) { index } }`)
+	assert.JSONEq(`{"data":{"lastAccepted":{"index":"2"}}}`, response)
+
+	// Blocks of the chain aren't indexed
+	response = exec(t, r, `{ lastAccepted(chain: "X", type: BLOCK) { index } }`)
+	assert.Contains(response, "aren't indexed")
+}
@@ -0,0 +1,54 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+// schema is the GraphQL schema of the node's state.
+//
+// 64 bit integers are serialized as strings, as they are by the JSON APIs,
+// because GraphQL's Int is only 32 bits.
+const schema = `
+schema {
+	query: Query
+}
+
+scalar Time
+scalar Uint64
+
+enum ContainerType {
+	BLOCK
+	VTX
+	TX
+}
+
+enum Encoding {
+	CB58
+	HEX
+}
+
+type Query {
+	node: Node!
+	validators(subnetID: String): [Validator!]!
+	container(chain: String!, type: ContainerType!, index: Uint64, id: String): Container
+	containers(chain: String!, type: ContainerType!, startIndex: Uint64!, numToFetch: Uint64!): [Container!]!
+	lastAccepted(chain: String!, type: ContainerType!): Container
+}
+
+type Node {
+	nodeID: String!
+	networkID: Uint64!
+	version: String!
+}
+
+type Validator {
+	nodeID: String!
+	weight: Uint64!
+}
+
+type Container {
+	id: String!
+	index: Uint64!
+	timestamp: Time!
+	bytes(encoding: Encoding = HEX): String!
+}
+`
@@ -0,0 +1,43 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+// NewService returns a new GraphQL API service that exposes node info, the
+// validator sets, and the indexed containers
+func NewService(
+	version version.Application,
+	nodeID ids.ShortID,
+	networkID uint32,
+	chains ChainLookup,
+	vdrs validators.Manager,
+	indexer indexer.Indexer,
+) (*common.HTTPHandler, error) {
+	s, err := newSchema(&resolver{
+		version:    version,
+		nodeID:     nodeID,
+		networkID:  networkID,
+		chains:     chains,
+		validators: vdrs,
+		indexer:    indexer,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &common.HTTPHandler{LockOptions: common.NoLock, Handler: &relay.Handler{Schema: s}}, nil
+}
+
+func newSchema(r *resolver) (*graphql.Schema, error) {
+	return graphql.ParseSchema(schema, r)
+}
@@ -0,0 +1,45 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+// Uint64 is the GraphQL Uint64 scalar. It's serialized as a string.
+type Uint64 uint64
+
+// ImplementsGraphQLType maps Uint64 to the Uint64 scalar in the schema
+func (Uint64) ImplementsGraphQLType(name string) bool { return name == "Uint64" }
+
+// UnmarshalGraphQL parses a Uint64 from a string or a non-negative Int
+func (u *Uint64) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case string:
+		val, err := strconv.ParseUint(input, 10, 64)
+		*u = Uint64(val)
+		return err
+	case int32:
+		if input < 0 {
+			return fmt.Errorf("%d is negative", input)
+		}
+		*u = Uint64(input)
+		return nil
+	case float64: // Numbers in the query's variables are parsed as floats
+		if input < 0 || input != math.Trunc(input) || input > math.MaxUint64 {
+			return fmt.Errorf("%v isn't a valid Uint64", input)
+		}
+		*u = Uint64(input)
+		return nil
+	default:
+		return fmt.Errorf("wrong type for Uint64: %T", input)
+	}
+}
+
+// MarshalJSON serializes [u] as a string
+func (u Uint64) MarshalJSON() ([]byte, error) { return json.Uint64(u).MarshalJSON() }
@@ -0,0 +1,71 @@
+// (c) 2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"context"
+
+	stdjson "encoding/json"
+
+	"github.com/ava-labs/avalanchego/api/health/healthproto"
+
+	health "github.com/AppsFlyer/go-sundheit"
+	healthlib "github.com/ava-labs/avalanchego/health"
+)
+
+var _ healthproto.HealthServer = &Server{}
+
+// Server serves the Health API over gRPC
+type Server struct {
+	healthproto.UnimplementedHealthServer
+	service healthlib.Service
+}
+
+// NewServer returns a gRPC server for [service]
+func NewServer(service healthlib.Service) *Server {
+	return &Server{service: service}
+}
+
+// Health returns the results of the health checks. Unlike the JSON API, an
+// unhealthy node isn't reported as an error.
+func (s *Server) Health(
+	context.Context,
+	*healthproto.HealthRequest,
+) (*healthproto.HealthResponse, error) {
+	results, healthy := s.service.Results()
+	checks := make(map[string]*healthproto.Result, len(results))
+	for name, result := range results {
+		checkResult, err := newResult(result)
+		if err != nil {
+			return nil, err
+		}
+		checks[name] = checkResult
+	}
+	return &healthproto.HealthResponse{
+		Checks:  checks,
+		Healthy: healthy,
+	}, nil
+}
+
+func newResult(result health.Result) (*healthproto.Result, error) {
+	protoResult := &healthproto.Result{
+		Timestamp:          result.Timestamp.UnixNano(),
+		Duration:           int64(result.Duration),
+		ContiguousFailures: result.ContiguousFailures,
+	}
+	if result.Details != nil {
+		details, err := stdjson.Marshal(result.Details)
+		if err != nil {
+			return nil, err
+		}
+		protoResult.Details = details
+	}
+	if result.Error != nil {
+		protoResult.Error = result.Error.Error()
+	}
+	if result.TimeOfFirstFailure != nil {
+		protoResult.TimeOfFirstFailure = result.TimeOfFirstFailure.UnixNano()
+	}
+	return protoResult, nil
+}
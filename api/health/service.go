@@ -9,10 +9,10 @@ import (
 
 	stdjson "encoding/json"
 
+	"github.com/ava-labs/avalanchego/api/openapi"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
-	"github.com/gorilla/rpc/v2"
 	"github.com/prometheus/client_golang/prometheus"
 
 	health "github.com/AppsFlyer/go-sundheit"
@@ -48,7 +48,7 @@ type apiServer struct {
 }
 
 func (as *apiServer) Handler() (*common.HTTPHandler, error) {
-	newServer := rpc.NewServer()
+	newServer := openapi.NewServer()
 	codec := json.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
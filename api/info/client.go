@@ -6,6 +6,7 @@ package info
 import (
 	"time"
 
+	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 )
@@ -29,6 +30,13 @@ func (c *Client) GetNodeID() (string, error) {
 	return res.NodeID, err
 }
 
+// GetBLSKeyRegistration ...
+func (c *Client) GetBLSKeyRegistration() (api.BLSKeyRegistration, error) {
+	res := api.BLSKeyRegistration{}
+	err := c.requester.SendRequest("getBLSKeyRegistration", struct{}{}, &res)
+	return res, err
+}
+
 // GetNetworkID ...
 func (c *Client) GetNetworkID() (uint32, error) {
 	res := &GetNetworkIDReply{}
@@ -75,6 +83,15 @@ func (c *Client) GetTxFee() (*GetTxFeeResponse, error) {
 	return res, err
 }
 
+// GetFeeLevels returns the current fee levels of [chain]
+func (c *Client) GetFeeLevels(chain string) (*GetFeeLevelsReply, error) {
+	res := &GetFeeLevelsReply{}
+	err := c.requester.SendRequest("getFeeLevels", &GetFeeLevelsArgs{
+		Chain: chain,
+	}, res)
+	return res, err
+}
+
 // GetNodeIP ...
 func (c *Client) GetNodeIP() (string, error) {
 	res := &GetNodeIPReply{}
@@ -0,0 +1,147 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package info
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/api/info/infoproto"
+)
+
+var _ infoproto.InfoServer = &Server{}
+
+// Server serves the Info API over gRPC
+type Server struct {
+	infoproto.UnimplementedInfoServer
+	service *Info
+}
+
+// NewServer returns a gRPC server for [service]
+func NewServer(service *Info) *Server {
+	return &Server{service: service}
+}
+
+func (s *Server) GetNodeVersion(
+	context.Context,
+	*infoproto.GetNodeVersionRequest,
+) (*infoproto.GetNodeVersionResponse, error) {
+	reply := GetNodeVersionReply{}
+	if err := s.service.GetNodeVersion(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &infoproto.GetNodeVersionResponse{
+		Version:         reply.Version,
+		GitCommit:       reply.GitCommit,
+		DatabaseVersion: reply.DatabaseVersion,
+	}, nil
+}
+
+func (s *Server) GetNodeID(
+	context.Context,
+	*infoproto.GetNodeIDRequest,
+) (*infoproto.GetNodeIDResponse, error) {
+	reply := GetNodeIDReply{}
+	if err := s.service.GetNodeID(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &infoproto.GetNodeIDResponse{NodeID: reply.NodeID}, nil
+}
+
+func (s *Server) GetNodeIP(
+	context.Context,
+	*infoproto.GetNodeIPRequest,
+) (*infoproto.GetNodeIPResponse, error) {
+	reply := GetNodeIPReply{}
+	if err := s.service.GetNodeIP(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &infoproto.GetNodeIPResponse{Ip: reply.IP}, nil
+}
+
+func (s *Server) GetNetworkID(
+	context.Context,
+	*infoproto.GetNetworkIDRequest,
+) (*infoproto.GetNetworkIDResponse, error) {
+	reply := GetNetworkIDReply{}
+	if err := s.service.GetNetworkID(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &infoproto.GetNetworkIDResponse{NetworkID: uint32(reply.NetworkID)}, nil
+}
+
+func (s *Server) GetNetworkName(
+	context.Context,
+	*infoproto.GetNetworkNameRequest,
+) (*infoproto.GetNetworkNameResponse, error) {
+	reply := GetNetworkNameReply{}
+	if err := s.service.GetNetworkName(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &infoproto.GetNetworkNameResponse{NetworkName: reply.NetworkName}, nil
+}
+
+func (s *Server) GetBlockchainID(
+	_ context.Context,
+	req *infoproto.GetBlockchainIDRequest,
+) (*infoproto.GetBlockchainIDResponse, error) {
+	reply := GetBlockchainIDReply{}
+	if err := s.service.GetBlockchainID(nil, &GetBlockchainIDArgs{Alias: req.Alias}, &reply); err != nil {
+		return nil, err
+	}
+	return &infoproto.GetBlockchainIDResponse{BlockchainID: reply.BlockchainID}, nil
+}
+
+func (s *Server) Peers(
+	_ context.Context,
+	req *infoproto.PeersRequest,
+) (*infoproto.PeersResponse, error) {
+	reply := PeersReply{}
+	if err := s.service.Peers(nil, &PeersArgs{NodeIDs: req.NodeIDs}, &reply); err != nil {
+		return nil, err
+	}
+
+	peers := make([]*infoproto.Peer, len(reply.Peers))
+	for i, peer := range reply.Peers {
+		benched := make([]string, len(peer.Benched))
+		for j, chainID := range peer.Benched {
+			benched[j] = chainID.String()
+		}
+		peers[i] = &infoproto.Peer{
+			Ip:           peer.IP,
+			PublicIP:     peer.PublicIP,
+			NodeID:       peer.ID,
+			Version:      peer.Version,
+			LastSent:     peer.LastSent.UnixNano(),
+			LastReceived: peer.LastReceived.UnixNano(),
+			Benched:      benched,
+			Capabilities: peer.Capabilities,
+		}
+	}
+	return &infoproto.PeersResponse{Peers: peers}, nil
+}
+
+func (s *Server) IsBootstrapped(
+	_ context.Context,
+	req *infoproto.IsBootstrappedRequest,
+) (*infoproto.IsBootstrappedResponse, error) {
+	reply := IsBootstrappedResponse{}
+	if err := s.service.IsBootstrapped(nil, &IsBootstrappedArgs{Chain: req.Chain}, &reply); err != nil {
+		return nil, err
+	}
+	return &infoproto.IsBootstrappedResponse{IsBootstrapped: reply.IsBootstrapped}, nil
+}
+
+func (s *Server) GetTxFee(
+	context.Context,
+	*infoproto.GetTxFeeRequest,
+) (*infoproto.GetTxFeeResponse, error) {
+	reply := GetTxFeeResponse{}
+	if err := s.service.GetTxFee(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &infoproto.GetTxFeeResponse{
+		CreationTxFee: uint64(reply.CreationTxFee),
+		TxFee:         uint64(reply.TxFee),
+	}, nil
+}
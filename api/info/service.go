@@ -4,21 +4,32 @@
 package info
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
-	"github.com/gorilla/rpc/v2"
-
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/openapi"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/version"
 )
 
+var (
+	errUnknownChain = errors.New("unknown chain")
+	errNoFeeLevels  = errors.New("chain doesn't report fee levels")
+	errNoBLSKey     = errors.New("node doesn't have a BLS key")
+)
+
 // Info is the API service for unprivileged info on a node
 type Info struct {
 	version       version.Application
@@ -29,6 +40,13 @@ type Info struct {
 	chainManager  chains.Manager
 	creationTxFee uint64
 	txFee         uint64
+
+	// Registration of the BLS key that the node attests to validator
+	// snapshots with
+	blsKeyRegistration *validators.BLSKeyRegistration
+
+	// Chain ID --> Consensus engine of the chain
+	engines ids.ConcurrentMap
 }
 
 // NewService returns a new admin API service
@@ -41,12 +59,24 @@ func NewService(
 	peers network.Network,
 	creationTxFee uint64,
 	txFee uint64,
+	blsKeyRegistration *validators.BLSKeyRegistration,
 ) (*common.HTTPHandler, error) {
-	newServer := rpc.NewServer()
-	codec := json.NewCodec()
-	newServer.RegisterCodec(codec, "application/json")
-	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := newServer.RegisterService(&Info{
+	return New(log, version, nodeID, networkID, chainManager, peers, creationTxFee, txFee, blsKeyRegistration).Handler()
+}
+
+// New returns a new Info service
+func New(
+	log logging.Logger,
+	version version.Application,
+	nodeID ids.ShortID,
+	networkID uint32,
+	chainManager chains.Manager,
+	peers network.Network,
+	creationTxFee uint64,
+	txFee uint64,
+	blsKeyRegistration *validators.BLSKeyRegistration,
+) *Info {
+	service := &Info{
 		version:       version,
 		nodeID:        nodeID,
 		networkID:     networkID,
@@ -55,7 +85,25 @@ func NewService(
 		networking:    peers,
 		creationTxFee: creationTxFee,
 		txFee:         txFee,
-	}, "info"); err != nil {
+
+		blsKeyRegistration: blsKeyRegistration,
+	}
+	chainManager.AddRegistrant(service)
+	return service
+}
+
+// RegisterChain implements the chains.Registrant interface
+func (service *Info) RegisterChain(_ string, ctx *snow.Context, engine common.Engine) {
+	service.engines.Put(ctx.ChainID, engine)
+}
+
+// Handler returns a handler that serves this service over JSON-RPC
+func (service *Info) Handler() (*common.HTTPHandler, error) {
+	newServer := openapi.NewServer()
+	codec := json.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	if err := newServer.RegisterService(service, "info"); err != nil {
 		return nil, err
 	}
 	return &common.HTTPHandler{Handler: newServer}, nil
@@ -87,7 +135,35 @@ type GetNodeIDReply struct {
 func (service *Info) GetNodeID(_ *http.Request, _ *struct{}, reply *GetNodeIDReply) error {
 	service.log.Info("Info: GetNodeID called")
 
-	reply.NodeID = service.nodeID.PrefixedString(constants.NodeIDPrefix)
+	reply.NodeID = address.FormatNodeID(service.nodeID)
+	return nil
+}
+
+// GetBLSKeyRegistration returns the registration of the BLS key that this
+// node attests to validator snapshots with, which can be issued to the
+// platform chain with platform.registerBLSKey
+func (service *Info) GetBLSKeyRegistration(_ *http.Request, _ *struct{}, reply *api.BLSKeyRegistration) error {
+	service.log.Info("Info: GetBLSKeyRegistration called")
+
+	if service.blsKeyRegistration == nil {
+		return errNoBLSKey
+	}
+	fields := []struct {
+		bytes []byte
+		str   *string
+	}{
+		{service.blsKeyRegistration.Certificate, &reply.Certificate},
+		{service.blsKeyRegistration.PublicKey, &reply.PublicKey},
+		{service.blsKeyRegistration.ProofOfPossession, &reply.ProofOfPossession},
+		{service.blsKeyRegistration.Signature, &reply.Signature},
+	}
+	for _, field := range fields {
+		str, err := formatting.Encode(formatting.Hex, field.bytes)
+		if err != nil {
+			return err
+		}
+		*field.str = str
+	}
 	return nil
 }
 
@@ -153,9 +229,9 @@ type PeersReply struct {
 func (service *Info) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply) error {
 	service.log.Info("Info: Peers called")
 
-	nodeIDs := make([]ids.ShortID, 0, len(args.NodeIDs))
+	nodeIDs := make([]ids.NodeID, 0, len(args.NodeIDs))
 	for _, nodeID := range args.NodeIDs {
-		nID, err := ids.ShortFromPrefixedString(nodeID, constants.NodeIDPrefix)
+		nID, err := ids.NodeIDFromString(nodeID)
 		if err != nil {
 			return err
 		}
@@ -208,6 +284,55 @@ func (service *Info) GetTxFee(_ *http.Request, args *struct{}, reply *GetTxFeeRe
 	return nil
 }
 
+// GetFeeLevelsArgs are the arguments for calling GetFeeLevels
+type GetFeeLevelsArgs struct {
+	// Alias or ID of the chain
+	Chain string `json:"chain"`
+}
+
+// GetFeeLevelsReply are the fee levels that the VM of a chain reports
+type GetFeeLevelsReply struct {
+	// How close the chain is to its capacity, from 0 to 1
+	Congestion float64 `json:"congestion"`
+	// Fee, in nAVAX, that every transaction must currently pay
+	BaseFee json.Uint64 `json:"baseFee"`
+	// Fee, in nAVAX, that a transaction should pay to be issued soon
+	SuggestedFee json.Uint64 `json:"suggestedFee"`
+}
+
+// GetFeeLevels returns the current fee levels of [args.Chain]. Returns an
+// error if the VM of the chain doesn't report its fee levels.
+func (service *Info) GetFeeLevels(_ *http.Request, args *GetFeeLevelsArgs, reply *GetFeeLevelsReply) error {
+	service.log.Info("Info: GetFeeLevels called with chain: %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("there is no chain with alias/ID '%s'", args.Chain)
+	}
+	engineIntf, ok := service.engines.Get(chainID)
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnknownChain, chainID)
+	}
+	engine := engineIntf.(common.Engine)
+	reporter, ok := engine.GetVM().(common.FeeReporter)
+	if !ok {
+		return fmt.Errorf("%w: %s", errNoFeeLevels, chainID)
+	}
+
+	ctx := engine.Context()
+	ctx.Lock.Lock()
+	levels, err := reporter.FeeLevels()
+	ctx.Lock.Unlock()
+	if err != nil {
+		return fmt.Errorf("couldn't get fee levels of chain %s: %w", chainID, err)
+	}
+
+	reply.Congestion = levels.Congestion
+	reply.BaseFee = json.Uint64(levels.BaseFee)
+	reply.SuggestedFee = json.Uint64(levels.SuggestedFee)
+	return nil
+}
+
 // GetNodeIPReply are the results from calling GetNodeVersion
 type GetNodeIPReply struct {
 	IP string `json:"ip"`
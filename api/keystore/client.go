@@ -49,6 +49,23 @@ func (c *Client) ExportUser(user api.UserPass) ([]byte, error) {
 	return formatting.Decode(res.Encoding, res.User)
 }
 
+// ExportUserEncrypted returns the byte representation of the requested
+// [user], encrypted with [encryptionPassword]
+func (c *Client) ExportUserEncrypted(user api.UserPass, encryptionPassword string) ([]byte, error) {
+	res := &ExportUserReply{
+		Encoding: formatting.Hex,
+	}
+	err := c.requester.SendRequest("exportUser", &ExportUserArgs{
+		UserPass:           user,
+		Encoding:           formatting.Hex,
+		EncryptionPassword: encryptionPassword,
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+	return formatting.Decode(res.Encoding, res.User)
+}
+
 // ImportUser imports the keystore user in [account] under [user]
 func (c *Client) ImportUser(user api.UserPass, account []byte) (bool, error) {
 	accountStr, err := formatting.Encode(formatting.Hex, account)
@@ -65,6 +82,24 @@ func (c *Client) ImportUser(user api.UserPass, account []byte) (bool, error) {
 	return res.Success, err
 }
 
+// ImportUserEncrypted imports the keystore user in [account], which was
+// encrypted with [encryptionPassword], under [user]
+func (c *Client) ImportUserEncrypted(user api.UserPass, account []byte, encryptionPassword string) (bool, error) {
+	accountStr, err := formatting.Encode(formatting.Hex, account)
+	if err != nil {
+		return false, err
+	}
+
+	res := &api.SuccessResponse{}
+	err = c.requester.SendRequest("importUser", &ImportUserArgs{
+		UserPass:           user,
+		User:               accountStr,
+		Encoding:           formatting.Hex,
+		EncryptionPassword: encryptionPassword,
+	}, res)
+	return res.Success, err
+}
+
 // DeleteUser removes [user] from the node's keystore users
 func (c *Client) DeleteUser(user api.UserPass) (bool, error) {
 	res := &api.SuccessResponse{}
@@ -0,0 +1,154 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// Parameters of the argon2id key derivation of newly encrypted users
+	argon2Time    = 3
+	argon2Memory  = 64 * 1024 // In KiB
+	argon2Threads = 4
+	argon2SaltLen = 16
+
+	// Bounds on the argon2id parameters of imported users. Importing a user
+	// isn't authenticated, so an imported user may cost at most twice as much
+	// to derive the key of as an exported one.
+	maxArgon2Time    = 2 * argon2Time
+	maxArgon2Memory  = 2 * argon2Memory // In KiB
+	maxArgon2Threads = 2 * argon2Threads
+
+	// AES-256 is used to encrypt users
+	encryptionKeyLen = 32
+)
+
+var (
+	// Key derivations are serialized, so that concurrent imports can't use
+	// more than one derivation's memory at once
+	kdfSemaphore = make(chan struct{}, 1)
+
+	errInvalidKDFParams        = errors.New("invalid key derivation parameters")
+	errWrongEncryptionPassword = errors.New("wrong encryption password or corrupted user")
+)
+
+// encryptedUserHeader describes how an encrypted user was encrypted
+type encryptedUserHeader struct {
+	// Salt of the argon2id key derivation
+	Salt []byte `serialize:"true"`
+	// Time, memory in KiB, and threads of the argon2id key derivation
+	Time    uint32 `serialize:"true"`
+	Memory  uint32 `serialize:"true"`
+	Threads uint8  `serialize:"true"`
+	// Nonce of the AES-GCM encryption
+	Nonce []byte `serialize:"true"`
+}
+
+// encryptedUser is the portable encrypted container that users are exported
+// in when an encryption password is provided. It is serialized with the
+// keystore codec, so its first two bytes are its version, followed by the
+// header (the salt, argon2id time, memory and threads, and AES-GCM nonce) and
+// the ciphertext. The encryption key is the argon2id key of the encryption
+// password, and the plaintext is the serialized user. The serialized header is
+// authenticated as additional data, so that the parameters can't be tampered
+// with.
+type encryptedUser struct {
+	Header     encryptedUserHeader `serialize:"true"`
+	Ciphertext []byte              `serialize:"true"`
+}
+
+// encryptUser returns the encrypted container of the serialized user
+// [userBytes], which is encrypted with [encryptionPassword]
+func encryptUser(userBytes []byte, encryptionPassword string) ([]byte, error) {
+	header := encryptedUserHeader{
+		Salt:    make([]byte, argon2SaltLen),
+		Time:    argon2Time,
+		Memory:  argon2Memory,
+		Threads: argon2Threads,
+	}
+	if _, err := rand.Read(header.Salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := newUserAEAD(&header, encryptionPassword)
+	if err != nil {
+		return nil, err
+	}
+	header.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(header.Nonce); err != nil {
+		return nil, err
+	}
+	headerBytes, err := c.Marshal(codecVersion, &header)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Marshal(codecVersion, &encryptedUser{
+		Header:     header,
+		Ciphertext: aead.Seal(nil, header.Nonce, userBytes, headerBytes),
+	})
+}
+
+// decryptUser returns the serialized user in the encrypted container
+// [encryptedUserBytes], which was encrypted with [encryptionPassword]
+func decryptUser(encryptedUserBytes []byte, encryptionPassword string) ([]byte, error) {
+	container := encryptedUser{}
+	if _, err := c.Unmarshal(encryptedUserBytes, &container); err != nil {
+		return nil, fmt.Errorf("couldn't parse encrypted user: %w", err)
+	}
+	header := &container.Header
+	switch {
+	case len(header.Salt) == 0,
+		header.Time == 0 || header.Time > maxArgon2Time,
+		header.Memory == 0 || header.Memory > maxArgon2Memory,
+		header.Threads == 0 || header.Threads > maxArgon2Threads:
+		return nil, errInvalidKDFParams
+	}
+
+	aead, err := newUserAEAD(header, encryptionPassword)
+	if err != nil {
+		return nil, err
+	}
+	if len(header.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("expected nonce of length %d but got %d", aead.NonceSize(), len(header.Nonce))
+	}
+	headerBytes, err := c.Marshal(codecVersion, header)
+	if err != nil {
+		return nil, err
+	}
+
+	userBytes, err := aead.Open(nil, header.Nonce, container.Ciphertext, headerBytes)
+	if err != nil {
+		return nil, errWrongEncryptionPassword
+	}
+	return userBytes, nil
+}
+
+// newUserAEAD returns the AES-GCM cipher keyed with the argon2id key of
+// [encryptionPassword] under the parameters of [header]
+func newUserAEAD(header *encryptedUserHeader, encryptionPassword string) (cipher.AEAD, error) {
+	kdfSemaphore <- struct{}{}
+	key := argon2.IDKey(
+		[]byte(encryptionPassword),
+		header.Salt,
+		header.Time,
+		header.Memory,
+		header.Threads,
+		encryptionKeyLen,
+	)
+	<-kdfSemaphore
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
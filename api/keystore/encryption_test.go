@@ -0,0 +1,65 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptUser(t *testing.T) {
+	assert := assert.New(t)
+
+	userBytes := []byte("user")
+	encryptedUserBytes, err := encryptUser(userBytes, "password")
+	assert.NoError(err)
+
+	decryptedUserBytes, err := decryptUser(encryptedUserBytes, "password")
+	assert.NoError(err)
+	assert.Equal(userBytes, decryptedUserBytes)
+
+	_, err = decryptUser(encryptedUserBytes, "wrong password")
+	assert.ErrorIs(err, errWrongEncryptionPassword)
+
+	// Encrypting the same user twice uses a different salt and nonce
+	otherEncryptedUserBytes, err := encryptUser(userBytes, "password")
+	assert.NoError(err)
+	assert.NotEqual(encryptedUserBytes, otherEncryptedUserBytes)
+}
+
+func TestDecryptUserTampered(t *testing.T) {
+	assert := assert.New(t)
+
+	encryptedUserBytes, err := encryptUser([]byte("user"), "password")
+	assert.NoError(err)
+
+	container := encryptedUser{}
+	_, err = c.Unmarshal(encryptedUserBytes, &container)
+	assert.NoError(err)
+
+	// Tampering with the header is detected
+	container.Header.Time++
+	tamperedBytes, err := c.Marshal(codecVersion, &container)
+	assert.NoError(err)
+	_, err = decryptUser(tamperedBytes, "password")
+	assert.ErrorIs(err, errWrongEncryptionPassword)
+
+	// Key derivation parameters that are too expensive are refused
+	container.Header.Memory = maxArgon2Memory + 1
+	tamperedBytes, err = c.Marshal(codecVersion, &container)
+	assert.NoError(err)
+	_, err = decryptUser(tamperedBytes, "password")
+	assert.ErrorIs(err, errInvalidKDFParams)
+
+	container.Header.Memory = argon2Memory
+	container.Header.Threads = maxArgon2Threads + 1
+	tamperedBytes, err = c.Marshal(codecVersion, &container)
+	assert.NoError(err)
+	_, err = decryptUser(tamperedBytes, "password")
+	assert.ErrorIs(err, errInvalidKDFParams)
+
+	_, err = decryptUser([]byte{0, 0, 1}, "password")
+	assert.Error(err)
+}
@@ -9,8 +9,7 @@ import (
 	"net/http"
 	"sync"
 
-	"github.com/gorilla/rpc/v2"
-
+	"github.com/ava-labs/avalanchego/api/openapi"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/encdb"
@@ -124,7 +123,7 @@ func New(log logging.Logger, dbManager manager.Manager) (Keystore, error) {
 }
 
 func (ks *keystore) CreateHandler() (http.Handler, error) {
-	newServer := rpc.NewServer()
+	newServer := openapi.NewServer()
 	codec := jsoncodec.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
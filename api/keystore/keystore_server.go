@@ -0,0 +1,107 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/api/keystore/keystoreproto"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	errNoUser = errors.New("user not given")
+
+	_ keystoreproto.KeystoreServer = &Server{}
+)
+
+// Server serves the Keystore API over gRPC
+type Server struct {
+	keystoreproto.UnimplementedKeystoreServer
+	ks  Keystore
+	log logging.Logger
+}
+
+// NewServer returns a gRPC server for [ks]
+func NewServer(ks Keystore, log logging.Logger) *Server {
+	return &Server{
+		ks:  ks,
+		log: log,
+	}
+}
+
+func (s *Server) CreateUser(
+	_ context.Context,
+	req *keystoreproto.CreateUserRequest,
+) (*keystoreproto.CreateUserResponse, error) {
+	if req.User == nil {
+		return nil, errNoUser
+	}
+	s.log.Info("Keystore: CreateUser called with %.*s", maxUserLen, req.User.Username)
+
+	if err := s.ks.CreateUser(req.User.Username, req.User.Password); err != nil {
+		return nil, err
+	}
+	return &keystoreproto.CreateUserResponse{}, nil
+}
+
+func (s *Server) DeleteUser(
+	_ context.Context,
+	req *keystoreproto.DeleteUserRequest,
+) (*keystoreproto.DeleteUserResponse, error) {
+	if req.User == nil {
+		return nil, errNoUser
+	}
+	s.log.Info("Keystore: DeleteUser called with %s", req.User.Username)
+
+	if err := s.ks.DeleteUser(req.User.Username, req.User.Password); err != nil {
+		return nil, err
+	}
+	return &keystoreproto.DeleteUserResponse{}, nil
+}
+
+func (s *Server) ListUsers(
+	context.Context,
+	*keystoreproto.ListUsersRequest,
+) (*keystoreproto.ListUsersResponse, error) {
+	s.log.Info("Keystore: ListUsers called")
+
+	users, err := s.ks.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	return &keystoreproto.ListUsersResponse{Users: users}, nil
+}
+
+func (s *Server) ImportUser(
+	_ context.Context,
+	req *keystoreproto.ImportUserRequest,
+) (*keystoreproto.ImportUserResponse, error) {
+	if req.User == nil {
+		return nil, errNoUser
+	}
+	s.log.Info("Keystore: ImportUser called for %s", req.User.Username)
+
+	if err := s.ks.ImportUser(req.User.Username, req.User.Password, req.UserBytes); err != nil {
+		return nil, err
+	}
+	return &keystoreproto.ImportUserResponse{}, nil
+}
+
+func (s *Server) ExportUser(
+	_ context.Context,
+	req *keystoreproto.ExportUserRequest,
+) (*keystoreproto.ExportUserResponse, error) {
+	if req.User == nil {
+		return nil, errNoUser
+	}
+	s.log.Info("Keystore: ExportUser called for %s", req.User.Username)
+
+	userBytes, err := s.ks.ExportUser(req.User.Username, req.User.Password)
+	if err != nil {
+		return nil, err
+	}
+	return &keystoreproto.ExportUserResponse{UserBytes: userBytes}, nil
+}
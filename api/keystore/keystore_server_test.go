@@ -0,0 +1,57 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api/keystore/keystoreproto"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestServerUsers(t *testing.T) {
+	assert := assert.New(t)
+
+	ks, err := CreateTestKeystore()
+	assert.NoError(err)
+	s := NewServer(ks, logging.NoLog{})
+	ctx := context.Background()
+	user := &keystoreproto.UserPass{
+		Username: "bob",
+		Password: strongPassword,
+	}
+
+	_, err = s.CreateUser(ctx, &keystoreproto.CreateUserRequest{})
+	assert.Error(err, "should have errored due to the missing user")
+
+	_, err = s.CreateUser(ctx, &keystoreproto.CreateUserRequest{User: user})
+	assert.NoError(err)
+
+	users, err := s.ListUsers(ctx, &keystoreproto.ListUsersRequest{})
+	assert.NoError(err)
+	assert.Equal([]string{"bob"}, users.Users)
+
+	exported, err := s.ExportUser(ctx, &keystoreproto.ExportUserRequest{User: user})
+	assert.NoError(err)
+
+	_, err = s.DeleteUser(ctx, &keystoreproto.DeleteUserRequest{User: user})
+	assert.NoError(err)
+
+	users, err = s.ListUsers(ctx, &keystoreproto.ListUsersRequest{})
+	assert.NoError(err)
+	assert.Empty(users.Users)
+
+	_, err = s.ImportUser(ctx, &keystoreproto.ImportUserRequest{
+		User:      user,
+		UserBytes: exported.UserBytes,
+	})
+	assert.NoError(err)
+
+	users, err = s.ListUsers(ctx, &keystoreproto.ListUsersRequest{})
+	assert.NoError(err)
+	assert.Equal([]string{"bob"}, users.Users)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/password"
 	"github.com/ava-labs/avalanchego/version"
 )
 
@@ -52,6 +53,9 @@ type ImportUserArgs struct {
 	User string `json:"user"`
 	// The encoding of [User] ("hex" or "cb58")
 	Encoding formatting.Encoding `json:"encoding"`
+	// If non-empty, [User] is an encrypted user that is decrypted with this
+	// password
+	EncryptionPassword string `json:"encryptionPassword"`
 }
 
 func (s *service) ImportUser(r *http.Request, args *ImportUserArgs, reply *api.SuccessResponse) error {
@@ -62,6 +66,12 @@ func (s *service) ImportUser(r *http.Request, args *ImportUserArgs, reply *api.S
 	if err != nil {
 		return fmt.Errorf("couldn't decode 'user' to bytes: %w", err)
 	}
+	if args.EncryptionPassword != "" {
+		user, err = decryptUser(user, args.EncryptionPassword)
+		if err != nil {
+			return fmt.Errorf("couldn't decrypt user: %w", err)
+		}
+	}
 
 	reply.Success = true
 	return s.ks.ImportUser(args.Username, args.Password, user)
@@ -72,6 +82,9 @@ type ExportUserArgs struct {
 	api.UserPass
 	// The encoding for the exported user ("hex" or "cb58")
 	Encoding formatting.Encoding `json:"encoding"`
+	// If non-empty, the user is exported in an encrypted container that is
+	// encrypted with this password
+	EncryptionPassword string `json:"encryptionPassword"`
 }
 
 type ExportUserReply struct {
@@ -84,10 +97,22 @@ type ExportUserReply struct {
 func (s *service) ExportUser(_ *http.Request, args *ExportUserArgs, reply *ExportUserReply) error {
 	s.ks.log.Info("Keystore: ExportUser called for %s", args.Username)
 
+	if args.EncryptionPassword != "" {
+		if err := password.IsValid(args.EncryptionPassword, password.OK); err != nil {
+			return fmt.Errorf("invalid encryption password: %w", err)
+		}
+	}
+
 	userBytes, err := s.ks.ExportUser(args.Username, args.Password)
 	if err != nil {
 		return err
 	}
+	if args.EncryptionPassword != "" {
+		userBytes, err = encryptUser(userBytes, args.EncryptionPassword)
+		if err != nil {
+			return fmt.Errorf("couldn't encrypt user: %w", err)
+		}
+	}
 
 	// Encode the user from bytes to string
 	reply.User, err = formatting.Encode(args.Encoding, userBytes)
@@ -525,3 +525,94 @@ func TestMigrateKeystoreUser(t *testing.T) {
 		t.Fatalf("Expected value: %s, but found %s", value, v2)
 	}
 }
+
+func TestServiceExportImportEncrypted(t *testing.T) {
+	ks, err := CreateTestKeystore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := service{ks: ks.(*keystore)}
+
+	userPass := api.UserPass{
+		Username: "bob",
+		Password: strongPassword,
+	}
+	if err := s.CreateUser(nil, &userPass, &api.SuccessResponse{}); err != nil {
+		t.Fatal(err)
+	}
+	{
+		db, err := ks.GetDatabase(ids.Empty, "bob", strongPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put([]byte("hello"), []byte("world")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	encryptionPassword := strongPassword + "export"
+	{
+		exportReply := ExportUserReply{}
+		if err := s.ExportUser(nil, &ExportUserArgs{
+			UserPass:           userPass,
+			Encoding:           formatting.Hex,
+			EncryptionPassword: "weak",
+		}, &exportReply); err == nil {
+			t.Fatal("Should have errored due to weak encryption password")
+		}
+	}
+
+	exportReply := ExportUserReply{}
+	if err := s.ExportUser(nil, &ExportUserArgs{
+		UserPass:           userPass,
+		Encoding:           formatting.Hex,
+		EncryptionPassword: encryptionPassword,
+	}, &exportReply); err != nil {
+		t.Fatal(err)
+	}
+
+	newKS, err := CreateTestKeystore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newS := service{ks: newKS.(*keystore)}
+
+	if err := newS.ImportUser(nil, &ImportUserArgs{
+		UserPass: userPass,
+		User:     exportReply.User,
+		Encoding: formatting.Hex,
+	}, &api.SuccessResponse{}); err == nil {
+		t.Fatal("Should have errored due to the user being encrypted")
+	}
+	if err := newS.ImportUser(nil, &ImportUserArgs{
+		UserPass:           userPass,
+		User:               exportReply.User,
+		Encoding:           formatting.Hex,
+		EncryptionPassword: strongPassword,
+	}, &api.SuccessResponse{}); err == nil {
+		t.Fatal("Should have errored due to incorrect encryption password")
+	}
+
+	reply := api.SuccessResponse{}
+	if err := newS.ImportUser(nil, &ImportUserArgs{
+		UserPass:           userPass,
+		User:               exportReply.User,
+		Encoding:           formatting.Hex,
+		EncryptionPassword: encryptionPassword,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Success {
+		t.Fatalf("User should have been imported successfully")
+	}
+
+	db, err := newKS.GetDatabase(ids.Empty, "bob", strongPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, err := db.Get([]byte("hello")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(val, []byte("world")) {
+		t.Fatalf("Should have read '%s' from the db", "world")
+	}
+}
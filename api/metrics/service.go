@@ -10,14 +10,18 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 )
 
-// NewService returns a new prometheus service
-func NewService() (*prometheus.Registry, *common.HTTPHandler) {
+// NewService returns a new prometheus service. If [enableOpenMetrics], the
+// metrics are served in the OpenMetrics format to scrapers that ask for it,
+// which includes the exemplars of the metrics.
+func NewService(enableOpenMetrics bool) (*prometheus.Registry, *common.HTTPHandler) {
 	registerer := prometheus.NewRegistry()
 	handler := promhttp.InstrumentMetricHandler(
 		registerer,
 		promhttp.HandlerFor(
 			registerer,
-			promhttp.HandlerOpts{},
+			promhttp.HandlerOpts{
+				EnableOpenMetrics: enableOpenMetrics,
+			},
 		),
 	)
 	return registerer, &common.HTTPHandler{LockOptions: common.NoLock, Handler: handler}
@@ -0,0 +1,209 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// Version of the OpenAPI specification that documents conform to
+	Version = "3.0.3"
+
+	jsonRPCVersion   = "2.0"
+	jsonContentType  = "application/json"
+	errorSchemaName  = "JSONRPCError"
+	schemaRefPrefix  = "#/components/schemas/"
+	successfulStatus = "200"
+)
+
+var (
+	requestType = reflect.TypeOf((*http.Request)(nil))
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Document is an OpenAPI document. Every JSON-RPC method is documented as a
+// POST operation on the path "<route>#<method>", since all the methods of a
+// route share its URL.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+
+	schemas      *schemaGenerator
+	operationIDs map[string]struct{}
+}
+
+// Info describes the API of a document
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem describes the operations available on a path
+type PathItem struct {
+	Post *Operation `json:"post,omitempty"`
+}
+
+// Operation describes a single API operation
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// RequestBody describes the body of a request
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response describes a response to an operation
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType describes the content of a request or response
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the schemas that are referenced in a document
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// NewDocument returns an OpenAPI document, without any paths, of the API
+// called [title] at [version]
+func NewDocument(title, version string) *Document {
+	d := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths:        make(map[string]*PathItem),
+		schemas:      newSchemaGenerator(),
+		operationIDs: make(map[string]struct{}),
+	}
+	d.Components.Schemas = d.schemas.schemas
+	d.Components.Schemas[errorSchemaName] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":    {Type: "integer", Format: "int32"},
+			"message": {Type: "string"},
+			"data":    {},
+		},
+	}
+	return d
+}
+
+// AddServices documents the JSON-RPC methods of [services], which are served
+// on [path]
+func (d *Document) AddServices(path string, services []Service) {
+	for _, service := range services {
+		receiverType := reflect.TypeOf(service.Receiver)
+		for i := 0; i < receiverType.NumMethod(); i++ {
+			method := receiverType.Method(i)
+			argsType, replyType, ok := rpcMethodTypes(method)
+			if !ok {
+				continue
+			}
+			methodName := fmt.Sprintf("%s.%s", service.Name, lowerFirst(method.Name))
+			d.Paths[fmt.Sprintf("%s#%s", path, methodName)] = &PathItem{
+				Post: d.operation(service.Name, methodName, argsType, replyType),
+			}
+		}
+	}
+}
+
+func (d *Document) operation(serviceName, methodName string, argsType, replyType reflect.Type) *Operation {
+	request := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"jsonrpc": {Type: "string", Enum: []interface{}{jsonRPCVersion}},
+			"id":      {Type: "integer", Format: "int64"},
+			"method":  {Type: "string", Enum: []interface{}{methodName}},
+			"params":  d.schemas.schemaOf(argsType),
+		},
+		Required: []string{"jsonrpc", "id", "method", "params"},
+	}
+	response := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"jsonrpc": {Type: "string", Enum: []interface{}{jsonRPCVersion}},
+			"id":      {Type: "integer", Format: "int64"},
+			"result":  d.schemas.schemaOf(replyType),
+			"error":   {Ref: schemaRefPrefix + errorSchemaName},
+		},
+		Required: []string{"jsonrpc", "id"},
+	}
+	return &Operation{
+		OperationID: d.operationID(methodName),
+		Summary:     fmt.Sprintf("Calls the JSON-RPC method %s", methodName),
+		Tags:        []string{serviceName},
+		RequestBody: &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				jsonContentType: {Schema: request},
+			},
+		},
+		Responses: map[string]*Response{
+			successfulStatus: {
+				Description: "The JSON-RPC response",
+				Content: map[string]*MediaType{
+					jsonContentType: {Schema: response},
+				},
+			},
+		},
+	}
+}
+
+// operationID returns a unique operation ID for [methodName], which may be
+// served on multiple paths
+func (d *Document) operationID(methodName string) string {
+	operationID := methodName
+	for i := 2; ; i++ {
+		if _, exists := d.operationIDs[operationID]; !exists {
+			break
+		}
+		operationID = fmt.Sprintf("%s_%d", methodName, i)
+	}
+	d.operationIDs[operationID] = struct{}{}
+	return operationID
+}
+
+// rpcMethodTypes returns the argument and reply types of [method] if it is
+// served by a JSON-RPC server. That is, if it takes a *http.Request, a pointer
+// to its arguments and a pointer to its reply, and returns an error.
+func rpcMethodTypes(method reflect.Method) (reflect.Type, reflect.Type, bool) {
+	methodType := method.Type
+	if method.PkgPath != "" || methodType.NumIn() != 4 || methodType.NumOut() != 1 {
+		return nil, nil, false
+	}
+	argsType := methodType.In(2)
+	replyType := methodType.In(3)
+	if methodType.In(1) != requestType ||
+		argsType.Kind() != reflect.Ptr ||
+		replyType.Kind() != reflect.Ptr ||
+		methodType.Out(0) != errorType {
+		return nil, nil, false
+	}
+	return argsType.Elem(), replyType.Elem(), true
+}
+
+// lowerFirst returns [name] with its first letter lowercased, which is how
+// method names are called over JSON-RPC
+func lowerFirst(name string) string {
+	firstRune, runeLen := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(firstRune)) + name[runeLen:]
+}
@@ -0,0 +1,101 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+type Header struct {
+	Username string `json:"username"`
+	Password string `json:"-"`
+}
+
+type Node struct {
+	ID       ids.ID          `json:"id"`
+	Children []*Node         `json:"children"`
+	Weight   cjson.Uint64    `json:"weight"`
+	Time     time.Time       `json:"time"`
+	Bytes    []byte          `json:"bytes"`
+	Labels   map[string]bool `json:"labels,omitempty"`
+	Count    uint32          `json:"count,string"`
+	Value    interface{}     `json:"value"`
+	Parent   *string         `json:"parent"`
+	NoTag    int
+	private  int
+}
+
+type TreeArgs struct {
+	Header
+	Root Node `json:"root"`
+}
+
+type TreeReply struct {
+	Username string `json:"username"`
+	Size     int    `json:"size"`
+}
+
+type TreeService struct{}
+
+func (s *TreeService) GetTree(_ *http.Request, args *TreeArgs, reply *TreeReply) error {
+	return nil
+}
+
+func (s *TreeService) NotAMethod(_ *http.Request, args TreeArgs, reply *TreeReply) error {
+	return nil
+}
+
+func TestDocument(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := NewDocument("test API", "1.2.3")
+	services := []Service{{Name: "tree", Receiver: &TreeService{}}}
+	doc.AddServices("/ext/a", services)
+	doc.AddServices("/ext/b", services)
+
+	assert.Equal(Version, doc.OpenAPI)
+	assert.Equal("1.2.3", doc.Info.Version)
+	assert.Len(doc.Paths, 2)
+
+	operationA := doc.Paths["/ext/a#tree.getTree"].Post
+	operationB := doc.Paths["/ext/b#tree.getTree"].Post
+	assert.Equal("tree.getTree", operationA.OperationID)
+	assert.Equal("tree.getTree_2", operationB.OperationID)
+	assert.Equal([]string{"tree"}, operationA.Tags)
+
+	request := operationA.RequestBody.Content[jsonContentType].Schema
+	assert.Equal([]interface{}{"tree.getTree"}, request.Properties["method"].Enum)
+	assert.Equal(schemaRefPrefix+"openapi.TreeArgs", request.Properties["params"].Ref)
+	response := operationA.Responses[successfulStatus].Content[jsonContentType].Schema
+	assert.Equal(schemaRefPrefix+"openapi.TreeReply", response.Properties["result"].Ref)
+
+	args := doc.Components.Schemas["openapi.TreeArgs"]
+	assert.Len(args.Properties, 2)
+	assert.Equal("string", args.Properties["username"].Type)
+	assert.Equal(schemaRefPrefix+"openapi.Node", args.Properties["root"].Ref)
+
+	node := doc.Components.Schemas["openapi.Node"]
+	assert.Len(node.Properties, 10)
+	assert.Equal(&Schema{Type: "string"}, node.Properties["id"])
+	assert.Equal(&Schema{Type: "array", Items: &Schema{Ref: schemaRefPrefix + "openapi.Node"}}, node.Properties["children"])
+	assert.Equal(&Schema{Type: "string"}, node.Properties["weight"])
+	assert.Equal(&Schema{Type: "string", Format: "date-time"}, node.Properties["time"])
+	assert.Equal(&Schema{Type: "string", Format: "byte"}, node.Properties["bytes"])
+	assert.Equal(&Schema{Type: "object", AdditionalProperties: &Schema{Type: "boolean"}}, node.Properties["labels"])
+	assert.Equal(&Schema{Type: "string"}, node.Properties["count"])
+	assert.Equal(&Schema{}, node.Properties["value"])
+	assert.Equal(&Schema{Type: "string", Nullable: true}, node.Properties["parent"])
+	assert.Equal(&Schema{Type: "integer", Format: "int64"}, node.Properties["NoTag"])
+
+	_, err := json.Marshal(doc)
+	assert.NoError(err)
+}
@@ -0,0 +1,195 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Schema describes the JSON encoding of a type
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// schemaGenerator derives schemas from types. Named struct types are added to
+// [schemas] and referenced, so that recursive types can be described.
+type schemaGenerator struct {
+	// schema name --> schema
+	schemas map[string]*Schema
+	// type --> schema name
+	names map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schemaOf returns the schema of the JSON encoding of [t]
+func (g *schemaGenerator) schemaOf(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Interface:
+		// Any value may be encoded
+		return &Schema{}
+	case t.Kind() != reflect.Ptr && isMarshaler(t):
+		if t.Kind() == reflect.Struct {
+			// The encoding can't be derived from the type
+			return &Schema{}
+		}
+		// Types with custom encodings, such as IDs and quoted integers, are
+		// encoded as strings
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := g.schemaOf(t.Elem())
+		if schema.Ref != "" {
+			// Properties can't be added to references
+			return schema
+		}
+		nullable := *schema
+		nullable.Nullable = true
+		return &nullable
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && !isMarshaler(t.Elem()) {
+			// Byte slices are encoded in base64
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+			g.addFields(t, schema, false)
+			return schema
+		}
+		return g.refOf(t)
+	default:
+		// Channels, functions and complex numbers can't be encoded
+		return &Schema{}
+	}
+}
+
+// refOf returns a reference to the schema of the named struct [t], adding the
+// schema to [g.schemas] if it wasn't already
+func (g *schemaGenerator) refOf(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = g.newName(t)
+		g.names[t] = name
+
+		// The schema is added before its fields, so that the fields can refer
+		// to it
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		g.schemas[name] = schema
+		g.addFields(t, schema, false)
+	}
+	return &Schema{Ref: schemaRefPrefix + name}
+}
+
+// newName returns an unused schema name for [t], such as "avm.GetBalanceArgs"
+func (g *schemaGenerator) newName(t reflect.Type) string {
+	baseName := fmt.Sprintf("%s.%s", path.Base(t.PkgPath()), t.Name())
+	name := baseName
+	for i := 2; ; i++ {
+		if _, exists := g.schemas[name]; !exists {
+			return name
+		}
+		name = fmt.Sprintf("%s%d", baseName, i)
+	}
+}
+
+// addFields adds the encoded fields of the struct [t] to [schema]. Fields of
+// embedded structs are encoded as if they were fields of [t], unless [t]
+// has a field with the same name. [embedded] is true if [t] is embedded.
+func (g *schemaGenerator) addFields(t reflect.Type, schema *Schema, embedded bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		tagParts := strings.Split(tag, ",")
+		name := tagParts[0]
+
+		if field.Anonymous && name == "" {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct && !isMarshaler(fieldType) {
+				g.addFields(fieldType, schema, true)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			// Unexported fields aren't encoded
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, exists := schema.Properties[name]; exists && embedded {
+			continue
+		}
+
+		fieldSchema := g.schemaOf(field.Type)
+		for _, option := range tagParts[1:] {
+			if option == "string" {
+				// The ",string" option quotes numbers and booleans
+				fieldSchema = &Schema{Type: "string"}
+			}
+		}
+		schema.Properties[name] = fieldSchema
+	}
+}
+
+// isMarshaler returns true if [t] has a custom JSON or text encoding
+func isMarshaler(t reflect.Type) bool {
+	ptrType := reflect.PtrTo(t)
+	return t.Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) ||
+		ptrType.Implements(jsonMarshalerType) ||
+		ptrType.Implements(textMarshalerType)
+}
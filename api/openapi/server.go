@@ -0,0 +1,68 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openapi
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/gorilla/rpc/v2"
+)
+
+var _ Documented = &Server{}
+
+// Service is a JSON-RPC service that is served by a handler
+type Service struct {
+	// Name the service is registered under, which prefixes its method names
+	Name string
+	// Receiver whose methods are served
+	Receiver interface{}
+}
+
+// Documented is implemented by HTTP handlers that can list the JSON-RPC
+// services they serve, so that they can be included in an OpenAPI document
+type Documented interface {
+	Services() []Service
+}
+
+// Server is a JSON-RPC server that records the services registered with it
+type Server struct {
+	*rpc.Server
+
+	lock     sync.Mutex
+	services []Service
+}
+
+// NewServer returns a new JSON-RPC server
+func NewServer() *Server {
+	return &Server{Server: rpc.NewServer()}
+}
+
+// RegisterService registers [receiver] with the JSON-RPC server under [name]
+func (s *Server) RegisterService(receiver interface{}, name string) error {
+	if err := s.Server.RegisterService(receiver, name); err != nil {
+		return err
+	}
+	if name == "" {
+		// Mirror the JSON-RPC server, which defaults to the type's name
+		name = reflect.Indirect(reflect.ValueOf(receiver)).Type().Name()
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.services = append(s.services, Service{
+		Name:     name,
+		Receiver: receiver,
+	})
+	return nil
+}
+
+// Services implements the Documented interface
+func (s *Server) Services() []Service {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]Service(nil), s.services...)
+}
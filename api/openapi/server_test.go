@@ -0,0 +1,25 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerServices(t *testing.T) {
+	assert := assert.New(t)
+
+	server := NewServer()
+	assert.NoError(server.RegisterService(&TreeService{}, "tree"))
+	assert.NoError(server.RegisterService(&TreeService{}, ""))
+	assert.Error(server.RegisterService(&TreeService{}, "tree"))
+
+	services := server.Services()
+	if assert.Len(services, 2) {
+		assert.Equal("tree", services[0].Name)
+		assert.Equal("TreeService", services[1].Name)
+	}
+}
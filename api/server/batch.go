@@ -0,0 +1,192 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	rpc "github.com/gorilla/rpc/v2/json2"
+)
+
+// batchHandler serves JSON-RPC batch requests, which are arrays of requests,
+// by passing each request of the batch to [handler] separately and responding
+// with the array of their responses. Other requests are passed to [handler]
+// unmodified, and only the first bytes of their bodies are read. Each request
+// of a batch counts against the client's rate limit.
+type batchHandler struct {
+	maxBatchSize int
+	handler      http.Handler
+}
+
+func newBatchHandler(handler http.Handler, maxBatchSize int) http.Handler {
+	return &batchHandler{
+		maxBatchSize: maxBatchSize,
+		handler:      handler,
+	}
+}
+
+type batchErrorResponse struct {
+	Version string           `json:"jsonrpc"`
+	Err     *rpc.Error       `json:"error"`
+	ID      *json.RawMessage `json:"id"`
+}
+
+func (b *batchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.Body == nil || !isJSON(r) {
+		b.handler.ServeHTTP(w, r)
+		return
+	}
+
+	reader := bufio.NewReader(r.Body)
+	if !isBatch(reader) {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{reader, r.Body}
+		b.handler.ServeHTTP(w, r)
+		return
+	}
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("couldn't read request body: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var requests []json.RawMessage
+	if err := json.Unmarshal(body, &requests); err != nil {
+		writeJSON(w, newBatchError(rpc.E_PARSE, err.Error(), nil))
+		return
+	}
+	switch {
+	case len(requests) == 0:
+		writeJSON(w, newBatchError(rpc.E_INVALID_REQ, "empty batch request", nil))
+		return
+	case len(requests) > b.maxBatchSize:
+		writeJSON(w, newBatchError(
+			rpc.E_INVALID_REQ,
+			fmt.Sprintf("batch request contains %d requests but at most %d are allowed", len(requests), b.maxBatchSize),
+			nil,
+		))
+		return
+	}
+	// The batch was counted as one request by the rate limiter
+	if !allowMore(r, len(requests)-1) {
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return
+	}
+
+	responses := make([]json.RawMessage, 0, len(requests))
+	for _, request := range requests {
+		subRequest := r.Clone(r.Context())
+		subRequest.Body = ioutil.NopCloser(bytes.NewReader(request))
+		subRequest.ContentLength = int64(len(request))
+
+		recorder := newResponseRecorder()
+		b.handler.ServeHTTP(recorder, subRequest)
+
+		response := bytes.TrimSpace(recorder.body.Bytes())
+		switch {
+		case len(response) == 0:
+			// Notifications don't have a response
+			continue
+		case !json.Valid(response):
+			// The request failed before reaching the JSON-RPC server
+			responseBytes, err := json.Marshal(newBatchError(rpc.E_INTERNAL, string(response), requestID(request)))
+			if err != nil {
+				http.Error(w, fmt.Sprintf("couldn't marshal response: %s", err), http.StatusInternalServerError)
+				return
+			}
+			response = responseBytes
+		}
+		responses = append(responses, response)
+	}
+
+	if len(responses) == 0 {
+		// A batch of notifications doesn't have a response
+		return
+	}
+	writeJSON(w, responses)
+}
+
+// isJSON returns true if [r] may be a JSON-RPC request, which is sent with the
+// JSON content type, or without a content type
+func isJSON(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// isBatch returns true if the body read by [reader] is a JSON-RPC batch
+// request. Only the leading whitespace and the first character of the body
+// are peeked at.
+func isBatch(reader *bufio.Reader) bool {
+	for n := 1; n <= reader.Size(); n++ {
+		peeked, _ := reader.Peek(n)
+		if len(peeked) < n {
+			return false
+		}
+		switch peeked[n-1] {
+		case ' ', '\t', '\r', '\n':
+		case '[':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// requestID returns the ID of [request], or nil if it doesn't have one
+func requestID(request json.RawMessage) *json.RawMessage {
+	parsed := struct {
+		ID *json.RawMessage `json:"id"`
+	}{}
+	if err := json.Unmarshal(request, &parsed); err != nil {
+		return nil
+	}
+	return parsed.ID
+}
+
+func newBatchError(code rpc.ErrorCode, msg string, id *json.RawMessage) *batchErrorResponse {
+	return &batchErrorResponse{
+		Version: rpc.Version,
+		Err: &rpc.Error{
+			Code:    code,
+			Message: msg,
+		},
+		ID: id,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	// There isn't anything to do with the returned error, so it is dropped.
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// responseRecorder records the response to one request of a batch
+type responseRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: make(http.Header)}
+}
+
+func (r *responseRecorder) Header() http.Header { return r.header }
+
+func (r *responseRecorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+
+func (r *responseRecorder) WriteHeader(int) {}
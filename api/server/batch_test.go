@@ -0,0 +1,152 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+	"github.com/stretchr/testify/assert"
+)
+
+type EchoService struct{}
+
+type EchoArgs struct {
+	Msg string `json:"msg"`
+}
+
+type EchoReply struct {
+	Msg string `json:"msg"`
+}
+
+func (s *EchoService) Echo(_ *http.Request, args *EchoArgs, reply *EchoReply) error {
+	if args.Msg == "" {
+		return errors.New("empty message")
+	}
+	reply.Msg = args.Msg
+	return nil
+}
+
+func newEchoBatchHandler(t *testing.T, maxBatchSize int) http.Handler {
+	server := rpc.NewServer()
+	server.RegisterCodec(json2.NewCodec(), "application/json")
+	if err := server.RegisterService(&EchoService{}, "echo"); err != nil {
+		t.Fatal(err)
+	}
+	return newBatchHandler(server, maxBatchSize)
+}
+
+func serveBatch(handler http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/ext/echo", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestBatchHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := newEchoBatchHandler(t, 3)
+	rr := serveBatch(handler, `[
+		{"jsonrpc":"2.0","id":1,"method":"echo.Echo","params":{"msg":"a"}},
+		{"jsonrpc":"2.0","method":"echo.Echo","params":{"msg":"notification"}},
+		{"jsonrpc":"2.0","id":"2","method":"echo.Echo","params":{"msg":""}}
+	]`)
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var responses []struct {
+		ID     json.RawMessage `json:"id"`
+		Result *EchoReply      `json:"result"`
+		Error  *json2.Error    `json:"error"`
+	}
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &responses))
+	if assert.Len(responses, 2) {
+		assert.Equal("1", string(responses[0].ID))
+		assert.Equal("a", responses[0].Result.Msg)
+		assert.Equal(`"2"`, string(responses[1].ID))
+		assert.Equal("empty message", responses[1].Error.Message)
+	}
+}
+
+func TestBatchHandlerSingleRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := newEchoBatchHandler(t, 3)
+	rr := serveBatch(handler, `{"jsonrpc":"2.0","id":1,"method":"echo.Echo","params":{"msg":"a"}}`)
+	assert.Equal(http.StatusOK, rr.Code)
+
+	response := struct {
+		Result EchoReply `json:"result"`
+	}{}
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal("a", response.Result.Msg)
+}
+
+func TestBatchHandlerLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := newEchoBatchHandler(t, 1)
+	tests := []struct {
+		body string
+		code json2.ErrorCode
+	}{
+		{body: `[]`, code: json2.E_INVALID_REQ},
+		{body: `[{"jsonrpc":"2.0","id":1,"method":"echo.Echo"}, {"jsonrpc":"2.0","id":2,"method":"echo.Echo"}]`, code: json2.E_INVALID_REQ},
+		{body: `[{"jsonrpc":"2.0"`, code: json2.E_PARSE},
+	}
+	for _, test := range tests {
+		rr := serveBatch(handler, test.body)
+		response := batchErrorResponse{}
+		assert.NoError(json.Unmarshal(rr.Body.Bytes(), &response))
+		if assert.NotNil(response.Err, test.body) {
+			assert.Equal(test.code, response.Err.Code, test.body)
+		}
+	}
+}
+
+func TestBatchHandlerRateLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := NewRateLimiter(0.001, 4).WrapHandler(newEchoBatchHandler(t, 3))
+	batch := `[
+		{"jsonrpc":"2.0","id":1,"method":"echo.Echo","params":{"msg":"a"}},
+		{"jsonrpc":"2.0","id":2,"method":"echo.Echo","params":{"msg":"b"}},
+		{"jsonrpc":"2.0","id":3,"method":"echo.Echo","params":{"msg":"c"}}
+	]`
+
+	// Each request of a batch counts against the client's limit, so the
+	// second batch exceeds it
+	assert.Equal(http.StatusOK, serveBatch(handler, batch).Code)
+	assert.Equal(http.StatusTooManyRequests, serveBatch(handler, batch).Code)
+}
+
+func TestBatchHandlerIgnoresOtherContentTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	var read []byte
+	handler := newBatchHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		read, _ = ioutil.ReadAll(r.Body)
+	}), 1)
+
+	for _, contentType := range []string{"application/octet-stream", "application/json; charset=utf-8"} {
+		req := httptest.NewRequest(http.MethodPost, "/ext/echo", strings.NewReader(" {1}"))
+		req.Header.Set("Content-Type", contentType)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal([]byte(" {1}"), read, contentType)
+	}
+
+	// Only JSON bodies are parsed as batches
+	req := httptest.NewRequest(http.MethodPost, "/ext/echo", strings.NewReader("[1, 2]"))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal([]byte("[1, 2]"), read)
+}
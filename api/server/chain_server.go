@@ -0,0 +1,113 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/api/server/serverproto"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+const defaultCallContentType = "application/json"
+
+var (
+	_ serverproto.ChainServer = &ChainServer{}
+	_ http.ResponseWriter     = &callRecorder{}
+)
+
+// ChainLookup returns the ID of the chain with the given alias
+type ChainLookup interface {
+	Lookup(alias string) (ids.ID, error)
+}
+
+// ChainServer forwards calls made over gRPC to the HTTP APIs of the chains
+// that [server] serves, so that every chain's API, such as its JSON-RPC
+// API, can be called over gRPC.
+type ChainServer struct {
+	serverproto.UnimplementedChainServer
+	server *Server
+	chains ChainLookup
+}
+
+// NewChainServer returns a gRPC server that forwards calls to the chain APIs
+// of [server]
+func NewChainServer(server *Server, chains ChainLookup) *ChainServer {
+	return &ChainServer{
+		server: server,
+		chains: chains,
+	}
+}
+
+// Call sends the body of [req] to the chain's API as an HTTP POST request,
+// and returns the API's response. The response's status code is that of the
+// HTTP response, so a failed JSON-RPC call isn't a gRPC error.
+func (s *ChainServer) Call(
+	ctx context.Context,
+	req *serverproto.CallRequest,
+) (*serverproto.CallResponse, error) {
+	chainID, err := s.chains.Lookup(req.Chain)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	base := fmt.Sprintf("%s/bc/%s", baseURL, chainID)
+	handler, err := s.server.router.GetHandler(base, req.Endpoint)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "couldn't find %s%s: %s", base, req.Endpoint, err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, base+req.Endpoint, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	contentType := req.ContentType
+	if contentType == "" {
+		contentType = defaultCallContentType
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+	if p, ok := peer.FromContext(ctx); ok {
+		httpReq.RemoteAddr = p.Addr.String()
+	}
+
+	recorder := &callRecorder{
+		header:     make(http.Header),
+		statusCode: http.StatusOK,
+	}
+	handler.ServeHTTP(recorder, httpReq)
+	return &serverproto.CallResponse{
+		StatusCode:  uint32(recorder.statusCode),
+		Body:        recorder.body.Bytes(),
+		ContentType: recorder.header.Get("Content-Type"),
+	}, nil
+}
+
+// callRecorder keeps the response of a forwarded call
+type callRecorder struct {
+	header      http.Header
+	body        bytes.Buffer
+	statusCode  int
+	wroteHeader bool
+}
+
+func (r *callRecorder) Header() http.Header { return r.header }
+
+func (r *callRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(b)
+}
+
+func (r *callRecorder) WriteHeader(statusCode int) {
+	if r.wroteHeader {
+		return
+	}
+	r.wroteHeader = true
+	r.statusCode = statusCode
+}
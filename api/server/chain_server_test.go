@@ -0,0 +1,79 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/api/server/serverproto"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type testChainLookup map[string]ids.ID
+
+func (l testChainLookup) Lookup(alias string) (ids.ID, error) {
+	if chainID, ok := l[alias]; ok {
+		return chainID, nil
+	}
+	return ids.ID{}, errors.New("unknown chain")
+}
+
+func TestChainServerCall(t *testing.T) {
+	assert := assert.New(t)
+
+	s := Server{}
+	s.Initialize(
+		logging.NoLog{},
+		logging.NoFactory{},
+		"localhost",
+		8080,
+		[]string{"*"},
+		10,
+	)
+
+	serv := &Service{}
+	newServer := rpc.NewServer()
+	newServer.RegisterCodec(json2.NewCodec(), "application/json")
+	assert.NoError(newServer.RegisterService(serv, "test"))
+
+	chainID := ids.GenerateTestID()
+	err := s.AddRoute(
+		&common.HTTPHandler{Handler: newServer},
+		new(sync.RWMutex),
+		"bc/"+chainID.String(),
+		"/rpc",
+		logging.NoLog{},
+	)
+	assert.NoError(err)
+
+	chainServer := NewChainServer(&s, testChainLookup{"X": chainID})
+	resp, err := chainServer.Call(context.Background(), &serverproto.CallRequest{
+		Chain:    "X",
+		Endpoint: "/rpc",
+		Body:     []byte(`{"jsonrpc":"2.0","method":"test.Call","params":{},"id":1}`),
+	})
+	assert.NoError(err)
+	assert.True(serv.called)
+	assert.Equal(uint32(http.StatusOK), resp.StatusCode)
+	assert.Contains(string(resp.Body), `"result"`)
+
+	// Unknown chains and endpoints aren't found
+	_, err = chainServer.Call(context.Background(), &serverproto.CallRequest{Chain: "P"})
+	assert.Equal(codes.NotFound, status.Code(err))
+	_, err = chainServer.Call(context.Background(), &serverproto.CallRequest{Chain: "X", Endpoint: "/ws"})
+	assert.Equal(codes.NotFound, status.Code(err))
+}
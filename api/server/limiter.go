@@ -0,0 +1,265 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/cache"
+)
+
+const (
+	// Max number of clients whose rate limiters are kept in memory. When a
+	// client is evicted, it gets a new, full, bucket on its next request.
+	maxRateLimitedClients = 4096
+)
+
+var (
+	_ RateLimiter = &rateLimiter{}
+	_ Wrapper     = &requestSizeLimiter{}
+)
+
+// RateLimiter is a wrapper that limits the rate of requests of each client
+type RateLimiter interface {
+	Wrapper
+
+	// SetLimits changes the limits of every client. If [requestsPerSecond] is
+	// 0, requests aren't limited.
+	SetLimits(requestsPerSecond float64, burst int)
+
+	// IPLimiter returns a wrapper that limits the rate of requests of each IP,
+	// whether or not the client proves its identity. It must run before the
+	// wrappers that verify clients' identities, so that clients can't avoid
+	// being rate limited by sending invalid authorization tokens. Requests
+	// that it limited are only limited again by the rate limiter itself if
+	// their client's identity was verified.
+	IPLimiter() Wrapper
+
+	// UnaryInterceptor and StreamInterceptor limit the rate of gRPC calls.
+	// Calls share each client's limit with its HTTP requests, and clients are
+	// identified by their IP.
+	UnaryInterceptor() grpc.UnaryServerInterceptor
+	StreamInterceptor() grpc.StreamServerInterceptor
+}
+
+// rateLimiter limits the rate of requests of each client with a token bucket
+type rateLimiter struct {
+	lock              sync.Mutex
+	requestsPerSecond rate.Limit
+	burst             int
+	// client key --> *rate.Limiter
+	limiters cache.LRU
+}
+
+// NewRateLimiter returns a wrapper that allows each client to make
+// [requestsPerSecond] requests per second on average, and up to [burst]
+// requests at once. If [requestsPerSecond] is 0, requests aren't limited.
+// Clients are identified by their IP, unless a wrapper that runs before the
+// rate limiter has verified their identity with WithClientID.
+func NewRateLimiter(requestsPerSecond float64, burst int) RateLimiter {
+	return &rateLimiter{
+		requestsPerSecond: rate.Limit(requestsPerSecond),
+		burst:             burst,
+		limiters:          cache.LRU{Size: maxRateLimitedClients},
+	}
+}
+
+func (l *rateLimiter) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, verified := r.Context().Value(clientIDKey{}).(string)
+		_, ipLimited := r.Context().Value(ipLimitedKey{}).(bool)
+		if ipLimited && !verified {
+			// The request was already limited by its IP
+			h.ServeHTTP(w, r)
+			return
+		}
+		l.limit(w, r, clientKey(r), h)
+	})
+}
+
+func (l *rateLimiter) IPLimiter() Wrapper { return ipRateLimiter{l: l} }
+
+// limit serves [r] with [h] unless the client identified by [key] exceeded its
+// rate limit
+func (l *rateLimiter) limit(w http.ResponseWriter, r *http.Request, key string, h http.Handler) {
+	limiter := l.limiter(key)
+	if limiter == nil {
+		h.ServeHTTP(w, r)
+		return
+	}
+	if !limiter.Allow() {
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return
+	}
+	h.ServeHTTP(w, withRateLimit(r, limiter))
+}
+
+// ipRateLimiter limits the rate of requests of each IP with the buckets of a
+// rateLimiter
+type ipRateLimiter struct {
+	l *rateLimiter
+}
+
+func (i ipRateLimiter) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(context.WithValue(r.Context(), ipLimitedKey{}, true))
+		i.l.limit(w, r, ipKey(r), h)
+	})
+}
+
+func (l *rateLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.allowCall(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (l *rateLimiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.allowCall(stream.Context()); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// allowCall returns an error if the client that made the gRPC call with
+// [ctx] has exceeded its rate limit
+func (l *rateLimiter) allowCall(ctx context.Context) error {
+	if limiter := l.limiter(callClientKey(ctx)); limiter != nil && !limiter.Allow() {
+		return status.Error(codes.ResourceExhausted, http.StatusText(http.StatusTooManyRequests))
+	}
+	return nil
+}
+
+func (l *rateLimiter) SetLimits(requestsPerSecond float64, burst int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.requestsPerSecond = rate.Limit(requestsPerSecond)
+	l.burst = burst
+	// Clients get new, full, buckets with the new limits on their next request
+	l.limiters.Flush()
+}
+
+// limiter returns the rate limiter of the client identified by [key], or nil
+// if requests aren't limited
+func (l *rateLimiter) limiter(key string) *rate.Limiter {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.requestsPerSecond == 0 {
+		return nil
+	}
+	if limiter, ok := l.limiters.Get(key); ok {
+		return limiter.(*rate.Limiter)
+	}
+	limiter := rate.NewLimiter(l.requestsPerSecond, l.burst)
+	l.limiters.Put(key, limiter)
+	return limiter
+}
+
+type (
+	clientIDKey  struct{}
+	ipLimitedKey struct{}
+	rateLimitKey struct{}
+)
+
+// withRateLimit returns [r] with [limiter] charged, along with the limiters
+// that were already charged for [r], when [r] turns out to carry more than one
+// request
+func withRateLimit(r *http.Request, limiter *rate.Limiter) *http.Request {
+	charged, _ := r.Context().Value(rateLimitKey{}).(func(int) bool)
+	charge := func(n int) bool {
+		if charged != nil && !charged(n) {
+			return false
+		}
+		return limiter.AllowN(time.Now(), n)
+	}
+	return r.WithContext(context.WithValue(r.Context(), rateLimitKey{}, charge))
+}
+
+// allowMore charges the client that sent [r] for [n] requests in addition to
+// [r] itself, such as the other requests of a batch. Returns false if the
+// client exceeded its rate limit.
+func allowMore(r *http.Request, n int) bool {
+	charge, ok := r.Context().Value(rateLimitKey{}).(func(int) bool)
+	return !ok || n <= 0 || charge(n)
+}
+
+// WithClientID returns [r] with its client identified by [clientID]. It must
+// only be called once the client has proven its identity, such as by sending
+// a valid authorization token, since the client is then rate limited by
+// [clientID] rather than by its IP.
+func WithClientID(r *http.Request, clientID string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), clientIDKey{}, clientID))
+}
+
+// clientKey returns the key that identifies the client that sent [r]
+func clientKey(r *http.Request) string {
+	if clientID, ok := r.Context().Value(clientIDKey{}).(string); ok {
+		return "id:" + clientID
+	}
+	return ipKey(r)
+}
+
+// ipKey returns the key that identifies the IP that sent [r]
+func ipKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + strings.ToLower(host)
+}
+
+// callClientKey returns the key that identifies the client that made the gRPC
+// call with [ctx]
+func callClientKey(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "ip:"
+	}
+	addr := p.Addr.String()
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return "ip:" + strings.ToLower(host)
+}
+
+// requestSizeLimiter limits the size of request bodies
+type requestSizeLimiter struct {
+	maxBodySize int64
+}
+
+// NewRequestSizeLimiter returns a wrapper that fails reading the body of a
+// request after [maxBodySize] bytes.
+func NewRequestSizeLimiter(maxBodySize int64) Wrapper {
+	return &requestSizeLimiter{maxBodySize: maxBodySize}
+}
+
+func (l *requestSizeLimiter) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > l.maxBodySize {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, l.maxBodySize)
+		h.ServeHTTP(w, r)
+	})
+}
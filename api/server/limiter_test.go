@@ -0,0 +1,196 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := NewRateLimiter(0.001, 2).WrapHandler(okHandler)
+
+	serve := func(remoteAddr, clientID string) int {
+		req := httptest.NewRequest(http.MethodPost, "/ext/info", nil)
+		req.RemoteAddr = remoteAddr
+		if clientID != "" {
+			req = WithClientID(req, clientID)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// The first client uses its burst
+	assert.Equal(http.StatusOK, serve("1.2.3.4:1000", ""))
+	assert.Equal(http.StatusOK, serve("1.2.3.4:1001", ""))
+	assert.Equal(http.StatusTooManyRequests, serve("1.2.3.4:1002", ""))
+
+	// Other clients have their own buckets
+	assert.Equal(http.StatusOK, serve("5.6.7.8:1000", ""))
+	assert.Equal(http.StatusOK, serve("1.2.3.4:1003", "token"))
+	assert.Equal(http.StatusOK, serve("1.2.3.4:1004", "token"))
+	assert.Equal(http.StatusTooManyRequests, serve("1.2.3.4:1005", "token"))
+}
+
+func TestRateLimiterInterceptor(t *testing.T) {
+	assert := assert.New(t)
+
+	limiter := NewRateLimiter(0.001, 2)
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	httpHandler := limiter.WrapHandler(okHandler)
+	interceptor := limiter.UnaryInterceptor()
+
+	call := func(ip string) codes.Code {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1000},
+		})
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		return status.Code(err)
+	}
+
+	// gRPC calls and HTTP requests share each client's bucket
+	req := httptest.NewRequest(http.MethodPost, "/ext/info", nil)
+	req.RemoteAddr = "1.2.3.4:1000"
+	httpHandler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(codes.OK, call("1.2.3.4"))
+	assert.Equal(codes.ResourceExhausted, call("1.2.3.4"))
+	assert.Equal(codes.OK, call("5.6.7.8"))
+}
+
+func TestRateLimiterIgnoresUnverifiedTokens(t *testing.T) {
+	assert := assert.New(t)
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := NewRateLimiter(0.001, 1).WrapHandler(okHandler)
+
+	serve := func(token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/ext/info", nil)
+		req.RemoteAddr = "1.2.3.4:1000"
+		req.Header.Set("Authorization", token)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// A client can't get new buckets by sending tokens that weren't verified
+	assert.Equal(http.StatusOK, serve("Bearer a"))
+	assert.Equal(http.StatusTooManyRequests, serve("Bearer b"))
+}
+
+func TestIPLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	limiter := NewRateLimiter(0.001, 2)
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	// Clients whose identity is verified are identified in between the IP
+	// limiter and the rate limiter
+	handler := limiter.IPLimiter().WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.Header.Get("Authorization"); token != "" {
+			r = WithClientID(r, token)
+		}
+		limiter.WrapHandler(okHandler).ServeHTTP(w, r)
+	}))
+
+	serve := func(token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/ext/info", nil)
+		req.RemoteAddr = "1.2.3.4:1000"
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// Requests without a verified identity are only limited once
+	assert.Equal(http.StatusOK, serve(""))
+	// Requests with a verified identity are limited by their IP and identity
+	assert.Equal(http.StatusOK, serve("token"))
+	// The IP is limited no matter which identity the client claims
+	assert.Equal(http.StatusTooManyRequests, serve("other token"))
+	assert.Equal(http.StatusTooManyRequests, serve(""))
+}
+
+func TestRateLimiterSetLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	limiter := NewRateLimiter(0, 0)
+	handler := limiter.WrapHandler(okHandler)
+
+	serve := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/ext/info", nil)
+		req.RemoteAddr = "1.2.3.4:1000"
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// Requests aren't limited until a limit is set
+	for i := 0; i < 10; i++ {
+		assert.Equal(http.StatusOK, serve())
+	}
+
+	limiter.SetLimits(0.001, 1)
+	assert.Equal(http.StatusOK, serve())
+	assert.Equal(http.StatusTooManyRequests, serve())
+
+	// Changing the limits refills the bucket of the client
+	limiter.SetLimits(0.001, 2)
+	assert.Equal(http.StatusOK, serve())
+	assert.Equal(http.StatusOK, serve())
+	assert.Equal(http.StatusTooManyRequests, serve())
+
+	limiter.SetLimits(0, 0)
+	assert.Equal(http.StatusOK, serve())
+}
+
+func TestRequestSizeLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		readErr error
+		read    []byte
+	)
+	handler := NewRequestSizeLimiter(4).WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		read, readErr = ioutil.ReadAll(r.Body)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/ext/info", bytes.NewBufferString("1234"))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.NoError(readErr)
+	assert.Equal([]byte("1234"), read)
+
+	req = httptest.NewRequest(http.MethodPost, "/ext/info", bytes.NewBufferString("12345"))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(http.StatusRequestEntityTooLarge, rr.Code)
+
+	// Bodies of unknown length are cut off while being read
+	req = httptest.NewRequest(http.MethodPost, "/ext/info", bytes.NewBufferString("12345"))
+	req.ContentLength = -1
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Error(readErr)
+}
@@ -0,0 +1,55 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	httpScheme  = "http://"
+	httpsScheme = "https://"
+)
+
+// Listener is an address the API server is served on
+type Listener struct {
+	// Address to listen on, in the form host:port
+	Address string
+	// If true, TLS connections are terminated by the server
+	TLS bool
+}
+
+// ParseListener parses a listener of the form http://host:port or
+// https://host:port
+func ParseListener(listener string) (Listener, error) {
+	var (
+		address string
+		useTLS  bool
+	)
+	switch {
+	case strings.HasPrefix(listener, httpScheme):
+		address = listener[len(httpScheme):]
+	case strings.HasPrefix(listener, httpsScheme):
+		address = listener[len(httpsScheme):]
+		useTLS = true
+	default:
+		return Listener{}, fmt.Errorf("listener %q should start with %q or %q", listener, httpScheme, httpsScheme)
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return Listener{}, fmt.Errorf("couldn't parse listener %q: %w", listener, err)
+	}
+	return Listener{
+		Address: address,
+		TLS:     useTLS,
+	}, nil
+}
+
+func (l Listener) String() string {
+	if l.TLS {
+		return httpsScheme + l.Address
+	}
+	return httpScheme + l.Address
+}
@@ -0,0 +1,28 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseListener(t *testing.T) {
+	assert := assert.New(t)
+
+	listener, err := ParseListener("http://127.0.0.1:9660")
+	assert.NoError(err)
+	assert.Equal(Listener{Address: "127.0.0.1:9660"}, listener)
+
+	listener, err = ParseListener("https://[::]:9661")
+	assert.NoError(err)
+	assert.Equal(Listener{Address: "[::]:9661", TLS: true}, listener)
+	assert.Equal("https://[::]:9661", listener.String())
+
+	_, err = ParseListener("127.0.0.1:9660")
+	assert.Error(err)
+	_, err = ParseListener("http://127.0.0.1")
+	assert.Error(err)
+}
@@ -0,0 +1,60 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/api/openapi"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+// documentedRoute is a route whose JSON-RPC services are included in the
+// OpenAPI document of the server
+type documentedRoute struct {
+	path     string
+	services []openapi.Service
+}
+
+// document includes the JSON-RPC services served by [handler] on [path] in the
+// OpenAPI document of the server, if [handler] can list them
+func (s *Server) document(path string, handler http.Handler) {
+	documented, ok := handler.(openapi.Documented)
+	if !ok {
+		return
+	}
+
+	s.documentedRoutesLock.Lock()
+	defer s.documentedRoutesLock.Unlock()
+
+	s.documentedRoutes = append(s.documentedRoutes, documentedRoute{
+		path:     path,
+		services: documented.Services(),
+	})
+}
+
+// OpenAPIDocument returns an OpenAPI document of the JSON-RPC services that
+// are currently mounted on the server. Routes are only documented at their
+// primary path, not at their aliases.
+func (s *Server) OpenAPIDocument(title, version string) *openapi.Document {
+	s.documentedRoutesLock.Lock()
+	defer s.documentedRoutesLock.Unlock()
+
+	doc := openapi.NewDocument(title, version)
+	for _, route := range s.documentedRoutes {
+		doc.AddServices(route.path, route.services)
+	}
+	return doc
+}
+
+// OpenAPIHandler returns a handler that serves the current OpenAPI document of
+// the server
+func (s *Server) OpenAPIHandler(title, version string) *common.HTTPHandler {
+	return &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, s.OpenAPIDocument(title, version))
+		}),
+	}
+}
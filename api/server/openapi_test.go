@@ -0,0 +1,56 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api/openapi"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	assert := assert.New(t)
+
+	s := Server{}
+	s.Initialize(
+		logging.NoLog{},
+		logging.NoFactory{},
+		"localhost",
+		8080,
+		[]string{"*"},
+		10,
+	)
+
+	documentedServer := openapi.NewServer()
+	assert.NoError(documentedServer.RegisterService(&EchoService{}, "echo"))
+	undocumentedServer := rpc.NewServer()
+	assert.NoError(undocumentedServer.RegisterService(&EchoService{}, "echo"))
+
+	assert.NoError(s.AddRoute(&common.HTTPHandler{Handler: documentedServer}, new(sync.RWMutex), "echo", "", logging.NoLog{}))
+	assert.NoError(s.AddRoute(&common.HTTPHandler{Handler: undocumentedServer}, new(sync.RWMutex), "undocumented", "", logging.NoLog{}))
+	assert.NoError(s.AddAliases("echo", "alias"))
+	assert.NoError(s.AddRoute(s.OpenAPIHandler("test API", "1.2.3"), new(sync.RWMutex), "openapi", "", logging.NoLog{}))
+
+	rr := httptest.NewRecorder()
+	s.handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ext/openapi", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	doc := openapi.Document{}
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &doc))
+	assert.Equal("test API", doc.Info.Title)
+	if assert.Len(doc.Paths, 1) {
+		assert.Contains(doc.Paths, "/ext/echo#echo.echo")
+	}
+	assert.Contains(doc.Components.Schemas, "server.EchoArgs")
+	assert.Contains(doc.Components.Schemas, "server.EchoReply")
+}
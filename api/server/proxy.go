@@ -0,0 +1,96 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const forwardedForHeaderKey = "X-Forwarded-For"
+
+var _ Wrapper = &trustedProxies{}
+
+// trustedProxies replaces the remote address of requests forwarded by a
+// trusted proxy with the address of the client that sent the request to the
+// proxy, so that clients behind a reverse proxy can be told apart.
+type trustedProxies struct {
+	proxies []*net.IPNet
+}
+
+// NewTrustedProxies returns a wrapper that trusts the X-Forwarded-For header
+// of requests sent from [proxies]
+func NewTrustedProxies(proxies []*net.IPNet) Wrapper {
+	return &trustedProxies{proxies: proxies}
+}
+
+func (p *trustedProxies) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if clientIP, ok := p.clientIP(r); ok {
+			r.RemoteAddr = clientIP.String()
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the IP of the client that sent [r] through a trusted
+// proxy. Returns false if [r] wasn't sent by a trusted proxy.
+func (p *trustedProxies) clientIP(r *http.Request) (net.IP, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !p.trusted(ip) {
+		return nil, false
+	}
+
+	// Each proxy appends the address it received the request from, so the
+	// client is the last address that wasn't added by a trusted proxy
+	var forwardedFor []string
+	for _, header := range r.Header.Values(forwardedForHeaderKey) {
+		forwardedFor = append(forwardedFor, strings.Split(header, ",")...)
+	}
+	for i := len(forwardedFor) - 1; i >= 0; i-- {
+		forwardedIP := net.ParseIP(strings.TrimSpace(forwardedFor[i]))
+		if forwardedIP == nil {
+			return nil, false
+		}
+		ip = forwardedIP
+		if !p.trusted(ip) {
+			break
+		}
+	}
+	return ip, true
+}
+
+func (p *trustedProxies) trusted(ip net.IP) bool {
+	for _, proxy := range p.proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTrustedProxy parses [proxy], which is either an IP or a CIDR block
+func ParseTrustedProxy(proxy string) (*net.IPNet, error) {
+	if !strings.Contains(proxy, "/") {
+		ip := net.ParseIP(proxy)
+		if ip == nil {
+			return nil, fmt.Errorf("couldn't parse trusted proxy %q", proxy)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &net.IPNet{IP: ip4, Mask: net.CIDRMask(8*net.IPv4len, 8*net.IPv4len)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(8*net.IPv6len, 8*net.IPv6len)}, nil
+	}
+	_, ipNet, err := net.ParseCIDR(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse trusted proxy %q: %w", proxy, err)
+	}
+	return ipNet, nil
+}
@@ -0,0 +1,84 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrustedProxies(t *testing.T) {
+	assert := assert.New(t)
+
+	var proxies []*net.IPNet
+	for _, proxy := range []string{"10.0.0.0/8", "127.0.0.1"} {
+		ipNet, err := ParseTrustedProxy(proxy)
+		assert.NoError(err)
+		proxies = append(proxies, ipNet)
+	}
+
+	var remoteAddr string
+	handler := NewTrustedProxies(proxies).WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteAddr = r.RemoteAddr
+	}))
+
+	tests := []struct {
+		name               string
+		remoteAddr         string
+		forwardedFor       []string
+		expectedRemoteAddr string
+	}{
+		{
+			name:               "untrusted sender",
+			remoteAddr:         "1.2.3.4:1000",
+			forwardedFor:       []string{"5.6.7.8"},
+			expectedRemoteAddr: "1.2.3.4:1000",
+		},
+		{
+			name:               "trusted proxy",
+			remoteAddr:         "127.0.0.1:1000",
+			forwardedFor:       []string{"5.6.7.8"},
+			expectedRemoteAddr: "5.6.7.8",
+		},
+		{
+			name:               "chain of trusted proxies",
+			remoteAddr:         "127.0.0.1:1000",
+			forwardedFor:       []string{"9.9.9.9, 5.6.7.8", "10.1.2.3"},
+			expectedRemoteAddr: "5.6.7.8",
+		},
+		{
+			name:               "malformed header",
+			remoteAddr:         "127.0.0.1:1000",
+			forwardedFor:       []string{"not an ip"},
+			expectedRemoteAddr: "127.0.0.1:1000",
+		},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/ext/info", nil)
+		req.RemoteAddr = test.remoteAddr
+		for _, forwardedFor := range test.forwardedFor {
+			req.Header.Add(forwardedForHeaderKey, forwardedFor)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(test.expectedRemoteAddr, remoteAddr, test.name)
+	}
+}
+
+func TestParseTrustedProxy(t *testing.T) {
+	assert := assert.New(t)
+
+	ipNet, err := ParseTrustedProxy("::1")
+	assert.NoError(err)
+	assert.True(ipNet.Contains(net.ParseIP("::1")))
+	assert.False(ipNet.Contains(net.ParseIP("::2")))
+
+	_, err = ParseTrustedProxy("10.0.0.0/33")
+	assert.Error(err)
+	_, err = ParseTrustedProxy("localhost")
+	assert.Error(err)
+}
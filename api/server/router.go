@@ -15,6 +15,7 @@ import (
 var (
 	errUnknownBaseURL  = errors.New("unknown base url")
 	errUnknownEndpoint = errors.New("unknown endpoint")
+	errUnknownAlias    = errors.New("unknown alias")
 )
 
 type router struct {
@@ -135,3 +136,59 @@ func (r *router) AddAlias(base string, aliases ...string) error {
 	}
 	return err
 }
+
+// RemoveAlias stops serving the routes of [base] at [aliases]
+func (r *router) RemoveAlias(base string, aliases ...string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.routeLock.Lock()
+	defer r.routeLock.Unlock()
+
+	baseAliases := r.aliases[base]
+	for _, alias := range aliases {
+		if !containsAlias(baseAliases, alias) {
+			return fmt.Errorf("%w: %s isn't an alias of %s", errUnknownAlias, alias, base)
+		}
+	}
+
+	for _, alias := range aliases {
+		for i, baseAlias := range baseAliases {
+			if baseAlias == alias {
+				baseAliases = append(baseAliases[:i], baseAliases[i+1:]...)
+				break
+			}
+		}
+		delete(r.reservedRoutes, alias)
+		delete(r.routes, alias)
+	}
+	if len(baseAliases) == 0 {
+		delete(r.aliases, base)
+	} else {
+		r.aliases[base] = baseAliases
+	}
+
+	// Routes can't be removed from a mux router, so the remaining routes are
+	// added to a new one
+	newRouter := mux.NewRouter()
+	for routeBase, endpoints := range r.routes {
+		for endpoint, handler := range endpoints {
+			url := routeBase + endpoint
+			if route := newRouter.Handle(url, handler); route != nil {
+				route.Name(url)
+			} else {
+				return fmt.Errorf("failed to create new route for %s", url)
+			}
+		}
+	}
+	r.router = newRouter
+	return nil
+}
+
+func containsAlias(aliases []string, alias string) bool {
+	for _, a := range aliases {
+		if a == alias {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ package server
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -75,3 +76,50 @@ func TestBlock(t *testing.T) {
 		t.Fatalf("Permanently locked %s", "1")
 	}
 }
+
+func TestRemoveAlias(t *testing.T) {
+	r := newRouter()
+
+	handler1 := &testHandler{}
+	if err := r.AddRouter("/ext/1", "", handler1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddAlias("/ext/1", "/ext/2", "/ext/3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RemoveAlias("/ext/1", "/ext/4"); err == nil {
+		t.Fatalf("Shouldn't have removed an unknown alias")
+	}
+	if err := r.RemoveAlias("/ext/1", "/ext/2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.GetHandler("/ext/2", ""); err == nil {
+		t.Fatalf("Should have removed %s", "/ext/2")
+	}
+	if handler, err := r.GetHandler("/ext/3", ""); err != nil {
+		t.Fatal(err)
+	} else if handler != handler1 {
+		t.Fatalf("Registered unknown handler")
+	}
+
+	// The removed alias is no longer served, but the others still are
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/ext/2", nil))
+	if handler1.called {
+		t.Fatalf("Shouldn't have served a removed alias")
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/ext/3", nil))
+	if !handler1.called {
+		t.Fatalf("Should have served the remaining alias")
+	}
+
+	// The removed alias can be reused
+	handler2 := &testHandler{}
+	if err := r.AddRouter("/ext/2", "", handler2); err != nil {
+		t.Fatal(err)
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/ext/2", nil))
+	if !handler2.called {
+		t.Fatalf("Should have served the new route")
+	}
+}
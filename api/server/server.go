@@ -5,6 +5,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -22,13 +23,13 @@ import (
 
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
-	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
 const (
 	baseURL               = "/ext"
 	serverShutdownTimeout = 10 * time.Second
+	readHeaderTimeout     = 10 * time.Second
 )
 
 var (
@@ -56,6 +57,10 @@ type Server struct {
 
 	// http server
 	srv *http.Server
+
+	// Routes included in the OpenAPI document of the server
+	documentedRoutesLock sync.Mutex
+	documentedRoutes     []documentedRoute
 }
 
 // Initialize creates the API server at the provided host and port
@@ -65,6 +70,7 @@ func (s *Server) Initialize(
 	host string,
 	port uint16,
 	allowedOrigins []string,
+	maxBatchSize int,
 	wrappers ...Wrapper,
 ) {
 	s.log = log
@@ -78,48 +84,66 @@ func (s *Server) Initialize(
 		AllowedOrigins:   allowedOrigins,
 		AllowCredentials: true,
 	})
-	s.handler = gziphandler.GzipHandler(corsWrapper.Handler(s.router))
+	s.handler = gziphandler.GzipHandler(corsWrapper.Handler(newBatchHandler(s.router, maxBatchSize)))
 
 	for _, wrapper := range wrappers {
 		s.handler = wrapper.WrapHandler(s.handler)
 	}
+	s.srv = &http.Server{
+		Handler:           s.handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
 }
 
 // Dispatch starts the API server
 func (s *Server) Dispatch() error {
-	listenAddress := fmt.Sprintf("%s:%d", s.listenHost, s.listenPort)
-	listener, err := net.Listen("tcp", listenAddress)
-	if err != nil {
-		return err
-	}
+	return s.DispatchAddress(fmt.Sprintf("%s:%d", s.listenHost, s.listenPort), nil)
+}
 
-	ipDesc, err := utils.ToIPDesc(listener.Addr().String())
+// DispatchTLS starts the API server with the provided TLS certificate
+func (s *Server) DispatchTLS(certFile, keyFile string) error {
+	tlsConfig, err := NewTLSConfig(certFile, keyFile)
 	if err != nil {
-		s.log.Info("HTTP API server listening on %q", listenAddress)
-	} else {
-		s.log.Info("HTTP API server listening on \"%s:%d\"", s.listenHost, ipDesc.Port)
+		return err
 	}
-
-	s.srv = &http.Server{Handler: s.handler}
-	return s.srv.Serve(listener)
+	return s.DispatchAddress(fmt.Sprintf("%s:%d", s.listenHost, s.listenPort), tlsConfig)
 }
 
-// DispatchTLS starts the API server with the provided TLS certificate
-func (s *Server) DispatchTLS(certFile, keyFile string) error {
-	listenAddress := fmt.Sprintf("%s:%d", s.listenHost, s.listenPort)
+// DispatchAddress serves the API on [listenAddress]. If [tlsConfig] is
+// non-nil, TLS connections are terminated by the server. May be called
+// multiple times to serve the API on multiple addresses.
+func (s *Server) DispatchAddress(listenAddress string, tlsConfig *tls.Config) error {
 	listener, err := net.Listen("tcp", listenAddress)
 	if err != nil {
 		return err
 	}
+	return s.DispatchListener(listener, tlsConfig)
+}
 
-	ipDesc, err := utils.ToIPDesc(listener.Addr().String())
-	if err != nil {
-		s.log.Info("HTTPS API server listening on %q", listenAddress)
-	} else {
-		s.log.Info("HTTPS API server listening on \"%s:%d\"", s.listenHost, ipDesc.Port)
+// DispatchListener serves the API on [listener]. If [tlsConfig] is non-nil,
+// TLS connections are terminated by the server. May be called multiple times
+// to serve the API on multiple listeners.
+func (s *Server) DispatchListener(listener net.Listener, tlsConfig *tls.Config) error {
+	protocol := "HTTP"
+	if tlsConfig != nil {
+		protocol = "HTTPS"
+		listener = tls.NewListener(listener, tlsConfig)
 	}
+	s.log.Info("%s API server listening on %q", protocol, listener.Addr())
+	return s.srv.Serve(listener)
+}
 
-	return http.ServeTLS(listener, s.handler, certFile, keyFile)
+// NewTLSConfig returns the TLS configuration of an API server that uses the
+// provided certificate
+func NewTLSConfig(certFile, keyFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load API server TLS certificate: %w", err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
 }
 
 // RegisterChain registers the API endpoints associated with this chain. That is,
@@ -185,7 +209,13 @@ func (s *Server) AddChainRoute(handler *common.HTTPHandler, ctx *snow.Context, b
 	}
 	// Apply middleware to reject calls to the handler before the chain finishes bootstrapping
 	h = rejectMiddleware(h, ctx)
-	return s.router.AddRouter(url, endpoint, h)
+	// Apply middleware to reject calls that aren't allowed to access the chain
+	h = scopeMiddleware(h, ctx)
+	if err := s.router.AddRouter(url, endpoint, h); err != nil {
+		return err
+	}
+	s.document(url+endpoint, handler.Handler)
+	return nil
 }
 
 // AddRoute registers a route to a handler.
@@ -199,7 +229,11 @@ func (s *Server) AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base,
 	if err != nil {
 		return err
 	}
-	return s.router.AddRouter(url, endpoint, h)
+	if err := s.router.AddRouter(url, endpoint, h); err != nil {
+		return err
+	}
+	s.document(url+endpoint, handler.Handler)
+	return nil
 }
 
 // Wraps a handler by grabbing and releasing a lock before calling the handler.
@@ -238,6 +272,46 @@ func rejectMiddleware(handler http.Handler, ctx *snow.Context) http.Handler {
 	})
 }
 
+type chainScopeKey struct{}
+
+// WithChainScope returns [r] restricted to the APIs of [chains], which are
+// chain IDs or aliases, among the APIs of chains. If [chains] is empty, [r]
+// isn't restricted.
+func WithChainScope(r *http.Request, chains []string) *http.Request {
+	if len(chains) == 0 {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), chainScopeKey{}, chains))
+}
+
+// Scope middleware wraps a handler. If the request was restricted to the APIs
+// of other chains than the chain that the context describes, writes back an
+// error.
+func scopeMiddleware(handler http.Handler, ctx *snow.Context) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chains, ok := r.Context().Value(chainScopeKey{}).([]string)
+		if !ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		// Aliases are resolved when the request is made, so a chain can be
+		// named by an alias it was given after the restriction was made
+		for _, chain := range chains {
+			if chain == ctx.ChainID.String() {
+				handler.ServeHTTP(w, r)
+				return
+			}
+			if chainID, err := ctx.BCLookup.Lookup(chain); err == nil && chainID == ctx.ChainID {
+				handler.ServeHTTP(w, r)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		// Doesn't matter if there's an error while writing. They'll get the StatusUnauthorized code.
+		_, _ = w.Write([]byte("API call rejected because the request isn't allowed to access this chain"))
+	})
+}
+
 // AddAliases registers aliases to the server
 func (s *Server) AddAliases(endpoint string, aliases ...string) error {
 	url := fmt.Sprintf("%s/%s", baseURL, endpoint)
@@ -260,6 +334,26 @@ func (s *Server) AddAliasesWithReadLock(endpoint string, aliases ...string) erro
 	return s.AddAliases(endpoint, aliases...)
 }
 
+// RemoveAliases stops serving the routes of [endpoint] at [aliases]
+func (s *Server) RemoveAliases(endpoint string, aliases ...string) error {
+	url := fmt.Sprintf("%s/%s", baseURL, endpoint)
+	endpoints := make([]string, len(aliases))
+	for i, alias := range aliases {
+		endpoints[i] = fmt.Sprintf("%s/%s", baseURL, alias)
+	}
+	return s.router.RemoveAlias(url, endpoints...)
+}
+
+// RemoveAliasesWithReadLock removes aliases from the server assuming the http
+// read lock is currently held.
+func (s *Server) RemoveAliasesWithReadLock(endpoint string, aliases ...string) error {
+	// See AddAliasesWithReadLock
+	s.router.lock.RUnlock()
+	defer s.router.lock.RLock()
+
+	return s.RemoveAliases(endpoint, aliases...)
+}
+
 // Call ...
 func (s *Server) Call(
 	writer http.ResponseWriter,
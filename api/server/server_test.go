@@ -13,6 +13,8 @@ import (
 	"github.com/gorilla/rpc/v2"
 	"github.com/gorilla/rpc/v2/json2"
 
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
@@ -36,6 +38,7 @@ func TestCall(t *testing.T) {
 		"localhost",
 		8080,
 		[]string{"*"},
+		10,
 	)
 
 	serv := &Service{}
@@ -76,3 +79,39 @@ func TestCall(t *testing.T) {
 		t.Fatalf("Should have been called")
 	}
 }
+
+func TestScopeMiddleware(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	aliaser := ctx.BCLookup.(*ids.Aliaser)
+	if err := aliaser.Alias(ctx.ChainID, "X"); err != nil {
+		t.Fatal(err)
+	}
+	otherChainID := ids.GenerateTestID()
+	if err := aliaser.Alias(otherChainID, "P"); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := scopeMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), ctx)
+
+	tests := []struct {
+		chains []string
+		code   int
+	}{
+		{chains: nil, code: http.StatusOK},
+		{chains: []string{ctx.ChainID.String()}, code: http.StatusOK},
+		{chains: []string{"X"}, code: http.StatusOK},
+		{chains: []string{"P", "X"}, code: http.StatusOK},
+		{chains: []string{"P"}, code: http.StatusUnauthorized},
+		{chains: []string{otherChainID.String()}, code: http.StatusUnauthorized},
+		{chains: []string{"unknown"}, code: http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		req := WithChainScope(httptest.NewRequest("POST", "/", nil), test.chains)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != test.code {
+			t.Fatalf("expected %d with chains %v but got %d", test.code, test.chains, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+// +build !windows
+
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package entry
+
+import (
+	"os"
+	"syscall"
+)
+
+// Signals that restart the node without closing its sockets
+var restartSignals = []os.Signal{syscall.SIGUSR2}
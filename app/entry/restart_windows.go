@@ -0,0 +1,13 @@
+// +build windows
+
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package entry
+
+import (
+	"os"
+)
+
+// Sockets can't be handed over by a signal on Windows
+var restartSignals []os.Signal
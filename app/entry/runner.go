@@ -35,6 +35,17 @@ func Run(processConfig process.Config, nodeConfig node.Config) {
 
 	app := process.NewApp(nodeConfig) // Create node wrapper
 
+	// Reload the node's config on SIGHUP, also when running as a plugin of
+	// the daemon, which forwards the signal
+	_ = utils.HandleSignals(
+		func(os.Signal) {
+			if err := app.Reload(); err != nil {
+				fmt.Printf("couldn't reload the node's config: %s\n", err)
+			}
+		},
+		syscall.SIGHUP,
+	)
+
 	if processConfig.PluginMode { // Serve as a plugin
 		plugin.Serve(&plugin.ServeConfig{
 			HandshakeConfig: appPlugin.Handshake,
@@ -57,6 +68,15 @@ func Run(processConfig process.Config, nodeConfig node.Config) {
 		},
 		syscall.SIGINT, syscall.SIGTERM,
 	)
+	_ = utils.HandleSignals(
+		func(os.Signal) {
+			// SIGUSR2 restarts the node without closing its sockets
+			if err := app.Restart(); err != nil {
+				fmt.Printf("couldn't restart the node: %s\n", err)
+			}
+		},
+		restartSignals...,
+	)
 	exitCode := app.Start() // Start the node
 	os.Exit(exitCode)
 }
@@ -6,12 +6,19 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/ava-labs/avalanchego/app/entry"
 	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/node"
+	"github.com/ava-labs/avalanchego/utils/handover"
 	"github.com/ava-labs/avalanchego/version"
 )
 
+// Longest that a node process waits for the node process it replaces to shut
+// down
+const handoverTimeout = 5 * time.Minute
+
 func main() {
 	fs := config.BuildFlagSet()
 	v, err := config.BuildViper(fs, os.Args[1:])
@@ -31,11 +38,40 @@ func main() {
 		os.Exit(0)
 	}
 
+	// The listening sockets are inherited from the node process that this one
+	// replaces, or from systemd. Nodes run by the daemon are restarted by it
+	// instead.
+	var sockets *handover.Sockets
+	if !processConfig.PluginMode {
+		sockets, err = handover.Inherit()
+		if err != nil {
+			fmt.Printf("couldn't inherit sockets: %s\n", err)
+			os.Exit(1)
+		}
+		// The previous node process holds the database until it exits
+		if err := sockets.WaitForPrevious(handoverTimeout); err != nil {
+			fmt.Printf("couldn't take over from the previous node process: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
 	nodeConfig, err := config.GetNodeConfig(v, processConfig.BuildDir)
 	if err != nil {
 		fmt.Printf("couldn't load node config: %s\n", err)
 		os.Exit(1)
 	}
 
+	nodeConfig.Sockets = sockets
+
+	// The flags and config file are parsed again when the node's config is
+	// reloaded
+	nodeConfig.ReloadConfig = func() (node.ReloadableConfig, error) {
+		v, err := config.BuildViper(config.BuildFlagSet(), os.Args[1:])
+		if err != nil {
+			return node.ReloadableConfig{}, err
+		}
+		return config.GetReloadableConfig(v)
+	}
+
 	entry.Run(processConfig, nodeConfig)
 }
@@ -4,9 +4,13 @@
 package process
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/backup"
+	"github.com/ava-labs/avalanchego/database/cryptdb"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/nat"
@@ -55,11 +59,15 @@ amount of disk space required by AvalancheGo. Ensure that this computer has at
 least enough disk space available.`
 
 	alreadyUpgradedMsg = "fetch only mode done. Restart this node without --fetch-only to run normally"
+
+	notBootstrappedArchiveMsg = "archival mode requires a database that has been bootstrapped. Run this node without --archival-mode until it is bootstrapped"
 )
 
 var (
 	stakingPortName = fmt.Sprintf("%s-staking", constants.AppName)
 	httpPortName    = fmt.Sprintf("%s-http", constants.AppName)
+
+	errEncryptedDB = errors.New("database is encrypted but no encryption key is configured")
 )
 
 // App is a wrapper around a node
@@ -95,9 +103,17 @@ func (a *App) Start() int {
 	// start the db manager
 	var dbManager manager.Manager
 	if a.config.DBEnabled {
-		dbManager, err = manager.New(a.config.DBPath, a.log, version.CurrentDatabase, !a.config.FetchOnly)
+		if a.config.ArchivalMode {
+			a.log.Info("running in archival mode. The database is opened read-only")
+		}
+		dbManager, err = a.openDatabase()
+		if err != nil {
+			a.log.Fatal("%s", err)
+			return 1
+		}
+		dbManager, err = a.restoreDatabase(dbManager)
 		if err != nil {
-			a.log.Fatal("couldn't create db manager at %s: %s", a.config.DBPath, err)
+			a.log.Fatal("couldn't restore database from backup %s: %s", a.config.DBRestoreBackup, err)
 			return 1
 		}
 	} else {
@@ -121,6 +137,11 @@ func (a *App) Start() int {
 		return 1
 	}
 	a.log.Info("bootstrapped with current database version: %v", currentDBBootstrapped)
+	if a.config.ArchivalMode && !currentDBBootstrapped {
+		// There is nothing to serve, and the chains can't bootstrap
+		a.log.Fatal(notBootstrappedArchiveMsg)
+		return 1
+	}
 	if a.config.FetchOnly {
 		// Flag says to run in fetch only mode
 		if currentDBBootstrapped {
@@ -233,6 +254,219 @@ func (a *App) Start() int {
 	return a.node.ExitCode()
 }
 
+// encryptDatabase returns [dbManager] with its values encrypted if an
+// encryption key is configured. If a previous key is also configured, the
+// values encrypted with it are re-encrypted with the current key. If no key
+// is configured, makes sure that the current database isn't encrypted.
+func (a *App) encryptDatabase(dbManager manager.Manager) (manager.Manager, error) {
+	if a.config.DBEncryptionKey == nil {
+		encrypted, err := cryptdb.IsEncrypted(dbManager.Current().Database)
+		if err != nil {
+			return nil, err
+		}
+		if encrypted {
+			return nil, errEncryptedDB
+		}
+		return dbManager, nil
+	}
+
+	var previousKeys [][]byte
+	if a.config.DBEncryptionPreviousKey != nil {
+		previousKeys = append(previousKeys, a.config.DBEncryptionPreviousKey)
+	}
+	keyring, err := cryptdb.NewKeyring(a.config.DBEncryptionKey, previousKeys...)
+	if err != nil {
+		return nil, err
+	}
+	encManager, err := dbManager.NewEncryptedDBManager(keyring)
+	if err != nil {
+		return nil, err
+	}
+	if len(previousKeys) == 0 {
+		return encManager, nil
+	}
+
+	for _, vdb := range encManager.GetDatabases() {
+		encDB, ok := vdb.Database.(*cryptdb.Database)
+		if !ok {
+			continue
+		}
+		a.log.Info("re-encrypting database %s with the current encryption key", vdb.Version)
+		numRotated, err := encDB.Rotate()
+		if err != nil {
+			return nil, fmt.Errorf("couldn't re-encrypt database %s: %w", vdb.Version, err)
+		}
+		a.log.Info("re-encrypted %d values of database %s. The previous encryption key is no longer needed", numRotated, vdb.Version)
+	}
+	return encManager, nil
+}
+
+// openDatabase opens the node's databases and sets up their encryption
+func (a *App) openDatabase() (manager.Manager, error) {
+	if a.config.DBEncryptionKey != nil && !a.config.ArchivalMode {
+		if err := a.encryptExistingDatabases(); err != nil {
+			return nil, fmt.Errorf("couldn't encrypt the existing databases: %w", err)
+		}
+	}
+
+	var (
+		dbManager manager.Manager
+		err       error
+	)
+	if a.config.ArchivalMode {
+		dbManager, err = manager.NewReadOnlyWithBackend(a.config.DBPath, a.config.DBWALPath, a.config.DBBackend, a.log, version.CurrentDatabase)
+	} else {
+		dbManager, err = manager.NewWithBackend(a.config.DBPath, a.config.DBWALPath, a.config.DBBackend, a.log, version.CurrentDatabase, !a.config.FetchOnly)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create db manager at %s: %w", a.config.DBPath, err)
+	}
+	encManager, err := a.encryptDatabase(dbManager)
+	if err != nil {
+		_ = dbManager.Close()
+		return nil, fmt.Errorf("couldn't set up database encryption: %w", err)
+	}
+	return encManager, nil
+}
+
+// encryptExistingDatabases encrypts every database version that holds
+// unencrypted data, since encryption can only be enabled on an empty database.
+// Previous versions are encrypted too, even if they won't be opened, so that
+// no unencrypted data is left on disk.
+func (a *App) encryptExistingDatabases() error {
+	for {
+		dbManager, err := manager.NewWithBackend(a.config.DBPath, a.config.DBWALPath, a.config.DBBackend, a.log, version.CurrentDatabase, true)
+		if err != nil {
+			return fmt.Errorf("couldn't create db manager at %s: %w", a.config.DBPath, err)
+		}
+		encrypted, err := a.encryptExistingDatabase(dbManager)
+		if err != nil {
+			_ = dbManager.Close()
+			return err
+		}
+		if !encrypted {
+			return dbManager.Close()
+		}
+	}
+}
+
+// encryptExistingDatabase encrypts the first database version of [dbManager]
+// that holds unencrypted data. The values are encrypted into a fresh database
+// that replaces the unencrypted one, which closes [dbManager]. Returns true if
+// a database was encrypted.
+func (a *App) encryptExistingDatabase(dbManager manager.Manager) (bool, error) {
+	for _, vdb := range dbManager.GetDatabases() {
+		unencrypted, err := hasUnencryptedData(vdb.Database)
+		if err != nil {
+			return false, err
+		}
+		if !unencrypted {
+			continue
+		}
+
+		keyring, err := cryptdb.NewKeyring(a.config.DBEncryptionKey)
+		if err != nil {
+			return false, err
+		}
+		a.log.Info("encrypting database %s. This needs enough disk space for a copy of the database", vdb.Version)
+		numEncrypted := uint64(0)
+		err = manager.ReplaceWithBackend(a.config.DBPath, a.config.DBWALPath, a.config.DBBackend, a.log, vdb.Version, func(db database.Database) error {
+			encDB, err := cryptdb.New(keyring, db)
+			if err != nil {
+				return err
+			}
+			numEncrypted, err = encDB.Encrypt(vdb.Database)
+			// The database is replaced once this returns
+			if closeErr := dbManager.Close(); err == nil {
+				err = closeErr
+			}
+			return err
+		})
+		if err != nil {
+			return false, err
+		}
+		a.log.Info("encrypted %d values of database %s", numEncrypted, vdb.Version)
+		return true, nil
+	}
+	return false, nil
+}
+
+// hasUnencryptedData returns true if [db] holds data and isn't encrypted
+func hasUnencryptedData(db database.Database) (bool, error) {
+	encrypted, err := cryptdb.IsEncrypted(db)
+	if err != nil || encrypted {
+		return false, err
+	}
+	iter := db.NewIterator()
+	hasData := iter.Next()
+	err = iter.Error()
+	iter.Release()
+	return hasData, err
+}
+
+// restoreDatabase replaces the current database with the configured backup, if
+// there is one, and returns the reopened databases. The backup is restored to
+// a fresh database that only replaces the current one once the restore
+// succeeds. If the node stops while the restored database is swapped in, the
+// swap is finished when the databases are next opened, and the backup isn't
+// restored again. If the node stops before then, the current database is kept.
+// The accepted frontiers of the chains in the backup are kept so that the
+// chains can check their restored state.
+func (a *App) restoreDatabase(dbManager manager.Manager) (manager.Manager, error) {
+	if a.config.DBRestoreBackup == "" {
+		return dbManager, nil
+	}
+	restoredFrom, err := backup.RestoredFrom(dbManager.Current().Database)
+	if err != nil {
+		return nil, err
+	}
+	if restoredFrom == a.config.DBRestoreBackup {
+		a.log.Warn("database was already restored from backup %s, so it isn't restored again", a.config.DBRestoreBackup)
+		return dbManager, nil
+	}
+
+	a.log.Info("restoring database from backup %s", a.config.DBRestoreBackup)
+	if err := dbManager.Close(); err != nil {
+		return nil, err
+	}
+	var manifest *backup.Manifest
+	err = manager.ReplaceWithBackend(a.config.DBPath, a.config.DBWALPath, a.config.DBBackend, a.log, version.CurrentDatabase, func(db database.Database) error {
+		// The fresh database is encrypted like the one it replaces
+		freshManager, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{{
+			Database: db,
+			Version:  version.CurrentDatabase,
+		}})
+		if err != nil {
+			return err
+		}
+		freshManager, err = a.encryptDatabase(freshManager)
+		if err != nil {
+			return err
+		}
+		manifest, err = backup.Restore(a.config.DBBackupStore, a.config.DBRestoreBackup, freshManager.Current().Database)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.config.DBRestoredFrontiers = manifest.Frontiers()
+	a.config.DBRestoredCheckpoints = manifest.Checkpoints()
+	a.log.Info("restored %d key/value pairs from backup %s, which was taken at %s", manifest.NumKeys, a.config.DBRestoreBackup, manifest.Time)
+	return a.openDatabase()
+}
+
+// Reload reads the reloadable part of the node's config again and applies it
+// to the running node
+func (a *App) Reload() error {
+	return a.node.Reload()
+}
+
+// Restart hands the node's sockets over to a new node process and shuts the
+// node down
+func (a *App) Restart() error {
+	return a.node.Restart()
+}
+
 // Assumes [a.node] is not nil.
 // Blocks until [a.node] is done shutting down.
 func (a *App) Stop() {
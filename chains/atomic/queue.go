@@ -0,0 +1,300 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// Number of times an operation is written to shared memory before it's marked
+// as failed, which is a day of retries every 30 seconds
+const defaultMaxAttempts = 2880
+
+var (
+	queuedPrefix = []byte("queued")
+	statePrefix  = []byte("state")
+)
+
+// OperationStatus is the status of an atomic operation of a Queue
+type OperationStatus uint32
+
+const (
+	// Queued operations haven't been written to shared memory yet
+	Queued OperationStatus = iota
+	// Applied operations have been written to shared memory
+	Applied
+	// Failed operations couldn't be written to shared memory in as many
+	// attempts as the queue makes, and aren't retried anymore
+	Failed
+)
+
+func (s OperationStatus) String() string {
+	switch s {
+	case Queued:
+		return "Queued"
+	case Applied:
+		return "Applied"
+	case Failed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// Operation puts the elements that a tx exports into the side of shared
+// memory of the peer chain
+type Operation struct {
+	TxID        ids.ID     `serialize:"true"`
+	PeerChainID ids.ID     `serialize:"true"`
+	Elems       []*Element `serialize:"true"`
+}
+
+// OperationState is the state of the atomic operation of a tx
+type OperationState struct {
+	Status OperationStatus `serialize:"true"`
+	// Number of times that the operation was written to shared memory,
+	// including the attempts that failed
+	Attempts uint32 `serialize:"true"`
+	// Error of the last attempt that failed, if any
+	LastError string `serialize:"true"`
+}
+
+// QueuedOperation is an operation that is waiting to be written to shared
+// memory, or that failed to be
+type QueuedOperation struct {
+	*Operation
+	State *OperationState
+}
+
+// Queue writes the atomic operations of a chain to shared memory. An operation
+// that can't be written when its tx is accepted is persisted, atomically with
+// the acceptance of the tx, and written by Retry later. This way, a chain
+// keeps accepting txs that export UTXOs while shared memory is unavailable,
+// and the exported UTXOs aren't lost. An operation that still can't be written
+// after many attempts is marked as failed, and is kept so that it can be
+// reported.
+//
+// Removals from shared memory aren't queued, since an imported UTXO must be
+// removed when the tx that imports it is accepted.
+//
+// Queue isn't safe for concurrent use.
+type Queue struct {
+	sm    SharedMemory
+	codec codec.Manager
+
+	db       *versiondb.Database
+	queuedDB database.Database
+	stateDB  database.Database
+
+	// Number of attempts after which an operation is marked as failed
+	maxAttempts uint32
+}
+
+// NewQueue returns a queue of the operations of the chain that [sm] is the
+// shared memory of. The queue is persisted in [db], which must have the same
+// underlying database as the batches that are given to Put.
+func NewQueue(sm SharedMemory, db database.Database) (*Queue, error) {
+	c := linearcodec.NewDefault()
+	manager := codec.NewDefaultManager()
+	if err := manager.RegisterCodec(codecVersion, c); err != nil {
+		return nil, err
+	}
+
+	vdb := versiondb.New(db)
+	return &Queue{
+		sm:       sm,
+		codec:    manager,
+		db:       vdb,
+		queuedDB:    prefixdb.New(queuedPrefix, vdb),
+		stateDB:     prefixdb.New(statePrefix, vdb),
+		maxAttempts: defaultMaxAttempts,
+	}, nil
+}
+
+// Put writes [op] to shared memory atomically with [batch]. If that fails,
+// [batch] is written with [op] queued instead, and Put doesn't return an
+// error.
+func (q *Queue) Put(op *Operation, batch database.Batch) error {
+	defer q.db.Abort()
+
+	if err := q.putState(op.TxID, &OperationState{
+		Status:   Applied,
+		Attempts: 1,
+	}); err != nil {
+		return err
+	}
+	stateBatch, err := q.db.CommitBatch()
+	if err != nil {
+		return err
+	}
+	putErr := q.sm.Put(op.PeerChainID, op.Elems, batch, stateBatch)
+	if putErr == nil {
+		return nil
+	}
+	q.db.Abort()
+
+	opBytes, err := q.codec.Marshal(codecVersion, op)
+	if err != nil {
+		return err
+	}
+	if err := q.queuedDB.Put(op.TxID[:], opBytes); err != nil {
+		return err
+	}
+	if err := q.putState(op.TxID, &OperationState{
+		Status:    Queued,
+		Attempts:  1,
+		LastError: putErr.Error(),
+	}); err != nil {
+		return err
+	}
+	queueBatch, err := q.db.CommitBatch()
+	if err != nil {
+		return err
+	}
+	return WriteAll(queueBatch, batch)
+}
+
+// Retry tries to write each queued operation to shared memory again. If an
+// operation can't be written, the failure is recorded in its state and the
+// other operations are still retried. Returns the number of operations that
+// are still queued, and an error if the failure of an operation couldn't be
+// recorded.
+func (q *Queue) Retry() (int, error) {
+	queued, err := q.Queued(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	remaining := 0
+	errs := wrappers.Errs{}
+	for _, op := range queued {
+		if op.State.Status != Queued {
+			continue
+		}
+		op.State.Attempts++
+		if err := q.retry(op); err != nil {
+			errs.Add(fmt.Errorf("couldn't record the failure of the operation of tx %s: %w", op.TxID, err))
+		}
+		if op.State.Status == Queued {
+			remaining++
+		}
+	}
+	return remaining, errs.Err
+}
+
+// retry tries to write [op] to shared memory. If that fails, the error is
+// recorded in the state of [op], which is marked as failed once it was
+// attempted [q.maxAttempts] times.
+func (q *Queue) retry(op *QueuedOperation) error {
+	applyErr := q.apply(op)
+	if applyErr == nil {
+		op.State.Status = Applied
+		op.State.LastError = ""
+		return nil
+	}
+
+	op.State.LastError = applyErr.Error()
+	if op.State.Attempts >= q.maxAttempts {
+		op.State.Status = Failed
+	}
+
+	defer q.db.Abort()
+
+	if err := q.putState(op.TxID, op.State); err != nil {
+		return err
+	}
+	return q.db.Commit()
+}
+
+// apply writes [op] to shared memory, atomically with removing it from the
+// queue
+func (q *Queue) apply(op *QueuedOperation) error {
+	defer q.db.Abort()
+
+	if err := q.queuedDB.Delete(op.TxID[:]); err != nil {
+		return err
+	}
+	if err := q.putState(op.TxID, &OperationState{
+		Status:   Applied,
+		Attempts: op.State.Attempts,
+	}); err != nil {
+		return err
+	}
+	batch, err := q.db.CommitBatch()
+	if err != nil {
+		return err
+	}
+	return q.sm.Put(op.PeerChainID, op.Elems, batch)
+}
+
+// State returns the state of the operation of tx [txID]. Returns
+// database.ErrNotFound if the tx has no operation.
+func (q *Queue) State(txID ids.ID) (*OperationState, error) {
+	stateBytes, err := q.stateDB.Get(txID[:])
+	if err != nil {
+		return nil, err
+	}
+	state := &OperationState{}
+	_, err = q.codec.Unmarshal(stateBytes, state)
+	return state, err
+}
+
+// Queued returns the queued and failed operations that put an element with one
+// of [traits]. If [traits] is empty, every queued and failed operation is
+// returned.
+func (q *Queue) Queued(traits [][]byte) ([]*QueuedOperation, error) {
+	iter := q.queuedDB.NewIterator()
+	defer iter.Release()
+
+	queued := []*QueuedOperation(nil)
+	for iter.Next() {
+		op := &Operation{}
+		if _, err := q.codec.Unmarshal(iter.Value(), op); err != nil {
+			return nil, err
+		}
+		if len(traits) > 0 && !hasTrait(op.Elems, traits) {
+			continue
+		}
+		state, err := q.State(op.TxID)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get the state of the operation of tx %s: %w", op.TxID, err)
+		}
+		queued = append(queued, &QueuedOperation{
+			Operation: op,
+			State:     state,
+		})
+	}
+	return queued, iter.Error()
+}
+
+func (q *Queue) putState(txID ids.ID, state *OperationState) error {
+	stateBytes, err := q.codec.Marshal(codecVersion, state)
+	if err != nil {
+		return err
+	}
+	return q.stateDB.Put(txID[:], stateBytes)
+}
+
+// hasTrait returns true if one of [elems] has one of [traits]
+func hasTrait(elems []*Element, traits [][]byte) bool {
+	for _, elem := range elems {
+		for _, elemTrait := range elem.Traits {
+			for _, trait := range traits {
+				if bytes.Equal(elemTrait, trait) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
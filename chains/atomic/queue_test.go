@@ -0,0 +1,230 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+// unavailableSharedMemory fails to put elements while [unavailable] is set
+type unavailableSharedMemory struct {
+	SharedMemory
+	unavailable bool
+}
+
+func (sm *unavailableSharedMemory) Put(peerChainID ids.ID, elems []*Element, batches ...database.Batch) error {
+	if sm.unavailable {
+		return errUnavailable
+	}
+	return sm.SharedMemory.Put(peerChainID, elems, batches...)
+}
+
+type queueTest struct {
+	chainID0, chainID1 ids.ID
+	sm0                *unavailableSharedMemory
+	sm1                SharedMemory
+	chainDB            *versiondb.Database
+	queue              *Queue
+}
+
+func newQueueTest(t *testing.T) *queueTest {
+	baseDB := memdb.New()
+	m := Memory{}
+	if err := m.Initialize(logging.NoLog{}, prefixdb.New([]byte{0}, baseDB)); err != nil {
+		t.Fatal(err)
+	}
+
+	test := &queueTest{
+		chainID0: ids.GenerateTestID(),
+		chainID1: ids.GenerateTestID(),
+		chainDB:  versiondb.New(prefixdb.New([]byte{1}, baseDB)),
+	}
+	test.sm0 = &unavailableSharedMemory{SharedMemory: m.NewSharedMemory(test.chainID0)}
+	test.sm1 = m.NewSharedMemory(test.chainID1)
+
+	queue, err := NewQueue(test.sm0, prefixdb.New([]byte{2}, baseDB))
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.queue = queue
+	return test
+}
+
+// accept writes [op] to shared memory along with a change to the chain's
+// database
+func (test *queueTest) accept(t *testing.T, op *Operation) {
+	if err := test.chainDB.Put(op.TxID[:], []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	batch, err := test.chainDB.CommitBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := test.queue.Put(op, batch); err != nil {
+		t.Fatal(err)
+	}
+	test.chainDB.Abort()
+}
+
+func TestQueuePut(t *testing.T) {
+	assert := assert.New(t)
+	test := newQueueTest(t)
+
+	op := &Operation{
+		TxID:        ids.GenerateTestID(),
+		PeerChainID: test.chainID1,
+		Elems: []*Element{{
+			Key:    []byte{0},
+			Value:  []byte{1},
+			Traits: [][]byte{{2}},
+		}},
+	}
+	test.accept(t, op)
+
+	values, err := test.sm1.Get(test.chainID0, [][]byte{{0}})
+	assert.NoError(err)
+	assert.Equal([][]byte{{1}}, values)
+	has, err := test.chainDB.Has(op.TxID[:])
+	assert.NoError(err)
+	assert.True(has)
+
+	state, err := test.queue.State(op.TxID)
+	assert.NoError(err)
+	assert.Equal(&OperationState{Status: Applied, Attempts: 1}, state)
+	queued, err := test.queue.Queued(nil)
+	assert.NoError(err)
+	assert.Empty(queued)
+}
+
+func TestQueueRetry(t *testing.T) {
+	assert := assert.New(t)
+	test := newQueueTest(t)
+
+	op := &Operation{
+		TxID:        ids.GenerateTestID(),
+		PeerChainID: test.chainID1,
+		Elems: []*Element{{
+			Key:    []byte{0},
+			Value:  []byte{1},
+			Traits: [][]byte{{2}},
+		}},
+	}
+	test.sm0.unavailable = true
+	test.accept(t, op)
+
+	// The tx is accepted even though its elements aren't in shared memory
+	has, err := test.chainDB.Has(op.TxID[:])
+	assert.NoError(err)
+	assert.True(has)
+	_, err = test.sm1.Get(test.chainID0, [][]byte{{0}})
+	assert.Equal(database.ErrNotFound, err)
+
+	queued, err := test.queue.Queued([][]byte{{2}})
+	assert.NoError(err)
+	if assert.Len(queued, 1) {
+		assert.Equal(op, queued[0].Operation)
+		assert.Equal(&OperationState{Status: Queued, Attempts: 1, LastError: errUnavailable.Error()}, queued[0].State)
+	}
+	queued, err = test.queue.Queued([][]byte{{3}})
+	assert.NoError(err)
+	assert.Empty(queued)
+
+	remaining, err := test.queue.Retry()
+	assert.NoError(err)
+	assert.Equal(1, remaining)
+	state, err := test.queue.State(op.TxID)
+	assert.NoError(err)
+	assert.Equal(&OperationState{Status: Queued, Attempts: 2, LastError: errUnavailable.Error()}, state)
+
+	test.sm0.unavailable = false
+	remaining, err = test.queue.Retry()
+	assert.NoError(err)
+	assert.Equal(0, remaining)
+
+	values, err := test.sm1.Get(test.chainID0, [][]byte{{0}})
+	assert.NoError(err)
+	assert.Equal([][]byte{{1}}, values)
+	state, err = test.queue.State(op.TxID)
+	assert.NoError(err)
+	assert.Equal(&OperationState{Status: Applied, Attempts: 3}, state)
+	queued, err = test.queue.Queued(nil)
+	assert.NoError(err)
+	assert.Empty(queued)
+}
+
+func TestQueueRetryFails(t *testing.T) {
+	assert := assert.New(t)
+	test := newQueueTest(t)
+	test.queue.maxAttempts = 3
+
+	failedOp := &Operation{
+		TxID:        ids.GenerateTestID(),
+		PeerChainID: test.chainID1,
+		Elems: []*Element{{
+			Key:    []byte{0},
+			Value:  []byte{1},
+			Traits: [][]byte{{4}},
+		}},
+	}
+	test.sm0.unavailable = true
+	test.accept(t, failedOp)
+
+	for i := 0; i < 2; i++ {
+		_, err := test.queue.Retry()
+		assert.NoError(err)
+	}
+	state, err := test.queue.State(failedOp.TxID)
+	assert.NoError(err)
+	assert.Equal(&OperationState{Status: Failed, Attempts: 3, LastError: errUnavailable.Error()}, state)
+
+	// Failed operations aren't retried, but the other operations still are
+	op := &Operation{
+		TxID:        ids.GenerateTestID(),
+		PeerChainID: test.chainID1,
+		Elems: []*Element{{
+			Key:    []byte{2},
+			Value:  []byte{3},
+			Traits: [][]byte{{5}},
+		}},
+	}
+	test.accept(t, op)
+	test.sm0.unavailable = false
+	remaining, err := test.queue.Retry()
+	assert.NoError(err)
+	assert.Equal(0, remaining)
+
+	values, err := test.sm1.Get(test.chainID0, [][]byte{{2}})
+	assert.NoError(err)
+	assert.Equal([][]byte{{3}}, values)
+	_, err = test.sm1.Get(test.chainID0, [][]byte{{0}})
+	assert.Equal(database.ErrNotFound, err)
+
+	// Failed operations are still reported
+	queued, err := test.queue.Queued(nil)
+	assert.NoError(err)
+	if assert.Len(queued, 1) {
+		assert.Equal(failedOp, queued[0].Operation)
+		assert.Equal(Failed, queued[0].State.Status)
+		assert.Equal(uint32(3), queued[0].State.Attempts)
+	}
+}
+
+func TestQueueUnknownTx(t *testing.T) {
+	test := newQueueTest(t)
+
+	_, err := test.queue.State(ids.GenerateTestID())
+	assert.Equal(t, database.ErrNotFound, err)
+}
@@ -45,9 +45,9 @@ type dbElement struct {
 
 // Element ...
 type Element struct {
-	Key    []byte
-	Value  []byte
-	Traits [][]byte
+	Key    []byte   `serialize:"true"`
+	Value  []byte   `serialize:"true"`
+	Traits [][]byte `serialize:"true"`
 }
 
 // SharedMemory ...
@@ -0,0 +1,174 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/backup"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+)
+
+var (
+	errNoBackupStore           = errors.New("no backup target is configured")
+	errWrongRestoredFrontier   = errors.New("accepted frontier of the restored chain doesn't match its backup")
+	errUnknownAcceptedFrontier = errors.New("chain doesn't report its accepted frontier")
+)
+
+// Backup writes a backup of the node's database to the backup store while the
+// chains keep running. The chains are only paused while their VMs are
+// checkpointed and a snapshot of the database and their accepted frontiers
+// are taken, so that the backup is consistent across chains. If the database
+// doesn't take snapshots, the chains are paused until the backup is written.
+// Returns the name of the backup and its manifest.
+func (m *manager) Backup() (string, *backup.Manifest, error) {
+	if m.BackupStore == nil {
+		return "", nil, errNoBackupStore
+	}
+
+	m.chainsLock.Lock()
+	handlers := make([]*router.Handler, 0, len(m.chains))
+	chainIDs := make([]ids.ID, 0, len(m.chains))
+	for chainID := range m.chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	ids.SortIDs(chainIDs)
+	for _, chainID := range chainIDs {
+		handlers = append(handlers, m.chains[chainID])
+	}
+	m.chainsLock.Unlock()
+
+	// The chains are always paused in the same order
+	for _, handler := range handlers {
+		handler.Context().Lock.Lock()
+	}
+	paused := true
+	resume := func() {
+		if !paused {
+			return
+		}
+		paused = false
+		for _, handler := range handlers {
+			handler.Context().Lock.Unlock()
+		}
+	}
+	defer resume()
+
+	chains := make([]backup.ChainFrontier, 0, len(handlers))
+	for i, handler := range handlers {
+		engine := handler.Engine()
+		frontier, err := acceptedFrontier(engine)
+		if err != nil {
+			return "", nil, fmt.Errorf("couldn't get accepted frontier of chain %s: %w", chainIDs[i], err)
+		}
+		checkpoint, err := checkpointVM(engine)
+		if err != nil {
+			return "", nil, fmt.Errorf("couldn't checkpoint the VM of chain %s: %w", chainIDs[i], err)
+		}
+		chains = append(chains, backup.ChainFrontier{
+			ChainID:    chainIDs[i],
+			Frontier:   frontier,
+			Checkpoint: checkpoint,
+		})
+	}
+
+	var db database.Iteratee = m.DBManager.Current().Database
+	if snapshotter, ok := db.(database.Snapshotter); ok {
+		snapshot, err := snapshotter.NewSnapshot()
+		switch err {
+		case nil:
+			defer snapshot.Release()
+			db = snapshot
+			resume()
+		case database.ErrSnapshotsUnsupported:
+		default:
+			return "", nil, fmt.Errorf("couldn't take snapshot of the database: %w", err)
+		}
+	}
+	if paused {
+		m.Log.Warn("the database doesn't take snapshots, so the chains are paused until the backup is written")
+	}
+
+	name := fmt.Sprintf("backup-%d", time.Now().Unix())
+	manifest, err := backup.Write(m.BackupStore, name, db, chains)
+	if err != nil {
+		return "", nil, err
+	}
+	m.Log.Info("wrote backup %s of the database with %d key/value pairs", name, manifest.NumKeys)
+	return name, manifest, nil
+}
+
+// verifyRestoredFrontier checks that the accepted frontier of [chainID], which
+// was just built, matches its frontier in the backup that the database was
+// restored from, if there is one
+func (m *manager) verifyRestoredFrontier(chainID ids.ID, engine common.Engine) error {
+	expectedFrontier, restored := m.RestoredFrontiers[chainID]
+	if !restored {
+		return nil
+	}
+
+	ctx := engine.Context()
+	ctx.Lock.Lock()
+	frontier, err := acceptedFrontier(engine)
+	ctx.Lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	expected := ids.NewSet(len(expectedFrontier))
+	expected.Add(expectedFrontier...)
+	actual := ids.NewSet(len(frontier))
+	actual.Add(frontier...)
+	if !expected.Equals(actual) {
+		return fmt.Errorf("%w: expected %s but got %s", errWrongRestoredFrontier, expected, actual)
+	}
+	m.Log.Info("verified the accepted frontier of restored chain %s", chainID)
+	return nil
+}
+
+// restoreCheckpoint restores the VM of [chainID], which was just built, from
+// its checkpoint in the backup that the database was restored from, if the
+// chain is in that backup and its VM can be checkpointed
+func (m *manager) restoreCheckpoint(chainID ids.ID, engine common.Engine) error {
+	if _, restored := m.RestoredFrontiers[chainID]; !restored {
+		return nil
+	}
+	checkpointer, ok := engine.GetVM().(common.Checkpointer)
+	if !ok {
+		return nil
+	}
+
+	ctx := engine.Context()
+	ctx.Lock.Lock()
+	err := checkpointer.Restore(m.RestoredCheckpoints[chainID])
+	ctx.Lock.Unlock()
+	if err != nil {
+		return fmt.Errorf("couldn't restore the VM from its checkpoint: %w", err)
+	}
+	m.Log.Info("restored the VM of chain %s from its checkpoint", chainID)
+	return nil
+}
+
+// checkpointVM returns the checkpoint of the VM of [engine], or nil if the VM
+// can't be checkpointed. Assumes the context lock is held.
+func checkpointVM(engine common.Engine) ([]byte, error) {
+	checkpointer, ok := engine.GetVM().(common.Checkpointer)
+	if !ok {
+		return nil, nil
+	}
+	return checkpointer.Checkpoint()
+}
+
+func acceptedFrontier(engine common.Engine) ([]ids.ID, error) {
+	bootstrapable, ok := engine.(common.Bootstrapable)
+	if !ok {
+		return nil, errUnknownAcceptedFrontier
+	}
+	return bootstrapable.CurrentAcceptedFrontier()
+}
@@ -0,0 +1,148 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/backup"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	dbManager "github.com/ava-labs/avalanchego/database/manager"
+)
+
+func TestBackup(t *testing.T) {
+	assert := assert.New(t)
+
+	store, err := backup.NewStore(t.TempDir())
+	assert.NoError(err)
+	m := &manager{
+		ManagerConfig: ManagerConfig{
+			Log:       logging.NoLog{},
+			DBManager: dbManager.NewDefaultMemDBManager(),
+		},
+		chains: make(map[ids.ID]*router.Handler),
+	}
+
+	// Backups are disabled without a store
+	_, _, err = m.Backup()
+	assert.ErrorIs(err, errNoBackupStore)
+
+	m.BackupStore = store
+	db := m.DBManager.Current().Database
+	assert.NoError(db.Put([]byte{1}, []byte{2}))
+	name, manifest, err := m.Backup()
+	assert.NoError(err)
+	assert.EqualValues(1, manifest.NumKeys)
+
+	verified, err := backup.Verify(store, name)
+	assert.NoError(err)
+	assert.Equal(manifest.Checksum, verified.Checksum)
+}
+
+type bootstrapableEngine struct {
+	*common.EngineTest
+	*common.BootstrapableTest
+}
+
+func TestVerifyRestoredFrontier(t *testing.T) {
+	assert := assert.New(t)
+	chainID := ids.GenerateTestID()
+	frontier := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+
+	ctx := snow.DefaultContextTest()
+	engine := &bootstrapableEngine{
+		EngineTest:        &common.EngineTest{ContextF: func() *snow.Context { return ctx }},
+		BootstrapableTest: &common.BootstrapableTest{},
+	}
+	engine.CurrentAcceptedFrontierF = func() ([]ids.ID, error) {
+		return []ids.ID{frontier[1], frontier[0]}, nil
+	}
+
+	m := &manager{
+		ManagerConfig: ManagerConfig{
+			Log: logging.NoLog{},
+		},
+	}
+	// Chains are only checked if the database was restored
+	assert.NoError(m.verifyRestoredFrontier(chainID, engine))
+
+	m.RestoredFrontiers = map[ids.ID][]ids.ID{chainID: frontier}
+	assert.NoError(m.verifyRestoredFrontier(chainID, engine))
+	assert.NoError(m.verifyRestoredFrontier(ids.GenerateTestID(), engine))
+
+	m.RestoredFrontiers[chainID] = frontier[:1]
+	assert.ErrorIs(m.verifyRestoredFrontier(chainID, engine), errWrongRestoredFrontier)
+}
+
+type checkpointableVM struct {
+	*common.TestVM
+	checkpoint []byte
+	restored   [][]byte
+}
+
+func (vm *checkpointableVM) Checkpoint() ([]byte, error) { return vm.checkpoint, nil }
+
+func (vm *checkpointableVM) Restore(checkpoint []byte) error {
+	vm.restored = append(vm.restored, checkpoint)
+	return nil
+}
+
+func TestCheckpointVM(t *testing.T) {
+	assert := assert.New(t)
+
+	var vm common.VM = &common.TestVM{}
+	engine := &common.EngineTest{GetVMF: func() common.VM { return vm }}
+
+	// VMs that can't be checkpointed have no checkpoint
+	checkpoint, err := checkpointVM(engine)
+	assert.NoError(err)
+	assert.Nil(checkpoint)
+
+	vm = &checkpointableVM{
+		TestVM:     &common.TestVM{},
+		checkpoint: []byte{1, 2, 3},
+	}
+	checkpoint, err = checkpointVM(engine)
+	assert.NoError(err)
+	assert.Equal([]byte{1, 2, 3}, checkpoint)
+}
+
+func TestRestoreCheckpoint(t *testing.T) {
+	assert := assert.New(t)
+	chainID := ids.GenerateTestID()
+	otherChainID := ids.GenerateTestID()
+
+	ctx := snow.DefaultContextTest()
+	vm := &checkpointableVM{TestVM: &common.TestVM{}}
+	engine := &common.EngineTest{
+		ContextF: func() *snow.Context { return ctx },
+		GetVMF:   func() common.VM { return vm },
+	}
+
+	m := &manager{
+		ManagerConfig: ManagerConfig{
+			Log: logging.NoLog{},
+		},
+	}
+	// VMs are only restored if the database was restored
+	assert.NoError(m.restoreCheckpoint(chainID, engine))
+	assert.Empty(vm.restored)
+
+	m.RestoredFrontiers = map[ids.ID][]ids.ID{
+		chainID:      {ids.GenerateTestID()},
+		otherChainID: {ids.GenerateTestID()},
+	}
+	m.RestoredCheckpoints = map[ids.ID][]byte{chainID: {1, 2, 3}}
+	assert.NoError(m.restoreCheckpoint(chainID, engine))
+	assert.NoError(m.restoreCheckpoint(otherChainID, engine))
+	assert.NoError(m.restoreCheckpoint(ids.GenerateTestID(), engine))
+	assert.Equal([][]byte{{1, 2, 3}, nil}, vm.restored)
+}
@@ -0,0 +1,156 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	avcon "github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+)
+
+var errNegativeCacheQuota = errors.New("database cache quota can't be negative")
+
+// ChainOverrides are settings of the node that are overridden for a chain. A
+// nil field isn't overridden.
+type ChainOverrides struct {
+	// Whether the chain's APIs are served
+	APIEnabled *bool
+	// Max number of bytes of the shared database cache that the chain can
+	// take up
+	DBCacheQuota *int
+	// Consensus parameters of the chain's engine
+	Consensus ConsensusOverrides
+}
+
+// ConsensusOverrides are the consensus parameters that are overridden for a
+// chain. Only the parameters that don't change how this node's containers are
+// built are included, so a chain can't be made to issue containers that its
+// other validators reject.
+type ConsensusOverrides struct {
+	K                     *int
+	Alpha                 *int
+	BetaVirtuous          *int
+	BetaRogue             *int
+	ConcurrentRepolls     *int
+	OptimalProcessing     *int
+	MaxOutstandingItems   *int
+	MaxItemProcessingTime *time.Duration
+}
+
+type jsonChainOverrides struct {
+	APIEnabled   *bool                  `json:"apiEnabled"`
+	DBCacheQuota *int                   `json:"dbCacheQuota"`
+	Consensus    jsonConsensusOverrides `json:"consensus"`
+}
+
+type jsonConsensusOverrides struct {
+	K                     *int    `json:"k"`
+	Alpha                 *int    `json:"alpha"`
+	BetaVirtuous          *int    `json:"betaVirtuous"`
+	BetaRogue             *int    `json:"betaRogue"`
+	ConcurrentRepolls     *int    `json:"concurrentRepolls"`
+	OptimalProcessing     *int    `json:"optimalProcessing"`
+	MaxOutstandingItems   *int    `json:"maxOutstandingItems"`
+	MaxItemProcessingTime *string `json:"maxItemProcessingTime"`
+}
+
+// ParseChainOverrides parses the JSON overrides of a chain, such as
+// {"apiEnabled": false, "dbCacheQuota": 1048576, "consensus": {"k": 30, "maxItemProcessingTime": "1m"}}
+// Unknown fields are rejected, so that a misspelled setting isn't ignored.
+func ParseChainOverrides(overridesJSON []byte) (ChainOverrides, error) {
+	if len(overridesJSON) == 0 {
+		return ChainOverrides{}, nil
+	}
+
+	jsonOverrides := jsonChainOverrides{}
+	decoder := json.NewDecoder(bytes.NewReader(overridesJSON))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&jsonOverrides); err != nil {
+		return ChainOverrides{}, err
+	}
+	if jsonOverrides.DBCacheQuota != nil && *jsonOverrides.DBCacheQuota < 0 {
+		return ChainOverrides{}, errNegativeCacheQuota
+	}
+
+	consensus := jsonOverrides.Consensus
+	overrides := ChainOverrides{
+		APIEnabled:   jsonOverrides.APIEnabled,
+		DBCacheQuota: jsonOverrides.DBCacheQuota,
+		Consensus: ConsensusOverrides{
+			K:                   consensus.K,
+			Alpha:               consensus.Alpha,
+			BetaVirtuous:        consensus.BetaVirtuous,
+			BetaRogue:           consensus.BetaRogue,
+			ConcurrentRepolls:   consensus.ConcurrentRepolls,
+			OptimalProcessing:   consensus.OptimalProcessing,
+			MaxOutstandingItems: consensus.MaxOutstandingItems,
+		},
+	}
+	if consensus.MaxItemProcessingTime != nil {
+		maxItemProcessingTime, err := time.ParseDuration(*consensus.MaxItemProcessingTime)
+		if err != nil {
+			return ChainOverrides{}, fmt.Errorf("couldn't parse maxItemProcessingTime: %w", err)
+		}
+		overrides.Consensus.MaxItemProcessingTime = &maxItemProcessingTime
+	}
+	return overrides, nil
+}
+
+// Apply returns [params] with the overridden parameters replaced. Returns an
+// error if the resulting parameters are invalid.
+func (o *ConsensusOverrides) Apply(params avcon.Parameters) (avcon.Parameters, error) {
+	overrideInt(&params.K, o.K)
+	overrideInt(&params.Alpha, o.Alpha)
+	overrideInt(&params.BetaVirtuous, o.BetaVirtuous)
+	overrideInt(&params.BetaRogue, o.BetaRogue)
+	overrideInt(&params.ConcurrentRepolls, o.ConcurrentRepolls)
+	overrideInt(&params.OptimalProcessing, o.OptimalProcessing)
+	overrideInt(&params.MaxOutstandingItems, o.MaxOutstandingItems)
+	if o.MaxItemProcessingTime != nil {
+		params.MaxItemProcessingTime = *o.MaxItemProcessingTime
+	}
+	if err := params.Valid(); err != nil {
+		return avcon.Parameters{}, fmt.Errorf("overridden consensus parameters are invalid: %w", err)
+	}
+	return params, nil
+}
+
+// withFallback returns the overrides with the fields that aren't overridden
+// taken from [fallback]
+func (o ChainOverrides) withFallback(fallback ChainOverrides) ChainOverrides {
+	if o.APIEnabled == nil {
+		o.APIEnabled = fallback.APIEnabled
+	}
+	o.DBCacheQuota = intOrFallback(o.DBCacheQuota, fallback.DBCacheQuota)
+
+	consensus := &o.Consensus
+	consensus.K = intOrFallback(consensus.K, fallback.Consensus.K)
+	consensus.Alpha = intOrFallback(consensus.Alpha, fallback.Consensus.Alpha)
+	consensus.BetaVirtuous = intOrFallback(consensus.BetaVirtuous, fallback.Consensus.BetaVirtuous)
+	consensus.BetaRogue = intOrFallback(consensus.BetaRogue, fallback.Consensus.BetaRogue)
+	consensus.ConcurrentRepolls = intOrFallback(consensus.ConcurrentRepolls, fallback.Consensus.ConcurrentRepolls)
+	consensus.OptimalProcessing = intOrFallback(consensus.OptimalProcessing, fallback.Consensus.OptimalProcessing)
+	consensus.MaxOutstandingItems = intOrFallback(consensus.MaxOutstandingItems, fallback.Consensus.MaxOutstandingItems)
+	if consensus.MaxItemProcessingTime == nil {
+		consensus.MaxItemProcessingTime = fallback.Consensus.MaxItemProcessingTime
+	}
+	return o
+}
+
+func overrideInt(value *int, override *int) {
+	if override != nil {
+		*value = *override
+	}
+}
+
+func intOrFallback(value *int, fallback *int) *int {
+	if value != nil {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,94 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+var testParams = avalanche.Parameters{
+	Parameters: snowball.Parameters{
+		K:                     20,
+		Alpha:                 15,
+		BetaVirtuous:          20,
+		BetaRogue:             30,
+		ConcurrentRepolls:     4,
+		OptimalProcessing:     50,
+		MaxOutstandingItems:   1024,
+		MaxItemProcessingTime: 2 * time.Minute,
+	},
+	Parents:   5,
+	BatchSize: 30,
+}
+
+func TestParseChainOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	overrides, err := ParseChainOverrides(nil)
+	assert.NoError(err)
+	assert.Equal(ChainOverrides{}, overrides)
+
+	overrides, err = ParseChainOverrides([]byte(`{
+		"apiEnabled": false,
+		"dbCacheQuota": 1024,
+		"consensus": {"k": 30, "alpha": 20, "maxItemProcessingTime": "1m"}
+	}`))
+	assert.NoError(err)
+	assert.False(*overrides.APIEnabled)
+	assert.Equal(1024, *overrides.DBCacheQuota)
+	assert.Equal(30, *overrides.Consensus.K)
+	assert.Equal(20, *overrides.Consensus.Alpha)
+	assert.Nil(overrides.Consensus.BetaVirtuous)
+	assert.Equal(time.Minute, *overrides.Consensus.MaxItemProcessingTime)
+
+	// Parameters that change how containers are built can't be overridden
+	_, err = ParseChainOverrides([]byte(`{"consensus": {"parents": 3}}`))
+	assert.Error(err)
+	_, err = ParseChainOverrides([]byte(`{"dbCacheQuota": -1}`))
+	assert.ErrorIs(err, errNegativeCacheQuota)
+	_, err = ParseChainOverrides([]byte(`{"consensus": {"maxItemProcessingTime": "soon"}}`))
+	assert.Error(err)
+}
+
+func TestApplyConsensusOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	overrides, err := ParseChainOverrides([]byte(`{"consensus": {"k": 30, "alpha": 20, "maxItemProcessingTime": "1m"}}`))
+	assert.NoError(err)
+	params, err := overrides.Consensus.Apply(testParams)
+	assert.NoError(err)
+	assert.Equal(30, params.K)
+	assert.Equal(20, params.Alpha)
+	assert.Equal(time.Minute, params.MaxItemProcessingTime)
+	assert.Equal(testParams.BetaVirtuous, params.BetaVirtuous)
+	assert.Equal(testParams.Parents, params.Parents)
+
+	// The overridden parameters must be valid
+	overrides, err = ParseChainOverrides([]byte(`{"consensus": {"alpha": 5}}`))
+	assert.NoError(err)
+	_, err = overrides.Consensus.Apply(testParams)
+	assert.Error(err)
+}
+
+func TestChainOverridesWithFallback(t *testing.T) {
+	assert := assert.New(t)
+
+	chainOverrides, err := ParseChainOverrides([]byte(`{"consensus": {"k": 30}}`))
+	assert.NoError(err)
+	vmOverrides, err := ParseChainOverrides([]byte(`{"apiEnabled": false, "consensus": {"k": 25, "alpha": 20}}`))
+	assert.NoError(err)
+
+	overrides := chainOverrides.withFallback(vmOverrides)
+	assert.False(*overrides.APIEnabled)
+	assert.Nil(overrides.DBCacheQuota)
+	// The chain's overrides take precedence over the VM's
+	assert.Equal(30, *overrides.Consensus.K)
+	assert.Equal(20, *overrides.Consensus.Alpha)
+}
@@ -0,0 +1,31 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+// containerHashConfig is the part of the upgrade config of a chain that
+// selects the hash function its container IDs are derived with. Every node
+// must derive the same IDs, so the selection is coordinated like the chain's
+// upgrades rather than set in its local config.
+type containerHashConfig struct {
+	ContainerHash string `json:"containerHash"`
+}
+
+// getContainerHash returns the hash function selected by the upgrade config
+// [upgradeBytes] of a chain. Returns SHA-256 if none is selected.
+func getContainerHash(upgradeBytes []byte) (hashing.Hasher256, error) {
+	config := containerHashConfig{}
+	if len(upgradeBytes) != 0 {
+		if err := json.Unmarshal(upgradeBytes, &config); err != nil {
+			return nil, fmt.Errorf("couldn't parse container hash: %w", err)
+		}
+	}
+	return hashing.GetHasher256(config.ContainerHash)
+}
@@ -0,0 +1,34 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+func TestGetContainerHash(t *testing.T) {
+	input := []byte{1, 2, 3}
+
+	hash, err := getContainerHash(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash(input) != hashing.ComputeHash256Array(input) {
+		t.Fatal("chains without an upgrade config should use SHA-256")
+	}
+
+	hash, err = getContainerHash([]byte(`{"upgrades":[],"containerHash":"blake3"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash(input) != hashing.ComputeBLAKE3Array(input) {
+		t.Fatal("the upgrade config should have selected BLAKE3")
+	}
+
+	if _, err := getContainerHash([]byte(`{"containerHash":"md5"}`)); err == nil {
+		t.Fatal("shouldn't have selected an unknown hash function")
+	}
+}
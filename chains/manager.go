@@ -6,14 +6,22 @@ package chains
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/avalanchego/api/health"
 	"github.com/ava-labs/avalanchego/api/keystore"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database/backup"
+	"github.com/ava-labs/avalanchego/database/cachedb"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/verify"
+	"github.com/ava-labs/avalanchego/health/watchdog"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow"
@@ -27,9 +35,13 @@ import (
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/snow/triggers"
+	"github.com/ava-labs/avalanchego/snow/upgrade"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/metric"
+	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/ava-labs/avalanchego/vms"
 	"github.com/ava-labs/avalanchego/vms/metervm"
 
@@ -46,6 +58,10 @@ import (
 
 const (
 	defaultChannelSize = 1024
+
+	// Labels applied to every metric registered by a chain
+	chainLabel  = "chain"
+	subnetLabel = "subnet"
 )
 
 var (
@@ -83,9 +99,34 @@ type Manager interface {
 	// Return the aliases associated with a chain
 	Aliases(ids.ID) []string
 
+	// Return the first alias of a chain
+	PrimaryAlias(ids.ID) (string, error)
+
 	// Add an alias to a chain
 	Alias(ids.ID, string) error
 
+	// Remove an alias from the chain it was given to
+	RemoveAlias(string) error
+
+	// Write a snapshot of the state of a chain to a new file in the snapshot
+	// directory. Returns the path of the file and the number of key/value
+	// pairs in the snapshot.
+	ExportSnapshot(ids.ID) (string, uint64, error)
+
+	// Stage a snapshot, which is a file in the snapshot directory, to replace
+	// the state of a chain when the node restarts. Returns the number of
+	// key/value pairs in the snapshot.
+	ImportSnapshot(ids.ID, string) (uint64, error)
+
+	// Write a backup of the node's database to the backup store without
+	// stopping the chains. Returns the name of the backup and its manifest.
+	Backup() (string, *backup.Manifest, error)
+
+	// Scan the database of a chain for corrupted data and check the
+	// consistency of the chain's state. If the bool is true, repairs what
+	// can be repaired. Returns a report of each check.
+	VerifyDB(ids.ID, bool) ([]*verify.Report, error)
+
 	// Returns the ID of the subnet that is validating the provided chain
 	SubnetID(chainID ids.ID) (ids.ID, error)
 
@@ -113,14 +154,20 @@ type chain struct {
 	Ctx     *snow.Context
 	VM      interface{}
 	Beacons validators.Set
+	// False if the chain's APIs aren't served
+	APIEnabled bool
+	// Consistency checks of the chain's state
+	Verifiers []chainVerifier
 }
 
 // ChainConfig is configuration settings for the current execution.
 // [Config] is the user-provided config blob for the chain.
 // [Upgrade] is a chain-specific blob for coordinating upgrades.
+// [Overrides] are settings of the node that are overridden for the chain.
 type ChainConfig struct {
-	Config  []byte
-	Upgrade []byte
+	Config    []byte
+	Upgrade   []byte
+	Overrides ChainOverrides
 }
 
 // ManagerConfig ...
@@ -143,6 +190,7 @@ type ManagerConfig struct {
 	EpochDuration             time.Duration
 	Validators                validators.Manager // Validators validating on this chain
 	NodeID                    ids.ShortID        // The ID of this node
+	StakingBLSKey             *bls.SecretKey     // Signs the cross-subnet messages that chains originate
 	NetworkID                 uint32             // ID of the network this node is connected to
 	Server                    *server.Server     // Handles HTTP API calls
 	Keystore                  keystore.Keystore
@@ -156,11 +204,24 @@ type ManagerConfig struct {
 	RetryBootstrap            bool                   // Should Bootstrap be retried
 	RetryBootstrapMaxAttempts int                    // Max number of times to retry bootstrap
 	ChainConfigs              map[string]ChainConfig // alias -> ChainConfig
+	// VM alias --> config of the chains of the VM. Used for the chains, or
+	// the parts of their config, that aren't configured in [ChainConfigs].
+	// The upgrades of a VM's config are ignored.
+	VMConfigs map[string]ChainConfig
 	// If true, shut down the node after the Primary Network has bootstrapped
 	// and use [FetchOnlyFrom] as beacons
 	FetchOnly bool
 	// [FetchOnlyFrom] ignored unless [FetchOnly] is true
 	FetchOnlyFrom validators.Set
+	// If true, the database is read-only, so the chains only serve the data
+	// they already have. They don't bootstrap from beacons, don't take part in
+	// consensus and don't import staged snapshots.
+	ArchivalMode bool
+	// If non-nil, the reads of each chain's database are cached in its own
+	// partition of [DBCache], which holds at most [DBCacheChainQuota] bytes.
+	// The writes to [DBManager] must invalidate the cached values.
+	DBCache           *cachedb.Cache
+	DBCacheChainQuota int
 	// ShutdownNodeFunc allows the chain manager to issue a request to shutdown the node
 	ShutdownNodeFunc func(exitCode int)
 	MeterVMEnabled   bool // Should each VM be wrapped with a MeterVM
@@ -173,6 +234,44 @@ type ManagerConfig struct {
 	// This node will only consider the first [MultiputMaxContainersReceived]
 	// containers in a multiput it receives.
 	BootstrapMultiputMaxContainersReceived int
+	// If true, the validators sampled for each query are a deterministic
+	// function of the query, so that polls can be replayed
+	DeterministicSampling bool
+	// Directory that the audit log of each chain's decisions is written to. If
+	// empty, decisions aren't audited.
+	AuditLogDir string
+	// If true, the consensus engines record the lifecycle of the containers
+	// they process
+	TraceContainers bool
+	// Parameters of the health checks of each chain's consensus engine
+	ConsensusHealthConfig common.HealthConfig
+	// Watchdog that fires alerts about the chains. May be nil.
+	Watchdog *watchdog.Watchdog
+	// Directory that chain snapshots are exported to and imported from
+	SnapshotDir string
+	// Store that backups of the database are written to. If nil, backups
+	// are disabled.
+	BackupStore backup.Store
+	// If the database was restored from a backup when the node started, the
+	// accepted frontier of each chain in the backup. Each of these chains
+	// checks that its restored state has this frontier when it is created.
+	RestoredFrontiers map[ids.ID][]ids.ID
+	// If the database was restored from a backup when the node started, the
+	// checkpoint of each chain's VM in the backup. The VM of each restored
+	// chain is restored from its checkpoint when the chain is created.
+	RestoredCheckpoints map[ids.ID][]byte
+	// If true, the consistency of the state of each chain is verified, and
+	// repaired where possible, before the chain starts
+	VerifyDBOnStartup bool
+	// The VMs that report validators' liveness are registered here, to be
+	// sampled into validators' uptime histories. May be nil.
+	LivenessSources validators.LivenessSources
+	// The consensus engines sample from views of their validator sets whose
+	// weight changes by at most [ValidatorChurnLimit] times their weight per
+	// [ValidatorChurnPeriod]. If 0, the engines sample from the validator
+	// sets directly.
+	ValidatorChurnLimit  float64
+	ValidatorChurnPeriod time.Duration
 }
 
 type manager struct {
@@ -187,6 +286,9 @@ type manager struct {
 	unblocked     bool
 	blockedChains []ChainParameters
 
+	// Held while a chain is created
+	createLock sync.Mutex
+
 	// Key: Subnet's ID
 	// Value: Subnet description
 	subnets map[ids.ID]Subnet
@@ -195,6 +297,9 @@ type manager struct {
 	// Key: Chain's ID
 	// Value: The chain
 	chains map[ids.ID]*router.Handler
+	// Key: Chain's ID
+	// Value: The consistency checks of the chain's state
+	verifiers map[ids.ID][]chainVerifier
 }
 
 // New returns a new Manager
@@ -203,6 +308,7 @@ func New(config *ManagerConfig) Manager {
 		ManagerConfig: *config,
 		subnets:       make(map[ids.ID]Subnet),
 		chains:        make(map[ids.ID]*router.Handler),
+		verifiers:     make(map[ids.ID][]chainVerifier),
 	}
 	m.Initialize()
 	return m
@@ -220,9 +326,12 @@ func (m *manager) CreateChain(chain ChainParameters) {
 	}
 }
 
-// Create a chain, this is only called from the P-chain thread, except for
-// creating the P-chain.
+// Create a chain. This is called from the P-chain thread, when creating the
+// P-chain, and when a validator snapshot of a subnet is synced.
 func (m *manager) ForceCreateChain(chainParams ChainParameters) {
+	m.createLock.Lock()
+	defer m.createLock.Unlock()
+
 	if chainParams.SubnetID != constants.PrimaryNetworkID && !m.WhitelistedSubnets.Contains(chainParams.SubnetID) {
 		m.Log.Debug("Skipped creating non-whitelisted chain:\n"+
 			"    ID: %s\n"+
@@ -276,7 +385,23 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 		chainParams.CustomBeacons = m.FetchOnlyFrom
 	}
 
+	if m.ArchivalMode {
+		// Without beacons, the chain finishes bootstrapping with the
+		// containers it already has
+		chainParams.CustomBeacons = validators.NewSet()
+	} else if err := m.importPendingSnapshot(chainParams.ID); err != nil {
+		// A staged snapshot replaces the chain's state before the chain starts
+		// bootstrapping, so that it only fetches what was accepted since
+		m.Log.Error("error importing snapshot of chain %s: %s", chainParams.ID, err)
+	}
+
 	chain, err := m.buildChain(chainParams, sb)
+	if err == nil {
+		err = m.restoreCheckpoint(chainParams.ID, chain.Engine)
+	}
+	if err == nil {
+		err = m.verifyRestoredFrontier(chainParams.ID, chain.Engine)
+	}
 	if err != nil {
 		sb.removeChain(chainParams.ID)
 		if m.CriticalChains.Contains(chainParams.ID) {
@@ -291,13 +416,14 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 
 	m.chainsLock.Lock()
 	m.chains[chainParams.ID] = chain.Handler
+	m.verifiers[chainParams.ID] = chain.Verifiers
 	m.chainsLock.Unlock()
 
 	// Associate the newly created chain with its default alias
 	m.Log.AssertNoError(m.Alias(chainParams.ID, chainParams.ID.String()))
 
 	// Notify those that registered to be notified when a new chain is created
-	m.notifyRegistrants(chain.Name, chain.Ctx, chain.Engine)
+	m.notifyRegistrants(chain)
 
 	// Tell the chain to start processing messages.
 	// If the X or P Chain panics, do not attempt to recover
@@ -331,6 +457,32 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		return nil, fmt.Errorf("error while creating chain's log %w", err)
 	}
 
+	vmAlias, err := m.VMManager.PrimaryAlias(vmID)
+	if err != nil {
+		vmAlias = vmID.String()
+	}
+
+	// Every metric of the chain is labeled with the chain and its subnet, so
+	// that the metrics of all chains share the same names.
+	chainMetrics := prometheus.WrapRegistererWith(
+		prometheus.Labels{
+			chainLabel:  primaryAlias,
+			subnetLabel: chainParams.SubnetID.String(),
+		},
+		m.ConsensusParams.Metrics,
+	)
+
+	chainConfig := m.getChainConfig(chainParams.ID, vmID)
+	upgradeBytes := chainConfig.Upgrade
+	upgrades, err := upgrade.Parse(upgradeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing the upgrades of the chain: %w", err)
+	}
+	containerHash, err := getContainerHash(upgradeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error while selecting the container hash of the chain: %w", err)
+	}
+
 	ctx := &snow.Context{
 		NetworkID:            m.NetworkID,
 		SubnetID:             chainParams.SubnetID,
@@ -345,10 +497,14 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		SharedMemory:         m.AtomicMemory.NewSharedMemory(chainParams.ID),
 		BCLookup:             m,
 		SNLookup:             m,
-		Namespace:            fmt.Sprintf("%s_%s_vm", constants.PlatformName, primaryAlias),
-		Metrics:              m.ConsensusParams.Metrics,
+		Namespace:            fmt.Sprintf("%s_%s_vm", constants.PlatformName, metric.SanitizeName(vmAlias)),
+		Metrics:              chainMetrics,
 		EpochFirstTransition: m.EpochFirstTransition,
 		EpochDuration:        m.EpochDuration,
+		Archival:             m.ArchivalMode,
+		Upgrades:             upgrades,
+		ContainerHash:        containerHash,
+		CrossSubnet:          router.NewCrossSubnetOriginator(m.ManagerConfig.Router, m.NodeID, chainParams.ID, m.StakingBLSKey),
 	}
 
 	// Get a factory for the vm we want to use on our chain
@@ -389,8 +545,12 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		}
 	}
 
-	consensusParams := m.ConsensusParams
-	consensusParams.Namespace = fmt.Sprintf("%s_%s", constants.PlatformName, primaryAlias)
+	consensusParams, err := chainConfig.Overrides.Consensus.Apply(m.ConsensusParams)
+	if err != nil {
+		return nil, err
+	}
+	consensusParams.Namespace = fmt.Sprintf("%s_chain", constants.PlatformName)
+	consensusParams.Metrics = chainMetrics
 
 	// The validators of this blockchain
 	var vdrs validators.Set // Validators validating this blockchain
@@ -409,6 +569,15 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		beacons = chainParams.CustomBeacons
 	}
 
+	// Limit how quickly the validators that the engine samples can change,
+	// without changing the validator set that the platform chain maintains
+	if m.StakingEnabled && m.ValidatorChurnLimit > 0 {
+		vdrs, err = m.Validators.GetChurnLimitedValidators(chainParams.SubnetID, m.ValidatorChurnLimit, m.ValidatorChurnPeriod)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get churn limited validators of subnet %s: %w", chainParams.SubnetID, err)
+		}
+	}
+
 	bootstrapWeight := beacons.Weight()
 
 	var chain *chain
@@ -424,6 +593,7 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 			consensusParams,
 			bootstrapWeight,
 			sb,
+			chainConfig,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error while creating new avalanche vm %w", err)
@@ -439,6 +609,7 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 			consensusParams.Parameters,
 			bootstrapWeight,
 			sb,
+			chainConfig,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error while creating new snowman vm %w", err)
@@ -452,9 +623,37 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		return nil, err
 	}
 
+	// The platform chain samples the liveness sources while holding its own
+	// lock, so it can't be one of them
+	if source, ok := vm.(validators.LivenessSource); ok && m.LivenessSources != nil && ctx.ChainID != constants.PlatformChainID {
+		lockedSource := &lockedLivenessSource{
+			lock:   &ctx.Lock,
+			source: source,
+		}
+		if err := m.LivenessSources.Register(ctx.ChainID.String(), lockedSource); err != nil {
+			return nil, err
+		}
+	}
+
+	// The chain's APIs are served unless they're disabled in its config
+	chain.APIEnabled = chainConfig.Overrides.APIEnabled == nil || *chainConfig.Overrides.APIEnabled
 	return chain, nil
 }
 
+// lockedLivenessSource holds the lock of the chain that [source] belongs to
+// while it's asked whether a validator is up
+type lockedLivenessSource struct {
+	lock   *sync.RWMutex
+	source validators.LivenessSource
+}
+
+func (s *lockedLivenessSource) IsUp(nodeID ids.ShortID) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.source.IsUp(nodeID)
+}
+
 // Implements Manager.AddRegistrant
 func (m *manager) AddRegistrant(r Registrant) { m.registrants = append(m.registrants, r) }
 
@@ -468,6 +667,74 @@ func (m *manager) unblockChains() {
 }
 
 // Create a DAG-based blockchain that uses Avalanche
+// Returns [db] with the reads of its current database cached in the partition
+// of the shared database cache that belongs to [chainID], if the cache is
+// enabled. If [quota] is non-nil, it replaces the default size of the
+// partition.
+func (m *manager) newCachedDBManager(chainID ids.ID, db dbManager.Manager, quota *int) (dbManager.Manager, error) {
+	if m.DBCache == nil {
+		return db, nil
+	}
+	chainAlias, err := m.PrimaryAlias(chainID)
+	if err != nil {
+		chainAlias = chainID.String()
+	}
+	chainQuota := m.DBCacheChainQuota
+	if quota != nil {
+		chainQuota = *quota
+	}
+	view := m.DBCache.NewView(db.Current().Database, chainAlias, chainQuota)
+	return dbManager.NewManagerWithCurrent(db, view)
+}
+
+// newAuditLog returns the audit log of the decisions of the chain of [ctx],
+// registered to be told about the chain's decisions. Returns nil if decisions
+// aren't audited.
+func (m *manager) newAuditLog(ctx *snow.Context) (*common.AuditLog, error) {
+	if m.AuditLogDir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(m.AuditLogDir, perms.ReadWriteExecute); err != nil {
+		return nil, fmt.Errorf("couldn't create audit log directory: %w", err)
+	}
+	path := filepath.Join(m.AuditLogDir, fmt.Sprintf("%s.log", ctx.ChainID))
+	auditLog, err := common.OpenAuditLog(path, common.DefaultMaxAuditPolls)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open audit log: %w", err)
+	}
+
+	// A chain that is rebuilt replaces the audit log of its previous instance,
+	// which was closed when the previous engine shut down
+	_ = m.ConsensusEvents.DeregisterChain(ctx.ChainID, common.AuditHandlerID)
+	if err := m.ConsensusEvents.RegisterChain(ctx.ChainID, common.AuditHandlerID, auditLog, false); err != nil {
+		_ = auditLog.Close()
+		return nil, err
+	}
+	return auditLog, nil
+}
+
+// newPollTracker returns the tracker of the queries of the chain of [ctx]. If
+// there is a watchdog, the chain is registered to be watched by it.
+func (m *manager) newPollTracker(ctx *snow.Context) (*common.PollTracker, error) {
+	pollTracker := common.NewPollTracker(m.ConsensusHealthConfig)
+	if m.Watchdog == nil {
+		return pollTracker, nil
+	}
+
+	chainAlias, err := m.PrimaryAlias(ctx.ChainID)
+	if err != nil {
+		chainAlias = ctx.ChainID.String()
+	}
+	watch := m.Watchdog.RegisterChain(chainAlias, ctx, pollTracker)
+
+	// A chain that is rebuilt replaces the watch of its previous instance
+	_ = m.ConsensusEvents.DeregisterChain(ctx.ChainID, watchdog.HandlerID)
+	if err := m.ConsensusEvents.RegisterChain(ctx.ChainID, watchdog.HandlerID, watch, false); err != nil {
+		return nil, err
+	}
+	return pollTracker, nil
+}
+
 func (m *manager) createAvalancheChain(
 	ctx *snow.Context,
 	genesisData []byte,
@@ -478,6 +745,7 @@ func (m *manager) createAvalancheChain(
 	consensusParams avcon.Parameters,
 	bootstrapWeight uint64,
 	sb Subnet,
+	chainConfig ChainConfig,
 ) (*chain, error) {
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
@@ -489,7 +757,11 @@ func (m *manager) createAvalancheChain(
 	if err != nil {
 		return nil, err
 	}
-	prefixDBManager := meterDBManager.NewPrefixDBManager(ctx.ChainID[:])
+	cachedDBManager, err := m.newCachedDBManager(ctx.ChainID, meterDBManager, chainConfig.Overrides.DBCacheQuota)
+	if err != nil {
+		return nil, err
+	}
+	prefixDBManager := cachedDBManager.NewPrefixDBManager(ctx.ChainID[:])
 	vmDBManager := prefixDBManager.NewPrefixDBManager([]byte("vm"))
 
 	db := prefixDBManager.Current()
@@ -510,7 +782,6 @@ func (m *manager) createAvalancheChain(
 	// VM uses this channel to notify engine that a block is ready to be made
 	msgChan := make(chan common.Message, defaultChannelSize)
 
-	chainConfig := m.getChainConfig(ctx.ChainID)
 	if err := vm.Initialize(ctx, vmDBManager, genesisData, chainConfig.Upgrade, chainConfig.Config, msgChan, fxs); err != nil {
 		return nil, fmt.Errorf("error during vm's Initialize: %w", err)
 	}
@@ -520,6 +791,14 @@ func (m *manager) createAvalancheChain(
 	vtxManager := &state.Serializer{}
 	vtxManager.Initialize(ctx, vm, vertexDB)
 
+	verifiers := []chainVerifier{{name: "vertices", Verifier: vtxManager}}
+	if m.VerifyDBOnStartup {
+		// Repair the chain's state before the engine starts
+		if _, err := m.verifyChain(ctx.ChainID, verifiers, true); err != nil {
+			return nil, err
+		}
+	}
+
 	// Passes messages from the consensus engine to the network
 	sender := sender.Sender{}
 	err = sender.Initialize(ctx, m.Net, m.ManagerConfig.Router, m.TimeoutManager, consensusParams.Namespace, consensusParams.Metrics)
@@ -540,6 +819,15 @@ func (m *manager) createAvalancheChain(
 		Preempt: sb.afterBootstrapped(),
 	}
 
+	auditLog, err := m.newAuditLog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pollTracker, err := m.newPollTracker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// The engine handles consensus
 	engine := &aveng.Transitive{}
 	if err := engine.Initialize(aveng.Config{
@@ -559,6 +847,11 @@ func (m *manager) createAvalancheChain(
 				MaxTimeGetAncestors:           m.BootstrapMaxTimeGetAncestors,
 				MultiputMaxContainersSent:     m.BootstrapMultiputMaxContainersSent,
 				MultiputMaxContainersReceived: m.BootstrapMultiputMaxContainersReceived,
+				DeterministicSampling:         m.DeterministicSampling,
+				AuditLog:                      auditLog,
+				TraceContainers:               m.TraceContainers,
+				HealthConfig:                  m.ConsensusHealthConfig,
+				PollTracker:                   pollTracker,
 			},
 			VtxBlocked: vtxBlocker,
 			TxBlocked:  txBlocker,
@@ -600,11 +893,12 @@ func (m *manager) createAvalancheChain(
 	)
 
 	return &chain{
-		Name:    chainAlias,
-		Engine:  engine,
-		Handler: handler,
-		VM:      vm,
-		Ctx:     ctx,
+		Name:      chainAlias,
+		Engine:    engine,
+		Handler:   handler,
+		VM:        vm,
+		Ctx:       ctx,
+		Verifiers: verifiers,
 	}, err
 }
 
@@ -619,10 +913,19 @@ func (m *manager) createSnowmanChain(
 	consensusParams snowball.Parameters,
 	bootstrapWeight uint64,
 	sb Subnet,
+	chainConfig ChainConfig,
 ) (*chain, error) {
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
+	// The chains of the platform chain are created once it is bootstrapped.
+	// Other chains, such as those synced from validator snapshots, can finish
+	// bootstrapping first.
+	onBootstrapped := func() {}
+	if ctx.ChainID == constants.PlatformChainID {
+		onBootstrapped = m.unblockChains
+	}
+
 	if m.MeterVMEnabled {
 		vm = metervm.NewBlockVM(vm)
 	}
@@ -630,7 +933,11 @@ func (m *manager) createSnowmanChain(
 	if err != nil {
 		return nil, err
 	}
-	prefixDBManager := meterDBManager.NewPrefixDBManager(ctx.ChainID[:])
+	cachedDBManager, err := m.newCachedDBManager(ctx.ChainID, meterDBManager, chainConfig.Overrides.DBCacheQuota)
+	if err != nil {
+		return nil, err
+	}
+	prefixDBManager := cachedDBManager.NewPrefixDBManager(ctx.ChainID[:])
 	vmDBManager := prefixDBManager.NewPrefixDBManager([]byte("vm"))
 
 	db := prefixDBManager.Current()
@@ -646,7 +953,6 @@ func (m *manager) createSnowmanChain(
 	msgChan := make(chan common.Message, defaultChannelSize)
 
 	// Initialize the VM
-	chainConfig := m.getChainConfig(ctx.ChainID)
 	if err := vm.Initialize(ctx, vmDBManager, genesisData, chainConfig.Upgrade, chainConfig.Config, msgChan, fxs); err != nil {
 		return nil, err
 	}
@@ -678,6 +984,15 @@ func (m *manager) createSnowmanChain(
 		Preempt: sb.afterBootstrapped(),
 	}
 
+	auditLog, err := m.newAuditLog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pollTracker, err := m.newPollTracker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// The engine handles consensus
 	engine := &smeng.Transitive{}
 	if err := engine.Initialize(smeng.Config{
@@ -697,10 +1012,15 @@ func (m *manager) createSnowmanChain(
 				MaxTimeGetAncestors:           m.BootstrapMaxTimeGetAncestors,
 				MultiputMaxContainersSent:     m.BootstrapMultiputMaxContainersSent,
 				MultiputMaxContainersReceived: m.BootstrapMultiputMaxContainersReceived,
+				DeterministicSampling:         m.DeterministicSampling,
+				AuditLog:                      auditLog,
+				TraceContainers:               m.TraceContainers,
+				HealthConfig:                  m.ConsensusHealthConfig,
+				PollTracker:                   pollTracker,
 			},
 			Blocked:      blocked,
 			VM:           vm,
-			Bootstrapped: m.unblockChains,
+			Bootstrapped: onBootstrapped,
 		},
 		Params:    consensusParams,
 		Consensus: &smcon.Topological{},
@@ -779,10 +1099,14 @@ func (m *manager) Shutdown() {
 func (m *manager) LookupVM(alias string) (ids.ID, error) { return m.VMManager.Lookup(alias) }
 
 // Notify registrants [those who want to know about the creation of chains]
-// that the specified chain has been created
-func (m *manager) notifyRegistrants(name string, ctx *snow.Context, engine common.Engine) {
+// that the specified chain has been created. The API server isn't notified if
+// the chain's APIs are disabled.
+func (m *manager) notifyRegistrants(chain *chain) {
 	for _, registrant := range m.registrants {
-		registrant.RegisterChain(name, ctx, engine)
+		if !chain.APIEnabled && registrant == Registrant(m.Server) {
+			continue
+		}
+		registrant.RegisterChain(chain.Name, chain.Ctx, chain.Engine)
 	}
 }
 
@@ -799,17 +1123,30 @@ func (m *manager) isChainWithAlias(aliases ...string) (string, bool) {
 }
 
 // getChainConfig returns value of a entry by looking at ID key and alias key
-// it first searches ID key, then falls back to it's corresponding primary alias
-func (m *manager) getChainConfig(id ids.ID) ChainConfig {
-	if val, ok := m.ManagerConfig.ChainConfigs[id.String()]; ok {
-		return val
-	}
-	aliases := m.Aliases(id)
-	for _, alias := range aliases {
-		if val, ok := m.ManagerConfig.ChainConfigs[alias]; ok {
-			return val
+// it first searches ID key, then falls back to it's corresponding primary alias.
+// The config and overrides that the chain doesn't have are taken from the
+// config of the VM with [vmID], if it has them.
+func (m *manager) getChainConfig(id ids.ID, vmID ids.ID) ChainConfig {
+	config, ok := m.ManagerConfig.ChainConfigs[id.String()]
+	if !ok {
+		for _, alias := range m.Aliases(id) {
+			if config, ok = m.ManagerConfig.ChainConfigs[alias]; ok {
+				break
+			}
 		}
 	}
 
-	return ChainConfig{}
+	vmConfig, ok := m.ManagerConfig.VMConfigs[vmID.String()]
+	if !ok {
+		for _, alias := range m.VMManager.Aliases(vmID) {
+			if vmConfig, ok = m.ManagerConfig.VMConfigs[alias]; ok {
+				break
+			}
+		}
+	}
+	if len(config.Config) == 0 {
+		config.Config = vmConfig.Config
+	}
+	config.Overrides = config.Overrides.withFallback(vmConfig.Overrides)
+	return config
 }
@@ -1,6 +1,8 @@
 package chains
 
 import (
+	"github.com/ava-labs/avalanchego/database/backup"
+	"github.com/ava-labs/avalanchego/database/verify"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 )
@@ -15,10 +17,20 @@ func (mm MockManager) ForceCreateChain(ChainParameters) {}
 func (mm MockManager) AddRegistrant(Registrant)         {}
 func (mm MockManager) Aliases(ids.ID) []string          { return nil }
 func (mm MockManager) Alias(ids.ID, string) error       { return nil }
+func (mm MockManager) RemoveAlias(string) error         { return nil }
 func (mm MockManager) Shutdown()                        {}
 func (mm MockManager) SubnetID(ids.ID) (ids.ID, error)  { return ids.ID{}, nil }
 func (mm MockManager) IsBootstrapped(ids.ID) bool       { return false }
 
+func (mm MockManager) ExportSnapshot(ids.ID) (string, uint64, error) { return "", 0, nil }
+func (mm MockManager) ImportSnapshot(ids.ID, string) (uint64, error) { return 0, nil }
+
+func (mm MockManager) PrimaryAlias(ids.ID) (string, error) { return "", nil }
+
+func (mm MockManager) Backup() (string, *backup.Manifest, error) { return "", &backup.Manifest{}, nil }
+
+func (mm MockManager) VerifyDB(ids.ID, bool) ([]*verify.Report, error) { return nil, nil }
+
 func (mm MockManager) Lookup(s string) (ids.ID, error) {
 	id, err := ids.FromString(s)
 	if err == nil {
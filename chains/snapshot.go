@@ -0,0 +1,344 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+const (
+	snapshotVersion = uint16(0)
+
+	// Name of the directory, in the snapshot directory, that holds the
+	// snapshots to import when the chains are created
+	pendingSnapshotDir = "pending"
+	snapshotExtension  = ".snapshot"
+
+	// Marks whether another key/value pair follows in a snapshot
+	snapshotRecord = byte(1)
+	snapshotEnd    = byte(0)
+
+	// Number of bytes written to the database at a time when a snapshot is
+	// imported
+	snapshotBatchSize = 4 * 1024 * 1024
+)
+
+var (
+	errUnknownChain          = errors.New("unknown chain")
+	errNoSnapshotDir         = errors.New("no snapshot directory is configured")
+	errInvalidSnapshotName   = errors.New("snapshot must be a file in the snapshot directory")
+	errUnknownSnapshotVer    = errors.New("unknown snapshot version")
+	errWrongSnapshotChain    = errors.New("snapshot is of a different chain")
+	errWrongSnapshotNumPairs = errors.New("snapshot has the wrong number of key/value pairs")
+)
+
+// writeSnapshot writes every key/value pair in [db], which holds the state of
+// [chainID], to [w]. Returns the number of pairs written.
+func writeSnapshot(db database.Database, chainID ids.ID, w io.Writer) (uint64, error) {
+	zw := gzip.NewWriter(w)
+	bw := bufio.NewWriter(zw)
+
+	if err := binary.Write(bw, binary.BigEndian, snapshotVersion); err != nil {
+		return 0, err
+	}
+	if _, err := bw.Write(chainID[:]); err != nil {
+		return 0, err
+	}
+
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	numPairs := uint64(0)
+	for iter.Next() {
+		if err := bw.WriteByte(snapshotRecord); err != nil {
+			return numPairs, err
+		}
+		if err := writeSnapshotBytes(bw, iter.Key()); err != nil {
+			return numPairs, err
+		}
+		if err := writeSnapshotBytes(bw, iter.Value()); err != nil {
+			return numPairs, err
+		}
+		numPairs++
+	}
+	if err := iter.Error(); err != nil {
+		return numPairs, fmt.Errorf("couldn't iterate over the chain's database: %w", err)
+	}
+
+	if err := bw.WriteByte(snapshotEnd); err != nil {
+		return numPairs, err
+	}
+	if err := binary.Write(bw, binary.BigEndian, numPairs); err != nil {
+		return numPairs, err
+	}
+	if err := bw.Flush(); err != nil {
+		return numPairs, err
+	}
+	return numPairs, zw.Close()
+}
+
+func writeSnapshotBytes(w io.Writer, b []byte) error {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// readSnapshot calls [onPair] with each key/value pair of the snapshot of
+// [chainID] in [r]. Returns the number of pairs read.
+func readSnapshot(r io.Reader, chainID ids.ID, onPair func(key, value []byte) error) (uint64, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't decompress snapshot: %w", err)
+	}
+	defer zr.Close()
+	br := bufio.NewReader(zr)
+
+	version := uint16(0)
+	if err := binary.Read(br, binary.BigEndian, &version); err != nil {
+		return 0, err
+	}
+	if version != snapshotVersion {
+		return 0, fmt.Errorf("%w: %d", errUnknownSnapshotVer, version)
+	}
+	snapshotChainID := ids.ID{}
+	if _, err := io.ReadFull(br, snapshotChainID[:]); err != nil {
+		return 0, err
+	}
+	if snapshotChainID != chainID {
+		return 0, fmt.Errorf("%w: expected %s but got %s", errWrongSnapshotChain, chainID, snapshotChainID)
+	}
+
+	numPairs := uint64(0)
+	for {
+		marker, err := br.ReadByte()
+		if err != nil {
+			return numPairs, err
+		}
+		if marker == snapshotEnd {
+			break
+		}
+		key, err := readSnapshotBytes(br)
+		if err != nil {
+			return numPairs, err
+		}
+		value, err := readSnapshotBytes(br)
+		if err != nil {
+			return numPairs, err
+		}
+		if err := onPair(key, value); err != nil {
+			return numPairs, err
+		}
+		numPairs++
+	}
+
+	expectedNumPairs := uint64(0)
+	if err := binary.Read(br, binary.BigEndian, &expectedNumPairs); err != nil {
+		return numPairs, err
+	}
+	if numPairs != expectedNumPairs {
+		return numPairs, fmt.Errorf("%w: expected %d but got %d", errWrongSnapshotNumPairs, expectedNumPairs, numPairs)
+	}
+	// The checksum of the snapshot is verified once the end of the stream is
+	// reached
+	if _, err := io.Copy(ioutil.Discard, br); err != nil {
+		return numPairs, fmt.Errorf("couldn't verify snapshot: %w", err)
+	}
+	return numPairs, nil
+}
+
+func readSnapshotBytes(r io.Reader) ([]byte, error) {
+	length := uint32(0)
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	b := make([]byte, length)
+	_, err := io.ReadFull(r, b)
+	return b, err
+}
+
+// applySnapshot replaces the contents of [db], which holds the state of
+// [chainID], with the snapshot in [r]. Returns the number of pairs written.
+func applySnapshot(db database.Database, chainID ids.ID, r io.Reader) (uint64, error) {
+	batch := db.NewBatch()
+	iter := db.NewIterator()
+	for iter.Next() {
+		if err := batch.Delete(iter.Key()); err != nil {
+			iter.Release()
+			return 0, err
+		}
+		if batch.Size() < snapshotBatchSize {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			iter.Release()
+			return 0, err
+		}
+		batch.Reset()
+	}
+	err := iter.Error()
+	iter.Release()
+	if err != nil {
+		return 0, fmt.Errorf("couldn't clear the chain's database: %w", err)
+	}
+
+	numPairs, err := readSnapshot(r, chainID, func(key, value []byte) error {
+		if err := batch.Put(key, value); err != nil {
+			return err
+		}
+		if batch.Size() < snapshotBatchSize {
+			return nil
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		return nil
+	})
+	if err != nil {
+		return numPairs, err
+	}
+	return numPairs, batch.Write()
+}
+
+// ExportSnapshot writes a snapshot of the state of [chainID] to a new file in
+// the snapshot directory. The chain doesn't process anything while the
+// snapshot is written, so the snapshot is consistent. Returns the path of the
+// file and the number of key/value pairs in the snapshot.
+func (m *manager) ExportSnapshot(chainID ids.ID) (string, uint64, error) {
+	if m.SnapshotDir == "" {
+		return "", 0, errNoSnapshotDir
+	}
+	m.chainsLock.Lock()
+	chain, exists := m.chains[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return "", 0, fmt.Errorf("%w: %s", errUnknownChain, chainID)
+	}
+
+	if err := os.MkdirAll(m.SnapshotDir, perms.ReadWriteExecute); err != nil {
+		return "", 0, fmt.Errorf("couldn't create snapshot directory: %w", err)
+	}
+	path := filepath.Join(m.SnapshotDir, fmt.Sprintf("%s-%d%s", chainID, time.Now().Unix(), snapshotExtension))
+	file, err := perms.Create(path, perms.ReadWrite)
+	if err != nil {
+		return "", 0, fmt.Errorf("couldn't create snapshot file: %w", err)
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	numPairs, err := writeSnapshot(m.chainDB(chainID), chainID, file)
+	ctx.Lock.Unlock()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return "", 0, fmt.Errorf("couldn't write snapshot: %w", err)
+	}
+	m.Log.Info("exported snapshot of chain %s with %d key/value pairs to %s", chainID, numPairs, path)
+	return path, numPairs, nil
+}
+
+// ImportSnapshot checks that [name], a file in the snapshot directory, is a
+// snapshot of [chainID] and stages it to replace the state of [chainID] the
+// next time the chain is created, which is when the node restarts.
+func (m *manager) ImportSnapshot(chainID ids.ID, name string) (uint64, error) {
+	if m.SnapshotDir == "" {
+		return 0, errNoSnapshotDir
+	}
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		return 0, fmt.Errorf("%w: %s", errInvalidSnapshotName, name)
+	}
+	path := filepath.Join(m.SnapshotDir, name)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't open snapshot: %w", err)
+	}
+	numPairs, err := readSnapshot(file, chainID, func([]byte, []byte) error { return nil })
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	pendingDir := filepath.Join(m.SnapshotDir, pendingSnapshotDir)
+	if err := os.MkdirAll(pendingDir, perms.ReadWriteExecute); err != nil {
+		return 0, fmt.Errorf("couldn't create pending snapshot directory: %w", err)
+	}
+	pendingPath := filepath.Join(pendingDir, chainID.String()+snapshotExtension)
+	if err := copyFile(path, pendingPath); err != nil {
+		return 0, fmt.Errorf("couldn't stage snapshot: %w", err)
+	}
+	m.Log.Info("staged snapshot %s of chain %s, which will be imported when the node restarts", path, chainID)
+	return numPairs, nil
+}
+
+// importPendingSnapshot replaces the state of [chainID] with its staged
+// snapshot, if there is one. Must be called before the chain is built.
+func (m *manager) importPendingSnapshot(chainID ids.ID) error {
+	if m.SnapshotDir == "" {
+		return nil
+	}
+	path := filepath.Join(m.SnapshotDir, pendingSnapshotDir, chainID.String()+snapshotExtension)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't open pending snapshot: %w", err)
+	}
+	defer file.Close()
+
+	m.Log.Info("importing snapshot %s into chain %s", path, chainID)
+	numPairs, err := applySnapshot(m.chainDB(chainID), chainID, file)
+	if err != nil {
+		return fmt.Errorf("couldn't import snapshot: %w", err)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("couldn't remove imported snapshot: %w", err)
+	}
+	m.Log.Info("imported snapshot of chain %s with %d key/value pairs", chainID, numPairs)
+	return nil
+}
+
+// chainDB returns the database that holds the state of [chainID]
+func (m *manager) chainDB(chainID ids.ID) database.Database {
+	return prefixdb.New(chainID[:], m.DBManager.Current().Database)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := perms.Create(dst, perms.ReadWrite)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,105 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	dbManager "github.com/ava-labs/avalanchego/database/manager"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	chainID := ids.GenerateTestID()
+
+	src := memdb.New()
+	assert.NoError(src.Put([]byte{1}, []byte{2}))
+	assert.NoError(src.Put([]byte{3, 4}, []byte{}))
+	assert.NoError(prefixdb.New([]byte("vm"), src).Put([]byte{5}, []byte{6, 7}))
+
+	buf := &bytes.Buffer{}
+	numPairs, err := writeSnapshot(src, chainID, buf)
+	assert.NoError(err)
+	assert.EqualValues(3, numPairs)
+	snapshot := buf.Bytes()
+
+	// The state of the chain is replaced by the snapshot
+	dst := memdb.New()
+	assert.NoError(dst.Put([]byte{9}, []byte{9}))
+	assert.NoError(dst.Put([]byte{1}, []byte{0}))
+	numPairs, err = applySnapshot(dst, chainID, bytes.NewReader(snapshot))
+	assert.NoError(err)
+	assert.EqualValues(3, numPairs)
+
+	has, err := dst.Has([]byte{9})
+	assert.NoError(err)
+	assert.False(has)
+	value, err := dst.Get([]byte{1})
+	assert.NoError(err)
+	assert.Equal([]byte{2}, value)
+	value, err = prefixdb.New([]byte("vm"), dst).Get([]byte{5})
+	assert.NoError(err)
+	assert.Equal([]byte{6, 7}, value)
+
+	// Snapshots of other chains are rejected
+	_, err = applySnapshot(memdb.New(), ids.GenerateTestID(), bytes.NewReader(snapshot))
+	assert.ErrorIs(err, errWrongSnapshotChain)
+
+	// Truncated snapshots are rejected
+	_, err = readSnapshot(bytes.NewReader(snapshot[:len(snapshot)-8]), chainID, func([]byte, []byte) error { return nil })
+	assert.Error(err)
+}
+
+func TestImportPendingSnapshot(t *testing.T) {
+	assert := assert.New(t)
+	chainID := ids.GenerateTestID()
+	snapshotDir := t.TempDir()
+
+	m := &manager{
+		ManagerConfig: ManagerConfig{
+			Log:         logging.NoLog{},
+			DBManager:   dbManager.NewDefaultMemDBManager(),
+			SnapshotDir: snapshotDir,
+		},
+	}
+
+	src := memdb.New()
+	assert.NoError(src.Put([]byte{1}, []byte{2}))
+	buf := &bytes.Buffer{}
+	_, err := writeSnapshot(src, chainID, buf)
+	assert.NoError(err)
+	assert.NoError(ioutil.WriteFile(filepath.Join(snapshotDir, "x.snapshot"), buf.Bytes(), 0o600))
+
+	// Only files in the snapshot directory can be imported
+	_, err = m.ImportSnapshot(chainID, "../x.snapshot")
+	assert.ErrorIs(err, errInvalidSnapshotName)
+	_, err = m.ImportSnapshot(ids.GenerateTestID(), "x.snapshot")
+	assert.ErrorIs(err, errWrongSnapshotChain)
+
+	numPairs, err := m.ImportSnapshot(chainID, "x.snapshot")
+	assert.NoError(err)
+	assert.EqualValues(1, numPairs)
+
+	// The snapshot is imported when the chain is created
+	assert.NoError(m.importPendingSnapshot(chainID))
+	value, err := m.chainDB(chainID).Get([]byte{1})
+	assert.NoError(err)
+	assert.Equal([]byte{2}, value)
+
+	// A snapshot is only imported once
+	_, err = os.Stat(filepath.Join(snapshotDir, pendingSnapshotDir, chainID.String()+snapshotExtension))
+	assert.True(os.IsNotExist(err))
+	assert.NoError(m.importPendingSnapshot(chainID))
+}
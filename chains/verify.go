@@ -0,0 +1,61 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database/verify"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// chainVerifier checks the consistency of a part of the state of a chain
+type chainVerifier struct {
+	// Describes the part of the state, for the reports
+	name string
+	verify.Verifier
+}
+
+// VerifyDB scans the database of [chainID] for corrupted data and checks the
+// consistency of the chain's state, repairing what it can if [repair]. The
+// chain doesn't process anything while it's verified. Returns the report of
+// the scan followed by the report of each consistency check.
+func (m *manager) VerifyDB(chainID ids.ID, repair bool) ([]*verify.Report, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[chainID]
+	verifiers := m.verifiers[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", errUnknownChain, chainID)
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	scanReport := verify.NewReport(fmt.Sprintf("database of chain %s", chainID))
+	verify.Scan(m.chainDB(chainID), scanReport)
+	scanReport.Log(m.Log)
+
+	reports, err := m.verifyChain(chainID, verifiers, repair)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*verify.Report{scanReport}, reports...), nil
+}
+
+// verifyChain runs the consistency checks of [chainID] and logs their
+// reports. Assumes the chain's context lock is held.
+func (m *manager) verifyChain(chainID ids.ID, verifiers []chainVerifier, repair bool) ([]*verify.Report, error) {
+	reports := make([]*verify.Report, 0, len(verifiers))
+	for _, verifier := range verifiers {
+		report := verify.NewReport(fmt.Sprintf("%s of chain %s", verifier.name, chainID))
+		if err := verifier.VerifyDB(repair, report); err != nil {
+			return nil, fmt.Errorf("couldn't verify %s of chain %s: %w", verifier.name, chainID, err)
+		}
+		report.Log(m.Log)
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
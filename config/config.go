@@ -4,6 +4,8 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,22 +19,36 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/app/process"
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database/backup"
+	"github.com/ava-labs/avalanchego/database/benchmark"
+	"github.com/ava-labs/avalanchego/database/compaction"
+	"github.com/ava-labs/avalanchego/database/cryptdb"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/rpcdb"
 	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/health/watchdog"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/ipcs"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/node"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/dynamicip"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/password"
 	"github.com/ava-labs/avalanchego/utils/ulimit"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/sandbox"
+	"github.com/ava-labs/avalanchego/vms/scaffold"
 )
 
 const (
@@ -40,6 +56,7 @@ const (
 	avalanchegoPreupgrade = "avalanchego-preupgrade"
 	chainConfigFileName   = "config"
 	chainUpgradeFileName  = "upgrade"
+	chainNodeFileName     = "node"
 )
 
 var (
@@ -102,6 +119,177 @@ func GetProcessConfig(v *viper.Viper) (process.Config, error) {
 	return config, nil
 }
 
+// GetDBBenchmarkConfig returns the config of a benchmark of the configured
+// database backend, in the database directory
+func GetDBBenchmarkConfig(v *viper.Viper) (benchmark.Config, error) {
+	config := benchmark.Config{
+		Dir:         os.ExpandEnv(v.GetString(DBPathKey)),
+		Backend:     v.GetString(DBTypeKey),
+		NumKeys:     int(v.GetUint(DBBenchmarkKeysKey)),
+		ValueSize:   int(v.GetUint(DBBenchmarkValueSizeKey)),
+		Concurrency: int(v.GetUint(DBBenchmarkConcurrencyKey)),
+	}
+	if _, err := manager.GetBackend(config.Backend); err != nil {
+		return benchmark.Config{}, fmt.Errorf("couldn't parse %s: %w", DBTypeKey, err)
+	}
+	return config, config.Verify()
+}
+
+// DBServerConfig is the config of the database server that the db-server
+// command runs
+type DBServerConfig struct {
+	// Address that the database is served on
+	Address string
+	// Directory of the database
+	Dir string
+	// Name of the backend of the database
+	Backend string
+	// How the connections to the database server are secured
+	Security rpcdb.Security
+}
+
+// GetDBServerConfig returns the config of a database server that serves a
+// database of the configured backend, in the database directory
+func GetDBServerConfig(v *viper.Viper) (DBServerConfig, error) {
+	config := DBServerConfig{
+		Address: v.GetString(DBServerAddressKey),
+		Dir:     filepath.Join(os.ExpandEnv(v.GetString(DBPathKey)), "server"),
+		Backend: v.GetString(DBTypeKey),
+	}
+	if _, err := manager.GetBackend(config.Backend); err != nil {
+		return DBServerConfig{}, fmt.Errorf("couldn't parse %s: %w", DBTypeKey, err)
+	}
+	authKey, err := getDBAuthKey(v)
+	if err != nil {
+		return DBServerConfig{}, err
+	}
+	config.Security.AuthKey = authKey
+	keyFile := os.ExpandEnv(v.GetString(DBServerTLSKeyFileKey))
+	certFile := os.ExpandEnv(v.GetString(DBServerTLSCertFileKey))
+	if keyFile != "" || certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return DBServerConfig{}, fmt.Errorf("couldn't load %s and %s: %w", DBServerTLSCertFileKey, DBServerTLSKeyFileKey, err)
+		}
+		config.Security.TLS = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+	if err := config.Security.Verify(config.Address); err != nil {
+		return DBServerConfig{}, fmt.Errorf("couldn't serve on %s: %w", DBServerAddressKey, err)
+	}
+	return config, nil
+}
+
+// getDBRemoteSecurity returns how the connections of the node to the database
+// server are secured
+func getDBRemoteSecurity(v *viper.Viper) (rpcdb.Security, error) {
+	authKey, err := getDBAuthKey(v)
+	if err != nil {
+		return rpcdb.Security{}, err
+	}
+	security := rpcdb.Security{AuthKey: authKey}
+	if caFile := os.ExpandEnv(v.GetString(DBRemoteTLSCAFileKey)); caFile != "" {
+		pemCerts, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return rpcdb.Security{}, fmt.Errorf("couldn't read %s: %w", DBRemoteTLSCAFileKey, err)
+		}
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(pemCerts) {
+			return rpcdb.Security{}, fmt.Errorf("%s has no PEM encoded certificates", DBRemoteTLSCAFileKey)
+		}
+		security.TLS = &tls.Config{
+			RootCAs:    certPool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	if err := security.Verify(v.GetString(DBRemoteAddressKey)); err != nil {
+		return rpcdb.Security{}, fmt.Errorf("couldn't dial %s: %w", DBRemoteAddressKey, err)
+	}
+	return security, nil
+}
+
+func getDBAuthKey(v *viper.Viper) ([]byte, error) {
+	keySource := v.GetString(DBAuthKeyKey)
+	if keySource == "" {
+		return nil, nil
+	}
+	authKey, err := cryptdb.LoadKey(keySource)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load %s: %w", DBAuthKeyKey, err)
+	}
+	return authKey, nil
+}
+
+// GetCreateVMConfig returns the config of the VM that the create-vm command
+// generates
+func GetCreateVMConfig(v *viper.Viper) (scaffold.Config, error) {
+	vmType, err := scaffold.ParseVMType(v.GetString(CreateVMTypeKey))
+	if err != nil {
+		return scaffold.Config{}, fmt.Errorf("couldn't parse %s: %w", CreateVMTypeKey, err)
+	}
+	config := scaffold.Config{
+		Name:               v.GetString(CreateVMNameKey),
+		Type:               vmType,
+		Module:             v.GetString(CreateVMModuleKey),
+		Dir:                os.ExpandEnv(v.GetString(CreateVMDirKey)),
+		AvalancheGoVersion: scaffold.CurrentAvalancheGoVersion(),
+		AvalancheGoDir:     os.ExpandEnv(v.GetString(CreateVMAvalancheGoDirKey)),
+	}
+	if config.Module == "" {
+		config.Module = config.Name
+	}
+	if config.Dir == "" {
+		config.Dir = config.Name
+	}
+	if config.AvalancheGoDir != "" {
+		// The module is in a different directory than the working directory,
+		// so a relative path would point somewhere else
+		config.AvalancheGoDir, err = filepath.Abs(config.AvalancheGoDir)
+		if err != nil {
+			return scaffold.Config{}, fmt.Errorf("couldn't resolve %s: %w", CreateVMAvalancheGoDirKey, err)
+		}
+	}
+	return config, config.Verify()
+}
+
+// GetReloadableConfig returns the part of the node's config that can be
+// reloaded while the node is running
+func GetReloadableConfig(v *viper.Viper) (node.ReloadableConfig, error) {
+	config := node.ReloadableConfig{}
+
+	var err error
+	config.LogLevel, err = logging.ToLevel(v.GetString(LogLevelKey))
+	if err != nil {
+		return node.ReloadableConfig{}, err
+	}
+	logDisplayLevel := v.GetString(LogLevelKey)
+	if v.IsSet(LogDisplayLevelKey) {
+		logDisplayLevel = v.GetString(LogDisplayLevelKey)
+	}
+	config.LogDisplayLevel, err = logging.ToLevel(logDisplayLevel)
+	if err != nil {
+		return node.ReloadableConfig{}, err
+	}
+
+	config.HTTPRateLimitRPS = v.GetFloat64(HTTPRateLimitRPSKey)
+	if config.HTTPRateLimitRPS < 0 {
+		return node.ReloadableConfig{}, fmt.Errorf("%s can't be negative", HTTPRateLimitRPSKey)
+	}
+	config.HTTPRateLimitBurst = int(v.GetUint(HTTPRateLimitBurstKey))
+	if config.HTTPRateLimitRPS > 0 && config.HTTPRateLimitBurst == 0 {
+		return node.ReloadableConfig{}, fmt.Errorf("%s must be positive when %s is set", HTTPRateLimitBurstKey, HTTPRateLimitRPSKey)
+	}
+
+	config.OutboundConnectionThrottlingRps = v.GetUint32(OutboundConnectionThrottlingRps)
+
+	config.PeerListGossipSize = v.GetUint32(NetworkPeerListGossipSizeKey)
+	config.ConsensusGossipAcceptedFrontierSize = uint(v.GetUint32(ConsensusGossipAcceptedFrontierSizeKey))
+	config.ConsensusGossipOnAcceptSize = uint(v.GetUint32(ConsensusGossipOnAcceptSizeKey))
+	return config, nil
+}
+
 func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	// TODO Divide this function into smaller parts (see getChainConfigs) for efficient testing
 	// First, get the process config
@@ -110,7 +298,15 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	// Plugin directory defaults to [buildDirectory]/avalanchego-latest/plugins
 	nodeConfig.PluginDir = filepath.Join(buildDir, avalanchegoLatest, "plugins")
 
+	nodeConfig.Settings = RedactedSettings(v)
+
+	// The part of the config that can be reloaded while the node is running
+	reloadableConfig, err := GetReloadableConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
 	nodeConfig.FetchOnly = v.GetBool(FetchOnlyKey)
+	nodeConfig.ArchivalMode = v.GetBool(ArchivalModeKey)
 
 	// Consensus Parameters
 	nodeConfig.ConsensusParams.K = v.GetInt(SnowSampleSizeKey)
@@ -123,10 +319,23 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.ConsensusParams.OptimalProcessing = v.GetInt(SnowOptimalProcessingKey)
 	nodeConfig.ConsensusParams.MaxOutstandingItems = v.GetInt(SnowMaxProcessingKey)
 	nodeConfig.ConsensusParams.MaxItemProcessingTime = v.GetDuration(SnowMaxTimeProcessingKey)
+	nodeConfig.DeterministicSampling = v.GetBool(SnowDeterministicSamplingKey)
+	nodeConfig.AuditLogDir = os.ExpandEnv(v.GetString(SnowAuditLogDirKey))
+	nodeConfig.MaxIPPrefixSampleFraction = v.GetFloat64(SnowMaxIPPrefixSampleFractionKey)
+	if nodeConfig.MaxIPPrefixSampleFraction < 0 || nodeConfig.MaxIPPrefixSampleFraction > 1 {
+		return node.Config{}, fmt.Errorf("%s must be in [0, 1]", SnowMaxIPPrefixSampleFractionKey)
+	}
+	if asnFile := v.GetString(SnowASNPrefixFileKey); asnFile != "" {
+		nodeConfig.ASNTable, err = network.LoadASNTable(os.ExpandEnv(asnFile))
+		if err != nil {
+			return node.Config{}, fmt.Errorf("couldn't load %s: %w", SnowASNPrefixFileKey, err)
+		}
+	}
 	nodeConfig.ConsensusGossipFrequency = v.GetDuration(ConsensusGossipFrequencyKey)
 	nodeConfig.ConsensusShutdownTimeout = v.GetDuration(ConsensusShutdownTimeoutKey)
-	nodeConfig.ConsensusGossipAcceptedFrontierSize = uint(v.GetUint32(ConsensusGossipAcceptedFrontierSizeKey))
-	nodeConfig.ConsensusGossipOnAcceptSize = uint(v.GetUint32(ConsensusGossipOnAcceptSizeKey))
+	nodeConfig.ConsensusGossipAcceptedFrontierSize = reloadableConfig.ConsensusGossipAcceptedFrontierSize
+	nodeConfig.ConsensusGossipOnAcceptSize = reloadableConfig.ConsensusGossipOnAcceptSize
+	nodeConfig.ConsensusPushAcceptedFrontierSize = uint(v.GetUint32(ConsensusPushAcceptedFrontierSizeKey))
 
 	// Logging:
 	loggingConfig, err := logging.DefaultConfig()
@@ -136,21 +345,43 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	if v.IsSet(LogsDirKey) {
 		loggingConfig.Directory = os.ExpandEnv(v.GetString(LogsDirKey))
 	}
-	loggingConfig.LogLevel, err = logging.ToLevel(v.GetString(LogLevelKey))
+	loggingConfig.LogLevel = reloadableConfig.LogLevel
+	loggingConfig.DisplayLevel = reloadableConfig.LogDisplayLevel
+
+	loggingConfig.DisplayHighlight, err = logging.ToHighlight(v.GetString(LogDisplayHighlightKey), os.Stdout.Fd())
 	if err != nil {
 		return node.Config{}, err
 	}
-	logDisplayLevel := v.GetString(LogLevelKey)
-	if v.IsSet(LogDisplayLevelKey) {
-		logDisplayLevel = v.GetString(LogDisplayLevelKey)
+
+	loggingConfig.LogFormat, err = logging.ToFormat(v.GetString(LogFormatKey))
+	if err != nil {
+		return node.Config{}, err
 	}
-	displayLevel, err := logging.ToLevel(logDisplayLevel)
+	logDisplayFormat := v.GetString(LogFormatKey)
+	if v.IsSet(LogDisplayFormatKey) {
+		logDisplayFormat = v.GetString(LogDisplayFormatKey)
+	}
+	loggingConfig.DisplayFormat, err = logging.ToFormat(logDisplayFormat)
 	if err != nil {
 		return node.Config{}, err
 	}
-	loggingConfig.DisplayLevel = displayLevel
 
-	loggingConfig.DisplayHighlight, err = logging.ToHighlight(v.GetString(LogDisplayHighlightKey), os.Stdout.Fd())
+	loggingConfig.RotationInterval = v.GetDuration(LogRotationIntervalKey)
+	loggingConfig.FileSize = v.GetInt(LogRotationFileSizeKey)
+	loggingConfig.RotationSize = v.GetInt(LogRotationFilesKey)
+	loggingConfig.MaxAge = v.GetDuration(LogRotationMaxAgeKey)
+	loggingConfig.Compress = v.GetBool(LogRotationCompressKey)
+	switch {
+	case loggingConfig.RotationInterval <= 0:
+		return node.Config{}, fmt.Errorf("%s must be positive", LogRotationIntervalKey)
+	case loggingConfig.FileSize <= 0:
+		return node.Config{}, fmt.Errorf("%s must be positive", LogRotationFileSizeKey)
+	case loggingConfig.RotationSize < 0:
+		return node.Config{}, fmt.Errorf("%s can't be negative", LogRotationFilesKey)
+	case loggingConfig.MaxAge < 0:
+		return node.Config{}, fmt.Errorf("%s can't be negative", LogRotationMaxAgeKey)
+	}
+	loggingConfig.ChainRotations, err = getChainLogRotations(v, loggingConfig)
 	if err != nil {
 		return node.Config{}, err
 	}
@@ -170,6 +401,100 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		os.ExpandEnv(v.GetString(DBPathKey)),
 		constants.NetworkName(nodeConfig.NetworkID),
 	)
+	nodeConfig.DBType = v.GetString(DBTypeKey)
+	if nodeConfig.DBType == manager.RemoteDB {
+		numConns := int(v.GetUint(DBRemoteConnsKey))
+		if numConns == 0 {
+			return node.Config{}, fmt.Errorf("%s must be positive", DBRemoteConnsKey)
+		}
+		security, err := getDBRemoteSecurity(v)
+		if err != nil {
+			return node.Config{}, err
+		}
+		nodeConfig.DBBackend = manager.NewRemoteBackend(
+			v.GetString(DBRemoteAddressKey),
+			security,
+			numConns,
+			int(v.GetUint(DBRemoteRetriesKey)),
+		)
+	} else {
+		nodeConfig.DBBackend, err = manager.GetBackend(nodeConfig.DBType)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("couldn't parse %s: %w", DBTypeKey, err)
+		}
+	}
+	// Databases of other backends are kept apart so that a database is never
+	// opened with a backend other than the one that created it
+	if nodeConfig.DBType != manager.LevelDB {
+		nodeConfig.DBPath = filepath.Join(nodeConfig.DBPath, nodeConfig.DBType)
+	}
+	if walPath := v.GetString(DBWALPathKey); walPath != "" {
+		nodeConfig.DBWALPath = filepath.Join(
+			os.ExpandEnv(walPath),
+			constants.NetworkName(nodeConfig.NetworkID),
+		)
+		if nodeConfig.DBType != manager.LevelDB {
+			nodeConfig.DBWALPath = filepath.Join(nodeConfig.DBWALPath, nodeConfig.DBType)
+		}
+	}
+	if keySource := v.GetString(DBEncryptionKeyKey); keySource != "" {
+		nodeConfig.DBEncryptionKey, err = cryptdb.LoadKey(keySource)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("couldn't load %s: %w", DBEncryptionKeyKey, err)
+		}
+	}
+	if keySource := v.GetString(DBEncryptionPreviousKeyKey); keySource != "" {
+		if nodeConfig.DBEncryptionKey == nil {
+			return node.Config{}, fmt.Errorf("%s requires %s", DBEncryptionPreviousKeyKey, DBEncryptionKeyKey)
+		}
+		nodeConfig.DBEncryptionPreviousKey, err = cryptdb.LoadKey(keySource)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("couldn't load %s: %w", DBEncryptionPreviousKeyKey, err)
+		}
+	}
+	if target := os.ExpandEnv(v.GetString(DBBackupTargetKey)); target != "" {
+		nodeConfig.DBBackupStore, err = backup.NewStore(target)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("couldn't parse %s: %w", DBBackupTargetKey, err)
+		}
+	}
+	nodeConfig.DBRestoreBackup = v.GetString(DBRestoreBackupKey)
+	if nodeConfig.DBRestoreBackup != "" && nodeConfig.DBBackupStore == nil {
+		return node.Config{}, fmt.Errorf("%s requires %s", DBRestoreBackupKey, DBBackupTargetKey)
+	}
+	if nodeConfig.ArchivalMode {
+		// Each of these would write to the database
+		switch {
+		case !nodeConfig.DBEnabled:
+			return node.Config{}, fmt.Errorf("%s requires %s", ArchivalModeKey, DBEnabledKey)
+		case nodeConfig.FetchOnly:
+			return node.Config{}, fmt.Errorf("%s can't be used with %s", ArchivalModeKey, FetchOnlyKey)
+		case nodeConfig.DBEncryptionPreviousKey != nil:
+			return node.Config{}, fmt.Errorf("%s can't be used with %s", ArchivalModeKey, DBEncryptionPreviousKeyKey)
+		case nodeConfig.DBRestoreBackup != "":
+			return node.Config{}, fmt.Errorf("%s can't be used with %s", ArchivalModeKey, DBRestoreBackupKey)
+		}
+	}
+	nodeConfig.DBVerify = v.GetBool(DBVerifyKey)
+	nodeConfig.DBCacheSize = int(v.GetUint(DBCacheSizeKey))
+	nodeConfig.DBCacheChainQuota = v.GetFloat64(DBCacheChainQuotaKey)
+	if nodeConfig.DBCacheChainQuota <= 0 || nodeConfig.DBCacheChainQuota > 1 {
+		return node.Config{}, fmt.Errorf("%s must be in (0, 1]", DBCacheChainQuotaKey)
+	}
+	nodeConfig.DBCompactionConfig.Freq = v.GetDuration(DBCompactionFreqKey)
+	if nodeConfig.DBCompactionConfig.Freq < 0 {
+		return node.Config{}, fmt.Errorf("%s can't be negative", DBCompactionFreqKey)
+	}
+	nodeConfig.DBCompactionConfig.Window, err = compaction.ParseWindow(v.GetString(DBCompactionWindowKey))
+	if err != nil {
+		return node.Config{}, fmt.Errorf("couldn't parse %s: %w", DBCompactionWindowKey, err)
+	}
+
+	// Outbound connection proxy
+	nodeConfig.ProxyConfig, err = getProxyConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
 
 	// IP configuration
 	// Resolves our public IP, or does nothing
@@ -178,6 +503,11 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	var ip net.IP
 	publicIP := v.GetString(PublicIPKey)
 	switch {
+	case nodeConfig.ProxyConfig.Enabled() && publicIP == "":
+		// Peers are dialed through the proxy to hide this node's IP, so it
+		// isn't discovered or advertised. Peers ignore the unspecified IP.
+		nodeConfig.Nat = nat.NewNoRouter()
+		ip = net.IPv4zero
 	case nodeConfig.DynamicPublicIPResolver.IsResolver():
 		// User specified to use dynamic IP resolution; don't use NAT traversal
 		nodeConfig.Nat = nat.NewNoRouter()
@@ -221,6 +551,14 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	if !nodeConfig.EnableStaking && nodeConfig.DisabledStakingWeight == 0 {
 		return node.Config{}, errInvalidStakerWeights
 	}
+	nodeConfig.ValidatorChurnLimit = v.GetFloat64(ValidatorChurnLimitKey)
+	nodeConfig.ValidatorChurnPeriod = v.GetDuration(ValidatorChurnPeriodKey)
+	switch {
+	case nodeConfig.ValidatorChurnLimit < 0:
+		return node.Config{}, fmt.Errorf("%s can't be negative", ValidatorChurnLimitKey)
+	case nodeConfig.ValidatorChurnLimit > 0 && nodeConfig.ValidatorChurnPeriod <= 0:
+		return node.Config{}, fmt.Errorf("%s must be positive", ValidatorChurnPeriodKey)
+	}
 
 	if nodeConfig.FetchOnly || v.GetBool(StakingEphemeralCertEnabledKey) {
 		// In fetch only mode or if explicitly set, use an ephemeral staking key/cert
@@ -229,6 +567,20 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 			return node.Config{}, fmt.Errorf("couldn't generate ephemeral staking key/cert: %w", err)
 		}
 		nodeConfig.StakingTLSCert = *cert
+		nodeConfig.StakingBLSKey, err = bls.NewSecretKey()
+		if err != nil {
+			return node.Config{}, fmt.Errorf("couldn't generate ephemeral BLS key: %w", err)
+		}
+	} else if signerPath := v.GetString(StakingSignerPluginKey); signerPath != "" {
+		// The staking key is held by the signer plugin, which is launched by
+		// the node
+		nodeConfig.StakingSignerPluginPath = os.ExpandEnv(signerPath)
+		stakingCertPath := os.ExpandEnv(v.GetString(StakingCertPathKey))
+		cert, err := staking.LoadTLSCertWithoutKey(stakingCertPath)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("problem reading staking certificate: %w", err)
+		}
+		nodeConfig.StakingTLSCert = *cert
 	} else {
 		// Parse the staking key/cert paths
 		stakingKeyPath := os.ExpandEnv(v.GetString(StakingKeyPathKey))
@@ -256,6 +608,25 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		}
 		nodeConfig.StakingTLSCert = *cert
 	}
+	if nodeConfig.StakingBLSKey == nil {
+		// The BLS key is registered on the platform chain, so it is kept
+		// across restarts like the staking key
+		blsKeyPath := os.ExpandEnv(v.GetString(StakingBLSKeyPathKey))
+		if err := staking.InitNodeBLSKey(blsKeyPath); err != nil {
+			return node.Config{}, fmt.Errorf("couldn't generate BLS key: %w", err)
+		}
+		nodeConfig.StakingBLSKey, err = staking.LoadBLSKey(blsKeyPath)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("problem reading BLS key: %w", err)
+		}
+	}
+
+	// Attribute the node's JSON log lines to its node ID
+	nodeID, err := ids.ToShortID(hashing.PubkeyBytesToAddress(nodeConfig.StakingTLSCert.Leaf.Raw))
+	if err != nil {
+		return node.Config{}, fmt.Errorf("problem deriving node ID from certificate: %w", err)
+	}
+	nodeConfig.LoggingConfig.NodeID = address.FormatNodeID(nodeID)
 
 	if err := initBootstrapPeers(v, &nodeConfig); err != nil {
 		return node.Config{}, err
@@ -264,14 +635,27 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.WhitelistedSubnets.Add(constants.PrimaryNetworkID)
 	for _, subnet := range strings.Split(v.GetString(WhitelistedSubnetsKey), ",") {
 		if subnet != "" {
-			subnetID, err := ids.FromString(subnet)
+			subnetID, err := address.ParseID(subnet)
 			if err != nil {
-				return node.Config{}, fmt.Errorf("couldn't parse subnetID %s: %w", subnet, err)
+				return node.Config{}, fmt.Errorf("couldn't parse subnetID: %w", err)
 			}
 			nodeConfig.WhitelistedSubnets.Add(subnetID)
 		}
 	}
 
+	nodeConfig.SubnetSamplingCaps, err = getSubnetSamplingCaps(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+	nodeConfig.VMAliases, err = getVMAliases(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+	nodeConfig.DistinctSamplingMaxValidators = v.GetInt(DistinctSamplingMaxValidatorsKey)
+	if nodeConfig.DistinctSamplingMaxValidators < 0 {
+		return node.Config{}, fmt.Errorf("%s can't be negative", DistinctSamplingMaxValidatorsKey)
+	}
+
 	// HTTP:
 	nodeConfig.HTTPHost = v.GetString(HTTPHostKey)
 	nodeConfig.HTTPPort = uint16(v.GetUint(HTTPPortKey))
@@ -279,6 +663,28 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.HTTPSKeyFile = os.ExpandEnv(v.GetString(HTTPSKeyFileKey))
 	nodeConfig.HTTPSCertFile = os.ExpandEnv(v.GetString(HTTPSCertFileKey))
 	nodeConfig.APIAllowedOrigins = v.GetStringSlice(HTTPAllowedOrigins)
+	nodeConfig.GRPCPort = uint16(v.GetUint(GRPCPortKey))
+	nodeConfig.HTTPRateLimitRPS = reloadableConfig.HTTPRateLimitRPS
+	nodeConfig.HTTPRateLimitBurst = reloadableConfig.HTTPRateLimitBurst
+	nodeConfig.HTTPMaxRequestBodySize = int64(v.GetUint64(HTTPMaxRequestBodySizeKey))
+	nodeConfig.HTTPMaxBatchSize = int(v.GetUint(HTTPMaxBatchSizeKey))
+	for _, proxy := range v.GetStringSlice(HTTPTrustedProxiesKey) {
+		trustedProxy, err := server.ParseTrustedProxy(proxy)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("couldn't parse %s: %w", HTTPTrustedProxiesKey, err)
+		}
+		nodeConfig.HTTPTrustedProxies = append(nodeConfig.HTTPTrustedProxies, trustedProxy)
+	}
+	for _, listener := range v.GetStringSlice(HTTPAdditionalListenersKey) {
+		apiListener, err := server.ParseListener(listener)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("couldn't parse %s: %w", HTTPAdditionalListenersKey, err)
+		}
+		if apiListener.TLS && (nodeConfig.HTTPSCertFile == "" || nodeConfig.HTTPSKeyFile == "") {
+			return node.Config{}, fmt.Errorf("%s requires %s and %s to serve %s", HTTPAdditionalListenersKey, HTTPSCertFileKey, HTTPSKeyFileKey, apiListener)
+		}
+		nodeConfig.HTTPAdditionalListeners = append(nodeConfig.HTTPAdditionalListeners, apiListener)
+	}
 
 	// API Auth
 	nodeConfig.APIRequireAuthToken = v.GetBool(APIAuthRequiredKey)
@@ -296,12 +702,21 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 
 	// APIs
 	nodeConfig.AdminAPIEnabled = v.GetBool(AdminAPIEnabledKey)
+	nodeConfig.DebugAPIEnabled = v.GetBool(DebugAPIEnabledKey)
+	nodeConfig.AuditAPIEnabled = v.GetBool(AuditAPIEnabledKey)
 	nodeConfig.InfoAPIEnabled = v.GetBool(InfoAPIEnabledKey)
 	nodeConfig.KeystoreAPIEnabled = v.GetBool(KeystoreAPIEnabledKey)
 	nodeConfig.MetricsAPIEnabled = v.GetBool(MetricsAPIEnabledKey)
+	nodeConfig.MetricsAPIExemplarsEnabled = v.GetBool(MetricsAPIExemplarsEnabledKey)
 	nodeConfig.HealthAPIEnabled = v.GetBool(HealthAPIEnabledKey)
 	nodeConfig.IPCAPIEnabled = v.GetBool(IpcAPIEnabledKey)
 	nodeConfig.IndexAPIEnabled = v.GetBool(IndexEnabledKey)
+	nodeConfig.EventsAPIEnabled = v.GetBool(EventsAPIEnabledKey)
+	nodeConfig.GraphQLAPIEnabled = v.GetBool(GraphQLAPIEnabledKey)
+	nodeConfig.GRPCAPIEnabled = v.GetBool(GRPCAPIEnabledKey)
+	if nodeConfig.GRPCAPIEnabled && nodeConfig.APIRequireAuthToken {
+		return node.Config{}, fmt.Errorf("%s doesn't support %s", GRPCAPIEnabledKey, APIAuthRequiredKey)
+	}
 
 	// Halflife of continuous averager used in health checks
 	healthCheckAveragerHalflife := v.GetDuration(HealthCheckAveragerHalflifeKey)
@@ -323,6 +738,30 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, fmt.Errorf("%s must be positive", NetworkHealthMaxOutstandingDurationKey)
 	}
 
+	// Consensus engines
+	nodeConfig.ConsensusHealthConfig.MaxPollFailureRate = v.GetFloat64(ConsensusHealthMaxPollFailureRateKey)
+	nodeConfig.ConsensusHealthConfig.PollFailureRateHalflife = healthCheckAveragerHalflife
+	if nodeConfig.ConsensusHealthConfig.MaxPollFailureRate < 0 || nodeConfig.ConsensusHealthConfig.MaxPollFailureRate > 1 {
+		return node.Config{}, fmt.Errorf("%s must be in [0,1]", ConsensusHealthMaxPollFailureRateKey)
+	}
+
+	// Watchdog
+	if rulesStr := v.GetString(WatchdogRulesKey); rulesStr != "" {
+		nodeConfig.WatchdogConfig.Rules, err = watchdog.ParseRules([]byte(rulesStr))
+		if err != nil {
+			return node.Config{}, fmt.Errorf("couldn't parse %s: %w", WatchdogRulesKey, err)
+		}
+	}
+	nodeConfig.WatchdogConfig.WebhookURL = v.GetString(WatchdogWebhookURLKey)
+	nodeConfig.WatchdogConfig.ExecCommand = v.GetString(WatchdogExecCommandKey)
+	nodeConfig.WatchdogConfig.CheckFreq = v.GetDuration(WatchdogCheckFreqKey)
+	switch {
+	case len(nodeConfig.WatchdogConfig.Rules) > 0 && nodeConfig.WatchdogConfig.WebhookURL == "" && nodeConfig.WatchdogConfig.ExecCommand == "":
+		return node.Config{}, fmt.Errorf("%s requires %s or %s", WatchdogRulesKey, WatchdogWebhookURLKey, WatchdogExecCommandKey)
+	case nodeConfig.WatchdogConfig.CheckFreq <= 0:
+		return node.Config{}, fmt.Errorf("%s must be positive", WatchdogCheckFreqKey)
+	}
+
 	// IPCs
 	if v.IsSet(IpcsChainIDsKey) {
 		nodeConfig.IPCDefaultChainIDs = strings.Split(v.GetString(IpcsChainIDsKey), ",")
@@ -392,12 +831,19 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	// [PeerListGossipFreq]
 	nodeConfig.PeerListSize = v.GetUint32(NetworkPeerListSizeKey)
 	nodeConfig.PeerListGossipFreq = v.GetDuration(NetworkPeerListGossipFreqKey)
-	nodeConfig.PeerListGossipSize = v.GetUint32(NetworkPeerListGossipSizeKey)
+	nodeConfig.PeerListGossipSize = reloadableConfig.PeerListGossipSize
+
+	// Optional protocol features advertised during the handshake
+	nodeConfig.NetworkCapabilities, err = network.ParseCapabilities(v.GetString(NetworkCapabilitiesKey))
+	if err != nil {
+		return node.Config{}, fmt.Errorf("couldn't parse %s: %w", NetworkCapabilitiesKey, err)
+	}
 
 	// Outbound connection throttling
 	nodeConfig.DialerConfig = network.NewDialerConfig(
-		v.GetUint32(OutboundConnectionThrottlingRps),
+		reloadableConfig.OutboundConnectionThrottlingRps,
 		v.GetDuration(OutboundConnectionTimeout),
+		nodeConfig.ProxyConfig,
 	)
 
 	// Benchlist
@@ -406,6 +852,14 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.BenchlistConfig.Duration = v.GetDuration(BenchlistDurationKey)
 	nodeConfig.BenchlistConfig.MinimumFailingDuration = v.GetDuration(BenchlistMinFailingDurationKey)
 	nodeConfig.BenchlistConfig.MaxPortion = (1.0 - (float64(nodeConfig.ConsensusParams.Alpha) / float64(nodeConfig.ConsensusParams.K))) / 3.0
+	nodeConfig.BenchlistConfig.MaxSamplingPenalty = v.GetFloat64(BenchlistMaxSamplingPenaltyKey)
+	nodeConfig.BenchlistConfig.SamplingPenaltyThreshold = v.GetFloat64(BenchlistSamplingPenaltyThresholdKey)
+	switch {
+	case nodeConfig.BenchlistConfig.MaxSamplingPenalty < 0 || nodeConfig.BenchlistConfig.MaxSamplingPenalty >= 1:
+		return node.Config{}, fmt.Errorf("%s must be in [0, 1)", BenchlistMaxSamplingPenaltyKey)
+	case nodeConfig.BenchlistConfig.SamplingPenaltyThreshold < 0 || nodeConfig.BenchlistConfig.SamplingPenaltyThreshold > 1:
+		return node.Config{}, fmt.Errorf("%s must be in [0, 1]", BenchlistSamplingPenaltyThresholdKey)
+	}
 
 	if nodeConfig.ConsensusGossipFrequency < 0 {
 		return node.Config{}, errors.New("gossip frequency can't be negative")
@@ -479,6 +933,30 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 
 	// Indexer
 	nodeConfig.IndexAllowIncomplete = v.GetBool(IndexAllowIncompleteKey)
+	for _, chain := range strings.Split(v.GetString(IndexChainsKey), ",") {
+		if chain != "" {
+			chainID, err := address.ParseID(chain)
+			if err != nil {
+				return node.Config{}, fmt.Errorf("couldn't parse chainID: %w", err)
+			}
+			nodeConfig.IndexedChains.Add(chainID)
+		}
+	}
+	nodeConfig.IndexPruningConfig.Freq = v.GetDuration(IndexPruningFreqKey)
+	nodeConfig.IndexPruningConfig.RetainHeight = v.GetUint64(IndexPruningRetainHeightKey)
+	nodeConfig.IndexPruningConfig.RetainAge = v.GetDuration(IndexPruningRetainAgeKey)
+	switch {
+	case nodeConfig.IndexPruningConfig.Freq < 0:
+		return node.Config{}, fmt.Errorf("%s can't be negative", IndexPruningFreqKey)
+	case nodeConfig.IndexPruningConfig.RetainAge < 0:
+		return node.Config{}, fmt.Errorf("%s can't be negative", IndexPruningRetainAgeKey)
+	case !nodeConfig.IndexPruningConfig.Enabled():
+	case nodeConfig.IndexPruningConfig.RetainHeight == 0 && nodeConfig.IndexPruningConfig.RetainAge == 0:
+		// Every container but the last accepted one would be pruned
+		return node.Config{}, fmt.Errorf("%s requires %s or %s", IndexPruningFreqKey, IndexPruningRetainHeightKey, IndexPruningRetainAgeKey)
+	case nodeConfig.ArchivalMode:
+		return node.Config{}, fmt.Errorf("%s can't be used with %s", IndexPruningFreqKey, ArchivalModeKey)
+	}
 
 	// Bootstrap Configs
 	nodeConfig.RetryBootstrap = v.GetBool(RetryBootstrapKey)
@@ -498,11 +976,36 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	}
 	nodeConfig.ChainConfigs = chainConfigs
 
+	// VM Configs
+	vmConfigs, err := getVMConfigs(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+	nodeConfig.VMConfigs = vmConfigs
+
+	// Chain snapshots
+	nodeConfig.SnapshotDir = os.ExpandEnv(v.GetString(SnapshotDirKey))
+
+	// Plugin resource limits
+	nodeConfig.PluginLimits = sandbox.Limits{
+		CPUShares:       v.GetUint64(PluginCPUSharesKey),
+		MemoryBytes:     v.GetUint64(PluginMemoryLimitKey),
+		FileDescriptors: v.GetUint64(PluginFDLimitKey),
+		CgroupDir:       os.ExpandEnv(v.GetString(PluginCgroupDirKey)),
+	}
+	if err := nodeConfig.PluginLimits.Verify(); err != nil {
+		return node.Config{}, fmt.Errorf("invalid plugin resource limits: %w", err)
+	}
+
 	// Profile config
 	nodeConfig.ProfilerConfig.Dir = os.ExpandEnv(v.GetString(ProfileDirKey))
 	nodeConfig.ProfilerConfig.Enabled = v.GetBool(ProfileContinuousEnabledKey)
 	nodeConfig.ProfilerConfig.Freq = v.GetDuration(ProfileContinuousFreqKey)
 	nodeConfig.ProfilerConfig.MaxNumFiles = v.GetInt(ProfileContinuousMaxFilesKey)
+	nodeConfig.ProfilerConfig.UploadURL = os.ExpandEnv(v.GetString(ProfileContinuousUploadURLKey))
+	if nodeConfig.ProfilerConfig.UploadURL != "" && !nodeConfig.ProfilerConfig.Enabled {
+		return node.Config{}, fmt.Errorf("%s requires %s", ProfileContinuousUploadURLKey, ProfileContinuousEnabledKey)
+	}
 
 	return nodeConfig, nil
 }
@@ -556,6 +1059,152 @@ func getChainConfigs(v *viper.Viper) (map[string]chains.ChainConfig, error) {
 	return chainConfigs, nil
 }
 
+// getVMConfigs reads the configs of the chains of each VM
+func getVMConfigs(v *viper.Viper) (map[string]chains.ChainConfig, error) {
+	vmsPath := path.Clean(os.ExpandEnv(v.GetString(VMConfigDirKey)))
+	// user specified a VM config dir explicitly, but dir does not exist.
+	if v.IsSet(VMConfigDirKey) {
+		info, err := os.Stat(vmsPath)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("not a directory: %v", vmsPath)
+		}
+	}
+	// gets direct subdirs
+	vmDirs, err := filepath.Glob(path.Join(vmsPath, "*"))
+	if err != nil {
+		return nil, err
+	}
+	vmConfigs, err := readChainConfigDirs(vmDirs)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read VM configs: %w", err)
+	}
+	for vm, vmConfig := range vmConfigs {
+		if len(vmConfig.Upgrade) != 0 {
+			return nil, fmt.Errorf("config of VM %s has an %s file, but upgrades can only be configured for each chain", vm, chainUpgradeFileName)
+		}
+	}
+	return vmConfigs, nil
+}
+
+// getSubnetSamplingCaps returns the caps applied when sampling the validators
+// of each subnet
+func getSubnetSamplingCaps(v *viper.Viper) (map[ids.ID]validators.SamplingCaps, error) {
+	subnetSamplingCaps := make(map[ids.ID]validators.SamplingCaps)
+	capsStr := v.GetString(SubnetSamplingCapsKey)
+	if capsStr == "" {
+		return subnetSamplingCaps, nil
+	}
+
+	capsMap := make(map[string]validators.SamplingCaps)
+	if err := json.Unmarshal([]byte(capsStr), &capsMap); err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", SubnetSamplingCapsKey, err)
+	}
+	for subnet, caps := range capsMap {
+		subnetID, err := address.ParseID(subnet)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse subnetID: %w", err)
+		}
+		if err := caps.Verify(); err != nil {
+			return nil, fmt.Errorf("invalid sampling caps for subnet %s: %w", subnet, err)
+		}
+		subnetSamplingCaps[subnetID] = caps
+	}
+	return subnetSamplingCaps, nil
+}
+
+// getChainLogRotations returns the overrides of how the logs of chains are
+// rotated, by the aliases of the chains
+func getChainLogRotations(v *viper.Viper, loggingConfig logging.Config) (map[string]logging.RotationOverride, error) {
+	rotations := make(map[string]logging.RotationOverride)
+	rotationsStr := v.GetString(LogChainRotationKey)
+	if rotationsStr == "" {
+		return rotations, nil
+	}
+
+	if err := json.Unmarshal([]byte(rotationsStr), &rotations); err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", LogChainRotationKey, err)
+	}
+	for chain, rotation := range rotations {
+		// Apply the override to a copy, so that invalid overrides are reported
+		// now rather than when the chain is created
+		chainConfig := loggingConfig
+		if err := rotation.Apply(&chainConfig); err != nil {
+			return nil, fmt.Errorf("invalid log rotation of chain %s: %w", chain, err)
+		}
+	}
+	return rotations, nil
+}
+
+// getVMAliases returns the aliases given to VMs, by the IDs of the VMs
+func getVMAliases(v *viper.Viper) (map[ids.ID][]string, error) {
+	vmAliases := make(map[ids.ID][]string)
+	aliasesStr := v.GetString(VMAliasesKey)
+	if aliasesStr == "" {
+		return vmAliases, nil
+	}
+
+	aliasesMap := make(map[string][]string)
+	if err := json.Unmarshal([]byte(aliasesStr), &aliasesMap); err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", VMAliasesKey, err)
+	}
+	for vm, aliases := range aliasesMap {
+		vmID, err := address.ParseID(vm)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse vmID: %w", err)
+		}
+		vmAliases[vmID] = aliases
+	}
+	return vmAliases, nil
+}
+
+// getProxyConfig returns the SOCKS5 proxy that outbound peer connections are
+// made through. The proxy only hides this node's IP if nothing else reaches
+// the node directly, so when it's enabled the APIs must only be served on
+// loopback addresses, and the node's public IP isn't resolved dynamically.
+func getProxyConfig(v *viper.Viper) (network.ProxyConfig, error) {
+	proxyConfig := network.ProxyConfig{
+		Address: v.GetString(OutboundProxyAddressKey),
+	}
+	if !proxyConfig.Enabled() {
+		return proxyConfig, nil
+	}
+	if _, _, err := net.SplitHostPort(proxyConfig.Address); err != nil {
+		return network.ProxyConfig{}, fmt.Errorf("invalid %s: %w", OutboundProxyAddressKey, err)
+	}
+	proxyConfig.Username = v.GetString(OutboundProxyUsernameKey)
+	proxyConfig.Password = v.GetString(OutboundProxyPasswordKey)
+
+	if v.GetString(DynamicPublicIPResolverKey) != "" {
+		return network.ProxyConfig{}, fmt.Errorf("%s can't be used with %s", DynamicPublicIPResolverKey, OutboundProxyAddressKey)
+	}
+	if host := v.GetString(HTTPHostKey); !isLoopbackHost(host) {
+		return network.ProxyConfig{}, fmt.Errorf("%s must be a loopback address when %s is set, but is %q", HTTPHostKey, OutboundProxyAddressKey, host)
+	}
+	for _, listener := range v.GetStringSlice(HTTPAdditionalListenersKey) {
+		apiListener, err := server.ParseListener(listener)
+		if err != nil {
+			return network.ProxyConfig{}, fmt.Errorf("couldn't parse %s: %w", HTTPAdditionalListenersKey, err)
+		}
+		host, _, err := net.SplitHostPort(apiListener.Address)
+		if err != nil || !isLoopbackHost(host) {
+			return network.ProxyConfig{}, fmt.Errorf("%s must only listen on loopback addresses when %s is set, but has %s", HTTPAdditionalListenersKey, OutboundProxyAddressKey, apiListener)
+		}
+	}
+	return proxyConfig, nil
+}
+
+// isLoopbackHost returns true if [host] only resolves to this machine
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 // Initialize config.BootstrapPeers.
 func initBootstrapPeers(v *viper.Viper, config *node.Config) error {
 	bootstrapIPs, bootstrapIDs := genesis.SampleBeacons(config.NetworkID, 5)
@@ -580,7 +1229,7 @@ func initBootstrapPeers(v *viper.Viper, config *node.Config) error {
 		if id == "" {
 			continue
 		}
-		nodeID, err := ids.ShortFromPrefixedString(id, constants.NodeIDPrefix)
+		nodeID, err := address.ParseNodeID(id)
 		if err != nil {
 			return fmt.Errorf("couldn't parse bootstrap peer id: %w", err)
 		}
@@ -615,9 +1264,20 @@ func readChainConfigDirs(chainDirs []string) (map[string]chains.ChainConfig, err
 			return chainConfigMap, err
 		}
 
+		// chainconfigdir/chainId/node.*
+		nodeData, err := readSingleFile(chainDir, chainNodeFileName)
+		if err != nil {
+			return chainConfigMap, err
+		}
+		overrides, err := chains.ParseChainOverrides(nodeData)
+		if err != nil {
+			return chainConfigMap, fmt.Errorf("couldn't parse %s file in %s: %w", chainNodeFileName, chainDir, err)
+		}
+
 		chainConfigMap[dirInfo.Name()] = chains.ChainConfig{
-			Config:  configData,
-			Upgrade: upgradeData,
+			Config:    configData,
+			Upgrade:   upgradeData,
+			Overrides: overrides,
 		}
 	}
 
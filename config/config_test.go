@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/pflag"
@@ -18,7 +19,9 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
 func TestSetChainConfigs(t *testing.T) {
@@ -237,6 +240,101 @@ func TestSetChainConfigDefaultDir(t *testing.T) {
 	assert.Equal(expected, chainConfigs)
 }
 
+func TestGetVMConfigs(t *testing.T) {
+	assert := assert.New(t)
+	root := t.TempDir()
+
+	v := viper.New()
+	v.Set(VMConfigDirKey, root)
+	setupFile(t, path.Join(root, "evm"), chainConfigFileName+".json", `{"pruning-enabled": true}`)
+	setupFile(t, path.Join(root, "evm"), chainNodeFileName+".json", `{"apiEnabled": false, "consensus": {"k": 30}}`)
+	vmConfigs, err := getVMConfigs(v)
+	assert.NoError(err)
+	assert.Len(vmConfigs, 1)
+	evmConfig := vmConfigs["evm"]
+	assert.Equal(`{"pruning-enabled": true}`, string(evmConfig.Config))
+	assert.NotNil(evmConfig.Overrides.APIEnabled)
+	assert.False(*evmConfig.Overrides.APIEnabled)
+	assert.NotNil(evmConfig.Overrides.Consensus.K)
+	assert.Equal(30, *evmConfig.Overrides.Consensus.K)
+
+	// Upgrades can't be configured for every chain of a VM
+	setupFile(t, path.Join(root, "evm"), chainUpgradeFileName+".json", "{}")
+	_, err = getVMConfigs(v)
+	assert.Error(err)
+
+	// The overrides of the node must be valid
+	root = t.TempDir()
+	v.Set(VMConfigDirKey, root)
+	setupFile(t, path.Join(root, "evm"), chainNodeFileName+".json", `{"apiEnabld": false}`)
+	_, err = getVMConfigs(v)
+	assert.Error(err)
+
+	v.Set(VMConfigDirKey, path.Join(root, "missing"))
+	_, err = getVMConfigs(v)
+	assert.Error(err)
+}
+
+func TestGetVMAliases(t *testing.T) {
+	assert := assert.New(t)
+	vmID := ids.ID{'v', 'm'}
+
+	v := viper.New()
+	vmAliases, err := getVMAliases(v)
+	assert.NoError(err)
+	assert.Empty(vmAliases)
+
+	v.Set(VMAliasesKey, fmt.Sprintf(`{%q: ["vm1", "vm2"]}`, vmID))
+	vmAliases, err = getVMAliases(v)
+	assert.NoError(err)
+	assert.Equal(map[ids.ID][]string{vmID: {"vm1", "vm2"}}, vmAliases)
+
+	v.Set(VMAliasesKey, `{"notAnID": ["vm1"]}`)
+	_, err = getVMAliases(v)
+	assert.Error(err)
+}
+
+func TestGetReloadableConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	configFilePath := setupConfigJSON(t, t.TempDir(), `{
+		"log-level": "debug",
+		"http-rate-limit-rps": 10,
+		"http-rate-limit-burst": 20,
+		"network-peer-list-gossip-size": 30
+	}`)
+	args := []string{"--" + ConfigFileKey + "=" + configFilePath}
+	v, err := BuildViper(BuildFlagSet(), args)
+	assert.NoError(err)
+
+	config, err := GetReloadableConfig(v)
+	assert.NoError(err)
+	assert.Equal(logging.Debug, config.LogLevel)
+	// The display level defaults to the log level
+	assert.Equal(logging.Debug, config.LogDisplayLevel)
+	assert.Equal(10.0, config.HTTPRateLimitRPS)
+	assert.Equal(20, config.HTTPRateLimitBurst)
+	assert.EqualValues(30, config.PeerListGossipSize)
+
+	// Changes to the config file are picked up when it's parsed again
+	setupConfigJSON(t, filepath.Dir(configFilePath), `{
+		"log-level": "warn",
+		"log-display-level": "error"
+	}`)
+	v, err = BuildViper(BuildFlagSet(), args)
+	assert.NoError(err)
+	config, err = GetReloadableConfig(v)
+	assert.NoError(err)
+	assert.Equal(logging.Warn, config.LogLevel)
+	assert.Equal(logging.Error, config.LogDisplayLevel)
+	assert.Zero(config.HTTPRateLimitRPS)
+
+	v.Set(HTTPRateLimitRPSKey, 10)
+	v.Set(HTTPRateLimitBurstKey, 0)
+	_, err = GetReloadableConfig(v)
+	assert.Error(err)
+}
+
 // setups config json file and writes content
 func setupConfigJSON(t *testing.T, rootPath string, value string) string {
 	configFilePath := path.Join(rootPath, "config.json")
@@ -275,3 +373,71 @@ func setupViper(configFilePath string) *viper.Viper {
 	}
 	return v
 }
+
+func TestGetProxyConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	v := viper.New()
+	v.Set(HTTPHostKey, "0.0.0.0")
+	proxyConfig, err := getProxyConfig(v)
+	assert.NoError(err)
+	assert.False(proxyConfig.Enabled())
+
+	v.Set(OutboundProxyAddressKey, "127.0.0.1:9050")
+	v.Set(OutboundProxyUsernameKey, "user")
+	_, err = getProxyConfig(v)
+	assert.Error(err, "the API server should only listen on loopback addresses")
+
+	v.Set(HTTPHostKey, "localhost")
+	proxyConfig, err = getProxyConfig(v)
+	assert.NoError(err)
+	assert.True(proxyConfig.Enabled())
+	assert.Equal("127.0.0.1:9050", proxyConfig.Address)
+	assert.Equal("user", proxyConfig.Username)
+
+	v.Set(HTTPAdditionalListenersKey, []string{"http://127.0.0.1:9660", "https://0.0.0.0:9661"})
+	_, err = getProxyConfig(v)
+	assert.Error(err, "additional API listeners should only listen on loopback addresses")
+
+	v.Set(HTTPAdditionalListenersKey, []string{"http://[::1]:9660"})
+	_, err = getProxyConfig(v)
+	assert.NoError(err)
+
+	v.Set(DynamicPublicIPResolverKey, "opendns")
+	_, err = getProxyConfig(v)
+	assert.Error(err, "the public IP shouldn't be resolved dynamically")
+
+	v.Set(DynamicPublicIPResolverKey, "")
+	v.Set(OutboundProxyAddressKey, "9050")
+	_, err = getProxyConfig(v)
+	assert.Error(err)
+}
+
+func TestGetDBServerConfigRequiresSecurity(t *testing.T) {
+	assert := assert.New(t)
+
+	v := viper.New()
+	v.Set(DBTypeKey, manager.LevelDB)
+	v.Set(DBServerAddressKey, "127.0.0.1:9652")
+	_, err := GetDBServerConfig(v)
+	assert.NoError(err)
+
+	v.Set(DBServerAddressKey, "0.0.0.0:9652")
+	_, err = GetDBServerConfig(v)
+	assert.Error(err, "the database shouldn't be served on a public address without TLS and an authentication key")
+
+	assert.NoError(os.Setenv("TEST_DB_AUTH_KEY", strings.Repeat("ab", 32)))
+	defer os.Unsetenv("TEST_DB_AUTH_KEY")
+	v.Set(DBAuthKeyKey, "env:TEST_DB_AUTH_KEY")
+	_, err = GetDBServerConfig(v)
+	assert.Error(err, "the database shouldn't be served on a public address without TLS")
+
+	v.Set(DBServerAddressKey, "127.0.0.1:9652")
+	serverConfig, err := GetDBServerConfig(v)
+	assert.NoError(err)
+	assert.Len(serverConfig.Security.AuthKey, 32)
+
+	v.Set(DBRemoteAddressKey, "10.0.0.1:9652")
+	_, err = getDBRemoteSecurity(v)
+	assert.Error(err, "the node shouldn't send its authentication key to a public address without TLS")
+}
@@ -13,12 +13,18 @@ import (
 
 	"github.com/kardianos/osext"
 
+	"github.com/ava-labs/avalanchego/database/cryptdb"
+	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/ulimit"
 	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/sandbox"
+	"github.com/ava-labs/avalanchego/vms/scaffold"
 )
 
+const defaultDBServerAddress = "127.0.0.1:9652"
+
 // Results of parsing the CLI
 var (
 	defaultNetworkName     = constants.MainnetName
@@ -29,7 +35,11 @@ var (
 	defaultProfileDir      = filepath.Join(defaultDataDir, "profiles")
 	defaultStakingKeyPath  = filepath.Join(defaultDataDir, "staking", "staker.key")
 	defaultStakingCertPath = filepath.Join(defaultDataDir, "staking", "staker.crt")
+	defaultStakingBLSPath  = filepath.Join(defaultDataDir, "staking", "signer.key")
 	defaultChainConfigDir  = filepath.Join(defaultDataDir, "configs", "chains")
+	defaultVMConfigDir     = filepath.Join(defaultDataDir, "configs", "vms")
+	defaultSnapshotDir     = filepath.Join(defaultDataDir, "snapshots")
+	defaultBackupDir       = filepath.Join(defaultDataDir, "backups")
 
 	// Places to look for the build directory
 	defaultBuildDirs = []string{}
@@ -67,6 +77,9 @@ func addNodeFlags(fs *flag.FlagSet) {
 	// Fetch only mode
 	fs.Bool(FetchOnlyKey, false, "If true, bootstrap the current database version then stop")
 
+	// Archival mode
+	fs.Bool(ArchivalModeKey, false, "If true, open the bootstrapped database read-only and serve its data without issuing transactions or taking part in consensus")
+
 	// System
 	fs.Uint64(FdLimitKey, ulimit.DefaultFDLimit, "Attempts to raise the process file descriptor limit to at least this value.")
 
@@ -86,6 +99,35 @@ func addNodeFlags(fs *flag.FlagSet) {
 	// Database
 	fs.Bool(DBEnabledKey, true, "Turn on persistent storage")
 	fs.String(DBPathKey, defaultDBDir, "Path to database directory")
+	fs.String(DBTypeKey, manager.LevelDB, fmt.Sprintf("Database backend to use. One of %v. Each backend other than %s keeps its data in its own subdirectory of the database directory. If %s, the database is kept on the database server at %s", append(manager.Backends(), manager.RemoteDB), manager.LevelDB, manager.RemoteDB, DBRemoteAddressKey))
+	fs.String(DBWALPathKey, "", "Path to the directory that the write-ahead log of the database is kept in, such as on a faster device than the database directory. If empty, it's kept in the database directory. Once set, it can only be changed after the log files are moved to the new directory")
+	fs.Duration(DBCompactionFreqKey, 0, "How often the database is compacted. If 0, the database is only compacted when requested through the admin API")
+	fs.String(DBCompactionWindowKey, "", "Daily time window, in UTC and of the form HH:MM-HH:MM, in which scheduled compactions of the database start. If empty, they start at any time")
+	fs.String(DBEncryptionKeyKey, "", fmt.Sprintf("Source of the 32 byte, hex encoded key that the values of the database are encrypted with. One of %s<path>, %s<variable name> or %s<command that prints the key>. If empty, the database isn't encrypted. If the database already holds unencrypted data, it is encrypted on startup, which needs enough disk space for a copy of it. This isn't done in archival mode, which requires an encrypted or empty database", cryptdb.FileKeySource, cryptdb.EnvKeySource, cryptdb.ExecKeySource))
+	fs.String(DBEncryptionPreviousKeyKey, "", fmt.Sprintf("Source, like %s, of the key that the database was encrypted with before. If given, the database is re-encrypted with %s on startup", DBEncryptionKeyKey, DBEncryptionKeyKey))
+	fs.String(DBBackupTargetKey, defaultBackupDir, "Where backups of the database are written to by the Admin API and restored from. Either a directory or an http(s) URL that backups are uploaded to with PUT requests and downloaded from with GET requests. If empty, backups are disabled")
+	fs.String(DBRestoreBackupKey, "", fmt.Sprintf("Name of a backup in %s that replaces the contents of the database when the node starts. A database is only restored from a given backup once", DBBackupTargetKey))
+	fs.Bool(DBVerifyKey, false, "If true, scan the database for corrupted data and check the consistency of the state of each chain and index when the node starts, before the chains start. Inconsistencies are repaired where possible, and the rest are reported")
+	fs.Uint(DBCacheSizeKey, 0, "Number of bytes of the read cache that the chains share in front of the database. The chains that read the most keep the most in the cache. If 0, the database reads aren't cached")
+	fs.Float64(DBCacheChainQuotaKey, 0.25, fmt.Sprintf("Fraction of %s that a single chain can take up. Must be in (0, 1]", DBCacheSizeKey))
+	fs.String(DBRemoteAddressKey, defaultDBServerAddress, fmt.Sprintf("Address of the database server that the database is kept on if %s is %s. The server is started with the db-server command", DBTypeKey, manager.RemoteDB))
+	fs.Uint(DBRemoteConnsKey, 4, "Number of connections to the database server that requests are spread over")
+	fs.Uint(DBRemoteRetriesKey, 5, "Number of times that a request is retried, with an exponential backoff, when the database server is unavailable")
+	fs.String(DBRemoteTLSCAFileKey, "", fmt.Sprintf("File of the PEM encoded certificates that the TLS certificate of the database server is verified with. If empty, the connections to the server aren't encrypted, which is only allowed if %s is a loopback address", DBRemoteAddressKey))
+	fs.String(DBServerAddressKey, defaultDBServerAddress, fmt.Sprintf("Address that the db-server command serves the database on. The database is kept in the server subdirectory of the database directory. Unless it's a loopback address, %s, %s and %s are required", DBServerTLSKeyFileKey, DBServerTLSCertFileKey, DBAuthKeyKey))
+	fs.String(DBServerTLSKeyFileKey, "", "TLS private key file that the db-server command serves the database with")
+	fs.String(DBServerTLSCertFileKey, "", "TLS certificate file that the db-server command serves the database with")
+	fs.String(DBAuthKeyKey, "", fmt.Sprintf("Source, like %s, of the 32 byte, hex encoded key that nodes authenticate to the database server with. The node and the db-server command must be given the same key. If empty, nodes aren't authenticated, which is only allowed on loopback addresses", DBEncryptionKeyKey))
+	fs.Uint(DBBenchmarkKeysKey, 1000000, "Number of keys that the db-benchmark command writes and then reads")
+	fs.Uint(DBBenchmarkValueSizeKey, 512, "Number of bytes of each value that the db-benchmark command writes")
+	fs.Uint(DBBenchmarkConcurrencyKey, 8, "Number of goroutines that read concurrently in the db-benchmark command")
+
+	// VM scaffolding
+	fs.String(CreateVMNameKey, "", "Name of the VM that the create-vm command generates. It's the name of the Go package of the VM, and is put into the ID of the VM")
+	fs.String(CreateVMTypeKey, string(scaffold.SnowmanVM), fmt.Sprintf("Type of the VM that the create-vm command generates. One of {%s, %s}", scaffold.SnowmanVM, scaffold.DAGVM))
+	fs.String(CreateVMModuleKey, "", fmt.Sprintf("Path of the Go module that the create-vm command generates. If empty, %s is used", CreateVMNameKey))
+	fs.String(CreateVMDirKey, "", fmt.Sprintf("Directory that the create-vm command generates the module in. It must be empty if it exists. If empty, %s is used", CreateVMNameKey))
+	fs.String(CreateVMAvalancheGoDirKey, "", "If set, the module that the create-vm command generates uses the AvalancheGo source in this directory rather than the released version")
 
 	// Coreth config
 	fs.String(CorethConfigKey, "", "Specifies config to pass into coreth")
@@ -95,6 +137,14 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.String(LogLevelKey, "info", "The log level. Should be one of {verbo, debug, trace, info, warn, error, fatal, off}")
 	fs.String(LogDisplayLevelKey, "", "The log display level. If left blank, will inherit the value of log-level. Otherwise, should be one of {verbo, debug, info, warn, error, fatal, off}")
 	fs.String(LogDisplayHighlightKey, "auto", "Whether to color/highlight display logs. Default highlights when the output is a terminal. Otherwise, should be one of {auto, plain, colors}")
+	fs.String(LogFormatKey, "text", "The format of the lines written to log files. Should be one of {text, json}")
+	fs.String(LogDisplayFormatKey, "", "The format of displayed logs. If left blank, will inherit the value of log-format. Otherwise, should be one of {text, json}")
+	fs.Duration(LogRotationIntervalKey, 24*time.Hour, "Interval after which a log file is rotated, regardless of its size")
+	fs.Int(LogRotationFileSizeKey, 8*1024*1024, "Size in bytes that a log file is rotated at")
+	fs.Int(LogRotationFilesKey, 7, "Number of rotated files that are kept of each log")
+	fs.Duration(LogRotationMaxAgeKey, 0, "Age after which rotated log files are deleted. If 0, rotated files are only deleted once there are more than log-rotation-files of them")
+	fs.Bool(LogRotationCompressKey, false, "If true, rotated log files are compressed with gzip")
+	fs.String(LogChainRotationKey, "", "JSON object mapping chain aliases to overrides of how the logs of the chain are rotated. Example: {\"X\":{\"fileSize\":33554432,\"files\":30,\"maxAge\":\"720h\",\"compress\":true}}")
 
 	// Assertions
 	fs.Bool(AssertionsEnabledKey, true, "Turn on assertion execution")
@@ -113,6 +163,9 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Uint(NetworkPeerListGossipSizeKey, 50, gossipHelpMsg)
 	fs.Duration(NetworkPeerListGossipFreqKey, time.Minute, gossipHelpMsg)
 
+	// Protocol Capabilities
+	fs.String(NetworkCapabilitiesKey, "", "Comma separated list of optional protocol features to advertise to peers. A feature is only used with peers that also advertise it. Example: state-sync,chunked-transfer")
+
 	// Public IP Resolution
 	fs.String(PublicIPKey, "", "Public IP of this node for P2P communication. If empty, try to discover with NAT. Ignored if dynamic-public-ip is non-empty.")
 	fs.Duration(DynamicUpdateDurationKey, 5*time.Minute, "Dynamic IP and NAT Traversal update duration")
@@ -131,6 +184,10 @@ func addNodeFlags(fs *flag.FlagSet) {
 	// Outgoing Connection Throttling
 	fs.Uint(OutboundConnectionThrottlingRps, 50, "Make at most this number of outgoing peer connection attempts per second.")
 	fs.Duration(OutboundConnectionTimeout, 30*time.Second, "Timeout when dialing a peer.")
+	// Outgoing Connection Proxy
+	fs.String(OutboundProxyAddressKey, "", "Address (host:port) of a SOCKS5 proxy, such as a Tor client, to make outgoing peer connections through. If empty, peers are dialed directly. If set, inbound peer connections are only accepted on the loopback interface, the HTTP server must listen on a loopback address, and this node advertises no IP to its peers unless public-ip is set.")
+	fs.String(OutboundProxyUsernameKey, "", "Username to authenticate with the outbound SOCKS5 proxy. Ignored if outbound-proxy-address is empty.")
+	fs.String(OutboundProxyPasswordKey, "", "Password to authenticate with the outbound SOCKS5 proxy. Ignored if outbound-proxy-address is empty.")
 	// Timeouts
 	fs.Duration(NetworkInitialTimeoutKey, 5*time.Second, "Initial timeout value of the adaptive timeout manager.")
 	fs.Duration(NetworkMinimumTimeoutKey, 2*time.Second, "Minimum timeout value of the adaptive timeout manager.")
@@ -148,6 +205,8 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Bool(BenchlistPeerSummaryEnabledKey, false, "Enables peer specific query latency metrics.")
 	fs.Duration(BenchlistDurationKey, 30*time.Minute, "Max amount of time a peer is benchlisted after surpassing the threshold.")
 	fs.Duration(BenchlistMinFailingDurationKey, 5*time.Minute, "Minimum amount of time messages to a peer must be failing before the peer is benched.")
+	fs.Float64(BenchlistMaxSamplingPenaltyKey, 0, "Maximum fraction of a peer's weight it isn't sampled with because it doesn't respond to queries. Must be in [0, 1). If 0, peers aren't penalized.")
+	fs.Float64(BenchlistSamplingPenaltyThresholdKey, .8, "Peers that respond to less than this fraction of queries are sampled less often.")
 
 	// Router
 	fs.Uint(MaxNonStakerPendingMsgsKey, uint(router.DefaultMaxNonStakerPendingMsgs), "Maximum number of messages a non-staker is allowed to have pending.")
@@ -158,6 +217,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(ConsensusShutdownTimeoutKey, 5*time.Second, "Timeout before killing an unresponsive chain.")
 	fs.Uint(ConsensusGossipAcceptedFrontierSizeKey, 35, "Number of peers to gossip to when gossiping accepted frontier")
 	fs.Uint(ConsensusGossipOnAcceptSizeKey, 20, "Number of peers to gossip to each accepted container to")
+	fs.Uint(ConsensusPushAcceptedFrontierSizeKey, 0, "Number of peers to push the ID of each accepted container to. If 0, accepted frontiers aren't pushed")
 
 	// HTTP APIs
 	fs.String(HTTPHostKey, "127.0.0.1", "Address of the HTTP server")
@@ -166,15 +226,28 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.String(HTTPSKeyFileKey, "", "TLS private key file for the HTTPs server")
 	fs.String(HTTPSCertFileKey, "", "TLS certificate file for the HTTPs server")
 	fs.String(HTTPAllowedOrigins, "*", "Origins to allow on the HTTP port. Defaults to * which allows all origins. Example: https://*.avax.network https://*.avax-test.network")
+	fs.Uint(GRPCPortKey, 9652, "Port of the gRPC server. Uses the HTTP server's address and TLS configuration")
 	fs.Bool(APIAuthRequiredKey, false, "Require authorization token to call HTTP APIs")
 	fs.String(APIAuthPasswordFileKey, "", "Password file used to initially create/validate API authorization tokens. Leading and trailing whitespace is removed from the password. Can be changed via API call.")
+	fs.Float64(HTTPRateLimitRPSKey, 0, "Average number of HTTP API requests per second allowed from each client, identified by its authorization token or IP. If 0, requests aren't rate limited")
+	fs.Uint(HTTPRateLimitBurstKey, 100, "Maximum number of HTTP API requests each client can make at once when rate limiting is enabled")
+	fs.Uint64(HTTPMaxRequestBodySizeKey, 0, "Maximum size of the body of an HTTP API request in bytes. If 0, the size isn't limited")
+	fs.String(HTTPTrustedProxiesKey, "", "Space separated IPs or CIDR blocks of reverse proxies whose X-Forwarded-For header is trusted to identify the client of an HTTP API request. Example: 10.0.0.0/8 127.0.0.1")
+	fs.String(HTTPAdditionalListenersKey, "", "Space separated addresses to serve the HTTP APIs on in addition to the HTTP server's address. https listeners use the TLS certificate of the HTTP server. Example: http://127.0.0.1:9660 https://0.0.0.0:9661")
+	fs.Uint(HTTPMaxBatchSizeKey, 100, "Maximum number of requests in a JSON-RPC batch request to the HTTP APIs. If 0, batch requests are rejected")
 	// Enable/Disable APIs
 	fs.Bool(AdminAPIEnabledKey, false, "If true, this node exposes the Admin API")
+	fs.Bool(DebugAPIEnabledKey, false, "If true, this node exposes the Debug API. Its consensus engines then record the lifecycle of the containers they process so that it can be looked up")
+	fs.Bool(AuditAPIEnabledKey, false, "If true, this node records calls to the Admin, Keystore and Auth APIs in an audit log, which is exposed by the Audit API")
 	fs.Bool(InfoAPIEnabledKey, true, "If true, this node exposes the Info API")
 	fs.Bool(KeystoreAPIEnabledKey, true, "If true, this node exposes the Keystore API")
 	fs.Bool(MetricsAPIEnabledKey, true, "If true, this node exposes the Metrics API")
+	fs.Bool(MetricsAPIExemplarsEnabledKey, false, "If true, the Metrics API serves the OpenMetrics format to scrapers that ask for it. Its consensus latency histograms then have exemplars holding the ID of a container they were measured on, whose lifecycle can be looked up with the Debug API")
 	fs.Bool(HealthAPIEnabledKey, true, "If true, this node exposes the Health API")
 	fs.Bool(IpcAPIEnabledKey, false, "If true, IPCs can be opened")
+	fs.Bool(GRPCAPIEnabledKey, false, "If true, this node serves the enabled info, health, keystore and index APIs over gRPC, and forwards gRPC calls to the chains' APIs")
+	fs.Bool(GraphQLAPIEnabledKey, false, "If true, this node exposes a GraphQL API over node info, validator sets and indexed containers")
+	fs.Bool(EventsAPIEnabledKey, false, "If true, this node exposes a websocket for each chain that notifies subscribers of accepted transactions and blocks")
 
 	// Health Checks
 	fs.Duration(HealthCheckFreqKey, 30*time.Second, "Time between health checks")
@@ -189,6 +262,13 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Float64(RouterHealthMaxDropRateKey, 1, "Node reports unhealthy if the router drops more than this portion of messages.")
 	fs.Uint(RouterHealthMaxOutstandingRequestsKey, 1024, "Node reports unhealthy if there are more than this many outstanding consensus requests (Get, PullQuery, etc.) over all chains")
 	fs.Duration(NetworkHealthMaxOutstandingDurationKey, 5*time.Minute, "Node reports unhealthy if there has been a request outstanding for this duration")
+	// Consensus Health
+	fs.Float64(ConsensusHealthMaxPollFailureRateKey, .5, "A chain reports unhealthy if more than this portion of the queries it sends fail")
+	// Watchdog
+	fs.String(WatchdogRulesKey, "", `JSON array of the conditions that the watchdog fires alerts about, such as [{"chain": "X", "maxTimeSinceAccept": "10m"}, {"chain": "*", "maxPollFailureRate": 0.5}]. Each rule names a chain by its alias or ID, or every chain with "*". If empty, the watchdog is disabled`)
+	fs.String(WatchdogWebhookURLKey, "", "If non-empty, each watchdog alert is POSTed to this URL as JSON")
+	fs.String(WatchdogExecCommandKey, "", "If non-empty, this command is run for each watchdog alert, with the alert written to its standard input as JSON")
+	fs.Duration(WatchdogCheckFreqKey, 30*time.Second, "Time between checks of the conditions of the watchdog's rules")
 
 	// Staking
 	fs.Uint(StakingPortKey, 9651, "Port of the consensus server")
@@ -196,7 +276,11 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Bool(StakingEphemeralCertEnabledKey, false, "If true, the node uses an ephemeral staking key and certificate, and has an ephemeral node ID.")
 	fs.String(StakingKeyPathKey, defaultStakingKeyPath, "Path to the TLS private key for staking")
 	fs.String(StakingCertPathKey, defaultStakingCertPath, "Path to the TLS certificate for staking")
+	fs.String(StakingSignerPluginKey, "", "Path to a signer plugin that holds the TLS private key for staking, such as in an HSM or PKCS#11 token. If set, the key isn't read from disk")
+	fs.String(StakingBLSKeyPathKey, defaultStakingBLSPath, "Path to the BLS key that the node attests to validator snapshots with. The key is created if it doesn't exist, and must be registered on the platform chain for the node's attestations to count")
 	fs.Uint64(StakingDisabledWeightKey, 1, "Weight to provide to each peer when staking is disabled")
+	fs.Float64(ValidatorChurnLimitKey, 0, "Maximum fraction of the weight of the validators that each chain's consensus engine samples that can be added or removed per churn period. The platform chain's validator sets aren't limited. If 0, changes aren't limited")
+	fs.Duration(ValidatorChurnPeriodKey, time.Hour, "Period over which the validator churn limit applies")
 	// Uptime Requirement
 	fs.Float64(UptimeRequirementKey, .6, "Fraction of time a validator must be online to receive rewards")
 	// Minimum Stake required to validate the Primary Network
@@ -214,6 +298,8 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(StakeMintingPeriodKey, 365*24*time.Hour, "Consumption period of the staking function")
 	// Subnets
 	fs.String(WhitelistedSubnetsKey, "", "Whitelist of subnets to validate.")
+	fs.String(SubnetSamplingCapsKey, "", "JSON object mapping subnet IDs to the caps applied when sampling their validators. Example: {\"2bRCr6B4MiEfSjidDwxDpdCyviwnfUVqB2HGwhm947w9YYqb7r\":{\"minStake\":2000000000000,\"maxWeightFraction\":0.1}}")
+	fs.Int(DistinctSamplingMaxValidatorsKey, 0, "Validator sets with at most this many validators are sampled without querying the same validator more than once per poll")
 
 	// Bootstrapping
 	fs.String(BootstrapIPsKey, "", "Comma separated list of bootstrap peer ips to connect to. Example: 127.0.0.1:9630,127.0.0.1:9631")
@@ -238,6 +324,10 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(SnowMaxTimeProcessingKey, 2*time.Minute, "Maximum amount of time an item should be processing and still be healthy")
 	fs.Int64(SnowEpochFirstTransition, 1607626800, "Unix timestamp of the first epoch transaction, in seconds. Defaults to 12/10/2020 @ 7:00pm (UTC)")
 	fs.Duration(SnowEpochDuration, 6*time.Hour, "Duration of each epoch")
+	fs.Float64(SnowMaxIPPrefixSampleFractionKey, 0, fmt.Sprintf("Maximum fraction of the validators sampled for each poll that can connect from the same IP prefix (/24 for IPv4, /48 for IPv6), or from the same ASN if %s knows their IP. If 0 or 1, samples aren't limited", SnowASNPrefixFileKey))
+	fs.String(SnowASNPrefixFileKey, "", "File that maps IP prefixes to the ASNs that announce them, one prefix in CIDR notation and ASN per line. Validators are grouped by the ASN of the longest prefix that contains their IP when polls are sampled. If empty, validators are only grouped by IP prefix")
+	fs.Bool(SnowDeterministicSamplingKey, false, "If true, the validators sampled for each poll are derived from the chain, the polled container, and the request ID, so that polls can be replayed and audited from message logs")
+	fs.String(SnowAuditLogDirKey, "", "If non-empty, each chain appends the decisions of its containers, with the chits of the polls that decided them, to an audit log in this directory")
 
 	// Metrics
 	fs.Bool(MeterVMsEnabledKey, false, "Enable Meter VMs to track VM performance with more granularity")
@@ -249,15 +339,32 @@ func addNodeFlags(fs *flag.FlagSet) {
 	// Indexer
 	fs.Bool(IndexEnabledKey, false, "If true, index all accepted containers and transactions and expose them via an API")
 	fs.Bool(IndexAllowIncompleteKey, false, "If true, allow running the node in such a way that could cause an index to miss transactions. Ignored if index is disabled.")
+	fs.String(IndexChainsKey, "", "Comma separated list of chain IDs to index. If empty, all primary network chains are indexed. Chains of other subnets are only indexed if they're listed. Ignored if index is disabled.")
+	fs.Duration(IndexPruningFreqKey, 0, fmt.Sprintf("How often indexed containers that are retained by neither %s nor %s are pruned from the index. Their IDs stay indexed. Only the indexer's database is pruned; chains keep the containers they accepted. If 0, containers are never pruned", IndexPruningRetainHeightKey, IndexPruningRetainAgeKey))
+	fs.Uint64(IndexPruningRetainHeightKey, 0, "Number of most recently accepted containers of each index that are never pruned. If 0, containers aren't retained because of their height")
+	fs.Duration(IndexPruningRetainAgeKey, 0, "Indexed containers accepted less than this long ago are never pruned. If 0, containers aren't retained because of their age")
 
 	// Chain Config Dir
 	fs.String(ChainConfigDirKey, defaultChainConfigDir, "Chain specific configurations parent directory. Defaults to $HOME/.avalanchego/configs/chains/")
+	// VM Config Dir
+	fs.String(VMConfigDirKey, defaultVMConfigDir, fmt.Sprintf("VM specific configurations parent directory. Each subdirectory, named by a VM's ID or alias, configures the chains of the VM that %s doesn't. Defaults to $HOME/.avalanchego/configs/vms/", ChainConfigDirKey))
+
+	// Chain snapshots
+	fs.String(SnapshotDirKey, defaultSnapshotDir, "Directory that chain snapshots are exported to and imported from by the Admin API. Snapshots staged in its pending subdirectory replace the state of their chain when the node starts")
+
+	// Plugin resource limits
+	fs.Uint64(PluginCPUSharesKey, 0, fmt.Sprintf("Share of the CPU time, from %d to %d, that each plugin VM gets relative to the other plugin VMs when the CPU is contended. Plugins get 100 shares if they aren't limited. If 0, plugins aren't limited. Requires %s", sandbox.MinCPUShares, sandbox.MaxCPUShares, PluginCgroupDirKey))
+	fs.Uint64(PluginMemoryLimitKey, 0, fmt.Sprintf("Bytes of memory that each plugin VM may use. A plugin that needs more is killed, without affecting the rest of the node. If 0, plugins aren't limited. Requires %s", PluginCgroupDirKey))
+	fs.Uint64(PluginFDLimitKey, 0, "Maximum number of file descriptors that each plugin VM may have open. If 0, plugins inherit the limit of the node")
+	fs.String(VMAliasesKey, "", "JSON object mapping VM IDs to aliases that the static APIs of the VMs are served at, such as the plugin VMs that the node finds in its plugin directory. Example: {\"tGas3T58KzdjLHhBDMnH2TvrddhqTji5iZAMZ3RXs2NLpSnhH\":[\"timestamp2\"]}")
+	fs.String(PluginCgroupDirKey, "", "Directory of a cgroup v2 that the node may manage, such as one delegated to it by systemd. Each plugin VM is put in a cgroup of its own under it to limit its CPU and memory")
 
 	// Profiles
 	fs.String(ProfileDirKey, defaultProfileDir, "Path to the profile directory")
 	fs.Bool(ProfileContinuousEnabledKey, false, "Whether the app should continuously produce performance profiles")
 	fs.Duration(ProfileContinuousFreqKey, 15*time.Minute, "How frequently to rotate performance profiles")
 	fs.Int(ProfileContinuousMaxFilesKey, 5, "Maximum number of historical profiles to keep")
+	fs.String(ProfileContinuousUploadURLKey, "", fmt.Sprintf("If non-empty, each profile written by the continuous profiler is POSTed to this http(s) URL in the pprof format. Requires %s", ProfileContinuousEnabledKey))
 }
 
 // BuildFlagSet returns a complete set of flags for avalanchego
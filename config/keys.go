@@ -5,6 +5,7 @@ package config
 
 const (
 	FetchOnlyKey                              = "fetch-only"
+	ArchivalModeKey                           = "archival-mode"
 	ConfigFileKey                             = "config-file"
 	VersionKey                                = "version"
 	GenesisConfigFileKey                      = "genesis"
@@ -23,6 +24,33 @@ const (
 	SignatureVerificationEnabledKey           = "signature-verification-enabled"
 	DBEnabledKey                              = "db-enabled"
 	DBPathKey                                 = "db-dir"
+	DBTypeKey                                 = "db-type"
+	DBWALPathKey                              = "db-wal-dir"
+	DBRemoteAddressKey                        = "db-remote-address"
+	DBRemoteConnsKey                          = "db-remote-conns"
+	DBRemoteRetriesKey                        = "db-remote-retries"
+	DBRemoteTLSCAFileKey                      = "db-remote-tls-ca-file"
+	DBServerAddressKey                        = "db-server-address"
+	DBServerTLSKeyFileKey                     = "db-server-tls-key-file"
+	DBServerTLSCertFileKey                    = "db-server-tls-cert-file"
+	DBAuthKeyKey                              = "db-auth-key"
+	DBCompactionFreqKey                       = "db-compaction-frequency"
+	DBCompactionWindowKey                     = "db-compaction-window"
+	DBEncryptionKeyKey                        = "db-encryption-key"
+	DBEncryptionPreviousKeyKey                = "db-encryption-previous-key"
+	DBBackupTargetKey                         = "db-backup-target"
+	DBRestoreBackupKey                        = "db-restore-backup"
+	DBVerifyKey                               = "db-verify"
+	DBCacheSizeKey                            = "db-cache-size"
+	DBCacheChainQuotaKey                      = "db-cache-chain-quota"
+	DBBenchmarkKeysKey                        = "db-benchmark-keys"
+	DBBenchmarkValueSizeKey                   = "db-benchmark-value-size"
+	DBBenchmarkConcurrencyKey                 = "db-benchmark-concurrency"
+	CreateVMNameKey                           = "create-vm-name"
+	CreateVMTypeKey                           = "create-vm-type"
+	CreateVMModuleKey                         = "create-vm-module"
+	CreateVMDirKey                            = "create-vm-dir"
+	CreateVMAvalancheGoDirKey                 = "create-vm-avalanchego-dir"
 	PublicIPKey                               = "public-ip"
 	DynamicUpdateDurationKey                  = "dynamic-update-duration"
 	DynamicPublicIPResolverKey                = "dynamic-public-ip"
@@ -30,14 +58,24 @@ const (
 	ConnMeterMaxConnsKey                      = "conn-meter-max-conns"
 	OutboundConnectionThrottlingRps           = "outbound-connection-throttling-rps"
 	OutboundConnectionTimeout                 = "outbound-connection-timeout"
+	OutboundProxyAddressKey                   = "outbound-proxy-address"
+	OutboundProxyUsernameKey                  = "outbound-proxy-username"
+	OutboundProxyPasswordKey                  = "outbound-proxy-password" // #nosec G101
 	HTTPHostKey                               = "http-host"
 	HTTPPortKey                               = "http-port"
 	HTTPSEnabledKey                           = "http-tls-enabled"
 	HTTPSKeyFileKey                           = "http-tls-key-file"
 	HTTPSCertFileKey                          = "http-tls-cert-file"
 	HTTPAllowedOrigins                        = "http-allowed-origins"
+	GRPCPortKey                               = "grpc-port"
 	APIAuthRequiredKey                        = "api-auth-required"
 	APIAuthPasswordFileKey                    = "api-auth-password-file" // #nosec G101
+	HTTPRateLimitRPSKey                       = "http-rate-limit-rps"
+	HTTPRateLimitBurstKey                     = "http-rate-limit-burst"
+	HTTPMaxRequestBodySizeKey                 = "http-max-request-body-size"
+	HTTPTrustedProxiesKey                     = "http-trusted-proxies"
+	HTTPAdditionalListenersKey                = "http-additional-listeners"
+	HTTPMaxBatchSizeKey                       = "http-max-batch-size"
 	BootstrapIPsKey                           = "bootstrap-ips"
 	BootstrapIDsKey                           = "bootstrap-ids"
 	StakingPortKey                            = "staking-port"
@@ -45,7 +83,11 @@ const (
 	StakingEphemeralCertEnabledKey            = "staking-ephemeral-cert-enabled"
 	StakingKeyPathKey                         = "staking-tls-key-file"
 	StakingCertPathKey                        = "staking-tls-cert-file"
+	StakingSignerPluginKey                    = "staking-signer-plugin"
+	StakingBLSKeyPathKey                      = "staking-bls-key-file"
 	StakingDisabledWeightKey                  = "staking-disabled-weight"
+	ValidatorChurnLimitKey                    = "validator-churn-limit"
+	ValidatorChurnPeriodKey                   = "validator-churn-period"
 	MaxNonStakerPendingMsgsKey                = "max-non-staker-pending-msgs"
 	StakerMsgReservedKey                      = "staker-msg-reserved"
 	StakerCPUReservedKey                      = "staker-cpu-reserved"
@@ -64,16 +106,27 @@ const (
 	NetworkPeerListSizeKey                    = "network-peer-list-size"
 	NetworkPeerListGossipSizeKey              = "network-peer-list-gossip-size"
 	NetworkPeerListGossipFreqKey              = "network-peer-list-gossip-frequency"
+	NetworkCapabilitiesKey                    = "network-capabilities"
 	SendQueueSizeKey                          = "send-queue-size"
 	BenchlistFailThresholdKey                 = "benchlist-fail-threshold"
 	BenchlistPeerSummaryEnabledKey            = "benchlist-peer-summary-enabled"
 	BenchlistDurationKey                      = "benchlist-duration"
 	BenchlistMinFailingDurationKey            = "benchlist-min-failing-duration"
+	BenchlistMaxSamplingPenaltyKey            = "benchlist-max-sampling-penalty"
+	BenchlistSamplingPenaltyThresholdKey      = "benchlist-sampling-penalty-threshold"
 	BuildDirKey                               = "build-dir"
 	LogsDirKey                                = "log-dir"
 	LogLevelKey                               = "log-level"
 	LogDisplayLevelKey                        = "log-display-level"
 	LogDisplayHighlightKey                    = "log-display-highlight"
+	LogFormatKey                              = "log-format"
+	LogDisplayFormatKey                       = "log-display-format"
+	LogRotationIntervalKey                    = "log-rotation-interval"
+	LogRotationFileSizeKey                    = "log-rotation-file-size"
+	LogRotationFilesKey                       = "log-rotation-files"
+	LogRotationMaxAgeKey                      = "log-rotation-max-age"
+	LogRotationCompressKey                    = "log-rotation-compress"
+	LogChainRotationKey                       = "log-chain-rotation"
 	SnowSampleSizeKey                         = "snow-sample-size"
 	SnowQuorumSizeKey                         = "snow-quorum-size"
 	SnowVirtuousCommitThresholdKey            = "snow-virtuous-commit-threshold"
@@ -86,28 +139,50 @@ const (
 	SnowMaxTimeProcessingKey                  = "snow-max-time-processing"
 	SnowEpochFirstTransition                  = "snow-epoch-first-transition"
 	SnowEpochDuration                         = "snow-epoch-duration"
+	SnowDeterministicSamplingKey              = "snow-deterministic-sampling"
+	SnowAuditLogDirKey                        = "snow-audit-log-dir"
+	SnowMaxIPPrefixSampleFractionKey          = "snow-max-ip-prefix-sample-fraction"
+	SnowASNPrefixFileKey                      = "snow-asn-prefix-file"
 	WhitelistedSubnetsKey                     = "whitelisted-subnets"
+	SubnetSamplingCapsKey                     = "subnet-sampling-caps"
+	DistinctSamplingMaxValidatorsKey          = "distinct-sampling-max-validators"
 	AdminAPIEnabledKey                        = "api-admin-enabled"
+	DebugAPIEnabledKey                        = "api-debug-enabled"
+	AuditAPIEnabledKey                        = "api-audit-enabled"
 	InfoAPIEnabledKey                         = "api-info-enabled"
 	KeystoreAPIEnabledKey                     = "api-keystore-enabled"
 	MetricsAPIEnabledKey                      = "api-metrics-enabled"
+	MetricsAPIExemplarsEnabledKey             = "api-metrics-exemplars-enabled"
 	HealthAPIEnabledKey                       = "api-health-enabled"
 	IpcAPIEnabledKey                          = "api-ipcs-enabled"
+	EventsAPIEnabledKey                       = "api-events-enabled"
+	GraphQLAPIEnabledKey                      = "api-graphql-enabled"
+	GRPCAPIEnabledKey                         = "api-grpc-enabled"
 	IpcsChainIDsKey                           = "ipcs-chain-ids"
 	IpcsPathKey                               = "ipcs-path"
 	MeterVMsEnabledKey                        = "meter-vms-enabled"
 	ConsensusGossipFrequencyKey               = "consensus-gossip-frequency"
 	ConsensusGossipAcceptedFrontierSizeKey    = "consensus-accepted-frontier-gossip-size"
 	ConsensusGossipOnAcceptSizeKey            = "consensus-on-accept-gossip-size"
+	ConsensusPushAcceptedFrontierSizeKey      = "consensus-accepted-frontier-push-size"
 	ConsensusShutdownTimeoutKey               = "consensus-shutdown-timeout"
 	FdLimitKey                                = "fd-limit"
 	CorethConfigKey                           = "coreth-config"
 	IndexEnabledKey                           = "index-enabled"
 	IndexAllowIncompleteKey                   = "index-allow-incomplete"
+	IndexChainsKey                            = "index-chains"
+	IndexPruningFreqKey                       = "index-pruning-frequency"
+	IndexPruningRetainHeightKey               = "index-pruning-retain-height"
+	IndexPruningRetainAgeKey                  = "index-pruning-retain-age"
 	RouterHealthMaxDropRateKey                = "router-health-max-drop-rate"
 	RouterHealthMaxOutstandingRequestsKey     = "router-health-max-outstanding-requests"
+	ConsensusHealthMaxPollFailureRateKey      = "consensus-health-max-poll-failure-rate"
 	HealthCheckFreqKey                        = "health-check-frequency"
 	HealthCheckAveragerHalflifeKey            = "health-check-averager-halflife"
+	WatchdogRulesKey                          = "watchdog-rules"
+	WatchdogWebhookURLKey                     = "watchdog-webhook-url"
+	WatchdogExecCommandKey                    = "watchdog-exec-command"
+	WatchdogCheckFreqKey                      = "watchdog-check-frequency"
 	RetryBootstrapKey                         = "bootstrap-retry-enabled"
 	RetryBootstrapMaxAttemptsKey              = "bootstrap-retry-max-attempts"
 	PeerAliasTimeoutKey                       = "peer-alias-timeout"
@@ -117,8 +192,16 @@ const (
 	BootstrapMultiputMaxContainersSentKey     = "bootstrap-multiput-max-containers-sent"
 	BootstrapMultiputMaxContainersReceivedKey = "bootstrap-multiput-max-containers-received"
 	ChainConfigDirKey                         = "chain-config-dir"
+	VMConfigDirKey                            = "vm-config-dir"
 	ProfileDirKey                             = "profile-dir"
+	SnapshotDirKey                            = "snapshot-dir"
 	ProfileContinuousEnabledKey               = "profile-continuous-enabled"
 	ProfileContinuousFreqKey                  = "profile-continuous-freq"
 	ProfileContinuousMaxFilesKey              = "profile-continuous-max-files"
+	ProfileContinuousUploadURLKey             = "profile-continuous-upload-url"
+	PluginCPUSharesKey                        = "plugin-cpu-shares"
+	PluginMemoryLimitKey                      = "plugin-memory-limit"
+	PluginFDLimitKey                          = "plugin-fd-limit"
+	PluginCgroupDirKey                        = "plugin-cgroup-dir"
+	VMAliasesKey                              = "vm-aliases"
 )
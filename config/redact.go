@@ -0,0 +1,39 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"github.com/spf13/viper"
+)
+
+// Redacted replaces the value of secret settings
+const Redacted = "<redacted>"
+
+// redactedKeys are the keys of the settings that hold secrets
+var redactedKeys = map[string]struct{}{
+	DBEncryptionKeyKey:         {},
+	DBEncryptionPreviousKeyKey: {},
+	// Backup targets and upload URLs may hold the credentials of the store
+	DBBackupTargetKey:             {},
+	ProfileContinuousUploadURLKey: {},
+	OutboundProxyPasswordKey:      {},
+	// Webhook URLs often hold a token
+	WatchdogWebhookURLKey: {},
+}
+
+// RedactedSettings returns the settings of [v] by their key, with the values
+// of secrets replaced by Redacted. Secrets that aren't set are left empty, so
+// that it's still visible whether they're set.
+func RedactedSettings(v *viper.Viper) map[string]interface{} {
+	keys := v.AllKeys()
+	settings := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		value := v.Get(key)
+		if _, ok := redactedKeys[key]; ok && v.GetString(key) != "" {
+			value = Redacted
+		}
+		settings[key] = value
+	}
+	return settings
+}
@@ -0,0 +1,367 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+const (
+	formatVersion = uint16(0)
+
+	dataSuffix     = ".data"
+	manifestSuffix = ".manifest"
+
+	// Marks whether another key/value pair follows in the data of a backup
+	record = byte(1)
+	end    = byte(0)
+
+	// Number of bytes written to the database at a time when a backup is
+	// restored
+	restoreBatchSize = 4 * 1024 * 1024
+)
+
+var (
+	// restoredKey maps to the name of the backup that the database was last
+	// restored from
+	restoredKey = []byte("restoredBackup")
+
+	errDatabaseNotEmpty = errors.New("backups can only be restored to an empty database")
+	errUnknownVersion   = errors.New("unknown backup version")
+	errWrongChecksum    = errors.New("backup data doesn't match the checksum in its manifest")
+	errWrongNumKeys     = errors.New("backup data has the wrong number of key/value pairs")
+)
+
+// Manifest describes a backup
+type Manifest struct {
+	Version uint16 `json:"version"`
+	// Time the backup was taken
+	Time time.Time `json:"time"`
+	// Number of key/value pairs in the backup
+	NumKeys uint64 `json:"numKeys"`
+	// Hex encoded SHA-256 hash of the backup's data
+	Checksum string `json:"checksum"`
+	// Accepted frontier of each chain when the backup was taken
+	Chains []ChainFrontier `json:"chains"`
+}
+
+// ChainFrontier is the accepted frontier of a chain, and the checkpoint of its
+// VM, when a backup was taken
+type ChainFrontier struct {
+	ChainID  ids.ID   `json:"chainID"`
+	Frontier []ids.ID `json:"frontier"`
+	// Returned by the chain's VM when it was checkpointed, if it was
+	Checkpoint []byte `json:"checkpoint,omitempty"`
+}
+
+// Frontiers returns the accepted frontier of each chain in the manifest
+func (m *Manifest) Frontiers() map[ids.ID][]ids.ID {
+	frontiers := make(map[ids.ID][]ids.ID, len(m.Chains))
+	for _, chain := range m.Chains {
+		frontiers[chain.ChainID] = chain.Frontier
+	}
+	return frontiers
+}
+
+// Checkpoints returns the checkpoint of each chain in the manifest whose VM
+// was checkpointed
+func (m *Manifest) Checkpoints() map[ids.ID][]byte {
+	checkpoints := make(map[ids.ID][]byte, len(m.Chains))
+	for _, chain := range m.Chains {
+		if chain.Checkpoint != nil {
+			checkpoints[chain.ChainID] = chain.Checkpoint
+		}
+	}
+	return checkpoints
+}
+
+// Write a backup named [name] of every key/value pair in [db] to [store].
+// [chains] is recorded in the backup's manifest. The manifest is written once
+// the backup's data is, so backups without a manifest are incomplete.
+func Write(store Store, name string, db database.Iteratee, chains []ChainFrontier) (*Manifest, error) {
+	manifest := &Manifest{
+		Version: formatVersion,
+		Time:    time.Now().UTC(),
+		Chains:  chains,
+	}
+
+	w, err := store.Create(name + dataSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create backup data: %w", err)
+	}
+	checksum := sha256.New()
+	numKeys, err := writeData(db, io.MultiWriter(w, checksum))
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't write backup data: %w", err)
+	}
+	manifest.NumKeys = numKeys
+	manifest.Checksum = hex.EncodeToString(checksum.Sum(nil))
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	w, err = store.Create(name + manifestSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create backup manifest: %w", err)
+	}
+	_, err = w.Write(manifestBytes)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't write backup manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// Verify that the backup named [name] in [store] is complete and matches its
+// manifest. Returns the manifest.
+func Verify(store Store, name string) (*Manifest, error) {
+	manifest, err := readManifest(store, name)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, readBackup(store, name, manifest, func([]byte, []byte) error { return nil })
+}
+
+// RestoredFrom returns the name of the backup that [db] was restored from, or
+// the empty string if it wasn't restored from a backup
+func RestoredFrom(db database.KeyValueReader) (string, error) {
+	restored, err := db.Get(restoredKey)
+	switch err {
+	case nil:
+		return string(restored), nil
+	case database.ErrNotFound:
+		return "", nil
+	default:
+		return "", err
+	}
+}
+
+// Restore writes the backup named [name] in [store] to [db], which must be
+// empty. The backup is verified before [db] is modified, but [db] may be
+// partially written if the backup can't be read again, so [db] should be a
+// fresh database that only replaces the restored one once Restore succeeds.
+// Returns the backup's manifest.
+func Restore(store Store, name string, db database.Database) (*Manifest, error) {
+	iter := db.NewIterator()
+	hasKeys := iter.Next()
+	err := iter.Error()
+	iter.Release()
+	if err != nil {
+		return nil, err
+	}
+	if hasKeys {
+		return nil, errDatabaseNotEmpty
+	}
+
+	manifest, err := Verify(store, name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup: %w", err)
+	}
+
+	// The backup was verified, but it is read again, so it is checked again
+	// in case it changed since
+	batch := db.NewBatch()
+	err = readBackup(store, name, manifest, func(key, value []byte) error {
+		if err := batch.Put(key, value); err != nil {
+			return err
+		}
+		if batch.Size() < restoreBatchSize {
+			return nil
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := batch.Put(restoredKey, []byte(name)); err != nil {
+		return nil, err
+	}
+	return manifest, batch.Write()
+}
+
+func readManifest(store Store, name string) (*Manifest, error) {
+	r, err := store.Open(name + manifestSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open backup manifest: %w", err)
+	}
+	defer r.Close()
+
+	manifestBytes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read backup manifest: %w", err)
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(manifestBytes, manifest); err != nil {
+		return nil, fmt.Errorf("couldn't parse backup manifest: %w", err)
+	}
+	if manifest.Version != formatVersion {
+		return nil, fmt.Errorf("%w: %d", errUnknownVersion, manifest.Version)
+	}
+	return manifest, nil
+}
+
+// readBackup calls [onPair] with each key/value pair in the data of the
+// backup named [name] in [store], and checks the data against [manifest]
+func readBackup(store Store, name string, manifest *Manifest, onPair func(key, value []byte) error) error {
+	r, err := store.Open(name + dataSuffix)
+	if err != nil {
+		return fmt.Errorf("couldn't open backup data: %w", err)
+	}
+	defer r.Close()
+
+	checksum := sha256.New()
+	numKeys, err := readData(io.TeeReader(r, checksum), onPair)
+	if err != nil {
+		return fmt.Errorf("couldn't read backup data: %w", err)
+	}
+	if numKeys != manifest.NumKeys {
+		return fmt.Errorf("%w: expected %d but got %d", errWrongNumKeys, manifest.NumKeys, numKeys)
+	}
+	expectedChecksum, err := hex.DecodeString(manifest.Checksum)
+	if err != nil || !bytes.Equal(checksum.Sum(nil), expectedChecksum) {
+		return errWrongChecksum
+	}
+	return nil
+}
+
+// writeData writes every key/value pair in [db] to [w]. Returns the number of
+// pairs written.
+func writeData(db database.Iteratee, w io.Writer) (uint64, error) {
+	zw := gzip.NewWriter(w)
+	bw := bufio.NewWriter(zw)
+
+	if err := binary.Write(bw, binary.BigEndian, formatVersion); err != nil {
+		return 0, err
+	}
+
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	numKeys := uint64(0)
+	for iter.Next() {
+		if err := bw.WriteByte(record); err != nil {
+			return numKeys, err
+		}
+		if err := writeBytes(bw, iter.Key()); err != nil {
+			return numKeys, err
+		}
+		if err := writeBytes(bw, iter.Value()); err != nil {
+			return numKeys, err
+		}
+		numKeys++
+	}
+	if err := iter.Error(); err != nil {
+		return numKeys, fmt.Errorf("couldn't iterate over the database: %w", err)
+	}
+
+	if err := bw.WriteByte(end); err != nil {
+		return numKeys, err
+	}
+	if err := binary.Write(bw, binary.BigEndian, numKeys); err != nil {
+		return numKeys, err
+	}
+	if err := bw.Flush(); err != nil {
+		return numKeys, err
+	}
+	return numKeys, zw.Close()
+}
+
+func writeBytes(w io.Writer, b []byte) error {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// readData calls [onPair] with each key/value pair in the data in [r]. Reads
+// [r] to its end. Returns the number of pairs read.
+func readData(r io.Reader, onPair func(key, value []byte) error) (uint64, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't decompress backup: %w", err)
+	}
+	defer zr.Close()
+	br := bufio.NewReader(zr)
+
+	version := uint16(0)
+	if err := binary.Read(br, binary.BigEndian, &version); err != nil {
+		return 0, err
+	}
+	if version != formatVersion {
+		return 0, fmt.Errorf("%w: %d", errUnknownVersion, version)
+	}
+
+	numKeys := uint64(0)
+	for {
+		marker, err := br.ReadByte()
+		if err != nil {
+			return numKeys, err
+		}
+		if marker == end {
+			break
+		}
+		key, err := readBytes(br)
+		if err != nil {
+			return numKeys, err
+		}
+		value, err := readBytes(br)
+		if err != nil {
+			return numKeys, err
+		}
+		if err := onPair(key, value); err != nil {
+			return numKeys, err
+		}
+		numKeys++
+	}
+
+	expectedNumKeys := uint64(0)
+	if err := binary.Read(br, binary.BigEndian, &expectedNumKeys); err != nil {
+		return numKeys, err
+	}
+	if numKeys != expectedNumKeys {
+		return numKeys, fmt.Errorf("%w: expected %d but got %d", errWrongNumKeys, expectedNumKeys, numKeys)
+	}
+	// Reading to the end of the stream verifies the checksum of the
+	// compressed data, and hashes all of it
+	if _, err := io.Copy(ioutil.Discard, br); err != nil {
+		return numKeys, err
+	}
+	_, err = io.Copy(ioutil.Discard, r)
+	return numKeys, err
+}
+
+func readBytes(r io.Reader) ([]byte, error) {
+	length := uint32(0)
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	b := make([]byte, length)
+	_, err := io.ReadFull(r, b)
+	return b, err
+}
@@ -0,0 +1,117 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func newTestDB(t *testing.T, numKeys int) database.Database {
+	db := memdb.New()
+	for i := 0; i < numKeys; i++ {
+		err := db.Put([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+		assert.NoError(t, err)
+	}
+	return db
+}
+
+func TestWriteRestore(t *testing.T) {
+	assert := assert.New(t)
+
+	store, err := NewStore(t.TempDir())
+	assert.NoError(err)
+
+	chains := []ChainFrontier{
+		{
+			ChainID:    ids.GenerateTestID(),
+			Frontier:   []ids.ID{ids.GenerateTestID()},
+			Checkpoint: []byte{1, 2, 3},
+		},
+		{
+			ChainID:  ids.GenerateTestID(),
+			Frontier: []ids.ID{ids.GenerateTestID()},
+		},
+	}
+	written, err := Write(store, "backup", newTestDB(t, 100), chains)
+	assert.NoError(err)
+	assert.Equal(uint64(100), written.NumKeys)
+
+	verified, err := Verify(store, "backup")
+	assert.NoError(err)
+	assert.Equal(written.Checksum, verified.Checksum)
+	assert.Equal(chains[0].Frontier, verified.Frontiers()[chains[0].ChainID])
+	assert.Equal(map[ids.ID][]byte{chains[0].ChainID: {1, 2, 3}}, verified.Checkpoints())
+
+	db := memdb.New()
+	restoredFrom, err := RestoredFrom(db)
+	assert.NoError(err)
+	assert.Empty(restoredFrom)
+
+	restored, err := Restore(store, "backup", db)
+	assert.NoError(err)
+	assert.Equal(written.NumKeys, restored.NumKeys)
+
+	for i := 0; i < 100; i++ {
+		value, err := db.Get([]byte(fmt.Sprintf("key%d", i)))
+		assert.NoError(err)
+		assert.Equal([]byte(fmt.Sprintf("value%d", i)), value)
+	}
+	restoredFrom, err = RestoredFrom(db)
+	assert.NoError(err)
+	assert.Equal("backup", restoredFrom)
+
+	// Backups are only restored to empty databases
+	_, err = Restore(store, "backup", db)
+	assert.ErrorIs(err, errDatabaseNotEmpty)
+}
+
+func TestRestoreCorrupted(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	assert.NoError(err)
+	_, err = Write(store, "backup", newTestDB(t, 10), nil)
+	assert.NoError(err)
+
+	path := filepath.Join(dir, "backup"+dataSuffix)
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(err)
+	data[len(data)/2]++
+	assert.NoError(ioutil.WriteFile(path, data, 0600))
+
+	// The database isn't modified if the backup is corrupted
+	db := memdb.New()
+	_, err = Restore(store, "backup", db)
+	assert.Error(err)
+	iter := db.NewIterator()
+	assert.False(iter.Next())
+	iter.Release()
+}
+
+func TestVerifyIncomplete(t *testing.T) {
+	assert := assert.New(t)
+
+	store, err := NewStore(t.TempDir())
+	assert.NoError(err)
+
+	w, err := store.Create("backup" + dataSuffix)
+	assert.NoError(err)
+	_, err = writeData(newTestDB(t, 10), w)
+	assert.NoError(err)
+	assert.NoError(w.Close())
+
+	// Without a manifest, the backup is incomplete
+	_, err = Verify(store, "backup")
+	assert.Error(err)
+}
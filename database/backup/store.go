@@ -0,0 +1,211 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+// Time allowed to connect to an http backup store, for it to respond once a
+// request was sent, and for a connection to it to go without reading or
+// writing. The whole transfer of an object isn't bounded, since backups can be
+// large.
+const httpTimeout = time.Minute
+
+var (
+	errEmptyTarget       = errors.New("backup target is empty")
+	errInvalidObjectName = errors.New("backup object names must not contain path separators")
+	errUploadStopped     = errors.New("upload stopped")
+
+	_ Store = &dirStore{}
+	_ Store = &httpStore{}
+)
+
+// Store holds the objects that backups are made of
+type Store interface {
+	// Create returns a writer of a new object named [name]. The object is
+	// complete once the writer is closed without error.
+	Create(name string) (io.WriteCloser, error)
+
+	// Open returns a reader of the object named [name]
+	Open(name string) (io.ReadCloser, error)
+}
+
+// NewStore returns the store at [target]. If [target] is an http or https
+// URL, objects are uploaded to and downloaded from it with PUT and GET
+// requests, which most object stores and their gateways support. Otherwise,
+// [target] is a directory that objects are written to as files.
+func NewStore(target string) (Store, error) {
+	if target == "" {
+		return nil, errEmptyTarget
+	}
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		baseURL, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse backup target: %w", err)
+		}
+		return &httpStore{
+			baseURL: baseURL,
+			client:  newHTTPClient(httpTimeout),
+		}, nil
+	}
+	return &dirStore{dir: target}, nil
+}
+
+func checkObjectName(name string) error {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." || strings.Contains(name, "/") {
+		return fmt.Errorf("%w: %q", errInvalidObjectName, name)
+	}
+	return nil
+}
+
+// dirStore keeps each object in a file of a directory
+type dirStore struct{ dir string }
+
+func (s *dirStore) Create(name string) (io.WriteCloser, error) {
+	if err := checkObjectName(name); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.dir, perms.ReadWriteExecute); err != nil {
+		return nil, fmt.Errorf("couldn't create backup directory: %w", err)
+	}
+	return perms.Create(filepath.Join(s.dir, name), perms.ReadWrite)
+}
+
+func (s *dirStore) Open(name string) (io.ReadCloser, error) {
+	if err := checkObjectName(name); err != nil {
+		return nil, err
+	}
+	return os.Open(filepath.Join(s.dir, name))
+}
+
+// httpStore keeps each object at a URL under a base URL
+type httpStore struct {
+	baseURL *url.URL
+	client  *http.Client
+}
+
+func (s *httpStore) objectURL(name string) (string, error) {
+	if err := checkObjectName(name); err != nil {
+		return "", err
+	}
+	objectURL := *s.baseURL
+	objectURL.Path = strings.TrimSuffix(objectURL.Path, "/") + "/" + url.PathEscape(name)
+	return objectURL.String(), nil
+}
+
+func (s *httpStore) Create(name string) (io.WriteCloser, error) {
+	objectURL, err := s.objectURL(name)
+	if err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	request, err := http.NewRequest(http.MethodPut, objectURL, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &httpWriter{
+		PipeWriter: pw,
+		done:       make(chan error, 1),
+	}
+	go func() {
+		response, err := s.client.Do(request)
+		if err == nil {
+			err = checkResponse(response)
+			_ = response.Body.Close()
+		}
+		// Unblock the writer if the upload stopped before the object was
+		// written
+		_ = pr.CloseWithError(errUploadStopped)
+		w.done <- err
+	}()
+	return w, nil
+}
+
+func (s *httpStore) Open(name string) (io.ReadCloser, error) {
+	objectURL, err := s.objectURL(name)
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.client.Get(objectURL) // #nosec G107
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(response); err != nil {
+		_ = response.Body.Close()
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+// newHTTPClient returns a client whose connections time out after [timeout]
+// without progress
+func newHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		return &idleTimeoutConn{Conn: conn, timeout: timeout}, nil
+	}
+	transport.TLSHandshakeTimeout = timeout
+	transport.ResponseHeaderTimeout = timeout
+	return &http.Client{Transport: transport}
+}
+
+// idleTimeoutConn fails reads and writes that make no progress for [timeout]
+type idleTimeoutConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *idleTimeoutConn) Read(b []byte) (int, error) {
+	if err := c.Conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *idleTimeoutConn) Write(b []byte) (int, error) {
+	if err := c.Conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(b)
+}
+
+// httpWriter uploads what is written to it. Close returns once the upload is
+// done.
+type httpWriter struct {
+	*io.PipeWriter
+	done chan error
+}
+
+func (w *httpWriter) Close() error {
+	if err := w.PipeWriter.Close(); err != nil {
+		return err
+	}
+	return <-w.done
+}
+
+func checkResponse(response *http.Response) error {
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("backup store responded with status %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// objectServer is an object store that is accessed with PUT and GET requests
+type objectServer struct {
+	lock    sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *objectServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		object, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.objects[r.URL.Path] = object
+	case http.MethodGet:
+		object, exists := s.objects[r.URL.Path]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(object)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestHTTPStore(t *testing.T) {
+	assert := assert.New(t)
+
+	objects := &objectServer{objects: make(map[string][]byte)}
+	server := httptest.NewServer(objects)
+	defer server.Close()
+
+	store, err := NewStore(server.URL + "/bucket/")
+	assert.NoError(err)
+
+	written, err := Write(store, "backup", newTestDB(t, 100), nil)
+	assert.NoError(err)
+	assert.Contains(objects.objects, "/bucket/backup"+dataSuffix)
+	assert.Contains(objects.objects, "/bucket/backup"+manifestSuffix)
+
+	verified, err := Verify(store, "backup")
+	assert.NoError(err)
+	assert.Equal(written.Checksum, verified.Checksum)
+
+	_, err = store.Open("missing")
+	assert.Error(err)
+}
+
+func TestHTTPStoreUploadFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	store, err := NewStore(server.URL)
+	assert.NoError(t, err)
+
+	w, err := store.Create("object")
+	assert.NoError(t, err)
+	_, _ = w.Write([]byte(strings.Repeat("a", 1024*1024)))
+	assert.Error(t, w.Close())
+}
+
+func TestHTTPStoreTimeout(t *testing.T) {
+	stop := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-stop
+	}))
+	defer server.Close()
+	defer close(stop)
+
+	baseURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	store := &httpStore{
+		baseURL: baseURL,
+		client:  newHTTPClient(50 * time.Millisecond),
+	}
+
+	_, err = store.Open("object")
+	assert.Error(t, err)
+
+	w, err := store.Create("object")
+	assert.NoError(t, err)
+	_, _ = w.Write([]byte("object"))
+	assert.Error(t, w.Close())
+}
+
+func TestInvalidObjectNames(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	assert.NoError(t, err)
+
+	for _, name := range []string{"", ".", "..", "../object", "dir/object"} {
+		_, err := store.Create(name)
+		assert.Error(t, err, name)
+		_, err = store.Open(name)
+		assert.Error(t, err, name)
+	}
+
+	_, err = NewStore("")
+	assert.Error(t, err)
+}
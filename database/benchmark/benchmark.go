@@ -0,0 +1,349 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package benchmark
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// Number of writes in each batch of the bootstrap workload, like the
+	// containers that are accepted together while bootstrapping
+	bootstrapBatchSize = 1024
+	// Number of writes in each batch of the steady state workload, like the
+	// state changes of an accepted block
+	steadyStateBatchSize = 64
+	// Number of distinct values that are written
+	numValues = 1024
+)
+
+var (
+	errNoKeys        = errors.New("number of keys must be positive")
+	errNoValueSize   = errors.New("value size must be positive")
+	errNoConcurrency = errors.New("concurrency must be positive")
+)
+
+// Config of a database benchmark
+type Config struct {
+	// Directory that the benchmarked database is created in. It's removed
+	// once the benchmark is done.
+	Dir string
+	// Name of the database backend that is benchmarked
+	Backend string
+	// Number of keys that are written by the bootstrap workload and read by
+	// the other workloads
+	NumKeys int
+	// Number of bytes of each value
+	ValueSize int
+	// Number of goroutines that read concurrently
+	Concurrency int
+}
+
+// Verify returns an error if the config is invalid
+func (c *Config) Verify() error {
+	switch {
+	case c.NumKeys <= 0:
+		return errNoKeys
+	case c.ValueSize <= 0:
+		return errNoValueSize
+	case c.Concurrency <= 0:
+		return errNoConcurrency
+	default:
+		return nil
+	}
+}
+
+// Result of a workload of the benchmark
+type Result struct {
+	Name string
+	// Number of operations that were timed. An operation is either a read or
+	// the write of a batch.
+	NumOps int
+	// Number of bytes that were read or written
+	NumBytes int
+	// Time that the workload took
+	Duration time.Duration
+	// Latencies of the operations
+	Median, P99, Max time.Duration
+}
+
+func (r *Result) String() string {
+	seconds := r.Duration.Seconds()
+	return fmt.Sprintf(
+		"%s: %d ops in %s (%.0f ops/s, %.2f MiB/s), latency median %s, p99 %s, max %s",
+		r.Name,
+		r.NumOps,
+		r.Duration,
+		float64(r.NumOps)/seconds,
+		float64(r.NumBytes)/seconds/(1<<20),
+		r.Median,
+		r.P99,
+		r.Max,
+	)
+}
+
+// Run creates a database with the configured backend in a new directory of
+// [config.Dir] and benchmarks it with workloads that resemble bootstrapping
+// and steady state consensus. The database is removed once it's benchmarked.
+func Run(config Config, log logging.Logger) ([]*Result, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	backend, err := manager.GetBackend(config.Backend)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(config.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("couldn't create %s: %w", config.Dir, err)
+	}
+	dir, err := ioutil.TempDir(config.Dir, "benchmark")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create benchmark directory in %s: %w", config.Dir, err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := backend(dir, "", false, log)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create %s database in %s: %w", config.Backend, dir, err)
+	}
+	defer db.Close()
+	return run(db, config)
+}
+
+// run benchmarks [db], which must be empty
+func run(db database.Database, config Config) ([]*Result, error) {
+	values := make([][]byte, numValues)
+	for i := range values {
+		values[i] = make([]byte, config.ValueSize)
+		_, _ = rand.Read(values[i]) // #nosec G404
+	}
+	w := &workloads{
+		db:          db,
+		values:      values,
+		numKeys:     config.NumKeys,
+		concurrency: config.Concurrency,
+	}
+
+	bootstrap, err := w.bootstrap()
+	if err != nil {
+		return nil, err
+	}
+	reads, err := w.randomReads(config.NumKeys)
+	if err != nil {
+		return nil, err
+	}
+	reads.Name = "random reads"
+	steadyStateReads, steadyStateWrites, err := w.steadyState()
+	if err != nil {
+		return nil, err
+	}
+	return []*Result{bootstrap, reads, steadyStateReads, steadyStateWrites}, nil
+}
+
+type workloads struct {
+	db     database.Database
+	values [][]byte
+	// Number of keys that are written by the bootstrap workload
+	numKeys     int
+	concurrency int
+}
+
+// Keys are hashes, so that they are spread over the key space like the IDs of
+// containers
+func key(i int) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(i))
+	return hashing.ComputeHash256(b)
+}
+
+func (w *workloads) value(i int) []byte { return w.values[i%len(w.values)] }
+
+// bootstrap writes [w.numKeys] keys in large batches
+func (w *workloads) bootstrap() (*Result, error) {
+	t := newTimer("bootstrap batch writes")
+	for start := 0; start < w.numKeys; start += bootstrapBatchSize {
+		end := start + bootstrapBatchSize
+		if end > w.numKeys {
+			end = w.numKeys
+		}
+		n, err := w.writeBatch(start, end)
+		if err != nil {
+			return nil, err
+		}
+		t.done(n)
+	}
+	return t.result(), nil
+}
+
+// Writes the keys from [start] up to [end] in a batch. Returns the number of
+// bytes written.
+func (w *workloads) writeBatch(start, end int) (int, error) {
+	batch := w.db.NewBatch()
+	for i := start; i < end; i++ {
+		if err := batch.Put(key(i), w.value(i)); err != nil {
+			return 0, err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return 0, fmt.Errorf("couldn't write batch: %w", err)
+	}
+	return batch.Size(), nil
+}
+
+// randomReads reads [numReads] random keys of the first [w.numKeys] keys with
+// [w.concurrency] goroutines
+func (w *workloads) randomReads(numReads int) (*Result, error) {
+	var (
+		numKeys = w.numKeys
+		timers  = make([]*timer, w.concurrency)
+		errs    = make([]error, w.concurrency)
+		wg      sync.WaitGroup
+	)
+	for r := range timers {
+		timers[r] = newTimer("")
+		wg.Add(1)
+		go func(t *timer, err *error, numReads int) {
+			defer wg.Done()
+			source := rand.New(rand.NewSource(time.Now().UnixNano())) // #nosec G404
+			for i := 0; i < numReads; i++ {
+				t.start()
+				value, getErr := w.db.Get(key(source.Intn(numKeys)))
+				if getErr != nil {
+					*err = fmt.Errorf("couldn't read key: %w", getErr)
+					return
+				}
+				t.done(len(value))
+			}
+		}(timers[r], &errs[r], (numReads+r)/w.concurrency)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return mergeTimers(timers), nil
+}
+
+// steadyState reads random keys while new keys are written in small batches,
+// until the keys have been read as many times as there are keys
+func (w *workloads) steadyState() (*Result, *Result, error) {
+	var (
+		writes   = newTimer("steady state batch writes")
+		stop     = make(chan struct{})
+		writeErr = make(chan error, 1)
+		numKeys  = w.numKeys
+	)
+	go func() {
+		next := numKeys
+		for {
+			select {
+			case <-stop:
+				writeErr <- nil
+				return
+			default:
+			}
+			writes.start()
+			n, err := w.writeBatch(next, next+steadyStateBatchSize)
+			if err != nil {
+				writeErr <- err
+				return
+			}
+			writes.done(n)
+			next += steadyStateBatchSize
+		}
+	}()
+
+	reads, readErr := w.randomReads(numKeys)
+	close(stop)
+	if err := <-writeErr; err != nil {
+		return nil, nil, err
+	}
+	if readErr != nil {
+		return nil, nil, readErr
+	}
+	reads.Name = "steady state reads"
+	return reads, writes.result(), nil
+}
+
+// timer records the latencies of the operations of a workload. It isn't safe
+// for concurrent use.
+type timer struct {
+	name      string
+	began     time.Time
+	lastStart time.Time
+	ended     time.Time
+	numBytes  int
+	latencies []time.Duration
+}
+
+func newTimer(name string) *timer {
+	now := time.Now()
+	return &timer{
+		name:      name,
+		began:     now,
+		lastStart: now,
+	}
+}
+
+// start the timing of an operation
+func (t *timer) start() { t.lastStart = time.Now() }
+
+// done records that the operation that was started last, which read or wrote
+// [numBytes] bytes, is done. The next operation is started.
+func (t *timer) done(numBytes int) {
+	t.ended = time.Now()
+	t.latencies = append(t.latencies, t.ended.Sub(t.lastStart))
+	t.numBytes += numBytes
+	t.lastStart = t.ended
+}
+
+func (t *timer) result() *Result {
+	return mergeTimers([]*timer{t})
+}
+
+// mergeTimers returns the result of the operations of [timers], which ran
+// concurrently
+func mergeTimers(timers []*timer) *Result {
+	var (
+		result    = &Result{Name: timers[0].name}
+		began     = timers[0].began
+		ended     = timers[0].ended
+		latencies []time.Duration
+	)
+	for _, t := range timers {
+		if t.began.Before(began) {
+			began = t.began
+		}
+		if t.ended.After(ended) {
+			ended = t.ended
+		}
+		result.NumBytes += t.numBytes
+		latencies = append(latencies, t.latencies...)
+	}
+	result.NumOps = len(latencies)
+	if result.NumOps == 0 {
+		return result
+	}
+	result.Duration = ended.Sub(began)
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	result.Median = latencies[len(latencies)/2]
+	result.P99 = latencies[len(latencies)*99/100]
+	result.Max = latencies[len(latencies)-1]
+	return result
+}
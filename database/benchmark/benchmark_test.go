@@ -0,0 +1,61 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package benchmark
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	config := Config{
+		Dir:         dir,
+		Backend:     manager.LevelDB,
+		NumKeys:     2*bootstrapBatchSize + 1,
+		ValueSize:   100,
+		Concurrency: 3,
+	}
+	results, err := Run(config, logging.NoLog{})
+	assert.NoError(err)
+	assert.Len(results, 4)
+
+	bootstrap := results[0]
+	assert.Equal(3, bootstrap.NumOps)
+	assert.GreaterOrEqual(bootstrap.NumBytes, config.NumKeys*config.ValueSize)
+	for _, reads := range results[1:3] {
+		assert.Equal(config.NumKeys, reads.NumOps)
+		assert.Equal(config.NumKeys*config.ValueSize, reads.NumBytes)
+		assert.LessOrEqual(reads.Median, reads.P99)
+		assert.LessOrEqual(reads.P99, reads.Max)
+	}
+
+	// The benchmarked database is removed
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(err)
+	assert.Empty(files)
+}
+
+func TestConfigVerify(t *testing.T) {
+	config := Config{
+		NumKeys:     1,
+		ValueSize:   1,
+		Concurrency: 1,
+	}
+	assert.NoError(t, config.Verify())
+
+	config.Concurrency = 0
+	assert.Equal(t, errNoConcurrency, config.Verify())
+}
@@ -0,0 +1,223 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cachedb
+
+import (
+	"container/list"
+	"hash/fnv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+const (
+	// Estimated number of bytes that an entry takes up in addition to its key
+	// and value
+	entryOverhead = 128
+
+	// Number of shards that the keys are spread over to tell whether a key
+	// was written while it was read
+	numShards = 1024
+)
+
+// Cache is a read cache of a database that is shared by views of the
+// database. Each view caches its reads in its own partition of the cache,
+// which holds at most the view's quota of bytes. The cache as a whole holds at
+// most its size. When the cache is full, the least recently used entry of any
+// partition is evicted, so the views that read the most keep the most in the
+// cache, up to their quotas.
+type Cache struct {
+	lock    sync.Mutex
+	size    int
+	maxSize int
+	// Least recently used entry at the back
+	lru *list.List
+	// Key --> the entry's element in [lru]
+	entries map[string]*list.Element
+	// Name --> partition
+	partitions map[string]*partition
+	// Incremented each time a key of the shard is written. A value read from
+	// the database is only cached if no key of its shard was written while
+	// it was read, so that a concurrent write can't be overwritten in the
+	// cache by a stale value.
+	generations [numShards]uint64
+
+	metrics metrics
+}
+
+type partition struct {
+	name  string
+	size  int
+	quota int
+	// Least recently used entry of the partition at the back
+	lru *list.List
+
+	hits, misses prometheus.Counter
+	sizeGauge    prometheus.Gauge
+}
+
+type entry struct {
+	key   string
+	value []byte
+	// False if the key isn't in the database
+	found bool
+	size  int
+
+	partition *partition
+	// The entry's element in [partition.lru]
+	partitionElement *list.Element
+}
+
+// NewCache returns a cache that holds at most [maxSize] bytes and whose
+// metrics are registered with [registerer]
+func NewCache(maxSize int, namespace string, registerer prometheus.Registerer) (*Cache, error) {
+	c := &Cache{
+		maxSize:    maxSize,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+		partitions: make(map[string]*partition),
+	}
+	return c, c.metrics.initialize(namespace, registerer)
+}
+
+// Wrap returns [db] with its writes invalidating the values cached by the
+// views of the cache. Every write to [db] must go through the returned
+// database, so that the views never read stale values. Reads of the returned
+// database aren't cached. Closing it closes [db].
+func (c *Cache) Wrap(db database.Database) *Database {
+	return &Database{
+		cache:   c,
+		db:      db,
+		closeDB: true,
+	}
+}
+
+// NewView returns a view of [db] whose reads are cached in the partition of
+// the cache named [name], which holds at most [quota] bytes. The writes to
+// [db] must go through the database returned by Wrap, with the same keys, so
+// that [db] may only add layers, such as metering, that don't change keys.
+// Closing the view doesn't close [db].
+func (c *Cache) NewView(db database.Database, name string, quota int) *Database {
+	return &Database{
+		cache:     c,
+		partition: c.getPartition(name, quota),
+		db:        db,
+	}
+}
+
+// Returns the partition named [name], creating it with quota [quota] if it
+// doesn't exist
+func (c *Cache) getPartition(name string, quota int) *partition {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if p, exists := c.partitions[name]; exists {
+		return p
+	}
+	p := &partition{
+		name:      name,
+		quota:     quota,
+		lru:       list.New(),
+		hits:      c.metrics.hits.WithLabelValues(name),
+		misses:    c.metrics.misses.WithLabelValues(name),
+		sizeGauge: c.metrics.size.WithLabelValues(name),
+	}
+	c.partitions[name] = p
+	return p
+}
+
+// get returns the cached value of [key], whether the key is in the database
+// and whether it was cached. If it wasn't cached, also returns the generation
+// of its shard, which is passed to put once the key is read from the
+// database.
+func (c *Cache) get(p *partition, key []byte) ([]byte, bool, bool, uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, exists := c.entries[string(key)]
+	if !exists {
+		p.misses.Inc()
+		return nil, false, false, c.generations[shard(key)]
+	}
+	e := element.Value.(*entry)
+	c.lru.MoveToFront(element)
+	e.partition.lru.MoveToFront(e.partitionElement)
+	p.hits.Inc()
+	return utils.CopyBytes(e.value), e.found, true, 0
+}
+
+// put caches [value] as the value of [key] in [p], unless a key of its shard
+// was written since [generation]. [found] is false if the key isn't in the
+// database.
+func (c *Cache) put(p *partition, key []byte, value []byte, found bool, generation uint64) {
+	size := len(key) + len(value) + entryOverhead
+	if size > p.quota || size > c.maxSize {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.generations[shard(key)] != generation {
+		return
+	}
+	if element, exists := c.entries[string(key)]; exists {
+		c.remove(element)
+	}
+	for p.size+size > p.quota {
+		c.remove(p.lru.Back().Value.(*list.Element))
+		c.metrics.evictions.Inc()
+	}
+	for c.size+size > c.maxSize {
+		c.remove(c.lru.Back())
+		c.metrics.evictions.Inc()
+	}
+
+	e := &entry{
+		key:       string(key),
+		value:     utils.CopyBytes(value),
+		found:     found,
+		size:      size,
+		partition: p,
+	}
+	element := c.lru.PushFront(e)
+	e.partitionElement = p.lru.PushFront(element)
+	c.entries[e.key] = element
+	c.size += size
+	p.size += size
+	p.sizeGauge.Set(float64(p.size))
+}
+
+// invalidate the cached values of [keys], which were written
+func (c *Cache) invalidate(keys ...[]byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, key := range keys {
+		c.generations[shard(key)]++
+		if element, exists := c.entries[string(key)]; exists {
+			c.remove(element)
+		}
+	}
+}
+
+// Assumes [c.lock] is held
+func (c *Cache) remove(element *list.Element) {
+	e := element.Value.(*entry)
+	c.lru.Remove(element)
+	e.partition.lru.Remove(e.partitionElement)
+	delete(c.entries, e.key)
+	c.size -= e.size
+	e.partition.size -= e.size
+	e.partition.sizeGauge.Set(float64(e.partition.size))
+}
+
+func shard(key []byte) int {
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+	return int(h.Sum32() % numShards)
+}
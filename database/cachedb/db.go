@@ -0,0 +1,245 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cachedb
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/nodb"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+var (
+	errCompactionStatsUnsupported = errors.New("compaction stats aren't supported")
+
+	_ database.Database         = &Database{}
+	_ database.Snapshotter      = &Database{}
+	_ database.CompactionStater = &Database{}
+	_ database.Batch            = &batch{}
+)
+
+// Database is a view of a database whose reads are cached in a partition of
+// a shared cache, or the database that the views are of, whose writes
+// invalidate the cached values. Iterators and snapshots aren't cached.
+type Database struct {
+	cache *Cache
+	// Nil if reads aren't cached
+	partition *partition
+	db        database.Database
+	// True if closing the view closes [db]
+	closeDB bool
+	closed  utils.AtomicBool
+}
+
+// Has implements the database.Database interface. Only keys that aren't in
+// the database are cached, since the value of a key isn't read.
+func (db *Database) Has(key []byte) (bool, error) {
+	if db.closed.GetValue() {
+		return false, database.ErrClosed
+	}
+	if db.partition == nil {
+		return db.db.Has(key)
+	}
+	_, found, cached, generation := db.cache.get(db.partition, key)
+	if cached {
+		return found, nil
+	}
+	has, err := db.db.Has(key)
+	if err == nil && !has {
+		db.cache.put(db.partition, key, nil, false, generation)
+	}
+	return has, err
+}
+
+// Get implements the database.Database interface
+func (db *Database) Get(key []byte) ([]byte, error) {
+	if db.closed.GetValue() {
+		return nil, database.ErrClosed
+	}
+	if db.partition == nil {
+		return db.db.Get(key)
+	}
+	value, found, cached, generation := db.cache.get(db.partition, key)
+	if cached {
+		if !found {
+			return nil, database.ErrNotFound
+		}
+		return value, nil
+	}
+	value, err := db.db.Get(key)
+	switch err {
+	case nil:
+		db.cache.put(db.partition, key, value, true, generation)
+	case database.ErrNotFound:
+		db.cache.put(db.partition, key, nil, false, generation)
+	}
+	return value, err
+}
+
+// Put implements the database.Database interface
+func (db *Database) Put(key, value []byte) error {
+	if db.closed.GetValue() {
+		return database.ErrClosed
+	}
+	err := db.db.Put(key, value)
+	db.cache.invalidate(key)
+	return err
+}
+
+// Delete implements the database.Database interface
+func (db *Database) Delete(key []byte) error {
+	if db.closed.GetValue() {
+		return database.ErrClosed
+	}
+	err := db.db.Delete(key)
+	db.cache.invalidate(key)
+	return err
+}
+
+// NewBatch implements the database.Database interface
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		Batch: db.db.NewBatch(),
+		db:    db,
+	}
+}
+
+// NewIterator implements the database.Database interface
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart implements the database.Database interface
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the database.Database interface
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the database.Database interface
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	if db.closed.GetValue() {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	return db.db.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+// Stat implements the database.Database interface
+func (db *Database) Stat(property string) (string, error) {
+	if db.closed.GetValue() {
+		return "", database.ErrClosed
+	}
+	return db.db.Stat(property)
+}
+
+// Compact implements the database.Database interface
+func (db *Database) Compact(start, limit []byte) error {
+	if db.closed.GetValue() {
+		return database.ErrClosed
+	}
+	return db.db.Compact(start, limit)
+}
+
+// NewSnapshot returns a snapshot of the underlying database, if it takes
+// snapshots
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	snapshotter, ok := db.db.(database.Snapshotter)
+	if !ok {
+		return nil, database.ErrSnapshotsUnsupported
+	}
+	return snapshotter.NewSnapshot()
+}
+
+// CompactionStats returns the compaction stats of the underlying database, if
+// it reports them
+func (db *Database) CompactionStats() (database.CompactionStats, error) {
+	stater, ok := db.db.(database.CompactionStater)
+	if !ok {
+		return database.CompactionStats{}, errCompactionStatsUnsupported
+	}
+	return stater.CompactionStats()
+}
+
+// Close implements the database.Database interface
+func (db *Database) Close() error {
+	if db.closed.GetValue() {
+		return database.ErrClosed
+	}
+	db.closed.SetValue(true)
+	if !db.closeDB {
+		return nil
+	}
+	return db.db.Close()
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch invalidates the cached values of its keys when it's written. Its
+// writes are kept so that the batch can be replayed, and it's its own inner
+// batch, so that it's also written when it's replayed into another batch of
+// the database.
+type batch struct {
+	database.Batch
+
+	db     *Database
+	writes []keyValue
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), utils.CopyBytes(value), false})
+	return b.Batch.Put(key, value)
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), nil, true})
+	return b.Batch.Delete(key)
+}
+
+func (b *batch) Write() error {
+	if b.db.closed.GetValue() {
+		return database.ErrClosed
+	}
+	err := b.Batch.Write()
+	keys := make([][]byte, len(b.writes))
+	for i, keyvalue := range b.writes {
+		keys[i] = keyvalue.key
+	}
+	b.db.cache.invalidate(keys...)
+	return err
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	if cap(b.writes) > len(b.writes)*database.MaxExcessCapacityFactor {
+		b.writes = make([]keyValue, 0, cap(b.writes)/database.CapacityReductionFactor)
+	} else {
+		b.writes = b.writes[:0]
+	}
+	b.Batch.Reset()
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w database.KeyValueWriter) error {
+	for _, keyvalue := range b.writes {
+		if keyvalue.delete {
+			if err := w.Delete(keyvalue.key); err != nil {
+				return err
+			}
+		} else if err := w.Put(keyvalue.key, keyvalue.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
@@ -0,0 +1,139 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cachedb
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func newTestDB(t *testing.T, maxSize int) (*Cache, *Database) {
+	cache, err := NewCache(maxSize, "", prometheus.NewRegistry())
+	assert.NoError(t, err)
+	return cache, cache.Wrap(memdb.New())
+}
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		_, db := newTestDB(t, 1<<20)
+		test(t, db)
+	}
+}
+
+func TestViewInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		cache, db := newTestDB(t, 1<<20)
+		test(t, cache.NewView(db, "chain", 1<<20))
+	}
+}
+
+func TestCachedReads(t *testing.T) {
+	assert := assert.New(t)
+
+	cache, db := newTestDB(t, 1<<20)
+	view := cache.NewView(db, "chain", 1<<20)
+	assert.NoError(db.db.Put([]byte("key"), []byte("value")))
+
+	value, err := view.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+	_, err = view.Get([]byte("missing"))
+	assert.Equal(database.ErrNotFound, err)
+
+	// Both reads are now served by the cache
+	value, err = view.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+	has, err := view.Has([]byte("missing"))
+	assert.NoError(err)
+	assert.False(has)
+	assert.EqualValues(2, testutil.ToFloat64(view.partition.hits))
+	assert.EqualValues(2, testutil.ToFloat64(view.partition.misses))
+
+	// A write through the wrapped database invalidates the cached value
+	assert.NoError(db.Put([]byte("key"), []byte("new value")))
+	value, err = view.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("new value"), value)
+}
+
+func TestReplayedBatchInvalidates(t *testing.T) {
+	assert := assert.New(t)
+
+	cache, db := newTestDB(t, 1<<20)
+	view := cache.NewView(db, "chain", 1<<20)
+	assert.NoError(db.Put([]byte("key"), []byte("value")))
+	_, err := view.Get([]byte("key"))
+	assert.NoError(err)
+
+	// Like an atomic write of several chains' batches
+	viewBatch := view.NewBatch()
+	assert.NoError(viewBatch.Put([]byte("key"), []byte("new value")))
+	baseBatch := db.NewBatch().Inner()
+	assert.NoError(viewBatch.Inner().Replay(baseBatch))
+	assert.NoError(baseBatch.Write())
+
+	value, err := view.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("new value"), value)
+}
+
+func TestPartitionQuota(t *testing.T) {
+	assert := assert.New(t)
+
+	entrySize := len("key0") + len("value") + entryOverhead
+	cache, db := newTestDB(t, 4*entrySize)
+	busy := cache.NewView(db, "busy", 2*entrySize)
+	quiet := cache.NewView(db, "quiet", 4*entrySize)
+
+	for _, key := range []string{"key0", "key1", "key2", "key3"} {
+		assert.NoError(db.Put([]byte(key), []byte("value")))
+	}
+	_, err := quiet.Get([]byte("key0"))
+	assert.NoError(err)
+	for _, key := range []string{"key1", "key2", "key3"} {
+		_, err := busy.Get([]byte(key))
+		assert.NoError(err)
+	}
+
+	// The busy partition evicted its own entry instead of the quiet one's
+	assert.Equal(2*entrySize, busy.partition.size)
+	assert.Equal(entrySize, quiet.partition.size)
+	assert.Contains(cache.entries, "key0")
+	assert.NotContains(cache.entries, "key1")
+
+	// When the cache is full, the least recently used entry is evicted
+	assert.NoError(db.Put([]byte("key4"), []byte("value")))
+	_, err = quiet.Get([]byte("key4"))
+	assert.NoError(err)
+	_, err = quiet.Get([]byte("key1"))
+	assert.NoError(err)
+	assert.Equal(4*entrySize, cache.size)
+	assert.NotContains(cache.entries, "key0")
+}
+
+func TestConcurrentWriteIsNotOverwritten(t *testing.T) {
+	assert := assert.New(t)
+
+	cache, db := newTestDB(t, 1<<20)
+	view := cache.NewView(db, "chain", 1<<20)
+	assert.NoError(db.Put([]byte("key"), []byte("value")))
+
+	// The key is written after it's read from the database but before the
+	// read value is cached
+	_, _, cached, generation := cache.get(view.partition, []byte("key"))
+	assert.False(cached)
+	assert.NoError(db.Put([]byte("key"), []byte("new value")))
+	cache.put(view.partition, []byte("key"), []byte("value"), true, generation)
+
+	value, err := view.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("new value"), value)
+}
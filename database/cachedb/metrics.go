@@ -0,0 +1,48 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cachedb
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+type metrics struct {
+	hits, misses *prometheus.CounterVec
+	size         *prometheus.GaugeVec
+	evictions    prometheus.Counter
+}
+
+func (m *metrics) initialize(namespace string, registerer prometheus.Registerer) error {
+	m.hits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hits",
+		Help:      "Number of reads of the partition that were served by the cache",
+	}, []string{"partition"})
+	m.misses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "misses",
+		Help:      "Number of reads of the partition that weren't served by the cache",
+	}, []string{"partition"})
+	m.size = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "size",
+		Help:      "Estimated number of bytes that the partition takes up in the cache",
+	}, []string{"partition"})
+	m.evictions = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "evictions",
+		Help:      "Number of cached values that were evicted to make room for others",
+	})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.hits),
+		registerer.Register(m.misses),
+		registerer.Register(m.size),
+		registerer.Register(m.evictions),
+	)
+	return errs.Err
+}
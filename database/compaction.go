@@ -0,0 +1,20 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+// CompactionStats describe how much compaction a database is behind on
+type CompactionStats struct {
+	// Estimated number of bytes that compactions need to write before the
+	// database is compacted
+	Debt uint64
+	// Number of sorted runs that a read may need to check
+	ReadAmplification uint64
+}
+
+// CompactionStater is implemented by databases that can report their
+// compaction stats
+type CompactionStater interface {
+	// CompactionStats returns the current compaction stats of the database
+	CompactionStats() (CompactionStats, error)
+}
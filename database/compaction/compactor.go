@@ -0,0 +1,211 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compaction
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+// How often the schedule is checked for whether a compaction is due
+const checkFreq = time.Minute
+
+var errCompactionInProgress = errors.New("a compaction is already in progress")
+
+// Config describes when the database is compacted automatically
+type Config struct {
+	// How often the database is compacted. If 0, the database is only
+	// compacted when requested.
+	Freq time.Duration
+	// Scheduled compactions only start in this window
+	Window Window
+}
+
+// Status of the compactions of a database
+type Status struct {
+	// True if a compaction is in progress
+	Compacting bool
+	// Time the last compaction finished. Zero if there hasn't been one.
+	LastCompaction time.Time
+	// Duration of the last compaction
+	LastDuration time.Duration
+	// Error of the last compaction, if it failed
+	LastErr error
+	// Nil if the database doesn't report its compaction stats
+	Stats *database.CompactionStats
+}
+
+// Compactor compacts a database, or the state of one of its chains, on
+// request and on a schedule. Only one compaction runs at a time.
+type Compactor struct {
+	log    logging.Logger
+	db     database.Database
+	config Config
+	clock  timer.Clock
+
+	metrics metrics
+
+	lock           sync.Mutex
+	compacting     bool
+	lastCompaction time.Time
+	lastDuration   time.Duration
+	lastErr        error
+
+	// Dispatch returns when closer is closed
+	closer chan struct{}
+}
+
+// New returns a compactor of [db] whose metrics are registered with
+// [registerer]
+func New(
+	log logging.Logger,
+	db database.Database,
+	config Config,
+	namespace string,
+	registerer prometheus.Registerer,
+) (*Compactor, error) {
+	c := &Compactor{
+		log:    log,
+		db:     db,
+		config: config,
+		closer: make(chan struct{}),
+	}
+	return c, c.metrics.initialize(namespace, registerer, db)
+}
+
+// Compact the keys of the database that start with [prefix], as it is applied
+// by prefixdb. If [prefix] is nil, the whole database is compacted. Blocks
+// until the compaction is done.
+func (c *Compactor) Compact(prefix []byte) error {
+	if err := c.begin(); err != nil {
+		return err
+	}
+	return c.compact(prefix)
+}
+
+// CompactAsync starts compacting the keys of the database that start with
+// [prefix], as Compact does, and returns without waiting for the compaction to
+// finish.
+func (c *Compactor) CompactAsync(prefix []byte) error {
+	if err := c.begin(); err != nil {
+		return err
+	}
+	go c.log.RecoverAndPanic(func() { _ = c.compact(prefix) })
+	return nil
+}
+
+// Status returns the status of the compactions of the database
+func (c *Compactor) Status() Status {
+	c.lock.Lock()
+	status := Status{
+		Compacting:     c.compacting,
+		LastCompaction: c.lastCompaction,
+		LastDuration:   c.lastDuration,
+		LastErr:        c.lastErr,
+	}
+	c.lock.Unlock()
+
+	if stater, ok := c.db.(database.CompactionStater); ok {
+		if stats, err := stater.CompactionStats(); err == nil {
+			status.Stats = &stats
+		}
+	}
+	return status
+}
+
+// Dispatch compacts the whole database every [Freq], in [Window], until
+// Shutdown is called. Compactions that are requested reset the schedule.
+func (c *Compactor) Dispatch() {
+	if c.config.Freq == 0 {
+		return
+	}
+	c.lock.Lock()
+	// The first scheduled compaction is one period after the node starts
+	c.lastCompaction = c.clock.Time()
+	c.lock.Unlock()
+
+	t := time.NewTicker(checkFreq)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-c.closer:
+			return
+		case <-t.C:
+			c.compactIfDue()
+		}
+	}
+}
+
+// Shutdown stops the scheduled compactions. A compaction that is in progress
+// is finished.
+func (c *Compactor) Shutdown() { close(c.closer) }
+
+// compactIfDue compacts the whole database if a scheduled compaction is due
+func (c *Compactor) compactIfDue() {
+	now := c.clock.Time()
+
+	c.lock.Lock()
+	due := !c.compacting &&
+		now.Sub(c.lastCompaction) >= c.config.Freq &&
+		c.config.Window.Contains(now)
+	if due {
+		c.compacting = true
+	}
+	c.lock.Unlock()
+
+	if !due {
+		return
+	}
+	c.log.Info("starting scheduled compaction of the database")
+	_ = c.compact(nil)
+}
+
+// begin marks that a compaction is in progress
+func (c *Compactor) begin() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.compacting {
+		return errCompactionInProgress
+	}
+	c.compacting = true
+	return nil
+}
+
+// compact the keys with [prefix]. Assumes begin was called.
+func (c *Compactor) compact(prefix []byte) error {
+	db := c.db
+	if prefix != nil {
+		db = prefixdb.New(prefix, c.db)
+	}
+
+	start := c.clock.Time()
+	err := db.Compact(nil, nil)
+	end := c.clock.Time()
+	duration := end.Sub(start)
+
+	c.lock.Lock()
+	c.compacting = false
+	c.lastCompaction = end
+	c.lastDuration = duration
+	c.lastErr = err
+	c.lock.Unlock()
+
+	c.metrics.observe(duration, err)
+	if err != nil {
+		c.log.Error("compaction failed after %s with %s", duration, err)
+		return err
+	}
+	c.log.Info("compaction finished in %s", duration)
+	return nil
+}
@@ -0,0 +1,117 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compaction
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/mockdb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestCompactInProgress(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	db := mockdb.New()
+	db.OnCompact = func([]byte, []byte) error {
+		close(started)
+		<-finish
+		return nil
+	}
+
+	c, err := New(logging.NoLog{}, db, Config{}, "", prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.CompactAsync(nil))
+	<-started
+	assert.True(t, c.Status().Compacting)
+
+	err = c.Compact(nil)
+	assert.True(t, errors.Is(err, errCompactionInProgress))
+
+	close(finish)
+	assert.Eventually(t, func() bool { return !c.Status().Compacting }, time.Second, time.Millisecond)
+	assert.NoError(t, c.Status().LastErr)
+}
+
+func TestCompactPrefix(t *testing.T) {
+	var compactedStart []byte
+	db := mockdb.New()
+	db.OnCompact = func(start, _ []byte) error {
+		compactedStart = start
+		return nil
+	}
+
+	c, err := New(logging.NoLog{}, db, Config{}, "", prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.Compact(nil))
+	assert.Nil(t, compactedStart)
+
+	// The keys of a prefixdb start with the hash of its prefix
+	assert.NoError(t, c.Compact([]byte("chain")))
+	assert.Len(t, compactedStart, 32)
+}
+
+func TestScheduledCompaction(t *testing.T) {
+	numCompactions := 0
+	db := mockdb.New()
+	db.OnCompact = func([]byte, []byte) error {
+		numCompactions++
+		return nil
+	}
+
+	config := Config{
+		Freq:   24 * time.Hour,
+		Window: Window{Start: 2 * time.Hour, End: 4 * time.Hour},
+	}
+	c, err := New(logging.NoLog{}, db, config, "", prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	now := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+	c.clock.Set(now)
+	c.lastCompaction = now
+
+	// Not due yet
+	c.clock.Set(now.Add(14 * time.Hour))
+	c.compactIfDue()
+	assert.Equal(t, 0, numCompactions)
+
+	// Due, but outside of the window
+	c.clock.Set(now.Add(25 * time.Hour))
+	c.compactIfDue()
+	assert.Equal(t, 0, numCompactions)
+
+	// Due and inside of the window
+	c.clock.Set(now.Add(39 * time.Hour))
+	c.compactIfDue()
+	assert.Equal(t, 1, numCompactions)
+
+	// Not due again until a period after the last compaction
+	c.clock.Set(now.Add(40 * time.Hour))
+	c.compactIfDue()
+	assert.Equal(t, 1, numCompactions)
+}
+
+func TestCompactFailed(t *testing.T) {
+	errTest := errors.New("non-nil error")
+	db := mockdb.New()
+	db.OnCompact = func([]byte, []byte) error { return errTest }
+
+	c, err := New(logging.NoLog{}, db, Config{}, "", prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	err = c.Compact(nil)
+	assert.True(t, errors.Is(err, errTest))
+	status := c.Status()
+	assert.False(t, status.Compacting)
+	assert.True(t, errors.Is(status.LastErr, errTest))
+	// mockdb doesn't report compaction stats
+	assert.Nil(t, status.Stats)
+}
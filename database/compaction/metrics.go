@@ -0,0 +1,81 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compaction
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+type metrics struct {
+	compactions, failedCompactions prometheus.Counter
+	duration                       prometheus.Gauge
+}
+
+func (m *metrics) initialize(namespace string, registerer prometheus.Registerer, db database.Database) error {
+	m.compactions = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "compactions",
+		Help:      "Number of compactions of the database that finished",
+	})
+	m.failedCompactions = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "failed_compactions",
+		Help:      "Number of compactions of the database that failed",
+	})
+	m.duration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_compaction_duration",
+		Help:      "Time the last compaction of the database took, in seconds",
+	})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.compactions),
+		registerer.Register(m.failedCompactions),
+		registerer.Register(m.duration),
+	)
+
+	// Only some databases report their compaction stats
+	if stater, ok := db.(database.CompactionStater); ok {
+		errs.Add(
+			registerer.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "compaction_debt",
+				Help:      "Estimated number of bytes that compactions need to write before the database is compacted",
+			}, func() float64 {
+				stats, err := stater.CompactionStats()
+				if err != nil {
+					return 0
+				}
+				return float64(stats.Debt)
+			})),
+			registerer.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "read_amplification",
+				Help:      "Number of sorted runs that a read of the database may need to check",
+			}, func() float64 {
+				stats, err := stater.CompactionStats()
+				if err != nil {
+					return 0
+				}
+				return float64(stats.ReadAmplification)
+			})),
+		)
+	}
+	return errs.Err
+}
+
+func (m *metrics) observe(duration time.Duration, err error) {
+	if err != nil {
+		m.failedCompactions.Inc()
+		return
+	}
+	m.compactions.Inc()
+	m.duration.Set(duration.Seconds())
+}
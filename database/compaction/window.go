@@ -0,0 +1,77 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compaction
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+var errInvalidWindow = errors.New("window must be of the form HH:MM-HH:MM")
+
+// Window is a daily time range, in UTC. The range may wrap past midnight. An
+// empty window, where Start == End, contains every time.
+type Window struct {
+	// Offsets from midnight
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseWindow parses a window of the form "HH:MM-HH:MM". The empty string is
+// parsed as the empty window.
+func ParseWindow(s string) (Window, error) {
+	if s == "" {
+		return Window{}, nil
+	}
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return Window{}, fmt.Errorf("%w: %q", errInvalidWindow, s)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(parts[0]))
+	if err != nil {
+		return Window{}, fmt.Errorf("%w: %q", errInvalidWindow, s)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(parts[1]))
+	if err != nil {
+		return Window{}, fmt.Errorf("%w: %q", errInvalidWindow, s)
+	}
+	return Window{
+		Start: sinceMidnight(start),
+		End:   sinceMidnight(end),
+	}, nil
+}
+
+// Contains returns true if [t] is in the window
+func (w Window) Contains(t time.Time) bool {
+	offset := sinceMidnight(t.UTC())
+	switch {
+	case w.Start == w.End:
+		return true
+	case w.Start < w.End:
+		return w.Start <= offset && offset < w.End
+	default:
+		// The window wraps past midnight
+		return w.Start <= offset || offset < w.End
+	}
+}
+
+func (w Window) String() string {
+	if w.Start == w.End {
+		return "any time"
+	}
+	return fmt.Sprintf("%s-%s UTC", formatOffset(w.Start), formatOffset(w.End))
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+func formatOffset(offset time.Duration) string {
+	offset %= day
+	return fmt.Sprintf("%02d:%02d", int(offset/time.Hour), int(offset%time.Hour/time.Minute))
+}
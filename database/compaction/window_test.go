@@ -0,0 +1,52 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compaction
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWindow(t *testing.T) {
+	window, err := ParseWindow("")
+	assert.NoError(t, err)
+	assert.Equal(t, Window{}, window)
+
+	window, err = ParseWindow("02:30-04:00")
+	assert.NoError(t, err)
+	assert.Equal(t, Window{Start: 2*time.Hour + 30*time.Minute, End: 4 * time.Hour}, window)
+	assert.Equal(t, "02:30-04:00 UTC", window.String())
+
+	for _, s := range []string{"02:30", "2:30-4:00-5:00", "25:00-04:00", "02:30-noon"} {
+		_, err := ParseWindow(s)
+		assert.True(t, errors.Is(err, errInvalidWindow), "parsed invalid window %q", s)
+	}
+}
+
+func TestWindowContains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2021, time.June, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	anyTime := Window{}
+	assert.True(t, anyTime.Contains(at(0, 0)))
+	assert.True(t, anyTime.Contains(at(23, 59)))
+
+	night := Window{Start: 2 * time.Hour, End: 4 * time.Hour}
+	assert.False(t, night.Contains(at(1, 59)))
+	assert.True(t, night.Contains(at(2, 0)))
+	assert.True(t, night.Contains(at(3, 59)))
+	assert.False(t, night.Contains(at(4, 0)))
+
+	wrapping := Window{Start: 22 * time.Hour, End: 2 * time.Hour}
+	assert.True(t, wrapping.Contains(at(23, 0)))
+	assert.True(t, wrapping.Contains(at(1, 0)))
+	assert.False(t, wrapping.Contains(at(12, 0)))
+
+	// Times are compared in UTC
+	assert.True(t, night.Contains(at(3, 0).In(time.FixedZone("UTC+5", 5*60*60))))
+}
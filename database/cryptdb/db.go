@@ -0,0 +1,511 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cryptdb
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/nodb"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+const (
+	formatVersion = byte(0)
+
+	// Number of bytes before the ciphertext of an encrypted value: the format
+	// version, the fingerprint of the key and the nonce
+	headerLen = 1 + fingerprintLen + chacha20poly1305.NonceSizeX
+
+	// Number of bytes re-encrypted at a time when the key is rotated
+	rotationBatchSize = 4 * 1024 * 1024
+)
+
+var (
+	// markerKey maps to an encrypted value in every encrypted database. It
+	// tells encrypted databases apart from unencrypted ones, and lets the keys
+	// be checked when the database is opened.
+	markerKey   = []byte("cryptdb marker")
+	markerValue = []byte("encrypted")
+
+	errUnencryptedData   = errors.New("database holds unencrypted data, so encryption can only be enabled on an empty database")
+	errUnknownKey        = errors.New("value is encrypted with an unknown key")
+	errUnknownFormat     = errors.New("unknown encrypted value format")
+	errInvalidCiphertext = errors.New("encrypted value is too short")
+
+	_ database.Database    = &Database{}
+	_ database.Snapshotter = &Database{}
+	_ database.Batch       = &batch{}
+	_ database.Snapshot    = &snapshot{}
+)
+
+// Database encrypts the values of an underlying database with the keys of a
+// keyring. Keys aren't encrypted. Each value is bound to its key, so values
+// can't be swapped between keys without being detected.
+type Database struct {
+	lock    sync.RWMutex
+	keyring *Keyring
+	db      database.Database
+}
+
+// New returns a database that encrypts the values of [db] with [keyring]. If
+// [db] is empty, it is marked as encrypted. Returns an error if [db] holds
+// unencrypted data, or if it was encrypted with a key that isn't in
+// [keyring].
+func New(keyring *Keyring, db database.Database) (*Database, error) {
+	encDB := &Database{
+		keyring: keyring,
+		db:      db,
+	}
+
+	encMarker, err := db.Get(markerKey)
+	switch err {
+	case nil:
+		// Make sure that the database can be decrypted
+		if _, err := encDB.decrypt(markerKey, encMarker); err != nil {
+			return nil, fmt.Errorf("couldn't decrypt database: %w", err)
+		}
+		return encDB, nil
+	case database.ErrNotFound:
+	default:
+		return nil, err
+	}
+
+	iter := db.NewIterator()
+	hasData := iter.Next()
+	err = iter.Error()
+	iter.Release()
+	if err != nil {
+		return nil, err
+	}
+	if hasData {
+		return nil, errUnencryptedData
+	}
+
+	encMarker, err = encDB.encrypt(markerKey, markerValue)
+	if err != nil {
+		return nil, err
+	}
+	return encDB, db.Put(markerKey, encMarker)
+}
+
+// IsEncrypted returns true if [db] was encrypted by a Database
+func IsEncrypted(db database.Database) (bool, error) { return db.Has(markerKey) }
+
+// Has implements the Database interface
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return false, database.ErrClosed
+	}
+	return db.db.Has(key)
+}
+
+// Get implements the Database interface
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+	encValue, err := db.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return db.decrypt(key, encValue)
+}
+
+// Put implements the Database interface
+func (db *Database) Put(key, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	encValue, err := db.encrypt(key, value)
+	if err != nil {
+		return err
+	}
+	return db.db.Put(key, encValue)
+}
+
+// Delete implements the Database interface
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	return db.db.Delete(key)
+}
+
+// NewBatch implements the Database interface
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		Batch: db.db.NewBatch(),
+		db:    db,
+	}
+}
+
+// NewIterator implements the Database interface
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart implements the Database interface
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the Database interface
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the Database interface
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	return &iterator{
+		Iterator: db.db.NewIteratorWithStartAndPrefix(start, prefix),
+		db:       db,
+	}
+}
+
+// Stat implements the Database interface
+func (db *Database) Stat(stat string) (string, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return "", database.ErrClosed
+	}
+	return db.db.Stat(stat)
+}
+
+// Compact implements the Database interface
+func (db *Database) Compact(start, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	return db.db.Compact(start, limit)
+}
+
+// NewSnapshot returns a snapshot of the underlying database, if it takes
+// snapshots, whose values are decrypted when they are read
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+	snapshotter, ok := db.db.(database.Snapshotter)
+	if !ok {
+		return nil, database.ErrSnapshotsUnsupported
+	}
+	snap, err := snapshotter.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{
+		Snapshot: snap,
+		db:       db,
+	}, nil
+}
+
+// Close implements the Database interface. The underlying database is closed.
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	err := db.db.Close()
+	db.db = nil
+	return err
+}
+
+// Rotate re-encrypts with the current key every value that is encrypted with
+// a previous key. Once it returns, the previous keys are no longer needed.
+// Returns the number of values that were re-encrypted.
+func (db *Database) Rotate() (uint64, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return 0, database.ErrClosed
+	}
+
+	iter := db.db.NewIterator()
+	defer iter.Release()
+
+	batch := db.db.NewBatch()
+	numRotated := uint64(0)
+	for iter.Next() {
+		key := iter.Key()
+		encValue := iter.Value()
+		if len(encValue) >= headerLen && fingerprintOf(encValue) == db.keyring.current {
+			continue
+		}
+		value, err := db.decrypt(key, encValue)
+		if err != nil {
+			return numRotated, fmt.Errorf("couldn't decrypt value of key 0x%x: %w", key, err)
+		}
+		encValue, err = db.encrypt(key, value)
+		if err != nil {
+			return numRotated, err
+		}
+		if err := batch.Put(key, encValue); err != nil {
+			return numRotated, err
+		}
+		numRotated++
+
+		if batch.Size() < rotationBatchSize {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return numRotated, err
+		}
+		batch.Reset()
+	}
+	if err := iter.Error(); err != nil {
+		return numRotated, err
+	}
+	return numRotated, batch.Write()
+}
+
+// Encrypt writes every key/value pair of the unencrypted database [src] to
+// this database, encrypted with the current key. This is how an existing
+// database is encrypted, since encryption can only be enabled on an empty
+// database. Returns the number of values that were encrypted.
+func (db *Database) Encrypt(src database.Iteratee) (uint64, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return 0, database.ErrClosed
+	}
+
+	iter := src.NewIterator()
+	defer iter.Release()
+
+	batch := db.db.NewBatch()
+	numEncrypted := uint64(0)
+	for iter.Next() {
+		key := iter.Key()
+		encValue, err := db.encrypt(key, iter.Value())
+		if err != nil {
+			return numEncrypted, err
+		}
+		if err := batch.Put(key, encValue); err != nil {
+			return numEncrypted, err
+		}
+		numEncrypted++
+
+		if batch.Size() < rotationBatchSize {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return numEncrypted, err
+		}
+		batch.Reset()
+	}
+	if err := iter.Error(); err != nil {
+		return numEncrypted, err
+	}
+	return numEncrypted, batch.Write()
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+type batch struct {
+	database.Batch
+
+	db     *Database
+	writes []keyValue
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), utils.CopyBytes(value), false})
+	encValue, err := b.db.encrypt(key, value)
+	if err != nil {
+		return err
+	}
+	return b.Batch.Put(key, encValue)
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), nil, true})
+	return b.Batch.Delete(key)
+}
+
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.db == nil {
+		return database.ErrClosed
+	}
+	return b.Batch.Write()
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	if cap(b.writes) > len(b.writes)*database.MaxExcessCapacityFactor {
+		b.writes = make([]keyValue, 0, cap(b.writes)/database.CapacityReductionFactor)
+	} else {
+		b.writes = b.writes[:0]
+	}
+	b.Batch.Reset()
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w database.KeyValueWriter) error {
+	for _, keyvalue := range b.writes {
+		if keyvalue.delete {
+			if err := w.Delete(keyvalue.key); err != nil {
+				return err
+			}
+		} else if err := w.Put(keyvalue.key, keyvalue.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
+type snapshot struct {
+	database.Snapshot
+	db *Database
+}
+
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	encValue, err := s.Snapshot.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.db.decrypt(key, encValue)
+}
+
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return &iterator{
+		Iterator: s.Snapshot.NewIteratorWithStartAndPrefix(start, prefix),
+		db:       s.db,
+	}
+}
+
+type iterator struct {
+	database.Iterator
+	db *Database
+
+	key []byte
+	val []byte
+	err error
+}
+
+func (it *iterator) Next() bool {
+	for it.Iterator.Next() {
+		key := it.Iterator.Key()
+		// The marker isn't part of the database's contents
+		if string(key) == string(markerKey) {
+			continue
+		}
+		val, err := it.db.decrypt(key, it.Iterator.Value())
+		if err != nil {
+			it.err = err
+			it.key = nil
+			it.val = nil
+			return false
+		}
+		it.key = key
+		it.val = val
+		return true
+	}
+	it.key = nil
+	it.val = nil
+	return false
+}
+
+func (it *iterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Error()
+}
+
+func (it *iterator) Key() []byte { return it.key }
+
+func (it *iterator) Value() []byte { return it.val }
+
+func fingerprintOf(encValue []byte) fingerprint {
+	f := fingerprint{}
+	copy(f[:], encValue[1:1+fingerprintLen])
+	return f
+}
+
+// encrypt [value] of [key] with the current key
+func (db *Database) encrypt(key, value []byte) ([]byte, error) {
+	encValue := make([]byte, headerLen, headerLen+len(value)+db.keyring.ciphers[db.keyring.current].Overhead())
+	encValue[0] = formatVersion
+	copy(encValue[1:], db.keyring.current[:])
+	nonce := encValue[1+fingerprintLen : headerLen]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return db.keyring.ciphers[db.keyring.current].Seal(encValue, nonce, value, key), nil
+}
+
+// decrypt [encValue] of [key] with the key that it was encrypted with
+func (db *Database) decrypt(key, encValue []byte) ([]byte, error) {
+	if len(encValue) < headerLen {
+		return nil, errInvalidCiphertext
+	}
+	if encValue[0] != formatVersion {
+		return nil, fmt.Errorf("%w: %d", errUnknownFormat, encValue[0])
+	}
+	f := fingerprintOf(encValue)
+	aead, exists := db.keyring.ciphers[f]
+	if !exists {
+		return nil, fmt.Errorf("%w with fingerprint %s", errUnknownKey, f)
+	}
+	nonce := encValue[1+fingerprintLen : headerLen]
+	return aead.Open(nil, nonce, encValue[headerLen:], key)
+}
@@ -0,0 +1,198 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cryptdb
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func newTestKey(t testing.TB) []byte {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newTestKeyring(t testing.TB, current []byte, previous ...[]byte) *Keyring {
+	keyring, err := NewKeyring(current, previous...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyring
+}
+
+func TestInterface(t *testing.T) {
+	keyring := newTestKeyring(t, newTestKey(t))
+	for _, test := range database.Tests {
+		db, err := New(keyring, memdb.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		test(t, db)
+	}
+}
+
+func TestValuesAreEncrypted(t *testing.T) {
+	assert := assert.New(t)
+
+	baseDB := memdb.New()
+	db, err := New(newTestKeyring(t, newTestKey(t)), baseDB)
+	assert.NoError(err)
+
+	key := []byte("hello")
+	value := []byte("world")
+	assert.NoError(db.Put(key, value))
+
+	encValue, err := baseDB.Get(key)
+	assert.NoError(err)
+	assert.NotContains(string(encValue), string(value))
+
+	encrypted, err := IsEncrypted(baseDB)
+	assert.NoError(err)
+	assert.True(encrypted)
+
+	// A value moved to another key doesn't decrypt
+	assert.NoError(baseDB.Put([]byte("other"), encValue))
+	_, err = db.Get([]byte("other"))
+	assert.Error(err)
+}
+
+func TestReopen(t *testing.T) {
+	assert := assert.New(t)
+
+	key := newTestKey(t)
+	baseDB := memdb.New()
+	db, err := New(newTestKeyring(t, key), baseDB)
+	assert.NoError(err)
+	assert.NoError(db.Put([]byte("hello"), []byte("world")))
+
+	// The database can be opened again with the same key
+	db, err = New(newTestKeyring(t, key), baseDB)
+	assert.NoError(err)
+	value, err := db.Get([]byte("hello"))
+	assert.NoError(err)
+	assert.Equal([]byte("world"), value)
+
+	// but not with another key
+	_, err = New(newTestKeyring(t, newTestKey(t)), baseDB)
+	assert.True(errors.Is(err, errUnknownKey))
+}
+
+func TestUnencryptedData(t *testing.T) {
+	baseDB := memdb.New()
+	assert.NoError(t, baseDB.Put([]byte("hello"), []byte("world")))
+
+	_, err := New(newTestKeyring(t, newTestKey(t)), baseDB)
+	assert.True(t, errors.Is(err, errUnencryptedData))
+
+	encrypted, err := IsEncrypted(baseDB)
+	assert.NoError(t, err)
+	assert.False(t, encrypted)
+}
+
+func TestEncrypt(t *testing.T) {
+	assert := assert.New(t)
+
+	src := memdb.New()
+	assert.NoError(src.Put([]byte("hello"), []byte("world")))
+	assert.NoError(src.Put([]byte("foo"), []byte("bar")))
+
+	key := newTestKey(t)
+	baseDB := memdb.New()
+	db, err := New(newTestKeyring(t, key), baseDB)
+	assert.NoError(err)
+	numEncrypted, err := db.Encrypt(src)
+	assert.NoError(err)
+	assert.Equal(uint64(2), numEncrypted)
+
+	stored, err := baseDB.Get([]byte("hello"))
+	assert.NoError(err)
+	assert.NotEqual([]byte("world"), stored)
+
+	db, err = New(newTestKeyring(t, key), baseDB)
+	assert.NoError(err)
+	value, err := db.Get([]byte("hello"))
+	assert.NoError(err)
+	assert.Equal([]byte("world"), value)
+	value, err = db.Get([]byte("foo"))
+	assert.NoError(err)
+	assert.Equal([]byte("bar"), value)
+}
+
+func TestRotate(t *testing.T) {
+	assert := assert.New(t)
+
+	oldKey := newTestKey(t)
+	newKey := newTestKey(t)
+	baseDB := memdb.New()
+
+	db, err := New(newTestKeyring(t, oldKey), baseDB)
+	assert.NoError(err)
+	assert.NoError(db.Put([]byte("hello"), []byte("world")))
+	assert.NoError(db.Put([]byte("foo"), []byte("bar")))
+
+	db, err = New(newTestKeyring(t, newKey, oldKey), baseDB)
+	assert.NoError(err)
+	// Values written with the old key can still be read
+	value, err := db.Get([]byte("hello"))
+	assert.NoError(err)
+	assert.Equal([]byte("world"), value)
+
+	// The two values and the marker are re-encrypted
+	numRotated, err := db.Rotate()
+	assert.NoError(err)
+	assert.Equal(uint64(3), numRotated)
+
+	numRotated, err = db.Rotate()
+	assert.NoError(err)
+	assert.Equal(uint64(0), numRotated)
+
+	// The old key is no longer needed
+	db, err = New(newTestKeyring(t, newKey), baseDB)
+	assert.NoError(err)
+	value, err = db.Get([]byte("foo"))
+	assert.NoError(err)
+	assert.Equal([]byte("bar"), value)
+}
+
+func TestNewKeyring(t *testing.T) {
+	key := newTestKey(t)
+
+	_, err := NewKeyring(key[1:])
+	assert.True(t, errors.Is(err, errInvalidKeySize))
+
+	_, err = NewKeyring(key, key)
+	assert.True(t, errors.Is(err, errDuplicatedKey))
+}
+
+func BenchmarkInterface(b *testing.B) {
+	keyring := newTestKeyring(b, newTestKey(b))
+	for _, size := range database.BenchmarkSizes {
+		keys, values := database.SetupBenchmark(b, size, size)
+		for _, bench := range database.Benchmarks {
+			db, err := New(keyring, memdb.New())
+			if err != nil {
+				b.Fatal(err)
+			}
+			bench(b, db, "cryptdb", keys, values)
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	db, err := New(newTestKeyring(t, newTestKey(t)), memdb.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.TestSnapshot(t, db)
+}
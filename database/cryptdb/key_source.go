@@ -0,0 +1,106 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cryptdb
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Prefixes of the sources that keys can be loaded from
+const (
+	FileKeySource = "file:"
+	EnvKeySource  = "env:"
+	ExecKeySource = "exec:"
+
+	// How long a command that prints a key may run for
+	execKeyTimeout = 30 * time.Second
+)
+
+var (
+	errUnknownKeySource = fmt.Errorf("key source must start with one of %q, %q or %q", FileKeySource, EnvKeySource, ExecKeySource)
+	errEmptyKeySource   = errors.New("key source is empty")
+)
+
+// LoadKey loads a hex encoded key from [source], which is one of:
+//   - "file:<path>", a file that holds the key
+//   - "env:<name>", an environment variable that holds the key
+//   - "exec:<command>", a command that prints the key, such as a client of a
+//     key management service. The command is split on whitespace and isn't
+//     run in a shell.
+func LoadKey(source string) ([]byte, error) {
+	var (
+		encodedKey string
+		err        error
+	)
+	switch {
+	case strings.HasPrefix(source, FileKeySource):
+		encodedKey, err = loadFileKey(strings.TrimPrefix(source, FileKeySource))
+	case strings.HasPrefix(source, EnvKeySource):
+		encodedKey, err = loadEnvKey(strings.TrimPrefix(source, EnvKeySource))
+	case strings.HasPrefix(source, ExecKeySource):
+		encodedKey, err = loadExecKey(strings.TrimPrefix(source, ExecKeySource))
+	default:
+		return nil, errUnknownKeySource
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode key: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, errInvalidKeySize
+	}
+	return key, nil
+}
+
+func loadFileKey(path string) (string, error) {
+	if path == "" {
+		return "", errEmptyKeySource
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("couldn't read key file: %w", err)
+	}
+	return string(b), nil
+}
+
+func loadEnvKey(name string) (string, error) {
+	if name == "" {
+		return "", errEmptyKeySource
+	}
+	value, exists := os.LookupEnv(name)
+	if !exists {
+		return "", fmt.Errorf("environment variable %s isn't set", name)
+	}
+	return value, nil
+}
+
+func loadExecKey(command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", errEmptyKeySource
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), execKeyTimeout)
+	defer cancel()
+
+	// #nosec G204
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("couldn't run key command: %w", err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,48 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cryptdb
+
+import (
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadKey(t *testing.T) {
+	assert := assert.New(t)
+
+	key := newTestKey(t)
+	encodedKey := hex.EncodeToString(key)
+
+	path := filepath.Join(t.TempDir(), "key")
+	assert.NoError(ioutil.WriteFile(path, []byte(encodedKey+"\n"), 0600))
+	loadedKey, err := LoadKey(FileKeySource + path)
+	assert.NoError(err)
+	assert.Equal(key, loadedKey)
+
+	const envName = "CRYPTDB_TEST_KEY"
+	assert.NoError(os.Setenv(envName, encodedKey))
+	defer os.Unsetenv(envName)
+	loadedKey, err = LoadKey(EnvKeySource + envName)
+	assert.NoError(err)
+	assert.Equal(key, loadedKey)
+
+	loadedKey, err = LoadKey(ExecKeySource + "echo " + encodedKey)
+	assert.NoError(err)
+	assert.Equal(key, loadedKey)
+
+	_, err = LoadKey(encodedKey)
+	assert.True(errors.Is(err, errUnknownKeySource))
+
+	_, err = LoadKey(EnvKeySource)
+	assert.True(errors.Is(err, errEmptyKeySource))
+
+	_, err = LoadKey(ExecKeySource + "echo " + encodedKey[2:])
+	assert.True(errors.Is(err, errInvalidKeySize))
+}
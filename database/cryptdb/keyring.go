@@ -0,0 +1,74 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cryptdb
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+const (
+	// KeySize is the number of bytes in an encryption key
+	KeySize = chacha20poly1305.KeySize
+
+	fingerprintLen = 4
+)
+
+var (
+	errInvalidKeySize = fmt.Errorf("encryption keys must be %d bytes", KeySize)
+	errDuplicatedKey  = errors.New("duplicated encryption key")
+)
+
+// fingerprint identifies the key that a value was encrypted with, without
+// revealing the key
+type fingerprint [fingerprintLen]byte
+
+func (f fingerprint) String() string { return fmt.Sprintf("%x", f[:]) }
+
+// Keyring holds the key that values are encrypted with, and the previous keys
+// that values written before the last key rotations may still be encrypted
+// with
+type Keyring struct {
+	current fingerprint
+	ciphers map[fingerprint]cipher.AEAD
+}
+
+// NewKeyring returns a keyring that encrypts with [current] and decrypts with
+// [current] and [previous]
+func NewKeyring(current []byte, previous ...[]byte) (*Keyring, error) {
+	k := &Keyring{ciphers: make(map[fingerprint]cipher.AEAD, 1+len(previous))}
+	currentFingerprint, err := k.add(current)
+	if err != nil {
+		return nil, err
+	}
+	k.current = currentFingerprint
+	for _, key := range previous {
+		if _, err := k.add(key); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+func (k *Keyring) add(key []byte) (fingerprint, error) {
+	if len(key) != KeySize {
+		return fingerprint{}, errInvalidKeySize
+	}
+	f := fingerprint{}
+	copy(f[:], hashing.ComputeHash256(key))
+	if _, exists := k.ciphers[f]; exists {
+		return fingerprint{}, fmt.Errorf("%w with fingerprint %s", errDuplicatedKey, f)
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return fingerprint{}, err
+	}
+	k.ciphers[f] = aead
+	return f, nil
+}
@@ -10,4 +10,9 @@ var (
 	ErrClosed          = errors.New("closed")
 	ErrNotFound        = errors.New("not found")
 	ErrAvoidCorruption = errors.New("closed to avoid possible corruption")
+	ErrReadOnly        = errors.New("read-only")
+
+	// ErrSnapshotsUnsupported is returned by databases that wrap a database
+	// that doesn't take snapshots
+	ErrSnapshotsUnsupported = errors.New("snapshots aren't supported")
 )
@@ -5,6 +5,7 @@ package leveldb
 
 import (
 	"bytes"
+	"math"
 	"sync/atomic"
 
 	"github.com/syndtr/goleveldb/leveldb"
@@ -12,6 +13,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
 	"github.com/syndtr/goleveldb/leveldb/util"
 
 	"github.com/ava-labs/avalanchego/database"
@@ -38,8 +40,11 @@ const (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ database.Batch    = &batch{}
+	_ database.Database         = &Database{}
+	_ database.CompactionStater = &Database{}
+	_ database.Snapshotter      = &Database{}
+	_ database.Batch            = &batch{}
+	_ database.Snapshot         = &snapshot{}
 )
 
 // Database is a persistent key-value store. Apart from basic data storage
@@ -47,7 +52,9 @@ var (
 // in binary-alphabetical order.
 type Database struct {
 	*leveldb.DB
-	log logging.Logger
+	// Closed after [DB] is closed
+	storage storage.Storage
+	log     logging.Logger
 
 	// 1 if there was previously an error other than "not found" or "closed"
 	// while performing a db operation. If [errored] == 1, Has, Get, Put,
@@ -57,6 +64,13 @@ type Database struct {
 
 // New returns a wrapped LevelDB object.
 func New(file string, log logging.Logger, blockCacheSize, writeBufferSize, handleCap int) (*Database, error) {
+	return NewWithWAL(file, "", log, blockCacheSize, writeBufferSize, handleCap)
+}
+
+// NewWithWAL returns a wrapped LevelDB object whose journal, which is its
+// write-ahead log, is kept in the directory [walDir] instead of in [file], if
+// [walDir] isn't empty.
+func NewWithWAL(file, walDir string, log logging.Logger, blockCacheSize, writeBufferSize, handleCap int) (*Database, error) {
 	// Enforce minimums
 	if blockCacheSize < minBlockCacheSize {
 		blockCacheSize = minBlockCacheSize
@@ -68,8 +82,12 @@ func New(file string, log logging.Logger, blockCacheSize, writeBufferSize, handl
 		handleCap = minHandleCap
 	}
 
+	stor, err := openStorage(file, walDir, false)
+	if err != nil {
+		return nil, err
+	}
 	// Open the db and recover any potential corruptions
-	db, err := leveldb.OpenFile(file, &opt.Options{
+	db, err := leveldb.Open(stor, &opt.Options{
 		OpenFilesCacheCapacity: handleCap,
 		BlockCacheCapacity:     blockCacheSize,
 		// There are two buffers of size WriteBuffer used.
@@ -77,14 +95,56 @@ func New(file string, log logging.Logger, blockCacheSize, writeBufferSize, handl
 		Filter:      filter.NewBloomFilter(10),
 	})
 	if _, corrupted := err.(*errors.ErrCorrupted); corrupted {
-		db, err = leveldb.RecoverFile(file, nil)
+		db, err = leveldb.Recover(stor, nil)
+	}
+	if err != nil {
+		_ = stor.Close()
+		return nil, err
+	}
+	return &Database{
+		DB:      db,
+		storage: stor,
+		log:     log,
+	}, nil
+}
+
+// NewReadOnly returns a wrapped LevelDB object of the existing database in
+// [file], which is opened read-only. Writes to the returned database fail.
+func NewReadOnly(file string, log logging.Logger, blockCacheSize, handleCap int) (*Database, error) {
+	return NewReadOnlyWithWAL(file, "", log, blockCacheSize, handleCap)
+}
+
+// NewReadOnlyWithWAL is like NewReadOnly for a database whose journal is
+// kept in the directory [walDir], if it isn't empty
+func NewReadOnlyWithWAL(file, walDir string, log logging.Logger, blockCacheSize, handleCap int) (*Database, error) {
+	// Enforce minimums
+	if blockCacheSize < minBlockCacheSize {
+		blockCacheSize = minBlockCacheSize
+	}
+	if handleCap < minHandleCap {
+		handleCap = minHandleCap
 	}
+
+	stor, err := openStorage(file, walDir, true)
 	if err != nil {
 		return nil, err
 	}
+	// Corruptions aren't recovered, since that would modify the database
+	db, err := leveldb.Open(stor, &opt.Options{
+		OpenFilesCacheCapacity: handleCap,
+		BlockCacheCapacity:     blockCacheSize,
+		Filter:                 filter.NewBloomFilter(10),
+		ErrorIfMissing:         true,
+		ReadOnly:               true,
+	})
+	if err != nil {
+		_ = stor.Close()
+		return nil, err
+	}
 	return &Database{
-		DB:  db,
-		log: log,
+		DB:      db,
+		storage: stor,
+		log:     log,
 	}, nil
 }
 
@@ -175,8 +235,60 @@ func (db *Database) Compact(start []byte, limit []byte) error {
 	return db.handleError(db.DB.CompactRange(util.Range{Start: start, Limit: limit}))
 }
 
+// CompactionStats implements the CompactionStater interface. LevelDB doesn't
+// track its compaction debt, so it is estimated as the number of bytes by
+// which each level exceeds its target size.
+func (db *Database) CompactionStats() (database.CompactionStats, error) {
+	stats := leveldb.DBStats{}
+	if err := db.DB.Stats(&stats); err != nil {
+		return database.CompactionStats{}, db.handleError(err)
+	}
+
+	compactionStats := database.CompactionStats{}
+	for level, size := range stats.LevelSizes {
+		numTables := 0
+		if level < len(stats.LevelTablesCounts) {
+			numTables = stats.LevelTablesCounts[level]
+		}
+		if level == 0 {
+			// Every table in level 0 may need to be checked by a read
+			compactionStats.ReadAmplification += uint64(numTables)
+			if numTables >= opt.DefaultCompactionL0Trigger {
+				compactionStats.Debt += uint64(size)
+			}
+			continue
+		}
+		if numTables > 0 {
+			compactionStats.ReadAmplification++
+		}
+		targetSize := float64(opt.DefaultCompactionTotalSize) * math.Pow(opt.DefaultCompactionTotalSizeMultiplier, float64(level-1))
+		if excess := float64(size) - targetSize; excess > 0 {
+			compactionStats.Debt += uint64(excess)
+		}
+	}
+	return compactionStats, nil
+}
+
+// NewSnapshot implements the Snapshotter interface
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	snap, err := db.DB.GetSnapshot()
+	if err != nil {
+		return nil, db.handleError(err)
+	}
+	return &snapshot{
+		snap: snap,
+		db:   db,
+	}, nil
+}
+
 // Close implements the Database interface
-func (db *Database) Close() error { return db.handleError(db.DB.Close()) }
+func (db *Database) Close() error {
+	err := db.DB.Close()
+	if err == nil {
+		err = db.storage.Close()
+	}
+	return db.handleError(err)
+}
 
 func (db *Database) corrupted() bool {
 	return atomic.LoadUint64(&db.errored) == 1
@@ -265,6 +377,51 @@ func (r *replayer) Delete(key []byte) {
 	r.err = r.writer.Delete(key)
 }
 
+// snapshot is a wrapper around a levelDB snapshot
+type snapshot struct {
+	snap *leveldb.Snapshot
+	db   *Database
+}
+
+// Has implements the Snapshot interface
+func (s *snapshot) Has(key []byte) (bool, error) {
+	has, err := s.snap.Has(key, nil)
+	return has, s.db.handleError(err)
+}
+
+// Get implements the Snapshot interface
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	value, err := s.snap.Get(key, nil)
+	return value, s.db.handleError(err)
+}
+
+// NewIterator implements the Snapshot interface
+func (s *snapshot) NewIterator() database.Iterator {
+	return &iter{s.snap.NewIterator(new(util.Range), nil)}
+}
+
+// NewIteratorWithStart implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return &iter{s.snap.NewIterator(&util.Range{Start: start}, nil)}
+}
+
+// NewIteratorWithPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return &iter{s.snap.NewIterator(util.BytesPrefix(prefix), nil)}
+}
+
+// NewIteratorWithStartAndPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	iterRange := util.BytesPrefix(prefix)
+	if bytes.Compare(start, prefix) == 1 {
+		iterRange.Start = start
+	}
+	return &iter{s.snap.NewIterator(iterRange, nil)}
+}
+
+// Release implements the Snapshot interface
+func (s *snapshot) Release() { s.snap.Release() }
+
 type iter struct{ iterator.Iterator }
 
 // Error implements the Iterator interface
@@ -4,6 +4,7 @@
 package leveldb
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/ava-labs/avalanchego/database"
@@ -43,3 +44,73 @@ func BenchmarkInterface(b *testing.B) {
 		}
 	}
 }
+
+func TestCompactionStats(t *testing.T) {
+	db, err := New(t.TempDir(), logging.NoLog{}, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for i := 0; i < 1024; i++ {
+		if err := db.Put([]byte{byte(i), byte(i >> 8)}, make([]byte, 1024)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.CompactionStats(); err != nil {
+		t.Fatalf("CompactionStats errored with %s", err)
+	}
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := db.CompactionStats()
+	if err != nil {
+		t.Fatalf("CompactionStats errored with %s", err)
+	}
+	if stats.Debt != 0 {
+		t.Fatalf("expected no compaction debt after compacting but got %d", stats.Debt)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	db, err := New(t.TempDir(), logging.NoLog{}, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.TestSnapshot(t, db)
+}
+
+func TestReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewReadOnly(filepath.Join(dir, "missing"), logging.NoLog{}, 0, 0); err == nil {
+		t.Fatal("opening a missing database read-only should have failed")
+	}
+
+	db, err := New(dir, logging.NoLog{}, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = NewReadOnly(dir, logging.NoLog{}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	value, err := db.Get([]byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != "value" {
+		t.Fatalf("expected %q but got %q", "value", value)
+	}
+	if err := db.Put([]byte("key"), []byte("other")); err == nil {
+		t.Fatal("writing to a read-only database should have failed")
+	}
+}
@@ -0,0 +1,116 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package leveldb
+
+import (
+	"os"
+
+	"github.com/syndtr/goleveldb/leveldb/storage"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var _ storage.Storage = &walStorage{}
+
+// Returns the storage of the database in [dir]. If [walDir] isn't empty, the
+// journals of the database are kept in it.
+func openStorage(dir, walDir string, readOnly bool) (storage.Storage, error) {
+	stor, err := storage.OpenFile(dir, readOnly)
+	if err != nil || walDir == "" {
+		return stor, err
+	}
+	wal, err := storage.OpenFile(walDir, readOnly)
+	if err != nil {
+		_ = stor.Close()
+		return nil, err
+	}
+	return &walStorage{
+		Storage: stor,
+		wal:     wal,
+	}, nil
+}
+
+// walStorage keeps the journals of the database, which are its write-ahead
+// log, in [wal] and its other files in [Storage]. Journals that were written
+// to [Storage] before the journals were moved are still read and removed from
+// there, so that their writes aren't lost.
+type walStorage struct {
+	storage.Storage
+	wal storage.Storage
+}
+
+func (s *walStorage) Lock() (storage.Locker, error) {
+	lock, err := s.Storage.Lock()
+	if err != nil {
+		return nil, err
+	}
+	walLock, err := s.wal.Lock()
+	if err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	return &walLocker{
+		lock:    lock,
+		walLock: walLock,
+	}, nil
+}
+
+func (s *walStorage) List(ft storage.FileType) ([]storage.FileDesc, error) {
+	fds, err := s.Storage.List(ft)
+	if err != nil || ft&storage.TypeJournal == 0 {
+		return fds, err
+	}
+	walFDs, err := s.wal.List(storage.TypeJournal)
+	if err != nil {
+		return nil, err
+	}
+	return append(fds, walFDs...), nil
+}
+
+func (s *walStorage) Open(fd storage.FileDesc) (storage.Reader, error) {
+	if fd.Type != storage.TypeJournal {
+		return s.Storage.Open(fd)
+	}
+	r, err := s.wal.Open(fd)
+	if os.IsNotExist(err) {
+		return s.Storage.Open(fd)
+	}
+	return r, err
+}
+
+func (s *walStorage) Create(fd storage.FileDesc) (storage.Writer, error) {
+	if fd.Type != storage.TypeJournal {
+		return s.Storage.Create(fd)
+	}
+	return s.wal.Create(fd)
+}
+
+func (s *walStorage) Remove(fd storage.FileDesc) error {
+	if fd.Type != storage.TypeJournal {
+		return s.Storage.Remove(fd)
+	}
+	err := s.wal.Remove(fd)
+	if os.IsNotExist(err) {
+		return s.Storage.Remove(fd)
+	}
+	return err
+}
+
+func (s *walStorage) Close() error {
+	errs := wrappers.Errs{}
+	errs.Add(
+		s.wal.Close(),
+		s.Storage.Close(),
+	)
+	return errs.Err
+}
+
+type walLocker struct {
+	lock, walLock storage.Locker
+}
+
+func (l *walLocker) Unlock() {
+	l.walLock.Unlock()
+	l.lock.Unlock()
+}
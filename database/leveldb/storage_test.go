@@ -0,0 +1,73 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package leveldb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestWALInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		db, err := NewWithWAL(t.TempDir(), t.TempDir(), logging.NoLog{}, 0, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The database may have been closed by the test, so we don't care if it
+		// errors here.
+		defer db.Close()
+
+		test(t, db)
+	}
+}
+
+func TestJournalIsInWALDir(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	walDir := t.TempDir()
+	db, err := NewWithWAL(dir, walDir, logging.NoLog{}, 0, 0, 0)
+	assert.NoError(err)
+	assert.NoError(db.Put([]byte("key"), []byte("value")))
+	assert.NoError(db.Close())
+
+	journals, err := filepath.Glob(filepath.Join(walDir, "*.log"))
+	assert.NoError(err)
+	assert.NotEmpty(journals)
+	journals, err = filepath.Glob(filepath.Join(dir, "*.log"))
+	assert.NoError(err)
+	assert.Empty(journals)
+
+	// The write, which is only in the journal, is replayed
+	db, err = NewReadOnlyWithWAL(dir, walDir, logging.NoLog{}, 0, 0)
+	assert.NoError(err)
+	value, err := db.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+	assert.NoError(db.Close())
+}
+
+func TestJournalMovedToWALDir(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	db, err := New(dir, logging.NoLog{}, 0, 0, 0)
+	assert.NoError(err)
+	assert.NoError(db.Put([]byte("key"), []byte("value")))
+	assert.NoError(db.Close())
+
+	// The journal that was written to [dir] is still replayed
+	db, err = NewWithWAL(dir, t.TempDir(), logging.NoLog{}, 0, 0, 0)
+	assert.NoError(err)
+	value, err := db.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+	assert.NoError(db.Close())
+}
@@ -0,0 +1,87 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/database/pebbledb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// Names of the database backends that are registered by default
+const (
+	LevelDB  = "leveldb"
+	PebbleDB = "pebbledb"
+)
+
+var (
+	errUnknownBackend    = errors.New("unknown database backend")
+	errDuplicatedBackend = errors.New("duplicated database backend")
+
+	backendsLock sync.RWMutex
+	backends     = map[string]Backend{
+		LevelDB: func(path, walPath string, readOnly bool, log logging.Logger) (database.Database, error) {
+			if readOnly {
+				return leveldb.NewReadOnlyWithWAL(path, walPath, log, 0, 0)
+			}
+			return leveldb.NewWithWAL(path, walPath, log, 0, 0, 0)
+		},
+		PebbleDB: func(path, walPath string, readOnly bool, log logging.Logger) (database.Database, error) {
+			if readOnly {
+				return pebbledb.NewReadOnlyWithWAL(path, walPath, log, 0, 0)
+			}
+			return pebbledb.NewWithWAL(path, walPath, log, 0, 0, 0)
+		},
+	}
+)
+
+// Backend opens the database in the directory [path], creating it if it
+// doesn't exist. If [walPath] isn't empty, the database keeps its
+// write-ahead log in the directory [walPath] instead of in [path]. If
+// [readOnly], the database must already exist and writes to it fail.
+type Backend func(path, walPath string, readOnly bool, log logging.Logger) (database.Database, error)
+
+// RegisterBackend makes [backend] available under [name]. Returns an error if
+// a backend is already registered under [name].
+func RegisterBackend(name string, backend Backend) error {
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+
+	if _, exists := backends[name]; exists {
+		return fmt.Errorf("%w: %s", errDuplicatedBackend, name)
+	}
+	backends[name] = backend
+	return nil
+}
+
+// GetBackend returns the backend registered under [name]
+func GetBackend(name string) (Backend, error) {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+
+	backend, exists := backends[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %q", errUnknownBackend, name)
+	}
+	return backend, nil
+}
+
+// Backends returns the names of the registered backends in sorted order
+func Backends() []string {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,172 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+const (
+	// Number of blocks written by each iteration of the bootstrap benchmark
+	benchmarkBootstrapBlocks = 1024
+	// Number of key/value pairs in the database in the steady-state benchmark
+	benchmarkSteadyStateKeys = 16 * 1024
+	// Size of the values written by the benchmarks, which is about the size
+	// of a block
+	benchmarkValueSize = 1024
+	// Number of bytes written before a batch is flushed while bootstrapping
+	benchmarkBatchSize = 256 * 1024
+)
+
+func TestRegisterBackend(t *testing.T) {
+	backend := func(string, string, bool, logging.Logger) (database.Database, error) { return memdb.New(), nil }
+
+	err := RegisterBackend(LevelDB, backend)
+	assert.True(t, errors.Is(err, errDuplicatedBackend))
+
+	_, err = GetBackend("testdb")
+	assert.True(t, errors.Is(err, errUnknownBackend))
+
+	err = RegisterBackend("testdb", backend)
+	assert.NoError(t, err)
+	defer func() {
+		backendsLock.Lock()
+		delete(backends, "testdb")
+		backendsLock.Unlock()
+	}()
+
+	_, err = GetBackend("testdb")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{LevelDB, PebbleDB, "testdb"}, Backends())
+}
+
+func TestNewUnknownBackend(t *testing.T) {
+	_, err := New(t.TempDir(), "", "testdb", logging.NoLog{}, version.DefaultVersion1_0_0, true)
+	assert.True(t, errors.Is(err, errUnknownBackend))
+}
+
+func TestNewEachBackend(t *testing.T) {
+	key := []byte("hello")
+	value := []byte("world")
+
+	for _, name := range Backends() {
+		dir := t.TempDir()
+
+		manager, err := New(dir, "", name, logging.NoLog{}, version.DefaultVersion1_0_0, true)
+		if err != nil {
+			t.Fatalf("couldn't create %s manager: %s", name, err)
+		}
+		assert.NoError(t, manager.Current().Database.Put(key, value))
+		assert.NoError(t, manager.Close())
+
+		// The database should be persisted
+		manager, err = New(dir, "", name, logging.NoLog{}, version.DefaultVersion1_0_0, true)
+		if err != nil {
+			t.Fatalf("couldn't reopen %s manager: %s", name, err)
+		}
+		got, err := manager.Current().Database.Get(key)
+		assert.NoError(t, err)
+		assert.Equal(t, value, got)
+		assert.NoError(t, manager.Close())
+	}
+}
+
+// BenchmarkBackendBootstrap measures how long each backend takes to write
+// blocks the way they're written while bootstrapping: in large batches of
+// random keys that are each written once.
+func BenchmarkBackendBootstrap(b *testing.B) {
+	for _, name := range Backends() {
+		b.Run(name, func(b *testing.B) {
+			db := openBenchmarkDB(b, name)
+			defer db.Close()
+
+			keys, values := database.SetupBenchmark(b, benchmarkBootstrapBlocks, benchmarkValueSize)
+			b.SetBytes(benchmarkBootstrapBlocks * 2 * benchmarkValueSize)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// Each iteration writes new blocks
+				keys[0][0] = byte(i)
+				keys[0][1] = byte(i >> 8)
+
+				batch := db.NewBatch()
+				for j, key := range keys {
+					if err := batch.Put(key, values[j]); err != nil {
+						b.Fatal(err)
+					}
+					if batch.Size() < benchmarkBatchSize {
+						continue
+					}
+					if err := batch.Write(); err != nil {
+						b.Fatal(err)
+					}
+					batch.Reset()
+				}
+				if err := batch.Write(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkBackendSteadyState measures how long each backend takes to serve
+// the operations of a bootstrapped node: mostly reads of random keys, mixed
+// with small writes.
+func BenchmarkBackendSteadyState(b *testing.B) {
+	for _, name := range Backends() {
+		b.Run(name, func(b *testing.B) {
+			db := openBenchmarkDB(b, name)
+			defer db.Close()
+
+			keys, values := database.SetupBenchmark(b, benchmarkSteadyStateKeys, benchmarkValueSize)
+			batch := db.NewBatch()
+			for i, key := range keys {
+				if err := batch.Put(key, values[i]); err != nil {
+					b.Fatal(err)
+				}
+			}
+			if err := batch.Write(); err != nil {
+				b.Fatal(err)
+			}
+
+			r := rand.New(rand.NewSource(0)) // #nosec G404
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				index := r.Intn(len(keys))
+				// One in ten operations is a write
+				if i%10 == 0 {
+					if err := db.Put(keys[index], values[i%len(values)]); err != nil {
+						b.Fatal(err)
+					}
+					continue
+				}
+				if _, err := db.Get(keys[index]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func openBenchmarkDB(b *testing.B, name string) database.Database {
+	backend, err := GetBackend(name)
+	if err != nil {
+		b.Fatal(err)
+	}
+	db, err := backend(b.TempDir(), "", false, logging.NoLog{})
+	if err != nil {
+		b.Fatal(fmt.Errorf("couldn't open %s: %w", name, err))
+	}
+	return db
+}
@@ -12,7 +12,8 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
-	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/cryptdb"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/database/meterdb"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
@@ -62,6 +63,11 @@ type Manager interface {
 	// Note: calling this more than once with the same [namespace] will cause a
 	// conflict error for the [registerer].
 	NewCompleteMeterDBManager(namespace string, registerer prometheus.Registerer) (Manager, error)
+
+	// NewEncryptedDBManager returns a new database manager whose current
+	// database, and previous databases that were encrypted, encrypt their
+	// values with [keyring].
+	NewEncryptedDBManager(keyring *cryptdb.Keyring) (Manager, error)
 }
 
 type manager struct {
@@ -72,17 +78,44 @@ type manager struct {
 }
 
 // New creates a database manager at [filePath] by creating a database instance from each directory
-// with a version <= [currentVersion]. If [includePreviousVersions], opens previous database
-// versions and includes them in the returned Manager.
+// with a version <= [currentVersion]. The databases are opened with the backend registered
+// under [backendName]. If [walDirPath] isn't empty, each database keeps its
+// write-ahead log in the directory of its version in [walDirPath]. If
+// [includePreviousVersions], opens previous database versions and includes
+// them in the returned Manager.
 func New(
 	dbDirPath string,
+	walDirPath string,
+	backendName string,
 	log logging.Logger,
 	currentVersion version.Version,
 	includePreviousVersions bool,
 ) (Manager, error) {
+	backend, err := GetBackend(backendName)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithBackend(dbDirPath, walDirPath, backend, log, currentVersion, includePreviousVersions)
+}
+
+// NewWithBackend is like New, but the databases are opened with [backend],
+// which needn't be registered. Database replacements that were interrupted are
+// recovered before the databases are opened.
+func NewWithBackend(
+	dbDirPath string,
+	walDirPath string,
+	backend Backend,
+	log logging.Logger,
+	currentVersion version.Version,
+	includePreviousVersions bool,
+) (Manager, error) {
+	if err := RecoverReplacements(dbDirPath, walDirPath, log); err != nil {
+		return nil, fmt.Errorf("couldn't recover interrupted database replacements in %s: %w", dbDirPath, err)
+	}
+
 	parser := version.NewDefaultParser()
 	currentDBPath := filepath.Join(dbDirPath, currentVersion.String())
-	currentDB, err := leveldb.New(currentDBPath, log, 0, 0, 0)
+	currentDB, err := openDB(backend, currentDBPath, walPath(walDirPath, currentVersion), false, log)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create db at %s: %w", currentDBPath, err)
 	}
@@ -134,7 +167,7 @@ func New(
 			return filepath.SkipDir
 		}
 
-		db, err := leveldb.New(path, log, 0, 0, 0)
+		db, err := openDB(backend, path, walPath(walDirPath, version), false, log)
 		if err != nil {
 			return fmt.Errorf("couldn't create db at %s: %w", path, err)
 		}
@@ -186,6 +219,19 @@ func NewManagerFromDBs(dbs []*VersionedDatabase) (Manager, error) {
 	}, nil
 }
 
+// NewManagerWithCurrent returns a manager of the databases of [m] with the
+// current database replaced by [current]
+func NewManagerWithCurrent(m Manager, current database.Database) (Manager, error) {
+	dbs := m.GetDatabases()
+	newDBs := make([]*VersionedDatabase, len(dbs))
+	copy(newDBs[1:], dbs[1:])
+	newDBs[0] = &VersionedDatabase{
+		Database: current,
+		Version:  dbs[0].Version,
+	}
+	return NewManagerFromDBs(newDBs)
+}
+
 func (m *manager) Current() *VersionedDatabase { return m.databases[0] }
 
 func (m *manager) Previous() (*VersionedDatabase, bool) {
@@ -265,6 +311,32 @@ func (m *manager) NewCompleteMeterDBManager(namespace string, registerer prometh
 	})
 }
 
+// NewEncryptedDBManager wraps the current database, and each previous
+// database that was encrypted, with a cryptdb instance. Previous databases
+// that were written before encryption was enabled are left unencrypted so
+// that they can still be migrated from.
+func (m *manager) NewEncryptedDBManager(keyring *cryptdb.Keyring) (Manager, error) {
+	return m.wrapManager(func(vdb *VersionedDatabase) (*VersionedDatabase, error) {
+		if vdb != m.Current() {
+			encrypted, err := cryptdb.IsEncrypted(vdb.Database)
+			if err != nil {
+				return nil, err
+			}
+			if !encrypted {
+				return vdb, nil
+			}
+		}
+		encDB, err := cryptdb.New(keyring, vdb.Database)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't encrypt database %s: %w", vdb.Version, err)
+		}
+		return &VersionedDatabase{
+			Database: encDB,
+			Version:  vdb.Version,
+		}, nil
+	})
+}
+
 // wrapManager returns a new database manager with each managed database wrapped
 // by the [wrap] function. If an error is returned by wrap, the error is
 // returned immediately. If [wrap] never returns an error, then wrapManager is
@@ -12,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ava-labs/avalanchego/database/cryptdb"
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/database/meterdb"
@@ -36,7 +37,7 @@ func TestNewSingleDB(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	manager, err := New(dir, logging.NoLog{}, v1, true)
+	manager, err := New(dir, "", LevelDB, logging.NoLog{}, v1, true)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -60,7 +61,7 @@ func TestNewCreatesSingleDB(t *testing.T) {
 
 	v1 := version.DefaultVersion1_0_0
 
-	manager, err := New(dir, logging.NoLog{}, v1, true)
+	manager, err := New(dir, "", LevelDB, logging.NoLog{}, v1, true)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -102,7 +103,7 @@ func TestNewInvalidMemberPresent(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err = New(dir, logging.NoLog{}, v2, true)
+	_, err = New(dir, "", LevelDB, logging.NoLog{}, v2, true)
 	assert.Error(t, err, "expected to error creating the manager due to an open db")
 
 	err = db1.Close()
@@ -114,7 +115,7 @@ func TestNewInvalidMemberPresent(t *testing.T) {
 	err = f.Close()
 	assert.NoError(t, err)
 
-	db, err := New(dir, logging.NoLog{}, v1, true)
+	db, err := New(dir, "", LevelDB, logging.NoLog{}, v1, true)
 	assert.NoError(t, err, "expected not to error with a non-directory file being present")
 
 	err = db.Close()
@@ -145,7 +146,7 @@ func TestNewSortsDatabases(t *testing.T) {
 		}
 	}
 
-	manager, err := New(dir, logging.NoLog{}, vers[0], true)
+	manager, err := New(dir, "", LevelDB, logging.NoLog{}, vers[0], true)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -361,6 +362,33 @@ func TestNewManagerFromDBs(t *testing.T) {
 	}
 }
 
+func TestNewManagerWithCurrent(t *testing.T) {
+	previousDB := memdb.New()
+	m, err := NewManagerFromDBs(
+		[]*VersionedDatabase{
+			{
+				Database: memdb.New(),
+				Version:  version.NewDefaultVersion(1, 2, 0),
+			},
+			{
+				Database: previousDB,
+				Version:  version.NewDefaultVersion(1, 1, 0),
+			},
+		})
+	assert.NoError(t, err)
+
+	currentDB := memdb.New()
+	m, err = NewManagerWithCurrent(m, currentDB)
+	assert.NoError(t, err)
+
+	current := m.Current()
+	assert.Same(t, currentDB, current.Database)
+	assert.Equal(t, 0, current.Version.Compare(version.NewDefaultVersion(1, 2, 0)))
+	previous, exists := m.Previous()
+	assert.True(t, exists)
+	assert.Same(t, previousDB, previous.Database)
+}
+
 func TestNewManagerFromNoDBs(t *testing.T) {
 	// Should error if no dbs are given
 	_, err := NewManagerFromDBs(nil)
@@ -401,10 +429,59 @@ func TestDoesNotIncludePreviousVersions(t *testing.T) {
 	err = db2.Close()
 	assert.NoError(t, err)
 
-	manager, err := New(dir, logging.NoLog{}, v2, false)
+	manager, err := New(dir, "", LevelDB, logging.NoLog{}, v2, false)
 	assert.NoError(t, err, "shouldn't error because shouldn't try to open previous database version")
 	assert.NoError(t, manager.Close())
 
-	_, err = New(dir, logging.NoLog{}, v2, true)
+	_, err = New(dir, "", LevelDB, logging.NoLog{}, v2, true)
 	assert.Error(t, err, "should error because trying to open open database (1.1.0)")
 }
+
+func TestNewEncryptedDBManager(t *testing.T) {
+	keyring, err := cryptdb.NewKeyring(make([]byte, cryptdb.KeySize))
+	assert.NoError(t, err)
+
+	encryptedPrevDB := memdb.New()
+	_, err = cryptdb.New(keyring, encryptedPrevDB)
+	assert.NoError(t, err)
+	unencryptedPrevDB := memdb.New()
+	assert.NoError(t, unencryptedPrevDB.Put([]byte("hello"), []byte("world")))
+
+	m, err := NewManagerFromDBs(
+		[]*VersionedDatabase{
+			{
+				Database: memdb.New(),
+				Version:  version.NewDefaultVersion(1, 2, 0),
+			},
+			{
+				Database: encryptedPrevDB,
+				Version:  version.NewDefaultVersion(1, 1, 0),
+			},
+			{
+				Database: unencryptedPrevDB,
+				Version:  version.NewDefaultVersion(1, 0, 0),
+			},
+		})
+	assert.NoError(t, err)
+
+	m, err = m.NewEncryptedDBManager(keyring)
+	assert.NoError(t, err)
+
+	dbs := m.GetDatabases()
+	assert.Len(t, dbs, 3)
+	_, ok := dbs[0].Database.(*cryptdb.Database)
+	assert.True(t, ok)
+	_, ok = dbs[1].Database.(*cryptdb.Database)
+	assert.True(t, ok)
+	// Unencrypted previous databases are left as they are
+	assert.Equal(t, unencryptedPrevDB, dbs[2].Database)
+
+	// The current database must be encrypted
+	m, err = NewManagerFromDBs([]*VersionedDatabase{{
+		Database: unencryptedPrevDB,
+		Version:  version.NewDefaultVersion(1, 2, 0),
+	}})
+	assert.NoError(t, err)
+	_, err = m.NewEncryptedDBManager(keyring)
+	assert.Error(t, err)
+}
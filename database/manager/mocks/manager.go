@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	cryptdb "github.com/ava-labs/avalanchego/database/cryptdb"
 	manager "github.com/ava-labs/avalanchego/database/manager"
 	mock "github.com/stretchr/testify/mock"
 
@@ -118,6 +119,29 @@ func (_m *Manager) NewCompleteMeterDBManager(namespace string, registerer promet
 	return r0, r1
 }
 
+// NewEncryptedDBManager provides a mock function with given fields: keyring
+func (_m *Manager) NewEncryptedDBManager(keyring *cryptdb.Keyring) (manager.Manager, error) {
+	ret := _m.Called(keyring)
+
+	var r0 manager.Manager
+	if rf, ok := ret.Get(0).(func(*cryptdb.Keyring) manager.Manager); ok {
+		r0 = rf(keyring)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(manager.Manager)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*cryptdb.Keyring) error); ok {
+		r1 = rf(keyring)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewMeterDBManager provides a mock function with given fields: namespace, registerer
 func (_m *Manager) NewMeterDBManager(namespace string, registerer prometheus.Registerer) (manager.Manager, error) {
 	ret := _m.Called(namespace, registerer)
@@ -0,0 +1,86 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+var _ database.Database = &readOnlyDatabase{}
+
+// NewReadOnly returns a manager of the existing database in [dbDirPath] with
+// version [currentVersion], which is opened read-only with the backend
+// registered under [backendName]. If [walDirPath] isn't empty, the database
+// keeps its write-ahead log in the directory of its version in [walDirPath].
+// Previous database versions are ignored.
+// Writes to the managed database are kept in memory and dropped when the
+// manager is closed, so the database on disk is never modified.
+func NewReadOnly(
+	dbDirPath string,
+	walDirPath string,
+	backendName string,
+	log logging.Logger,
+	currentVersion version.Version,
+) (Manager, error) {
+	backend, err := GetBackend(backendName)
+	if err != nil {
+		return nil, err
+	}
+	return NewReadOnlyWithBackend(dbDirPath, walDirPath, backend, log, currentVersion)
+}
+
+// NewReadOnlyWithBackend is like NewReadOnly, but the database is opened with
+// [backend], which needn't be registered.
+func NewReadOnlyWithBackend(
+	dbDirPath string,
+	walDirPath string,
+	backend Backend,
+	log logging.Logger,
+	currentVersion version.Version,
+) (Manager, error) {
+	currentDBPath := filepath.Join(dbDirPath, currentVersion.String())
+	currentDB, err := openDB(backend, currentDBPath, walPath(walDirPath, currentVersion), true, log)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open db at %s read-only: %w", currentDBPath, err)
+	}
+	return &manager{
+		databases: []*VersionedDatabase{
+			{
+				Database: &readOnlyDatabase{
+					Database: versiondb.New(currentDB),
+					db:       currentDB,
+				},
+				Version: currentVersion,
+			},
+		},
+	}, nil
+}
+
+// readOnlyDatabase keeps the writes to [db], which was opened read-only, in
+// memory. The writes are never committed.
+type readOnlyDatabase struct {
+	*versiondb.Database
+	db database.Database
+}
+
+// Compact implements the database.Database interface. A read-only database
+// can't be compacted.
+func (db *readOnlyDatabase) Compact([]byte, []byte) error { return database.ErrReadOnly }
+
+// Close drops the writes kept in memory and closes the underlying database
+func (db *readOnlyDatabase) Close() error {
+	errs := wrappers.Errs{}
+	errs.Add(
+		db.Database.Close(),
+		db.db.Close(),
+	)
+	return errs.Err
+}
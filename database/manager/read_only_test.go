@@ -0,0 +1,48 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+func TestNewReadOnly(t *testing.T) {
+	v1 := version.DefaultVersion1_0_0
+	for _, name := range Backends() {
+		dir := t.TempDir()
+
+		// The database must already exist
+		_, err := NewReadOnly(dir, "", name, logging.NoLog{}, v1)
+		assert.Error(t, err)
+
+		manager, err := New(dir, "", name, logging.NoLog{}, v1, true)
+		assert.NoError(t, err)
+		assert.NoError(t, manager.Current().Database.Put([]byte("key"), []byte("value")))
+		assert.NoError(t, manager.Close())
+
+		manager, err = NewReadOnly(dir, "", name, logging.NoLog{}, v1)
+		assert.NoError(t, err)
+		db := manager.Current().Database
+		assert.NoError(t, db.Put([]byte("key"), []byte("other")))
+		value, err := db.Get([]byte("key"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("other"), value)
+		assert.ErrorIs(t, db.Compact(nil, nil), database.ErrReadOnly)
+		assert.NoError(t, manager.Close())
+
+		// The writes weren't persisted
+		manager, err = New(dir, "", name, logging.NoLog{}, v1, true)
+		assert.NoError(t, err)
+		value, err = manager.Current().Database.Get([]byte("key"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), value)
+		assert.NoError(t, manager.Close())
+	}
+}
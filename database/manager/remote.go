@@ -0,0 +1,76 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/rpcdb"
+	"github.com/ava-labs/avalanchego/database/rpcdb/rpcdbproto"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// RemoteDB is the name of the backend that keeps the database on a remote
+// database server. It isn't registered, since it must be configured with the
+// address of the server. See NewRemoteBackend.
+const RemoteDB = "rpcdb"
+
+// Time until a request to a remote database server that failed because the
+// server was unavailable is first retried
+const remoteRetryDelay = 100 * time.Millisecond
+
+var errRemoteReadOnly = errors.New("remote databases can't be opened read-only")
+
+// NewRemoteBackend returns a backend that keeps its databases on the database
+// server at [address], which it keeps [numConns] connections to, secured with
+// [security]. Requests that fail because the server is unavailable are
+// retried up to [maxRetries] times.
+//
+// The server serves a single database, so the keys of each database are
+// prefixed with the name of its directory, which is the version of the
+// database. The directory is still created so that the versions can be found.
+// Each server should only be used by a single node on a single network.
+func NewRemoteBackend(address string, security rpcdb.Security, numConns, maxRetries int) Backend {
+	return func(path, _ string, readOnly bool, _ logging.Logger) (database.Database, error) {
+		if readOnly {
+			return nil, errRemoteReadOnly
+		}
+		if err := os.MkdirAll(path, 0o750); err != nil {
+			return nil, fmt.Errorf("couldn't create %s: %w", path, err)
+		}
+		pool, err := rpcdb.Dial(address, security, numConns, maxRetries, remoteRetryDelay)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't dial database server at %s: %w", address, err)
+		}
+		client := rpcdb.NewClient(rpcdbproto.NewDatabaseClient(pool))
+		return &remoteDatabase{
+			Database: prefixdb.New([]byte(filepath.Base(path)), client),
+			pool:     pool,
+		}, nil
+	}
+}
+
+// remoteDatabase is a database on a remote database server. Closing it closes
+// the connections to the server, but not the database on the server, which
+// other databases are kept in too.
+type remoteDatabase struct {
+	*prefixdb.Database
+	pool *rpcdb.Pool
+}
+
+func (db *remoteDatabase) Close() error {
+	errs := wrappers.Errs{}
+	errs.Add(
+		db.Database.Close(),
+		db.pool.Close(),
+	)
+	return errs.Err
+}
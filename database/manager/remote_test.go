@@ -0,0 +1,58 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/rpcdb"
+	"github.com/ava-labs/avalanchego/database/rpcdb/rpcdbproto"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+func TestRemoteBackend(t *testing.T) {
+	assert := assert.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	server := grpc.NewServer()
+	rpcdbproto.RegisterDatabaseServer(server, rpcdb.NewServer(memdb.New()))
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	backend := NewRemoteBackend(listener.Addr().String(), rpcdb.Security{}, 2, 0)
+	dir := t.TempDir()
+	v1 := version.DefaultVersion1_0_0
+	v2 := version.NewDefaultVersion(1, 1, 0)
+
+	manager, err := NewWithBackend(dir, "", backend, logging.NoLog{}, v1, true)
+	assert.NoError(err)
+	assert.NoError(manager.Current().Database.Put([]byte("key"), []byte("v1")))
+	assert.NoError(manager.Close())
+
+	// The databases of each version are kept apart on the server, and the
+	// previous version is found
+	manager, err = NewWithBackend(dir, "", backend, logging.NoLog{}, v2, true)
+	assert.NoError(err)
+	current := manager.Current().Database
+	has, err := current.Has([]byte("key"))
+	assert.NoError(err)
+	assert.False(has)
+	previous, exists := manager.Previous()
+	assert.True(exists)
+	value, err := previous.Database.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("v1"), value)
+	assert.NoError(manager.Close())
+
+	_, err = NewReadOnlyWithBackend(dir, "", backend, logging.NoLog{}, v2)
+	assert.ErrorIs(err, errRemoteReadOnly)
+}
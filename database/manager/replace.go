@@ -0,0 +1,256 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+const (
+	// Suffixes of the directories that a database is written to, and that the
+	// database it replaces is moved to, next to the database's directory
+	replacementSuffix = ".replacement"
+	replacedSuffix    = ".replaced"
+
+	// Suffix of the file that marks a replacement database as complete. Once
+	// it exists, an interrupted replacement is finished rather than discarded.
+	completeSuffix = ".replacement-complete"
+)
+
+var errUnconfirmedReplacement = errors.New("database replacement wasn't confirmed")
+
+// Replace replaces the database with version [currentVersion] in
+// [dbDirPath] with a fresh database that [fill] writes to. The database is
+// opened with the backend registered under [backendName]. [fill] may read the
+// current database, but it must be closed once [fill] returns.
+func Replace(
+	dbDirPath string,
+	walDirPath string,
+	backendName string,
+	log logging.Logger,
+	currentVersion version.Version,
+	fill func(database.Database) error,
+) error {
+	backend, err := GetBackend(backendName)
+	if err != nil {
+		return err
+	}
+	return ReplaceWithBackend(dbDirPath, walDirPath, backend, log, currentVersion, fill)
+}
+
+// ReplaceWithBackend is like Replace, but the databases are opened with
+// [backend], which needn't be registered.
+//
+// The fresh database is written in a directory next to the current one, and
+// only replaces it once [fill] returns without error, so the current database
+// is left as it was if [fill] fails. The write-ahead log of the current
+// database is discarded so that it isn't replayed into the fresh one.
+//
+// The fresh database is marked as complete before the current one is moved
+// aside, so if the node stops during the swap, the swap is finished when the
+// databases are next opened. The current database is only removed once the
+// fresh one has taken its place.
+func ReplaceWithBackend(
+	dbDirPath string,
+	walDirPath string,
+	backend Backend,
+	log logging.Logger,
+	currentVersion version.Version,
+	fill func(database.Database) error,
+) error {
+	// Finish or undo a replacement that was interrupted
+	if err := recoverReplacement(dbDirPath, walDirPath, log, currentVersion); err != nil {
+		return err
+	}
+
+	currentDBPath := filepath.Join(dbDirPath, currentVersion.String())
+	replacementPath := currentDBPath + replacementSuffix
+
+	// The fresh database keeps its write-ahead log in its own directory, which
+	// is replayed when it's opened with [walDirPath] after the swap
+	db, err := backend(replacementPath, "", false, log)
+	if err != nil {
+		return fmt.Errorf("couldn't create db at %s: %w", replacementPath, err)
+	}
+	err = fill(db)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = createSynced(currentDBPath + completeSuffix)
+	}
+	if err != nil {
+		_ = os.RemoveAll(replacementPath)
+		return err
+	}
+	return finishReplacement(dbDirPath, walDirPath, currentVersion)
+}
+
+// RecoverReplacements finishes the replacements of the databases in
+// [dbDirPath] that were complete when they were interrupted, and restores the
+// databases whose replacements were not.
+func RecoverReplacements(dbDirPath string, walDirPath string, log logging.Logger) error {
+	files, err := ioutil.ReadDir(dbDirPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	parser := version.NewDefaultParser()
+	for _, file := range files {
+		name := file.Name()
+		for _, suffix := range []string{replacementSuffix, replacedSuffix, completeSuffix} {
+			if !strings.HasSuffix(name, suffix) {
+				continue
+			}
+			v, err := parser.Parse(strings.TrimSuffix(name, suffix))
+			if err != nil {
+				// Ignore files that weren't left behind by a replacement
+				break
+			}
+			if err := recoverReplacement(dbDirPath, walDirPath, log, v); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// recoverReplacement finishes the replacement of the database with version [v]
+// if the replacement database is complete. Otherwise, the replacement database
+// is discarded and the database it was replacing is moved back, if it was
+// already moved aside.
+func recoverReplacement(dbDirPath string, walDirPath string, log logging.Logger, v version.Version) error {
+	currentDBPath := filepath.Join(dbDirPath, v.String())
+	replacedPath := currentDBPath + replacedSuffix
+
+	complete, err := exists(currentDBPath + completeSuffix)
+	if err != nil {
+		return err
+	}
+	if complete {
+		log.Info("finishing the interrupted replacement of database %s", v)
+		return finishReplacement(dbDirPath, walDirPath, v)
+	}
+
+	if err := os.RemoveAll(currentDBPath + replacementSuffix); err != nil {
+		return err
+	}
+	replaced, err := exists(replacedPath)
+	if err != nil || !replaced {
+		return err
+	}
+	current, err := exists(currentDBPath)
+	if err != nil {
+		return err
+	}
+	if current {
+		return fmt.Errorf(
+			"%w: both %s and %s exist. Remove the one that shouldn't be used",
+			errUnconfirmedReplacement,
+			currentDBPath,
+			replacedPath,
+		)
+	}
+	log.Warn("restoring database %s, whose replacement was interrupted", v)
+	if err := os.Rename(replacedPath, currentDBPath); err != nil {
+		return fmt.Errorf("couldn't move db back to %s: %w", currentDBPath, err)
+	}
+	return syncDir(dbDirPath)
+}
+
+// finishReplacement moves the complete replacement of the database with
+// version [v] into its place. Each step may already have been taken by a
+// replacement that was interrupted.
+func finishReplacement(dbDirPath string, walDirPath string, v version.Version) error {
+	currentDBPath := filepath.Join(dbDirPath, v.String())
+	replacementPath := currentDBPath + replacementSuffix
+	replacedPath := currentDBPath + replacedSuffix
+
+	if walPath := walPath(walDirPath, v); walPath != "" {
+		if err := os.RemoveAll(walPath); err != nil {
+			return fmt.Errorf("couldn't remove write-ahead log at %s: %w", walPath, err)
+		}
+	}
+
+	hasReplacement, err := exists(replacementPath)
+	if err != nil {
+		return err
+	}
+	if hasReplacement {
+		if err := os.Rename(currentDBPath, replacedPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("couldn't move db at %s: %w", currentDBPath, err)
+		}
+		if err := os.Rename(replacementPath, currentDBPath); err != nil {
+			return fmt.Errorf("couldn't move replacement db to %s: %w", currentDBPath, err)
+		}
+		if err := syncDir(dbDirPath); err != nil {
+			return err
+		}
+	}
+
+	// The replaced database is only removed once the replacement has taken its
+	// place
+	current, err := exists(currentDBPath)
+	if err != nil {
+		return err
+	}
+	if !current {
+		return fmt.Errorf("%w: %s is missing", errUnconfirmedReplacement, currentDBPath)
+	}
+	if err := os.RemoveAll(replacedPath); err != nil {
+		return err
+	}
+	return os.Remove(currentDBPath + completeSuffix)
+}
+
+func exists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// createSynced creates an empty file at [path] and makes sure that it, and
+// its directory entry, are on disk
+func createSynced(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = f.Sync()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir makes sure that the entries of the directory at [path] are on disk
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	err = dir.Sync()
+	if closeErr := dir.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
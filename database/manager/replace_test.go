@@ -0,0 +1,216 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+func TestReplace(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	walDir := t.TempDir()
+	v1 := version.DefaultVersion1_0_0
+
+	manager, err := New(dir, walDir, LevelDB, logging.NoLog{}, v1, true)
+	assert.NoError(err)
+	assert.NoError(manager.Current().Database.Put([]byte("stale"), []byte("value")))
+	assert.NoError(manager.Close())
+
+	// A failed replacement leaves the current database as it was
+	errFill := errors.New("fill failed")
+	err = Replace(dir, walDir, LevelDB, logging.NoLog{}, v1, func(db database.Database) error {
+		if err := db.Put([]byte("key"), []byte("value")); err != nil {
+			return err
+		}
+		return errFill
+	})
+	assert.ErrorIs(err, errFill)
+
+	manager, err = New(dir, walDir, LevelDB, logging.NoLog{}, v1, true)
+	assert.NoError(err)
+	has, err := manager.Current().Database.Has([]byte("stale"))
+	assert.NoError(err)
+	assert.True(has)
+	has, err = manager.Current().Database.Has([]byte("key"))
+	assert.NoError(err)
+	assert.False(has)
+	assert.NoError(manager.Close())
+
+	// A successful replacement replaces the current database
+	err = Replace(dir, walDir, LevelDB, logging.NoLog{}, v1, func(db database.Database) error {
+		return db.Put([]byte("key"), []byte("value"))
+	})
+	assert.NoError(err)
+
+	manager, err = New(dir, walDir, LevelDB, logging.NoLog{}, v1, true)
+	assert.NoError(err)
+	has, err = manager.Current().Database.Has([]byte("stale"))
+	assert.NoError(err)
+	assert.False(has)
+	value, err := manager.Current().Database.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+	assert.NoError(manager.Close())
+
+	_, err = os.Stat(filepath.Join(dir, v1.String()+replacementSuffix))
+	assert.True(os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, v1.String()+replacedSuffix))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestReplaceRecovery(t *testing.T) {
+	v1 := version.DefaultVersion1_0_0
+
+	type test struct {
+		name string
+		// moves the databases into the state an interrupted replacement left
+		// them in
+		interrupt     func(currentPath string) error
+		expectedValue []byte
+	}
+	tests := []test{
+		{
+			name: "moved aside before complete",
+			interrupt: func(currentPath string) error {
+				if err := os.Rename(currentPath, currentPath+replacedSuffix); err != nil {
+					return err
+				}
+				return os.Rename(currentPath+".new", currentPath+replacementSuffix)
+			},
+			expectedValue: []byte("old"),
+		},
+		{
+			name: "complete before moved aside",
+			interrupt: func(currentPath string) error {
+				if err := os.Rename(currentPath+".new", currentPath+replacementSuffix); err != nil {
+					return err
+				}
+				return createSynced(currentPath + completeSuffix)
+			},
+			expectedValue: []byte("new"),
+		},
+		{
+			name: "complete and moved aside",
+			interrupt: func(currentPath string) error {
+				if err := os.Rename(currentPath+".new", currentPath+replacementSuffix); err != nil {
+					return err
+				}
+				if err := createSynced(currentPath + completeSuffix); err != nil {
+					return err
+				}
+				return os.Rename(currentPath, currentPath+replacedSuffix)
+			},
+			expectedValue: []byte("new"),
+		},
+		{
+			name: "complete and swapped",
+			interrupt: func(currentPath string) error {
+				if err := createSynced(currentPath + completeSuffix); err != nil {
+					return err
+				}
+				if err := os.Rename(currentPath, currentPath+replacedSuffix); err != nil {
+					return err
+				}
+				return os.Rename(currentPath+".new", currentPath)
+			},
+			expectedValue: []byte("new"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			dir := t.TempDir()
+			currentPath := filepath.Join(dir, v1.String())
+
+			db, err := leveldb.New(currentPath, logging.NoLog{}, 0, 0, 0)
+			assert.NoError(err)
+			assert.NoError(db.Put([]byte("key"), []byte("old")))
+			assert.NoError(db.Close())
+
+			db, err = leveldb.New(currentPath+".new", logging.NoLog{}, 0, 0, 0)
+			assert.NoError(err)
+			assert.NoError(db.Put([]byte("key"), []byte("new")))
+			assert.NoError(db.Close())
+
+			assert.NoError(test.interrupt(currentPath))
+
+			manager, err := New(dir, "", LevelDB, logging.NoLog{}, v1, true)
+			assert.NoError(err)
+			value, err := manager.Current().Database.Get([]byte("key"))
+			assert.NoError(err)
+			assert.Equal(test.expectedValue, value)
+			assert.NoError(manager.Close())
+
+			for _, suffix := range []string{replacementSuffix, replacedSuffix, completeSuffix} {
+				_, err = os.Stat(currentPath + suffix)
+				assert.True(os.IsNotExist(err))
+			}
+		})
+	}
+}
+
+func TestReplaceRecoveryUnconfirmed(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	v1 := version.DefaultVersion1_0_0
+	currentPath := filepath.Join(dir, v1.String())
+	assert.NoError(os.MkdirAll(currentPath, perms.ReadWriteExecute))
+	assert.NoError(os.MkdirAll(currentPath+replacedSuffix, perms.ReadWriteExecute))
+
+	// Without a completion marker, neither database can be known to be the
+	// right one, so neither is removed
+	_, err := New(dir, "", LevelDB, logging.NoLog{}, v1, true)
+	assert.ErrorIs(err, errUnconfirmedReplacement)
+	_, err = os.Stat(currentPath + replacedSuffix)
+	assert.NoError(err)
+}
+
+func TestReplaceAfterInterruptedReplacement(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	v1 := version.DefaultVersion1_0_0
+	currentPath := filepath.Join(dir, v1.String())
+
+	db, err := leveldb.New(currentPath, logging.NoLog{}, 0, 0, 0)
+	assert.NoError(err)
+	assert.NoError(db.Put([]byte("key"), []byte("old")))
+	assert.NoError(db.Close())
+
+	// A replacement, such as a restore from a backup, was interrupted after
+	// the current database was moved aside but before the replacement was
+	// complete
+	assert.NoError(os.Rename(currentPath, currentPath+replacedSuffix))
+	assert.NoError(os.MkdirAll(currentPath+replacementSuffix, perms.ReadWriteExecute))
+
+	// The next replacement moves the current database back rather than
+	// discarding it, even though it fails
+	errFill := errors.New("fill failed")
+	err = Replace(dir, "", LevelDB, logging.NoLog{}, v1, func(database.Database) error {
+		return errFill
+	})
+	assert.ErrorIs(err, errFill)
+
+	manager, err := New(dir, "", LevelDB, logging.NoLog{}, v1, true)
+	assert.NoError(err)
+	value, err := manager.Current().Database.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("old"), value)
+	assert.NoError(manager.Close())
+}
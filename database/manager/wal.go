@@ -0,0 +1,66 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+// Name of the file in the directory of a database that records the directory
+// that the database keeps its write-ahead log in, if it isn't kept in the
+// database's directory
+const walDirFileName = "WAL_DIR"
+
+var errWALDirChanged = errors.New("write-ahead log directory changed")
+
+// Returns the directory that the write-ahead log of the database with version
+// [v] is kept in, or the empty string if [walDirPath] is empty
+func walPath(walDirPath string, v version.Version) string {
+	if walDirPath == "" {
+		return ""
+	}
+	return filepath.Join(walDirPath, v.String())
+}
+
+// openDB opens the database in [path] with [backend], keeping its write-ahead
+// log in [walPath] if it isn't empty. The backends replay the logs that were
+// written to [path] before a write-ahead log directory was given, but the
+// logs in a previously given directory wouldn't be found, so the database
+// isn't opened if a different directory was given before.
+func openDB(backend Backend, path, walPath string, readOnly bool, log logging.Logger) (database.Database, error) {
+	walDirFile := filepath.Join(path, walDirFileName)
+	previousWALPath, err := ioutil.ReadFile(walDirFile)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, err
+	case filepath.Clean(string(previousWALPath)) != filepath.Clean(walPath) || walPath == "":
+		return nil, fmt.Errorf(
+			"%w: the database in %s keeps its write-ahead log in %s. To change it, move the log files to the new directory, or to %s if none, and update or remove %s",
+			errWALDirChanged,
+			path,
+			previousWALPath,
+			path,
+			walDirFile,
+		)
+	}
+
+	db, err := backend(path, walPath, readOnly, log)
+	if err != nil || walPath == "" || readOnly || len(previousWALPath) != 0 {
+		return db, err
+	}
+	if err := ioutil.WriteFile(walDirFile, []byte(walPath), 0o600); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("couldn't record write-ahead log directory: %w", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,43 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+func TestWALDir(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	walDir := t.TempDir()
+	v1 := version.DefaultVersion1_0_0
+
+	manager, err := New(dir, walDir, LevelDB, logging.NoLog{}, v1, true)
+	assert.NoError(err)
+	assert.NoError(manager.Current().Database.Put([]byte("key"), []byte("value")))
+	assert.NoError(manager.Close())
+
+	// The write is replayed from the write-ahead log
+	manager, err = NewReadOnly(dir, walDir, LevelDB, logging.NoLog{}, v1)
+	assert.NoError(err)
+	value, err := manager.Current().Database.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+	assert.NoError(manager.Close())
+
+	// The database isn't opened without the write-ahead log
+	_, err = New(dir, "", LevelDB, logging.NoLog{}, v1, true)
+	assert.True(errors.Is(err, errWALDirChanged))
+	_, err = New(dir, t.TempDir(), LevelDB, logging.NoLog{}, v1, true)
+	assert.True(errors.Is(err, errWALDirChanged))
+	_, err = NewReadOnly(dir, "", LevelDB, logging.NoLog{}, v1)
+	assert.True(errors.Is(err, errWALDirChanged))
+}
@@ -19,8 +19,10 @@ const (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ database.Batch    = &batch{}
+	_ database.Database    = &Database{}
+	_ database.Snapshotter = &Database{}
+	_ database.Batch       = &batch{}
+	_ database.Snapshot    = &snapshot{}
 )
 
 // Database is an ephemeral key-value store that implements the Database
@@ -233,6 +235,29 @@ func (b *batch) Replay(w database.KeyValueWriter) error {
 // Inner returns itself
 func (b *batch) Inner() database.Batch { return b }
 
+// NewSnapshot implements the Snapshotter interface. The contents of the
+// database are copied into the snapshot.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+	snap := NewWithSize(len(db.db))
+	for key, value := range db.db {
+		// Values are never modified in place, so they can be shared
+		snap.db[key] = value
+	}
+	return &snapshot{Database: snap}, nil
+}
+
+// snapshot is a copy of the contents of a database
+type snapshot struct{ *Database }
+
+// Release implements the Snapshot interface
+func (s *snapshot) Release() { _ = s.Database.Close() }
+
 type iterator struct {
 	initialized bool
 	keys        []string
@@ -24,3 +24,5 @@ func BenchmarkInterface(b *testing.B) {
 		}
 	}
 }
+
+func TestSnapshot(t *testing.T) { database.TestSnapshot(t, New()) }
@@ -11,8 +11,9 @@ import (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ database.Batch    = &batch{}
+	_ database.Database    = &Database{}
+	_ database.Snapshotter = &Database{}
+	_ database.Batch       = &batch{}
 )
 
 // Database tracks the amount of time each operation takes and how many bytes
@@ -40,6 +41,7 @@ func (db *Database) Has(key []byte) (bool, error) {
 	db.readSize.Observe(float64(len(key)))
 	db.has.Observe(float64(end.Sub(start)))
 	db.hasSize.Observe(float64(len(key)))
+	db.observeError("has", err)
 	return has, err
 }
 
@@ -50,6 +52,7 @@ func (db *Database) Get(key []byte) ([]byte, error) {
 	db.readSize.Observe(float64(len(key) + len(value)))
 	db.get.Observe(float64(end.Sub(start)))
 	db.getSize.Observe(float64(len(key) + len(value)))
+	db.observeError("get", err)
 	return value, err
 }
 
@@ -60,6 +63,7 @@ func (db *Database) Put(key, value []byte) error {
 	db.writeSize.Observe(float64(len(key) + len(value)))
 	db.put.Observe(float64(end.Sub(start)))
 	db.putSize.Observe(float64(len(key) + len(value)))
+	db.observeError("put", err)
 	return err
 }
 
@@ -70,6 +74,7 @@ func (db *Database) Delete(key []byte) error {
 	db.writeSize.Observe(float64(len(key)))
 	db.delete.Observe(float64(end.Sub(start)))
 	db.deleteSize.Observe(float64(len(key)))
+	db.observeError("delete", err)
 	return err
 }
 
@@ -123,9 +128,20 @@ func (db *Database) Compact(start, limit []byte) error {
 	err := db.db.Compact(start, limit)
 	end := db.clock.Time()
 	db.compact.Observe(float64(end.Sub(startTime)))
+	db.observeError("compact", err)
 	return err
 }
 
+// NewSnapshot returns a snapshot of the underlying database, if it takes
+// snapshots. Reads from the snapshot aren't metered.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	snapshotter, ok := db.db.(database.Snapshotter)
+	if !ok {
+		return nil, database.ErrSnapshotsUnsupported
+	}
+	return snapshotter.NewSnapshot()
+}
+
 func (db *Database) Close() error {
 	start := db.clock.Time()
 	err := db.db.Close()
@@ -137,6 +153,8 @@ func (db *Database) Close() error {
 type batch struct {
 	batch database.Batch
 	db    *Database
+	// Number of puts and deletes in the batch
+	ops int
 }
 
 func (b *batch) Put(key, value []byte) error {
@@ -145,6 +163,7 @@ func (b *batch) Put(key, value []byte) error {
 	end := b.db.clock.Time()
 	b.db.bPut.Observe(float64(end.Sub(start)))
 	b.db.bPutSize.Observe(float64(len(key) + len(value)))
+	b.ops++
 	return err
 }
 
@@ -154,6 +173,7 @@ func (b *batch) Delete(key []byte) error {
 	end := b.db.clock.Time()
 	b.db.bDelete.Observe(float64(end.Sub(start)))
 	b.db.bDeleteSize.Observe(float64(len(key)))
+	b.ops++
 	return err
 }
 
@@ -173,12 +193,15 @@ func (b *batch) Write() error {
 	b.db.writeSize.Observe(batchSize)
 	b.db.bWrite.Observe(float64(end.Sub(start)))
 	b.db.bWriteSize.Observe(batchSize)
+	b.db.bWriteOps.Observe(float64(b.ops))
+	b.db.observeError("batch_write", err)
 	return err
 }
 
 func (b *batch) Reset() {
 	start := b.db.clock.Time()
 	b.batch.Reset()
+	b.ops = 0
 	end := b.db.clock.Time()
 	b.db.bReset.Observe(float64(end.Sub(start)))
 }
@@ -220,6 +243,7 @@ func (it *iterator) Error() error {
 	err := it.iterator.Error()
 	end := it.db.clock.Time()
 	it.db.iError.Observe(float64(end.Sub(start)))
+	it.db.observeError("iterator", err)
 	return err
 }
 
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
@@ -37,3 +39,52 @@ func BenchmarkInterface(b *testing.B) {
 		}
 	}
 }
+
+func TestSnapshot(t *testing.T) {
+	db, err := New("", prometheus.NewRegistry(), memdb.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.TestSnapshot(t, db)
+}
+
+func TestErrorsAndBatchOps(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+	db, err := New("", registry, memdb.New())
+	assert.NoError(err)
+
+	batch := db.NewBatch()
+	assert.NoError(batch.Put([]byte{1}, []byte{1}))
+	assert.NoError(batch.Put([]byte{2}, []byte{2}))
+	assert.NoError(batch.Delete([]byte{3}))
+	assert.NoError(batch.Write())
+	batch.Reset()
+	assert.NoError(batch.Write())
+	metricFamilies, err := registry.Gather()
+	assert.NoError(err)
+	found := false
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != "batch_write_ops" {
+			continue
+		}
+		found = true
+		histogram := metricFamily.GetMetric()[0].GetHistogram()
+		assert.Equal(uint64(2), histogram.GetSampleCount())
+		assert.Equal(3.0, histogram.GetSampleSum())
+	}
+	assert.True(found)
+
+	// Keys that aren't found aren't errors
+	_, err = db.Get([]byte{3})
+	assert.Equal(database.ErrNotFound, err)
+	assert.Equal(0, testutil.CollectAndCount(db.errors))
+
+	assert.NoError(db.Close())
+	_, err = db.Get([]byte{1})
+	assert.Equal(database.ErrClosed, err)
+	assert.Equal(1.0, testutil.ToFloat64(db.errors.WithLabelValues("get")))
+	assert.Error(db.Put([]byte{1}, []byte{1}))
+	assert.Equal(1.0, testutil.ToFloat64(db.errors.WithLabelValues("put")))
+}
@@ -8,10 +8,13 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/utils/metric"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
+const operationLabel = "operation"
+
 func newSizeMetric(namespace, name string) prometheus.Histogram {
 	return prometheus.NewHistogram(prometheus.HistogramOpts{
 		Namespace: namespace,
@@ -53,6 +56,11 @@ type metrics struct {
 	iKey,
 	iValue,
 	iRelease prometheus.Histogram
+
+	// Number of operations in each written batch
+	bWriteOps prometheus.Histogram
+	// Number of operations that failed, by operation
+	errors *prometheus.CounterVec
 }
 
 func (m *metrics) Initialize(
@@ -90,6 +98,17 @@ func (m *metrics) Initialize(
 	m.iKey = metric.NewNanosecondsLatencyMetric(namespace, "iterator_key")
 	m.iValue = metric.NewNanosecondsLatencyMetric(namespace, "iterator_value")
 	m.iRelease = metric.NewNanosecondsLatencyMetric(namespace, "iterator_release")
+	m.bWriteOps = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "batch_write_ops",
+		Help:      "Puts and deletes in a batch when it is written",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+	})
+	m.errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "errors",
+		Help:      "Number of calls that failed with an error other than not found",
+	}, []string{operationLabel})
 
 	errs := wrappers.Errs{}
 	errs.Add(
@@ -124,6 +143,16 @@ func (m *metrics) Initialize(
 		registerer.Register(m.iKey),
 		registerer.Register(m.iValue),
 		registerer.Register(m.iRelease),
+		registerer.Register(m.bWriteOps),
+		registerer.Register(m.errors),
 	)
 	return errs.Err
 }
+
+// observeError counts [err] as a failure of [operation], unless it is nil or
+// only reports that a key wasn't found
+func (m *metrics) observeError(operation string, err error) {
+	if err != nil && err != database.ErrNotFound {
+		m.errors.WithLabelValues(operation).Inc()
+	}
+}
@@ -0,0 +1,169 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migration
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	// versionPrefix is the prefix of the keyspace that holds the schema
+	// version of each other keyspace, keyed by the keyspace's name
+	versionPrefix = []byte("schema versions")
+
+	errEmptyName               = errors.New("keyspace name is empty")
+	errDuplicatedKeyspace      = errors.New("duplicated keyspace")
+	errNonConsecutiveMigration = errors.New("migrations must have consecutive versions starting at 1")
+	errUnknownVersion          = errors.New("keyspace has a schema version that is newer than the latest known version")
+)
+
+// Migration changes the layout of the data in a keyspace from the schema
+// version before [Version] to [Version]
+type Migration struct {
+	// Schema version of the keyspace once the migration is done
+	Version uint64
+	// Describes the change of layout, for the logs
+	Description string
+	// Migrate is given the keyspace, as a database whose changes are written
+	// atomically with the keyspace's new schema version once Migrate returns
+	// without error. The changes are buffered in memory until then, so
+	// migrations of large keyspaces should be split into several migrations.
+	Migrate func(db database.Database) error
+}
+
+// Keyspace is a part of the database whose data has its own layout
+type Keyspace struct {
+	// Identifies the keyspace's schema version. Must not change.
+	Name string
+	// Prefix of the keyspace's keys, as applied by prefixdb
+	Prefix []byte
+	// Migrations of the keyspace, in order. The schema version of the
+	// keyspace's current layout is the version of the last migration, or 0
+	// if there are no migrations.
+	Migrations []Migration
+}
+
+// LatestVersion returns the schema version of the keyspace's current layout
+func (k *Keyspace) LatestVersion() uint64 { return uint64(len(k.Migrations)) }
+
+// Runner migrates the keyspaces of a database to their latest schema versions
+type Runner struct {
+	log       logging.Logger
+	db        database.Database
+	keyspaces []Keyspace
+	names     map[string]struct{}
+}
+
+// NewRunner returns a runner of the migrations of [db]
+func NewRunner(log logging.Logger, db database.Database) *Runner {
+	return &Runner{
+		log:   log,
+		db:    db,
+		names: make(map[string]struct{}),
+	}
+}
+
+// Register a keyspace whose migrations are run by Run
+func (r *Runner) Register(keyspace Keyspace) error {
+	if keyspace.Name == "" {
+		return errEmptyName
+	}
+	if _, exists := r.names[keyspace.Name]; exists {
+		return fmt.Errorf("%w: %s", errDuplicatedKeyspace, keyspace.Name)
+	}
+	for i, migration := range keyspace.Migrations {
+		if migration.Version != uint64(i+1) {
+			return fmt.Errorf("%w: keyspace %s has migration %d at position %d", errNonConsecutiveMigration, keyspace.Name, migration.Version, i+1)
+		}
+	}
+	r.names[keyspace.Name] = struct{}{}
+	r.keyspaces = append(r.keyspaces, keyspace)
+	return nil
+}
+
+// Run migrates each registered keyspace, in the order they were registered,
+// to its latest schema version. Keyspaces that don't have a schema version
+// are at version 0 if they hold data. Empty keyspaces are marked as being at
+// their latest version, since there is nothing to migrate. Each migration is
+// written atomically, so if Run is interrupted it resumes from the last
+// migration that was written.
+func (r *Runner) Run() error {
+	for i := range r.keyspaces {
+		if err := r.migrate(&r.keyspaces[i]); err != nil {
+			return fmt.Errorf("couldn't migrate keyspace %s: %w", r.keyspaces[i].Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) migrate(keyspace *Keyspace) error {
+	latestVersion := keyspace.LatestVersion()
+	version, exists, err := Version(r.db, keyspace.Name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		empty, err := isEmpty(prefixdb.New(keyspace.Prefix, r.db))
+		if err != nil {
+			return err
+		}
+		if empty {
+			return putVersion(r.db, keyspace.Name, latestVersion)
+		}
+	}
+	if version > latestVersion {
+		return fmt.Errorf("%w: %d > %d", errUnknownVersion, version, latestVersion)
+	}
+
+	for _, migration := range keyspace.Migrations[version:] {
+		r.log.Info("migrating keyspace %s to schema version %d: %s", keyspace.Name, migration.Version, migration.Description)
+		vdb := versiondb.New(r.db)
+		if err := migration.Migrate(prefixdb.New(keyspace.Prefix, vdb)); err != nil {
+			return fmt.Errorf("migration to schema version %d failed: %w", migration.Version, err)
+		}
+		if err := putVersion(vdb, keyspace.Name, migration.Version); err != nil {
+			return err
+		}
+		if err := vdb.Commit(); err != nil {
+			return err
+		}
+	}
+	if !exists && latestVersion == 0 {
+		// Mark the keyspace so that later migrations know where to start
+		return putVersion(r.db, keyspace.Name, 0)
+	}
+	return nil
+}
+
+// Version returns the schema version of the keyspace named [name] in [db],
+// and false if the keyspace doesn't have one
+func Version(db database.Database, name string) (uint64, bool, error) {
+	version, err := database.GetUInt64(prefixdb.New(versionPrefix, db), []byte(name))
+	switch err {
+	case nil:
+		return version, true, nil
+	case database.ErrNotFound:
+		return 0, false, nil
+	default:
+		return 0, false, err
+	}
+}
+
+func putVersion(db database.Database, name string, version uint64) error {
+	return database.PutUInt64(prefixdb.New(versionPrefix, db), []byte(name), version)
+}
+
+func isEmpty(db database.Iteratee) (bool, error) {
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	hasData := iter.Next()
+	return !hasData, iter.Error()
+}
@@ -0,0 +1,169 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	testPrefix = []byte("test")
+
+	// Renames the key "old" to "new"
+	renameMigration = Migration{
+		Version:     1,
+		Description: "rename old to new",
+		Migrate: func(db database.Database) error {
+			value, err := db.Get([]byte("old"))
+			if err != nil {
+				return err
+			}
+			if err := db.Put([]byte("new"), value); err != nil {
+				return err
+			}
+			return db.Delete([]byte("old"))
+		},
+	}
+)
+
+func TestRunMigrations(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	keyspaceDB := prefixdb.New(testPrefix, db)
+	assert.NoError(keyspaceDB.Put([]byte("old"), []byte("value")))
+
+	runner := NewRunner(logging.NoLog{}, db)
+	assert.NoError(runner.Register(Keyspace{
+		Name:       "test",
+		Prefix:     testPrefix,
+		Migrations: []Migration{renameMigration},
+	}))
+	assert.NoError(runner.Run())
+
+	value, err := keyspaceDB.Get([]byte("new"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+	has, err := keyspaceDB.Has([]byte("old"))
+	assert.NoError(err)
+	assert.False(has)
+
+	version, exists, err := Version(db, "test")
+	assert.NoError(err)
+	assert.True(exists)
+	assert.EqualValues(1, version)
+
+	// Migrations only run once
+	assert.NoError(runner.Run())
+}
+
+func TestEmptyKeyspaceIsLatest(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	runner := NewRunner(logging.NoLog{}, db)
+	assert.NoError(runner.Register(Keyspace{
+		Name:   "test",
+		Prefix: testPrefix,
+		Migrations: []Migration{{
+			Version: 1,
+			Migrate: func(database.Database) error { return errors.New("unexpected migration") },
+		}},
+	}))
+	assert.NoError(runner.Run())
+
+	version, exists, err := Version(db, "test")
+	assert.NoError(err)
+	assert.True(exists)
+	assert.EqualValues(1, version)
+}
+
+func TestKeyspaceWithoutMigrations(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	assert.NoError(prefixdb.New(testPrefix, db).Put([]byte("key"), []byte("value")))
+	runner := NewRunner(logging.NoLog{}, db)
+	assert.NoError(runner.Register(Keyspace{Name: "test", Prefix: testPrefix}))
+	assert.NoError(runner.Run())
+
+	version, exists, err := Version(db, "test")
+	assert.NoError(err)
+	assert.True(exists)
+	assert.EqualValues(0, version)
+}
+
+func TestFailedMigrationIsNotWritten(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	keyspaceDB := prefixdb.New(testPrefix, db)
+	assert.NoError(keyspaceDB.Put([]byte("old"), []byte("value")))
+
+	errFailed := errors.New("failed")
+	runner := NewRunner(logging.NoLog{}, db)
+	assert.NoError(runner.Register(Keyspace{
+		Name:   "test",
+		Prefix: testPrefix,
+		Migrations: []Migration{
+			renameMigration,
+			{
+				Version: 2,
+				Migrate: func(db database.Database) error {
+					if err := db.Delete([]byte("new")); err != nil {
+						return err
+					}
+					return errFailed
+				},
+			},
+		},
+	}))
+	assert.ErrorIs(runner.Run(), errFailed)
+
+	// The first migration was written, but none of the second one was
+	value, err := keyspaceDB.Get([]byte("new"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+	version, _, err := Version(db, "test")
+	assert.NoError(err)
+	assert.EqualValues(1, version)
+}
+
+func TestUnknownVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	assert.NoError(putVersion(db, "test", 2))
+
+	runner := NewRunner(logging.NoLog{}, db)
+	assert.NoError(runner.Register(Keyspace{
+		Name:       "test",
+		Prefix:     testPrefix,
+		Migrations: []Migration{renameMigration},
+	}))
+	assert.ErrorIs(runner.Run(), errUnknownVersion)
+}
+
+func TestRegister(t *testing.T) {
+	assert := assert.New(t)
+
+	runner := NewRunner(logging.NoLog{}, memdb.New())
+	assert.ErrorIs(runner.Register(Keyspace{}), errEmptyName)
+	assert.NoError(runner.Register(Keyspace{Name: "test"}))
+	assert.ErrorIs(runner.Register(Keyspace{Name: "test"}), errDuplicatedKeyspace)
+
+	err := runner.Register(Keyspace{
+		Name:       "other",
+		Migrations: []Migration{{Version: 2}},
+	})
+	assert.ErrorIs(err, errNonConsecutiveMigration)
+}
@@ -0,0 +1,662 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebbledb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/nodb"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// minCacheSize is the minimum number of bytes to use for block caching in
+	// pebble.
+	minCacheSize = 12 * 1024 * 1024
+
+	// minMemTableSize is the minimum number of bytes to use for each memtable
+	// in pebble.
+	minMemTableSize = 12 * 1024 * 1024
+
+	// minHandleCap is the minimum number of files descriptors to cap pebble
+	// to use
+	minHandleCap = 64
+
+	// pebbleByteOverhead is the number of bytes of constant overhead that
+	// should be added to a batch size per operation.
+	pebbleByteOverhead = 8
+
+	// MetricsProperty is the property that Stat reports pebble's metrics for
+	MetricsProperty = "pebble.metrics"
+)
+
+var (
+	errUnknownProperty = errors.New("unknown property")
+
+	_ database.Database         = &Database{}
+	_ database.CompactionStater = &Database{}
+	_ database.Snapshotter      = &Database{}
+	_ database.Batch            = &batch{}
+	_ database.Iterator         = &iter{}
+	_ database.Snapshot         = &snapshot{}
+)
+
+// Database is a persistent key-value store backed by pebble, a log-structured
+// merge tree. Apart from basic data storage functionality it also supports
+// batch writes and iterating over the keyspace in binary-alphabetical order.
+type Database struct {
+	// lock protects [closed] and [openIterators]. It is held for reading
+	// while the underlying database is used, because pebble panics if it is
+	// used after being closed.
+	lock sync.RWMutex
+	db   *pebble.DB
+	log  logging.Logger
+
+	closed bool
+	// Number of iterators and snapshots that haven't been released. Pebble
+	// can't be closed while it has open iterators, so if the database is
+	// closed while there are any, pebble is closed when the last of them is
+	// released.
+	openIterators int
+
+	// 1 if there was previously an error other than "not found" or "closed"
+	// while performing a db operation. If [errored] == 1, Has, Get, Put,
+	// Delete and batch writes fail with ErrAvoidCorruption.
+	errored uint64
+}
+
+// New returns a wrapped pebble object.
+func New(file string, log logging.Logger, cacheSize, memTableSize, handleCap int) (*Database, error) {
+	return NewWithWAL(file, "", log, cacheSize, memTableSize, handleCap)
+}
+
+// NewWithWAL returns a wrapped pebble object whose write-ahead log is kept in
+// the directory [walDir] instead of in [file], if [walDir] isn't empty
+func NewWithWAL(file, walDir string, log logging.Logger, cacheSize, memTableSize, handleCap int) (*Database, error) {
+	// Enforce minimums
+	if cacheSize < minCacheSize {
+		cacheSize = minCacheSize
+	}
+	if memTableSize < minMemTableSize {
+		memTableSize = minMemTableSize
+	}
+	if handleCap < minHandleCap {
+		handleCap = minHandleCap
+	}
+
+	cache := pebble.NewCache(int64(cacheSize))
+	defer cache.Unref()
+
+	return open(file, log, &pebble.Options{
+		Cache:        cache,
+		MemTableSize: memTableSize,
+		MaxOpenFiles: handleCap,
+		Logger:       &logger{log: log},
+		WALDir:       walDir,
+	})
+}
+
+// NewReadOnly returns a wrapped pebble object of the existing database in
+// [file], which is opened read-only. Writes to the returned database fail.
+func NewReadOnly(file string, log logging.Logger, cacheSize, handleCap int) (*Database, error) {
+	return NewReadOnlyWithWAL(file, "", log, cacheSize, handleCap)
+}
+
+// NewReadOnlyWithWAL is like NewReadOnly for a database whose write-ahead log
+// is kept in the directory [walDir], if it isn't empty
+func NewReadOnlyWithWAL(file, walDir string, log logging.Logger, cacheSize, handleCap int) (*Database, error) {
+	// Enforce minimums
+	if cacheSize < minCacheSize {
+		cacheSize = minCacheSize
+	}
+	if handleCap < minHandleCap {
+		handleCap = minHandleCap
+	}
+
+	cache := pebble.NewCache(int64(cacheSize))
+	defer cache.Unref()
+
+	return open(file, log, &pebble.Options{
+		Cache:            cache,
+		MaxOpenFiles:     handleCap,
+		Logger:           &logger{log: log},
+		ErrorIfNotExists: true,
+		ReadOnly:         true,
+		WALDir:           walDir,
+	})
+}
+
+func open(file string, log logging.Logger, opts *pebble.Options) (*Database, error) {
+	opts.Levels = make([]pebble.LevelOptions, 7)
+	for i := range opts.Levels {
+		opts.Levels[i].FilterPolicy = bloom.FilterPolicy(10)
+	}
+	if opts.WALDir != "" && !opts.ReadOnly {
+		if err := moveWALs(file, opts.WALDir); err != nil {
+			return nil, err
+		}
+	}
+	db, err := pebble.Open(file, opts.EnsureDefaults())
+	if err != nil {
+		return nil, err
+	}
+	return &Database{
+		db:  db,
+		log: log,
+	}, nil
+}
+
+// Has returns if the key is set in the database
+func (db *Database) Has(key []byte) (bool, error) {
+	_, err := db.Get(key)
+	switch err {
+	case nil:
+		return true, nil
+	case database.ErrNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Get returns the value the key maps to in the database
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	if db.corrupted() {
+		return nil, database.ErrAvoidCorruption
+	}
+	value, closer, err := db.db.Get(key)
+	if err != nil {
+		return nil, db.handleError(err)
+	}
+	// [value] is only valid until [closer] is closed
+	value = utils.CopyBytes(value)
+	return value, db.handleError(closer.Close())
+}
+
+// Put sets the value of the provided key to the provided value
+func (db *Database) Put(key []byte, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	if db.corrupted() {
+		return database.ErrAvoidCorruption
+	}
+	return db.handleError(db.db.Set(key, value, pebble.NoSync))
+}
+
+// Delete removes the key from the database
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	if db.corrupted() {
+		return database.ErrAvoidCorruption
+	}
+	return db.handleError(db.db.Delete(key, pebble.NoSync))
+}
+
+// NewBatch creates a write/delete-only buffer that is atomically committed to
+// the database when write is called
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		db:    db,
+		batch: db.db.NewBatch(),
+	}
+}
+
+// NewIterator creates a lexicographically ordered iterator over the database
+func (db *Database) NewIterator() database.Iterator {
+	return db.newIterator(&pebble.IterOptions{})
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// database starting at the provided key
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.newIterator(&pebble.IterOptions{LowerBound: start})
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// database ignoring keys that do not start with the provided prefix
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.newIterator(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the database starting at start and ignoring keys that do not start with
+// the provided prefix
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	opts := &pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	}
+	if bytes.Compare(start, prefix) == 1 {
+		opts.LowerBound = start
+	}
+	return db.newIterator(opts)
+}
+
+func (db *Database) newIterator(opts *pebble.IterOptions) database.Iterator {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	db.openIterators++
+	return &iter{
+		db:   db,
+		iter: db.db.NewIter(opts),
+	}
+}
+
+// NewSnapshot implements the Snapshotter interface
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	db.openIterators++
+	return &snapshot{
+		db:   db,
+		snap: db.db.NewSnapshot(),
+	}, nil
+}
+
+// Stat returns a particular internal stat of the database. The only supported
+// property is MetricsProperty.
+func (db *Database) Stat(property string) (string, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return "", database.ErrClosed
+	}
+	if property != MetricsProperty {
+		return "", errUnknownProperty
+	}
+	return db.db.Metrics().String(), nil
+}
+
+// Compact the underlying DB for the given key range.
+// Specifically, deleted and overwritten versions are discarded,
+// and the data is rearranged to reduce the cost of operations
+// needed to access the data. This operation should typically only
+// be invoked by users who understand the underlying implementation.
+//
+// A nil start is treated as a key before all keys in the DB.
+// And a nil limit is treated as a key after all keys in the DB.
+// Therefore if both are nil then it will compact entire DB.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	if limit == nil {
+		// Pebble requires an end key, so the key after the last key in the
+		// database is used
+		it := db.db.NewIter(&pebble.IterOptions{})
+		if it.Last() {
+			limit = append(utils.CopyBytes(it.Key()), 0)
+		}
+		if err := it.Close(); err != nil {
+			return db.handleError(err)
+		}
+		if limit == nil {
+			// The database is empty
+			return nil
+		}
+	}
+	if start == nil {
+		start = []byte{}
+	}
+	if bytes.Compare(start, limit) >= 0 {
+		return nil
+	}
+	return db.handleError(db.db.Compact(start, limit))
+}
+
+// CompactionStats implements the CompactionStater interface
+func (db *Database) CompactionStats() (database.CompactionStats, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.CompactionStats{}, database.ErrClosed
+	}
+	metrics := db.db.Metrics()
+	return database.CompactionStats{
+		Debt:              metrics.Compact.EstimatedDebt,
+		ReadAmplification: uint64(metrics.ReadAmp()),
+	}, nil
+}
+
+// Close implements the Database interface
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.closed = true
+	if db.openIterators > 0 {
+		return nil
+	}
+	return db.handleError(db.db.Close())
+}
+
+// releaseIterator is called when an iterator is released. If this was the
+// last open iterator of a closed database, pebble is closed.
+func (db *Database) releaseIterator() {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.openIterators--
+	if !db.closed || db.openIterators > 0 {
+		return
+	}
+	if err := db.db.Close(); err != nil {
+		db.log.Error("failed to close pebble after its last iterator was released: %s", err)
+	}
+}
+
+func (db *Database) corrupted() bool {
+	return atomic.LoadUint64(&db.errored) == 1
+}
+
+func (db *Database) handleError(err error) error {
+	err = updateError(err)
+	switch err {
+	case nil, database.ErrNotFound, database.ErrClosed:
+	// If we get an error other than "not found" or "closed", disallow future
+	// database operations to avoid possible corruption
+	default:
+		db.log.Fatal("pebble error: %s", err)
+		atomic.StoreUint64(&db.errored, 1)
+	}
+	return err
+}
+
+// batch is a wrapper around a pebble batch to contain sizes.
+type batch struct {
+	batch *pebble.Batch
+	db    *Database
+	size  int
+
+	// true if [batch] was committed. Pebble doesn't allow a batch to be
+	// committed more than once.
+	written bool
+}
+
+// Put the value into the batch for later writing
+func (b *batch) Put(key, value []byte) error {
+	b.size += len(key) + len(value) + pebbleByteOverhead
+	return b.batch.Set(key, value, nil)
+}
+
+// Delete the key during writing
+func (b *batch) Delete(key []byte) error {
+	b.size += len(key) + pebbleByteOverhead
+	return b.batch.Delete(key, nil)
+}
+
+// Size retrieves the amount of data queued up for writing.
+func (b *batch) Size() int { return b.size }
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.closed {
+		return database.ErrClosed
+	}
+	if b.db.corrupted() {
+		return database.ErrAvoidCorruption
+	}
+	if b.written {
+		// Copy the operations into a new batch so that they can be committed
+		// again
+		newBatch := b.db.db.NewBatch()
+		if err := newBatch.SetRepr(utils.CopyBytes(b.batch.Repr())); err != nil {
+			return b.db.handleError(err)
+		}
+		b.batch = newBatch
+	}
+	b.written = true
+	return b.db.handleError(b.batch.Commit(pebble.NoSync))
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.batch.Reset()
+	b.size = 0
+	b.written = false
+}
+
+// Replay the batch contents.
+func (b *batch) Replay(w database.KeyValueWriter) error {
+	reader := b.batch.Reader()
+	for {
+		kind, key, value, ok := reader.Next()
+		if !ok {
+			return nil
+		}
+		switch kind {
+		case pebble.InternalKeyKindSet:
+			if err := w.Put(key, value); err != nil {
+				return err
+			}
+		case pebble.InternalKeyKindDelete:
+			if err := w.Delete(key); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
+type iter struct {
+	db   *Database
+	iter *pebble.Iterator
+
+	started  bool
+	valid    bool
+	err      error
+	released bool
+}
+
+// Next implements the Iterator interface
+func (it *iter) Next() bool {
+	if it.released {
+		return false
+	}
+	if it.started {
+		it.valid = it.iter.Next()
+	} else {
+		it.started = true
+		it.valid = it.iter.First()
+	}
+	return it.valid
+}
+
+// Error implements the Iterator interface
+func (it *iter) Error() error {
+	if it.released {
+		return it.err
+	}
+	return updateError(it.iter.Error())
+}
+
+// Key implements the Iterator interface
+func (it *iter) Key() []byte {
+	if it.released || !it.valid {
+		return nil
+	}
+	return utils.CopyBytes(it.iter.Key())
+}
+
+// Value implements the Iterator interface
+func (it *iter) Value() []byte {
+	if it.released || !it.valid {
+		return nil
+	}
+	return utils.CopyBytes(it.iter.Value())
+}
+
+// Release implements the Iterator interface
+func (it *iter) Release() {
+	if it.released {
+		return
+	}
+	it.released = true
+	it.valid = false
+	it.err = updateError(it.iter.Close())
+	it.db.releaseIterator()
+}
+
+// snapshot is a wrapper around a pebble snapshot. Its iterators count as open
+// iterators of the database, like the snapshot itself.
+type snapshot struct {
+	db   *Database
+	snap *pebble.Snapshot
+}
+
+// Has implements the Snapshot interface
+func (s *snapshot) Has(key []byte) (bool, error) {
+	_, err := s.Get(key)
+	switch err {
+	case nil:
+		return true, nil
+	case database.ErrNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Get implements the Snapshot interface
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	value, closer, err := s.snap.Get(key)
+	if err != nil {
+		return nil, s.db.handleError(err)
+	}
+	// [value] is only valid until [closer] is closed
+	value = utils.CopyBytes(value)
+	return value, s.db.handleError(closer.Close())
+}
+
+// NewIterator implements the Snapshot interface
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.newIterator(&pebble.IterOptions{})
+}
+
+// NewIteratorWithStart implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.newIterator(&pebble.IterOptions{LowerBound: start})
+}
+
+// NewIteratorWithPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.newIterator(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+}
+
+// NewIteratorWithStartAndPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	opts := &pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	}
+	if bytes.Compare(start, prefix) == 1 {
+		opts.LowerBound = start
+	}
+	return s.newIterator(opts)
+}
+
+func (s *snapshot) newIterator(opts *pebble.IterOptions) database.Iterator {
+	s.db.lock.Lock()
+	defer s.db.lock.Unlock()
+
+	s.db.openIterators++
+	return &iter{
+		db:   s.db,
+		iter: s.snap.NewIter(opts),
+	}
+}
+
+// Release implements the Snapshot interface
+func (s *snapshot) Release() {
+	if err := s.snap.Close(); err != nil {
+		s.db.log.Error("failed to release pebble snapshot: %s", err)
+	}
+	s.db.releaseIterator()
+}
+
+// prefixUpperBound returns the smallest key that is larger than every key
+// starting with [prefix], or nil if there is no such key
+func prefixUpperBound(prefix []byte) []byte {
+	upperBound := utils.CopyBytes(prefix)
+	for i := len(upperBound) - 1; i >= 0; i-- {
+		upperBound[i]++
+		if upperBound[i] != 0 {
+			return upperBound[:i+1]
+		}
+	}
+	return nil
+}
+
+func updateError(err error) error {
+	switch {
+	case errors.Is(err, pebble.ErrClosed):
+		return database.ErrClosed
+	case errors.Is(err, pebble.ErrNotFound):
+		return database.ErrNotFound
+	default:
+		return err
+	}
+}
+
+// logger passes pebble's logs to an avalanchego logger
+type logger struct{ log logging.Logger }
+
+func (l *logger) Infof(format string, args ...interface{}) { l.log.Debug(format, args...) }
+
+// Fatalf is expected by pebble to not return
+func (l *logger) Fatalf(format string, args ...interface{}) {
+	l.log.Fatal(format, args...)
+	panic(fmt.Sprintf(format, args...))
+}
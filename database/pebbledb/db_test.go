@@ -0,0 +1,133 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebbledb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		folder := t.TempDir()
+		db, err := New(folder, logging.NoLog{}, 0, 0, 0)
+		if err != nil {
+			t.Fatalf("pebbledb.New(%q, logging.NoLog{}, 0, 0, 0) errored with %s", folder, err)
+		}
+
+		// The database may have been closed by the test, so we don't care if it
+		// errors here.
+		defer db.Close()
+
+		test(t, db)
+	}
+}
+
+func TestPrefixUpperBound(t *testing.T) {
+	tests := []struct {
+		prefix   []byte
+		expected []byte
+	}{
+		{prefix: nil, expected: nil},
+		{prefix: []byte{0x01}, expected: []byte{0x02}},
+		{prefix: []byte{0x01, 0xff}, expected: []byte{0x02}},
+		{prefix: []byte{0xff, 0xff}, expected: nil},
+	}
+	for _, test := range tests {
+		if upperBound := prefixUpperBound(test.prefix); string(upperBound) != string(test.expected) {
+			t.Fatalf("prefixUpperBound(0x%x) returned 0x%x but expected 0x%x", test.prefix, upperBound, test.expected)
+		}
+	}
+}
+
+func BenchmarkInterface(b *testing.B) {
+	for _, size := range database.BenchmarkSizes {
+		keys, values := database.SetupBenchmark(b, size, size)
+		for _, bench := range database.Benchmarks {
+			folder := b.TempDir()
+
+			db, err := New(folder, logging.NoLog{}, 0, 0, 0)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			defer db.Close()
+
+			bench(b, db, "pebbledb", keys, values)
+		}
+	}
+}
+
+func TestCompactionStats(t *testing.T) {
+	db, err := New(t.TempDir(), logging.NoLog{}, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for i := 0; i < 1024; i++ {
+		if err := db.Put([]byte{byte(i), byte(i >> 8)}, make([]byte, 1024)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.CompactionStats(); err != nil {
+		t.Fatalf("CompactionStats errored with %s", err)
+	}
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := db.CompactionStats()
+	if err != nil {
+		t.Fatalf("CompactionStats errored with %s", err)
+	}
+	if stats.Debt != 0 {
+		t.Fatalf("expected no compaction debt after compacting but got %d", stats.Debt)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	db, err := New(t.TempDir(), logging.NoLog{}, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.TestSnapshot(t, db)
+}
+
+func TestReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewReadOnly(filepath.Join(dir, "missing"), logging.NoLog{}, 0, 0); err == nil {
+		t.Fatal("opening a missing database read-only should have failed")
+	}
+
+	db, err := New(dir, logging.NoLog{}, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = NewReadOnly(dir, logging.NoLog{}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	value, err := db.Get([]byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != "value" {
+		t.Fatalf("expected %q but got %q", "value", value)
+	}
+	if err := db.Put([]byte("key"), []byte("other")); err == nil {
+		t.Fatal("writing to a read-only database should have failed")
+	}
+}
@@ -0,0 +1,60 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebbledb
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// moveWALs moves the write-ahead logs that were written to [dir], before the
+// write-ahead log was kept in [walDir], to [walDir]. Pebble only replays the
+// logs in [walDir], so they would otherwise be lost.
+func moveWALs(dir, walDir string) error {
+	logs, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil || len(logs) == 0 {
+		return err
+	}
+	if err := os.MkdirAll(walDir, 0o750); err != nil {
+		return err
+	}
+	for _, log := range logs {
+		dst := filepath.Join(walDir, filepath.Base(log))
+		if err := os.Rename(log, dst); err == nil {
+			continue
+		}
+		// [walDir] may be on another device, so the log is copied instead
+		if err := copyFile(log, dst); err != nil {
+			return fmt.Errorf("couldn't move %s to %s: %w", log, walDir, err)
+		}
+		if err := os.Remove(log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -181,7 +181,8 @@ func (db *Database) Stat(stat string) (string, error) {
 	return db.db.Stat(stat)
 }
 
-// Compact implements the Database interface
+// Compact implements the Database interface. A nil limit is treated as a key
+// after all the keys of this database.
 func (db *Database) Compact(start, limit []byte) error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
@@ -189,9 +190,26 @@ func (db *Database) Compact(start, limit []byte) error {
 	if db.db == nil {
 		return database.ErrClosed
 	}
+	if limit == nil {
+		return db.db.Compact(db.prefix(start), prefixLimit(db.dbPrefix))
+	}
 	return db.db.Compact(db.prefix(start), db.prefix(limit))
 }
 
+// prefixLimit returns the smallest key that is larger than every key starting
+// with [prefix], or nil if there is no such key
+func prefixLimit(prefix []byte) []byte {
+	limit := make([]byte, len(prefix))
+	copy(limit, prefix)
+	for i := len(limit) - 1; i >= 0; i-- {
+		limit[i]++
+		if limit[i] != 0 {
+			return limit[:i+1]
+		}
+	}
+	return nil
+}
+
 // Close implements the Database interface
 func (db *Database) Close() error {
 	db.lock.Lock()
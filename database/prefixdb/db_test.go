@@ -4,10 +4,12 @@
 package prefixdb
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/mockdb"
 )
 
 func TestInterface(t *testing.T) {
@@ -31,3 +33,27 @@ func BenchmarkInterface(b *testing.B) {
 		}
 	}
 }
+
+func TestCompactWholePrefix(t *testing.T) {
+	var (
+		compactedStart []byte
+		compactedLimit []byte
+	)
+	baseDB := mockdb.New()
+	baseDB.OnCompact = func(start, limit []byte) error {
+		compactedStart = start
+		compactedLimit = limit
+		return nil
+	}
+
+	db := New([]byte("hello"), baseDB)
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(compactedStart, db.dbPrefix) {
+		t.Fatalf("compacted from 0x%x but expected 0x%x", compactedStart, db.dbPrefix)
+	}
+	if bytes.Compare(compactedLimit, db.dbPrefix) <= 0 || bytes.HasPrefix(compactedLimit, db.dbPrefix) {
+		t.Fatalf("compacted up to 0x%x, which doesn't cover the keys with prefix 0x%x", compactedLimit, db.dbPrefix)
+	}
+}
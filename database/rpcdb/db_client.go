@@ -4,6 +4,8 @@
 package rpcdb
 
 import (
+	"crypto/rand"
+	"encoding/binary"
 	"sync/atomic"
 
 	"golang.org/x/net/context"
@@ -37,7 +39,19 @@ type DatabaseClient struct {
 
 // NewClient returns a database instance connected to a remote database instance
 func NewClient(client rpcdbproto.DatabaseClient) *DatabaseClient {
-	return &DatabaseClient{client: client}
+	return &DatabaseClient{
+		client: client,
+		// The server tells batches apart by their IDs, so the IDs start at a
+		// random value to keep them apart from those of the server's other
+		// clients
+		batchIndex: randomBatchIndex(),
+	}
+}
+
+func randomBatchIndex() int64 {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return int64(binary.BigEndian.Uint64(b) >> 1)
 }
 
 // Has attempts to return if the database has a key with the provided value.
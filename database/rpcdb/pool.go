@@ -0,0 +1,105 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// Requests of methods with this prefix move iterators on the server, so they
+// aren't retried in case the server handled them before they failed
+const iteratorMethodPrefix = "/rpcdbproto.Database/Iterator"
+
+var (
+	errNoConnections = errors.New("number of connections must be positive")
+
+	_ grpc.ClientConnInterface = &Pool{}
+)
+
+// Pool is a pool of connections to a remote database server. Requests are
+// spread over the connections, and requests that fail because the server is
+// unavailable are retried with an exponential backoff.
+type Pool struct {
+	conns      []*grpc.ClientConn
+	next       uint32
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// Dial returns a pool of [numConns] connections to the database server at
+// [address], secured with [security]. The connections are established in the
+// background, so requests may fail until the server is reachable. A request
+// is retried up to [maxRetries] times, the first time after [retryDelay].
+func Dial(address string, security Security, numConns, maxRetries int, retryDelay time.Duration) (*Pool, error) {
+	if numConns <= 0 {
+		return nil, errNoConnections
+	}
+	if err := security.Verify(address); err != nil {
+		return nil, err
+	}
+	p := &Pool{
+		conns:      make([]*grpc.ClientConn, 0, numConns),
+		maxRetries: maxRetries,
+		retryDelay: retryDelay,
+	}
+	for i := 0; i < numConns; i++ {
+		conn, err := grpc.Dial(address, security.dialOptions()...)
+		if err != nil {
+			_ = p.Close()
+			return nil, err
+		}
+		p.conns = append(p.conns, conn)
+	}
+	return p, nil
+}
+
+func (p *Pool) conn() *grpc.ClientConn {
+	i := atomic.AddUint32(&p.next, 1)
+	return p.conns[i%uint32(len(p.conns))]
+}
+
+// Invoke implements the grpc.ClientConnInterface interface
+func (p *Pool) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	delay := p.retryDelay
+	for retries := 0; ; retries++ {
+		err := p.conn().Invoke(ctx, method, args, reply, opts...)
+		if retries == p.maxRetries ||
+			status.Code(err) != codes.Unavailable ||
+			strings.HasPrefix(method, iteratorMethodPrefix) {
+			return err
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// NewStream implements the grpc.ClientConnInterface interface
+func (p *Pool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return p.conn().NewStream(ctx, desc, method, opts...)
+}
+
+// Close the connections of the pool
+func (p *Pool) Close() error {
+	errs := wrappers.Errs{}
+	for _, conn := range p.conns {
+		errs.Add(conn.Close())
+	}
+	return errs.Err
+}
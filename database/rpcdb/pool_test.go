@@ -0,0 +1,97 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/rpcdb/rpcdbproto"
+)
+
+func serve(t *testing.T, listener net.Listener) *grpc.Server {
+	server := grpc.NewServer()
+	rpcdbproto.RegisterDatabaseServer(server, NewServer(memdb.New()))
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			t.Errorf("Server exited with error: %v", err)
+		}
+	}()
+	return server
+}
+
+func TestPoolInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := serve(t, listener)
+
+		pool, err := Dial(listener.Addr().String(), Security{}, 3, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		test(t, NewClient(rpcdbproto.NewDatabaseClient(pool)))
+
+		server.Stop()
+		_ = pool.Close()
+	}
+}
+
+func TestPoolRetriesUnavailableServer(t *testing.T) {
+	assert := assert.New(t)
+
+	// Reserve an address that nothing listens on yet
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	address := listener.Addr().String()
+	assert.NoError(listener.Close())
+
+	pool, err := Dial(address, Security{}, 2, 0, time.Millisecond)
+	assert.NoError(err)
+	db := NewClient(rpcdbproto.NewDatabaseClient(pool))
+	err = db.Put([]byte("key"), []byte("value"))
+	assert.Equal(codes.Unavailable, status.Code(err))
+	assert.NoError(pool.Close())
+
+	// The server starts while the request is retried
+	pool, err = Dial(address, Security{}, 2, 10, 10*time.Millisecond)
+	assert.NoError(err)
+	defer pool.Close()
+	servers := make(chan *grpc.Server, 1)
+	time.AfterFunc(50*time.Millisecond, func() {
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			t.Error(err)
+			close(servers)
+			return
+		}
+		servers <- serve(t, listener)
+	})
+	defer func() {
+		if server := <-servers; server != nil {
+			server.Stop()
+		}
+	}()
+	db = NewClient(rpcdbproto.NewDatabaseClient(pool))
+	assert.NoError(db.Put([]byte("key"), []byte("value")))
+	value, err := db.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+}
+
+func TestDialNoConnections(t *testing.T) {
+	_, err := Dial("127.0.0.1:0", Security{}, 0, 0, 0)
+	assert.Equal(t, errNoConnections, err)
+}
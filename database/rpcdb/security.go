@@ -0,0 +1,125 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+
+	"golang.org/x/net/context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata key of the requests that the authentication key is sent in
+const authKeyMetadataKey = "rpcdb-auth-key"
+
+var (
+	errInsecureAddress = errors.New("databases can only be served and dialed without TLS and an authentication key on loopback addresses")
+
+	errUnauthenticated = status.Error(codes.Unauthenticated, "invalid authentication key")
+)
+
+// Security is how the connections between a database server and its clients
+// are secured
+type Security struct {
+	// TLS config of the connections. If nil, the connections aren't
+	// encrypted.
+	TLS *tls.Config
+	// Key that clients authenticate with. If nil, clients aren't
+	// authenticated.
+	AuthKey []byte
+}
+
+// Verify that the database can be served on, or dialed at, [address] with
+// this security. Without both TLS and an authentication key, anyone that can
+// reach the server can read and write the database, so it's only allowed on
+// loopback addresses.
+func (s Security) Verify(address string) error {
+	if s.TLS != nil && len(s.AuthKey) != 0 {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("couldn't parse address %s: %w", address, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return errInsecureAddress
+}
+
+// dialOptions returns the options that clients dial the server with
+func (s Security) dialOptions() []grpc.DialOption {
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if s.TLS != nil {
+		opts[0] = grpc.WithTransportCredentials(credentials.NewTLS(s.TLS))
+	}
+	if len(s.AuthKey) != 0 {
+		opts = append(opts, grpc.WithPerRPCCredentials(authKeyCredentials{
+			authKey:              hex.EncodeToString(s.AuthKey),
+			requireTransportAuth: s.TLS != nil,
+		}))
+	}
+	return opts
+}
+
+// ServerOptions returns the options that the server must be created with
+func (s Security) ServerOptions() []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if s.TLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.TLS)))
+	}
+	if len(s.AuthKey) != 0 {
+		authKey := []byte(hex.EncodeToString(s.AuthKey))
+		opts = append(opts,
+			grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				if !authenticated(ctx, authKey) {
+					return nil, errUnauthenticated
+				}
+				return handler(ctx, req)
+			}),
+			grpc.StreamInterceptor(func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				if !authenticated(stream.Context(), authKey) {
+					return errUnauthenticated
+				}
+				return handler(srv, stream)
+			}),
+		)
+	}
+	return opts
+}
+
+// authenticated returns true if the request of [ctx] was sent with [authKey]
+func authenticated(ctx context.Context, authKey []byte) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	values := md.Get(authKeyMetadataKey)
+	return len(values) == 1 && subtle.ConstantTimeCompare([]byte(values[0]), authKey) == 1
+}
+
+// authKeyCredentials sends the authentication key with each request
+type authKeyCredentials struct {
+	authKey              string
+	requireTransportAuth bool
+}
+
+func (c authKeyCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{authKeyMetadataKey: c.authKey}, nil
+}
+
+func (c authKeyCredentials) RequireTransportSecurity() bool { return c.requireTransportAuth }
@@ -0,0 +1,93 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/rpcdb/rpcdbproto"
+)
+
+func TestSecurityVerify(t *testing.T) {
+	secure := Security{
+		TLS:     &tls.Config{},
+		AuthKey: []byte{1},
+	}
+	tests := []struct {
+		address  string
+		security Security
+		err      bool
+	}{
+		{"127.0.0.1:9652", Security{}, false},
+		{"[::1]:9652", Security{}, false},
+		{"localhost:9652", Security{}, false},
+		{"0.0.0.0:9652", Security{}, true},
+		{"10.0.0.1:9652", Security{}, true},
+		{"10.0.0.1:9652", Security{AuthKey: []byte{1}}, true},
+		{"10.0.0.1:9652", Security{TLS: &tls.Config{}}, true},
+		{"10.0.0.1:9652", secure, false},
+		{"db.example.com:9652", secure, false},
+		{"10.0.0.1", Security{}, true},
+	}
+	for _, test := range tests {
+		err := test.security.Verify(test.address)
+		if test.err {
+			assert.Error(t, err, test.address)
+		} else {
+			assert.NoError(t, err, test.address)
+		}
+	}
+
+	_, err := Dial("10.0.0.1:9652", Security{}, 1, 0, 0)
+	assert.Equal(t, errInsecureAddress, err)
+}
+
+func TestAuthKey(t *testing.T) {
+	assert := assert.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	server := grpc.NewServer(Security{AuthKey: []byte{1, 2, 3}}.ServerOptions()...)
+	rpcdbproto.RegisterDatabaseServer(server, NewServer(memdb.New()))
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			t.Errorf("Server exited with error: %v", err)
+		}
+	}()
+	defer server.Stop()
+	address := listener.Addr().String()
+
+	for _, authKey := range [][]byte{nil, {1, 2, 4}} {
+		pool, err := Dial(address, Security{AuthKey: authKey}, 1, 0, 0)
+		assert.NoError(err)
+		db := NewClient(rpcdbproto.NewDatabaseClient(pool))
+		err = db.Put([]byte("key"), []byte("value"))
+		assert.Equal(codes.Unauthenticated, status.Code(err))
+		_, err = db.Get([]byte("key"))
+		assert.Equal(codes.Unauthenticated, status.Code(err))
+		it := db.NewIterator()
+		assert.False(it.Next())
+		assert.Error(it.Error())
+		it.Release()
+		assert.NoError(pool.Close())
+	}
+
+	pool, err := Dial(address, Security{AuthKey: []byte{1, 2, 3}}, 1, 0, 0)
+	assert.NoError(err)
+	defer pool.Close()
+	db := NewClient(rpcdbproto.NewDatabaseClient(pool))
+	assert.NoError(db.Put([]byte("key"), []byte("value")))
+	value, err := db.Get([]byte("key"))
+	assert.NoError(err)
+	assert.Equal([]byte("value"), value)
+}
@@ -0,0 +1,23 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+// Snapshot is a read-only view of the contents of a database at the time the
+// snapshot was taken. Writes to the database after that time aren't visible
+// through the snapshot.
+type Snapshot interface {
+	KeyValueReader
+	Iteratee
+
+	// Release the snapshot. The snapshot must not be used after it is
+	// released.
+	Release()
+}
+
+// Snapshotter is implemented by databases that can take snapshots of their
+// contents
+type Snapshotter interface {
+	// NewSnapshot returns a snapshot of the current contents of the database
+	NewSnapshot() (Snapshot, error)
+}
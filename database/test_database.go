@@ -975,3 +975,74 @@ func TestCompactNoPanic(t *testing.T, db Database) {
 		t.Fatalf("Expected error %s on db.Close but got %s", ErrClosed, err)
 	}
 }
+
+// TestSnapshot tests to make sure that a snapshot of [db], which must be a
+// Snapshotter, doesn't see the writes made after it was taken.
+func TestSnapshot(t *testing.T, db Database) {
+	snapshotter, ok := db.(Snapshotter)
+	if !ok {
+		t.Fatalf("Database doesn't take snapshots")
+	}
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+
+	key2 := []byte("hello2")
+	value2 := []byte("world2")
+
+	if err := db.Put(key1, value1); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+
+	snapshot, err := snapshotter.NewSnapshot()
+	if err != nil {
+		t.Fatalf("Unexpected error on db.NewSnapshot: %s", err)
+	}
+
+	if err := db.Put(key2, value2); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	} else if err := db.Delete(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Delete: %s", err)
+	}
+
+	if value, err := snapshot.Get(key1); err != nil {
+		t.Fatalf("Unexpected error on snapshot.Get: %s", err)
+	} else if !bytes.Equal(value, value1) {
+		t.Fatalf("snapshot.Get: Returned: 0x%x ; Expected: 0x%x", value, value1)
+	} else if has, err := snapshot.Has(key2); err != nil {
+		t.Fatalf("Unexpected error on snapshot.Has: %s", err)
+	} else if has {
+		t.Fatalf("snapshot.Has unexpectedly returned true")
+	} else if _, err := snapshot.Get(key2); err != ErrNotFound {
+		t.Fatalf("Expected error %s on snapshot.Get but got %s", ErrNotFound, err)
+	}
+
+	iterator := snapshot.NewIteratorWithPrefix([]byte("hello"))
+	if !iterator.Next() {
+		t.Fatalf("iterator.Next Returned: %v ; Expected: %v", false, true)
+	} else if key := iterator.Key(); !bytes.Equal(key, key1) {
+		t.Fatalf("iterator.Key Returned: 0x%x ; Expected: 0x%x", key, key1)
+	} else if value := iterator.Value(); !bytes.Equal(value, value1) {
+		t.Fatalf("iterator.Value Returned: 0x%x ; Expected: 0x%x", value, value1)
+	} else if iterator.Next() {
+		t.Fatalf("iterator.Next Returned: %v ; Expected: %v", true, false)
+	} else if err := iterator.Error(); err != nil {
+		t.Fatalf("iterator.Error Returned: %s ; Expected: nil", err)
+	}
+	iterator.Release()
+	snapshot.Release()
+
+	// The database still sees the writes made after the snapshot was taken
+	if value, err := db.Get(key2); err != nil {
+		t.Fatalf("Unexpected error on db.Get: %s", err)
+	} else if !bytes.Equal(value, value2) {
+		t.Fatalf("db.Get: Returned: 0x%x ; Expected: 0x%x", value, value2)
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("Unexpected error on db.Close: %s", err)
+	}
+	if _, err := snapshotter.NewSnapshot(); err != ErrClosed {
+		t.Fatalf("Expected error %s on db.NewSnapshot but got %s", ErrClosed, err)
+	}
+}
@@ -0,0 +1,109 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package verify finds, and where possible repairs, corrupted or inconsistent
+// data in a database.
+package verify
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// Verifier checks the consistency of the data that it manages in a database
+type Verifier interface {
+	// VerifyDB adds each inconsistency it finds to [report]. If [repair], the
+	// inconsistencies that can be repaired are repaired. Returns an error if
+	// the verification couldn't be done.
+	VerifyDB(repair bool, report *Report) error
+}
+
+// Issue is a corrupted or inconsistent piece of data
+type Issue struct {
+	// Key of the data, or ID of the container, that the issue is about
+	Item string `json:"item"`
+	// What is wrong with the data
+	Problem string `json:"problem"`
+	// True if the issue was repaired
+	Repaired bool `json:"repaired"`
+}
+
+// Report of the verification of a database, or of a part of one
+type Report struct {
+	// What was verified
+	Name string `json:"name"`
+	// Number of key/value pairs, or containers, that were checked
+	NumChecked uint64  `json:"numChecked"`
+	Issues     []Issue `json:"issues"`
+}
+
+// NewReport returns an empty report of the verification of [name]
+func NewReport(name string) *Report {
+	return &Report{
+		Name:   name,
+		Issues: []Issue{},
+	}
+}
+
+// Add an issue with [item] to the report
+func (r *Report) Add(item string, repaired bool, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, Issue{
+		Item:     item,
+		Problem:  fmt.Sprintf(format, args...),
+		Repaired: repaired,
+	})
+}
+
+// NumUnrepaired returns the number of issues that weren't repaired
+func (r *Report) NumUnrepaired() int {
+	numUnrepaired := 0
+	for _, issue := range r.Issues {
+		if !issue.Repaired {
+			numUnrepaired++
+		}
+	}
+	return numUnrepaired
+}
+
+// Log each issue of the report to [log], followed by a summary. Issues that
+// weren't repaired are logged as errors.
+func (r *Report) Log(log logging.Logger) {
+	for _, issue := range r.Issues {
+		if issue.Repaired {
+			log.Warn("repaired %s of %s: %s", issue.Item, r.Name, issue.Problem)
+		} else {
+			log.Error("couldn't repair %s of %s: %s", issue.Item, r.Name, issue.Problem)
+		}
+	}
+	log.Info("verified %s: checked %d items and found %d issues, of which %d weren't repaired",
+		r.Name,
+		r.NumChecked,
+		len(r.Issues),
+		r.NumUnrepaired(),
+	)
+}
+
+// Scan reads every key/value pair of [db], which makes the database check the
+// checksums of all of its data. Corrupted data can't be repaired and stops
+// the scan, since the keys after it can't be read reliably. The corruption is
+// added to [report] rather than returned.
+func Scan(db database.Iteratee, report *Report) {
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	var lastKey []byte
+	for iter.Next() {
+		report.NumChecked++
+		lastKey = append(lastKey[:0], iter.Key()...)
+	}
+	if err := iter.Error(); err != nil {
+		item := "first key"
+		if report.NumChecked > 0 {
+			item = hex.EncodeToString(lastKey)
+		}
+		report.Add(item, false, "data after this key couldn't be read: %s", err)
+	}
+}
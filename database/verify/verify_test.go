@@ -0,0 +1,55 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package verify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/nodb"
+)
+
+// corruptedDB fails to iterate over its data
+type corruptedDB struct {
+	database.Database
+	err error
+}
+
+func (db *corruptedDB) NewIterator() database.Iterator { return &nodb.Iterator{Err: db.err} }
+
+func TestScan(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	assert.NoError(db.Put([]byte{1}, []byte{2}))
+	assert.NoError(db.Put([]byte{3}, []byte{4}))
+
+	report := NewReport("test")
+	Scan(db, report)
+	assert.EqualValues(2, report.NumChecked)
+	assert.Empty(report.Issues)
+}
+
+func TestScanCorrupted(t *testing.T) {
+	assert := assert.New(t)
+
+	report := NewReport("test")
+	Scan(&corruptedDB{Database: memdb.New(), err: errors.New("checksum mismatch")}, report)
+	assert.Len(report.Issues, 1)
+	assert.Equal("first key", report.Issues[0].Item)
+	assert.Contains(report.Issues[0].Problem, "checksum mismatch")
+	assert.Equal(1, report.NumUnrepaired())
+}
+
+func TestReportNumUnrepaired(t *testing.T) {
+	report := NewReport("test")
+	report.Add("a", true, "repaired")
+	report.Add("b", false, "not repaired %d", 1)
+	assert.Equal(t, 1, report.NumUnrepaired())
+	assert.Equal(t, "not repaired 1", report.Issues[1].Problem)
+}
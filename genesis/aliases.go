@@ -13,6 +13,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/propertyfx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 	"github.com/ava-labs/avalanchego/vms/timestampvm"
+	"github.com/ava-labs/avalanchego/vms/wasmvm"
 )
 
 // Aliases returns the default aliases based on the network ID
@@ -61,6 +62,7 @@ func getGeneralAliases() map[string][]string {
 		"vm/" + avm.ID.String():                    {"vm/avm"},
 		"vm/" + evm.ID.String():                    {"vm/evm"},
 		"vm/" + timestampvm.ID.String():            {"vm/timestamp"},
+		"vm/" + wasmvm.ID.String():                 {"vm/wasm"},
 		"bc/" + constants.PlatformChainID.String(): {"P", "platform", "bc/P", "bc/platform"},
 	}
 }
@@ -71,6 +73,7 @@ func getChainAliases() map[ids.ID][]string {
 		avm.ID:         {"avm"},
 		evm.ID:         {"evm"},
 		timestampvm.ID: {"timestamp"},
+		wasmvm.ID:      {"wasm"},
 		secp256k1fx.ID: {"secp256k1fx"},
 		nftfx.ID:       {"nftfx"},
 		propertyfx.ID:  {"propertyfx"},
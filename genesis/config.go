@@ -12,7 +12,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
-	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 
 	safemath "github.com/ava-labs/avalanchego/utils/math"
@@ -39,7 +39,7 @@ func (a Allocation) Unparse(networkID uint32) (UnparsedAllocation, error) {
 		UnlockSchedule: a.UnlockSchedule,
 		ETHAddr:        "0x" + hex.EncodeToString(a.ETHAddr.Bytes()),
 	}
-	avaxAddr, err := formatting.FormatAddress(
+	avaxAddr, err := address.Format(
 		"X",
 		constants.GetHRP(networkID),
 		a.AVAXAddr.Bytes(),
@@ -57,13 +57,13 @@ type Staker struct {
 
 // Unparse ...
 func (s Staker) Unparse(networkID uint32) (UnparsedStaker, error) {
-	avaxAddr, err := formatting.FormatAddress(
+	avaxAddr, err := address.Format(
 		"X",
 		constants.GetHRP(networkID),
 		s.RewardAddress.Bytes(),
 	)
 	return UnparsedStaker{
-		NodeID:        s.NodeID.PrefixedString(constants.NodeIDPrefix),
+		NodeID:        address.FormatNodeID(s.NodeID),
 		RewardAddress: avaxAddr,
 		DelegationFee: s.DelegationFee,
 	}, err
@@ -107,7 +107,7 @@ func (c Config) Unparse() (UnparsedConfig, error) {
 		uc.Allocations[i] = ua
 	}
 	for i, isa := range c.InitialStakedFunds {
-		avaxAddr, err := formatting.FormatAddress(
+		avaxAddr, err := address.Format(
 			"X",
 			constants.GetHRP(uc.NetworkID),
 			isa.Bytes(),
@@ -8,8 +8,7 @@ import (
 	"errors"
 
 	"github.com/ava-labs/avalanchego/ids"
-	"github.com/ava-labs/avalanchego/utils/constants"
-	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 )
 
 // UnparsedAllocation ...
@@ -41,7 +40,7 @@ func (ua UnparsedAllocation) Parse() (Allocation, error) {
 	}
 	a.ETHAddr = ethAddr
 
-	_, _, avaxAddrBytes, err := formatting.ParseAddress(ua.AVAXAddr)
+	_, _, avaxAddrBytes, err := address.Parse(ua.AVAXAddr)
 	if err != nil {
 		return a, err
 	}
@@ -67,13 +66,13 @@ func (us UnparsedStaker) Parse() (Staker, error) {
 		DelegationFee: us.DelegationFee,
 	}
 
-	nodeID, err := ids.ShortFromPrefixedString(us.NodeID, constants.NodeIDPrefix)
+	nodeID, err := address.ParseNodeID(us.NodeID)
 	if err != nil {
 		return s, err
 	}
 	s.NodeID = nodeID
 
-	_, _, avaxAddrBytes, err := formatting.ParseAddress(us.RewardAddress)
+	_, _, avaxAddrBytes, err := address.Parse(us.RewardAddress)
 	if err != nil {
 		return s, err
 	}
@@ -123,7 +122,7 @@ func (uc UnparsedConfig) Parse() (Config, error) {
 		c.Allocations[i] = a
 	}
 	for i, isa := range uc.InitialStakedFunds {
-		_, _, avaxAddrBytes, err := formatting.ParseAddress(isa)
+		_, _, avaxAddrBytes, err := address.Parse(isa)
 		if err != nil {
 			return c, err
 		}
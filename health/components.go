@@ -0,0 +1,61 @@
+// (c) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Component is the result of checking one aspect of the health of a service
+type Component struct {
+	Healthy bool        `json:"healthy"`
+	Details interface{} `json:"details,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// Components are the results of checking several aspects of the health of a
+// service, keyed by the name of the aspect. Returned as the details of a health
+// check so that the reason for a failure is reported along with it.
+type Components map[string]Component
+
+// Add records the result of checking the component named [name]. If [err] is
+// non-nil, the component is unhealthy.
+func (c Components) Add(name string, details interface{}, err error) {
+	component := Component{
+		Healthy: err == nil,
+		Details: details,
+	}
+	if err != nil {
+		component.Error = err.Error()
+	}
+	c[name] = component
+}
+
+// Healthy returns true iff all the components are healthy
+func (c Components) Healthy() bool {
+	for _, component := range c {
+		if !component.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns an error describing the unhealthy components, or nil if all the
+// components are healthy
+func (c Components) Err() error {
+	failures := []string(nil)
+	for name, component := range c {
+		if !component.Healthy {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, component.Error))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	sort.Strings(failures)
+	return fmt.Errorf("unhealthy components: %s", strings.Join(failures, "; "))
+}
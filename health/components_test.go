@@ -0,0 +1,30 @@
+// (c) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComponents(t *testing.T) {
+	assert := assert.New(t)
+
+	components := Components{}
+	assert.True(components.Healthy())
+	assert.NoError(components.Err())
+
+	components.Add("vm", "details", nil)
+	assert.True(components.Healthy())
+	assert.NoError(components.Err())
+	assert.Equal(Component{Healthy: true, Details: "details"}, components["vm"])
+
+	components.Add("polls", 0.9, errors.New("too many failures"))
+	components.Add("bootstrapped", false, errors.New("not bootstrapped"))
+	assert.False(components.Healthy())
+	assert.Equal(Component{Details: 0.9, Error: "too many failures"}, components["polls"])
+	assert.EqualError(components.Err(), "unhealthy components: bootstrapped: not bootstrapped; polls: too many failures")
+}
@@ -0,0 +1,109 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Longest that an action may take to handle an alert
+const actionTimeout = 30 * time.Second
+
+var (
+	errEmptyCommand = errors.New("alert command is empty")
+
+	_ Action = &webhook{}
+	_ Action = &execHook{}
+)
+
+// Alert describes a condition of a chain that started or stopped holding
+type Alert struct {
+	Time time.Time `json:"time"`
+	// Primary alias of the chain
+	Chain   string `json:"chain"`
+	ChainID string `json:"chainID"`
+	// Condition is one of the Condition constants
+	Condition string `json:"condition"`
+	// True if the condition stopped holding
+	Resolved bool   `json:"resolved"`
+	Message  string `json:"message"`
+}
+
+// Action is taken when an alert fires
+type Action interface {
+	Fire(alert Alert) error
+}
+
+// NewWebhook returns an action that POSTs each alert as JSON to [url]
+func NewWebhook(url string) Action {
+	return &webhook{
+		url:    url,
+		client: &http.Client{Timeout: actionTimeout},
+	}
+}
+
+type webhook struct {
+	url    string
+	client *http.Client
+}
+
+func (w *webhook) Fire(alert Alert) error {
+	alertJSON, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	response, err := w.client.Post(w.url, "application/json", bytes.NewReader(alertJSON))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %s", response.Status)
+	}
+	return nil
+}
+
+// NewExecHook returns an action that runs [command] for each alert, with the
+// alert written to its standard input as JSON. [command] is split on white
+// space into the program and its arguments.
+func NewExecHook(command string) (Action, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errEmptyCommand
+	}
+	return &execHook{args: args}, nil
+}
+
+type execHook struct {
+	args []string
+}
+
+func (e *execHook) Fire(alert Alert) error {
+	alertJSON, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+	defer cancel()
+
+	// #nosec G204
+	cmd := exec.CommandContext(ctx, e.args[0], e.args[1:]...)
+	cmd.Stdin = bytes.NewReader(alertJSON)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("couldn't run alert command: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package watchdog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AllChains is the chain of rules that apply to every chain
+const AllChains = "*"
+
+var (
+	errNoRuleChain     = errors.New("rule must name a chain")
+	errNoRuleCondition = errors.New("rule must have a condition")
+	errNegativeMaxTime = errors.New("max time since accept must be positive")
+	errInvalidMaxRate  = errors.New("max poll failure rate must be in (0, 1]")
+)
+
+// Rule describes the conditions under which alerts are fired for a chain
+type Rule struct {
+	// Primary alias or ID of the chain the rule applies to, or AllChains
+	Chain string
+	// If non-zero, alerts fire once no container of the chain has been
+	// accepted for this long
+	MaxTimeSinceAccept time.Duration
+	// If non-zero, alerts fire once more than this portion of the chain's
+	// queries fail
+	MaxPollFailureRate float64
+}
+
+// appliesTo returns true iff the rule applies to the chain with [alias] and
+// [chainID]
+func (r *Rule) appliesTo(alias, chainID string) bool {
+	return r.Chain == AllChains || r.Chain == alias || r.Chain == chainID
+}
+
+type jsonRule struct {
+	Chain              string  `json:"chain"`
+	MaxTimeSinceAccept string  `json:"maxTimeSinceAccept"`
+	MaxPollFailureRate float64 `json:"maxPollFailureRate"`
+}
+
+// ParseRules parses a JSON array of rules, such as
+// [{"chain": "X", "maxTimeSinceAccept": "10m"}, {"chain": "*", "maxPollFailureRate": 0.5}]
+func ParseRules(rulesJSON []byte) ([]Rule, error) {
+	jsonRules := []jsonRule(nil)
+	if err := json.Unmarshal(rulesJSON, &jsonRules); err != nil {
+		return nil, err
+	}
+
+	rules := make([]Rule, len(jsonRules))
+	for i, jsonRule := range jsonRules {
+		rule := Rule{
+			Chain:              jsonRule.Chain,
+			MaxPollFailureRate: jsonRule.MaxPollFailureRate,
+		}
+		if jsonRule.MaxTimeSinceAccept != "" {
+			maxTimeSinceAccept, err := time.ParseDuration(jsonRule.MaxTimeSinceAccept)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse rule %d: %w", i, err)
+			}
+			rule.MaxTimeSinceAccept = maxTimeSinceAccept
+		}
+
+		switch {
+		case rule.Chain == "":
+			return nil, fmt.Errorf("invalid rule %d: %w", i, errNoRuleChain)
+		case rule.MaxTimeSinceAccept == 0 && rule.MaxPollFailureRate == 0:
+			return nil, fmt.Errorf("invalid rule %d: %w", i, errNoRuleCondition)
+		case rule.MaxTimeSinceAccept < 0:
+			return nil, fmt.Errorf("invalid rule %d: %w", i, errNegativeMaxTime)
+		case rule.MaxPollFailureRate < 0 || rule.MaxPollFailureRate > 1:
+			return nil, fmt.Errorf("invalid rule %d: %w", i, errInvalidMaxRate)
+		}
+		rules[i] = rule
+	}
+	return rules, nil
+}
@@ -0,0 +1,238 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package watchdog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+const (
+	// HandlerID is the identifier that chain watches are registered with in
+	// the consensus event dispatcher
+	HandlerID = "watchdog"
+
+	// StallCondition holds while no container of a chain has been accepted
+	// for longer than allowed
+	StallCondition = "stall"
+	// PollFailureCondition holds while more of a chain's queries fail than
+	// allowed
+	PollFailureCondition = "pollFailureRate"
+)
+
+// Config describes the options of the watchdog
+type Config struct {
+	Rules []Rule
+	// If non-empty, alerts are POSTed to this URL
+	WebhookURL string
+	// If non-empty, this command is run for each alert
+	ExecCommand string
+	// How often the conditions of the rules are checked
+	CheckFreq time.Duration
+}
+
+// Watchdog periodically checks the chains of the node for the conditions
+// described by its rules, and fires alerts when a condition starts or stops
+// holding
+type Watchdog struct {
+	log     logging.Logger
+	clock   timer.Clock
+	rules   []Rule
+	actions []Action
+	freq    time.Duration
+
+	lock   sync.Mutex
+	chains map[ids.ID]*ChainWatch
+
+	// Dispatch returns when closer is closed
+	closer chan struct{}
+}
+
+// New returns a watchdog that checks [rules] every [freq] and takes each of
+// [actions] when an alert fires
+func New(log logging.Logger, rules []Rule, actions []Action, freq time.Duration) *Watchdog {
+	return &Watchdog{
+		log:     log,
+		rules:   rules,
+		actions: actions,
+		freq:    freq,
+		chains:  make(map[ids.ID]*ChainWatch),
+		closer:  make(chan struct{}),
+	}
+}
+
+// RegisterChain starts watching the chain of [ctx], whose primary alias is
+// [alias] and whose queries are tracked by [polls]. The chain's accepted
+// containers must be passed to the returned ChainWatch. Replaces the previous
+// watch of the chain, if any.
+func (w *Watchdog) RegisterChain(alias string, ctx *snow.Context, polls *common.PollTracker) *ChainWatch {
+	c := &ChainWatch{
+		watchdog:     w,
+		alias:        alias,
+		ctx:          ctx,
+		polls:        polls,
+		lastAccepted: w.clock.Time(),
+		firing:       make(map[string]bool),
+	}
+	// If several rules apply to the chain, the strictest limits are used
+	for _, rule := range w.rules {
+		if !rule.appliesTo(alias, ctx.ChainID.String()) {
+			continue
+		}
+		if rule.MaxTimeSinceAccept > 0 && (c.maxTimeSinceAccept == 0 || rule.MaxTimeSinceAccept < c.maxTimeSinceAccept) {
+			c.maxTimeSinceAccept = rule.MaxTimeSinceAccept
+		}
+		if rule.MaxPollFailureRate > 0 && (c.maxPollFailureRate == 0 || rule.MaxPollFailureRate < c.maxPollFailureRate) {
+			c.maxPollFailureRate = rule.MaxPollFailureRate
+		}
+	}
+
+	w.lock.Lock()
+	w.chains[ctx.ChainID] = c
+	w.lock.Unlock()
+	return c
+}
+
+// Dispatch checks the conditions of the rules until Shutdown is called
+func (w *Watchdog) Dispatch() {
+	t := time.NewTicker(w.freq)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-w.closer:
+			return
+		case <-t.C:
+			w.check()
+		}
+	}
+}
+
+// Shutdown stops Dispatch
+func (w *Watchdog) Shutdown() {
+	close(w.closer)
+}
+
+// check the conditions of every chain and fire the alerts of the conditions
+// that started or stopped holding
+func (w *Watchdog) check() {
+	w.lock.Lock()
+	chains := make([]*ChainWatch, 0, len(w.chains))
+	for _, c := range w.chains {
+		chains = append(chains, c)
+	}
+	w.lock.Unlock()
+
+	now := w.clock.Time()
+	for _, c := range chains {
+		for _, alert := range c.check(now) {
+			w.fire(alert)
+		}
+	}
+}
+
+func (w *Watchdog) fire(alert Alert) {
+	if alert.Resolved {
+		w.log.Info("watchdog alert resolved on chain %s: %s", alert.Chain, alert.Message)
+	} else {
+		w.log.Warn("watchdog alert fired on chain %s: %s", alert.Chain, alert.Message)
+	}
+	for _, action := range w.actions {
+		if err := action.Fire(alert); err != nil {
+			w.log.Error("couldn't take action on watchdog alert: %s", err)
+		}
+	}
+}
+
+// ChainWatch tracks the conditions of a chain. Safe for concurrent use, so
+// that a stuck chain doesn't block the watchdog.
+type ChainWatch struct {
+	watchdog *Watchdog
+	alias    string
+	ctx      *snow.Context
+	polls    *common.PollTracker
+
+	// Strictest limits of the rules that apply to the chain. Zero if no rule
+	// limits them.
+	maxTimeSinceAccept time.Duration
+	maxPollFailureRate float64
+
+	lock         sync.Mutex
+	lastAccepted time.Time
+	// Condition --> whether its alert is firing
+	firing map[string]bool
+}
+
+// Accept marks that a container of the chain was accepted. Implements the
+// triggers.Acceptor interface.
+func (c *ChainWatch) Accept(*snow.Context, ids.ID, []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.lastAccepted = c.watchdog.clock.Time()
+	return nil
+}
+
+// check returns the alerts of the conditions that started or stopped holding
+// as of [now]
+func (c *ChainWatch) check(now time.Time) []Alert {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Conditions aren't checked until the chain has finished bootstrapping,
+	// and the chain isn't stalled until it's been bootstrapped for long enough
+	if !c.ctx.IsBootstrapped() {
+		c.lastAccepted = now
+		return nil
+	}
+
+	alerts := []Alert(nil)
+	if c.maxTimeSinceAccept > 0 {
+		timeSinceAccept := now.Sub(c.lastAccepted)
+		holds := timeSinceAccept > c.maxTimeSinceAccept
+		message := "containers are being accepted again"
+		if holds {
+			message = fmt.Sprintf("no container accepted for %s, longer than the maximum of %s", timeSinceAccept, c.maxTimeSinceAccept)
+		}
+		if alert, ok := c.update(now, StallCondition, holds, message); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+	if c.maxPollFailureRate > 0 {
+		failureRate := c.polls.FailureRate()
+		holds := failureRate > c.maxPollFailureRate
+		message := fmt.Sprintf("%f of queries failed, at most the maximum of %f", failureRate, c.maxPollFailureRate)
+		if holds {
+			message = fmt.Sprintf("%f of queries failed, more than the maximum of %f", failureRate, c.maxPollFailureRate)
+		}
+		if alert, ok := c.update(now, PollFailureCondition, holds, message); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+// update records whether [condition] [holds]. Returns the alert to fire and
+// true if the condition started or stopped holding.
+func (c *ChainWatch) update(now time.Time, condition string, holds bool, message string) (Alert, bool) {
+	if c.firing[condition] == holds {
+		return Alert{}, false
+	}
+	c.firing[condition] = holds
+	return Alert{
+		Time:      now,
+		Chain:     c.alias,
+		ChainID:   c.ctx.ChainID.String(),
+		Condition: condition,
+		Resolved:  !holds,
+		Message:   message,
+	}, true
+}
@@ -0,0 +1,194 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package watchdog
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// actionTest records the alerts it's fired with
+type actionTest struct {
+	alerts []Alert
+}
+
+func (a *actionTest) Fire(alert Alert) error {
+	a.alerts = append(a.alerts, alert)
+	return nil
+}
+
+func newPollTracker() *common.PollTracker {
+	return common.NewPollTracker(common.HealthConfig{
+		MaxPollFailureRate:      1,
+		PollFailureRateHalflife: time.Second,
+	})
+}
+
+func TestParseRules(t *testing.T) {
+	assert := assert.New(t)
+
+	rules, err := ParseRules([]byte(`[
+		{"chain": "X", "maxTimeSinceAccept": "10m"},
+		{"chain": "*", "maxPollFailureRate": 0.5}
+	]`))
+	assert.NoError(err)
+	assert.Equal([]Rule{
+		{Chain: "X", MaxTimeSinceAccept: 10 * time.Minute},
+		{Chain: AllChains, MaxPollFailureRate: .5},
+	}, rules)
+
+	_, err = ParseRules([]byte(`[{"maxTimeSinceAccept": "10m"}]`))
+	assert.ErrorIs(err, errNoRuleChain)
+	_, err = ParseRules([]byte(`[{"chain": "X"}]`))
+	assert.ErrorIs(err, errNoRuleCondition)
+	_, err = ParseRules([]byte(`[{"chain": "X", "maxTimeSinceAccept": "-1m"}]`))
+	assert.ErrorIs(err, errNegativeMaxTime)
+	_, err = ParseRules([]byte(`[{"chain": "X", "maxPollFailureRate": 2}]`))
+	assert.ErrorIs(err, errInvalidMaxRate)
+	_, err = ParseRules([]byte(`[{"chain": "X", "maxTimeSinceAccept": "soon"}]`))
+	assert.Error(err)
+}
+
+func TestStallAlert(t *testing.T) {
+	assert := assert.New(t)
+
+	action := &actionTest{}
+	w := New(logging.NoLog{}, []Rule{{Chain: "X", MaxTimeSinceAccept: time.Minute}}, []Action{action}, time.Second)
+	now := time.Now()
+	w.clock.Set(now)
+
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	watch := w.RegisterChain("X", ctx, newPollTracker())
+
+	// Chains aren't watched while bootstrapping
+	now = now.Add(time.Hour)
+	w.clock.Set(now)
+	w.check()
+	assert.Empty(action.alerts)
+
+	ctx.Bootstrapped()
+	now = now.Add(time.Minute)
+	w.clock.Set(now)
+	w.check()
+	assert.Empty(action.alerts)
+
+	now = now.Add(time.Second)
+	w.clock.Set(now)
+	w.check()
+	assert.Len(action.alerts, 1)
+	assert.Equal("X", action.alerts[0].Chain)
+	assert.Equal(ctx.ChainID.String(), action.alerts[0].ChainID)
+	assert.Equal(StallCondition, action.alerts[0].Condition)
+	assert.False(action.alerts[0].Resolved)
+
+	// An alert fires once while its condition holds
+	w.check()
+	assert.Len(action.alerts, 1)
+
+	assert.NoError(watch.Accept(ctx, ids.GenerateTestID(), nil))
+	w.check()
+	assert.Len(action.alerts, 2)
+	assert.Equal(StallCondition, action.alerts[1].Condition)
+	assert.True(action.alerts[1].Resolved)
+}
+
+func TestPollFailureAlert(t *testing.T) {
+	assert := assert.New(t)
+
+	action := &actionTest{}
+	rules := []Rule{
+		{Chain: AllChains, MaxPollFailureRate: .9},
+		{Chain: "C", MaxPollFailureRate: .5},
+	}
+	w := New(logging.NoLog{}, rules, []Action{action}, time.Second)
+
+	ctx := snow.DefaultContextTest()
+	ctx.Bootstrapped()
+	polls := newPollTracker()
+	watch := w.RegisterChain("C", ctx, polls)
+
+	// The strictest of the rules that apply is used
+	assert.Equal(.5, watch.maxPollFailureRate)
+	assert.Zero(watch.maxTimeSinceAccept)
+
+	w.check()
+	assert.Empty(action.alerts)
+
+	for i := 0; i < 10; i++ {
+		polls.Failed()
+	}
+	w.check()
+	assert.Len(action.alerts, 1)
+	assert.Equal(PollFailureCondition, action.alerts[0].Condition)
+	assert.False(action.alerts[0].Resolved)
+
+	// Rules of other chains don't apply
+	other := w.RegisterChain("X", snow.DefaultContextTest(), newPollTracker())
+	assert.Equal(.9, other.maxPollFailureRate)
+}
+
+func TestWebhook(t *testing.T) {
+	assert := assert.New(t)
+
+	var received Alert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(http.MethodPost, r.Method)
+		assert.NoError(json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	alert := Alert{
+		Time:      time.Unix(100, 0).UTC(),
+		Chain:     "X",
+		Condition: StallCondition,
+		Message:   "stalled",
+	}
+	assert.NoError(NewWebhook(server.URL).Fire(alert))
+	assert.Equal(alert, received)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	assert.Error(NewWebhook(failing.URL).Fire(alert))
+}
+
+func TestExecHook(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewExecHook(" ")
+	assert.ErrorIs(err, errEmptyCommand)
+
+	// The alert is written to the command's standard input
+	path := filepath.Join(t.TempDir(), "alert.json")
+	execHook, err := NewExecHook("tee " + path)
+	assert.NoError(err)
+
+	alert := Alert{
+		Time:      time.Unix(100, 0).UTC(),
+		Chain:     "X",
+		Condition: PollFailureCondition,
+		Resolved:  true,
+	}
+	assert.NoError(execHook.Fire(alert))
+
+	alertJSON, err := ioutil.ReadFile(path)
+	assert.NoError(err)
+	received := Alert{}
+	assert.NoError(json.Unmarshal(alertJSON, &received))
+	assert.Equal(alert, received)
+}
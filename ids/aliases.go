@@ -4,10 +4,18 @@
 package ids
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 )
 
+var (
+	errNoIDWithAlias  = errors.New("there is no ID with alias")
+	errNoAliasForID   = errors.New("there is no alias for ID")
+	errAliasInUse     = errors.New("alias is already in use")
+	errAliasIsOtherID = errors.New("alias is the string of a different ID")
+)
+
 // Aliaser allows one to give an ID aliases and lookup the aliases given to an
 // ID. An ID can have arbitrarily many aliases; two IDs may not have the same
 // alias.
@@ -31,15 +39,20 @@ func (a *Aliaser) Lookup(alias string) (ID, error) {
 	if ID, ok := a.dealias[alias]; ok {
 		return ID, nil
 	}
-	return ID{}, fmt.Errorf("there is no ID with alias %s", alias)
+	return ID{}, fmt.Errorf("%w: %s", errNoIDWithAlias, alias)
 }
 
-// Aliases returns the aliases of an ID
+// Aliases returns the aliases of an ID. The returned slice is a copy, so it
+// isn't changed by aliases that are added or removed later.
 func (a *Aliaser) Aliases(id ID) []string {
 	a.lock.RLock()
 	defer a.lock.RUnlock()
 
-	return a.aliases[id]
+	aliases := a.aliases[id]
+	if len(aliases) == 0 {
+		return nil
+	}
+	return append([]string(nil), aliases...)
 }
 
 // PrimaryAlias returns the first alias of [id]
@@ -49,18 +62,33 @@ func (a *Aliaser) PrimaryAlias(id ID) (string, error) {
 
 	aliases, exists := a.aliases[id]
 	if !exists || len(aliases) == 0 {
-		return "", fmt.Errorf("there is no alias for ID %s", id)
+		return "", fmt.Errorf("%w: %s", errNoAliasForID, id)
 	}
 	return aliases[0], nil
 }
 
-// Alias gives [id] the alias [alias]
+// PrimaryAliasOrDefault returns the first alias of [id], or the string of [id]
+// if it has no aliases
+func (a *Aliaser) PrimaryAliasOrDefault(id ID) string {
+	alias, err := a.PrimaryAlias(id)
+	if err != nil {
+		return id.String()
+	}
+	return alias
+}
+
+// Alias gives [id] the alias [alias]. Errors if [alias] is already an alias of
+// any ID, including [id], or if [alias] is the string of another ID, since
+// looking up that string should never resolve to [id].
 func (a *Aliaser) Alias(id ID, alias string) error {
 	a.lock.Lock()
 	defer a.lock.Unlock()
 
-	if _, exists := a.dealias[alias]; exists {
-		return fmt.Errorf("%s is already used as an alias for an ID", alias)
+	if owner, exists := a.dealias[alias]; exists {
+		return fmt.Errorf("%w: %q is an alias of %s", errAliasInUse, alias, owner)
+	}
+	if aliasID, err := FromString(alias); err == nil && aliasID != id {
+		return fmt.Errorf("%w: %q can't be an alias of %s", errAliasIsOtherID, alias, id)
 	}
 
 	a.dealias[alias] = id
@@ -68,6 +96,32 @@ func (a *Aliaser) Alias(id ID, alias string) error {
 	return nil
 }
 
+// RemoveAlias removes [alias] from the ID it was given to
+func (a *Aliaser) RemoveAlias(alias string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	id, exists := a.dealias[alias]
+	if !exists {
+		return fmt.Errorf("%w: %s", errNoIDWithAlias, alias)
+	}
+	delete(a.dealias, alias)
+
+	aliases := a.aliases[id]
+	for i, idAlias := range aliases {
+		if idAlias == alias {
+			aliases = append(aliases[:i], aliases[i+1:]...)
+			break
+		}
+	}
+	if len(aliases) == 0 {
+		delete(a.aliases, id)
+	} else {
+		a.aliases[id] = aliases
+	}
+	return nil
+}
+
 // RemoveAliases of the provided ID
 func (a *Aliaser) RemoveAliases(id ID) {
 	a.lock.Lock()
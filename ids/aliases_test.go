@@ -4,7 +4,9 @@
 package ids
 
 import (
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -158,3 +160,116 @@ func TestAliaserRemoveAlias(t *testing.T) {
 		t.Fatalf("Unexpected error: %s when re-assigning removed ID in aliaser", err)
 	}
 }
+
+func TestAliaserRemoveSingleAlias(t *testing.T) {
+	id1 := ID{'B', 'r', 'u', 'c', 'e', ' ', 'W', 'a', 'y', 'n', 'e'}
+	id2 := ID{'J', 'a', 'm', 'e', 's', ' ', 'G', 'o', 'r', 'd', 'o', 'n'}
+	aliaser := Aliaser{}
+	aliaser.Initialize()
+	if err := aliaser.Alias(id1, "Batman"); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliaser.Alias(id1, "Dark Knight"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := aliaser.RemoveAlias("Batman"); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliaser.RemoveAlias("Batman"); err == nil {
+		t.Fatalf("Should have errored while removing an unknown alias")
+	}
+	if _, err := aliaser.Lookup("Batman"); err == nil {
+		t.Fatalf("Lookup should have errored for a removed alias")
+	}
+	if aliases := aliaser.Aliases(id1); !reflect.DeepEqual(aliases, []string{"Dark Knight"}) {
+		t.Fatalf("Got aliases %v, expected %v", aliases, []string{"Dark Knight"})
+	}
+
+	if err := aliaser.Alias(id2, "Batman"); err != nil {
+		t.Fatalf("Unexpected error: %s when re-assigning removed alias", err)
+	}
+
+	if err := aliaser.RemoveAlias("Dark Knight"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := aliaser.PrimaryAlias(id1); err == nil {
+		t.Fatalf("PrimaryAlias should have errored for an ID without aliases")
+	}
+}
+
+func TestAliaserConflicts(t *testing.T) {
+	id1 := ID{'B', 'r', 'u', 'c', 'e', ' ', 'W', 'a', 'y', 'n', 'e'}
+	id2 := ID{'D', 'i', 'c', 'k', ' ', 'G', 'r', 'a', 'y', 's', 'o', 'n'}
+	aliaser := Aliaser{}
+	aliaser.Initialize()
+	if err := aliaser.Alias(id1, "Batman"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := aliaser.Alias(id1, "Batman"); !errors.Is(err, errAliasInUse) {
+		t.Fatalf("expected %s but got %v", errAliasInUse, err)
+	}
+	err := aliaser.Alias(id2, "Batman")
+	if !errors.Is(err, errAliasInUse) {
+		t.Fatalf("expected %s but got %v", errAliasInUse, err)
+	}
+	if !strings.Contains(err.Error(), id1.String()) {
+		t.Fatalf("expected %q to name the ID that has the alias", err)
+	}
+
+	// An ID may be aliased to its own string, but not to the string of
+	// another ID
+	if err := aliaser.Alias(id2, id2.String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliaser.Alias(id1, id2.String()); !errors.Is(err, errAliasInUse) {
+		t.Fatalf("expected %s but got %v", errAliasInUse, err)
+	}
+	id3 := ID{'A', 'l', 'f', 'r', 'e', 'd'}
+	if err := aliaser.Alias(id1, id3.String()); !errors.Is(err, errAliasIsOtherID) {
+		t.Fatalf("expected %s but got %v", errAliasIsOtherID, err)
+	}
+
+	if _, err := aliaser.Lookup("Robin"); !errors.Is(err, errNoIDWithAlias) {
+		t.Fatalf("expected %s but got %v", errNoIDWithAlias, err)
+	}
+	if err := aliaser.RemoveAlias("Robin"); !errors.Is(err, errNoIDWithAlias) {
+		t.Fatalf("expected %s but got %v", errNoIDWithAlias, err)
+	}
+	if _, err := aliaser.PrimaryAlias(id3); !errors.Is(err, errNoAliasForID) {
+		t.Fatalf("expected %s but got %v", errNoAliasForID, err)
+	}
+}
+
+func TestAliaserReverseLookup(t *testing.T) {
+	id1 := ID{'B', 'r', 'u', 'c', 'e', ' ', 'W', 'a', 'y', 'n', 'e'}
+	id2 := ID{'D', 'i', 'c', 'k', ' ', 'G', 'r', 'a', 'y', 's', 'o', 'n'}
+	aliaser := Aliaser{}
+	aliaser.Initialize()
+	if err := aliaser.Alias(id1, "Batman"); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliaser.Alias(id1, "Dark Knight"); err != nil {
+		t.Fatal(err)
+	}
+
+	if alias := aliaser.PrimaryAliasOrDefault(id1); alias != "Batman" {
+		t.Fatalf("expected %q but got %q", "Batman", alias)
+	}
+	if alias := aliaser.PrimaryAliasOrDefault(id2); alias != id2.String() {
+		t.Fatalf("expected %q but got %q", id2, alias)
+	}
+
+	// Removing an alias must not change aliases that were already returned
+	aliases := aliaser.Aliases(id1)
+	if err := aliaser.RemoveAlias("Batman"); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Batman", "Dark Knight"}; !reflect.DeepEqual(aliases, expected) {
+		t.Fatalf("Got aliases %v, expected %v", aliases, expected)
+	}
+	if alias := aliaser.PrimaryAliasOrDefault(id1); alias != "Dark Knight" {
+		t.Fatalf("expected %q but got %q", "Dark Knight", alias)
+	}
+}
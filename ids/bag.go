@@ -5,7 +5,10 @@ package ids
 
 import (
 	"fmt"
+	stdmath "math"
 	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/math"
 )
 
 const (
@@ -16,6 +19,10 @@ const (
 //
 // A bag has the ability to split and filter on its bits for ease of use for
 // binary voting.
+//
+// Each addition of an ID can also carry a weight, such as the stake of the
+// validator that voted for it, so that votes can be tallied by weight rather
+// than by count.
 type Bag struct {
 	counts map[ID]int
 	size   int
@@ -25,6 +32,15 @@ type Bag struct {
 
 	threshold    int
 	metThreshold Set
+
+	weights     map[ID]uint64
+	totalWeight uint64
+
+	weightMode ID
+	modeWeight uint64
+
+	weightThreshold    uint64
+	metWeightThreshold Set
 }
 
 func (b *Bag) init() {
@@ -49,6 +65,22 @@ func (b *Bag) SetThreshold(threshold int) {
 	}
 }
 
+// SetWeightThreshold sets the weight an ID must have been added with to be
+// contained in the weight threshold set.
+func (b *Bag) SetWeightThreshold(threshold uint64) {
+	if b.weightThreshold == threshold {
+		return
+	}
+
+	b.weightThreshold = threshold
+	b.metWeightThreshold.Clear()
+	for vote, weight := range b.weights {
+		if weight >= threshold {
+			b.metWeightThreshold.Add(vote)
+		}
+	}
+}
+
 // Add increases the number of times each id has been seen by one.
 func (b *Bag) Add(ids ...ID) {
 	for _, id := range ids {
@@ -79,14 +111,56 @@ func (b *Bag) AddCount(id ID, count int) {
 	}
 }
 
+// AddWeighted increases the number of times the id has been seen by one, and
+// its weight by [weight]. Weights saturate at the maximum uint64.
+func (b *Bag) AddWeighted(id ID, weight uint64) {
+	b.addCountWeighted(id, 1, weight)
+}
+
+func (b *Bag) addCountWeighted(id ID, count int, weight uint64) {
+	b.AddCount(id, count)
+	if count <= 0 {
+		return
+	}
+
+	if b.weights == nil {
+		b.weights = make(map[ID]uint64, minBagSize)
+	}
+
+	totalWeight, err := math.Add64(b.weights[id], weight)
+	if err != nil {
+		totalWeight = stdmath.MaxUint64
+	}
+	b.weights[id] = totalWeight
+	if b.totalWeight, err = math.Add64(b.totalWeight, weight); err != nil {
+		b.totalWeight = stdmath.MaxUint64
+	}
+
+	if totalWeight > b.modeWeight {
+		b.weightMode = id
+		b.modeWeight = totalWeight
+	}
+	if totalWeight >= b.weightThreshold {
+		b.metWeightThreshold.Add(id)
+	}
+}
+
 // Count returns the number of times the id has been added.
 func (b *Bag) Count(id ID) int {
 	return b.counts[id]
 }
 
+// Weight returns the total weight the id has been added with.
+func (b *Bag) Weight(id ID) uint64 {
+	return b.weights[id]
+}
+
 // Len returns the number of times an id has been added.
 func (b *Bag) Len() int { return b.size }
 
+// TotalWeight returns the total weight ids have been added with.
+func (b *Bag) TotalWeight() uint64 { return b.totalWeight }
+
 // List returns a list of all ids that have been added.
 func (b *Bag) List() []ID {
 	idList := make([]ID, len(b.counts))
@@ -100,11 +174,11 @@ func (b *Bag) List() []ID {
 
 // Equals returns true if the bags contain the same elements
 func (b *Bag) Equals(oIDs Bag) bool {
-	if b.Len() != oIDs.Len() {
+	if b.Len() != oIDs.Len() || b.TotalWeight() != oIDs.TotalWeight() {
 		return false
 	}
 	for key, value := range b.counts {
-		if value != oIDs.counts[key] {
+		if value != oIDs.counts[key] || b.weights[key] != oIDs.weights[key] {
 			return false
 		}
 	}
@@ -116,9 +190,17 @@ func (b *Bag) Equals(oIDs Bag) bool {
 // of times.
 func (b *Bag) Mode() (ID, int) { return b.mode, b.modeFreq }
 
+// ModeWeight returns the id that has been added with the most weight and its
+// weight. Ties are broken by the first id to reach the reported weight.
+func (b *Bag) ModeWeight() (ID, uint64) { return b.weightMode, b.modeWeight }
+
 // Threshold returns the ids that have been seen at least threshold times.
 func (b *Bag) Threshold() Set { return b.metThreshold }
 
+// WeightThreshold returns the ids that have been added with at least the
+// weight threshold.
+func (b *Bag) WeightThreshold() Set { return b.metWeightThreshold }
+
 // Filter returns the bag of ids with the same counts as this bag, except all
 // the ids in the returned bag must have the same bits in the range [start, end)
 // as id.
@@ -126,7 +208,7 @@ func (b *Bag) Filter(start, end int, id ID) Bag {
 	newBag := Bag{}
 	for vote, count := range b.counts {
 		if EqualSubset(start, end, id, vote) {
-			newBag.AddCount(vote, count)
+			newBag.addCountWeighted(vote, count, b.weights[vote])
 		}
 	}
 	return newBag
@@ -139,7 +221,7 @@ func (b *Bag) Split(index uint) [2]Bag {
 	splitVotes := [2]Bag{}
 	for vote, count := range b.counts {
 		bit := vote.Bit(index)
-		splitVotes[bit].AddCount(vote, count)
+		splitVotes[bit].addCountWeighted(vote, count, b.weights[vote])
 	}
 	return splitVotes
 }
@@ -4,6 +4,7 @@
 package ids
 
 import (
+	stdmath "math"
 	"testing"
 )
 
@@ -132,6 +133,53 @@ func TestBagSetThreshold(t *testing.T) {
 	}
 }
 
+func TestBagAddWeighted(t *testing.T) {
+	id0 := Empty
+	id1 := ID{1}
+
+	bag := Bag{}
+	bag.SetWeightThreshold(10)
+
+	bag.AddWeighted(id0, 4)
+	bag.AddWeighted(id1, 7)
+	bag.AddWeighted(id0, 6)
+
+	if count := bag.Count(id0); count != 2 {
+		t.Fatalf("Bag.Count returned %d expected %d", count, 2)
+	} else if weight := bag.Weight(id0); weight != 10 {
+		t.Fatalf("Bag.Weight returned %d expected %d", weight, 10)
+	} else if weight := bag.TotalWeight(); weight != 17 {
+		t.Fatalf("Bag.TotalWeight returned %d expected %d", weight, 17)
+	} else if mode, weight := bag.ModeWeight(); mode != id0 {
+		t.Fatalf("Bag.ModeWeight[0] returned %s expected %s", mode, id0)
+	} else if weight != 10 {
+		t.Fatalf("Bag.ModeWeight[1] returned %d expected %d", weight, 10)
+	} else if threshold := bag.WeightThreshold(); threshold.Len() != 1 || !threshold.Contains(id0) {
+		t.Fatalf("Bag.WeightThreshold returned %s expected %s", threshold, []ID{id0})
+	}
+
+	bag.SetWeightThreshold(5)
+	if threshold := bag.WeightThreshold(); threshold.Len() != 2 {
+		t.Fatalf("Bag.WeightThreshold returned %s expected %s", threshold, []ID{id0, id1})
+	}
+
+	// Weights saturate rather than overflow
+	bag.AddWeighted(id1, stdmath.MaxUint64)
+	if weight := bag.Weight(id1); weight != stdmath.MaxUint64 {
+		t.Fatalf("Bag.Weight returned %d expected %d", weight, uint64(stdmath.MaxUint64))
+	} else if mode, _ := bag.ModeWeight(); mode != id1 {
+		t.Fatalf("Bag.ModeWeight[0] returned %s expected %s", mode, id1)
+	}
+
+	// Splitting the bag keeps the weights
+	split := bag.Split(0)
+	if weight := split[0].Weight(id0); weight != 10 {
+		t.Fatalf("Bag.Weight returned %d expected %d", weight, 10)
+	} else if weight := split[1].Weight(id1); weight != stdmath.MaxUint64 {
+		t.Fatalf("Bag.Weight returned %d expected %d", weight, uint64(stdmath.MaxUint64))
+	}
+}
+
 func TestBagFilter(t *testing.T) {
 	id0 := Empty
 	id1 := ID{1}
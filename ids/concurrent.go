@@ -0,0 +1,195 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"sync"
+)
+
+// The concurrent collections are split into shards that are each protected by
+// their own lock, so that goroutines working on different IDs rarely contend.
+// IDs are hashes, so their first byte spreads them evenly over the shards.
+const numShards = 16
+
+type setShard struct {
+	lock sync.RWMutex
+	set  Set
+}
+
+// ConcurrentSet is a set of IDs that is safe to share between goroutines. The
+// zero value is an empty set.
+type ConcurrentSet struct {
+	shards [numShards]setShard
+}
+
+func (s *ConcurrentSet) shard(id ID) *setShard { return &s.shards[id[0]%numShards] }
+
+// Add all the ids to this set, if the id is already in the set, nothing happens
+func (s *ConcurrentSet) Add(ids ...ID) {
+	for _, id := range ids {
+		s.Insert(id)
+	}
+}
+
+// Insert adds [id] to this set. Returns true if [id] wasn't already in the set.
+// This allows goroutines to claim an ID, such that only one of them processes
+// it.
+func (s *ConcurrentSet) Insert(id ID) bool {
+	shard := s.shard(id)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	if shard.set.Contains(id) {
+		return false
+	}
+	shard.set.Add(id)
+	return true
+}
+
+// Contains returns true if the set contains this id, false otherwise
+func (s *ConcurrentSet) Contains(id ID) bool {
+	shard := s.shard(id)
+	shard.lock.RLock()
+	defer shard.lock.RUnlock()
+
+	return shard.set.Contains(id)
+}
+
+// Remove all the id from this set, if the id isn't in the set, nothing happens
+func (s *ConcurrentSet) Remove(ids ...ID) {
+	for _, id := range ids {
+		shard := s.shard(id)
+		shard.lock.Lock()
+		shard.set.Remove(id)
+		shard.lock.Unlock()
+	}
+}
+
+// Len returns the number of ids in this set. IDs that are concurrently added
+// or removed may or may not be counted.
+func (s *ConcurrentSet) Len() int {
+	size := 0
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.lock.RLock()
+		size += shard.set.Len()
+		shard.lock.RUnlock()
+	}
+	return size
+}
+
+// List converts this set into a list. IDs that are concurrently added or
+// removed may or may not be listed.
+func (s *ConcurrentSet) List() []ID {
+	idList := []ID(nil)
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.lock.RLock()
+		idList = append(idList, shard.set.List()...)
+		shard.lock.RUnlock()
+	}
+	return idList
+}
+
+// Clear empties this set
+func (s *ConcurrentSet) Clear() {
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.lock.Lock()
+		shard.set.Clear()
+		shard.lock.Unlock()
+	}
+}
+
+type mapShard struct {
+	lock    sync.RWMutex
+	entries map[ID]interface{}
+}
+
+// ConcurrentMap maps IDs to values and is safe to share between goroutines.
+// The zero value is an empty map.
+type ConcurrentMap struct {
+	shards [numShards]mapShard
+}
+
+func (m *ConcurrentMap) shard(id ID) *mapShard { return &m.shards[id[0]%numShards] }
+
+// Get returns the value of [id] and whether [id] is in the map
+func (m *ConcurrentMap) Get(id ID) (interface{}, bool) {
+	shard := m.shard(id)
+	shard.lock.RLock()
+	defer shard.lock.RUnlock()
+
+	value, ok := shard.entries[id]
+	return value, ok
+}
+
+// Put sets the value of [id] to [value]
+func (m *ConcurrentMap) Put(id ID, value interface{}) {
+	shard := m.shard(id)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	if shard.entries == nil {
+		shard.entries = make(map[ID]interface{})
+	}
+	shard.entries[id] = value
+}
+
+// GetOrPut returns the value of [id] if [id] is in the map. Otherwise, it sets
+// the value of [id] to [value] and returns [value]. The returned bool is true
+// if the value was already in the map.
+func (m *ConcurrentMap) GetOrPut(id ID, value interface{}) (interface{}, bool) {
+	shard := m.shard(id)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	if existing, ok := shard.entries[id]; ok {
+		return existing, true
+	}
+	if shard.entries == nil {
+		shard.entries = make(map[ID]interface{})
+	}
+	shard.entries[id] = value
+	return value, false
+}
+
+// Delete removes [id] from the map, if it's in the map
+func (m *ConcurrentMap) Delete(id ID) {
+	shard := m.shard(id)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	delete(shard.entries, id)
+}
+
+// Len returns the number of IDs in the map. IDs that are concurrently added or
+// removed may or may not be counted.
+func (m *ConcurrentMap) Len() int {
+	size := 0
+	for i := range m.shards {
+		shard := &m.shards[i]
+		shard.lock.RLock()
+		size += len(shard.entries)
+		shard.lock.RUnlock()
+	}
+	return size
+}
+
+// Range calls [f] on every ID in the map and its value, until [f] returns
+// false. [f] is called while a lock of the map is held, so it must not modify
+// the map.
+func (m *ConcurrentMap) Range(f func(ID, interface{}) bool) {
+	for i := range m.shards {
+		shard := &m.shards[i]
+		shard.lock.RLock()
+		for id, value := range shard.entries {
+			if !f(id, value) {
+				shard.lock.RUnlock()
+				return
+			}
+		}
+		shard.lock.RUnlock()
+	}
+}
@@ -0,0 +1,104 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentSet(t *testing.T) {
+	idList := make([]ID, 256)
+	for i := range idList {
+		idList[i] = Empty.Prefix(uint64(i))
+	}
+
+	// Every goroutine tries to claim every ID, but each ID must only be
+	// claimed once
+	set := ConcurrentSet{}
+	claimed := make([]int, 8)
+	wg := sync.WaitGroup{}
+	wg.Add(len(claimed))
+	for i := range claimed {
+		go func(i int) {
+			defer wg.Done()
+			for _, id := range idList {
+				if set.Insert(id) {
+					claimed[i]++
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	total := 0
+	for _, numClaimed := range claimed {
+		total += numClaimed
+	}
+	switch {
+	case total != len(idList):
+		t.Fatalf("expected %d claims but got %d", len(idList), total)
+	case set.Len() != len(idList):
+		t.Fatalf("expected size %d but got %d", len(idList), set.Len())
+	case len(set.List()) != len(idList):
+		t.Fatalf("expected %d listed IDs but got %d", len(idList), len(set.List()))
+	case !set.Contains(idList[0]):
+		t.Fatalf("should contain %s", idList[0])
+	}
+
+	set.Remove(idList[0])
+	if set.Contains(idList[0]) || set.Len() != len(idList)-1 {
+		t.Fatalf("%s should have been removed", idList[0])
+	}
+	set.Clear()
+	if set.Len() != 0 {
+		t.Fatalf("expected an empty set but got size %d", set.Len())
+	}
+}
+
+func TestConcurrentMap(t *testing.T) {
+	m := ConcurrentMap{}
+	wg := sync.WaitGroup{}
+	wg.Add(8)
+	for i := 0; i < 8; i++ {
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 64; j++ {
+				m.GetOrPut(Empty.Prefix(uint64(j)), i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if m.Len() != 64 {
+		t.Fatalf("expected size 64 but got %d", m.Len())
+	}
+
+	id := Empty.Prefix(0)
+	first, ok := m.Get(id)
+	if !ok {
+		t.Fatalf("should contain %s", id)
+	}
+	if value, existed := m.GetOrPut(id, -1); !existed || value != first {
+		t.Fatalf("expected existing value %v but got %v", first, value)
+	}
+	m.Put(id, -1)
+	if value, _ := m.Get(id); value != -1 {
+		t.Fatalf("expected -1 but got %v", value)
+	}
+
+	visited := 0
+	m.Range(func(ID, interface{}) bool {
+		visited++
+		return visited < 10
+	})
+	if visited != 10 {
+		t.Fatalf("expected Range to stop after 10 entries but visited %d", visited)
+	}
+
+	m.Delete(id)
+	if _, ok := m.Get(id); ok || m.Len() != 63 {
+		t.Fatalf("%s should have been deleted", id)
+	}
+}
@@ -0,0 +1,123 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"bytes"
+	"errors"
+)
+
+// The helpers in this file treat an ID as a 256-bit unsigned big-endian
+// integer, which is the order that IDs are sorted in and that databases
+// iterate over keys prefixed by an ID. This allows the keyspace of IDs to be
+// split into ranges, for example to scan a database in parallel.
+
+var errNoPartitions = errors.New("number of partitions must be positive")
+
+// Compare returns -1, 0 or 1 if [id] is less than, equal to or greater than
+// [other], respectively
+func (id ID) Compare(other ID) int { return bytes.Compare(id[:], other[:]) }
+
+// Add returns [id] + [other] and whether the sum overflowed 256 bits. If it
+// did, the sum wraps around.
+func (id ID) Add(other ID) (ID, bool) {
+	var (
+		sum   ID
+		carry uint16
+	)
+	for i := len(id) - 1; i >= 0; i-- {
+		s := uint16(id[i]) + uint16(other[i]) + carry
+		sum[i] = byte(s)
+		carry = s >> 8
+	}
+	return sum, carry != 0
+}
+
+// Sub returns [id] - [other] and whether the difference underflowed. If it
+// did, the difference wraps around.
+func (id ID) Sub(other ID) (ID, bool) {
+	var (
+		diff   ID
+		borrow int16
+	)
+	for i := len(id) - 1; i >= 0; i-- {
+		d := int16(id[i]) - int16(other[i]) - borrow
+		borrow = 0
+		if d < 0 {
+			d += 256
+			borrow = 1
+		}
+		diff[i] = byte(d)
+	}
+	return diff, borrow != 0
+}
+
+// Midpoint returns the ID halfway between [id0] and [id1], rounded down
+func Midpoint(id0, id1 ID) ID {
+	sum, carry := id0.Add(id1)
+	// Shift the 257-bit sum right by one bit
+	var mid ID
+	high := byte(0)
+	if carry {
+		high = 1
+	}
+	for i := range sum {
+		mid[i] = high<<7 | sum[i]>>1
+		high = sum[i] & 1
+	}
+	return mid
+}
+
+// divUint64 returns [id] / [divisor], rounded down
+func (id ID) divUint64(divisor uint64) ID {
+	var (
+		quotient  ID
+		remainder uint64
+	)
+	if divisor < 1<<56 {
+		// Long division a byte at a time. [remainder] < [divisor], so shifting
+		// it by a byte can't overflow.
+		for i, b := range id {
+			cur := remainder<<8 | uint64(b)
+			quotient[i] = byte(cur / divisor)
+			remainder = cur % divisor
+		}
+		return quotient
+	}
+
+	// Long division a bit at a time, which doesn't overflow for any divisor
+	for i := 0; i < len(id)*BitsPerByte; i++ {
+		bit := uint64(id[i/BitsPerByte]>>uint(BitsPerByte-1-i%BitsPerByte)) & 1
+		overflow := remainder >= 1<<63
+		remainder = remainder<<1 | bit
+		if overflow || remainder >= divisor {
+			remainder -= divisor
+			quotient[i/BitsPerByte] |= 1 << uint(BitsPerByte-1-i%BitsPerByte)
+		}
+	}
+	return quotient
+}
+
+// Partition splits the range of IDs [start, end] into [n] contiguous ranges of
+// about the same size. It returns the first ID of each range, in order. The
+// i-th range ends right before the (i+1)-th starts, and the last one ends at
+// [end]. If the range holds fewer than [n] IDs, some ranges start at the same
+// ID and are empty.
+func Partition(start, end ID, n int) ([]ID, error) {
+	if n <= 0 {
+		return nil, errNoPartitions
+	}
+	if start.Compare(end) > 0 {
+		start, end = end, start
+	}
+
+	width, _ := end.Sub(start)
+	step := width.divUint64(uint64(n))
+	starts := make([]ID, n)
+	starts[0] = start
+	for i := 1; i < n; i++ {
+		starts[i], _ = starts[i-1].Add(step)
+	}
+	return starts, nil
+}
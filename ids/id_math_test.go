@@ -0,0 +1,112 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"math/big"
+	"testing"
+)
+
+func idToInt(id ID) *big.Int { return new(big.Int).SetBytes(id[:]) }
+
+func intToID(i *big.Int) ID {
+	var id ID
+	i.FillBytes(id[:])
+	return id
+}
+
+var maxID = func() ID {
+	var id ID
+	for i := range id {
+		id[i] = 0xff
+	}
+	return id
+}()
+
+func TestIDCompare(t *testing.T) {
+	if cmp := (ID{1}).Compare(ID{0, 0xff}); cmp != 1 {
+		t.Fatalf("expected 1 but got %d", cmp)
+	}
+	if cmp := (ID{0, 0xff}).Compare(ID{1}); cmp != -1 {
+		t.Fatalf("expected -1 but got %d", cmp)
+	}
+	if cmp := (ID{1}).Compare(ID{1}); cmp != 0 {
+		t.Fatalf("expected 0 but got %d", cmp)
+	}
+}
+
+func TestIDArithmetic(t *testing.T) {
+	modulus := new(big.Int).Lsh(big.NewInt(1), 256)
+	for i := 0; i < 100; i++ {
+		id0, id1 := GenerateTestID(), GenerateTestID()
+		if i == 0 {
+			id0, id1 = maxID, ID{31: 1}
+		}
+		a, b := idToInt(id0), idToInt(id1)
+
+		sum, overflow := id0.Add(id1)
+		expectedSum := new(big.Int).Add(a, b)
+		if overflow != (expectedSum.Cmp(modulus) >= 0) {
+			t.Fatalf("wrong overflow adding %s and %s", id0, id1)
+		}
+		if expected := intToID(expectedSum.Mod(expectedSum, modulus)); sum != expected {
+			t.Fatalf("expected %s + %s = %s but got %s", id0, id1, expected, sum)
+		}
+
+		diff, underflow := id0.Sub(id1)
+		expectedDiff := new(big.Int).Sub(a, b)
+		if underflow != (expectedDiff.Sign() < 0) {
+			t.Fatalf("wrong underflow subtracting %s from %s", id1, id0)
+		}
+		if expected := intToID(expectedDiff.Mod(expectedDiff, modulus)); diff != expected {
+			t.Fatalf("expected %s - %s = %s but got %s", id0, id1, expected, diff)
+		}
+
+		expectedMid := new(big.Int).Add(a, b)
+		expectedMid.Rsh(expectedMid, 1)
+		if mid := Midpoint(id0, id1); mid != intToID(expectedMid) {
+			t.Fatalf("expected midpoint of %s and %s to be %s but got %s", id0, id1, intToID(expectedMid), mid)
+		}
+
+		for _, divisor := range []uint64{1, 3, 1 << 40, 1<<56 + 1, 1<<64 - 1} {
+			expected := new(big.Int).Div(a, new(big.Int).SetUint64(divisor))
+			if quotient := id0.divUint64(divisor); quotient != intToID(expected) {
+				t.Fatalf("expected %s / %d = %s but got %s", id0, divisor, intToID(expected), quotient)
+			}
+		}
+	}
+}
+
+func TestPartition(t *testing.T) {
+	if _, err := Partition(Empty, maxID, 0); err != errNoPartitions {
+		t.Fatalf("expected %s but got %v", errNoPartitions, err)
+	}
+
+	starts, err := Partition(Empty, maxID, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The width of the keyspace is 2^256 - 1, so each partition holds
+	// (2^256 - 1) / 4 IDs, rounded down
+	step := new(big.Int).Lsh(big.NewInt(1), 256)
+	step.Sub(step, big.NewInt(1))
+	step.Div(step, big.NewInt(4))
+	for i, start := range starts {
+		expected := new(big.Int).Mul(step, big.NewInt(int64(i)))
+		if start != intToID(expected) {
+			t.Fatalf("expected partition %d to start at %s but got %s", i, intToID(expected), start)
+		}
+	}
+
+	// Ranges with fewer IDs than partitions have empty partitions
+	starts, err = Partition(ID{31: 2}, ID{31: 0}, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, start := range starts {
+		if start != (ID{31: 0}) {
+			t.Fatalf("expected every partition to start at %s but got %s", ID{31: 0}, start)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NodeIDPrefix is the prefix of the string representation of a NodeID
+const NodeIDPrefix = "NodeID-"
+
+var (
+	// EmptyNodeID is a useful all zero value
+	EmptyNodeID = NodeID{}
+
+	errMissingNodeIDPrefix = errors.New("node ID is missing the prefix " + NodeIDPrefix)
+)
+
+// NodeID identifies a node. It has the same bytes as the ShortID that node IDs
+// used to be, but is a distinct type so that node IDs can't be mixed up with
+// the ShortIDs of addresses.
+//
+// Polls, validator sets and the network identify nodes by their NodeIDs. The
+// router, the consensus engines and the VM API haven't been moved over to
+// NodeID yet, so code converts at its boundary with them with
+// NodeIDFromShortID and NodeID.ShortID.
+type NodeID ShortID
+
+// NodeIDFromShortID returns the NodeID with the same bytes as [id]
+func NodeIDFromShortID(id ShortID) NodeID { return NodeID(id) }
+
+// ToNodeID attempts to convert a byte slice into a NodeID
+func ToNodeID(bytes []byte) (NodeID, error) {
+	id, err := ToShortID(bytes)
+	return NodeID(id), err
+}
+
+// NodeIDFromString is the inverse of NodeID.String()
+func NodeIDFromString(nodeIDStr string) (NodeID, error) {
+	if !strings.HasPrefix(nodeIDStr, NodeIDPrefix) {
+		return NodeID{}, fmt.Errorf("%w: %q", errMissingNodeIDPrefix, nodeIDStr)
+	}
+	id, err := ShortFromString(strings.TrimPrefix(nodeIDStr, NodeIDPrefix))
+	if err != nil {
+		return NodeID{}, fmt.Errorf("couldn't parse node ID %q: %w", nodeIDStr, err)
+	}
+	return NodeID(id), nil
+}
+
+// ShortID returns the ShortID with the same bytes as [id]
+func (id NodeID) ShortID() ShortID { return ShortID(id) }
+
+// Bytes returns the 20 byte hash as a slice. It is assumed this slice is not
+// modified.
+func (id NodeID) Bytes() []byte { return id[:] }
+
+func (id NodeID) String() string { return ShortID(id).PrefixedString(NodeIDPrefix) }
+
+// MarshalJSON ...
+func (id NodeID) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + id.String() + "\""), nil
+}
+
+// UnmarshalJSON ...
+func (id *NodeID) UnmarshalJSON(b []byte) error {
+	str := string(b)
+	if str == "null" { // If "null", do nothing
+		return nil
+	} else if len(str) < 2 {
+		return errMissingQuotes
+	}
+
+	lastIndex := len(str) - 1
+	if str[0] != '"' || str[lastIndex] != '"' {
+		return errMissingQuotes
+	}
+
+	var err error
+	*id, err = NodeIDFromString(str[1:lastIndex])
+	return err
+}
+
+// NodeIDsToShortIDs returns the ShortIDs with the same bytes as [nodeIDs]
+func NodeIDsToShortIDs(nodeIDs []NodeID) []ShortID {
+	shortIDs := make([]ShortID, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		shortIDs[i] = ShortID(nodeID)
+	}
+	return shortIDs
+}
+
+// ShortIDsToNodeIDs returns the NodeIDs with the same bytes as [shortIDs]
+func ShortIDsToNodeIDs(shortIDs []ShortID) []NodeID {
+	nodeIDs := make([]NodeID, len(shortIDs))
+	for i, shortID := range shortIDs {
+		nodeIDs[i] = NodeID(shortID)
+	}
+	return nodeIDs
+}
+
+// NodeIDBag is a multiset of NodeIDs
+type NodeIDBag struct {
+	bag ShortBag
+}
+
+// Add increases the number of times each id has been seen by one.
+func (b *NodeIDBag) Add(ids ...NodeID) {
+	for _, id := range ids {
+		b.bag.AddCount(ShortID(id), 1)
+	}
+}
+
+// AddCount increases the number of times the id has been seen by count.
+//
+// count must be >= 0
+func (b *NodeIDBag) AddCount(id NodeID, count int) { b.bag.AddCount(ShortID(id), count) }
+
+// Count returns the number of times the id has been added.
+func (b *NodeIDBag) Count(id NodeID) int { return b.bag.Count(ShortID(id)) }
+
+// Remove sets the count of the provided ID to zero.
+func (b *NodeIDBag) Remove(id NodeID) { b.bag.Remove(ShortID(id)) }
+
+// Len returns the number of times an id has been added.
+func (b *NodeIDBag) Len() int { return b.bag.Len() }
+
+// List returns a list of all ids that have been added.
+func (b *NodeIDBag) List() []NodeID { return ShortIDsToNodeIDs(b.bag.List()) }
+
+// Equals returns true if the bags contain the same elements
+func (b *NodeIDBag) Equals(oIDs NodeIDBag) bool { return b.bag.Equals(oIDs.bag) }
+
+// PrefixedString ...
+func (b *NodeIDBag) PrefixedString(prefix string) string {
+	sb := strings.Builder{}
+
+	sb.WriteString(fmt.Sprintf("Bag: (Size = %d)", b.Len()))
+	for id, count := range b.bag.counts {
+		sb.WriteString(fmt.Sprintf("\n%s    ID[%s]: Count = %d", prefix, NodeID(id), count))
+	}
+
+	return sb.String()
+}
+
+func (b *NodeIDBag) String() string { return b.PrefixedString("") }
+
+// NodeIDSet is a set of NodeIDs
+type NodeIDSet map[NodeID]struct{}
+
+// NewNodeIDSet returns a new NodeIDSet that can hold [size] NodeIDs without
+// growing
+func NewNodeIDSet(size int) NodeIDSet { return make(map[NodeID]struct{}, size) }
+
+// NodeIDSetFromShortSet returns a NodeIDSet with the same bytes as [set]
+func NodeIDSetFromShortSet(set ShortSet) NodeIDSet {
+	nodeIDs := NewNodeIDSet(set.Len())
+	for id := range set {
+		nodeIDs[NodeID(id)] = struct{}{}
+	}
+	return nodeIDs
+}
+
+// ShortSet returns a ShortSet with the same bytes as the set
+func (s NodeIDSet) ShortSet() ShortSet {
+	shortIDs := NewShortSet(s.Len())
+	for id := range s {
+		shortIDs[ShortID(id)] = struct{}{}
+	}
+	return shortIDs
+}
+
+// Add all the ids to this set, if the id is already in the set, nothing
+// happens
+func (s *NodeIDSet) Add(ids ...NodeID) {
+	if *s == nil {
+		*s = NewNodeIDSet(len(ids))
+	}
+	for _, id := range ids {
+		(*s)[id] = struct{}{}
+	}
+}
+
+// Union adds all the ids from the provided set to this set.
+func (s *NodeIDSet) Union(set NodeIDSet) {
+	if *s == nil {
+		*s = NewNodeIDSet(len(set))
+	}
+	for id := range set {
+		(*s)[id] = struct{}{}
+	}
+}
+
+// Contains returns true if the set contains this id, false otherwise
+func (s NodeIDSet) Contains(id NodeID) bool {
+	_, contains := s[id]
+	return contains
+}
+
+// Len returns the number of ids in this set
+func (s NodeIDSet) Len() int { return len(s) }
+
+// Remove all the id from this set, if the id isn't in the set, nothing happens
+func (s NodeIDSet) Remove(ids ...NodeID) {
+	for _, id := range ids {
+		delete(s, id)
+	}
+}
+
+// Clear empties this set
+func (s *NodeIDSet) Clear() { *s = nil }
+
+// List converts this set into a list
+func (s NodeIDSet) List() []NodeID {
+	idList := make([]NodeID, 0, len(s))
+	for id := range s {
+		idList = append(idList, id)
+	}
+	return idList
+}
+
+// SortedList returns this set as a sorted list
+func (s NodeIDSet) SortedList() []NodeID {
+	idList := s.List()
+	SortNodeIDs(idList)
+	return idList
+}
+
+// Equals returns true if the sets contain the same elements
+func (s NodeIDSet) Equals(set NodeIDSet) bool {
+	if len(s) != len(set) {
+		return false
+	}
+	for id := range set {
+		if _, contains := s[id]; !contains {
+			return false
+		}
+	}
+	return true
+}
+
+func (s NodeIDSet) String() string {
+	sb := strings.Builder{}
+	sb.WriteString("{")
+	for i, id := range s.SortedList() {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(id.String())
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// SortNodeIDs sorts [nodeIDs] by their bytes
+func SortNodeIDs(nodeIDs []NodeID) {
+	sort.Slice(nodeIDs, func(i, j int) bool {
+		return bytes.Compare(nodeIDs[i][:], nodeIDs[j][:]) < 0
+	})
+}
@@ -0,0 +1,112 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNodeIDString(t *testing.T) {
+	shortID := GenerateTestShortID()
+	nodeID := NodeIDFromShortID(shortID)
+	if nodeID.ShortID() != shortID {
+		t.Fatalf("expected %s but got %s", shortID, nodeID.ShortID())
+	}
+
+	nodeIDStr := nodeID.String()
+	if expected := NodeIDPrefix + shortID.String(); nodeIDStr != expected {
+		t.Fatalf("expected %q but got %q", expected, nodeIDStr)
+	}
+	parsed, err := NodeIDFromString(nodeIDStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != nodeID {
+		t.Fatalf("expected %s but got %s", nodeID, parsed)
+	}
+
+	if _, err := NodeIDFromString(shortID.String()); !errors.Is(err, errMissingNodeIDPrefix) {
+		t.Fatalf("expected %s but got %v", errMissingNodeIDPrefix, err)
+	}
+	if _, err := NodeIDFromString(nodeIDStr[:len(nodeIDStr)-1]); err == nil {
+		t.Fatal("should have failed to parse a truncated node ID")
+	}
+}
+
+func TestNodeIDJSON(t *testing.T) {
+	nodeID := NodeIDFromShortID(GenerateTestShortID())
+	jsonBytes, err := json.Marshal(nodeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "\"" + nodeID.String() + "\""; string(jsonBytes) != expected {
+		t.Fatalf("expected %s but got %s", expected, jsonBytes)
+	}
+
+	var parsed NodeID
+	if err := json.Unmarshal(jsonBytes, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed != nodeID {
+		t.Fatalf("expected %s but got %s", nodeID, parsed)
+	}
+}
+
+func TestNodeIDBag(t *testing.T) {
+	nodeID0 := NodeIDFromShortID(ShortID{1})
+	nodeID1 := NodeIDFromShortID(ShortID{2})
+
+	bag := NodeIDBag{}
+	bag.Add(nodeID0, nodeID1)
+	bag.AddCount(nodeID0, 2)
+	switch {
+	case bag.Len() != 4:
+		t.Fatalf("expected size 4 but got %d", bag.Len())
+	case bag.Count(nodeID0) != 3:
+		t.Fatalf("expected count 3 but got %d", bag.Count(nodeID0))
+	case len(bag.List()) != 2:
+		t.Fatalf("expected 2 distinct IDs but got %d", len(bag.List()))
+	case !strings.Contains(bag.String(), nodeID0.String()):
+		t.Fatalf("expected %q to contain %s", bag.String(), nodeID0)
+	}
+
+	bag.Remove(nodeID0)
+	if bag.Len() != 1 || bag.Count(nodeID0) != 0 {
+		t.Fatalf("%s should have been removed", nodeID0)
+	}
+
+	shortIDs := NodeIDsToShortIDs(bag.List())
+	if len(shortIDs) != 1 || shortIDs[0] != nodeID1.ShortID() {
+		t.Fatalf("expected [%s] but got %v", nodeID1.ShortID(), shortIDs)
+	}
+}
+
+func TestNodeIDSet(t *testing.T) {
+	nodeID0 := NodeIDFromShortID(ShortID{1})
+	nodeID1 := NodeIDFromShortID(ShortID{2})
+
+	set := NodeIDSet{}
+	set.Add(nodeID1, nodeID0, nodeID1)
+	switch {
+	case set.Len() != 2:
+		t.Fatalf("expected size 2 but got %d", set.Len())
+	case !set.Contains(nodeID0):
+		t.Fatalf("expected %s to be in the set", nodeID0)
+	case set.String() != "{"+nodeID0.String()+", "+nodeID1.String()+"}":
+		t.Fatalf("unexpected string %q", set.String())
+	}
+
+	set.Remove(nodeID0)
+	if set.Len() != 1 || set.Contains(nodeID0) {
+		t.Fatalf("%s should have been removed", nodeID0)
+	}
+
+	shortSet := set.ShortSet()
+	if shortSet.Len() != 1 || !shortSet.Contains(nodeID1.ShortID()) {
+		t.Fatalf("expected {%s} but got %s", nodeID1.ShortID(), shortSet)
+	}
+}
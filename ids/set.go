@@ -5,6 +5,7 @@ package ids
 
 import (
 	"strings"
+	"sync"
 )
 
 const (
@@ -16,9 +17,32 @@ const (
 	clearSizeThreshold = 512
 )
 
+// setPool holds empty sets that can be reused, so that sets that only live
+// for the duration of a call don't need to be allocated each time
+var setPool = sync.Pool{
+	New: func() interface{} { return make(Set, minSetSize) },
+}
+
 // Set is a set of IDs
 type Set map[ID]struct{}
 
+// GetSet returns an empty set from a pool of sets. The set should be returned
+// to the pool with PutSet once it's no longer used.
+func GetSet() Set { return setPool.Get().(Set) }
+
+// PutSet clears [set] and returns it to the pool of sets. [set] must not be
+// used after it's returned. Sets that grew larger than the threshold at which
+// they are cleared by dropping their map aren't pooled.
+func PutSet(set Set) {
+	if set == nil || len(set) > clearSizeThreshold {
+		return
+	}
+	for id := range set {
+		delete(set, id)
+	}
+	setPool.Put(set)
+}
+
 // Return a new set with initial capacity [size].
 // More or less than [size] elements can be added to this set.
 // Using NewSet() rather than ids.Set{} is just an optimization that can
@@ -77,6 +101,34 @@ func (ids *Set) Overlaps(big Set) bool {
 	return false
 }
 
+// Intersection returns a new set of the ids that are in both this set and
+// [set]
+func (ids Set) Intersection(set Set) Set {
+	small, big := ids, set
+	if small.Len() > big.Len() {
+		small, big = big, small
+	}
+	intersection := NewSet(small.Len())
+	for id := range small {
+		if _, ok := big[id]; ok {
+			intersection[id] = struct{}{}
+		}
+	}
+	return intersection
+}
+
+// Difference returns a new set of the ids that are in this set but not in
+// [set]
+func (ids Set) Difference(set Set) Set {
+	difference := NewSet(ids.Len())
+	for id := range ids {
+		if _, ok := set[id]; !ok {
+			difference[id] = struct{}{}
+		}
+	}
+	return difference
+}
+
 // Len returns the number of ids in this set
 func (ids Set) Len() int { return len(ids) }
 
@@ -109,6 +161,14 @@ func (ids Set) List() []ID {
 	return idList
 }
 
+// SortedList returns the ids in this set sorted lexicographically, so that
+// they can be iterated over in a deterministic order
+func (ids Set) SortedList() []ID {
+	idList := ids.List()
+	SortIDs(idList)
+	return idList
+}
+
 // CappedList returns a list of length at most [size].
 // Size should be >= 0. If size < 0, returns nil.
 func (ids Set) CappedList(size int) []ID {
@@ -69,3 +69,28 @@ func BenchmarkSetClear(b *testing.B) {
 		})
 	}
 }
+
+// BenchmarkSetPool compares allocating a short lived set each time it's used
+// with taking it from the pool of sets
+func BenchmarkSetPool(b *testing.B) {
+	idList := make([]ID, 10)
+	for i := range idList {
+		idList[i] = GenerateTestID()
+	}
+
+	b.Run("alloc", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			set := Set{}
+			set.Add(idList...)
+		}
+	})
+	b.Run("pool", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			set := GetSet()
+			set.Add(idList...)
+			PutSet(set)
+		}
+	})
+}
@@ -157,3 +157,45 @@ func TestSetPop(t *testing.T) {
 	_, ok = s.Pop()
 	assert.False(t, ok)
 }
+
+func TestSetPool(t *testing.T) {
+	s := GetSet()
+	assert.EqualValues(t, 0, s.Len())
+
+	id := GenerateTestID()
+	s.Add(id)
+	PutSet(s)
+
+	// Sets are always empty when they are taken from the pool
+	s = GetSet()
+	assert.False(t, s.Contains(id))
+	assert.EqualValues(t, 0, s.Len())
+	PutSet(s)
+
+	// Returning a nil set is a no-op
+	PutSet(nil)
+}
+
+func TestSetAlgebra(t *testing.T) {
+	id0, id1, id2 := ID{0}, ID{1}, ID{2}
+	s0 := Set{}
+	s0.Add(id0, id1)
+	s1 := Set{}
+	s1.Add(id1, id2)
+
+	intersection := s0.Intersection(s1)
+	assert.EqualValues(t, 1, intersection.Len())
+	assert.True(t, intersection.Contains(id1))
+
+	difference := s0.Difference(s1)
+	assert.EqualValues(t, 1, difference.Len())
+	assert.True(t, difference.Contains(id0))
+
+	// Neither operation modifies the sets
+	assert.EqualValues(t, 2, s0.Len())
+	assert.EqualValues(t, 2, s1.Len())
+
+	assert.Equal(t, []ID{id0, id1}, s0.SortedList())
+	assert.Empty(t, Set{}.Intersection(s0))
+	assert.Empty(t, Set{}.Difference(s0))
+}
@@ -5,13 +5,20 @@ package ids
 
 import (
 	"fmt"
+	stdmath "math"
 	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/math"
 )
 
-// ShortBag is a multiset of ShortIDs.
+// ShortBag is a multiset of ShortIDs. Like Bag, each addition of an ID can
+// carry a weight.
 type ShortBag struct {
 	counts map[ShortID]int
 	size   int
+
+	weights     map[ShortID]uint64
+	totalWeight uint64
 }
 
 func (b *ShortBag) init() {
@@ -42,23 +49,77 @@ func (b *ShortBag) AddCount(id ShortID, count int) {
 	b.size += count
 }
 
+// AddWeighted increases the number of times the id has been seen by one, and
+// its weight by [weight]. Weights saturate at the maximum uint64.
+func (b *ShortBag) AddWeighted(id ShortID, weight uint64) {
+	b.AddCount(id, 1)
+	if b.weights == nil {
+		b.weights = make(map[ShortID]uint64, minBagSize)
+	}
+
+	totalWeight, err := math.Add64(b.weights[id], weight)
+	if err != nil {
+		totalWeight = stdmath.MaxUint64
+	}
+	b.weights[id] = totalWeight
+	if b.totalWeight, err = math.Add64(b.totalWeight, weight); err != nil {
+		b.totalWeight = stdmath.MaxUint64
+	}
+}
+
 // Count returns the number of times the id has been added.
 func (b *ShortBag) Count(id ShortID) int {
 	b.init()
 	return b.counts[id]
 }
 
-// Remove sets the count of the provided ID to zero.
+// Weight returns the total weight the id has been added with.
+func (b *ShortBag) Weight(id ShortID) uint64 {
+	return b.weights[id]
+}
+
+// Remove sets the count and the weight of the provided ID to zero.
 func (b *ShortBag) Remove(id ShortID) {
 	b.init()
 	count := b.counts[id]
 	delete(b.counts, id)
 	b.size -= count
+
+	weight := b.weights[id]
+	delete(b.weights, id)
+	b.totalWeight -= weight
 }
 
 // Len returns the number of times an id has been added.
 func (b *ShortBag) Len() int { return b.size }
 
+// TotalWeight returns the total weight ids have been added with.
+func (b *ShortBag) TotalWeight() uint64 { return b.totalWeight }
+
+// ModeWeight returns the id that has been added with the most weight and its
+// weight. Ties are broken arbitrarily.
+func (b *ShortBag) ModeWeight() (ShortID, uint64) {
+	mode, modeWeight := ShortEmpty, uint64(0)
+	for id, weight := range b.weights {
+		if weight > modeWeight {
+			mode, modeWeight = id, weight
+		}
+	}
+	return mode, modeWeight
+}
+
+// WeightThreshold returns the ids that have been added with at least
+// [threshold] weight.
+func (b *ShortBag) WeightThreshold(threshold uint64) ShortSet {
+	met := ShortSet{}
+	for id, weight := range b.weights {
+		if weight >= threshold {
+			met.Add(id)
+		}
+	}
+	return met
+}
+
 // List returns a list of all ids that have been added.
 func (b *ShortBag) List() []ShortID {
 	idList := make([]ShortID, len(b.counts))
@@ -76,7 +137,7 @@ func (b *ShortBag) Equals(oIDs ShortBag) bool {
 		return false
 	}
 	for key, value := range b.counts {
-		if value != oIDs.counts[key] {
+		if value != oIDs.counts[key] || b.weights[key] != oIDs.weights[key] {
 			return false
 		}
 	}
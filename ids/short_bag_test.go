@@ -0,0 +1,37 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"testing"
+)
+
+func TestShortBagAddWeighted(t *testing.T) {
+	id0 := ShortEmpty
+	id1 := ShortID{1}
+
+	bag := ShortBag{}
+	bag.AddWeighted(id0, 4)
+	bag.AddWeighted(id1, 7)
+	bag.AddWeighted(id0, 6)
+
+	if count := bag.Count(id0); count != 2 {
+		t.Fatalf("ShortBag.Count returned %d expected %d", count, 2)
+	} else if weight := bag.Weight(id0); weight != 10 {
+		t.Fatalf("ShortBag.Weight returned %d expected %d", weight, 10)
+	} else if weight := bag.TotalWeight(); weight != 17 {
+		t.Fatalf("ShortBag.TotalWeight returned %d expected %d", weight, 17)
+	} else if mode, weight := bag.ModeWeight(); mode != id0 || weight != 10 {
+		t.Fatalf("ShortBag.ModeWeight returned (%s, %d) expected (%s, %d)", mode, weight, id0, 10)
+	} else if threshold := bag.WeightThreshold(8); threshold.Len() != 1 || !threshold.Contains(id0) {
+		t.Fatalf("ShortBag.WeightThreshold returned %s expected %s", threshold, []ShortID{id0})
+	}
+
+	bag.Remove(id0)
+	if weight := bag.TotalWeight(); weight != 7 {
+		t.Fatalf("ShortBag.TotalWeight returned %d expected %d", weight, 7)
+	} else if mode, weight := bag.ModeWeight(); mode != id1 || weight != 7 {
+		t.Fatalf("ShortBag.ModeWeight returned (%s, %d) expected (%s, %d)", mode, weight, id1, 7)
+	}
+}
@@ -55,6 +55,34 @@ func (ids *ShortSet) Contains(id ShortID) bool {
 	return contains
 }
 
+// Intersection returns a new set of the ids that are in both this set and
+// [set]
+func (ids ShortSet) Intersection(set ShortSet) ShortSet {
+	small, big := ids, set
+	if small.Len() > big.Len() {
+		small, big = big, small
+	}
+	intersection := NewShortSet(small.Len())
+	for id := range small {
+		if _, ok := big[id]; ok {
+			intersection[id] = struct{}{}
+		}
+	}
+	return intersection
+}
+
+// Difference returns a new set of the ids that are in this set but not in
+// [set]
+func (ids ShortSet) Difference(set ShortSet) ShortSet {
+	difference := NewShortSet(ids.Len())
+	for id := range ids {
+		if _, ok := set[id]; !ok {
+			difference[id] = struct{}{}
+		}
+	}
+	return difference
+}
+
 // Len returns the number of ids in this set
 func (ids ShortSet) Len() int { return len(ids) }
 
@@ -69,6 +97,14 @@ func (ids *ShortSet) Remove(idList ...ShortID) {
 // Clear empties this set
 func (ids *ShortSet) Clear() { *ids = nil }
 
+// SortedList returns the ids in this set sorted lexicographically, so that
+// they can be iterated over in a deterministic order
+func (ids ShortSet) SortedList() []ShortID {
+	idList := ids.List()
+	SortShortIDs(idList)
+	return idList
+}
+
 // CappedList returns a list of length at most [size].
 // Size should be >= 0. If size < 0, returns nil.
 func (ids ShortSet) CappedList(size int) []ShortID {
@@ -265,3 +265,22 @@ func TestShortSetPop(t *testing.T) {
 	_, ok = s.Pop()
 	assert.False(t, ok)
 }
+
+func TestShortSetAlgebra(t *testing.T) {
+	id0, id1, id2 := ShortID{0}, ShortID{1}, ShortID{2}
+	s0 := ShortSet{}
+	s0.Add(id2, id1)
+	s1 := ShortSet{}
+	s1.Add(id0, id1)
+
+	intersection := s0.Intersection(s1)
+	assert.EqualValues(t, 1, intersection.Len())
+	assert.True(t, intersection.Contains(id1))
+
+	difference := s0.Difference(s1)
+	assert.EqualValues(t, 1, difference.Len())
+	assert.True(t, difference.Contains(id2))
+
+	assert.Equal(t, []ShortID{id1, id2}, s0.SortedList())
+	assert.Equal(t, s0, s0.Difference(nil))
+}
@@ -17,3 +17,6 @@ func GenerateTestShortID() ShortID {
 	newShortID, _ := ToShortID(newID[:20])
 	return newShortID
 }
+
+// GenerateTestNodeID returns a new ID that should only be used for testing
+func GenerateTestNodeID() NodeID { return NodeID(GenerateTestShortID()) }
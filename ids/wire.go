@@ -0,0 +1,167 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// SetEncoding is how the IDs of a set are packed
+type SetEncoding byte
+
+const (
+	// PlainSetEncoding packs every ID of the set in full, in sorted order
+	PlainSetEncoding SetEncoding = iota
+	// DeltaSetEncoding packs the IDs of the set in sorted order, without the
+	// leading bytes that each ID shares with the previous one. This is
+	// smaller for sets of IDs that share prefixes.
+	DeltaSetEncoding
+)
+
+var (
+	errUnknownSetEncoding = errors.New("unknown set encoding")
+	errTooManyIDs         = errors.New("too many IDs")
+	errNotSortedUnique    = errors.New("IDs aren't sorted and unique")
+	errNonCanonicalDelta  = errors.New("shared prefix of ID isn't canonical")
+	errZeroCount          = errors.New("ID has a count of zero")
+)
+
+// PackSet packs [set] into [p] with [encoding]. The IDs are packed in sorted
+// order, so that equal sets are packed into equal bytes.
+func PackSet(p *wrappers.Packer, set Set, encoding SetEncoding) {
+	idList := set.SortedList()
+	p.PackByte(byte(encoding))
+	p.PackInt(uint32(len(idList)))
+	switch encoding {
+	case PlainSetEncoding:
+		for _, id := range idList {
+			p.PackFixedBytes(id[:])
+		}
+	case DeltaSetEncoding:
+		prev := Empty
+		for i, id := range idList {
+			shared := 0
+			if i > 0 {
+				shared = commonPrefixLen(prev, id)
+			}
+			p.PackByte(byte(shared))
+			p.PackFixedBytes(id[shared:])
+			prev = id
+		}
+	default:
+		p.Add(fmt.Errorf("%w: %d", errUnknownSetEncoding, encoding))
+	}
+}
+
+// UnpackSet unpacks a set packed by PackSet from [p]. At most [maxSize] IDs
+// are unpacked, so that the packed bytes can't make this allocate an
+// arbitrarily large set. Only the canonical packing of a set is accepted.
+func UnpackSet(p *wrappers.Packer, maxSize int) Set {
+	encoding := SetEncoding(p.UnpackByte())
+	size := p.UnpackInt()
+	if p.Errored() {
+		return nil
+	}
+	if uint64(size) > uint64(maxSize) {
+		p.Add(fmt.Errorf("%w: %d > %d", errTooManyIDs, size, maxSize))
+		return nil
+	}
+
+	set := NewSet(int(size))
+	prev := Empty
+	for i := 0; i < int(size); i++ {
+		var id ID
+		switch encoding {
+		case PlainSetEncoding:
+			copy(id[:], p.UnpackFixedBytes(len(id)))
+		case DeltaSetEncoding:
+			shared := int(p.UnpackByte())
+			if shared >= len(id) || (i == 0 && shared != 0) {
+				p.Add(errNonCanonicalDelta)
+				return nil
+			}
+			copy(id[:shared], prev[:shared])
+			copy(id[shared:], p.UnpackFixedBytes(len(id)-shared))
+			if i > 0 && commonPrefixLen(prev, id) != shared {
+				p.Add(errNonCanonicalDelta)
+				return nil
+			}
+		default:
+			p.Add(fmt.Errorf("%w: %d", errUnknownSetEncoding, encoding))
+			return nil
+		}
+		if p.Errored() {
+			return nil
+		}
+		if i > 0 && prev.Compare(id) >= 0 {
+			p.Add(errNotSortedUnique)
+			return nil
+		}
+		set.Add(id)
+		prev = id
+	}
+	return set
+}
+
+// PackBag packs the IDs of [bag] and their counts into [p]. The IDs are
+// packed in sorted order, so that equal bags are packed into equal bytes. The
+// weights of the bag aren't packed.
+func PackBag(p *wrappers.Packer, bag *Bag) {
+	idList := bag.List()
+	SortIDs(idList)
+	p.PackInt(uint32(len(idList)))
+	for _, id := range idList {
+		p.PackFixedBytes(id[:])
+		p.PackInt(uint32(bag.Count(id)))
+	}
+}
+
+// UnpackBag unpacks a bag packed by PackBag from [p]. At most [maxSize]
+// distinct IDs are unpacked. Only the canonical packing of a bag is accepted.
+func UnpackBag(p *wrappers.Packer, maxSize int) Bag {
+	bag := Bag{}
+	size := p.UnpackInt()
+	if p.Errored() {
+		return bag
+	}
+	if uint64(size) > uint64(maxSize) {
+		p.Add(fmt.Errorf("%w: %d > %d", errTooManyIDs, size, maxSize))
+		return bag
+	}
+
+	prev := Empty
+	for i := 0; i < int(size); i++ {
+		var id ID
+		copy(id[:], p.UnpackFixedBytes(len(id)))
+		count := p.UnpackInt()
+		if p.Errored() {
+			return Bag{}
+		}
+		if i > 0 && prev.Compare(id) >= 0 {
+			p.Add(errNotSortedUnique)
+			return Bag{}
+		}
+		if count == 0 {
+			p.Add(fmt.Errorf("%w: %s", errZeroCount, id))
+			return Bag{}
+		}
+		bag.AddCount(id, int(count))
+		prev = id
+	}
+	return bag
+}
+
+// commonPrefixLen returns the number of leading bytes that [id0] and [id1]
+// share
+func commonPrefixLen(id0, id1 ID) int {
+	for i := range id0 {
+		if id0[i] != id1[i] {
+			return i
+		}
+	}
+	return len(id0)
+}
@@ -0,0 +1,111 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+func TestPackSet(t *testing.T) {
+	set := Set{}
+	base := GenerateTestID()
+	for i := uint64(0); i < 10; i++ {
+		set.Add(base.Prefix(i))
+	}
+	// IDs that share most of their bytes
+	for i := byte(0); i < 10; i++ {
+		id := base
+		id[31] = i
+		set.Add(id)
+	}
+
+	for _, encoding := range []SetEncoding{PlainSetEncoding, DeltaSetEncoding} {
+		p := wrappers.Packer{MaxSize: 1 << 20}
+		PackSet(&p, set, encoding)
+		if p.Errored() {
+			t.Fatal(p.Err)
+		}
+		packed := p.Bytes
+
+		p = wrappers.Packer{Bytes: packed}
+		unpacked := UnpackSet(&p, set.Len())
+		if p.Errored() {
+			t.Fatal(p.Err)
+		}
+		if !unpacked.Equals(set) {
+			t.Fatalf("expected %s but got %s", set, unpacked)
+		}
+
+		p = wrappers.Packer{Bytes: packed}
+		if UnpackSet(&p, set.Len()-1); !errors.Is(p.Err, errTooManyIDs) {
+			t.Fatalf("expected %s but got %v", errTooManyIDs, p.Err)
+		}
+	}
+
+	plain := wrappers.Packer{MaxSize: 1 << 20}
+	PackSet(&plain, set, PlainSetEncoding)
+	delta := wrappers.Packer{MaxSize: 1 << 20}
+	PackSet(&delta, set, DeltaSetEncoding)
+	if len(delta.Bytes) >= len(plain.Bytes) {
+		t.Fatalf("delta encoding should be smaller, but is %d bytes vs %d", len(delta.Bytes), len(plain.Bytes))
+	}
+}
+
+func TestUnpackSetNonCanonical(t *testing.T) {
+	id0, id1 := ID{1}, ID{2}
+
+	// IDs out of order
+	p := wrappers.Packer{MaxSize: 1 << 10}
+	p.PackByte(byte(PlainSetEncoding))
+	p.PackInt(2)
+	p.PackFixedBytes(id1[:])
+	p.PackFixedBytes(id0[:])
+	p = wrappers.Packer{Bytes: p.Bytes}
+	if UnpackSet(&p, 2); !errors.Is(p.Err, errNotSortedUnique) {
+		t.Fatalf("expected %s but got %v", errNotSortedUnique, p.Err)
+	}
+
+	// The second ID doesn't share its first byte with the first, but is
+	// packed as if it does
+	p = wrappers.Packer{MaxSize: 1 << 10}
+	p.PackByte(byte(DeltaSetEncoding))
+	p.PackInt(2)
+	p.PackByte(0)
+	p.PackFixedBytes(id0[:])
+	p.PackByte(1)
+	p.PackFixedBytes(id1[1:])
+	p = wrappers.Packer{Bytes: p.Bytes}
+	if UnpackSet(&p, 2); !errors.Is(p.Err, errNonCanonicalDelta) {
+		t.Fatalf("expected %s but got %v", errNonCanonicalDelta, p.Err)
+	}
+}
+
+func TestPackBag(t *testing.T) {
+	bag := Bag{}
+	bag.AddCount(ID{1}, 3)
+	bag.AddCount(ID{2}, 1)
+
+	p := wrappers.Packer{MaxSize: 1 << 10}
+	PackBag(&p, &bag)
+	p = wrappers.Packer{Bytes: p.Bytes}
+	unpacked := UnpackBag(&p, 2)
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+	if !unpacked.Equals(bag) {
+		t.Fatalf("expected %s but got %s", &bag, &unpacked)
+	}
+
+	p = wrappers.Packer{MaxSize: 1 << 10}
+	p.PackInt(1)
+	p.PackFixedBytes(make([]byte, 32))
+	p.PackInt(0)
+	p = wrappers.Packer{Bytes: p.Bytes}
+	if UnpackBag(&p, 1); !errors.Is(p.Err, errZeroCount) {
+		t.Fatalf("expected %s but got %v", errZeroCount, p.Err)
+	}
+}
@@ -48,3 +48,9 @@ func (c *Client) IsAccepted(args *GetIndexArgs) (bool, error) {
 	err := c.SendRequest("isAccepted", args, &response)
 	return response, err
 }
+
+func (c *Client) EstimateFee(args *EstimateFeeArgs) (EstimateFeeResponse, error) {
+	var response EstimateFeeResponse
+	err := c.SendRequest("estimateFee", args, &response)
+	return response, err
+}
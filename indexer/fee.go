@@ -0,0 +1,123 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var errZeroTargetLatency = errors.New("target latency must be positive")
+
+// FeeReporter is implemented by VMs that can report the fees paid by the
+// containers that they accept, so that the fees of new containers can be
+// estimated from the indexed containers
+type FeeReporter interface {
+	// MinFee returns the smallest fee that a container must pay to be
+	// accepted
+	MinFee() uint64
+
+	// ContainerFee returns the fee paid by the accepted container
+	// [containerBytes]
+	ContainerFee(containerBytes []byte) (uint64, error)
+}
+
+// FeeEstimate is a suggested fee, along with the data it was estimated from
+type FeeEstimate struct {
+	// Fee that a container should pay to be accepted within the target latency
+	Fee uint64
+	// Smallest fee that a container must pay to be accepted
+	MinFee uint64
+	// Percentile of the recently paid fees that [Fee] was chosen at, between
+	// 0 and 1
+	Percentile float64
+	// Number of recently accepted containers whose fees were sampled
+	NumContainers int
+	// Number of containers accepted per second while they were sampled
+	AcceptanceRate float64
+}
+
+// estimateFee suggests the fee that a container should pay to be accepted
+// within [targetLatency], based on the fees paid by the last [numContainers]
+// containers accepted into [index].
+//
+// Containers are assumed to be accepted at the rate that the sampled
+// containers were accepted at, with the highest paying containers accepted
+// first. So, a container is expected to be accepted within [targetLatency] if
+// it pays more than all but the containers that would be accepted within
+// [targetLatency]. The suggested fee is never less than the minimum fee.
+func estimateFee(
+	index Index,
+	reporter FeeReporter,
+	numContainers uint64,
+	targetLatency time.Duration,
+) (FeeEstimate, error) {
+	estimate := FeeEstimate{
+		Fee:    reporter.MinFee(),
+		MinFee: reporter.MinFee(),
+	}
+	if targetLatency <= 0 {
+		return estimate, errZeroTargetLatency
+	}
+
+	lastAccepted, err := index.GetLastAccepted()
+	if err == errNoneAccepted {
+		// Nothing was accepted yet, so paying the minimum fee is enough
+		return estimate, nil
+	}
+	if err != nil {
+		return estimate, fmt.Errorf("couldn't get last accepted container: %w", err)
+	}
+	lastAcceptedIndex, err := index.GetIndex(lastAccepted.ID)
+	if err != nil {
+		return estimate, fmt.Errorf("couldn't get index: %w", err)
+	}
+	if numContainers > lastAcceptedIndex+1 {
+		numContainers = lastAcceptedIndex + 1
+	}
+	containers, err := index.GetContainerRange(lastAcceptedIndex+1-numContainers, numContainers)
+	if err != nil {
+		return estimate, fmt.Errorf("couldn't get recently accepted containers: %w", err)
+	}
+
+	fees := make([]uint64, 0, len(containers))
+	for _, container := range containers {
+		fee, err := reporter.ContainerFee(container.Bytes)
+		if err != nil {
+			// Containers whose fee can't be reported don't compete for
+			// inclusion by paying fees, so they're skipped
+			continue
+		}
+		fees = append(fees, fee)
+	}
+	estimate.NumContainers = len(fees)
+	if len(fees) == 0 {
+		return estimate, nil
+	}
+	sort.Slice(fees, func(i, j int) bool { return fees[i] < fees[j] })
+
+	// Containers are assumed to be accepted at a steady rate, so the fraction
+	// of the sampled containers that would be accepted within [targetLatency]
+	// is the fraction of the sampled period that [targetLatency] covers.
+	percentile := 0.0
+	if len(containers) > 1 {
+		first := time.Unix(0, containers[0].Timestamp)
+		last := time.Unix(0, containers[len(containers)-1].Timestamp)
+		if period := last.Sub(first); period > 0 {
+			estimate.AcceptanceRate = float64(len(containers)-1) / period.Seconds()
+			if targetLatency < period {
+				percentile = 1 - float64(targetLatency)/float64(period)
+			}
+		}
+	}
+	estimate.Percentile = percentile
+
+	fee := fees[int(percentile*float64(len(fees)-1))]
+	if fee > estimate.Fee {
+		estimate.Fee = fee
+	}
+	return estimate, nil
+}
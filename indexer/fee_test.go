@@ -0,0 +1,106 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/stretchr/testify/assert"
+)
+
+var errNoFee = errors.New("container doesn't pay a fee")
+
+// testFeeReporter reports the fee that is encoded in the first 8 bytes of a
+// container
+type testFeeReporter struct {
+	minFee uint64
+}
+
+func (r *testFeeReporter) MinFee() uint64 { return r.minFee }
+
+func (r *testFeeReporter) ContainerFee(containerBytes []byte) (uint64, error) {
+	if len(containerBytes) < 8 {
+		return 0, errNoFee
+	}
+	return binary.BigEndian.Uint64(containerBytes), nil
+}
+
+func TestEstimateFee(t *testing.T) {
+	assert := assert.New(t)
+	codec := codec.NewDefaultManager()
+	assert.NoError(codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+	indexIntf, err := newIndex(versiondb.New(memdb.New()), logging.NoLog{}, codec, timer.Clock{})
+	assert.NoError(err)
+	idx := indexIntf.(*index)
+	reporter := &testFeeReporter{minFee: 50}
+
+	// Nothing was accepted, so the minimum fee is suggested
+	estimate, err := estimateFee(idx, reporter, 100, time.Second)
+	assert.NoError(err)
+	assert.EqualValues(50, estimate.Fee)
+	assert.EqualValues(0, estimate.NumContainers)
+
+	_, err = estimateFee(idx, reporter, 100, 0)
+	assert.Error(err)
+
+	// Accept a container paying 100, 200, ..., 1000 every second, along with
+	// containers that don't pay a fee
+	ctx := snow.DefaultContextTest()
+	start := time.Unix(1000, 0)
+	for i := 0; i < 10; i++ {
+		idx.clock.Set(start.Add(time.Duration(i) * time.Second))
+
+		containerBytes := make([]byte, 8)
+		binary.BigEndian.PutUint64(containerBytes, uint64(100*(i+1)))
+		assert.NoError(idx.Accept(ctx, ids.GenerateTestID(), containerBytes))
+		assert.NoError(idx.Accept(ctx, ids.GenerateTestID(), nil))
+	}
+
+	// The sampled containers were accepted over 9 seconds, so a third of
+	// them would be accepted within 3 seconds
+	estimate, err = estimateFee(idx, reporter, 100, 3*time.Second)
+	assert.NoError(err)
+	assert.EqualValues(700, estimate.Fee)
+	assert.EqualValues(50, estimate.MinFee)
+	assert.InDelta(2.0/3, estimate.Percentile, 0.001)
+	assert.EqualValues(10, estimate.NumContainers)
+	assert.InDelta(19.0/9, estimate.AcceptanceRate, 0.001)
+
+	// All of the sampled containers would be accepted within 10 seconds
+	estimate, err = estimateFee(idx, reporter, 100, 10*time.Second)
+	assert.NoError(err)
+	assert.EqualValues(100, estimate.Fee)
+	assert.EqualValues(0, estimate.Percentile)
+
+	// Only the last 2 containers are sampled, which were accepted at the same
+	// time, so the acceptance rate is unknown
+	estimate, err = estimateFee(idx, reporter, 2, time.Second)
+	assert.NoError(err)
+	assert.EqualValues(1000, estimate.Fee)
+	assert.EqualValues(1, estimate.NumContainers)
+	assert.EqualValues(0, estimate.AcceptanceRate)
+
+	// The suggested fee is never less than the minimum fee
+	reporter.minFee = 5000
+	estimate, err = estimateFee(idx, reporter, 100, time.Second)
+	assert.NoError(err)
+	assert.EqualValues(5000, estimate.Fee)
+}
+
+func TestServiceEstimateFeeNotReported(t *testing.T) {
+	s := &service{}
+	err := s.EstimateFee(nil, &EstimateFeeArgs{}, &EstimateFeeResponse{})
+	assert.Equal(t, errFeesNotReported, err)
+}
@@ -29,19 +29,24 @@ var (
 	nextAcceptedIndexKey   []byte = []byte{0x00}
 	indexToContainerPrefix []byte = []byte{0x01}
 	containerToIDPrefix    []byte = []byte{0x02}
-	errNoneAccepted               = errors.New("no containers have been accepted")
-	errNumToFetchZero             = fmt.Errorf("numToFetch must be in [1,%d]", MaxFetchedByRange)
+	// Maps to the byte representation of the lowest index whose container
+	// hasn't been pruned
+	firstRetainedIndexKey []byte = []byte{0x03}
+	errNoneAccepted              = errors.New("no containers have been accepted")
+	errNumToFetchZero            = fmt.Errorf("numToFetch must be in [1,%d]", MaxFetchedByRange)
+	errPruned                    = errors.New("container was pruned")
 
 	_ Index = &index{}
 )
 
 // Index indexes containers in their order of acceptance
-// Index implements triggers.Acceptor
+// Index implements triggers.BatchedAcceptor
 // Index is thread-safe.
 // Index assumes that Accept is called before the container is committed to the
 // database of the VM that the container exists in.
 type Index interface {
 	Accept(ctx *snow.Context, containerID ids.ID, container []byte) error
+	AcceptBatch(ctx *snow.Context, containerID ids.ID, container []byte) (database.Batch, error)
 	GetContainerByIndex(index uint64) (Container, error)
 	GetContainerRange(startIndex uint64, numToFetch uint64) ([]Container, error)
 	GetLastAccepted() (Container, error)
@@ -57,6 +62,8 @@ type index struct {
 	lock  sync.RWMutex
 	// The index of the next accepted transaction
 	nextAcceptedIndex uint64
+	// The containers at lower indices were pruned
+	firstRetainedIndex uint64
 	// When [baseDB] is committed, writes to [baseDB]
 	vDB    *versiondb.Database
 	baseDB database.Database
@@ -90,6 +97,16 @@ func newIndex(
 		log:              log,
 	}
 
+	firstRetainedIndex, err := database.GetUInt64(i.vDB, firstRetainedIndexKey)
+	switch err {
+	case nil:
+		i.firstRetainedIndex = firstRetainedIndex
+	case database.ErrNotFound:
+		// Nothing has been pruned
+	default:
+		return nil, fmt.Errorf("couldn't get first retained index from database: %w", err)
+	}
+
 	// Get next accepted index from db
 	nextAcceptedIndex, err := database.GetUInt64(i.vDB, nextAcceptedIndexKey)
 	if err == database.ErrNotFound {
@@ -124,6 +141,47 @@ func (i *index) Accept(ctx *snow.Context, containerID ids.ID, containerBytes []b
 	i.lock.Lock()
 	defer i.lock.Unlock()
 
+	if err := i.stage(ctx, containerID, containerBytes); err != nil {
+		return err
+	}
+	// Atomically commit [i.vDB], [i.indexToContainer], [i.containerToIndex] to [i.baseDB]
+	return i.vDB.Commit()
+}
+
+// AcceptBatch is Accept, but returns the writes that index the container
+// instead of committing them, so that the chain can write them atomically with
+// its acceptance of the container. The writes must be written before the next
+// call to AcceptBatch. Until then, they're visible to the readers of the index.
+// The returned batch isn't used by the index, so the chain may write it
+// without holding [i.lock].
+func (i *index) AcceptBatch(ctx *snow.Context, containerID ids.ID, containerBytes []byte) (database.Batch, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	// The writes returned by the previous call were written by now, so they
+	// don't need to be held in memory anymore
+	i.vDB.Abort()
+
+	if err := i.stage(ctx, containerID, containerBytes); err != nil {
+		return nil, err
+	}
+	// [i.vDB] reuses its batch, so the writes are copied into a batch of
+	// their own
+	vdbBatch, err := i.vDB.CommitBatch()
+	if err != nil {
+		return nil, err
+	}
+	batch := i.baseDB.NewBatch()
+	if err := vdbBatch.Replay(batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// stage writes the indexing of the container into [i.vDB] without committing
+// it. Does nothing if the container is already indexed.
+// Assumes [i.lock] is held.
+func (i *index) stage(ctx *snow.Context, containerID ids.ID, containerBytes []byte) error {
 	// It may be the case that in a previous run of this node, this index committed [containerID]
 	// as accepted and then the node shut down before the VM committed [containerID] as accepted.
 	// In that case, when the node restarts Accept will be called with the same container.
@@ -162,9 +220,7 @@ func (i *index) Accept(ctx *snow.Context, containerID ids.ID, containerBytes []b
 	if err := database.PutUInt64(i.vDB, nextAcceptedIndexKey, i.nextAcceptedIndex); err != nil {
 		return fmt.Errorf("couldn't put accepted container %s into index: %w", containerID, err)
 	}
-
-	// Atomically commit [i.vDB], [i.indexToContainer], [i.containerToIndex] to [i.baseDB]
-	return i.vDB.Commit()
+	return nil
 }
 
 // Returns the ID of the [index]th accepted container and the container itself.
@@ -184,6 +240,9 @@ func (i *index) getContainerByIndex(index uint64) (Container, error) {
 	if !ok || index > lastAcceptedIndex {
 		return Container{}, fmt.Errorf("no container at index %d", index)
 	}
+	if index < i.firstRetainedIndex {
+		return Container{}, fmt.Errorf("%w: container at index %d", errPruned, index)
+	}
 	indexBytes := database.PackUInt64(index)
 	return i.getContainerByIndexBytes(indexBytes)
 }
@@ -223,6 +282,8 @@ func (i *index) GetContainerRange(startIndex, numToFetch uint64) ([]Container, e
 		return nil, errNoneAccepted
 	} else if startIndex > lastAcceptedIndex {
 		return nil, fmt.Errorf("start index (%d) > last accepted index (%d)", startIndex, lastAcceptedIndex)
+	} else if startIndex < i.firstRetainedIndex {
+		return nil, fmt.Errorf("%w: start index (%d) < first retained index (%d)", errPruned, startIndex, i.firstRetainedIndex)
 	}
 
 	// Calculate the last index we will fetch
@@ -256,11 +317,11 @@ func (i *index) GetContainerByID(containerID ids.ID) (Container, error) {
 	defer i.lock.RUnlock()
 
 	// Read index from database
-	indexBytes, err := i.containerToIndex.Get(containerID[:])
+	index, err := database.GetUInt64(i.containerToIndex, containerID[:])
 	if err != nil {
 		return Container{}, err
 	}
-	return i.getContainerByIndexBytes(indexBytes)
+	return i.getContainerByIndex(index)
 }
 
 // GetLastAccepted returns the last accepted container.
@@ -284,3 +345,76 @@ func (i *index) GetLastAccepted() (Container, error) {
 func (i *index) lastAcceptedIndex() (uint64, bool) {
 	return i.nextAcceptedIndex - 1, i.nextAcceptedIndex != 0
 }
+
+// Returns the number of containers that haven't been pruned
+func (i *index) numRetained() uint64 {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.nextAcceptedIndex - i.firstRetainedIndex
+}
+
+// prune deletes the oldest containers, up to [maxPruned] of them, that are
+// neither among the [retainHeight] most recently accepted containers nor were
+// accepted at or after [retainTime], which is in Unix nanoseconds. If
+// [retainHeight] or [retainTime] is 0, it doesn't retain any containers. The
+// index of each pruned container is kept, so GetIndex still reports that it
+// was accepted. The last accepted container is never pruned.
+// Returns the number of containers that were pruned and true if there may be
+// more containers to prune.
+func (i *index) prune(retainHeight uint64, retainTime int64, maxPruned int) (int, bool, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	// The deletions are written through a database of their own, since
+	// [i.vDB] may hold writes returned by AcceptBatch that the chain hasn't
+	// written yet. Committing those here would index the container before the
+	// chain accepts it.
+	pruneDB := versiondb.New(i.baseDB)
+	indexToContainer := prefixdb.New(indexToContainerPrefix, pruneDB)
+
+	end := i.nextAcceptedIndex
+	if end > 0 {
+		// Keep the last accepted container
+		end--
+	}
+	if retainHeight > 0 {
+		if i.nextAcceptedIndex < retainHeight {
+			return 0, false, nil
+		}
+		end = math.Min64(end, i.nextAcceptedIndex-retainHeight)
+	}
+
+	index := i.firstRetainedIndex
+	for ; index < end && int(index-i.firstRetainedIndex) < maxPruned; index++ {
+		indexBytes := database.PackUInt64(index)
+		if retainTime > 0 {
+			container, err := i.getContainerByIndexBytes(indexBytes)
+			if err != nil {
+				return 0, false, err
+			}
+			if container.Timestamp >= retainTime {
+				// Containers are accepted in order, so all later containers
+				// are retained too
+				end = index
+				break
+			}
+		}
+		if err := indexToContainer.Delete(indexBytes); err != nil {
+			return 0, false, fmt.Errorf("couldn't delete container at index %d: %w", index, err)
+		}
+	}
+
+	numPruned := int(index - i.firstRetainedIndex)
+	if numPruned == 0 {
+		return 0, false, nil
+	}
+	if err := database.PutUInt64(pruneDB, firstRetainedIndexKey, index); err != nil {
+		return 0, false, fmt.Errorf("couldn't put first retained index: %w", err)
+	}
+	if err := pruneDB.Commit(); err != nil {
+		return 0, false, err
+	}
+	i.firstRetainedIndex = index
+	return numPruned, index < end, nil
+}
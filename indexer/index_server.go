@@ -0,0 +1,244 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer/indexerproto"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/triggers"
+)
+
+const (
+	streamNamePrefix = "stream-"
+
+	// Max number of accepted containers that can be waiting to be sent to a
+	// stream before the stream is closed
+	maxPendingStreamed = 1024
+)
+
+var (
+	errStreamTooSlow = errors.New("stream closed because it fell too far behind")
+
+	_ indexerproto.IndexServer = &Server{}
+	_ triggers.Acceptor        = &acceptedStreamer{}
+)
+
+// ChainLookup returns the ID of the chain with the given alias
+type ChainLookup interface {
+	Lookup(alias string) (ids.ID, error)
+}
+
+// Server serves the indexed containers of each chain over gRPC, along with
+// streams of newly accepted containers
+type Server struct {
+	indexerproto.UnimplementedIndexServer
+	indexer                                 Indexer
+	chains                                  ChainLookup
+	consensusDispatcher, decisionDispatcher *triggers.EventDispatcher
+
+	// Used to give each stream a unique identifier
+	nextStreamID uint64
+}
+
+// NewServer returns a gRPC server for the indices of [indexer]. Accepted
+// containers are streamed from the given dispatchers.
+func NewServer(
+	indexer Indexer,
+	chains ChainLookup,
+	consensusDispatcher *triggers.EventDispatcher,
+	decisionDispatcher *triggers.EventDispatcher,
+) *Server {
+	return &Server{
+		indexer:             indexer,
+		chains:              chains,
+		consensusDispatcher: consensusDispatcher,
+		decisionDispatcher:  decisionDispatcher,
+	}
+}
+
+func (s *Server) GetContainerByIndex(
+	_ context.Context,
+	req *indexerproto.GetContainerByIndexRequest,
+) (*indexerproto.Container, error) {
+	index, err := s.getIndex(req.Chain, req.ContainerType)
+	if err != nil {
+		return nil, err
+	}
+	container, err := index.GetContainerByIndex(req.Index)
+	if err != nil {
+		return nil, err
+	}
+	return &indexerproto.Container{
+		Id:        container.ID[:],
+		Bytes:     container.Bytes,
+		Timestamp: container.Timestamp,
+		Index:     req.Index,
+	}, nil
+}
+
+func (s *Server) GetContainerByID(
+	_ context.Context,
+	req *indexerproto.GetContainerByIDRequest,
+) (*indexerproto.Container, error) {
+	index, err := s.getIndex(req.Chain, req.ContainerType)
+	if err != nil {
+		return nil, err
+	}
+	containerID, err := ids.ToID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	container, err := index.GetContainerByID(containerID)
+	if err != nil {
+		return nil, err
+	}
+	return newProtoContainer(index, container)
+}
+
+func (s *Server) GetContainerRange(
+	_ context.Context,
+	req *indexerproto.GetContainerRangeRequest,
+) (*indexerproto.GetContainerRangeResponse, error) {
+	index, err := s.getIndex(req.Chain, req.ContainerType)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := index.GetContainerRange(req.StartIndex, req.NumToFetch)
+	if err != nil {
+		return nil, err
+	}
+
+	protoContainers := make([]*indexerproto.Container, len(containers))
+	for i, container := range containers {
+		containerID := container.ID
+		protoContainers[i] = &indexerproto.Container{
+			Id:        containerID[:],
+			Bytes:     container.Bytes,
+			Timestamp: container.Timestamp,
+			Index:     req.StartIndex + uint64(i),
+		}
+	}
+	return &indexerproto.GetContainerRangeResponse{Containers: protoContainers}, nil
+}
+
+func (s *Server) GetLastAccepted(
+	_ context.Context,
+	req *indexerproto.GetLastAcceptedRequest,
+) (*indexerproto.Container, error) {
+	index, err := s.getIndex(req.Chain, req.ContainerType)
+	if err != nil {
+		return nil, err
+	}
+	container, err := index.GetLastAccepted()
+	if err != nil {
+		return nil, err
+	}
+	return newProtoContainer(index, container)
+}
+
+// StreamAccepted sends each container of the requested type that the chain
+// accepts until the stream is closed. Containers are streamed whether or not
+// they're indexed.
+func (s *Server) StreamAccepted(
+	req *indexerproto.StreamAcceptedRequest,
+	stream indexerproto.Index_StreamAcceptedServer,
+) error {
+	chainID, err := s.chains.Lookup(req.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %s: %w", req.Chain, err)
+	}
+
+	var dispatcher *triggers.EventDispatcher
+	switch req.ContainerType {
+	case BlockContainers, VtxContainers:
+		dispatcher = s.consensusDispatcher
+	case TxContainers:
+		dispatcher = s.decisionDispatcher
+	default:
+		return fmt.Errorf("unknown container type %q", req.ContainerType)
+	}
+
+	streamer := &acceptedStreamer{
+		accepted: make(chan *indexerproto.AcceptedContainer, maxPendingStreamed),
+	}
+	identifier := fmt.Sprintf("%s%d", streamNamePrefix, atomic.AddUint64(&s.nextStreamID, 1))
+	if err := dispatcher.RegisterChain(chainID, identifier, streamer, false); err != nil {
+		return err
+	}
+	defer func() {
+		_ = dispatcher.DeregisterChain(chainID, identifier)
+	}()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case container, ok := <-streamer.accepted:
+			if !ok {
+				return errStreamTooSlow
+			}
+			if err := stream.Send(container); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// getIndex returns the index of the [containerType] containers of the chain
+// with ID or alias [chain]
+func (s *Server) getIndex(chain, containerType string) (Index, error) {
+	chainID, err := s.chains.Lookup(chain)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find chain %s: %w", chain, err)
+	}
+	index, ok := s.indexer.GetIndex(chainID, containerType)
+	if !ok {
+		return nil, fmt.Errorf("%s containers of chain %s aren't indexed", containerType, chain)
+	}
+	return index, nil
+}
+
+func newProtoContainer(index Index, container Container) (*indexerproto.Container, error) {
+	i, err := index.GetIndex(container.ID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get index: %w", err)
+	}
+	return &indexerproto.Container{
+		Id:        container.ID[:],
+		Bytes:     container.Bytes,
+		Timestamp: container.Timestamp,
+		Index:     i,
+	}, nil
+}
+
+// acceptedStreamer queues accepted containers to be sent to a stream. If the
+// queue overflows, it's closed and no more containers are queued.
+type acceptedStreamer struct {
+	// Only accessed in Accept, which the dispatcher never calls concurrently
+	overflowed bool
+	accepted   chan *indexerproto.AcceptedContainer
+}
+
+func (a *acceptedStreamer) Accept(_ *snow.Context, containerID ids.ID, container []byte) error {
+	if a.overflowed {
+		return nil
+	}
+	select {
+	case a.accepted <- &indexerproto.AcceptedContainer{
+		Id:    containerID[:],
+		Bytes: append([]byte(nil), container...),
+	}:
+	default:
+		a.overflowed = true
+		close(a.accepted)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"context"
+	"log"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer/indexerproto"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/triggers"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+const bufSize = 1 << 20
+
+// testIndexer has a tx index for a single chain
+type testIndexer struct {
+	chainID ids.ID
+	txIndex Index
+}
+
+func (i *testIndexer) RegisterChain(string, *snow.Context, common.Engine) {}
+
+func (i *testIndexer) GetIndex(chainID ids.ID, containerType string) (Index, bool) {
+	return i.txIndex, chainID == i.chainID && containerType == TxContainers
+}
+
+func (i *testIndexer) Close() error { return nil }
+
+func newTestIndexClient(t *testing.T, server *Server) (indexerproto.IndexClient, func()) {
+	listener := bufconn.Listen(bufSize)
+	grpcServer := grpc.NewServer()
+	indexerproto.RegisterIndexServer(grpcServer, server)
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatalf("Server exited with error: %v", err)
+		}
+	}()
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+	conn, err := grpc.DialContext(context.Background(), "", dialer, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial: %s", err)
+	}
+	return indexerproto.NewIndexClient(conn), func() {
+		grpcServer.Stop()
+		_ = conn.Close()
+		_ = listener.Close()
+	}
+}
+
+func TestServerGetContainers(t *testing.T) {
+	assert := assert.New(t)
+
+	codec := codec.NewDefaultManager()
+	assert.NoError(codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+	txIndex, err := newIndex(versiondb.New(memdb.New()), logging.NoLog{}, codec, timer.Clock{})
+	assert.NoError(err)
+
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	aliaser := &ids.Aliaser{}
+	aliaser.Initialize()
+	assert.NoError(aliaser.Alias(ctx.ChainID, "X"))
+
+	containerIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	for i, containerID := range containerIDs {
+		assert.NoError(txIndex.Accept(ctx, containerID, []byte{byte(i)}))
+	}
+
+	idxr := &testIndexer{chainID: ctx.ChainID, txIndex: txIndex}
+	client, closeFn := newTestIndexClient(t, NewServer(idxr, aliaser, nil, nil))
+	defer closeFn()
+
+	container, err := client.GetContainerByIndex(context.Background(), &indexerproto.GetContainerByIndexRequest{
+		Chain:         "X",
+		ContainerType: TxContainers,
+		Index:         1,
+	})
+	assert.NoError(err)
+	assert.Equal(containerIDs[1][:], container.Id)
+	assert.Equal([]byte{1}, container.Bytes)
+
+	container, err = client.GetContainerByID(context.Background(), &indexerproto.GetContainerByIDRequest{
+		Chain:         "X",
+		ContainerType: TxContainers,
+		Id:            containerIDs[0][:],
+	})
+	assert.NoError(err)
+	assert.EqualValues(0, container.Index)
+
+	containers, err := client.GetContainerRange(context.Background(), &indexerproto.GetContainerRangeRequest{
+		Chain:         "X",
+		ContainerType: TxContainers,
+		StartIndex:    0,
+		NumToFetch:    2,
+	})
+	assert.NoError(err)
+	assert.Len(containers.Containers, 2)
+
+	container, err = client.GetLastAccepted(context.Background(), &indexerproto.GetLastAcceptedRequest{
+		Chain:         "X",
+		ContainerType: TxContainers,
+	})
+	assert.NoError(err)
+	assert.EqualValues(1, container.Index)
+
+	// Blocks of the chain aren't indexed
+	_, err = client.GetLastAccepted(context.Background(), &indexerproto.GetLastAcceptedRequest{
+		Chain:         "X",
+		ContainerType: BlockContainers,
+	})
+	assert.Error(err)
+}
+
+func TestServerStreamAccepted(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	aliaser := &ids.Aliaser{}
+	aliaser.Initialize()
+	assert.NoError(aliaser.Alias(ctx.ChainID, "X"))
+
+	cd := &triggers.EventDispatcher{}
+	cd.Initialize(logging.NoLog{})
+	dd := &triggers.EventDispatcher{}
+	dd.Initialize(logging.NoLog{})
+
+	server := NewServer(&testIndexer{}, aliaser, cd, dd)
+	client, closeFn := newTestIndexClient(t, server)
+	defer closeFn()
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.StreamAccepted(streamCtx, &indexerproto.StreamAcceptedRequest{
+		Chain:         "X",
+		ContainerType: BlockContainers,
+	})
+	assert.NoError(err)
+
+	// Accept blocks until the stream is registered and receives one
+	blkID := ids.GenerateTestID()
+	received := make(chan *indexerproto.AcceptedContainer)
+	go func() {
+		container, err := stream.Recv()
+		if err == nil {
+			received <- container
+		}
+		close(received)
+	}()
+	var container *indexerproto.AcceptedContainer
+	for container == nil {
+		assert.NoError(cd.Accept(ctx, blkID, []byte{1}))
+		select {
+		case c, ok := <-received:
+			if !ok {
+				t.Fatal("stream closed before receiving a container")
+			}
+			container = c
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	assert.Equal(blkID[:], container.Id)
+	assert.Equal([]byte{1}, container.Bytes)
+}
+
+func TestAcceptedStreamerOverflow(t *testing.T) {
+	assert := assert.New(t)
+
+	streamer := &acceptedStreamer{
+		accepted: make(chan *indexerproto.AcceptedContainer, 1),
+	}
+	ctx := snow.DefaultContextTest()
+	assert.NoError(streamer.Accept(ctx, ids.GenerateTestID(), nil))
+	assert.NoError(streamer.Accept(ctx, ids.GenerateTestID(), nil))
+	assert.NoError(streamer.Accept(ctx, ids.GenerateTestID(), nil))
+	assert.True(streamer.overflowed)
+
+	_, ok := <-streamer.accepted
+	assert.True(ok)
+	_, ok = <-streamer.accepted
+	assert.False(ok)
+}
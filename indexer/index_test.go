@@ -2,10 +2,13 @@ package indexer
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/verify"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
@@ -170,3 +173,246 @@ func TestDontIndexSameContainerTwice(t *testing.T) {
 	assert.NoError(err)
 	assert.EqualValues(gotContainer.Bytes, []byte{1, 2, 3}, "should not have accepted same container twice")
 }
+
+func TestAcceptBatch(t *testing.T) {
+	// Setup
+	assert := assert.New(t)
+	codec := codec.NewDefaultManager()
+	err := codec.RegisterCodec(codecVersion, linearcodec.NewDefault())
+	assert.NoError(err)
+	ctx := snow.DefaultContextTest()
+	baseDB := memdb.New()
+	idx, err := newIndex(baseDB, logging.NoLog{}, codec, timer.Clock{})
+	assert.NoError(err)
+	// Reads what was written, rather than what [idx] holds in memory
+	persisted := func() Index {
+		idx, err := newIndex(versiondb.New(baseDB), logging.NoLog{}, codec, timer.Clock{})
+		assert.NoError(err)
+		return idx
+	}
+
+	// The container isn't indexed until the batch is written, but the index
+	// already reports it
+	firstID := ids.GenerateTestID()
+	batch, err := idx.AcceptBatch(ctx, firstID, []byte{1})
+	assert.NoError(err)
+	_, err = persisted().GetIndex(firstID)
+	assert.Equal(database.ErrNotFound, err)
+	index, err := idx.GetIndex(firstID)
+	assert.NoError(err)
+	assert.EqualValues(0, index)
+	assert.NoError(batch.Write())
+	index, err = persisted().GetIndex(firstID)
+	assert.NoError(err)
+	assert.EqualValues(0, index)
+
+	// An indexed container isn't indexed again
+	batch, err = idx.AcceptBatch(ctx, firstID, []byte{1})
+	assert.NoError(err)
+	assert.NoError(batch.Write())
+	_, err = persisted().GetContainerByIndex(1)
+	assert.Error(err)
+
+	secondID := ids.GenerateTestID()
+	batch, err = idx.AcceptBatch(ctx, secondID, []byte{2})
+	assert.NoError(err)
+	assert.NoError(batch.Write())
+	container, err := persisted().GetContainerByIndex(1)
+	assert.NoError(err)
+	assert.Equal(secondID, container.ID)
+	container, err = idx.GetLastAccepted()
+	assert.NoError(err)
+	assert.Equal(secondID, container.ID)
+}
+
+func TestPruneDoesntWriteAcceptBatch(t *testing.T) {
+	// Setup
+	assert := assert.New(t)
+	codec := codec.NewDefaultManager()
+	err := codec.RegisterCodec(codecVersion, linearcodec.NewDefault())
+	assert.NoError(err)
+	ctx := snow.DefaultContextTest()
+	baseDB := memdb.New()
+	indexIntf, err := newIndex(baseDB, logging.NoLog{}, codec, timer.Clock{})
+	assert.NoError(err)
+	idx := indexIntf.(*index)
+	persisted := func() Index {
+		idx, err := newIndex(versiondb.New(baseDB), logging.NoLog{}, codec, timer.Clock{})
+		assert.NoError(err)
+		return idx
+	}
+
+	firstID := ids.GenerateTestID()
+	assert.NoError(idx.Accept(ctx, firstID, []byte{1}))
+	secondID := ids.GenerateTestID()
+	assert.NoError(idx.Accept(ctx, secondID, []byte{2}))
+
+	// Pruning while the chain hasn't written the batch yet doesn't write the
+	// batch's writes
+	thirdID := ids.GenerateTestID()
+	batch, err := idx.AcceptBatch(ctx, thirdID, []byte{3})
+	assert.NoError(err)
+	numPruned, _, err := idx.prune(2, 0, pruneBatchSize)
+	assert.NoError(err)
+	assert.Equal(1, numPruned)
+	_, err = persisted().GetIndex(thirdID)
+	assert.Equal(database.ErrNotFound, err)
+	_, err = persisted().GetContainerByIndex(0)
+	assert.ErrorIs(err, errPruned)
+
+	// The batch is still intact
+	assert.NoError(batch.Write())
+	index, err := persisted().GetIndex(thirdID)
+	assert.NoError(err)
+	assert.EqualValues(2, index)
+}
+
+func TestPruneByHeight(t *testing.T) {
+	// Setup
+	assert := assert.New(t)
+	codec := codec.NewDefaultManager()
+	err := codec.RegisterCodec(codecVersion, linearcodec.NewDefault())
+	assert.NoError(err)
+	db := memdb.New()
+	ctx := snow.DefaultContextTest()
+	indexIntf, err := newIndex(db, logging.NoLog{}, codec, timer.Clock{})
+	assert.NoError(err)
+	idx := indexIntf.(*index)
+
+	containerIDs := make([]ids.ID, 10)
+	for i := range containerIDs {
+		containerIDs[i] = ids.GenerateTestID()
+		assert.NoError(idx.Accept(ctx, containerIDs[i], utils.RandomBytes(32)))
+	}
+
+	// Prune in batches of 3 until only the last 4 containers are retained
+	numPruned, more, err := idx.prune(4, 0, 3)
+	assert.NoError(err)
+	assert.Equal(3, numPruned)
+	assert.True(more)
+	numPruned, more, err = idx.prune(4, 0, 3)
+	assert.NoError(err)
+	assert.Equal(3, numPruned)
+	assert.False(more)
+	numPruned, _, err = idx.prune(4, 0, 3)
+	assert.NoError(err)
+	assert.Equal(0, numPruned)
+	assert.EqualValues(4, idx.numRetained())
+
+	// Pruned containers can't be fetched, but their indices are kept
+	_, err = idx.GetContainerByIndex(5)
+	assert.ErrorIs(err, errPruned)
+	_, err = idx.GetContainerByID(containerIDs[5])
+	assert.ErrorIs(err, errPruned)
+	_, err = idx.GetContainerRange(0, 10)
+	assert.ErrorIs(err, errPruned)
+	gotIndex, err := idx.GetIndex(containerIDs[5])
+	assert.NoError(err)
+	assert.EqualValues(5, gotIndex)
+
+	containers, err := idx.GetContainerRange(6, 10)
+	assert.NoError(err)
+	assert.Len(containers, 4)
+	assert.Equal(containerIDs[6], containers[0].ID)
+
+	// The pruning persists
+	indexIntf, err = newIndex(db, logging.NoLog{}, codec, timer.Clock{})
+	assert.NoError(err)
+	idx = indexIntf.(*index)
+	assert.EqualValues(4, idx.numRetained())
+	_, err = idx.GetContainerByIndex(5)
+	assert.ErrorIs(err, errPruned)
+}
+
+func TestPruneByAge(t *testing.T) {
+	// Setup
+	assert := assert.New(t)
+	codec := codec.NewDefaultManager()
+	err := codec.RegisterCodec(codecVersion, linearcodec.NewDefault())
+	assert.NoError(err)
+	ctx := snow.DefaultContextTest()
+	indexIntf, err := newIndex(memdb.New(), logging.NoLog{}, codec, timer.Clock{})
+	assert.NoError(err)
+	idx := indexIntf.(*index)
+
+	// Accept a container every second
+	now := time.Unix(1000, 0)
+	for i := 0; i < 10; i++ {
+		idx.clock.Set(now.Add(time.Duration(i) * time.Second))
+		assert.NoError(idx.Accept(ctx, ids.GenerateTestID(), utils.RandomBytes(32)))
+	}
+
+	// Containers accepted at or after the 7th second are retained
+	numPruned, more, err := idx.prune(0, now.Add(7*time.Second).UnixNano(), pruneBatchSize)
+	assert.NoError(err)
+	assert.Equal(7, numPruned)
+	assert.False(more)
+	container, err := idx.GetContainerByIndex(7)
+	assert.NoError(err)
+	assert.Equal(now.Add(7*time.Second).UnixNano(), container.Timestamp)
+
+	// The last accepted container is never pruned
+	numPruned, _, err = idx.prune(0, now.Add(time.Hour).UnixNano(), pruneBatchSize)
+	assert.NoError(err)
+	assert.Equal(2, numPruned)
+	_, err = idx.GetLastAccepted()
+	assert.NoError(err)
+	assert.EqualValues(1, idx.numRetained())
+}
+
+func TestIndexVerifyDB(t *testing.T) {
+	// Setup
+	assert := assert.New(t)
+	codec := codec.NewDefaultManager()
+	err := codec.RegisterCodec(codecVersion, linearcodec.NewDefault())
+	assert.NoError(err)
+	ctx := snow.DefaultContextTest()
+	indexIntf, err := newIndex(memdb.New(), logging.NoLog{}, codec, timer.Clock{})
+	assert.NoError(err)
+	idx := indexIntf.(*index)
+
+	containerIDs := make([]ids.ID, 5)
+	for i := range containerIDs {
+		containerIDs[i] = ids.GenerateTestID()
+		assert.NoError(idx.Accept(ctx, containerIDs[i], utils.RandomBytes(32)))
+	}
+
+	report := verify.NewReport("test")
+	assert.NoError(idx.VerifyDB(true, report))
+	assert.EqualValues(10, report.NumChecked)
+	assert.Empty(report.Issues)
+
+	// Corrupt the index
+	containerID := containerIDs[2]
+	assert.NoError(idx.containerToIndex.Delete(containerID[:]))
+	danglingID := ids.GenerateTestID()
+	assert.NoError(database.PutUInt64(idx.containerToIndex, danglingID[:], 10))
+	assert.NoError(idx.indexToContainer.Put(database.PackUInt64(7), []byte{1}))
+	assert.NoError(idx.indexToContainer.Delete(database.PackUInt64(3)))
+	assert.NoError(idx.vDB.Commit())
+
+	report = verify.NewReport("test")
+	assert.NoError(idx.VerifyDB(false, report))
+	assert.Len(report.Issues, 4)
+	_, err = idx.GetContainerByID(containerID)
+	assert.Error(err)
+
+	report = verify.NewReport("test")
+	assert.NoError(idx.VerifyDB(true, report))
+	assert.Len(report.Issues, 4)
+	assert.Equal(1, report.NumUnrepaired())
+
+	// Only the missing container is left
+	report = verify.NewReport("test")
+	assert.NoError(idx.VerifyDB(true, report))
+	assert.Len(report.Issues, 1)
+	assert.Equal(1, report.NumUnrepaired())
+
+	container, err := idx.GetContainerByID(containerID)
+	assert.NoError(err)
+	assert.Equal(containerID, container.ID)
+	_, err = idx.GetIndex(danglingID)
+	assert.Error(err)
+	_, err = idx.indexToContainer.Get(database.PackUInt64(7))
+	assert.Equal(database.ErrNotFound, err)
+}
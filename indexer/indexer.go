@@ -6,6 +6,9 @@ import (
 	"math"
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/api/openapi"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/network"
@@ -21,18 +24,19 @@ import (
 	"github.com/ava-labs/avalanchego/codec/reflectcodec"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/verify"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman"
 	"github.com/ava-labs/avalanchego/snow/triggers"
 	"github.com/ava-labs/avalanchego/utils/logging"
-	"github.com/gorilla/rpc/v2"
 )
 
 const (
-	indexNamePrefix = "index-"
-	codecVersion    = uint16(0)
+	indexNamePrefix  = "index-"
+	pruningNamespace = constants.PlatformName + "_index_pruning"
+	codecVersion     = uint16(0)
 	// Max size, in bytes, of something serialized by this indexer
 	// Assumes no containers are larger than math.MaxUint32
 	// wrappers.IntLen accounts for the size of the container bytes
@@ -42,6 +46,13 @@ const (
 	codecMaxSize = int(network.DefaultMaxMessageSize) + wrappers.IntLen + wrappers.LongLen + hashing.HashLen + wrappers.ShortLen
 )
 
+// Types of containers that are indexed
+const (
+	BlockContainers = "block"
+	VtxContainers   = "vtx"
+	TxContainers    = "tx"
+)
+
 var (
 	txPrefix                = byte(0x01)
 	vtxPrefix               = byte(0x02)
@@ -55,6 +66,8 @@ var (
 
 // Config for an indexer
 type Config struct {
+	// Must be over the same underlying database as the chains, so that the
+	// indexing of a vertex can be written atomically with the vertex
 	DB                                      database.Database
 	Log                                     logging.Logger
 	IndexingEnabled                         bool
@@ -62,6 +75,19 @@ type Config struct {
 	DecisionDispatcher, ConsensusDispatcher *triggers.EventDispatcher
 	APIServer                               server.RouteAdder
 	ShutdownF                               func()
+
+	// If non-empty, only these chains are indexed. Chains that aren't in the
+	// primary network are only indexed if they're in this set.
+	IndexedChains ids.Set
+
+	// Which indexed containers are pruned. The pruning metrics are registered
+	// with [MetricsRegisterer] if pruning is enabled.
+	Pruning           PruningConfig
+	MetricsRegisterer prometheus.Registerer
+
+	// If true, each index is verified, and repaired where possible, when its
+	// chain is registered
+	VerifyDB bool
 }
 
 // Indexer causes accepted containers for a given chain
@@ -70,6 +96,9 @@ type Config struct {
 // Indexer is threadsafe.
 type Indexer interface {
 	chains.Registrant
+	// GetIndex returns the index of the [containerType] containers of chain
+	// [chainID], and false if they aren't indexed
+	GetIndex(chainID ids.ID, containerType string) (Index, bool)
 	// Close will do nothing and return nil after the first call
 	io.Closer
 }
@@ -82,6 +111,8 @@ func NewIndexer(config Config) (Indexer, error) {
 		db:                   config.DB,
 		allowIncompleteIndex: config.AllowIncompleteIndex,
 		indexingEnabled:      config.IndexingEnabled,
+		indexedChains:        config.IndexedChains,
+		verifyDB:             config.VerifyDB,
 		consensusDispatcher:  config.ConsensusDispatcher,
 		decisionDispatcher:   config.DecisionDispatcher,
 		txIndices:            map[ids.ID]Index{},
@@ -101,7 +132,19 @@ func NewIndexer(config Config) (Indexer, error) {
 		return nil, err
 	}
 	indexer.hasRunBefore = hasRun
-	return indexer, indexer.markHasRun()
+	if err := indexer.markHasRun(); err != nil {
+		return nil, err
+	}
+
+	if config.Pruning.Enabled() {
+		pruner, err := newPruner(config.Log, config.Pruning, pruningNamespace, config.MetricsRegisterer)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create pruner: %w", err)
+		}
+		indexer.pruner = pruner
+		go config.Log.RecoverAndPanic(pruner.Dispatch)
+	}
+	return indexer, nil
 }
 
 // indexer implements Indexer
@@ -129,6 +172,12 @@ type indexer struct {
 	// If false, don't create index for a chain when RegisterChain is called
 	indexingEnabled bool
 
+	// If non-empty, only create indices for the chains in this set
+	indexedChains ids.Set
+
+	// If true, verify and repair each index when it's created
+	verifyDB bool
+
 	// Chain ID --> index of blocks of that chain (if applicable)
 	blockIndices map[ids.ID]Index
 	// Chain ID --> index of vertices of that chain (if applicable)
@@ -140,6 +189,9 @@ type indexer struct {
 	consensusDispatcher *triggers.EventDispatcher
 	// Notifies of newly accepted transactions
 	decisionDispatcher *triggers.EventDispatcher
+
+	// Nil if pruning is disabled
+	pruner *pruner
 }
 
 // Assumes [engine]'s context lock is not held
@@ -147,15 +199,15 @@ func (i *indexer) RegisterChain(name string, ctx *snow.Context, engine common.En
 	i.lock.Lock()
 	defer i.lock.Unlock()
 
+	chainID := ctx.ChainID
 	if i.closed {
 		i.log.Debug("not registering chain %s because indexer is closed", name)
 		return
-	} else if ctx.SubnetID != constants.PrimaryNetworkID {
+	} else if ctx.SubnetID != constants.PrimaryNetworkID && !i.indexedChains.Contains(chainID) {
 		i.log.Debug("not registering chain %s because it's not in primary network", name)
 		return
 	}
 
-	chainID := ctx.ChainID
 	if i.blockIndices[chainID] != nil || i.txIndices[chainID] != nil || i.vtxIndices[chainID] != nil {
 		i.log.Warn("chain %s is already being indexed", chainID)
 		return
@@ -181,7 +233,8 @@ func (i *indexer) RegisterChain(name string, ctx *snow.Context, engine common.En
 		return
 	}
 
-	if !i.indexingEnabled { // Indexing is disabled
+	// Indexing is disabled, or this chain wasn't opted in
+	if !i.indexingEnabled || (i.indexedChains.Len() > 0 && !i.indexedChains.Contains(chainID)) {
 		if previouslyIndexed && !i.allowIncompleteIndex {
 			// We indexed this chain in a previous run but not in this run.
 			// This would create an incomplete index, which is not allowed, so exit.
@@ -221,9 +274,12 @@ func (i *indexer) RegisterChain(name string, ctx *snow.Context, engine common.En
 		return
 	}
 
+	// The fees of the containers are only reported if the VM reports them
+	feeReporter, _ := engine.GetVM().(FeeReporter)
+
 	switch engine.(type) {
 	case snowman.Engine:
-		index, err := i.registerChainHelper(chainID, blockPrefix, name, "block", i.consensusDispatcher)
+		index, err := i.registerChainHelper(chainID, blockPrefix, name, BlockContainers, i.consensusDispatcher, feeReporter)
 		if err != nil {
 			i.log.Fatal("couldn't create block index for %s: %s", name, err)
 			if err := i.close(); err != nil {
@@ -233,7 +289,7 @@ func (i *indexer) RegisterChain(name string, ctx *snow.Context, engine common.En
 		}
 		i.blockIndices[chainID] = index
 	case avalanche.Engine:
-		vtxIndex, err := i.registerChainHelper(chainID, vtxPrefix, name, "vtx", i.consensusDispatcher)
+		vtxIndex, err := i.registerChainHelper(chainID, vtxPrefix, name, VtxContainers, i.consensusDispatcher, nil)
 		if err != nil {
 			i.log.Fatal("couldn't create vertex index for %s: %s", name, err)
 			if err := i.close(); err != nil {
@@ -243,7 +299,7 @@ func (i *indexer) RegisterChain(name string, ctx *snow.Context, engine common.En
 		}
 		i.vtxIndices[chainID] = vtxIndex
 
-		txIndex, err := i.registerChainHelper(chainID, txPrefix, name, "tx", i.decisionDispatcher)
+		txIndex, err := i.registerChainHelper(chainID, txPrefix, name, TxContainers, i.decisionDispatcher, feeReporter)
 		if err != nil {
 			i.log.Fatal("couldn't create tx index for %s: %s", name, err)
 			if err := i.close(); err != nil {
@@ -261,11 +317,28 @@ func (i *indexer) RegisterChain(name string, ctx *snow.Context, engine common.En
 	}
 }
 
+func (i *indexer) GetIndex(chainID ids.ID, containerType string) (Index, bool) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	var index Index
+	switch containerType {
+	case BlockContainers:
+		index = i.blockIndices[chainID]
+	case VtxContainers:
+		index = i.vtxIndices[chainID]
+	case TxContainers:
+		index = i.txIndices[chainID]
+	}
+	return index, index != nil
+}
+
 func (i *indexer) registerChainHelper(
 	chainID ids.ID,
 	prefixEnd byte,
 	name, endpoint string,
 	dispatcher *triggers.EventDispatcher,
+	feeReporter FeeReporter,
 ) (Index, error) {
 	prefix := make([]byte, hashing.HashLen+wrappers.ByteLen)
 	copy(prefix, chainID[:])
@@ -277,6 +350,15 @@ func (i *indexer) registerChainHelper(
 		return nil, err
 	}
 
+	if i.verifyDB {
+		report := verify.NewReport(fmt.Sprintf("index %s/%s", name, endpoint))
+		if err := index.(verify.Verifier).VerifyDB(true, report); err != nil {
+			_ = index.Close()
+			return nil, fmt.Errorf("couldn't verify index: %w", err)
+		}
+		report.Log(i.log)
+	}
+
 	// Register index to learn about new accepted vertices
 	if err := dispatcher.RegisterChain(chainID, fmt.Sprintf("%s%s", indexNamePrefix, chainID), index, true); err != nil {
 		_ = index.Close()
@@ -284,11 +366,11 @@ func (i *indexer) registerChainHelper(
 	}
 
 	// Create an API endpoint for this index
-	apiServer := rpc.NewServer()
+	apiServer := openapi.NewServer()
 	codec := json.NewCodec()
 	apiServer.RegisterCodec(codec, "application/json")
 	apiServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := apiServer.RegisterService(&service{Index: index}, "index"); err != nil {
+	if err := apiServer.RegisterService(&service{Index: index, feeReporter: feeReporter}, "index"); err != nil {
 		_ = index.Close()
 		return nil, err
 	}
@@ -297,6 +379,10 @@ func (i *indexer) registerChainHelper(
 		_ = index.Close()
 		return nil, err
 	}
+
+	if i.pruner != nil {
+		i.pruner.add(name+"/"+endpoint, index)
+	}
 	return index, nil
 }
 
@@ -316,6 +402,9 @@ func (i *indexer) close() error {
 		return nil
 	}
 	i.closed = true
+	if i.pruner != nil {
+		i.pruner.shutdown()
+	}
 
 	errs := &wrappers.Errs{}
 	for chainID, txIndex := range i.txIndices {
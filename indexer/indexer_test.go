@@ -273,7 +273,7 @@ func TestIndexer(t *testing.T) {
 	assert.False(previouslyIndexed)
 	dagVM := &avvtxmocks.DAGVM{}
 	dagEngine := &mocks.Engine{}
-	dagEngine.On("GetVM").Return(dagVM).Once()
+	dagEngine.On("GetVM").Return(dagVM)
 	idxr.RegisterChain("chain2", chain2Ctx, dagEngine)
 	assert.NoError(err)
 	server = config.APIServer.(*apiServerMock)
@@ -454,7 +454,9 @@ func TestIncompleteIndex(t *testing.T) {
 	previouslyIndexed, err := idxr.previouslyIndexed(chain1Ctx.ChainID)
 	assert.NoError(err)
 	assert.False(previouslyIndexed)
+	chainVM := &smblockmocks.ChainVM{}
 	chainEngine := &smengmocks.Engine{}
+	chainEngine.On("GetVM").Return(chainVM)
 	idxr.RegisterChain("chain1", chain1Ctx, chainEngine)
 	isIncomplete, err = idxr.isIncomplete(chain1Ctx.ChainID)
 	assert.NoError(err)
@@ -542,3 +544,53 @@ func TestIgnoreNonDefaultChains(t *testing.T) {
 	idxr.RegisterChain("chain1", chain1Ctx, chainEngine)
 	assert.Len(idxr.blockIndices, 0)
 }
+
+// Ensure only the opted in chains are indexed when [IndexedChains] is set
+func TestIndexedChains(t *testing.T) {
+	assert := assert.New(t)
+	cd := &triggers.EventDispatcher{}
+	cd.Initialize(logging.NoLog{})
+	dd := &triggers.EventDispatcher{}
+	dd.Initialize(logging.NoLog{})
+	subnetChainID := ids.GenerateTestID()
+	config := Config{
+		IndexingEnabled:      true,
+		AllowIncompleteIndex: true,
+		Log:                  logging.NoLog{},
+		DB:                   versiondb.New(memdb.New()),
+		ConsensusDispatcher:  cd,
+		DecisionDispatcher:   dd,
+		APIServer:            &apiServerMock{},
+		ShutdownF:            func() {},
+	}
+	config.IndexedChains.Add(subnetChainID)
+
+	idxrIntf, err := NewIndexer(config)
+	assert.NoError(err)
+	idxr, ok := idxrIntf.(*indexer)
+	assert.True(ok)
+
+	// A primary network chain that wasn't opted in shouldn't be indexed
+	chain1Ctx := snow.DefaultContextTest()
+	chain1Ctx.ChainID = ids.GenerateTestID()
+	chain1Engine := &smengmocks.Engine{}
+	idxr.RegisterChain("chain1", chain1Ctx, chain1Engine)
+	assert.Len(idxr.blockIndices, 0)
+	isIncomplete, err := idxr.isIncomplete(chain1Ctx.ChainID)
+	assert.NoError(err)
+	assert.True(isIncomplete)
+
+	// A chain of another subnet that was opted in should be indexed
+	chain2Ctx := snow.DefaultContextTest()
+	chain2Ctx.ChainID = subnetChainID
+	chain2Ctx.SubnetID = ids.GenerateTestID()
+	chain2VM := &smblockmocks.ChainVM{}
+	chain2Engine := &smengmocks.Engine{}
+	chain2Engine.On("GetVM").Return(chain2VM)
+	idxr.RegisterChain("chain2", chain2Ctx, chain2Engine)
+	assert.False(idxr.closed)
+	assert.Len(idxr.blockIndices, 1)
+	assert.Contains(idxr.blockIndices, subnetChainID)
+
+	assert.NoError(idxr.Close())
+}
@@ -0,0 +1,185 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// Maximum number of containers pruned while an index is locked
+const pruneBatchSize = 1024
+
+// PruningConfig describes which indexed containers are pruned. A container is
+// pruned once neither [RetainHeight] nor [RetainAge] retain it. The ID of a
+// pruned container stays mapped to its index, so the API still reports that
+// it was accepted, but the container itself can't be fetched anymore.
+//
+// Only the indexer's copies of containers, which are kept in the indexer's own
+// database, are pruned. The containers that chains and their VMs keep in their
+// state aren't touched, so pruning doesn't shrink the chains' databases.
+type PruningConfig struct {
+	// How often the indices are pruned. If 0, containers are never pruned.
+	Freq time.Duration
+	// Number of most recently accepted containers of each index that are
+	// retained. If 0, containers aren't retained because of their height.
+	RetainHeight uint64
+	// Containers accepted less than this long ago are retained. If 0,
+	// containers aren't retained because of their age.
+	RetainAge time.Duration
+}
+
+// Enabled returns true if containers are pruned
+func (c *PruningConfig) Enabled() bool { return c.Freq > 0 }
+
+// pruner prunes the containers of indices while they keep indexing accepted
+// containers. Indices are only locked while a batch of containers is pruned.
+// Chain state isn't pruned.
+type pruner struct {
+	log    logging.Logger
+	config PruningConfig
+	clock  timer.Clock
+
+	metrics pruningMetrics
+
+	lock sync.Mutex
+	// Name of each index --> the index
+	indices map[string]*index
+
+	// Dispatch returns when closer is closed
+	closer chan struct{}
+}
+
+func newPruner(
+	log logging.Logger,
+	config PruningConfig,
+	namespace string,
+	registerer prometheus.Registerer,
+) (*pruner, error) {
+	p := &pruner{
+		log:     log,
+		config:  config,
+		indices: make(map[string]*index),
+		closer:  make(chan struct{}),
+	}
+	return p, p.metrics.initialize(namespace, registerer)
+}
+
+// add [idx], named [name], to the indices that are pruned
+func (p *pruner) add(name string, idx Index) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	prunedIndex := idx.(*index)
+	p.indices[name] = prunedIndex
+	p.metrics.retained.WithLabelValues(name).Set(float64(prunedIndex.numRetained()))
+}
+
+// Dispatch prunes the indices every [Freq] until shutdown is called
+func (p *pruner) Dispatch() {
+	t := time.NewTicker(p.config.Freq)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.closer:
+			return
+		case <-t.C:
+			p.pruneAll()
+		}
+	}
+}
+
+// shutdown stops the pruning. The batch being pruned is finished.
+func (p *pruner) shutdown() { close(p.closer) }
+
+// pruneAll prunes each index of every container that isn't retained
+func (p *pruner) pruneAll() {
+	p.lock.Lock()
+	indices := make(map[string]*index, len(p.indices))
+	for name, index := range p.indices {
+		indices[name] = index
+	}
+	p.lock.Unlock()
+
+	start := p.clock.Time()
+	var retainTime int64
+	if p.config.RetainAge > 0 {
+		retainTime = start.Add(-p.config.RetainAge).UnixNano()
+	}
+	for name, index := range indices {
+		if err := p.prune(name, index, retainTime); err != nil {
+			p.log.Error("couldn't prune index %s: %s", name, err)
+		}
+	}
+	p.metrics.duration.Set(p.clock.Time().Sub(start).Seconds())
+}
+
+// prune [index], named [name], in batches until it's done or the pruner is
+// shut down
+func (p *pruner) prune(name string, index *index, retainTime int64) error {
+	pruned := p.metrics.pruned.WithLabelValues(name)
+	retained := p.metrics.retained.WithLabelValues(name)
+	totalPruned := 0
+	for {
+		select {
+		case <-p.closer:
+			return nil
+		default:
+		}
+
+		numPruned, more, err := index.prune(p.config.RetainHeight, retainTime, pruneBatchSize)
+		totalPruned += numPruned
+		pruned.Add(float64(numPruned))
+		retained.Set(float64(index.numRetained()))
+		if err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+	}
+	if totalPruned > 0 {
+		p.log.Info("pruned %d containers of index %s", totalPruned, name)
+	}
+	return nil
+}
+
+type pruningMetrics struct {
+	pruned   *prometheus.CounterVec
+	retained *prometheus.GaugeVec
+	duration prometheus.Gauge
+}
+
+func (m *pruningMetrics) initialize(namespace string, registerer prometheus.Registerer) error {
+	m.pruned = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pruned_containers",
+		Help:      "Number of containers of the index that were pruned",
+	}, []string{"index"})
+	m.retained = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "retained_containers",
+		Help:      "Number of containers of the index that haven't been pruned",
+	}, []string{"index"})
+	m.duration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_pruning_duration",
+		Help:      "Time the last pruning of the indices took, in seconds",
+	})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.pruned),
+		registerer.Register(m.retained),
+		registerer.Register(m.duration),
+	)
+	return errs.Err
+}
@@ -0,0 +1,52 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+func TestPrunerPruneAll(t *testing.T) {
+	assert := assert.New(t)
+	codec := codec.NewDefaultManager()
+	err := codec.RegisterCodec(codecVersion, linearcodec.NewDefault())
+	assert.NoError(err)
+	ctx := snow.DefaultContextTest()
+	idx, err := newIndex(memdb.New(), logging.NoLog{}, codec, timer.Clock{})
+	assert.NoError(err)
+	for i := 0; i < 2*pruneBatchSize; i++ {
+		assert.NoError(idx.Accept(ctx, ids.GenerateTestID(), utils.RandomBytes(32)))
+	}
+
+	config := PruningConfig{Freq: 1, RetainHeight: 10}
+	p, err := newPruner(logging.NoLog{}, config, "", prometheus.NewRegistry())
+	assert.NoError(err)
+	p.add("test", idx)
+	assert.EqualValues(2*pruneBatchSize, testutil.ToFloat64(p.metrics.retained.WithLabelValues("test")))
+
+	// Every batch is pruned in one pass
+	p.pruneAll()
+	assert.EqualValues(2*pruneBatchSize-10, testutil.ToFloat64(p.metrics.pruned.WithLabelValues("test")))
+	assert.EqualValues(10, testutil.ToFloat64(p.metrics.retained.WithLabelValues("test")))
+
+	// Nothing is pruned once the pruner is shut down
+	assert.NoError(idx.Accept(ctx, ids.GenerateTestID(), utils.RandomBytes(32)))
+	p.shutdown()
+	p.pruneAll()
+	assert.EqualValues(2*pruneBatchSize-10, testutil.ToFloat64(p.metrics.pruned.WithLabelValues("test")))
+	assert.EqualValues(11, idx.(*index).numRetained())
+}
@@ -1,6 +1,7 @@
 package indexer
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -11,8 +12,21 @@ import (
 	"github.com/ava-labs/avalanchego/utils/json"
 )
 
+var errFeesNotReported = errors.New("the fees of these containers aren't reported")
+
+const (
+	// Default number of recently accepted containers that fees are estimated
+	// from
+	defaultFeeEstimateContainers = 100
+	// Default number of seconds that fees are estimated to be accepted within
+	defaultFeeEstimateTargetLatency = 10
+)
+
 type service struct {
 	Index
+	// Reports the fees paid by the containers in [Index]. Nil if the VM
+	// doesn't report the fees of these containers.
+	feeReporter FeeReporter
 }
 
 type FormattedContainer struct {
@@ -152,3 +166,57 @@ func (s *service) GetContainerByID(r *http.Request, args *GetIndexArgs, reply *F
 	*reply, err = newFormattedContainer(container, index, args.Encoding)
 	return err
 }
+
+type EstimateFeeArgs struct {
+	// Number of seconds that the container should be accepted within.
+	// Defaults to 10 seconds.
+	TargetLatency json.Uint64 `json:"targetLatency"`
+	// Number of recently accepted containers to estimate the fee from.
+	// Defaults to 100 and can be at most [MaxFetchedByRange].
+	NumContainers json.Uint64 `json:"numContainers"`
+}
+
+type EstimateFeeResponse struct {
+	// Suggested fee
+	Fee json.Uint64 `json:"fee"`
+	// Smallest fee that a container must pay to be accepted
+	MinFee json.Uint64 `json:"minFee"`
+	// Percentile of the recently paid fees that [Fee] was chosen at
+	Percentile json.Float64 `json:"percentile"`
+	// Number of recently accepted containers whose fees were sampled
+	NumContainers json.Uint64 `json:"numContainers"`
+	// Number of containers accepted per second while they were sampled
+	AcceptanceRate json.Float64 `json:"acceptanceRate"`
+}
+
+// EstimateFee suggests the fee that a container should pay to be accepted
+// within the target latency, based on the fees paid by the most recently
+// accepted containers and the rate that they were accepted at
+func (s *service) EstimateFee(r *http.Request, args *EstimateFeeArgs, reply *EstimateFeeResponse) error {
+	if s.feeReporter == nil {
+		return errFeesNotReported
+	}
+
+	numContainers := uint64(args.NumContainers)
+	switch {
+	case numContainers == 0:
+		numContainers = defaultFeeEstimateContainers
+	case numContainers > MaxFetchedByRange:
+		return fmt.Errorf("numContainers must be at most %d", MaxFetchedByRange)
+	}
+	targetLatency := uint64(args.TargetLatency)
+	if targetLatency == 0 {
+		targetLatency = defaultFeeEstimateTargetLatency
+	}
+
+	estimate, err := estimateFee(s.Index, s.feeReporter, numContainers, time.Duration(targetLatency)*time.Second)
+	if err != nil {
+		return err
+	}
+	reply.Fee = json.Uint64(estimate.Fee)
+	reply.MinFee = json.Uint64(estimate.MinFee)
+	reply.Percentile = json.Float64(estimate.Percentile)
+	reply.NumContainers = json.Uint64(estimate.NumContainers)
+	reply.AcceptanceRate = json.Float64(estimate.AcceptanceRate)
+	return nil
+}
@@ -0,0 +1,129 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/verify"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+var _ verify.Verifier = &index{}
+
+// VerifyDB implements the verify.Verifier interface. Each retained container
+// must be stored, and its ID must map to its index. A container is indexed
+// atomically with the next accepted index, so containers and IDs that are
+// indexed at or after the next accepted index are dangling and are deleted.
+// So are the containers that should have been pruned. A missing or
+// unparsable container can't be repaired.
+func (i *index) VerifyDB(repair bool, report *verify.Report) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	// Drops the repairs unless they are all made
+	defer i.vDB.Abort()
+
+	// The changes are only made once the iterations are done
+	var (
+		containersToDelete [][]byte
+		idsToDelete        [][]byte
+		toMap              = make(map[ids.ID]uint64)
+	)
+
+	nextIndex := i.firstRetainedIndex
+	iter := i.indexToContainer.NewIterator()
+	for iter.Next() {
+		report.NumChecked++
+		indexBytes := utils.CopyBytes(iter.Key())
+		index, err := database.ParseUInt64(indexBytes)
+		switch {
+		case err != nil:
+			containersToDelete = append(containersToDelete, indexBytes)
+			report.Add(hex.EncodeToString(indexBytes), repair, "container has a malformed index")
+			continue
+		case index < i.firstRetainedIndex:
+			containersToDelete = append(containersToDelete, indexBytes)
+			report.Add(fmt.Sprintf("index %d", index), repair, "container wasn't pruned")
+			continue
+		case index >= i.nextAcceptedIndex:
+			containersToDelete = append(containersToDelete, indexBytes)
+			report.Add(fmt.Sprintf("index %d", index), repair, "container is indexed after the last accepted container")
+			continue
+		case index > nextIndex:
+			report.Add(fmt.Sprintf("indices %d to %d", nextIndex, index-1), false, "containers are missing")
+		}
+		nextIndex = index + 1
+
+		var container Container
+		if _, err := i.codec.Unmarshal(iter.Value(), &container); err != nil {
+			report.Add(fmt.Sprintf("index %d", index), false, "container can't be parsed: %s", err)
+			continue
+		}
+		mappedIndex, err := database.GetUInt64(i.containerToIndex, container.ID[:])
+		switch {
+		case err == database.ErrNotFound:
+			report.Add(container.ID.String(), repair, "container ID isn't mapped to index %d", index)
+		case err != nil:
+			iter.Release()
+			return fmt.Errorf("couldn't get index of container %s: %w", container.ID, err)
+		case mappedIndex != index:
+			report.Add(container.ID.String(), repair, "container ID is mapped to index %d instead of %d", mappedIndex, index)
+		default:
+			continue
+		}
+		toMap[container.ID] = index
+	}
+	err := iter.Error()
+	iter.Release()
+	if err != nil {
+		return fmt.Errorf("couldn't iterate over containers: %w", err)
+	}
+	if nextIndex < i.nextAcceptedIndex {
+		report.Add(fmt.Sprintf("indices %d to %d", nextIndex, i.nextAcceptedIndex-1), false, "containers are missing")
+	}
+
+	iter = i.containerToIndex.NewIterator()
+	for iter.Next() {
+		report.NumChecked++
+		idBytes := utils.CopyBytes(iter.Key())
+		index, err := database.ParseUInt64(iter.Value())
+		if err == nil && index < i.nextAcceptedIndex {
+			continue
+		}
+		idsToDelete = append(idsToDelete, idBytes)
+		if err != nil {
+			report.Add(hex.EncodeToString(idBytes), repair, "container ID is mapped to a malformed index")
+		} else {
+			report.Add(hex.EncodeToString(idBytes), repair, "container ID is mapped to index %d after the last accepted container", index)
+		}
+	}
+	err = iter.Error()
+	iter.Release()
+	if err != nil {
+		return fmt.Errorf("couldn't iterate over container IDs: %w", err)
+	}
+
+	if !repair {
+		return nil
+	}
+	for _, indexBytes := range containersToDelete {
+		if err := i.indexToContainer.Delete(indexBytes); err != nil {
+			return err
+		}
+	}
+	for _, idBytes := range idsToDelete {
+		if err := i.containerToIndex.Delete(idBytes); err != nil {
+			return err
+		}
+	}
+	for containerID, index := range toMap {
+		if err := database.PutUInt64(i.containerToIndex, containerID[:], index); err != nil {
+			return err
+		}
+	}
+	return i.vDB.Commit()
+}
@@ -0,0 +1,56 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/vms/scaffold"
+)
+
+// Name of the command that generates the module of a new VM instead of
+// running the node. It's given as the first argument, followed by the usual
+// flags.
+const createVMCommand = "create-vm"
+
+// runCreateVM generates the module of the configured VM and prints how to
+// build it. Returns the exit code of the process.
+func runCreateVM(args []string) int {
+	fs := config.BuildFlagSet()
+	v, err := config.BuildViper(fs, args)
+	if err != nil {
+		fmt.Printf("couldn't configure flags: %s\n", err)
+		return 1
+	}
+
+	vmConfig, err := config.GetCreateVMConfig(v)
+	if err != nil {
+		fmt.Printf("couldn't load VM config: %s\n", err)
+		return 1
+	}
+
+	paths, err := scaffold.Generate(vmConfig)
+	if err != nil {
+		fmt.Printf("couldn't generate VM: %s\n", err)
+		return 1
+	}
+	fmt.Printf("generated %s VM %s in %s:\n", vmConfig.Type, vmConfig.Name, vmConfig.Dir)
+	for _, path := range paths {
+		fmt.Printf("  %s\n", filepath.FromSlash(path))
+	}
+
+	fmt.Println("\nnext steps:")
+	fmt.Printf("  cd %s\n", vmConfig.Dir)
+	fmt.Println("  go mod tidy")
+	fmt.Println("  go test ./...")
+	switch vmConfig.Type {
+	case scaffold.SnowmanVM:
+		fmt.Printf("  go build -o <plugin directory>/%s ./main\n", vmConfig.VMID())
+	case scaffold.DAGVM:
+		fmt.Println("rpcchainvm only serves snowman VMs, so a DAG VM is registered in the node rather than built as a plugin")
+	}
+	return 0
+}
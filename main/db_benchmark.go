@@ -0,0 +1,50 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/database/benchmark"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// Name of the command that benchmarks the database instead of running the
+// node. It's given as the first argument, followed by the usual flags.
+const dbBenchmarkCommand = "db-benchmark"
+
+// runDBBenchmark benchmarks the configured database backend, in the
+// configured database directory, and prints the results. Returns the exit
+// code of the process.
+func runDBBenchmark(args []string) int {
+	fs := config.BuildFlagSet()
+	v, err := config.BuildViper(fs, args)
+	if err != nil {
+		fmt.Printf("couldn't configure flags: %s\n", err)
+		return 1
+	}
+
+	benchmarkConfig, err := config.GetDBBenchmarkConfig(v)
+	if err != nil {
+		fmt.Printf("couldn't load benchmark config: %s\n", err)
+		return 1
+	}
+
+	fmt.Printf("benchmarking %s with %d keys of %d byte values in %s\n",
+		benchmarkConfig.Backend,
+		benchmarkConfig.NumKeys,
+		benchmarkConfig.ValueSize,
+		benchmarkConfig.Dir,
+	)
+	results, err := benchmark.Run(benchmarkConfig, logging.NoLog{})
+	if err != nil {
+		fmt.Printf("benchmark failed: %s\n", err)
+		return 1
+	}
+	for _, result := range results {
+		fmt.Println(result)
+	}
+	return 0
+}
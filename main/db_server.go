@@ -0,0 +1,81 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/rpcdb"
+	"github.com/ava-labs/avalanchego/database/rpcdb/rpcdbproto"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// Name of the command that serves a database to nodes whose database backend
+// is manager.RemoteDB, instead of running the node. It's given as the first
+// argument, followed by the usual flags.
+const dbServerCommand = "db-server"
+
+// runDBServer serves the configured database backend, in the configured
+// database directory, until the process is interrupted. Returns the exit code
+// of the process.
+func runDBServer(args []string) int {
+	fs := config.BuildFlagSet()
+	v, err := config.BuildViper(fs, args)
+	if err != nil {
+		fmt.Printf("couldn't configure flags: %s\n", err)
+		return 1
+	}
+
+	serverConfig, err := config.GetDBServerConfig(v)
+	if err != nil {
+		fmt.Printf("couldn't load database server config: %s\n", err)
+		return 1
+	}
+
+	backend, err := manager.GetBackend(serverConfig.Backend)
+	if err != nil {
+		fmt.Printf("couldn't get database backend: %s\n", err)
+		return 1
+	}
+	db, err := backend(serverConfig.Dir, "", false, logging.NoLog{})
+	if err != nil {
+		fmt.Printf("couldn't open %s database in %s: %s\n", serverConfig.Backend, serverConfig.Dir, err)
+		return 1
+	}
+	defer db.Close()
+
+	listener, err := net.Listen("tcp", serverConfig.Address)
+	if err != nil {
+		fmt.Printf("couldn't listen on %s: %s\n", serverConfig.Address, err)
+		return 1
+	}
+
+	server := grpc.NewServer(serverConfig.Security.ServerOptions()...)
+	rpcdbproto.RegisterDatabaseServer(server, rpcdb.NewServer(db))
+	_ = utils.HandleSignals(
+		func(os.Signal) {
+			server.GracefulStop()
+		},
+		syscall.SIGINT, syscall.SIGTERM,
+	)
+
+	fmt.Printf("serving %s database in %s on %s\n",
+		serverConfig.Backend,
+		serverConfig.Dir,
+		listener.Addr(),
+	)
+	if err := server.Serve(listener); err != nil {
+		fmt.Printf("database server failed: %s\n", err)
+		return 1
+	}
+	return 0
+}
@@ -17,6 +17,16 @@ import (
 
 // main is the entry point to AvalancheGo.
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == dbBenchmarkCommand {
+		os.Exit(runDBBenchmark(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == dbServerCommand {
+		os.Exit(runDBServer(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == createVMCommand {
+		os.Exit(runCreateVM(os.Args[2:]))
+	}
+
 	fs := config.BuildFlagSet()
 	v, err := config.BuildViper(fs, os.Args[1:])
 	if err != nil {
@@ -64,6 +74,13 @@ func main() {
 		},
 		syscall.SIGINT, syscall.SIGTERM,
 	)
+	_ = utils.HandleSignals(
+		func(os.Signal) {
+			// SIGHUP causes all running nodes to reload their config
+			nodeManager.reload()
+		},
+		syscall.SIGHUP,
+	)
 
 	// Migrate the database if necessary
 	migrationManager := newMigrationManager(nodeManager, nodeConfig, log)
@@ -92,7 +92,7 @@ func (m *migrationManager) shouldMigrate() (bool, error) {
 	if !m.rootConfig.DBEnabled {
 		return false, nil
 	}
-	dbManager, err := manager.New(m.rootConfig.DBPath, logging.NoLog{}, version.CurrentDatabase, true)
+	dbManager, err := manager.NewWithBackend(m.rootConfig.DBPath, m.rootConfig.DBWALPath, m.rootConfig.DBBackend, logging.NoLog{}, version.CurrentDatabase, true)
 	if err != nil {
 		return false, fmt.Errorf("couldn't create db manager at %s: %w", m.rootConfig.DBPath, err)
 	}
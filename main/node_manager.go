@@ -6,12 +6,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sync"
+	"syscall"
 
 	appplugin "github.com/ava-labs/avalanchego/app/plugin"
 	"github.com/ava-labs/avalanchego/config"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/node"
-	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/hashicorp/go-hclog"
@@ -33,6 +34,8 @@ type nodeProcess struct {
 	// on each nodeProcess
 	rawClient *plugin.Client
 	node      *appplugin.Client
+	// The node's process
+	cmd *exec.Cmd
 }
 
 // Returns a channel that the node's exit code is sent on when the node is done.
@@ -100,6 +103,22 @@ func (nm *nodeManager) shutdown() {
 	}
 }
 
+// Forward SIGHUP to every running node, so that they reload their config
+func (nm *nodeManager) reload() {
+	nm.lock.Lock()
+	defer nm.lock.Unlock()
+
+	for _, node := range nm.nodes {
+		if node.cmd.Process == nil {
+			continue
+		}
+		nm.log.Info("reloading config of node at path '%s'", node.path)
+		if err := node.cmd.Process.Signal(syscall.SIGHUP); err != nil {
+			nm.log.Error("error reloading config of node: %s", err)
+		}
+	}
+}
+
 // Stop a node. Blocks until the node is done shutting down.
 // Assumes [nm.lock] is not held
 func (nm *nodeManager) Stop(path string) error {
@@ -135,10 +154,11 @@ func newNodeManager(path string, log logging.Logger) *nodeManager {
 // Assumes [nm.lock] is held
 func (nm *nodeManager) newNode(path string, args []string, printToStdOut bool) (*nodeProcess, error) {
 	nm.log.Debug("creating new node from binary at '%s'", path)
+	cmd := exec.Command(path, args...)
 	clientConfig := &plugin.ClientConfig{
 		HandshakeConfig:  appplugin.Handshake,
 		Plugins:          appplugin.PluginMap,
-		Cmd:              exec.Command(path, args...),
+		Cmd:              cmd,
 		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
 		Logger:           hclog.New(&hclog.LoggerOptions{Level: hclog.Error}),
 	}
@@ -171,6 +191,7 @@ func (nm *nodeManager) newNode(path string, args []string, printToStdOut bool) (
 		node:      node,
 		rawClient: client,
 		path:      path,
+		cmd:       cmd,
 	}
 	nm.nodes[np.path] = np
 	return np, nil
@@ -205,7 +226,7 @@ func (nm *nodeManager) latestVersionNodeFetchOnly(rootConfig node.Config) (*node
 		args,
 		// Tell this node to run in fetch only mode and to bootstrap only from the local node
 		fmt.Sprintf("--%s=127.0.0.1:%d", config.BootstrapIPsKey, int(rootConfig.StakingIP.Port)),
-		fmt.Sprintf("--%s=%s%s", config.BootstrapIDsKey, constants.NodeIDPrefix, nodeID),
+		fmt.Sprintf("--%s=%s", config.BootstrapIDsKey, address.FormatNodeID(nodeID)),
 		fmt.Sprintf("--%s=%s", config.FetchOnlyKey, "true"),
 		fmt.Sprintf("--%s=%d", config.StakingPortKey, 0), // use any available port for staking port
 		fmt.Sprintf("--%s=%d", config.HTTPPortKey, 0),    // use any available port for HTTP port
@@ -0,0 +1,117 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ASNTable maps IP prefixes to the autonomous system that announces them, so
+// that validators hosted by the same network operator can be grouped together
+// even if their addresses don't share a prefix.
+type ASNTable struct {
+	ipv4 asnPrefixes
+	ipv6 asnPrefixes
+}
+
+type asnPrefixes struct {
+	// Prefix lengths that have prefixes, from longest to shortest
+	lengths []int
+	// Prefix length --> Masked prefix --> ASN
+	prefixes map[int]map[string]uint32
+}
+
+// LoadASNTable reads the ASN table in the file at [path]. See ParseASNTable
+// for its format.
+func LoadASNTable(path string) (*ASNTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseASNTable(f)
+}
+
+// ParseASNTable reads an ASN table from [r]. Each line holds a prefix in CIDR
+// notation and the number of the autonomous system that announces it, with or
+// without an "AS" prefix, separated by whitespace. Empty lines and lines
+// starting with '#' are ignored. If a prefix is listed more than once, the
+// last line is used.
+func ParseASNTable(r io.Reader) (*ASNTable, error) {
+	t := &ASNTable{}
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected a prefix and an ASN but got %q", lineNum, line)
+		}
+		_, prefix, err := net.ParseCIDR(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		asn, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(fields[1]), "AS"), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid ASN %q: %w", lineNum, fields[1], err)
+		}
+
+		length, _ := prefix.Mask.Size()
+		if ip4 := prefix.IP.To4(); ip4 != nil {
+			t.ipv4.add(ip4, length, uint32(asn))
+		} else {
+			t.ipv6.add(prefix.IP, length, uint32(asn))
+		}
+	}
+	return t, scanner.Err()
+}
+
+// Lookup returns the ASN of the longest prefix that contains [ip], and false
+// if no prefix does. Returns false if [t] is nil.
+func (t *ASNTable) Lookup(ip net.IP) (uint32, bool) {
+	if t == nil {
+		return 0, false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return t.ipv4.lookup(ip4)
+	}
+	if len(ip) != net.IPv6len {
+		return 0, false
+	}
+	return t.ipv6.lookup(ip)
+}
+
+func (p *asnPrefixes) add(ip net.IP, length int, asn uint32) {
+	if p.prefixes == nil {
+		p.prefixes = make(map[int]map[string]uint32)
+	}
+	prefixes, ok := p.prefixes[length]
+	if !ok {
+		prefixes = make(map[string]uint32)
+		p.prefixes[length] = prefixes
+		p.lengths = append(p.lengths, length)
+		sort.Sort(sort.Reverse(sort.IntSlice(p.lengths)))
+	}
+	prefixes[string(ip.Mask(net.CIDRMask(length, 8*len(ip))))] = asn
+}
+
+func (p *asnPrefixes) lookup(ip net.IP) (uint32, bool) {
+	for _, length := range p.lengths {
+		masked := ip.Mask(net.CIDRMask(length, 8*len(ip)))
+		if asn, ok := p.prefixes[length][string(masked)]; ok {
+			return asn, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,58 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestASNTableLookup(t *testing.T) {
+	table, err := ParseASNTable(strings.NewReader(`
+# prefix asn
+1.0.0.0/8	100
+1.2.0.0/16	AS200
+1.2.3.0/24	as300
+2001:db8::/32	400
+2001:db8:1::/48	500
+`))
+	assert.NoError(t, err)
+
+	for ip, expectedASN := range map[string]uint32{
+		"1.2.3.4":       300,
+		"1.2.4.4":       200,
+		"1.3.0.1":       100,
+		"2001:db8:1::1": 500,
+		"2001:db8:2::1": 400,
+	} {
+		asn, ok := table.Lookup(net.ParseIP(ip))
+		assert.True(t, ok, ip)
+		assert.Equal(t, expectedASN, asn, ip)
+	}
+
+	_, ok := table.Lookup(net.ParseIP("2.0.0.1"))
+	assert.False(t, ok)
+	_, ok = table.Lookup(nil)
+	assert.False(t, ok)
+
+	var noTable *ASNTable
+	_, ok = noTable.Lookup(net.ParseIP("1.2.3.4"))
+	assert.False(t, ok)
+}
+
+func TestParseASNTableInvalid(t *testing.T) {
+	for _, table := range []string{
+		"1.2.3.0/24",
+		"1.2.3.0/24 100 200",
+		"1.2.3.0 100",
+		"1.2.3.0/24 ASX",
+		"1.2.3.0/24 4294967296",
+	} {
+		_, err := ParseASNTable(strings.NewReader(table))
+		assert.Error(t, err, table)
+	}
+}
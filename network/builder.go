@@ -48,6 +48,12 @@ func (m Builder) PeerList(peers []utils.IPCertDesc) (Msg, error) {
 	return m.Pack(buf, PeerList, map[Field]interface{}{SignedPeers: peers})
 }
 
+// Capabilities message
+func (m Builder) Capabilities(capabilities Capability) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, Capabilities, map[Field]interface{}{CapabilityFlags: uint64(capabilities)})
+}
+
 // Ping message
 func (m Builder) Ping() (Msg, error) {
 	buf := m.getByteSlice()
@@ -159,6 +165,129 @@ func (m Builder) Put(chainID ids.ID, requestID uint32, containerID ids.ID, conta
 	})
 }
 
+// ChunkedPut message
+func (m Builder) ChunkedPut(chainID ids.ID, requestID uint32, deadline uint64, containerID ids.ID, op Op, chunkHashes []ids.ID) (Msg, error) {
+	chunkHashBytes := make([][]byte, len(chunkHashes))
+	for i, chunkHash := range chunkHashes {
+		copy := chunkHash
+		chunkHashBytes[i] = copy[:]
+	}
+	buf := m.getByteSlice()
+	return m.Pack(buf, ChunkedPut, map[Field]interface{}{
+		ChainID:     chainID[:],
+		RequestID:   requestID,
+		Deadline:    deadline,
+		ContainerID: containerID[:],
+		ChunkedOp:   uint8(op),
+		ChunkHashes: chunkHashBytes,
+	})
+}
+
+// PutChunk message
+func (m Builder) PutChunk(chainID ids.ID, requestID uint32, containerID ids.ID, chunkIndex uint32, chunk []byte) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, PutChunk, map[Field]interface{}{
+		ChainID:        chainID[:],
+		RequestID:      requestID,
+		ContainerID:    containerID[:],
+		ChunkIndex:     chunkIndex,
+		ContainerBytes: chunk,
+	})
+}
+
+// CrossSubnet message
+func (m Builder) CrossSubnet(sourceChainID, destinationChainID ids.ID, originID ids.ShortID, msg, signature []byte) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, CrossSubnet, map[Field]interface{}{
+		ChainID:            sourceChainID[:],
+		DestinationChainID: destinationChainID[:],
+		OriginID:           originID[:],
+		ContainerBytes:     msg,
+		BLSSignature:       signature,
+	})
+}
+
+// GetValidatorSnapshot message
+func (m Builder) GetValidatorSnapshot(subnetID ids.ID) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, GetValidatorSnapshot, map[Field]interface{}{
+		SubnetID: subnetID[:],
+	})
+}
+
+// ValidatorSnapshot message
+func (m Builder) ValidatorSnapshot(snapshot, publicKey, proofOfPossession, keySignature, signature []byte) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, ValidatorSnapshot, map[Field]interface{}{
+		ContainerBytes:       snapshot,
+		BLSPublicKey:         publicKey,
+		BLSProofOfPossession: proofOfPossession,
+		BLSKeySignature:      keySignature,
+		BLSSignature:         signature,
+	})
+}
+
+// GetStateSummaryFrontier message
+func (m Builder) GetStateSummaryFrontier(chainID ids.ID, requestID uint32, deadline uint64) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, GetStateSummaryFrontier, map[Field]interface{}{
+		ChainID:   chainID[:],
+		RequestID: requestID,
+		Deadline:  deadline,
+	})
+}
+
+// StateSummaryFrontier message
+func (m Builder) StateSummaryFrontier(chainID ids.ID, requestID uint32, summary []byte) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, StateSummaryFrontier, map[Field]interface{}{
+		ChainID:        chainID[:],
+		RequestID:      requestID,
+		ContainerBytes: summary,
+	})
+}
+
+// GetAcceptedStateSummary message
+func (m Builder) GetAcceptedStateSummary(chainID ids.ID, requestID uint32, deadline uint64, heights []uint64) (Msg, error) {
+	buf := m.getByteSlice()
+	return m.Pack(buf, GetAcceptedStateSummary, map[Field]interface{}{
+		ChainID:        chainID[:],
+		RequestID:      requestID,
+		Deadline:       deadline,
+		SummaryHeights: heights,
+	})
+}
+
+// AcceptedStateSummary message
+func (m Builder) AcceptedStateSummary(chainID ids.ID, requestID uint32, summaryIDs []ids.ID) (Msg, error) {
+	summaryIDBytes := make([][]byte, len(summaryIDs))
+	for i, summaryID := range summaryIDs {
+		copy := summaryID
+		summaryIDBytes[i] = copy[:]
+	}
+	buf := m.getByteSlice()
+	return m.Pack(buf, AcceptedStateSummary, map[Field]interface{}{
+		ChainID:      chainID[:],
+		RequestID:    requestID,
+		ContainerIDs: summaryIDBytes,
+	})
+}
+
+// UptimeReport message
+func (m Builder) UptimeReport(bucketStart uint64, validatorIDs []ids.NodeID, upDurations []uint64) (Msg, error) {
+	validatorIDBytes := make([][]byte, len(validatorIDs))
+	for i, validatorID := range validatorIDs {
+		copy := validatorID
+		validatorIDBytes[i] = copy[:]
+	}
+	buf := m.getByteSlice()
+	return m.Pack(buf, UptimeReport, map[Field]interface{}{
+		BucketStart:  bucketStart,
+		ValidatorIDs: validatorIDBytes,
+		UpDurations:  upDurations,
+	})
+}
+
 // PushQuery message
 func (m Builder) PushQuery(chainID ids.ID, requestID uint32, deadline uint64, containerID ids.ID, container []byte) (Msg, error) {
 	buf := m.getByteSlice()
@@ -39,6 +39,22 @@ func TestBuildGetPeerList(t *testing.T) {
 	assert.Equal(t, GetPeerList, parsedMsg.Op())
 }
 
+func TestBuildCapabilities(t *testing.T) {
+	capabilities := ChunkedTransferCapability | StateSyncCapability
+
+	msg, err := TestBuilder.Capabilities(capabilities)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, Capabilities, msg.Op())
+	assert.Equal(t, uint64(capabilities), msg.Get(CapabilityFlags))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, Capabilities, parsedMsg.Op())
+	assert.Equal(t, uint64(capabilities), parsedMsg.Get(CapabilityFlags))
+}
+
 func TestBuildGetAcceptedFrontier(t *testing.T) {
 	chainID := ids.Empty.Prefix(0)
 	requestID := uint32(5)
@@ -133,6 +149,65 @@ func TestBuildAccepted(t *testing.T) {
 	assert.Equal(t, containerIDs, parsedMsg.Get(ContainerIDs))
 }
 
+func TestBuildStateSummaryFrontier(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	summary := []byte{1, 2, 3}
+
+	msg, err := TestBuilder.StateSummaryFrontier(chainID, requestID, summary)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, StateSummaryFrontier, msg.Op())
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, StateSummaryFrontier, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, summary, parsedMsg.Get(ContainerBytes))
+}
+
+func TestBuildGetAcceptedStateSummary(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	deadline := uint64(15)
+	heights := []uint64{100, 200}
+
+	msg, err := TestBuilder.GetAcceptedStateSummary(chainID, requestID, deadline, heights)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, GetAcceptedStateSummary, msg.Op())
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, GetAcceptedStateSummary, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, deadline, parsedMsg.Get(Deadline))
+	assert.Equal(t, heights, parsedMsg.Get(SummaryHeights))
+}
+
+func TestBuildUptimeReport(t *testing.T) {
+	bucketStart := uint64(3600)
+	validatorID := ids.GenerateTestNodeID()
+	upDurations := []uint64{60}
+
+	msg, err := TestBuilder.UptimeReport(bucketStart, []ids.NodeID{validatorID}, upDurations)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, UptimeReport, msg.Op())
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, UptimeReport, parsedMsg.Op())
+	assert.Equal(t, bucketStart, parsedMsg.Get(BucketStart))
+	assert.Equal(t, [][]byte{validatorID[:]}, parsedMsg.Get(ValidatorIDs))
+	assert.Equal(t, upDurations, parsedMsg.Get(UpDurations))
+}
+
 func TestBuildGet(t *testing.T) {
 	chainID := ids.Empty.Prefix(0)
 	requestID := uint32(5)
@@ -183,6 +258,65 @@ func TestBuildPut(t *testing.T) {
 	assert.Equal(t, container, parsedMsg.Get(ContainerBytes))
 }
 
+func TestBuildChunkedPut(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	deadline := uint64(15)
+	containerID := ids.Empty.Prefix(1)
+	chunkHashes := []ids.ID{ids.Empty.Prefix(2), ids.Empty.Prefix(3)}
+	chunkHashBytes := [][]byte{chunkHashes[0][:], chunkHashes[1][:]}
+
+	msg, err := TestBuilder.ChunkedPut(chainID, requestID, deadline, containerID, PushQuery, chunkHashes)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, ChunkedPut, msg.Op())
+	assert.Equal(t, chainID[:], msg.Get(ChainID))
+	assert.Equal(t, requestID, msg.Get(RequestID))
+	assert.Equal(t, deadline, msg.Get(Deadline))
+	assert.Equal(t, containerID[:], msg.Get(ContainerID))
+	assert.Equal(t, uint8(PushQuery), msg.Get(ChunkedOp))
+	assert.Equal(t, chunkHashBytes, msg.Get(ChunkHashes))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, ChunkedPut, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, deadline, parsedMsg.Get(Deadline))
+	assert.Equal(t, containerID[:], parsedMsg.Get(ContainerID))
+	assert.Equal(t, uint8(PushQuery), parsedMsg.Get(ChunkedOp))
+	assert.Equal(t, chunkHashBytes, parsedMsg.Get(ChunkHashes))
+}
+
+func TestBuildPutChunk(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	containerID := ids.Empty.Prefix(1)
+	chunkIndex := uint32(3)
+	chunk := []byte{2}
+
+	msg, err := TestBuilder.PutChunk(chainID, requestID, containerID, chunkIndex, chunk)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, PutChunk, msg.Op())
+	assert.Equal(t, chainID[:], msg.Get(ChainID))
+	assert.Equal(t, requestID, msg.Get(RequestID))
+	assert.Equal(t, containerID[:], msg.Get(ContainerID))
+	assert.Equal(t, chunkIndex, msg.Get(ChunkIndex))
+	assert.Equal(t, chunk, msg.Get(ContainerBytes))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, PutChunk, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, containerID[:], parsedMsg.Get(ContainerID))
+	assert.Equal(t, chunkIndex, parsedMsg.Get(ChunkIndex))
+	assert.Equal(t, chunk, parsedMsg.Get(ContainerBytes))
+}
+
 func TestBuildPushQuery(t *testing.T) {
 	chainID := ids.Empty.Prefix(0)
 	requestID := uint32(5)
@@ -258,3 +392,67 @@ func TestBuildChits(t *testing.T) {
 	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
 	assert.Equal(t, containerIDs, parsedMsg.Get(ContainerIDs))
 }
+
+func TestBuildCrossSubnet(t *testing.T) {
+	sourceChainID := ids.Empty.Prefix(0)
+	destinationChainID := ids.Empty.Prefix(1)
+	originID := ids.NodeID{2}
+	payload := []byte{3}
+	signature := []byte{4}
+
+	msg, err := TestBuilder.CrossSubnet(sourceChainID, destinationChainID, originID.ShortID(), payload, signature)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, CrossSubnet, msg.Op())
+	assert.Equal(t, sourceChainID[:], msg.Get(ChainID))
+	assert.Equal(t, destinationChainID[:], msg.Get(DestinationChainID))
+	assert.Equal(t, originID[:], msg.Get(OriginID))
+	assert.Equal(t, payload, msg.Get(ContainerBytes))
+	assert.Equal(t, signature, msg.Get(BLSSignature))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, CrossSubnet, parsedMsg.Op())
+	assert.Equal(t, sourceChainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, destinationChainID[:], parsedMsg.Get(DestinationChainID))
+	assert.Equal(t, originID[:], parsedMsg.Get(OriginID))
+	assert.Equal(t, payload, parsedMsg.Get(ContainerBytes))
+	assert.Equal(t, signature, parsedMsg.Get(BLSSignature))
+}
+
+func TestBuildValidatorSnapshot(t *testing.T) {
+	subnetID := ids.Empty.Prefix(0)
+	snapshot := []byte{1}
+	publicKey := []byte{2}
+	proofOfPossession := []byte{3}
+	keySignature := []byte{4}
+	signature := []byte{5}
+
+	msg, err := TestBuilder.GetValidatorSnapshot(subnetID)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, GetValidatorSnapshot, msg.Op())
+	assert.Equal(t, subnetID[:], msg.Get(SubnetID))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, GetValidatorSnapshot, parsedMsg.Op())
+	assert.Equal(t, subnetID[:], parsedMsg.Get(SubnetID))
+
+	msg, err = TestBuilder.ValidatorSnapshot(snapshot, publicKey, proofOfPossession, keySignature, signature)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, ValidatorSnapshot, msg.Op())
+
+	parsedMsg, err = TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, ValidatorSnapshot, parsedMsg.Op())
+	assert.Equal(t, snapshot, parsedMsg.Get(ContainerBytes))
+	assert.Equal(t, publicKey, parsedMsg.Get(BLSPublicKey))
+	assert.Equal(t, proofOfPossession, parsedMsg.Get(BLSProofOfPossession))
+	assert.Equal(t, keySignature, parsedMsg.Get(BLSKeySignature))
+	assert.Equal(t, signature, parsedMsg.Get(BLSSignature))
+}
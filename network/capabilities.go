@@ -0,0 +1,89 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Capability is a bitmap of optional protocol features a node supports. Nodes
+// exchange their capabilities during the handshake so that new features can be
+// rolled out incrementally: a feature is only used with a peer if both sides
+// advertised it. Peers that never send a Capabilities message are treated as
+// supporting no optional features.
+type Capability uint64
+
+// Optional protocol features. New features must be appended so that the bit
+// assignments of existing features never change. Bits 0, 1 and 3 are reserved
+// for compression, batched messages and QUIC, which aren't implemented yet and
+// therefore can't be advertised.
+const (
+	_ Capability = 1 << iota
+	_
+	StateSyncCapability
+	_
+	ChunkedTransferCapability
+	ValidatorSnapshotCapability
+	UptimeReportCapability
+
+	// NoCapabilities is the capability set of a peer that didn't advertise any
+	// optional features.
+	NoCapabilities Capability = 0
+)
+
+var capabilityNames = map[Capability]string{
+	StateSyncCapability:         "state-sync",
+	ChunkedTransferCapability:   "chunked-transfer",
+	ValidatorSnapshotCapability: "validator-snapshot",
+	UptimeReportCapability:      "uptime-report",
+}
+
+// ParseCapabilities converts a comma separated list of capability names into a
+// capability set.
+func ParseCapabilities(s string) (Capability, error) {
+	caps := NoCapabilities
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for capability, capabilityName := range capabilityNames {
+			if capabilityName == name {
+				caps |= capability
+				found = true
+				break
+			}
+		}
+		if !found {
+			return NoCapabilities, fmt.Errorf("unknown capability %q", name)
+		}
+	}
+	return caps, nil
+}
+
+// Contains returns true if every feature in [o] is also in [c].
+func (c Capability) Contains(o Capability) bool { return c&o == o }
+
+// Intersect returns the features supported by both [c] and [o].
+func (c Capability) Intersect(o Capability) Capability { return c & o }
+
+// List returns the names of the features in [c]. Features this node doesn't
+// know about are reported by their bit index.
+func (c Capability) List() []string {
+	names := make([]string, 0, bits.OnesCount64(uint64(c)))
+	for remaining := uint64(c); remaining != 0; remaining &= remaining - 1 {
+		bit := Capability(remaining & -remaining)
+		if name, ok := capabilityNames[bit]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, fmt.Sprintf("unknown-%d", bits.TrailingZeros64(uint64(bit))))
+		}
+	}
+	return names
+}
+
+func (c Capability) String() string { return strings.Join(c.List(), ",") }
@@ -0,0 +1,44 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	caps, err := ParseCapabilities("")
+	assert.NoError(t, err)
+	assert.Equal(t, NoCapabilities, caps)
+
+	caps, err = ParseCapabilities("state-sync, chunked-transfer")
+	assert.NoError(t, err)
+	assert.Equal(t, StateSyncCapability|ChunkedTransferCapability, caps)
+	assert.Equal(t, "state-sync,chunked-transfer", caps.String())
+
+	_, err = ParseCapabilities("state-sync,telepathy")
+	assert.Error(t, err)
+
+	// Reserved features aren't implemented, so they can't be advertised
+	_, err = ParseCapabilities("compression")
+	assert.Error(t, err)
+}
+
+func TestCapabilityIntersect(t *testing.T) {
+	mine := ChunkedTransferCapability | StateSyncCapability
+	theirs := StateSyncCapability | UptimeReportCapability
+
+	negotiated := mine.Intersect(theirs)
+	assert.True(t, negotiated.Contains(StateSyncCapability))
+	assert.False(t, negotiated.Contains(ChunkedTransferCapability))
+	assert.False(t, negotiated.Contains(UptimeReportCapability))
+	assert.True(t, negotiated.Contains(NoCapabilities))
+}
+
+func TestCapabilityListUnknown(t *testing.T) {
+	caps := StateSyncCapability | Capability(1<<1) | Capability(1<<63)
+	assert.Equal(t, []string{"unknown-1", "state-sync", "unknown-63"}, caps.List())
+}
@@ -0,0 +1,329 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+const (
+	// DefaultMaxChunkedContainerSize is the largest container that will be
+	// reassembled from chunks.
+	DefaultMaxChunkedContainerSize = 8 * 1024 * 1024 // 8 MB
+
+	// DefaultMaxConcurrentChunkedTransfers is the number of containers a single
+	// peer may be sending us in chunks at the same time.
+	DefaultMaxConcurrentChunkedTransfers = 1
+
+	// DefaultMaxChunkedTransferBytes is the number of bytes that all peers
+	// together may have buffered in chunked transfers.
+	DefaultMaxChunkedTransferBytes = 32 * 1024 * 1024 // 32 MB
+
+	// Upper bound on the size of a PutChunk message, excluding the chunk
+	// itself.
+	putChunkOverhead = 128
+
+	// Chunked transfers that haven't completed after this long are abandoned.
+	defaultChunkedTransferTimeout = time.Minute
+)
+
+var (
+	errNoChunks                 = errors.New("chunked transfer has no chunks")
+	errChunkedContainerTooLarge = errors.New("chunked container is too large")
+	errTooManyChunkedTransfers  = errors.New("too many concurrent chunked transfers")
+	errDuplicateChunkedTransfer = errors.New("chunked transfer already in progress")
+	errUnknownChunkedTransfer   = errors.New("chunk doesn't belong to a chunked transfer in progress")
+	errChunkIndexOutOfRange     = errors.New("chunk index out of range")
+	errChunkTooLarge            = errors.New("chunk is too large")
+	errChunkHashMismatch        = errors.New("chunk doesn't match its hash")
+	errDuplicateChunk           = errors.New("chunk was already received")
+	errUnexpectedChunkedOp      = errors.New("op can't be sent in chunks")
+	errChunkBudgetExceeded      = errors.New("too many bytes buffered in chunked transfers")
+)
+
+// splitContainer splits [container] into chunks of at most [chunkSize] bytes
+// and returns the chunks along with the hash of each chunk.
+func splitContainer(container []byte, chunkSize int) ([][]byte, []ids.ID) {
+	numChunks := (len(container) + chunkSize - 1) / chunkSize
+	chunks := make([][]byte, 0, numChunks)
+	hashes := make([]ids.ID, 0, numChunks)
+	for start := 0; start < len(container); start += chunkSize {
+		end := start + chunkSize
+		if end > len(container) {
+			end = len(container)
+		}
+		chunk := container[start:end]
+		chunks = append(chunks, chunk)
+		hashes = append(hashes, hashing.ComputeHash256Array(chunk))
+	}
+	return chunks, hashes
+}
+
+// chunkedTransferID identifies a container being sent to us in chunks.
+type chunkedTransferID struct {
+	chainID     ids.ID
+	requestID   uint32
+	containerID ids.ID
+}
+
+type chunkedTransfer struct {
+	op       Op
+	deadline uint64
+	hashes   []ids.ID
+	chunks   [][]byte
+	received int
+	size     int
+	started  time.Time
+}
+
+// chunkedContainer is a container that was reassembled from chunks, along
+// with how it should be handled.
+type chunkedContainer struct {
+	// Op that the container was sent as. One of Put, PushQuery or MultiPut.
+	op Op
+	// Deadline of the query, if [op] is PushQuery. It's measured from when the
+	// transfer started, since that's when the sender sent the query.
+	deadline  time.Time
+	container []byte
+}
+
+// chunkBudget limits the number of bytes that all peers together can have
+// buffered in chunked transfers, so that a few peers can't exhaust our memory.
+type chunkBudget struct {
+	lock sync.Mutex
+	max  int
+	used int
+}
+
+func newChunkBudget(max int) *chunkBudget {
+	return &chunkBudget{max: max}
+}
+
+// reserve [size] bytes. Returns false if they would exceed the budget.
+func (b *chunkBudget) reserve(size int) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.used+size > b.max {
+		return false
+	}
+	b.used += size
+	return true
+}
+
+// release [size] bytes that were reserved.
+func (b *chunkBudget) release(size int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.used -= size
+}
+
+// chunkAssembler reassembles the containers that a single peer sends us in
+// chunks.
+type chunkAssembler struct {
+	lock sync.Mutex
+
+	maxContainerSize int
+	maxConcurrent    int
+	maxChunkSize     int
+	timeout          time.Duration
+
+	// Shared by the assemblers of all peers
+	budget *chunkBudget
+
+	transfers map[chunkedTransferID]*chunkedTransfer
+}
+
+func newChunkAssembler(maxContainerSize, maxConcurrent, maxChunkSize int, timeout time.Duration, budget *chunkBudget) *chunkAssembler {
+	return &chunkAssembler{
+		maxContainerSize: maxContainerSize,
+		maxConcurrent:    maxConcurrent,
+		maxChunkSize:     maxChunkSize,
+		timeout:          timeout,
+		budget:           budget,
+		transfers:        make(map[chunkedTransferID]*chunkedTransfer),
+	}
+}
+
+// Start a chunked transfer of the container identified by [id], whose chunks
+// have the hashes [hashes]. Once it's reassembled, the container is handled as
+// [op], with the query deadline [deadline].
+func (a *chunkAssembler) Start(id chunkedTransferID, op Op, deadline uint64, hashes []ids.ID, now time.Time) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.removeExpired(now)
+
+	switch {
+	case op != Put && op != PushQuery && op != MultiPut:
+		return fmt.Errorf("%w: %s", errUnexpectedChunkedOp, op)
+	case len(hashes) == 0:
+		return errNoChunks
+	case len(hashes) > (a.maxContainerSize+a.maxChunkSize-1)/a.maxChunkSize:
+		return fmt.Errorf("%w: %d chunks", errChunkedContainerTooLarge, len(hashes))
+	}
+	if _, exists := a.transfers[id]; exists {
+		return errDuplicateChunkedTransfer
+	}
+	if len(a.transfers) >= a.maxConcurrent {
+		return errTooManyChunkedTransfers
+	}
+
+	a.transfers[id] = &chunkedTransfer{
+		op:       op,
+		deadline: deadline,
+		hashes:   hashes,
+		chunks:   make([][]byte, len(hashes)),
+		started:  now,
+	}
+	return nil
+}
+
+// Add the chunk at [index] to the transfer identified by [id]. If this was the
+// last missing chunk, the reassembled container is returned along with true.
+// The chunk hashes only protect the transfer, since they are chosen by the
+// sender. The container is checked against its ID once the chain parses it.
+func (a *chunkAssembler) Add(id chunkedTransferID, index uint32, chunk []byte, now time.Time) (chunkedContainer, bool, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.removeExpired(now)
+
+	transfer, exists := a.transfers[id]
+	switch {
+	case !exists:
+		return chunkedContainer{}, false, errUnknownChunkedTransfer
+	case int(index) >= len(transfer.hashes):
+		return chunkedContainer{}, false, fmt.Errorf("%w: %d >= %d", errChunkIndexOutOfRange, index, len(transfer.hashes))
+	case transfer.chunks[index] != nil:
+		return chunkedContainer{}, false, errDuplicateChunk
+	case len(chunk) > a.maxChunkSize:
+		return chunkedContainer{}, false, fmt.Errorf("%w: %d bytes", errChunkTooLarge, len(chunk))
+	case transfer.size+len(chunk) > a.maxContainerSize:
+		a.remove(id, transfer)
+		return chunkedContainer{}, false, errChunkedContainerTooLarge
+	case hashing.ComputeHash256Array(chunk) != transfer.hashes[index]:
+		// The sender is faulty, so there is no point in waiting for the
+		// remaining chunks.
+		a.remove(id, transfer)
+		return chunkedContainer{}, false, fmt.Errorf("%w: chunk %d", errChunkHashMismatch, index)
+	case !a.budget.reserve(len(chunk)):
+		a.remove(id, transfer)
+		return chunkedContainer{}, false, errChunkBudgetExceeded
+	}
+
+	transfer.chunks[index] = chunk
+	transfer.received++
+	transfer.size += len(chunk)
+	if transfer.received < len(transfer.chunks) {
+		return chunkedContainer{}, false, nil
+	}
+
+	a.remove(id, transfer)
+	container := make([]byte, 0, transfer.size)
+	for _, chunk := range transfer.chunks {
+		container = append(container, chunk...)
+	}
+	return chunkedContainer{
+		op:        transfer.op,
+		deadline:  transfer.started.Add(time.Duration(transfer.deadline)),
+		container: container,
+	}, true, nil
+}
+
+// Len returns the number of chunked transfers in progress.
+func (a *chunkAssembler) Len() int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return len(a.transfers)
+}
+
+// Release releases the bytes buffered in the transfers in progress. Called
+// when the peer disconnects.
+func (a *chunkAssembler) Release() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for id, transfer := range a.transfers {
+		a.remove(id, transfer)
+	}
+}
+
+// removeExpired abandons the transfers that started more than [a.timeout] ago.
+// Assumes [a.lock] is held.
+func (a *chunkAssembler) removeExpired(now time.Time) {
+	for id, transfer := range a.transfers {
+		if now.Sub(transfer.started) > a.timeout {
+			a.remove(id, transfer)
+		}
+	}
+}
+
+// remove the transfer identified by [id] and release the bytes it buffered.
+// Assumes [a.lock] is held.
+func (a *chunkAssembler) remove(id chunkedTransferID, transfer *chunkedTransfer) {
+	delete(a.transfers, id)
+	a.budget.release(transfer.size)
+}
+
+// containerRequestID identifies a Get or GetAncestors that we sent to a peer.
+type containerRequestID struct {
+	nodeID    ids.NodeID
+	chainID   ids.ID
+	requestID uint32
+}
+
+// containerRequests tracks the Get and GetAncestors requests that are waiting
+// for a reply, so that peers that aren't validators can only send us
+// containers in chunks that we asked them for.
+type containerRequests struct {
+	lock sync.Mutex
+	// Request --> Time the request expires
+	requests map[containerRequestID]time.Time
+}
+
+func newContainerRequests() *containerRequests {
+	return &containerRequests{
+		requests: make(map[containerRequestID]time.Time),
+	}
+}
+
+// Add a request that expires at [expiry].
+func (r *containerRequests) Add(id containerRequestID, expiry, now time.Time) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.removeExpired(now)
+	r.requests[id] = expiry
+}
+
+// Remove the request identified by [id]. Returns true if it was waiting for a
+// reply.
+func (r *containerRequests) Remove(id containerRequestID, now time.Time) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.removeExpired(now)
+	_, exists := r.requests[id]
+	delete(r.requests, id)
+	return exists
+}
+
+// removeExpired forgets the requests that have expired.
+// Assumes [r.lock] is held.
+func (r *containerRequests) removeExpired(now time.Time) {
+	for id, expiry := range r.requests {
+		if now.After(expiry) {
+			delete(r.requests, id)
+		}
+	}
+}
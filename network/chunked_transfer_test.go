@@ -0,0 +1,172 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestSplitContainer(t *testing.T) {
+	container := []byte{0, 1, 2, 3, 4, 5, 6}
+
+	chunks, hashes := splitContainer(container, 3)
+	assert.Equal(t, [][]byte{{0, 1, 2}, {3, 4, 5}, {6}}, chunks)
+	assert.Len(t, hashes, 3)
+	assert.NotEqual(t, hashes[0], hashes[1])
+}
+
+func TestChunkAssemblerReassembles(t *testing.T) {
+	now := time.Now()
+	budget := newChunkBudget(1024)
+	a := newChunkAssembler(1024, 1, 3, time.Minute, budget)
+	container := []byte{0, 1, 2, 3, 4, 5, 6}
+	id := chunkedTransferID{
+		chainID:     ids.GenerateTestID(),
+		requestID:   1,
+		containerID: ids.GenerateTestID(),
+	}
+	chunks, hashes := splitContainer(container, 3)
+
+	assert.NoError(t, a.Start(id, PushQuery, uint64(time.Second), hashes, now))
+	assert.Equal(t, 1, a.Len())
+
+	// Chunks may arrive in any order
+	for _, i := range []int{2, 0} {
+		_, done, err := a.Add(id, uint32(i), chunks[i], now)
+		assert.NoError(t, err)
+		assert.False(t, done)
+	}
+	_, _, err := a.Add(id, 0, chunks[0], now)
+	assert.True(t, errors.Is(err, errDuplicateChunk))
+
+	reassembled, done, err := a.Add(id, 1, chunks[1], now.Add(time.Millisecond))
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, PushQuery, reassembled.op)
+	// The deadline is measured from when the transfer started
+	assert.Equal(t, now.Add(time.Second), reassembled.deadline)
+	assert.Equal(t, container, reassembled.container)
+	assert.Equal(t, 0, a.Len())
+	assert.Equal(t, 0, budget.used)
+}
+
+func TestChunkAssemblerBudget(t *testing.T) {
+	now := time.Now()
+	// The budget is shared by the assemblers of every peer
+	budget := newChunkBudget(5)
+	a0 := newChunkAssembler(1024, 1, 3, time.Minute, budget)
+	a1 := newChunkAssembler(1024, 1, 3, time.Minute, budget)
+	id := chunkedTransferID{containerID: ids.GenerateTestID()}
+	chunks, hashes := splitContainer([]byte{0, 1, 2, 3, 4, 5}, 3)
+
+	assert.True(t, errors.Is(a0.Start(id, Get, 0, hashes, now), errUnexpectedChunkedOp))
+
+	assert.NoError(t, a0.Start(id, Put, 0, hashes, now))
+	assert.NoError(t, a1.Start(id, Put, 0, hashes, now))
+	_, _, err := a0.Add(id, 0, chunks[0], now)
+	assert.NoError(t, err)
+
+	// The second peer's chunk doesn't fit in what's left of the budget, so its
+	// transfer is abandoned
+	_, _, err = a1.Add(id, 0, chunks[0], now)
+	assert.True(t, errors.Is(err, errChunkBudgetExceeded))
+	assert.Equal(t, 0, a1.Len())
+
+	// The buffered bytes are released when the peer disconnects
+	a0.Release()
+	assert.Equal(t, 0, a0.Len())
+	assert.Equal(t, 0, budget.used)
+}
+
+func TestContainerRequests(t *testing.T) {
+	now := time.Now()
+	r := newContainerRequests()
+	id := containerRequestID{
+		nodeID:    ids.GenerateTestNodeID(),
+		chainID:   ids.GenerateTestID(),
+		requestID: 1,
+	}
+
+	assert.False(t, r.Remove(id, now))
+
+	r.Add(id, now.Add(time.Second), now)
+	assert.True(t, r.Remove(id, now))
+	// A request can only be replied to once
+	assert.False(t, r.Remove(id, now))
+
+	r.Add(id, now.Add(time.Second), now)
+	assert.False(t, r.Remove(id, now.Add(2*time.Second)))
+}
+
+func TestWaitForSendQueue(t *testing.T) {
+	p := &peer{
+		net:          &network{maxMessageSize: 1},
+		pendingBytes: 2,
+	}
+	p.sendQueueDrained = sync.NewCond(&p.senderLock)
+
+	// The send queue doesn't drain before the deadline
+	assert.False(t, p.waitForSendQueue(time.Now().Add(time.Millisecond)))
+
+	// Drain the send queue while the chunk sender is waiting
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		p.senderLock.Lock()
+		p.pendingBytes = 0
+		p.sendQueueDrained.Broadcast()
+		p.senderLock.Unlock()
+	}()
+	assert.True(t, p.waitForSendQueue(time.Now().Add(time.Minute)))
+
+	p.closed.SetValue(true)
+	assert.False(t, p.waitForSendQueue(time.Now().Add(time.Minute)))
+}
+
+func TestChunkAssemblerRejectsBadChunk(t *testing.T) {
+	now := time.Now()
+	a := newChunkAssembler(1024, 1, 3, time.Minute, newChunkBudget(1024))
+	id := chunkedTransferID{containerID: ids.GenerateTestID()}
+	_, hashes := splitContainer([]byte{0, 1, 2, 3}, 3)
+
+	assert.NoError(t, a.Start(id, Put, 0, hashes, now))
+
+	_, _, err := a.Add(id, 2, []byte{0}, now)
+	assert.True(t, errors.Is(err, errChunkIndexOutOfRange))
+
+	_, _, err = a.Add(id, 0, []byte{0, 1, 3}, now)
+	assert.True(t, errors.Is(err, errChunkHashMismatch))
+
+	// A faulty chunk abandons the transfer
+	_, _, err = a.Add(id, 1, []byte{3}, now)
+	assert.True(t, errors.Is(err, errUnknownChunkedTransfer))
+}
+
+func TestChunkAssemblerLimits(t *testing.T) {
+	now := time.Now()
+	a := newChunkAssembler(6, 1, 3, time.Minute, newChunkBudget(1024))
+	id0 := chunkedTransferID{containerID: ids.GenerateTestID()}
+	id1 := chunkedTransferID{containerID: ids.GenerateTestID()}
+
+	_, tooManyHashes := splitContainer([]byte{0, 1, 2, 3, 4, 5, 6}, 3)
+	err := a.Start(id0, Put, 0, tooManyHashes, now)
+	assert.True(t, errors.Is(err, errChunkedContainerTooLarge))
+
+	assert.True(t, errors.Is(a.Start(id0, Put, 0, nil, now), errNoChunks))
+
+	_, hashes := splitContainer([]byte{0, 1, 2, 3}, 3)
+	assert.NoError(t, a.Start(id0, Put, 0, hashes, now))
+	assert.True(t, errors.Is(a.Start(id0, Put, 0, hashes, now), errDuplicateChunkedTransfer))
+	assert.True(t, errors.Is(a.Start(id1, Put, 0, hashes, now), errTooManyChunkedTransfers))
+
+	// Once the first transfer expires, there is room for another one
+	assert.NoError(t, a.Start(id1, Put, 0, hashes, now.Add(2*time.Minute)))
+	assert.Equal(t, 1, a.Len())
+}
@@ -12,22 +12,37 @@ type Field uint32
 
 // Fields that may be packed. These values are not sent over the wire.
 const (
-	VersionStr          Field = iota // Used in handshake
-	NetworkID                        // Used in handshake
-	NodeID                           // Used in handshake
-	MyTime                           // Used in handshake
-	IP                               // Used in handshake
-	Peers                            // Used in handshake
-	ChainID                          // Used for dispatching
-	RequestID                        // Used for all messages
-	Deadline                         // Used for request messages
-	ContainerID                      // Used for querying
-	ContainerBytes                   // Used for gossiping
-	ContainerIDs                     // Used for querying
-	MultiContainerBytes              // Used in MultiPut
-	SigBytes                         // Used in handshake / peer gossiping
-	VersionTime                      // Used in handshake / peer gossiping
-	SignedPeers                      // Used in peer gossiping
+	VersionStr           Field = iota // Used in handshake
+	NetworkID                         // Used in handshake
+	NodeID                            // Used in handshake
+	MyTime                            // Used in handshake
+	IP                                // Used in handshake
+	Peers                             // Used in handshake
+	ChainID                           // Used for dispatching
+	RequestID                         // Used for all messages
+	Deadline                          // Used for request messages
+	ContainerID                       // Used for querying
+	ContainerBytes                    // Used for gossiping
+	ContainerIDs                      // Used for querying
+	MultiContainerBytes               // Used in MultiPut
+	SigBytes                          // Used in handshake / peer gossiping
+	VersionTime                       // Used in handshake / peer gossiping
+	SignedPeers                       // Used in peer gossiping
+	CapabilityFlags                   // Used in handshake
+	ChunkHashes                       // Used in chunked transfers
+	ChunkIndex                        // Used in chunked transfers
+	DestinationChainID                // Used in cross-subnet messages
+	OriginID                          // Used in cross-subnet messages
+	SubnetID                          // Used in validator snapshots
+	BLSPublicKey                      // Used in validator snapshots
+	BLSProofOfPossession              // Used in validator snapshots
+	BLSSignature                      // Used in validator snapshots and cross-subnet messages
+	BLSKeySignature                   // Used in validator snapshots
+	SummaryHeights                    // Used in state sync
+	BucketStart                       // Used in uptime reports
+	ValidatorIDs                      // Used in uptime reports
+	UpDurations                       // Used in uptime reports
+	ChunkedOp                         // Used in chunked transfers
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -65,6 +80,36 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackLong
 	case SignedPeers:
 		return wrappers.TryPackIPCertList
+	case CapabilityFlags:
+		return wrappers.TryPackLong
+	case ChunkHashes:
+		return wrappers.TryPackHashes
+	case ChunkIndex:
+		return wrappers.TryPackInt
+	case ChunkedOp:
+		return wrappers.TryPackByte
+	case DestinationChainID:
+		return wrappers.TryPackHash
+	case OriginID:
+		return wrappers.TryPackAddr
+	case SubnetID:
+		return wrappers.TryPackHash
+	case BLSPublicKey:
+		return wrappers.TryPackBytes
+	case BLSProofOfPossession:
+		return wrappers.TryPackBytes
+	case BLSSignature:
+		return wrappers.TryPackBytes
+	case BLSKeySignature:
+		return wrappers.TryPackBytes
+	case SummaryHeights:
+		return wrappers.TryPackLongs
+	case BucketStart:
+		return wrappers.TryPackLong
+	case ValidatorIDs:
+		return wrappers.TryPackAddrList
+	case UpDurations:
+		return wrappers.TryPackLongs
 	default:
 		return nil
 	}
@@ -105,6 +150,36 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackLong
 	case SignedPeers:
 		return wrappers.TryUnpackIPCertList
+	case CapabilityFlags:
+		return wrappers.TryUnpackLong
+	case ChunkHashes:
+		return wrappers.TryUnpackHashes
+	case ChunkIndex:
+		return wrappers.TryUnpackInt
+	case ChunkedOp:
+		return wrappers.TryUnpackByte
+	case DestinationChainID:
+		return wrappers.TryUnpackHash
+	case OriginID:
+		return wrappers.TryUnpackAddr
+	case SubnetID:
+		return wrappers.TryUnpackHash
+	case BLSPublicKey:
+		return wrappers.TryUnpackBytes
+	case BLSProofOfPossession:
+		return wrappers.TryUnpackBytes
+	case BLSSignature:
+		return wrappers.TryUnpackBytes
+	case BLSKeySignature:
+		return wrappers.TryUnpackBytes
+	case SummaryHeights:
+		return wrappers.TryUnpackLongs
+	case BucketStart:
+		return wrappers.TryUnpackLong
+	case ValidatorIDs:
+		return wrappers.TryUnpackAddrList
+	case UpDurations:
+		return wrappers.TryUnpackLongs
 	default:
 		return nil
 	}
@@ -144,6 +219,36 @@ func (f Field) String() string {
 		return "VersionTime"
 	case SignedPeers:
 		return "SignedPeers"
+	case CapabilityFlags:
+		return "CapabilityFlags"
+	case ChunkHashes:
+		return "ChunkHashes"
+	case ChunkIndex:
+		return "ChunkIndex"
+	case ChunkedOp:
+		return "ChunkedOp"
+	case DestinationChainID:
+		return "DestinationChainID"
+	case OriginID:
+		return "OriginID"
+	case SubnetID:
+		return "SubnetID"
+	case BLSPublicKey:
+		return "BLSPublicKey"
+	case BLSProofOfPossession:
+		return "BLSProofOfPossession"
+	case BLSSignature:
+		return "BLSSignature"
+	case BLSKeySignature:
+		return "BLSKeySignature"
+	case SummaryHeights:
+		return "SummaryHeights"
+	case BucketStart:
+		return "BucketStart"
+	case ValidatorIDs:
+		return "ValidatorIDs"
+	case UpDurations:
+		return "UpDurations"
 	default:
 		return "Unknown Field"
 	}
@@ -188,6 +293,28 @@ func (op Op) String() string {
 		return "pull_query"
 	case Chits:
 		return "chits"
+	case Capabilities:
+		return "capabilities"
+	case ChunkedPut:
+		return "chunked_put"
+	case PutChunk:
+		return "put_chunk"
+	case CrossSubnet:
+		return "cross_subnet"
+	case GetValidatorSnapshot:
+		return "get_validator_snapshot"
+	case ValidatorSnapshot:
+		return "validator_snapshot"
+	case GetStateSummaryFrontier:
+		return "get_state_summary_frontier"
+	case StateSummaryFrontier:
+		return "state_summary_frontier"
+	case GetAcceptedStateSummary:
+		return "get_accepted_state_summary"
+	case AcceptedStateSummary:
+		return "accepted_state_summary"
+	case UptimeReport:
+		return "uptime_report"
 	default:
 		return "Unknown Op"
 	}
@@ -218,6 +345,23 @@ const (
 	// Handshake / peer gossiping
 	Version
 	PeerList
+	// Handshake:
+	Capabilities
+	// Chunked transfers:
+	ChunkedPut
+	PutChunk
+	// Cross-subnet messaging:
+	CrossSubnet
+	// Validator set syncing:
+	GetValidatorSnapshot
+	ValidatorSnapshot
+	// State sync:
+	GetStateSummaryFrontier
+	StateSummaryFrontier
+	GetAcceptedStateSummary
+	AcceptedStateSummary
+	// Uptime reports:
+	UptimeReport
 )
 
 // Defines the messages that can be sent/received with this network
@@ -228,8 +372,12 @@ var (
 		Version:     {NetworkID, NodeID, MyTime, IP, VersionStr, VersionTime, SigBytes},
 		GetPeerList: {},
 		PeerList:    {SignedPeers},
-		Ping:        {},
-		Pong:        {},
+		// Capabilities is sent after Version. Peers that don't recognize the
+		// op will drop it, so older peers are treated as having no optional
+		// capabilities.
+		Capabilities: {CapabilityFlags},
+		Ping:         {},
+		Pong:         {},
 		// Bootstrapping:
 		GetAcceptedFrontier: {ChainID, RequestID, Deadline},
 		AcceptedFrontier:    {ChainID, RequestID, ContainerIDs},
@@ -243,5 +391,44 @@ var (
 		PushQuery: {ChainID, RequestID, Deadline, ContainerID, ContainerBytes},
 		PullQuery: {ChainID, RequestID, Deadline, ContainerID},
 		Chits:     {ChainID, RequestID, ContainerIDs},
+		// Chunked transfers:
+		// A container too large for a Put, PushQuery or MultiPut is sent as
+		// a ChunkedPut, listing the hash of every chunk, followed by one
+		// PutChunk per chunk. [ChunkedOp] is the op that the reassembled
+		// container is handled as, and [Deadline] is only used by queries.
+		// These are only sent to peers that advertised
+		// ChunkedTransferCapability, and are only accepted from validators or
+		// in reply to a Get or GetAncestors.
+		ChunkedPut: {ChainID, RequestID, Deadline, ContainerID, ChunkedOp, ChunkHashes},
+		PutChunk:   {ChainID, RequestID, ContainerID, ChunkIndex, ContainerBytes},
+		// Cross-subnet messaging:
+		// A message from chain [ChainID], created by node [OriginID], to chain
+		// [DestinationChainID] in a different subnet. [BLSSignature] is the
+		// origin's signature of the message.
+		CrossSubnet: {ChainID, DestinationChainID, OriginID, ContainerBytes, BLSSignature},
+		// Validator set syncing:
+		// A ValidatorSnapshot is the sender's BLS signed snapshot of the
+		// validator set and chains of [SubnetID], in response to a
+		// GetValidatorSnapshot. [BLSKeySignature] is the sender's staking key
+		// signature of its BLS public key. These are only sent to peers that
+		// advertised ValidatorSnapshotCapability.
+		GetValidatorSnapshot: {SubnetID},
+		ValidatorSnapshot:    {ContainerBytes, BLSPublicKey, BLSProofOfPossession, BLSKeySignature, BLSSignature},
+		// State sync:
+		// A StateSummaryFrontier carries the sender's latest state summary,
+		// which is empty if it has none. An AcceptedStateSummary carries the
+		// IDs of the sender's state summaries at the requested heights. The
+		// requests are only sent to peers that advertised
+		// StateSyncCapability.
+		GetStateSummaryFrontier: {ChainID, RequestID, Deadline},
+		StateSummaryFrontier:    {ChainID, RequestID, ContainerBytes},
+		GetAcceptedStateSummary: {ChainID, RequestID, Deadline, SummaryHeights},
+		AcceptedStateSummary:    {ChainID, RequestID, ContainerIDs},
+		// Uptime reports:
+		// An UptimeReport carries the time the sender observed each of
+		// [ValidatorIDs] to be up, in nanoseconds, during the bucket starting
+		// at [BucketStart], in Unix seconds. These are only sent to peers that
+		// advertised UptimeReportCapability.
+		UptimeReport: {BucketStart, ValidatorIDs, UpDurations},
 	}
 )
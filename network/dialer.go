@@ -9,6 +9,8 @@ import (
 	"net"
 	"time"
 
+	"golang.org/x/net/proxy"
+
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
@@ -20,6 +22,10 @@ type Dialer interface {
 	// If [ctx] is canceled, gives up trying to connect to [ip]
 	// and returns an error.
 	Dial(ctx context.Context, ip utils.IPDesc) (net.Conn, error)
+
+	// SetThrottleRps changes the max number of outgoing connection attempts
+	// per second. If [throttleRps] is 0, they aren't rate-limited.
+	SetThrottleRps(throttleRps uint32)
 }
 
 type dialer struct {
@@ -27,17 +33,37 @@ type dialer struct {
 	network           string
 	throttler         Throttler
 	connectionTimeout time.Duration
+	// [contextDialer] is used to establish the connection. It either dials the
+	// peer directly or goes through a SOCKS5 proxy.
+	contextDialer proxy.ContextDialer
 }
 
 type DialerConfig struct {
 	throttleRps       uint32
 	connectionTimeout time.Duration
+	proxyConfig       ProxyConfig
 }
 
-func NewDialerConfig(throttleRps uint32, dialTimeout time.Duration) DialerConfig {
+// ProxyConfig describes the SOCKS5 proxy that outbound peer connections are
+// routed through. The proxy is only used for connections this node initiates,
+// so a node that uses one doesn't accept inbound peer connections from other
+// machines.
+type ProxyConfig struct {
+	// Address of the SOCKS5 proxy as host:port. If empty, peers are dialed
+	// directly.
+	Address string
+	// Credentials to authenticate with the proxy. May be empty.
+	Username, Password string
+}
+
+// Enabled returns true if outbound connections should use the proxy.
+func (c ProxyConfig) Enabled() bool { return c.Address != "" }
+
+func NewDialerConfig(throttleRps uint32, dialTimeout time.Duration, proxyConfig ProxyConfig) DialerConfig {
 	return DialerConfig{
 		throttleRps,
 		dialTimeout,
+		proxyConfig,
 	}
 }
 
@@ -46,24 +72,46 @@ func NewDialerConfig(throttleRps uint32, dialTimeout time.Duration) DialerConfig
 // [dialerConfig.connectionTimeout] gives the timeout when dialing an IP.
 // [dialerConfig.throttleRps] gives the max number of outgoing connection attempts/second.
 // If [dialerConfig.throttleRps] == 0, outgoing connections aren't rate-limited.
-func NewDialer(network string, dialerConfig DialerConfig, log logging.Logger) Dialer {
-	var throttler Throttler
-	if dialerConfig.throttleRps <= 0 {
-		throttler = NewNoThrottler()
-	} else {
-		throttler = NewThrottler(int(dialerConfig.throttleRps))
-	}
+// If [dialerConfig.proxyConfig] is enabled, connections are made through the
+// SOCKS5 proxy.
+func NewDialer(network string, dialerConfig DialerConfig, log logging.Logger) (Dialer, error) {
+	// The throttler is created even if connections aren't rate-limited, so
+	// that a limit can be set later
+	throttler := NewThrottler(int(dialerConfig.throttleRps))
 	log.Debug(
 		"dialer has outgoing connection limit of %d/second and dial timeout %s",
 		dialerConfig.throttleRps,
 		dialerConfig.connectionTimeout,
 	)
+	netDialer := &net.Dialer{Timeout: dialerConfig.connectionTimeout}
+	var contextDialer proxy.ContextDialer = netDialer
+	if proxyConfig := dialerConfig.proxyConfig; proxyConfig.Enabled() {
+		var auth *proxy.Auth
+		if proxyConfig.Username != "" || proxyConfig.Password != "" {
+			auth = &proxy.Auth{
+				User:     proxyConfig.Username,
+				Password: proxyConfig.Password,
+			}
+		}
+		socksDialer, err := proxy.SOCKS5(network, proxyConfig.Address, auth, netDialer)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create SOCKS5 dialer: %w", err)
+		}
+		// The SOCKS5 dialer always supports dialing with a context.
+		contextDialer = socksDialer.(proxy.ContextDialer)
+		log.Info("outbound peer connections will be made through the SOCKS5 proxy at %s", proxyConfig.Address)
+	}
 	return &dialer{
 		log:               log,
 		network:           network,
 		throttler:         throttler,
 		connectionTimeout: dialerConfig.connectionTimeout,
-	}
+		contextDialer:     contextDialer,
+	}, nil
+}
+
+func (d *dialer) SetThrottleRps(throttleRps uint32) {
+	d.throttler.SetLimit(int(throttleRps))
 }
 
 func (d *dialer) Dial(ctx context.Context, ip utils.IPDesc) (net.Conn, error) {
@@ -71,8 +119,7 @@ func (d *dialer) Dial(ctx context.Context, ip utils.IPDesc) (net.Conn, error) {
 		return nil, err
 	}
 	d.log.Verbo("dialing %s", ip)
-	dialer := net.Dialer{Timeout: d.connectionTimeout}
-	conn, err := dialer.DialContext(ctx, d.network, ip.String())
+	conn, err := d.contextDialer.DialContext(ctx, d.network, ip.String())
 	if err != nil {
 		return nil, fmt.Errorf("error while dialing %s: %s", ip, err)
 	}
@@ -64,7 +64,8 @@ func TestDialerCancelDial(t *testing.T) {
 	}
 
 	// Create a dialer that should allow 10 outgoing connections per second
-	dialer := NewDialer("tcp", NewDialerConfig(10, 30*time.Second), logging.NoLog{})
+	dialer, err := NewDialer("tcp", NewDialerConfig(10, 30*time.Second, ProxyConfig{}), logging.NoLog{})
+	assert.NoError(t, err)
 	// Make 5 outgoing connections. Should not be throttled.
 	for i := 0; i < 5; i++ {
 		startTime := time.Now()
@@ -93,3 +94,41 @@ func TestDialerCancelDial(t *testing.T) {
 	done <- struct{}{} // mark that test is done
 	_ = l.Close()
 }
+
+// Test that when a proxy is configured, the dialer connects to the proxy
+// rather than to the peer
+func TestDialerUsesProxy(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	greeting := make(chan byte, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// The first byte of a SOCKS5 greeting is the protocol version
+		b := make([]byte, 1)
+		if _, err := conn.Read(b); err == nil {
+			greeting <- b[0]
+		}
+	}()
+
+	proxyConfig := ProxyConfig{Address: l.Addr().String()}
+	dialer, err := NewDialer("tcp", NewDialerConfig(0, 30*time.Second, proxyConfig), logging.NoLog{})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// The proxy never answers, so the dial itself fails
+	_, err = dialer.Dial(ctx, utils.IPDesc{
+		IP:   net.ParseIP("10.0.0.1"),
+		Port: 9651,
+	})
+	assert.Error(t, err)
+	assert.Equal(t, byte(5), <-greeting)
+}
@@ -0,0 +1,41 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"fmt"
+	"net"
+)
+
+const (
+	// Number of leading bits of an IPv4 address that identify its group
+	ipv4GroupBits = 24
+	// Number of leading bits of an IPv6 address that identify its group
+	ipv6GroupBits = 48
+)
+
+// ipGroup returns the group of the addresses that are likely to be hosted in
+// the same network as [ip], such as a datacenter. That is the autonomous system
+// that announces [ip] if [asns] knows it, and otherwise the prefix of [ip].
+// Validators in the same group are grouped together when sampling.
+func ipGroup(asns *ASNTable, ip net.IP) string {
+	if asn, ok := asns.Lookup(ip); ok {
+		return fmt.Sprintf("AS%d", asn)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		prefix := net.IPNet{
+			IP:   ip4.Mask(net.CIDRMask(ipv4GroupBits, 8*net.IPv4len)),
+			Mask: net.CIDRMask(ipv4GroupBits, 8*net.IPv4len),
+		}
+		return prefix.String()
+	}
+	if len(ip) != net.IPv6len {
+		return ""
+	}
+	prefix := net.IPNet{
+		IP:   ip.Mask(net.CIDRMask(ipv6GroupBits, 8*net.IPv6len)),
+		Mask: net.CIDRMask(ipv6GroupBits, 8*net.IPv6len),
+	}
+	return prefix.String()
+}
@@ -0,0 +1,31 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPGroup(t *testing.T) {
+	assert.Equal(t, "1.2.3.0/24", ipGroup(nil, net.ParseIP("1.2.3.4")))
+	assert.Equal(t, ipGroup(nil, net.ParseIP("1.2.3.4")), ipGroup(nil, net.ParseIP("1.2.3.200")))
+	assert.NotEqual(t, ipGroup(nil, net.ParseIP("1.2.3.4")), ipGroup(nil, net.ParseIP("1.2.4.4")))
+	assert.Equal(t, "2001:db8:1::/48", ipGroup(nil, net.ParseIP("2001:db8:1:2::1")))
+	assert.Equal(t, "", ipGroup(nil, nil))
+}
+
+func TestIPGroupASN(t *testing.T) {
+	asns, err := ParseASNTable(strings.NewReader("1.2.0.0/16 100"))
+	assert.NoError(t, err)
+
+	// Addresses of the same autonomous system are grouped together even if
+	// they don't share a prefix
+	assert.Equal(t, "AS100", ipGroup(asns, net.ParseIP("1.2.3.4")))
+	assert.Equal(t, "AS100", ipGroup(asns, net.ParseIP("1.2.200.4")))
+	assert.Equal(t, "5.6.7.0/24", ipGroup(asns, net.ParseIP("5.6.7.8")))
+}
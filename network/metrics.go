@@ -65,12 +65,19 @@ type metrics struct {
 
 	getVersion, version,
 	getPeerlist, peerList,
+	capabilities,
 	ping, pong,
 	getAcceptedFrontier, acceptedFrontier,
 	getAccepted, accepted,
 	getAncestors, multiPut,
 	get, put,
-	pushQuery, pullQuery, chits messageMetrics
+	pushQuery, pullQuery, chits,
+	chunkedPut, putChunk,
+	crossSubnet,
+	getValidatorSnapshot, validatorSnapshot,
+	getStateSummaryFrontier, stateSummaryFrontier,
+	getAcceptedStateSummary, acceptedStateSummary,
+	uptimeReport messageMetrics
 }
 
 func (m *metrics) initialize(registerer prometheus.Registerer) error {
@@ -113,6 +120,7 @@ func (m *metrics) initialize(registerer prometheus.Registerer) error {
 		m.version.initialize(Version, registerer),
 		m.getPeerlist.initialize(GetPeerList, registerer),
 		m.peerList.initialize(PeerList, registerer),
+		m.capabilities.initialize(Capabilities, registerer),
 		m.ping.initialize(Ping, registerer),
 		m.pong.initialize(Pong, registerer),
 		m.getAcceptedFrontier.initialize(GetAcceptedFrontier, registerer),
@@ -126,6 +134,16 @@ func (m *metrics) initialize(registerer prometheus.Registerer) error {
 		m.pushQuery.initialize(PushQuery, registerer),
 		m.pullQuery.initialize(PullQuery, registerer),
 		m.chits.initialize(Chits, registerer),
+		m.chunkedPut.initialize(ChunkedPut, registerer),
+		m.putChunk.initialize(PutChunk, registerer),
+		m.crossSubnet.initialize(CrossSubnet, registerer),
+		m.getValidatorSnapshot.initialize(GetValidatorSnapshot, registerer),
+		m.validatorSnapshot.initialize(ValidatorSnapshot, registerer),
+		m.getStateSummaryFrontier.initialize(GetStateSummaryFrontier, registerer),
+		m.stateSummaryFrontier.initialize(StateSummaryFrontier, registerer),
+		m.getAcceptedStateSummary.initialize(GetAcceptedStateSummary, registerer),
+		m.acceptedStateSummary.initialize(AcceptedStateSummary, registerer),
+		m.uptimeReport.initialize(UptimeReport, registerer),
 	)
 	return errs.Err
 }
@@ -140,6 +158,8 @@ func (m *metrics) message(msgType Op) *messageMetrics {
 		return &m.getPeerlist
 	case PeerList:
 		return &m.peerList
+	case Capabilities:
+		return &m.capabilities
 	case Ping:
 		return &m.ping
 	case Pong:
@@ -166,6 +186,26 @@ func (m *metrics) message(msgType Op) *messageMetrics {
 		return &m.pullQuery
 	case Chits:
 		return &m.chits
+	case ChunkedPut:
+		return &m.chunkedPut
+	case PutChunk:
+		return &m.putChunk
+	case CrossSubnet:
+		return &m.crossSubnet
+	case GetValidatorSnapshot:
+		return &m.getValidatorSnapshot
+	case ValidatorSnapshot:
+		return &m.validatorSnapshot
+	case GetStateSummaryFrontier:
+		return &m.getStateSummaryFrontier
+	case StateSummaryFrontier:
+		return &m.stateSummaryFrontier
+	case GetAcceptedStateSummary:
+		return &m.getAcceptedStateSummary
+	case AcceptedStateSummary:
+		return &m.acceptedStateSummary
+	case UptimeReport:
+		return &m.uptimeReport
 	default:
 		return nil
 	}
@@ -29,6 +29,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/sampler"
@@ -54,6 +55,9 @@ const (
 	defaultConnMeterCacheSize                        = 1024
 	defaultByteSliceCap                              = 128
 	defaultConcurrentUpgrades                        = 64
+	defaultPeerStoreReconnectSize                    = 64
+	defaultSnapshotRequestsPerSecond                 = 1
+	defaultSnapshotRequestBurst                      = 8
 )
 
 var (
@@ -62,7 +66,10 @@ var (
 	errNetworkLayerUnhealthy = errors.New("network layer is unhealthy")
 )
 
-var _ Network = &network{}
+var (
+	_ Network               = &network{}
+	_ validators.Subscriber = &network{}
+)
 
 func init() { rand.Seed(time.Now().UnixNano()) }
 
@@ -76,6 +83,10 @@ type Network interface {
 	// Thread safety must be managed internally in the network.
 	triggers.Acceptor
 
+	// Cross-subnet messages relayed by the router are sent through this
+	// interface. Thread safety must be managed internally in the network.
+	router.CrossSubnetSender
+
 	// Should only be called once, will run until either a fatal error occurs,
 	// or the network is closed. Returns a non-nil error.
 	Dispatch() error
@@ -87,12 +98,12 @@ type Network interface {
 
 	// Attempt to connect to this node ID at IP. Thread safety must be managed
 	// internally to the network.
-	Track(ip utils.IPDesc, nodeID ids.ShortID)
+	Track(ip utils.IPDesc, nodeID ids.NodeID)
 
 	// Returns the description of the specified [nodeIDs] this network is currently
 	// connected to externally or all nodes this network is connected to if [nodeIDs]
 	// is empty. Thread safety must be managed internally to the network.
-	Peers(nodeIDs []ids.ShortID) []PeerID
+	Peers(nodeIDs []ids.NodeID) []PeerID
 
 	// Close this network and all existing connections it has. Thread safety
 	// must be managed internally to the network. Calling close multiple times
@@ -102,6 +113,21 @@ type Network interface {
 	// Return the IP of the node
 	IP() utils.IPDesc
 
+	// SyncValidators requests the validator set of [subnetID] from the
+	// primary network validators this node is connected to, if it isn't
+	// already known. Thread safety must be managed internally to the network.
+	SyncValidators(subnetID ids.ID)
+
+	// ReportUptimes sends this node's latest uptime report to the primary
+	// network validators this node is connected to. Thread safety must be
+	// managed internally to the network.
+	ReportUptimes()
+
+	// SetGossipSizes changes the number of peers that peer lists, gossiped
+	// containers and accepted containers are gossiped to. Thread safety must be
+	// managed internally to the network.
+	SetGossipSizes(peerListGossipSize int, acceptedFrontierSize, onAcceptSize uint)
+
 	// Has a health check
 	health.Checkable
 }
@@ -121,7 +147,7 @@ type network struct {
 	// Keeps track of the percentage of sends that fail
 	sendFailRateCalculator             math.Averager
 	log                                logging.Logger
-	id                                 ids.ShortID
+	id                                 ids.NodeID
 	ip                                 utils.DynamicIPDesc
 	networkID                          uint32
 	versionCompatibility               version.Compatibility
@@ -146,6 +172,9 @@ type network struct {
 	peerListSize int
 	// Gossip a peer list to peers with this frequency
 	peerListGossipFreq time.Duration
+	// gossipLock protects the gossip sizes, which can be changed while the
+	// network is running
+	gossipLock sync.RWMutex
 	// Gossip a peer list to this many peers when gossiping
 	peerListGossipSize           int
 	peerListStakerGossipFraction int
@@ -154,6 +183,7 @@ type network struct {
 	allowPrivateIPs              bool
 	gossipAcceptedFrontierSize   uint
 	gossipOnAcceptSize           uint
+	pushAcceptedFrontierSize     uint
 	pingPongTimeout              time.Duration
 	pingFrequency                time.Duration
 	readBufferSize               uint32
@@ -161,6 +191,32 @@ type network struct {
 	connMeter                    ConnMeter
 	b                            Builder
 	isFetchOnly                  bool
+	// Optional protocol features this node advertises to its peers
+	enabledCapabilities Capability
+	// Peers remembered across restarts
+	peerStore PeerStore
+	// Syncs the validator sets of subnets from peers. May be nil.
+	snapshotSyncer validators.SnapshotSyncer
+	// Notified of the group of each connected peer, so that validator samples
+	// can be spread across networks. The network subscribes to it to learn
+	// when nodes join or leave the primary network. May be nil.
+	vdrGroups validators.Manager
+	// Groups peers by the autonomous system that announces their IP. May be
+	// nil, in which case peers are grouped by IP prefix.
+	asns *ASNTable
+	// Exchanges the uptimes that validators observe each other to have. May be
+	// nil.
+	uptimeReports validators.UptimeReports
+	// Number of stored peers to try to reconnect to on startup
+	peerStoreReconnectSize int
+	// Limits on the containers peers send us in chunks
+	maxChunkedContainerSize       int
+	maxConcurrentChunkedTransfers int
+	chunkedTransferTimeout        time.Duration
+	// Shared by the chunk assemblers of all peers
+	chunkBudget *chunkBudget
+	// Requests that peers that aren't validators may reply to in chunks
+	containerRequests *containerRequests
 
 	// stateLock should never be held when grabbing a peer senderLock
 	stateLock    sync.RWMutex
@@ -185,6 +241,10 @@ type network struct {
 	// again.
 	retryDelay map[string]time.Duration
 
+	// Node ID --> ip.String() of the IP we're attempting to connect to the
+	// node at
+	trackedIPs map[ids.NodeID]string
+
 	// peerAliasTimeout is the age a peer alias must
 	// be before we attempt to release it (so that we
 	// attempt to dial the IP again if gossiped to us).
@@ -194,7 +254,7 @@ type network struct {
 	closeOnce sync.Once
 
 	hasMasked        bool
-	maskedValidators ids.ShortSet
+	maskedValidators ids.NodeIDSet
 
 	benchlistManager benchlist.Manager
 
@@ -216,7 +276,7 @@ type network struct {
 	// The values in this map all have [signature] == nil
 	// A peer is removed from this map when [connected] is called with the peer as the argument
 	// TODO also remove from this map when the peer leaves the validator set
-	latestPeerIP map[ids.ShortID]signedPeerIP
+	latestPeerIP map[ids.NodeID]signedPeerIP
 
 	// Node ID --> Function to execute to stop trying to dial the node.
 	// A node is present in this map if and only if we are actively
@@ -235,7 +295,7 @@ type network struct {
 func NewDefaultNetwork(
 	registerer prometheus.Registerer,
 	log logging.Logger,
-	id ids.ShortID,
+	id ids.NodeID,
 	ip utils.DynamicIPDesc,
 	networkID uint32,
 	versionCompatibility version.Compatibility,
@@ -261,6 +321,13 @@ func NewDefaultNetwork(
 	isFetchOnly bool,
 	gossipAcceptedFrontierSize uint,
 	gossipOnAcceptSize uint,
+	pushAcceptedFrontierSize uint,
+	enabledCapabilities Capability,
+	peerStore PeerStore,
+	snapshotSyncer validators.SnapshotSyncer,
+	vdrGroups validators.Manager,
+	asns *ASNTable,
+	uptimeReports validators.UptimeReports,
 ) Network {
 	return NewNetwork(
 		registerer,
@@ -292,6 +359,7 @@ func NewDefaultNetwork(
 		defaultAllowPrivateIPs,
 		gossipAcceptedFrontierSize,
 		gossipOnAcceptSize,
+		pushAcceptedFrontierSize,
 		defaultPingPongTimeout,
 		defaultPingFrequency,
 		defaultReadBufferSize,
@@ -305,14 +373,30 @@ func NewDefaultNetwork(
 		dialerConfig,
 		tlsKey,
 		isFetchOnly,
+		enabledCapabilities,
+		peerStore,
+		defaultPeerStoreReconnectSize,
+		DefaultMaxChunkedContainerSize,
+		DefaultMaxConcurrentChunkedTransfers,
+		DefaultMaxChunkedTransferBytes,
+		snapshotSyncer,
+		vdrGroups,
+		asns,
+		uptimeReports,
 	)
 }
 
 // NewNetwork returns a new Network implementation with the provided parameters.
+// [peerStore] may be nil, in which case peers aren't remembered across
+// restarts. [snapshotSyncer] may be nil, in which case validator snapshots
+// aren't served or requested. [vdrGroups] may be nil, in which case the
+// groups of connected validators aren't reported for sampling. [asns] may be
+// nil, in which case validators are grouped by IP prefix rather than by ASN.
+// [uptimeReports] may be nil, in which case uptime reports aren't exchanged.
 func NewNetwork(
 	registerer prometheus.Registerer,
 	log logging.Logger,
-	id ids.ShortID,
+	id ids.NodeID,
 	ip utils.DynamicIPDesc,
 	networkID uint32,
 	versionCompatibility version.Compatibility,
@@ -339,6 +423,7 @@ func NewNetwork(
 	allowPrivateIPs bool,
 	gossipAcceptedFrontierSize uint,
 	gossipOnAcceptSize uint,
+	pushAcceptedFrontierSize uint,
 	pingPongTimeout time.Duration,
 	pingFrequency time.Duration,
 	readBufferSize uint32,
@@ -352,7 +437,20 @@ func NewNetwork(
 	dialerConfig DialerConfig,
 	tlsKey crypto.Signer,
 	isFetchOnly bool,
+	enabledCapabilities Capability,
+	peerStore PeerStore,
+	peerStoreReconnectSize int,
+	maxChunkedContainerSize int,
+	maxConcurrentChunkedTransfers int,
+	maxChunkedTransferBytes int,
+	snapshotSyncer validators.SnapshotSyncer,
+	vdrGroups validators.Manager,
+	asns *ASNTable,
+	uptimeReports validators.UptimeReports,
 ) Network {
+	if peerStore == nil {
+		peerStore = noPeerStore{}
+	}
 	// #nosec G404
 	netw := &network{
 		log:                  log,
@@ -388,6 +486,7 @@ func NewNetwork(
 		allowPrivateIPs:                    allowPrivateIPs,
 		gossipAcceptedFrontierSize:         gossipAcceptedFrontierSize,
 		gossipOnAcceptSize:                 gossipOnAcceptSize,
+		pushAcceptedFrontierSize:           pushAcceptedFrontierSize,
 		pingPongTimeout:                    pingPongTimeout,
 		pingFrequency:                      pingFrequency,
 		disconnectedIPs:                    make(map[string]struct{}),
@@ -395,6 +494,7 @@ func NewNetwork(
 		peerAliasIPs:                       make(map[string]struct{}),
 		peerAliasTimeout:                   peerAliasTimeout,
 		retryDelay:                         make(map[string]time.Duration),
+		trackedIPs:                         make(map[ids.NodeID]string),
 		myIPs:                              map[string]struct{}{ip.IP().String(): {}},
 		readBufferSize:                     readBufferSize,
 		readHandshakeTimeout:               readHandshakeTimeout,
@@ -402,8 +502,20 @@ func NewNetwork(
 		healthConfig:                       healthConfig,
 		benchlistManager:                   benchlistManager,
 		tlsKey:                             tlsKey,
-		latestPeerIP:                       make(map[ids.ShortID]signedPeerIP),
+		latestPeerIP:                       make(map[ids.NodeID]signedPeerIP),
 		isFetchOnly:                        isFetchOnly,
+		enabledCapabilities:                enabledCapabilities,
+		peerStore:                          peerStore,
+		snapshotSyncer:                     snapshotSyncer,
+		vdrGroups:                          vdrGroups,
+		asns:                               asns,
+		uptimeReports:                      uptimeReports,
+		peerStoreReconnectSize:             peerStoreReconnectSize,
+		maxChunkedContainerSize:            maxChunkedContainerSize,
+		maxConcurrentChunkedTransfers:      maxConcurrentChunkedTransfers,
+		chunkedTransferTimeout:             defaultChunkedTransferTimeout,
+		chunkBudget:                        newChunkBudget(maxChunkedTransferBytes),
+		containerRequests:                  newContainerRequests(),
 		byteSlicePool: sync.Pool{
 			New: func() interface{} {
 				return make([]byte, 0, defaultByteSliceCap)
@@ -422,6 +534,9 @@ func NewNetwork(
 	if err := netw.initialize(registerer); err != nil {
 		log.Warn("initializing network metrics failed with: %s", err)
 	}
+	if vdrGroups != nil {
+		vdrGroups.Subscribe(netw)
+	}
 	return netw
 }
 
@@ -470,7 +585,7 @@ func (n *network) AcceptedFrontier(nodeID ids.ShortID, chainID ids.ID, requestID
 		return // Packing message failed
 	}
 
-	peer := n.getPeer(nodeID)
+	peer := n.getPeer(ids.NodeIDFromShortID(nodeID))
 	lenMsg := len(msg.Bytes())
 	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
 		n.log.Debug("failed to send AcceptedFrontier(%s, %s, %d, %s)",
@@ -542,7 +657,7 @@ func (n *network) Accepted(nodeID ids.ShortID, chainID ids.ID, requestID uint32,
 		return // Packing message failed
 	}
 
-	peer := n.getPeer(nodeID)
+	peer := n.getPeer(ids.NodeIDFromShortID(nodeID))
 	lenMsg := len(msg.Bytes())
 	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
 		n.log.Debug("failed to send Accepted(%s, %s, %d, %s)",
@@ -559,6 +674,141 @@ func (n *network) Accepted(nodeID ids.ShortID, chainID ids.ID, requestID uint32,
 	}
 }
 
+// GetStateSummaryFrontier implements the Sender interface.
+// The request is only sent to peers that support StateSyncCapability.
+// Assumes [n.stateLock] is not held.
+func (n *network) GetStateSummaryFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration) []ids.ShortID {
+	msg, err := n.b.GetStateSummaryFrontier(chainID, requestID, uint64(deadline))
+	n.log.AssertNoError(err)
+
+	sentTo := make([]ids.ShortID, 0, validatorIDs.Len())
+	now := n.clock.Time()
+	for _, peerElement := range n.getPeers(validatorIDs) {
+		peer := peerElement.peer
+		vID := peerElement.id
+		lenMsg := len(msg.Bytes())
+		if peer == nil || !peer.finishedHandshake.GetValue() || !peer.supports(StateSyncCapability) || !peer.Send(msg, false) {
+			n.log.Debug("failed to send GetStateSummaryFrontier(%s, %s, %d)",
+				vID,
+				chainID,
+				requestID)
+			n.getStateSummaryFrontier.numFailed.Inc()
+			n.sendFailRateCalculator.Observe(1, now)
+		} else {
+			sentTo = append(sentTo, vID)
+			n.getStateSummaryFrontier.numSent.Inc()
+			n.sendFailRateCalculator.Observe(0, now)
+			n.getStateSummaryFrontier.sentBytes.Add(float64(lenMsg))
+		}
+	}
+	return sentTo
+}
+
+// StateSummaryFrontier implements the Sender interface.
+// Assumes [n.stateLock] is not held.
+func (n *network) StateSummaryFrontier(nodeID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	now := n.clock.Time()
+
+	msg, err := n.b.StateSummaryFrontier(chainID, requestID, summary)
+	if err != nil {
+		n.log.Error("failed to build StateSummaryFrontier(%s, %d, %d bytes): %s",
+			chainID,
+			requestID,
+			len(summary),
+			err)
+		n.sendFailRateCalculator.Observe(1, now)
+		return // Packing message failed
+	}
+
+	peer := n.getPeer(ids.NodeIDFromShortID(nodeID))
+	lenMsg := len(msg.Bytes())
+	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
+		n.log.Debug("failed to send StateSummaryFrontier(%s, %s, %d)",
+			nodeID,
+			chainID,
+			requestID)
+		n.stateSummaryFrontier.numFailed.Inc()
+		n.sendFailRateCalculator.Observe(1, now)
+	} else {
+		n.stateSummaryFrontier.numSent.Inc()
+		n.sendFailRateCalculator.Observe(0, now)
+		n.stateSummaryFrontier.sentBytes.Add(float64(lenMsg))
+	}
+}
+
+// GetAcceptedStateSummary implements the Sender interface.
+// The request is only sent to peers that support StateSyncCapability.
+// Assumes [n.stateLock] is not held.
+func (n *network) GetAcceptedStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration, heights []uint64) []ids.ShortID {
+	now := n.clock.Time()
+
+	msg, err := n.b.GetAcceptedStateSummary(chainID, requestID, uint64(deadline), heights)
+	if err != nil {
+		n.log.Error("failed to build GetAcceptedStateSummary(%s, %d, %v): %s",
+			chainID,
+			requestID,
+			heights,
+			err)
+		n.sendFailRateCalculator.Observe(1, now)
+		return nil
+	}
+
+	sentTo := make([]ids.ShortID, 0, validatorIDs.Len())
+	for _, peerElement := range n.getPeers(validatorIDs) {
+		peer := peerElement.peer
+		vID := peerElement.id
+		lenMsg := len(msg.Bytes())
+		if peer == nil || !peer.finishedHandshake.GetValue() || !peer.supports(StateSyncCapability) || !peer.Send(msg, false) {
+			n.log.Debug("failed to send GetAcceptedStateSummary(%s, %s, %d, %v)",
+				vID,
+				chainID,
+				requestID,
+				heights)
+			n.getAcceptedStateSummary.numFailed.Inc()
+			n.sendFailRateCalculator.Observe(1, now)
+		} else {
+			n.getAcceptedStateSummary.numSent.Inc()
+			n.sendFailRateCalculator.Observe(0, now)
+			n.getAcceptedStateSummary.sentBytes.Add(float64(lenMsg))
+			sentTo = append(sentTo, vID)
+		}
+	}
+	return sentTo
+}
+
+// AcceptedStateSummary implements the Sender interface.
+// Assumes [n.stateLock] is not held.
+func (n *network) AcceptedStateSummary(nodeID ids.ShortID, chainID ids.ID, requestID uint32, summaryIDs []ids.ID) {
+	now := n.clock.Time()
+
+	msg, err := n.b.AcceptedStateSummary(chainID, requestID, summaryIDs)
+	if err != nil {
+		n.log.Error("failed to build AcceptedStateSummary(%s, %d, %s): %s",
+			chainID,
+			requestID,
+			summaryIDs,
+			err)
+		n.sendFailRateCalculator.Observe(1, now)
+		return // Packing message failed
+	}
+
+	peer := n.getPeer(ids.NodeIDFromShortID(nodeID))
+	lenMsg := len(msg.Bytes())
+	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
+		n.log.Debug("failed to send AcceptedStateSummary(%s, %s, %d, %s)",
+			nodeID,
+			chainID,
+			requestID,
+			summaryIDs)
+		n.acceptedStateSummary.numFailed.Inc()
+		n.sendFailRateCalculator.Observe(1, now)
+	} else {
+		n.sendFailRateCalculator.Observe(0, now)
+		n.acceptedStateSummary.numSent.Inc()
+		n.acceptedStateSummary.sentBytes.Add(float64(lenMsg))
+	}
+}
+
 // GetAncestors implements the Sender interface.
 // Assumes [n.stateLock] is not held.
 func (n *network) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID) bool {
@@ -571,7 +821,7 @@ func (n *network) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestI
 		return false
 	}
 
-	peer := n.getPeer(validatorID)
+	peer := n.getPeer(ids.NodeIDFromShortID(validatorID))
 	lenMsg := len(msg.Bytes())
 	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
 		n.log.Debug("failed to send GetAncestors(%s, %s, %d, %s)",
@@ -586,6 +836,7 @@ func (n *network) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestI
 	n.getAncestors.numSent.Inc()
 	n.sendFailRateCalculator.Observe(0, now)
 	n.getAncestors.sentBytes.Add(float64(lenMsg))
+	n.addContainerRequest(peer, chainID, requestID, deadline, now)
 	return true
 }
 
@@ -601,8 +852,15 @@ func (n *network) MultiPut(nodeID ids.ShortID, chainID ids.ID, requestID uint32,
 		return
 	}
 
-	peer := n.getPeer(nodeID)
+	peer := n.getPeer(ids.NodeIDFromShortID(nodeID))
 	lenMsg := len(msg.Bytes())
+	if len(containers) == 1 && n.shouldSendChunked(peer, lenMsg) {
+		// Ancestors are only added to a MultiPut while they fit in one
+		// message, so only a lone container can be too large
+		containerID := hashing.ComputeHash256Array(containers[0])
+		n.sendChunked(peer, MultiPut, chainID, requestID, 0, containerID, containers[0])
+		return
+	}
 	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
 		n.log.Debug("failed to send MultiPut(%s, %s, %d, %d)",
 			nodeID,
@@ -626,7 +884,7 @@ func (n *network) Get(nodeID ids.ShortID, chainID ids.ID, requestID uint32, dead
 	msg, err := n.b.Get(chainID, requestID, uint64(deadline), containerID)
 	n.log.AssertNoError(err)
 
-	peer := n.getPeer(nodeID)
+	peer := n.getPeer(ids.NodeIDFromShortID(nodeID))
 	lenMsg := len(msg.Bytes())
 	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
 		n.log.Debug("failed to send Get(%s, %s, %d, %s)",
@@ -641,9 +899,27 @@ func (n *network) Get(nodeID ids.ShortID, chainID ids.ID, requestID uint32, dead
 	n.get.numSent.Inc()
 	n.sendFailRateCalculator.Observe(0, now)
 	n.get.sentBytes.Add(float64(lenMsg))
+	n.addContainerRequest(peer, chainID, requestID, deadline, now)
 	return true
 }
 
+// addContainerRequest records that a Get or GetAncestors was sent to [peer],
+// which may then reply in chunks until [deadline] passes.
+func (n *network) addContainerRequest(peer *peer, chainID ids.ID, requestID uint32, deadline time.Duration, now time.Time) {
+	if !peer.supports(ChunkedTransferCapability) {
+		return
+	}
+	n.containerRequests.Add(
+		containerRequestID{
+			nodeID:    peer.nodeID,
+			chainID:   chainID,
+			requestID: requestID,
+		},
+		now.Add(deadline),
+		now,
+	)
+}
+
 // Put implements the Sender interface.
 // Assumes [n.stateLock] is not held.
 func (n *network) Put(nodeID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
@@ -661,8 +937,13 @@ func (n *network) Put(nodeID ids.ShortID, chainID ids.ID, requestID uint32, cont
 		return
 	}
 
-	peer := n.getPeer(nodeID)
+	peer := n.getPeer(ids.NodeIDFromShortID(nodeID))
 	lenMsg := len(msg.Bytes())
+	if n.shouldSendChunked(peer, lenMsg) {
+		// This container is too large to send in one message
+		n.sendChunked(peer, Put, chainID, requestID, 0, containerID, container)
+		return
+	}
 	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
 		n.log.Debug("failed to send Put(%s, %s, %d, %s)",
 			nodeID,
@@ -679,6 +960,86 @@ func (n *network) Put(nodeID ids.ShortID, chainID ids.ID, requestID uint32, cont
 	}
 }
 
+// shouldSendChunked returns true if a message of [lenMsg] bytes is too large
+// to send to [peer] in one message, but [peer] can receive it in chunks.
+func (n *network) shouldSendChunked(peer *peer, lenMsg int) bool {
+	return int64(lenMsg) > n.maxMessageSize && peer != nil && peer.supports(ChunkedTransferCapability)
+}
+
+// sendChunked sends [container] to [peer] as a ChunkedPut followed by a
+// PutChunk per chunk. The peer handles the reassembled container as [op],
+// which is one of Put, PushQuery or MultiPut. [deadline] is only used by
+// queries. The chunks are sent in the background, waiting for the peer's send
+// queue to drain between chunks. Returns true if the ChunkedPut was sent.
+// Assumes [n.stateLock] is not held.
+func (n *network) sendChunked(peer *peer, op Op, chainID ids.ID, requestID uint32, deadline uint64, containerID ids.ID, container []byte) bool {
+	now := n.clock.Time()
+
+	chunks, hashes := splitContainer(container, int(n.maxMessageSize)-putChunkOverhead)
+	msg, err := n.b.ChunkedPut(chainID, requestID, deadline, containerID, op, hashes)
+	if err != nil {
+		n.log.Error("failed to build ChunkedPut(%s, %d, %s, %s): %s. len(container) : %d",
+			chainID,
+			requestID,
+			containerID,
+			op,
+			err,
+			len(container))
+		n.sendFailRateCalculator.Observe(1, now)
+		return false
+	}
+
+	lenMsg := len(msg.Bytes())
+	if !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
+		n.log.Debug("failed to send ChunkedPut(%s, %s, %d, %s, %s)",
+			peer.nodeID,
+			chainID,
+			requestID,
+			containerID,
+			op)
+		n.chunkedPut.numFailed.Inc()
+		n.sendFailRateCalculator.Observe(1, now)
+		return false
+	}
+	n.chunkedPut.numSent.Inc()
+	n.sendFailRateCalculator.Observe(0, now)
+	n.chunkedPut.sentBytes.Add(float64(lenMsg))
+
+	go n.log.RecoverAndPanic(func() {
+		deadline := time.Now().Add(n.chunkedTransferTimeout)
+		for i, chunk := range chunks {
+			msg, err := n.b.PutChunk(chainID, requestID, containerID, uint32(i), chunk)
+			if err != nil {
+				n.log.Error("failed to build PutChunk(%s, %d, %s, %d): %s",
+					chainID,
+					requestID,
+					containerID,
+					i,
+					err)
+				n.sendFailRateCalculator.Observe(1, n.clock.Time())
+				return
+			}
+
+			lenMsg := len(msg.Bytes())
+			if !peer.waitForSendQueue(deadline) || !peer.Send(msg, true) {
+				n.log.Debug("failed to send PutChunk(%s, %s, %d, %s, %d)",
+					peer.nodeID,
+					chainID,
+					requestID,
+					containerID,
+					i)
+				n.putChunk.numFailed.Inc()
+				n.sendFailRateCalculator.Observe(1, n.clock.Time())
+				return
+			}
+			n.putChunk.numSent.Inc()
+			n.sendFailRateCalculator.Observe(0, n.clock.Time())
+			n.putChunk.sentBytes.Add(float64(lenMsg))
+		}
+	})
+	return true
+}
+
 // PushQuery implements the Sender interface.
 // Assumes [n.stateLock] is not held.
 func (n *network) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID, container []byte) []ids.ShortID {
@@ -702,6 +1063,13 @@ func (n *network) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID
 		peer := peerElement.peer
 		vID := peerElement.id
 		lenMsg := len(msg.Bytes())
+		if n.shouldSendChunked(peer, lenMsg) {
+			// This container is too large to send in one message
+			if n.sendChunked(peer, PushQuery, chainID, requestID, uint64(deadline), containerID, container) {
+				sentTo = append(sentTo, vID)
+			}
+			continue
+		}
 		if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, false) {
 			n.log.Debug("failed to send PushQuery(%s, %s, %d, %s)",
 				vID,
@@ -752,6 +1120,46 @@ func (n *network) PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID
 	return sentTo
 }
 
+// CrossSubnet implements the router.CrossSubnetSender interface.
+// Assumes [n.stateLock] is not held.
+func (n *network) CrossSubnet(validatorIDs ids.ShortSet, sourceChainID, destinationChainID ids.ID, originID ids.ShortID, msgBytes, signature []byte) []ids.ShortID {
+	now := n.clock.Time()
+
+	msg, err := n.b.CrossSubnet(sourceChainID, destinationChainID, originID, msgBytes, signature)
+	if err != nil {
+		n.log.Error("failed to build CrossSubnet(%s, %s, %s): %s. len(msg): %d",
+			sourceChainID,
+			destinationChainID,
+			originID,
+			err,
+			len(msgBytes))
+		n.sendFailRateCalculator.Observe(1, now)
+		return nil
+	}
+
+	sentTo := make([]ids.ShortID, 0, validatorIDs.Len())
+	for _, peerElement := range n.getPeers(validatorIDs) {
+		peer := peerElement.peer
+		vID := peerElement.id
+		lenMsg := len(msg.Bytes())
+		if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, false) {
+			n.log.Debug("failed to send CrossSubnet(%s, %s, %s, %s)",
+				vID,
+				sourceChainID,
+				destinationChainID,
+				originID)
+			n.crossSubnet.numFailed.Inc()
+			n.sendFailRateCalculator.Observe(1, now)
+		} else {
+			n.crossSubnet.numSent.Inc()
+			n.sendFailRateCalculator.Observe(0, now)
+			sentTo = append(sentTo, vID)
+			n.crossSubnet.sentBytes.Add(float64(lenMsg))
+		}
+	}
+	return sentTo
+}
+
 // Chits implements the Sender interface.
 // Assumes [n.stateLock] is not held.
 func (n *network) Chits(nodeID ids.ShortID, chainID ids.ID, requestID uint32, votes []ids.ID) {
@@ -768,7 +1176,7 @@ func (n *network) Chits(nodeID ids.ShortID, chainID ids.ID, requestID uint32, vo
 		return
 	}
 
-	peer := n.getPeer(nodeID)
+	peer := n.getPeer(ids.NodeIDFromShortID(nodeID))
 	lenMsg := len(msg.Bytes())
 	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
 		n.log.Debug("failed to send Chits(%s, %s, %d, %s)",
@@ -788,7 +1196,11 @@ func (n *network) Chits(nodeID ids.ShortID, chainID ids.ID, requestID uint32, vo
 // Gossip attempts to gossip the container to the network
 // Assumes [n.stateLock] is not held.
 func (n *network) Gossip(chainID, containerID ids.ID, container []byte) {
-	if err := n.gossipContainer(chainID, containerID, container, n.gossipAcceptedFrontierSize); err != nil {
+	n.gossipLock.RLock()
+	numToGossip := n.gossipAcceptedFrontierSize
+	n.gossipLock.RUnlock()
+
+	if err := n.gossipContainer(chainID, containerID, container, numToGossip); err != nil {
 		n.log.Debug("failed to Gossip(%s, %s): %s", chainID, containerID, err)
 		n.log.Verbo("container:\n%s", formatting.DumpBytes{Bytes: container})
 	}
@@ -801,7 +1213,14 @@ func (n *network) Accept(ctx *snow.Context, containerID ids.ID, container []byte
 		// don't gossip during bootstrapping
 		return nil
 	}
-	return n.gossipContainer(ctx.ChainID, containerID, container, n.gossipOnAcceptSize)
+	n.gossipLock.RLock()
+	numToGossip := n.gossipOnAcceptSize
+	n.gossipLock.RUnlock()
+
+	if err := n.gossipContainer(ctx.ChainID, containerID, container, numToGossip); err != nil {
+		return err
+	}
+	return n.pushAcceptedFrontier(ctx.ChainID, containerID, n.pushAcceptedFrontierSize)
 }
 
 // shouldUpgradeIncoming returns whether we should
@@ -857,6 +1276,15 @@ func (n *network) Dispatch() error {
 		n.log.Verbo("The new staking set is:\n%s", n.vdrs)
 	}()
 
+	// Attempt to reconnect to the best peers we knew about before restarting
+	for _, record := range n.peerStore.ReconnectTargets(n.peerStoreReconnectSize, n.clock.Time()) {
+		n.log.Verbo("attempting to reconnect to stored peer %s at %s",
+			record.NodeID,
+			record.IPDesc(),
+		)
+		n.Track(record.IPDesc(), record.NodeID)
+	}
+
 	for { // Continuously accept new connections
 		conn, err := n.listener.Accept() // Returns error when n.Close() is called
 		if err != nil {
@@ -925,7 +1353,7 @@ func (n *network) Dispatch() error {
 // the handshake. Otherwise, returns info about the peers in [nodeIDs]
 // that have finished the handshake.
 // Assumes [n.stateLock] is not held.
-func (n *network) Peers(nodeIDs []ids.ShortID) []PeerID {
+func (n *network) Peers(nodeIDs []ids.NodeID) []PeerID {
 	n.stateLock.RLock()
 	defer n.stateLock.RUnlock()
 
@@ -936,11 +1364,12 @@ func (n *network) Peers(nodeIDs []ids.ShortID) []PeerID {
 				peers = append(peers, PeerID{
 					IP:           peer.conn.RemoteAddr().String(),
 					PublicIP:     peer.getIP().String(),
-					ID:           peer.nodeID.PrefixedString(constants.NodeIDPrefix),
+					ID:           peer.nodeID.String(),
 					Version:      peer.versionStr.GetValue().(string),
 					LastSent:     time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
 					LastReceived: time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
-					Benched:      n.benchlistManager.GetBenched(peer.nodeID),
+					Benched:      n.benchlistManager.GetBenched(peer.nodeID.ShortID()),
+					Capabilities: peer.getCapabilities().List(),
 				})
 			}
 		}
@@ -953,11 +1382,12 @@ func (n *network) Peers(nodeIDs []ids.ShortID) []PeerID {
 			peers = append(peers, PeerID{
 				IP:           peer.conn.RemoteAddr().String(),
 				PublicIP:     peer.getIP().String(),
-				ID:           peer.nodeID.PrefixedString(constants.NodeIDPrefix),
+				ID:           peer.nodeID.String(),
 				Version:      peer.versionStr.GetValue().(string),
 				LastSent:     time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
 				LastReceived: time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
-				Benched:      n.benchlistManager.GetBenched(peer.nodeID),
+				Benched:      n.benchlistManager.GetBenched(peer.nodeID.ShortID()),
+				Capabilities: peer.getCapabilities().List(),
 			})
 		}
 	}
@@ -1002,12 +1432,52 @@ func (n *network) close() {
 // TrackIP implements the Network interface
 // Assumes [n.stateLock] is not held.
 func (n *network) TrackIP(ip utils.IPDesc) {
-	n.Track(ip, ids.ShortEmpty)
+	n.Track(ip, ids.EmptyNodeID)
+}
+
+// SyncValidators implements the Network interface
+// Assumes [n.stateLock] is not held.
+func (n *network) SyncValidators(subnetID ids.ID) {
+	if n.snapshotSyncer == nil || !n.snapshotSyncer.Track(subnetID) {
+		return
+	}
+
+	n.stateLock.RLock()
+	peers := make([]*peer, n.peers.size())
+	copy(peers, n.peers.peersList)
+	n.stateLock.RUnlock()
+
+	for _, peer := range peers {
+		if peer.finishedHandshake.GetValue() &&
+			peer.supports(ValidatorSnapshotCapability) &&
+			peer.canAttestSnapshots() {
+			peer.sendGetValidatorSnapshot(subnetID)
+		}
+	}
+}
+
+// ReportUptimes implements the Network interface
+// Assumes [n.stateLock] is not held.
+func (n *network) ReportUptimes() {
+	if n.uptimeReports == nil {
+		return
+	}
+
+	n.stateLock.RLock()
+	peers := make([]*peer, n.peers.size())
+	copy(peers, n.peers.peersList)
+	n.stateLock.RUnlock()
+
+	for _, peer := range peers {
+		if peer.finishedHandshake.GetValue() {
+			peer.reportUptimes()
+		}
+	}
 }
 
 // Track implements the Network interface
 // Assumes [n.stateLock] is not held.
-func (n *network) Track(ip utils.IPDesc, nodeID ids.ShortID) {
+func (n *network) Track(ip utils.IPDesc, nodeID ids.NodeID) {
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
@@ -1042,8 +1512,15 @@ func (n *network) gossipContainer(chainID, containerID ids.ID, container []byte,
 	if err != nil {
 		return err
 	}
+	lenMsg := len(msg.Bytes())
 	for _, index := range indices {
-		if allPeers[int(index)].Send(msg, false) {
+		peer := allPeers[int(index)]
+		if n.shouldSendChunked(peer, lenMsg) {
+			// This container is too large to send in one message
+			n.sendChunked(peer, Put, chainID, constants.GossipMsgRequestID, 0, containerID, container)
+			continue
+		}
+		if peer.Send(msg, false) {
 			n.put.numSent.Inc()
 			n.sendFailRateCalculator.Observe(0, now)
 		} else {
@@ -1054,9 +1531,56 @@ func (n *network) gossipContainer(chainID, containerID ids.ID, container []byte,
 	return nil
 }
 
+// pushAcceptedFrontier sends an unsolicited AcceptedFrontier message containing
+// [containerID] to [numToPush] random peers. This lets peers that are behind
+// fetch the container without waiting for their next poll.
+// Assumes [n.stateLock] is not held.
+func (n *network) pushAcceptedFrontier(chainID, containerID ids.ID, numToPush uint) error {
+	if numToPush == 0 {
+		return nil
+	}
+
+	now := n.clock.Time()
+
+	containerIDs := []ids.ID{containerID}
+	msg, err := n.b.AcceptedFrontier(chainID, constants.GossipMsgRequestID, containerIDs)
+	if err != nil {
+		n.sendFailRateCalculator.Observe(1, now)
+		return fmt.Errorf("failed to build AcceptedFrontier(%s, %d, %s): %w",
+			chainID, constants.GossipMsgRequestID, containerIDs, err)
+	}
+
+	allPeers := n.getAllPeers()
+
+	if int(numToPush) > len(allPeers) {
+		numToPush = uint(len(allPeers))
+	}
+
+	s := sampler.NewUniform()
+	if err := s.Initialize(uint64(len(allPeers))); err != nil {
+		return err
+	}
+	indices, err := s.Sample(int(numToPush))
+	if err != nil {
+		return err
+	}
+	lenMsg := len(msg.Bytes())
+	for _, index := range indices {
+		if allPeers[int(index)].Send(msg, false) {
+			n.acceptedFrontier.numSent.Inc()
+			n.acceptedFrontier.sentBytes.Add(float64(lenMsg))
+			n.sendFailRateCalculator.Observe(0, now)
+		} else {
+			n.sendFailRateCalculator.Observe(1, now)
+			n.acceptedFrontier.numFailed.Inc()
+		}
+	}
+	return nil
+}
+
 // assumes the stateLock is held.
 // Try to connect to [nodeID] at [ip].
-func (n *network) track(ip utils.IPDesc, nodeID ids.ShortID) {
+func (n *network) track(ip utils.IPDesc, nodeID ids.NodeID) {
 	if n.closed.GetValue() {
 		return
 	}
@@ -1082,10 +1606,23 @@ func (n *network) track(ip utils.IPDesc, nodeID ids.ShortID) {
 		}
 	}
 	n.disconnectedIPs[str] = struct{}{}
+	if nodeID != ids.EmptyNodeID {
+		n.trackedIPs[nodeID] = str
+	}
 
 	go n.connectTo(ip, nodeID)
 }
 
+// SetGossipSizes implements the Network interface
+func (n *network) SetGossipSizes(peerListGossipSize int, acceptedFrontierSize, onAcceptSize uint) {
+	n.gossipLock.Lock()
+	defer n.gossipLock.Unlock()
+
+	n.peerListGossipSize = peerListGossipSize
+	n.gossipAcceptedFrontierSize = acceptedFrontierSize
+	n.gossipOnAcceptSize = onAcceptSize
+}
+
 // Assumes [n.stateLock] is not held. Only returns after the network is closed.
 func (n *network) gossipPeerList() {
 	t := time.NewTicker(n.peerListGossipFreq)
@@ -1111,11 +1648,15 @@ func (n *network) gossipPeerList() {
 			}
 		}
 
-		numStakersToSend := (n.peerListGossipSize + n.peerListStakerGossipFraction - 1) / n.peerListStakerGossipFraction
+		n.gossipLock.RLock()
+		peerListGossipSize := n.peerListGossipSize
+		n.gossipLock.RUnlock()
+
+		numStakersToSend := (peerListGossipSize + n.peerListStakerGossipFraction - 1) / n.peerListStakerGossipFraction
 		if len(stakers) < numStakersToSend {
 			numStakersToSend = len(stakers)
 		}
-		numNonStakersToSend := n.peerListGossipSize - numStakersToSend
+		numNonStakersToSend := peerListGossipSize - numStakersToSend
 		if len(nonStakers) < numNonStakersToSend {
 			numNonStakersToSend = len(nonStakers)
 		}
@@ -1181,12 +1722,12 @@ func (n *network) gossipPeerList() {
 // * We connected to [ip]
 // * The network is closed
 // * We gave up connecting to [ip] because the IP is stale
-// If [nodeID] == ids.ShortEmpty, won't cancel an existing
+// If [nodeID] == ids.EmptyNodeID, won't cancel an existing
 // attempt to connect to the peer with that IP.
 // We do this so we don't cancel attempted to connect to bootstrap beacons.
 // See method TrackIP.
 // Assumes [n.stateLock] isn't held when this method is called.
-func (n *network) connectTo(ip utils.IPDesc, nodeID ids.ShortID) {
+func (n *network) connectTo(ip utils.IPDesc, nodeID ids.NodeID) {
 	str := ip.String()
 	n.stateLock.RLock()
 	delay := n.retryDelay[str]
@@ -1242,9 +1783,9 @@ func (n *network) connectTo(ip utils.IPDesc, nodeID ids.ShortID) {
 		// cancel the existing attempt.
 		// If [nodeID] is the empty ID, [ip] is a bootstrap beacon.
 		// In that case, don't cancel existing connection attempt.
-		if nodeID != ids.ShortEmpty {
+		if nodeID != ids.EmptyNodeID {
 			if cancel, exists := n.connAttempts.Load(nodeID); exists {
-				n.log.Verbo("canceling attempt to connect to stale IP of %s%s", constants.NodeIDPrefix, nodeID)
+				n.log.Verbo("canceling attempt to connect to stale IP of %s", nodeID)
 				cancel.(context.CancelFunc)()
 			}
 		}
@@ -1384,7 +1925,7 @@ func (n *network) tryAddPeer(p *peer) error {
 			delete(n.retryDelay, str)
 			peer.addAlias(ip)
 		}
-		return fmt.Errorf("duplicated connection from %s at %s", p.nodeID.PrefixedString(constants.NodeIDPrefix), ip)
+		return fmt.Errorf("duplicated connection from %s at %s", p.nodeID, ip)
 	}
 
 	n.peers.add(p)
@@ -1468,7 +2009,6 @@ func (n *network) validatorIPs() ([]utils.IPCertDesc, error) {
 // Assumes [n.stateLock] is not held.
 func (n *network) connected(p *peer) {
 	p.net.stateLock.Lock()
-	defer p.net.stateLock.Unlock()
 
 	p.finishedHandshake.SetValue(true)
 
@@ -1493,8 +2033,8 @@ func (n *network) connected(p *peer) {
 		}
 	}
 
-	// TODO also delete an entry from this map when they leave the validator set
 	delete(n.latestPeerIP, p.nodeID)
+	delete(n.trackedIPs, p.nodeID)
 
 	ip := p.getIP()
 	n.log.Debug("connected to %s at %s", p.nodeID, ip)
@@ -1505,16 +2045,28 @@ func (n *network) connected(p *peer) {
 		delete(n.disconnectedIPs, str)
 		delete(n.retryDelay, str)
 		n.connectedIPs[str] = struct{}{}
+
+		if n.vdrGroups != nil {
+			n.vdrGroups.SetValidatorGroup(p.nodeID, ipGroup(n.asns, ip.IP))
+		}
 	}
 
-	n.router.Connected(p.nodeID)
+	n.router.Connected(p.nodeID.ShortID())
+	p.net.stateLock.Unlock()
+
+	// The peer store writes to disk, so it's updated after releasing
+	// [stateLock].
+	if !ip.IsZero() {
+		if err := n.peerStore.Connected(p.nodeID, ip, peerVersion.String(), n.clock.Time()); err != nil {
+			n.log.Warn("failed to store peer %s due to %s", p.nodeID, err)
+		}
+	}
 }
 
 // should only be called after the peer is marked as connected.
 // Assumes [n.stateLock] is not held.
 func (n *network) disconnected(p *peer) {
 	p.net.stateLock.Lock()
-	defer p.net.stateLock.Unlock()
 
 	ip := p.getIP()
 
@@ -1537,8 +2089,74 @@ func (n *network) disconnected(p *peer) {
 	}
 
 	// Only send Disconnected to router if Connected was sent
-	if p.finishedHandshake.GetValue() {
-		n.router.Disconnected(p.nodeID)
+	finishedHandshake := p.finishedHandshake.GetValue()
+	if finishedHandshake {
+		n.router.Disconnected(p.nodeID.ShortID())
+	}
+	p.net.stateLock.Unlock()
+
+	// The peer store writes to disk, so it's updated after releasing
+	// [stateLock].
+	if finishedHandshake {
+		if err := n.peerStore.Disconnected(p.nodeID, n.clock.Time()); err != nil {
+			n.log.Warn("failed to store peer %s due to %s", p.nodeID, err)
+		}
+	}
+}
+
+// OnValidatorAdded implements the validators.Subscriber interface. When a
+// node joins the primary network, we start connecting to it at the IP we last
+// reached it at, if we aren't connected to it already.
+// Assumes [n.stateLock] is not held.
+func (n *network) OnValidatorAdded(subnetID ids.ID, nodeID ids.NodeID, _ uint64) {
+	if subnetID != constants.PrimaryNetworkID || nodeID == n.id {
+		return
+	}
+	record, ok := n.peerStore.Get(nodeID)
+	if !ok || record.Reputation < 0 {
+		return
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if _, connected := n.peers.getByID(nodeID); connected {
+		return
+	}
+	n.track(record.IPDesc(), nodeID)
+}
+
+// OnValidatorRemoved implements the validators.Subscriber interface. When a
+// node leaves the primary network, we forget the IP it was gossiped at and
+// stop attempting to reconnect to it, unless it's a beacon.
+// Assumes [n.stateLock] is not held.
+func (n *network) OnValidatorRemoved(subnetID ids.ID, nodeID ids.NodeID, _ uint64) {
+	if subnetID != constants.PrimaryNetworkID {
+		return
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	delete(n.latestPeerIP, nodeID)
+	str, ok := n.trackedIPs[nodeID]
+	if !ok || n.beacons.Contains(nodeID) {
+		return
+	}
+	delete(n.trackedIPs, nodeID)
+	// [connectTo] stops once the IP is no longer disconnected
+	delete(n.disconnectedIPs, str)
+	delete(n.retryDelay, str)
+}
+
+// OnValidatorWeightChanged implements the validators.Subscriber interface
+func (n *network) OnValidatorWeightChanged(ids.ID, ids.NodeID, uint64, uint64) {}
+
+// penalize lowers the stored reputation of [nodeID] because it sent us an
+// unacceptable handshake.
+func (n *network) penalize(nodeID ids.NodeID) {
+	if err := n.peerStore.ChangeReputation(nodeID, badHandshakeReputationPenalty, n.clock.Time()); err != nil {
+		n.log.Warn("failed to update the reputation of %s due to %s", nodeID, err)
 	}
 }
 
@@ -1565,8 +2183,8 @@ func (n *network) getPeers(nodeIDs ids.ShortSet) []*PeerElement {
 	peers := make([]*PeerElement, nodeIDs.Len())
 	i := 0
 	for nodeID := range nodeIDs {
-		nodeID := nodeID                   // Prevent overwrite in next loop iteration
-		peer, _ := n.peers.getByID(nodeID) // note: peer may be nil
+		nodeID := nodeID                                          // Prevent overwrite in next loop iteration
+		peer, _ := n.peers.getByID(ids.NodeIDFromShortID(nodeID)) // note: peer may be nil
 		peers[i] = &PeerElement{
 			peer: peer,
 			id:   nodeID,
@@ -1599,7 +2217,7 @@ func (n *network) getAllPeers() []*peer {
 
 // Safe find a single peer
 // Assumes [n.stateLock] is not held.
-func (n *network) getPeer(nodeID ids.ShortID) *peer {
+func (n *network) getPeer(nodeID ids.NodeID) *peer {
 	n.stateLock.RLock()
 	defer n.stateLock.RUnlock()
 
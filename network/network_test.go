@@ -39,6 +39,7 @@ const (
 	defaultGossipPeerListTo           = 100
 	defaultGossipAcceptedFrontierSize = 35
 	defaultGossipOnAcceptSize         = 20
+	defaultPushAcceptedFrontierSize   = 0
 )
 
 var (
@@ -83,6 +84,8 @@ type testDialer struct {
 	closer func(net.Addr, net.Addr)
 }
 
+func (d *testDialer) SetThrottleRps(uint32) {}
+
 func (d *testDialer) Dial(ctx context.Context, ip utils.IPDesc) (net.Conn, error) {
 	d.outboundsLock.Lock()
 	defer d.outboundsLock.Unlock()
@@ -215,12 +218,12 @@ func (h *testHandler) Disconnected(id ids.ShortID) {
 type testUpgrader struct {
 	// ids is a mapping of IP addresses
 	// to id
-	ids     map[string]ids.ShortID
+	ids     map[string]ids.NodeID
 	certs   map[string]*x509.Certificate
 	idsLock sync.Mutex
 }
 
-func (u *testUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, *x509.Certificate, error) {
+func (u *testUpgrader) Upgrade(conn net.Conn) (ids.NodeID, net.Conn, *x509.Certificate, error) {
 	u.idsLock.Lock()
 	defer u.idsLock.Unlock()
 	addr := conn.RemoteAddr()
@@ -228,7 +231,7 @@ func (u *testUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, *x509.Cert
 	return u.ids[str], conn, u.certs[str], nil
 }
 
-func (u *testUpgrader) Update(ip utils.DynamicIPDesc, id ids.ShortID) {
+func (u *testUpgrader) Update(ip utils.DynamicIPDesc, id ids.NodeID) {
 	u.idsLock.Lock()
 	defer u.idsLock.Unlock()
 
@@ -263,7 +266,7 @@ func initCerts(t *testing.T) {
 	tlsConfig2 = TLSConfig(*cert2)
 }
 
-var defaultTestDialerConfig = NewDialerConfig(0, 30*time.Second)
+var defaultTestDialerConfig = NewDialerConfig(0, 30*time.Second, ProxyConfig{})
 
 func TestNewDefaultNetwork(t *testing.T) {
 	initCerts(t)
@@ -273,7 +276,7 @@ func TestNewDefaultNetwork(t *testing.T) {
 		net.IPv6loopback,
 		0,
 	)
-	id := ids.ShortID(hashing.ComputeHash160Array([]byte(ip.IP().String())))
+	id := ids.NodeID(hashing.ComputeHash160Array([]byte(ip.IP().String())))
 	networkID := uint32(0)
 	appVersion := version.NewDefaultApplication("app", 0, 1, 0)
 	versionParser := version.NewDefaultApplicationParser()
@@ -338,6 +341,13 @@ func TestNewDefaultNetwork(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net)
 
@@ -361,12 +371,12 @@ func TestEstablishConnection(t *testing.T) {
 		net.IPv6loopback,
 		0,
 	)
-	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	id0 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
 	ip1 := utils.NewDynamicIPDesc(
 		net.IPv6loopback,
 		1,
 	)
-	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+	id1 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
 
 	listener0 := &testListener{
 		addr: &net.TCPAddr{
@@ -419,7 +429,7 @@ func TestEstablishConnection(t *testing.T) {
 
 	handler0 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id != id0 {
+			if id != id0.ShortID() {
 				wg0.Done()
 			}
 		},
@@ -427,7 +437,7 @@ func TestEstablishConnection(t *testing.T) {
 
 	handler1 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id != id1 {
+			if id != id1.ShortID() {
 				wg1.Done()
 			}
 		},
@@ -472,6 +482,13 @@ func TestEstablishConnection(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -504,6 +521,13 @@ func TestEstablishConnection(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -539,12 +563,12 @@ func TestDoubleTrack(t *testing.T) {
 		net.IPv6loopback,
 		0,
 	)
-	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	id0 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
 	ip1 := utils.NewDynamicIPDesc(
 		net.IPv6loopback,
 		1,
 	)
-	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+	id1 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
 
 	listener0 := &testListener{
 		addr: &net.TCPAddr{
@@ -596,7 +620,7 @@ func TestDoubleTrack(t *testing.T) {
 
 	handler0 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id != id0 {
+			if id != id0.ShortID() {
 				wg0.Done()
 			}
 		},
@@ -604,7 +628,7 @@ func TestDoubleTrack(t *testing.T) {
 
 	handler1 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id != id1 {
+			if id != id1.ShortID() {
 				wg1.Done()
 			}
 		},
@@ -649,6 +673,13 @@ func TestDoubleTrack(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -681,6 +712,13 @@ func TestDoubleTrack(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -717,12 +755,12 @@ func TestDoubleClose(t *testing.T) {
 		net.IPv6loopback,
 		0,
 	)
-	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	id0 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
 	ip1 := utils.NewDynamicIPDesc(
 		net.IPv6loopback,
 		1,
 	)
-	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+	id1 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
 
 	listener0 := &testListener{
 		addr: &net.TCPAddr{
@@ -774,7 +812,7 @@ func TestDoubleClose(t *testing.T) {
 
 	handler0 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id != id0 {
+			if id != id0.ShortID() {
 				wg0.Done()
 			}
 		},
@@ -782,7 +820,7 @@ func TestDoubleClose(t *testing.T) {
 
 	handler1 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id != id1 {
+			if id != id1.ShortID() {
 				wg1.Done()
 			}
 		},
@@ -827,6 +865,13 @@ func TestDoubleClose(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -859,6 +904,13 @@ func TestDoubleClose(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -900,12 +952,12 @@ func TestTrackConnected(t *testing.T) {
 		net.IPv6loopback,
 		0,
 	)
-	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	id0 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
 	ip1 := utils.NewDynamicIPDesc(
 		net.IPv6loopback,
 		1,
 	)
-	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+	id1 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
 
 	listener0 := &testListener{
 		addr: &net.TCPAddr{
@@ -957,7 +1009,7 @@ func TestTrackConnected(t *testing.T) {
 
 	handler0 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id != id0 {
+			if id != id0.ShortID() {
 				wg0.Done()
 			}
 		},
@@ -965,7 +1017,7 @@ func TestTrackConnected(t *testing.T) {
 
 	handler1 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id != id1 {
+			if id != id1.ShortID() {
 				wg1.Done()
 			}
 		},
@@ -1010,6 +1062,13 @@ func TestTrackConnected(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -1042,6 +1101,13 @@ func TestTrackConnected(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -1079,12 +1145,12 @@ func TestTrackConnectedRace(t *testing.T) {
 		net.IPv6loopback,
 		0,
 	)
-	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	id0 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
 	ip1 := utils.NewDynamicIPDesc(
 		net.IPv6loopback,
 		1,
 	)
-	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+	id1 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
 
 	listener0 := &testListener{
 		addr: &net.TCPAddr{
@@ -1167,6 +1233,13 @@ func TestTrackConnectedRace(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -1199,6 +1272,13 @@ func TestTrackConnectedRace(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -1220,12 +1300,12 @@ func TestTrackConnectedRace(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func assertEqualPeers(t *testing.T, expected map[string]ids.ShortID, actual []PeerID) {
+func assertEqualPeers(t *testing.T, expected map[string]ids.NodeID, actual []PeerID) {
 	assert.Len(t, actual, len(expected))
 	for _, p := range actual {
 		match, ok := expected[p.IP]
 		assert.True(t, ok, "peer with IP %s missing", p.IP)
-		assert.Equal(t, match.PrefixedString(constants.NodeIDPrefix), p.ID)
+		assert.Equal(t, match.String(), p.ID)
 	}
 }
 
@@ -1320,7 +1400,7 @@ func TestPeerAliasesTicker(t *testing.T) {
 	caller3.outbounds[ip0.IP().String()] = listener0
 
 	upgrader := &testUpgrader{
-		ids: map[string]ids.ShortID{
+		ids: map[string]ids.NodeID{
 			ip0.IP().String(): id0,
 			ip1.IP().String(): id1,
 			ip2.IP().String(): id1,
@@ -1350,11 +1430,11 @@ func TestPeerAliasesTicker(t *testing.T) {
 
 	handler0 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id == id1 {
+			if id == id1.ShortID() {
 				wg0.Done()
 				return
 			}
-			if id == id2 {
+			if id == id2.ShortID() {
 				wg2.Done()
 				return
 			}
@@ -1368,7 +1448,7 @@ func TestPeerAliasesTicker(t *testing.T) {
 
 	handler1 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id == id0 {
+			if id == id0.ShortID() {
 				wg0.Done()
 				return
 			}
@@ -1392,7 +1472,7 @@ func TestPeerAliasesTicker(t *testing.T) {
 
 	handler3 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id == id0 {
+			if id == id0.ShortID() {
 				wg2.Done()
 				return
 			}
@@ -1455,6 +1535,13 @@ func TestPeerAliasesTicker(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -1487,6 +1574,13 @@ func TestPeerAliasesTicker(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -1519,6 +1613,13 @@ func TestPeerAliasesTicker(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net2)
 
@@ -1551,6 +1652,13 @@ func TestPeerAliasesTicker(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net3)
 
@@ -1576,14 +1684,14 @@ func TestPeerAliasesTicker(t *testing.T) {
 
 	// Confirm peers correct
 	wg0.Wait()
-	assertEqualPeers(t, map[string]ids.ShortID{
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip1.String(): id1,
-	}, net0.Peers([]ids.ShortID{}))
-	assertEqualPeers(t, map[string]ids.ShortID{
+	}, net0.Peers([]ids.NodeID{}))
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip0.String(): id0,
-	}, net1.Peers([]ids.ShortID{}))
-	assert.Len(t, net2.Peers([]ids.ShortID{}), 0)
-	assert.Len(t, net3.Peers([]ids.ShortID{}), 0)
+	}, net1.Peers([]ids.NodeID{}))
+	assert.Len(t, net2.Peers([]ids.NodeID{}), 0)
+	assert.Len(t, net3.Peers([]ids.NodeID{}), 0)
 
 	// Attempt to connect to ip2 (same id as ip1)
 	net0.Track(ip2.IP(), id2)
@@ -1591,14 +1699,14 @@ func TestPeerAliasesTicker(t *testing.T) {
 	// Confirm that ip2 was not added to net0 peers
 	wg1.Wait()
 	wg1Done = true
-	assertEqualPeers(t, map[string]ids.ShortID{
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip1.String(): id1,
-	}, net0.Peers([]ids.ShortID{}))
-	assertEqualPeers(t, map[string]ids.ShortID{
+	}, net0.Peers([]ids.NodeID{}))
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip0.String(): id0,
-	}, net1.Peers([]ids.ShortID{}))
-	assert.Len(t, net2.Peers([]ids.ShortID{}), 0)
-	assert.Len(t, net3.Peers([]ids.ShortID{}), 0)
+	}, net1.Peers([]ids.NodeID{}))
+	assert.Len(t, net2.Peers([]ids.NodeID{}), 0)
+	assert.Len(t, net3.Peers([]ids.NodeID{}), 0)
 
 	// Subsequent track call returns immediately with no connection attempts
 	// (would cause fatal error from unauthorized connection if allowed)
@@ -1614,17 +1722,17 @@ func TestPeerAliasesTicker(t *testing.T) {
 
 	// Confirm that id2 was added as peer
 	wg2.Wait()
-	assertEqualPeers(t, map[string]ids.ShortID{
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip1.String(): id1,
 		ip2.String(): id2,
-	}, net0.Peers([]ids.ShortID{}))
-	assertEqualPeers(t, map[string]ids.ShortID{
+	}, net0.Peers([]ids.NodeID{}))
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip0.String(): id0,
-	}, net1.Peers([]ids.ShortID{}))
-	assert.Len(t, net2.Peers([]ids.ShortID{}), 0)
-	assertEqualPeers(t, map[string]ids.ShortID{
+	}, net1.Peers([]ids.NodeID{}))
+	assert.Len(t, net2.Peers([]ids.NodeID{}), 0)
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip0.String(): id0,
-	}, net3.Peers([]ids.ShortID{}))
+	}, net3.Peers([]ids.NodeID{}))
 
 	// Cleanup
 	cleanup = true
@@ -1654,17 +1762,17 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		net.IPv6loopback,
 		0,
 	)
-	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	id0 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
 	ip1 := utils.NewDynamicIPDesc(
 		net.IPv6loopback,
 		1,
 	)
-	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+	id1 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
 	ip2 := utils.NewDynamicIPDesc(
 		net.IPv6loopback,
 		2,
 	)
-	id2 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip2.IP().String())))
+	id2 := ids.NodeID(hashing.ComputeHash160Array([]byte(ip2.IP().String())))
 
 	err := vdrs.AddWeight(id0, 1)
 	if err != nil {
@@ -1749,7 +1857,7 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 	caller3.outbounds[ip0.IP().String()] = listener0
 
 	upgrader := &testUpgrader{
-		ids: map[string]ids.ShortID{
+		ids: map[string]ids.NodeID{
 			ip0.IP().String(): id0,
 			ip1.IP().String(): id1,
 			ip2.IP().String(): id1,
@@ -1780,11 +1888,11 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 
 	handler0 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id == id1 {
+			if id == id1.ShortID() {
 				wg0.Done()
 				return
 			}
-			if id == id2 {
+			if id == id2.ShortID() {
 				wg3.Done()
 				return
 			}
@@ -1795,7 +1903,7 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 			assert.Fail(t, "handler 0 unauthorized connection", id.String())
 		},
 		disconnected: func(id ids.ShortID) {
-			if id == id1 {
+			if id == id1.ShortID() {
 				wg2.Done()
 				return
 			}
@@ -1809,7 +1917,7 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 
 	handler1 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id == id0 {
+			if id == id0.ShortID() {
 				wg0.Done()
 				return
 			}
@@ -1833,7 +1941,7 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 
 	handler3 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id == id0 {
+			if id == id0.ShortID() {
 				wg3.Done()
 				return
 			}
@@ -1904,6 +2012,13 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -1936,6 +2051,13 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -1968,6 +2090,13 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net2)
 
@@ -2000,6 +2129,13 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net3)
 
@@ -2025,14 +2161,14 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 
 	// Confirm peers correct
 	wg0.Wait()
-	assertEqualPeers(t, map[string]ids.ShortID{
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip1.String(): id1,
-	}, net0.Peers([]ids.ShortID{}))
-	assertEqualPeers(t, map[string]ids.ShortID{
+	}, net0.Peers([]ids.NodeID{}))
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip0.String(): id0,
-	}, net1.Peers([]ids.ShortID{}))
-	assert.Len(t, net2.Peers([]ids.ShortID{}), 0)
-	assert.Len(t, net3.Peers([]ids.ShortID{}), 0)
+	}, net1.Peers([]ids.NodeID{}))
+	assert.Len(t, net2.Peers([]ids.NodeID{}), 0)
+	assert.Len(t, net3.Peers([]ids.NodeID{}), 0)
 
 	// Attempt to connect to ip2 (same id as ip1)
 	net0.Track(ip2.IP(), id2)
@@ -2040,14 +2176,14 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 	// Confirm that ip2 was not added to net0 peers
 	wg1.Wait()
 	wg1Done = true
-	assertEqualPeers(t, map[string]ids.ShortID{
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip1.String(): id1,
-	}, net0.Peers([]ids.ShortID{}))
-	assertEqualPeers(t, map[string]ids.ShortID{
+	}, net0.Peers([]ids.NodeID{}))
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip0.String(): id0,
-	}, net1.Peers([]ids.ShortID{}))
-	assert.Len(t, net2.Peers([]ids.ShortID{}), 0)
-	assert.Len(t, net3.Peers([]ids.ShortID{}), 0)
+	}, net1.Peers([]ids.NodeID{}))
+	assert.Len(t, net2.Peers([]ids.NodeID{}), 0)
+	assert.Len(t, net3.Peers([]ids.NodeID{}), 0)
 
 	// Disconnect original peer
 	_ = caller0.clients[ip1.String()].Close()
@@ -2055,28 +2191,28 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 	// Track ip2 on net3
 	wg2.Wait()
 	wg2Done = true
-	assertEqualPeers(t, map[string]ids.ShortID{}, net0.Peers([]ids.ShortID{}))
-	assertEqualPeers(t, map[string]ids.ShortID{
+	assertEqualPeers(t, map[string]ids.NodeID{}, net0.Peers([]ids.NodeID{}))
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip0.String(): id0,
-	}, net1.Peers([]ids.ShortID{}))
-	assert.Len(t, net2.Peers([]ids.ShortID{}), 0)
-	assert.Len(t, net3.Peers([]ids.ShortID{}), 0)
+	}, net1.Peers([]ids.NodeID{}))
+	assert.Len(t, net2.Peers([]ids.NodeID{}), 0)
+	assert.Len(t, net3.Peers([]ids.NodeID{}), 0)
 	upgrader.Update(ip2, id2)
 	caller0.Update(ip2, listener3)
 	net0.Track(ip2.IP(), id2)
 
 	// Confirm that id2 was added as peer
 	wg3.Wait()
-	assertEqualPeers(t, map[string]ids.ShortID{
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip2.String(): id2,
-	}, net0.Peers([]ids.ShortID{}))
-	assertEqualPeers(t, map[string]ids.ShortID{
+	}, net0.Peers([]ids.NodeID{}))
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip0.String(): id0,
-	}, net1.Peers([]ids.ShortID{}))
-	assert.Len(t, net2.Peers([]ids.ShortID{}), 0)
-	assertEqualPeers(t, map[string]ids.ShortID{
+	}, net1.Peers([]ids.NodeID{}))
+	assert.Len(t, net2.Peers([]ids.NodeID{}), 0)
+	assertEqualPeers(t, map[string]ids.NodeID{
 		ip0.String(): id0,
-	}, net3.Peers([]ids.ShortID{}))
+	}, net3.Peers([]ids.NodeID{}))
 
 	// Cleanup
 	cleanup = true
@@ -2183,7 +2319,7 @@ func TestPeerSignature(t *testing.T) {
 	_ = vdrs.Set([]validators.Validator{validators.NewValidator(id2, math.MaxUint64)})
 
 	allPeers := ids.ShortSet{}
-	allPeers.Add(id0, id1, id2)
+	allPeers.Add(id0.ShortID(), id1.ShortID(), id2.ShortID())
 
 	var (
 		wg0 sync.WaitGroup
@@ -2199,9 +2335,9 @@ func TestPeerSignature(t *testing.T) {
 
 	handler0 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id != id0 {
+			if id != id0.ShortID() {
 				handledLock.Lock()
-				handled[id0.String()+":"+id.String()] = struct{}{}
+				handled[id0.String()+":"+ids.NodeIDFromShortID(id).String()] = struct{}{}
 				handledLock.Unlock()
 				wg0.Done()
 			}
@@ -2210,9 +2346,9 @@ func TestPeerSignature(t *testing.T) {
 
 	handler1 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id != id1 {
+			if id != id1.ShortID() {
 				handledLock.Lock()
-				handled[id1.String()+":"+id.String()] = struct{}{}
+				handled[id1.String()+":"+ids.NodeIDFromShortID(id).String()] = struct{}{}
 				handledLock.Unlock()
 				wg1.Done()
 			}
@@ -2221,9 +2357,9 @@ func TestPeerSignature(t *testing.T) {
 
 	handler2 := &testHandler{
 		connected: func(id ids.ShortID) {
-			if id != id2 {
+			if id != id2.ShortID() {
 				handledLock.Lock()
-				handled[id2.String()+":"+id.String()] = struct{}{}
+				handled[id2.String()+":"+ids.NodeIDFromShortID(id).String()] = struct{}{}
 				handledLock.Unlock()
 				wg2.Done()
 			}
@@ -2269,6 +2405,13 @@ func TestPeerSignature(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -2301,6 +2444,13 @@ func TestPeerSignature(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -2333,6 +2483,13 @@ func TestPeerSignature(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net2)
 
@@ -2422,21 +2579,21 @@ func TestValidatorIPs(t *testing.T) {
 		IP:   net.IPv4(172, 17, 0, 1),
 		Port: 1,
 	}
-	firstValidatorPeer := createPeer(ids.ShortID{0x01}, firstValidatorIPDesc, appVersion)
+	firstValidatorPeer := createPeer(ids.NodeID{0x01}, firstValidatorIPDesc, appVersion)
 	addPeerToNetwork(&dummyNetwork, firstValidatorPeer, true)
 
 	secondValidatorIPDesc := utils.IPDesc{
 		IP:   net.IPv4(172, 17, 0, 2),
 		Port: 2,
 	}
-	secondValidatorPeer := createPeer(ids.ShortID{0x02}, secondValidatorIPDesc, appVersion)
+	secondValidatorPeer := createPeer(ids.NodeID{0x02}, secondValidatorIPDesc, appVersion)
 	addPeerToNetwork(&dummyNetwork, secondValidatorPeer, true)
 
 	thirdValidatorIPDesc := utils.IPDesc{
 		IP:   net.IPv4(172, 17, 0, 3),
 		Port: 3,
 	}
-	thirdValidatorPeer := createPeer(ids.ShortID{0x03}, thirdValidatorIPDesc, appVersion)
+	thirdValidatorPeer := createPeer(ids.NodeID{0x03}, thirdValidatorIPDesc, appVersion)
 	addPeerToNetwork(&dummyNetwork, thirdValidatorPeer, true)
 
 	assert.True(t, dummyNetwork.vdrs.Contains(firstValidatorPeer.nodeID))
@@ -2471,7 +2628,7 @@ func TestValidatorIPs(t *testing.T) {
 		IP:   net.IPv4(172, 17, 0, 4),
 		Port: 4,
 	}
-	disconnectedValidatorPeer := createPeer(ids.ShortID{0x01}, disconnectedValidatorIPDesc, appVersion)
+	disconnectedValidatorPeer := createPeer(ids.NodeID{0x01}, disconnectedValidatorIPDesc, appVersion)
 	disconnectedValidatorPeer.finishedHandshake.SetValue(false)
 	addPeerToNetwork(&dummyNetwork, disconnectedValidatorPeer, true)
 	assert.True(t, dummyNetwork.vdrs.Contains(disconnectedValidatorPeer.nodeID))
@@ -2490,7 +2647,7 @@ func TestValidatorIPs(t *testing.T) {
 		IP:   net.IPv4zero,
 		Port: 1,
 	}
-	zeroValidatorPeer := createPeer(ids.ShortID{0x01}, zeroIPValidatorIPDesc, appVersion)
+	zeroValidatorPeer := createPeer(ids.NodeID{0x01}, zeroIPValidatorIPDesc, appVersion)
 	addPeerToNetwork(&dummyNetwork, zeroValidatorPeer, true)
 	assert.True(t, dummyNetwork.vdrs.Contains(zeroValidatorPeer.nodeID))
 
@@ -2509,7 +2666,7 @@ func TestValidatorIPs(t *testing.T) {
 		Port: 5,
 	}
 
-	nonValidatorPeer := createPeer(ids.ShortID{0x04}, nonValidatorIPDesc, appVersion)
+	nonValidatorPeer := createPeer(ids.NodeID{0x04}, nonValidatorIPDesc, appVersion)
 	addPeerToNetwork(&dummyNetwork, nonValidatorPeer, false)
 	assert.False(t, dummyNetwork.vdrs.Contains(nonValidatorPeer.nodeID))
 
@@ -2529,7 +2686,7 @@ func TestValidatorIPs(t *testing.T) {
 		IP:   net.IPv4(172, 17, 0, 6),
 		Port: 6,
 	}
-	maskedValidatorPeer := createPeer(ids.ShortID{0x01}, maskedValidatorIPDesc, maskedVersion)
+	maskedValidatorPeer := createPeer(ids.NodeID{0x01}, maskedValidatorIPDesc, maskedVersion)
 	addPeerToNetwork(&dummyNetwork, maskedValidatorPeer, true)
 	assert.True(t, dummyNetwork.vdrs.Contains(maskedValidatorPeer.nodeID))
 
@@ -2551,7 +2708,7 @@ func TestValidatorIPs(t *testing.T) {
 		IP:   net.IPv4(172, 17, 0, 8),
 		Port: 8,
 	}
-	wrongCertValidatorPeer := createPeer(ids.ShortID{0x01}, wrongCertValidatorIPDesc, appVersion)
+	wrongCertValidatorPeer := createPeer(ids.NodeID{0x01}, wrongCertValidatorIPDesc, appVersion)
 	wrongCertValidatorPeer.sigAndTime.SetValue(signedPeerIP{
 		ip:   ipOnCert,
 		time: uint64(0),
@@ -2577,7 +2734,7 @@ func TestValidatorIPs(t *testing.T) {
 			IP:   net.IPv4(172, 17, 0, byte(i)),
 			Port: uint16(i),
 		}
-		peer := createPeer(ids.ShortID{byte(i)}, ipDesc, appVersion)
+		peer := createPeer(ids.NodeID{byte(i)}, ipDesc, appVersion)
 		addPeerToNetwork(&dummyNetwork, peer, true)
 		assert.True(t, dummyNetwork.vdrs.Contains(peer.nodeID))
 	}
@@ -2709,6 +2866,13 @@ func TestDontFinishHandshakeOnIncompatibleVersion(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -2741,6 +2905,13 @@ func TestDontFinishHandshakeOnIncompatibleVersion(t *testing.T) {
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -2772,7 +2943,7 @@ func TestDontFinishHandshakeOnIncompatibleVersion(t *testing.T) {
 }
 
 // Helper method for TestValidatorIPs
-func createPeer(peerID ids.ShortID, peerIPDesc utils.IPDesc, peerVersion version.Application) *peer {
+func createPeer(peerID ids.NodeID, peerIPDesc utils.IPDesc, peerVersion version.Application) *peer {
 	newPeer := peer{
 		ip:     peerIPDesc,
 		nodeID: peerID,
@@ -2813,3 +2984,43 @@ func isIPDescIn(targetIP utils.IPDesc, ipDescList []utils.IPCertDesc) bool {
 }
 
 // End of Helper method for TestValidatorIPs
+
+func TestStopReconnectingToRemovedValidator(t *testing.T) {
+	assert := assert.New(t)
+
+	vdrID := ids.GenerateTestNodeID()
+	beaconID := ids.GenerateTestNodeID()
+	vdrIP := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	beaconIP := utils.IPDesc{IP: net.IPv4(5, 6, 7, 8), Port: 9651}
+	beacons := validators.NewSet()
+	assert.NoError(beacons.AddWeight(beaconID, 1))
+	n := &network{
+		beacons:      beacons,
+		latestPeerIP: map[ids.NodeID]signedPeerIP{vdrID: {ip: vdrIP}},
+		disconnectedIPs: map[string]struct{}{
+			vdrIP.String():    {},
+			beaconIP.String(): {},
+		},
+		retryDelay: map[string]time.Duration{
+			vdrIP.String(): time.Second,
+		},
+		trackedIPs: map[ids.NodeID]string{
+			vdrID:    vdrIP.String(),
+			beaconID: beaconIP.String(),
+		},
+	}
+
+	// Leaving another subnet doesn't matter
+	n.OnValidatorRemoved(ids.GenerateTestID(), vdrID, 1)
+	assert.Contains(n.disconnectedIPs, vdrIP.String())
+
+	n.OnValidatorRemoved(constants.PrimaryNetworkID, vdrID, 1)
+	assert.NotContains(n.latestPeerIP, vdrID)
+	assert.NotContains(n.trackedIPs, vdrID)
+	assert.NotContains(n.disconnectedIPs, vdrIP.String())
+	assert.NotContains(n.retryDelay, vdrIP.String())
+
+	// Beacons are reconnected to even if they aren't validators
+	n.OnValidatorRemoved(constants.PrimaryNetworkID, beaconID, 1)
+	assert.Contains(n.disconnectedIPs, beaconIP.String())
+}
@@ -13,9 +13,11 @@ import (
 	"sync/atomic"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils"
-	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/timer"
@@ -67,6 +69,11 @@ type peer struct {
 	// Only modified on the connection's reader routine.
 	finishedHandshake utils.AtomicBool
 
+	// True if this peer has sent us a Capabilities message after finishing
+	// the handshake.
+	// Only modified on the connection's reader routine.
+	gotCapabilities utils.AtomicBool
+
 	// only close the peer once
 	once sync.Once
 
@@ -79,6 +86,10 @@ type peer struct {
 	// lock to ensure that closing of the sender queue is handled safely
 	senderLock sync.Mutex
 
+	// signalled, with [senderLock] held, whenever bytes leave the send queue
+	// or the peer is closed
+	sendQueueDrained *sync.Cond
+
 	// queue of messages this connection is attempting to send the peer. Is
 	// closed when the connection is closed.
 	sender chan []byte
@@ -101,7 +112,7 @@ type peer struct {
 	aliasLock sync.Mutex
 
 	// node ID of this peer.
-	nodeID ids.ShortID
+	nodeID ids.NodeID
 
 	// the connection object that is used to read/write messages from
 	conn net.Conn
@@ -114,6 +125,25 @@ type peer struct {
 	// Must only be accessed atomically
 	lastSent, lastReceived int64
 
+	// Unix time, in nanoseconds, of the last Ping sent to this peer. Used to
+	// measure the round trip time when the Pong is received.
+	// Must only be accessed atomically
+	lastPingSent int64
+
+	// Capabilities that this peer reported during the handshake. Peers that
+	// don't send a Capabilities message are assumed to have no optional
+	// capabilities.
+	// Must only be accessed atomically
+	capabilities uint64
+
+	// Reassembles the containers this peer sends us in chunks
+	chunks *chunkAssembler
+
+	// Limits the rate of this peer's GetValidatorSnapshot requests, which
+	// can each require a BLS signature.
+	// Should only be used in peer's reader goroutine.
+	snapshotRequests *rate.Limiter
+
 	tickerCloser chan struct{}
 
 	// ticker processes
@@ -129,7 +159,7 @@ type peer struct {
 	sigAndTime utils.AtomicInterface
 
 	// Used in [handleAcceptedFrontier], [handleAccepted],
-	// [handleGetAccepted], [handleChits].
+	// [handleGetAccepted], [handleAcceptedStateSummary], [handleChits].
 	// We use this one ids.Set rather than allocating one per method call.
 	// Should be cleared before use.
 	// Should only be used in peer's reader goroutine.
@@ -143,8 +173,17 @@ func newPeer(net *network, conn net.Conn, ip utils.IPDesc) *peer {
 		conn:         conn,
 		ip:           ip,
 		tickerCloser: make(chan struct{}),
+		chunks: newChunkAssembler(
+			net.maxChunkedContainerSize,
+			net.maxConcurrentChunkedTransfers,
+			int(net.maxMessageSize)-putChunkOverhead,
+			net.chunkedTransferTimeout,
+			net.chunkBudget,
+		),
+		snapshotRequests: rate.NewLimiter(defaultSnapshotRequestsPerSecond, defaultSnapshotRequestBurst),
 	}
 	p.aliasTimer = timer.NewTimer(p.releaseExpiredAliases)
+	p.sendQueueDrained = sync.NewCond(&p.senderLock)
 
 	return p
 }
@@ -333,6 +372,7 @@ func (p *peer) WriteMessages() {
 		p.senderLock.Lock()
 		atomic.AddInt64(&p.net.pendingBytes, -int64(len(msg)))
 		p.pendingBytes -= int64(len(msg))
+		p.sendQueueDrained.Broadcast()
 		p.senderLock.Unlock()
 
 		now := p.net.clock.Time().Unix()
@@ -439,9 +479,12 @@ func (p *peer) handle(msg Msg) {
 	case PeerList:
 		p.handlePeerList(msg)
 		return
+	case Capabilities:
+		p.handleCapabilities(msg)
+		return
 	}
 	if !p.finishedHandshake.GetValue() {
-		p.net.log.Debug("dropping message from %s%s because handshake isn't finished", constants.NodeIDPrefix, p.nodeID)
+		p.net.log.Debug("dropping message from %s because handshake isn't finished", p.nodeID)
 
 		// attempt to finish the handshake
 		if !p.gotVersion.GetValue() {
@@ -462,6 +505,14 @@ func (p *peer) handle(msg Msg) {
 		p.handleGetAccepted(msg)
 	case Accepted:
 		p.handleAccepted(msg)
+	case GetStateSummaryFrontier:
+		p.handleGetStateSummaryFrontier(msg)
+	case StateSummaryFrontier:
+		p.handleStateSummaryFrontier(msg)
+	case GetAcceptedStateSummary:
+		p.handleGetAcceptedStateSummary(msg)
+	case AcceptedStateSummary:
+		p.handleAcceptedStateSummary(msg)
 	case Get:
 		p.handleGet(msg)
 	case GetAncestors:
@@ -476,11 +527,52 @@ func (p *peer) handle(msg Msg) {
 		p.handlePullQuery(msg)
 	case Chits:
 		p.handleChits(msg)
+	case ChunkedPut:
+		p.handleChunkedPut(msg)
+	case PutChunk:
+		p.handlePutChunk(msg)
+	case CrossSubnet:
+		p.handleCrossSubnet(msg)
+	case GetValidatorSnapshot:
+		p.handleGetValidatorSnapshot(msg)
+	case ValidatorSnapshot:
+		p.handleValidatorSnapshot(msg)
+	case UptimeReport:
+		p.handleUptimeReport(msg)
 	default:
 		p.net.log.Debug("dropping an unknown message from %s with op %s", p.nodeID, op)
 	}
 }
 
+// waitForSendQueue blocks until a message sent to this peer wouldn't be dropped
+// because of the size of its send queue. Returns false if the peer is closed or
+// [deadline] passes first.
+// Assumes the peer's mutex is not held
+func (p *peer) waitForSendQueue(deadline time.Time) bool {
+	// Wake up the wait below once [deadline] passes
+	timeout := time.AfterFunc(time.Until(deadline), func() {
+		p.senderLock.Lock()
+		p.sendQueueDrained.Broadcast()
+		p.senderLock.Unlock()
+	})
+	defer timeout.Stop()
+
+	p.senderLock.Lock()
+	defer p.senderLock.Unlock()
+
+	for {
+		switch {
+		case p.closed.GetValue():
+			return false
+		case !p.dropMessagePeer():
+			return true
+		case !time.Now().Before(deadline):
+			return false
+		}
+		p.sendQueueDrained.Wait()
+	}
+}
+
 // Assumes the peer's mutex is held
 func (p *peer) dropMessagePeer() bool {
 	return p.pendingBytes > p.net.maxMessageSize
@@ -515,7 +607,9 @@ func (p *peer) close() {
 	// has been closed and will therefore not attempt to write on this channel.
 	close(p.sender)
 	atomic.AddInt64(&p.net.pendingBytes, -p.pendingBytes)
+	p.sendQueueDrained.Broadcast()
 	p.senderLock.Unlock()
+	p.chunks.Release()
 	p.net.disconnected(p)
 }
 
@@ -611,11 +705,127 @@ func (p *peer) sendPeerList() {
 	}
 }
 
+// assumes the [stateLock] is not held
+func (p *peer) sendCapabilities() {
+	msg, err := p.net.b.Capabilities(p.net.enabledCapabilities)
+	p.net.log.AssertNoError(err)
+	lenMsg := len(msg.Bytes())
+	sent := p.Send(msg, true)
+	if sent {
+		p.net.capabilities.numSent.Inc()
+		p.net.capabilities.sentBytes.Add(float64(lenMsg))
+		p.net.sendFailRateCalculator.Observe(0, p.net.clock.Time())
+	} else {
+		p.net.capabilities.numFailed.Inc()
+		p.net.sendFailRateCalculator.Observe(1, p.net.clock.Time())
+	}
+}
+
+// requestValidatorSnapshots requests the validator sets that are being synced
+// from this peer, if it is a beacon or a primary network validator that can
+// serve them.
+// assumes the [stateLock] is not held
+func (p *peer) requestValidatorSnapshots() {
+	if p.net.snapshotSyncer == nil ||
+		!p.supports(ValidatorSnapshotCapability) ||
+		!p.canAttestSnapshots() {
+		return
+	}
+	for _, subnetID := range p.net.snapshotSyncer.Pending() {
+		p.sendGetValidatorSnapshot(subnetID)
+	}
+}
+
+// canAttestSnapshots returns true if this peer may be trusted to attest to
+// validator snapshots
+func (p *peer) canAttestSnapshots() bool {
+	return p.net.beacons.Contains(p.nodeID) || p.net.vdrs.Contains(p.nodeID)
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) sendGetValidatorSnapshot(subnetID ids.ID) {
+	msg, err := p.net.b.GetValidatorSnapshot(subnetID)
+	p.net.log.AssertNoError(err)
+	lenMsg := len(msg.Bytes())
+	sent := p.Send(msg, true)
+	if sent {
+		p.net.getValidatorSnapshot.numSent.Inc()
+		p.net.getValidatorSnapshot.sentBytes.Add(float64(lenMsg))
+		p.net.sendFailRateCalculator.Observe(0, p.net.clock.Time())
+	} else {
+		p.net.getValidatorSnapshot.numFailed.Inc()
+		p.net.sendFailRateCalculator.Observe(1, p.net.clock.Time())
+	}
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) sendValidatorSnapshot(signed *validators.SignedSnapshot) {
+	msg, err := p.net.b.ValidatorSnapshot(
+		signed.Snapshot,
+		signed.Registration.PublicKey,
+		signed.Registration.ProofOfPossession,
+		signed.Registration.Signature,
+		signed.Signature,
+	)
+	if err != nil {
+		p.net.log.Warn("failed to build ValidatorSnapshot for %s due to %s", p.nodeID, err)
+		return
+	}
+	lenMsg := len(msg.Bytes())
+	sent := p.Send(msg, true)
+	if sent {
+		p.net.validatorSnapshot.numSent.Inc()
+		p.net.validatorSnapshot.sentBytes.Add(float64(lenMsg))
+		p.net.sendFailRateCalculator.Observe(0, p.net.clock.Time())
+	} else {
+		p.net.validatorSnapshot.numFailed.Inc()
+		p.net.sendFailRateCalculator.Observe(1, p.net.clock.Time())
+	}
+}
+
+// reportUptimes sends this node's latest uptime report to this peer, if it is a
+// primary network validator that exchanges uptime reports.
+// assumes the [stateLock] is not held
+func (p *peer) reportUptimes() {
+	if p.net.uptimeReports == nil ||
+		!p.supports(UptimeReportCapability) ||
+		!p.net.vdrs.Contains(p.nodeID) {
+		return
+	}
+	report, ok := p.net.uptimeReports.Local()
+	if !ok {
+		return
+	}
+
+	validatorIDs := make([]ids.NodeID, 0, len(report.Uptimes))
+	upDurations := make([]uint64, 0, len(report.Uptimes))
+	for validatorID, upDuration := range report.Uptimes {
+		validatorIDs = append(validatorIDs, validatorID)
+		upDurations = append(upDurations, uint64(upDuration))
+	}
+	msg, err := p.net.b.UptimeReport(uint64(report.Start.Unix()), validatorIDs, upDurations)
+	if err != nil {
+		p.net.log.Warn("failed to build UptimeReport for %s due to %s", p.nodeID, err)
+		return
+	}
+	lenMsg := len(msg.Bytes())
+	sent := p.Send(msg, true)
+	if sent {
+		p.net.uptimeReport.numSent.Inc()
+		p.net.uptimeReport.sentBytes.Add(float64(lenMsg))
+		p.net.sendFailRateCalculator.Observe(0, p.net.clock.Time())
+	} else {
+		p.net.uptimeReport.numFailed.Inc()
+		p.net.sendFailRateCalculator.Observe(1, p.net.clock.Time())
+	}
+}
+
 // assumes the [stateLock] is not held
 func (p *peer) sendPing() {
 	msg, err := p.net.b.Ping()
 	p.net.log.AssertNoError(err)
 	lenMsg := len(msg.Bytes())
+	atomic.StoreInt64(&p.lastPingSent, p.net.clock.Time().UnixNano())
 	sent := p.Send(msg, true)
 	if sent {
 		p.net.ping.numSent.Inc()
@@ -682,6 +892,7 @@ func (p *peer) handleVersion(msg Msg) {
 				uint64(peerTime),
 				uint64(myTime))
 		}
+		p.net.penalize(p.nodeID)
 		p.discardIP()
 		return
 	}
@@ -690,6 +901,7 @@ func (p *peer) handleVersion(msg Msg) {
 	peerVersion, err := p.net.parser.Parse(peerVersionStr)
 	if err != nil {
 		p.net.log.Debug("peer version could not be parsed: %s", err)
+		p.net.penalize(p.nodeID)
 		p.discardIP()
 		return
 	}
@@ -708,6 +920,7 @@ func (p *peer) handleVersion(msg Msg) {
 
 	if err := p.net.versionCompatibility.Compatible(peerVersion); err != nil {
 		p.net.log.Verbo("peer version (%s) not compatible: %s", peerVersion, err)
+		p.net.penalize(p.nodeID)
 		p.discardIP()
 		return
 	}
@@ -736,6 +949,7 @@ func (p *peer) handleVersion(msg Msg) {
 	err = p.cert.CheckSignature(p.cert.SignatureAlgorithm, signed, sig)
 	if err != nil {
 		p.net.log.Debug("signature verification failed for peer at %s: %s", peerIP, err)
+		p.net.penalize(p.nodeID)
 		p.discardIP()
 		return
 	}
@@ -766,6 +980,7 @@ func (p *peer) handleVersion(msg Msg) {
 	}
 
 	p.sendPeerList()
+	p.sendCapabilities()
 
 	p.versionStruct.SetValue(peerVersion)
 	p.versionStr.SetValue(peerVersion.String())
@@ -803,7 +1018,7 @@ func (p *peer) trackSignedPeer(peer utils.IPCertDesc) {
 	if !p.net.vdrs.Contains(nodeID) {
 		p.net.log.Verbo(
 			"not peering to %s at %s because they are not a validator",
-			nodeID.PrefixedString(constants.NodeIDPrefix),
+			nodeID.String(),
 			peer.IPDesc,
 		)
 		return
@@ -815,7 +1030,7 @@ func (p *peer) trackSignedPeer(peer utils.IPCertDesc) {
 		p.net.log.Verbo(
 			"not peering to %s because we are already connected to %s",
 			peer.IPDesc,
-			nodeID.PrefixedString(constants.NodeIDPrefix),
+			nodeID.String(),
 		)
 		return
 	}
@@ -823,7 +1038,7 @@ func (p *peer) trackSignedPeer(peer utils.IPCertDesc) {
 	if p.net.latestPeerIP[nodeID].time > peer.Time {
 		p.net.log.Verbo(
 			"not peering to %s at %s: the given timestamp (%d) < latest (%d)",
-			nodeID.PrefixedString(constants.NodeIDPrefix),
+			nodeID.String(),
 			peer.IPDesc,
 			peer.Time,
 			p.net.latestPeerIP[nodeID].time,
@@ -836,7 +1051,7 @@ func (p *peer) trackSignedPeer(peer utils.IPCertDesc) {
 	if err != nil {
 		p.net.log.Debug(
 			"signature verification failed for %s at %s: %s",
-			nodeID.PrefixedString(constants.NodeIDPrefix),
+			nodeID.String(),
 			peer.IPDesc,
 			err,
 		)
@@ -867,13 +1082,58 @@ func (p *peer) handlePeerList(msg Msg) {
 	}
 }
 
+// assumes the [stateLock] is not held
+func (p *peer) handleCapabilities(msg Msg) {
+	// Capabilities are sent after the PeerList, so a peer that advertises
+	// them before the handshake has finished is misbehaving.
+	if !p.finishedHandshake.GetValue() {
+		p.net.log.Debug("dropping Capabilities from %s because handshake isn't finished", p.nodeID)
+		return
+	}
+
+	// Capabilities are only negotiated once per connection, so the actions
+	// that depend on them are only triggered once.
+	if p.gotCapabilities.GetValue() {
+		p.net.log.Debug("dropping duplicate Capabilities from %s", p.nodeID)
+		return
+	}
+	p.gotCapabilities.SetValue(true)
+
+	capabilities := Capability(msg.Get(CapabilityFlags).(uint64))
+	atomic.StoreUint64(&p.capabilities, uint64(capabilities))
+	p.net.log.Verbo("peer %s reported capabilities [%s]", p.nodeID, capabilities)
+
+	p.requestValidatorSnapshots()
+	p.reportUptimes()
+}
+
+// getCapabilities returns the capabilities this peer reported.
+func (p *peer) getCapabilities() Capability {
+	return Capability(atomic.LoadUint64(&p.capabilities))
+}
+
+// supports returns true if both this node and the peer have the features in
+// [capability] enabled.
+func (p *peer) supports(capability Capability) bool {
+	return p.net.enabledCapabilities.Intersect(p.getCapabilities()).Contains(capability)
+}
+
 // assumes the [stateLock] is not held
 func (p *peer) handlePing(_ Msg) {
 	p.sendPong()
 }
 
 // assumes the [stateLock] is not held
-func (p *peer) handlePong(_ Msg) {}
+func (p *peer) handlePong(_ Msg) {
+	lastPingSent := atomic.SwapInt64(&p.lastPingSent, 0)
+	if lastPingSent == 0 || !p.finishedHandshake.GetValue() {
+		return
+	}
+	latency := p.net.clock.Time().Sub(time.Unix(0, lastPingSent))
+	if err := p.net.peerStore.ObserveLatency(p.nodeID, latency); err != nil {
+		p.net.log.Warn("failed to store latency of %s due to %s", p.nodeID, err)
+	}
+}
 
 // assumes the [stateLock] is not held
 func (p *peer) handleGetAcceptedFrontier(msg Msg) {
@@ -882,7 +1142,7 @@ func (p *peer) handleGetAcceptedFrontier(msg Msg) {
 	requestID := msg.Get(RequestID).(uint32)
 	deadline := p.net.clock.Time().Add(time.Duration(msg.Get(Deadline).(uint64)))
 
-	p.net.router.GetAcceptedFrontier(p.nodeID, chainID, requestID, deadline)
+	p.net.router.GetAcceptedFrontier(p.nodeID.ShortID(), chainID, requestID, deadline)
 }
 
 // assumes the [stateLock] is not held
@@ -908,7 +1168,7 @@ func (p *peer) handleAcceptedFrontier(msg Msg) {
 		p.idSet.Add(containerID)
 	}
 
-	p.net.router.AcceptedFrontier(p.nodeID, chainID, requestID, containerIDs)
+	p.net.router.AcceptedFrontier(p.nodeID.ShortID(), chainID, requestID, containerIDs)
 }
 
 // assumes the [stateLock] is not held
@@ -935,7 +1195,7 @@ func (p *peer) handleGetAccepted(msg Msg) {
 		p.idSet.Add(containerID)
 	}
 
-	p.net.router.GetAccepted(p.nodeID, chainID, requestID, deadline, containerIDs)
+	p.net.router.GetAccepted(p.nodeID.ShortID(), chainID, requestID, deadline, containerIDs)
 }
 
 // assumes the [stateLock] is not held
@@ -961,7 +1221,64 @@ func (p *peer) handleAccepted(msg Msg) {
 		p.idSet.Add(containerID)
 	}
 
-	p.net.router.Accepted(p.nodeID, chainID, requestID, containerIDs)
+	p.net.router.Accepted(p.nodeID.ShortID(), chainID, requestID, containerIDs)
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleGetStateSummaryFrontier(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	deadline := p.net.clock.Time().Add(time.Duration(msg.Get(Deadline).(uint64)))
+
+	p.net.router.GetStateSummaryFrontier(p.nodeID.ShortID(), chainID, requestID, deadline)
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleStateSummaryFrontier(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	summary := msg.Get(ContainerBytes).([]byte)
+
+	p.net.router.StateSummaryFrontier(p.nodeID.ShortID(), chainID, requestID, summary)
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleGetAcceptedStateSummary(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	deadline := p.net.clock.Time().Add(time.Duration(msg.Get(Deadline).(uint64)))
+	heights := msg.Get(SummaryHeights).([]uint64)
+
+	p.net.router.GetAcceptedStateSummary(p.nodeID.ShortID(), chainID, requestID, deadline, heights)
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleAcceptedStateSummary(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+
+	summaryIDsBytes := msg.Get(ContainerIDs).([][]byte)
+	summaryIDs := make([]ids.ID, len(summaryIDsBytes))
+	p.idSet.Clear()
+	for i, summaryIDBytes := range summaryIDsBytes {
+		summaryID, err := ids.ToID(summaryIDBytes)
+		if err != nil {
+			p.net.log.Debug("error parsing summary ID 0x%x: %s", summaryIDBytes, err)
+			return
+		}
+		if p.idSet.Contains(summaryID) {
+			p.net.log.Debug("message contains duplicate of summary ID %s", summaryID)
+			return
+		}
+		summaryIDs[i] = summaryID
+		p.idSet.Add(summaryID)
+	}
+
+	p.net.router.AcceptedStateSummary(p.nodeID.ShortID(), chainID, requestID, summaryIDs)
 }
 
 // assumes the [stateLock] is not held
@@ -973,7 +1290,7 @@ func (p *peer) handleGet(msg Msg) {
 	containerID, err := ids.ToID(msg.Get(ContainerID).([]byte))
 	p.net.log.AssertNoError(err)
 
-	p.net.router.Get(p.nodeID, chainID, requestID, deadline, containerID)
+	p.net.router.Get(p.nodeID.ShortID(), chainID, requestID, deadline, containerID)
 }
 
 func (p *peer) handleGetAncestors(msg Msg) {
@@ -984,7 +1301,7 @@ func (p *peer) handleGetAncestors(msg Msg) {
 	containerID, err := ids.ToID(msg.Get(ContainerID).([]byte))
 	p.net.log.AssertNoError(err)
 
-	p.net.router.GetAncestors(p.nodeID, chainID, requestID, deadline, containerID)
+	p.net.router.GetAncestors(p.nodeID.ShortID(), chainID, requestID, deadline, containerID)
 }
 
 // assumes the [stateLock] is not held
@@ -996,7 +1313,208 @@ func (p *peer) handlePut(msg Msg) {
 	p.net.log.AssertNoError(err)
 	container := msg.Get(ContainerBytes).([]byte)
 
-	p.net.router.Put(p.nodeID, chainID, requestID, containerID, container)
+	p.net.router.Put(p.nodeID.ShortID(), chainID, requestID, containerID, container)
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleChunkedPut(msg Msg) {
+	if !p.supports(ChunkedTransferCapability) {
+		p.net.log.Debug("dropping ChunkedPut from %s because chunked transfers weren't negotiated", p.nodeID)
+		return
+	}
+
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	containerID, err := ids.ToID(msg.Get(ContainerID).([]byte))
+	p.net.log.AssertNoError(err)
+
+	deadline := msg.Get(Deadline).(uint64)
+	op := Op(msg.Get(ChunkedOp).(uint8))
+
+	// Buffering chunks takes memory, so only validators may send us
+	// containers in chunks unprompted. Other peers may only reply to our
+	// requests.
+	if !p.net.vdrs.Contains(p.nodeID) {
+		request := containerRequestID{
+			nodeID:    p.nodeID,
+			chainID:   chainID,
+			requestID: requestID,
+		}
+		if op == PushQuery || !p.net.containerRequests.Remove(request, p.net.clock.Time()) {
+			p.net.log.Debug("dropping unrequested ChunkedPut(%s, %s, %d, %s, %s)",
+				p.nodeID,
+				chainID,
+				requestID,
+				containerID,
+				op)
+			return
+		}
+	}
+
+	chunkHashesBytes := msg.Get(ChunkHashes).([][]byte)
+	chunkHashes := make([]ids.ID, len(chunkHashesBytes))
+	for i, chunkHashBytes := range chunkHashesBytes {
+		chunkHash, err := ids.ToID(chunkHashBytes)
+		if err != nil {
+			p.net.log.Debug("error parsing chunk hash %v: %s", chunkHashBytes, err)
+			return
+		}
+		chunkHashes[i] = chunkHash
+	}
+
+	transferID := chunkedTransferID{
+		chainID:     chainID,
+		requestID:   requestID,
+		containerID: containerID,
+	}
+	if err := p.chunks.Start(transferID, op, deadline, chunkHashes, p.net.clock.Time()); err != nil {
+		p.net.log.Debug("dropping ChunkedPut(%s, %s, %d, %s, %s) due to %s",
+			p.nodeID,
+			chainID,
+			requestID,
+			containerID,
+			op,
+			err)
+	}
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handlePutChunk(msg Msg) {
+	if !p.supports(ChunkedTransferCapability) {
+		p.net.log.Debug("dropping PutChunk from %s because chunked transfers weren't negotiated", p.nodeID)
+		return
+	}
+
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	containerID, err := ids.ToID(msg.Get(ContainerID).([]byte))
+	p.net.log.AssertNoError(err)
+	chunkIndex := msg.Get(ChunkIndex).(uint32)
+	chunk := msg.Get(ContainerBytes).([]byte)
+
+	transferID := chunkedTransferID{
+		chainID:     chainID,
+		requestID:   requestID,
+		containerID: containerID,
+	}
+	chunked, done, err := p.chunks.Add(transferID, chunkIndex, chunk, p.net.clock.Time())
+	if err != nil {
+		p.net.log.Debug("dropping PutChunk(%s, %s, %d, %s, %d) due to %s",
+			p.nodeID,
+			chainID,
+			requestID,
+			containerID,
+			chunkIndex,
+			err)
+		return
+	}
+	if !done {
+		return
+	}
+
+	switch chunked.op {
+	case Put:
+		p.net.router.Put(p.nodeID.ShortID(), chainID, requestID, containerID, chunked.container)
+	case PushQuery:
+		p.net.router.PushQuery(p.nodeID.ShortID(), chainID, requestID, chunked.deadline, containerID, chunked.container)
+	case MultiPut:
+		p.net.router.MultiPut(p.nodeID.ShortID(), chainID, requestID, [][]byte{chunked.container})
+	}
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleCrossSubnet(msg Msg) {
+	sourceChainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	destinationChainID, err := ids.ToID(msg.Get(DestinationChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	originID, err := ids.ToShortID(msg.Get(OriginID).([]byte))
+	p.net.log.AssertNoError(err)
+	msgBytes := msg.Get(ContainerBytes).([]byte)
+	signature := msg.Get(BLSSignature).([]byte)
+
+	p.net.router.CrossSubnet(p.nodeID.ShortID(), sourceChainID, destinationChainID, originID, msgBytes, signature)
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleGetValidatorSnapshot(msg Msg) {
+	if p.net.snapshotSyncer == nil {
+		return
+	}
+	subnetID, err := ids.ToID(msg.Get(SubnetID).([]byte))
+	p.net.log.AssertNoError(err)
+
+	if !p.snapshotRequests.Allow() {
+		p.net.log.Debug("dropping GetValidatorSnapshot(%s) from %s because it is sending too many", subnetID, p.nodeID)
+		return
+	}
+	signed, ok := p.net.snapshotSyncer.Sign(subnetID)
+	if !ok {
+		p.net.log.Debug("dropping GetValidatorSnapshot(%s) from %s because the validator set is unknown", subnetID, p.nodeID)
+		return
+	}
+	p.sendValidatorSnapshot(signed)
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleValidatorSnapshot(msg Msg) {
+	if p.net.snapshotSyncer == nil {
+		return
+	}
+	signed := &validators.SignedSnapshot{
+		Snapshot: msg.Get(ContainerBytes).([]byte),
+		Registration: validators.BLSKeyRegistration{
+			// The peer's key is registered by the certificate it connected
+			// with
+			Certificate:       p.cert.Raw,
+			PublicKey:         msg.Get(BLSPublicKey).([]byte),
+			ProofOfPossession: msg.Get(BLSProofOfPossession).([]byte),
+			Signature:         msg.Get(BLSKeySignature).([]byte),
+		},
+		Signature: msg.Get(BLSSignature).([]byte),
+	}
+	cert, err := p.net.snapshotSyncer.Add(p.nodeID, signed)
+	if err != nil {
+		p.net.log.Debug("failed to add validator snapshot from %s due to %s", p.nodeID, err)
+		return
+	}
+	if cert != nil {
+		p.net.log.Info("synced the validator set and %d chains of subnet %s, attested to by %d validators",
+			len(cert.Snapshot.Chains),
+			cert.Snapshot.SubnetID,
+			len(cert.Signers))
+	}
+}
+
+// assumes the [stateLock] is not held
+func (p *peer) handleUptimeReport(msg Msg) {
+	if p.net.uptimeReports == nil {
+		return
+	}
+	validatorIDs := msg.Get(ValidatorIDs).([][]byte)
+	upDurations := msg.Get(UpDurations).([]uint64)
+	if len(validatorIDs) != len(upDurations) {
+		p.net.log.Debug("dropping UptimeReport from %s because it has %d validators but %d up durations",
+			p.nodeID,
+			len(validatorIDs),
+			len(upDurations))
+		return
+	}
+
+	report := validators.UptimeReport{
+		Start:   time.Unix(int64(msg.Get(BucketStart).(uint64)), 0),
+		Uptimes: make(map[ids.NodeID]time.Duration, len(validatorIDs)),
+	}
+	for i, validatorIDBytes := range validatorIDs {
+		validatorID, err := ids.ToNodeID(validatorIDBytes)
+		p.net.log.AssertNoError(err)
+		report.Uptimes[validatorID] = time.Duration(upDurations[i])
+	}
+	if err := p.net.uptimeReports.Add(p.nodeID, report); err != nil {
+		p.net.log.Debug("failed to add uptime report from %s due to %s", p.nodeID, err)
+	}
 }
 
 // assumes the [stateLock] is not held
@@ -1006,7 +1524,7 @@ func (p *peer) handleMultiPut(msg Msg) {
 	requestID := msg.Get(RequestID).(uint32)
 	containers := msg.Get(MultiContainerBytes).([][]byte)
 
-	p.net.router.MultiPut(p.nodeID, chainID, requestID, containers)
+	p.net.router.MultiPut(p.nodeID.ShortID(), chainID, requestID, containers)
 }
 
 // assumes the [stateLock] is not held
@@ -1019,7 +1537,7 @@ func (p *peer) handlePushQuery(msg Msg) {
 	p.net.log.AssertNoError(err)
 	container := msg.Get(ContainerBytes).([]byte)
 
-	p.net.router.PushQuery(p.nodeID, chainID, requestID, deadline, containerID, container)
+	p.net.router.PushQuery(p.nodeID.ShortID(), chainID, requestID, deadline, containerID, container)
 }
 
 // assumes the [stateLock] is not held
@@ -1031,7 +1549,7 @@ func (p *peer) handlePullQuery(msg Msg) {
 	containerID, err := ids.ToID(msg.Get(ContainerID).([]byte))
 	p.net.log.AssertNoError(err)
 
-	p.net.router.PullQuery(p.nodeID, chainID, requestID, deadline, containerID)
+	p.net.router.PullQuery(p.nodeID.ShortID(), chainID, requestID, deadline, containerID)
 }
 
 // assumes the [stateLock] is not held
@@ -1057,7 +1575,7 @@ func (p *peer) handleChits(msg Msg) {
 		p.idSet.Add(containerID)
 	}
 
-	p.net.router.Chits(p.nodeID, chainID, requestID, containerIDs)
+	p.net.router.Chits(p.nodeID.ShortID(), chainID, requestID, containerIDs)
 }
 
 // assumes the [stateLock] is held
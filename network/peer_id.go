@@ -18,4 +18,5 @@ type PeerID struct {
 	LastSent     time.Time `json:"lastSent"`
 	LastReceived time.Time `json:"lastReceived"`
 	Benched      []ids.ID  `json:"benched"`
+	Capabilities []string  `json:"capabilities"`
 }
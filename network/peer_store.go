@@ -0,0 +1,324 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"math"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/codec/reflectcodec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+const (
+	peerStoreCodecVersion = 0
+
+	// DefaultPeerStoreSize is the default number of peers remembered across
+	// restarts.
+	DefaultPeerStoreSize = 1024
+
+	// Reputation changes applied by the network. A peer is rewarded for every
+	// full [connectedReputationPeriod] it stays connected, so reconnecting
+	// doesn't improve its reputation.
+	connectedReputationReward     = 1
+	connectedReputationPeriod     = time.Hour
+	badHandshakeReputationPenalty = -5
+
+	// Bounds of a peer's reputation
+	maxReputation = 100
+	minReputation = -100
+
+	// While a peer isn't connected, its reputation decays towards 0, halving
+	// every [reputationHalfLife]
+	reputationHalfLife = 7 * 24 * time.Hour
+
+	// Weight given to a new latency observation in the latency moving average
+	latencyObservationWeight = 0.2
+)
+
+var (
+	peerStoreCodec codec.Manager
+
+	_ PeerStore = &peerStore{}
+	_ PeerStore = &noPeerStore{}
+)
+
+func init() {
+	lc := linearcodec.New(reflectcodec.DefaultTagName, math.MaxUint32)
+	peerStoreCodec = codec.NewManager(math.MaxUint32)
+
+	if err := peerStoreCodec.RegisterCodec(peerStoreCodecVersion, lc); err != nil {
+		panic(err)
+	}
+}
+
+// PeerRecord is the information remembered about a peer across restarts.
+type PeerRecord struct {
+	NodeID ids.NodeID `serialize:"true"`
+	IP     []byte     `serialize:"true"`
+	Port   uint16     `serialize:"true"`
+	// Version string the peer reported the last time we connected to it
+	Version string `serialize:"true"`
+	// Unix time, in seconds, we last had a connection with this peer
+	LastSeen uint64 `serialize:"true"`
+	// Moving average of the peer's ping round trip time, in nanoseconds
+	Latency int64 `serialize:"true"`
+	// Higher is better, as of [LastSeen]. Increased while the peer stays
+	// connected and decreased when the peer misbehaves during the handshake.
+	Reputation int64 `serialize:"true"`
+}
+
+// IPDesc returns the IP this peer was last reachable at.
+func (r *PeerRecord) IPDesc() utils.IPDesc {
+	return utils.IPDesc{
+		IP:   net.IP(r.IP),
+		Port: r.Port,
+	}
+}
+
+// PeerStore persists the peers this node has connected to, so that they can
+// be used as reconnection targets after a restart.
+type PeerStore interface {
+	// Connected records that a connection to [nodeID] at [ip] was established.
+	Connected(nodeID ids.NodeID, ip utils.IPDesc, version string, now time.Time) error
+
+	// Disconnected records that the connection to [nodeID] was closed.
+	Disconnected(nodeID ids.NodeID, now time.Time) error
+
+	// ObserveLatency records a round trip time to [nodeID].
+	ObserveLatency(nodeID ids.NodeID, latency time.Duration) error
+
+	// ChangeReputation records that [nodeID] was seen at [now] and adds
+	// [delta] to its reputation. Peers without a record are ignored.
+	ChangeReputation(nodeID ids.NodeID, delta int64, now time.Time) error
+
+	// Get returns the record of [nodeID], if there is one.
+	Get(nodeID ids.NodeID) (PeerRecord, bool)
+
+	// ReconnectTargets returns at most [max] peers with a non-negative
+	// reputation at [now]. Peers with a higher reputation are returned first,
+	// and ties are broken by the most recently seen peer.
+	ReconnectTargets(max int, now time.Time) []PeerRecord
+}
+
+type peerStore struct {
+	lock sync.Mutex
+
+	db      database.Database
+	maxSize int
+	records map[ids.NodeID]*PeerRecord
+	// node ID --> time the peer connected at, for the peers that are
+	// currently connected
+	connectedSince map[ids.NodeID]time.Time
+}
+
+// NewPeerStore returns a PeerStore that persists at most [maxSize] peers in
+// [db]. When the store is full, the peer with the worst reputation is evicted.
+func NewPeerStore(db database.Database, maxSize int) (PeerStore, error) {
+	s := &peerStore{
+		db:             db,
+		maxSize:        maxSize,
+		records:        make(map[ids.NodeID]*PeerRecord),
+		connectedSince: make(map[ids.NodeID]time.Time),
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		record := &PeerRecord{}
+		if _, err := peerStoreCodec.Unmarshal(it.Value(), record); err != nil {
+			return nil, err
+		}
+		s.records[record.NodeID] = record
+	}
+	return s, it.Error()
+}
+
+func (s *peerStore) Connected(nodeID ids.NodeID, ip utils.IPDesc, version string, now time.Time) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	record, ok := s.records[nodeID]
+	if !ok {
+		if err := s.makeRoom(now); err != nil {
+			return err
+		}
+		record = &PeerRecord{NodeID: nodeID}
+		s.records[nodeID] = record
+	}
+	s.see(record, now)
+	if _, connected := s.connectedSince[nodeID]; !connected {
+		s.connectedSince[nodeID] = now
+	}
+	record.IP = ip.IP
+	record.Port = ip.Port
+	record.Version = version
+	return s.put(record)
+}
+
+func (s *peerStore) Disconnected(nodeID ids.NodeID, now time.Time) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	record, ok := s.records[nodeID]
+	if !ok {
+		return nil
+	}
+	if connectedAt, connected := s.connectedSince[nodeID]; connected {
+		periods := int64(now.Sub(connectedAt) / connectedReputationPeriod)
+		record.Reputation = clampReputation(record.Reputation + periods*connectedReputationReward)
+		delete(s.connectedSince, nodeID)
+	}
+	record.LastSeen = uint64(now.Unix())
+	return s.put(record)
+}
+
+func (s *peerStore) ObserveLatency(nodeID ids.NodeID, latency time.Duration) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	record, ok := s.records[nodeID]
+	if !ok {
+		return nil
+	}
+	if record.Latency == 0 {
+		record.Latency = int64(latency)
+	} else {
+		record.Latency = int64((1-latencyObservationWeight)*float64(record.Latency) +
+			latencyObservationWeight*float64(latency))
+	}
+	return s.put(record)
+}
+
+func (s *peerStore) ChangeReputation(nodeID ids.NodeID, delta int64, now time.Time) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	record, ok := s.records[nodeID]
+	if !ok {
+		return nil
+	}
+	s.see(record, now)
+	record.Reputation = clampReputation(record.Reputation + delta)
+	return s.put(record)
+}
+
+func (s *peerStore) Get(nodeID ids.NodeID) (PeerRecord, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	record, ok := s.records[nodeID]
+	if !ok {
+		return PeerRecord{}, false
+	}
+	return *record, true
+}
+
+func (s *peerStore) ReconnectTargets(max int, now time.Time) []PeerRecord {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	records := s.sorted(now)
+	targets := make([]PeerRecord, 0, max)
+	for _, record := range records {
+		if len(targets) >= max || record.Reputation < 0 {
+			break
+		}
+		targets = append(targets, record)
+	}
+	return targets
+}
+
+// see applies the decay of [record]'s reputation up to [now] and records that
+// the peer was seen at [now].
+// Assumes [s.lock] is held.
+func (s *peerStore) see(record *PeerRecord, now time.Time) {
+	record.Reputation = s.reputation(record, now)
+	record.LastSeen = uint64(now.Unix())
+}
+
+// reputation returns the reputation of [record] at [now]. The reputation of a
+// connected peer doesn't decay.
+// Assumes [s.lock] is held.
+func (s *peerStore) reputation(record *PeerRecord, now time.Time) int64 {
+	if _, connected := s.connectedSince[record.NodeID]; connected {
+		return record.Reputation
+	}
+	away := now.Sub(time.Unix(int64(record.LastSeen), 0))
+	if away <= 0 {
+		return record.Reputation
+	}
+	halvings := float64(away) / float64(reputationHalfLife)
+	return int64(float64(record.Reputation) * math.Pow(0.5, halvings))
+}
+
+// sorted returns copies of the records, with their reputation at [now],
+// ordered from best to worst.
+// Assumes [s.lock] is held.
+func (s *peerStore) sorted(now time.Time) []PeerRecord {
+	records := make([]PeerRecord, 0, len(s.records))
+	for _, record := range s.records {
+		current := *record
+		current.Reputation = s.reputation(record, now)
+		records = append(records, current)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Reputation != records[j].Reputation {
+			return records[i].Reputation > records[j].Reputation
+		}
+		return records[i].LastSeen > records[j].LastSeen
+	})
+	return records
+}
+
+// makeRoom evicts the worst peer if the store is full.
+// Assumes [s.lock] is held.
+func (s *peerStore) makeRoom(now time.Time) error {
+	if len(s.records) < s.maxSize || len(s.records) == 0 {
+		return nil
+	}
+	records := s.sorted(now)
+	worst := records[len(records)-1].NodeID
+	delete(s.records, worst)
+	delete(s.connectedSince, worst)
+	return s.db.Delete(worst[:])
+}
+
+// Assumes [s.lock] is held.
+func (s *peerStore) put(record *PeerRecord) error {
+	bytes, err := peerStoreCodec.Marshal(peerStoreCodecVersion, record)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(record.NodeID[:], bytes)
+}
+
+func clampReputation(reputation int64) int64 {
+	switch {
+	case reputation > maxReputation:
+		return maxReputation
+	case reputation < minReputation:
+		return minReputation
+	default:
+		return reputation
+	}
+}
+
+// noPeerStore is used when the network wasn't given a PeerStore.
+type noPeerStore struct{}
+
+func (noPeerStore) Connected(ids.NodeID, utils.IPDesc, string, time.Time) error { return nil }
+func (noPeerStore) Disconnected(ids.NodeID, time.Time) error                    { return nil }
+func (noPeerStore) ObserveLatency(ids.NodeID, time.Duration) error              { return nil }
+func (noPeerStore) ChangeReputation(ids.NodeID, int64, time.Time) error         { return nil }
+func (noPeerStore) Get(ids.NodeID) (PeerRecord, bool)                           { return PeerRecord{}, false }
+func (noPeerStore) ReconnectTargets(int, time.Time) []PeerRecord                { return nil }
@@ -0,0 +1,188 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+func TestPeerStorePersists(t *testing.T) {
+	db := memdb.New()
+	s, err := NewPeerStore(db, DefaultPeerStoreSize)
+	assert.NoError(t, err)
+
+	nodeID := ids.NodeID{1}
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	now := time.Unix(1000, 0)
+
+	assert.NoError(t, s.Connected(nodeID, ip, "avalanche/1.4.10", now))
+	assert.NoError(t, s.ObserveLatency(nodeID, 100*time.Millisecond))
+	assert.NoError(t, s.Disconnected(nodeID, now.Add(2*connectedReputationPeriod)))
+
+	// Reload the store from the database
+	s, err = NewPeerStore(db, DefaultPeerStoreSize)
+	assert.NoError(t, err)
+
+	record, ok := s.Get(nodeID)
+	assert.True(t, ok)
+	assert.True(t, ip.Equal(record.IPDesc()))
+	assert.Equal(t, "avalanche/1.4.10", record.Version)
+	assert.Equal(t, uint64(now.Add(2*connectedReputationPeriod).Unix()), record.LastSeen)
+	assert.Equal(t, int64(100*time.Millisecond), record.Latency)
+	assert.Equal(t, int64(2*connectedReputationReward), record.Reputation)
+}
+
+func TestPeerStoreReconnectTargets(t *testing.T) {
+	s, err := NewPeerStore(memdb.New(), DefaultPeerStoreSize)
+	assert.NoError(t, err)
+
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	good := ids.NodeID{1}
+	recent := ids.NodeID{2}
+	old := ids.NodeID{3}
+	bad := ids.NodeID{4}
+
+	assert.NoError(t, s.Connected(good, ip, "", time.Unix(1, 0)))
+	assert.NoError(t, s.Disconnected(good, time.Unix(1, 0).Add(connectedReputationPeriod)))
+	assert.NoError(t, s.Connected(recent, ip, "", time.Unix(3, 0)))
+	assert.NoError(t, s.Connected(old, ip, "", time.Unix(2, 0)))
+	assert.NoError(t, s.Connected(bad, ip, "", time.Unix(4, 0)))
+	assert.NoError(t, s.ChangeReputation(bad, badHandshakeReputationPenalty, time.Unix(5, 0)))
+
+	now := time.Unix(10, 0)
+	targets := s.ReconnectTargets(10, now)
+	assert.Len(t, targets, 3)
+	assert.Equal(t, good, targets[0].NodeID)
+	assert.Equal(t, recent, targets[1].NodeID)
+	assert.Equal(t, old, targets[2].NodeID)
+
+	targets = s.ReconnectTargets(1, now)
+	assert.Len(t, targets, 1)
+	assert.Equal(t, good, targets[0].NodeID)
+}
+
+func TestPeerStoreEvictsWorst(t *testing.T) {
+	s, err := NewPeerStore(memdb.New(), 2)
+	assert.NoError(t, err)
+
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	first := ids.NodeID{1}
+	second := ids.NodeID{2}
+	third := ids.NodeID{3}
+
+	assert.NoError(t, s.Connected(first, ip, "", time.Unix(1, 0)))
+	assert.NoError(t, s.Connected(second, ip, "", time.Unix(2, 0)))
+	assert.NoError(t, s.Connected(third, ip, "", time.Unix(3, 0)))
+
+	_, ok := s.Get(first)
+	assert.False(t, ok)
+	_, ok = s.Get(second)
+	assert.True(t, ok)
+	_, ok = s.Get(third)
+	assert.True(t, ok)
+}
+
+func TestPeerStoreIgnoresUnknownPenalties(t *testing.T) {
+	db := memdb.New()
+	s, err := NewPeerStore(db, DefaultPeerStoreSize)
+	assert.NoError(t, err)
+
+	nodeID := ids.NodeID{1}
+	assert.NoError(t, s.ChangeReputation(nodeID, badHandshakeReputationPenalty, time.Unix(1, 0)))
+
+	_, ok := s.Get(nodeID)
+	assert.False(t, ok)
+
+	// Reload the store from the database
+	s, err = NewPeerStore(db, DefaultPeerStoreSize)
+	assert.NoError(t, err)
+
+	_, ok = s.Get(nodeID)
+	assert.False(t, ok)
+}
+
+func TestPeerStoreRewardsStayingConnected(t *testing.T) {
+	s, err := NewPeerStore(memdb.New(), DefaultPeerStoreSize)
+	assert.NoError(t, err)
+
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	nodeID := ids.NodeID{1}
+	now := time.Unix(1000, 0)
+
+	// Reconnecting isn't rewarded
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, s.Connected(nodeID, ip, "", now))
+		now = now.Add(time.Minute)
+		assert.NoError(t, s.Disconnected(nodeID, now))
+	}
+	record, ok := s.Get(nodeID)
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), record.Reputation)
+
+	// Staying connected is, up to the max reputation
+	assert.NoError(t, s.Connected(nodeID, ip, "", now))
+	now = now.Add(3 * connectedReputationPeriod)
+	assert.NoError(t, s.Disconnected(nodeID, now))
+	record, _ = s.Get(nodeID)
+	assert.Equal(t, int64(3*connectedReputationReward), record.Reputation)
+
+	assert.NoError(t, s.Connected(nodeID, ip, "", now))
+	now = now.Add(1000 * connectedReputationPeriod)
+	assert.NoError(t, s.Disconnected(nodeID, now))
+	record, _ = s.Get(nodeID)
+	assert.Equal(t, int64(maxReputation), record.Reputation)
+
+	// Penalties are bounded too
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, s.ChangeReputation(nodeID, badHandshakeReputationPenalty, now))
+	}
+	record, _ = s.Get(nodeID)
+	assert.Equal(t, int64(minReputation), record.Reputation)
+}
+
+func TestPeerStoreReputationDecays(t *testing.T) {
+	s, err := NewPeerStore(memdb.New(), DefaultPeerStoreSize)
+	assert.NoError(t, err)
+
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	good := ids.NodeID{1}
+	bad := ids.NodeID{2}
+	now := time.Unix(1000, 0)
+
+	assert.NoError(t, s.Connected(good, ip, "", now))
+	now = now.Add(maxReputation * connectedReputationPeriod)
+	assert.NoError(t, s.Disconnected(good, now))
+	assert.NoError(t, s.Connected(bad, ip, "", now))
+	assert.NoError(t, s.Disconnected(bad, now))
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, s.ChangeReputation(bad, badHandshakeReputationPenalty, now))
+	}
+	assert.Len(t, s.ReconnectTargets(10, now), 1)
+
+	// After a half life, both reputations have halved
+	now = now.Add(reputationHalfLife)
+	targets := s.ReconnectTargets(10, now)
+	assert.Len(t, targets, 1)
+	assert.Equal(t, good, targets[0].NodeID)
+	assert.Equal(t, int64(maxReputation/2), targets[0].Reputation)
+
+	// The decay is applied to the stored reputation once the peer is seen
+	assert.NoError(t, s.ChangeReputation(bad, 0, now))
+	record, _ := s.Get(bad)
+	assert.Equal(t, int64(minReputation/2), record.Reputation)
+
+	// Penalties are eventually forgiven
+	now = now.Add(10 * reputationHalfLife)
+	targets = s.ReconnectTargets(10, now)
+	assert.Len(t, targets, 2)
+	assert.Equal(t, int64(0), targets[1].Reputation)
+}
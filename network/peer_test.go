@@ -45,7 +45,7 @@ func TestPeer_Close(t *testing.T) {
 		net.IPv6loopback,
 		0,
 	)
-	id := ids.ShortID(hashing.ComputeHash160Array([]byte(ip.IP().String())))
+	id := ids.NodeID(hashing.ComputeHash160Array([]byte(ip.IP().String())))
 	networkID := uint32(0)
 	appVersion := version.NewDefaultApplication("app", 0, 1, 0)
 	versionParser := version.NewDefaultApplicationParser()
@@ -106,10 +106,17 @@ func TestPeer_Close(t *testing.T) {
 		defaultPeerListSize,
 		defaultGossipPeerListTo,
 		defaultGossipPeerListFreq,
-		NewDialerConfig(0, 30*time.Second),
+		NewDialerConfig(0, 30*time.Second, ProxyConfig{}),
 		false,
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
+		defaultPushAcceptedFrontierSize,
+		NoCapabilities,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	assert.NotNil(t, netwrk)
 
@@ -154,3 +161,32 @@ func TestPeer_Close(t *testing.T) {
 		t.Fatalf("pending bytes invalid")
 	}
 }
+
+func TestPeerIgnoresCapabilitiesBeforeHandshake(t *testing.T) {
+	p := &peer{
+		net: &network{
+			log:                 logging.NoLog{},
+			enabledCapabilities: StateSyncCapability | ChunkedTransferCapability,
+		},
+	}
+
+	msg, err := TestBuilder.Capabilities(StateSyncCapability)
+	assert.NoError(t, err)
+
+	p.handleCapabilities(msg)
+	assert.Equal(t, NoCapabilities, p.getCapabilities())
+	assert.False(t, p.supports(StateSyncCapability))
+
+	p.finishedHandshake.SetValue(true)
+	p.handleCapabilities(msg)
+	assert.Equal(t, StateSyncCapability, p.getCapabilities())
+	assert.True(t, p.supports(StateSyncCapability))
+	assert.False(t, p.supports(ChunkedTransferCapability))
+
+	// Capabilities can't be renegotiated on the same connection
+	msg, err = TestBuilder.Capabilities(ChunkedTransferCapability)
+	assert.NoError(t, err)
+
+	p.handleCapabilities(msg)
+	assert.Equal(t, StateSyncCapability, p.getCapabilities())
+}
@@ -8,12 +8,12 @@ import "github.com/ava-labs/avalanchego/ids"
 // Index associated with peers MAY CHANGE following removal of other peers
 
 type peersData struct {
-	peersIdxes map[ids.ShortID]int // peerID -> *peer index in peersList
-	peersList  []*peer             // invariant: len(peersList) == len(peersIdxes)
+	peersIdxes map[ids.NodeID]int // peerID -> *peer index in peersList
+	peersList  []*peer            // invariant: len(peersList) == len(peersIdxes)
 }
 
 func (p *peersData) initialize() {
-	p.peersIdxes = make(map[ids.ShortID]int)
+	p.peersIdxes = make(map[ids.NodeID]int)
 	p.peersList = make([]*peer, 0)
 }
 
@@ -50,7 +50,7 @@ func (p *peersData) remove(peer *peer) {
 	delete(p.peersIdxes, idToDrop)
 }
 
-func (p *peersData) getByID(id ids.ShortID) (*peer, bool) {
+func (p *peersData) getByID(id ids.NodeID) (*peer, bool) {
 	if idx, ok := p.peersIdxes[id]; ok {
 		return p.peersList[idx], ok
 	}
@@ -12,7 +12,7 @@ func TestPeersData(t *testing.T) {
 	data.initialize()
 
 	peer1 := peer{
-		nodeID: ids.ShortID{0x01},
+		nodeID: ids.NodeID{0x01},
 	}
 
 	// add of first peer is handled
@@ -24,7 +24,7 @@ func TestPeersData(t *testing.T) {
 
 	// re-addition of peer works as update
 	updatedPeer1 := peer{
-		nodeID: ids.ShortID{0x01},
+		nodeID: ids.NodeID{0x01},
 	}
 	data.add(&updatedPeer1)
 	retrievedPeer1, peer1Found = data.getByID(peer1.nodeID)
@@ -33,7 +33,7 @@ func TestPeersData(t *testing.T) {
 	assert.True(t, data.size() == 1)
 
 	peer2 := peer{
-		nodeID: ids.ShortID{0x02},
+		nodeID: ids.NodeID{0x02},
 	}
 
 	// add of another peer is handled
@@ -54,7 +54,7 @@ func TestPeersData(t *testing.T) {
 	assert.True(t, data.size() == 1)
 
 	unknownPeer := peer{
-		nodeID: ids.ShortID{0xff},
+		nodeID: ids.NodeID{0xff},
 	}
 
 	// query for unknown peer is handled
@@ -71,10 +71,10 @@ func TestPeersData(t *testing.T) {
 
 	// retrival by inbound index is handled
 	peer3 := peer{
-		nodeID: ids.ShortID{0x03},
+		nodeID: ids.NodeID{0x03},
 	}
 	peer4 := peer{
-		nodeID: ids.ShortID{0x04},
+		nodeID: ids.NodeID{0x04},
 	}
 	data.add(&peer3)
 	data.add(&peer4)
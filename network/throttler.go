@@ -15,6 +15,10 @@ type Throttler interface {
 	// Block until the event associated with this Acquire can happen.
 	// If [ctx] is canceled, gives up and returns an error.
 	Acquire(ctx context.Context) error
+
+	// SetLimit changes the number of events allowed per second. If
+	// [throttleLimit] is 0, events aren't limited.
+	SetLimit(throttleLimit int)
 }
 
 type throttler struct {
@@ -27,12 +31,27 @@ func (t throttler) Acquire(ctx context.Context) error {
 	return t.limiter.Wait(ctx)
 }
 
+func (t throttler) SetLimit(throttleLimit int) {
+	t.limiter.SetLimit(limit(throttleLimit))
+	t.limiter.SetBurst(throttleLimit)
+}
+
+// NewThrottler returns a throttler that allows [throttleLimit] events per
+// second. If [throttleLimit] is 0, events aren't limited until SetLimit is
+// called.
 func NewThrottler(throttleLimit int) Throttler {
 	return throttler{
-		limiter: rate.NewLimiter(rate.Limit(throttleLimit), throttleLimit),
+		limiter: rate.NewLimiter(limit(throttleLimit), throttleLimit),
 	}
 }
 
+func limit(throttleLimit int) rate.Limit {
+	if throttleLimit <= 0 {
+		return rate.Inf
+	}
+	return rate.Limit(throttleLimit)
+}
+
 func NewNoThrottler() Throttler {
 	return noThrottler{}
 }
@@ -40,3 +59,5 @@ func NewNoThrottler() Throttler {
 func (t noThrottler) Acquire(context.Context) error {
 	return nil
 }
+
+func (t noThrottler) SetLimit(int) {}
@@ -108,3 +108,23 @@ func TestNoThrottler(t *testing.T) {
 		assert.WithinDuration(t, time.Now(), startTime, 25*time.Millisecond)
 	}
 }
+
+// Test that the limit of the Throttler returned by NewThrottler can be changed
+func TestThrottlerSetLimit(t *testing.T) {
+	// A limit of 0 never blocks
+	throttler := NewThrottler(0)
+	for i := 0; i < 250; i++ {
+		assert.NoError(t, throttler.Acquire(context.Background()))
+	}
+
+	throttler.SetLimit(1)
+	assert.NoError(t, throttler.Acquire(context.Background()))
+
+	// Should block because 1 already taken within last second
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, throttler.Acquire(ctx))
+
+	throttler.SetLimit(0)
+	assert.NoError(t, throttler.Acquire(context.Background()))
+}
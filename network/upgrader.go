@@ -22,7 +22,7 @@ var (
 // Upgrader ...
 type Upgrader interface {
 	// Must be thread safe
-	Upgrade(net.Conn) (ids.ShortID, net.Conn, *x509.Certificate, error)
+	Upgrade(net.Conn) (ids.NodeID, net.Conn, *x509.Certificate, error)
 }
 
 type tlsServerUpgrader struct {
@@ -36,7 +36,7 @@ func NewTLSServerUpgrader(config *tls.Config) Upgrader {
 	}
 }
 
-func (t tlsServerUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, *x509.Certificate, error) {
+func (t tlsServerUpgrader) Upgrade(conn net.Conn) (ids.NodeID, net.Conn, *x509.Certificate, error) {
 	return connToIDAndCert(tls.Server(conn, t.config))
 }
 
@@ -51,25 +51,25 @@ func NewTLSClientUpgrader(config *tls.Config) Upgrader {
 	}
 }
 
-func (t tlsClientUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, *x509.Certificate, error) {
+func (t tlsClientUpgrader) Upgrade(conn net.Conn) (ids.NodeID, net.Conn, *x509.Certificate, error) {
 	return connToIDAndCert(tls.Client(conn, t.config))
 }
 
-func connToIDAndCert(conn *tls.Conn) (ids.ShortID, net.Conn, *x509.Certificate, error) {
+func connToIDAndCert(conn *tls.Conn) (ids.NodeID, net.Conn, *x509.Certificate, error) {
 	if err := conn.Handshake(); err != nil {
-		return ids.ShortID{}, nil, nil, err
+		return ids.NodeID{}, nil, nil, err
 	}
 
 	state := conn.ConnectionState()
 	if len(state.PeerCertificates) == 0 {
-		return ids.ShortID{}, nil, nil, errNoCert
+		return ids.NodeID{}, nil, nil, errNoCert
 	}
 	peerCert := state.PeerCertificates[0]
 	return certToID(peerCert), conn, peerCert, nil
 }
 
-func certToID(cert *x509.Certificate) ids.ShortID {
-	return ids.ShortID(
+func certToID(cert *x509.Certificate) ids.NodeID {
+	return ids.NodeID(
 		hashing.ComputeHash160Array(
 			hashing.ComputeHash256(cert.Raw)))
 }
@@ -5,30 +5,59 @@ package node
 
 import (
 	"crypto/tls"
+	"net"
 	"time"
 
+	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database/backup"
+	"github.com/ava-labs/avalanchego/database/compaction"
+	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/health/watchdog"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/dynamicip"
+	"github.com/ava-labs/avalanchego/utils/handover"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/sandbox"
 )
 
 // Config contains all of the configurations of an Avalanche node.
 type Config struct {
 	genesis.Params
 
+	// Settings the node was started with by their key, with the values of
+	// secrets redacted
+	Settings map[string]interface{}
+
+	// Reads the reloadable part of the node's config again. If nil, the
+	// config can't be reloaded.
+	ReloadConfig func() (ReloadableConfig, error)
+
+	// Listening sockets of the node, which are handed over to the process
+	// that replaces the node when it restarts. If nil, the node can't be
+	// restarted that way.
+	Sockets *handover.Sockets
+
 	// If true, bootstrap the current database version and then end the node.
 	FetchOnly bool
 
+	// If true, the database is opened read-only and the node serves its data
+	// without issuing transactions or taking part in consensus
+	ArchivalMode bool
+
 	// Genesis information
 	GenesisBytes []byte
 	AvaxAssetID  ids.ID
@@ -54,12 +83,66 @@ type Config struct {
 	// If false, uses an in memory database
 	DBEnabled bool
 
+	// Name of the database backend to use
+	DBType string
+
+	// Backend that the database is opened with
+	DBBackend manager.Backend
+
+	// If non-empty, path to the directory that the write-ahead log of the
+	// database is kept in
+	DBWALPath string
+
+	// When the database is compacted automatically
+	DBCompactionConfig compaction.Config
+
+	// If non-nil, the values of the database are encrypted with this key
+	DBEncryptionKey []byte
+	// If non-nil, the values of the database that are encrypted with this key
+	// are re-encrypted with [DBEncryptionKey] on startup
+	DBEncryptionPreviousKey []byte
+
+	// Store that backups of the database are written to and restored from.
+	// If nil, backups are disabled.
+	DBBackupStore backup.Store
+	// If non-empty, the database is restored from the backup with this name
+	// when the node starts
+	DBRestoreBackup string
+	// Accepted frontier of each chain in the backup that the database was
+	// restored from when the node started. Set by the app once the database
+	// is restored.
+	DBRestoredFrontiers map[ids.ID][]ids.ID
+	// Checkpoint of each chain's VM in the backup that the database was
+	// restored from when the node started. Set by the app once the database
+	// is restored.
+	DBRestoredCheckpoints map[ids.ID][]byte
+	// If true, the database is scanned for corrupted data, and the state of
+	// each chain and index is verified and repaired where possible, when the
+	// node starts
+	DBVerify bool
+	// Number of bytes of the read cache that the chains share in front of the
+	// database. If 0, reads aren't cached.
+	DBCacheSize int
+	// Fraction of [DBCacheSize] that a single chain can take up
+	DBCacheChainQuota float64
+
 	// Staking configuration
 	StakingIP             utils.DynamicIPDesc
 	EnableStaking         bool
 	StakingTLSCert        tls.Certificate
 	DisabledStakingWeight uint64
 
+	// Key that the node attests to validator snapshots with
+	StakingBLSKey *bls.SecretKey
+
+	// If non-empty, the staking key is held by the signer plugin at this path
+	// rather than being in [StakingTLSCert]
+	StakingSignerPluginPath string
+
+	// Validator set churn limits
+	ValidatorChurnLimit  float64
+	ValidatorChurnPeriod time.Duration
+
 	// Throttling
 	MaxNonStakerPendingMsgs uint32
 	StakerMSGPortion        float64
@@ -77,6 +160,8 @@ type Config struct {
 	PeerListGossipSize  uint32
 	PeerListGossipFreq  time.Duration
 	DialerConfig        network.DialerConfig
+	ProxyConfig         network.ProxyConfig
+	NetworkCapabilities network.Capability
 
 	// Benchlist Configuration
 	BenchlistConfig benchlist.Config
@@ -89,6 +174,9 @@ type Config struct {
 	HTTPHost string
 	HTTPPort uint16
 
+	// Port of the gRPC API server, which shares the HTTP host
+	GRPCPort uint16
+
 	HTTPSEnabled        bool
 	HTTPSKeyFile        string
 	HTTPSCertFile       string
@@ -96,13 +184,35 @@ type Config struct {
 	APIAuthPassword     string
 	APIAllowedOrigins   []string
 
+	// Average number of API requests per second allowed from each client. If
+	// 0, requests aren't rate limited.
+	HTTPRateLimitRPS   float64
+	HTTPRateLimitBurst int
+	// Max size of an API request body in bytes. If 0, the size isn't limited.
+	HTTPMaxRequestBodySize int64
+	// Max number of requests in a JSON-RPC batch request
+	HTTPMaxBatchSize int
+	// Reverse proxies whose X-Forwarded-For header is trusted
+	HTTPTrustedProxies []*net.IPNet
+	// Addresses the APIs are served on in addition to HTTPHost:HTTPPort
+	HTTPAdditionalListeners []server.Listener
+
 	// Enable/Disable APIs
 	AdminAPIEnabled    bool
+	DebugAPIEnabled    bool
+	AuditAPIEnabled    bool
 	InfoAPIEnabled     bool
 	KeystoreAPIEnabled bool
 	MetricsAPIEnabled  bool
 	HealthAPIEnabled   bool
 	IndexAPIEnabled    bool
+	EventsAPIEnabled   bool
+	GraphQLAPIEnabled  bool
+	GRPCAPIEnabled     bool
+
+	// If true, the Metrics API serves the OpenMetrics format, which includes
+	// exemplars
+	MetricsAPIExemplarsEnabled bool
 
 	// Profiling configurations
 	ProfilerConfig profiler.Config
@@ -113,9 +223,28 @@ type Config struct {
 	// Plugin directory
 	PluginDir string
 
+	// Limits of the resources of each plugin VM process
+	PluginLimits sandbox.Limits
+
 	// Consensus configuration
 	ConsensusParams avalanche.Parameters
 
+	// If true, the validators sampled for each query are a deterministic
+	// function of the query, so that polls can be replayed from message logs
+	DeterministicSampling bool
+
+	// Directory that the audit logs of the chains' decisions are written to. If
+	// empty, decisions aren't audited.
+	AuditLogDir string
+
+	// Max fraction of the validators sampled for a poll that can connect from
+	// the same IP prefix, or the same ASN if [ASNTable] knows it. If 0 or 1,
+	// samples aren't limited.
+	MaxIPPrefixSampleFraction float64
+
+	// Maps IP prefixes to ASNs. May be nil.
+	ASNTable *network.ASNTable
+
 	// IPC configuration
 	IPCAPIEnabled      bool
 	IPCPath            string
@@ -127,12 +256,16 @@ type Config struct {
 	// Router that is used to handle incoming consensus messages
 	ConsensusRouter          router.Router
 	RouterHealthConfig       router.HealthConfig
+	ConsensusHealthConfig    common.HealthConfig
+	WatchdogConfig           watchdog.Config
 	ConsensusShutdownTimeout time.Duration
 	ConsensusGossipFrequency time.Duration
 	// Number of peers to gossip to when gossiping accepted frontier
 	ConsensusGossipAcceptedFrontierSize uint
 	// Number of peers to gossip each accepted container to
 	ConsensusGossipOnAcceptSize uint
+	// Number of peers to push the ID of each accepted container to
+	ConsensusPushAcceptedFrontierSize uint
 
 	// Dynamic Update duration for IP or NAT traversal
 	DynamicUpdateDuration time.Duration
@@ -146,8 +279,26 @@ type Config struct {
 	// Subnet Whitelist
 	WhitelistedSubnets ids.Set
 
+	// Caps applied when sampling the validators of each subnet
+	SubnetSamplingCaps map[ids.ID]validators.SamplingCaps
+
+	// Aliases of VMs, such as plugin VMs, that their static APIs are also
+	// served at
+	VMAliases map[ids.ID][]string
+
+	// Validator sets with at most this many validators are sampled without
+	// duplicates
+	DistinctSamplingMaxValidators int
+
 	IndexAllowIncomplete bool
 
+	// If non-empty, only these chains are indexed
+	IndexedChains ids.Set
+
+	// Which containers are pruned from the indexer's database. Chain state
+	// isn't pruned.
+	IndexPruningConfig indexer.PruningConfig
+
 	// Should Bootstrap be retried
 	RetryBootstrap bool
 
@@ -169,6 +320,11 @@ type Config struct {
 
 	// ChainConfigs
 	ChainConfigs map[string]chains.ChainConfig
+	// VM alias --> config of the chains of the VM
+	VMConfigs map[string]chains.ChainConfig
+
+	// Directory that chain snapshots are exported to and imported from
+	SnapshotDir string
 
 	// Max time to spend fetching a container and its
 	// ancestors while responding to a GetAncestors message
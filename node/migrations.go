@@ -0,0 +1,35 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"github.com/ava-labs/avalanchego/database/migration"
+)
+
+// dbKeyspaces are the keyspaces of the node's database that the node manages
+// directly. A change to the layout of one of them must come with a migration
+// of existing data to the new layout, which is appended to its migrations.
+// The state of each chain is managed by its VM.
+var dbKeyspaces = []migration.Keyspace{
+	{Name: "indexer", Prefix: indexerDBPrefix},
+	{Name: "peer store", Prefix: peerStoreDBPrefix},
+	{Name: "auth", Prefix: authDBPrefix},
+	{Name: "audit log", Prefix: auditDBPrefix},
+	{Name: "chain aliases", Prefix: chainAliasPrefix},
+	{Name: "vm aliases", Prefix: vmAliasPrefix},
+	{Name: "shared memory", Prefix: sharedMemoryDBPrefix},
+	{Name: "keystore", Prefix: keystoreDBPrefix},
+}
+
+// migrateDatabase migrates each keyspace of the node's database to its latest
+// schema version. Assumes n.DB is initialized.
+func (n *Node) migrateDatabase() error {
+	runner := migration.NewRunner(n.Log, n.DB)
+	for _, keyspace := range dbKeyspaces {
+		if err := runner.Register(keyspace); err != nil {
+			return err
+		}
+	}
+	return runner.Run()
+}
@@ -7,35 +7,56 @@ import (
 	"crypto"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
 	"path/filepath"
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/avalanchego/api/admin"
+	"github.com/ava-labs/avalanchego/api/audit"
 	"github.com/ava-labs/avalanchego/api/auth"
+	"github.com/ava-labs/avalanchego/api/debug"
+	"github.com/ava-labs/avalanchego/api/graphql"
 	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/api/health/healthproto"
 	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/api/info/infoproto"
 	"github.com/ava-labs/avalanchego/api/keystore"
+	"github.com/ava-labs/avalanchego/api/keystore/keystoreproto"
 	"github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/api/server/serverproto"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/cachedb"
+	"github.com/ava-labs/avalanchego/database/compaction"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/verify"
 	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/health/watchdog"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/indexer/indexerproto"
 	"github.com/ava-labs/avalanchego/ipcs"
 	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/pubsub"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/snow/triggers"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/staking/signer"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/handover"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/math"
@@ -48,11 +69,15 @@ import (
 	"github.com/ava-labs/avalanchego/vms/evm"
 	"github.com/ava-labs/avalanchego/vms/nftfx"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/uptime"
 	"github.com/ava-labs/avalanchego/vms/propertyfx"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 	"github.com/ava-labs/avalanchego/vms/timestampvm"
+	"github.com/ava-labs/avalanchego/vms/wasmvm"
 	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	ipcsapi "github.com/ava-labs/avalanchego/api/ipcs"
 )
@@ -63,8 +88,15 @@ const (
 )
 
 var (
-	genesisHashKey  = []byte("genesisID")
-	indexerDBPrefix = []byte{0x00}
+	genesisHashKey       = []byte("genesisID")
+	indexerDBPrefix      = []byte{0x00}
+	peerStoreDBPrefix    = []byte("peer store")
+	authDBPrefix         = []byte("auth")
+	auditDBPrefix        = []byte("audit log")
+	chainAliasPrefix     = []byte("chain aliases")
+	vmAliasPrefix        = []byte("vm aliases")
+	sharedMemoryDBPrefix = []byte("shared memory")
+	keystoreDBPrefix     = []byte("keystore")
 
 	errPrimarySubnetNotBootstrapped = errors.New("primary subnet has not finished bootstrapping")
 	errInvalidTLSKey                = errors.New("invalid TLS key")
@@ -83,16 +115,39 @@ type Node struct {
 	// Storage for this node
 	DBManager manager.Manager
 	DB        database.Database
+	// Read cache that the chains share in front of [DB]. Nil if disabled.
+	dbCache *cachedb.Cache
+
+	// Compacts [DB] on request and on a schedule
+	compactor *compaction.Compactor
 
 	// Profiles the process. Nil if continuous profiling is disabled.
 	profiler profiler.ContinuousProfiler
 
+	// Fires alerts about the chains. Nil if there are no watchdog rules.
+	watchdog *watchdog.Watchdog
+
+	// Registry of the node's metrics
+	metricsRegistry *prometheus.Registry
+
 	// Indexes blocks, transactions and blocks
 	indexer indexer.Indexer
 
+	// Serves the public APIs over gRPC. Nil if the gRPC API is disabled.
+	grpcServer   *grpc.Server
+	grpcListener net.Listener
+
 	// Handles calls to Keystore API
 	keystore keystore.Keystore
 
+	// Aliases given to chains and VMs through the Admin API
+	chainAliases *admin.AliasStore
+	vmAliases    *admin.AliasStore
+
+	// Records calls to the Admin, Keystore and Auth APIs. Nil if the Audit
+	// API is disabled.
+	auditLog *audit.Log
+
 	// Manages shared memory
 	sharedMemory atomic.Memory
 
@@ -116,6 +171,14 @@ type Node struct {
 
 	// Net runs the networking stack
 	Net network.Network
+	// Dials the node's outbound peer connections
+	dialer network.Dialer
+	// Signs with the staking key held by the signer plugin. Nil if the node
+	// holds its staking key.
+	stakingSigner *signer.Client
+
+	// Limits the rate of API requests of each client
+	apiRateLimiter server.RateLimiter
 
 	// this node's initial connections to the network
 	beacons validators.Set
@@ -123,6 +186,21 @@ type Node struct {
 	// current validators of the network
 	vdrs validators.Manager
 
+	// BLS keys that nodes registered on the platform chain
+	blsKeys validators.BLSKeys
+	// Registration of the BLS key that this node attests to validator
+	// snapshots with
+	blsKeyRegistration *validators.BLSKeyRegistration
+	// Syncs the validator sets of subnets from validator snapshots. Nil if
+	// the node doesn't support validator snapshots.
+	snapshotSyncer validators.SnapshotSyncer
+	// Exchanges the uptimes that the primary network's validators observe
+	// each other to have. Nil if the node doesn't support uptime reports.
+	uptimeReports validators.UptimeReports
+	// The sources of validators' liveness, other than this node's
+	// connections, that the platform chain samples
+	livenessSources validators.LivenessSources
+
 	// Handles HTTP API calls
 	APIServer server.Server
 
@@ -138,6 +216,12 @@ type Node struct {
 	// Sets the exit code
 	shuttingDownExitCode utils.AtomicInterface
 
+	// True once the node has finished initializing
+	initialized utils.AtomicBool
+
+	// Held while the node's config is reloaded
+	reloadLock sync.Mutex
+
 	// Incremented only once on initialization.
 	// Decremented when node is done shutting down.
 	DoneShuttingDown sync.WaitGroup
@@ -150,7 +234,14 @@ type Node struct {
  */
 
 func (n *Node) initNetworking() error {
-	listener, err := net.Listen(TCP, fmt.Sprintf(":%d", n.Config.StakingIP.Port))
+	// The staking socket is inherited if the node was restarted. If peers are
+	// dialed through a proxy, peers can't dial this node, since accepting
+	// their connections would reveal its IP.
+	stakingAddress := fmt.Sprintf(":%d", n.Config.StakingIP.Port)
+	if n.Config.ProxyConfig.Enabled() {
+		stakingAddress = fmt.Sprintf("127.0.0.1:%d", n.Config.StakingIP.Port)
+	}
+	listener, err := n.Config.Sockets.Listen(handover.StakingSocket, stakingAddress)
 	if err != nil {
 		return err
 	}
@@ -166,7 +257,36 @@ func (n *Node) initNetworking() error {
 		n.Log.Info("this node's IP is set to: %q", ipDesc)
 	}
 
-	dialer := network.NewDialer(TCP, n.Config.DialerConfig, n.Log)
+	// Networking logs to its own logger, so that its level can be changed
+	// separately
+	networkLog, err := n.LogFactory.Make("network")
+	if err != nil {
+		return fmt.Errorf("problem initializing networking logger: %w", err)
+	}
+
+	n.dialer, err = network.NewDialer(TCP, n.Config.DialerConfig, networkLog)
+	if err != nil {
+		return err
+	}
+
+	// The signer plugin is owned by the node once the network is created.
+	// Until then, it's shut down if the networking can't be initialized.
+	var stakingSigner *signer.Client
+	defer func() {
+		if stakingSigner != nil && n.stakingSigner == nil {
+			_ = stakingSigner.Close()
+		}
+	}()
+	if n.Config.StakingSignerPluginPath != "" {
+		stakingSigner, err = signer.Launch(n.Config.StakingSignerPluginPath, n.Log)
+		if err != nil {
+			return fmt.Errorf("couldn't launch staking signer plugin: %w", err)
+		}
+		if err := staking.SetSigner(&n.Config.StakingTLSCert, stakingSigner); err != nil {
+			return err
+		}
+		n.Log.Info("staking key is held by the signer plugin at %s", n.Config.StakingSignerPluginPath)
+	}
 
 	tlsKey, ok := n.Config.StakingTLSCert.PrivateKey.(crypto.Signer)
 	if !ok {
@@ -181,6 +301,15 @@ func (n *Node) initNetworking() error {
 	// Initialize validator manager and primary network's validator set
 	primaryNetworkValidators := validators.NewSet()
 	n.vdrs = validators.NewManager()
+	n.vdrs.SetDistinctSampling(n.Config.DistinctSamplingMaxValidators)
+	if err := n.vdrs.SetMaxGroupFraction(n.Config.MaxIPPrefixSampleFraction); err != nil {
+		return err
+	}
+	for subnetID, caps := range n.Config.SubnetSamplingCaps {
+		if err := n.vdrs.SetSamplingCaps(subnetID, caps); err != nil {
+			return err
+		}
+	}
 	if err := n.vdrs.Set(constants.PrimaryNetworkID, primaryNetworkValidators); err != nil {
 		return err
 	}
@@ -192,7 +321,7 @@ func (n *Node) initNetworking() error {
 
 	consensusRouter := n.Config.ConsensusRouter
 	if !n.Config.EnableStaking {
-		if err := primaryNetworkValidators.AddWeight(n.ID, n.Config.DisabledStakingWeight); err != nil {
+		if err := primaryNetworkValidators.AddWeight(ids.NodeIDFromShortID(n.ID), n.Config.DisabledStakingWeight); err != nil {
 			return err
 		}
 		consensusRouter = &insecureValidatorManager{
@@ -228,18 +357,52 @@ func (n *Node) initNetworking() error {
 		}
 	}
 
+	peerStore, err := network.NewPeerStore(
+		prefixdb.New(peerStoreDBPrefix, n.DB),
+		network.DefaultPeerStoreSize,
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't load the peer store: %w", err)
+	}
+
+	n.blsKeys = validators.NewBLSKeys()
+	n.blsKeyRegistration, err = validators.NewBLSKeyRegistration(n.Config.StakingTLSCert.Leaf, tlsKey, n.Config.StakingBLSKey)
+	if err != nil {
+		return err
+	}
+	if n.Config.NetworkCapabilities.Contains(network.ValidatorSnapshotCapability) {
+		n.snapshotSyncer = validators.NewSnapshotSyncer(
+			n.vdrs,
+			n.beacons,
+			n.blsKeys,
+			n.blsKeyRegistration,
+			n.Config.StakingBLSKey,
+			validators.DefaultSnapshotThreshold,
+			n.onSnapshotSynced,
+		)
+	}
+
+	if n.Config.NetworkCapabilities.Contains(network.UptimeReportCapability) {
+		n.uptimeReports = validators.NewUptimeReports(
+			primaryNetworkValidators,
+			uptime.DefaultHistoryBucketDuration,
+			func(validators.UptimeReport) { n.Net.ReportUptimes() },
+		)
+	}
+	n.livenessSources = validators.NewLivenessSources()
+
 	versionManager := version.GetCompatibility(n.Config.NetworkID)
 
 	n.Net = network.NewDefaultNetwork(
 		n.Config.ConsensusParams.Metrics,
-		n.Log,
-		n.ID,
+		networkLog,
+		ids.NodeIDFromShortID(n.ID),
 		n.Config.StakingIP,
 		n.Config.NetworkID,
 		versionManager,
 		version.NewDefaultApplicationParser(),
 		listener,
-		dialer,
+		n.dialer,
 		serverUpgrader,
 		clientUpgrader,
 		primaryNetworkValidators,
@@ -259,11 +422,44 @@ func (n *Node) initNetworking() error {
 		n.Config.FetchOnly,
 		n.Config.ConsensusGossipAcceptedFrontierSize,
 		n.Config.ConsensusGossipOnAcceptSize,
+		n.Config.ConsensusPushAcceptedFrontierSize,
+		n.Config.NetworkCapabilities,
+		peerStore,
+		n.snapshotSyncer,
+		n.vdrs,
+		n.Config.ASNTable,
+		n.uptimeReports,
 	)
+	n.stakingSigner = stakingSigner
+
+	// Sync the validator sets of the tracked subnets from peers, rather than
+	// waiting for the platform chain to derive them
+	for subnetID := range n.Config.WhitelistedSubnets {
+		if subnetID != constants.PrimaryNetworkID {
+			n.Net.SyncValidators(subnetID)
+		}
+	}
 
 	return nil
 }
 
+// onSnapshotSynced creates the chains of the subnet whose validator snapshot
+// was synced, so that they don't wait for the platform chain to bootstrap
+func (n *Node) onSnapshotSynced(cert *validators.SnapshotCertificate) {
+	for _, chain := range cert.Snapshot.Chains {
+		chainParams := chains.ChainParameters{
+			ID:          chain.ID,
+			SubnetID:    cert.Snapshot.SubnetID,
+			GenesisData: chain.GenesisData,
+			VMAlias:     chain.VMID.String(),
+		}
+		for _, fxID := range chain.FxIDs {
+			chainParams.FxAliases = append(chainParams.FxAliases, fxID.String())
+		}
+		n.chainManager.ForceCreateChain(chainParams)
+	}
+}
+
 type insecureValidatorManager struct {
 	router.Router
 	vdrs   validators.Set
@@ -271,14 +467,14 @@ type insecureValidatorManager struct {
 }
 
 func (i *insecureValidatorManager) Connected(vdrID ids.ShortID) {
-	_ = i.vdrs.AddWeight(vdrID, i.weight)
+	_ = i.vdrs.AddWeight(ids.NodeIDFromShortID(vdrID), i.weight)
 	i.Router.Connected(vdrID)
 }
 
 func (i *insecureValidatorManager) Disconnected(vdrID ids.ShortID) {
 	// Shouldn't error unless the set previously had an error, which should
 	// never happen as described above
-	_ = i.vdrs.RemoveWeight(vdrID, i.weight)
+	_ = i.vdrs.RemoveWeight(ids.NodeIDFromShortID(vdrID), i.weight)
 	i.Router.Disconnected(vdrID)
 }
 
@@ -291,7 +487,7 @@ type beaconManager struct {
 }
 
 func (b *beaconManager) Connected(vdrID ids.ShortID) {
-	weight, ok := b.beacons.GetWeight(vdrID)
+	weight, ok := b.beacons.GetWeight(ids.NodeIDFromShortID(vdrID))
 	if !ok {
 		b.Router.Connected(vdrID)
 		return
@@ -310,7 +506,7 @@ func (b *beaconManager) Connected(vdrID ids.ShortID) {
 }
 
 func (b *beaconManager) Disconnected(vdrID ids.ShortID) {
-	if weight, ok := b.beacons.GetWeight(vdrID); ok {
+	if weight, ok := b.beacons.GetWeight(ids.NodeIDFromShortID(vdrID)); ok {
 		// TODO: Account for weight changes in a more robust manner.
 
 		// Sub64 should rarely error since only validators that have added their
@@ -322,29 +518,68 @@ func (b *beaconManager) Disconnected(vdrID ids.ShortID) {
 	b.Router.Disconnected(vdrID)
 }
 
+// dispatchAPIServer serves the HTTP APIs on [listener], whose socket is named
+// [socketName]
+func (n *Node) dispatchAPIServer(socketName string, listener server.Listener) error {
+	// The socket is inherited if the node was restarted
+	socket, err := n.Config.Sockets.Listen(socketName, listener.Address)
+	if err != nil {
+		return err
+	}
+	if !listener.TLS {
+		n.Log.Debug("initializing API server on %s without TLS", listener)
+		return n.APIServer.DispatchListener(socket, nil)
+	}
+	n.Log.Debug("initializing API server on %s with TLS", listener)
+	tlsConfig, err := server.NewTLSConfig(n.Config.HTTPSCertFile, n.Config.HTTPSKeyFile)
+	if err != nil {
+		_ = socket.Close()
+		return err
+	}
+	return n.APIServer.DispatchListener(socket, tlsConfig)
+}
+
 // Dispatch starts the node's servers.
 // Returns when the node exits.
 func (n *Node) Dispatch() error {
-	// Start the HTTP API server
-	go n.Log.RecoverAndPanic(func() {
-		var err error
-		if n.Config.HTTPSEnabled {
-			n.Log.Debug("initializing API server with TLS")
-			err = n.APIServer.DispatchTLS(n.Config.HTTPSCertFile, n.Config.HTTPSKeyFile)
-		} else {
-			n.Log.Debug("initializing API server without TLS")
-			err = n.APIServer.Dispatch()
-		}
-		// When [n].Shutdown() is called, [n.APIServer].Close() is called.
-		// This causes [n.APIServer].Dispatch() to return an error.
-		// If that happened, don't log/return an error here.
-		if !n.shuttingDown.GetValue() {
-			n.Log.Fatal("API server dispatch failed with %s", err)
+	// Start the HTTP API server on each of its listeners
+	listeners := []server.Listener{{
+		Address: fmt.Sprintf("%s:%d", n.Config.HTTPHost, n.Config.HTTPPort),
+		TLS:     n.Config.HTTPSEnabled,
+	}}
+	listeners = append(listeners, n.Config.HTTPAdditionalListeners...)
+	for i, listener := range listeners {
+		listener := listener
+		socketName := handover.HTTPSocket
+		if i > 0 {
+			socketName = fmt.Sprintf("%s-%d", handover.HTTPSocket, i)
 		}
-		// If the API server isn't running, shut down the node.
-		// If node is already shutting down, this does nothing.
-		n.Shutdown(1)
-	})
+		go n.Log.RecoverAndPanic(func() {
+			err := n.dispatchAPIServer(socketName, listener)
+			// When [n].Shutdown() is called, [n.APIServer].Close() is called.
+			// This causes [n.APIServer].Dispatch() to return an error.
+			// If that happened, don't log/return an error here.
+			if !n.shuttingDown.GetValue() {
+				n.Log.Fatal("API server dispatch on %s failed with %s", listener, err)
+			}
+			// If the API server isn't running, shut down the node.
+			// If node is already shutting down, this does nothing.
+			n.Shutdown(1)
+		})
+	}
+
+	// Start the gRPC API server
+	if n.grpcServer != nil {
+		go n.Log.RecoverAndPanic(func() {
+			err := n.grpcServer.Serve(n.grpcListener)
+			// When [n].Shutdown() is called, [n.grpcServer].Stop() is called.
+			// If that happened, don't log an error here.
+			if !n.shuttingDown.GetValue() {
+				n.Log.Fatal("gRPC API server failed with %s", err)
+			}
+			n.Shutdown(1)
+		})
+	}
 
 	// Add bootstrap nodes to the peer network
 	for _, peerIP := range n.Config.BootstrapIPs {
@@ -400,6 +635,20 @@ func (n *Node) initDatabase(dbManager manager.Manager) error {
 	if genesisHash != expectedGenesisHash {
 		return fmt.Errorf("db contains invalid genesis hash. DB Genesis: %s Generated Genesis: %s", genesisHash, expectedGenesisHash)
 	}
+
+	if n.Config.DBVerify {
+		// Report corrupted data before anything reads it
+		report := verify.NewReport("database")
+		verify.Scan(n.DB, report)
+		report.Log(n.Log)
+	}
+
+	if err := n.migrateDatabase(); err != nil {
+		return err
+	}
+
+	n.chainAliases = admin.NewAliasStore(prefixdb.New(chainAliasPrefix, n.DB))
+	n.vmAliases = admin.NewAliasStore(prefixdb.New(vmAliasPrefix, n.DB))
 	return nil
 }
 
@@ -407,7 +656,7 @@ func (n *Node) initDatabase(dbManager manager.Manager) error {
 func (n *Node) initBeacons() error {
 	n.beacons = validators.NewSet()
 	for _, peerID := range n.Config.BootstrapIDs {
-		if err := n.beacons.AddWeight(peerID, 1); err != nil {
+		if err := n.beacons.AddWeight(ids.NodeIDFromShortID(peerID), 1); err != nil {
 			return err
 		}
 	}
@@ -450,6 +699,10 @@ func (n *Node) initIndexer() error {
 	n.indexer, err = indexer.NewIndexer(indexer.Config{
 		IndexingEnabled:      n.Config.IndexAPIEnabled,
 		AllowIncompleteIndex: n.Config.IndexAllowIncomplete,
+		IndexedChains:        n.Config.IndexedChains,
+		Pruning:              n.Config.IndexPruningConfig,
+		MetricsRegisterer:    n.Config.ConsensusParams.Metrics,
+		VerifyDB:             n.Config.DBVerify,
 		DB:                   txIndexerDB,
 		Log:                  n.Log,
 		DecisionDispatcher:   n.DecisionDispatcher,
@@ -486,20 +739,47 @@ func (n *Node) initChains(genesisBytes []byte) {
 func (n *Node) initAPIServer() error {
 	n.Log.Info("initializing API server")
 
-	if !n.Config.APIRequireAuthToken {
-		n.APIServer.Initialize(
-			n.Log,
-			n.LogFactory,
-			n.Config.HTTPHost,
-			n.Config.HTTPPort,
-			n.Config.APIAllowedOrigins,
-		)
-		return nil
+	if n.Config.AuditAPIEnabled {
+		n.Log.Info("calls to the admin, keystore and auth APIs are recorded in the audit log")
+		auditLog, err := audit.NewLog(prefixdb.New(auditDBPrefix, n.DB))
+		if err != nil {
+			return fmt.Errorf("couldn't initialize audit log: %w", err)
+		}
+		n.auditLog = auditLog
+	}
+
+	// Requests pass through the wrappers in reverse order, so the client's IP
+	// is resolved first, then the size of the request is limited, then the
+	// rate of requests from the client's IP is limited, then the authorization
+	// token is verified, and finally the rate of requests with the verified
+	// token is limited. Requests are limited by their IP before their token is
+	// verified so that requests with invalid tokens are limited too.
+	// The rate limiter is installed even if requests aren't rate limited, so
+	// that limits can be set when the node's config is reloaded
+	if n.Config.HTTPRateLimitRPS > 0 {
+		n.Log.Info("API requests are rate limited to %f requests per second per client", n.Config.HTTPRateLimitRPS)
+	}
+	n.apiRateLimiter = server.NewRateLimiter(n.Config.HTTPRateLimitRPS, n.Config.HTTPRateLimitBurst)
+	var (
+		a        auth.Auth
+		wrappers = []server.Wrapper{n.apiRateLimiter}
+	)
+	if n.Config.APIRequireAuthToken {
+		var err error
+		a, err = auth.New(n.Log, "auth", n.Config.APIAuthPassword, prefixdb.New(authDBPrefix, n.DB))
+		if err != nil {
+			return err
+		}
+		wrappers = append(wrappers, a)
 	}
-
-	a, err := auth.New(n.Log, "auth", n.Config.APIAuthPassword)
-	if err != nil {
-		return err
+	wrappers = append(wrappers, n.apiRateLimiter.IPLimiter())
+	if n.Config.HTTPMaxRequestBodySize > 0 {
+		n.Log.Info("API request bodies are limited to %d bytes", n.Config.HTTPMaxRequestBodySize)
+		wrappers = append(wrappers, server.NewRequestSizeLimiter(n.Config.HTTPMaxRequestBodySize))
+	}
+	if len(n.Config.HTTPTrustedProxies) > 0 {
+		n.Log.Info("trusting the X-Forwarded-For header of API requests sent by %v", n.Config.HTTPTrustedProxies)
+		wrappers = append(wrappers, server.NewTrustedProxies(n.Config.HTTPTrustedProxies))
 	}
 
 	n.APIServer.Initialize(
@@ -508,8 +788,21 @@ func (n *Node) initAPIServer() error {
 		n.Config.HTTPHost,
 		n.Config.HTTPPort,
 		n.Config.APIAllowedOrigins,
-		a,
+		n.Config.HTTPMaxBatchSize,
+		wrappers...,
+	)
+
+	openAPIHandler := n.APIServer.OpenAPIHandler(
+		fmt.Sprintf("%s node API", constants.PlatformName),
+		fmt.Sprintf("%d.%d.%d", version.Current.Major(), version.Current.Minor(), version.Current.Patch()),
 	)
+	if err := n.APIServer.AddRoute(openAPIHandler, &sync.RWMutex{}, "openapi", "", n.Log); err != nil {
+		return err
+	}
+
+	if !n.Config.APIRequireAuthToken {
+		return nil
+	}
 
 	// only create auth service if token authorization is required
 	n.Log.Info("API authorization is enabled. Auth tokens must be passed in the header of API requests, except requests to the auth service.")
@@ -521,7 +814,20 @@ func (n *Node) initAPIServer() error {
 		LockOptions: common.NoLock,
 		Handler:     authService,
 	}
-	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "auth", "", n.Log)
+	return n.APIServer.AddRoute(n.audited(handler, "auth"), &sync.RWMutex{}, "auth", "", n.Log)
+}
+
+// audited returns [handler], which is served at [endpoint], wrapped so that
+// calls to it are recorded in the audit log. If the Audit API is disabled,
+// [handler] is returned.
+func (n *Node) audited(handler *common.HTTPHandler, endpoint string) *common.HTTPHandler {
+	if n.auditLog == nil {
+		return handler
+	}
+	return &common.HTTPHandler{
+		LockOptions: handler.LockOptions,
+		Handler:     audit.WrapHandler(n.Log, n.auditLog, endpoint, handler.Handler),
+	}
 }
 
 // Create the vmManager, chainManager and register the following VMs:
@@ -573,10 +879,11 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 	if err != nil {
 		return fmt.Errorf("couldn't initialize chain router: %w", err)
 	}
+	n.Config.ConsensusRouter.SetCrossSubnetSender(n.Net, n.blsKeys)
 
 	fetchOnlyFrom := validators.NewSet()
 	for _, peerID := range n.Config.BootstrapIDs {
-		if err := fetchOnlyFrom.AddWeight(peerID, 1); err != nil {
+		if err := fetchOnlyFrom.AddWeight(ids.NodeIDFromShortID(peerID), 1); err != nil {
 			return fmt.Errorf("couldn't initialize fetch from set: %w", err)
 		}
 	}
@@ -584,6 +891,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 	n.chainManager = chains.New(&chains.ManagerConfig{
 		FetchOnly:                              n.Config.FetchOnly,
 		FetchOnlyFrom:                          fetchOnlyFrom,
+		ArchivalMode:                           n.Config.ArchivalMode,
 		StakingEnabled:                         n.Config.EnableStaking,
 		MaxPendingMsgs:                         n.Config.MaxPendingMsgs,
 		MaxNonStakerPendingMsgs:                n.Config.MaxNonStakerPendingMsgs,
@@ -602,6 +910,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		EpochDuration:                          n.Config.EpochDuration,
 		Validators:                             n.vdrs,
 		NodeID:                                 n.ID,
+		StakingBLSKey:                          n.Config.StakingBLSKey,
 		NetworkID:                              n.Config.NetworkID,
 		Server:                                 &n.APIServer,
 		Keystore:                               n.keystore,
@@ -617,9 +926,25 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		ShutdownNodeFunc:                       n.Shutdown,
 		MeterVMEnabled:                         n.Config.MeterVMEnabled,
 		ChainConfigs:                           n.Config.ChainConfigs,
+		VMConfigs:                              n.Config.VMConfigs,
 		BootstrapMaxTimeGetAncestors:           n.Config.BootstrapMaxTimeGetAncestors,
 		BootstrapMultiputMaxContainersSent:     n.Config.BootstrapMultiputMaxContainersSent,
 		BootstrapMultiputMaxContainersReceived: n.Config.BootstrapMultiputMaxContainersReceived,
+		DeterministicSampling:                  n.Config.DeterministicSampling,
+		AuditLogDir:                            n.Config.AuditLogDir,
+		TraceContainers:                        n.Config.DebugAPIEnabled,
+		ConsensusHealthConfig:                  n.Config.ConsensusHealthConfig,
+		Watchdog:                               n.watchdog,
+		SnapshotDir:                            n.Config.SnapshotDir,
+		BackupStore:                            n.Config.DBBackupStore,
+		RestoredFrontiers:                      n.Config.DBRestoredFrontiers,
+		RestoredCheckpoints:                    n.Config.DBRestoredCheckpoints,
+		VerifyDBOnStartup:                      n.Config.DBVerify,
+		DBCache:                                n.dbCache,
+		DBCacheChainQuota:                      int(float64(n.Config.DBCacheSize) * n.Config.DBCacheChainQuota),
+		LivenessSources:                        n.livenessSources,
+		ValidatorChurnLimit:                    n.Config.ValidatorChurnLimit,
+		ValidatorChurnPeriod:                   n.Config.ValidatorChurnPeriod,
 	})
 
 	vdrs := n.vdrs
@@ -637,6 +962,10 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		n.vmManager.RegisterFactory(platformvm.ID, &platformvm.Factory{
 			Chains:             n.chainManager,
 			Validators:         vdrs,
+			BLSKeys:            n.blsKeys,
+			SnapshotSyncer:     n.snapshotSyncer,
+			UptimeReports:      n.uptimeReports,
+			LivenessSources:    n.livenessSources,
 			StakingEnabled:     n.Config.EnableStaking,
 			WhitelistedSubnets: n.Config.WhitelistedSubnets,
 			CreationTxFee:      n.Config.CreationTxFee,
@@ -655,9 +984,11 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 			Fee:         n.Config.TxFee,
 		}),
 		n.vmManager.RegisterFactory(evm.ID, &rpcchainvm.Factory{
-			Path: filepath.Join(n.Config.PluginDir, "evm"),
+			Path:   filepath.Join(n.Config.PluginDir, "evm"),
+			Limits: n.Config.PluginLimits,
 		}),
 		n.vmManager.RegisterFactory(timestampvm.ID, &timestampvm.Factory{}),
+		n.vmManager.RegisterFactory(wasmvm.ID, &wasmvm.Factory{}),
 		n.vmManager.RegisterFactory(secp256k1fx.ID, &secp256k1fx.Factory{}),
 		n.vmManager.RegisterFactory(nftfx.ID, &nftfx.Factory{}),
 		n.vmManager.RegisterFactory(propertyfx.ID, &propertyfx.Factory{}),
@@ -665,16 +996,57 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 	if errs.Errored() {
 		return errs.Err
 	}
+	if err := n.registerPluginVMs(); err != nil {
+		return err
+	}
 
 	// Notify the API server when new chains are created
 	n.chainManager.AddRegistrant(&n.APIServer)
 	return nil
 }
 
+// registerPluginVMs registers the VMs in the plugin directory that are named
+// by their VM IDs, so that chains of subnets can run them and their static
+// APIs are served before any such chain exists. A plugin that can't be
+// registered is skipped so that it doesn't keep the node from starting.
+func (n *Node) registerPluginVMs() error {
+	files, err := ioutil.ReadDir(n.Config.PluginDir)
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return fmt.Errorf("couldn't read plugin directory: %w", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		vmID, err := ids.FromString(file.Name())
+		if err != nil {
+			// Plugins such as evm are registered by name above
+			continue
+		}
+		if _, err := n.vmManager.GetFactory(vmID); err == nil {
+			n.Log.Warn("skipping plugin %s because a VM with its ID is already registered", file.Name())
+			continue
+		}
+		n.Log.Info("registering plugin VM %s", vmID)
+		err = n.vmManager.RegisterFactory(vmID, &rpcchainvm.Factory{
+			Path:   filepath.Join(n.Config.PluginDir, file.Name()),
+			Limits: n.Config.PluginLimits,
+		})
+		if err != nil {
+			n.Log.Warn("couldn't register plugin VM %s: %s", vmID, err)
+		}
+	}
+	return nil
+}
+
 // initSharedMemory initializes the shared memory for cross chain interation
 func (n *Node) initSharedMemory() error {
 	n.Log.Info("initializing SharedMemory")
-	sharedMemoryDB := prefixdb.New([]byte("shared memory"), n.DB)
+	sharedMemoryDB := prefixdb.New(sharedMemoryDBPrefix, n.DB)
 	return n.sharedMemory.Initialize(n.Log, sharedMemoryDB)
 }
 
@@ -682,7 +1054,7 @@ func (n *Node) initSharedMemory() error {
 // Assumes n.APIServer is already set
 func (n *Node) initKeystoreAPI() error {
 	n.Log.Info("initializing keystore")
-	keystoreDB := n.DBManager.NewPrefixDBManager([]byte("keystore"))
+	keystoreDB := n.DBManager.NewPrefixDBManager(keystoreDBPrefix)
 	ks, err := keystore.New(n.Log, keystoreDB)
 	if err != nil {
 		return err
@@ -701,19 +1073,26 @@ func (n *Node) initKeystoreAPI() error {
 		LockOptions: common.NoLock,
 		Handler:     keystoreHandler,
 	}
-	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "keystore", "", n.HTTPLog)
+	return n.APIServer.AddRoute(n.audited(handler, "keystore"), &sync.RWMutex{}, "keystore", "", n.HTTPLog)
 }
 
 // initMetricsAPI initializes the Metrics API
 // Assumes n.APIServer is already set
 func (n *Node) initMetricsAPI() error {
-	registry, handler := metrics.NewService()
+	registry, handler := metrics.NewService(n.Config.MetricsAPIExemplarsEnabled)
+	n.metricsRegistry = registry
 	// It is assumed by components of the system that the Metrics interface is
 	// non-nil. So, it is set regardless of if the metrics API is available or not.
 	n.Config.ConsensusParams.Metrics = registry
 	n.Config.NetworkConfig.MetricsNamespace = constants.PlatformName
 	n.Config.NetworkConfig.Registerer = registry
 
+	// The database cache must be below the meters, so that every write
+	// through them invalidates the cached values
+	if err := n.initDBCache(); err != nil {
+		return err
+	}
+
 	if !n.Config.MetricsAPIEnabled {
 		n.Log.Info("skipping metrics API initialization because it has been disabled")
 		return nil
@@ -731,6 +1110,60 @@ func (n *Node) initMetricsAPI() error {
 	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "metrics", "", n.HTTPLog)
 }
 
+// initDBCache initializes the read cache that the chains share in front of the
+// database. Every write to the database must go through [n.DB] or
+// [n.DBManager] once they are wrapped.
+// Assumes n.DB and the metrics registry are already initialized
+func (n *Node) initDBCache() error {
+	if n.Config.DBCacheSize == 0 {
+		n.Log.Info("skipping database cache initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("caching %d bytes of database reads", n.Config.DBCacheSize)
+
+	namespace := fmt.Sprintf("%s_db_cache", constants.PlatformName)
+	cache, err := cachedb.NewCache(n.Config.DBCacheSize, namespace, n.Config.ConsensusParams.Metrics)
+	if err != nil {
+		return err
+	}
+	cachedDB := cache.Wrap(n.DB)
+	dbManager, err := manager.NewManagerWithCurrent(n.DBManager, cachedDB)
+	if err != nil {
+		return err
+	}
+	n.dbCache = cache
+	n.DB = cachedDB
+	n.DBManager = dbManager
+	return nil
+}
+
+// initCompactor initializes the compactions of the database
+// Assumes n.DB and the metrics registry are already initialized
+func (n *Node) initCompactor() error {
+	namespace := fmt.Sprintf("%s_db_compaction", constants.PlatformName)
+	dbLog, err := n.LogFactory.Make("db")
+	if err != nil {
+		return fmt.Errorf("problem initializing database logger: %w", err)
+	}
+	compactor, err := compaction.New(dbLog, n.DB, n.Config.DBCompactionConfig, namespace, n.Config.ConsensusParams.Metrics)
+	if err != nil {
+		return err
+	}
+	n.compactor = compactor
+
+	if n.Config.DBCompactionConfig.Freq == 0 {
+		n.Log.Info("skipping scheduled database compactions because they have been disabled")
+		return nil
+	}
+	if n.Config.ArchivalMode {
+		n.Log.Info("skipping scheduled database compactions because the database is read-only")
+		return nil
+	}
+	n.Log.Info("compacting the database every %s, starting %s", n.Config.DBCompactionConfig.Freq, n.Config.DBCompactionConfig.Window)
+	go n.Log.RecoverAndPanic(n.compactor.Dispatch)
+	return nil
+}
+
 // initAdminAPI initializes the Admin API service
 // Assumes n.log, n.chainManager, and n.ValidatorAPI already initialized
 func (n *Node) initAdminAPI() error {
@@ -739,25 +1172,101 @@ func (n *Node) initAdminAPI() error {
 		return nil
 	}
 	n.Log.Info("initializing admin API")
-	service, err := admin.NewService(n.Log, n.chainManager, &n.APIServer, n.Config.ProfilerConfig.Dir)
+
+	// The pprof endpoints are only served while the admin API enables them
+	profilerGate := profiler.NewGate(n.Log)
+	for _, endpoint := range profiler.GateEndpoints {
+		handler := &common.HTTPHandler{
+			LockOptions: common.NoLock,
+			Handler:     profilerGate.Handler(endpoint),
+		}
+		if err := n.APIServer.AddRoute(handler, &sync.RWMutex{}, "pprof", endpoint, n.HTTPLog); err != nil {
+			return err
+		}
+	}
+
+	service, err := admin.NewService(
+		n.Log,
+		n.LogFactory,
+		n.chainManager,
+		n.vmManager,
+		&n.APIServer,
+		n.Config.ProfilerConfig.Dir,
+		profilerGate,
+		n.chainAliases,
+		n.vmAliases,
+		n.compactor,
+		admin.DiagnosticsConfig{
+			Dir:      n.Config.ProfilerConfig.Dir,
+			LogDir:   n.Config.LoggingConfig.Directory,
+			Settings: n.Config.Settings,
+			Metrics:  n.metricsRegistry,
+		},
+		n.Reload,
+		n.Restart,
+	)
 	if err != nil {
 		return err
 	}
-	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "admin", "", n.HTTPLog)
+	return n.APIServer.AddRoute(n.audited(service, "admin"), &sync.RWMutex{}, "admin", "", n.HTTPLog)
+}
+
+// initAuditAPI initializes the Audit API service
+// Assumes n.auditLog is already initialized if the Audit API is enabled
+func (n *Node) initAuditAPI() error {
+	if !n.Config.AuditAPIEnabled {
+		n.Log.Info("skipping audit API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing audit API")
+	service, err := audit.NewService(n.Log, n.auditLog)
+	if err != nil {
+		return err
+	}
+	return n.APIServer.AddRoute(n.audited(service, "audit"), &sync.RWMutex{}, "audit", "", n.HTTPLog)
+}
+
+// initDebugAPI initializes the Debug API service
+// Assumes n.log and n.chainManager already initialized
+func (n *Node) initDebugAPI() error {
+	if !n.Config.DebugAPIEnabled {
+		n.Log.Info("skipping debug API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing debug API")
+	service, err := debug.NewService(n.Log, n.chainManager)
+	if err != nil {
+		return err
+	}
+	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "debug", "", n.HTTPLog)
 }
 
 // initProfiler initializes the continuous profiling
-func (n *Node) initProfiler() {
+func (n *Node) initProfiler() error {
 	if !n.Config.ProfilerConfig.Enabled {
 		n.Log.Info("skipping profiler initialization because it has been disabled")
-		return
+		return nil
+	}
+
+	var uploader profiler.Uploader
+	if uploadURL := n.Config.ProfilerConfig.UploadURL; uploadURL != "" {
+		var err error
+		uploader, err = profiler.NewUploader(uploadURL, map[string]string{
+			"nodeID":  n.ID.PrefixedString(constants.NodeIDPrefix),
+			"version": version.Current.String(),
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	n.Log.Info("initializing continuous profiler")
 	n.profiler = profiler.NewContinuous(
+		n.Log,
 		filepath.Join(n.Config.ProfilerConfig.Dir, "continuous"),
 		n.Config.ProfilerConfig.Freq,
 		n.Config.ProfilerConfig.MaxNumFiles,
+		uploader,
 	)
 	go n.Log.RecoverAndPanic(func() {
 		err := n.profiler.Dispatch()
@@ -766,6 +1275,33 @@ func (n *Node) initProfiler() {
 		}
 		n.Shutdown(1)
 	})
+	return nil
+}
+
+// initWatchdog initializes the watchdog that fires alerts about the chains
+func (n *Node) initWatchdog() error {
+	config := n.Config.WatchdogConfig
+	if len(config.Rules) == 0 {
+		n.Log.Info("skipping watchdog initialization because it has no rules")
+		return nil
+	}
+
+	actions := []watchdog.Action(nil)
+	if config.WebhookURL != "" {
+		actions = append(actions, watchdog.NewWebhook(config.WebhookURL))
+	}
+	if config.ExecCommand != "" {
+		execHook, err := watchdog.NewExecHook(config.ExecCommand)
+		if err != nil {
+			return err
+		}
+		actions = append(actions, execHook)
+	}
+
+	n.Log.Info("initializing watchdog")
+	n.watchdog = watchdog.New(n.Log, config.Rules, actions, config.CheckFreq)
+	go n.Log.RecoverAndPanic(n.watchdog.Dispatch)
+	return nil
 }
 
 func (n *Node) initInfoAPI() error {
@@ -783,6 +1319,7 @@ func (n *Node) initInfoAPI() error {
 		n.Net,
 		n.Config.CreationTxFee,
 		n.Config.TxFee,
+		n.blsKeyRegistration,
 	)
 	if err != nil {
 		return err
@@ -860,6 +1397,116 @@ func (n *Node) initIPCAPI() error {
 	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "ipcs", "", n.HTTPLog)
 }
 
+// initGraphQLAPI initializes the GraphQL API service
+// Assumes n.vdrs, n.chainManager and n.indexer already initialized
+func (n *Node) initGraphQLAPI() error {
+	if !n.Config.GraphQLAPIEnabled {
+		n.Log.Info("skipping GraphQL API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing GraphQL API")
+	service, err := graphql.NewService(
+		version.Current,
+		n.ID,
+		n.Config.NetworkID,
+		n.chainManager,
+		n.vdrs,
+		n.indexer,
+	)
+	if err != nil {
+		return err
+	}
+	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "graphql", "", n.HTTPLog)
+}
+
+// initGRPCAPI creates the gRPC server that serves the enabled info, health,
+// keystore and index APIs, and forwards calls to the chains' APIs. Calls are
+// rate limited, size limited and audited like HTTP requests. The server is
+// started in Dispatch.
+// Assumes n.APIServer, n.apiRateLimiter, n.auditLog, n.keystore,
+// n.healthService, n.chainManager and n.indexer already initialized
+func (n *Node) initGRPCAPI() error {
+	if !n.Config.GRPCAPIEnabled {
+		n.Log.Info("skipping gRPC API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing gRPC API")
+
+	// The rate limiter is installed even if calls aren't rate limited, so
+	// that limits can be set when the node's config is reloaded
+	unaryInterceptors := []grpc.UnaryServerInterceptor{n.apiRateLimiter.UnaryInterceptor()}
+	opts := []grpc.ServerOption{grpc.StreamInterceptor(n.apiRateLimiter.StreamInterceptor())}
+	if n.auditLog != nil {
+		unaryInterceptors = append(unaryInterceptors, audit.UnaryInterceptor(n.Log, n.auditLog, map[string]string{
+			keystoreproto.Keystore_ServiceDesc.ServiceName: "keystore",
+		}))
+	}
+	opts = append(opts, grpc.ChainUnaryInterceptor(unaryInterceptors...))
+	if n.Config.HTTPMaxRequestBodySize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(int(n.Config.HTTPMaxRequestBodySize)))
+	}
+	if n.Config.HTTPSEnabled {
+		creds, err := credentials.NewServerTLSFromFile(n.Config.HTTPSCertFile, n.Config.HTTPSKeyFile)
+		if err != nil {
+			return fmt.Errorf("couldn't load gRPC TLS credentials: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	grpcServer := grpc.NewServer(opts...)
+
+	if n.Config.InfoAPIEnabled {
+		infoService := info.New(
+			n.Log,
+			version.Current,
+			n.ID,
+			n.Config.NetworkID,
+			n.chainManager,
+			n.Net,
+			n.Config.CreationTxFee,
+			n.Config.TxFee,
+			n.blsKeyRegistration,
+		)
+		infoproto.RegisterInfoServer(grpcServer, info.NewServer(infoService))
+	}
+	if n.Config.HealthAPIEnabled {
+		healthproto.RegisterHealthServer(grpcServer, health.NewServer(n.healthService))
+	}
+	if n.Config.KeystoreAPIEnabled {
+		keystoreproto.RegisterKeystoreServer(grpcServer, keystore.NewServer(n.keystore, n.Log))
+	}
+	if n.Config.IndexAPIEnabled {
+		indexerproto.RegisterIndexServer(grpcServer, indexer.NewServer(
+			n.indexer,
+			n.chainManager,
+			n.ConsensusDispatcher,
+			n.DecisionDispatcher,
+		))
+	}
+	serverproto.RegisterChainServer(grpcServer, server.NewChainServer(&n.APIServer, n.chainManager))
+
+	listener, err := n.Config.Sockets.Listen(handover.GRPCSocket, fmt.Sprintf("%s:%d", n.Config.HTTPHost, n.Config.GRPCPort))
+	if err != nil {
+		return fmt.Errorf("couldn't listen for gRPC API requests: %w", err)
+	}
+	n.grpcServer = grpcServer
+	n.grpcListener = listener
+	return nil
+}
+
+// initEventsAPI creates a websocket endpoint for each chain that notifies
+// subscribers of the chain's accepted transactions, or blocks.
+// Assumes n.DecisionDispatcher, n.APIServer and n.chainManager already
+// initialized
+func (n *Node) initEventsAPI() {
+	if !n.Config.EventsAPIEnabled {
+		n.Log.Info("skipping events API initialization because it has been disabled")
+		return
+	}
+	n.Log.Info("initializing events API")
+	chainEvents := pubsub.NewChainEvents(n.Config.NetworkID, n.Log, n.DecisionDispatcher, &n.APIServer)
+	n.chainManager.AddRegistrant(chainEvents)
+}
+
 // Give chains and VMs aliases as specified by the genesis information
 func (n *Node) initAliases(genesisBytes []byte) error {
 	n.Log.Info("initializing aliases")
@@ -887,6 +1534,50 @@ func (n *Node) initAliases(genesisBytes []byte) error {
 			return err
 		}
 	}
+	for vmID, aliases := range n.Config.VMAliases {
+		for _, alias := range aliases {
+			if err := n.vmManager.Alias(vmID, alias); err != nil {
+				return fmt.Errorf("couldn't alias VM %s to %s: %w", vmID, alias, err)
+			}
+			if err := n.APIServer.AddAliases("vm/"+vmID.String(), "vm/"+alias); err != nil {
+				return fmt.Errorf("couldn't alias the API of VM %s to %s: %w", vmID, alias, err)
+			}
+		}
+	}
+
+	// Restore the aliases given through the Admin API. An alias that now
+	// conflicts with another one is skipped rather than preventing the node
+	// from starting.
+	storedChainAliases, err := n.chainAliases.Aliases()
+	if err != nil {
+		return fmt.Errorf("couldn't get persisted chain aliases: %w", err)
+	}
+	for chainID, aliases := range storedChainAliases {
+		for _, alias := range aliases {
+			if err := n.chainManager.Alias(chainID, alias); err != nil {
+				n.Log.Warn("couldn't restore alias %s of chain %s: %s", alias, chainID, err)
+				continue
+			}
+			if err := n.APIServer.AddAliases("bc/"+chainID.String(), "bc/"+alias); err != nil {
+				n.Log.Warn("couldn't restore API alias %s of chain %s: %s", alias, chainID, err)
+			}
+		}
+	}
+	storedVMAliases, err := n.vmAliases.Aliases()
+	if err != nil {
+		return fmt.Errorf("couldn't get persisted VM aliases: %w", err)
+	}
+	for vmID, aliases := range storedVMAliases {
+		for _, alias := range aliases {
+			if err := n.vmManager.Alias(vmID, alias); err != nil {
+				n.Log.Warn("couldn't restore alias %s of VM %s: %s", alias, vmID, err)
+				continue
+			}
+			if err := n.APIServer.AddAliases("vm/"+vmID.String(), "vm/"+alias); err != nil {
+				n.Log.Warn("couldn't restore API alias %s of VM %s: %s", alias, vmID, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -907,7 +1598,7 @@ func (n *Node) Initialize(
 	n.LogFactory = logFactory
 	n.DoneShuttingDown.Add(1)
 	n.Log.Info("node version is: %s", version.Current)
-	n.Log.Info("node ID is: %s", n.ID.PrefixedString(constants.NodeIDPrefix))
+	n.Log.Info("node ID is: %s", address.FormatNodeID(n.ID))
 	n.Log.Info("current database version: %s", dbManager.Current().Version)
 
 	httpLog, err := logFactory.Make("http")
@@ -930,6 +1621,9 @@ func (n *Node) Initialize(
 	if err := n.initMetricsAPI(); err != nil { // Start the Metrics API
 		return fmt.Errorf("couldn't initialize metrics API: %w", err)
 	}
+	if err := n.initCompactor(); err != nil { // Start the database compactions
+		return fmt.Errorf("couldn't initialize database compactor: %w", err)
+	}
 	if err := n.initKeystoreAPI(); err != nil { // Start the Keystore API
 		return fmt.Errorf("couldn't initialize keystore API: %w", err)
 	}
@@ -951,12 +1645,22 @@ func (n *Node) Initialize(
 	if err := n.initHealthAPI(); err != nil {
 		return fmt.Errorf("couldn't initialize health API: %w", err)
 	}
+	// Has to be initialized before chain manager
+	if err := n.initWatchdog(); err != nil {
+		return fmt.Errorf("couldn't initialize watchdog: %w", err)
+	}
 	if err := n.initChainManager(n.Config.AvaxAssetID); err != nil { // Set up the chain manager
 		return fmt.Errorf("couldn't initialize chain manager: %w", err)
 	}
 	if err := n.initAdminAPI(); err != nil { // Start the Admin API
 		return fmt.Errorf("couldn't initialize admin API: %w", err)
 	}
+	if err := n.initDebugAPI(); err != nil { // Start the Debug API
+		return fmt.Errorf("couldn't initialize debug API: %w", err)
+	}
+	if err := n.initAuditAPI(); err != nil { // Start the Audit API
+		return fmt.Errorf("couldn't initialize audit API: %w", err)
+	}
 	if err := n.initInfoAPI(); err != nil { // Start the Info API
 		return fmt.Errorf("couldn't initialize info API: %w", err)
 	}
@@ -972,11 +1676,21 @@ func (n *Node) Initialize(
 	if err := n.initIndexer(); err != nil {
 		return fmt.Errorf("couldn't initialize indexer: %w", err)
 	}
+	if err := n.initGraphQLAPI(); err != nil { // Start the GraphQL API
+		return fmt.Errorf("couldn't initialize GraphQL API: %w", err)
+	}
+	if err := n.initGRPCAPI(); err != nil { // Start the gRPC API
+		return fmt.Errorf("couldn't initialize gRPC API: %w", err)
+	}
+	n.initEventsAPI()
 
-	n.initProfiler()
+	if err := n.initProfiler(); err != nil {
+		return fmt.Errorf("couldn't initialize profiler: %w", err)
+	}
 
 	// Start the Platform chain
 	n.initChains(n.Config.GenesisBytes)
+	n.initialized.SetValue(true)
 	return nil
 }
 
@@ -1003,6 +1717,12 @@ func (n *Node) shutdown() {
 	if n.profiler != nil {
 		n.profiler.Shutdown()
 	}
+	if n.watchdog != nil {
+		n.watchdog.Shutdown()
+	}
+	if n.compactor != nil {
+		n.compactor.Shutdown()
+	}
 	if n.Net != nil {
 		// Close already logs its own error if one occurs, so the error is ignored here
 		_ = n.Net.Close()
@@ -1010,9 +1730,17 @@ func (n *Node) shutdown() {
 	if err := n.APIServer.Shutdown(); err != nil {
 		n.Log.Debug("error during API shutdown: %s", err)
 	}
+	if n.grpcServer != nil {
+		n.grpcServer.Stop()
+	}
 	if err := n.indexer.Close(); err != nil {
 		n.Log.Debug("error closing tx indexer: %w", err)
 	}
+	if n.stakingSigner != nil {
+		if err := n.stakingSigner.Close(); err != nil {
+			n.Log.Debug("error closing staking signer: %s", err)
+		}
+	}
 
 	// Make sure all plugin subprocesses are killed
 	n.Log.Info("cleaning up plugin subprocesses")
@@ -0,0 +1,85 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var (
+	errReloadUnsupported = errors.New("reloading the node's config isn't supported")
+	errNotInitialized    = errors.New("node hasn't finished initializing")
+)
+
+// ReloadableConfig is the part of the node's configuration that can be
+// changed while the node is running, without dropping its peers
+type ReloadableConfig struct {
+	// Levels of every logger
+	LogLevel        logging.Level
+	LogDisplayLevel logging.Level
+
+	// Average number of API requests per second allowed from each client. If
+	// 0, requests aren't rate limited.
+	HTTPRateLimitRPS   float64
+	HTTPRateLimitBurst int
+
+	// Max number of outgoing connection attempts per second. If 0, they
+	// aren't rate limited.
+	OutboundConnectionThrottlingRps uint32
+
+	// Number of peers to gossip peer lists to
+	PeerListGossipSize uint32
+	// Number of peers to gossip the accepted frontier to
+	ConsensusGossipAcceptedFrontierSize uint
+	// Number of peers to gossip each accepted container to
+	ConsensusGossipOnAcceptSize uint
+}
+
+// Reload reads the reloadable part of the node's configuration again and
+// applies it to the running node
+func (n *Node) Reload() error {
+	if n.Config == nil || !n.initialized.GetValue() {
+		return errNotInitialized
+	}
+	if n.Config.ReloadConfig == nil {
+		return errReloadUnsupported
+	}
+
+	n.reloadLock.Lock()
+	defer n.reloadLock.Unlock()
+
+	config, err := n.Config.ReloadConfig()
+	if err != nil {
+		n.Log.Warn("couldn't reload the node's config: %s", err)
+		return err
+	}
+
+	if err := n.LogFactory.SetLogLevel("", config.LogLevel); err != nil {
+		return err
+	}
+	if err := n.LogFactory.SetDisplayLevel("", config.LogDisplayLevel); err != nil {
+		return err
+	}
+	n.apiRateLimiter.SetLimits(config.HTTPRateLimitRPS, config.HTTPRateLimitBurst)
+	n.dialer.SetThrottleRps(config.OutboundConnectionThrottlingRps)
+	n.Net.SetGossipSizes(
+		int(config.PeerListGossipSize),
+		config.ConsensusGossipAcceptedFrontierSize,
+		config.ConsensusGossipOnAcceptSize,
+	)
+
+	n.Log.Info("reloaded the node's config. Log level: %s, display level: %s, API rate limit: %f requests per second with burst %d, outbound connection limit: %d/second, gossip sizes: %d peer list, %d accepted frontier, %d on accept",
+		config.LogLevel,
+		config.LogDisplayLevel,
+		config.HTTPRateLimitRPS,
+		config.HTTPRateLimitBurst,
+		config.OutboundConnectionThrottlingRps,
+		config.PeerListGossipSize,
+		config.ConsensusGossipAcceptedFrontierSize,
+		config.ConsensusGossipOnAcceptSize,
+	)
+	return nil
+}
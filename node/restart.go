@@ -0,0 +1,41 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var errRestartUnsupported = errors.New("restarting the node by handing its sockets over isn't supported")
+
+// Restart starts a new node process with the same executable and arguments,
+// hands the listening sockets of the node over to it and shuts this node
+// down. The new process starts once this one exits. Connections made in the
+// meantime wait in the sockets' backlogs, rather than being refused.
+// Established connections can't be handed over, so peers reconnect to the new
+// process.
+func (n *Node) Restart() error {
+	if n.Config == nil || !n.initialized.GetValue() {
+		return errNotInitialized
+	}
+	if n.Config.Sockets == nil {
+		return errRestartUnsupported
+	}
+
+	path, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("couldn't find the node's executable: %w", err)
+	}
+	if err := n.Config.Sockets.Handover(path, os.Args[1:]); err != nil {
+		return fmt.Errorf("couldn't hand the node's sockets over: %w", err)
+	}
+	n.Log.Info("handed the node's sockets over to a new process started from %s. Shutting down", path)
+
+	// Shut down asynchronously, so that an API call that restarts the node
+	// can return before the API server shuts down
+	go n.Shutdown(0)
+	return nil
+}
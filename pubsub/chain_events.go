@@ -0,0 +1,100 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/api/server"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/triggers"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const chainEventsPrefix = "pubsub-"
+
+var (
+	_ triggers.Acceptor = &acceptedPublisher{}
+	_ Filterer          = &acceptedFilterer{}
+)
+
+// Accepted is sent to the subscribers of a chain when one of its transactions,
+// or blocks, is accepted
+type Accepted struct {
+	ID ids.ID `json:"id"`
+}
+
+// acceptedFilterer notifies the connections subscribed to the accepted ID
+type acceptedFilterer struct {
+	containerID ids.ID
+}
+
+func (f *acceptedFilterer) Filter(filters []Filter) ([]bool, interface{}) {
+	resp := make([]bool, len(filters))
+	for i, c := range filters {
+		resp[i] = c.Check(f.containerID[:])
+	}
+	return resp, &Accepted{ID: f.containerID}
+}
+
+// acceptedPublisher publishes the containers accepted by a chain to a server
+type acceptedPublisher struct {
+	s *Server
+}
+
+func (p *acceptedPublisher) Accept(_ *snow.Context, containerID ids.ID, _ []byte) error {
+	p.s.Publish(containerID, &acceptedFilterer{containerID: containerID})
+	return nil
+}
+
+// ChainEvents creates a websocket endpoint for each chain that notifies
+// subscribers when the chain's transactions, or blocks, are accepted.
+// Subscribers can subscribe to specific IDs or to every accepted ID.
+type ChainEvents struct {
+	log        logging.Logger
+	networkID  uint32
+	dispatcher *triggers.EventDispatcher
+	routeAdder server.RouteAdder
+}
+
+// NewChainEvents returns a new ChainEvents that learns about accepted
+// containers from [dispatcher] and adds endpoints to [routeAdder]
+func NewChainEvents(
+	networkID uint32,
+	log logging.Logger,
+	dispatcher *triggers.EventDispatcher,
+	routeAdder server.RouteAdder,
+) *ChainEvents {
+	return &ChainEvents{
+		log:        log,
+		networkID:  networkID,
+		dispatcher: dispatcher,
+		routeAdder: routeAdder,
+	}
+}
+
+// RegisterChain implements the chains.Registrant interface
+func (e *ChainEvents) RegisterChain(name string, ctx *snow.Context, _ common.Engine) {
+	if err := e.registerChain(name, ctx.ChainID); err != nil {
+		e.log.Error("failed to create the events endpoint of chain %s due to %s", name, err)
+	}
+}
+
+func (e *ChainEvents) registerChain(name string, chainID ids.ID) error {
+	s := New(e.networkID, e.log)
+	identifier := fmt.Sprintf("%s%s", chainEventsPrefix, chainID)
+	if err := e.dispatcher.RegisterChain(chainID, identifier, &acceptedPublisher{s: s}, false); err != nil {
+		return err
+	}
+
+	handler := &common.HTTPHandler{LockOptions: common.NoLock, Handler: s}
+	if err := e.routeAdder.AddRoute(handler, &sync.RWMutex{}, "events/"+name, "", e.log); err != nil {
+		_ = e.dispatcher.DeregisterChain(chainID, identifier)
+		return fmt.Errorf("couldn't add route: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/triggers"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type routeAdderMock struct {
+	bases []string
+}
+
+func (r *routeAdderMock) AddRoute(_ *common.HTTPHandler, _ *sync.RWMutex, base, _ string, _ io.Writer) error {
+	r.bases = append(r.bases, base)
+	return nil
+}
+
+func TestAcceptedFilterer(t *testing.T) {
+	assert := assert.New(t)
+
+	id := ids.GenerateTestID()
+	subscribed := NewFilterParam()
+	assert.NoError(subscribed.Add(id[:]))
+	all := NewFilterParam()
+	all.SubscribeAll()
+	other := NewFilterParam()
+	otherID := ids.GenerateTestID()
+	assert.NoError(other.Add(otherID[:]))
+
+	filterer := &acceptedFilterer{containerID: id}
+	notify, msg := filterer.Filter([]Filter{subscribed, all, other})
+	assert.Equal([]bool{true, true, false}, notify)
+	assert.Equal(&Accepted{ID: id}, msg)
+}
+
+func TestChainEventsRegisterChain(t *testing.T) {
+	assert := assert.New(t)
+
+	dispatcher := &triggers.EventDispatcher{}
+	dispatcher.Initialize(logging.NoLog{})
+	routeAdder := &routeAdderMock{}
+	chainEvents := NewChainEvents(1, logging.NoLog{}, dispatcher, routeAdder)
+
+	ctx := snow.DefaultContextTest()
+	chainEvents.RegisterChain("X", ctx, nil)
+	assert.Equal([]string{"events/X"}, routeAdder.bases)
+
+	// The chain's publisher is already registered
+	err := dispatcher.RegisterChain(ctx.ChainID, chainEventsPrefix+ctx.ChainID.String(), &acceptedPublisher{}, false)
+	assert.Error(err)
+
+	// Accepting a container with no subscribers shouldn't fail
+	assert.NoError(dispatcher.Accept(ctx, ids.GenerateTestID(), nil))
+}
@@ -165,6 +165,10 @@ func (c *connection) readMessage() error {
 		c.handleNewSet(cmd.NewSet)
 	case cmd.AddAddresses != nil:
 		err = c.handleAddAddresses(cmd.AddAddresses)
+	case cmd.AddIDs != nil:
+		err = c.handleAddIDs(cmd.AddIDs)
+	case cmd.SubscribeAll != nil:
+		c.handleSubscribeAll(cmd.SubscribeAll)
 	default:
 		err = ErrInvalidCommand
 	}
@@ -203,3 +207,20 @@ func (c *connection) handleAddAddresses(cmd *AddAddresses) error {
 	c.s.subscribedConnections.Add(c)
 	return nil
 }
+
+func (c *connection) handleAddIDs(cmd *AddIDs) error {
+	if err := cmd.parseIDs(); err != nil {
+		return fmt.Errorf("id parse failed %w", err)
+	}
+	err := c.fp.Add(cmd.idBytes...)
+	if err != nil {
+		return fmt.Errorf("id append failed %w", err)
+	}
+	c.s.subscribedConnections.Add(c)
+	return nil
+}
+
+func (c *connection) handleSubscribeAll(_ *SubscribeAll) {
+	c.fp.SubscribeAll()
+	c.s.subscribedConnections.Add(c)
+}
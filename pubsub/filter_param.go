@@ -13,6 +13,9 @@ type FilterParam struct {
 	lock   sync.RWMutex
 	set    map[string]struct{}
 	filter bloom.Filter
+
+	// all is true if every check should pass
+	all bool
 }
 
 func NewFilterParam() *FilterParam {
@@ -27,6 +30,15 @@ func (f *FilterParam) NewSet() {
 
 	f.set = make(map[string]struct{})
 	f.filter = nil
+	f.all = false
+}
+
+// SubscribeAll causes every check to pass
+func (f *FilterParam) SubscribeAll() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.all = true
 }
 
 func (f *FilterParam) Filter() bloom.Filter {
@@ -42,6 +54,7 @@ func (f *FilterParam) SetFilter(filter bloom.Filter) bloom.Filter {
 
 	f.filter = filter
 	f.set = nil
+	f.all = false
 	return f.filter
 }
 
@@ -49,6 +62,9 @@ func (f *FilterParam) Check(addr []byte) bool {
 	f.lock.RLock()
 	defer f.lock.RUnlock()
 
+	if f.all {
+		return true
+	}
 	if f.filter != nil && f.filter.Check(addr) {
 		return true
 	}
@@ -12,7 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/bloom"
 	"github.com/ava-labs/avalanchego/utils/constants"
-	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 )
 
 func TestAddAddressesParseAddresses(t *testing.T) {
@@ -22,7 +22,7 @@ func TestAddAddressesParseAddresses(t *testing.T) {
 	hrp := constants.GetHRP(5)
 
 	addrID := ids.ShortID{1}
-	addrStr, err := formatting.FormatAddress(chainAlias, hrp, addrID[:])
+	addrStr, err := address.Format(chainAlias, hrp, addrID[:])
 	assert.NoError(err)
 
 	msg := &AddAddresses{JSONAddresses: api.JSONAddresses{
@@ -92,3 +92,34 @@ func TestNewBloom(t *testing.T) {
 		t.Fatalf("new filter check failed")
 	}
 }
+
+func TestAddIDsParseIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	id := ids.GenerateTestID()
+	msg := &AddIDs{IDs: []string{id.String()}}
+
+	err := msg.parseIDs()
+	assert.NoError(err)
+
+	assert.Len(msg.idBytes, 1)
+	assert.Equal(id[:], msg.idBytes[0])
+
+	msg = &AddIDs{IDs: []string{"not an id"}}
+	err = msg.parseIDs()
+	assert.Error(err)
+}
+
+func TestFilterParamSubscribeAll(t *testing.T) {
+	assert := assert.New(t)
+
+	fp := NewFilterParam()
+	addr := ids.GenerateTestShortID()
+	assert.False(fp.Check(addr[:]))
+
+	fp.SubscribeAll()
+	assert.True(fp.Check(addr[:]))
+
+	fp.NewSet()
+	assert.False(fp.Check(addr[:]))
+}
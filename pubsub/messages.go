@@ -5,7 +5,7 @@ package pubsub
 
 import (
 	"github.com/ava-labs/avalanchego/api"
-	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
 )
 
@@ -28,11 +28,26 @@ type AddAddresses struct {
 	addressIds [][]byte
 }
 
+// AddIDs command to add the IDs of transactions, or blocks, to be notified
+// about when they're accepted
+type AddIDs struct {
+	IDs []string `json:"ids"`
+
+	// idBytes array of IDs, kept as a [][]byte for use in the bloom filter
+	idBytes [][]byte
+}
+
+// SubscribeAll command to be notified about every accepted transaction, or
+// block
+type SubscribeAll struct{}
+
 // Command execution command
 type Command struct {
 	NewBloom     *NewBloom     `json:"newBloom,omitempty"`
 	NewSet       *NewSet       `json:"newSet,omitempty"`
 	AddAddresses *AddAddresses `json:"addAddresses,omitempty"`
+	AddIDs       *AddIDs       `json:"addIDs,omitempty"`
+	SubscribeAll *SubscribeAll `json:"subscribeAll,omitempty"`
 }
 
 func (c *Command) String() string {
@@ -43,6 +58,10 @@ func (c *Command) String() string {
 		return "newSet"
 	case c.AddAddresses != nil:
 		return "addAddresses"
+	case c.AddIDs != nil:
+		return "addIDs"
+	case c.SubscribeAll != nil:
+		return "subscribeAll"
 	default:
 		return "unknown"
 	}
@@ -59,7 +78,7 @@ func (c *AddAddresses) parseAddresses() error {
 		c.addressIds = make([][]byte, len(c.Addresses))
 	}
 	for i, addrStr := range c.Addresses {
-		_, _, addrBytes, err := formatting.ParseAddress(addrStr)
+		_, _, addrBytes, err := address.Parse(addrStr)
 		if err != nil {
 			return err
 		}
@@ -67,3 +86,18 @@ func (c *AddAddresses) parseAddresses() error {
 	}
 	return nil
 }
+
+// parseIDs converts the IDs to their byte format.
+func (c *AddIDs) parseIDs() error {
+	if c.idBytes == nil {
+		c.idBytes = make([][]byte, len(c.IDs))
+	}
+	for i, idStr := range c.IDs {
+		id, err := address.ParseID(idStr)
+		if err != nil {
+			return err
+		}
+		c.idBytes[i] = id[:]
+	}
+	return nil
+}
@@ -56,6 +56,9 @@ type Consensus interface {
 	// Returns a set of vertex IDs that are preferred
 	Preferences() ids.Set
 
+	// Returns the set of vertex IDs that are processing
+	Processing() ids.Set
+
 	// RecordPoll collects the results of a network poll. If a result has not
 	// been added, the result is dropped. Returns if a critical error has
 	// occurred.
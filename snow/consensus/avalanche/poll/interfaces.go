@@ -13,8 +13,8 @@ import (
 type Set interface {
 	fmt.Stringer
 
-	Add(requestID uint32, vdrs ids.ShortBag) bool
-	Vote(requestID uint32, vdr ids.ShortID, votes []ids.ID) (ids.UniqueBag, bool)
+	Add(requestID uint32, containerID ids.ID, vdrs ids.NodeIDBag) bool
+	Vote(requestID uint32, vdr ids.NodeID, votes []ids.ID) (ids.UniqueBag, bool)
 	Len() int
 }
 
@@ -23,12 +23,12 @@ type Poll interface {
 	fmt.Stringer
 	PrefixedString(string) string
 
-	Vote(vdr ids.ShortID, votes []ids.ID)
+	Vote(vdr ids.NodeID, votes []ids.ID)
 	Finished() bool
 	Result() ids.UniqueBag
 }
 
 // Factory creates a new Poll
 type Factory interface {
-	New(vdrs ids.ShortBag) Poll
+	New(vdrs ids.NodeIDBag) Poll
 }
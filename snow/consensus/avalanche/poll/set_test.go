@@ -27,6 +27,9 @@ func TestNewSetErrorOnMetrics(t *testing.T) {
 		registerer.Register(prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "poll_duration",
 		})),
+		registerer.Register(prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "poll_responses",
+		})),
 	)
 	if errs.Errored() {
 		t.Fatal(errs.Err)
@@ -47,10 +50,10 @@ func TestCreateAndFinishPoll(t *testing.T) {
 	vtxID := ids.ID{1}
 	votes := []ids.ID{vtxID}
 
-	vdr1 := ids.ShortID{1}
-	vdr2 := ids.ShortID{2} // k = 2
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2} // k = 2
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(
 		vdr1,
 		vdr2,
@@ -58,11 +61,11 @@ func TestCreateAndFinishPoll(t *testing.T) {
 
 	if s.Len() != 0 {
 		t.Fatalf("Shouldn't have any active polls yet")
-	} else if !s.Add(0, vdrs) {
+	} else if !s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Should have been able to add a new poll")
 	} else if s.Len() != 1 {
 		t.Fatalf("Should only have one active poll")
-	} else if s.Add(0, vdrs) {
+	} else if s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Shouldn't have been able to add a duplicated poll")
 	} else if s.Len() != 1 {
 		t.Fatalf("Should only have one active poll")
@@ -90,15 +93,15 @@ func TestSetString(t *testing.T) {
 	registerer := prometheus.NewRegistry()
 	s := NewSet(factory, log, namespace, registerer)
 
-	vdr1 := ids.ShortID{1} // k = 1
+	vdr1 := ids.NodeID{1} // k = 1
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(vdr1)
 
 	expected := "current polls: (Size = 1)\n" +
 		"    0: waiting on Bag: (Size = 1)\n" +
-		"        ID[6HgC8KRBEhXYbF4riJyJFLSHt37UNuRt]: Count = 1"
-	if !s.Add(0, vdrs) {
+		"        ID[NodeID-6HgC8KRBEhXYbF4riJyJFLSHt37UNuRt]: Count = 1"
+	if !s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Should have been able to add a new poll")
 	} else if str := s.String(); expected != str {
 		t.Fatalf("Set return wrong string, Expected:\n%s\nReturned:\n%s",
@@ -5,7 +5,9 @@ package avalanche
 
 import (
 	"errors"
+	"fmt"
 
+	"github.com/ava-labs/avalanchego/health"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -122,6 +124,15 @@ func (ta *Topological) Initialize(
 // NumProcessing implements the Avalanche interface
 func (ta *Topological) NumProcessing() int { return len(ta.nodes) }
 
+// Processing implements the Avalanche interface
+func (ta *Topological) Processing() ids.Set {
+	processing := ids.NewSet(len(ta.nodes))
+	for vtxID := range ta.nodes {
+		processing.Add(vtxID)
+	}
+	return processing
+}
+
 // Parameters implements the Avalanche interface
 func (ta *Topological) Parameters() Parameters { return ta.params }
 
@@ -229,25 +240,39 @@ func (ta *Topological) Finalized() bool { return ta.cg.Finalized() }
 
 // HealthCheck returns information about the consensus health.
 func (ta *Topological) HealthCheck() (interface{}, error) {
-	numOutstandingVtx := ta.Metrics.ProcessingLen()
-	healthy := numOutstandingVtx <= ta.params.MaxOutstandingItems
-	details := map[string]interface{}{
-		"outstandingVertices": numOutstandingVtx,
-	}
+	components := health.Components{}
 
-	// check for long running vertices
+	numOutstanding := ta.Metrics.ProcessingLen()
 	timeReqRunning := ta.Metrics.MeasureAndGetOldestDuration()
-	healthy = healthy && timeReqRunning <= ta.params.MaxItemProcessingTime
-	details["longestRunningVertex"] = timeReqRunning.String()
+	var processingErr error
+	switch {
+	case numOutstanding > ta.params.MaxOutstandingItems:
+		processingErr = fmt.Errorf("%d vertices processing, more than the maximum of %d", numOutstanding, ta.params.MaxOutstandingItems)
+	case timeReqRunning > ta.params.MaxItemProcessingTime:
+		processingErr = fmt.Errorf("vertex processing for %s, longer than the maximum of %s", timeReqRunning, ta.params.MaxItemProcessingTime)
+	}
+	components.Add("processing", map[string]interface{}{
+		"outstandingVertices":  numOutstanding,
+		"longestRunningVertex": timeReqRunning.String(),
+	}, processingErr)
 
-	snowstormReport, err := ta.cg.HealthCheck()
-	healthy = healthy && err == nil
-	details["snowstorm"] = snowstormReport
+	// Check that vertices are still being accepted while vertices are processing
+	timeStalled := ta.Metrics.TimeStalled()
+	var lastAcceptedErr error
+	if timeStalled > ta.params.MaxItemProcessingTime {
+		lastAcceptedErr = fmt.Errorf("no vertices accepted for %s while vertices are processing", timeStalled)
+	}
+	components.Add("lastAccepted", map[string]interface{}{
+		"timeSinceLastAccept": ta.Metrics.TimeSinceLastAccepted().String(),
+	}, lastAcceptedErr)
+
+	snowstormReport, snowstormErr := ta.cg.HealthCheck()
+	components.Add("snowstorm", snowstormReport, snowstormErr)
 
-	if !healthy {
-		return details, errUnhealthy
+	if !components.Healthy() {
+		return components, errUnhealthy
 	}
-	return details, nil
+	return components, nil
 }
 
 // Takes in a list of votes and sets up the topological ordering. Returns the
@@ -537,14 +562,8 @@ func (ta *Topological) update(vtx Vertex) error {
 	switch {
 	case acceptable:
 		// I'm acceptable, why not accept?
-		// Note that ConsensusDispatcher.Accept must be called before vtx.Accept to honor
-		// EventDispatcher.Accept's invariant.
-		if err := ta.ctx.ConsensusDispatcher.Accept(ta.ctx, vtxID, vtx.Bytes()); err != nil {
-			return err
-		}
-
 		ta.ctx.Log.Trace("accepting vertex %s", vtxID)
-		if err := vtx.Accept(); err != nil {
+		if err := ta.accept(vtx); err != nil {
 			return err
 		}
 		delete(ta.nodes, vtxID)
@@ -565,6 +584,29 @@ func (ta *Topological) update(vtx Vertex) error {
 	return nil
 }
 
+// accept notifies the dispatcher of the acceptance of [vtx] and accepts it.
+// If both support it, the dispatcher's writes are made atomically with the
+// vertex's.
+func (ta *Topological) accept(vtx Vertex) error {
+	vtxID := vtx.ID()
+	dispatcher, batching := ta.ctx.ConsensusDispatcher.(snow.BatchingEventDispatcher)
+	batchedVtx, batched := vtx.(BatchedVertex)
+	if !batching || !batched {
+		// Note that ConsensusDispatcher.Accept must be called before vtx.Accept
+		// to honor EventDispatcher.Accept's invariant.
+		if err := ta.ctx.ConsensusDispatcher.Accept(ta.ctx, vtxID, vtx.Bytes()); err != nil {
+			return err
+		}
+		return vtx.Accept()
+	}
+
+	batches, err := dispatcher.AcceptBatch(ta.ctx, vtxID, vtx.Bytes())
+	if err != nil {
+		return err
+	}
+	return batchedVtx.AcceptWithBatches(batches...)
+}
+
 // Update the frontier sets
 func (ta *Topological) updateFrontiers() error {
 	vts := ta.frontier
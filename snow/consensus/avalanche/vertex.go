@@ -4,6 +4,7 @@
 package avalanche
 
 import (
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 )
@@ -28,3 +29,14 @@ type Vertex interface {
 	// Returns the binary representation of this vertex
 	Bytes() []byte
 }
+
+// BatchedVertex is optionally implemented by vertices that can be accepted
+// atomically with other writes
+type BatchedVertex interface {
+	Vertex
+
+	// AcceptWithBatches accepts the vertex and writes [batches] in the same
+	// atomic write. The batches must be over the same underlying database as
+	// the vertex.
+	AcceptWithBatches(batches ...database.Batch) error
+}
@@ -26,6 +26,10 @@ type Metrics struct {
 	// accept or reject the item.
 	processingEntries linkedhashmap.LinkedHashmap
 
+	// lastAccepted is the time that an item was last accepted, or the time
+	// the metrics were initialized if no items have been accepted.
+	lastAccepted time.Time
+
 	// log reports anomalous events.
 	log logging.Logger
 
@@ -45,6 +49,7 @@ type Metrics struct {
 // Initialize the metrics with the provided names.
 func (m *Metrics) Initialize(metricName, descriptionName string, log logging.Logger, namespace string, registerer prometheus.Registerer) error {
 	m.processingEntries = linkedhashmap.New()
+	m.lastAccepted = m.Clock.Time()
 	m.log = log
 
 	m.numProcessing = prometheus.NewGauge(prometheus.GaugeOpts{
@@ -97,8 +102,9 @@ func (m *Metrics) Accepted(id ids.ID) {
 
 	endTime := m.Clock.Time()
 	duration := endTime.Sub(startTime.(time.Time))
-	m.latAccepted.Observe(float64(duration.Milliseconds()))
+	metric.ObserveContainer(m.latAccepted, float64(duration.Milliseconds()), id)
 	m.numProcessing.Dec()
+	m.lastAccepted = endTime
 }
 
 // Rejected marks the item as having been rejected.
@@ -112,7 +118,7 @@ func (m *Metrics) Rejected(id ids.ID) {
 
 	endTime := m.Clock.Time()
 	duration := endTime.Sub(startTime.(time.Time))
-	m.latRejected.Observe(float64(duration.Milliseconds()))
+	metric.ObserveContainer(m.latRejected, float64(duration.Milliseconds()), id)
 	m.numProcessing.Dec()
 }
 
@@ -132,3 +138,24 @@ func (m *Metrics) MeasureAndGetOldestDuration() time.Duration {
 func (m *Metrics) ProcessingLen() int {
 	return m.processingEntries.Len()
 }
+
+// TimeSinceLastAccepted returns the amount of time since an item was last
+// accepted.
+func (m *Metrics) TimeSinceLastAccepted() time.Duration {
+	return m.Clock.Time().Sub(m.lastAccepted)
+}
+
+// TimeStalled returns the amount of time that items have been processing
+// without any item being accepted. Returns 0 if no items are processing.
+func (m *Metrics) TimeStalled() time.Duration {
+	now := m.Clock.Time()
+	oldestTimeIntf, exists := m.processingEntries.Oldest()
+	if !exists {
+		return 0
+	}
+	since := oldestTimeIntf.(time.Time)
+	if m.lastAccepted.After(since) {
+		since = m.lastAccepted
+	}
+	return now.Sub(since)
+}
@@ -0,0 +1,47 @@
+// (c) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestTimeStalled(t *testing.T) {
+	assert := assert.New(t)
+
+	m := Metrics{}
+	start := time.Now()
+	m.Clock.Set(start)
+	assert.NoError(m.Initialize("blks", "block", logging.NoLog{}, "", prometheus.NewRegistry()))
+
+	// Nothing is processing, so nothing is stalled
+	m.Clock.Set(start.Add(time.Hour))
+	assert.Equal(time.Hour, m.TimeSinceLastAccepted())
+	assert.Zero(m.TimeStalled())
+
+	// A stall is measured from when the first item started processing
+	blkID0 := ids.GenerateTestID()
+	blkID1 := ids.GenerateTestID()
+	m.Issued(blkID0)
+	m.Clock.Set(start.Add(2 * time.Hour))
+	m.Issued(blkID1)
+	assert.Equal(time.Hour, m.TimeStalled())
+
+	// and restarts when an item is accepted
+	m.Accepted(blkID0)
+	m.Clock.Set(start.Add(3 * time.Hour))
+	assert.Equal(time.Hour, m.TimeSinceLastAccepted())
+	assert.Equal(time.Hour, m.TimeStalled())
+
+	m.Accepted(blkID1)
+	assert.Zero(m.TimeSinceLastAccepted())
+	assert.Zero(m.TimeStalled())
+}
@@ -26,6 +26,9 @@ type Consensus interface {
 	// Returns the number of blocks processing
 	NumProcessing() int
 
+	// Returns the IDs of the blocks processing
+	Processing() ids.Set
+
 	// Adds a new decision. Assumes the dependency has already been added.
 	// Returns if a critical error has occurred.
 	Add(Block) error
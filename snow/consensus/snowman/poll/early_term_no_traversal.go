@@ -19,7 +19,7 @@ func NewEarlyTermNoTraversalFactory(alpha int) Factory {
 	return &earlyTermNoTraversalFactory{alpha: alpha}
 }
 
-func (f *earlyTermNoTraversalFactory) New(vdrs ids.ShortBag) Poll {
+func (f *earlyTermNoTraversalFactory) New(vdrs ids.NodeIDBag) Poll {
 	return &earlyTermNoTraversalPoll{
 		polled: vdrs,
 		alpha:  f.alpha,
@@ -31,12 +31,12 @@ func (f *earlyTermNoTraversalFactory) New(vdrs ids.ShortBag) Poll {
 // It terminates as quickly as it can without performing any DAG traversals.
 type earlyTermNoTraversalPoll struct {
 	votes  ids.Bag
-	polled ids.ShortBag
+	polled ids.NodeIDBag
 	alpha  int
 }
 
 // Vote registers a response for this poll
-func (p *earlyTermNoTraversalPoll) Vote(vdr ids.ShortID, vote ids.ID) {
+func (p *earlyTermNoTraversalPoll) Vote(vdr ids.NodeID, vote ids.ID) {
 	count := p.polled.Count(vdr)
 	// make sure that a validator can't respond multiple times
 	p.polled.Remove(vdr)
@@ -46,7 +46,7 @@ func (p *earlyTermNoTraversalPoll) Vote(vdr ids.ShortID, vote ids.ID) {
 }
 
 // Drop any future response for this poll
-func (p *earlyTermNoTraversalPoll) Drop(vdr ids.ShortID) {
+func (p *earlyTermNoTraversalPoll) Drop(vdr ids.NodeID) {
 	p.polled.Remove(vdr)
 }
 
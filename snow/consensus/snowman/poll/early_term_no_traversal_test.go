@@ -14,9 +14,9 @@ func TestEarlyTermNoTraversalResults(t *testing.T) {
 
 	vtxID := ids.ID{1}
 
-	vdr1 := ids.ShortID{1} // k = 1
+	vdr1 := ids.NodeID{1} // k = 1
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(vdr1)
 
 	factory := NewEarlyTermNoTraversalFactory(alpha)
@@ -42,10 +42,10 @@ func TestEarlyTermNoTraversalString(t *testing.T) {
 
 	vtxID := ids.ID{1}
 
-	vdr1 := ids.ShortID{1}
-	vdr2 := ids.ShortID{2} // k = 2
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2} // k = 2
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(
 		vdr1,
 		vdr2,
@@ -57,7 +57,7 @@ func TestEarlyTermNoTraversalString(t *testing.T) {
 	poll.Vote(vdr1, vtxID)
 
 	expected := "waiting on Bag: (Size = 1)\n" +
-		"    ID[BaMPFdqMUQ46BV8iRcwbVfsam55kMqcp]: Count = 1"
+		"    ID[NodeID-BaMPFdqMUQ46BV8iRcwbVfsam55kMqcp]: Count = 1"
 	if result := poll.String(); expected != result {
 		t.Fatalf("Poll should have returned %s but returned %s", expected, result)
 	}
@@ -68,10 +68,10 @@ func TestEarlyTermNoTraversalDropsDuplicatedVotes(t *testing.T) {
 
 	vtxID := ids.ID{1}
 
-	vdr1 := ids.ShortID{1}
-	vdr2 := ids.ShortID{2} // k = 2
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2} // k = 2
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(
 		vdr1,
 		vdr2,
@@ -99,13 +99,13 @@ func TestEarlyTermNoTraversalTerminatesEarly(t *testing.T) {
 
 	vtxID := ids.ID{1}
 
-	vdr1 := ids.ShortID{1}
-	vdr2 := ids.ShortID{2}
-	vdr3 := ids.ShortID{3}
-	vdr4 := ids.ShortID{4}
-	vdr5 := ids.ShortID{5} // k = 5
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2}
+	vdr3 := ids.NodeID{3}
+	vdr4 := ids.NodeID{4}
+	vdr5 := ids.NodeID{5} // k = 5
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(
 		vdr1,
 		vdr2,
@@ -144,12 +144,12 @@ func TestEarlyTermNoTraversalForSharedAncestor(t *testing.T) {
 	// A, then we cannot terminate early with alpha = k = 4
 	// If the final vote is cast for any of A, B, C, or D, then
 	// vertex A will have transitively received alpha = 4 votes
-	vdr1 := ids.ShortID{1}
-	vdr2 := ids.ShortID{2}
-	vdr3 := ids.ShortID{3}
-	vdr4 := ids.ShortID{4}
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2}
+	vdr3 := ids.NodeID{3}
+	vdr4 := ids.NodeID{4}
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(
 		vdr1,
 		vdr2,
@@ -181,11 +181,11 @@ func TestEarlyTermNoTraversalForSharedAncestor(t *testing.T) {
 func TestEarlyTermNoTraversalWithFastDrops(t *testing.T) {
 	alpha := 2
 
-	vdr1 := ids.ShortID{1}
-	vdr2 := ids.ShortID{2}
-	vdr3 := ids.ShortID{3} // k = 3
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2}
+	vdr3 := ids.NodeID{3} // k = 3
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(
 		vdr1,
 		vdr2,
@@ -210,10 +210,10 @@ func TestEarlyTermNoTraversalWithWeightedResponses(t *testing.T) {
 
 	vtxID := ids.ID{1}
 
-	vdr1 := ids.ShortID{2}
-	vdr2 := ids.ShortID{3}
+	vdr1 := ids.NodeID{2}
+	vdr2 := ids.NodeID{3}
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(
 		vdr1,
 		vdr2,
@@ -241,10 +241,10 @@ func TestEarlyTermNoTraversalWithWeightedResponses(t *testing.T) {
 func TestEarlyTermNoTraversalDropWithWeightedResponses(t *testing.T) {
 	alpha := 2
 
-	vdr1 := ids.ShortID{1}
-	vdr2 := ids.ShortID{2}
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2}
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(
 		vdr1,
 		vdr2,
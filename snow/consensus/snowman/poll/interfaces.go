@@ -13,9 +13,9 @@ import (
 type Set interface {
 	fmt.Stringer
 
-	Add(requestID uint32, vdrs ids.ShortBag) bool
-	Vote(requestID uint32, vdr ids.ShortID, vote ids.ID) (ids.Bag, bool)
-	Drop(requestID uint32, vdr ids.ShortID) (ids.Bag, bool)
+	Add(requestID uint32, containerID ids.ID, vdrs ids.NodeIDBag) bool
+	Vote(requestID uint32, vdr ids.NodeID, vote ids.ID) (ids.Bag, bool)
+	Drop(requestID uint32, vdr ids.NodeID) (ids.Bag, bool)
 	Len() int
 }
 
@@ -24,13 +24,13 @@ type Poll interface {
 	fmt.Stringer
 	PrefixedString(string) string
 
-	Vote(vdr ids.ShortID, vote ids.ID)
-	Drop(vdr ids.ShortID)
+	Vote(vdr ids.NodeID, vote ids.ID)
+	Drop(vdr ids.NodeID)
 	Finished() bool
 	Result() ids.Bag
 }
 
 // Factory creates a new Poll
 type Factory interface {
-	New(vdrs ids.ShortBag) Poll
+	New(vdrs ids.NodeIDBag) Poll
 }
@@ -15,7 +15,7 @@ type noEarlyTermFactory struct{}
 // termination
 func NewNoEarlyTermFactory() Factory { return noEarlyTermFactory{} }
 
-func (noEarlyTermFactory) New(vdrs ids.ShortBag) Poll {
+func (noEarlyTermFactory) New(vdrs ids.NodeIDBag) Poll {
 	return &noEarlyTermPoll{polled: vdrs}
 }
 
@@ -23,11 +23,11 @@ func (noEarlyTermFactory) New(vdrs ids.ShortBag) Poll {
 // query or a timeout occurs
 type noEarlyTermPoll struct {
 	votes  ids.Bag
-	polled ids.ShortBag
+	polled ids.NodeIDBag
 }
 
 // Vote registers a response for this poll
-func (p *noEarlyTermPoll) Vote(vdr ids.ShortID, vote ids.ID) {
+func (p *noEarlyTermPoll) Vote(vdr ids.NodeID, vote ids.ID) {
 	count := p.polled.Count(vdr)
 	// make sure that a validator can't respond multiple times
 	p.polled.Remove(vdr)
@@ -37,7 +37,7 @@ func (p *noEarlyTermPoll) Vote(vdr ids.ShortID, vote ids.ID) {
 }
 
 // Drop any future response for this poll
-func (p *noEarlyTermPoll) Drop(vdr ids.ShortID) { p.polled.Remove(vdr) }
+func (p *noEarlyTermPoll) Drop(vdr ids.NodeID) { p.polled.Remove(vdr) }
 
 // Finished returns true when all validators have voted
 func (p *noEarlyTermPoll) Finished() bool { return p.polled.Len() == 0 }
@@ -18,14 +18,19 @@ import (
 type poll struct {
 	Poll
 	start time.Time
+	// container that the poll was issued for
+	containerID ids.ID
+	// validators that responded to the poll with votes
+	responded ids.ShortSet
 }
 
 type set struct {
-	log      logging.Logger
-	numPolls prometheus.Gauge
-	durPolls prometheus.Histogram
-	factory  Factory
-	polls    map[uint32]poll
+	log       logging.Logger
+	numPolls  prometheus.Gauge
+	durPolls  prometheus.Histogram
+	respPolls prometheus.Histogram
+	factory   Factory
+	polls     map[uint32]poll
 }
 
 // NewSet returns a new empty set of polls
@@ -54,19 +59,30 @@ func NewSet(
 		log.Error("failed to register poll_duration statistics due to %s", err)
 	}
 
+	respPolls := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "poll_responses",
+		Help:      "Number of validators that responded with votes before the poll finished",
+		Buckets:   prometheus.LinearBuckets(0, 2, 11),
+	})
+	if err := registerer.Register(respPolls); err != nil {
+		log.Error("failed to register poll_responses statistics due to %s", err)
+	}
+
 	return &set{
-		log:      log,
-		numPolls: numPolls,
-		durPolls: durPolls,
-		factory:  factory,
-		polls:    make(map[uint32]poll),
+		log:       log,
+		numPolls:  numPolls,
+		durPolls:  durPolls,
+		respPolls: respPolls,
+		factory:   factory,
+		polls:     make(map[uint32]poll),
 	}
 }
 
-// Add to the current set of polls
+// Add to the current set of polls about [containerID]
 // Returns true if the poll was registered correctly and the network sample
 //         should be made.
-func (s *set) Add(requestID uint32, vdrs ids.ShortBag) bool {
+func (s *set) Add(requestID uint32, containerID ids.ID, vdrs ids.NodeIDBag) bool {
 	if _, exists := s.polls[requestID]; exists {
 		s.log.Debug("dropping poll due to duplicated requestID: %d", requestID)
 		return false
@@ -77,8 +93,9 @@ func (s *set) Add(requestID uint32, vdrs ids.ShortBag) bool {
 		&vdrs)
 
 	s.polls[requestID] = poll{
-		Poll:  s.factory.New(vdrs), // create the new poll
-		start: time.Now(),
+		Poll:        s.factory.New(vdrs), // create the new poll
+		start:       time.Now(),
+		containerID: containerID,
 	}
 	s.numPolls.Inc() // increase the metrics
 	return true
@@ -88,7 +105,7 @@ func (s *set) Add(requestID uint32, vdrs ids.ShortBag) bool {
 // query, or the response has already be registered, nothing is performed.
 func (s *set) Vote(
 	requestID uint32,
-	vdr ids.ShortID,
+	vdr ids.NodeID,
 	vote ids.ID,
 ) (ids.Bag, bool) {
 	poll, exists := s.polls[requestID]
@@ -105,6 +122,8 @@ func (s *set) Vote(
 		vote)
 
 	poll.Vote(vdr, vote)
+	poll.responded.Add(vdr.ShortID())
+	s.polls[requestID] = poll
 	if !poll.Finished() {
 		return ids.Bag{}, false
 	}
@@ -112,14 +131,15 @@ func (s *set) Vote(
 	s.log.Verbo("poll with requestID %d finished as %s", requestID, poll)
 
 	delete(s.polls, requestID) // remove the poll from the current set
-	s.durPolls.Observe(float64(time.Since(poll.start).Milliseconds()))
+	metric.ObserveContainer(s.durPolls, float64(time.Since(poll.start).Milliseconds()), poll.containerID)
+	s.respPolls.Observe(float64(poll.responded.Len()))
 	s.numPolls.Dec() // decrease the metrics
 	return poll.Result(), true
 }
 
 // Drop registers the connections response to a query for [id]. If there was no
 // query, or the response has already be registered, nothing is performed.
-func (s *set) Drop(requestID uint32, vdr ids.ShortID) (ids.Bag, bool) {
+func (s *set) Drop(requestID uint32, vdr ids.NodeID) (ids.Bag, bool) {
 	poll, exists := s.polls[requestID]
 	if !exists {
 		s.log.Verbo("dropping vote from %s to an unknown poll with requestID: %d",
@@ -140,7 +160,8 @@ func (s *set) Drop(requestID uint32, vdr ids.ShortID) (ids.Bag, bool) {
 	s.log.Verbo("poll with requestID %d finished as %s", requestID, poll)
 
 	delete(s.polls, requestID) // remove the poll from the current set
-	s.durPolls.Observe(float64(time.Since(poll.start).Milliseconds()))
+	metric.ObserveContainer(s.durPolls, float64(time.Since(poll.start).Milliseconds()), poll.containerID)
+	s.respPolls.Observe(float64(poll.responded.Len()))
 	s.numPolls.Dec() // decrease the metrics
 	return poll.Result(), true
 }
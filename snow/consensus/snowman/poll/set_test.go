@@ -27,6 +27,9 @@ func TestNewSetErrorOnMetrics(t *testing.T) {
 		registerer.Register(prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "poll_duration",
 		})),
+		registerer.Register(prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "poll_responses",
+		})),
 	)
 	if errs.Errored() {
 		t.Fatal(errs.Err)
@@ -47,10 +50,10 @@ func TestCreateAndFinishSuccessfulPoll(t *testing.T) {
 
 	vtxID := ids.ID{1}
 
-	vdr1 := ids.ShortID{1}
-	vdr2 := ids.ShortID{2} // k = 2
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2} // k = 2
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(
 		vdr1,
 		vdr2,
@@ -58,11 +61,11 @@ func TestCreateAndFinishSuccessfulPoll(t *testing.T) {
 
 	if s.Len() != 0 {
 		t.Fatalf("Shouldn't have any active polls yet")
-	} else if !s.Add(0, vdrs) {
+	} else if !s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Should have been able to add a new poll")
 	} else if s.Len() != 1 {
 		t.Fatalf("Should only have one active poll")
-	} else if s.Add(0, vdrs) {
+	} else if s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Shouldn't have been able to add a duplicated poll")
 	} else if s.Len() != 1 {
 		t.Fatalf("Should only have one active poll")
@@ -90,10 +93,10 @@ func TestCreateAndFinishFailedPoll(t *testing.T) {
 	registerer := prometheus.NewRegistry()
 	s := NewSet(factory, log, namespace, registerer)
 
-	vdr1 := ids.ShortID{1}
-	vdr2 := ids.ShortID{2} // k = 2
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2} // k = 2
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(
 		vdr1,
 		vdr2,
@@ -101,11 +104,11 @@ func TestCreateAndFinishFailedPoll(t *testing.T) {
 
 	if s.Len() != 0 {
 		t.Fatalf("Shouldn't have any active polls yet")
-	} else if !s.Add(0, vdrs) {
+	} else if !s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Should have been able to add a new poll")
 	} else if s.Len() != 1 {
 		t.Fatalf("Should only have one active poll")
-	} else if s.Add(0, vdrs) {
+	} else if s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Shouldn't have been able to add a duplicated poll")
 	} else if s.Len() != 1 {
 		t.Fatalf("Should only have one active poll")
@@ -122,6 +125,59 @@ func TestCreateAndFinishFailedPoll(t *testing.T) {
 	}
 }
 
+func TestSetResponsesMetric(t *testing.T) {
+	factory := NewNoEarlyTermFactory()
+	log := logging.NoLog{}
+	namespace := ""
+	registerer := prometheus.NewRegistry()
+	s := NewSet(factory, log, namespace, registerer)
+
+	vtxID := ids.ID{1}
+
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2}
+	vdr3 := ids.NodeID{3} // k = 3
+
+	vdrs := ids.NodeIDBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr3,
+	)
+
+	if !s.Add(0, ids.Empty, vdrs) {
+		t.Fatalf("Should have been able to add a new poll")
+	} else if _, finished := s.Vote(0, vdr1, vtxID); finished {
+		t.Fatalf("Shouldn't have been able to finish an ongoing poll")
+	} else if _, finished := s.Vote(0, vdr1, vtxID); finished {
+		t.Fatalf("Should have dropped a duplicated vote")
+	} else if _, finished := s.Drop(0, vdr2); finished {
+		t.Fatalf("Shouldn't have been able to finish an ongoing poll")
+	} else if _, finished := s.Vote(0, vdr3, vtxID); !finished {
+		t.Fatalf("Should have finished the poll")
+	}
+
+	metricFamilies, err := registerer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != "poll_responses" {
+			continue
+		}
+		histogram := metricFamily.GetMetric()[0].GetHistogram()
+		if count := histogram.GetSampleCount(); count != 1 {
+			t.Fatalf("Should have observed 1 poll but observed %d", count)
+		}
+		// The dropped validator and the duplicated vote aren't responses
+		if sum := histogram.GetSampleSum(); sum != 2 {
+			t.Fatalf("Should have observed 2 responses but observed %f", sum)
+		}
+		return
+	}
+	t.Fatalf("Should have registered the poll_responses metric")
+}
+
 func TestSetString(t *testing.T) {
 	factory := NewNoEarlyTermFactory()
 	log := logging.NoLog{}
@@ -129,15 +185,15 @@ func TestSetString(t *testing.T) {
 	registerer := prometheus.NewRegistry()
 	s := NewSet(factory, log, namespace, registerer)
 
-	vdr1 := ids.ShortID{1} // k = 1
+	vdr1 := ids.NodeID{1} // k = 1
 
-	vdrs := ids.ShortBag{}
+	vdrs := ids.NodeIDBag{}
 	vdrs.Add(vdr1)
 
 	expected := "current polls: (Size = 1)\n" +
 		"    0: waiting on Bag: (Size = 1)\n" +
-		"        ID[6HgC8KRBEhXYbF4riJyJFLSHt37UNuRt]: Count = 1"
-	if !s.Add(0, vdrs) {
+		"        ID[NodeID-6HgC8KRBEhXYbF4riJyJFLSHt37UNuRt]: Count = 1"
+	if !s.Add(0, ids.Empty, vdrs) {
 		t.Fatalf("Should have been able to add a new poll")
 	} else if str := s.String(); expected != str {
 		t.Fatalf("Set return wrong string, Expected:\n%s\nReturned:\n%s",
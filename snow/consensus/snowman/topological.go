@@ -5,7 +5,9 @@ package snowman
 
 import (
 	"errors"
+	"fmt"
 
+	"github.com/ava-labs/avalanchego/health"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -106,6 +108,17 @@ func (ts *Topological) Parameters() snowball.Parameters { return ts.params }
 // NumProcessing implements the Snowman interface
 func (ts *Topological) NumProcessing() int { return len(ts.blocks) - 1 }
 
+// Processing implements the Snowman interface
+func (ts *Topological) Processing() ids.Set {
+	processing := ids.NewSet(len(ts.blocks))
+	for blkID := range ts.blocks {
+		if blkID != ts.head {
+			processing.Add(blkID)
+		}
+	}
+	return processing
+}
+
 // Add implements the Snowman interface
 func (ts *Topological) Add(blk Block) error {
 	parent := blk.Parent()
@@ -280,21 +293,36 @@ func (ts *Topological) Finalized() bool { return len(ts.blocks) == 1 }
 
 // HealthCheck returns information about the consensus health.
 func (ts *Topological) HealthCheck() (interface{}, error) {
-	numOutstandingBlks := ts.Metrics.ProcessingLen()
-	healthy := numOutstandingBlks <= ts.params.MaxOutstandingItems
-	details := map[string]interface{}{
-		"outstandingBlocks": numOutstandingBlks,
-	}
+	components := health.Components{}
 
-	// check for long running blocks
+	numOutstanding := ts.Metrics.ProcessingLen()
 	timeReqRunning := ts.Metrics.MeasureAndGetOldestDuration()
-	healthy = healthy && timeReqRunning <= ts.params.MaxItemProcessingTime
-	details["longestRunningBlock"] = timeReqRunning.String()
+	var processingErr error
+	switch {
+	case numOutstanding > ts.params.MaxOutstandingItems:
+		processingErr = fmt.Errorf("%d blocks processing, more than the maximum of %d", numOutstanding, ts.params.MaxOutstandingItems)
+	case timeReqRunning > ts.params.MaxItemProcessingTime:
+		processingErr = fmt.Errorf("block processing for %s, longer than the maximum of %s", timeReqRunning, ts.params.MaxItemProcessingTime)
+	}
+	components.Add("processing", map[string]interface{}{
+		"outstandingBlocks":   numOutstanding,
+		"longestRunningBlock": timeReqRunning.String(),
+	}, processingErr)
+
+	// Check that blocks are still being accepted while blocks are processing
+	timeStalled := ts.Metrics.TimeStalled()
+	var lastAcceptedErr error
+	if timeStalled > ts.params.MaxItemProcessingTime {
+		lastAcceptedErr = fmt.Errorf("no blocks accepted for %s while blocks are processing", timeStalled)
+	}
+	components.Add("lastAccepted", map[string]interface{}{
+		"timeSinceLastAccept": ts.Metrics.TimeSinceLastAccepted().String(),
+	}, lastAcceptedErr)
 
-	if !healthy {
-		return details, errUnhealthy
+	if !components.Healthy() {
+		return components, errUnhealthy
 	}
-	return details, nil
+	return components, nil
 }
 
 // takes in a list of votes and sets up the topological ordering. Returns the
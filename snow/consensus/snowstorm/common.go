@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/ava-labs/avalanchego/health"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -93,21 +94,36 @@ func (c *common) Finalized() bool {
 
 // HealthCheck returns information about the consensus health.
 func (c *common) HealthCheck() (interface{}, error) {
-	numOutstandingTxs := c.Metrics.ProcessingLen()
-	healthy := numOutstandingTxs <= c.params.MaxOutstandingItems
-	details := map[string]interface{}{
-		"outstandingTransactions": numOutstandingTxs,
-	}
+	components := health.Components{}
 
-	// check for long running transactions
+	numOutstanding := c.Metrics.ProcessingLen()
 	timeReqRunning := c.Metrics.MeasureAndGetOldestDuration()
-	healthy = healthy && timeReqRunning <= c.params.MaxItemProcessingTime
-	details["longestRunningTx"] = timeReqRunning.String()
+	var processingErr error
+	switch {
+	case numOutstanding > c.params.MaxOutstandingItems:
+		processingErr = fmt.Errorf("%d transactions processing, more than the maximum of %d", numOutstanding, c.params.MaxOutstandingItems)
+	case timeReqRunning > c.params.MaxItemProcessingTime:
+		processingErr = fmt.Errorf("transaction processing for %s, longer than the maximum of %s", timeReqRunning, c.params.MaxItemProcessingTime)
+	}
+	components.Add("processing", map[string]interface{}{
+		"outstandingTransactions": numOutstanding,
+		"longestRunningTx":        timeReqRunning.String(),
+	}, processingErr)
+
+	// Check that transactions are still being accepted while transactions are processing
+	timeStalled := c.Metrics.TimeStalled()
+	var lastAcceptedErr error
+	if timeStalled > c.params.MaxItemProcessingTime {
+		lastAcceptedErr = fmt.Errorf("no transactions accepted for %s while transactions are processing", timeStalled)
+	}
+	components.Add("lastAccepted", map[string]interface{}{
+		"timeSinceLastAccept": c.Metrics.TimeSinceLastAccepted().String(),
+	}, lastAcceptedErr)
 
-	if !healthy {
-		return details, errUnhealthy
+	if !components.Healthy() {
+		return components, errUnhealthy
 	}
-	return details, nil
+	return components, nil
 }
 
 // shouldVote returns if the provided tx should be voted on to determine if it
@@ -183,10 +199,18 @@ func (c *common) acceptTx(tx Tx) error {
 	return nil
 }
 
-// reject the provided tx.
-func (c *common) rejectTx(tx Tx) error {
+// reject the provided tx because of [reason].
+func (c *common) rejectTx(tx Tx, reason RejectionReason) error {
 	txID := tx.ID()
-	c.ctx.Log.Trace("rejecting transaction %s due to a conflicting acceptance", txID)
+	if reason.ConflictID != ids.Empty {
+		c.ctx.Log.Trace("rejecting transaction %s due to the acceptance of %s", txID, reason.ConflictID)
+	} else {
+		c.ctx.Log.Trace("rejecting transaction %s due to the rejection of %s", txID, reason.DependencyID)
+	}
+
+	if tracker, ok := tx.(RejectionTracker); ok {
+		tracker.RejectedBecause(reason)
+	}
 
 	// Reject is called before notifying the IPC so that rejections that
 	// cause fatal errors aren't sent to an IPC peer.
@@ -303,14 +327,14 @@ type rejector struct {
 
 func (r *rejector) Dependencies() ids.Set { return r.deps }
 
-func (r *rejector) Fulfill(ids.ID) {
+func (r *rejector) Fulfill(id ids.ID) {
 	if r.rejected || r.errs.Errored() {
 		return
 	}
 	r.rejected = true
 	asSet := ids.NewSet(1)
 	asSet.Add(r.txID)
-	r.errs.Add(r.g.reject(asSet))
+	r.errs.Add(r.g.reject(asSet, RejectionReason{DependencyID: id}))
 }
 
 func (*rejector) Abandon(ids.ID) {}
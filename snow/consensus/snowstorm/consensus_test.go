@@ -34,6 +34,7 @@ var (
 		AcceptingDependencyTest,
 		AcceptingSlowDependencyTest,
 		RejectingDependencyTest,
+		RejectionReasonTest,
 		VacuouslyAcceptedTest,
 		ConflictsTest,
 		VirtuousDependsOnRogueTest,
@@ -64,6 +65,7 @@ func Setup() {
 		color.InputIDsV = []ids.ID{}
 		color.VerifyV = nil
 		color.BytesV = []byte{byte(i)}
+		color.RejectionReasonV = RejectionReason{}
 	}
 
 	X := ids.Empty.Prefix(4)
@@ -1002,6 +1004,59 @@ func RejectingDependencyTest(t *testing.T, factory Factory) {
 	}
 }
 
+func RejectionReasonTest(t *testing.T, factory Factory) {
+	graph := factory.New()
+
+	purple := &TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(7),
+			StatusV: choices.Processing,
+		},
+		DependenciesV: []Tx{Red},
+	}
+	purple.InputIDsV = append(purple.InputIDsV, ids.Empty.Prefix(8))
+
+	params := sbcon.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             1,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	if err := graph.Initialize(snow.DefaultContextTest(), params); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tx := range []Tx{Red, Green, purple} {
+		if err := graph.Add(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g := ids.Bag{}
+	g.Add(Green.ID())
+	if _, err := graph.RecordPoll(g); err != nil {
+		t.Fatal(err)
+	}
+
+	switch {
+	case Green.Status() != choices.Accepted:
+		t.Fatalf("Wrong status. %s should be %s", Green.ID(), choices.Accepted)
+	case Red.Status() != choices.Rejected:
+		t.Fatalf("Wrong status. %s should be %s", Red.ID(), choices.Rejected)
+	case purple.Status() != choices.Rejected:
+		t.Fatalf("Wrong status. %s should be %s", purple.ID(), choices.Rejected)
+	case Red.RejectionReasonV != RejectionReason{ConflictID: Green.ID()}:
+		t.Fatalf("%s should have been rejected because of %s", Red.ID(), Green.ID())
+	case purple.RejectionReasonV != RejectionReason{DependencyID: Red.ID()}:
+		t.Fatalf("%s should have been rejected because of %s", purple.ID(), Red.ID())
+	}
+}
+
 func VacuouslyAcceptedTest(t *testing.T, factory Factory) {
 	graph := factory.New()
 
@@ -272,20 +272,21 @@ func (dg *Directed) accept(txID ids.ID) error {
 	dg.preferences.Remove(txID)
 
 	// Reject all the txs that conflicted with this tx.
-	if err := dg.reject(txNode.ins); err != nil {
+	reason := RejectionReason{ConflictID: txID}
+	if err := dg.reject(txNode.ins, reason); err != nil {
 		return err
 	}
 	// While it is typically true that a tx this is being accepted is preferred,
 	// it is possible for this to not be the case. So this is handled for
 	// completeness.
-	if err := dg.reject(txNode.outs); err != nil {
+	if err := dg.reject(txNode.outs, reason); err != nil {
 		return err
 	}
 	return dg.acceptTx(txNode.tx)
 }
 
 // reject all the named txIDs and remove them from the graph
-func (dg *Directed) reject(conflictIDs ids.Set) error {
+func (dg *Directed) reject(conflictIDs ids.Set, reason RejectionReason) error {
 	for conflictKey := range conflictIDs {
 		conflict := dg.txs[conflictKey]
 		// This tx is no longer an option for consuming the UTXOs from its
@@ -321,7 +322,7 @@ func (dg *Directed) reject(conflictIDs ids.Set) error {
 		dg.removeConflict(conflictKey, conflict.ins)
 		dg.removeConflict(conflictKey, conflict.outs)
 
-		if err := dg.rejectTx(conflict.tx); err != nil {
+		if err := dg.rejectTx(conflict.tx, reason); err != nil {
 			return err
 		}
 	}
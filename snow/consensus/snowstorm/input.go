@@ -360,14 +360,14 @@ func (ig *Input) accept(txID ids.ID) error {
 	ig.preferences.Remove(txID)
 
 	// Reject all the txs that conflicted with this tx.
-	if err := ig.reject(conflicts); err != nil {
+	if err := ig.reject(conflicts, RejectionReason{ConflictID: txID}); err != nil {
 		return err
 	}
 	return ig.acceptTx(txNode.tx)
 }
 
 // reject all the named txIDs and remove them from their conflict sets
-func (ig *Input) reject(conflictIDs ids.Set) error {
+func (ig *Input) reject(conflictIDs ids.Set, reason RejectionReason) error {
 	for conflictKey := range conflictIDs {
 		conflict := ig.txs[conflictKey]
 
@@ -381,7 +381,7 @@ func (ig *Input) reject(conflictIDs ids.Set) error {
 		// Remove this tx from all the conflict sets it's currently in
 		ig.removeConflict(conflictKey, conflict.tx.InputIDs())
 
-		if err := ig.rejectTx(conflict.tx); err != nil {
+		if err := ig.rejectTx(conflict.tx, reason); err != nil {
 			return err
 		}
 	}
@@ -16,6 +16,8 @@ type TestTx struct {
 	InputIDsV     []ids.ID
 	VerifyV       error
 	BytesV        []byte
+
+	RejectionReasonV RejectionReason
 }
 
 // Dependencies implements the Tx interface
@@ -29,3 +31,6 @@ func (t *TestTx) Verify() error { return t.VerifyV }
 
 // Bytes returns the bits
 func (t *TestTx) Bytes() []byte { return t.BytesV }
+
+// RejectedBecause implements the RejectionTracker interface
+func (t *TestTx) RejectedBecause(reason RejectionReason) { t.RejectionReasonV = reason }
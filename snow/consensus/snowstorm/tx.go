@@ -41,3 +41,23 @@ type Tx interface {
 	// able to parse these bytes to the same transaction.
 	Bytes() []byte
 }
+
+// RejectionReason describes why a transaction was rejected. Exactly one of
+// the fields is set.
+type RejectionReason struct {
+	// ConflictID is the ID of the accepted transaction that conflicted with
+	// the rejected transaction.
+	ConflictID ids.ID
+
+	// DependencyID is the ID of the rejected transaction that the rejected
+	// transaction depended on.
+	DependencyID ids.ID
+}
+
+// RejectionTracker is optionally implemented by transactions that record why
+// they were rejected.
+type RejectionTracker interface {
+	// RejectedBecause is called with the reason this transaction is being
+	// rejected, immediately before Reject is called.
+	RejectedBecause(reason RejectionReason)
+}
@@ -13,7 +13,10 @@ import (
 
 	"github.com/ava-labs/avalanchego/api/keystore"
 	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/upgrade"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
 )
@@ -28,6 +31,18 @@ type EventDispatcher interface {
 	Reject(ctx *Context, containerID ids.ID, container []byte) error
 }
 
+// BatchingEventDispatcher is optionally implemented by EventDispatchers whose
+// handlers can write their record of an acceptance atomically with the
+// container's acceptance
+type BatchingEventDispatcher interface {
+	EventDispatcher
+
+	// AcceptBatch is Accept, but returns the writes of the handlers that
+	// support it instead of writing them. The caller must write the returned
+	// batches atomically with [container]'s acceptance.
+	AcceptBatch(ctx *Context, containerID ids.ID, container []byte) ([]database.Batch, error)
+}
+
 // AliasLookup ...
 type AliasLookup interface {
 	Lookup(alias string) (ids.ID, error)
@@ -39,6 +54,14 @@ type SubnetLookup interface {
 	SubnetID(chainID ids.ID) (ids.ID, error)
 }
 
+// CrossSubnetSender sends messages from a chain to chains in other subnets
+type CrossSubnetSender interface {
+	// SendCrossSubnet sends [msg] to the chain [destinationChainID], signed by
+	// this node. The message is delivered to the destination's
+	// common.CrossSubnetHandler on the nodes that run it.
+	SendCrossSubnet(destinationChainID ids.ID, msg []byte) error
+}
+
 // Context is information about the current execution.
 // [NetworkID] is the ID of the network this context exists within.
 // [ChainID] is the ID of the chain this context exists within.
@@ -68,6 +91,24 @@ type Context struct {
 	EpochDuration        time.Duration
 	Clock                timer.Clock
 
+	// True if the node is in archival mode. The chain serves the data it
+	// already has, but doesn't take part in consensus, so the VM must refuse
+	// to issue transactions.
+	Archival bool
+
+	// The upgrades of this chain, in the order that they activate. The
+	// snowman engine drops blocks that violate the rules that are active at
+	// them.
+	Upgrades upgrade.Schedule
+
+	// The hash function that the IDs of this chain's containers are derived
+	// with. If nil, SHA-256 is used.
+	ContainerHash hashing.Hasher256
+
+	// Sends messages to chains in other subnets. May be nil, in which case
+	// the chain can't originate cross-subnet messages.
+	CrossSubnet CrossSubnetSender
+
 	// Non-zero iff this chain bootstrapped. Should only be accessed atomically.
 	bootstrapped uint32
 }
@@ -82,6 +123,15 @@ func (ctx *Context) Bootstrapped() {
 	stdatomic.StoreUint32(&ctx.bootstrapped, 1)
 }
 
+// ContainerID returns the ID of the container of this chain whose bytes are
+// [container]
+func (ctx *Context) ContainerID(container []byte) ids.ID {
+	if ctx.ContainerHash == nil {
+		return hashing.ComputeHash256Array(container)
+	}
+	return ctx.ContainerHash(container)
+}
+
 // Epoch this context thinks it's in based on the wall clock time.
 func (ctx *Context) Epoch() uint32 {
 	now := ctx.Clock.Time()
@@ -115,6 +115,9 @@ func (b *Bootstrapper) Initialize(
 	}
 
 	config.Bootstrapable = b
+	if stateSyncer, ok := b.VM.(common.StateSyncableVM); ok && config.StateSyncer == nil {
+		config.StateSyncer = stateSyncer
+	}
 	return b.Bootstrapper.Initialize(config.Config)
 }
 
@@ -158,7 +161,7 @@ func (b *Bootstrapper) fetch(vtxIDs ...ids.ID) error {
 		if err != nil {
 			return fmt.Errorf("dropping request for %s as there are no validators", vtxID)
 		}
-		validatorID := validators[0].ID()
+		validatorID := validators[0].ID().ShortID()
 		b.RequestID++
 
 		b.OutstandingRequests.Add(validatorID, b.RequestID, vtxID)
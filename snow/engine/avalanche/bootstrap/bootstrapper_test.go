@@ -54,7 +54,7 @@ func newConfig(t *testing.T) (Config, ids.ShortID, *common.SenderTest, *vertex.T
 	sender.CantGetAcceptedFrontier = false
 
 	peer := ids.GenerateTestShortID()
-	if err := peers.AddWeight(peer, 1); err != nil {
+	if err := peers.AddWeight(ids.NodeIDFromShortID(peer), 1); err != nil {
 		t.Fatal(err)
 	}
 
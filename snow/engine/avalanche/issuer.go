@@ -4,11 +4,17 @@
 package avalanche
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 )
 
+var errWrongNumVerifyResults = errors.New("VM returned the wrong number of verification results")
+
 // issuer issues [vtx] into consensus after its dependencies are met.
 type issuer struct {
 	t                 *Transitive
@@ -34,6 +40,7 @@ func (i *issuer) Abandon() {
 	if !i.abandoned {
 		vtxID := i.vtx.ID()
 		i.t.pending.Remove(vtxID)
+		i.t.trace(vtxID, traceAbandoned, ids.ShortEmpty, "a dependency won't be issued")
 		i.abandoned = true
 		i.t.vtxBlocked.Abandon(vtxID) // Inform vertices waiting on this vtx that it won't be issued
 	}
@@ -56,9 +63,14 @@ func (i *issuer) Update() {
 		i.t.errs.Add(err)
 		return
 	}
+	txErrs, err := i.verifyTxs(txs)
+	if err != nil {
+		i.t.errs.Add(err)
+		return
+	}
 	validTxs := make([]snowstorm.Tx, 0, len(txs))
-	for _, tx := range txs {
-		if err := tx.Verify(); err != nil {
+	for j, tx := range txs {
+		if err := txErrs[j]; err != nil {
 			i.t.Ctx.Log.Debug("Transaction %s failed verification due to %s", tx.ID(), err)
 		} else {
 			validTxs = append(validTxs, tx)
@@ -69,6 +81,8 @@ func (i *issuer) Update() {
 	// Take the valid transactions and issue a new vertex with them.
 	if len(validTxs) != len(txs) {
 		i.t.Ctx.Log.Debug("Abandoning %s due to failed transaction verification", vtxID)
+		i.t.trace(vtxID, traceAbandoned, ids.ShortEmpty, "%d of %d transactions failed verification",
+			len(txs)-len(validTxs), len(txs))
 		if _, err := i.t.batch(validTxs, false /*=force*/, false /*=empty*/, false /*=limit*/); err != nil {
 			i.t.errs.Add(err)
 		}
@@ -86,22 +100,34 @@ func (i *issuer) Update() {
 
 	// Issue a poll for this vertex.
 	p := i.t.Consensus.Parameters()
-	vdrs, err := i.t.Validators.Sample(p.K) // Validators to sample
+	vdrs, err := i.t.SampleValidators(p.K, i.vtx.ID(), i.t.RequestID+1) // Validators to sample
 
-	vdrBag := ids.ShortBag{} // Validators to sample repr. as a set
+	vdrBag := ids.NodeIDBag{} // Validators to sample repr. as a set
 	for _, vdr := range vdrs {
 		vdrBag.Add(vdr.ID())
 	}
 
 	vdrList := vdrBag.List()
 	vdrSet := ids.NewShortSet(len(vdrList))
-	vdrSet.Add(vdrList...)
+	vdrSet.Add(ids.NodeIDsToShortIDs(vdrList)...)
 
 	i.t.RequestID++
-	if err == nil && i.t.polls.Add(i.t.RequestID, vdrBag) {
+	if err == nil && i.t.polls.Add(i.t.RequestID, vtxID, vdrBag) {
+		if i.t.AuditLog != nil {
+			i.t.AuditLog.StartPoll(i.t.RequestID)
+		}
 		i.t.Sender.PushQuery(vdrSet, i.t.RequestID, vtxID, i.vtx.Bytes())
-	} else if err != nil {
-		i.t.Ctx.Log.Error("Query for %s was dropped due to an insufficient number of validators", vtxID)
+		i.t.trace(vtxID, traceIssued, ids.ShortEmpty, "queried %d validators with request ID %d",
+			vdrSet.Len(), i.t.RequestID)
+	} else {
+		if err != nil {
+			i.t.Ctx.Log.Error("Query for %s was dropped due to an insufficient number of validators", vtxID)
+		}
+		i.t.trace(vtxID, traceIssued, ids.ShortEmpty, "without a query")
+	}
+	if i.t.tracer != nil {
+		i.t.tracedProcessing[vtxID] = i.vtx
+		i.t.traceDecisions()
 	}
 
 	// Notify vertices waiting on this one that it (and its transactions) have been issued.
@@ -127,3 +153,22 @@ func (ti *txIssuer) Dependencies() ids.Set { return ti.i.txDeps }
 func (ti *txIssuer) Fulfill(id ids.ID)     { ti.i.FulfillTx(id) }
 func (ti *txIssuer) Abandon(ids.ID)        { ti.i.Abandon() }
 func (ti *txIssuer) Update()               { ti.i.Update() }
+
+// verifyTxs returns the result of verifying each of [txs]. If the VM is a
+// BatchVerifierVM, the transactions are verified as a batch.
+func (i *issuer) verifyTxs(txs []snowstorm.Tx) ([]error, error) {
+	if vm, ok := i.t.VM.(vertex.BatchVerifierVM); ok {
+		txErrs := vm.VerifyTxs(txs)
+		if len(txErrs) != len(txs) {
+			return nil, fmt.Errorf("%w: verified %d transactions but got %d results",
+				errWrongNumVerifyResults, len(txs), len(txErrs))
+		}
+		return txErrs, nil
+	}
+
+	txErrs := make([]error, len(txs))
+	for j, tx := range txs {
+		txErrs[j] = tx.Verify()
+	}
+	return txErrs, nil
+}
@@ -0,0 +1,115 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var (
+	_ common.Mempool      = &Transitive{}
+	_ common.TxRegossiper = &Transitive{}
+)
+
+// MempoolTxs implements the common.Mempool interface. The transactions are
+// the ones from the VM that are waiting for fewer vertices to be processing.
+func (t *Transitive) MempoolTxs() []common.MempoolTx {
+	txs := make([]common.MempoolTx, len(t.pendingTxs))
+	for i, tx := range t.pendingTxs {
+		txs[i] = common.MempoolTx{
+			ID:   tx.ID(),
+			Size: len(tx.Bytes()),
+		}
+	}
+	return txs
+}
+
+// EvictTx implements the common.Mempool interface
+func (t *Transitive) EvictTx(txID ids.ID) bool {
+	for i, tx := range t.pendingTxs {
+		if tx.ID() != txID {
+			continue
+		}
+		t.pendingTxs = append(t.pendingTxs[:i], t.pendingTxs[i+1:]...)
+		t.Ctx.Log.Info("evicted tx %s from the mempool", txID)
+		return true
+	}
+	return false
+}
+
+// vmPendingTxs returns the txs that the VM wants to issue. If the VM is a
+// common.PriorityMempool, all of its txs are popped in order of priority.
+func (t *Transitive) vmPendingTxs() []snowstorm.Tx {
+	mempool, ok := t.VM.(common.PriorityMempool)
+	if !ok {
+		return t.VM.PendingTxs()
+	}
+
+	txs := make([]snowstorm.Tx, 0, mempool.NumTxs())
+	for {
+		pendingTx, ok := mempool.PopTx()
+		if !ok {
+			return txs
+		}
+		tx, ok := pendingTx.(snowstorm.Tx)
+		if !ok {
+			var err error
+			tx, err = t.VM.ParseTx(pendingTx.Bytes())
+			if err != nil {
+				t.Ctx.Log.Debug("dropping tx %s from the mempool due to %s", pendingTx.ID(), err)
+				continue
+			}
+		}
+		txs = append(txs, tx)
+	}
+}
+
+// prioritizePendingTxs orders the pending txs by the priority that the VM
+// gives them, highest first, if the VM is a common.FeeReporter. Txs with the
+// same priority keep their order.
+func (t *Transitive) prioritizePendingTxs() {
+	reporter, ok := t.VM.(common.FeeReporter)
+	if !ok {
+		return
+	}
+	priorities := make(map[ids.ID]uint64, len(t.pendingTxs))
+	for _, tx := range t.pendingTxs {
+		priorities[tx.ID()] = reporter.TxPriority(tx)
+	}
+	sort.SliceStable(t.pendingTxs, func(i, j int) bool {
+		return priorities[t.pendingTxs[i].ID()] > priorities[t.pendingTxs[j].ID()]
+	})
+}
+
+// RegossipTx implements the common.TxRegossiper interface
+func (t *Transitive) RegossipTx(txID ids.ID) (bool, error) {
+	// Consensus is only initialized once bootstrapping has finished
+	if !t.Ctx.IsBootstrapped() {
+		return false, nil
+	}
+
+	for vtxID := range t.Consensus.Processing() {
+		vtx, err := t.Manager.GetVtx(vtxID)
+		if err != nil {
+			return false, err
+		}
+		txs, err := vtx.Txs()
+		if err != nil {
+			return false, err
+		}
+		for _, tx := range txs {
+			if tx.ID() != txID {
+				continue
+			}
+			t.Ctx.Log.Info("gossiping %s, which includes tx %s, to the network", vtxID, txID)
+			t.Sender.Gossip(vtxID, vtx.Bytes())
+			return true, nil
+		}
+	}
+	return false, nil
+}
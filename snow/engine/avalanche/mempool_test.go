@@ -0,0 +1,143 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+func TestEngineMempool(t *testing.T) {
+	config := DefaultConfig()
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	manager.Default(true)
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	mVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	vts := []avalanche.Vertex{gVtx, mVtx}
+
+	tx0 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx0.InputIDsV = append(tx0.InputIDsV, ids.GenerateTestID())
+
+	vtx0 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0},
+		BytesV:   []byte{0, 1, 2, 3},
+	}
+
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID(), mVtx.ID()} }
+	manager.GetVtxF = func(id ids.ID) (avalanche.Vertex, error) {
+		switch id {
+		case gVtx.ID():
+			return gVtx, nil
+		case mVtx.ID():
+			return mVtx, nil
+		case vtx0.ID():
+			return vtx0, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+	manager.ParseVtxF = func([]byte) (avalanche.Vertex, error) { return vtx0, nil }
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pending transactions are in the mempool until they're evicted
+	tx1 := &snowstorm.TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		BytesV: []byte{4, 5, 6},
+	}
+	te.pendingTxs = []snowstorm.Tx{tx1}
+
+	txs := te.MempoolTxs()
+	if len(txs) != 1 {
+		t.Fatalf("Expected 1 tx in the mempool but got %d", len(txs))
+	}
+	if txs[0].ID != tx1.ID() || txs[0].Size != len(tx1.Bytes()) {
+		t.Fatalf("Wrong summary of the mempool tx: %+v", txs[0])
+	}
+	if te.EvictTx(tx0.ID()) {
+		t.Fatalf("Shouldn't have evicted a tx that isn't in the mempool")
+	}
+	if !te.EvictTx(tx1.ID()) {
+		t.Fatalf("Should have evicted the tx")
+	}
+	if txs := te.MempoolTxs(); len(txs) != 0 {
+		t.Fatalf("Expected the mempool to be empty but got %d txs", len(txs))
+	}
+
+	// Processing transactions are gossiped in the vertex that includes them
+	sender.PushQueryF = func(ids.ShortSet, uint32, ids.ID, []byte) {}
+	sender.ChitsF = func(ids.ShortID, uint32, []ids.ID) {}
+	if err := te.PushQuery(vdr, 0, vtx0.ID(), vtx0.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if vtx0.Status() != choices.Processing {
+		t.Fatalf("Vertex should be processing")
+	}
+
+	gossiped := false
+	sender.GossipF = func(vtxID ids.ID, vtxBytes []byte) {
+		if vtxID != vtx0.ID() || !bytes.Equal(vtxBytes, vtx0.Bytes()) {
+			t.Fatalf("Gossiped the wrong vertex")
+		}
+		gossiped = true
+	}
+	if ok, err := te.RegossipTx(tx0.ID()); err != nil {
+		t.Fatal(err)
+	} else if !ok || !gossiped {
+		t.Fatalf("Should have gossiped the vertex including the tx")
+	}
+	if ok, err := te.RegossipTx(tx1.ID()); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatalf("Shouldn't have gossiped a tx that isn't processing")
+	}
+}
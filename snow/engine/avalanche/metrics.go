@@ -12,6 +12,7 @@ import (
 type metrics struct {
 	numVtxRequests, numPendingVts, numMissingTxs prometheus.Gauge
 	getAncestorsVtxs                             prometheus.Histogram
+	repollDepth                                  prometheus.Histogram
 }
 
 // Initialize implements the Engine interface
@@ -47,6 +48,23 @@ func (m *metrics) Initialize(namespace string, registerer prometheus.Registerer)
 			2000,
 		},
 	})
+	m.repollDepth = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repoll_depth",
+		Help:      "The number of repolls issued before a poll decided vertices",
+		Buckets: []float64{
+			0,
+			1,
+			2,
+			4,
+			8,
+			16,
+			32,
+			64,
+			128,
+			256,
+		},
+	})
 
 	errs := wrappers.Errs{}
 	errs.Add(
@@ -54,6 +72,7 @@ func (m *metrics) Initialize(namespace string, registerer prometheus.Registerer)
 		registerer.Register(m.numPendingVts),
 		registerer.Register(m.numMissingTxs),
 		registerer.Register(m.getAncestorsVtxs),
+		registerer.Register(m.repollDepth),
 	)
 	return errs.Err
 }
@@ -48,6 +48,20 @@ func (_m *Engine) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, co
 	return r0
 }
 
+// AcceptedStateSummary provides a mock function with given fields: validatorID, requestID, summaryIDs
+func (_m *Engine) AcceptedStateSummary(validatorID ids.ShortID, requestID uint32, summaryIDs []ids.ID) error {
+	ret := _m.Called(validatorID, requestID, summaryIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(ids.ShortID, uint32, []ids.ID) error); ok {
+		r0 = rf(validatorID, requestID, summaryIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Chits provides a mock function with given fields: validatorID, requestID, containerIDs
 func (_m *Engine) Chits(validatorID ids.ShortID, requestID uint32, containerIDs []ids.ID) error {
 	ret := _m.Called(validatorID, requestID, containerIDs)
@@ -176,6 +190,34 @@ func (_m *Engine) GetAcceptedFrontierFailed(validatorID ids.ShortID, requestID u
 	return r0
 }
 
+// GetAcceptedStateSummary provides a mock function with given fields: validatorID, requestID, heights
+func (_m *Engine) GetAcceptedStateSummary(validatorID ids.ShortID, requestID uint32, heights []uint64) error {
+	ret := _m.Called(validatorID, requestID, heights)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(ids.ShortID, uint32, []uint64) error); ok {
+		r0 = rf(validatorID, requestID, heights)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAcceptedStateSummaryFailed provides a mock function with given fields: validatorID, requestID
+func (_m *Engine) GetAcceptedStateSummaryFailed(validatorID ids.ShortID, requestID uint32) error {
+	ret := _m.Called(validatorID, requestID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(ids.ShortID, uint32) error); ok {
+		r0 = rf(validatorID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetAncestors provides a mock function with given fields: validatorID, requestID, containerID
 func (_m *Engine) GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID) error {
 	ret := _m.Called(validatorID, requestID, containerID)
@@ -218,6 +260,34 @@ func (_m *Engine) GetFailed(validatorID ids.ShortID, requestID uint32) error {
 	return r0
 }
 
+// GetStateSummaryFrontier provides a mock function with given fields: validatorID, requestID
+func (_m *Engine) GetStateSummaryFrontier(validatorID ids.ShortID, requestID uint32) error {
+	ret := _m.Called(validatorID, requestID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(ids.ShortID, uint32) error); ok {
+		r0 = rf(validatorID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetStateSummaryFrontierFailed provides a mock function with given fields: validatorID, requestID
+func (_m *Engine) GetStateSummaryFrontierFailed(validatorID ids.ShortID, requestID uint32) error {
+	ret := _m.Called(validatorID, requestID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(ids.ShortID, uint32) error); ok {
+		r0 = rf(validatorID, requestID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetVM provides a mock function with given fields:
 func (_m *Engine) GetVM() common.VM {
 	ret := _m.Called()
@@ -425,6 +495,20 @@ func (_m *Engine) Shutdown() error {
 	return r0
 }
 
+// StateSummaryFrontier provides a mock function with given fields: validatorID, requestID, summary
+func (_m *Engine) StateSummaryFrontier(validatorID ids.ShortID, requestID uint32, summary []byte) error {
+	ret := _m.Called(validatorID, requestID, summary)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(ids.ShortID, uint32, []byte) error); ok {
+		r0 = rf(validatorID, requestID, summary)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Timeout provides a mock function with given fields:
 func (_m *Engine) Timeout() error {
 	ret := _m.Called()
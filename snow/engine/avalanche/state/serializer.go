@@ -10,6 +10,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/verify"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
@@ -17,6 +18,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/math"
 )
 
@@ -30,7 +32,10 @@ var (
 	errWrongChainID  = errors.New("wrong ChainID in vertex")
 )
 
-var _ vertex.Manager = &Serializer{}
+var (
+	_ vertex.Manager  = &Serializer{}
+	_ verify.Verifier = &Serializer{}
+)
 
 // Serializer manages the state of multiple vertices
 type Serializer struct {
@@ -39,12 +44,19 @@ type Serializer struct {
 	state *prefixedState
 	db    *versiondb.Database
 	edge  ids.Set
+
+	// Derives the IDs of vertices
+	hash hashing.Hasher256
 }
 
 // Initialize implements the avalanche.State interface
 func (s *Serializer) Initialize(ctx *snow.Context, vm vertex.DAGVM, db database.Database) {
 	s.ctx = ctx
 	s.vm = vm
+	s.hash = ctx.ContainerHash
+	if s.hash == nil {
+		s.hash = hashing.ComputeHash256Array
+	}
 
 	vdb := versiondb.New(db)
 	dbCache := &cache.LRU{Size: dbCacheSize}
@@ -90,13 +102,14 @@ func (s *Serializer) BuildVtx(
 		txBytes[i] = tx.Bytes()
 	}
 
-	vtx, err := vertex.Build(
+	vtx, err := vertex.BuildWithHash(
 		s.ctx.ChainID,
 		height,
 		epoch,
 		parentIDs,
 		txBytes,
 		restrictions,
+		s.hash,
 	)
 	if err != nil {
 		return nil, err
@@ -117,8 +130,51 @@ func (s *Serializer) GetVtx(vtxID ids.ID) (avalanche.Vertex, error) { return s.g
 // Edge implements the avalanche.State interface
 func (s *Serializer) Edge() []ids.ID { return s.edge.List() }
 
+// VerifyDB implements the verify.Verifier interface. The accepted vertices are
+// walked from the accepted frontier, and each of them must be stored and
+// marked as accepted. A vertex is marked as accepted atomically with being
+// added to the frontier, so a stored ancestor of the frontier that isn't
+// marked as accepted is repaired by marking it as accepted. A missing or
+// unparsable vertex can't be repaired.
+func (s *Serializer) VerifyDB(repair bool, report *verify.Report) error {
+	defer s.db.Abort()
+
+	toVisit := s.Edge()
+	visited := ids.NewSet(len(toVisit))
+	visited.Add(toVisit...)
+	for len(toVisit) > 0 {
+		vtxID := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		report.NumChecked++
+
+		vtx := s.state.Vertex(vtxID)
+		if vtx == nil {
+			report.Add(vtxID.String(), false, "accepted vertex is missing or can't be parsed")
+			continue
+		}
+		if status := s.state.Status(vtxID); status != choices.Accepted {
+			if repair {
+				if err := s.state.SetStatus(vtxID, choices.Accepted); err != nil {
+					return err
+				}
+			}
+			report.Add(vtxID.String(), repair, "accepted vertex has status %s", status)
+		}
+		for _, parentID := range vtx.ParentIDs() {
+			if !visited.Contains(parentID) {
+				visited.Add(parentID)
+				toVisit = append(toVisit, parentID)
+			}
+		}
+	}
+	if !repair {
+		return nil
+	}
+	return s.db.Commit()
+}
+
 func (s *Serializer) parseVertex(b []byte) (vertex.StatelessVertex, error) {
-	vtx, err := vertex.Parse(b)
+	vtx, err := vertex.ParseWithHash(b, s.hash)
 	if err != nil {
 		return nil, err
 	}
@@ -8,18 +8,20 @@ import (
 	"strings"
 
 	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/utils/formatting"
-	"github.com/ava-labs/avalanchego/utils/hashing"
 )
 
 var (
-	_ cache.Evictable  = &uniqueVertex{}
-	_ avalanche.Vertex = &uniqueVertex{}
+	_ cache.Evictable         = &uniqueVertex{}
+	_ avalanche.Vertex        = &uniqueVertex{}
+	_ avalanche.BatchedVertex = &uniqueVertex{}
 )
 
 // uniqueVertex acts as a cache for vertices in the database.
@@ -39,7 +41,7 @@ type uniqueVertex struct {
 // and then parsing the vertex bytes on a cache miss.
 func newUniqueVertex(s *Serializer, b []byte) (*uniqueVertex, error) {
 	vtx := &uniqueVertex{
-		vtxID:      hashing.ComputeHash256Array(b),
+		vtxID:      s.hash(b),
 		serializer: s,
 	}
 	vtx.shallowRefresh()
@@ -164,7 +166,18 @@ func (vtx *uniqueVertex) setStatus(status choices.Status) error {
 func (vtx *uniqueVertex) ID() ids.ID       { return vtx.vtxID }
 func (vtx *uniqueVertex) Key() interface{} { return vtx.vtxID }
 
-func (vtx *uniqueVertex) Accept() error {
+// Accept marks the vertex as accepted and records the vertex in its
+// transactions
+func (vtx *uniqueVertex) Accept() error { return vtx.AcceptWithBatches() }
+
+// AcceptWithBatches is Accept, but also writes [batches] atomically with the
+// vertex. The vertex's status, the new edge, the writes the VM is holding back
+// for the vertex's transactions and [batches] are written together, so a
+// crash can't leave the VM or an index pointing at a vertex that isn't
+// accepted.
+func (vtx *uniqueVertex) AcceptWithBatches(batches ...database.Batch) error {
+	defer vtx.serializer.db.Abort()
+
 	if err := vtx.setStatus(choices.Accepted); err != nil {
 		return err
 	}
@@ -183,11 +196,32 @@ func (vtx *uniqueVertex) Accept() error {
 		return fmt.Errorf("failed to set edge while accepting vertex %s due to %w", vtx.vtxID, err)
 	}
 
+	txs, err := vtx.Txs()
+	if err != nil {
+		return err
+	}
+
+	epoch := vtx.v.vtx.Epoch()
+	for _, tx := range txs {
+		if tx, ok := tx.(vertex.AcceptedInVertexTx); ok {
+			if err := tx.AcceptedInVertex(vtx.vtxID, epoch); err != nil {
+				return fmt.Errorf("failed to record acceptance of tx %s in vertex %s due to %w", tx.ID(), vtx.vtxID, err)
+			}
+		}
+	}
+
 	// Should never traverse into parents of a decided vertex. Allows for the
 	// parents to be garbage collected
 	vtx.v.parents = nil
 
-	return vtx.serializer.db.Commit()
+	vtxBatch, err := vtx.serializer.db.CommitBatch()
+	if err != nil {
+		return err
+	}
+	if vm, ok := vtx.serializer.vm.(vertex.BatchCommitter); ok {
+		return vm.CommitWith(append([]database.Batch{vtxBatch}, batches...)...)
+	}
+	return atomic.WriteAll(vtxBatch, batches...)
 }
 
 func (vtx *uniqueVertex) Reject() error {
@@ -8,7 +8,12 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/verify"
+	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -337,3 +342,252 @@ func TestParseVertexWithInvalidTxs(t *testing.T) {
 		t.Fatal("the parent is invalid, so it shouldn't be marked as fetched")
 	}
 }
+
+type acceptedInVertexTx struct {
+	*snowstorm.TestTx
+
+	vtxID ids.ID
+	epoch uint32
+}
+
+func (tx *acceptedInVertexTx) AcceptedInVertex(vtxID ids.ID, epoch uint32) error {
+	tx.vtxID = vtxID
+	tx.epoch = epoch
+	return nil
+}
+
+func TestUniqueVertexAcceptNotifiesTxs(t *testing.T) {
+	testTx := &acceptedInVertexTx{TestTx: &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.ID{1},
+		StatusV: choices.Accepted,
+	}}}
+
+	s := newSerializer(t, func(b []byte) (snowstorm.Tx, error) {
+		if !bytes.Equal(b, []byte{0}) {
+			t.Fatal("unknown tx")
+		}
+		return testTx, nil
+	})
+
+	epoch := uint32(0) // Epochs other than 0 are currently invalid
+	vtx, err := vertex.Build(
+		ids.ID{}, // Same as chainID of serializer
+		1,
+		epoch,
+		[]ids.ID{{'p', 'a', 'r', 'e', 'n', 't'}},
+		[][]byte{{0}},
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uVtx := &uniqueVertex{
+		vtxID:      vtx.ID(),
+		serializer: s,
+	}
+	if err := uVtx.setVertex(vtx); err != nil {
+		t.Fatalf("Failed to set vertex due to: %s", err)
+	}
+	if err := uVtx.Accept(); err != nil {
+		t.Fatalf("Failed to accept vertex due to: %s", err)
+	}
+
+	if testTx.vtxID != vtx.ID() {
+		t.Fatalf("Tx should have been accepted in vertex %s but was %s", vtx.ID(), testTx.vtxID)
+	}
+	if testTx.epoch != epoch {
+		t.Fatalf("Tx should have been accepted in epoch %d but was %d", epoch, testTx.epoch)
+	}
+}
+
+// batchCommitterVM holds back the writes of its txs until they're committed
+// with a vertex
+type batchCommitterVM struct {
+	*vertex.TestVM
+
+	db  *versiondb.Database
+	err error
+}
+
+func (vm *batchCommitterVM) CommitWith(batches ...database.Batch) error {
+	defer vm.db.Abort()
+
+	if vm.err != nil {
+		return vm.err
+	}
+	batch, err := vm.db.CommitBatch()
+	if err != nil {
+		return err
+	}
+	return atomic.WriteAll(batch, batches...)
+}
+
+type pendingAcceptedInVertexTx struct {
+	*snowstorm.TestTx
+
+	db *versiondb.Database
+}
+
+func (tx *pendingAcceptedInVertexTx) AcceptedInVertex(vtxID ids.ID, _ uint32) error {
+	txID := tx.ID()
+	return tx.db.Put(txID[:], vtxID[:])
+}
+
+func TestUniqueVertexAcceptIsAtomic(t *testing.T) {
+	vm := &batchCommitterVM{TestVM: &vertex.TestVM{}}
+	vm.T = t
+	vm.Default(true)
+
+	baseDB := memdb.New()
+	vm.db = versiondb.New(prefixdb.New([]byte("vm"), baseDB))
+	testTx := &pendingAcceptedInVertexTx{
+		TestTx: &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+			IDV:     ids.ID{1},
+			StatusV: choices.Accepted,
+		}},
+		db: vm.db,
+	}
+	vm.ParseTxF = func([]byte) (snowstorm.Tx, error) { return testTx, nil }
+
+	s := &Serializer{}
+	s.Initialize(snow.DefaultContextTest(), vm, prefixdb.New([]byte("vertex"), baseDB))
+
+	vtx, err := vertex.Build(
+		ids.ID{}, // Same as chainID of serializer
+		1,
+		0,
+		[]ids.ID{{'p', 'a', 'r', 'e', 'n', 't'}},
+		[][]byte{{0}},
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uVtx := &uniqueVertex{
+		vtxID:      vtx.ID(),
+		serializer: s,
+	}
+	if err := uVtx.setVertex(vtx); err != nil {
+		t.Fatalf("Failed to set vertex due to: %s", err)
+	}
+
+	// Stands in for the writes of an index
+	indexDB := versiondb.New(prefixdb.New([]byte("index"), baseDB))
+	indexKey := []byte{'k', 'e', 'y'}
+	if err := indexDB.Put(indexKey, vtx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	indexBatch, err := indexDB.CommitBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// If the VM fails to commit, neither the vertex nor the other writes are
+	// written
+	errFailed := errors.New("failed")
+	vm.err = errFailed
+	if err := uVtx.AcceptWithBatches(indexBatch); !errors.Is(err, errFailed) {
+		t.Fatalf("Accept should have failed with %s but got %v", errFailed, err)
+	}
+	// Reads the vertices from the database rather than from [s]'s caches
+	persisted := func() *prefixedState {
+		s := &Serializer{}
+		s.Initialize(snow.DefaultContextTest(), vm, prefixdb.New([]byte("vertex"), baseDB))
+		return s.state
+	}
+	if status := persisted().Status(vtx.ID()); status == choices.Accepted {
+		t.Fatal("Vertex shouldn't have been written as accepted")
+	}
+	if has, err := prefixdb.New([]byte("index"), baseDB).Has(indexKey); err != nil || has {
+		t.Fatalf("Index shouldn't have been written but got (%v, %v)", has, err)
+	}
+
+	vm.err = nil
+	uVtx.v.status = choices.Processing
+	if err := uVtx.AcceptWithBatches(indexBatch); err != nil {
+		t.Fatalf("Failed to accept vertex due to: %s", err)
+	}
+	if status := persisted().Status(vtx.ID()); status != choices.Accepted {
+		t.Fatalf("Vertex should have been written as accepted but was %s", status)
+	}
+	txID := testTx.ID()
+	txVtxID, err := prefixdb.New([]byte("vm"), baseDB).Get(txID[:])
+	if err != nil {
+		t.Fatalf("Tx's vertex should have been written but got %s", err)
+	}
+	if vtxID := vtx.ID(); !bytes.Equal(txVtxID, vtxID[:]) {
+		t.Fatalf("Tx should have been accepted in vertex %s", vtx.ID())
+	}
+	if has, err := prefixdb.New([]byte("index"), baseDB).Has(indexKey); err != nil || !has {
+		t.Fatalf("Index should have been written but got (%v, %v)", has, err)
+	}
+}
+
+func TestSerializerVerifyDB(t *testing.T) {
+	s := newSerializer(t, nil)
+
+	parent, err := vertex.Build(s.ctx.ChainID, 0, 0, nil, [][]byte{{0}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := vertex.Build(s.ctx.ChainID, 1, 0, []ids.ID{parent.ID()}, [][]byte{{1}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The parent of the accepted frontier isn't marked as accepted
+	if err := s.state.SetVertex(parent); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.state.SetStatus(parent.ID(), choices.Processing); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.state.SetVertex(child); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.state.SetStatus(child.ID(), choices.Accepted); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.state.SetEdge([]ids.ID{child.ID()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.db.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	s.edge.Add(child.ID())
+
+	report := verify.NewReport("test")
+	if err := s.VerifyDB(false, report); err != nil {
+		t.Fatal(err)
+	}
+	if report.NumChecked != 2 || report.NumUnrepaired() != 1 {
+		t.Fatalf("expected 1 unrepaired issue in 2 vertices but got %+v", report)
+	}
+	if status := s.state.Status(parent.ID()); status != choices.Processing {
+		t.Fatalf("vertex shouldn't have been repaired but has status %s", status)
+	}
+
+	report = verify.NewReport("test")
+	if err := s.VerifyDB(true, report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Issues) != 1 || report.NumUnrepaired() != 0 {
+		t.Fatalf("expected 1 repaired issue but got %+v", report)
+	}
+	if status := s.state.Status(parent.ID()); status != choices.Accepted {
+		t.Fatalf("vertex should have been repaired but has status %s", status)
+	}
+
+	// A missing vertex can't be repaired
+	if err := s.state.state.SetVertex(parent.ID().Prefix(vtxID), nil); err != nil {
+		t.Fatal(err)
+	}
+	report = verify.NewReport("test")
+	if err := s.VerifyDB(true, report); err != nil {
+		t.Fatal(err)
+	}
+	if report.NumUnrepaired() != 1 {
+		t.Fatalf("expected 1 unrepaired issue but got %+v", report)
+	}
+}
@@ -0,0 +1,73 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var _ common.StateDumper = &Transitive{}
+
+// StateDump is a snapshot of the internal state of an avalanche engine
+type StateDump struct {
+	Bootstrapped bool `json:"bootstrapped"`
+	// Accepted vertices with no accepted children
+	Frontier []ids.ID `json:"frontier"`
+	// Processing vertices that are strongly preferred
+	Preferences []ids.ID `json:"preferences"`
+	// Processing vertices that are strongly virtuous
+	Virtuous []ids.ID `json:"virtuous"`
+	// Transactions that are virtuous but not in a preferred vertex
+	Orphans []ids.ID `json:"orphans"`
+	// Vertices that have been issued to consensus but not yet decided
+	Processing []ids.ID `json:"processing"`
+	// Vertices waiting on their dependencies before being issued to consensus
+	Pending []ids.ID `json:"pending"`
+	// Transactions that vertices are waiting on
+	MissingTxs []ids.ID `json:"missingTxs"`
+	// Transactions from the VM waiting for fewer vertices to be processing
+	PendingTxs []ids.ID `json:"pendingTxs"`
+	// Number of vertices requested from peers but not yet received
+	OutstandingVertexRequests int `json:"outstandingVertexRequests"`
+	// Number of polls that haven't finished
+	NumPolls int `json:"numPolls"`
+	// Description of the outstanding polls and the votes received so far
+	Polls string `json:"polls"`
+	// Vertex ID -> number of operations blocked on the vertex being issued
+	VtxBlocked map[string]int `json:"vtxBlocked"`
+	// Tx ID -> number of operations blocked on the tx being issued
+	TxBlocked map[string]int `json:"txBlocked"`
+}
+
+// DumpState implements the common.StateDumper interface
+func (t *Transitive) DumpState() (interface{}, error) {
+	state := &StateDump{
+		Bootstrapped:              t.Ctx.IsBootstrapped(),
+		Frontier:                  t.Manager.Edge(),
+		Preferences:               []ids.ID{},
+		Virtuous:                  []ids.ID{},
+		Orphans:                   []ids.ID{},
+		Processing:                []ids.ID{},
+		Pending:                   t.pending.List(),
+		MissingTxs:                t.missingTxs.List(),
+		PendingTxs:                make([]ids.ID, len(t.pendingTxs)),
+		OutstandingVertexRequests: t.outstandingVtxReqs.Len(),
+		NumPolls:                  t.polls.Len(),
+		Polls:                     t.polls.String(),
+		VtxBlocked:                t.vtxBlocked.Summary(),
+		TxBlocked:                 t.txBlocked.Summary(),
+	}
+	for i, tx := range t.pendingTxs {
+		state.PendingTxs[i] = tx.ID()
+	}
+	// Consensus is only initialized once bootstrapping has finished
+	if state.Bootstrapped {
+		state.Preferences = t.Consensus.Preferences().List()
+		state.Virtuous = t.Consensus.Virtuous().List()
+		state.Orphans = t.Consensus.Orphans().List()
+		state.Processing = t.Consensus.Processing().List()
+	}
+	return state, nil
+}
@@ -0,0 +1,71 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+const (
+	// Max number of vertices whose lifecycle is kept in memory
+	maxTracedVertices = 1024
+	// Max number of events kept in the lifecycle of a vertex
+	maxVertexTraceEvents = 64
+
+	// A vertex was received from a peer
+	traceReceived = "received"
+	// A vertex was built by this node
+	traceBuilt = "built"
+	// A vertex was queued to be issued to consensus once its dependencies are
+	// issued
+	traceQueued = "queued"
+	// A vertex was issued to consensus
+	traceIssued = "issued"
+	// A vertex won't be issued to consensus
+	traceAbandoned = "abandoned"
+	// A poll that a vertex received votes in finished
+	tracePoll = "poll"
+	// A vertex was accepted or rejected
+	traceAccepted = "accepted"
+	traceRejected = "rejected"
+)
+
+var _ common.ContainerTracer = &Transitive{}
+
+// TraceContainer implements the common.ContainerTracer interface
+func (t *Transitive) TraceContainer(vtxID ids.ID) (*common.Trace, bool) {
+	if t.tracer == nil {
+		return nil, false
+	}
+	return t.tracer.Get(vtxID)
+}
+
+// trace records an event in the lifecycle of [vtxID], if vertices are traced.
+// [args] are only formatted if they are.
+func (t *Transitive) trace(vtxID ids.ID, eventType string, nodeID ids.ShortID, format string, args ...interface{}) {
+	if t.tracer != nil {
+		t.tracer.Record(vtxID, eventType, nodeID, format, args...)
+	}
+}
+
+// traceDecisions records the decisions of the issued vertices that have been
+// decided since the last call
+func (t *Transitive) traceDecisions() {
+	if t.tracer == nil {
+		return
+	}
+	for vtxID, vtx := range t.tracedProcessing {
+		switch vtx.Status() {
+		case choices.Accepted:
+			t.trace(vtxID, traceAccepted, ids.ShortEmpty, "")
+		case choices.Rejected:
+			t.trace(vtxID, traceRejected, ids.ShortEmpty, "")
+		default:
+			continue
+		}
+		delete(t.tracedProcessing, vtxID)
+	}
+}
@@ -0,0 +1,139 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+func TestEngineTraceContainer(t *testing.T) {
+	testEngineTraceContainer(t, true)
+}
+
+func TestEngineTraceContainerDisabled(t *testing.T) {
+	testEngineTraceContainer(t, false)
+}
+
+func testEngineTraceContainer(t *testing.T, traceContainers bool) {
+	config := DefaultConfig()
+	config.TraceContainers = traceContainers
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	manager.Default(true)
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	mVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	vts := []avalanche.Vertex{gVtx, mVtx}
+
+	tx0 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx0.InputIDsV = append(tx0.InputIDsV, ids.GenerateTestID())
+
+	vtx0 := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0},
+		BytesV:   []byte{0, 1, 2, 3},
+	}
+
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID(), mVtx.ID()} }
+	manager.GetVtxF = func(id ids.ID) (avalanche.Vertex, error) {
+		switch id {
+		case gVtx.ID():
+			return gVtx, nil
+		case mVtx.ID():
+			return mVtx, nil
+		case vtx0.ID():
+			return vtx0, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+	manager.ParseVtxF = func([]byte) (avalanche.Vertex, error) { return vtx0, nil }
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := te.TraceContainer(vtx0.ID()); ok {
+		t.Fatalf("Shouldn't have traced an unknown vertex")
+	}
+
+	queryRequestID := new(uint32)
+	sender.PushQueryF = func(_ ids.ShortSet, requestID uint32, _ ids.ID, _ []byte) {
+		*queryRequestID = requestID
+	}
+	sender.ChitsF = func(ids.ShortID, uint32, []ids.ID) {}
+
+	if err := te.PushQuery(vdr, 0, vtx0.ID(), vtx0.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.Chits(vdr, *queryRequestID, []ids.ID{vtx0.ID()}); err != nil {
+		t.Fatal(err)
+	}
+	if vtx0.Status() != choices.Accepted {
+		t.Fatalf("Should have accepted the vertex")
+	}
+
+	trace, ok := te.TraceContainer(vtx0.ID())
+	if !traceContainers {
+		if ok {
+			t.Fatalf("Shouldn't have traced the vertex")
+		}
+		return
+	}
+	if !ok {
+		t.Fatalf("Should have traced the vertex")
+	}
+	expectedTypes := []string{traceReceived, traceQueued, traceIssued, tracePoll, traceAccepted}
+	if len(trace.Events) != len(expectedTypes) {
+		t.Fatalf("Expected %d events but got:\n%s", len(expectedTypes), trace)
+	}
+	for i, expectedType := range expectedTypes {
+		if trace.Events[i].Type != expectedType {
+			t.Fatalf("Expected event %d to be %q but got:\n%s", i, expectedType, trace)
+		}
+	}
+	if trace.Events[0].NodeID == "" {
+		t.Fatalf("Expected the receipt to name the sender")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ava-labs/avalanchego/health"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -27,6 +28,15 @@ const (
 	// TODO define this constant in one place rather than here and in snowman
 	// Max containers size in a MultiPut message
 	maxContainersLen = int(4 * network.DefaultMaxMessageSize / 5)
+
+	// Max number of the container IDs in a pushed accepted frontier that are
+	// fetched. Peers push the ID of each container as they accept it.
+	maxPushedFrontierSize = 4
+
+	// Pushed accepted frontiers are ignored while this many containers are
+	// already being fetched, so that peers can't make us send an unbounded
+	// number of requests.
+	maxPushedFrontierRequests = 64
 )
 
 var _ Engine = &Transitive{}
@@ -42,6 +52,9 @@ type Transitive struct {
 
 	polls poll.Set // track people I have asked for their preference
 
+	// tracks the portion of queries that fail
+	pollTracker *common.PollTracker
+
 	// The set of vertices that have been requested in Get messages but not yet received
 	outstandingVtxReqs common.Requests
 
@@ -64,6 +77,15 @@ type Transitive struct {
 	// A uniform sampler without replacement
 	uniformSampler sampler.Uniform
 
+	// tracer records the lifecycle of vertices. Nil if vertices aren't traced.
+	tracer *common.Tracer
+	// vertices that have been issued to consensus but whose decision hasn't
+	// been traced yet
+	tracedProcessing map[ids.ID]avalanche.Vertex
+
+	// number of repolls issued since a poll last decided vertices
+	repolls int
+
 	errs wrappers.Errs
 }
 
@@ -73,6 +95,10 @@ func (t *Transitive) Initialize(config Config) error {
 
 	t.Params = config.Params
 	t.Consensus = config.Consensus
+	t.pollTracker = config.PollTracker
+	if t.pollTracker == nil {
+		t.pollTracker = common.NewPollTracker(config.HealthConfig)
+	}
 
 	factory := poll.NewEarlyTermNoTraversalFactory(config.Params.Alpha)
 	t.polls = poll.NewSet(factory,
@@ -81,6 +107,10 @@ func (t *Transitive) Initialize(config Config) error {
 		config.Params.Metrics,
 	)
 	t.uniformSampler = sampler.NewUniform()
+	if config.TraceContainers {
+		t.tracer = common.NewTracer(maxTracedVertices, maxVertexTraceEvents)
+		t.tracedProcessing = make(map[ids.ID]avalanche.Vertex)
+	}
 
 	if err := t.metrics.Initialize(config.Params.Namespace, config.Params.Metrics); err != nil {
 		return err
@@ -140,6 +170,11 @@ func (t *Transitive) Gossip() error {
 // Shutdown implements the Engine interface
 func (t *Transitive) Shutdown() error {
 	t.Ctx.Log.Info("shutting down consensus engine")
+	if t.AuditLog != nil {
+		if err := t.AuditLog.Close(); err != nil {
+			t.Ctx.Log.Warn("failed to close the audit log: %s", err)
+		}
+	}
 	return t.VM.Shutdown()
 }
 
@@ -220,12 +255,48 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxByt
 		t.Ctx.Log.Verbo("vertex:\n%s", formatting.DumpBytes{Bytes: vtxBytes})
 		return t.GetFailed(vdr, requestID)
 	}
+	t.trace(vtx.ID(), traceReceived, vdr, "Put with request ID %d", requestID)
 	if _, err := t.issueFrom(vdr, vtx); err != nil {
 		return err
 	}
 	return t.attemptToIssueTxs()
 }
 
+// AcceptedFrontier implements the Engine interface
+func (t *Transitive) AcceptedFrontier(vdr ids.ShortID, requestID uint32, vtxIDs []ids.ID) error {
+	if requestID != constants.GossipMsgRequestID {
+		return t.Bootstrapper.AcceptedFrontier(vdr, requestID, vtxIDs)
+	}
+
+	// [vdr] pushed its newly accepted frontier to us. Fetch any of these
+	// vertices we don't know about so that we catch up without waiting for a
+	// poll.
+	if !t.Ctx.IsBootstrapped() {
+		t.Ctx.Log.Verbo("dropping pushed AcceptedFrontier(%s, %d, %s) due to bootstrapping",
+			vdr, requestID, vtxIDs)
+		return nil
+	}
+	if !t.Validators.Contains(ids.NodeIDFromShortID(vdr)) {
+		t.Ctx.Log.Verbo("dropping pushed AcceptedFrontier(%s, %d, %s) from a non-validator",
+			vdr, requestID, vtxIDs)
+		return nil
+	}
+	if t.outstandingVtxReqs.Len() >= maxPushedFrontierRequests {
+		t.Ctx.Log.Debug("dropping pushed AcceptedFrontier(%s, %d, %s) due to %d outstanding requests",
+			vdr, requestID, vtxIDs, t.outstandingVtxReqs.Len())
+		return nil
+	}
+	if len(vtxIDs) > maxPushedFrontierSize {
+		vtxIDs = vtxIDs[:maxPushedFrontierSize]
+	}
+	for _, vtxID := range vtxIDs {
+		if _, err := t.issueFromByID(vdr, vtxID); err != nil {
+			return err
+		}
+	}
+	return t.attemptToIssueTxs()
+}
+
 // GetFailed implements the Engine interface
 func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32) error {
 	if !t.Ctx.IsBootstrapped() { // Bootstrapping unfinished --> didn't call Get --> this message is invalid
@@ -302,6 +373,7 @@ func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, vtxID ids.ID,
 		t.Ctx.Log.Verbo("vertex:\n%s", formatting.DumpBytes{Bytes: vtxBytes})
 		return nil
 	}
+	t.trace(vtx.ID(), traceReceived, vdr, "PushQuery with request ID %d", requestID)
 
 	if _, err := t.issueFrom(vdr, vtx); err != nil {
 		return err
@@ -317,6 +389,24 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes []ids.ID) er
 		return nil
 	}
 
+	t.pollTracker.Succeeded()
+	return t.chits(vdr, requestID, votes)
+}
+
+// QueryFailed implements the Engine interface
+func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
+	if !t.Ctx.IsBootstrapped() {
+		t.Ctx.Log.Debug("dropping QueryFailed(%s, %d) due to bootstrapping", vdr, requestID)
+		return nil
+	}
+
+	t.pollTracker.Failed()
+	return t.chits(vdr, requestID, nil)
+}
+
+// chits applies the [votes] of [vdr] in response to the query [requestID]. If
+// the query failed, [votes] is empty.
+func (t *Transitive) chits(vdr ids.ShortID, requestID uint32, votes []ids.ID) error {
 	v := &voter{
 		t:         t,
 		vdr:       vdr,
@@ -335,13 +425,13 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes []ids.ID) er
 	return t.attemptToIssueTxs()
 }
 
-// QueryFailed implements the Engine interface
-func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
-	return t.Chits(vdr, requestID, nil)
-}
-
 // Notify implements the Engine interface
 func (t *Transitive) Notify(msg common.Message) error {
+	// state sync is part of bootstrapping, so the bootstrapper handles this
+	if msg == common.StateSyncDone {
+		return t.Bootstrapper.StateSyncDone()
+	}
+
 	if !t.Ctx.IsBootstrapped() {
 		t.Ctx.Log.Debug("dropping Notify due to bootstrapping")
 		return nil
@@ -349,7 +439,8 @@ func (t *Transitive) Notify(msg common.Message) error {
 
 	switch msg {
 	case common.PendingTxs:
-		t.pendingTxs = append(t.pendingTxs, t.VM.PendingTxs()...)
+		t.pendingTxs = append(t.pendingTxs, t.vmPendingTxs()...)
+		t.prioritizePendingTxs()
 		return t.attemptToIssueTxs()
 	default:
 		t.Ctx.Log.Warn("unexpected message from the VM: %s", msg)
@@ -373,6 +464,7 @@ func (t *Transitive) attemptToIssueTxs() error {
 func (t *Transitive) repoll() {
 	for i := t.polls.Len(); i < t.Params.ConcurrentRepolls && !t.errs.Errored(); i++ {
 		t.issueRepoll()
+		t.repolls++
 	}
 }
 
@@ -467,7 +559,7 @@ func (t *Transitive) issue(vtx avalanche.Vertex) error {
 	if err != nil {
 		return err
 	}
-	txIDs := ids.NewSet(len(txs))
+	txIDs := ids.GetSet()
 	for _, tx := range txs {
 		txIDs.Add(tx.ID())
 	}
@@ -482,9 +574,12 @@ func (t *Transitive) issue(vtx avalanche.Vertex) error {
 			}
 		}
 	}
+	ids.PutSet(txIDs)
 
 	t.Ctx.Log.Verbo("vertex %s is blocking on %d vertices and %d transactions",
 		vtxID, i.vtxDeps.Len(), i.txDeps.Len())
+	t.trace(vtxID, traceQueued, ids.ShortEmpty, "waiting on vertices %s and transactions %s",
+		i.vtxDeps, i.txDeps)
 
 	// Wait until all the parents of [vtx] are added to consensus before adding [vtx]
 	t.vtxBlocked.Register(&vtxIssuer{i: i})
@@ -514,15 +609,24 @@ func (t *Transitive) batch(txs []snowstorm.Tx, force, empty, limit bool) ([]snow
 	if limit && t.Params.OptimalProcessing <= t.Consensus.NumProcessing() {
 		return txs, nil
 	}
-	issuedTxs := ids.Set{}
-	consumed := ids.Set{}
+	// These sets only live for this call, so they are taken from the pool of
+	// sets rather than allocated each time a batch is issued
+	issuedTxs := ids.GetSet()
+	consumed := ids.GetSet()
+	inputs := ids.GetSet()
+	defer func() {
+		ids.PutSet(issuedTxs)
+		ids.PutSet(consumed)
+		ids.PutSet(inputs)
+	}()
+
 	issued := false
 	orphans := t.Consensus.Orphans()
 	start := 0
 	end := 0
 	for end < len(txs) {
 		tx := txs[end]
-		inputs := ids.Set{}
+		inputs.Clear()
 		inputs.Add(tx.InputIDs()...)
 		overlaps := consumed.Overlaps(inputs)
 		if end-start >= t.Params.BatchSize || (force && overlaps) {
@@ -571,19 +675,22 @@ func (t *Transitive) issueRepoll() {
 	}
 
 	vtxID := preferredIDs.CappedList(1)[0]
-	vdrs, err := t.Validators.Sample(t.Params.K) // Validators to sample
-	vdrBag := ids.ShortBag{}                     // IDs of validators to be sampled
+	vdrs, err := t.SampleValidators(t.Params.K, vtxID, t.RequestID+1) // Validators to sample
+	vdrBag := ids.NodeIDBag{}                                         // IDs of validators to be sampled
 	for _, vdr := range vdrs {
 		vdrBag.Add(vdr.ID())
 	}
 
 	vdrList := vdrBag.List()
 	vdrSet := ids.NewShortSet(len(vdrList))
-	vdrSet.Add(vdrList...)
+	vdrSet.Add(ids.NodeIDsToShortIDs(vdrList)...)
 
 	// Poll the network
 	t.RequestID++
-	if err == nil && t.polls.Add(t.RequestID, vdrBag) {
+	if err == nil && t.polls.Add(t.RequestID, vtxID, vdrBag) {
+		if t.AuditLog != nil {
+			t.AuditLog.StartPoll(t.RequestID)
+		}
 		t.Sender.PullQuery(vdrSet, t.RequestID, vtxID)
 	} else if err != nil {
 		t.Ctx.Log.Error("re-query for %s was dropped due to an insufficient number of validators", vtxID)
@@ -617,6 +724,7 @@ func (t *Transitive) issueBatch(txs []snowstorm.Tx) error {
 			len(parentIDs), len(txs))
 		return nil
 	}
+	t.trace(vtx.ID(), traceBuilt, ids.ShortEmpty, "%d transactions and parents %s", len(txs), parentIDs)
 	return t.issue(vtx)
 }
 
@@ -632,27 +740,27 @@ func (t *Transitive) sendRequest(vdr ids.ShortID, vtxID ids.ID) {
 	t.numVtxRequests.Set(float64(t.outstandingVtxReqs.Len())) // Tracks performance statistics
 }
 
-// Health implements the common.Engine interface
+// HealthCheck implements the common.Engine interface. Reports whether the
+// chain has bootstrapped, the health of consensus, the poll failure rate and
+// the health of the VM as separate components.
 func (t *Transitive) HealthCheck() (interface{}, error) {
-	var (
-		consensusIntf interface{} = struct{}{}
-		consensusErr  error
-	)
-	if t.Ctx.IsBootstrapped() {
-		consensusIntf, consensusErr = t.Consensus.HealthCheck()
+	components := health.Components{}
+
+	bootstrappedIntf, bootstrappedErr := common.BootstrappedHealthCheck(t.Ctx)
+	components.Add("bootstrapped", bootstrappedIntf, bootstrappedErr)
+
+	// Consensus is only initialized once bootstrapping has finished
+	if bootstrappedErr == nil {
+		consensusIntf, consensusErr := t.Consensus.HealthCheck()
+		components.Add("consensus", consensusIntf, consensusErr)
+
+		pollsIntf, pollsErr := t.pollTracker.HealthCheck()
+		components.Add("polls", pollsIntf, pollsErr)
 	}
+
 	vmIntf, vmErr := t.VM.HealthCheck()
-	intf := map[string]interface{}{
-		"consensus": consensusIntf,
-		"vm":        vmIntf,
-	}
-	if consensusErr == nil {
-		return intf, vmErr
-	}
-	if vmErr == nil {
-		return intf, consensusErr
-	}
-	return intf, fmt.Errorf("vm: %s ; consensus: %s", vmErr, consensusErr)
+	components.Add("vm", vmIntf, vmErr)
+	return components, components.Err()
 }
 
 // GetVtx returns a vertex by its ID.
@@ -0,0 +1,74 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+// BenchmarkEngineBatch measures batching txs into vertices, which builds the
+// sets of the issuer path for every batch. Building the vertices fails, so
+// the state of the engine doesn't grow between iterations.
+func BenchmarkEngineBatch(b *testing.B) {
+	for _, numTxs := range []int{10, 100, 1000} {
+		b.Run(strconv.Itoa(numTxs), func(b *testing.B) {
+			config := DefaultConfig()
+			config.Params.BatchSize = 30
+
+			vals := validators.NewSet()
+			if err := vals.AddWeight(ids.NodeIDFromShortID(ids.GenerateTestShortID()), 1); err != nil {
+				b.Fatal(err)
+			}
+			config.Validators = vals
+
+			gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Accepted,
+			}}
+			manager := &vertex.TestManager{}
+			manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+			manager.GetVtxF = func(ids.ID) (avalanche.Vertex, error) { return gVtx, nil }
+			manager.BuildVtxF = func(uint32, []ids.ID, []snowstorm.Tx, []ids.ID) (avalanche.Vertex, error) {
+				return nil, errors.New("not building vertices")
+			}
+			config.Manager = manager
+			config.VM = &vertex.TestVM{}
+
+			te := &Transitive{}
+			if err := te.Initialize(config); err != nil {
+				b.Fatal(err)
+			}
+
+			txs := make([]snowstorm.Tx, numTxs)
+			for i := range txs {
+				txs[i] = &snowstorm.TestTx{
+					TestDecidable: choices.TestDecidable{
+						IDV:     ids.GenerateTestID(),
+						StatusV: choices.Processing,
+					},
+					InputIDsV: []ids.ID{ids.GenerateTestID()},
+				}
+			}
+			batch := make([]snowstorm.Tx, numTxs)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				copy(batch, txs)
+				if _, err := te.batch(batch, true, false, false); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
@@ -57,7 +58,7 @@ func TestEngineAdd(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -155,7 +156,7 @@ func TestEngineQuery(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -435,9 +436,9 @@ func TestEngineMultipleQuery(t *testing.T) {
 
 	errs := wrappers.Errs{}
 	errs.Add(
-		vals.AddWeight(vdr0, 1),
-		vals.AddWeight(vdr1, 1),
-		vals.AddWeight(vdr2, 1),
+		vals.AddWeight(ids.NodeIDFromShortID(vdr0), 1),
+		vals.AddWeight(ids.NodeIDFromShortID(vdr1), 1),
+		vals.AddWeight(ids.NodeIDFromShortID(vdr2), 1),
 	)
 	if errs.Errored() {
 		t.Fatal(errs.Err)
@@ -599,7 +600,7 @@ func TestEngineBlockedIssue(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -675,7 +676,7 @@ func TestEngineAbandonResponse(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -748,7 +749,7 @@ func TestEngineScheduleRepoll(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -842,7 +843,7 @@ func TestEngineRejectDoubleSpendTx(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -950,7 +951,7 @@ func TestEngineRejectDoubleSpendIssuedTx(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1063,7 +1064,7 @@ func TestEngineIssueRepoll(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1132,7 +1133,7 @@ func TestEngineReissue(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1309,7 +1310,7 @@ func TestEngineLargeIssue(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1452,7 +1453,7 @@ func TestEngineGetVertex(t *testing.T) {
 	}
 
 	sender.PutF = func(v ids.ShortID, _ uint32, vtxID ids.ID, vtx []byte) {
-		if v != vdr.ID() {
+		if v != vdr.ID().ShortID() {
 			t.Fatalf("Wrong validator")
 		}
 		if mVtx.ID() != vtxID {
@@ -1460,7 +1461,7 @@ func TestEngineGetVertex(t *testing.T) {
 		}
 	}
 
-	if err := te.Get(vdr.ID(), 0, mVtx.ID()); err != nil {
+	if err := te.Get(vdr.ID().ShortID(), 0, mVtx.ID()); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -1542,7 +1543,7 @@ func TestEnginePushGossip(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1629,7 +1630,7 @@ func TestEngineSingleQuery(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1700,7 +1701,7 @@ func TestEngineParentBlockingInsert(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1804,7 +1805,7 @@ func TestEngineBlockingChitRequest(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1925,7 +1926,7 @@ func TestEngineBlockingChitResponse(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -2057,7 +2058,7 @@ func TestEngineMissingTx(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -2189,7 +2190,7 @@ func TestEngineIssueBlockingTx(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -2250,7 +2251,7 @@ func TestEngineReissueAbortedVertex(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -2379,7 +2380,7 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 	config.Beacons = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -2638,7 +2639,7 @@ func TestEngineReBootstrapFails(t *testing.T) {
 	config.Beacons = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -2781,7 +2782,7 @@ func TestEngineReBootstrappingIntoConsensus(t *testing.T) {
 
 	vals := validators.NewSet()
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -3048,7 +3049,7 @@ func TestEngineUndeclaredDependencyDeadlock(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -3149,7 +3150,7 @@ func TestEnginePartiallyValidVertex(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -3283,10 +3284,10 @@ func TestEngineInvalidVertexIgnoredFromUnexpectedPeer(t *testing.T) {
 	vdr := ids.GenerateTestShortID()
 	secondVdr := ids.GenerateTestShortID()
 
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
-	if err := vals.AddWeight(secondVdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(secondVdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -3426,7 +3427,7 @@ func TestEnginePushQueryRequestIDConflict(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -3575,7 +3576,7 @@ func TestEngineAggressivePolling(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -3694,7 +3695,7 @@ func TestEngineDuplicatedIssuance(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -3805,10 +3806,10 @@ func TestEngineDoubleChit(t *testing.T) {
 	vdr0 := ids.GenerateTestShortID()
 	vdr1 := ids.GenerateTestShortID()
 
-	if err := vals.AddWeight(vdr0, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr0), 1); err != nil {
 		t.Fatal(err)
 	}
-	if err := vals.AddWeight(vdr1, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr1), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -3930,7 +3931,7 @@ func TestEngineBubbleVotes(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	err := vals.AddWeight(vdr, 1)
+	err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1)
 	assert.NoError(t, err)
 
 	sender := &common.SenderTest{}
@@ -4086,7 +4087,7 @@ func TestEngineIssue(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -4213,3 +4214,423 @@ func TestEngineIssue(t *testing.T) {
 		t.Fatalf("Should have issued txs differently")
 	}
 }
+
+// batchVerifierVM is a DAGVM that verifies txs as a batch
+type batchVerifierVM struct {
+	*vertex.TestVM
+
+	verifyTxsF func([]snowstorm.Tx) []error
+}
+
+func (vm *batchVerifierVM) VerifyTxs(txs []snowstorm.Tx) []error { return vm.verifyTxsF(txs) }
+
+func TestEngineBatchVerifiesTxs(t *testing.T) {
+	config := DefaultConfig()
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	vts := []avalanche.Vertex{gVtx}
+	utxos := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+
+	tx0 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx0.InputIDsV = append(tx0.InputIDsV, utxos[0])
+
+	// tx1 is only invalid when it's verified in a batch with tx0
+	tx1 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx1.InputIDsV = append(tx1.InputIDsV, utxos[1])
+
+	vtx := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentsV: vts,
+		HeightV:  1,
+		TxsV:     []snowstorm.Tx{tx0, tx1},
+	}
+
+	vm := &batchVerifierVM{TestVM: &vertex.TestVM{}}
+	vm.T = t
+	verifiedBatch := false
+	vm.verifyTxsF = func(txs []snowstorm.Tx) []error {
+		if len(txs) != 2 {
+			return make([]error, len(txs))
+		}
+		verifiedBatch = true
+		return []error{nil, errors.New("invalid in this batch")}
+	}
+	config.VM = vm
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedVtxID := ids.GenerateTestID()
+	manager.BuildVtxF = func(_ uint32, _ []ids.ID, txs []snowstorm.Tx, _ []ids.ID) (avalanche.Vertex, error) {
+		if len(txs) != 1 || txs[0].ID() != tx0.ID() {
+			t.Fatalf("only tx0 should have been reissued")
+		}
+		return &avalanche.TestVertex{
+			TestDecidable: choices.TestDecidable{
+				IDV:     expectedVtxID,
+				StatusV: choices.Processing,
+			},
+			ParentsV: vts,
+			HeightV:  1,
+			TxsV:     txs,
+			BytesV:   []byte{1},
+		}, nil
+	}
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	te.Sender = sender
+
+	sender.PushQueryF = func(_ ids.ShortSet, _ uint32, vtxID ids.ID, _ []byte) {
+		if expectedVtxID != vtxID {
+			t.Fatalf("wrong vertex queried")
+		}
+	}
+
+	if err := te.issue(vtx); err != nil {
+		t.Fatal(err)
+	}
+	if !verifiedBatch {
+		t.Fatalf("the txs should have been verified as a batch")
+	}
+}
+
+// mempoolVM is a DAGVM whose mempool pops [txs] in order
+type mempoolVM struct {
+	*vertex.TestVM
+
+	txs []common.PendingTx
+}
+
+func (vm *mempoolVM) MempoolTxs() []common.MempoolTx       { return nil }
+func (vm *mempoolVM) EvictTx(ids.ID) bool                  { return false }
+func (vm *mempoolVM) AddTx(common.PendingTx, uint64) error { return nil }
+func (vm *mempoolVM) NumTxs() int                          { return len(vm.txs) }
+
+func (vm *mempoolVM) PopTx() (common.PendingTx, bool) {
+	if len(vm.txs) == 0 {
+		return nil, false
+	}
+	tx := vm.txs[0]
+	vm.txs = vm.txs[1:]
+	return tx, true
+}
+
+func TestEngineIssueFromMempool(t *testing.T) {
+	config := DefaultConfig()
+	config.Params.BatchSize = 1
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	manager.Default(true)
+
+	// PendingTxs shouldn't be called, since the engine takes the txs from the
+	// mempool
+	vm := &mempoolVM{TestVM: &vertex.TestVM{}}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	tx0 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx0.InputIDsV = append(tx0.InputIDsV, ids.GenerateTestID())
+
+	tx1 := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Processing,
+	}}
+	tx1.InputIDsV = append(tx1.InputIDsV, ids.GenerateTestID())
+
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	manager.GetVtxF = func(id ids.ID) (avalanche.Vertex, error) {
+		if id != gVtx.ID() {
+			t.Fatalf("Unknown vertex")
+		}
+		return gVtx, nil
+	}
+
+	vm.CantBootstrapping = false
+	vm.CantBootstrapped = false
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	vm.CantBootstrapping = true
+	vm.CantBootstrapped = true
+
+	issued := []ids.ID(nil)
+	manager.BuildVtxF = func(_ uint32, _ []ids.ID, txs []snowstorm.Tx, _ []ids.ID) (avalanche.Vertex, error) {
+		for _, tx := range txs {
+			issued = append(issued, tx.ID())
+		}
+		return &avalanche.TestVertex{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentsV: []avalanche.Vertex{gVtx},
+			HeightV:  1,
+			TxsV:     txs,
+			BytesV:   []byte{1},
+		}, nil
+	}
+
+	sender.CantPushQuery = false
+
+	vm.txs = []common.PendingTx{tx1, tx0}
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(issued) != 2 || issued[0] != tx1.ID() || issued[1] != tx0.ID() {
+		t.Fatalf("Should have issued the txs in the order they were popped")
+	}
+	if vm.NumTxs() != 0 {
+		t.Fatalf("Should have popped every tx from the mempool")
+	}
+}
+
+// feeReporterVM is a DAGVM that gives txs the priorities in [priorities]
+type feeReporterVM struct {
+	*vertex.TestVM
+
+	priorities map[ids.ID]uint64
+}
+
+func (vm *feeReporterVM) FeeLevels() (common.FeeLevels, error)  { return common.FeeLevels{}, nil }
+func (vm *feeReporterVM) TxPriority(tx common.PendingTx) uint64 { return vm.priorities[tx.ID()] }
+
+func TestEngineIssuesTxsByPriority(t *testing.T) {
+	config := DefaultConfig()
+	config.Params.BatchSize = 1
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	manager.Default(true)
+
+	vm := &feeReporterVM{TestVM: &vertex.TestVM{}}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	txs := make([]snowstorm.Tx, 3)
+	for i := range txs {
+		tx := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		}}
+		tx.InputIDsV = append(tx.InputIDsV, ids.GenerateTestID())
+		txs[i] = tx
+	}
+	vm.priorities = map[ids.ID]uint64{
+		txs[0].ID(): 1,
+		txs[1].ID(): 5,
+		txs[2].ID(): 1,
+	}
+
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	manager.GetVtxF = func(id ids.ID) (avalanche.Vertex, error) {
+		if id != gVtx.ID() {
+			t.Fatalf("Unknown vertex")
+		}
+		return gVtx, nil
+	}
+
+	vm.CantBootstrapping = false
+	vm.CantBootstrapped = false
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	vm.CantBootstrapping = true
+	vm.CantBootstrapped = true
+
+	issued := []ids.ID(nil)
+	manager.BuildVtxF = func(_ uint32, _ []ids.ID, txs []snowstorm.Tx, _ []ids.ID) (avalanche.Vertex, error) {
+		for _, tx := range txs {
+			issued = append(issued, tx.ID())
+		}
+		return &avalanche.TestVertex{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentsV: []avalanche.Vertex{gVtx},
+			HeightV:  1,
+			TxsV:     txs,
+			BytesV:   []byte{1},
+		}, nil
+	}
+
+	sender.CantPushQuery = false
+
+	vm.PendingTxsF = func() []snowstorm.Tx { return txs }
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ids.ID{txs[1].ID(), txs[0].ID(), txs[2].ID()}
+	if len(issued) != len(expected) {
+		t.Fatalf("expected %d txs to be issued but %d were", len(expected), len(issued))
+	}
+	for i, txID := range expected {
+		if issued[i] != txID {
+			t.Fatalf("expected tx %s to be issued at index %d but got %s", txID, i, issued[i])
+		}
+	}
+}
+
+func TestEnginePushedAcceptedFrontier(t *testing.T) {
+	config := DefaultConfig()
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
+		t.Fatal(err)
+	}
+	nonVdr := ids.GenerateTestShortID()
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	manager.Default(true)
+
+	manager.CantEdge = false
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	manager.GetVtxF = func(ids.ID) (avalanche.Vertex, error) { return nil, errUnknownVertex }
+
+	requested := ids.Set{}
+	sender.GetF = func(inVdr ids.ShortID, _ uint32, vtxID ids.ID) {
+		if inVdr != vdr {
+			t.Fatalf("Asking wrong validator for vertex")
+		}
+		requested.Add(vtxID)
+	}
+
+	newFrontier := func(size int) []ids.ID {
+		vtxIDs := make([]ids.ID, size)
+		for i := range vtxIDs {
+			vtxIDs[i] = ids.GenerateTestID()
+		}
+		return vtxIDs
+	}
+
+	// Frontiers pushed by non-validators are ignored
+	if err := te.AcceptedFrontier(nonVdr, constants.GossipMsgRequestID, newFrontier(1)); err != nil {
+		t.Fatal(err)
+	}
+	if requested.Len() != 0 {
+		t.Fatalf("Shouldn't have requested vertices pushed by a non-validator")
+	}
+
+	// Only the first vertices of a large frontier are fetched
+	if err := te.AcceptedFrontier(vdr, constants.GossipMsgRequestID, newFrontier(maxPushedFrontierSize+1)); err != nil {
+		t.Fatal(err)
+	}
+	if requested.Len() != maxPushedFrontierSize {
+		t.Fatalf("Should have requested %d vertices but requested %d", maxPushedFrontierSize, requested.Len())
+	}
+
+	// Once enough vertices are being fetched, pushed frontiers are ignored
+	for requested.Len() < maxPushedFrontierRequests {
+		if err := te.AcceptedFrontier(vdr, constants.GossipMsgRequestID, newFrontier(maxPushedFrontierSize)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := te.AcceptedFrontier(vdr, constants.GossipMsgRequestID, newFrontier(1)); err != nil {
+		t.Fatal(err)
+	}
+	if requested.Len() != maxPushedFrontierRequests {
+		t.Fatalf("Should have stopped requesting vertices after %d requests but requested %d", maxPushedFrontierRequests, requested.Len())
+	}
+}
@@ -21,7 +21,8 @@ type Builder interface {
 	) (avalanche.Vertex, error)
 }
 
-// Build a new stateless vertex from the contents of a vertex
+// Build a new stateless vertex from the contents of a vertex. The ID of the
+// vertex is the SHA-256 hash of its bytes.
 func Build(
 	chainID ids.ID,
 	height uint64,
@@ -29,6 +30,20 @@ func Build(
 	parentIDs []ids.ID,
 	txs [][]byte,
 	restrictions []ids.ID,
+) (StatelessVertex, error) {
+	return BuildWithHash(chainID, height, epoch, parentIDs, txs, restrictions, hashing.ComputeHash256Array)
+}
+
+// BuildWithHash builds a new stateless vertex like Build, but derives the ID
+// of the vertex with [hash]
+func BuildWithHash(
+	chainID ids.ID,
+	height uint64,
+	epoch uint32,
+	parentIDs []ids.ID,
+	txs [][]byte,
+	restrictions []ids.ID,
+	hash hashing.Hasher256,
 ) (StatelessVertex, error) {
 	ids.SortIDs(parentIDs)
 	SortHashOf(txs)
@@ -50,7 +65,7 @@ func Build(
 	vtxBytes, err := c.Marshal(innerVtx.Version, innerVtx)
 	vtx := statelessVertex{
 		innerStatelessVertex: innerVtx,
-		id:                   hashing.ComputeHash256Array(vtxBytes),
+		id:                   hash(vtxBytes),
 		bytes:                vtxBytes,
 	}
 	return vtx, err
@@ -48,6 +48,20 @@ func (_m *DAGVM) Bootstrapping() error {
 	return r0
 }
 
+// Connected provides a mock function with given fields: id
+func (_m *DAGVM) Connected(id ids.ShortID) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(ids.ShortID) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateHandlers provides a mock function with given fields:
 func (_m *DAGVM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 	ret := _m.Called()
@@ -71,6 +85,43 @@ func (_m *DAGVM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 	return r0, r1
 }
 
+// CreateStaticHandlers provides a mock function with given fields:
+func (_m *DAGVM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
+	ret := _m.Called()
+
+	var r0 map[string]*common.HTTPHandler
+	if rf, ok := ret.Get(0).(func() map[string]*common.HTTPHandler); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]*common.HTTPHandler)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Disconnected provides a mock function with given fields: id
+func (_m *DAGVM) Disconnected(id ids.ShortID) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(ids.ShortID) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetTx provides a mock function with given fields: _a0
 func (_m *DAGVM) GetTx(_a0 ids.ID) (snowstorm.Tx, error) {
 	ret := _m.Called(_a0)
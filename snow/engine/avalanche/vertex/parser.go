@@ -14,14 +14,21 @@ type Parser interface {
 	ParseVtx(vertex []byte) (avalanche.Vertex, error)
 }
 
-// Parse the provided vertex bytes into a stateless vertex
+// Parse the provided vertex bytes into a stateless vertex whose ID is the
+// SHA-256 hash of its bytes
 func Parse(vertex []byte) (StatelessVertex, error) {
+	return ParseWithHash(vertex, hashing.ComputeHash256Array)
+}
+
+// ParseWithHash parses the provided vertex bytes into a stateless vertex whose
+// ID is derived with [hash]
+func ParseWithHash(vertex []byte, hash hashing.Hasher256) (StatelessVertex, error) {
 	vtx := innerStatelessVertex{}
 	version, err := c.Unmarshal(vertex, &vtx)
 	vtx.Version = version
 	return statelessVertex{
 		innerStatelessVertex: vtx,
-		id:                   hashing.ComputeHash256Array(vertex),
+		id:                   hash(vertex),
 		bytes:                vertex,
 	}, err
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -35,3 +36,28 @@ func TestParseValid(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, vtx, parsedVtx)
 }
+
+func TestParseWithHash(t *testing.T) {
+	vtx, err := BuildWithHash(
+		ids.ID{1},
+		2,
+		0,
+		[]ids.ID{{4}, {5}},
+		[][]byte{{6}, {7}},
+		nil,
+		hashing.ComputeBLAKE3Array,
+	)
+	assert.NoError(t, err)
+
+	vtxBytes := vtx.Bytes()
+	assert.Equal(t, ids.ID(hashing.ComputeBLAKE3Array(vtxBytes)), vtx.ID())
+
+	parsedVtx, err := ParseWithHash(vtxBytes, hashing.ComputeBLAKE3Array)
+	assert.NoError(t, err)
+	assert.Equal(t, vtx, parsedVtx)
+
+	// The same vertex has a different ID under the default hash
+	sha256Vtx, err := Parse(vtxBytes)
+	assert.NoError(t, err)
+	assert.NotEqual(t, vtx.ID(), sha256Vtx.ID())
+}
@@ -4,6 +4,7 @@
 package vertex
 
 import (
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
@@ -23,3 +24,39 @@ type DAGVM interface {
 	// Retrieve a transaction that was submitted previously
 	GetTx(ids.ID) (snowstorm.Tx, error)
 }
+
+// AcceptedInVertexTx is optionally implemented by transactions that record the
+// vertex they were accepted in
+type AcceptedInVertexTx interface {
+	snowstorm.Tx
+
+	// AcceptedInVertex is called when a vertex containing this accepted
+	// transaction is accepted. If the transaction is included in multiple
+	// vertices, this may be called multiple times.
+	AcceptedInVertex(vtxID ids.ID, epoch uint32) error
+}
+
+// BatchCommitter is optionally implemented by DAGVMs that hold back the writes
+// of accepted transactions until the vertex they were accepted in is
+// accepted, so that the transactions' state is written atomically with the
+// vertex.
+type BatchCommitter interface {
+	DAGVM
+
+	// CommitWith writes the VM's pending writes together with [batches] in a
+	// single atomic write. The batches must be over the same underlying
+	// database as the VM.
+	CommitWith(batches ...database.Batch) error
+}
+
+// BatchVerifierVM is optionally implemented by DAGVMs that can verify the
+// transactions of a vertex as a batch, for example to share signature
+// verification and database reads between them.
+type BatchVerifierVM interface {
+	DAGVM
+
+	// VerifyTxs verifies each of [txs], as if Verify was called on each of
+	// them, and returns one error per transaction. The error of a transaction
+	// is nil iff the transaction is valid.
+	VerifyTxs(txs []snowstorm.Tx) []error
+}
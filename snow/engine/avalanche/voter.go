@@ -34,7 +34,10 @@ func (v *voter) Update() {
 		return
 	}
 
-	results, finished := v.t.polls.Vote(v.requestID, v.vdr, v.response)
+	if v.t.AuditLog != nil {
+		v.t.AuditLog.RecordChit(v.requestID, v.vdr, v.response)
+	}
+	results, finished := v.t.polls.Vote(v.requestID, ids.NodeIDFromShortID(v.vdr), v.response)
 	if !finished {
 		return
 	}
@@ -44,11 +47,29 @@ func (v *voter) Update() {
 		return
 	}
 
+	if v.t.AuditLog != nil {
+		votes := make(map[ids.ID]int, len(results))
+		for vtxID, set := range results {
+			votes[vtxID] = set.Len()
+		}
+		v.t.AuditLog.FinishPoll(v.requestID, votes)
+	}
+
 	v.t.Ctx.Log.Debug("Finishing poll with:\n%s", &results)
+	for vtxID, set := range results {
+		v.t.trace(vtxID, tracePoll, ids.ShortEmpty, "request ID %d finished with %d votes out of %d",
+			v.requestID, set.Len(), v.t.Params.K)
+	}
+	numProcessing := v.t.Consensus.NumProcessing()
 	if err := v.t.Consensus.RecordPoll(results); err != nil {
 		v.t.errs.Add(err)
 		return
 	}
+	if v.t.Consensus.NumProcessing() < numProcessing {
+		v.t.repollDepth.Observe(float64(v.t.repolls))
+		v.t.repolls = 0
+	}
+	v.t.traceDecisions()
 
 	orphans := v.t.Consensus.Orphans()
 	txs := make([]snowstorm.Tx, 0, orphans.Len())
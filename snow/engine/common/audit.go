@@ -0,0 +1,212 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+const (
+	// AuditHandlerID is the identifier that audit logs are registered with in
+	// the consensus event dispatcher
+	AuditHandlerID = "audit"
+
+	// DefaultMaxAuditPolls is the default number of polls kept in memory for
+	// each processing container
+	DefaultMaxAuditPolls = 64
+)
+
+// AuditChit is the response of a validator to a poll
+type AuditChit struct {
+	NodeID string `json:"nodeID"`
+	// Containers that the validator voted for. Empty if the validator didn't
+	// respond to the poll.
+	Votes []ids.ID `json:"votes"`
+}
+
+// AuditPoll is a finished poll that a container received votes in
+type AuditPoll struct {
+	RequestID uint32    `json:"requestID"`
+	Finished  time.Time `json:"finished"`
+	// Votes the container received in the poll, after the votes for its
+	// descendants were applied to it
+	Votes int `json:"votes"`
+	// Responses of the validators that answered before the poll finished
+	Chits []AuditChit `json:"chits"`
+}
+
+// AuditDecision is an entry of the audit log. It records a decision of a
+// container and the polls that justified it.
+type AuditDecision struct {
+	Time        time.Time `json:"time"`
+	ChainID     ids.ID    `json:"chainID"`
+	ContainerID ids.ID    `json:"containerID"`
+	Status      string    `json:"status"`
+	// True if the container was decided while the chain was bootstrapping, in
+	// which case it wasn't polled
+	Bootstrapping bool        `json:"bootstrapping"`
+	Polls         []AuditPoll `json:"polls"`
+	// Number of older polls that were dropped because too many polls were kept
+	DroppedPolls int `json:"droppedPolls"`
+}
+
+type auditedContainer struct {
+	polls        []AuditPoll
+	droppedPolls int
+}
+
+// AuditLog is an append-only log of the decisions of a chain's containers.
+// Each decision is written as a line holding a JSON AuditDecision.
+//
+// The engine of the chain reports the chits of its polls to the audit log. The
+// audit log is registered as a handler of the consensus events of the chain,
+// so that it's told about the decisions of the containers.
+type AuditLog struct {
+	lock     sync.Mutex
+	clock    timer.Clock
+	maxPolls int
+	w        io.WriteCloser
+
+	// request ID --> chits received so far in the outstanding poll
+	outstanding map[uint32][]AuditChit
+	// container ID --> polls that the container received votes in
+	containers map[ids.ID]*auditedContainer
+}
+
+// NewAuditLog returns an audit log that writes to [w]. Up to [maxPolls] polls
+// are kept for each processing container. When a container has more polls,
+// the oldest ones are dropped.
+func NewAuditLog(w io.WriteCloser, maxPolls int) *AuditLog {
+	return &AuditLog{
+		maxPolls:    maxPolls,
+		w:           w,
+		outstanding: make(map[uint32][]AuditChit),
+		containers:  make(map[ids.ID]*auditedContainer),
+	}
+}
+
+// OpenAuditLog returns an audit log that appends to the file at [path]
+func OpenAuditLog(path string, maxPolls int) (*AuditLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, perms.ReadWrite)
+	if err != nil {
+		return nil, err
+	}
+	return NewAuditLog(f, maxPolls), nil
+}
+
+// StartPoll starts recording the chits of poll [requestID]
+func (a *AuditLog) StartPoll(requestID uint32) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.outstanding[requestID] = []AuditChit{}
+}
+
+// RecordChit records that [nodeID] voted for [votes] in poll [requestID].
+// [votes] is empty if [nodeID] didn't respond. Chits of polls that aren't
+// outstanding are ignored.
+func (a *AuditLog) RecordChit(requestID uint32, nodeID ids.ShortID, votes []ids.ID) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	chits, ok := a.outstanding[requestID]
+	if !ok {
+		return
+	}
+	a.outstanding[requestID] = append(chits, AuditChit{
+		NodeID: address.FormatNodeID(nodeID),
+		Votes:  append([]ids.ID{}, votes...),
+	})
+}
+
+// FinishPoll records that poll [requestID] finished with [results], which
+// maps the containers that received votes in the poll to their number of
+// votes
+func (a *AuditLog) FinishPoll(requestID uint32, results map[ids.ID]int) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	chits, ok := a.outstanding[requestID]
+	if !ok {
+		return
+	}
+	delete(a.outstanding, requestID)
+
+	finished := a.clock.Time()
+	for containerID, votes := range results {
+		container, ok := a.containers[containerID]
+		if !ok {
+			container = &auditedContainer{}
+			a.containers[containerID] = container
+		}
+		poll := AuditPoll{
+			RequestID: requestID,
+			Finished:  finished,
+			Votes:     votes,
+			Chits:     chits,
+		}
+		if len(container.polls) < a.maxPolls {
+			container.polls = append(container.polls, poll)
+			continue
+		}
+		copy(container.polls, container.polls[1:])
+		container.polls[len(container.polls)-1] = poll
+		container.droppedPolls++
+	}
+}
+
+// Accept implements the triggers.Acceptor interface
+func (a *AuditLog) Accept(ctx *snow.Context, containerID ids.ID, _ []byte) error {
+	return a.decide(ctx, containerID, "accepted")
+}
+
+// Reject implements the triggers.Rejector interface
+func (a *AuditLog) Reject(ctx *snow.Context, containerID ids.ID, _ []byte) error {
+	return a.decide(ctx, containerID, "rejected")
+}
+
+// decide writes the decision of [containerID] to the log and forgets its polls
+func (a *AuditLog) decide(ctx *snow.Context, containerID ids.ID, status string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	decision := AuditDecision{
+		Time:          a.clock.Time(),
+		ChainID:       ctx.ChainID,
+		ContainerID:   containerID,
+		Status:        status,
+		Bootstrapping: !ctx.IsBootstrapped(),
+		Polls:         []AuditPoll{},
+	}
+	if container, ok := a.containers[containerID]; ok {
+		decision.Polls = container.polls
+		decision.DroppedPolls = container.droppedPolls
+		delete(a.containers, containerID)
+	}
+
+	line, err := json.Marshal(decision)
+	if err != nil {
+		return err
+	}
+	_, err = a.w.Write(append(line, '\n'))
+	return err
+}
+
+// Close the underlying writer
+func (a *AuditLog) Close() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.w.Close()
+}
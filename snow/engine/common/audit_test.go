@@ -0,0 +1,121 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+)
+
+type nopWriteCloser struct{ bytes.Buffer }
+
+func (*nopWriteCloser) Close() error { return nil }
+
+func readAuditDecisions(t *testing.T, b []byte) []AuditDecision {
+	decisions := []AuditDecision(nil)
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		decision := AuditDecision{}
+		if err := json.Unmarshal(scanner.Bytes(), &decision); err != nil {
+			t.Fatal(err)
+		}
+		decisions = append(decisions, decision)
+	}
+	return decisions
+}
+
+func TestAuditLog(t *testing.T) {
+	assert := assert.New(t)
+
+	w := &nopWriteCloser{}
+	auditLog := NewAuditLog(w, 2)
+	auditLog.clock.Set(time.Unix(1000, 0))
+
+	ctx := snow.DefaultContextTest()
+	ctx.Bootstrapped()
+
+	accepted := ids.GenerateTestID()
+	rejected := ids.GenerateTestID()
+	vdr0 := ids.GenerateTestShortID()
+	vdr1 := ids.GenerateTestShortID()
+
+	// [vdr0] votes for [accepted] and [vdr1] doesn't respond
+	for requestID := uint32(0); requestID < 3; requestID++ {
+		auditLog.StartPoll(requestID)
+		auditLog.RecordChit(requestID, vdr0, []ids.ID{accepted})
+		auditLog.RecordChit(requestID, vdr1, nil)
+		auditLog.FinishPoll(requestID, map[ids.ID]int{accepted: 1})
+	}
+
+	// Chits of polls that aren't outstanding are ignored
+	auditLog.RecordChit(1, vdr0, []ids.ID{rejected})
+	auditLog.FinishPoll(1, map[ids.ID]int{rejected: 1})
+
+	assert.NoError(auditLog.Accept(ctx, accepted, nil))
+	assert.NoError(auditLog.Reject(ctx, rejected, nil))
+
+	decisions := readAuditDecisions(t, w.Bytes())
+	assert.Len(decisions, 2)
+
+	decision := decisions[0]
+	assert.Equal(ctx.ChainID, decision.ChainID)
+	assert.Equal(accepted, decision.ContainerID)
+	assert.Equal("accepted", decision.Status)
+	assert.False(decision.Bootstrapping)
+	// Only the 2 most recent polls are kept
+	assert.Equal(1, decision.DroppedPolls)
+	assert.Len(decision.Polls, 2)
+	assert.Equal(uint32(1), decision.Polls[0].RequestID)
+	assert.Equal(uint32(2), decision.Polls[1].RequestID)
+
+	poll := decision.Polls[1]
+	assert.Equal(1, poll.Votes)
+	assert.Equal([]AuditChit{
+		{NodeID: address.FormatNodeID(vdr0), Votes: []ids.ID{accepted}},
+		{NodeID: address.FormatNodeID(vdr1), Votes: []ids.ID{}},
+	}, poll.Chits)
+
+	decision = decisions[1]
+	assert.Equal(rejected, decision.ContainerID)
+	assert.Equal("rejected", decision.Status)
+	assert.Empty(decision.Polls)
+	assert.Zero(decision.DroppedPolls)
+}
+
+func TestAuditLogAppends(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	ctx := snow.DefaultContextTest()
+	containerIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+
+	// Reopening the audit log keeps the decisions that were already written
+	for _, containerID := range containerIDs {
+		auditLog, err := OpenAuditLog(path, DefaultMaxAuditPolls)
+		assert.NoError(err)
+		assert.NoError(auditLog.Accept(ctx, containerID, nil))
+		assert.NoError(auditLog.Close())
+	}
+
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(err)
+	decisions := readAuditDecisions(t, b)
+	assert.Len(decisions, 2)
+	for i, decision := range decisions {
+		assert.Equal(containerIDs[i], decision.ContainerID)
+		// The context isn't bootstrapped
+		assert.True(decision.Bootstrapping)
+	}
+}
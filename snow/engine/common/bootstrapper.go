@@ -9,6 +9,7 @@ import (
 
 	stdmath "math"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/math"
@@ -66,9 +67,35 @@ type Bootstrapper struct {
 	failedAccepted ids.ShortSet
 	// IDs of the returned accepted containers and the stake weight that has
 	// marked them as accepted
-	acceptedVotes    map[ids.ID]uint64
+	acceptedVotes    ids.Bag
 	acceptedFrontier []ids.ID
 
+	// True if the state sync was started. State sync is only attempted once,
+	// before the first attempt to bootstrap.
+	stateSyncStarted bool
+	// IDs of validators we should request their latest state summary from
+	pendingSendStateSummaryFrontier ids.ShortSet
+	// IDs of validators we requested their latest state summary from but
+	// haven't received a reply yet
+	pendingReceiveStateSummaryFrontier ids.ShortSet
+	// The returned state summaries, by ID
+	stateSummaries map[ids.ID]Summary
+	// Heights of the returned state summaries
+	stateSummaryHeights []uint64
+
+	// IDs of validators we should request the IDs of their state summaries
+	// from
+	pendingSendAcceptedStateSummary ids.ShortSet
+	// IDs of validators we requested the IDs of their state summaries from
+	// but haven't received a reply yet
+	pendingReceiveAcceptedStateSummary ids.ShortSet
+	// IDs of the returned state summaries and the stake weight that has
+	// vouched for them
+	stateSummaryVotes map[ids.ID]uint64
+
+	// The summary that the VM is fetching the state of, if any
+	syncingSummary Summary
+
 	// current weight
 	started bool
 	weight  uint64
@@ -156,7 +183,7 @@ func (b *Bootstrapper) AcceptedFrontier(validatorID ids.ShortID, requestID uint3
 
 	newAlpha := float64(b.sampledBeacons.Weight()*b.Alpha) / float64(b.Beacons.Weight())
 
-	failedBeaconWeight, err := b.Beacons.SubsetWeight(b.failedAcceptedFrontier)
+	failedBeaconWeight, err := b.Beacons.SubsetWeight(ids.NodeIDSetFromShortSet(b.failedAcceptedFrontier))
 	if err != nil {
 		return err
 	}
@@ -222,18 +249,12 @@ func (b *Bootstrapper) Accepted(validatorID ids.ShortID, requestID uint32, conta
 	b.pendingReceiveAccepted.Remove(validatorID)
 
 	weight := uint64(0)
-	if w, ok := b.Beacons.GetWeight(validatorID); ok {
+	if w, ok := b.Beacons.GetWeight(ids.NodeIDFromShortID(validatorID)); ok {
 		weight = w
 	}
 
 	for _, containerID := range containerIDs {
-		previousWeight := b.acceptedVotes[containerID]
-		newWeight, err := math.Add64(weight, previousWeight)
-		if err != nil {
-			b.Ctx.Log.Error("Error calculating the Accepted votes - weight: %v, previousWeight: %v", weight, previousWeight)
-			newWeight = stdmath.MaxUint64
-		}
-		b.acceptedVotes[containerID] = newWeight
+		b.acceptedVotes.AddWeighted(containerID, weight)
 	}
 
 	b.sendGetAccepted()
@@ -245,18 +266,14 @@ func (b *Bootstrapper) Accepted(validatorID ids.ShortID, requestID uint32, conta
 
 	// We've received the filtered accepted frontier from every bootstrap validator
 	// Accept all containers that have a sufficient weight behind them
-	accepted := make([]ids.ID, 0, len(b.acceptedVotes))
-	for containerID, weight := range b.acceptedVotes {
-		if weight >= b.Alpha {
-			accepted = append(accepted, containerID)
-		}
-	}
+	b.acceptedVotes.SetWeightThreshold(b.Alpha)
+	accepted := b.acceptedVotes.WeightThreshold().List()
 
 	// if we don't have enough weight for the bootstrap to be accepted then retry or fail the bootstrap
 	size := len(accepted)
 	if size == 0 && b.Beacons.Len() > 0 {
 		// retry the bootstrap if the weight is not enough to bootstrap
-		failedBeaconWeight, err := b.Beacons.SubsetWeight(b.failedAccepted)
+		failedBeaconWeight, err := b.Beacons.SubsetWeight(ids.NodeIDSetFromShortSet(b.failedAccepted))
 		if err != nil {
 			return err
 		}
@@ -278,12 +295,228 @@ func (b *Bootstrapper) Accepted(validatorID ids.ShortID, requestID uint32, conta
 	return b.Bootstrapable.ForceAccepted(accepted)
 }
 
+// GetStateSummaryFrontier implements the Engine interface.
+func (b *Bootstrapper) GetStateSummaryFrontier(validatorID ids.ShortID, requestID uint32) error {
+	var summaryBytes []byte
+	if b.StateSyncer != nil {
+		summary, err := b.StateSyncer.GetLastStateSummary()
+		switch {
+		case err == nil:
+			summaryBytes = summary.Bytes()
+		case err != database.ErrNotFound:
+			b.Ctx.Log.Debug("Failed to get the last state summary: %s", err)
+		}
+	}
+	b.Sender.StateSummaryFrontier(validatorID, requestID, summaryBytes)
+	return nil
+}
+
+// GetStateSummaryFrontierFailed implements the Engine interface.
+func (b *Bootstrapper) GetStateSummaryFrontierFailed(validatorID ids.ShortID, requestID uint32) error {
+	// ignores any late responses
+	if requestID != b.RequestID {
+		b.Ctx.Log.Debug("Received an Out-of-Sync GetStateSummaryFrontierFailed - validator: %v - expectedRequestID: %v, requestID: %v",
+			validatorID,
+			b.RequestID,
+			requestID)
+		return nil
+	}
+
+	// If we can't get a response from [validatorID], act as though they said
+	// they don't have a state summary
+	return b.StateSummaryFrontier(validatorID, requestID, nil)
+}
+
+// StateSummaryFrontier implements the Engine interface.
+func (b *Bootstrapper) StateSummaryFrontier(validatorID ids.ShortID, requestID uint32, summaryBytes []byte) error {
+	// ignores any late responses
+	if requestID != b.RequestID {
+		b.Ctx.Log.Debug("Received an Out-of-Sync StateSummaryFrontier - validator: %v - expectedRequestID: %v, requestID: %v",
+			validatorID,
+			b.RequestID,
+			requestID)
+		return nil
+	}
+
+	if !b.pendingReceiveStateSummaryFrontier.Contains(validatorID) {
+		b.Ctx.Log.Debug("Received a StateSummaryFrontier message from %s unexpectedly", validatorID)
+		return nil
+	}
+
+	// Mark that we received a response from [validatorID]
+	b.pendingReceiveStateSummaryFrontier.Remove(validatorID)
+
+	if len(summaryBytes) > 0 {
+		summary, err := b.StateSyncer.ParseStateSummary(summaryBytes)
+		if err != nil {
+			b.Ctx.Log.Debug("Failed to parse the state summary from %s: %s", validatorID, err)
+		} else {
+			b.stateSummaries[summary.ID()] = summary
+		}
+	}
+
+	b.sendGetStateSummaryFrontiers()
+
+	// still waiting on requests
+	if b.pendingReceiveStateSummaryFrontier.Len() != 0 {
+		return nil
+	}
+
+	if len(b.stateSummaries) == 0 {
+		b.Ctx.Log.Info("State sync skipped due to no state summaries")
+		return b.fetchAcceptedFrontiers()
+	}
+
+	// Ask each bootstrap validator which of the returned summaries it has, by
+	// the heights of the summaries
+	heights := make(map[uint64]struct{}, len(b.stateSummaries))
+	b.stateSummaryHeights = make([]uint64, 0, len(b.stateSummaries))
+	for _, summary := range b.stateSummaries {
+		height := summary.Height()
+		if _, ok := heights[height]; ok {
+			continue
+		}
+		heights[height] = struct{}{}
+		b.stateSummaryHeights = append(b.stateSummaryHeights, height)
+	}
+
+	b.RequestID++
+	b.sendGetAcceptedStateSummary()
+	return nil
+}
+
+// GetAcceptedStateSummary implements the Engine interface.
+func (b *Bootstrapper) GetAcceptedStateSummary(validatorID ids.ShortID, requestID uint32, heights []uint64) error {
+	summaryIDs := make([]ids.ID, 0, len(heights))
+	if b.StateSyncer != nil {
+		for _, height := range heights {
+			summary, err := b.StateSyncer.GetStateSummary(height)
+			switch {
+			case err == nil:
+				summaryIDs = append(summaryIDs, summary.ID())
+			case err != database.ErrNotFound:
+				b.Ctx.Log.Debug("Failed to get the state summary at height %d: %s", height, err)
+			}
+		}
+	}
+	b.Sender.AcceptedStateSummary(validatorID, requestID, summaryIDs)
+	return nil
+}
+
+// GetAcceptedStateSummaryFailed implements the Engine interface.
+func (b *Bootstrapper) GetAcceptedStateSummaryFailed(validatorID ids.ShortID, requestID uint32) error {
+	// ignores any late responses
+	if requestID != b.RequestID {
+		b.Ctx.Log.Debug("Received an Out-of-Sync GetAcceptedStateSummaryFailed - validator: %v - expectedRequestID: %v, requestID: %v",
+			validatorID,
+			b.RequestID,
+			requestID)
+		return nil
+	}
+
+	// If we can't get a response from [validatorID], act as though they said
+	// they don't have any of the state summaries
+	return b.AcceptedStateSummary(validatorID, requestID, nil)
+}
+
+// AcceptedStateSummary implements the Engine interface.
+func (b *Bootstrapper) AcceptedStateSummary(validatorID ids.ShortID, requestID uint32, summaryIDs []ids.ID) error {
+	// ignores any late responses
+	if requestID != b.RequestID {
+		b.Ctx.Log.Debug("Received an Out-of-Sync AcceptedStateSummary - validator: %v - expectedRequestID: %v, requestID: %v",
+			validatorID,
+			b.RequestID,
+			requestID)
+		return nil
+	}
+
+	if !b.pendingReceiveAcceptedStateSummary.Contains(validatorID) {
+		b.Ctx.Log.Debug("Received an AcceptedStateSummary message from %s unexpectedly", validatorID)
+		return nil
+	}
+	// Mark that we received a response from [validatorID]
+	b.pendingReceiveAcceptedStateSummary.Remove(validatorID)
+
+	weight := uint64(0)
+	if w, ok := b.Beacons.GetWeight(ids.NodeIDFromShortID(validatorID)); ok {
+		weight = w
+	}
+
+	// The weight of [validatorID] is only counted once for each summary
+	voted := ids.NewSet(len(summaryIDs))
+	for _, summaryID := range summaryIDs {
+		if voted.Contains(summaryID) {
+			continue
+		}
+		voted.Add(summaryID)
+
+		previousWeight := b.stateSummaryVotes[summaryID]
+		newWeight, err := math.Add64(weight, previousWeight)
+		if err != nil {
+			b.Ctx.Log.Error("Error calculating the AcceptedStateSummary votes - weight: %v, previousWeight: %v", weight, previousWeight)
+			newWeight = stdmath.MaxUint64
+		}
+		b.stateSummaryVotes[summaryID] = newWeight
+	}
+
+	b.sendGetAcceptedStateSummary()
+
+	// wait on pending responses
+	if b.pendingReceiveAcceptedStateSummary.Len() != 0 {
+		return nil
+	}
+
+	// Sync to the highest summary that has a sufficient weight behind it
+	var summary Summary
+	for summaryID, candidate := range b.stateSummaries {
+		if b.stateSummaryVotes[summaryID] < b.Alpha {
+			continue
+		}
+		if summary == nil || candidate.Height() > summary.Height() {
+			summary = candidate
+		}
+	}
+	b.stateSummaries = nil
+	b.stateSummaryVotes = nil
+
+	if summary == nil {
+		b.Ctx.Log.Info("State sync skipped due to no state summary having enough weight")
+		return b.fetchAcceptedFrontiers()
+	}
+
+	b.Ctx.Log.Info("State sync started fetching the state of summary %s at height %d", summary.ID(), summary.Height())
+	b.syncingSummary = summary
+	if err := b.StateSyncer.FetchState(summary); err != nil {
+		return fmt.Errorf("couldn't start fetching the state of summary %s: %w", summary.ID(), err)
+	}
+	return nil
+}
+
+// StateSyncDone is called when the VM notifies the engine that it's done
+// fetching the state of the summary it's syncing to. The state is committed
+// and then the containers accepted after the summary are bootstrapped.
+func (b *Bootstrapper) StateSyncDone() error {
+	summary := b.syncingSummary
+	if summary == nil {
+		b.Ctx.Log.Debug("Received a StateSyncDone message unexpectedly")
+		return nil
+	}
+	b.syncingSummary = nil
+
+	if err := b.StateSyncer.CommitState(summary); err != nil {
+		b.Ctx.Log.Warn("State sync failed, bootstrapping from the last accepted state: %s", err)
+	} else {
+		b.Ctx.Log.Info("State sync finished at height %d", summary.Height())
+	}
+	return b.fetchAcceptedFrontiers()
+}
+
 // Connected implements the Engine interface.
 func (b *Bootstrapper) Connected(validatorID ids.ShortID) error {
 	if b.started {
 		return nil
 	}
-	weight, ok := b.Beacons.GetWeight(validatorID)
+	weight, ok := b.Beacons.GetWeight(ids.NodeIDFromShortID(validatorID))
 	if !ok {
 		return nil
 	}
@@ -300,7 +533,7 @@ func (b *Bootstrapper) Connected(validatorID ids.ShortID) error {
 
 // Disconnected implements the Engine interface.
 func (b *Bootstrapper) Disconnected(validatorID ids.ShortID) error {
-	if weight, ok := b.Beacons.GetWeight(validatorID); ok {
+	if weight, ok := b.Beacons.GetWeight(ids.NodeIDFromShortID(validatorID)); ok {
 		// TODO: Account for weight changes in a more robust manner.
 
 		// Sub64 should rarely error since only validators that have added their
@@ -345,7 +578,7 @@ func (b *Bootstrapper) startup() error {
 
 	b.pendingSendAcceptedFrontier.Clear()
 	for _, vdr := range beacons {
-		vdrID := vdr.ID()
+		vdrID := vdr.ID().ShortID()
 		b.pendingSendAcceptedFrontier.Add(vdrID)
 	}
 
@@ -355,13 +588,13 @@ func (b *Bootstrapper) startup() error {
 
 	b.pendingSendAccepted.Clear()
 	for _, vdr := range b.Beacons.List() {
-		vdrID := vdr.ID()
+		vdrID := vdr.ID().ShortID()
 		b.pendingSendAccepted.Add(vdrID)
 	}
 
 	b.pendingReceiveAccepted.Clear()
 	b.failedAccepted.Clear()
-	b.acceptedVotes = make(map[ids.ID]uint64)
+	b.acceptedVotes = ids.Bag{}
 
 	b.bootstrapAttempts++
 	if b.pendingSendAcceptedFrontier.Len() == 0 {
@@ -369,11 +602,77 @@ func (b *Bootstrapper) startup() error {
 		return b.Bootstrapable.ForceAccepted(nil)
 	}
 
+	if b.StateSyncer != nil && !b.stateSyncStarted {
+		enabled, err := b.StateSyncer.StateSyncEnabled()
+		if err != nil {
+			return fmt.Errorf("couldn't check whether state sync is enabled: %w", err)
+		}
+		if enabled {
+			return b.startStateSync()
+		}
+	}
+	return b.fetchAcceptedFrontiers()
+}
+
+// startStateSync asks the sampled beacons for their latest state summaries.
+// The summaries are then voted on by all the beacons.
+func (b *Bootstrapper) startStateSync() error {
+	b.stateSyncStarted = true
+
+	b.pendingSendStateSummaryFrontier.Clear()
+	b.pendingSendStateSummaryFrontier.Union(b.pendingSendAcceptedFrontier)
+	b.pendingReceiveStateSummaryFrontier.Clear()
+	b.stateSummaries = make(map[ids.ID]Summary)
+
+	b.pendingSendAcceptedStateSummary.Clear()
+	b.pendingSendAcceptedStateSummary.Union(b.pendingSendAccepted)
+	b.pendingReceiveAcceptedStateSummary.Clear()
+	b.stateSummaryVotes = make(map[ids.ID]uint64)
+
+	b.Ctx.Log.Info("Starting state sync...")
+	b.RequestID++
+	b.sendGetStateSummaryFrontiers()
+	return nil
+}
+
+// fetchAcceptedFrontiers starts bootstrapping from the accepted frontiers of
+// the sampled beacons
+func (b *Bootstrapper) fetchAcceptedFrontiers() error {
 	b.RequestID++
 	b.sendGetAcceptedFrontiers()
 	return nil
 }
 
+// Ask up to [MaxOutstandingBootstrapRequests] bootstrap validators to send
+// their latest state summary
+func (b *Bootstrapper) sendGetStateSummaryFrontiers() {
+	vdrs := ids.NewShortSet(1)
+	for b.pendingSendStateSummaryFrontier.Len() > 0 && b.pendingReceiveStateSummaryFrontier.Len() < MaxOutstandingBootstrapRequests {
+		vdr, _ := b.pendingSendStateSummaryFrontier.Pop()
+		vdrs.Add(vdr)
+		b.pendingReceiveStateSummaryFrontier.Add(vdr)
+	}
+
+	if vdrs.Len() > 0 {
+		b.Sender.GetStateSummaryFrontier(vdrs, b.RequestID)
+	}
+}
+
+// Ask up to [MaxOutstandingBootstrapRequests] bootstrap validators to send
+// the IDs of their state summaries at the heights of the returned summaries
+func (b *Bootstrapper) sendGetAcceptedStateSummary() {
+	vdrs := ids.NewShortSet(1)
+	for b.pendingSendAcceptedStateSummary.Len() > 0 && b.pendingReceiveAcceptedStateSummary.Len() < MaxOutstandingBootstrapRequests {
+		vdr, _ := b.pendingSendAcceptedStateSummary.Pop()
+		vdrs.Add(vdr)
+		b.pendingReceiveAcceptedStateSummary.Add(vdr)
+	}
+
+	if vdrs.Len() > 0 {
+		b.Sender.GetAcceptedStateSummary(vdrs, b.RequestID, b.stateSummaryHeights)
+	}
+}
+
 // Ask up to [MaxOutstandingBootstrapRequests] bootstrap validators to send
 // their accepted frontier with the current accepted frontier
 func (b *Bootstrapper) sendGetAcceptedFrontiers() {
@@ -0,0 +1,25 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+// Checkpointer is implemented by VMs that hold state that isn't written to
+// their database as it changes, such as tries or indexes kept in memory. The
+// node calls Checkpoint before it takes a backup of its database, and Restore
+// once the VM is initialized from a restored database, so that the state of
+// the VM is consistent with the containers in the backup.
+type Checkpointer interface {
+	// Checkpoint writes the state of the VM to its database, and returns any
+	// other data that the VM needs to restore its state from the database.
+	// The returned bytes are recorded in the backup. Called while the chain
+	// is paused, right before the database is captured. Assumes the context
+	// lock is held.
+	Checkpoint() ([]byte, error)
+
+	// Restore is called after the VM is initialized from a database that was
+	// restored from a backup, with the bytes that Checkpoint returned when the
+	// backup was taken. [checkpoint] is nil if the VM didn't checkpoint
+	// itself then. Called once per restored backup. Assumes the context lock
+	// is held.
+	Restore(checkpoint []byte) error
+}
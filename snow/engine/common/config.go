@@ -4,10 +4,14 @@
 package common
 
 import (
+	"encoding/binary"
 	"time"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
 // Config wraps the common configurations that are needed by a Snow consensus
@@ -25,6 +29,10 @@ type Config struct {
 	Subnet        Subnet
 	Timer         Timer
 
+	// StateSyncer is the VM of the chain if it can sync its state from a state
+	// summary rather than executing every container since genesis. May be nil.
+	StateSyncer StateSyncableVM
+
 	// Should Bootstrap be retried
 	RetryBootstrap bool
 
@@ -41,6 +49,27 @@ type Config struct {
 	// This node will only consider the first [MultiputMaxContainersReceived]
 	// containers in a multiput it receives.
 	MultiputMaxContainersReceived int
+
+	// If true, the validators sampled for each query are a deterministic
+	// function of the validator set, the chain, the queried container, and
+	// the request ID, so that polls can be replayed from message logs.
+	DeterministicSampling bool
+
+	// AuditLog that the chits of the engine's polls are recorded to. If nil,
+	// polls aren't audited.
+	AuditLog *AuditLog
+
+	// If true, the engine records the lifecycle of the containers it
+	// processes, if it supports doing so, so that it can be served by the
+	// Debug API.
+	TraceContainers bool
+
+	// Parameters of the engine's health checks
+	HealthConfig HealthConfig
+
+	// PollTracker that the results of the engine's queries are recorded to. If
+	// nil, one is created from HealthConfig.
+	PollTracker *PollTracker
 }
 
 // Context implements the Engine interface
@@ -48,3 +77,23 @@ func (c *Config) Context() *snow.Context { return c.Ctx }
 
 // IsBootstrapped returns true iff this chain is done bootstrapping
 func (c *Config) IsBootstrapped() bool { return c.Ctx.IsBootstrapped() }
+
+// SampleValidators samples [k] validators to query about [containerID] in
+// request [requestID].
+func (c *Config) SampleValidators(k int, containerID ids.ID, requestID uint32) ([]validators.Validator, error) {
+	if !c.DeterministicSampling {
+		return c.Validators.Sample(k)
+	}
+	return c.Validators.SampleWithSeed(k, SamplingSeed(c.Ctx.ChainID, containerID, requestID))
+}
+
+// SamplingSeed returns the seed used to deterministically sample the validators
+// queried about [containerID] in request [requestID] of chain [chainID].
+func SamplingSeed(chainID, containerID ids.ID, requestID uint32) int64 {
+	p := wrappers.Packer{Bytes: make([]byte, 2*hashing.HashLen+wrappers.IntLen)}
+	p.PackFixedBytes(chainID[:])
+	p.PackFixedBytes(containerID[:])
+	p.PackInt(requestID)
+	hash := hashing.ComputeHash256(p.Bytes)
+	return int64(binary.BigEndian.Uint64(hash))
+}
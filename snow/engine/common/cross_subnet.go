@@ -0,0 +1,21 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// CrossSubnetHandler is implemented by VMs that accept messages from chains in
+// other subnets.
+type CrossSubnetHandler interface {
+	// Notify this VM of a message created by node [originID] on the chain
+	// [sourceChainID]. The origin is known to be a validator of the subnet
+	// validating [sourceChainID], either because this node validates that
+	// subnet too, or because a validator of this VM's subnet relayed the
+	// message and vouched for it.
+	//
+	// Returned errors are logged, but aren't treated as fatal.
+	CrossSubnet(originID ids.ShortID, sourceChainID ids.ID, msg []byte) error
+}
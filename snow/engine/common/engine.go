@@ -27,6 +27,14 @@ type Engine interface {
 	GetVM() VM
 }
 
+// StateDumper is implemented by engines that can report a snapshot of their
+// internal state, to help diagnose a stalled chain
+type StateDumper interface {
+	// DumpState returns a JSON marshallable snapshot of the engine's state.
+	// Assumes the context lock is held.
+	DumpState() (interface{}, error)
+}
+
 // Handler defines the functions that are acted on the node
 type Handler interface {
 	ExternalHandler
@@ -38,6 +46,7 @@ type Handler interface {
 type ExternalHandler interface {
 	FrontierHandler
 	AcceptedHandler
+	StateSummaryHandler
 	FetchHandler
 	QueryHandler
 }
@@ -65,6 +74,9 @@ type FrontierHandler interface {
 	// this message is in response to a GetAcceptedFrontier message, is
 	// utilizing a unique requestID, or that the containerIDs from a valid
 	// frontier. However, the validatorID is  assumed to be authenticated.
+	//
+	// If requestID is constants.GossipMsgRequestID, the validator pushed its
+	// newly accepted frontier to us without being asked.
 	AcceptedFrontier(
 		validatorID ids.ShortID,
 		requestID uint32,
@@ -128,6 +140,73 @@ type AcceptedHandler interface {
 	GetAcceptedFailed(validatorID ids.ShortID, requestID uint32) error
 }
 
+// StateSummaryHandler defines how a consensus engine reacts to messages
+// pertaining to the state summaries of other validators. Functions only return
+// fatal errors if they occur.
+type StateSummaryHandler interface {
+	// Notify this engine of a request for the latest state summary.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is utilizing a unique requestID. However, the validatorID is
+	// assumed to be authenticated.
+	//
+	// This engine should respond with a StateSummaryFrontier message with the
+	// same requestID, and the latest summary its VM can serve, if any.
+	GetStateSummaryFrontier(validatorID ids.ShortID, requestID uint32) error
+
+	// Notify this engine of the latest state summary of a validator.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is in response to a GetStateSummaryFrontier message, is
+	// utilizing a unique requestID, or that the summary is valid. However, the
+	// validatorID is assumed to be authenticated. An empty summary means that
+	// the validator doesn't have one.
+	StateSummaryFrontier(validatorID ids.ShortID, requestID uint32, summary []byte) error
+
+	// Notify this engine that a get state summary frontier request it issued
+	// has failed.
+	//
+	// This function will be called if the engine sent a GetStateSummaryFrontier
+	// message that is not anticipated to be responded to. This could be because
+	// the recipient of the message is unknown or if the message request has
+	// timed out.
+	//
+	// The validatorID, and requestID, are assumed to be the same as those sent
+	// in the GetStateSummaryFrontier message.
+	GetStateSummaryFrontierFailed(validatorID ids.ShortID, requestID uint32) error
+
+	// Notify this engine of a request for the IDs of its state summaries at
+	// [heights].
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is utilizing a unique requestID. However, the validatorID is
+	// assumed to be authenticated.
+	//
+	// This engine should respond with an AcceptedStateSummary message with the
+	// same requestID, and the IDs of the summaries its VM has at [heights].
+	GetAcceptedStateSummary(validatorID ids.ShortID, requestID uint32, heights []uint64) error
+
+	// Notify this engine of the IDs of the state summaries of a validator.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is in response to a GetAcceptedStateSummary message, is
+	// utilizing a unique requestID, or that the summaries are at the requested
+	// heights. However, the validatorID is assumed to be authenticated.
+	AcceptedStateSummary(validatorID ids.ShortID, requestID uint32, summaryIDs []ids.ID) error
+
+	// Notify this engine that a get accepted state summary request it issued
+	// has failed.
+	//
+	// This function will be called if the engine sent a GetAcceptedStateSummary
+	// message that is not anticipated to be responded to. This could be because
+	// the recipient of the message is unknown or if the message request has
+	// timed out.
+	//
+	// The validatorID, and requestID, are assumed to be the same as those sent
+	// in the GetAcceptedStateSummary message.
+	GetAcceptedStateSummaryFailed(validatorID ids.ShortID, requestID uint32) error
+}
+
 // FetchHandler defines how a consensus engine reacts to retrieval messages from
 // other validators. Functions only return fatal errors if they occur.
 type FetchHandler interface {
@@ -0,0 +1,31 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+// FeeLevels are the fees that a VM currently charges and how congested its
+// chain is
+type FeeLevels struct {
+	// Congestion is how close the chain is to its capacity, from 0 if the
+	// chain is idle to 1 if it's at capacity
+	Congestion float64
+	// BaseFee is the fee, in nAVAX, that every transaction must currently pay
+	BaseFee uint64
+	// SuggestedFee is the fee, in nAVAX, that a transaction should pay to be
+	// issued to consensus soon
+	SuggestedFee uint64
+}
+
+// FeeReporter is implemented by VMs whose fees depend on the load on their
+// chain. The node reports the fee levels of the VM to clients that estimate
+// fees, and the avalanche engine issues the transactions with the highest
+// priority first.
+type FeeReporter interface {
+	// FeeLevels returns the current fee levels of the VM. Assumes the context
+	// lock is held.
+	FeeLevels() (FeeLevels, error)
+
+	// TxPriority returns the priority of [tx], usually the fee it pays.
+	// Assumes the context lock is held.
+	TxPriority(tx PendingTx) uint64
+}
@@ -0,0 +1,89 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+var errNotBootstrapped = errors.New("chain is bootstrapping")
+
+// HealthConfig describes parameters for consensus engine health checks
+type HealthConfig struct {
+	// Reports unhealthy if more than [MaxPollFailureRate] of the queries sent
+	// to validators fail
+	MaxPollFailureRate float64
+
+	// Halflife of averager used to calculate the poll failure rate.
+	// Must be > 0.
+	// Larger value --> Failure rate affected less by recent queries
+	PollFailureRateHalflife time.Duration
+}
+
+// PollTracker tracks the portion of the queries sent by a consensus engine
+// that fail, rather than being answered with chits. Safe for concurrent use, so
+// that the failure rate can be read without the lock of the chain.
+type PollTracker struct {
+	lock           sync.Mutex
+	clock          timer.Clock
+	maxFailureRate float64
+	failureRate    math.Averager
+}
+
+// NewPollTracker returns a new PollTracker that reports unhealthy according to
+// [config]
+func NewPollTracker(config HealthConfig) *PollTracker {
+	p := &PollTracker{maxFailureRate: config.MaxPollFailureRate}
+	p.failureRate = math.NewAverager(0, config.PollFailureRateHalflife, p.clock.Time())
+	return p
+}
+
+// Succeeded marks that a validator responded to a query
+func (p *PollTracker) Succeeded() { p.observe(0) }
+
+// Failed marks that a query to a validator failed
+func (p *PollTracker) Failed() { p.observe(1) }
+
+func (p *PollTracker) observe(value float64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.failureRate.Observe(value, p.clock.Time())
+}
+
+// FailureRate returns the portion of recent queries that failed
+func (p *PollTracker) FailureRate() float64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.failureRate.Read()
+}
+
+// HealthCheck reports unhealthy if too many queries are failing
+func (p *PollTracker) HealthCheck() (interface{}, error) {
+	failureRate := p.FailureRate()
+	details := map[string]interface{}{
+		"failureRate": failureRate,
+	}
+	if failureRate > p.maxFailureRate {
+		return details, fmt.Errorf("%f of queries failed, more than the maximum of %f", failureRate, p.maxFailureRate)
+	}
+	return details, nil
+}
+
+// BootstrappedHealthCheck reports unhealthy until the chain of [ctx] has
+// finished bootstrapping
+func BootstrappedHealthCheck(ctx *snow.Context) (interface{}, error) {
+	if !ctx.IsBootstrapped() {
+		return false, errNotBootstrapped
+	}
+	return true, nil
+}
@@ -0,0 +1,55 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/snow"
+)
+
+func TestPollTracker(t *testing.T) {
+	assert := assert.New(t)
+
+	tracker := NewPollTracker(HealthConfig{
+		MaxPollFailureRate:      .5,
+		PollFailureRateHalflife: time.Second,
+	})
+	now := time.Now()
+	tracker.clock.Set(now)
+
+	_, err := tracker.HealthCheck()
+	assert.NoError(err)
+
+	for i := 0; i < 10; i++ {
+		tracker.Failed()
+	}
+	_, err = tracker.HealthCheck()
+	assert.Error(err)
+	assert.Greater(tracker.FailureRate(), .5)
+
+	// Once queries start succeeding again, the failure rate should recover
+	for i := 0; i < 10; i++ {
+		now = now.Add(time.Second)
+		tracker.clock.Set(now)
+		tracker.Succeeded()
+	}
+	_, err = tracker.HealthCheck()
+	assert.NoError(err)
+}
+
+func TestBootstrappedHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := snow.DefaultContextTest()
+	_, err := BootstrappedHealthCheck(ctx)
+	assert.ErrorIs(err, errNotBootstrapped)
+
+	ctx.Bootstrapped()
+	_, err = BootstrappedHealthCheck(ctx)
+	assert.NoError(err)
+}
@@ -0,0 +1,75 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// MempoolTx is a summary of a transaction that hasn't been issued to
+// consensus yet
+type MempoolTx struct {
+	ID ids.ID `json:"id"`
+	// Size of the transaction, in bytes
+	Size int `json:"size"`
+	// Type of the transaction, if it's known
+	Type string `json:"type,omitempty"`
+}
+
+// Mempool is implemented by engines and VMs that hold transactions before
+// issuing them to consensus, to help diagnose why a transaction isn't being
+// issued
+type Mempool interface {
+	// MempoolTxs returns the transactions that haven't been issued to
+	// consensus yet. Assumes the context lock is held.
+	MempoolTxs() []MempoolTx
+
+	// EvictTx removes [txID] from the mempool, so that it won't be issued to
+	// consensus. Returns false if [txID] isn't in the mempool. Assumes the
+	// context lock is held.
+	EvictTx(txID ids.ID) bool
+}
+
+// PendingTx is a transaction that hasn't been issued to consensus yet
+type PendingTx interface {
+	ID() ids.ID
+	Bytes() []byte
+}
+
+// PriorityMempool is a Mempool that VMs implement and engines take
+// transactions from, in order of priority, when building blocks or vertices.
+// A VM sends PendingTxs to its engine after it adds transactions.
+//
+// The avalanche engine pops the transactions of a PriorityMempool instead of
+// calling PendingTxs on the VM. The transactions must be snowstorm.Txs or be
+// parsable by the VM. The snowman engine only builds blocks while the mempool
+// of the VM isn't empty, and keeps building blocks until it is.
+type PriorityMempool interface {
+	Mempool
+
+	// AddTx adds [tx] to the mempool with [priority]. Transactions with a
+	// higher priority are popped first. Returns an error if [tx] wasn't
+	// added, for example because the mempool is full of transactions with a
+	// higher priority. Assumes the context lock is held.
+	AddTx(tx PendingTx, priority uint64) error
+
+	// PopTx removes and returns the transaction with the highest priority.
+	// Returns false if the mempool is empty. Assumes the context lock is
+	// held.
+	PopTx() (PendingTx, bool)
+
+	// NumTxs returns the number of transactions in the mempool. Assumes the
+	// context lock is held.
+	NumTxs() int
+}
+
+// TxRegossiper is implemented by engines that can gossip the processing
+// containers that include a transaction again, in case peers didn't receive
+// them
+type TxRegossiper interface {
+	// RegossipTx gossips the processing container that includes [txID].
+	// Returns false if no processing container includes [txID]. Assumes the
+	// context lock is held.
+	RegossipTx(txID ids.ID) (bool, error)
+}
@@ -17,12 +17,18 @@ const (
 	// its VM has pending transactions
 	// (i.e. it would like to add a new block/vertex to consensus)
 	PendingTxs Message = iota
+
+	// StateSyncDone notifies a consensus engine that its VM is done
+	// fetching the state of the summary it's syncing to
+	StateSyncDone
 )
 
 func (msg Message) String() string {
 	switch msg {
 	case PendingTxs:
 		return "Pending Transactions"
+	case StateSyncDone:
+		return "State Sync Done"
 	default:
 		return fmt.Sprintf("Unknown Message: %d", msg)
 	}
@@ -12,6 +12,7 @@ import (
 type Sender interface {
 	FrontierSender
 	AcceptedSender
+	StateSummarySender
 	FetchSender
 	QuerySender
 	Gossiper
@@ -42,6 +43,27 @@ type AcceptedSender interface {
 	Accepted(validatorID ids.ShortID, requestID uint32, containerIDs []ids.ID)
 }
 
+// StateSummarySender defines how a consensus engine sends messages pertaining
+// to state summaries
+type StateSummarySender interface {
+	// GetStateSummaryFrontier requests that every validator in [validatorIDs]
+	// sends a StateSummaryFrontier message with its latest state summary.
+	GetStateSummaryFrontier(validatorIDs ids.ShortSet, requestID uint32)
+
+	// StateSummaryFrontier responds to a GetStateSummaryFrontier message with
+	// this engine's latest state summary. [summary] is empty if there is none.
+	StateSummaryFrontier(validatorID ids.ShortID, requestID uint32, summary []byte)
+
+	// GetAcceptedStateSummary requests that every validator in [validatorIDs]
+	// sends an AcceptedStateSummary message with the IDs of its state
+	// summaries at [heights].
+	GetAcceptedStateSummary(validatorIDs ids.ShortSet, requestID uint32, heights []uint64)
+
+	// AcceptedStateSummary responds to a GetAcceptedStateSummary message with
+	// the IDs of this engine's state summaries at the requested heights.
+	AcceptedStateSummary(validatorID ids.ShortID, requestID uint32, summaryIDs []ids.ID)
+}
+
 // FetchSender defines how a consensus engine sends retrieval messages to other
 // validators
 type FetchSender interface {
@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Summary describes the state of a chain at an accepted height. A VM that
+// syncs to a summary can fetch the state it describes from its peers, rather
+// than executing every container since genesis.
+type Summary interface {
+	// ID uniquely identifies this summary. Two nodes that agree on the ID
+	// of a summary agree on the state it describes.
+	ID() ids.ID
+
+	// Height of the accepted container that this summary describes the
+	// state after.
+	Height() uint64
+
+	// Bytes returns the byte representation of this summary.
+	Bytes() []byte
+}
+
+// StateSyncableVM is implemented by VMs, either DAGVMs or ChainVMs, that can
+// sync their state from a summary that was accepted by the network. When a
+// chain bootstraps, its engine asks the beacons for their latest summaries
+// and, if enough stake vouches for one of them, syncs the VM to it before
+// fetching the containers that were accepted after it.
+type StateSyncableVM interface {
+	// StateSyncEnabled returns true if the VM should sync its state from a
+	// summary when the chain bootstraps. A VM may return false if, for
+	// example, it's already close to the tip of the chain.
+	StateSyncEnabled() (bool, error)
+
+	// GetLastStateSummary returns the latest summary that this VM can serve
+	// the state of. Returns database.ErrNotFound if there is none.
+	GetLastStateSummary() (Summary, error)
+
+	// GetStateSummary returns the summary at [height]. Returns
+	// database.ErrNotFound if this VM doesn't have a summary at [height].
+	GetStateSummary(height uint64) (Summary, error)
+
+	// ParseStateSummary parses the summary [summaryBytes] that was received
+	// from a peer.
+	ParseStateSummary(summaryBytes []byte) (Summary, error)
+
+	// FetchState starts fetching the state described by [summary] from
+	// peers. It shouldn't block; the VM sends StateSyncDone to the engine
+	// once it's done, whether or not fetching succeeded.
+	FetchState(summary Summary) error
+
+	// CommitState makes the fetched state of [summary] the accepted state of
+	// the VM, so that its last accepted container is the one at the height
+	// of [summary]. Returns an error if the state couldn't be fetched, in
+	// which case the chain bootstraps from its current last accepted state.
+	//
+	// A DAGVM's vertices that were accepted before [summary] are still
+	// fetched when the chain bootstraps, but their txs that are accepted in
+	// the state of [summary] aren't executed again.
+	CommitState(summary Summary) error
+}
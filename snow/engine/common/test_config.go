@@ -4,6 +4,8 @@
 package common
 
 import (
+	"time"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/validators"
@@ -27,5 +29,9 @@ func DefaultConfigTest() Config {
 		Timer:                         &TimerTest{},
 		MultiputMaxContainersSent:     2000,
 		MultiputMaxContainersReceived: 2000,
+		HealthConfig: HealthConfig{
+			MaxPollFailureRate:      .5,
+			PollFailureRateHalflife: 10 * time.Second,
+		},
 	}
 }
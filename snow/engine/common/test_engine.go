@@ -34,6 +34,13 @@ type EngineTest struct {
 	CantGetAcceptedFailed,
 	CantAccepted,
 
+	CantGetStateSummaryFrontier,
+	CantStateSummaryFrontier,
+	CantGetStateSummaryFrontierFailed,
+	CantGetAcceptedStateSummary,
+	CantAcceptedStateSummary,
+	CantGetAcceptedStateSummaryFailed,
+
 	CantGet,
 	CantGetAncestors,
 	CantGetFailed,
@@ -68,6 +75,12 @@ type EngineTest struct {
 	HealthF                   func() (interface{}, error)
 	GetVtxF                   func() (avalanche.Vertex, error)
 	GetVMF                    func() VM
+
+	StateSummaryFrontierF    func(validatorID ids.ShortID, requestID uint32, summary []byte) error
+	GetAcceptedStateSummaryF func(validatorID ids.ShortID, requestID uint32, heights []uint64) error
+	AcceptedStateSummaryF    func(validatorID ids.ShortID, requestID uint32, summaryIDs []ids.ID) error
+	GetStateSummaryFrontierF, GetStateSummaryFrontierFailedF,
+	GetAcceptedStateSummaryFailedF func(validatorID ids.ShortID, requestID uint32) error
 }
 
 var _ Engine = &EngineTest{}
@@ -93,6 +106,13 @@ func (e *EngineTest) Default(cant bool) {
 	e.CantGetAcceptedFailed = cant
 	e.CantAccepted = cant
 
+	e.CantGetStateSummaryFrontier = cant
+	e.CantStateSummaryFrontier = cant
+	e.CantGetStateSummaryFrontierFailed = cant
+	e.CantGetAcceptedStateSummary = cant
+	e.CantAcceptedStateSummary = cant
+	e.CantGetAcceptedStateSummaryFailed = cant
+
 	e.CantGet = cant
 	e.CantGetAncestors = cant
 	e.CantGetAncestorsFailed = cant
@@ -262,6 +282,84 @@ func (e *EngineTest) Accepted(validatorID ids.ShortID, requestID uint32, contain
 	return errors.New("unexpectedly called Accepted")
 }
 
+func (e *EngineTest) GetStateSummaryFrontier(validatorID ids.ShortID, requestID uint32) error {
+	if e.GetStateSummaryFrontierF != nil {
+		return e.GetStateSummaryFrontierF(validatorID, requestID)
+	}
+	if !e.CantGetStateSummaryFrontier {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateSummaryFrontier")
+	}
+	return errors.New("unexpectedly called GetStateSummaryFrontier")
+}
+
+func (e *EngineTest) StateSummaryFrontier(validatorID ids.ShortID, requestID uint32, summary []byte) error {
+	if e.StateSummaryFrontierF != nil {
+		return e.StateSummaryFrontierF(validatorID, requestID, summary)
+	}
+	if !e.CantStateSummaryFrontier {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called StateSummaryFrontier")
+	}
+	return errors.New("unexpectedly called StateSummaryFrontier")
+}
+
+func (e *EngineTest) GetStateSummaryFrontierFailed(validatorID ids.ShortID, requestID uint32) error {
+	if e.GetStateSummaryFrontierFailedF != nil {
+		return e.GetStateSummaryFrontierFailedF(validatorID, requestID)
+	}
+	if !e.CantGetStateSummaryFrontierFailed {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateSummaryFrontierFailed")
+	}
+	return errors.New("unexpectedly called GetStateSummaryFrontierFailed")
+}
+
+func (e *EngineTest) GetAcceptedStateSummary(validatorID ids.ShortID, requestID uint32, heights []uint64) error {
+	if e.GetAcceptedStateSummaryF != nil {
+		return e.GetAcceptedStateSummaryF(validatorID, requestID, heights)
+	}
+	if !e.CantGetAcceptedStateSummary {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetAcceptedStateSummary")
+	}
+	return errors.New("unexpectedly called GetAcceptedStateSummary")
+}
+
+func (e *EngineTest) AcceptedStateSummary(validatorID ids.ShortID, requestID uint32, summaryIDs []ids.ID) error {
+	if e.AcceptedStateSummaryF != nil {
+		return e.AcceptedStateSummaryF(validatorID, requestID, summaryIDs)
+	}
+	if !e.CantAcceptedStateSummary {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called AcceptedStateSummary")
+	}
+	return errors.New("unexpectedly called AcceptedStateSummary")
+}
+
+func (e *EngineTest) GetAcceptedStateSummaryFailed(validatorID ids.ShortID, requestID uint32) error {
+	if e.GetAcceptedStateSummaryFailedF != nil {
+		return e.GetAcceptedStateSummaryFailedF(validatorID, requestID)
+	}
+	if !e.CantGetAcceptedStateSummaryFailed {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetAcceptedStateSummaryFailed")
+	}
+	return errors.New("unexpectedly called GetAcceptedStateSummaryFailed")
+}
+
 func (e *EngineTest) Get(validatorID ids.ShortID, requestID uint32, containerID ids.ID) error {
 	if e.GetF != nil {
 		return e.GetF(validatorID, requestID, containerID)
@@ -15,6 +15,8 @@ type SenderTest struct {
 
 	CantGetAcceptedFrontier, CantAcceptedFrontier,
 	CantGetAccepted, CantAccepted,
+	CantGetStateSummaryFrontier, CantStateSummaryFrontier,
+	CantGetAcceptedStateSummary, CantAcceptedStateSummary,
 	CantGet, CantGetAncestors, CantPut, CantMultiPut,
 	CantPullQuery, CantPushQuery, CantChits,
 	CantGossip bool
@@ -31,6 +33,11 @@ type SenderTest struct {
 	PullQueryF           func(ids.ShortSet, uint32, ids.ID)
 	ChitsF               func(ids.ShortID, uint32, []ids.ID)
 	GossipF              func(ids.ID, []byte)
+
+	GetStateSummaryFrontierF func(ids.ShortSet, uint32)
+	StateSummaryFrontierF    func(ids.ShortID, uint32, []byte)
+	GetAcceptedStateSummaryF func(ids.ShortSet, uint32, []uint64)
+	AcceptedStateSummaryF    func(ids.ShortID, uint32, []ids.ID)
 }
 
 // Default set the default callable value to [cant]
@@ -39,6 +46,10 @@ func (s *SenderTest) Default(cant bool) {
 	s.CantAcceptedFrontier = cant
 	s.CantGetAccepted = cant
 	s.CantAccepted = cant
+	s.CantGetStateSummaryFrontier = cant
+	s.CantStateSummaryFrontier = cant
+	s.CantGetAcceptedStateSummary = cant
+	s.CantAcceptedStateSummary = cant
 	s.CantGet = cant
 	s.CantGetAccepted = cant
 	s.CantPut = cant
@@ -93,6 +104,50 @@ func (s *SenderTest) Accepted(validatorID ids.ShortID, requestID uint32, contain
 	}
 }
 
+// GetStateSummaryFrontier calls GetStateSummaryFrontierF if it was
+// initialized. If it wasn't initialized and this function shouldn't be called
+// and testing was initialized, then testing will fail.
+func (s *SenderTest) GetStateSummaryFrontier(validatorIDs ids.ShortSet, requestID uint32) {
+	if s.GetStateSummaryFrontierF != nil {
+		s.GetStateSummaryFrontierF(validatorIDs, requestID)
+	} else if s.CantGetStateSummaryFrontier && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateSummaryFrontier")
+	}
+}
+
+// StateSummaryFrontier calls StateSummaryFrontierF if it was initialized. If
+// it wasn't initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) StateSummaryFrontier(validatorID ids.ShortID, requestID uint32, summary []byte) {
+	if s.StateSummaryFrontierF != nil {
+		s.StateSummaryFrontierF(validatorID, requestID, summary)
+	} else if s.CantStateSummaryFrontier && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateSummaryFrontier")
+	}
+}
+
+// GetAcceptedStateSummary calls GetAcceptedStateSummaryF if it was
+// initialized. If it wasn't initialized and this function shouldn't be called
+// and testing was initialized, then testing will fail.
+func (s *SenderTest) GetAcceptedStateSummary(validatorIDs ids.ShortSet, requestID uint32, heights []uint64) {
+	if s.GetAcceptedStateSummaryF != nil {
+		s.GetAcceptedStateSummaryF(validatorIDs, requestID, heights)
+	} else if s.CantGetAcceptedStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetAcceptedStateSummary")
+	}
+}
+
+// AcceptedStateSummary calls AcceptedStateSummaryF if it was initialized. If
+// it wasn't initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) AcceptedStateSummary(validatorID ids.ShortID, requestID uint32, summaryIDs []ids.ID) {
+	if s.AcceptedStateSummaryF != nil {
+		s.AcceptedStateSummaryF(validatorID, requestID, summaryIDs)
+	} else if s.CantAcceptedStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called AcceptedStateSummary")
+	}
+}
+
 // Get calls GetF if it was initialized. If it wasn't initialized and this
 // function shouldn't be called and testing was initialized, then testing will
 // fail.
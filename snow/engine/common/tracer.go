@@ -0,0 +1,131 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+// ContainerTracer is implemented by engines that record the lifecycle of the
+// containers they process, to help diagnose why a container isn't being
+// decided
+type ContainerTracer interface {
+	// TraceContainer returns the recorded lifecycle of [containerID], or false
+	// if nothing was recorded about it. Assumes the context lock is held.
+	TraceContainer(containerID ids.ID) (*Trace, bool)
+}
+
+// TraceEvent is a step in the lifecycle of a container
+type TraceEvent struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	// Node the event was caused by, if any
+	NodeID  string `json:"nodeID,omitempty"`
+	Details string `json:"details,omitempty"`
+}
+
+func (e *TraceEvent) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(e.Time.UTC().Format(time.RFC3339Nano))
+	sb.WriteString(" ")
+	sb.WriteString(e.Type)
+	if e.NodeID != "" {
+		sb.WriteString(fmt.Sprintf(" from %s", e.NodeID))
+	}
+	if e.Details != "" {
+		sb.WriteString(": ")
+		sb.WriteString(e.Details)
+	}
+	return sb.String()
+}
+
+// Trace is the recorded lifecycle of a container
+type Trace struct {
+	ContainerID ids.ID       `json:"containerID"`
+	Events      []TraceEvent `json:"events"`
+	// Number of events that were dropped because the trace was full
+	DroppedEvents int `json:"droppedEvents"`
+}
+
+func (t *Trace) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("Trace of %s:", t.ContainerID))
+	for _, event := range t.Events {
+		sb.WriteString("\n    ")
+		sb.WriteString(event.String())
+	}
+	if t.DroppedEvents > 0 {
+		sb.WriteString(fmt.Sprintf("\n    ... %d more events dropped", t.DroppedEvents))
+	}
+	return sb.String()
+}
+
+// Tracer records the lifecycle of the most recently traced containers. It
+// isn't safe for concurrent use.
+type Tracer struct {
+	clock     timer.Clock
+	maxEvents int
+	// container ID --> *Trace
+	traces cache.LRU
+}
+
+// NewTracer returns a tracer that keeps the traces of up to [maxContainers]
+// containers, with up to [maxEvents] events each. When a trace is full, the
+// last event is replaced so that the most recent event is always kept.
+func NewTracer(maxContainers, maxEvents int) *Tracer {
+	return &Tracer{
+		maxEvents: maxEvents,
+		traces:    cache.LRU{Size: maxContainers},
+	}
+}
+
+// Record adds an event of type [eventType] to the trace of [containerID].
+// [nodeID] may be empty if the event wasn't caused by a node.
+func (t *Tracer) Record(containerID ids.ID, eventType string, nodeID ids.ShortID, format string, args ...interface{}) {
+	var trace *Trace
+	if traceIntf, ok := t.traces.Get(containerID); ok {
+		trace = traceIntf.(*Trace)
+	} else {
+		trace = &Trace{ContainerID: containerID}
+		t.traces.Put(containerID, trace)
+	}
+
+	event := TraceEvent{
+		Time:    t.clock.Time(),
+		Type:    eventType,
+		Details: fmt.Sprintf(format, args...),
+	}
+	if nodeID != ids.ShortEmpty {
+		event.NodeID = address.FormatNodeID(nodeID)
+	}
+
+	if len(trace.Events) < t.maxEvents {
+		trace.Events = append(trace.Events, event)
+		return
+	}
+	trace.Events[len(trace.Events)-1] = event
+	trace.DroppedEvents++
+}
+
+// Get returns a copy of the trace of [containerID], or false if nothing was
+// recorded about it
+func (t *Tracer) Get(containerID ids.ID) (*Trace, bool) {
+	traceIntf, ok := t.traces.Get(containerID)
+	if !ok {
+		return nil, false
+	}
+	trace := traceIntf.(*Trace)
+	return &Trace{
+		ContainerID:   trace.ContainerID,
+		Events:        append([]TraceEvent(nil), trace.Events...),
+		DroppedEvents: trace.DroppedEvents,
+	}, true
+}
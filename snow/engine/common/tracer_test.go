@@ -0,0 +1,54 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestTracer(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := NewTracer(1, 2)
+	tracer.clock.Set(time.Unix(1000, 0))
+
+	containerID := ids.GenerateTestID()
+	_, ok := tracer.Get(containerID)
+	assert.False(ok)
+
+	nodeID := ids.GenerateTestShortID()
+	tracer.Record(containerID, "received", nodeID, "request ID %d", 5)
+	tracer.Record(containerID, "issued", ids.ShortEmpty, "")
+
+	trace, ok := tracer.Get(containerID)
+	assert.True(ok)
+	assert.Equal(containerID, trace.ContainerID)
+	assert.Len(trace.Events, 2)
+	assert.Equal("received", trace.Events[0].Type)
+	assert.Equal("request ID 5", trace.Events[0].Details)
+	assert.True(strings.HasPrefix(trace.Events[0].NodeID, "NodeID-"))
+	assert.Equal("", trace.Events[1].NodeID)
+	assert.Contains(trace.String(), "received from NodeID-")
+
+	// When the trace is full, the most recent event replaces the last one
+	tracer.Record(containerID, "accepted", ids.ShortEmpty, "")
+	trace, _ = tracer.Get(containerID)
+	assert.Len(trace.Events, 2)
+	assert.Equal("accepted", trace.Events[1].Type)
+	assert.Equal(1, trace.DroppedEvents)
+
+	// Only the most recently traced containers are kept
+	otherContainerID := ids.GenerateTestID()
+	tracer.Record(otherContainerID, "received", nodeID, "")
+	_, ok = tracer.Get(containerID)
+	assert.False(ok)
+	_, ok = tracer.Get(otherContainerID)
+	assert.True(ok)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"gotest.tools/assert"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
@@ -50,7 +51,7 @@ func newConfig(t *testing.T) (Config, ids.ShortID, *common.SenderTest, *block.Te
 	sender.CantGetAcceptedFrontier = false
 
 	peer := ids.GenerateTestShortID()
-	if err := peers.AddWeight(peer, 1); err != nil {
+	if err := peers.AddWeight(ids.NodeIDFromShortID(peer), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1070,3 +1071,156 @@ func TestRestartBootstrapping(t *testing.T) {
 		t.Fatalf("Block should be accepted")
 	}
 }
+
+type testSummary struct {
+	id     ids.ID
+	height uint64
+	bytes  []byte
+}
+
+func (s *testSummary) ID() ids.ID     { return s.id }
+func (s *testSummary) Height() uint64 { return s.height }
+func (s *testSummary) Bytes() []byte  { return s.bytes }
+
+// stateSyncVM is a ChainVM that can sync its state from a summary
+type stateSyncVM struct {
+	*block.TestVM
+
+	summaries map[uint64]*testSummary
+	fetched   common.Summary
+	committed common.Summary
+}
+
+func (vm *stateSyncVM) StateSyncEnabled() (bool, error) { return true, nil }
+
+func (vm *stateSyncVM) GetLastStateSummary() (common.Summary, error) {
+	return nil, database.ErrNotFound
+}
+
+func (vm *stateSyncVM) GetStateSummary(height uint64) (common.Summary, error) {
+	summary, ok := vm.summaries[height]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return summary, nil
+}
+
+func (vm *stateSyncVM) ParseStateSummary(summaryBytes []byte) (common.Summary, error) {
+	for _, summary := range vm.summaries {
+		if bytes.Equal(summary.bytes, summaryBytes) {
+			return summary, nil
+		}
+	}
+	return nil, errUnknownBlock
+}
+
+func (vm *stateSyncVM) FetchState(summary common.Summary) error {
+	vm.fetched = summary
+	return nil
+}
+
+func (vm *stateSyncVM) CommitState(summary common.Summary) error {
+	vm.committed = summary
+	return nil
+}
+
+func newStateSyncConfig(t *testing.T) (Config, ids.ShortID, *common.SenderTest, *stateSyncVM) {
+	config, peerID, sender, testVM := newConfig(t)
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+		BytesV:  []byte{0},
+	}
+	testVM.CantLastAccepted = false
+	testVM.LastAcceptedF = func() (ids.ID, error) { return blk0.ID(), nil }
+	testVM.GetBlockF = func(ids.ID) (snowman.Block, error) { return blk0, nil }
+
+	vm := &stateSyncVM{
+		TestVM: testVM,
+		summaries: map[uint64]*testSummary{
+			10: {id: ids.GenerateTestID(), height: 10, bytes: []byte{10}},
+			20: {id: ids.GenerateTestID(), height: 20, bytes: []byte{20}},
+		},
+	}
+	config.VM = vm
+	return config, peerID, sender, vm
+}
+
+func TestBootstrapperStateSync(t *testing.T) {
+	config, peerID, sender, vm := newStateSyncConfig(t)
+
+	requestID := new(uint32)
+	sender.GetStateSummaryFrontierF = func(vdrs ids.ShortSet, reqID uint32) {
+		assert.Assert(t, vdrs.Contains(peerID))
+		*requestID = reqID
+	}
+	var requestedHeights []uint64
+	sender.GetAcceptedStateSummaryF = func(vdrs ids.ShortSet, reqID uint32, heights []uint64) {
+		assert.Assert(t, vdrs.Contains(peerID))
+		*requestID = reqID
+		requestedHeights = heights
+	}
+	fetchedFrontier := false
+	sender.GetAcceptedFrontierF = func(ids.ShortSet, uint32) { fetchedFrontier = true }
+
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		nil,
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Assert(t, !fetchedFrontier, "shouldn't fetch the accepted frontier before state sync")
+
+	summary := vm.summaries[20]
+	if err := bs.StateSummaryFrontier(peerID, *requestID, summary.bytes); err != nil {
+		t.Fatal(err)
+	}
+	assert.DeepEqual(t, []uint64{20}, requestedHeights)
+
+	if err := bs.AcceptedStateSummary(peerID, *requestID, []ids.ID{summary.id}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, common.Summary(summary), vm.fetched)
+	assert.Assert(t, !fetchedFrontier, "shouldn't fetch the accepted frontier while fetching the state")
+
+	if err := bs.StateSyncDone(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, common.Summary(summary), vm.committed)
+	assert.Assert(t, fetchedFrontier, "should fetch the accepted frontier after state sync")
+}
+
+func TestBootstrapperStateSyncNoSummary(t *testing.T) {
+	config, peerID, sender, vm := newStateSyncConfig(t)
+
+	requestID := new(uint32)
+	sender.GetStateSummaryFrontierF = func(_ ids.ShortSet, reqID uint32) { *requestID = reqID }
+	fetchedFrontier := false
+	sender.GetAcceptedFrontierF = func(ids.ShortSet, uint32) { fetchedFrontier = true }
+
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		nil,
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A peer that doesn't support state sync fails the request
+	if err := bs.GetStateSummaryFrontierFailed(peerID, *requestID); err != nil {
+		t.Fatal(err)
+	}
+	assert.Assert(t, vm.fetched == nil, "shouldn't fetch any state")
+	assert.Assert(t, fetchedFrontier, "should fall back to bootstrapping from the accepted frontier")
+}
@@ -12,6 +12,7 @@ import (
 type metrics struct {
 	numRequests, numBlocked prometheus.Gauge
 	getAncestorsBlks        prometheus.Histogram
+	repollDepth             prometheus.Histogram
 }
 
 // Initialize the metrics
@@ -42,12 +43,30 @@ func (m *metrics) Initialize(namespace string, registerer prometheus.Registerer)
 			2000,
 		},
 	})
+	m.repollDepth = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repoll_depth",
+		Help:      "The number of repolls issued before a poll decided blocks",
+		Buckets: []float64{
+			0,
+			1,
+			2,
+			4,
+			8,
+			16,
+			32,
+			64,
+			128,
+			256,
+		},
+	})
 
 	errs := wrappers.Errs{}
 	errs.Add(
 		registerer.Register(m.numRequests),
 		registerer.Register(m.numBlocked),
 		registerer.Register(m.getAncestorsBlks),
+		registerer.Register(m.repollDepth),
 	)
 	return errs.Err
 }
@@ -0,0 +1,25 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/upgrade"
+)
+
+// RulesBlock is a block whose validity depends on the upgrades of the chain
+// that are active at it. Before a RulesBlock is verified, the engine checks it
+// against the rules that the upgrade schedule of the chain has active at its
+// height and timestamp, and drops it if it violates them.
+type RulesBlock interface {
+	snowman.Block
+
+	// Timestamp returns the time that this block was built at
+	Timestamp() time.Time
+
+	// VerifyRules returns an error if this block isn't valid under [rules]
+	VerifyRules(rules upgrade.Rules) error
+}
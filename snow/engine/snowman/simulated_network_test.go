@@ -0,0 +1,281 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/networking/sender"
+	"github.com/ava-labs/avalanchego/snow/networking/timeout"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+// simulatedBlockID returns the ID of the block at [height] of the chain built
+// by the nodes of a simulated network
+func simulatedBlockID(height uint64) ids.ID {
+	if height == 0 {
+		return Genesis
+	}
+	return ids.ID{byte(height)}
+}
+
+// simulatedBlock is a block of the chain built by the nodes of a simulated
+// network. Each node parses its own copy of the block, so the block's parent is
+// looked up in the VM of the node.
+type simulatedBlock struct {
+	*snowman.TestBlock
+	vm *simulatedVM
+}
+
+func (b *simulatedBlock) Parent() snowman.Block {
+	parentID := simulatedBlockID(b.HeightV - 1)
+	if parent, ok := b.vm.blocks[parentID]; ok {
+		return parent
+	}
+	return &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     parentID,
+		StatusV: choices.Unknown,
+	}}
+}
+
+// simulatedVM is the VM of a node of a simulated network. It only knows about
+// the blocks it built or parsed. Assumes the lock of the node's context is held.
+type simulatedVM struct {
+	block.TestVM
+
+	blocks     map[ids.ID]*simulatedBlock
+	preference ids.ID
+}
+
+func newSimulatedVM() *simulatedVM {
+	vm := &simulatedVM{
+		blocks:     make(map[ids.ID]*simulatedBlock),
+		preference: Genesis,
+	}
+	vm.parse([]byte{0}).StatusV = choices.Accepted
+
+	vm.Default(false)
+	vm.LastAcceptedF = func() (ids.ID, error) {
+		lastAccepted := vm.blocks[Genesis]
+		for _, blk := range vm.blocks {
+			if blk.Status() == choices.Accepted && blk.HeightV > lastAccepted.HeightV {
+				lastAccepted = blk
+			}
+		}
+		return lastAccepted.ID(), nil
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blk, ok := vm.blocks[blkID]; ok {
+			return blk, nil
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		if len(b) != 1 {
+			return nil, errUnknownBytes
+		}
+		return vm.parse(b), nil
+	}
+	vm.BuildBlockF = func() (snowman.Block, error) {
+		return vm.parse([]byte{byte(vm.blocks[vm.preference].HeightV + 1)}), nil
+	}
+	vm.SetPreferenceF = func(blkID ids.ID) error {
+		vm.preference = blkID
+		return nil
+	}
+	return vm
+}
+
+func (vm *simulatedVM) parse(b []byte) *simulatedBlock {
+	height := uint64(b[0])
+	blkID := simulatedBlockID(height)
+	if blk, ok := vm.blocks[blkID]; ok {
+		return blk
+	}
+	blk := &simulatedBlock{
+		TestBlock: &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     blkID,
+				StatusV: choices.Processing,
+			},
+			HeightV: height,
+			BytesV:  b,
+		},
+		vm: vm,
+	}
+	vm.blocks[blkID] = blk
+	return blk
+}
+
+// simulatedNode is a node of a simulated network that runs a snowman engine
+type simulatedNode struct {
+	ctx    *snow.Context
+	vm     *simulatedVM
+	msgs   chan common.Message
+	router *router.ChainRouter
+}
+
+func newSimulatedNode(
+	t *testing.T,
+	network *sender.SimulatedNetwork,
+	nodeID ids.ShortID,
+	vdrs validators.Set,
+) *simulatedNode {
+	tm := &timeout.Manager{}
+	err := tm.Initialize(&timer.AdaptiveTimeoutConfig{
+		InitialTimeout:     time.Second,
+		MinimumTimeout:     time.Second,
+		MaximumTimeout:     10 * time.Second,
+		TimeoutHalflife:    5 * time.Minute,
+		TimeoutCoefficient: 1.25,
+		Registerer:         prometheus.NewRegistry(),
+	}, benchlist.NewNoBenchlist())
+	if err != nil {
+		t.Fatal(err)
+	}
+	go tm.Dispatch()
+
+	chainRouter := &router.ChainRouter{}
+	err = chainRouter.Initialize(nodeID, logging.NoLog{}, tm, time.Hour, time.Second, ids.Set{}, nil, router.HealthConfig{}, "", prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := snow.DefaultContextTest()
+	ctx.NodeID = nodeID
+	externalSender := network.AddNode(nodeID, chainRouter)
+	engineSender := &sender.Sender{}
+	if err := engineSender.Initialize(ctx, externalSender, chainRouter, tm, "", prometheus.NewRegistry()); err != nil {
+		t.Fatal(err)
+	}
+
+	vm := newSimulatedVM()
+	config := DefaultConfig()
+	config.Ctx = ctx
+	config.Validators = vdrs
+	config.Sender = engineSender
+	config.VM = vm
+	config.Params = snowball.Parameters{
+		Metrics:               prometheus.NewRegistry(),
+		K:                     vdrs.Len(),
+		Alpha:                 vdrs.Len()/2 + 1,
+		BetaVirtuous:          2,
+		BetaRogue:             3,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     100,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := make(chan common.Message, 1)
+	handler := &router.Handler{}
+	err = handler.Initialize(
+		te,
+		vdrs,
+		msgs,
+		1024,
+		router.DefaultMaxNonStakerPendingMsgs,
+		router.DefaultStakerPortion,
+		router.DefaultStakerPortion,
+		"",
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go handler.Dispatch()
+	chainRouter.AddChain(handler)
+
+	return &simulatedNode{
+		ctx:    ctx,
+		vm:     vm,
+		msgs:   msgs,
+		router: chainRouter,
+	}
+}
+
+// status returns the status of block [blkID] on this node
+func (n *simulatedNode) status(blkID ids.ID) choices.Status {
+	n.ctx.Lock.Lock()
+	defer n.ctx.Lock.Unlock()
+
+	blk, ok := n.vm.blocks[blkID]
+	if !ok {
+		return choices.Unknown
+	}
+	return blk.Status()
+}
+
+// waitForAccepted fails [t] if block [blkID] isn't accepted on every node in
+// [nodes] soon
+func waitForAccepted(t *testing.T, nodes []*simulatedNode, blkID ids.ID) {
+	deadline := time.Now().Add(10 * time.Second)
+	for _, node := range nodes {
+		for node.status(blkID) != choices.Accepted {
+			if time.Now().After(deadline) {
+				t.Fatalf("block %s wasn't accepted by %s", blkID, node.ctx.NodeID)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestEngineSimulatedNetworkPartition(t *testing.T) {
+	network := sender.NewSimulatedNetwork(sender.SimulatedNetworkConfig{
+		Latency: time.Millisecond,
+		Jitter:  time.Millisecond,
+	})
+	defer network.Close()
+
+	nodeIDs := make([]ids.ShortID, 5)
+	vdrs := validators.NewSet()
+	for i := range nodeIDs {
+		nodeIDs[i] = ids.GenerateTestShortID()
+		if err := vdrs.AddWeight(ids.NodeIDFromShortID(nodeIDs[i]), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Every poll queries every validator
+	vdrs.SetDistinctSampling(len(nodeIDs))
+
+	nodes := make([]*simulatedNode, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		nodes[i] = newSimulatedNode(t, network, nodeID, vdrs)
+		defer nodes[i].router.Shutdown()
+	}
+
+	// The last node is cut off from the others, which can still accept blocks
+	network.Partition(nodeIDs[:4])
+	nodes[0].msgs <- common.PendingTxs
+	waitForAccepted(t, nodes[:4], simulatedBlockID(1))
+	if status := nodes[4].status(simulatedBlockID(1)); status != choices.Unknown {
+		t.Fatalf("isolated node should never have heard of the block but its status is %s", status)
+	}
+
+	// Once the partition is healed, the last node fetches the block it missed
+	// when it's queried about the next one, and accepts both
+	network.Heal()
+	nodes[0].msgs <- common.PendingTxs
+	waitForAccepted(t, nodes, simulatedBlockID(2))
+	waitForAccepted(t, nodes[4:], simulatedBlockID(1))
+}
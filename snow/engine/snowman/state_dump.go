@@ -0,0 +1,59 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var _ common.StateDumper = &Transitive{}
+
+// StateDump is a snapshot of the internal state of a snowman engine
+type StateDump struct {
+	Bootstrapped bool `json:"bootstrapped"`
+	// Last accepted block
+	LastAccepted ids.ID `json:"lastAccepted"`
+	// Tail of the preferred chain
+	Preference ids.ID `json:"preference"`
+	// Blocks that have been issued to consensus but not yet decided
+	Processing []ids.ID `json:"processing"`
+	// Blocks waiting on their ancestors before being issued to consensus
+	Pending []ids.ID `json:"pending"`
+	// Number of blocks requested from peers but not yet received
+	OutstandingBlockRequests int `json:"outstandingBlockRequests"`
+	// Number of polls that haven't finished
+	NumPolls int `json:"numPolls"`
+	// Description of the outstanding polls and the votes received so far
+	Polls string `json:"polls"`
+	// Block ID -> number of operations blocked on the block being issued
+	Blocked map[string]int `json:"blocked"`
+	// Number of blocks that will be built once fewer blocks are processing
+	PendingBuildBlocks int `json:"pendingBuildBlocks"`
+}
+
+// DumpState implements the common.StateDumper interface
+func (t *Transitive) DumpState() (interface{}, error) {
+	lastAccepted, err := t.VM.LastAccepted()
+	if err != nil {
+		return nil, err
+	}
+	state := &StateDump{
+		Bootstrapped:             t.Ctx.IsBootstrapped(),
+		LastAccepted:             lastAccepted,
+		Processing:               []ids.ID{},
+		Pending:                  t.pending.List(),
+		OutstandingBlockRequests: t.blkReqs.Len(),
+		NumPolls:                 t.polls.Len(),
+		Polls:                    t.polls.String(),
+		Blocked:                  t.blocked.Summary(),
+		PendingBuildBlocks:       t.pendingBuildBlocks,
+	}
+	// Consensus is only initialized once bootstrapping has finished
+	if state.Bootstrapped {
+		state.Preference = t.Consensus.Preference()
+		state.Processing = t.Consensus.Processing().List()
+	}
+	return state, nil
+}
@@ -0,0 +1,88 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+)
+
+func TestEngineDumpState(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	processingBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	missingBlk := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Unknown,
+	}}
+	pendingBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: missingBlk,
+		HeightV: 2,
+		BytesV:  []byte{2},
+	}
+
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(b, processingBlk.Bytes()):
+			return processingBlk, nil
+		case bytes.Equal(b, pendingBlk.Bytes()):
+			return pendingBlk, nil
+		}
+		t.Fatalf("Unknown bytes")
+		return nil, errUnknownBytes
+	}
+	sender.CantPushQuery = false
+	sender.CantGet = false
+
+	if err := te.Put(vdr, 0, processingBlk.ID(), processingBlk.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.Put(vdr, 0, pendingBlk.ID(), pendingBlk.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	vm.LastAcceptedF = func() (ids.ID, error) { return gBlk.ID(), nil }
+
+	stateIntf, err := te.DumpState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, ok := stateIntf.(*StateDump)
+	switch {
+	case !ok:
+		t.Fatalf("Wrong state type %T", stateIntf)
+	case !state.Bootstrapped:
+		t.Fatalf("Should have been bootstrapped")
+	case state.LastAccepted != gBlk.ID():
+		t.Fatalf("Wrong last accepted block")
+	case state.Preference != processingBlk.ID():
+		t.Fatalf("Wrong preference")
+	case len(state.Processing) != 1 || state.Processing[0] != processingBlk.ID():
+		t.Fatalf("Wrong processing blocks %s", state.Processing)
+	case len(state.Pending) != 1 || state.Pending[0] != pendingBlk.ID():
+		t.Fatalf("Wrong pending blocks %s", state.Pending)
+	case state.OutstandingBlockRequests != 1:
+		t.Fatalf("Should have had 1 outstanding block request")
+	case state.NumPolls != 1:
+		t.Fatalf("Should have had 1 outstanding poll")
+	case state.Blocked[missingBlk.ID().String()] != 1:
+		t.Fatalf("Should have been blocking on the missing block")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ava-labs/avalanchego/health"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -25,6 +26,15 @@ const (
 	// TODO define this constant in one place rather than here and in snowman
 	// Max containers size in a MultiPut message
 	maxContainersLen = int(4 * network.DefaultMaxMessageSize / 5)
+
+	// Max number of the container IDs in a pushed accepted frontier that are
+	// fetched. Peers push the ID of each container as they accept it.
+	maxPushedFrontierSize = 4
+
+	// Pushed accepted frontiers are ignored while this many containers are
+	// already being fetched, so that peers can't make us send an unbounded
+	// number of requests.
+	maxPushedFrontierRequests = 64
 )
 
 var _ Engine = &Transitive{}
@@ -41,6 +51,9 @@ type Transitive struct {
 	// track outstanding preference requests
 	polls poll.Set
 
+	// tracks the portion of queries that fail
+	pollTracker *common.PollTracker
+
 	// blocks that have we have sent get requests for but haven't yet received
 	blkReqs common.Requests
 
@@ -55,6 +68,9 @@ type Transitive struct {
 	// processing blocks has gone below the optimal number.
 	pendingBuildBlocks int
 
+	// number of repolls issued since a poll last decided blocks
+	repolls int
+
 	// errs tracks if an error has occurred in a callback
 	errs wrappers.Errs
 }
@@ -65,6 +81,10 @@ func (t *Transitive) Initialize(config Config) error {
 
 	t.Params = config.Params
 	t.Consensus = config.Consensus
+	t.pollTracker = config.PollTracker
+	if t.pollTracker == nil {
+		t.pollTracker = common.NewPollTracker(config.HealthConfig)
+	}
 
 	factory := poll.NewEarlyTermNoTraversalFactory(config.Params.Alpha)
 	t.polls = poll.NewSet(factory,
@@ -148,6 +168,11 @@ func (t *Transitive) Gossip() error {
 // Shutdown implements the Engine interface
 func (t *Transitive) Shutdown() error {
 	t.Ctx.Log.Info("shutting down consensus engine")
+	if t.AuditLog != nil {
+		if err := t.AuditLog.Close(); err != nil {
+			t.Ctx.Log.Warn("failed to close the audit log: %s", err)
+		}
+	}
 	return t.VM.Shutdown()
 }
 
@@ -236,6 +261,40 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkByt
 	return t.buildBlocks()
 }
 
+// AcceptedFrontier implements the Engine interface
+func (t *Transitive) AcceptedFrontier(vdr ids.ShortID, requestID uint32, blkIDs []ids.ID) error {
+	if requestID != constants.GossipMsgRequestID {
+		return t.Bootstrapper.AcceptedFrontier(vdr, requestID, blkIDs)
+	}
+
+	// [vdr] pushed its newly accepted frontier to us. Fetch any of these blocks
+	// we don't know about so that we catch up without waiting for a poll.
+	if !t.IsBootstrapped() {
+		t.Ctx.Log.Verbo("dropping pushed AcceptedFrontier(%s, %d, %s) due to bootstrapping",
+			vdr, requestID, blkIDs)
+		return nil
+	}
+	if !t.Validators.Contains(ids.NodeIDFromShortID(vdr)) {
+		t.Ctx.Log.Verbo("dropping pushed AcceptedFrontier(%s, %d, %s) from a non-validator",
+			vdr, requestID, blkIDs)
+		return nil
+	}
+	if t.blkReqs.Len() >= maxPushedFrontierRequests {
+		t.Ctx.Log.Debug("dropping pushed AcceptedFrontier(%s, %d, %s) due to %d outstanding requests",
+			vdr, requestID, blkIDs, t.blkReqs.Len())
+		return nil
+	}
+	if len(blkIDs) > maxPushedFrontierSize {
+		blkIDs = blkIDs[:maxPushedFrontierSize]
+	}
+	for _, blkID := range blkIDs {
+		if _, err := t.issueFromByID(vdr, blkID); err != nil {
+			return err
+		}
+	}
+	return t.buildBlocks()
+}
+
 // GetFailed implements the Engine interface
 func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32) error {
 	// not done bootstrapping --> didn't send a get --> this message is invalid
@@ -336,6 +395,7 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes []ids.ID) er
 		return t.QueryFailed(vdr, requestID)
 	}
 	blkID := votes[0]
+	t.pollTracker.Succeeded()
 
 	t.Ctx.Log.Verbo("Chits(%s, %d) contains vote for %s", vdr, requestID, blkID)
 
@@ -368,6 +428,7 @@ func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
 		t.Ctx.Log.Warn("dropping QueryFailed(%s, %d) due to bootstrapping", vdr, requestID)
 		return nil
 	}
+	t.pollTracker.Failed()
 
 	t.blocked.Register(&voter{
 		t:         t,
@@ -379,6 +440,11 @@ func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
 
 // Notify implements the Engine interface
 func (t *Transitive) Notify(msg common.Message) error {
+	// state sync is part of bootstrapping, so the bootstrapper handles this
+	if msg == common.StateSyncDone {
+		return t.Bootstrapper.StateSyncDone()
+	}
+
 	// if the engine hasn't been bootstrapped, we shouldn't build/issue blocks from the VM
 	if !t.Ctx.IsBootstrapped() {
 		t.Ctx.Log.Debug("dropping Notify due to bootstrapping")
@@ -398,14 +464,26 @@ func (t *Transitive) Notify(msg common.Message) error {
 }
 
 // Build blocks if they have been requested and the number of processing blocks
-// is less than optimal.
+// is less than optimal. If the VM is a common.PriorityMempool, blocks are only
+// built while its mempool isn't empty, and are built until it is.
 func (t *Transitive) buildBlocks() error {
 	if err := t.errs.Err; err != nil {
 		return err
 	}
+	mempool, hasMempool := t.VM.(common.PriorityMempool)
 	for t.pendingBuildBlocks > 0 && t.Consensus.NumProcessing() < t.Params.OptimalProcessing {
 		t.pendingBuildBlocks--
 
+		numTxs := 0
+		if hasMempool {
+			numTxs = mempool.NumTxs()
+			if numTxs == 0 {
+				t.Ctx.Log.Verbo("not building a block because the mempool is empty")
+				t.pendingBuildBlocks = 0
+				return nil
+			}
+		}
+
 		blk, err := t.VM.BuildBlock()
 		if err != nil {
 			t.Ctx.Log.Debug("VM.BuildBlock errored with: %s", err)
@@ -436,6 +514,14 @@ func (t *Transitive) buildBlocks() error {
 		} else {
 			t.Ctx.Log.Warn("VM.BuildBlock returned a block with unissued ancestors")
 		}
+
+		// If the block took txs from the mempool but left some behind, build
+		// another block with them
+		if hasMempool && t.pendingBuildBlocks == 0 {
+			if remaining := mempool.NumTxs(); remaining > 0 && remaining < numTxs {
+				t.pendingBuildBlocks++
+			}
+		}
 	}
 	return nil
 }
@@ -449,6 +535,7 @@ func (t *Transitive) repoll() {
 
 	for i := t.polls.Len(); i < t.Params.ConcurrentRepolls; i++ {
 		t.pullQuery(prefID)
+		t.repolls++
 	}
 }
 
@@ -586,17 +673,20 @@ func (t *Transitive) sendRequest(vdr ids.ShortID, blkID ids.ID) {
 func (t *Transitive) pullQuery(blkID ids.ID) {
 	t.Ctx.Log.Verbo("about to sample from: %s", t.Validators)
 	// The validators we will query
-	vdrs, err := t.Validators.Sample(t.Params.K)
-	vdrBag := ids.ShortBag{}
+	vdrs, err := t.SampleValidators(t.Params.K, blkID, t.RequestID+1)
+	vdrBag := ids.NodeIDBag{}
 	for _, vdr := range vdrs {
 		vdrBag.Add(vdr.ID())
 	}
 
 	t.RequestID++
-	if err == nil && t.polls.Add(t.RequestID, vdrBag) {
+	if err == nil && t.polls.Add(t.RequestID, blkID, vdrBag) {
+		if t.AuditLog != nil {
+			t.AuditLog.StartPoll(t.RequestID)
+		}
 		vdrList := vdrBag.List()
 		vdrSet := ids.NewShortSet(len(vdrList))
-		vdrSet.Add(vdrList...)
+		vdrSet.Add(ids.NodeIDsToShortIDs(vdrList)...)
 		t.Sender.PullQuery(vdrSet, t.RequestID, blkID)
 	} else if err != nil {
 		t.Ctx.Log.Error("query for %s was dropped due to an insufficient number of validators", blkID)
@@ -606,17 +696,20 @@ func (t *Transitive) pullQuery(blkID ids.ID) {
 // send a push query for this block
 func (t *Transitive) pushQuery(blk snowman.Block) {
 	t.Ctx.Log.Verbo("about to sample from: %s", t.Validators)
-	vdrs, err := t.Validators.Sample(t.Params.K)
-	vdrBag := ids.ShortBag{}
+	vdrs, err := t.SampleValidators(t.Params.K, blk.ID(), t.RequestID+1)
+	vdrBag := ids.NodeIDBag{}
 	for _, vdr := range vdrs {
 		vdrBag.Add(vdr.ID())
 	}
 
 	t.RequestID++
-	if err == nil && t.polls.Add(t.RequestID, vdrBag) {
+	if err == nil && t.polls.Add(t.RequestID, blk.ID(), vdrBag) {
+		if t.AuditLog != nil {
+			t.AuditLog.StartPoll(t.RequestID)
+		}
 		vdrList := vdrBag.List()
 		vdrSet := ids.NewShortSet(len(vdrList))
-		vdrSet.Add(vdrList...)
+		vdrSet.Add(ids.NodeIDsToShortIDs(vdrList)...)
 
 		t.Sender.PushQuery(vdrSet, t.RequestID, blk.ID(), blk.Bytes())
 	} else if err != nil {
@@ -624,6 +717,18 @@ func (t *Transitive) pushQuery(blk snowman.Block) {
 	}
 }
 
+// verify [blk]. If [blk] is a RulesBlock, it's first checked against the rules
+// that are active at it.
+func (t *Transitive) verify(blk snowman.Block) error {
+	if blk, ok := blk.(RulesBlock); ok {
+		rules := t.Ctx.Upgrades.Rules(blk.Height(), blk.Timestamp())
+		if err := blk.VerifyRules(rules); err != nil {
+			return fmt.Errorf("block violates %s: %w", rules, err)
+		}
+	}
+	return blk.Verify()
+}
+
 // issue [blk] to consensus
 func (t *Transitive) deliver(blk snowman.Block) error {
 	if t.Consensus.DecidedOrProcessing(blk) {
@@ -648,7 +753,7 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 	// calling Verify on this block is allowed.
 
 	// make sure this block is valid
-	if err := blk.Verify(); err != nil {
+	if err := t.verify(blk); err != nil {
 		t.Ctx.Log.Debug("block failed verification due to %s, dropping block", err)
 
 		// if verify fails, then all descendants are also invalid
@@ -673,7 +778,7 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 			return err
 		}
 		for _, blk := range options {
-			if err := blk.Verify(); err != nil {
+			if err := t.verify(blk); err != nil {
 				t.Ctx.Log.Debug("block failed verification due to %s, dropping block", err)
 				dropped = append(dropped, blk)
 			} else {
@@ -727,27 +832,27 @@ func (t *Transitive) IsBootstrapped() bool {
 	return t.Ctx.IsBootstrapped()
 }
 
-// Health implements the common.Engine interface
+// HealthCheck implements the common.Engine interface. Reports whether the
+// chain has bootstrapped, the health of consensus, the poll failure rate and
+// the health of the VM as separate components.
 func (t *Transitive) HealthCheck() (interface{}, error) {
-	var (
-		consensusIntf interface{} = struct{}{}
-		consensusErr  error
-	)
-	if t.Ctx.IsBootstrapped() {
-		consensusIntf, consensusErr = t.Consensus.HealthCheck()
+	components := health.Components{}
+
+	bootstrappedIntf, bootstrappedErr := common.BootstrappedHealthCheck(t.Ctx)
+	components.Add("bootstrapped", bootstrappedIntf, bootstrappedErr)
+
+	// Consensus is only initialized once bootstrapping has finished
+	if bootstrappedErr == nil {
+		consensusIntf, consensusErr := t.Consensus.HealthCheck()
+		components.Add("consensus", consensusIntf, consensusErr)
+
+		pollsIntf, pollsErr := t.pollTracker.HealthCheck()
+		components.Add("polls", pollsIntf, pollsErr)
 	}
+
 	vmIntf, vmErr := t.VM.HealthCheck()
-	intf := map[string]interface{}{
-		"consensus": consensusIntf,
-		"vm":        vmIntf,
-	}
-	if consensusErr == nil {
-		return intf, vmErr
-	}
-	if vmErr == nil {
-		return intf, consensusErr
-	}
-	return intf, fmt.Errorf("vm: %s ; consensus: %s", vmErr, consensusErr)
+	components.Add("vm", vmIntf, vmErr)
+	return components, components.Err()
 }
 
 // GetBlock implements the snowman.Engine interface
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -16,7 +17,9 @@ import (
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/upgrade"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
@@ -34,7 +37,7 @@ func setup(t *testing.T) (ids.ShortID, validators.Set, *common.SenderTest, *bloc
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -392,9 +395,9 @@ func TestEngineMultipleQuery(t *testing.T) {
 
 	errs := wrappers.Errs{}
 	errs.Add(
-		vals.AddWeight(vdr0, 1),
-		vals.AddWeight(vdr1, 1),
-		vals.AddWeight(vdr2, 1),
+		vals.AddWeight(ids.NodeIDFromShortID(vdr0), 1),
+		vals.AddWeight(ids.NodeIDFromShortID(vdr1), 1),
+		vals.AddWeight(ids.NodeIDFromShortID(vdr2), 1),
 	)
 	if errs.Errored() {
 		t.Fatal(errs.Err)
@@ -787,6 +790,61 @@ func TestEngineBuildBlock(t *testing.T) {
 	}
 }
 
+// mempoolVM is a ChainVM whose mempool holds [numTxs] txs
+type mempoolVM struct {
+	*block.TestVM
+
+	numTxs int
+}
+
+func (vm *mempoolVM) MempoolTxs() []common.MempoolTx       { return nil }
+func (vm *mempoolVM) EvictTx(ids.ID) bool                  { return false }
+func (vm *mempoolVM) AddTx(common.PendingTx, uint64) error { vm.numTxs++; return nil }
+func (vm *mempoolVM) PopTx() (common.PendingTx, bool)      { return nil, false }
+func (vm *mempoolVM) NumTxs() int                          { return vm.numTxs }
+
+func TestEngineBuildBlocksFromMempool(t *testing.T) {
+	_, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(true)
+	sender.CantPushQuery = false
+
+	mempool := &mempoolVM{TestVM: vm}
+	te.VM = mempool
+
+	// The VM doesn't have any txs, so BuildBlock shouldn't be called
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each block takes one tx, so both txs should be built into blocks after
+	// one notification
+	mempool.numTxs = 2
+	parent := gBlk
+	built := 0
+	vm.BuildBlockF = func() (snowman.Block, error) {
+		mempool.numTxs--
+		built++
+		blk := &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentV: parent,
+			HeightV: parent.Height() + 1,
+			BytesV:  []byte{byte(built)},
+		}
+		parent = blk
+		return blk, nil
+	}
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+	if built != 2 {
+		t.Fatalf("expected 2 blocks to be built but %d were", built)
+	}
+}
+
 func TestEngineRepoll(t *testing.T) {
 	vdr, _, sender, _, te, _ := setup(t)
 
@@ -836,9 +894,9 @@ func TestVoteCanceling(t *testing.T) {
 
 	errs := wrappers.Errs{}
 	errs.Add(
-		vals.AddWeight(vdr0, 1),
-		vals.AddWeight(vdr1, 1),
-		vals.AddWeight(vdr2, 1),
+		vals.AddWeight(ids.NodeIDFromShortID(vdr0), 1),
+		vals.AddWeight(ids.NodeIDFromShortID(vdr1), 1),
+		vals.AddWeight(ids.NodeIDFromShortID(vdr2), 1),
 	)
 	if errs.Errored() {
 		t.Fatal(errs.Err)
@@ -1433,7 +1491,7 @@ func TestEngineInvalidBlockIgnoredFromUnexpectedPeer(t *testing.T) {
 	vdr, vdrs, sender, vm, te, gBlk := setup(t)
 
 	secondVdr := ids.GenerateTestShortID()
-	if err := vdrs.AddWeight(secondVdr, 1); err != nil {
+	if err := vdrs.AddWeight(ids.NodeIDFromShortID(secondVdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1638,7 +1696,7 @@ func TestEngineAggressivePolling(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1757,10 +1815,10 @@ func TestEngineDoubleChit(t *testing.T) {
 	vdr0 := ids.GenerateTestShortID()
 	vdr1 := ids.GenerateTestShortID()
 
-	if err := vals.AddWeight(vdr0, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr0), 1); err != nil {
 		t.Fatal(err)
 	}
-	if err := vals.AddWeight(vdr1, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr1), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1891,7 +1949,7 @@ func TestEngineBuildBlockLimit(t *testing.T) {
 	config.Validators = vals
 
 	vdr := ids.GenerateTestShortID()
-	if err := vals.AddWeight(vdr, 1); err != nil {
+	if err := vals.AddWeight(ids.NodeIDFromShortID(vdr), 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -2472,3 +2530,126 @@ func TestEngineNonPreferredAmplification(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestEnginePushedAcceptedFrontier(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(true)
+
+	missingBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Unknown,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+
+	vm.CantGetBlock = false
+
+	requested := new(bool)
+	sender.GetF = func(inVdr ids.ShortID, _ uint32, blkID ids.ID) {
+		if inVdr != vdr {
+			t.Fatalf("Asking wrong validator for block")
+		}
+		if blkID != missingBlk.ID() {
+			t.Fatalf("Asking for wrong block")
+		}
+		*requested = true
+	}
+
+	// Frontiers pushed by non-validators are ignored
+	if err := te.AcceptedFrontier(ids.GenerateTestShortID(), constants.GossipMsgRequestID, []ids.ID{missingBlk.ID()}); err != nil {
+		t.Fatal(err)
+	}
+	if *requested {
+		t.Fatalf("Shouldn't have requested a block pushed by a non-validator")
+	}
+
+	if err := te.AcceptedFrontier(vdr, constants.GossipMsgRequestID, []ids.ID{missingBlk.ID()}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !*requested {
+		t.Fatalf("Should have requested the pushed block")
+	}
+}
+
+// rulesBlock is a block that's only valid once the upgrade named [requires]
+// is active
+type rulesBlock struct {
+	snowman.TestBlock
+
+	timestamp time.Time
+	requires  string
+}
+
+func (b *rulesBlock) Timestamp() time.Time { return b.timestamp }
+
+func (b *rulesBlock) VerifyRules(rules upgrade.Rules) error {
+	if !rules.IsActive(b.requires) {
+		return errors.New("upgrade isn't active")
+	}
+	return nil
+}
+
+func TestEngineDropsBlocksThatViolateRules(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	activation := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	te.Ctx.Upgrades = upgrade.Schedule{{Name: "upgrade", Time: activation}}
+
+	tooEarlyBlk := &rulesBlock{
+		TestBlock: snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentV: gBlk,
+			HeightV: 1,
+			BytesV:  []byte{1},
+		},
+		timestamp: activation.Add(-time.Second),
+		requires:  "upgrade",
+	}
+	blk := &rulesBlock{
+		TestBlock: snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentV: gBlk,
+			HeightV: 1,
+			BytesV:  []byte{2},
+		},
+		timestamp: activation,
+		requires:  "upgrade",
+	}
+
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(b, tooEarlyBlk.Bytes()):
+			return tooEarlyBlk, nil
+		case bytes.Equal(b, blk.Bytes()):
+			return blk, nil
+		}
+		t.Fatalf("Unknown block bytes")
+		return nil, errUnknownBytes
+	}
+	sender.CantPushQuery = false
+
+	if err := te.Put(vdr, 0, tooEarlyBlk.ID(), tooEarlyBlk.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if te.Consensus.DecidedOrProcessing(tooEarlyBlk) {
+		t.Fatalf("A block that violates the active rules should have been dropped")
+	}
+
+	if err := te.Put(vdr, 0, blk.ID(), blk.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !te.Consensus.DecidedOrProcessing(blk) {
+		t.Fatalf("A block that follows the active rules should have been issued")
+	}
+}
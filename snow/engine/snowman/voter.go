@@ -32,12 +32,20 @@ func (v *voter) Update() {
 		return
 	}
 
+	if v.t.AuditLog != nil {
+		var votes []ids.ID
+		if v.response != ids.Empty {
+			votes = []ids.ID{v.response}
+		}
+		v.t.AuditLog.RecordChit(v.requestID, v.vdr, votes)
+	}
+
 	results := ids.Bag{}
 	finished := false
 	if v.response == ids.Empty {
-		results, finished = v.t.polls.Drop(v.requestID, v.vdr)
+		results, finished = v.t.polls.Drop(v.requestID, ids.NodeIDFromShortID(v.vdr))
 	} else {
-		results, finished = v.t.polls.Vote(v.requestID, v.vdr, v.response)
+		results, finished = v.t.polls.Vote(v.requestID, ids.NodeIDFromShortID(v.vdr), v.response)
 	}
 
 	if !finished {
@@ -48,11 +56,24 @@ func (v *voter) Update() {
 	// must be bubbled to the nearest valid block
 	results = v.bubbleVotes(results)
 
+	if v.t.AuditLog != nil {
+		votes := make(map[ids.ID]int)
+		for _, blkID := range results.List() {
+			votes[blkID] = results.Count(blkID)
+		}
+		v.t.AuditLog.FinishPoll(v.requestID, votes)
+	}
+
 	v.t.Ctx.Log.Debug("Finishing poll [%d] with:\n%s", v.requestID, &results)
+	numProcessing := v.t.Consensus.NumProcessing()
 	if err := v.t.Consensus.RecordPoll(results); err != nil {
 		v.t.errs.Add(err)
 		return
 	}
+	if v.t.Consensus.NumProcessing() < numProcessing {
+		v.t.repollDepth.Observe(float64(v.t.repolls))
+		v.t.repolls = 0
+	}
 
 	if err := v.t.VM.SetPreference(v.t.Consensus.Preference()); err != nil {
 		v.t.errs.Add(err)
@@ -60,6 +60,18 @@ func (b *Blocker) Register(pending Blockable) {
 	pending.Update()
 }
 
+// Summary returns the number of objects blocked on each event, keyed by the
+// event's ID
+func (b *Blocker) Summary() map[string]int {
+	b.init()
+
+	summary := make(map[string]int, len(*b))
+	for key, value := range *b {
+		summary[key.String()] = len(value)
+	}
+	return summary
+}
+
 // PrefixedString returns the same value as the String function, with all the
 // new lines prefixed by [prefix]
 func (b *Blocker) PrefixedString(prefix string) string {
@@ -36,6 +36,9 @@ type Benchlist interface {
 	// IsBenched returns true if messages to [validatorID]
 	// should not be sent over the network and should immediately fail.
 	IsBenched(validatorID ids.ShortID) bool
+	// Unbench removes [validatorID] from the benchlist, if it's benched, and
+	// forgets its failures
+	Unbench(validatorID ids.ShortID)
 }
 
 // Data about a validator who is benched
@@ -189,7 +192,7 @@ func (b *benchlist) remove(validator *benchData) {
 
 	// Update metrics
 	b.metrics.numBenched.Set(float64(b.benchedQueue.Len()))
-	benchedStake, err := b.vdrs.SubsetWeight(b.benchlistSet)
+	benchedStake, err := b.vdrs.SubsetWeight(ids.NodeIDSetFromShortSet(b.benchlistSet))
 	if err != nil {
 		// This should never happen
 		b.log.Error("couldn't get benched stake: %w", err)
@@ -243,6 +246,27 @@ func (b *benchlist) isBenched(validatorID ids.ShortID) bool {
 	return false
 }
 
+// Unbench implements the Benchlist interface
+func (b *benchlist) Unbench(validatorID ids.ShortID) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.streaklock.Lock()
+	delete(b.failureStreaks, validatorID)
+	b.streaklock.Unlock()
+
+	if !b.isBenched(validatorID) {
+		return
+	}
+	for _, benched := range b.benchedQueue {
+		if benched.validatorID == validatorID {
+			b.remove(benched)
+			break
+		}
+	}
+	b.setNextLeaveTime()
+}
+
 // RegisterResponse notes that we received a response from validator [validatorID]
 func (b *benchlist) RegisterResponse(validatorID ids.ShortID) {
 	b.streaklock.Lock()
@@ -281,14 +305,14 @@ func (b *benchlist) RegisterFailure(validatorID ids.ShortID) {
 // Assumes [b.lock] is held
 // Assumes [validatorID] is not already benched
 func (b *benchlist) bench(validatorID ids.ShortID) {
-	benchedStake, err := b.vdrs.SubsetWeight(b.benchlistSet)
+	benchedStake, err := b.vdrs.SubsetWeight(ids.NodeIDSetFromShortSet(b.benchlistSet))
 	if err != nil {
 		// This should never happen
 		b.log.Error("couldn't get benched stake: %w. Resetting benchlist", err)
 		return
 	}
 
-	validatorStake, isVdr := b.vdrs.GetWeight(validatorID)
+	validatorStake, isVdr := b.vdrs.GetWeight(ids.NodeIDFromShortID(validatorID))
 	if !isVdr {
 		// We might want to bench a non-validator because they don't respond to
 		// my Get requests, but we choose to only bench validators.
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
@@ -65,11 +66,11 @@ func TestBenchlistAdd(t *testing.T) {
 
 	// Nobody should be benched at the start
 	b.lock.Lock()
-	assert.False(t, b.isBenched(vdr0.ID()))
-	assert.False(t, b.isBenched(vdr1.ID()))
-	assert.False(t, b.isBenched(vdr2.ID()))
-	assert.False(t, b.isBenched(vdr3.ID()))
-	assert.False(t, b.isBenched(vdr4.ID()))
+	assert.False(t, b.isBenched(vdr0.ID().ShortID()))
+	assert.False(t, b.isBenched(vdr1.ID().ShortID()))
+	assert.False(t, b.isBenched(vdr2.ID().ShortID()))
+	assert.False(t, b.isBenched(vdr3.ID().ShortID()))
+	assert.False(t, b.isBenched(vdr4.ID().ShortID()))
 	assert.Len(t, b.failureStreaks, 0)
 	assert.Equal(t, b.benchedQueue.Len(), 0)
 	assert.Equal(t, b.benchlistSet.Len(), 0)
@@ -77,25 +78,25 @@ func TestBenchlistAdd(t *testing.T) {
 
 	// Register [threshold - 1] failures in a row for vdr0
 	for i := 0; i < threshold-1; i++ {
-		b.RegisterFailure(vdr0.ID())
+		b.RegisterFailure(vdr0.ID().ShortID())
 	}
 
 	// Still shouldn't be benched due to not enough consecutive failure
-	assert.False(t, b.isBenched(vdr0.ID()))
+	assert.False(t, b.isBenched(vdr0.ID().ShortID()))
 	assert.Equal(t, b.benchedQueue.Len(), 0)
 	assert.Equal(t, b.benchlistSet.Len(), 0)
 	assert.Len(t, b.failureStreaks, 1)
-	fs := b.failureStreaks[vdr0.ID()]
+	fs := b.failureStreaks[vdr0.ID().ShortID()]
 	assert.Equal(t, threshold-1, fs.consecutive)
 	assert.True(t, fs.firstFailure.Equal(now))
 
 	// Register another failure
-	b.RegisterFailure(vdr0.ID())
+	b.RegisterFailure(vdr0.ID().ShortID())
 
 	// Still shouldn't be benched because not enough time (any in this case)
 	// has passed since the first failure
 	b.lock.Lock()
-	assert.False(t, b.isBenched(vdr0.ID()))
+	assert.False(t, b.isBenched(vdr0.ID().ShortID()))
 	assert.Equal(t, b.benchedQueue.Len(), 0)
 	assert.Equal(t, b.benchlistSet.Len(), 0)
 	b.lock.Unlock()
@@ -112,16 +113,16 @@ func TestBenchlistAdd(t *testing.T) {
 	b.lock.Unlock()
 
 	// Register another failure
-	b.RegisterFailure(vdr0.ID())
+	b.RegisterFailure(vdr0.ID().ShortID())
 
 	// Now this validator should be benched
 	b.lock.Lock()
-	assert.True(t, b.isBenched(vdr0.ID()))
+	assert.True(t, b.isBenched(vdr0.ID().ShortID()))
 	assert.Equal(t, b.benchedQueue.Len(), 1)
 	assert.Equal(t, b.benchlistSet.Len(), 1)
 
 	next := b.benchedQueue[0]
-	assert.Equal(t, vdr0.ID(), next.validatorID)
+	assert.Equal(t, vdr0.ID().ShortID(), next.validatorID)
 	assert.True(t, !next.benchedUntil.After(now.Add(duration)))
 	assert.True(t, !next.benchedUntil.Before(now.Add(duration/2)))
 	assert.Len(t, b.failureStreaks, 0)
@@ -131,7 +132,7 @@ func TestBenchlistAdd(t *testing.T) {
 
 	// Give another validator [threshold-1] failures
 	for i := 0; i < threshold-1; i++ {
-		b.RegisterFailure(vdr1.ID())
+		b.RegisterFailure(vdr1.ID().ShortID())
 	}
 
 	// Advance the time
@@ -140,20 +141,20 @@ func TestBenchlistAdd(t *testing.T) {
 	b.lock.Unlock()
 
 	// Register another failure
-	b.RegisterResponse(vdr1.ID())
+	b.RegisterResponse(vdr1.ID().ShortID())
 
 	// vdr1 shouldn't be benched
 	// The response should have cleared its consecutive failures
 	b.lock.Lock()
-	assert.True(t, b.isBenched(vdr0.ID()))
-	assert.False(t, b.isBenched(vdr1.ID()))
+	assert.True(t, b.isBenched(vdr0.ID().ShortID()))
+	assert.False(t, b.isBenched(vdr1.ID().ShortID()))
 	assert.Equal(t, b.benchedQueue.Len(), 1)
 	assert.Equal(t, b.benchlistSet.Len(), 1)
 	assert.Len(t, b.failureStreaks, 0)
 	b.lock.Unlock()
 
 	// Register another failure for vdr0, who is benched
-	b.RegisterFailure(vdr0.ID())
+	b.RegisterFailure(vdr0.ID().ShortID())
 
 	// A failure for an already benched validator should not count against it
 	b.lock.Lock()
@@ -210,7 +211,7 @@ func TestBenchlistMaxStake(t *testing.T) {
 	// Register [threshold-1] failures for 3 validators
 	for _, vdr := range []validators.Validator{vdr0, vdr1, vdr2} {
 		for i := 0; i < threshold-1; i++ {
-			b.RegisterFailure(vdr.ID())
+			b.RegisterFailure(vdr.ID().ShortID())
 		}
 	}
 
@@ -222,27 +223,27 @@ func TestBenchlistMaxStake(t *testing.T) {
 
 	// Register another failure for all three
 	for _, vdr := range []validators.Validator{vdr0, vdr1, vdr2} {
-		b.RegisterFailure(vdr.ID())
+		b.RegisterFailure(vdr.ID().ShortID())
 	}
 
 	// Only vdr0 and vdr1 should be benched (total weight 2000)
 	// Benching vdr2 (weight 1000) would cause the amount benched
 	// to exceed the maximum
 	b.lock.Lock()
-	assert.True(t, b.isBenched(vdr0.ID()))
-	assert.True(t, b.isBenched(vdr1.ID()))
-	assert.False(t, b.isBenched(vdr2.ID()))
+	assert.True(t, b.isBenched(vdr0.ID().ShortID()))
+	assert.True(t, b.isBenched(vdr1.ID().ShortID()))
+	assert.False(t, b.isBenched(vdr2.ID().ShortID()))
 	assert.Equal(t, b.benchedQueue.Len(), 2)
 	assert.Equal(t, b.benchlistSet.Len(), 2)
 	assert.Len(t, b.failureStreaks, 1)
-	fs := b.failureStreaks[vdr2.ID()]
+	fs := b.failureStreaks[vdr2.ID().ShortID()]
 	fs.consecutive = threshold
 	fs.firstFailure = now
 	b.lock.Unlock()
 
 	// Register threshold - 1 failures for vdr4
 	for i := 0; i < threshold-1; i++ {
-		b.RegisterFailure(vdr4.ID())
+		b.RegisterFailure(vdr4.ID().ShortID())
 	}
 
 	// Advance the time past min failing duration
@@ -252,40 +253,40 @@ func TestBenchlistMaxStake(t *testing.T) {
 	b.lock.Unlock()
 
 	// Register another failure for vdr4
-	b.RegisterFailure(vdr4.ID())
+	b.RegisterFailure(vdr4.ID().ShortID())
 
 	// vdr4 should be benched now
 	b.lock.Lock()
-	assert.True(t, b.isBenched(vdr0.ID()))
-	assert.True(t, b.isBenched(vdr1.ID()))
-	assert.True(t, b.isBenched(vdr4.ID()))
+	assert.True(t, b.isBenched(vdr0.ID().ShortID()))
+	assert.True(t, b.isBenched(vdr1.ID().ShortID()))
+	assert.True(t, b.isBenched(vdr4.ID().ShortID()))
 	assert.Equal(t, 3, b.benchedQueue.Len())
 	assert.Equal(t, 3, b.benchlistSet.Len())
-	assert.Contains(t, b.benchlistSet, vdr0.ID())
-	assert.Contains(t, b.benchlistSet, vdr1.ID())
-	assert.Contains(t, b.benchlistSet, vdr4.ID())
+	assert.Contains(t, b.benchlistSet, vdr0.ID().ShortID())
+	assert.Contains(t, b.benchlistSet, vdr1.ID().ShortID())
+	assert.Contains(t, b.benchlistSet, vdr4.ID().ShortID())
 	assert.Len(t, b.failureStreaks, 1) // for vdr2
 	b.lock.Unlock()
 
 	// More failures for vdr2 shouldn't add it to the bench
 	// because the max bench amount would be exceeded
 	for i := 0; i < threshold-1; i++ {
-		b.RegisterFailure(vdr2.ID())
+		b.RegisterFailure(vdr2.ID().ShortID())
 	}
 
 	b.lock.Lock()
-	assert.True(t, b.isBenched(vdr0.ID()))
-	assert.True(t, b.isBenched(vdr1.ID()))
-	assert.True(t, b.isBenched(vdr4.ID()))
-	assert.False(t, b.isBenched(vdr2.ID()))
+	assert.True(t, b.isBenched(vdr0.ID().ShortID()))
+	assert.True(t, b.isBenched(vdr1.ID().ShortID()))
+	assert.True(t, b.isBenched(vdr4.ID().ShortID()))
+	assert.False(t, b.isBenched(vdr2.ID().ShortID()))
 	assert.Equal(t, 3, b.benchedQueue.Len())
 	assert.Equal(t, 3, b.benchlistSet.Len())
 	assert.Len(t, b.failureStreaks, 1)
-	assert.Contains(t, b.failureStreaks, vdr2.ID())
+	assert.Contains(t, b.failureStreaks, vdr2.ID().ShortID())
 
 	// Ensure the benched queue root has the min end time
 	minEndTime := b.benchedQueue[0].benchedUntil
-	benchedIDs := []ids.ShortID{vdr0.ID(), vdr1.ID(), vdr4.ID()}
+	benchedIDs := []ids.ShortID{vdr0.ID().ShortID(), vdr1.ID().ShortID(), vdr4.ID().ShortID()}
 	for _, benchedVdr := range b.benchedQueue {
 		assert.Contains(t, benchedIDs, benchedVdr.validatorID)
 		assert.True(t, !benchedVdr.benchedUntil.Before(minEndTime))
@@ -353,7 +354,7 @@ func TestBenchlistRemove(t *testing.T) {
 	// Register [threshold-1] failures for 3 validators
 	for _, vdr := range []validators.Validator{vdr0, vdr1, vdr2} {
 		for i := 0; i < threshold-1; i++ {
-			b.RegisterFailure(vdr.ID())
+			b.RegisterFailure(vdr.ID().ShortID())
 		}
 	}
 
@@ -364,21 +365,21 @@ func TestBenchlistRemove(t *testing.T) {
 	b.clock.Set(now)
 	b.lock.Unlock()
 	for _, vdr := range []validators.Validator{vdr0, vdr1, vdr2} {
-		b.RegisterFailure(vdr.ID())
+		b.RegisterFailure(vdr.ID().ShortID())
 	}
 
 	// All 3 should be benched
 	b.lock.Lock()
-	assert.True(t, b.isBenched(vdr0.ID()))
-	assert.True(t, b.isBenched(vdr1.ID()))
-	assert.True(t, b.isBenched(vdr2.ID()))
+	assert.True(t, b.isBenched(vdr0.ID().ShortID()))
+	assert.True(t, b.isBenched(vdr1.ID().ShortID()))
+	assert.True(t, b.isBenched(vdr2.ID().ShortID()))
 	assert.Equal(t, 3, b.benchedQueue.Len())
 	assert.Equal(t, 3, b.benchlistSet.Len())
 	assert.Len(t, b.failureStreaks, 0)
 
 	// Ensure the benched queue root has the min end time
 	minEndTime := b.benchedQueue[0].benchedUntil
-	benchedIDs := []ids.ShortID{vdr0.ID(), vdr1.ID(), vdr2.ID()}
+	benchedIDs := []ids.ShortID{vdr0.ID().ShortID(), vdr1.ID().ShortID(), vdr2.ID().ShortID()}
 	for _, benchedVdr := range b.benchedQueue {
 		assert.Contains(t, benchedIDs, benchedVdr.validatorID)
 		assert.True(t, !benchedVdr.benchedUntil.Before(minEndTime))
@@ -393,7 +394,7 @@ func TestBenchlistRemove(t *testing.T) {
 	assert.Eventually(
 		t,
 		func() bool {
-			return !b.IsBenched(vdr0.ID())
+			return !b.IsBenched(vdr0.ID().ShortID())
 		},
 		duration+time.Second, // extra time.Second as grace period
 		100*time.Millisecond,
@@ -402,7 +403,7 @@ func TestBenchlistRemove(t *testing.T) {
 	assert.Eventually(
 		t,
 		func() bool {
-			return !b.IsBenched(vdr1.ID())
+			return !b.IsBenched(vdr1.ID().ShortID())
 		},
 		duration+time.Second,
 		100*time.Millisecond,
@@ -411,7 +412,7 @@ func TestBenchlistRemove(t *testing.T) {
 	assert.Eventually(
 		t,
 		func() bool {
-			return !b.IsBenched(vdr2.ID())
+			return !b.IsBenched(vdr2.ID().ShortID())
 		},
 		duration+time.Second,
 		100*time.Millisecond,
@@ -419,3 +420,67 @@ func TestBenchlistRemove(t *testing.T) {
 
 	assert.Equal(t, 3, count)
 }
+
+// Test that validators that leave the subnet are removed from the bench
+func TestManagerUnbenchesRemovedValidators(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	vdrs := validators.NewManager()
+	vdr0 := validators.GenerateRandomValidator(50)
+	vdr1 := validators.GenerateRandomValidator(50)
+	vdr2 := validators.GenerateRandomValidator(50)
+	errs := wrappers.Errs{}
+	errs.Add(
+		vdrs.AddWeight(ctx.SubnetID, vdr0.ID(), vdr0.Weight()),
+		vdrs.AddWeight(ctx.SubnetID, vdr1.ID(), vdr1.Weight()),
+		vdrs.AddWeight(ctx.SubnetID, vdr2.ID(), vdr2.Weight()),
+	)
+	if errs.Errored() {
+		t.Fatal(errs.Err)
+	}
+
+	benched := ids.ShortSet{}
+	benchable := &TestBenchable{
+		T: t,
+		BenchedF: func(_ ids.ID, validatorID ids.ShortID) {
+			benched.Add(validatorID)
+		},
+		UnbenchedF: func(_ ids.ID, validatorID ids.ShortID) {
+			benched.Remove(validatorID)
+		},
+	}
+	mIntf := NewManager(&Config{
+		Benchable:              benchable,
+		Validators:             vdrs,
+		Threshold:              1,
+		MinimumFailingDuration: time.Second,
+		Duration:               time.Minute,
+		MaxPortion:             0.5,
+	})
+	if err := mIntf.RegisterChain(ctx, ""); err != nil {
+		t.Fatal(err)
+	}
+	m := mIntf.(*manager)
+	b := m.chainBenchlists[ctx.ChainID].(*benchlist)
+	defer b.timer.Stop()
+
+	now := time.Now()
+	b.clock.Set(now)
+	m.RegisterFailure(ctx.ChainID, vdr0.ID().ShortID())
+	b.clock.Set(now.Add(2 * time.Second))
+	m.RegisterFailure(ctx.ChainID, vdr0.ID().ShortID())
+	assert.True(t, m.IsBenched(vdr0.ID().ShortID(), ctx.ChainID))
+	assert.True(t, benched.Contains(vdr0.ID().ShortID()))
+
+	// Leaving another subnet doesn't unbench the validator
+	assert.NoError(t, vdrs.AddWeight(ids.GenerateTestID(), vdr0.ID(), 1))
+	assert.NoError(t, vdrs.RemoveWeight(ids.GenerateTestID(), vdr0.ID(), 1))
+	assert.True(t, m.IsBenched(vdr0.ID().ShortID(), ctx.ChainID))
+
+	assert.NoError(t, vdrs.RemoveWeight(ctx.SubnetID, vdr0.ID(), vdr0.Weight()))
+	assert.False(t, m.IsBenched(vdr0.ID().ShortID(), ctx.ChainID))
+	assert.False(t, benched.Contains(vdr0.ID().ShortID()))
+	b.lock.Lock()
+	assert.Equal(t, 0, b.benchedQueue.Len())
+	assert.Len(t, b.failureStreaks, 0)
+	b.lock.Unlock()
+}
@@ -10,7 +10,11 @@ import (
 	"github.com/ava-labs/avalanchego/snow/validators"
 )
 
-var errUnknownValidators = errors.New("unknown validator set for provided chain")
+var (
+	errUnknownValidators = errors.New("unknown validator set for provided chain")
+
+	_ validators.Subscriber = &manager{}
+)
 
 // Manager provides an interface for a benchlist to register whether
 // queries have been successful or unsuccessful and place validators with
@@ -44,6 +48,13 @@ type Config struct {
 	Duration               time.Duration
 	MaxPortion             float64
 	PeerSummaryEnabled     bool
+	// The maximum fraction of a validator's weight it isn't sampled with
+	// because it doesn't respond to queries. Must be in [0, 1). If 0,
+	// validators aren't penalized.
+	MaxSamplingPenalty float64
+	// Validators that respond to less than this fraction of queries are
+	// penalized
+	SamplingPenaltyThreshold float64
 }
 
 type manager struct {
@@ -51,6 +62,8 @@ type manager struct {
 	// Chain ID --> benchlist for that chain.
 	// Each benchlist is safe for concurrent access.
 	chainBenchlists map[ids.ID]Benchlist
+	// Chain ID --> subnet that validates the chain
+	chainSubnets map[ids.ID]ids.ID
 
 	lock sync.RWMutex
 }
@@ -58,14 +71,25 @@ type manager struct {
 // NewManager returns a manager for chain-specific query benchlisting
 func NewManager(config *Config) Manager {
 	// If the maximum portion of validators allowed to be benchlisted
-	// is 0, return the no-op benchlist
+	// is 0, use the no-op benchlist
+	var mgr Manager
 	if config.MaxPortion <= 0 {
-		return NewNoBenchlist()
+		mgr = NewNoBenchlist()
+	} else {
+		m := &manager{
+			config:          config,
+			chainBenchlists: make(map[ids.ID]Benchlist),
+			chainSubnets:    make(map[ids.ID]ids.ID),
+		}
+		if config.Validators != nil {
+			config.Validators.Subscribe(m)
+		}
+		mgr = m
 	}
-	return &manager{
-		config:          config,
-		chainBenchlists: make(map[ids.ID]Benchlist),
+	if config.MaxSamplingPenalty <= 0 {
+		return mgr
 	}
+	return newPenalizingManager(mgr, config.Validators, config.MaxSamplingPenalty, config.SamplingPenaltyThreshold)
 }
 
 // IsBenched returns true if messages to [validatorID] regarding [chainID]
@@ -129,9 +153,30 @@ func (m *manager) RegisterChain(ctx *snow.Context, namespace string) error {
 	}
 
 	m.chainBenchlists[ctx.ChainID] = benchlist
+	m.chainSubnets[ctx.ChainID] = ctx.SubnetID
 	return nil
 }
 
+// OnValidatorAdded implements the validators.Subscriber interface
+func (m *manager) OnValidatorAdded(ids.ID, ids.NodeID, uint64) {}
+
+// OnValidatorRemoved implements the validators.Subscriber interface. A node
+// that stops validating a subnet is no longer benched on the subnet's chains,
+// so that it doesn't count towards the benched stake.
+func (m *manager) OnValidatorRemoved(subnetID ids.ID, validatorID ids.NodeID, _ uint64) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	for chainID, benchlist := range m.chainBenchlists {
+		if m.chainSubnets[chainID] == subnetID {
+			benchlist.Unbench(validatorID.ShortID())
+		}
+	}
+}
+
+// OnValidatorWeightChanged implements the validators.Subscriber interface
+func (m *manager) OnValidatorWeightChanged(ids.ID, ids.NodeID, uint64, uint64) {}
+
 // RegisterResponse implements the Manager interface
 func (m *manager) RegisterResponse(chainID ids.ID, validatorID ids.ShortID) {
 	m.lock.RLock()
@@ -0,0 +1,152 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package benchlist
+
+import (
+	"math"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+const (
+	// Weight of the most recent query when updating a validator's response
+	// rate
+	responseRateDecay = .05
+
+	// Sampling penalties are rounded down to a multiple of this, so that the
+	// validator sets aren't updated after every query
+	penaltyGranularity = .05
+)
+
+// Validators that don't respond to queries waste the poll slots they are
+// sampled into, even if they aren't unresponsive enough to be benched.
+// Therefore, the weight that validators with a low response rate are sampled
+// with is reduced. The penalty only affects which validators are sampled, not
+// the thresholds that polls are evaluated against.
+
+var _ validators.Subscriber = &penalizingManager{}
+
+// penalizingManager wraps a Manager and tracks the fraction of queries each
+// validator responds to across all chains. A validator is forgotten once it
+// stops validating the primary network, and therefore every subnet.
+type penalizingManager struct {
+	Manager
+
+	lock sync.Mutex
+	vdrs validators.Manager
+
+	// Validators whose response rate is below [threshold] are penalized, up
+	// to [maxPenalty] for a validator that never responds
+	maxPenalty float64
+	threshold  float64
+
+	// Validator ID --> Exponential moving average of the fraction of queries
+	// that the validator responded to. Missing validators have a rate of 1.
+	responseRates map[ids.ShortID]float64
+
+	// Validator ID --> Current sampling penalty of the validator
+	penalties map[ids.ShortID]float64
+}
+
+func newPenalizingManager(
+	mgr Manager,
+	vdrs validators.Manager,
+	maxPenalty,
+	threshold float64,
+) Manager {
+	m := &penalizingManager{
+		Manager:       mgr,
+		vdrs:          vdrs,
+		maxPenalty:    maxPenalty,
+		threshold:     threshold,
+		responseRates: make(map[ids.ShortID]float64),
+		penalties:     make(map[ids.ShortID]float64),
+	}
+	vdrs.Subscribe(m)
+	return m
+}
+
+// RegisterResponse implements the Manager interface
+func (m *penalizingManager) RegisterResponse(chainID ids.ID, validatorID ids.ShortID) {
+	m.Manager.RegisterResponse(chainID, validatorID)
+	m.observe(validatorID, 1)
+}
+
+// RegisterFailure implements the Manager interface
+func (m *penalizingManager) RegisterFailure(chainID ids.ID, validatorID ids.ShortID) {
+	m.Manager.RegisterFailure(chainID, validatorID)
+	m.observe(validatorID, 0)
+}
+
+// observe updates the response rate of [validatorID] with a query that it
+// either responded to, if [response] is 1, or didn't respond to, if
+// [response] is 0
+func (m *penalizingManager) observe(validatorID ids.ShortID, response float64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	rate, ok := m.responseRates[validatorID]
+	if !ok {
+		rate = 1
+	}
+	rate = rate*(1-responseRateDecay) + response*responseRateDecay
+	if rate >= 1-responseRateDecay*responseRateDecay {
+		// The validator is responsive, stop tracking it
+		delete(m.responseRates, validatorID)
+	} else {
+		m.responseRates[validatorID] = rate
+	}
+
+	penalty := m.penalty(rate)
+	if penalty == m.penalties[validatorID] {
+		return
+	}
+	if penalty == 0 {
+		delete(m.penalties, validatorID)
+	} else {
+		m.penalties[validatorID] = penalty
+	}
+	// [penalty] is in [0, maxPenalty], which is in [0, 1), so this can't fail
+	_ = m.vdrs.SetSamplingPenalty(ids.NodeIDFromShortID(validatorID), penalty)
+}
+
+// OnValidatorAdded implements the validators.Subscriber interface
+func (m *penalizingManager) OnValidatorAdded(ids.ID, ids.NodeID, uint64) {}
+
+// OnValidatorRemoved implements the validators.Subscriber interface. A node
+// that stops validating the primary network is forgotten, so that the response
+// rates and penalties of nodes that left don't accumulate.
+func (m *penalizingManager) OnValidatorRemoved(subnetID ids.ID, validatorID ids.NodeID, _ uint64) {
+	if subnetID != constants.PrimaryNetworkID {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	shortID := validatorID.ShortID()
+	delete(m.responseRates, shortID)
+	if _, ok := m.penalties[shortID]; !ok {
+		return
+	}
+	delete(m.penalties, shortID)
+	// Removing a penalty can't fail
+	_ = m.vdrs.SetSamplingPenalty(validatorID, 0)
+}
+
+// OnValidatorWeightChanged implements the validators.Subscriber interface
+func (m *penalizingManager) OnValidatorWeightChanged(ids.ID, ids.NodeID, uint64, uint64) {}
+
+// penalty returns the sampling penalty of a validator with response rate
+// [rate]
+func (m *penalizingManager) penalty(rate float64) float64 {
+	if rate >= m.threshold {
+		return 0
+	}
+	penalty := m.maxPenalty * (m.threshold - rate) / m.threshold
+	return math.Floor(penalty/penaltyGranularity) * penaltyGranularity
+}
@@ -0,0 +1,95 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package benchlist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func TestPenalizingManager(t *testing.T) {
+	chainID := ids.GenerateTestID()
+	subnetID := ids.GenerateTestID()
+	vdr0 := ids.GenerateTestShortID()
+	vdr1 := ids.GenerateTestShortID()
+
+	vdrMgr := validators.NewManager()
+	assert.NoError(t, vdrMgr.AddWeight(subnetID, ids.NodeIDFromShortID(vdr0), 10))
+	assert.NoError(t, vdrMgr.AddWeight(subnetID, ids.NodeIDFromShortID(vdr1), 10))
+	vdrs, _ := vdrMgr.GetValidators(subnetID)
+
+	mgr := newPenalizingManager(NewNoBenchlist(), vdrMgr, .5, .8).(*penalizingManager)
+
+	// Occasional failures aren't penalized
+	for i := 0; i < 100; i++ {
+		mgr.RegisterResponse(chainID, vdr0)
+		if i%10 == 0 {
+			mgr.RegisterFailure(chainID, vdr0)
+		}
+	}
+	assert.Empty(t, mgr.penalties)
+
+	// Chronic failures are penalized, up to the maximum penalty
+	for i := 0; i < 100; i++ {
+		mgr.RegisterFailure(chainID, vdr0)
+	}
+	penalty := mgr.penalties[vdr0]
+	assert.Greater(t, penalty, .4)
+	assert.LessOrEqual(t, penalty, .5)
+
+	// vdr0 is sampled with a weight of 5
+	_, err := vdrs.Sample(15)
+	assert.NoError(t, err)
+	_, err = vdrs.Sample(16)
+	assert.Error(t, err)
+	// The weight used for consensus isn't changed
+	assert.Equal(t, uint64(20), vdrs.Weight())
+
+	// The penalty is removed as the validator recovers
+	for i := 0; i < 200; i++ {
+		mgr.RegisterResponse(chainID, vdr0)
+	}
+	assert.Empty(t, mgr.penalties)
+	assert.Empty(t, mgr.responseRates)
+	_, err = vdrs.Sample(20)
+	assert.NoError(t, err)
+}
+
+func TestPenalizingManagerForgetsRemovedValidators(t *testing.T) {
+	chainID := ids.GenerateTestID()
+	subnetID := ids.GenerateTestID()
+	vdr := ids.GenerateTestShortID()
+	nodeID := ids.NodeIDFromShortID(vdr)
+
+	vdrMgr := validators.NewManager()
+	assert.NoError(t, vdrMgr.AddWeight(constants.PrimaryNetworkID, nodeID, 10))
+	assert.NoError(t, vdrMgr.AddWeight(subnetID, nodeID, 10))
+
+	mgr := newPenalizingManager(NewNoBenchlist(), vdrMgr, .5, .8).(*penalizingManager)
+	for i := 0; i < 100; i++ {
+		mgr.RegisterFailure(chainID, vdr)
+	}
+	assert.Contains(t, mgr.penalties, vdr)
+	assert.Contains(t, mgr.responseRates, vdr)
+
+	// The validator still validates the primary network
+	assert.NoError(t, vdrMgr.RemoveWeight(subnetID, nodeID, 10))
+	assert.Contains(t, mgr.penalties, vdr)
+	assert.Contains(t, mgr.responseRates, vdr)
+
+	assert.NoError(t, vdrMgr.RemoveWeight(constants.PrimaryNetworkID, nodeID, 10))
+	assert.Empty(t, mgr.penalties)
+	assert.Empty(t, mgr.responseRates)
+
+	// If the node validates again, it isn't penalized
+	assert.NoError(t, vdrMgr.AddWeight(subnetID, nodeID, 10))
+	vdrs, _ := vdrMgr.GetValidators(subnetID)
+	_, err := vdrs.Sample(10)
+	assert.NoError(t, err)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/hashing"
@@ -46,6 +47,7 @@ type requestEntry struct {
 // that they are working on.
 type ChainRouter struct {
 	clock  timer.Clock
+	nodeID ids.ShortID
 	log    logging.Logger
 	lock   sync.Mutex
 	chains map[ids.ID]*Handler
@@ -77,6 +79,13 @@ type ChainRouter struct {
 	// Should only be accessed in that method.
 	// [lock] should be held when [requestIDBytes] is accessed.
 	requestIDBytes []byte
+	// Used to relay cross-subnet messages. May be nil, in which case
+	// cross-subnet messages are only delivered to local chains.
+	crossSubnetSender CrossSubnetSender
+	// The BLS keys that the origins of cross-subnet messages sign them with.
+	// May be nil, in which case only cross-subnet messages from local chains
+	// are accepted.
+	crossSubnetKeys validators.BLSKeys
 }
 
 // Initialize the router.
@@ -99,6 +108,7 @@ func (cr *ChainRouter) Initialize(
 	metricsNamespace string,
 	metricsRegisterer prometheus.Registerer,
 ) error {
+	cr.nodeID = nodeID
 	cr.log = log
 	cr.chains = make(map[ids.ID]*Handler)
 	cr.timeoutManager = timeoutManager
@@ -171,6 +181,10 @@ func (cr *ChainRouter) RegisterRequest(
 		timeoutHandler = func() { cr.GetAcceptedFailed(validatorID, chainID, requestID) }
 	case constants.GetAcceptedFrontierMsg:
 		timeoutHandler = func() { cr.GetAcceptedFrontierFailed(validatorID, chainID, requestID) }
+	case constants.GetStateSummaryFrontierMsg:
+		timeoutHandler = func() { cr.GetStateSummaryFrontierFailed(validatorID, chainID, requestID) }
+	case constants.GetAcceptedStateSummaryMsg:
+		timeoutHandler = func() { cr.GetAcceptedStateSummaryFailed(validatorID, chainID, requestID) }
 	default:
 		// This should never happen
 		cr.log.Error("expected message type to be one of GetMsg, PullQueryMsg, PushQueryMsg, GetAcceptedFrontierMsg, GetAcceptedMsg but got %s", msgType)
@@ -256,6 +270,122 @@ func (cr *ChainRouter) RemoveChain(chainID ids.ID) {
 	}
 }
 
+// SetCrossSubnetSender sets the sender used to relay cross-subnet messages to
+// other nodes, and the BLS keys that the origins of cross-subnet messages are
+// authenticated with.
+func (cr *ChainRouter) SetCrossSubnetSender(sender CrossSubnetSender, blsKeys validators.BLSKeys) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	cr.crossSubnetSender = sender
+	cr.crossSubnetKeys = blsKeys
+}
+
+// CrossSubnet routes an incoming message from the chain [sourceChainID],
+// created and signed by [originID] and received from [validatorID], to the
+// chain [destinationChainID].
+//
+// A message received directly from its origin is only accepted if this node
+// runs the source chain and the origin validates the source chain's subnet. If
+// this node also validates the destination chain's subnet, the message is then
+// relayed to the destination subnet's other validators. A relayed message is
+// only accepted if the relayer validates the destination chain's subnet, and is
+// never relayed again. Either way, [signature] must be the origin's BLS
+// signature of the message.
+//
+// Local chains send cross-subnet messages with NewCrossSubnetOriginator.
+func (cr *ChainRouter) CrossSubnet(validatorID ids.ShortID, sourceChainID, destinationChainID ids.ID, originID ids.ShortID, msg, signature []byte) {
+	cr.lock.Lock()
+	_, ok := cr.routeCrossSubnet(validatorID, sourceChainID, destinationChainID, originID)
+	blsKeys := cr.crossSubnetKeys
+	cr.lock.Unlock()
+	if !ok {
+		return
+	}
+
+	// Messages that this node originated were signed by the originator.
+	// Verifying the signature is expensive, so it's done without holding
+	// [cr.lock].
+	if originID != cr.nodeID || validatorID != cr.nodeID {
+		if err := verifyCrossSubnet(blsKeys, sourceChainID, destinationChainID, originID, msg, signature); err != nil {
+			cr.log.Debug("CrossSubnet(%s, %s, %s, %s) dropped: %s", validatorID, sourceChainID, destinationChainID, originID, err)
+			return
+		}
+	}
+
+	cr.lock.Lock()
+	// The chains may have changed while [cr.lock] wasn't held
+	destination, ok := cr.routeCrossSubnet(validatorID, sourceChainID, destinationChainID, originID)
+	if !ok {
+		cr.lock.Unlock()
+		return
+	}
+	cr.deliverCrossSubnet(destination, validatorID, sourceChainID, originID, msg)
+
+	sender := cr.crossSubnetSender
+	if validatorID != originID || sender == nil || !destination.validators.Contains(ids.NodeIDFromShortID(cr.nodeID)) {
+		cr.lock.Unlock()
+		return
+	}
+	relayTo := ids.ShortSet{}
+	for _, vdr := range destination.validators.List() {
+		relayTo.Add(vdr.ID().ShortID())
+	}
+	relayTo.Remove(cr.nodeID, originID)
+	cr.lock.Unlock()
+
+	// Sending may block on the network, so it's done without holding
+	// [cr.lock]
+	sender.CrossSubnet(relayTo, sourceChainID, destinationChainID, originID, msg, signature)
+}
+
+// routeCrossSubnet returns the chain that a cross-subnet message should be
+// delivered to, and false if the message should be dropped.
+// Assumes [cr.lock] is held
+func (cr *ChainRouter) routeCrossSubnet(validatorID ids.ShortID, sourceChainID, destinationChainID ids.ID, originID ids.ShortID) (*Handler, bool) {
+	destination, exists := cr.chains[destinationChainID]
+	if !exists {
+		cr.log.Debug("CrossSubnet(%s, %s, %s, %s) dropped due to unknown destination chain", validatorID, sourceChainID, destinationChainID, originID)
+		return nil, false
+	}
+
+	if validatorID != originID {
+		// The relayer vouches for the origin, so it must be trusted by the
+		// destination subnet.
+		if !destination.validators.Contains(ids.NodeIDFromShortID(validatorID)) {
+			cr.log.Debug("CrossSubnet(%s, %s, %s, %s) dropped because the relayer doesn't validate the destination subnet", validatorID, sourceChainID, destinationChainID, originID)
+			return nil, false
+		}
+		return destination, true
+	}
+
+	source, exists := cr.chains[sourceChainID]
+	if !exists {
+		cr.log.Debug("CrossSubnet(%s, %s, %s, %s) dropped due to unknown source chain", validatorID, sourceChainID, destinationChainID, originID)
+		return nil, false
+	}
+	if source.ctx.SubnetID == destination.ctx.SubnetID {
+		cr.log.Debug("CrossSubnet(%s, %s, %s, %s) dropped because both chains are in subnet %s", validatorID, sourceChainID, destinationChainID, originID, source.ctx.SubnetID)
+		return nil, false
+	}
+	if !source.validators.Contains(ids.NodeIDFromShortID(originID)) {
+		cr.log.Debug("CrossSubnet(%s, %s, %s, %s) dropped because the origin doesn't validate the source subnet", validatorID, sourceChainID, destinationChainID, originID)
+		return nil, false
+	}
+	return destination, true
+}
+
+// Assumes [cr.lock] is held
+func (cr *ChainRouter) deliverCrossSubnet(chain *Handler, validatorID ids.ShortID, sourceChainID ids.ID, originID ids.ShortID, msg []byte) {
+	// It's OK if we drop this.
+	dropped := !chain.CrossSubnet(validatorID, sourceChainID, originID, msg)
+	if dropped {
+		cr.registerMsgDrop(chain.ctx.IsBootstrapped())
+	} else {
+		cr.registerMsgSuccess(chain.ctx.IsBootstrapped())
+	}
+}
+
 // GetAcceptedFrontier routes an incoming GetAcceptedFrontier request from the
 // validator with ID [validatorID]  to the consensus engine working on the
 // chain with ID [chainID]
@@ -293,6 +423,18 @@ func (cr *ChainRouter) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID,
 		return
 	}
 
+	// If this is a pushed frontier, pass to the chain
+	if requestID == constants.GossipMsgRequestID {
+		// It's ok to drop this message.
+		dropped := !chain.AcceptedFrontier(validatorID, requestID, containerIDs)
+		if dropped {
+			cr.registerMsgDrop(chain.ctx.IsBootstrapped())
+		} else {
+			cr.registerMsgSuccess(chain.ctx.IsBootstrapped())
+		}
+		return
+	}
+
 	uniqueRequestID := cr.createRequestID(validatorID, chainID, requestID)
 
 	// Mark that an outstanding request has been fulfilled
@@ -441,6 +583,190 @@ func (cr *ChainRouter) GetAcceptedFailed(validatorID ids.ShortID, chainID ids.ID
 	chain.GetAcceptedFailed(validatorID, requestID)
 }
 
+// GetStateSummaryFrontier routes an incoming GetStateSummaryFrontier request
+// from the validator with ID [validatorID] to the consensus engine working on
+// the chain with ID [chainID]
+func (cr *ChainRouter) GetStateSummaryFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	chain, exists := cr.chains[chainID]
+	if !exists {
+		cr.log.Debug("GetStateSummaryFrontier(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+		return
+	}
+
+	// Pass the message to the chain. It's OK if we drop this.
+	dropped := !chain.GetStateSummaryFrontier(validatorID, requestID, deadline)
+	if dropped {
+		cr.registerMsgDrop(chain.ctx.IsBootstrapped())
+	} else {
+		cr.registerMsgSuccess(chain.ctx.IsBootstrapped())
+	}
+}
+
+// StateSummaryFrontier routes an incoming StateSummaryFrontier request from
+// the validator with ID [validatorID] to the consensus engine working on the
+// chain with ID [chainID]
+func (cr *ChainRouter) StateSummaryFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	// Get the chain, if it exists
+	chain, exists := cr.chains[chainID]
+	if !exists {
+		cr.log.Debug("StateSummaryFrontier(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+		return
+	}
+
+	uniqueRequestID := cr.createRequestID(validatorID, chainID, requestID)
+
+	// Mark that an outstanding request has been fulfilled
+	requestIntf, exists := cr.timedRequests.Get(uniqueRequestID)
+	if !exists {
+		// We didn't request this message. Ignore.
+		return
+	}
+	request := requestIntf.(requestEntry)
+	if request.msgType != constants.GetStateSummaryFrontierMsg {
+		// We got back a reply of wrong type. Ignore.
+		return
+	}
+	cr.timedRequests.Delete(uniqueRequestID)
+
+	// Calculate how long it took [validatorID] to reply
+	latency := cr.clock.Time().Sub(request.time)
+
+	// Tell the timeout manager we got a response
+	cr.timeoutManager.RegisterResponse(validatorID, chainID, uniqueRequestID, constants.GetStateSummaryFrontierMsg, latency)
+
+	// Pass the response to the chain
+	dropped := !chain.StateSummaryFrontier(validatorID, requestID, summary)
+	if dropped {
+		// We weren't able to pass the response to the chain
+		chain.GetStateSummaryFrontierFailed(validatorID, requestID)
+		cr.registerMsgDrop(chain.ctx.IsBootstrapped())
+	} else {
+		cr.registerMsgSuccess(chain.ctx.IsBootstrapped())
+	}
+}
+
+// GetStateSummaryFrontierFailed routes an incoming
+// GetStateSummaryFrontierFailed request from the validator with ID
+// [validatorID] to the consensus engine working on the chain with ID [chainID]
+func (cr *ChainRouter) GetStateSummaryFrontierFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	uniqueRequestID := cr.createRequestID(validatorID, chainID, requestID)
+
+	// Remove the outstanding request
+	cr.removeRequest(uniqueRequestID)
+
+	// Get the chain, if it exists
+	chain, exists := cr.chains[chainID]
+	if !exists {
+		// Should only happen when shutting down
+		cr.log.Debug("GetStateSummaryFrontierFailed(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+		return
+	}
+
+	// Pass the response to the chain
+	chain.GetStateSummaryFrontierFailed(validatorID, requestID)
+}
+
+// GetAcceptedStateSummary routes an incoming GetAcceptedStateSummary request
+// from the validator with ID [validatorID] to the consensus engine working on
+// the chain with ID [chainID]
+func (cr *ChainRouter) GetAcceptedStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, heights []uint64) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	chain, exists := cr.chains[chainID]
+	if !exists {
+		cr.log.Debug("GetAcceptedStateSummary(%s, %s, %d, %v) dropped due to unknown chain", validatorID, chainID, requestID, heights)
+		return
+	}
+
+	// Pass the message to the chain. It's OK if we drop this.
+	dropped := !chain.GetAcceptedStateSummary(validatorID, requestID, deadline, heights)
+	if dropped {
+		cr.registerMsgDrop(chain.ctx.IsBootstrapped())
+	} else {
+		cr.registerMsgSuccess(chain.ctx.IsBootstrapped())
+	}
+}
+
+// AcceptedStateSummary routes an incoming AcceptedStateSummary request from
+// the validator with ID [validatorID] to the consensus engine working on the
+// chain with ID [chainID]
+func (cr *ChainRouter) AcceptedStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summaryIDs []ids.ID) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	// Get the chain, if it exists
+	chain, exists := cr.chains[chainID]
+	if !exists {
+		cr.log.Debug("AcceptedStateSummary(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID, summaryIDs)
+		return
+	}
+
+	uniqueRequestID := cr.createRequestID(validatorID, chainID, requestID)
+
+	// Mark that an outstanding request has been fulfilled
+	requestIntf, exists := cr.timedRequests.Get(uniqueRequestID)
+	if !exists {
+		// We didn't request this message. Ignore.
+		return
+	}
+	request := requestIntf.(requestEntry)
+	if request.msgType != constants.GetAcceptedStateSummaryMsg {
+		// We got back a reply of wrong type. Ignore.
+		return
+	}
+	cr.timedRequests.Delete(uniqueRequestID)
+
+	// Calculate how long it took [validatorID] to reply
+	latency := cr.clock.Time().Sub(request.time)
+
+	// Tell the timeout manager we got a response
+	cr.timeoutManager.RegisterResponse(validatorID, chainID, uniqueRequestID, constants.GetAcceptedStateSummaryMsg, latency)
+
+	// Pass the response to the chain
+	dropped := !chain.AcceptedStateSummary(validatorID, requestID, summaryIDs)
+	if dropped {
+		// We weren't able to pass the response to the chain
+		chain.GetAcceptedStateSummaryFailed(validatorID, requestID)
+		cr.registerMsgDrop(chain.ctx.IsBootstrapped())
+	} else {
+		cr.registerMsgSuccess(chain.ctx.IsBootstrapped())
+	}
+}
+
+// GetAcceptedStateSummaryFailed routes an incoming
+// GetAcceptedStateSummaryFailed request from the validator with ID
+// [validatorID] to the consensus engine working on the chain with ID [chainID]
+func (cr *ChainRouter) GetAcceptedStateSummaryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	uniqueRequestID := cr.createRequestID(validatorID, chainID, requestID)
+
+	// Remove the outstanding request
+	cr.removeRequest(uniqueRequestID)
+
+	// Get the chain, if it exists
+	chain, exists := cr.chains[chainID]
+	if !exists {
+		// Should only happen when shutting down
+		cr.log.Debug("GetAcceptedStateSummaryFailed(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+		return
+	}
+
+	// Pass the response to the chain
+	chain.GetAcceptedStateSummaryFailed(validatorID, requestID)
+}
+
 // GetAncestors routes an incoming GetAncestors message from the validator with ID [validatorID]
 // to the consensus engine working on the chain with ID [chainID]
 // The maximum number of ancestors to respond with is defined in snow/engine/commong/bootstrapper.go
@@ -771,6 +1097,8 @@ func (cr *ChainRouter) Disconnected(validatorID ids.ShortID) {
 	defer cr.lock.Unlock()
 
 	cr.peers.Remove(validatorID)
+	// The peer's response time may be different when it reconnects
+	cr.timeoutManager.RemovePeer(validatorID)
 	if _, benched := cr.benched[validatorID]; benched {
 		return
 	}
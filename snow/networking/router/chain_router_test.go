@@ -18,6 +18,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
 )
@@ -335,3 +336,227 @@ func TestRouterClearTimeouts(t *testing.T) {
 
 	assert.Equal(t, chainRouter.timedRequests.Len(), 0)
 }
+
+func TestRouterPushedAcceptedFrontier(t *testing.T) {
+	tm := timeout.Manager{}
+	err := tm.Initialize(&timer.AdaptiveTimeoutConfig{
+		InitialTimeout:     10 * time.Millisecond,
+		MinimumTimeout:     10 * time.Millisecond,
+		MaximumTimeout:     10 * time.Second,
+		TimeoutCoefficient: 1.25,
+		TimeoutHalflife:    5 * time.Minute,
+		MetricsNamespace:   "",
+		Registerer:         prometheus.NewRegistry(),
+	}, benchlist.NewNoBenchlist())
+	if err != nil {
+		t.Fatal(err)
+	}
+	go tm.Dispatch()
+
+	chainRouter := ChainRouter{}
+	err = chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, &tm, time.Hour, time.Millisecond, ids.Set{}, nil, HealthConfig{}, "", prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	engine := common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = snow.DefaultContextTest
+
+	received := make(chan uint32, 2)
+	engine.AcceptedFrontierF = func(validatorID ids.ShortID, requestID uint32, containerIDs []ids.ID) error {
+		received <- requestID
+		return nil
+	}
+
+	handler := &Handler{}
+	err = handler.Initialize(
+		&engine,
+		validators.NewSet(),
+		nil,
+		DefaultMaxNonStakerPendingMsgs,
+		DefaultMaxNonStakerPendingMsgs,
+		DefaultStakerPortion,
+		DefaultStakerPortion,
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(t, err)
+
+	chainRouter.AddChain(handler)
+	go handler.Dispatch()
+
+	vdr := ids.GenerateTestShortID()
+	containerIDs := []ids.ID{ids.GenerateTestID()}
+
+	// An unrequested response should be dropped
+	chainRouter.AcceptedFrontier(vdr, handler.ctx.ChainID, 1, containerIDs)
+	// A pushed frontier should be passed to the engine
+	chainRouter.AcceptedFrontier(vdr, handler.ctx.ChainID, constants.GossipMsgRequestID, containerIDs)
+
+	select {
+	case requestID := <-received:
+		assert.Equal(t, uint32(constants.GossipMsgRequestID), requestID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("pushed frontier wasn't passed to the engine")
+	}
+	assert.Len(t, received, 0)
+}
+
+type crossSubnetVM struct {
+	common.TestVM
+	received chan ids.ShortID
+}
+
+func (vm *crossSubnetVM) CrossSubnet(originID ids.ShortID, sourceChainID ids.ID, msg []byte) error {
+	vm.received <- originID
+	return nil
+}
+
+type crossSubnetSender struct {
+	relayedTo ids.ShortSet
+}
+
+func (s *crossSubnetSender) CrossSubnet(validatorIDs ids.ShortSet, _, _ ids.ID, _ ids.ShortID, _, _ []byte) []ids.ShortID {
+	s.relayedTo.Union(validatorIDs)
+	return validatorIDs.List()
+}
+
+func TestRouterCrossSubnet(t *testing.T) {
+	tm := timeout.Manager{}
+	err := tm.Initialize(&timer.AdaptiveTimeoutConfig{
+		InitialTimeout:     10 * time.Millisecond,
+		MinimumTimeout:     10 * time.Millisecond,
+		MaximumTimeout:     10 * time.Second,
+		TimeoutCoefficient: 1.25,
+		TimeoutHalflife:    5 * time.Minute,
+		MetricsNamespace:   "",
+		Registerer:         prometheus.NewRegistry(),
+	}, benchlist.NewNoBenchlist())
+	if err != nil {
+		t.Fatal(err)
+	}
+	go tm.Dispatch()
+
+	nodeID := ids.GenerateTestShortID()
+	originID := ids.GenerateTestShortID()
+	relayerID := ids.GenerateTestShortID()
+	destinationVdrID := ids.GenerateTestShortID()
+
+	chainRouter := ChainRouter{}
+	err = chainRouter.Initialize(nodeID, logging.NoLog{}, &tm, time.Hour, time.Millisecond, ids.Set{}, nil, HealthConfig{}, "", prometheus.NewRegistry())
+	assert.NoError(t, err)
+	sender := &crossSubnetSender{}
+	blsKeys := validators.NewBLSKeys()
+	chainRouter.SetCrossSubnetSender(sender, blsKeys)
+
+	newKey := func(nodeID ids.ShortID) *bls.SecretKey {
+		sk, err := bls.NewSecretKey()
+		assert.NoError(t, err)
+		assert.NoError(t, blsKeys.Set(ids.NodeIDFromShortID(nodeID), sk.PublicKey()))
+		return sk
+	}
+
+	newChain := func(vdrIDs ...ids.ShortID) (*Handler, *crossSubnetVM) {
+		ctx := snow.DefaultContextTest()
+		ctx.SubnetID = ids.GenerateTestID()
+		ctx.ChainID = ids.GenerateTestID()
+
+		vm := &crossSubnetVM{received: make(chan ids.ShortID, 1)}
+		engine := common.EngineTest{T: t}
+		engine.Default(false)
+		engine.ContextF = func() *snow.Context { return ctx }
+		engine.GetVMF = func() common.VM { return vm }
+
+		vdrs := validators.NewSet()
+		for _, vdrID := range vdrIDs {
+			assert.NoError(t, vdrs.AddWeight(ids.NodeIDFromShortID(vdrID), 1))
+		}
+
+		handler := &Handler{}
+		err := handler.Initialize(
+			&engine,
+			vdrs,
+			nil,
+			DefaultMaxNonStakerPendingMsgs,
+			DefaultMaxNonStakerPendingMsgs,
+			DefaultStakerPortion,
+			DefaultStakerPortion,
+			"",
+			prometheus.NewRegistry(),
+		)
+		assert.NoError(t, err)
+
+		chainRouter.AddChain(handler)
+		go handler.Dispatch()
+		return handler, vm
+	}
+
+	source, _ := newChain(nodeID, originID)
+	destination, destinationVM := newChain(nodeID, relayerID, destinationVdrID)
+	sourceChainID := source.ctx.ChainID
+	destinationChainID := destination.ctx.ChainID
+
+	expectDelivery := func(expectedOriginID ids.ShortID) {
+		select {
+		case receivedOriginID := <-destinationVM.received:
+			assert.Equal(t, expectedOriginID, receivedOriginID)
+		case <-time.After(5 * time.Second):
+			t.Fatal("cross-subnet message wasn't delivered")
+		}
+	}
+
+	originKey := newKey(originID)
+	otherOriginID := ids.GenerateTestShortID()
+	otherOriginKey := newKey(otherOriginID)
+	sign := func(sk *bls.SecretKey, sourceChainID, destinationChainID ids.ID, msg []byte) []byte {
+		return sk.Sign(CrossSubnetSigningBytes(sourceChainID, destinationChainID, msg)).Bytes()
+	}
+
+	// A message from a validator of the source subnet is delivered and
+	// relayed to the other validators of the destination subnet
+	msg := []byte{1}
+	chainRouter.CrossSubnet(originID, sourceChainID, destinationChainID, originID, msg, sign(originKey, sourceChainID, destinationChainID, msg))
+	expectDelivery(originID)
+	assert.Equal(t, 2, sender.relayedTo.Len())
+	assert.True(t, sender.relayedTo.Contains(relayerID))
+	assert.True(t, sender.relayedTo.Contains(destinationVdrID))
+
+	// A message relayed by a validator of the destination subnet is delivered
+	// but not relayed again
+	sender.relayedTo.Clear()
+	otherSourceChainID := ids.GenerateTestID()
+	msg = []byte{2}
+	chainRouter.CrossSubnet(relayerID, otherSourceChainID, destinationChainID, otherOriginID, msg, sign(otherOriginKey, otherSourceChainID, destinationChainID, msg))
+	expectDelivery(otherOriginID)
+	assert.Equal(t, 0, sender.relayedTo.Len())
+
+	// A message that a local chain originates is signed with this node's key,
+	// delivered, and relayed
+	originator := NewCrossSubnetOriginator(&chainRouter, nodeID, sourceChainID, newKey(nodeID))
+	assert.NoError(t, originator.SendCrossSubnet(destinationChainID, []byte{3}))
+	expectDelivery(nodeID)
+	assert.Equal(t, 2, sender.relayedTo.Len())
+	assert.Error(t, NewCrossSubnetOriginator(&chainRouter, nodeID, sourceChainID, nil).SendCrossSubnet(destinationChainID, []byte{3}))
+
+	// Messages that can't be authenticated are dropped
+	sender.relayedTo.Clear()
+	msg = []byte{4}
+	chainRouter.CrossSubnet(otherOriginID, sourceChainID, destinationChainID, otherOriginID, msg, sign(otherOriginKey, sourceChainID, destinationChainID, msg))
+	chainRouter.CrossSubnet(originID, otherSourceChainID, destinationChainID, originID, msg, sign(originKey, otherSourceChainID, destinationChainID, msg))
+	chainRouter.CrossSubnet(otherOriginID, sourceChainID, destinationChainID, originID, msg, sign(originKey, sourceChainID, destinationChainID, msg))
+	chainRouter.CrossSubnet(originID, sourceChainID, ids.GenerateTestID(), originID, msg, sign(originKey, sourceChainID, destinationChainID, msg))
+	// Signed by the wrong key, over the wrong message, or not at all
+	chainRouter.CrossSubnet(originID, sourceChainID, destinationChainID, originID, msg, sign(otherOriginKey, sourceChainID, destinationChainID, msg))
+	chainRouter.CrossSubnet(originID, sourceChainID, destinationChainID, originID, msg, sign(originKey, sourceChainID, destinationChainID, []byte{5}))
+	chainRouter.CrossSubnet(relayerID, otherSourceChainID, destinationChainID, otherOriginID, msg, nil)
+	// Signed by an origin without a registered key
+	unknownKey, err := bls.NewSecretKey()
+	assert.NoError(t, err)
+	chainRouter.CrossSubnet(relayerID, otherSourceChainID, destinationChainID, ids.GenerateTestShortID(), msg, sign(unknownKey, otherSourceChainID, destinationChainID, msg))
+
+	select {
+	case <-destinationVM.received:
+		t.Fatal("unauthenticated cross-subnet message was delivered")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Equal(t, 0, sender.relayedTo.Len())
+}
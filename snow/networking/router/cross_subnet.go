@@ -0,0 +1,87 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+var (
+	errNoCrossSubnetKeys    = errors.New("no BLS keys to authenticate cross-subnet messages with")
+	errUnknownOriginKey     = errors.New("origin has no registered BLS key")
+	errInvalidOriginSig     = errors.New("invalid origin signature")
+	errNoCrossSubnetSignKey = errors.New("this node has no BLS key to sign cross-subnet messages with")
+
+	_ snow.CrossSubnetSender = &crossSubnetOriginator{}
+)
+
+// CrossSubnetSigningBytes returns the bytes that the origin of a message from
+// the chain [sourceChainID] to the chain [destinationChainID] signs
+func CrossSubnetSigningBytes(sourceChainID, destinationChainID ids.ID, msg []byte) []byte {
+	msgHash := hashing.ComputeHash256(msg)
+	signed := make([]byte, 0, 2*hashing.HashLen+len(msgHash))
+	signed = append(signed, sourceChainID[:]...)
+	signed = append(signed, destinationChainID[:]...)
+	return append(signed, msgHash...)
+}
+
+// verifyCrossSubnet returns nil if [signature] is [originID]'s signature of
+// the message [msg] from the chain [sourceChainID] to the chain
+// [destinationChainID]
+func verifyCrossSubnet(blsKeys validators.BLSKeys, sourceChainID, destinationChainID ids.ID, originID ids.ShortID, msg, signature []byte) error {
+	if blsKeys == nil {
+		return errNoCrossSubnetKeys
+	}
+	pk, ok := blsKeys.Get(ids.NodeIDFromShortID(originID))
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnknownOriginKey, originID)
+	}
+	sig, err := bls.SignatureFromBytes(signature)
+	if err != nil {
+		return fmt.Errorf("couldn't parse origin signature: %w", err)
+	}
+	if !bls.Verify(pk, sig, CrossSubnetSigningBytes(sourceChainID, destinationChainID, msg)) {
+		return errInvalidOriginSig
+	}
+	return nil
+}
+
+// crossSubnetOriginator sends the cross-subnet messages that a local chain
+// originates
+type crossSubnetOriginator struct {
+	router  ExternalRouter
+	nodeID  ids.ShortID
+	chainID ids.ID
+	sk      *bls.SecretKey
+}
+
+// NewCrossSubnetOriginator returns the sender that the chain [chainID] sends
+// cross-subnet messages with. The messages are signed with [sk], which should
+// be the BLS key of [nodeID], and routed by [router] as if [nodeID] had sent
+// them to this node. If [sk] is nil, sending fails.
+func NewCrossSubnetOriginator(router ExternalRouter, nodeID ids.ShortID, chainID ids.ID, sk *bls.SecretKey) snow.CrossSubnetSender {
+	return &crossSubnetOriginator{
+		router:  router,
+		nodeID:  nodeID,
+		chainID: chainID,
+		sk:      sk,
+	}
+}
+
+// SendCrossSubnet implements the snow.CrossSubnetSender interface
+func (o *crossSubnetOriginator) SendCrossSubnet(destinationChainID ids.ID, msg []byte) error {
+	if o.sk == nil {
+		return errNoCrossSubnetSignKey
+	}
+	sig := o.sk.Sign(CrossSubnetSigningBytes(o.chainID, destinationChainID, msg))
+	o.router.CrossSubnet(o.nodeID, o.chainID, destinationChainID, o.nodeID, msg, sig.Bytes())
+	return nil
+}
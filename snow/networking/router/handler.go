@@ -244,6 +244,13 @@ func (h *Handler) Dispatch() {
 
 // Dispatch a message to the consensus engine.
 func (h *Handler) dispatchMsg(msg message) {
+	if h.ctx.Archival && msg.IsConsensus() {
+		// In archival mode, the chain doesn't take part in consensus
+		h.ctx.Log.Verbo("Dropping consensus message in archival mode: %s", msg)
+		h.metrics.dropped.Inc()
+		return
+	}
+
 	startTime := h.clock.Time()
 
 	h.ctx.Lock.Lock()
@@ -270,6 +277,8 @@ func (h *Handler) dispatchMsg(msg message) {
 		err = h.handleValidatorMsg(msg, startTime)
 	}
 
+	h.metrics.busyTime.Add(float64(h.clock.Time().Sub(startTime)))
+
 	if msg.IsPeriodic() {
 		h.ctx.Log.Verbo("Finished sending message to consensus: %s", msg.messageType)
 	} else {
@@ -351,6 +360,75 @@ func (h *Handler) GetAcceptedFailed(validatorID ids.ShortID, requestID uint32) {
 	})
 }
 
+// GetStateSummaryFrontier passes a GetStateSummaryFrontier message received
+// from the network to the consensus engine.
+func (h *Handler) GetStateSummaryFrontier(validatorID ids.ShortID, requestID uint32, deadline time.Time) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: constants.GetStateSummaryFrontierMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		deadline:    deadline,
+		received:    h.clock.Time(),
+	})
+}
+
+// StateSummaryFrontier passes a StateSummaryFrontier message received from the
+// network to the consensus engine.
+func (h *Handler) StateSummaryFrontier(validatorID ids.ShortID, requestID uint32, summary []byte) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: constants.StateSummaryFrontierMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		container:   summary,
+		received:    h.clock.Time(),
+	})
+}
+
+// GetStateSummaryFrontierFailed passes a GetStateSummaryFrontierFailed message
+// received from the network to the consensus engine.
+func (h *Handler) GetStateSummaryFrontierFailed(validatorID ids.ShortID, requestID uint32) {
+	h.sendReliableMsg(message{
+		messageType: constants.GetStateSummaryFrontierFailedMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	})
+}
+
+// GetAcceptedStateSummary passes a GetAcceptedStateSummary message received
+// from the network to the consensus engine.
+func (h *Handler) GetAcceptedStateSummary(validatorID ids.ShortID, requestID uint32, deadline time.Time, heights []uint64) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: constants.GetAcceptedStateSummaryMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		deadline:    deadline,
+		heights:     heights,
+		received:    h.clock.Time(),
+	})
+}
+
+// AcceptedStateSummary passes a AcceptedStateSummary message received from the
+// network to the consensus engine.
+func (h *Handler) AcceptedStateSummary(validatorID ids.ShortID, requestID uint32, summaryIDs []ids.ID) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType:  constants.AcceptedStateSummaryMsg,
+		validatorID:  validatorID,
+		requestID:    requestID,
+		containerIDs: summaryIDs,
+		received:     h.clock.Time(),
+	})
+}
+
+// GetAcceptedStateSummaryFailed passes a GetAcceptedStateSummaryFailed message
+// received from the network to the consensus engine.
+func (h *Handler) GetAcceptedStateSummaryFailed(validatorID ids.ShortID, requestID uint32) {
+	h.sendReliableMsg(message{
+		messageType: constants.GetAcceptedStateSummaryFailedMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	})
+}
+
 // GetAncestors passes a GetAncestors message received from the network to the consensus engine.
 func (h *Handler) GetAncestors(validatorID ids.ShortID, requestID uint32, deadline time.Time, containerID ids.ID) bool {
 	return h.serviceQueue.PushMessage(message{
@@ -459,6 +537,19 @@ func (h *Handler) Chits(validatorID ids.ShortID, requestID uint32, votes []ids.I
 	})
 }
 
+// CrossSubnet passes a message from the chain [sourceChainID], created by
+// [originID] and received from [validatorID], to the VM.
+func (h *Handler) CrossSubnet(validatorID ids.ShortID, sourceChainID ids.ID, originID ids.ShortID, msg []byte) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType:   constants.CrossSubnetMsg,
+		validatorID:   validatorID,
+		sourceChainID: sourceChainID,
+		originID:      originID,
+		container:     msg,
+		received:      h.clock.Time(),
+	})
+}
+
 // QueryFailed passes a QueryFailed message received from the network to the consensus engine.
 func (h *Handler) QueryFailed(validatorID ids.ShortID, requestID uint32) {
 	h.sendReliableMsg(message{
@@ -543,6 +634,18 @@ func (h *Handler) handleValidatorMsg(msg message, startTime time.Time) error {
 		err = h.engine.Accepted(msg.validatorID, msg.requestID, msg.containerIDs)
 	case constants.GetAcceptedFailedMsg:
 		err = h.engine.GetAcceptedFailed(msg.validatorID, msg.requestID)
+	case constants.GetStateSummaryFrontierMsg:
+		err = h.engine.GetStateSummaryFrontier(msg.validatorID, msg.requestID)
+	case constants.StateSummaryFrontierMsg:
+		err = h.engine.StateSummaryFrontier(msg.validatorID, msg.requestID, msg.container)
+	case constants.GetStateSummaryFrontierFailedMsg:
+		err = h.engine.GetStateSummaryFrontierFailed(msg.validatorID, msg.requestID)
+	case constants.GetAcceptedStateSummaryMsg:
+		err = h.engine.GetAcceptedStateSummary(msg.validatorID, msg.requestID, msg.heights)
+	case constants.AcceptedStateSummaryMsg:
+		err = h.engine.AcceptedStateSummary(msg.validatorID, msg.requestID, msg.containerIDs)
+	case constants.GetAcceptedStateSummaryFailedMsg:
+		err = h.engine.GetAcceptedStateSummaryFailed(msg.validatorID, msg.requestID)
 	case constants.GetAncestorsMsg:
 		err = h.engine.GetAncestors(msg.validatorID, msg.requestID, msg.containerID)
 	case constants.GetAncestorsFailedMsg:
@@ -567,6 +670,8 @@ func (h *Handler) handleValidatorMsg(msg message, startTime time.Time) error {
 		err = h.engine.Connected(msg.validatorID)
 	case constants.DisconnectedMsg:
 		err = h.engine.Disconnected(msg.validatorID)
+	case constants.CrossSubnetMsg:
+		h.handleCrossSubnet(msg)
 	}
 	endTime := h.clock.Time()
 	timeConsumed := endTime.Sub(startTime)
@@ -579,6 +684,19 @@ func (h *Handler) handleValidatorMsg(msg message, startTime time.Time) error {
 	return err
 }
 
+// handleCrossSubnet passes a cross-subnet message to the VM, if the VM accepts
+// cross-subnet messages.
+func (h *Handler) handleCrossSubnet(msg message) {
+	vm, ok := h.engine.GetVM().(common.CrossSubnetHandler)
+	if !ok {
+		h.ctx.Log.Debug("dropping %s because the VM doesn't accept cross-subnet messages", msg)
+		return
+	}
+	if err := vm.CrossSubnet(msg.originID, msg.sourceChainID, msg.container); err != nil {
+		h.ctx.Log.Debug("failed to handle %s due to %s", msg, err)
+	}
+}
+
 func (h *Handler) sendReliableMsg(msg message) {
 	h.reliableMsgsLock.Lock()
 	defer h.reliableMsgsLock.Unlock()
@@ -36,13 +36,18 @@ type handlerMetrics struct {
 	namespace        string
 	registerer       prometheus.Registerer
 	pending          prometheus.Gauge
+	queueDepth       prometheus.Gauge
 	dropped, expired prometheus.Counter
+	busyTime         prometheus.Counter
 	getAcceptedFrontier, acceptedFrontier, getAcceptedFrontierFailed,
 	getAccepted, accepted, getAcceptedFailed,
+	getStateSummaryFrontier, stateSummaryFrontier, getStateSummaryFrontierFailed,
+	getAcceptedStateSummary, acceptedStateSummary, getAcceptedStateSummaryFailed,
 	getAncestors, multiPut, getAncestorsFailed,
 	get, put, getFailed,
 	pushQuery, pullQuery, chits, queryFailed,
 	connected, disconnected,
+	crossSubnet,
 	timeout,
 	notify,
 	gossip,
@@ -65,6 +70,15 @@ func (m *handlerMetrics) Initialize(namespace string, registerer prometheus.Regi
 		errs.Add(fmt.Errorf("failed to register pending statistics due to %w", err))
 	}
 
+	m.queueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_depth",
+		Help:      "Number of messages from peers waiting in the message queue",
+	})
+	if err := registerer.Register(m.queueDepth); err != nil {
+		errs.Add(fmt.Errorf("failed to register queue_depth statistics due to %w", err))
+	}
+
 	m.dropped = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: namespace,
 		Name:      "dropped",
@@ -84,12 +98,27 @@ func (m *handlerMetrics) Initialize(namespace string, registerer prometheus.Regi
 		errs.Add(fmt.Errorf("failed to register expired statistics due to %w", err))
 	}
 
+	m.busyTime = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "busy_time",
+		Help:      "Time spent by the engine processing messages in nanoseconds",
+	})
+	if err := registerer.Register(m.busyTime); err != nil {
+		errs.Add(fmt.Errorf("failed to register busy_time statistics due to %w", err))
+	}
+
 	m.getAcceptedFrontier = initHistogram(namespace, "get_accepted_frontier", registerer, &errs)
 	m.acceptedFrontier = initHistogram(namespace, "accepted_frontier", registerer, &errs)
 	m.getAcceptedFrontierFailed = initHistogram(namespace, "get_accepted_frontier_failed", registerer, &errs)
 	m.getAccepted = initHistogram(namespace, "get_accepted", registerer, &errs)
 	m.accepted = initHistogram(namespace, "accepted", registerer, &errs)
 	m.getAcceptedFailed = initHistogram(namespace, "get_accepted_failed", registerer, &errs)
+	m.getStateSummaryFrontier = initHistogram(namespace, "get_state_summary_frontier", registerer, &errs)
+	m.stateSummaryFrontier = initHistogram(namespace, "state_summary_frontier", registerer, &errs)
+	m.getStateSummaryFrontierFailed = initHistogram(namespace, "get_state_summary_frontier_failed", registerer, &errs)
+	m.getAcceptedStateSummary = initHistogram(namespace, "get_accepted_state_summary", registerer, &errs)
+	m.acceptedStateSummary = initHistogram(namespace, "accepted_state_summary", registerer, &errs)
+	m.getAcceptedStateSummaryFailed = initHistogram(namespace, "get_accepted_state_summary_failed", registerer, &errs)
 	m.getAncestors = initHistogram(namespace, "get_ancestors", registerer, &errs)
 	m.multiPut = initHistogram(namespace, "multi_put", registerer, &errs)
 	m.getAncestorsFailed = initHistogram(namespace, "get_ancestors_failed", registerer, &errs)
@@ -102,6 +131,7 @@ func (m *handlerMetrics) Initialize(namespace string, registerer prometheus.Regi
 	m.queryFailed = initHistogram(namespace, "query_failed", registerer, &errs)
 	m.connected = initHistogram(namespace, "connected", registerer, &errs)
 	m.disconnected = initHistogram(namespace, "disconnected", registerer, &errs)
+	m.crossSubnet = initHistogram(namespace, "cross_subnet", registerer, &errs)
 	m.timeout = initHistogram(namespace, "timeout", registerer, &errs)
 	m.notify = initHistogram(namespace, "notify", registerer, &errs)
 	m.gossip = initHistogram(namespace, "gossip", registerer, &errs)
@@ -166,6 +196,18 @@ func (m *handlerMetrics) getMSGHistogram(msg constants.MsgType) prometheus.Histo
 		return m.accepted
 	case constants.GetAcceptedFailedMsg:
 		return m.getAcceptedFailed
+	case constants.GetStateSummaryFrontierMsg:
+		return m.getStateSummaryFrontier
+	case constants.StateSummaryFrontierMsg:
+		return m.stateSummaryFrontier
+	case constants.GetStateSummaryFrontierFailedMsg:
+		return m.getStateSummaryFrontierFailed
+	case constants.GetAcceptedStateSummaryMsg:
+		return m.getAcceptedStateSummary
+	case constants.AcceptedStateSummaryMsg:
+		return m.acceptedStateSummary
+	case constants.GetAcceptedStateSummaryFailedMsg:
+		return m.getAcceptedStateSummaryFailed
 	case constants.GetAncestorsMsg:
 		return m.getAncestors
 	case constants.GetAncestorsFailedMsg:
@@ -192,6 +234,8 @@ func (m *handlerMetrics) getMSGHistogram(msg constants.MsgType) prometheus.Histo
 		return m.connected
 	case constants.DisconnectedMsg:
 		return m.disconnected
+	case constants.CrossSubnetMsg:
+		return m.crossSubnet
 	default:
 		panic(fmt.Sprintf("unknown message type %s", msg))
 	}
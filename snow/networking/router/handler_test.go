@@ -36,7 +36,7 @@ func TestHandlerDropsTimedOutMessages(t *testing.T) {
 	handler := &Handler{}
 	vdrs := validators.NewSet()
 	vdr0 := ids.GenerateTestShortID()
-	if err := vdrs.AddWeight(vdr0, 1); err != nil {
+	if err := vdrs.AddWeight(ids.NodeIDFromShortID(vdr0), 1); err != nil {
 		t.Fatal(err)
 	}
 	err := handler.Initialize(
@@ -192,3 +192,49 @@ func TestHandlerDropsGossipDuringBootstrapping(t *testing.T) {
 	case <-closed:
 	}
 }
+
+func TestHandlerDropsConsensusMessagesInArchivalMode(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	ctx.Archival = true
+
+	engine := common.EngineTest{T: t}
+	engine.Default(true)
+	engine.ContextF = func() *snow.Context { return ctx }
+	called := make(chan struct{})
+
+	// The queries and notifications are dropped, so calling them fails the
+	// test, but the chain's data is still served
+	engine.GetAncestorsF = func(validatorID ids.ShortID, requestID uint32, containerID ids.ID) error {
+		called <- struct{}{}
+		return nil
+	}
+
+	handler := &Handler{}
+	err := handler.Initialize(
+		&engine,
+		validators.NewSet(),
+		nil,
+		16,
+		DefaultMaxNonStakerPendingMsgs,
+		DefaultStakerPortion,
+		DefaultStakerPortion,
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(t, err)
+
+	deadline := time.Now().Add(time.Second)
+	handler.PullQuery(ids.ShortID{}, 1, deadline, ids.Empty)
+	handler.Notify(common.PendingTxs)
+	handler.GetAncestors(ids.ShortID{}, 2, deadline, ids.Empty)
+
+	go handler.Dispatch()
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	select {
+	case <-ticker.C:
+		t.Fatalf("Calling engine function timed out")
+	case <-called:
+	}
+}
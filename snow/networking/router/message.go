@@ -21,9 +21,14 @@ type message struct {
 	container    []byte
 	containers   [][]byte
 	containerIDs []ids.ID
+	heights      []uint64
 	notification common.Message
 	received     time.Time // Time this message was received
 	deadline     time.Time // Time this message must be responded to
+
+	// Only used for cross-subnet messages
+	sourceChainID ids.ID
+	originID      ids.ShortID
 }
 
 // IsPeriodic returns true if this message is of a type that is sent on a
@@ -33,6 +38,18 @@ func (m message) IsPeriodic() bool {
 		m.messageType == constants.GossipMsg
 }
 
+// IsConsensus returns true if this message is of a type that is only sent
+// while taking part in consensus, rather than to serve the chain's data.
+func (m message) IsConsensus() bool {
+	switch m.messageType {
+	case constants.PushQueryMsg, constants.PullQueryMsg, constants.ChitsMsg,
+		constants.QueryFailedMsg, constants.NotifyMsg, constants.GossipMsg:
+		return true
+	default:
+		return false
+	}
+}
+
 func (m message) String() string {
 	sb := strings.Builder{}
 	sb.WriteString(fmt.Sprintf("(%s, ValidatorID: %s, RequestID: %d", m.messageType, m.validatorID, m.requestID))
@@ -49,8 +66,16 @@ func (m message) String() string {
 		sb.WriteString(fmt.Sprintf(", ContainerID: %s)", m.containerID))
 	case constants.MultiPutMsg:
 		sb.WriteString(fmt.Sprintf(", NumContainers: %d)", len(m.containers)))
+	case constants.StateSummaryFrontierMsg:
+		sb.WriteString(fmt.Sprintf(", SummarySize: %d)", len(m.container)))
+	case constants.GetAcceptedStateSummaryMsg:
+		sb.WriteString(fmt.Sprintf(", Heights: %v)", m.heights))
+	case constants.AcceptedStateSummaryMsg:
+		sb.WriteString(fmt.Sprintf(", SummaryIDs: %s)", m.containerIDs))
 	case constants.NotifyMsg:
 		sb.WriteString(fmt.Sprintf(", Notification: %s)", m.notification))
+	case constants.CrossSubnetMsg:
+		sb.WriteString(fmt.Sprintf(", SourceChainID: %s, OriginID: %s)", m.sourceChainID, m.originID))
 	default:
 		sb.WriteString(")")
 	}
@@ -107,7 +107,7 @@ func (rm *msgManager) AddPending(vdr ids.ShortID) bool {
 	}
 
 	// Attempt to take the message from the individual allotment
-	weight, isStaker := rm.vdrs.GetWeight(vdr)
+	weight, isStaker := rm.vdrs.GetWeight(ids.NodeIDFromShortID(vdr))
 	if !isStaker {
 		if poolEmpty {
 			rm.metrics.throttledPoolEmpty.Inc()
@@ -146,7 +146,7 @@ func (rm *msgManager) Utilization(vdr ids.ShortID) float64 {
 	numSpenders := rm.cpuTracker.Len()
 	poolAllotment := (1 - rm.stakerCPUPortion) / float64(numSpenders)
 
-	weight, exists := rm.vdrs.GetWeight(vdr)
+	weight, exists := rm.vdrs.GetWeight(ids.NodeIDFromShortID(vdr))
 	if !exists {
 		return vdrUtilization / poolAllotment
 	}
@@ -46,7 +46,7 @@ func TestAddPending(t *testing.T) {
 	assert.NoError(t, err)
 
 	for i, vdr := range vdrList {
-		if success := resourceManager.AddPending(vdr.ID()); !success {
+		if success := resourceManager.AddPending(vdr.ID().ShortID()); !success {
 			t.Fatalf("Failed to take message %d.", i)
 		}
 	}
@@ -56,7 +56,7 @@ func TestAddPending(t *testing.T) {
 	}
 
 	for _, vdr := range vdrList {
-		resourceManager.RemovePending(vdr.ID())
+		resourceManager.RemovePending(vdr.ID().ShortID())
 	}
 
 	// Ensure that space is freed up after returning the messages
@@ -98,7 +98,7 @@ func TestStakerGetsThrottled(t *testing.T) {
 	// cannot take up the entire message queue
 	vdrID := vdrList[0].ID()
 	for i := 0; i < bufferSize; i++ {
-		if success := resourceManager.AddPending(vdrID); !success {
+		if success := resourceManager.AddPending(vdrID.ShortID()); !success {
 			// The staker was throttled before taking up the whole message queue
 			return
 		}
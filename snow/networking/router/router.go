@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/prometheus/client_golang/prometheus"
@@ -36,6 +37,7 @@ type Router interface {
 	Shutdown()
 	AddChain(chain *Handler)
 	RemoveChain(chainID ids.ID)
+	SetCrossSubnetSender(sender CrossSubnetSender, blsKeys validators.BLSKeys)
 	health.Checkable
 }
 
@@ -47,6 +49,10 @@ type ExternalRouter interface {
 	AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs []ids.ID)
 	GetAccepted(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerIDs []ids.ID)
 	Accepted(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs []ids.ID)
+	GetStateSummaryFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time)
+	StateSummaryFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte)
+	GetAcceptedStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, heights []uint64)
+	AcceptedStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summaryIDs []ids.ID)
 	GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID)
 	MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte)
 	Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID)
@@ -54,12 +60,24 @@ type ExternalRouter interface {
 	PushQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, container []byte)
 	PullQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID)
 	Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes []ids.ID)
+	CrossSubnet(validatorID ids.ShortID, sourceChainID, destinationChainID ids.ID, originID ids.ShortID, msg, signature []byte)
+}
+
+// CrossSubnetSender sends cross-subnet messages to other nodes
+type CrossSubnetSender interface {
+	// Send a message from chain [sourceChainID], created and signed by
+	// [originID], to chain [destinationChainID] on the validators in
+	// [validatorIDs]. Returns the IDs of validators that may receive the
+	// message.
+	CrossSubnet(validatorIDs ids.ShortSet, sourceChainID, destinationChainID ids.ID, originID ids.ShortID, msg, signature []byte) []ids.ShortID
 }
 
 // InternalRouter deals with messages internal to this node
 type InternalRouter interface {
 	GetAcceptedFrontierFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	GetAcceptedFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
+	GetStateSummaryFrontierFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
+	GetAcceptedStateSummaryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	GetFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	GetAncestorsFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	QueryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
@@ -110,6 +110,7 @@ func (ml *multiLevelQueue) PopMessage() (message, error) {
 		ml.msgManager.RemovePending(msg.validatorID)
 		ml.pendingMessages--
 		ml.metrics.pending.Dec()
+		ml.metrics.queueDepth.Dec()
 	}
 	return msg, err
 }
@@ -224,6 +225,7 @@ func (ml *multiLevelQueue) pushMessage(msg message) bool {
 		ml.log.Error("Sempahore channel was full after pushing message to the message queue")
 	}
 	ml.metrics.pending.Inc()
+	ml.metrics.queueDepth.Inc()
 	return true
 }
 
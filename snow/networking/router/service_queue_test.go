@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -89,6 +90,33 @@ func TestMultiLevelQueueSendsMessages(t *testing.T) {
 	}
 }
 
+func TestMultiLevelQueueTracksQueueDepth(t *testing.T) {
+	assert := assert.New(t)
+
+	metrics := &handlerMetrics{}
+	if err := metrics.Initialize("", prometheus.NewRegistry()); err != nil {
+		t.Fatal(err)
+	}
+	queue, semaChan := newMultiLevelQueue(
+		newInfiniteResourceManager(),
+		[]float64{math.MaxFloat64},
+		[]time.Duration{defaultCPUInterval},
+		4,
+		logging.NoLog{},
+		metrics,
+	)
+
+	for i := 0; i < 3; i++ {
+		assert.True(queue.PushMessage(message{validatorID: ids.ShortID{byte(i)}}))
+	}
+	assert.Equal(3.0, testutil.ToFloat64(metrics.queueDepth))
+
+	<-semaChan
+	_, err := queue.PopMessage()
+	assert.NoError(err)
+	assert.Equal(2.0, testutil.ToFloat64(metrics.queueDepth))
+}
+
 func TestExtraMessageNoDeadlock(t *testing.T) {
 	bufferSize := uint32(8)
 	oversizedBuffer := bufferSize * 2
@@ -188,22 +216,22 @@ func TestMultiLevelQueuePrioritizes(t *testing.T) {
 	startTime := time.Now()
 	duration := perTier / 2
 	endTime := startTime.Add(duration)
-	queue.UtilizeCPU(validator2.ID(), duration)
-	cpuTracker.UtilizeTime(validator2.ID(), startTime, endTime)
+	queue.UtilizeCPU(validator2.ID().ShortID(), duration)
+	cpuTracker.UtilizeTime(validator2.ID().ShortID(), startTime, endTime)
 
 	// Push two messages from from high priority validator and one from
 	// low priority validator
 	messages := []message{
 		{
-			validatorID: validator1.ID(),
+			validatorID: validator1.ID().ShortID(),
 			requestID:   1,
 		},
 		{
-			validatorID: validator1.ID(),
+			validatorID: validator1.ID().ShortID(),
 			requestID:   2,
 		},
 		{
-			validatorID: validator2.ID(),
+			validatorID: validator2.ID().ShortID(),
 			requestID:   3,
 		},
 	}
@@ -215,26 +243,26 @@ func TestMultiLevelQueuePrioritizes(t *testing.T) {
 	<-semaChan
 	if msg1, err := queue.PopMessage(); err != nil {
 		t.Fatal(err)
-	} else if msg1.validatorID != validator1.ID() {
+	} else if msg1.validatorID != validator1.ID().ShortID() {
 		t.Fatal("Expected first message to come from the high priority validator")
 	}
 
 	// Utilize the remainder of the time that should be alloted to the highest priority
 	// queue.
 	duration = perTier
-	queue.UtilizeCPU(validator1.ID(), duration)
+	queue.UtilizeCPU(validator1.ID().ShortID(), duration)
 
 	<-semaChan
 	if msg2, err := queue.PopMessage(); err != nil {
 		t.Fatal(err)
-	} else if msg2.validatorID != validator2.ID() {
+	} else if msg2.validatorID != validator2.ID().ShortID() {
 		t.Fatal("Expected second message to come from the low priority validator after moving on to the lower level queue")
 	}
 
 	<-semaChan
 	if msg3, err := queue.PopMessage(); err != nil {
 		t.Fatal(err)
-	} else if msg3.validatorID != validator1.ID() {
+	} else if msg3.validatorID != validator1.ID().ShortID() {
 		t.Fatal("Expected final message to come from validator1")
 	}
 }
@@ -301,22 +329,22 @@ func TestMultiLevelQueuePushesDownOldMessages(t *testing.T) {
 	)
 
 	queue.PushMessage(message{
-		validatorID: vdr0.ID(),
+		validatorID: vdr0.ID().ShortID(),
 		requestID:   1,
 	})
 	queue.PushMessage(message{
-		validatorID: vdr0.ID(),
+		validatorID: vdr0.ID().ShortID(),
 		requestID:   2,
 	})
 	queue.PushMessage(message{
-		validatorID: vdr1.ID(),
+		validatorID: vdr1.ID().ShortID(),
 		requestID:   3,
 	})
 
 	<-semaChan
 	if msg, err := queue.PopMessage(); err != nil {
 		t.Fatalf("Popping first message errored: %s", err)
-	} else if msg.validatorID != vdr0.ID() {
+	} else if msg.validatorID != vdr0.ID().ShortID() {
 		t.Fatal("Expected first message to come from vdr0")
 	}
 
@@ -326,20 +354,20 @@ func TestMultiLevelQueuePushesDownOldMessages(t *testing.T) {
 	startTime := time.Now()
 	duration := time.Second / 2
 	endTime := startTime.Add(duration)
-	queue.UtilizeCPU(vdr0.ID(), duration)
-	cpuTracker.UtilizeTime(vdr0.ID(), startTime, endTime)
+	queue.UtilizeCPU(vdr0.ID().ShortID(), duration)
+	cpuTracker.UtilizeTime(vdr0.ID().ShortID(), startTime, endTime)
 
 	<-semaChan
 	if msg, err := queue.PopMessage(); err != nil {
 		t.Fatalf("Popping second message errored: %s", err)
-	} else if msg.validatorID != vdr1.ID() {
+	} else if msg.validatorID != vdr1.ID().ShortID() {
 		t.Fatal("Expected second message to come from vdr1 after vdr0 dropped in priority")
 	}
 
 	<-semaChan
 	if msg, err := queue.PopMessage(); err != nil {
 		t.Fatalf("Popping third message errored: %s", err)
-	} else if msg.validatorID != vdr0.ID() {
+	} else if msg.validatorID != vdr0.ID().ShortID() {
 		t.Fatal("Expected third message to come from vdr0")
 	}
 }
@@ -410,12 +438,12 @@ func TestMultiLevelQueueFreesSpace(t *testing.T) {
 	for i := uint32(0); i < 4; i++ {
 		validator1.ID()
 		if success := queue.PushMessage(message{
-			validatorID: validator1.ID(),
+			validatorID: validator1.ID().ShortID(),
 		}); !success {
 			t.Fatalf("Failed to push message from validator1 on (Round 1, Iteration %d)", i)
 		}
 		if success := queue.PushMessage(message{
-			validatorID: validator2.ID(),
+			validatorID: validator2.ID().ShortID(),
 		}); !success {
 			t.Fatalf("Failed to push message from validator2 on (Round 1, Iteration %d)", i)
 		}
@@ -433,12 +461,12 @@ func TestMultiLevelQueueFreesSpace(t *testing.T) {
 	// popping previous messages freed up space
 	for i := uint32(0); i < 4; i++ {
 		if success := queue.PushMessage(message{
-			validatorID: validator1.ID(),
+			validatorID: validator1.ID().ShortID(),
 		}); !success {
 			t.Fatalf("Failed to push message from validator1 on (Round 2, Iteration %d)", i)
 		}
 		if success := queue.PushMessage(message{
-			validatorID: validator2.ID(),
+			validatorID: validator2.ID().ShortID(),
 		}); !success {
 			t.Fatalf("Failed to push message from validator2 on (Round 2, Iteration %d)", i)
 		}
@@ -23,6 +23,15 @@ type ExternalSender interface {
 	GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration, containerIDs []ids.ID) []ids.ShortID
 	Accepted(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs []ids.ID)
 
+	// Send a GetStateSummaryFrontier message for chain [chainID] to validators
+	// in [validatorIDs]. Validators that don't support state sync aren't
+	// included in the return value.
+	GetStateSummaryFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration) []ids.ShortID
+	StateSummaryFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte)
+
+	GetAcceptedStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration, heights []uint64) []ids.ShortID
+	AcceptedStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summaryIDs []ids.ID)
+
 	// Request ancestors of container [containerID] in chain [chainID] from validator [validatorID].
 	// The validator should reply by [deadline].
 	// Returns true if the validator may receive the message.
@@ -50,6 +50,9 @@ func (s *Sender) Initialize(
 		constants.GetAncestorsMsg:        "get_ancestors",
 		constants.PullQueryMsg:           "pull_query",
 		constants.PushQueryMsg:           "push_query",
+
+		constants.GetStateSummaryFrontierMsg: "get_state_summary_frontier",
+		constants.GetAcceptedStateSummaryMsg: "get_accepted_state_summary",
 	}
 
 	s.failedDueToBench = make(map[constants.MsgType]prometheus.Counter, len(requestTypes))
@@ -183,6 +186,118 @@ func (s *Sender) Accepted(validatorID ids.ShortID, requestID uint32, containerID
 	}
 }
 
+// GetStateSummaryFrontier asks [validatorIDs] for their latest state summaries
+func (s *Sender) GetStateSummaryFrontier(validatorIDs ids.ShortSet, requestID uint32) {
+	// Sending a message to myself. No need to send it over the network.
+	// Just put it right into the router. Asynchronously to avoid deadlock.
+	if validatorIDs.Contains(s.ctx.NodeID) {
+		validatorIDs.Remove(s.ctx.NodeID)
+		// Note that this timeout duration won't exactly match the one that gets registered. That's OK.
+		timeoutDuration := s.timeouts.TimeoutDuration()
+		// Tell the router to expect a reply message from this validator
+		s.router.RegisterRequest(s.ctx.NodeID, s.ctx.ChainID, requestID, constants.GetStateSummaryFrontierMsg)
+		go s.router.GetStateSummaryFrontier(s.ctx.NodeID, s.ctx.ChainID, requestID, time.Now().Add(timeoutDuration))
+	}
+
+	// Some of the validators in [validatorIDs] may be benched. That is, they've been unresponsive
+	// so we don't even bother sending messages to them. We just have them immediately fail.
+	for validatorID := range validatorIDs {
+		if s.timeouts.IsBenched(validatorID, s.ctx.ChainID) {
+			s.failedDueToBench[constants.GetStateSummaryFrontierMsg].Inc() // update metric
+			validatorIDs.Remove(validatorID)
+			s.timeouts.RegisterRequestToUnreachableValidator()
+			// Immediately register a failure. Do so asynchronously to avoid deadlock.
+			go s.router.GetStateSummaryFrontierFailed(validatorID, s.ctx.ChainID, requestID)
+		}
+	}
+
+	// Try to send the messages over the network.
+	// [sentTo] are the IDs of validators who may receive the message.
+	// Note that this timeout duration won't exactly match the one that gets registered. That's OK.
+	timeoutDuration := s.timeouts.TimeoutDuration()
+	sentTo := s.sender.GetStateSummaryFrontier(validatorIDs, s.ctx.ChainID, requestID, timeoutDuration)
+
+	// Tell the router to expect a reply message from these validators
+	for _, validatorID := range sentTo {
+		vID := validatorID // Prevent overwrite in next loop iteration
+		s.router.RegisterRequest(vID, s.ctx.ChainID, requestID, constants.GetStateSummaryFrontierMsg)
+		validatorIDs.Remove(vID)
+	}
+
+	// Register failures for validators we didn't even send a request to. This
+	// includes validators that don't support state sync.
+	for validatorID := range validatorIDs {
+		s.timeouts.RegisterRequestToUnreachableValidator()
+		go s.router.GetStateSummaryFrontierFailed(validatorID, s.ctx.ChainID, requestID)
+	}
+}
+
+// StateSummaryFrontier sends this node's latest state summary to [validatorID]
+func (s *Sender) StateSummaryFrontier(validatorID ids.ShortID, requestID uint32, summary []byte) {
+	if validatorID == s.ctx.NodeID {
+		go s.router.StateSummaryFrontier(validatorID, s.ctx.ChainID, requestID, summary)
+	} else {
+		s.sender.StateSummaryFrontier(validatorID, s.ctx.ChainID, requestID, summary)
+	}
+}
+
+// GetAcceptedStateSummary asks [validatorIDs] which of their state summaries
+// at [heights] they've accepted
+func (s *Sender) GetAcceptedStateSummary(validatorIDs ids.ShortSet, requestID uint32, heights []uint64) {
+	// Sending a message to myself. No need to send it over the network.
+	// Just put it right into the router. Asynchronously to avoid deadlock.
+	if validatorIDs.Contains(s.ctx.NodeID) {
+		validatorIDs.Remove(s.ctx.NodeID)
+		// Note that this timeout duration won't exactly match the one that gets registered. That's OK.
+		timeoutDuration := s.timeouts.TimeoutDuration()
+		// Tell the router to expect a reply message from this validator
+		s.router.RegisterRequest(s.ctx.NodeID, s.ctx.ChainID, requestID, constants.GetAcceptedStateSummaryMsg)
+		go s.router.GetAcceptedStateSummary(s.ctx.NodeID, s.ctx.ChainID, requestID, time.Now().Add(timeoutDuration), heights)
+	}
+
+	// Some of the validators in [validatorIDs] may be benched. That is, they've been unresponsive
+	// so we don't even bother sending messages to them. We just have them immediately fail.
+	for validatorID := range validatorIDs {
+		if s.timeouts.IsBenched(validatorID, s.ctx.ChainID) {
+			s.failedDueToBench[constants.GetAcceptedStateSummaryMsg].Inc() // update metric
+			validatorIDs.Remove(validatorID)
+			s.timeouts.RegisterRequestToUnreachableValidator()
+			// Immediately register a failure. Do so asynchronously to avoid deadlock.
+			go s.router.GetAcceptedStateSummaryFailed(validatorID, s.ctx.ChainID, requestID)
+		}
+	}
+
+	// Try to send the messages over the network.
+	// [sentTo] are the IDs of validators who may receive the message.
+	// Note that this timeout duration won't exactly match the one that gets registered. That's OK.
+	timeoutDuration := s.timeouts.TimeoutDuration()
+	sentTo := s.sender.GetAcceptedStateSummary(validatorIDs, s.ctx.ChainID, requestID, timeoutDuration, heights)
+
+	// Tell the router to expect a reply message from these validators
+	for _, validatorID := range sentTo {
+		vID := validatorID // Prevent overwrite in next loop iteration
+		s.router.RegisterRequest(vID, s.ctx.ChainID, requestID, constants.GetAcceptedStateSummaryMsg)
+		validatorIDs.Remove(vID)
+	}
+
+	// Register failures for validators we didn't even send a request to. This
+	// includes validators that don't support state sync.
+	for validatorID := range validatorIDs {
+		s.timeouts.RegisterRequestToUnreachableValidator()
+		go s.router.GetAcceptedStateSummaryFailed(validatorID, s.ctx.ChainID, requestID)
+	}
+}
+
+// AcceptedStateSummary sends the IDs of the state summaries that this node has
+// accepted to [validatorID]
+func (s *Sender) AcceptedStateSummary(validatorID ids.ShortID, requestID uint32, summaryIDs []ids.ID) {
+	if validatorID == s.ctx.NodeID {
+		go s.router.AcceptedStateSummary(validatorID, s.ctx.ChainID, requestID, summaryIDs)
+	} else {
+		s.sender.AcceptedStateSummary(validatorID, s.ctx.ChainID, requestID, summaryIDs)
+	}
+}
+
 // GetAncestors sends a GetAncestors message
 func (s *Sender) GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
 	s.ctx.Log.Verbo("Sending GetAncestors to validator %s. RequestID: %d. ContainerID: %s", validatorID, requestID, containerID)
@@ -202,7 +317,7 @@ func (s *Sender) GetAncestors(validatorID ids.ShortID, requestID uint32, contain
 	}
 
 	// Note that this timeout duration won't exactly match the one that gets registered. That's OK.
-	timeoutDuration := s.timeouts.TimeoutDuration()
+	timeoutDuration := s.timeouts.PeerTimeoutDuration(validatorID)
 	sent := s.sender.GetAncestors(validatorID, s.ctx.ChainID, requestID, timeoutDuration, containerID)
 
 	if sent {
@@ -245,7 +360,7 @@ func (s *Sender) Get(validatorID ids.ShortID, requestID uint32, containerID ids.
 	}
 
 	// Note that this timeout duration won't exactly match the one that gets registered. That's OK.
-	timeoutDuration := s.timeouts.TimeoutDuration()
+	timeoutDuration := s.timeouts.PeerTimeoutDuration(validatorID)
 	sent := s.sender.Get(validatorID, s.ctx.ChainID, requestID, timeoutDuration, containerID)
 
 	if sent {
@@ -273,8 +388,9 @@ func (s *Sender) Put(validatorID ids.ShortID, requestID uint32, containerID ids.
 func (s *Sender) PushQuery(validatorIDs ids.ShortSet, requestID uint32, containerID ids.ID, container []byte) {
 	s.ctx.Log.Verbo("Sending PushQuery to validators %v. RequestID: %d. ContainerID: %s", validatorIDs, requestID, containerID)
 
-	// Note that this timeout duration won't exactly match the one that gets registered. That's OK.
-	timeoutDuration := s.timeouts.TimeoutDuration()
+	// Note that the timeouts registered for each validator may be shorter than
+	// this. That's OK.
+	timeoutDuration := s.queryTimeout(validatorIDs)
 
 	// Sending a message to myself. No need to send it over the network.
 	// Just put it right into the router. Do so asynchronously to avoid deadlock.
@@ -323,8 +439,9 @@ func (s *Sender) PushQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 func (s *Sender) PullQuery(validatorIDs ids.ShortSet, requestID uint32, containerID ids.ID) {
 	s.ctx.Log.Verbo("Sending PullQuery. RequestID: %d. ContainerID: %s", requestID, containerID)
 
-	// Note that this timeout duration won't exactly match the one that gets registered. That's OK.
-	timeoutDuration := s.timeouts.TimeoutDuration()
+	// Note that the timeouts registered for each validator may be shorter than
+	// this. That's OK.
+	timeoutDuration := s.queryTimeout(validatorIDs)
 
 	// Sending a message to myself. No need to send it over the network.
 	// Just put it right into the router. Do so asynchronously to avoid deadlock.
@@ -365,6 +482,22 @@ func (s *Sender) PullQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 	}
 }
 
+// queryTimeout returns the longest timeout of the requests sent to
+// [validatorIDs]. A query is sent to all of them with the same deadline, which
+// must not be before this node stops waiting for any of their responses.
+func (s *Sender) queryTimeout(validatorIDs ids.ShortSet) time.Duration {
+	timeout := time.Duration(0)
+	for validatorID := range validatorIDs {
+		if peerTimeout := s.timeouts.PeerTimeoutDuration(validatorID); peerTimeout > timeout {
+			timeout = peerTimeout
+		}
+	}
+	if timeout == 0 {
+		return s.timeouts.TimeoutDuration()
+	}
+	return timeout
+}
+
 // Chits sends chits
 func (s *Sender) Chits(validatorID ids.ShortID, requestID uint32, votes []ids.ID) {
 	s.ctx.Log.Verbo("Sending Chits to validator %s. RequestID: %d. Votes: %s", validatorID, requestID, votes)
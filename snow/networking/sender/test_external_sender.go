@@ -17,6 +17,8 @@ type ExternalSenderTest struct {
 
 	CantGetAcceptedFrontier, CantAcceptedFrontier,
 	CantGetAccepted, CantAccepted,
+	CantGetStateSummaryFrontier, CantStateSummaryFrontier,
+	CantGetAcceptedStateSummary, CantAcceptedStateSummary,
 	CantGetAncestors, CantMultiPut,
 	CantGet, CantPut,
 	CantPullQuery, CantPushQuery, CantChits,
@@ -28,6 +30,12 @@ type ExternalSenderTest struct {
 	GetAcceptedF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration, containerIDs []ids.ID) []ids.ShortID
 	AcceptedF    func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs []ids.ID)
 
+	GetStateSummaryFrontierF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration) []ids.ShortID
+	StateSummaryFrontierF    func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte)
+
+	GetAcceptedStateSummaryF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration, heights []uint64) []ids.ShortID
+	AcceptedStateSummaryF    func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summaryIDs []ids.ID)
+
 	GetAncestorsF func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID) bool
 	MultiPutF     func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte)
 
@@ -49,6 +57,12 @@ func (s *ExternalSenderTest) Default(cant bool) {
 	s.CantGetAccepted = cant
 	s.CantAccepted = cant
 
+	s.CantGetStateSummaryFrontier = cant
+	s.CantStateSummaryFrontier = cant
+
+	s.CantGetAcceptedStateSummary = cant
+	s.CantAcceptedStateSummary = cant
+
 	s.CantGetAncestors = cant
 	s.CantMultiPut = cant
 
@@ -120,6 +134,64 @@ func (s *ExternalSenderTest) Accepted(validatorID ids.ShortID, chainID ids.ID, r
 	}
 }
 
+// GetStateSummaryFrontier calls GetStateSummaryFrontierF if it was
+// initialized. If it wasn't initialized and this function shouldn't be called
+// and testing was initialized, then testing will fail.
+func (s *ExternalSenderTest) GetStateSummaryFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration) []ids.ShortID {
+	switch {
+	case s.GetStateSummaryFrontierF != nil:
+		return s.GetStateSummaryFrontierF(validatorIDs, chainID, requestID, deadline)
+	case s.CantGetStateSummaryFrontier && s.T != nil:
+		s.T.Fatalf("Unexpectedly called GetStateSummaryFrontier")
+	case s.CantGetStateSummaryFrontier && s.B != nil:
+		s.B.Fatalf("Unexpectedly called GetStateSummaryFrontier")
+	}
+	return nil
+}
+
+// StateSummaryFrontier calls StateSummaryFrontierF if it was initialized. If it
+// wasn't initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) StateSummaryFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	switch {
+	case s.StateSummaryFrontierF != nil:
+		s.StateSummaryFrontierF(validatorID, chainID, requestID, summary)
+	case s.CantStateSummaryFrontier && s.T != nil:
+		s.T.Fatalf("Unexpectedly called StateSummaryFrontier")
+	case s.CantStateSummaryFrontier && s.B != nil:
+		s.B.Fatalf("Unexpectedly called StateSummaryFrontier")
+	}
+}
+
+// GetAcceptedStateSummary calls GetAcceptedStateSummaryF if it was
+// initialized. If it wasn't initialized and this function shouldn't be called
+// and testing was initialized, then testing will fail.
+func (s *ExternalSenderTest) GetAcceptedStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration, heights []uint64) []ids.ShortID {
+	switch {
+	case s.GetAcceptedStateSummaryF != nil:
+		return s.GetAcceptedStateSummaryF(validatorIDs, chainID, requestID, deadline, heights)
+	case s.CantGetAcceptedStateSummary && s.T != nil:
+		s.T.Fatalf("Unexpectedly called GetAcceptedStateSummary")
+	case s.CantGetAcceptedStateSummary && s.B != nil:
+		s.B.Fatalf("Unexpectedly called GetAcceptedStateSummary")
+	}
+	return nil
+}
+
+// AcceptedStateSummary calls AcceptedStateSummaryF if it was initialized. If it
+// wasn't initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) AcceptedStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summaryIDs []ids.ID) {
+	switch {
+	case s.AcceptedStateSummaryF != nil:
+		s.AcceptedStateSummaryF(validatorID, chainID, requestID, summaryIDs)
+	case s.CantAcceptedStateSummary && s.T != nil:
+		s.T.Fatalf("Unexpectedly called AcceptedStateSummary")
+	case s.CantAcceptedStateSummary && s.B != nil:
+		s.B.Fatalf("Unexpectedly called AcceptedStateSummary")
+	}
+}
+
 // GetAncestors calls GetAncestorsF if it was initialized. If it wasn't initialized and this
 // function shouldn't be called and testing was initialized, then testing will
 // fail.
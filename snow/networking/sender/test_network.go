@@ -0,0 +1,310 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sender
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// SimulatedNetworkConfig describes the conditions of a simulated network
+type SimulatedNetworkConfig struct {
+	// Minimum time it takes for a message to be delivered
+	Latency time.Duration
+	// Maximum additional time, chosen uniformly at random, that it takes for
+	// a message to be delivered. Messages may be delivered out of order.
+	Jitter time.Duration
+	// Probability in [0, 1] that a message is silently dropped
+	DropRate float64
+	// Seed of the randomness used to add jitter and drop messages
+	Seed int64
+}
+
+// SimulatedNetwork simulates the network connecting many nodes that run in a
+// single process. Every node gets an ExternalSender that delivers its messages
+// to the ExternalRouters of the other nodes, subject to the configured latency,
+// jitter, drop rate and partitions. This allows consensus engines to be tested
+// against adverse network conditions without opening any connections.
+type SimulatedNetwork struct {
+	lock    sync.Mutex
+	config  SimulatedNetworkConfig
+	rng     *rand.Rand
+	routers map[ids.ShortID]router.ExternalRouter
+	// Nodes in different groups, or in a negative group, can't communicate
+	groups   map[ids.ShortID]int
+	inFlight sync.WaitGroup
+	closed   bool
+}
+
+// NewSimulatedNetwork returns a simulated network with no nodes
+func NewSimulatedNetwork(config SimulatedNetworkConfig) *SimulatedNetwork {
+	return &SimulatedNetwork{
+		config:  config,
+		rng:     rand.New(rand.NewSource(config.Seed)), // #nosec G404
+		routers: make(map[ids.ShortID]router.ExternalRouter),
+		groups:  make(map[ids.ShortID]int),
+	}
+}
+
+// AddNode adds the node [nodeID], whose incoming messages are passed to
+// [externalRouter], to the network. Returns the ExternalSender that [nodeID]
+// should use to send messages to the other nodes.
+func (n *SimulatedNetwork) AddNode(nodeID ids.ShortID, externalRouter router.ExternalRouter) ExternalSender {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.routers[nodeID] = externalRouter
+	n.groups[nodeID] = 0
+	return &simulatedSender{
+		network: n,
+		nodeID:  nodeID,
+	}
+}
+
+// SetConfig replaces the conditions of the network. Messages already in flight
+// are unaffected.
+func (n *SimulatedNetwork) SetConfig(config SimulatedNetworkConfig) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.config = config
+	n.rng = rand.New(rand.NewSource(config.Seed)) // #nosec G404
+}
+
+// Partition the network so that nodes can only communicate with the nodes in
+// the same group. Nodes that aren't in any group are fully isolated: they can't
+// communicate with any other node, including each other. Messages already in
+// flight are still delivered.
+func (n *SimulatedNetwork) Partition(groups ...[]ids.ShortID) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for nodeID := range n.groups {
+		n.groups[nodeID] = -1
+	}
+	for i, group := range groups {
+		for _, nodeID := range group {
+			n.groups[nodeID] = i
+		}
+	}
+}
+
+// Heal removes all partitions
+func (n *SimulatedNetwork) Heal() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for nodeID := range n.groups {
+		n.groups[nodeID] = 0
+	}
+}
+
+// Wait until every message in flight has been delivered or dropped
+func (n *SimulatedNetwork) Wait() { n.inFlight.Wait() }
+
+// Close the network. Messages that haven't been delivered yet are dropped.
+func (n *SimulatedNetwork) Close() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.closed = true
+}
+
+// isConnected assumes [n.lock] is held
+func (n *SimulatedNetwork) isConnected(nodeID, peerID ids.ShortID) bool {
+	if n.closed || nodeID == peerID {
+		return false
+	}
+	peerGroup, exists := n.groups[peerID]
+	return exists && peerGroup >= 0 && peerGroup == n.groups[nodeID]
+}
+
+// send schedules [deliver] to be called with the router of [peerID], unless
+// the message is dropped. Returns false if [nodeID] isn't connected to
+// [peerID].
+func (n *SimulatedNetwork) send(nodeID, peerID ids.ShortID, deliver func(router.ExternalRouter)) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if !n.isConnected(nodeID, peerID) {
+		return false
+	}
+	if n.rng.Float64() < n.config.DropRate {
+		// The message looks like it was sent, but it never arrives
+		return true
+	}
+
+	delay := n.config.Latency
+	if n.config.Jitter > 0 {
+		delay += time.Duration(n.rng.Int63n(int64(n.config.Jitter)))
+	}
+	peerRouter := n.routers[peerID]
+
+	n.inFlight.Add(1)
+	time.AfterFunc(delay, func() {
+		defer n.inFlight.Done()
+
+		n.lock.Lock()
+		closed := n.closed
+		n.lock.Unlock()
+
+		if !closed {
+			deliver(peerRouter)
+		}
+	})
+	return true
+}
+
+// simulatedSender is the ExternalSender of a node in a SimulatedNetwork
+type simulatedSender struct {
+	network *SimulatedNetwork
+	nodeID  ids.ShortID
+}
+
+func (s *simulatedSender) GetAcceptedFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration) []ids.ShortID {
+	sentTo := []ids.ShortID(nil)
+	for _, validatorID := range validatorIDs.List() {
+		if s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+			r.GetAcceptedFrontier(s.nodeID, chainID, requestID, time.Now().Add(deadline))
+		}) {
+			sentTo = append(sentTo, validatorID)
+		}
+	}
+	return sentTo
+}
+
+func (s *simulatedSender) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs []ids.ID) {
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.AcceptedFrontier(s.nodeID, chainID, requestID, containerIDs)
+	})
+}
+
+func (s *simulatedSender) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration, containerIDs []ids.ID) []ids.ShortID {
+	sentTo := []ids.ShortID(nil)
+	for _, validatorID := range validatorIDs.List() {
+		if s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+			r.GetAccepted(s.nodeID, chainID, requestID, time.Now().Add(deadline), containerIDs)
+		}) {
+			sentTo = append(sentTo, validatorID)
+		}
+	}
+	return sentTo
+}
+
+func (s *simulatedSender) Accepted(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs []ids.ID) {
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.Accepted(s.nodeID, chainID, requestID, containerIDs)
+	})
+}
+
+func (s *simulatedSender) GetStateSummaryFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration) []ids.ShortID {
+	sentTo := []ids.ShortID(nil)
+	for _, validatorID := range validatorIDs.List() {
+		if s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+			r.GetStateSummaryFrontier(s.nodeID, chainID, requestID, time.Now().Add(deadline))
+		}) {
+			sentTo = append(sentTo, validatorID)
+		}
+	}
+	return sentTo
+}
+
+func (s *simulatedSender) StateSummaryFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.StateSummaryFrontier(s.nodeID, chainID, requestID, summary)
+	})
+}
+
+func (s *simulatedSender) GetAcceptedStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration, heights []uint64) []ids.ShortID {
+	sentTo := []ids.ShortID(nil)
+	for _, validatorID := range validatorIDs.List() {
+		if s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+			r.GetAcceptedStateSummary(s.nodeID, chainID, requestID, time.Now().Add(deadline), heights)
+		}) {
+			sentTo = append(sentTo, validatorID)
+		}
+	}
+	return sentTo
+}
+
+func (s *simulatedSender) AcceptedStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summaryIDs []ids.ID) {
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.AcceptedStateSummary(s.nodeID, chainID, requestID, summaryIDs)
+	})
+}
+
+func (s *simulatedSender) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID) bool {
+	return s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.GetAncestors(s.nodeID, chainID, requestID, time.Now().Add(deadline), containerID)
+	})
+}
+
+func (s *simulatedSender) MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte) {
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.MultiPut(s.nodeID, chainID, requestID, containers)
+	})
+}
+
+func (s *simulatedSender) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID) bool {
+	return s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.Get(s.nodeID, chainID, requestID, time.Now().Add(deadline), containerID)
+	})
+}
+
+func (s *simulatedSender) Put(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.Put(s.nodeID, chainID, requestID, containerID, container)
+	})
+}
+
+func (s *simulatedSender) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID, container []byte) []ids.ShortID {
+	sentTo := []ids.ShortID(nil)
+	for _, validatorID := range validatorIDs.List() {
+		if s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+			r.PushQuery(s.nodeID, chainID, requestID, time.Now().Add(deadline), containerID, container)
+		}) {
+			sentTo = append(sentTo, validatorID)
+		}
+	}
+	return sentTo
+}
+
+func (s *simulatedSender) PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID) []ids.ShortID {
+	sentTo := []ids.ShortID(nil)
+	for _, validatorID := range validatorIDs.List() {
+		if s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+			r.PullQuery(s.nodeID, chainID, requestID, time.Now().Add(deadline), containerID)
+		}) {
+			sentTo = append(sentTo, validatorID)
+		}
+	}
+	return sentTo
+}
+
+func (s *simulatedSender) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes []ids.ID) {
+	s.network.send(s.nodeID, validatorID, func(r router.ExternalRouter) {
+		r.Chits(s.nodeID, chainID, requestID, votes)
+	})
+}
+
+// Gossip sends the container to every node this node is connected to
+func (s *simulatedSender) Gossip(chainID ids.ID, containerID ids.ID, container []byte) {
+	s.network.lock.Lock()
+	peerIDs := make([]ids.ShortID, 0, len(s.network.routers))
+	for peerID := range s.network.routers {
+		peerIDs = append(peerIDs, peerID)
+	}
+	s.network.lock.Unlock()
+
+	for _, peerID := range peerIDs {
+		s.network.send(s.nodeID, peerID, func(r router.ExternalRouter) {
+			r.Put(s.nodeID, chainID, constants.GossipMsgRequestID, containerID, container)
+		})
+	}
+}
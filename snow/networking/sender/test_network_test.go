@@ -0,0 +1,124 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sender
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// recordingRouter records the Put and PullQuery messages it receives
+type recordingRouter struct {
+	router.ExternalRouter
+
+	lock     sync.Mutex
+	puts     []uint32
+	queriers ids.ShortSet
+}
+
+func (r *recordingRouter) Put(_ ids.ShortID, _ ids.ID, requestID uint32, _ ids.ID, _ []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.puts = append(r.puts, requestID)
+}
+
+func (r *recordingRouter) PullQuery(validatorID ids.ShortID, _ ids.ID, _ uint32, _ time.Time, _ ids.ID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.queriers.Add(validatorID)
+}
+
+func (r *recordingRouter) numPuts() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return len(r.puts)
+}
+
+func TestSimulatedNetworkLatency(t *testing.T) {
+	network := NewSimulatedNetwork(SimulatedNetworkConfig{
+		Latency: 50 * time.Millisecond,
+		Jitter:  10 * time.Millisecond,
+	})
+
+	nodeID0, nodeID1 := ids.GenerateTestShortID(), ids.GenerateTestShortID()
+	router1 := &recordingRouter{}
+	sender0 := network.AddNode(nodeID0, &recordingRouter{})
+	network.AddNode(nodeID1, router1)
+
+	start := time.Now()
+	sender0.Put(nodeID1, ids.Empty, 1, ids.Empty, nil)
+	assert.Equal(t, 0, router1.numPuts())
+
+	network.Wait()
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Millisecond))
+	assert.Equal(t, []uint32{1}, router1.puts)
+}
+
+func TestSimulatedNetworkDrops(t *testing.T) {
+	network := NewSimulatedNetwork(SimulatedNetworkConfig{DropRate: 1})
+
+	nodeID0, nodeID1 := ids.GenerateTestShortID(), ids.GenerateTestShortID()
+	router1 := &recordingRouter{}
+	sender0 := network.AddNode(nodeID0, &recordingRouter{})
+	network.AddNode(nodeID1, router1)
+
+	// Dropped messages look like they were sent
+	assert.True(t, sender0.Get(nodeID1, ids.Empty, 1, time.Second, ids.Empty))
+	sender0.Put(nodeID1, ids.Empty, 2, ids.Empty, nil)
+	network.Wait()
+	assert.Equal(t, 0, router1.numPuts())
+
+	network.SetConfig(SimulatedNetworkConfig{})
+	sender0.Put(nodeID1, ids.Empty, 3, ids.Empty, nil)
+	network.Wait()
+	assert.Equal(t, []uint32{3}, router1.puts)
+}
+
+func TestSimulatedNetworkPartition(t *testing.T) {
+	network := NewSimulatedNetwork(SimulatedNetworkConfig{})
+
+	nodeIDs := []ids.ShortID{
+		ids.GenerateTestShortID(),
+		ids.GenerateTestShortID(),
+		ids.GenerateTestShortID(),
+		ids.GenerateTestShortID(),
+	}
+	routers := []*recordingRouter{{}, {}, {}, {}}
+	senders := make([]ExternalSender, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		senders[i] = network.AddNode(nodeID, routers[i])
+	}
+
+	network.Partition([]ids.ShortID{nodeIDs[0], nodeIDs[1]})
+
+	validatorIDs := ids.ShortSet{}
+	validatorIDs.Add(nodeIDs...)
+	sentTo := senders[0].PullQuery(validatorIDs, ids.Empty, 1, time.Second, ids.Empty)
+	assert.Equal(t, []ids.ShortID{nodeIDs[1]}, sentTo)
+	assert.False(t, senders[2].GetAncestors(nodeIDs[0], ids.Empty, 2, time.Second, ids.Empty))
+
+	network.Wait()
+	assert.True(t, routers[1].queriers.Contains(nodeIDs[0]))
+	assert.Equal(t, 0, routers[2].queriers.Len())
+
+	// Nodes that aren't in any group can't reach each other either
+	assert.False(t, senders[2].GetAncestors(nodeIDs[3], ids.Empty, 3, time.Second, ids.Empty))
+
+	network.Heal()
+	senders[2].Gossip(ids.Empty, ids.Empty, nil)
+	network.Wait()
+	assert.Equal(t, []uint32{constants.GossipMsgRequestID}, routers[0].puts)
+	assert.Equal(t, []uint32{constants.GossipMsgRequestID}, routers[1].puts)
+	assert.Equal(t, 0, routers[2].numPuts())
+}
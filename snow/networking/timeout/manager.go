@@ -39,6 +39,17 @@ func (m *Manager) TimeoutDuration() time.Duration {
 	return m.tm.TimeoutDuration()
 }
 
+// PeerTimeoutDuration returns the timeout duration for requests sent to
+// [validatorID]
+func (m *Manager) PeerTimeoutDuration(validatorID ids.ShortID) time.Duration {
+	return m.tm.PeerTimeoutDuration(validatorID)
+}
+
+// RemovePeer forgets the estimated response time of [validatorID]
+func (m *Manager) RemovePeer(validatorID ids.ShortID) {
+	m.tm.RemovePeer(validatorID)
+}
+
 // IsBenched returns true if messages to [validatorID] regarding [chainID]
 // should not be sent over the network and should immediately fail.
 func (m *Manager) IsBenched(validatorID ids.ShortID, chainID ids.ID) bool {
@@ -71,7 +82,7 @@ func (m *Manager) RegisterRequest(
 		m.benchlistMgr.RegisterFailure(chainID, validatorID)
 		timeoutHandler()
 	}
-	return m.tm.Put(uniqueRequestID, msgType, newTimeoutHandler), true
+	return m.tm.Put(uniqueRequestID, validatorID, msgType, newTimeoutHandler), true
 }
 
 // RegisterResponse registers that we received a response from [validatorID]
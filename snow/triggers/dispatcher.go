@@ -7,12 +7,13 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
-var _ snow.EventDispatcher = &EventDispatcher{}
+var _ snow.BatchingEventDispatcher = &EventDispatcher{}
 
 type handler struct {
 	// Must implement at least one of Acceptor, Rejector, Issuer
@@ -45,6 +46,25 @@ func (ed *EventDispatcher) Accept(ctx *snow.Context, containerID ids.ID, contain
 	ed.lock.Lock()
 	defer ed.lock.Unlock()
 
+	_, err := ed.accept(ctx, containerID, container, false)
+	return err
+}
+
+// AcceptBatch is Accept, but the chain's handlers that implement
+// BatchedAcceptor return their writes instead of writing them. The caller must
+// write the returned batches atomically with [container]'s acceptance.
+func (ed *EventDispatcher) AcceptBatch(ctx *snow.Context, containerID ids.ID, container []byte) ([]database.Batch, error) {
+	ed.lock.Lock()
+	defer ed.lock.Unlock()
+
+	return ed.accept(ctx, containerID, container, true)
+}
+
+// accept notifies the handlers of the acceptance. If [batch], the writes of
+// the chain's handlers that implement BatchedAcceptor are returned rather than
+// written.
+// Assumes [ed.lock] is held.
+func (ed *EventDispatcher) accept(ctx *snow.Context, containerID ids.ID, container []byte, batch bool) ([]database.Batch, error) {
 	for id, handler := range ed.handlers {
 		handler, ok := handler.(Acceptor)
 		if !ok {
@@ -58,22 +78,36 @@ func (ed *EventDispatcher) Accept(ctx *snow.Context, containerID ids.ID, contain
 
 	events, exist := ed.chainHandlers[ctx.ChainID]
 	if !exist {
-		return nil
+		return nil, nil
 	}
+	var batches []database.Batch
 	for id, handler := range events {
-		handlerFunc, ok := handler.handlerFunc.(Acceptor)
-		if !ok {
+		var err error
+		switch handlerFunc := handler.handlerFunc.(type) {
+		case BatchedAcceptor:
+			if !batch {
+				err = handlerFunc.Accept(ctx, containerID, container)
+				break
+			}
+			var b database.Batch
+			b, err = handlerFunc.AcceptBatch(ctx, containerID, container)
+			if err == nil && b != nil {
+				batches = append(batches, b)
+			}
+		case Acceptor:
+			err = handlerFunc.Accept(ctx, containerID, container)
+		default:
 			continue
 		}
 
-		if err := handlerFunc.Accept(ctx, containerID, container); err != nil {
+		if err != nil {
 			ed.log.Error("handler %s on chain %s errored while accepting %s: %s", id, ctx.ChainID, containerID, err)
 			if handler.dieOnError {
-				return fmt.Errorf("handler %s on chain %s errored while accepting %s: %w", id, ctx.ChainID, containerID, err)
+				return nil, fmt.Errorf("handler %s on chain %s errored while accepting %s: %w", id, ctx.ChainID, containerID, err)
 			}
 		}
 	}
-	return nil
+	return batches, nil
 }
 
 // Reject is called when a transaction or block is rejected
@@ -4,6 +4,7 @@
 package triggers
 
 import (
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 )
@@ -13,6 +14,15 @@ type Acceptor interface {
 	Accept(ctx *snow.Context, containerID ids.ID, container []byte) error
 }
 
+// BatchedAcceptor is optionally implemented by Acceptors that can return their
+// record of an acceptance as a batch, to be written atomically with the
+// container's acceptance, instead of writing it themselves
+type BatchedAcceptor interface {
+	Acceptor
+
+	AcceptBatch(ctx *snow.Context, containerID ids.ID, container []byte) (database.Batch, error)
+}
+
 // Rejector is implemented when a struct is monitoring if a message is rejected
 type Rejector interface {
 	Reject(ctx *snow.Context, containerID ids.ID, container []byte) error
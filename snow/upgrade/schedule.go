@@ -0,0 +1,129 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package upgrade
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	errNoName        = errors.New("upgrade has no name")
+	errDuplicateName = errors.New("duplicate upgrade name")
+	errOutOfOrder    = errors.New("upgrade activates before the upgrade that precedes it")
+)
+
+// Upgrade is a change to the behavior of a VM. It's active for the containers
+// whose height is at least [Height] and whose timestamp isn't before [Time].
+// A zero [Height] or [Time] doesn't constrain the activation.
+type Upgrade struct {
+	Name   string    `json:"name"`
+	Height uint64    `json:"height"`
+	Time   time.Time `json:"time"`
+}
+
+// ActiveAt returns true if this upgrade is active for a container at [height]
+// with timestamp [timestamp]
+func (u *Upgrade) ActiveAt(height uint64, timestamp time.Time) bool {
+	return height >= u.Height && !timestamp.Before(u.Time)
+}
+
+// Schedule is the ordered list of upgrades of a chain. Each upgrade activates
+// no earlier than the upgrade that precedes it, so the upgrades that are
+// active for a container are always a prefix of the schedule.
+type Schedule []Upgrade
+
+// scheduleConfig is the part of the upgrade config of a chain that holds its
+// schedule. The rest of the config is left to the VM.
+type scheduleConfig struct {
+	Upgrades Schedule `json:"upgrades"`
+}
+
+// Parse returns the schedule in the upgrade config [upgradeBytes] of a chain.
+// Returns an empty schedule if [upgradeBytes] is empty.
+func Parse(upgradeBytes []byte) (Schedule, error) {
+	if len(upgradeBytes) == 0 {
+		return nil, nil
+	}
+	config := scheduleConfig{}
+	if err := json.Unmarshal(upgradeBytes, &config); err != nil {
+		return nil, fmt.Errorf("couldn't parse upgrade schedule: %w", err)
+	}
+	if err := config.Upgrades.Verify(); err != nil {
+		return nil, err
+	}
+	return config.Upgrades, nil
+}
+
+// Verify returns an error if the upgrades of this schedule don't have unique
+// names or don't activate in order
+func (s Schedule) Verify() error {
+	names := make(map[string]struct{}, len(s))
+	for i, u := range s {
+		if u.Name == "" {
+			return fmt.Errorf("%w at index %d", errNoName, i)
+		}
+		if _, exists := names[u.Name]; exists {
+			return fmt.Errorf("%w: %s", errDuplicateName, u.Name)
+		}
+		names[u.Name] = struct{}{}
+
+		if i == 0 {
+			continue
+		}
+		prev := s[i-1]
+		if u.Height < prev.Height || u.Time.Before(prev.Time) {
+			return fmt.Errorf("%w: %s activates before %s", errOutOfOrder, u.Name, prev.Name)
+		}
+	}
+	return nil
+}
+
+// Rules returns the rules that are active for a container at [height] with
+// timestamp [timestamp]
+func (s Schedule) Rules(height uint64, timestamp time.Time) Rules {
+	active := 0
+	for active < len(s) && s[active].ActiveAt(height, timestamp) {
+		active++
+	}
+	return Rules{upgrades: s[:active]}
+}
+
+// Rules are the upgrades that are active for a container
+type Rules struct {
+	upgrades Schedule
+}
+
+// IsActive returns true if the upgrade named [name] is active
+func (r Rules) IsActive(name string) bool {
+	for _, u := range r.upgrades {
+		if u.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Latest returns the name of the most recent active upgrade. Returns the
+// empty string if no upgrade is active.
+func (r Rules) Latest() string {
+	if len(r.upgrades) == 0 {
+		return ""
+	}
+	return r.upgrades[len(r.upgrades)-1].Name
+}
+
+// Len returns the number of active upgrades
+func (r Rules) Len() int { return len(r.upgrades) }
+
+func (r Rules) String() string {
+	names := make([]string, len(r.upgrades))
+	for i, u := range r.upgrades {
+		names[i] = u.Name
+	}
+	return fmt.Sprintf("Rules(%s)", strings.Join(names, ", "))
+}
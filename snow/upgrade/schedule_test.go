@@ -0,0 +1,111 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package upgrade
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	schedule, err := Parse([]byte(`{
+		"vmSpecific": true,
+		"upgrades": [
+			{"name": "first", "height": 10},
+			{"name": "second", "height": 10, "time": "2021-06-01T00:00:00Z"}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 2 {
+		t.Fatalf("expected 2 upgrades but got %d", len(schedule))
+	}
+	if schedule[1].Name != "second" || schedule[1].Height != 10 {
+		t.Fatalf("wrong upgrade parsed: %+v", schedule[1])
+	}
+
+	schedule, err = Parse(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 0 {
+		t.Fatal("an empty config should have an empty schedule")
+	}
+
+	if _, err := Parse([]byte("not json")); err == nil {
+		t.Fatal("should have failed to parse an invalid config")
+	}
+}
+
+func TestScheduleVerify(t *testing.T) {
+	june := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	july := time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		err      error
+	}{
+		{
+			name: "valid",
+			schedule: Schedule{
+				{Name: "a", Height: 1, Time: june},
+				{Name: "b", Height: 1, Time: july},
+				{Name: "c", Height: 5, Time: july},
+			},
+		},
+		{
+			name:     "no name",
+			schedule: Schedule{{Height: 1}},
+			err:      errNoName,
+		},
+		{
+			name:     "duplicate name",
+			schedule: Schedule{{Name: "a"}, {Name: "a", Height: 1}},
+			err:      errDuplicateName,
+		},
+		{
+			name:     "height out of order",
+			schedule: Schedule{{Name: "a", Height: 2}, {Name: "b", Height: 1}},
+			err:      errOutOfOrder,
+		},
+		{
+			name:     "time out of order",
+			schedule: Schedule{{Name: "a", Time: july}, {Name: "b", Height: 10, Time: june}},
+			err:      errOutOfOrder,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.schedule.Verify(); !errors.Is(err, test.err) {
+				t.Fatalf("expected %v but got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestScheduleRules(t *testing.T) {
+	june := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	schedule := Schedule{
+		{Name: "byHeight", Height: 10},
+		{Name: "byTime", Height: 10, Time: june},
+	}
+
+	rules := schedule.Rules(9, june)
+	if rules.Len() != 0 || rules.Latest() != "" {
+		t.Fatalf("no upgrade should be active but got %s", rules)
+	}
+
+	rules = schedule.Rules(10, june.Add(-time.Second))
+	if !rules.IsActive("byHeight") || rules.IsActive("byTime") {
+		t.Fatalf("only byHeight should be active but got %s", rules)
+	}
+
+	rules = schedule.Rules(10, june)
+	if !rules.IsActive("byTime") || rules.Latest() != "byTime" || rules.Len() != 2 {
+		t.Fatalf("both upgrades should be active but got %s", rules)
+	}
+}
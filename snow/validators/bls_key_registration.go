@@ -0,0 +1,85 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+// Prefix of the message that a node signs with its staking key to register a
+// BLS public key, so that the signature can't be used for anything else
+var blsKeyRegistrationPrefix = []byte("avalanche bls key registration:")
+
+var errInvalidRegistrationSig = errors.New("BLS key registration isn't signed by the node's staking key")
+
+// BLSKeyRegistration binds a BLS public key to a node. It is signed with the
+// node's staking key, which the node's ID is derived from, so only the node
+// can register a key for itself.
+type BLSKeyRegistration struct {
+	// Staking certificate of the node
+	Certificate []byte `serialize:"true" json:"certificate"`
+	// BLS public key of the node
+	PublicKey []byte `serialize:"true" json:"publicKey"`
+	// Proves that the node possesses the BLS secret key of [PublicKey]
+	ProofOfPossession []byte `serialize:"true" json:"proofOfPossession"`
+	// Signature of [PublicKey] by the node's staking key
+	Signature []byte `serialize:"true" json:"signature"`
+}
+
+// NewBLSKeyRegistration returns the registration of the public key of [sk] by
+// the node whose staking certificate is [cert] and staking key is
+// [stakingKey]
+func NewBLSKeyRegistration(cert *x509.Certificate, stakingKey crypto.Signer, sk *bls.SecretKey) (*BLSKeyRegistration, error) {
+	pkBytes := sk.PublicKey().Bytes()
+	msg := blsKeyRegistrationMsg(pkBytes)
+	sig, err := stakingKey.Sign(rand.Reader, hashing.ComputeHash256(msg), crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't sign BLS key registration: %w", err)
+	}
+	return &BLSKeyRegistration{
+		Certificate:       cert.Raw,
+		PublicKey:         pkBytes,
+		ProofOfPossession: sk.SignProofOfPossession().Bytes(),
+		Signature:         sig,
+	}, nil
+}
+
+// Verify returns the ID of the node that registered the public key, and the
+// public key, if the registration is valid
+func (r *BLSKeyRegistration) Verify() (ids.NodeID, *bls.PublicKey, error) {
+	cert, err := x509.ParseCertificate(r.Certificate)
+	if err != nil {
+		return ids.NodeID{}, nil, fmt.Errorf("couldn't parse staking certificate: %w", err)
+	}
+	if err := cert.CheckSignature(cert.SignatureAlgorithm, blsKeyRegistrationMsg(r.PublicKey), r.Signature); err != nil {
+		return ids.NodeID{}, nil, errInvalidRegistrationSig
+	}
+	pk, err := bls.PublicKeyFromBytes(r.PublicKey)
+	if err != nil {
+		return ids.NodeID{}, nil, err
+	}
+	pop, err := bls.SignatureFromBytes(r.ProofOfPossession)
+	if err != nil {
+		return ids.NodeID{}, nil, err
+	}
+	if !bls.VerifyProofOfPossession(pk, pop) {
+		return ids.NodeID{}, nil, errInvalidProofOfPossession
+	}
+	nodeID, err := ids.ToNodeID(hashing.PubkeyBytesToAddress(cert.Raw))
+	return nodeID, pk, err
+}
+
+func blsKeyRegistrationMsg(pkBytes []byte) []byte {
+	msg := make([]byte, 0, len(blsKeyRegistrationPrefix)+len(pkBytes))
+	msg = append(msg, blsKeyRegistrationPrefix...)
+	return append(msg, pkBytes...)
+}
@@ -0,0 +1,57 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+func TestBLSKeyRegistration(t *testing.T) {
+	cert, err := staking.NewTLSCert()
+	assert.NoError(t, err)
+	stakingKey := cert.PrivateKey.(crypto.Signer)
+	sk, err := bls.NewSecretKey()
+	assert.NoError(t, err)
+
+	registration, err := NewBLSKeyRegistration(cert.Leaf, stakingKey, sk)
+	assert.NoError(t, err)
+	nodeID, pk, err := registration.Verify()
+	assert.NoError(t, err)
+	expectedNodeID, err := ids.ToNodeID(hashing.PubkeyBytesToAddress(cert.Leaf.Raw))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedNodeID, nodeID)
+	assert.Equal(t, sk.PublicKey().Bytes(), pk.Bytes())
+
+	// Another node can't register the key in this node's name
+	otherCert, err := staking.NewTLSCert()
+	assert.NoError(t, err)
+	stolen := *registration
+	stolen.Certificate = otherCert.Leaf.Raw
+	_, _, err = stolen.Verify()
+	assert.Error(t, err)
+
+	// The registered key must be the one that was signed
+	otherSK, err := bls.NewSecretKey()
+	assert.NoError(t, err)
+	swapped := *registration
+	swapped.PublicKey = otherSK.PublicKey().Bytes()
+	swapped.ProofOfPossession = otherSK.SignProofOfPossession().Bytes()
+	_, _, err = swapped.Verify()
+	assert.Error(t, err)
+
+	// The node must possess the registered key
+	unpossessed, err := NewBLSKeyRegistration(cert.Leaf, stakingKey, sk)
+	assert.NoError(t, err)
+	unpossessed.ProofOfPossession = otherSK.SignProofOfPossession().Bytes()
+	_, _, err = unpossessed.Verify()
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+)
+
+var (
+	errInvalidProofOfPossession = errors.New("invalid proof of possession")
+	errInvalidAggregate         = errors.New("invalid aggregate signature")
+)
+
+// BLSKeys holds the BLS public key registered by each node
+type BLSKeys interface {
+	// Register [pk] as the BLS public key of [nodeID], replacing any key that
+	// was previously registered. [pop] must be the proof of possession of
+	// [pk]. A key can only be registered by one node at a time.
+	Register(nodeID ids.NodeID, pk *bls.PublicKey, pop *bls.Signature) error
+
+	// Set is Register without verifying the proof of possession, for keys
+	// whose proof of possession was already verified, such as keys registered
+	// on the platform chain
+	Set(nodeID ids.NodeID, pk *bls.PublicKey) error
+
+	// Deregister removes the BLS public key of [nodeID], if there is one
+	Deregister(nodeID ids.NodeID)
+
+	// Get returns the BLS public key of [nodeID]
+	Get(nodeID ids.NodeID) (*bls.PublicKey, bool)
+
+	// Aggregate returns the aggregate of the BLS public keys of [nodeIDs].
+	// Returns an error if a node hasn't registered a key.
+	Aggregate(nodeIDs ids.NodeIDSet) (*bls.PublicKey, error)
+
+	// Verify returns nil if [sig] is the aggregate of the signatures of [msg]
+	// by each of [nodeIDs]
+	Verify(nodeIDs ids.NodeIDSet, msg []byte, sig *bls.Signature) error
+}
+
+// NewBLSKeys returns a new, empty BLS key registry
+func NewBLSKeys() BLSKeys {
+	return &blsKeys{
+		keys:   make(map[ids.NodeID]*bls.PublicKey),
+		owners: make(map[string]ids.NodeID),
+	}
+}
+
+type blsKeys struct {
+	lock sync.RWMutex
+
+	// Key: Node ID
+	// Value: The node's BLS public key
+	keys map[ids.NodeID]*bls.PublicKey
+
+	// Key: Bytes of a BLS public key
+	// Value: The node that registered the key
+	owners map[string]ids.NodeID
+}
+
+// Register implements the BLSKeys interface.
+func (k *blsKeys) Register(nodeID ids.NodeID, pk *bls.PublicKey, pop *bls.Signature) error {
+	if !bls.VerifyProofOfPossession(pk, pop) {
+		return errInvalidProofOfPossession
+	}
+	return k.Set(nodeID, pk)
+}
+
+// Set implements the BLSKeys interface.
+func (k *blsKeys) Set(nodeID ids.NodeID, pk *bls.PublicKey) error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	pkStr := string(pk.Bytes())
+	if owner, ok := k.owners[pkStr]; ok && owner != nodeID {
+		return fmt.Errorf("BLS public key is already registered by %s", owner)
+	}
+	k.deregister(nodeID)
+	k.keys[nodeID] = pk
+	k.owners[pkStr] = nodeID
+	return nil
+}
+
+// Deregister implements the BLSKeys interface.
+func (k *blsKeys) Deregister(nodeID ids.NodeID) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	k.deregister(nodeID)
+}
+
+func (k *blsKeys) deregister(nodeID ids.NodeID) {
+	pk, ok := k.keys[nodeID]
+	if !ok {
+		return
+	}
+	delete(k.keys, nodeID)
+	delete(k.owners, string(pk.Bytes()))
+}
+
+// Get implements the BLSKeys interface.
+func (k *blsKeys) Get(nodeID ids.NodeID) (*bls.PublicKey, bool) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+
+	pk, ok := k.keys[nodeID]
+	return pk, ok
+}
+
+// Aggregate implements the BLSKeys interface.
+func (k *blsKeys) Aggregate(nodeIDs ids.NodeIDSet) (*bls.PublicKey, error) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+
+	pks := make([]*bls.PublicKey, 0, nodeIDs.Len())
+	for nodeID := range nodeIDs {
+		pk, ok := k.keys[nodeID]
+		if !ok {
+			return nil, fmt.Errorf("%s hasn't registered a BLS public key", nodeID)
+		}
+		pks = append(pks, pk)
+	}
+	return bls.AggregatePublicKeys(pks)
+}
+
+// Verify implements the BLSKeys interface.
+func (k *blsKeys) Verify(nodeIDs ids.NodeIDSet, msg []byte, sig *bls.Signature) error {
+	pk, err := k.Aggregate(nodeIDs)
+	if err != nil {
+		return err
+	}
+	if !bls.Verify(pk, sig, msg) {
+		return errInvalidAggregate
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+)
+
+func TestBLSKeys(t *testing.T) {
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+
+	sk0, err := bls.NewSecretKey()
+	assert.NoError(t, err)
+	sk1, err := bls.NewSecretKey()
+	assert.NoError(t, err)
+	pk0 := sk0.PublicKey()
+	pk1 := sk1.PublicKey()
+
+	keys := NewBLSKeys()
+
+	// The proof of possession must match the key
+	assert.Error(t, keys.Register(vdr0, pk0, sk1.SignProofOfPossession()))
+	assert.NoError(t, keys.Register(vdr0, pk0, sk0.SignProofOfPossession()))
+	// A key can't be registered by two nodes
+	assert.Error(t, keys.Register(vdr1, pk0, sk0.SignProofOfPossession()))
+	assert.NoError(t, keys.Register(vdr1, pk1, sk1.SignProofOfPossession()))
+
+	pk, ok := keys.Get(vdr0)
+	assert.True(t, ok)
+	assert.Equal(t, pk0.Bytes(), pk.Bytes())
+
+	msg := []byte("accepted")
+	sig, err := bls.AggregateSignatures([]*bls.Signature{sk0.Sign(msg), sk1.Sign(msg)})
+	assert.NoError(t, err)
+
+	signers := ids.NodeIDSet{}
+	signers.Add(vdr0, vdr1)
+	assert.NoError(t, keys.Verify(signers, msg, sig))
+	assert.Error(t, keys.Verify(signers, []byte("rejected"), sig))
+
+	signers.Remove(vdr1)
+	assert.Error(t, keys.Verify(signers, msg, sig))
+
+	// Once deregistered, a node can't be part of an aggregate
+	keys.Deregister(vdr1)
+	_, ok = keys.Get(vdr1)
+	assert.False(t, ok)
+	signers.Add(vdr1)
+	assert.Error(t, keys.Verify(signers, msg, sig))
+
+	// The deregistered key can be registered by another node
+	assert.NoError(t, keys.Register(ids.GenerateTestNodeID(), pk1, sk1.SignProofOfPossession()))
+}
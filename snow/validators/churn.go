@@ -0,0 +1,161 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+// churnDrainSteps is the number of times per churn period that a churn limited
+// set applies the changes it deferred
+const churnDrainSteps = 10
+
+// churnBudget is a token bucket that bounds how much weight may be added to
+// or removed from a validator set.
+type churnBudget struct {
+	// Weight that may currently be added or removed
+	available float64
+	// Last time [available] was refilled
+	lastRefill time.Time
+}
+
+// churnLimitedSet is a view of the validator set [source] whose weight changes
+// by at most [maxChurn] times its weight per [churnPeriod]. The changes that
+// exceed the limit are deferred, and applied by a timer as the budget refills.
+type churnLimitedSet struct {
+	// The validators of the view
+	vdrs Set
+
+	lock sync.Mutex
+	// Used to get time. Useful for faking time during tests.
+	clock timer.Clock
+
+	source      Set
+	maxChurn    float64
+	churnPeriod time.Duration
+	budget      *churnBudget
+	// Non-nil if the deferred changes are scheduled to be applied
+	drainTimer *time.Timer
+}
+
+// update moves the validators of the view towards those of [s.source], as far
+// as the churn budget allows, and schedules the changes that don't fit in the
+// budget to be applied later.
+func (s *churnLimitedSet) update() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	target := s.source.List()
+	limited, deferred := s.limitChurn(target)
+	if err := s.vdrs.Set(limited); err != nil {
+		return err
+	}
+	if deferred && s.drainTimer == nil {
+		s.drainTimer = time.AfterFunc(s.churnPeriod/churnDrainSteps, s.drain)
+	}
+	return nil
+}
+
+// drain applies the changes that were deferred, as far as the churn budget
+// allows
+func (s *churnLimitedSet) drain() {
+	s.lock.Lock()
+	s.drainTimer = nil
+	s.lock.Unlock()
+
+	// The only errors are from overflowing the weight of the set, which
+	// [s.source] would have already overflowed
+	_ = s.update()
+}
+
+// limitChurn returns the validators that the view should be changed to in
+// order to move towards [target] without exceeding the churn budget, and
+// whether some changes were left for future calls.
+// Assumes [s.lock] is held.
+func (s *churnLimitedSet) limitChurn(target []Validator) ([]Validator, bool) {
+	// An empty set is populated immediately, as there is no existing set
+	// whose security could be undermined.
+	weight := s.vdrs.Weight()
+	if weight == 0 {
+		return target, false
+	}
+	// The budget is a fraction of the set's current weight
+	capacity := s.maxChurn * float64(weight)
+
+	now := s.clock.Time()
+	if s.budget == nil {
+		s.budget = &churnBudget{
+			available:  capacity,
+			lastRefill: now,
+		}
+	}
+	if elapsed := now.Sub(s.budget.lastRefill); elapsed > 0 {
+		s.budget.available += capacity * float64(elapsed) / float64(s.churnPeriod)
+		s.budget.lastRefill = now
+	}
+	if s.budget.available > capacity {
+		s.budget.available = capacity
+	}
+
+	oldVdrs := s.vdrs.List()
+	oldWeights := make(map[ids.NodeID]uint64, len(oldVdrs))
+	for _, vdr := range oldVdrs {
+		oldWeights[vdr.ID()] = vdr.Weight()
+	}
+
+	deferred := false
+	limited := make([]Validator, 0, len(target)+len(oldVdrs))
+	targetIDs := ids.NodeIDSet{}
+	for _, vdr := range target {
+		vdrID := vdr.ID()
+		if targetIDs.Contains(vdrID) {
+			continue
+		}
+		targetIDs.Add(vdrID)
+		newWeight := s.budget.move(oldWeights[vdrID], vdr.Weight())
+		deferred = deferred || newWeight != vdr.Weight()
+		limited = append(limited, NewValidator(vdrID, newWeight))
+	}
+	// Validators that are leaving the set
+	for _, vdr := range oldVdrs {
+		vdrID := vdr.ID()
+		if targetIDs.Contains(vdrID) {
+			continue
+		}
+		newWeight := s.budget.move(vdr.Weight(), 0)
+		deferred = deferred || newWeight != 0
+		limited = append(limited, NewValidator(vdrID, newWeight))
+	}
+	return limited, deferred
+}
+
+// move returns the weight closest to [newWeight] that can be reached from
+// [oldWeight] with the available budget, and consumes the budget used.
+func (b *churnBudget) move(oldWeight, newWeight uint64) uint64 {
+	if oldWeight == newWeight {
+		return newWeight
+	}
+
+	var diff uint64
+	if newWeight > oldWeight {
+		diff = newWeight - oldWeight
+	} else {
+		diff = oldWeight - newWeight
+	}
+	if float64(diff) <= b.available {
+		b.available -= float64(diff)
+		return newWeight
+	}
+
+	allowed := uint64(b.available)
+	b.available -= float64(allowed)
+	if newWeight > oldWeight {
+		return oldWeight + allowed
+	}
+	return oldWeight - allowed
+}
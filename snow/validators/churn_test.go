@@ -0,0 +1,116 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestChurnLimitedValidators(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+	vdr2 := ids.GenerateTestNodeID()
+
+	m := NewManager().(*manager)
+
+	// The view starts with the subnet's current validators
+	vdrs := NewSet()
+	assert.NoError(t, vdrs.AddWeight(vdr0, 100))
+	assert.NoError(t, vdrs.AddWeight(vdr1, 100))
+	assert.NoError(t, m.Set(subnetID, vdrs))
+	limited, err := m.GetChurnLimitedValidators(subnetID, .1, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(200), limited.Weight())
+
+	view := m.views[subnetID][0]
+	now := time.Unix(0, 0)
+	view.clock.Set(now)
+
+	// Only 10% of the weight can be added per hour, but the subnet's
+	// validator set isn't limited
+	target := NewSet()
+	assert.NoError(t, target.AddWeight(vdr0, 100))
+	assert.NoError(t, target.AddWeight(vdr1, 100))
+	assert.NoError(t, target.AddWeight(vdr2, 50))
+	assert.NoError(t, m.Set(subnetID, target))
+	running, _ := m.GetValidators(subnetID)
+	assert.Equal(t, uint64(250), running.Weight())
+	weight, _ := limited.GetWeight(vdr2)
+	assert.Equal(t, uint64(20), weight)
+
+	// The budget refills over time
+	now = now.Add(30 * time.Minute)
+	view.clock.Set(now)
+	assert.NoError(t, view.update())
+	weight, _ = limited.GetWeight(vdr2)
+	assert.Equal(t, uint64(31), weight)
+
+	now = now.Add(2 * time.Hour)
+	view.clock.Set(now)
+	assert.NoError(t, view.update())
+	weight, _ = limited.GetWeight(vdr2)
+	assert.Equal(t, uint64(50), weight)
+
+	// Removals, including those made with RemoveWeight, are limited the same
+	// way
+	now = now.Add(2 * time.Hour)
+	view.clock.Set(now)
+	assert.NoError(t, m.RemoveWeight(subnetID, vdr1, 100))
+	weight, _ = limited.GetWeight(vdr1)
+	assert.Equal(t, uint64(75), weight)
+	assert.False(t, running.Contains(vdr1))
+}
+
+func TestChurnLimitedValidatorsDrain(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+
+	m := NewManager()
+	vdrs := NewSet()
+	assert.NoError(t, vdrs.AddWeight(vdr0, 100))
+	assert.NoError(t, m.Set(subnetID, vdrs))
+	limited, err := m.GetChurnLimitedValidators(subnetID, .5, 100*time.Millisecond)
+	assert.NoError(t, err)
+
+	// The change is deferred, and then applied without any further changes
+	// to the subnet's validators
+	assert.NoError(t, m.AddWeight(subnetID, vdr1, 100))
+	weight, _ := limited.GetWeight(vdr1)
+	assert.Equal(t, uint64(50), weight)
+	assert.Eventually(t, func() bool {
+		weight, _ := limited.GetWeight(vdr1)
+		return weight == 100
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestChurnLimitedValidatorsSamplingParams(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	vdr0 := ids.GenerateTestNodeID()
+
+	m := NewManager()
+	vdrs := NewSet()
+	assert.NoError(t, vdrs.AddWeight(vdr0, 100))
+	assert.NoError(t, m.Set(subnetID, vdrs))
+	assert.NoError(t, m.MaskValidator(vdr0))
+
+	// The only validator is masked, so it can't be sampled
+	limited, err := m.GetChurnLimitedValidators(subnetID, .1, time.Hour)
+	assert.NoError(t, err)
+	_, err = limited.Sample(1)
+	assert.Error(t, err)
+
+	assert.NoError(t, m.RevealValidator(vdr0))
+	_, err = limited.Sample(1)
+	assert.NoError(t, err)
+
+	_, err = m.GetChurnLimitedValidators(ids.GenerateTestID(), .1, time.Hour)
+	assert.Equal(t, errUnknownSubnet, err)
+}
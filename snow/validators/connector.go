@@ -8,7 +8,10 @@ import (
 )
 
 // Connector represents a handler that is called when a connection is marked as
-// connected or disconnected
+// connected or disconnected.
+//
+// Connector is part of the VM API, which VMs outside of this repository
+// implement, so it still identifies nodes by their ShortIDs.
 type Connector interface {
 	Connected(id ids.ShortID) error
 	Disconnected(id ids.ShortID) error
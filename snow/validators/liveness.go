@@ -0,0 +1,72 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var errDuplicateLivenessSource = errors.New("duplicate liveness source")
+
+// LivenessSource reports whether validators are up, according to something
+// other than this node's connections to them, e.g. whether they have recently
+// participated in a chain.
+//
+// Like Connector, LivenessSource is implemented by VMs, so it still identifies
+// nodes by their ShortIDs.
+type LivenessSource interface {
+	// IsUp returns true if [nodeID] is currently considered to be up
+	IsUp(nodeID ids.ShortID) bool
+}
+
+// LivenessSources is the set of named sources that the platform chain samples
+// into validators' uptime histories, in addition to its own connections.
+type LivenessSources interface {
+	// Register [source] under [name]. Returns an error if [name] is already
+	// registered.
+	Register(name string, source LivenessSource) error
+
+	// List returns the registered sources, by name
+	List() map[string]LivenessSource
+}
+
+// NewLivenessSources returns a new, empty, LivenessSources
+func NewLivenessSources() LivenessSources {
+	return &livenessSources{
+		sources: make(map[string]LivenessSource),
+	}
+}
+
+type livenessSources struct {
+	lock    sync.RWMutex
+	sources map[string]LivenessSource
+}
+
+// Register implements the LivenessSources interface
+func (s *livenessSources) Register(name string, source LivenessSource) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, exists := s.sources[name]; exists {
+		return fmt.Errorf("%w: %s", errDuplicateLivenessSource, name)
+	}
+	s.sources[name] = source
+	return nil
+}
+
+// List implements the LivenessSources interface
+func (s *livenessSources) List() map[string]LivenessSource {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	sources := make(map[string]LivenessSource, len(s.sources))
+	for name, source := range s.sources {
+		sources[name] = source
+	}
+	return sources
+}
@@ -4,66 +4,173 @@
 package validators
 
 import (
+	"errors"
 	"sync"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 )
 
+var errUnknownSubnet = errors.New("unknown subnet")
+
 // Manager holds the validator set of each subnet
 type Manager interface {
-	// Set a subnet's validator set
+	// Set a subnet's validator set. If the subnet already has a validator set,
+	// its validators are replaced in place, so holders of the set returned by
+	// GetValidators, such as running consensus engines, atomically observe the
+	// new validators.
 	Set(ids.ID, Set) error
 
 	// AddWeight adds weight to a given validator on the given subnet
-	AddWeight(ids.ID, ids.ShortID, uint64) error
+	AddWeight(ids.ID, ids.NodeID, uint64) error
 
 	// RemoveWeight removes weight from a given validator on a given subnet
-	RemoveWeight(ids.ID, ids.ShortID, uint64) error
+	RemoveWeight(ids.ID, ids.NodeID, uint64) error
 
 	// GetValidators returns the validator set for the given subnet
 	// Returns false if the subnet doesn't exist
 	GetValidators(ids.ID) (Set, bool)
 
+	// GetChurnLimitedValidators returns a new view of the validator set of
+	// [subnetID] whose weight changes by at most [maxChurn] times its weight
+	// per [churnPeriod]. Changes that exceed the limit are deferred, and
+	// applied over time as the limit allows. The view starts with the
+	// subnet's current validators and is sampled with the same settings as
+	// the subnet's validator set. Returns an error if the subnet doesn't
+	// exist.
+	GetChurnLimitedValidators(subnetID ids.ID, maxChurn float64, churnPeriod time.Duration) (Set, error)
+
 	// MaskValidator hides the named validator from future samplings
-	MaskValidator(ids.ShortID) error
+	MaskValidator(ids.NodeID) error
 
 	// RevealValidator ensures the named validator is not hidden from future
 	// samplings
-	RevealValidator(ids.ShortID) error
+	RevealValidator(ids.NodeID) error
+
+	// Subscribe registers [subscriber] to be notified of every change to the
+	// validator sets of the subnets. Subscribers are notified in the order of
+	// the changes, after the Manager is unlocked.
+	Subscribe(subscriber Subscriber)
+
+	// Unsubscribe stops notifying [subscriber]
+	Unsubscribe(subscriber Subscriber)
+
+	// SetSamplingCaps sets the caps applied to the weights of the validators
+	// of the given subnet when they are sampled
+	SetSamplingCaps(ids.ID, SamplingCaps) error
+
+	// SetSamplingPenalty reduces the weight that the named validator is
+	// sampled with in every subnet by the fraction [penalty], which must be in
+	// [0, 1)
+	SetSamplingPenalty(ids.NodeID, float64) error
+
+	// SetDistinctSampling makes the validator sets of every subnet return
+	// distinct validators when sampled, if they have at most [maxSetSize]
+	// validators
+	SetDistinctSampling(maxSetSize int)
+
+	// SetValidatorGroup sets the group of the named validator in the
+	// validator sets of every subnet. If [group] is empty, the validator is
+	// removed from its group.
+	SetValidatorGroup(vdrID ids.NodeID, group string)
+
+	// SetMaxGroupFraction limits the validators of one group to the fraction
+	// [fraction] of each sample from the validator sets of every subnet
+	SetMaxGroupFraction(fraction float64) error
 }
 
 // NewManager returns a new, empty manager
 func NewManager() Manager {
 	return &manager{
 		subnetToVdrs: make(map[ids.ID]Set),
+		samplingCaps: make(map[ids.ID]SamplingCaps),
+		penalties:    make(map[ids.NodeID]float64),
+		groups:       make(map[ids.NodeID]string),
+		views:        make(map[ids.ID][]*churnLimitedSet),
 	}
 }
 
 // manager implements Manager
 type manager struct {
 	lock sync.Mutex
+	// Held while the subscribers are notified, so that they're notified of
+	// the changes in order. Acquired before [lock] if both are held.
+	notifyLock sync.Mutex
 
 	// Key: Subnet ID
 	// Value: The validators that validate the subnet
 	subnetToVdrs map[ids.ID]Set
 
-	maskedVdrs ids.ShortSet
+	maskedVdrs ids.NodeIDSet
+
+	subscribers []Subscriber
+	// Notifications of the changes that the subscribers haven't been notified
+	// of yet, in the order of the changes
+	pending []func(Subscriber)
+
+	// Key: Subnet ID
+	// Value: The caps applied when sampling the subnet's validators
+	samplingCaps map[ids.ID]SamplingCaps
+
+	// Validator ID --> Sampling penalty of the validator
+	penalties map[ids.NodeID]float64
+
+	// Validator sets with at most this many validators are sampled without
+	// duplicates
+	maxDistinctSetSize int
+
+	// Validator ID --> Group of the validator
+	groups map[ids.NodeID]string
+
+	// Max fraction of a sample that can be from the same group
+	maxGroupFraction float64
+
+	// Key: Subnet ID
+	// Value: The churn limited views of the subnet's validators
+	views map[ids.ID][]*churnLimitedSet
 }
 
 func (m *manager) Set(subnetID ids.ID, newSet Set) error {
+	// The subscribers are notified after [m.lock] is released
+	defer m.notify()
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
 	oldSet, exists := m.subnetToVdrs[subnetID]
 	if !exists {
+		if err := m.applySamplingParams(subnetID, newSet); err != nil {
+			return err
+		}
 		m.subnetToVdrs[subnetID] = newSet
+		for _, vdr := range newSet.List() {
+			m.notifyAdded(subnetID, vdr.ID(), vdr.Weight())
+		}
 		return nil
 	}
-	return oldSet.Set(newSet.List())
+
+	oldWeights := make(map[ids.NodeID]uint64, oldSet.Len())
+	for _, vdr := range oldSet.List() {
+		oldWeights[vdr.ID()] = vdr.Weight()
+	}
+	if err := oldSet.Set(newSet.List()); err != nil {
+		return err
+	}
+	for _, vdr := range oldSet.List() {
+		vdrID := vdr.ID()
+		oldWeight, existed := oldWeights[vdrID]
+		delete(oldWeights, vdrID)
+		m.notifyChanged(subnetID, vdrID, oldWeight, existed, vdr.Weight(), true)
+	}
+	for vdrID, oldWeight := range oldWeights {
+		m.notifyRemoved(subnetID, vdrID, oldWeight)
+	}
+	return m.updateViews(subnetID)
 }
 
 // AddWeight implements the Manager interface.
-func (m *manager) AddWeight(subnetID ids.ID, vdrID ids.ShortID, weight uint64) error {
+func (m *manager) AddWeight(subnetID ids.ID, vdrID ids.NodeID, weight uint64) error {
+	// The subscribers are notified after [m.lock] is released
+	defer m.notify()
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -75,20 +182,40 @@ func (m *manager) AddWeight(subnetID ids.ID, vdrID ids.ShortID, weight uint64) e
 				return err
 			}
 		}
+		if err := m.applySamplingParams(subnetID, vdrs); err != nil {
+			return err
+		}
 		m.subnetToVdrs[subnetID] = vdrs
 	}
-	return vdrs.AddWeight(vdrID, weight)
+
+	oldWeight, existed := weightOf(vdrs, vdrID)
+	if err := vdrs.AddWeight(vdrID, weight); err != nil {
+		return err
+	}
+	newWeight, exists := weightOf(vdrs, vdrID)
+	m.notifyChanged(subnetID, vdrID, oldWeight, existed, newWeight, exists)
+	return m.updateViews(subnetID)
 }
 
 // RemoveValidatorSet implements the Manager interface.
-func (m *manager) RemoveWeight(subnetID ids.ID, vdrID ids.ShortID, weight uint64) error {
+func (m *manager) RemoveWeight(subnetID ids.ID, vdrID ids.NodeID, weight uint64) error {
+	// The subscribers are notified after [m.lock] is released
+	defer m.notify()
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
-	if vdrs, ok := m.subnetToVdrs[subnetID]; ok {
-		return vdrs.RemoveWeight(vdrID, weight)
+	vdrs, ok := m.subnetToVdrs[subnetID]
+	if !ok {
+		return nil
 	}
-	return nil
+
+	oldWeight, existed := weightOf(vdrs, vdrID)
+	if err := vdrs.RemoveWeight(vdrID, weight); err != nil {
+		return err
+	}
+	newWeight, exists := weightOf(vdrs, vdrID)
+	m.notifyChanged(subnetID, vdrID, oldWeight, existed, newWeight, exists)
+	return m.updateViews(subnetID)
 }
 
 // GetValidatorSet implements the Manager interface.
@@ -100,8 +227,40 @@ func (m *manager) GetValidators(subnetID ids.ID) (Set, bool) {
 	return vdrs, ok
 }
 
+// GetChurnLimitedValidators implements the Manager interface.
+func (m *manager) GetChurnLimitedValidators(subnetID ids.ID, maxChurn float64, churnPeriod time.Duration) (Set, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	source, ok := m.subnetToVdrs[subnetID]
+	if !ok {
+		return nil, errUnknownSubnet
+	}
+
+	vdrs := NewSet()
+	for _, maskedVdrID := range m.maskedVdrs.List() {
+		if err := vdrs.MaskValidator(maskedVdrID); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.applySamplingParams(subnetID, vdrs); err != nil {
+		return nil, err
+	}
+	view := &churnLimitedSet{
+		vdrs:        vdrs,
+		source:      source,
+		maxChurn:    maxChurn,
+		churnPeriod: churnPeriod,
+	}
+	if err := view.update(); err != nil {
+		return nil, err
+	}
+	m.views[subnetID] = append(m.views[subnetID], view)
+	return vdrs, nil
+}
+
 // MaskValidator implements the Manager interface.
-func (m *manager) MaskValidator(vdrID ids.ShortID) error {
+func (m *manager) MaskValidator(vdrID ids.NodeID) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -110,7 +269,7 @@ func (m *manager) MaskValidator(vdrID ids.ShortID) error {
 	}
 	m.maskedVdrs.Add(vdrID)
 
-	for _, vdrs := range m.subnetToVdrs {
+	for _, vdrs := range m.sets() {
 		if err := vdrs.MaskValidator(vdrID); err != nil {
 			return err
 		}
@@ -119,7 +278,7 @@ func (m *manager) MaskValidator(vdrID ids.ShortID) error {
 }
 
 // RevealValidator implements the Manager interface.
-func (m *manager) RevealValidator(vdrID ids.ShortID) error {
+func (m *manager) RevealValidator(vdrID ids.NodeID) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -128,10 +287,233 @@ func (m *manager) RevealValidator(vdrID ids.ShortID) error {
 	}
 	m.maskedVdrs.Remove(vdrID)
 
-	for _, vdrs := range m.subnetToVdrs {
+	for _, vdrs := range m.sets() {
 		if err := vdrs.RevealValidator(vdrID); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// Subscribe implements the Manager interface.
+func (m *manager) Subscribe(subscriber Subscriber) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.subscribers = append(m.subscribers, subscriber)
+}
+
+// Unsubscribe implements the Manager interface.
+func (m *manager) Unsubscribe(subscriber Subscriber) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for i, s := range m.subscribers {
+		if s == subscriber {
+			m.subscribers = append(m.subscribers[:i], m.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetSamplingCaps implements the Manager interface.
+func (m *manager) SetSamplingCaps(subnetID ids.ID, caps SamplingCaps) error {
+	if err := caps.Verify(); err != nil {
+		return err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.samplingCaps[subnetID] = caps
+	if vdrs, ok := m.subnetToVdrs[subnetID]; ok {
+		if err := vdrs.SetSamplingCaps(caps); err != nil {
+			return err
+		}
+	}
+	for _, view := range m.views[subnetID] {
+		if err := view.vdrs.SetSamplingCaps(caps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetSamplingPenalty implements the Manager interface.
+func (m *manager) SetSamplingPenalty(vdrID ids.NodeID, penalty float64) error {
+	if penalty < 0 || penalty >= 1 {
+		return errInvalidSamplingPenalty
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if penalty == 0 {
+		delete(m.penalties, vdrID)
+	} else {
+		m.penalties[vdrID] = penalty
+	}
+	for _, vdrs := range m.sets() {
+		if err := vdrs.SetSamplingPenalty(vdrID, penalty); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetDistinctSampling implements the Manager interface.
+func (m *manager) SetDistinctSampling(maxSetSize int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.maxDistinctSetSize = maxSetSize
+	for _, vdrs := range m.sets() {
+		vdrs.SetDistinctSampling(maxSetSize)
+	}
+}
+
+// SetValidatorGroup implements the Manager interface.
+func (m *manager) SetValidatorGroup(vdrID ids.NodeID, group string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if group == "" {
+		delete(m.groups, vdrID)
+	} else {
+		m.groups[vdrID] = group
+	}
+	for _, vdrs := range m.sets() {
+		vdrs.SetValidatorGroup(vdrID, group)
+	}
+}
+
+// SetMaxGroupFraction implements the Manager interface.
+func (m *manager) SetMaxGroupFraction(fraction float64) error {
+	if fraction < 0 || fraction > 1 {
+		return errInvalidMaxGroupFraction
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.maxGroupFraction = fraction
+	for _, vdrs := range m.sets() {
+		if err := vdrs.SetMaxGroupFraction(fraction); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applySamplingParams applies the sampling caps of [subnetID] and the
+// per-validator and manager-wide sampling settings to [vdrs]. Assumes [m.lock]
+// is held.
+func (m *manager) applySamplingParams(subnetID ids.ID, vdrs Set) error {
+	if caps, ok := m.samplingCaps[subnetID]; ok {
+		if err := vdrs.SetSamplingCaps(caps); err != nil {
+			return err
+		}
+	}
+	for vdrID, penalty := range m.penalties {
+		if err := vdrs.SetSamplingPenalty(vdrID, penalty); err != nil {
+			return err
+		}
+	}
+	vdrs.SetDistinctSampling(m.maxDistinctSetSize)
+	for vdrID, group := range m.groups {
+		vdrs.SetValidatorGroup(vdrID, group)
+	}
+	return vdrs.SetMaxGroupFraction(m.maxGroupFraction)
+}
+
+// updateViews moves the churn limited views of [subnetID]'s validators
+// towards its validators. Assumes [m.lock] is held.
+func (m *manager) updateViews(subnetID ids.ID) error {
+	for _, view := range m.views[subnetID] {
+		if err := view.update(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sets returns the validator sets of every subnet and their churn limited
+// views. Assumes [m.lock] is held.
+func (m *manager) sets() []Set {
+	sets := make([]Set, 0, len(m.subnetToVdrs))
+	for _, vdrs := range m.subnetToVdrs {
+		sets = append(sets, vdrs)
+	}
+	for _, views := range m.views {
+		for _, view := range views {
+			sets = append(sets, view.vdrs)
+		}
+	}
+	return sets
+}
+
+// weightOf returns the weight of [vdrID] in [vdrs], ignoring whether it is
+// masked.
+func weightOf(vdrs Set, vdrID ids.NodeID) (uint64, bool) {
+	vdr, ok := vdrs.Get(vdrID)
+	if !ok {
+		return 0, false
+	}
+	return vdr.Weight(), true
+}
+
+// notifyChanged queues the notification of the change of [vdrID] from
+// [oldWeight] to [newWeight]. [existed] and [exists] are whether [vdrID] was
+// a validator before and after the change. Assumes [m.lock] is held.
+func (m *manager) notifyChanged(
+	subnetID ids.ID,
+	vdrID ids.NodeID,
+	oldWeight uint64,
+	existed bool,
+	newWeight uint64,
+	exists bool,
+) {
+	switch {
+	case !existed && exists:
+		m.notifyAdded(subnetID, vdrID, newWeight)
+	case existed && !exists:
+		m.notifyRemoved(subnetID, vdrID, oldWeight)
+	case existed && exists && oldWeight != newWeight:
+		m.pending = append(m.pending, func(subscriber Subscriber) {
+			subscriber.OnValidatorWeightChanged(subnetID, vdrID, oldWeight, newWeight)
+		})
+	}
+}
+
+// Assumes [m.lock] is held.
+func (m *manager) notifyAdded(subnetID ids.ID, vdrID ids.NodeID, weight uint64) {
+	m.pending = append(m.pending, func(subscriber Subscriber) {
+		subscriber.OnValidatorAdded(subnetID, vdrID, weight)
+	})
+}
+
+// Assumes [m.lock] is held.
+func (m *manager) notifyRemoved(subnetID ids.ID, vdrID ids.NodeID, weight uint64) {
+	m.pending = append(m.pending, func(subscriber Subscriber) {
+		subscriber.OnValidatorRemoved(subnetID, vdrID, weight)
+	})
+}
+
+// notify notifies the subscribers of the pending changes. Subscribers may
+// lock their own state, which may be held while the Manager is called, so
+// they're notified without holding [m.lock].
+// Assumes [m.lock] isn't held.
+func (m *manager) notify() {
+	m.notifyLock.Lock()
+	defer m.notifyLock.Unlock()
+
+	m.lock.Lock()
+	pending := m.pending
+	m.pending = nil
+	subscribers := make([]Subscriber, len(m.subscribers))
+	copy(subscribers, m.subscribers)
+	m.lock.Unlock()
+
+	for _, notification := range pending {
+		for _, subscriber := range subscribers {
+			notification(subscriber)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+type testSubscriber struct {
+	events []string
+}
+
+func (s *testSubscriber) OnValidatorAdded(subnetID ids.ID, validatorID ids.NodeID, weight uint64) {
+	s.events = append(s.events, fmt.Sprintf("added %s %s %d", subnetID, validatorID, weight))
+}
+
+func (s *testSubscriber) OnValidatorRemoved(subnetID ids.ID, validatorID ids.NodeID, weight uint64) {
+	s.events = append(s.events, fmt.Sprintf("removed %s %s %d", subnetID, validatorID, weight))
+}
+
+func (s *testSubscriber) OnValidatorWeightChanged(subnetID ids.ID, validatorID ids.NodeID, oldWeight, newWeight uint64) {
+	s.events = append(s.events, fmt.Sprintf("changed %s %s %d %d", subnetID, validatorID, oldWeight, newWeight))
+}
+
+func TestManagerSubscribe(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+
+	m := NewManager()
+	subscriber := &testSubscriber{}
+	m.Subscribe(subscriber)
+
+	assert.NoError(t, m.AddWeight(subnetID, vdr0, 1))
+	assert.NoError(t, m.AddWeight(subnetID, vdr0, 2))
+	// Masking doesn't change the validator set
+	assert.NoError(t, m.MaskValidator(vdr0))
+	assert.NoError(t, m.RemoveWeight(subnetID, vdr0, 1))
+	assert.NoError(t, m.RemoveWeight(subnetID, vdr0, 2))
+	// Removing weight from a validator that doesn't exist is a no-op
+	assert.NoError(t, m.RemoveWeight(subnetID, vdr1, 1))
+
+	assert.Equal(t, []string{
+		fmt.Sprintf("added %s %s 1", subnetID, vdr0),
+		fmt.Sprintf("changed %s %s 1 3", subnetID, vdr0),
+		fmt.Sprintf("changed %s %s 3 2", subnetID, vdr0),
+		fmt.Sprintf("removed %s %s 2", subnetID, vdr0),
+	}, subscriber.events)
+
+	m.Unsubscribe(subscriber)
+	assert.NoError(t, m.AddWeight(subnetID, vdr0, 1))
+	assert.Len(t, subscriber.events, 4, "shouldn't have been notified after unsubscribing")
+}
+
+func TestManagerSubscribeSet(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+	vdr2 := ids.GenerateTestNodeID()
+
+	m := NewManager()
+	subscriber := &testSubscriber{}
+	m.Subscribe(subscriber)
+
+	vdrs := NewSet()
+	assert.NoError(t, vdrs.AddWeight(vdr0, 1))
+	assert.NoError(t, m.Set(subnetID, vdrs))
+	assert.Equal(t, []string{
+		fmt.Sprintf("added %s %s 1", subnetID, vdr0),
+	}, subscriber.events)
+
+	subscriber.events = nil
+	assert.NoError(t, m.Set(subnetID, NewSet()))
+	assert.Equal(t, []string{
+		fmt.Sprintf("removed %s %s 1", subnetID, vdr0),
+	}, subscriber.events)
+
+	subscriber.events = nil
+	vdrs = NewSet()
+	assert.NoError(t, vdrs.AddWeight(vdr1, 2))
+	assert.NoError(t, m.Set(subnetID, vdrs))
+	vdrs = NewSet()
+	assert.NoError(t, vdrs.AddWeight(vdr1, 3))
+	assert.NoError(t, vdrs.AddWeight(vdr2, 4))
+	assert.NoError(t, m.Set(subnetID, vdrs))
+	assert.Equal(t, []string{
+		fmt.Sprintf("added %s %s 2", subnetID, vdr1),
+		fmt.Sprintf("changed %s %s 2 3", subnetID, vdr1),
+		fmt.Sprintf("added %s %s 4", subnetID, vdr2),
+	}, subscriber.events)
+}
+
+// readingSubscriber reads the validator set that changed when it's notified
+type readingSubscriber struct {
+	m       Manager
+	weights []uint64
+}
+
+func (s *readingSubscriber) OnValidatorAdded(subnetID ids.ID, validatorID ids.NodeID, _ uint64) {
+	s.read(subnetID, validatorID)
+}
+
+func (s *readingSubscriber) OnValidatorRemoved(subnetID ids.ID, validatorID ids.NodeID, _ uint64) {
+	s.read(subnetID, validatorID)
+}
+
+func (s *readingSubscriber) OnValidatorWeightChanged(subnetID ids.ID, validatorID ids.NodeID, _, _ uint64) {
+	s.read(subnetID, validatorID)
+}
+
+func (s *readingSubscriber) read(subnetID ids.ID, validatorID ids.NodeID) {
+	vdrs, _ := s.m.GetValidators(subnetID)
+	weight, _ := vdrs.GetWeight(validatorID)
+	s.weights = append(s.weights, weight)
+}
+
+func TestManagerNotifiesWithoutLock(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	vdrID := ids.GenerateTestNodeID()
+
+	m := NewManager()
+	subscriber := &readingSubscriber{m: m}
+	m.Subscribe(subscriber)
+
+	// The subscriber can read the validator sets when it's notified
+	assert.NoError(t, m.AddWeight(subnetID, vdrID, 1))
+	assert.NoError(t, m.AddWeight(subnetID, vdrID, 2))
+	assert.NoError(t, m.RemoveWeight(subnetID, vdrID, 3))
+	assert.Equal(t, []uint64{1, 3, 0}, subscriber.weights)
+}
+
+func TestManagerSetUpdatesInPlace(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+
+	m := NewManager()
+	vdrs := NewSet()
+	assert.NoError(t, vdrs.AddWeight(vdr0, 1))
+	assert.NoError(t, m.Set(subnetID, vdrs))
+
+	running, ok := m.GetValidators(subnetID)
+	assert.True(t, ok)
+
+	newVdrs := NewSet()
+	assert.NoError(t, newVdrs.AddWeight(vdr1, 1))
+	assert.NoError(t, m.Set(subnetID, newVdrs))
+
+	// The set that was handed out reflects the new validators
+	assert.False(t, running.Contains(vdr0))
+	assert.True(t, running.Contains(vdr1))
+	current, ok := m.GetValidators(subnetID)
+	assert.True(t, ok)
+	assert.Same(t, running, current)
+}
+
+func TestManagerSamplingCaps(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+
+	m := NewManager()
+	assert.NoError(t, m.SetSamplingCaps(subnetID, SamplingCaps{MinStake: 2}))
+
+	// The caps apply to validator sets created after they were set
+	assert.NoError(t, m.AddWeight(subnetID, vdr0, 1))
+	assert.NoError(t, m.AddWeight(subnetID, vdr1, 2))
+	vdrs, ok := m.GetValidators(subnetID)
+	assert.True(t, ok)
+	sampled, err := vdrs.Sample(2)
+	assert.NoError(t, err)
+	assert.Equal(t, vdr1, sampled[0].ID())
+	assert.Equal(t, vdr1, sampled[1].ID())
+
+	// Removing the caps makes vdr0 sampleable
+	assert.NoError(t, m.SetSamplingCaps(subnetID, SamplingCaps{}))
+	_, err = vdrs.Sample(3)
+	assert.NoError(t, err)
+
+	assert.Error(t, m.SetSamplingCaps(subnetID, SamplingCaps{MaxWeightFraction: -1}))
+}
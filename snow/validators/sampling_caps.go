@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+)
+
+var (
+	errInvalidMaxWeightFraction = errors.New("max weight fraction must be in [0, 1]")
+	errInvalidSamplingPenalty   = errors.New("sampling penalty must be in [0, 1)")
+	errInvalidMaxGroupFraction  = errors.New("max group fraction must be in [0, 1]")
+)
+
+// SamplingCaps bound how likely a validator is to be sampled. They don't
+// change the weight of the validators, only the weight they are sampled with.
+type SamplingCaps struct {
+	// MinStake is the minimum weight a validator must have to be sampled
+	MinStake uint64 `json:"minStake"`
+
+	// MaxWeightFraction is the maximum fraction of the sampleable weight of
+	// the set that a single validator is sampled with. If 0, the weight that
+	// validators are sampled with isn't capped.
+	MaxWeightFraction float64 `json:"maxWeightFraction"`
+}
+
+// Verify returns nil if the caps are valid
+func (c SamplingCaps) Verify() error {
+	if c.MaxWeightFraction < 0 || c.MaxWeightFraction > 1 {
+		return errInvalidMaxWeightFraction
+	}
+	return nil
+}
+
+// samplingWeights returns the weights that the validators are sampled with,
+// given the validators' [weights] and [maskedWeights].
+func (c SamplingCaps) samplingWeights(weights, maskedWeights []uint64) []uint64 {
+	sampleWeights := make([]uint64, len(maskedWeights))
+	eligibleWeight := uint64(0)
+	for i, weight := range maskedWeights {
+		if weights[i] < c.MinStake {
+			continue
+		}
+		sampleWeights[i] = weight
+		eligibleWeight += weight
+	}
+	if c.MaxWeightFraction == 0 || c.MaxWeightFraction == 1 {
+		return sampleWeights
+	}
+
+	maxWeight := uint64(c.MaxWeightFraction * float64(eligibleWeight))
+	if maxWeight == 0 {
+		// Every eligible validator should still be sampleable
+		maxWeight = 1
+	}
+	for i, weight := range sampleWeights {
+		if weight > maxWeight {
+			sampleWeights[i] = maxWeight
+		}
+	}
+	return sampleWeights
+}
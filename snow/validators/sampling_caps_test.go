@@ -0,0 +1,67 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplingCapsVerify(t *testing.T) {
+	assert.NoError(t, SamplingCaps{}.Verify())
+	assert.NoError(t, SamplingCaps{MinStake: 5, MaxWeightFraction: 1}.Verify())
+	assert.Error(t, SamplingCaps{MaxWeightFraction: -.1}.Verify())
+	assert.Error(t, SamplingCaps{MaxWeightFraction: 1.1}.Verify())
+}
+
+func TestSamplingCapsSamplingWeights(t *testing.T) {
+	tests := []struct {
+		name          string
+		caps          SamplingCaps
+		weights       []uint64
+		maskedWeights []uint64
+		expected      []uint64
+	}{
+		{
+			name:          "no caps",
+			weights:       []uint64{1, 5, 100},
+			maskedWeights: []uint64{1, 5, 0},
+			expected:      []uint64{1, 5, 0},
+		},
+		{
+			name:          "min stake",
+			caps:          SamplingCaps{MinStake: 5},
+			weights:       []uint64{1, 5, 100},
+			maskedWeights: []uint64{1, 5, 100},
+			expected:      []uint64{0, 5, 100},
+		},
+		{
+			name:          "max weight fraction",
+			caps:          SamplingCaps{MaxWeightFraction: .5},
+			weights:       []uint64{10, 20, 70},
+			maskedWeights: []uint64{10, 20, 70},
+			expected:      []uint64{10, 20, 50},
+		},
+		{
+			name:          "max weight fraction of eligible weight",
+			caps:          SamplingCaps{MinStake: 2, MaxWeightFraction: .5},
+			weights:       []uint64{1, 5, 100},
+			maskedWeights: []uint64{1, 5, 0},
+			expected:      []uint64{0, 2, 0},
+		},
+		{
+			name:          "tiny max weight",
+			caps:          SamplingCaps{MaxWeightFraction: .01},
+			weights:       []uint64{1, 2},
+			maskedWeights: []uint64{1, 2},
+			expected:      []uint64{1, 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.caps.samplingWeights(test.weights, test.maskedWeights))
+		})
+	}
+}
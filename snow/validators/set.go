@@ -4,7 +4,10 @@
 package validators
 
 import (
+	"bytes"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"sync"
 
@@ -37,20 +40,24 @@ type Set interface {
 	Set([]Validator) error
 
 	// AddWeight to a staker.
-	AddWeight(ids.ShortID, uint64) error
+	AddWeight(ids.NodeID, uint64) error
 
 	// GetWeight retrieves the validator weight from the set.
-	GetWeight(ids.ShortID) (uint64, bool)
+	GetWeight(ids.NodeID) (uint64, bool)
+
+	// Get returns the validator with the specified ID, ignoring whether it is
+	// masked.
+	Get(ids.NodeID) (Validator, bool)
 
 	// SubsetWeight returns the sum of the weights of the validators.
-	SubsetWeight(ids.ShortSet) (uint64, error)
+	SubsetWeight(ids.NodeIDSet) (uint64, error)
 
 	// RemoveWeight from a staker.
-	RemoveWeight(ids.ShortID, uint64) error
+	RemoveWeight(ids.NodeID, uint64) error
 
 	// Contains returns true if there is a validator with the specified ID
 	// currently in the set.
-	Contains(ids.ShortID) bool
+	Contains(ids.NodeID) bool
 
 	// Len returns the number of validators currently in the set.
 	Len() int
@@ -65,43 +72,105 @@ type Set interface {
 	// If sampling the requested size isn't possible, an error will be returned.
 	Sample(size int) ([]Validator, error)
 
+	// SampleWithSeed returns a collection of validators, potentially with
+	// duplicates, that is a deterministic function of the validators, their
+	// stake weights, the masked validators and [seed]. Masked validators are
+	// never sampled. Penalties, sampling caps and groups don't apply to it.
+	SampleWithSeed(size int, seed int64) ([]Validator, error)
+
 	// MaskValidator hides the named validator from future samplings
-	MaskValidator(ids.ShortID) error
+	MaskValidator(ids.NodeID) error
 
 	// RevealValidator ensures the named validator is not hidden from future
 	// samplings
-	RevealValidator(ids.ShortID) error
+	RevealValidator(ids.NodeID) error
+
+	// SetSamplingCaps sets the caps applied to the weights of the validators
+	// when sampling
+	SetSamplingCaps(SamplingCaps) error
+
+	// SetSamplingPenalty reduces the weight that the named validator is
+	// sampled with by the fraction [penalty], which must be in [0, 1). A
+	// penalized validator is always sampled with a weight of at least 1.
+	SetSamplingPenalty(ids.NodeID, float64) error
+
+	// SetDistinctSampling makes Sample return distinct validators when the
+	// set has at most [maxSetSize] validators, so that small sets don't
+	// query the same validator multiple times in one sample. If there aren't
+	// enough validators, Sample may still return duplicates.
+	SetDistinctSampling(maxSetSize int)
+
+	// SetValidatorGroup sets the group of the named validator, such as the IP
+	// prefix it connects from. If [group] is empty, the validator is removed
+	// from its group.
+	SetValidatorGroup(vdrID ids.NodeID, group string)
+
+	// SetMaxGroupFraction limits the validators of one group to the fraction
+	// [fraction] of each sample, rounded up. If the limit can't be met, Sample
+	// ignores it. If [fraction] is 0 or 1, samples aren't limited.
+	SetMaxGroupFraction(fraction float64) error
 }
 
 // NewSet returns a new, empty set of validators.
 func NewSet() Set {
 	return &set{
-		vdrMap:  make(map[ids.ShortID]int),
-		sampler: sampler.NewWeightedWithoutReplacement(),
+		vdrMap:    make(map[ids.NodeID]int),
+		sampler:   sampler.NewIncrementalWeightedWithoutReplacement(),
+		penalties: make(map[ids.NodeID]float64),
+		groups:    make(map[ids.NodeID]string),
 	}
 }
 
 // NewBestSet returns a new, empty set of validators.
 func NewBestSet(expectedSampleSize int) Set {
 	return &set{
-		vdrMap:  make(map[ids.ShortID]int),
-		sampler: sampler.NewBestWeightedWithoutReplacement(expectedSampleSize),
+		vdrMap:    make(map[ids.NodeID]int),
+		sampler:   sampler.NewBestIncrementalWeightedWithoutReplacement(expectedSampleSize),
+		penalties: make(map[ids.NodeID]float64),
+		groups:    make(map[ids.NodeID]string),
 	}
 }
 
 // set of validators. Validator function results are cached. Therefore, to
 // update a validators weight, one should ensure to call add with the updated
 // validator.
+//
+// Once the sampler has been initialized, changes to the weights of individual
+// validators are applied to it in O(log(n)) time rather than rebuilding it.
+// If the sampling weights are capped to a fraction of the total weight, the
+// sampler is instead rebuilt after a change, as the change may move the cap.
 type set struct {
 	initialized      bool
 	lock             sync.RWMutex
-	vdrMap           map[ids.ShortID]int
+	vdrMap           map[ids.NodeID]int
 	vdrSlice         []*validator
 	vdrWeights       []uint64
 	vdrMaskedWeights []uint64
-	sampler          sampler.WeightedWithoutReplacement
+	sampler          sampler.IncrementalWeightedWithoutReplacement
 	totalWeight      uint64
-	maskedVdrs       ids.ShortSet
+	maskedVdrs       ids.NodeIDSet
+	caps             SamplingCaps
+	// Validator ID --> Fraction of its weight the validator isn't sampled with
+	penalties map[ids.NodeID]float64
+	// Sets with at most this many validators are sampled without duplicates
+	maxDistinctSetSize int
+	// Validator ID --> Group of the validator
+	groups map[ids.NodeID]string
+	// Max fraction of a sample that can be from the same group
+	maxGroupFraction float64
+	// Group numbers of the validators in [vdrSlice], as returned by
+	// sampleGroups. Rebuilt on the next sample after the validators or their
+	// groups change.
+	groupNums []int
+
+	// Samples the validators in order of their IDs, so that the samples of
+	// SampleWithSeed don't depend on the order that validators were added to
+	// and removed from the set in. Rebuilt on the next seeded sample after
+	// the set changes.
+	seededInitialized bool
+	seededSampler     sampler.IncrementalWeightedWithoutReplacement
+	// Index in [vdrSlice] of each validator sampled by [seededSampler]
+	seededOrder []int
 }
 
 // Set implements the Set interface.
@@ -109,9 +178,62 @@ func (s *set) Set(vdrs []Validator) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	if s.initialized {
+		return s.update(vdrs)
+	}
 	return s.set(vdrs)
 }
 
+// update replaces the validators in the set with [vdrs] by only modifying the
+// validators that were added, removed, or had their weight changed. This keeps
+// the sampler initialized, so that replacing the validators of a running chain
+// with a slightly different set is cheap.
+func (s *set) update(vdrs []Validator) error {
+	newWeights := make(map[ids.NodeID]uint64, len(vdrs))
+	for _, vdr := range vdrs {
+		vdrID := vdr.ID()
+		if _, exists := newWeights[vdrID]; !exists {
+			newWeights[vdrID] = vdr.Weight()
+		}
+	}
+
+	// Removing a validator moves the last validator into its place, so iterate
+	// backwards to visit every validator.
+	for i := len(s.vdrSlice) - 1; i >= 0; i-- {
+		vdrID := s.vdrSlice[i].ID()
+		if newWeight := newWeights[vdrID]; newWeight == 0 {
+			if err := s.remove(vdrID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, vdr := range vdrs {
+		vdrID := vdr.ID()
+		newWeight, exists := newWeights[vdrID]
+		if !exists {
+			continue // This validator was a duplicate
+		}
+		delete(newWeights, vdrID)
+
+		oldWeight := uint64(0)
+		if i, ok := s.vdrMap[vdrID]; ok {
+			oldWeight = s.vdrWeights[i]
+		}
+		switch {
+		case newWeight > oldWeight:
+			if err := s.addWeight(vdrID, newWeight-oldWeight); err != nil {
+				return err
+			}
+		case newWeight < oldWeight:
+			if err := s.removeWeight(vdrID, oldWeight-newWeight); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (s *set) set(vdrs []Validator) error {
 	lenVdrs := len(vdrs)
 	// If the underlying arrays are much larger than necessary, resize them to
@@ -129,9 +251,11 @@ func (s *set) set(vdrs []Validator) error {
 		s.vdrWeights = s.vdrWeights[:0]
 		s.vdrMaskedWeights = s.vdrMaskedWeights[:0]
 	}
-	s.vdrMap = make(map[ids.ShortID]int, lenVdrs)
+	s.vdrMap = make(map[ids.NodeID]int, lenVdrs)
 	s.totalWeight = 0
 	s.initialized = false
+	s.seededInitialized = false
+	s.groupNums = nil
 
 	for _, vdr := range vdrs {
 		vdrID := vdr.ID()
@@ -167,7 +291,7 @@ func (s *set) set(vdrs []Validator) error {
 }
 
 // Add implements the Set interface.
-func (s *set) AddWeight(vdrID ids.ShortID, weight uint64) error {
+func (s *set) AddWeight(vdrID ids.NodeID, weight uint64) error {
 	if weight == 0 {
 		return nil // This validator would never be sampled anyway
 	}
@@ -177,7 +301,9 @@ func (s *set) AddWeight(vdrID ids.ShortID, weight uint64) error {
 	return s.addWeight(vdrID, weight)
 }
 
-func (s *set) addWeight(vdrID ids.ShortID, weight uint64) error {
+func (s *set) addWeight(vdrID ids.NodeID, weight uint64) error {
+	s.seededInitialized = false
+
 	var vdr *validator
 	i, ok := s.vdrMap[vdrID]
 	if !ok {
@@ -189,6 +315,7 @@ func (s *set) addWeight(vdrID ids.ShortID, weight uint64) error {
 		s.vdrWeights = append(s.vdrWeights, 0)
 		s.vdrMaskedWeights = append(s.vdrMaskedWeights, 0)
 		s.vdrMap[vdrID] = i
+		s.groupNums = nil
 	} else {
 		vdr = s.vdrSlice[i]
 	}
@@ -197,6 +324,10 @@ func (s *set) addWeight(vdrID ids.ShortID, weight uint64) error {
 	vdr.addWeight(weight)
 
 	if s.maskedVdrs.Contains(vdrID) {
+		if !ok {
+			// Keep the sampler aligned with [s.vdrSlice]
+			s.updateSampler(i)
+		}
 		return nil
 	}
 	s.vdrMaskedWeights[i] += weight
@@ -206,19 +337,19 @@ func (s *set) addWeight(vdrID ids.ShortID, weight uint64) error {
 		return nil
 	}
 	s.totalWeight = newTotalWeight
-	s.initialized = false
+	s.updateSampler(i)
 	return nil
 }
 
 // GetWeight implements the Set interface.
-func (s *set) GetWeight(vdrID ids.ShortID) (uint64, bool) {
+func (s *set) GetWeight(vdrID ids.NodeID) (uint64, bool) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
 	return s.getWeight(vdrID)
 }
 
-func (s *set) getWeight(vdrID ids.ShortID) (uint64, bool) {
+func (s *set) getWeight(vdrID ids.NodeID) (uint64, bool) {
 	if index, ok := s.vdrMap[vdrID]; ok {
 		return s.vdrMaskedWeights[index], true
 	}
@@ -226,7 +357,7 @@ func (s *set) getWeight(vdrID ids.ShortID) (uint64, bool) {
 }
 
 // SubsetWeight implements the Set interface.
-func (s *set) SubsetWeight(subset ids.ShortSet) (uint64, error) {
+func (s *set) SubsetWeight(subset ids.NodeIDSet) (uint64, error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
@@ -246,7 +377,7 @@ func (s *set) SubsetWeight(subset ids.ShortSet) (uint64, error) {
 }
 
 // RemoveWeight implements the Set interface.
-func (s *set) RemoveWeight(vdrID ids.ShortID, weight uint64) error {
+func (s *set) RemoveWeight(vdrID ids.NodeID, weight uint64) error {
 	if weight == 0 {
 		return nil
 	}
@@ -256,7 +387,7 @@ func (s *set) RemoveWeight(vdrID ids.ShortID, weight uint64) error {
 	return s.removeWeight(vdrID, weight)
 }
 
-func (s *set) removeWeight(vdrID ids.ShortID, weight uint64) error {
+func (s *set) removeWeight(vdrID ids.NodeID, weight uint64) error {
 	i, ok := s.vdrMap[vdrID]
 	if !ok {
 		return nil
@@ -274,23 +405,21 @@ func (s *set) removeWeight(vdrID ids.ShortID, weight uint64) error {
 	}
 
 	if vdr.Weight() == 0 {
-		if err := s.remove(vdrID); err != nil {
-			return err
-		}
+		return s.remove(vdrID)
 	}
-	s.initialized = false
+	s.updateSampler(i)
 	return nil
 }
 
 // Get implements the Set interface.
-func (s *set) Get(vdrID ids.ShortID) (Validator, bool) {
+func (s *set) Get(vdrID ids.NodeID) (Validator, bool) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
 	return s.get(vdrID)
 }
 
-func (s *set) get(vdrID ids.ShortID) (Validator, bool) {
+func (s *set) get(vdrID ids.NodeID) (Validator, bool) {
 	index, ok := s.vdrMap[vdrID]
 	if !ok {
 		return nil, false
@@ -298,7 +427,7 @@ func (s *set) get(vdrID ids.ShortID) (Validator, bool) {
 	return s.vdrSlice[index], true
 }
 
-func (s *set) remove(vdrID ids.ShortID) error {
+func (s *set) remove(vdrID ids.NodeID) error {
 	// Get the element to remove
 	i, contains := s.vdrMap[vdrID]
 	if !contains {
@@ -322,6 +451,15 @@ func (s *set) remove(vdrID ids.ShortID) error {
 	s.vdrWeights = s.vdrWeights[:e]
 	s.vdrMaskedWeights = s.vdrMaskedWeights[:e]
 
+	s.seededInitialized = false
+	s.groupNums = nil
+	if s.initialized {
+		s.sampler.Truncate(e)
+		if i != e {
+			s.updateSampler(i)
+		}
+	}
+
 	if !s.maskedVdrs.Contains(vdrID) {
 		newTotalWeight, err := safemath.Sub64(s.totalWeight, iElem.Weight())
 		if err != nil {
@@ -329,19 +467,18 @@ func (s *set) remove(vdrID ids.ShortID) error {
 		}
 		s.totalWeight = newTotalWeight
 	}
-	s.initialized = false
 	return nil
 }
 
 // Contains implements the Set interface.
-func (s *set) Contains(vdrID ids.ShortID) bool {
+func (s *set) Contains(vdrID ids.NodeID) bool {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
 	return s.contains(vdrID)
 }
 
-func (s *set) contains(vdrID ids.ShortID) bool {
+func (s *set) contains(vdrID ids.NodeID) bool {
 	_, contains := s.vdrMap[vdrID]
 	return contains
 }
@@ -383,14 +520,65 @@ func (s *set) Sample(size int) ([]Validator, error) {
 	return s.sample(size)
 }
 
+// SampleWithSeed implements the Set interface. The validators are sampled in
+// order of their IDs, so that the sample only depends on the validators and
+// their weights, and not on the order that they were added in. Every node
+// must draw the same sample for the same seed, so the penalties, caps and
+// groups of this node, which are local configuration, are ignored. Masks are
+// applied, since validators are masked when the network no longer considers
+// their version compatible, and they mustn't be queried.
+func (s *set) SampleWithSeed(size int, seed int64) ([]Validator, error) {
+	if size == 0 {
+		return nil, nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.seededInitialized {
+		order := make([]int, len(s.vdrSlice))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(i, j int) bool {
+			iID, jID := s.vdrSlice[order[i]].ID(), s.vdrSlice[order[j]].ID()
+			return bytes.Compare(iID[:], jID[:]) < 0
+		})
+		sortedWeights := make([]uint64, len(order))
+		for i, index := range order {
+			sortedWeights[i] = s.vdrMaskedWeights[index]
+		}
+		if s.seededSampler == nil {
+			s.seededSampler = sampler.NewIncrementalWeightedWithoutReplacement()
+		}
+		if err := s.seededSampler.Initialize(sortedWeights); err != nil {
+			return nil, err
+		}
+		s.seededOrder = order
+		s.seededInitialized = true
+	}
+
+	s.seededSampler.Seed(seed)
+	defer s.seededSampler.ClearSeed()
+	indices, err := s.seededSampler.Sample(size)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Validator, size)
+	for i, index := range indices {
+		list[i] = s.vdrSlice[s.seededOrder[index]]
+	}
+	return list, nil
+}
+
 func (s *set) sample(size int) ([]Validator, error) {
 	if !s.initialized {
-		if err := s.sampler.Initialize(s.vdrMaskedWeights); err != nil {
+		if err := s.sampler.Initialize(s.samplingWeights()); err != nil {
 			return nil, err
 		}
 		s.initialized = true
 	}
-	indices, err := s.sampler.Sample(size)
+	indices, err := s.sampleIndices(size)
 	if err != nil {
 		return nil, err
 	}
@@ -402,6 +590,102 @@ func (s *set) sample(size int) ([]Validator, error) {
 	return list, nil
 }
 
+// samplingWeights returns the weight that each validator in [s.vdrSlice] is
+// sampled with
+func (s *set) samplingWeights() []uint64 {
+	penalizedWeights := make([]uint64, len(s.vdrMaskedWeights))
+	for i := range penalizedWeights {
+		penalizedWeights[i] = s.penalizedWeight(i)
+	}
+	return s.caps.samplingWeights(s.vdrWeights, penalizedWeights)
+}
+
+// sampleIndices samples the indices in [s.vdrSlice] of [size] validators
+func (s *set) sampleIndices(size int) ([]int, error) {
+	distinct := len(s.vdrSlice) <= s.maxDistinctSetSize
+	if s.maxGroupFraction != 0 && s.maxGroupFraction != 1 && len(s.groups) > 0 {
+		maxPerGroup := int(math.Ceil(s.maxGroupFraction * float64(size)))
+		if indices, err := s.sampler.SampleWithGroupLimit(size, s.cachedSampleGroups(), maxPerGroup, distinct); err == nil {
+			return indices, nil
+		}
+		// The validators are too concentrated in a few groups, or there
+		// aren't enough sampleable validators, to meet the limit
+	}
+	if distinct {
+		if indices, err := s.sampler.SampleDistinct(size); err == nil {
+			return indices, nil
+		}
+		// There aren't enough sampleable validators to avoid duplicates
+	}
+	return s.sampler.Sample(size)
+}
+
+// cachedSampleGroups returns sampleGroups(), which is only rebuilt after the
+// validators or their groups change.
+func (s *set) cachedSampleGroups() []int {
+	if s.groupNums == nil {
+		s.groupNums = s.sampleGroups()
+	}
+	return s.groupNums
+}
+
+// sampleGroups returns the group of each validator in [s.vdrSlice], numbered
+// from 0. Validators without a group are assigned -1.
+func (s *set) sampleGroups() []int {
+	groupNums := make(map[string]int, len(s.groups))
+	groups := make([]int, len(s.vdrSlice))
+	for i, vdr := range s.vdrSlice {
+		group, ok := s.groups[vdr.ID()]
+		if !ok {
+			groups[i] = -1
+			continue
+		}
+		groupNum, ok := groupNums[group]
+		if !ok {
+			groupNum = len(groupNums)
+			groupNums[group] = groupNum
+		}
+		groups[i] = groupNum
+	}
+	return groups
+}
+
+// updateSampler applies the current masked weight of the validator at index
+// [i] to the sampler. If the sampler can't be updated, it will be rebuilt on the
+// next sample.
+func (s *set) updateSampler(i int) {
+	s.seededInitialized = false
+	if !s.initialized {
+		return
+	}
+	if s.caps.MaxWeightFraction != 0 && s.caps.MaxWeightFraction != 1 {
+		s.initialized = false
+		return
+	}
+	weight := s.penalizedWeight(i)
+	if s.vdrWeights[i] < s.caps.MinStake {
+		weight = 0
+	}
+	if err := s.sampler.Update(i, weight); err != nil {
+		s.initialized = false
+	}
+}
+
+// penalizedWeight returns the masked weight of the validator at index [i],
+// reduced by the validator's sampling penalty
+func (s *set) penalizedWeight(i int) uint64 {
+	weight := s.vdrMaskedWeights[i]
+	penalty, ok := s.penalties[s.vdrSlice[i].ID()]
+	if !ok || weight == 0 {
+		return weight
+	}
+	penalizedWeight := uint64(float64(weight) * (1 - penalty))
+	if penalizedWeight == 0 {
+		return 1
+	}
+	return penalizedWeight
+}
+
 func (s *set) Weight() uint64 {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -429,7 +713,7 @@ func (s *set) string() string {
 		s.totalWeight,
 		totalWeight,
 	))
-	format := fmt.Sprintf("\n    Validator[%s]: %%33s, %%d/%%d", formatting.IntFormat(len(s.vdrSlice)-1))
+	format := fmt.Sprintf("\n    Validator[%s]: %%40s, %%d/%%d", formatting.IntFormat(len(s.vdrSlice)-1))
 	for i, vdr := range s.vdrSlice {
 		sb.WriteString(fmt.Sprintf(format,
 			i,
@@ -441,14 +725,14 @@ func (s *set) string() string {
 	return sb.String()
 }
 
-func (s *set) MaskValidator(vdrID ids.ShortID) error {
+func (s *set) MaskValidator(vdrID ids.NodeID) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
 	return s.maskValidator(vdrID)
 }
 
-func (s *set) maskValidator(vdrID ids.ShortID) error {
+func (s *set) maskValidator(vdrID ids.NodeID) error {
 	if s.maskedVdrs.Contains(vdrID) {
 		return nil
 	}
@@ -463,19 +747,19 @@ func (s *set) maskValidator(vdrID ids.ShortID) error {
 
 	s.vdrMaskedWeights[i] = 0
 	s.totalWeight -= s.vdrWeights[i]
-	s.initialized = false
+	s.updateSampler(i)
 
 	return nil
 }
 
-func (s *set) RevealValidator(vdrID ids.ShortID) error {
+func (s *set) RevealValidator(vdrID ids.NodeID) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
 	return s.revealValidator(vdrID)
 }
 
-func (s *set) revealValidator(vdrID ids.ShortID) error {
+func (s *set) revealValidator(vdrID ids.NodeID) error {
 	if !s.maskedVdrs.Contains(vdrID) {
 		return nil
 	}
@@ -495,7 +779,75 @@ func (s *set) revealValidator(vdrID ids.ShortID) error {
 		return err
 	}
 	s.totalWeight = newTotalWeight
+	s.updateSampler(i)
+
+	return nil
+}
+
+// SetSamplingCaps implements the Set interface.
+func (s *set) SetSamplingCaps(caps SamplingCaps) error {
+	if err := caps.Verify(); err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.caps = caps
 	s.initialized = false
+	return nil
+}
+
+// SetSamplingPenalty implements the Set interface.
+func (s *set) SetSamplingPenalty(vdrID ids.NodeID, penalty float64) error {
+	if penalty < 0 || penalty >= 1 {
+		return errInvalidSamplingPenalty
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if penalty == 0 {
+		delete(s.penalties, vdrID)
+	} else {
+		s.penalties[vdrID] = penalty
+	}
+	if i, ok := s.vdrMap[vdrID]; ok {
+		s.updateSampler(i)
+	}
+	return nil
+}
+
+// SetDistinctSampling implements the Set interface.
+func (s *set) SetDistinctSampling(maxSetSize int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.maxDistinctSetSize = maxSetSize
+}
+
+// SetValidatorGroup implements the Set interface.
+func (s *set) SetValidatorGroup(vdrID ids.NodeID, group string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.groups[vdrID] == group {
+		return
+	}
+	if group == "" {
+		delete(s.groups, vdrID)
+	} else {
+		s.groups[vdrID] = group
+	}
+	s.groupNums = nil
+}
+
+// SetMaxGroupFraction implements the Set interface.
+func (s *set) SetMaxGroupFraction(fraction float64) error {
+	if fraction < 0 || fraction > 1 {
+		return errInvalidMaxGroupFraction
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
+	s.maxGroupFraction = fraction
 	return nil
 }
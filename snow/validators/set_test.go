@@ -13,10 +13,10 @@ import (
 )
 
 func TestSetSet(t *testing.T) {
-	vdr0 := NewValidator(ids.ShortEmpty, 1)
-	vdr1 := NewValidator(ids.ShortID{0xFF}, math.MaxInt64-1)
+	vdr0 := NewValidator(ids.EmptyNodeID, 1)
+	vdr1 := NewValidator(ids.NodeID{0xFF}, math.MaxInt64-1)
 	// Should be discarded, because it has a weight of 0
-	vdr2 := NewValidator(ids.ShortID{0xAA}, 0)
+	vdr2 := NewValidator(ids.NodeID{0xAA}, 0)
 
 	s := NewSet()
 	err := s.Set([]Validator{vdr0, vdr1, vdr2})
@@ -38,8 +38,8 @@ func TestSetSet(t *testing.T) {
 }
 
 func TestSamplerSample(t *testing.T) {
-	vdr0 := ids.GenerateTestShortID()
-	vdr1 := ids.GenerateTestShortID()
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
 
 	s := NewSet()
 	err := s.AddWeight(vdr0, 1)
@@ -76,8 +76,8 @@ func TestSamplerSample(t *testing.T) {
 }
 
 func TestSamplerDuplicate(t *testing.T) {
-	vdr0 := ids.GenerateTestShortID()
-	vdr1 := ids.GenerateTestShortID()
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
 
 	s := NewSet()
 	err := s.AddWeight(vdr0, 1)
@@ -96,7 +96,7 @@ func TestSamplerDuplicate(t *testing.T) {
 }
 
 func TestSamplerContains(t *testing.T) {
-	vdr := ids.GenerateTestShortID()
+	vdr := ids.GenerateTestNodeID()
 
 	s := NewSet()
 	err := s.AddWeight(vdr, 1)
@@ -113,8 +113,8 @@ func TestSamplerContains(t *testing.T) {
 }
 
 func TestSamplerString(t *testing.T) {
-	vdr0 := ids.ShortEmpty
-	vdr1 := ids.ShortID{
+	vdr0 := ids.EmptyNodeID
+	vdr1 := ids.NodeID{
 		0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
 		0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
 	}
@@ -127,16 +127,16 @@ func TestSamplerString(t *testing.T) {
 	assert.NoError(t, err)
 
 	expected := "Validator Set: (Size = 2, SampleableWeight = 9223372036854775807, Weight = 9223372036854775807)\n" +
-		"    Validator[0]:        111111111111111111116DBWJs, 1/1\n" +
-		"    Validator[1]: QLbz7JHiBTspS962RLKV8GndWFwdYhk6V, 9223372036854775806/9223372036854775806"
+		"    Validator[0]:        NodeID-111111111111111111116DBWJs, 1/1\n" +
+		"    Validator[1]: NodeID-QLbz7JHiBTspS962RLKV8GndWFwdYhk6V, 9223372036854775806/9223372036854775806"
 	result := s.String()
 	assert.Equal(t, expected, result, "wrong string returned")
 }
 
 func TestSetWeight(t *testing.T) {
-	vdr0 := ids.ShortID{1}
+	vdr0 := ids.NodeID{1}
 	weight0 := uint64(93)
-	vdr1 := ids.ShortID{2}
+	vdr1 := ids.NodeID{2}
 	weight1 := uint64(123)
 
 	s := NewSet()
@@ -152,13 +152,13 @@ func TestSetWeight(t *testing.T) {
 }
 
 func TestSetSubsetWeight(t *testing.T) {
-	vdr0 := ids.ShortID{1}
+	vdr0 := ids.NodeID{1}
 	weight0 := uint64(93)
-	vdr1 := ids.ShortID{2}
+	vdr1 := ids.NodeID{2}
 	weight1 := uint64(123)
-	vdr2 := ids.ShortID{3}
+	vdr2 := ids.NodeID{3}
 	weight2 := uint64(810)
-	subset := ids.ShortSet{}
+	subset := ids.NodeIDSet{}
 	subset.Add(vdr0)
 	subset.Add(vdr1)
 
@@ -180,8 +180,8 @@ func TestSetSubsetWeight(t *testing.T) {
 }
 
 func TestSamplerMasked(t *testing.T) {
-	vdr0 := ids.ShortEmpty
-	vdr1 := ids.ShortID{
+	vdr0 := ids.EmptyNodeID
+	vdr1 := ids.NodeID{
 		0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
 		0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
 	}
@@ -195,7 +195,7 @@ func TestSamplerMasked(t *testing.T) {
 
 	{
 		expected := "Validator Set: (Size = 1, SampleableWeight = 1, Weight = 1)\n" +
-			"    Validator[0]:        111111111111111111116DBWJs, 1/1"
+			"    Validator[0]:        NodeID-111111111111111111116DBWJs, 1/1"
 		result := s.String()
 		assert.Equal(t, expected, result, "wrong string returned")
 	}
@@ -205,8 +205,8 @@ func TestSamplerMasked(t *testing.T) {
 
 	{
 		expected := "Validator Set: (Size = 2, SampleableWeight = 1, Weight = 9223372036854775807)\n" +
-			"    Validator[0]:        111111111111111111116DBWJs, 1/1\n" +
-			"    Validator[1]: QLbz7JHiBTspS962RLKV8GndWFwdYhk6V, 0/9223372036854775806"
+			"    Validator[0]:        NodeID-111111111111111111116DBWJs, 1/1\n" +
+			"    Validator[1]: NodeID-QLbz7JHiBTspS962RLKV8GndWFwdYhk6V, 0/9223372036854775806"
 		result := s.String()
 		assert.Equal(t, expected, result, "wrong string returned")
 	}
@@ -216,8 +216,8 @@ func TestSamplerMasked(t *testing.T) {
 
 	{
 		expected := "Validator Set: (Size = 2, SampleableWeight = 9223372036854775807, Weight = 9223372036854775807)\n" +
-			"    Validator[0]:        111111111111111111116DBWJs, 1/1\n" +
-			"    Validator[1]: QLbz7JHiBTspS962RLKV8GndWFwdYhk6V, 9223372036854775806/9223372036854775806"
+			"    Validator[0]:        NodeID-111111111111111111116DBWJs, 1/1\n" +
+			"    Validator[1]: NodeID-QLbz7JHiBTspS962RLKV8GndWFwdYhk6V, 9223372036854775806/9223372036854775806"
 		result := s.String()
 		assert.Equal(t, expected, result, "wrong string returned")
 	}
@@ -227,8 +227,8 @@ func TestSamplerMasked(t *testing.T) {
 
 	{
 		expected := "Validator Set: (Size = 2, SampleableWeight = 1, Weight = 9223372036854775807)\n" +
-			"    Validator[0]:        111111111111111111116DBWJs, 1/1\n" +
-			"    Validator[1]: QLbz7JHiBTspS962RLKV8GndWFwdYhk6V, 0/9223372036854775806"
+			"    Validator[0]:        NodeID-111111111111111111116DBWJs, 1/1\n" +
+			"    Validator[1]: NodeID-QLbz7JHiBTspS962RLKV8GndWFwdYhk6V, 0/9223372036854775806"
 		result := s.String()
 		assert.Equal(t, expected, result, "wrong string returned")
 	}
@@ -238,8 +238,8 @@ func TestSamplerMasked(t *testing.T) {
 
 	{
 		expected := "Validator Set: (Size = 2, SampleableWeight = 9223372036854775807, Weight = 9223372036854775807)\n" +
-			"    Validator[0]:        111111111111111111116DBWJs, 1/1\n" +
-			"    Validator[1]: QLbz7JHiBTspS962RLKV8GndWFwdYhk6V, 9223372036854775806/9223372036854775806"
+			"    Validator[0]:        NodeID-111111111111111111116DBWJs, 1/1\n" +
+			"    Validator[1]: NodeID-QLbz7JHiBTspS962RLKV8GndWFwdYhk6V, 9223372036854775806/9223372036854775806"
 		result := s.String()
 		assert.Equal(t, expected, result, "wrong string returned")
 	}
@@ -249,9 +249,430 @@ func TestSamplerMasked(t *testing.T) {
 
 	{
 		expected := "Validator Set: (Size = 2, SampleableWeight = 9223372036854775807, Weight = 9223372036854775807)\n" +
-			"    Validator[0]:        111111111111111111116DBWJs, 1/1\n" +
-			"    Validator[1]: QLbz7JHiBTspS962RLKV8GndWFwdYhk6V, 9223372036854775806/9223372036854775806"
+			"    Validator[0]:        NodeID-111111111111111111116DBWJs, 1/1\n" +
+			"    Validator[1]: NodeID-QLbz7JHiBTspS962RLKV8GndWFwdYhk6V, 9223372036854775806/9223372036854775806"
 		result := s.String()
 		assert.Equal(t, expected, result, "wrong string returned")
 	}
 }
+
+func TestSamplerIncrementalUpdates(t *testing.T) {
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+	vdr2 := ids.GenerateTestNodeID()
+
+	s := NewSet()
+	err := s.AddWeight(vdr0, 1)
+	assert.NoError(t, err)
+
+	// Initialize the sampler
+	sampled, err := s.Sample(1)
+	assert.NoError(t, err)
+	assert.Equal(t, vdr0, sampled[0].ID(), "should have sampled vdr0")
+
+	// Added while masked, so it must never be sampled
+	err = s.MaskValidator(vdr2)
+	assert.NoError(t, err)
+	err = s.AddWeight(vdr2, math.MaxInt64-2)
+	assert.NoError(t, err)
+
+	err = s.AddWeight(vdr1, 1)
+	assert.NoError(t, err)
+	err = s.RemoveWeight(vdr0, 1)
+	assert.NoError(t, err)
+
+	sampled, err = s.Sample(1)
+	assert.NoError(t, err)
+	assert.Equal(t, vdr1, sampled[0].ID(), "should have sampled vdr1")
+
+	_, err = s.Sample(2)
+	assert.Error(t, err, "should have errored during sampling")
+
+	err = s.RevealValidator(vdr2)
+	assert.NoError(t, err)
+	err = s.MaskValidator(vdr1)
+	assert.NoError(t, err)
+
+	sampled, err = s.Sample(3)
+	assert.NoError(t, err)
+	for _, vdr := range sampled {
+		assert.Equal(t, vdr2, vdr.ID(), "should have sampled vdr2")
+	}
+}
+
+func TestSetSetAfterSampling(t *testing.T) {
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+	vdr2 := ids.GenerateTestNodeID()
+
+	s := NewSet()
+	err := s.Set([]Validator{
+		NewValidator(vdr0, 1),
+		NewValidator(vdr1, 2),
+	})
+	assert.NoError(t, err)
+
+	// Initialize the sampler
+	_, err = s.Sample(1)
+	assert.NoError(t, err)
+
+	err = s.Set([]Validator{
+		NewValidator(vdr2, 3),
+		NewValidator(vdr1, 1),
+		NewValidator(vdr2, 5), // Duplicates are ignored
+		NewValidator(vdr0, 0), // Validators without weight are removed
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, s.Len())
+	assert.False(t, s.Contains(vdr0), "should have removed vdr0")
+	weight, ok := s.GetWeight(vdr1)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), weight)
+	weight, ok = s.GetWeight(vdr2)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(3), weight)
+	assert.Equal(t, uint64(4), s.Weight())
+
+	sampled, err := s.Sample(4)
+	assert.NoError(t, err)
+	sampledWeights := map[ids.NodeID]int{}
+	for _, vdr := range sampled {
+		sampledWeights[vdr.ID()]++
+	}
+	assert.Equal(t, map[ids.NodeID]int{vdr1: 1, vdr2: 3}, sampledWeights)
+
+	_, err = s.Sample(5)
+	assert.Error(t, err, "should have errored during sampling")
+}
+
+func TestSamplerSamplingCaps(t *testing.T) {
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+
+	s := NewSet()
+	assert.NoError(t, s.AddWeight(vdr0, 1))
+	assert.NoError(t, s.AddWeight(vdr1, 3))
+
+	// vdr0 doesn't have enough stake to be sampled
+	assert.NoError(t, s.SetSamplingCaps(SamplingCaps{MinStake: 2}))
+	sampled, err := s.Sample(3)
+	assert.NoError(t, err)
+	for _, vdr := range sampled {
+		assert.Equal(t, vdr1, vdr.ID(), "should have only sampled vdr1")
+	}
+	_, err = s.Sample(4)
+	assert.Error(t, err, "should have errored during sampling")
+	assert.Equal(t, uint64(4), s.Weight(), "caps shouldn't change the weight")
+
+	// Both validators are sampled with a weight of 1
+	assert.NoError(t, s.SetSamplingCaps(SamplingCaps{MaxWeightFraction: .25}))
+	sampled, err = s.Sample(2)
+	assert.NoError(t, err)
+	assert.Len(t, sampled, 2)
+	assert.NotEqual(t, sampled[0].ID(), sampled[1].ID(), "should have sampled both validators")
+
+	// Changes to the weights are capped as well
+	assert.NoError(t, s.AddWeight(vdr0, 3))
+	_, err = s.Sample(3)
+	assert.Error(t, err, "should have errored during sampling")
+	assert.NoError(t, s.SetSamplingCaps(SamplingCaps{MaxWeightFraction: .5}))
+	sampled, err = s.Sample(6)
+	assert.NoError(t, err)
+	assert.Len(t, sampled, 6)
+
+	assert.Error(t, s.SetSamplingCaps(SamplingCaps{MaxWeightFraction: 2}))
+}
+
+func TestSamplerSamplingPenalty(t *testing.T) {
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+
+	s := NewSet()
+	assert.NoError(t, s.AddWeight(vdr0, 1))
+	assert.NoError(t, s.AddWeight(vdr1, 3))
+	_, err := s.Sample(4)
+	assert.NoError(t, err)
+
+	// vdr1 is sampled with a weight of 1
+	assert.NoError(t, s.SetSamplingPenalty(vdr1, .5))
+	sampled, err := s.Sample(2)
+	assert.NoError(t, err)
+	assert.NotEqual(t, sampled[0].ID(), sampled[1].ID(), "should have sampled both validators")
+	_, err = s.Sample(3)
+	assert.Error(t, err, "should have errored during sampling")
+	assert.Equal(t, uint64(4), s.Weight(), "penalties shouldn't change the weight")
+
+	// A penalized validator can always be sampled
+	assert.NoError(t, s.SetSamplingPenalty(vdr0, .99))
+	_, err = s.Sample(2)
+	assert.NoError(t, err)
+
+	// Removing the penalties restores the sampling weights
+	assert.NoError(t, s.SetSamplingPenalty(vdr0, 0))
+	assert.NoError(t, s.SetSamplingPenalty(vdr1, 0))
+	_, err = s.Sample(4)
+	assert.NoError(t, err)
+
+	assert.Error(t, s.SetSamplingPenalty(vdr0, 1))
+	assert.Error(t, s.SetSamplingPenalty(vdr0, -.1))
+}
+
+func TestSamplerDistinct(t *testing.T) {
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+
+	s := NewSet()
+	assert.NoError(t, s.AddWeight(vdr0, 1))
+	assert.NoError(t, s.AddWeight(vdr1, math.MaxInt32))
+
+	s.SetDistinctSampling(2)
+	for i := 0; i < 10; i++ {
+		sampled, err := s.Sample(2)
+		assert.NoError(t, err)
+		assert.NotEqual(t, sampled[0].ID(), sampled[1].ID(), "should have sampled both validators")
+	}
+
+	// There aren't enough validators to avoid duplicates
+	sampled, err := s.Sample(3)
+	assert.NoError(t, err)
+	assert.Len(t, sampled, 3)
+
+	// Sets larger than the limit are sampled with duplicates
+	s.SetDistinctSampling(1)
+	sampled, err = s.Sample(2)
+	assert.NoError(t, err)
+	assert.Equal(t, vdr1, sampled[0].ID())
+	assert.Equal(t, vdr1, sampled[1].ID())
+}
+
+func TestSamplerWithSeed(t *testing.T) {
+	s0 := NewSet()
+	s1 := NewBestSet(20)
+	for i := 0; i < 100; i++ {
+		vdrID := ids.GenerateTestNodeID()
+		assert.NoError(t, s0.AddWeight(vdrID, uint64(i+1)))
+		assert.NoError(t, s1.AddWeight(vdrID, uint64(i+1)))
+	}
+
+	sampled0, err := s0.SampleWithSeed(20, 1)
+	assert.NoError(t, err)
+	sampled1, err := s1.SampleWithSeed(20, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, sampled0, sampled1, "sets with the same validators should sample the same validators")
+
+	sampled2, err := s0.SampleWithSeed(20, 2)
+	assert.NoError(t, err)
+	assert.NotEqual(t, sampled0, sampled2)
+
+	sampled3, err := s0.SampleWithSeed(20, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, sampled0, sampled3)
+}
+
+func TestSamplerWithSeedIgnoresLocalConfig(t *testing.T) {
+	s0 := NewSet()
+	s1 := NewSet()
+	vdrIDs := make([]ids.NodeID, 20)
+	for i := range vdrIDs {
+		vdrIDs[i] = ids.GenerateTestNodeID()
+		assert.NoError(t, s0.AddWeight(vdrIDs[i], uint64(i+1)))
+		assert.NoError(t, s1.AddWeight(vdrIDs[i], uint64(i+1)))
+	}
+
+	assert.NoError(t, s1.SetSamplingPenalty(vdrIDs[18], .9))
+	assert.NoError(t, s1.SetSamplingCaps(SamplingCaps{MinStake: 10, MaxWeightFraction: .1}))
+	assert.NoError(t, s1.SetMaxGroupFraction(.25))
+	for _, vdrID := range vdrIDs {
+		s1.SetValidatorGroup(vdrID, "1.2.3.0/24")
+	}
+	s1.SetDistinctSampling(len(vdrIDs))
+
+	for seed := int64(0); seed < 10; seed++ {
+		sampled0, err := s0.SampleWithSeed(10, seed)
+		assert.NoError(t, err)
+		sampled1, err := s1.SampleWithSeed(10, seed)
+		assert.NoError(t, err)
+		assert.Equal(t, sampled0, sampled1)
+	}
+}
+
+func TestSamplerWithSeedAppliesMasks(t *testing.T) {
+	s0 := NewSet()
+	s1 := NewSet()
+	vdrIDs := make([]ids.NodeID, 20)
+	for i := range vdrIDs {
+		vdrIDs[i] = ids.GenerateTestNodeID()
+		assert.NoError(t, s0.AddWeight(vdrIDs[i], uint64(i+1)))
+		assert.NoError(t, s1.AddWeight(vdrIDs[i], uint64(i+1)))
+	}
+
+	// Sets with the same masks sample the same validators, and never the
+	// masked ones
+	assert.NoError(t, s0.MaskValidator(vdrIDs[19]))
+	assert.NoError(t, s1.MaskValidator(vdrIDs[19]))
+	for seed := int64(0); seed < 10; seed++ {
+		sampled0, err := s0.SampleWithSeed(10, seed)
+		assert.NoError(t, err)
+		sampled1, err := s1.SampleWithSeed(10, seed)
+		assert.NoError(t, err)
+		assert.Equal(t, sampled0, sampled1)
+		for _, vdr := range sampled0 {
+			assert.NotEqual(t, vdrIDs[19], vdr.ID())
+		}
+	}
+
+	// Revealing the validator makes it sampleable again
+	assert.NoError(t, s0.RevealValidator(vdrIDs[19]))
+	sampled := false
+	for seed := int64(0); seed < 10 && !sampled; seed++ {
+		vdrs, err := s0.SampleWithSeed(10, seed)
+		assert.NoError(t, err)
+		for _, vdr := range vdrs {
+			sampled = sampled || vdr.ID() == vdrIDs[19]
+		}
+	}
+	assert.True(t, sampled)
+}
+
+func TestSamplerMaxGroupFraction(t *testing.T) {
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+	vdr2 := ids.GenerateTestNodeID()
+
+	s := NewSet()
+	assert.NoError(t, s.AddWeight(vdr0, math.MaxInt32))
+	assert.NoError(t, s.AddWeight(vdr1, math.MaxInt32))
+	assert.NoError(t, s.AddWeight(vdr2, 1))
+	s.SetValidatorGroup(vdr0, "1.2.3.0/24")
+	s.SetValidatorGroup(vdr1, "1.2.3.0/24")
+	s.SetValidatorGroup(vdr2, "5.6.7.0/24")
+
+	assert.NoError(t, s.SetMaxGroupFraction(.5))
+	for i := 0; i < 10; i++ {
+		sampled, err := s.Sample(4)
+		assert.NoError(t, err)
+
+		numFromVdr2 := 0
+		for _, vdr := range sampled {
+			if vdr.ID() == vdr2 {
+				numFromVdr2++
+			}
+		}
+		assert.Equal(t, 2, numFromVdr2, "should have sampled half of the validators from the second group")
+	}
+
+	// The groups are rebuilt when a validator joins or changes its group
+	vdr3 := ids.GenerateTestNodeID()
+	assert.NoError(t, s.AddWeight(vdr3, math.MaxInt32))
+	s.SetValidatorGroup(vdr3, "5.6.7.0/24")
+	for i := 0; i < 10; i++ {
+		sampled, err := s.Sample(4)
+		assert.NoError(t, err)
+
+		numFromSecondGroup := 0
+		for _, vdr := range sampled {
+			if vdr.ID() == vdr2 || vdr.ID() == vdr3 {
+				numFromSecondGroup++
+			}
+		}
+		assert.Equal(t, 2, numFromSecondGroup, "should have sampled half of the validators from the second group")
+	}
+	assert.NoError(t, s.RemoveWeight(vdr3, math.MaxInt32))
+
+	// If the limit can't be met, it's ignored
+	s.SetValidatorGroup(vdr2, "1.2.3.0/24")
+	_, err := s.Sample(4)
+	assert.NoError(t, err)
+
+	assert.Error(t, s.SetMaxGroupFraction(-.1))
+	assert.Error(t, s.SetMaxGroupFraction(1.1))
+}
+
+func TestSamplerMaxGroupFractionDistinct(t *testing.T) {
+	vdr0 := ids.GenerateTestNodeID()
+	vdr1 := ids.GenerateTestNodeID()
+	vdr2 := ids.GenerateTestNodeID()
+	vdr3 := ids.GenerateTestNodeID()
+
+	s := NewSet()
+	assert.NoError(t, s.AddWeight(vdr0, math.MaxInt32))
+	assert.NoError(t, s.AddWeight(vdr1, math.MaxInt32))
+	assert.NoError(t, s.AddWeight(vdr2, 1))
+	assert.NoError(t, s.AddWeight(vdr3, 1))
+	s.SetValidatorGroup(vdr0, "1.2.3.0/24")
+	s.SetValidatorGroup(vdr1, "1.2.3.0/24")
+	s.SetValidatorGroup(vdr2, "5.6.7.0/24")
+	s.SetValidatorGroup(vdr3, "5.6.7.0/24")
+
+	assert.NoError(t, s.SetMaxGroupFraction(.5))
+	s.SetDistinctSampling(4)
+	for i := 0; i < 10; i++ {
+		sampled, err := s.Sample(4)
+		assert.NoError(t, err)
+
+		// Without distinct sampling, the light validators would be sampled
+		// twice each to meet the group limit
+		sampledIDs := ids.NewNodeIDSet(len(sampled))
+		for _, vdr := range sampled {
+			sampledIDs.Add(vdr.ID())
+		}
+		assert.Len(t, sampledIDs, 4, "should have sampled each validator once")
+	}
+}
+
+func TestSamplerWithSeedIgnoresOrder(t *testing.T) {
+	vdrIDs := make([]ids.NodeID, 50)
+	for i := range vdrIDs {
+		vdrIDs[i] = ids.GenerateTestNodeID()
+	}
+	removedID := ids.GenerateTestNodeID()
+
+	// [s0] adds the validators in order
+	s0 := NewSet()
+	for i, vdrID := range vdrIDs {
+		assert.NoError(t, s0.AddWeight(vdrID, uint64(i+1)))
+	}
+
+	// [s1] adds them in reverse order, with another validator that is removed
+	// after it has been sampled, which moves validators around in the set
+	s1 := NewSet()
+	assert.NoError(t, s1.AddWeight(removedID, 1000))
+	for i := len(vdrIDs) - 1; i >= 0; i-- {
+		assert.NoError(t, s1.AddWeight(vdrIDs[i], uint64(i+1)))
+	}
+	_, err := s1.SampleWithSeed(10, 1)
+	assert.NoError(t, err)
+	_, err = s1.Sample(10)
+	assert.NoError(t, err)
+	assert.NoError(t, s1.RemoveWeight(removedID, 1000))
+
+	// [s2] is built from a list in a different order, and has weight added
+	// and removed
+	s2 := NewSet()
+	vdrs := make([]Validator, len(vdrIDs))
+	for i, vdrID := range vdrIDs {
+		vdrs[(i*7)%len(vdrIDs)] = NewValidator(vdrID, uint64(i+1))
+	}
+	assert.NoError(t, s2.Set(vdrs))
+	assert.NoError(t, s2.AddWeight(vdrIDs[0], 5))
+	_, err = s2.SampleWithSeed(10, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, s2.RemoveWeight(vdrIDs[0], 5))
+
+	for seed := int64(0); seed < 10; seed++ {
+		sampled0, err := s0.SampleWithSeed(20, seed)
+		assert.NoError(t, err)
+		sampled1, err := s1.SampleWithSeed(20, seed)
+		assert.NoError(t, err)
+		sampled2, err := s2.SampleWithSeed(20, seed)
+		assert.NoError(t, err)
+
+		ids0, ids1, ids2 := make([]ids.NodeID, 20), make([]ids.NodeID, 20), make([]ids.NodeID, 20)
+		for i := range ids0 {
+			ids0[i], ids1[i], ids2[i] = sampled0[i].ID(), sampled1[i].ID(), sampled2[i].ID()
+		}
+		assert.Equal(t, ids0, ids1)
+		assert.Equal(t, ids0, ids2)
+	}
+}
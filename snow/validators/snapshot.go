@@ -0,0 +1,186 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var (
+	errUnsortedSnapshot  = errors.New("snapshot validators aren't sorted and unique")
+	errUnsortedChains    = errors.New("snapshot chains aren't sorted and unique")
+	errZeroWeight        = errors.New("snapshot validator has no weight")
+	errTrailingBytes     = errors.New("snapshot has trailing bytes")
+	errTooManyValidators = errors.New("snapshot has too many validators")
+	errTooManyChains     = errors.New("snapshot has too many chains")
+)
+
+// SnapshotValidator is a validator in a snapshot
+type SnapshotValidator struct {
+	NodeID ids.NodeID
+	Weight uint64
+}
+
+// SnapshotChain is a chain of the subnet in a snapshot, which a node that
+// syncs the snapshot creates without waiting for the platform chain
+type SnapshotChain struct {
+	ID          ids.ID
+	VMID        ids.ID
+	FxIDs       []ids.ID
+	GenesisData []byte
+}
+
+// Snapshot is the validator set and the chains of a subnet at some point in
+// time
+type Snapshot struct {
+	SubnetID ids.ID
+	// Sorted by node ID
+	Validators []SnapshotValidator
+	// Sorted by chain ID
+	Chains []SnapshotChain
+}
+
+// NewSnapshot returns a snapshot of the validators of [subnetID] in [vdrs]
+// and of the subnet's [chains]
+func NewSnapshot(subnetID ids.ID, vdrs Set, chains []SnapshotChain) *Snapshot {
+	vdrList := vdrs.List()
+	nodeIDs := make([]ids.NodeID, len(vdrList))
+	weights := make(map[ids.NodeID]uint64, len(vdrList))
+	for i, vdr := range vdrList {
+		nodeIDs[i] = vdr.ID()
+		weights[vdr.ID()] = vdr.Weight()
+	}
+	ids.SortNodeIDs(nodeIDs)
+
+	snapshot := &Snapshot{
+		SubnetID:   subnetID,
+		Validators: make([]SnapshotValidator, len(nodeIDs)),
+	}
+	for i, nodeID := range nodeIDs {
+		snapshot.Validators[i] = SnapshotValidator{
+			NodeID: nodeID,
+			Weight: weights[nodeID],
+		}
+	}
+	snapshot.Chains = append(snapshot.Chains, chains...)
+	sort.Slice(snapshot.Chains, func(i, j int) bool {
+		return bytes.Compare(snapshot.Chains[i].ID[:], snapshot.Chains[j].ID[:]) < 0
+	})
+	return snapshot
+}
+
+// ParseSnapshot parses a snapshot from the output of Bytes
+func ParseSnapshot(b []byte) (*Snapshot, error) {
+	p := wrappers.Packer{Bytes: b}
+	snapshot := &Snapshot{}
+	copy(snapshot.SubnetID[:], p.UnpackFixedBytes(hashing.HashLen))
+	numValidators := p.UnpackInt()
+	if p.Errored() {
+		return nil, p.Err
+	}
+	// Each validator takes at least this many bytes, so the number of
+	// validators can be bounded before allocating
+	if int(numValidators) > (len(b)-p.Offset)/(hashing.AddrLen+wrappers.LongLen) {
+		return nil, errTooManyValidators
+	}
+
+	snapshot.Validators = make([]SnapshotValidator, numValidators)
+	for i := range snapshot.Validators {
+		vdr := &snapshot.Validators[i]
+		copy(vdr.NodeID[:], p.UnpackFixedBytes(hashing.AddrLen))
+		vdr.Weight = p.UnpackLong()
+	}
+	numChains := p.UnpackInt()
+	if p.Errored() {
+		return nil, p.Err
+	}
+	// Each chain takes at least this many bytes
+	if int(numChains) > (len(b)-p.Offset)/(2*hashing.HashLen+2*wrappers.IntLen) {
+		return nil, errTooManyChains
+	}
+	if numChains > 0 {
+		snapshot.Chains = make([]SnapshotChain, numChains)
+	}
+	for i := range snapshot.Chains {
+		chain := &snapshot.Chains[i]
+		copy(chain.ID[:], p.UnpackFixedBytes(hashing.HashLen))
+		copy(chain.VMID[:], p.UnpackFixedBytes(hashing.HashLen))
+		for _, fxIDBytes := range p.UnpackFixedByteSlices(hashing.HashLen) {
+			fxID := ids.ID{}
+			copy(fxID[:], fxIDBytes)
+			chain.FxIDs = append(chain.FxIDs, fxID)
+		}
+		chain.GenesisData = p.UnpackBytes()
+	}
+	switch {
+	case p.Errored():
+		return nil, p.Err
+	case p.Offset != len(b):
+		return nil, errTrailingBytes
+	}
+
+	for i, vdr := range snapshot.Validators {
+		if vdr.Weight == 0 {
+			return nil, errZeroWeight
+		}
+		if i > 0 && bytes.Compare(snapshot.Validators[i-1].NodeID[:], vdr.NodeID[:]) >= 0 {
+			return nil, errUnsortedSnapshot
+		}
+	}
+	for i := 1; i < len(snapshot.Chains); i++ {
+		if bytes.Compare(snapshot.Chains[i-1].ID[:], snapshot.Chains[i].ID[:]) >= 0 {
+			return nil, errUnsortedChains
+		}
+	}
+	return snapshot, nil
+}
+
+// Bytes returns the canonical representation of the snapshot
+func (s *Snapshot) Bytes() []byte {
+	p := wrappers.Packer{
+		MaxSize: math.MaxInt32,
+		Bytes:   make([]byte, 0, hashing.HashLen+2*wrappers.IntLen+len(s.Validators)*(hashing.AddrLen+wrappers.LongLen)),
+	}
+	p.PackFixedBytes(s.SubnetID[:])
+	p.PackInt(uint32(len(s.Validators)))
+	for _, vdr := range s.Validators {
+		p.PackFixedBytes(vdr.NodeID[:])
+		p.PackLong(vdr.Weight)
+	}
+	p.PackInt(uint32(len(s.Chains)))
+	for _, chain := range s.Chains {
+		p.PackFixedBytes(chain.ID[:])
+		p.PackFixedBytes(chain.VMID[:])
+		fxIDs := make([][]byte, len(chain.FxIDs))
+		for i, fxID := range chain.FxIDs {
+			fxIDs[i] = fxID[:]
+		}
+		p.PackFixedByteSlices(fxIDs)
+		p.PackBytes(chain.GenesisData)
+	}
+	return p.Bytes
+}
+
+// ID returns the hash of the snapshot
+func (s *Snapshot) ID() ids.ID {
+	return hashing.ComputeHash256Array(s.Bytes())
+}
+
+// Set returns a validator set containing the validators in the snapshot
+func (s *Snapshot) Set() (Set, error) {
+	vdrs := NewSet()
+	for _, vdr := range s.Validators {
+		if err := vdrs.AddWeight(vdr.NodeID, vdr.Weight); err != nil {
+			return nil, err
+		}
+	}
+	return vdrs, nil
+}
@@ -0,0 +1,385 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+const (
+	// DefaultSnapshotThreshold is the fraction of the trusted stake that must
+	// attest to a snapshot before it is used
+	DefaultSnapshotThreshold = .67
+
+	// Time that this node's attestation of a validator set is reused for
+	// before the validator set is read again. Attestations are only signed
+	// again if the validator set changed.
+	signedSnapshotTTL = 10 * time.Second
+)
+
+var (
+	errNotTrusted           = errors.New("not trusted to attest to snapshots")
+	errInvalidSnapshotSig   = errors.New("invalid snapshot signature")
+	errInsufficientSigners  = errors.New("signers don't hold enough stake")
+	errUnknownTrustedVdrs   = errors.New("unknown trusted validators")
+	errWrongRegistrant      = errors.New("BLS key is registered by another node")
+	errUnregisteredKey      = errors.New("BLS key isn't registered on the platform chain")
+	errMismatchedRegistered = errors.New("BLS key doesn't match the key registered on the platform chain")
+)
+
+// SignedSnapshot is a node's attestation that [Snapshot] is the validator set
+// and the chains of a subnet
+type SignedSnapshot struct {
+	Snapshot []byte
+	// Binds the BLS key that signed the snapshot to the attesting node
+	Registration BLSKeyRegistration
+	Signature    []byte
+}
+
+// SnapshotCertificate proves that trusted validators attested to a snapshot
+type SnapshotCertificate struct {
+	Snapshot *Snapshot
+	// Sorted node IDs of the validators that attested to the snapshot
+	Signers []ids.NodeID
+	// Aggregate of the signers' signatures of the snapshot
+	Signature *bls.Signature
+}
+
+// Verify returns nil if the signers of the certificate hold at least
+// [threshold] of the weight of [trustedVdrs] and signed the snapshot
+func (c *SnapshotCertificate) Verify(trustedVdrs Set, keys BLSKeys, threshold float64) error {
+	signers := ids.NodeIDSet{}
+	signers.Add(c.Signers...)
+	signedWeight := uint64(0)
+	for nodeID := range signers {
+		vdr, ok := trustedVdrs.Get(nodeID)
+		if !ok {
+			return fmt.Errorf("%s is %w", nodeID, errNotTrusted)
+		}
+		signedWeight += vdr.Weight()
+	}
+	if float64(signedWeight) < threshold*float64(trustedVdrs.Weight()) {
+		return errInsufficientSigners
+	}
+	return keys.Verify(signers, c.Snapshot.Bytes(), c.Signature)
+}
+
+// SnapshotSyncer fetches the validator sets and the chains of subnets from
+// peers, so that a node that starts tracking a subnet can start the subnet's
+// chains without deriving the subnet from the platform chain first. A snapshot
+// is only used once trusted validators holding enough stake attested to it.
+//
+// Until the platform chain is bootstrapped, the node's view of the primary
+// network's validators is out of date, so the beacons are trusted, as they are
+// trusted to bootstrap the platform chain. Afterwards, the primary network's
+// validators are trusted, and their attestations must be signed with the BLS
+// keys they registered on the platform chain.
+type SnapshotSyncer interface {
+	// Sign returns this node's attestation of [subnetID]. Returns false if
+	// this node doesn't know the subnet's validator set, or if the platform
+	// chain isn't bootstrapped, in which case the validator set may be out of
+	// date.
+	Sign(subnetID ids.ID) (*SignedSnapshot, bool)
+
+	// Track marks [subnetID] to be synced. Returns false if the validator set
+	// is already known.
+	Track(subnetID ids.ID) bool
+
+	// Pending returns the subnets that are being synced
+	Pending() []ids.ID
+
+	// Add the attestation of [nodeID]. If enough trusted stake has attested to
+	// the same snapshot, the snapshot becomes the subnet's validator set and
+	// its certificate is returned.
+	Add(nodeID ids.NodeID, signed *SignedSnapshot) (*SnapshotCertificate, error)
+
+	// AddChain adds [chain] to the chains of [subnetID] that are included in
+	// this node's attestations
+	AddChain(subnetID ids.ID, chain SnapshotChain)
+
+	// Bootstrapped marks the platform chain as bootstrapped
+	Bootstrapped()
+}
+
+// NewSnapshotSyncer returns a new SnapshotSyncer that sets the validator sets
+// of synced subnets in [vdrs] and then calls [onSynced], if it isn't nil.
+// [beacons] are trusted until the platform chain is bootstrapped. [registered]
+// are the BLS keys registered on the platform chain. Attestations are signed
+// with [sk], which [registration] binds to this node.
+func NewSnapshotSyncer(
+	vdrs Manager,
+	beacons Set,
+	registered BLSKeys,
+	registration *BLSKeyRegistration,
+	sk *bls.SecretKey,
+	threshold float64,
+	onSynced func(*SnapshotCertificate),
+) SnapshotSyncer {
+	return &snapshotSyncer{
+		vdrs:         vdrs,
+		beacons:      beacons,
+		registered:   registered,
+		attestedKeys: NewBLSKeys(),
+		registration: registration,
+		sk:           sk,
+		threshold:    threshold,
+		onSynced:     onSynced,
+		chains:       make(map[ids.ID][]SnapshotChain),
+		signed:       make(map[ids.ID]*signedSnapshot),
+		pending:      make(map[ids.ID]*snapshotAttestations),
+	}
+}
+
+type snapshotSyncer struct {
+	lock  sync.Mutex
+	clock timer.Clock
+
+	vdrs       Manager
+	beacons    Set
+	registered BLSKeys
+	// BLS keys of the nodes that attested to snapshots
+	attestedKeys BLSKeys
+	registration *BLSKeyRegistration
+	sk           *bls.SecretKey
+	threshold    float64
+	onSynced     func(*SnapshotCertificate)
+
+	bootstrapped bool
+
+	// Subnet ID --> The subnet's chains
+	chains map[ids.ID][]SnapshotChain
+
+	// Subnet ID --> This node's latest attestation of the subnet
+	signed map[ids.ID]*signedSnapshot
+
+	// Subnet ID --> Attestations of the subnet
+	pending map[ids.ID]*snapshotAttestations
+}
+
+type signedSnapshot struct {
+	signed   *SignedSnapshot
+	signedAt time.Time
+}
+
+type snapshotAttestations struct {
+	// Snapshot ID --> Snapshot
+	snapshots map[ids.ID]*Snapshot
+	// Node ID --> ID of the snapshot the node attested to
+	attested map[ids.NodeID]ids.ID
+	// Node ID --> The node's signature
+	signatures map[ids.NodeID]*bls.Signature
+}
+
+// Sign implements the SnapshotSyncer interface.
+func (s *snapshotSyncer) Sign(subnetID ids.ID) (*SignedSnapshot, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.bootstrapped {
+		return nil, false
+	}
+
+	// Signing is much more expensive than reading the validator set, so a
+	// recent attestation is reused, and the attestation is only signed again
+	// once the subnet changed
+	now := s.clock.Time()
+	cached, ok := s.signed[subnetID]
+	if ok && now.Sub(cached.signedAt) < signedSnapshotTTL {
+		return cached.signed, true
+	}
+	vdrs, ok := s.vdrs.GetValidators(subnetID)
+	if !ok {
+		return nil, false
+	}
+	snapshotBytes := NewSnapshot(subnetID, vdrs, s.chains[subnetID]).Bytes()
+	if cached != nil && bytes.Equal(cached.signed.Snapshot, snapshotBytes) {
+		cached.signedAt = now
+		return cached.signed, true
+	}
+
+	signed := &SignedSnapshot{
+		Snapshot:     snapshotBytes,
+		Registration: *s.registration,
+		Signature:    s.sk.Sign(snapshotBytes).Bytes(),
+	}
+	s.signed[subnetID] = &signedSnapshot{
+		signed:   signed,
+		signedAt: now,
+	}
+	return signed, true
+}
+
+// Track implements the SnapshotSyncer interface.
+func (s *snapshotSyncer) Track(subnetID ids.ID) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.vdrs.GetValidators(subnetID); ok {
+		return false
+	}
+	if _, ok := s.pending[subnetID]; !ok {
+		s.pending[subnetID] = &snapshotAttestations{
+			snapshots:  make(map[ids.ID]*Snapshot),
+			attested:   make(map[ids.NodeID]ids.ID),
+			signatures: make(map[ids.NodeID]*bls.Signature),
+		}
+	}
+	return true
+}
+
+// Pending implements the SnapshotSyncer interface.
+func (s *snapshotSyncer) Pending() []ids.ID {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	subnetIDs := make([]ids.ID, 0, len(s.pending))
+	for subnetID := range s.pending {
+		if _, ok := s.vdrs.GetValidators(subnetID); ok {
+			// The validator set was learned from the platform chain
+			delete(s.pending, subnetID)
+			continue
+		}
+		subnetIDs = append(subnetIDs, subnetID)
+	}
+	return subnetIDs
+}
+
+// AddChain implements the SnapshotSyncer interface.
+func (s *snapshotSyncer) AddChain(subnetID ids.ID, chain SnapshotChain) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, existing := range s.chains[subnetID] {
+		if existing.ID == chain.ID {
+			return
+		}
+	}
+	s.chains[subnetID] = append(s.chains[subnetID], chain)
+}
+
+// Bootstrapped implements the SnapshotSyncer interface.
+func (s *snapshotSyncer) Bootstrapped() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.bootstrapped = true
+}
+
+// Add implements the SnapshotSyncer interface.
+func (s *snapshotSyncer) Add(nodeID ids.NodeID, signed *SignedSnapshot) (*SnapshotCertificate, error) {
+	cert, err := s.add(nodeID, signed)
+	if cert != nil && s.onSynced != nil {
+		s.onSynced(cert)
+	}
+	return cert, err
+}
+
+func (s *snapshotSyncer) add(nodeID ids.NodeID, signed *SignedSnapshot) (*SnapshotCertificate, error) {
+	snapshot, err := ParseSnapshot(signed.Snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse snapshot: %w", err)
+	}
+	registrant, pk, err := signed.Registration.Verify()
+	if err != nil {
+		return nil, err
+	}
+	if registrant != nodeID {
+		return nil, errWrongRegistrant
+	}
+	sig, err := bls.SignatureFromBytes(signed.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if !bls.Verify(pk, sig, signed.Snapshot) {
+		return nil, errInvalidSnapshotSig
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	attestations, ok := s.pending[snapshot.SubnetID]
+	if !ok {
+		return nil, nil // This subnet isn't being synced
+	}
+
+	// The key registered on the platform chain takes precedence over the
+	// attested key, so a node can revoke a compromised key by registering
+	// another one
+	trustedVdrs := s.beacons
+	registeredPK, registered := s.registered.Get(nodeID)
+	if s.bootstrapped {
+		trustedVdrs, ok = s.vdrs.GetValidators(constants.PrimaryNetworkID)
+		if !ok {
+			return nil, errUnknownTrustedVdrs
+		}
+		if !registered {
+			return nil, errUnregisteredKey
+		}
+	}
+	if registered && !bytes.Equal(registeredPK.Bytes(), pk.Bytes()) {
+		return nil, errMismatchedRegistered
+	}
+	if !trustedVdrs.Contains(nodeID) {
+		return nil, fmt.Errorf("%s is %w", nodeID, errNotTrusted)
+	}
+	pop, err := bls.SignatureFromBytes(signed.Registration.ProofOfPossession)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attestedKeys.Register(nodeID, pk, pop); err != nil {
+		return nil, err
+	}
+
+	// Only the most recent attestation of each node counts
+	snapshotID := snapshot.ID()
+	attestations.snapshots[snapshotID] = snapshot
+	attestations.attested[nodeID] = snapshotID
+	attestations.signatures[nodeID] = sig
+
+	signers := []ids.NodeID(nil)
+	sigs := []*bls.Signature(nil)
+	for signer, attestedID := range attestations.attested {
+		if attestedID != snapshotID {
+			continue
+		}
+		signers = append(signers, signer)
+		sigs = append(sigs, attestations.signatures[signer])
+	}
+	ids.SortNodeIDs(signers)
+	aggregateSig, err := bls.AggregateSignatures(sigs)
+	if err != nil {
+		return nil, err
+	}
+	cert := &SnapshotCertificate{
+		Snapshot:  snapshot,
+		Signers:   signers,
+		Signature: aggregateSig,
+	}
+	if err := cert.Verify(trustedVdrs, s.attestedKeys, s.threshold); err == errInsufficientSigners {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	delete(s.pending, snapshot.SubnetID)
+	if _, ok := s.vdrs.GetValidators(snapshot.SubnetID); ok {
+		// The validator set was learned from the platform chain in the
+		// meantime, which takes precedence
+		return cert, nil
+	}
+	vdrs, err := snapshot.Set()
+	if err != nil {
+		return nil, err
+	}
+	return cert, s.vdrs.Set(snapshot.SubnetID, vdrs)
+}
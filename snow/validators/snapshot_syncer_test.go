@@ -0,0 +1,228 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+)
+
+type testSnapshotNode struct {
+	nodeID       ids.NodeID
+	sk           *bls.SecretKey
+	registration *BLSKeyRegistration
+}
+
+func newTestSnapshotNode(t *testing.T) *testSnapshotNode {
+	cert, err := staking.NewTLSCert()
+	assert.NoError(t, err)
+	sk, err := bls.NewSecretKey()
+	assert.NoError(t, err)
+	registration, err := NewBLSKeyRegistration(cert.Leaf, cert.PrivateKey.(crypto.Signer), sk)
+	assert.NoError(t, err)
+	nodeID, _, err := registration.Verify()
+	assert.NoError(t, err)
+	return &testSnapshotNode{
+		nodeID:       nodeID,
+		sk:           sk,
+		registration: registration,
+	}
+}
+
+func (n *testSnapshotNode) newSyncer(vdrs Manager, beacons Set, registered BLSKeys, onSynced func(*SnapshotCertificate)) SnapshotSyncer {
+	return NewSnapshotSyncer(vdrs, beacons, registered, n.registration, n.sk, .5, onSynced)
+}
+
+func TestSnapshotSyncer(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	chain := SnapshotChain{
+		ID:          ids.GenerateTestID(),
+		VMID:        ids.GenerateTestID(),
+		FxIDs:       []ids.ID{ids.GenerateTestID()},
+		GenesisData: []byte("genesis"),
+	}
+	nodes := []*testSnapshotNode{
+		newTestSnapshotNode(t),
+		newTestSnapshotNode(t),
+		newTestSnapshotNode(t),
+	}
+	subnetVdr := ids.GenerateTestNodeID()
+
+	// The beacons, which know the subnet's validators and chains
+	serverVdrs := NewManager()
+	beacons := NewSet()
+	for _, node := range nodes {
+		assert.NoError(t, serverVdrs.AddWeight(constants.PrimaryNetworkID, node.nodeID, 10))
+		assert.NoError(t, beacons.AddWeight(node.nodeID, 1))
+	}
+	assert.NoError(t, serverVdrs.AddWeight(subnetID, subnetVdr, 5))
+	servers := []SnapshotSyncer{
+		nodes[0].newSyncer(serverVdrs, beacons, NewBLSKeys(), nil),
+		nodes[1].newSyncer(serverVdrs, beacons, NewBLSKeys(), nil),
+	}
+	for _, server := range servers {
+		_, ok := server.Sign(subnetID)
+		assert.False(t, ok, "shouldn't attest before the platform chain is bootstrapped")
+		server.AddChain(subnetID, chain)
+		server.Bootstrapped()
+	}
+
+	// A node whose platform chain isn't bootstrapped, which only trusts the
+	// beacons
+	clientVdrs := NewManager()
+	synced := []*SnapshotCertificate(nil)
+	client := newTestSnapshotNode(t).newSyncer(clientVdrs, beacons, NewBLSKeys(), func(cert *SnapshotCertificate) {
+		synced = append(synced, cert)
+	})
+
+	assert.True(t, client.Track(subnetID))
+	assert.Equal(t, []ids.ID{subnetID}, client.Pending())
+
+	signed0, ok := servers[0].Sign(subnetID)
+	assert.True(t, ok)
+	signed1, ok := servers[1].Sign(subnetID)
+	assert.True(t, ok)
+
+	// Attestations must come from trusted nodes
+	_, err := client.Add(subnetVdr, signed0)
+	assert.Error(t, err)
+
+	// The attestation's key must be registered by the attesting node
+	_, err = client.Add(nodes[1].nodeID, signed0)
+	assert.Error(t, err)
+
+	// Attestations must be signed
+	forged := *signed0
+	forged.Signature = signed1.Signature
+	_, err = client.Add(nodes[0].nodeID, &forged)
+	assert.Error(t, err)
+
+	// One attestation isn't enough
+	cert, err := client.Add(nodes[0].nodeID, signed0)
+	assert.NoError(t, err)
+	assert.Nil(t, cert)
+
+	// An attestation of a different validator set doesn't count
+	otherVdrs := NewSet()
+	assert.NoError(t, otherVdrs.AddWeight(nodes[2].nodeID, 1))
+	otherServer := nodes[1].newSyncer(NewManager(), beacons, NewBLSKeys(), nil)
+	assert.NoError(t, otherServer.(*snapshotSyncer).vdrs.Set(subnetID, otherVdrs))
+	otherServer.Bootstrapped()
+	signedOther, ok := otherServer.Sign(subnetID)
+	assert.True(t, ok)
+	cert, err = client.Add(nodes[1].nodeID, signedOther)
+	assert.NoError(t, err)
+	assert.Nil(t, cert)
+	assert.Empty(t, synced)
+
+	// nodes[1] changes its attestation
+	cert, err = client.Add(nodes[1].nodeID, signed1)
+	assert.NoError(t, err)
+	assert.NotNil(t, cert)
+	assert.Equal(t, subnetID, cert.Snapshot.SubnetID)
+	assert.Equal(t, []SnapshotChain{chain}, cert.Snapshot.Chains)
+	assert.Len(t, cert.Signers, 2)
+	assert.Equal(t, []*SnapshotCertificate{cert}, synced)
+
+	vdrs, ok := clientVdrs.GetValidators(subnetID)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(5), vdrs.Weight())
+	assert.True(t, vdrs.Contains(subnetVdr))
+	assert.Empty(t, client.Pending())
+
+	// The certificate proves the snapshot to anyone who knows the signers'
+	// keys and trusts the beacons
+	keys := NewBLSKeys()
+	for _, node := range nodes[:2] {
+		assert.NoError(t, keys.Register(node.nodeID, node.sk.PublicKey(), node.sk.SignProofOfPossession()))
+	}
+	assert.NoError(t, cert.Verify(beacons, keys, .5))
+	assert.Error(t, cert.Verify(beacons, keys, .9), "signers don't hold 90% of the stake")
+
+	// Late attestations are ignored
+	lateServer := nodes[2].newSyncer(serverVdrs, beacons, NewBLSKeys(), nil)
+	lateServer.Bootstrapped()
+	signed2, ok := lateServer.Sign(subnetID)
+	assert.True(t, ok)
+	cert, err = client.Add(nodes[2].nodeID, signed2)
+	assert.NoError(t, err)
+	assert.Nil(t, cert)
+}
+
+func TestSnapshotSyncerRegisteredKeys(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	server := newTestSnapshotNode(t)
+	serverVdrs := NewManager()
+	assert.NoError(t, serverVdrs.AddWeight(constants.PrimaryNetworkID, server.nodeID, 1))
+	assert.NoError(t, serverVdrs.AddWeight(subnetID, ids.GenerateTestNodeID(), 1))
+	beacons := NewSet()
+	assert.NoError(t, beacons.AddWeight(server.nodeID, 1))
+	serverSyncer := server.newSyncer(serverVdrs, beacons, NewBLSKeys(), nil)
+	serverSyncer.Bootstrapped()
+	signed, ok := serverSyncer.Sign(subnetID)
+	assert.True(t, ok)
+
+	// Once the platform chain is bootstrapped, the attesting node must have
+	// registered its key on the platform chain
+	registered := NewBLSKeys()
+	clientVdrs := NewManager()
+	assert.NoError(t, clientVdrs.AddWeight(constants.PrimaryNetworkID, server.nodeID, 1))
+	client := newTestSnapshotNode(t).newSyncer(clientVdrs, NewSet(), registered, nil)
+	client.Bootstrapped()
+	assert.True(t, client.Track(subnetID))
+	_, err := client.Add(server.nodeID, signed)
+	assert.Error(t, err)
+
+	// A key other than the registered key is rejected
+	otherSK, err := bls.NewSecretKey()
+	assert.NoError(t, err)
+	assert.NoError(t, registered.Register(server.nodeID, otherSK.PublicKey(), otherSK.SignProofOfPossession()))
+	_, err = client.Add(server.nodeID, signed)
+	assert.Error(t, err)
+
+	assert.NoError(t, registered.Register(server.nodeID, server.sk.PublicKey(), server.sk.SignProofOfPossession()))
+	cert, err := client.Add(server.nodeID, signed)
+	assert.NoError(t, err)
+	assert.NotNil(t, cert)
+}
+
+func TestSnapshotSyncerSignCache(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	node := newTestSnapshotNode(t)
+	vdrs := NewManager()
+	assert.NoError(t, vdrs.AddWeight(subnetID, ids.GenerateTestNodeID(), 1))
+	syncer := node.newSyncer(vdrs, NewSet(), NewBLSKeys(), nil)
+	syncer.Bootstrapped()
+	clock := &syncer.(*snapshotSyncer).clock
+	now := clock.Time()
+	clock.Set(now)
+
+	signed, ok := syncer.Sign(subnetID)
+	assert.True(t, ok)
+
+	// A recent attestation is reused even if the validator set changed
+	assert.NoError(t, vdrs.AddWeight(subnetID, ids.GenerateTestNodeID(), 1))
+	cached, ok := syncer.Sign(subnetID)
+	assert.True(t, ok)
+	assert.Same(t, signed, cached)
+
+	// Once the attestation expires, it's signed again with the new validators
+	clock.Set(now.Add(signedSnapshotTTL))
+	resigned, ok := syncer.Sign(subnetID)
+	assert.True(t, ok)
+	assert.NotEqual(t, signed.Snapshot, resigned.Snapshot)
+
+	// An expired attestation of an unchanged validator set isn't signed again
+	clock.Set(now.Add(2 * signedSnapshotTTL))
+	cached, ok = syncer.Sign(subnetID)
+	assert.True(t, ok)
+	assert.Same(t, resigned, cached)
+}
@@ -0,0 +1,125 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestSnapshot(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	vdr0 := ids.NodeID{2}
+	vdr1 := ids.NodeID{1}
+
+	vdrs := NewSet()
+	assert.NoError(t, vdrs.AddWeight(vdr0, 1))
+	assert.NoError(t, vdrs.AddWeight(vdr1, 2))
+
+	chain0 := SnapshotChain{
+		ID:          ids.ID{2},
+		VMID:        ids.GenerateTestID(),
+		FxIDs:       []ids.ID{ids.GenerateTestID()},
+		GenesisData: []byte("genesis"),
+	}
+	chain1 := SnapshotChain{
+		ID:          ids.ID{1},
+		VMID:        ids.GenerateTestID(),
+		GenesisData: []byte{},
+	}
+
+	snapshot := NewSnapshot(subnetID, vdrs, []SnapshotChain{chain0, chain1})
+	assert.Equal(t, subnetID, snapshot.SubnetID)
+	assert.Equal(t, []SnapshotValidator{
+		{NodeID: vdr1, Weight: 2},
+		{NodeID: vdr0, Weight: 1},
+	}, snapshot.Validators)
+	assert.Equal(t, []SnapshotChain{chain1, chain0}, snapshot.Chains)
+
+	parsed, err := ParseSnapshot(snapshot.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, parsed)
+	assert.Equal(t, snapshot.ID(), parsed.ID())
+
+	parsedVdrs, err := parsed.Set()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), parsedVdrs.Weight())
+	assert.True(t, parsedVdrs.Contains(vdr0))
+	assert.True(t, parsedVdrs.Contains(vdr1))
+}
+
+func TestParseSnapshotInvalid(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+	tests := []struct {
+		name     string
+		snapshot *Snapshot
+	}{
+		{
+			name: "unsorted",
+			snapshot: &Snapshot{
+				SubnetID: subnetID,
+				Validators: []SnapshotValidator{
+					{NodeID: ids.NodeID{2}, Weight: 1},
+					{NodeID: ids.NodeID{1}, Weight: 1},
+				},
+			},
+		},
+		{
+			name: "duplicate",
+			snapshot: &Snapshot{
+				SubnetID: subnetID,
+				Validators: []SnapshotValidator{
+					{NodeID: ids.NodeID{1}, Weight: 1},
+					{NodeID: ids.NodeID{1}, Weight: 1},
+				},
+			},
+		},
+		{
+			name: "unsorted chains",
+			snapshot: &Snapshot{
+				SubnetID: subnetID,
+				Chains: []SnapshotChain{
+					{ID: ids.ID{2}},
+					{ID: ids.ID{1}},
+				},
+			},
+		},
+		{
+			name: "zero weight",
+			snapshot: &Snapshot{
+				SubnetID: subnetID,
+				Validators: []SnapshotValidator{
+					{NodeID: ids.NodeID{1}, Weight: 0},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseSnapshot(test.snapshot.Bytes())
+			assert.Error(t, err)
+		})
+	}
+
+	validBytes := (&Snapshot{SubnetID: subnetID}).Bytes()
+	_, err := ParseSnapshot(append(validBytes, 0))
+	assert.Error(t, err, "should have errored due to trailing bytes")
+	_, err = ParseSnapshot(validBytes[:len(validBytes)-1])
+	assert.Error(t, err, "should have errored due to missing bytes")
+
+	// Claims to have more chains than there are bytes for
+	tooMany := append([]byte(nil), validBytes...)
+	tooMany[len(tooMany)-1] = 0xff
+	_, err = ParseSnapshot(tooMany)
+	assert.Error(t, err, "should have errored due to too many chains")
+
+	// Claims to have more validators than there are bytes for
+	tooMany = append([]byte(nil), validBytes...)
+	tooMany[len(tooMany)-5] = 0xff
+	_, err = ParseSnapshot(tooMany)
+	assert.Error(t, err, "should have errored due to too many validators")
+}
@@ -0,0 +1,28 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Subscriber is notified when the validator set of a subnet changes.
+//
+// Subscribers are called synchronously, in the order of the changes, after the
+// Manager is unlocked. They may read from the Manager, but they must not block
+// or change the validator sets, since the changes that follow aren't notified
+// until they return.
+type Subscriber interface {
+	// OnValidatorAdded is called when [validatorID] joins the validator set
+	// of [subnetID] with [weight].
+	OnValidatorAdded(subnetID ids.ID, validatorID ids.NodeID, weight uint64)
+
+	// OnValidatorRemoved is called when [validatorID], which had [weight],
+	// leaves the validator set of [subnetID].
+	OnValidatorRemoved(subnetID ids.ID, validatorID ids.NodeID, weight uint64)
+
+	// OnValidatorWeightChanged is called when the weight of [validatorID] in
+	// the validator set of [subnetID] changes from [oldWeight] to [newWeight].
+	OnValidatorWeightChanged(subnetID ids.ID, validatorID ids.NodeID, oldWeight, newWeight uint64)
+}
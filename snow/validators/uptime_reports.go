@@ -0,0 +1,225 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+// maxReportedBuckets is the number of the most recent buckets that peers'
+// reports are kept for
+const maxReportedBuckets = 2
+
+var (
+	errNotValidator      = errors.New("reporter isn't a validator")
+	errUnalignedBucket   = errors.New("bucket isn't aligned to the bucket duration")
+	errFutureBucket      = errors.New("bucket hasn't ended")
+	errStaleBucket       = errors.New("bucket is too old")
+	errDuplicateReport   = errors.New("already reported on this bucket")
+	errTooManyUptimes    = errors.New("report has more uptimes than there are validators")
+	errInvalidUpDuration = errors.New("up duration is longer than the bucket")
+)
+
+// UptimeReport is the time a validator observed each of the primary network's
+// validators to be up during the bucket of time starting at [Start]
+type UptimeReport struct {
+	Start   time.Time
+	Uptimes map[ids.NodeID]time.Duration
+}
+
+// UptimeReports exchanges the uptimes that the primary network's validators
+// observe each other to have. The platform chain sets this node's report,
+// which the network sends to peers, and takes the reports the network receives
+// from peers.
+type UptimeReports interface {
+	// SetLocal sets this node's report of the latest bucket that ended and
+	// calls the callback that the reports were created with, if any
+	SetLocal(report UptimeReport)
+
+	// Local returns this node's report of the latest bucket that ended.
+	// Returns false if there isn't one.
+	Local() (UptimeReport, bool)
+
+	// Add the report of [nodeID]. Returns an error if [nodeID] isn't a
+	// validator or the report is invalid.
+	Add(nodeID ids.NodeID, report UptimeReport) error
+
+	// Take returns, for each validator reported on, the stake weighted median
+	// of the up durations reported during the bucket starting at [start], and
+	// forgets the reports of that bucket and of the buckets before it
+	Take(start time.Time) map[ids.NodeID]time.Duration
+}
+
+// NewUptimeReports returns a new UptimeReports that accepts reports from
+// [vdrs], on buckets of [bucketDuration], and calls [onLocal] with this node's
+// reports, if it isn't nil.
+func NewUptimeReports(vdrs Set, bucketDuration time.Duration, onLocal func(UptimeReport)) UptimeReports {
+	return &uptimeReports{
+		vdrs:           vdrs,
+		bucketDuration: bucketDuration,
+		onLocal:        onLocal,
+		reports:        make(map[int64]map[ids.NodeID]map[ids.NodeID]time.Duration),
+	}
+}
+
+type uptimeReports struct {
+	lock  sync.Mutex
+	clock timer.Clock
+
+	vdrs           Set
+	bucketDuration time.Duration
+	onLocal        func(UptimeReport)
+
+	local    UptimeReport
+	hasLocal bool
+
+	// Bucket start, in Unix seconds --> Reporter --> Validator --> Up duration
+	reports map[int64]map[ids.NodeID]map[ids.NodeID]time.Duration
+	// Start of the most recent bucket reported on, in Unix seconds
+	latest int64
+	// Start of the oldest bucket that hasn't been taken, in Unix seconds
+	untaken int64
+}
+
+// SetLocal implements the UptimeReports interface
+func (r *uptimeReports) SetLocal(report UptimeReport) {
+	r.lock.Lock()
+	r.local = report
+	r.hasLocal = true
+	r.lock.Unlock()
+
+	if r.onLocal != nil {
+		r.onLocal(report)
+	}
+}
+
+// Local implements the UptimeReports interface
+func (r *uptimeReports) Local() (UptimeReport, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.local, r.hasLocal
+}
+
+// Add implements the UptimeReports interface
+func (r *uptimeReports) Add(nodeID ids.NodeID, report UptimeReport) error {
+	if !r.vdrs.Contains(nodeID) {
+		return errNotValidator
+	}
+	if !report.Start.Truncate(r.bucketDuration).Equal(report.Start) {
+		return errUnalignedBucket
+	}
+	if report.Start.Add(r.bucketDuration).After(r.clock.Time()) {
+		return errFutureBucket
+	}
+	if len(report.Uptimes) > r.vdrs.Len() {
+		return errTooManyUptimes
+	}
+	for vdrID, upDuration := range report.Uptimes {
+		if upDuration < 0 || upDuration > r.bucketDuration {
+			return fmt.Errorf("%w: %s", errInvalidUpDuration, vdrID)
+		}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	start := report.Start.Unix()
+	oldest := r.latest - int64(maxReportedBuckets-1)*int64(r.bucketDuration/time.Second)
+	if start < oldest || start < r.untaken {
+		return errStaleBucket
+	}
+	bucket, ok := r.reports[start]
+	if !ok {
+		bucket = make(map[ids.NodeID]map[ids.NodeID]time.Duration)
+		r.reports[start] = bucket
+	}
+	if _, reported := bucket[nodeID]; reported {
+		return errDuplicateReport
+	}
+	bucket[nodeID] = report.Uptimes
+
+	if start > r.latest {
+		r.latest = start
+		r.prune(start - int64(maxReportedBuckets-1)*int64(r.bucketDuration/time.Second))
+	}
+	return nil
+}
+
+// Take implements the UptimeReports interface
+func (r *uptimeReports) Take(start time.Time) map[ids.NodeID]time.Duration {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	bucket := r.reports[start.Unix()]
+	r.prune(start.Unix() + 1)
+	if next := start.Add(r.bucketDuration).Unix(); next > r.untaken {
+		r.untaken = next
+	}
+
+	// Validator --> The reported up durations, with the weights of their
+	// reporters
+	reported := make(map[ids.NodeID][]weightedDuration)
+	for reporterID, uptimes := range bucket {
+		weight, ok := r.vdrs.GetWeight(reporterID)
+		if !ok {
+			// The reporter stopped validating since it reported
+			continue
+		}
+		for vdrID, upDuration := range uptimes {
+			reported[vdrID] = append(reported[vdrID], weightedDuration{
+				duration: upDuration,
+				weight:   weight,
+			})
+		}
+	}
+
+	medians := make(map[ids.NodeID]time.Duration, len(reported))
+	for vdrID, durations := range reported {
+		medians[vdrID] = weightedMedian(durations)
+	}
+	return medians
+}
+
+// prune removes the reports of the buckets that start before [start]
+// Assumes [r.lock] is held
+func (r *uptimeReports) prune(start int64) {
+	for bucketStart := range r.reports {
+		if bucketStart < start {
+			delete(r.reports, bucketStart)
+		}
+	}
+}
+
+type weightedDuration struct {
+	duration time.Duration
+	weight   uint64
+}
+
+// weightedMedian returns the duration that at least half of the weight of
+// [durations] is at or below
+func weightedMedian(durations []weightedDuration) time.Duration {
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i].duration < durations[j].duration
+	})
+	totalWeight := uint64(0)
+	for _, d := range durations {
+		totalWeight += d.weight
+	}
+	cumulativeWeight := uint64(0)
+	for _, d := range durations {
+		cumulativeWeight += d.weight
+		if cumulativeWeight >= totalWeight-cumulativeWeight {
+			return d.duration
+		}
+	}
+	return 0
+}
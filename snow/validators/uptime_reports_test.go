@@ -0,0 +1,107 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestUptimeReportsMedian(t *testing.T) {
+	assert := assert.New(t)
+
+	vdrs := NewSet()
+	vdr0, vdr1, vdr2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	assert.NoError(vdrs.AddWeight(vdr0, 1))
+	assert.NoError(vdrs.AddWeight(vdr1, 1))
+	assert.NoError(vdrs.AddWeight(vdr2, 3))
+
+	var local UptimeReport
+	reports := NewUptimeReports(vdrs, time.Hour, func(report UptimeReport) { local = report }).(*uptimeReports)
+	start := time.Unix(0, 0)
+	reports.clock.Set(start.Add(time.Hour))
+
+	report := UptimeReport{
+		Start:   start,
+		Uptimes: map[ids.NodeID]time.Duration{vdr0: time.Minute},
+	}
+	reports.SetLocal(report)
+	assert.Equal(report, local)
+	gotLocal, ok := reports.Local()
+	assert.True(ok)
+	assert.Equal(report, gotLocal)
+
+	assert.NoError(reports.Add(vdr0, UptimeReport{
+		Start:   start,
+		Uptimes: map[ids.NodeID]time.Duration{vdr0: time.Hour, vdr1: 0},
+	}))
+	assert.NoError(reports.Add(vdr1, UptimeReport{
+		Start:   start,
+		Uptimes: map[ids.NodeID]time.Duration{vdr0: time.Hour, vdr1: time.Hour},
+	}))
+	assert.NoError(reports.Add(vdr2, UptimeReport{
+		Start:   start,
+		Uptimes: map[ids.NodeID]time.Duration{vdr0: 10 * time.Minute, vdr1: 30 * time.Minute},
+	}))
+
+	// [vdr2] has more than half of the weight, so its reports are the medians
+	assert.Equal(map[ids.NodeID]time.Duration{
+		vdr0: 10 * time.Minute,
+		vdr1: 30 * time.Minute,
+	}, reports.Take(start))
+
+	// The reports were forgotten
+	assert.Empty(reports.Take(start))
+	assert.True(errors.Is(reports.Add(vdr0, UptimeReport{Start: start}), errStaleBucket))
+}
+
+func TestUptimeReportsInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	vdrs := NewSet()
+	vdr := ids.GenerateTestNodeID()
+	assert.NoError(vdrs.AddWeight(vdr, 1))
+
+	reports := NewUptimeReports(vdrs, time.Hour, nil).(*uptimeReports)
+	start := time.Unix(0, 0).Add(2 * time.Hour)
+	reports.clock.Set(start.Add(time.Hour))
+
+	err := reports.Add(ids.GenerateTestNodeID(), UptimeReport{Start: start})
+	assert.True(errors.Is(err, errNotValidator))
+
+	err = reports.Add(vdr, UptimeReport{Start: start.Add(time.Minute)})
+	assert.True(errors.Is(err, errUnalignedBucket))
+
+	err = reports.Add(vdr, UptimeReport{Start: start.Add(time.Hour)})
+	assert.True(errors.Is(err, errFutureBucket))
+
+	err = reports.Add(vdr, UptimeReport{
+		Start: start,
+		Uptimes: map[ids.NodeID]time.Duration{
+			vdr:                      time.Minute,
+			ids.GenerateTestNodeID(): time.Minute,
+		},
+	})
+	assert.True(errors.Is(err, errTooManyUptimes))
+
+	err = reports.Add(vdr, UptimeReport{
+		Start:   start,
+		Uptimes: map[ids.NodeID]time.Duration{vdr: time.Hour + 1},
+	})
+	assert.True(errors.Is(err, errInvalidUpDuration))
+
+	assert.NoError(reports.Add(vdr, UptimeReport{Start: start}))
+	err = reports.Add(vdr, UptimeReport{Start: start})
+	assert.True(errors.Is(err, errDuplicateReport))
+
+	// The bucket before the latest is still accepted, but older ones aren't
+	assert.NoError(reports.Add(vdr, UptimeReport{Start: start.Add(-time.Hour)}))
+	err = reports.Add(vdr, UptimeReport{Start: start.Add(-2 * time.Hour)})
+	assert.True(errors.Is(err, errStaleBucket))
+}
@@ -14,7 +14,7 @@ import (
 // Validator is the minimal description of someone that can be sampled.
 type Validator interface {
 	// ID returns the node ID of this validator
-	ID() ids.ShortID
+	ID() ids.NodeID
 
 	// Weight that can be used for weighted sampling. If this validator is
 	// validating the primary network, returns the amount of AVAX staked.
@@ -24,12 +24,12 @@ type Validator interface {
 // validator is a struct that contains the base values required by the validator
 // interface.
 type validator struct {
-	nodeID ids.ShortID
+	nodeID ids.NodeID
 	weight uint64
 }
 
-func (v *validator) ID() ids.ShortID { return v.nodeID }
-func (v *validator) Weight() uint64  { return v.weight }
+func (v *validator) ID() ids.NodeID { return v.nodeID }
+func (v *validator) Weight() uint64 { return v.weight }
 
 func (v *validator) addWeight(weight uint64) {
 	newTotalWeight, err := safemath.Add64(weight, v.weight)
@@ -50,7 +50,7 @@ func (v *validator) removeWeight(weight uint64) {
 // NewValidator returns a validator object that implements the Validator
 // interface
 func NewValidator(
-	nodeID ids.ShortID,
+	nodeID ids.NodeID,
 	weight uint64,
 ) Validator {
 	return &validator{
@@ -61,7 +61,7 @@ func NewValidator(
 
 // GenerateRandomValidator creates a random validator with the provided weight
 func GenerateRandomValidator(weight uint64) Validator {
-	nodeID := ids.GenerateTestShortID()
+	nodeID := ids.GenerateTestNodeID()
 	return NewValidator(
 		nodeID,
 		weight,
@@ -0,0 +1,53 @@
+package staking
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+const blsKeyPEMType = "BLS PRIVATE KEY"
+
+var errInvalidBLSKey = errors.New("couldn't find a PEM encoded BLS key")
+
+// InitNodeBLSKey generates a BLS key, which the node attests to validator
+// snapshots with, and writes it to [keyPath]. If there is already a file at
+// [keyPath], returns nil.
+func InitNodeBLSKey(keyPath string) error {
+	// If there is already a file at [keyPath], do nothing
+	if _, err := os.Stat(keyPath); !os.IsNotExist(err) {
+		return nil
+	}
+
+	sk, err := bls.NewSecretKey()
+	if err != nil {
+		return fmt.Errorf("couldn't generate BLS key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), perms.ReadWriteExecute); err != nil {
+		return fmt.Errorf("couldn't create path for BLS key: %w", err)
+	}
+	keyBytes := pem.EncodeToMemory(&pem.Block{Type: blsKeyPEMType, Bytes: sk.Bytes()})
+	if err := ioutil.WriteFile(keyPath, keyBytes, perms.ReadOnly); err != nil {
+		return fmt.Errorf("couldn't write BLS key: %w", err)
+	}
+	return nil
+}
+
+// LoadBLSKey loads the BLS key at [keyPath]
+func LoadBLSKey(keyPath string) (*bls.SecretKey, error) {
+	keyBytes, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(keyBytes)
+	if block == nil || block.Type != blsKeyPEMType {
+		return nil, errInvalidBLSKey
+	}
+	return bls.SecretKeyFromBytes(block.Bytes)
+}
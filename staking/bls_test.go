@@ -0,0 +1,34 @@
+package staking
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitNodeBLSKey(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "staking")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	keyPath := filepath.Join(dir, "signer.key")
+	assert.NoError(InitNodeBLSKey(keyPath))
+	sk, err := LoadBLSKey(keyPath)
+	assert.NoError(err)
+
+	// The key is kept across restarts
+	assert.NoError(InitNodeBLSKey(keyPath))
+	loadedSK, err := LoadBLSKey(keyPath)
+	assert.NoError(err)
+	assert.Equal(sk.Bytes(), loadedSK.Bytes())
+
+	certPath := filepath.Join(dir, "staker.crt")
+	assert.NoError(InitNodeStakingKeyPair(filepath.Join(dir, "staker.key"), certPath))
+	_, err = LoadBLSKey(certPath)
+	assert.Error(err, "should have rejected a file that isn't a BLS key")
+}
@@ -0,0 +1,62 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"errors"
+	"os/exec"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var errWrongSigner = errors.New("wrong signer type")
+
+// Launch starts the signer plugin at [path] and returns a signer that signs
+// with it
+func Launch(path string, log logging.Logger) (*Client, error) {
+	// Ignore warning from launching an executable with a variable command
+	// because the command is a controlled and required input
+
+	// #nosec G204
+	cmd := exec.Command(path)
+
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig:  Handshake,
+		Plugins:          PluginMap,
+		Cmd:              cmd,
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolNetRPC},
+		Stderr:           log,
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Output: log,
+			Level:  hclog.Info,
+		}),
+		// Ensure the plugin is killed by plugin.CleanupClients when the node
+		// shuts down
+		Managed: true,
+	})
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, err
+	}
+
+	raw, err := rpcClient.Dispense("signer")
+	if err != nil {
+		client.Kill()
+		return nil, err
+	}
+
+	signer, ok := raw.(*Client)
+	if !ok {
+		client.Kill()
+		return nil, errWrongSigner
+	}
+
+	signer.SetProcess(client)
+	return signer, nil
+}
@@ -0,0 +1,58 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package signer allows the node's staking key to be held by a separate
+// process, such as one backed by an HSM or a PKCS#11 token, rather than read
+// from a file on disk. The node sends the digests it needs signed to the
+// process and never sees the key.
+package signer
+
+import (
+	"crypto"
+	"net/rpc"
+
+	"github.com/hashicorp/go-plugin"
+)
+
+// Handshake is a common handshake that is shared by the node and the signer.
+var Handshake = plugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "STAKING_SIGNER_PLUGIN",
+	MagicCookieValue: "dynamic",
+}
+
+// PluginMap is the map of plugins we can dispense.
+var PluginMap = map[string]plugin.Plugin{
+	"signer": &Plugin{},
+}
+
+// Plugin is the implementation of plugin.Plugin so we can serve/consume this.
+// The signer is served over net/rpc.
+type Plugin struct {
+	// signer is only set on the plugin side
+	signer crypto.Signer
+}
+
+// New returns a new plugin that signs with [signer]
+func New(signer crypto.Signer) *Plugin { return &Plugin{signer: signer} }
+
+// Server returns the net/rpc server of the signer
+func (p *Plugin) Server(*plugin.MuxBroker) (interface{}, error) {
+	return NewServer(p.signer), nil
+}
+
+// Client returns a signer that sends its requests over [c]
+func (p *Plugin) Client(_ *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewClient(c)
+}
+
+// Serve serves [signer] to the node that launched this process. Blocks until
+// the node shuts down the process.
+func Serve(signer crypto.Signer) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins: map[string]plugin.Plugin{
+			"signer": New(signer),
+		},
+	})
+}
@@ -0,0 +1,70 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"io"
+	"net/rpc"
+
+	"github.com/hashicorp/go-plugin"
+)
+
+var _ crypto.Signer = &Client{}
+
+// Client is a crypto.Signer whose signatures are created by a signer plugin
+type Client struct {
+	client    *rpc.Client
+	publicKey crypto.PublicKey
+	proc      *plugin.Client
+}
+
+// NewClient returns a signer that sends its requests over [client]
+func NewClient(client *rpc.Client) (*Client, error) {
+	reply := PublicKeyReply{}
+	if err := client.Call("Plugin.Public", struct{}{}, &reply); err != nil {
+		return nil, err
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(reply.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		client:    client,
+		publicKey: publicKey,
+	}, nil
+}
+
+// SetProcess gives ownership of the server process to the client.
+func (c *Client) SetProcess(proc *plugin.Client) { c.proc = proc }
+
+// Public implements the crypto.Signer interface
+func (c *Client) Public() crypto.PublicKey { return c.publicKey }
+
+// Sign implements the crypto.Signer interface. The randomness used to sign is
+// provided by the plugin, so [rand] is ignored.
+func (c *Client) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	args := &SignArgs{
+		Digest: digest,
+		Hash:   opts.HashFunc(),
+	}
+	if pssOpts, ok := opts.(*rsa.PSSOptions); ok {
+		args.PSS = true
+		args.SaltLength = pssOpts.SaltLength
+	}
+
+	reply := SignReply{}
+	err := c.client.Call("Plugin.Sign", args, &reply)
+	return reply.Signature, err
+}
+
+// Close shuts down the server process, if the client owns it
+func (c *Client) Close() error {
+	if c.proc != nil {
+		c.proc.Kill()
+	}
+	return nil
+}
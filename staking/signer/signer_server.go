@@ -0,0 +1,69 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+)
+
+// PublicKeyReply is the reply to a Public request
+type PublicKeyReply struct {
+	// PKIX, ASN.1 DER encoding of the signer's public key
+	PublicKey []byte
+}
+
+// SignArgs are the arguments to a Sign request
+type SignArgs struct {
+	Digest []byte
+	Hash   crypto.Hash
+	// If true, [Digest] is signed with RSA-PSS using [SaltLength]
+	PSS        bool
+	SaltLength int
+}
+
+// SignReply is the reply to a Sign request
+type SignReply struct {
+	Signature []byte
+}
+
+// Server serves a crypto.Signer over net/rpc
+type Server struct {
+	signer crypto.Signer
+}
+
+// NewServer returns a server that signs with [signer]
+func NewServer(signer crypto.Signer) *Server {
+	return &Server{signer: signer}
+}
+
+// Public returns the public key of the signer
+func (s *Server) Public(_ struct{}, reply *PublicKeyReply) error {
+	publicKey, err := x509.MarshalPKIXPublicKey(s.signer.Public())
+	if err != nil {
+		return err
+	}
+	reply.PublicKey = publicKey
+	return nil
+}
+
+// Sign signs the provided digest
+func (s *Server) Sign(args *SignArgs, reply *SignReply) error {
+	var opts crypto.SignerOpts = args.Hash
+	if args.PSS {
+		opts = &rsa.PSSOptions{
+			SaltLength: args.SaltLength,
+			Hash:       args.Hash,
+		}
+	}
+
+	sig, err := s.signer.Sign(rand.Reader, args.Digest, opts)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig
+	return nil
+}
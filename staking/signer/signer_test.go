@@ -0,0 +1,62 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func dispense(t *testing.T, signer crypto.Signer) crypto.Signer {
+	client, _ := plugin.TestPluginRPCConn(t, map[string]plugin.Plugin{
+		"signer": New(signer),
+	}, nil)
+
+	raw, err := client.Dispense("signer")
+	assert.NoError(t, err)
+	signerClient, ok := raw.(*Client)
+	assert.True(t, ok)
+	return signerClient
+}
+
+func TestSignerRSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	signer := dispense(t, key)
+	assert.Equal(t, &key.PublicKey, signer.Public())
+
+	digest := sha256.Sum256([]byte("hello"))
+
+	sig, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	assert.NoError(t, err)
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig))
+
+	pssOpts := &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       crypto.SHA256,
+	}
+	sig, err = signer.Sign(rand.Reader, digest[:], pssOpts)
+	assert.NoError(t, err)
+	assert.NoError(t, rsa.VerifyPSS(&key.PublicKey, crypto.SHA256, digest[:], sig, pssOpts))
+}
+
+func TestSignerECDSA(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	signer := dispense(t, key)
+	assert.Equal(t, &key.PublicKey, signer.Public())
+
+	digest := sha256.Sum256([]byte("hello"))
+	sig, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	assert.NoError(t, err)
+	assert.True(t, ecdsa.VerifyASN1(&key.PublicKey, digest[:], sig))
+}
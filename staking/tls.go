@@ -2,12 +2,15 @@ package staking
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"os"
 	"path/filepath"
@@ -16,6 +19,11 @@ import (
 	"github.com/ava-labs/avalanchego/utils/perms"
 )
 
+var (
+	errInvalidCertificate = errors.New("couldn't find a PEM encoded certificate")
+	errSignerMismatch     = errors.New("signer's public key doesn't match the certificate")
+)
+
 // InitNodeStakingKeyPair generates a self-signed TLS key/cert pair to use in
 // staking. The key and files will be placed at [keyPath] and [certPath],
 // respectively. If there is already a file at [keyPath], returns nil.
@@ -83,6 +91,40 @@ func LoadTLSCert(keyPath, certPath string) (*tls.Certificate, error) {
 	return &cert, nil
 }
 
+// LoadTLSCertWithoutKey loads the certificate at [certPath]. The private key of
+// the returned certificate must be set before it is used.
+func LoadTLSCertWithoutKey(certPath string) (*tls.Certificate, error) {
+	certBytes, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(certBytes)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errInvalidCertificate
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Certificate{
+		Certificate: [][]byte{block.Bytes},
+		Leaf:        leaf,
+	}, nil
+}
+
+// SetSigner sets the private key of [cert] to [signer]. Returns an error if the
+// public key of [signer] isn't the public key of [cert].
+func SetSigner(cert *tls.Certificate, signer crypto.Signer) error {
+	publicKey, ok := signer.Public().(interface {
+		Equal(crypto.PublicKey) bool
+	})
+	if !ok || !publicKey.Equal(cert.Leaf.PublicKey) {
+		return errSignerMismatch
+	}
+	cert.PrivateKey = signer
+	return nil
+}
+
 func NewTLSCert() (*tls.Certificate, error) {
 	certBytes, keyBytes, err := newStakerKeys()
 	if err != nil {
@@ -4,6 +4,9 @@ import (
 	"crypto"
 	"crypto/rand"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -26,3 +29,30 @@ func TestMakeKeys(t *testing.T) {
 	err = cert.Leaf.CheckSignature(cert.Leaf.SignatureAlgorithm, msg, sig)
 	assert.NoError(err)
 }
+
+func TestLoadTLSCertWithSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "staking")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	keyPath := filepath.Join(dir, "staker.key")
+	certPath := filepath.Join(dir, "staker.crt")
+	assert.NoError(InitNodeStakingKeyPair(keyPath, certPath))
+	keyCert, err := LoadTLSCert(keyPath, certPath)
+	assert.NoError(err)
+
+	cert, err := LoadTLSCertWithoutKey(certPath)
+	assert.NoError(err)
+	assert.Equal(keyCert.Certificate, cert.Certificate)
+	assert.Equal(keyCert.Leaf, cert.Leaf)
+	assert.Nil(cert.PrivateKey)
+
+	otherCert, err := NewTLSCert()
+	assert.NoError(err)
+	assert.Error(SetSigner(cert, otherCert.PrivateKey.(crypto.Signer)), "should have rejected a signer with a different key")
+
+	assert.NoError(SetSigner(cert, keyCert.PrivateKey.(crypto.Signer)))
+	assert.Equal(keyCert.PrivateKey, cert.PrivateKey)
+}
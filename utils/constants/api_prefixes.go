@@ -3,10 +3,12 @@
 
 package constants
 
+import "github.com/ava-labs/avalanchego/ids"
+
 const (
 	// NodeIDPrefix is used to denote node addresses rather than other
 	// addresses.
-	NodeIDPrefix string = "NodeID-"
+	NodeIDPrefix string = ids.NodeIDPrefix
 
 	// SecretKeyPrefix is used to denote secret keys rather than other byte
 	// arrays.
@@ -31,6 +31,13 @@ const (
 	MultiPutMsg
 	GetAncestorsFailedMsg
 	TimeoutMsg
+	CrossSubnetMsg
+	GetStateSummaryFrontierMsg
+	StateSummaryFrontierMsg
+	GetStateSummaryFrontierFailedMsg
+	GetAcceptedStateSummaryMsg
+	AcceptedStateSummaryMsg
+	GetAcceptedStateSummaryFailedMsg
 )
 
 func (t MsgType) String() string {
@@ -79,6 +86,20 @@ func (t MsgType) String() string {
 		return "Notify"
 	case GossipMsg:
 		return "Gossip"
+	case CrossSubnetMsg:
+		return "Cross Subnet"
+	case GetStateSummaryFrontierMsg:
+		return "Get State Summary Frontier"
+	case StateSummaryFrontierMsg:
+		return "State Summary Frontier"
+	case GetStateSummaryFrontierFailedMsg:
+		return "Get State Summary Frontier Failed"
+	case GetAcceptedStateSummaryMsg:
+		return "Get Accepted State Summary"
+	case AcceptedStateSummaryMsg:
+		return "Accepted State Summary"
+	case GetAcceptedStateSummaryFailedMsg:
+		return "Get Accepted State Summary Failed"
 	default:
 		return fmt.Sprintf("Unknown Message Type: %d", t)
 	}
@@ -0,0 +1,57 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bls
+
+import (
+	"errors"
+	"math/big"
+	"sync/atomic"
+
+	"golang.org/x/crypto/bn256"
+)
+
+var (
+	errNoPublicKeys      = errors.New("no public keys to aggregate")
+	errNoSignatures      = errors.New("no signatures to aggregate")
+	errUnprovenKey       = errors.New("public key's proof of possession wasn't verified")
+	errInfiniteAggregate = errors.New("aggregate is the point at infinity")
+)
+
+// AggregatePublicKeys returns the public key that verifies the aggregate of
+// signatures of the same message by each of [pks]. Each of [pks] must have had
+// its proof of possession verified, so that a key chosen to cancel out the
+// other keys can't be aggregated.
+func AggregatePublicKeys(pks []*PublicKey) (*PublicKey, error) {
+	if len(pks) == 0 {
+		return nil, errNoPublicKeys
+	}
+	aggregate := new(bn256.G2).ScalarBaseMult(new(big.Int))
+	for _, pk := range pks {
+		if atomic.LoadUint32(&pk.proven) == 0 {
+			return nil, errUnprovenKey
+		}
+		aggregate = new(bn256.G2).Add(aggregate, pk.pk)
+	}
+	if isInfinity(aggregate.Marshal()) {
+		return nil, errInfiniteAggregate
+	}
+	return &PublicKey{pk: aggregate}, nil
+}
+
+// AggregateSignatures returns the aggregate of [sigs]. If each of [sigs] is a
+// signature of the same message, the aggregate is verified against the
+// aggregate of the signers' public keys.
+func AggregateSignatures(sigs []*Signature) (*Signature, error) {
+	if len(sigs) == 0 {
+		return nil, errNoSignatures
+	}
+	aggregate := new(bn256.G1).ScalarBaseMult(new(big.Int))
+	for _, sig := range sigs {
+		aggregate = new(bn256.G1).Add(aggregate, sig.sig)
+	}
+	if isInfinity(aggregate.Marshal()) {
+		return nil, errInfiniteAggregate
+	}
+	return &Signature{sig: aggregate}, nil
+}
@@ -0,0 +1,201 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package bls implements BLS signatures over the BN256 pairing friendly curve.
+//
+// Signatures are in G1 and public keys are in G2. Messages are hashed to G1
+// with the hash_to_curve construction of RFC 9380. Signatures of the same
+// message can be aggregated into a single signature that is verified against
+// the aggregate of the signers' public keys. To prevent rogue key attacks, a
+// public key can only be aggregated after its proof of possession has been
+// verified. The point at infinity is neither a valid public key nor a valid
+// signature.
+//
+// Recent attacks on BN curves reduce the security of BN256 to roughly 100
+// bits, and the arithmetic of the curve isn't constant time, so keys should be
+// replaced once a BLS12-381 implementation such as blst is available to the
+// node.
+package bls
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sync/atomic"
+
+	"golang.org/x/crypto/bn256"
+)
+
+const (
+	// SecretKeyLen is the number of bytes in a secret key
+	SecretKeyLen = 32
+
+	// PublicKeyLen is the number of bytes in a public key
+	PublicKeyLen = 128
+
+	// SignatureLen is the number of bytes in a signature
+	SignatureLen = 64
+
+	fieldElementLen = 32
+)
+
+var (
+	// Domain separation tags, so that a signature of a message can't be used
+	// as a proof of possession, or vice versa.
+	signatureDST         = []byte("AVALANCHE_BLS_SIG_BN256G1_XMD:SHA-256_SVDW_RO_")
+	proofOfPossessionDST = []byte("AVALANCHE_BLS_POP_BN256G1_XMD:SHA-256_SVDW_RO_")
+
+	// The modulus of the field that the curve is defined over
+	fieldModulus, _ = new(big.Int).SetString("65000549695646603732796438742359905742825358107623003571877145026864184071783", 10)
+	// (fieldModulus + 1) / 4, used to compute square roots, as
+	// fieldModulus = 3 mod 4
+	sqrtExponent = new(big.Int).Rsh(new(big.Int).Add(fieldModulus, big.NewInt(1)), 2)
+	curveB       = big.NewInt(3)
+
+	g2Generator = new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+
+	errInvalidSecretKey = errors.New("invalid secret key")
+	errInvalidPublicKey = errors.New("invalid public key")
+	errInvalidSignature = errors.New("invalid signature")
+)
+
+// SecretKey is a BLS secret key
+type SecretKey struct {
+	sk *big.Int
+}
+
+// NewSecretKey returns a new, random secret key
+func NewSecretKey() (*SecretKey, error) {
+	sk, _, err := bn256.RandomG2(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretKey{sk: sk}, nil
+}
+
+// SecretKeyFromBytes parses a secret key from the output of Bytes
+func SecretKeyFromBytes(b []byte) (*SecretKey, error) {
+	if len(b) != SecretKeyLen {
+		return nil, errInvalidSecretKey
+	}
+	sk := new(big.Int).SetBytes(b)
+	if sk.Sign() == 0 || sk.Cmp(bn256.Order) >= 0 {
+		return nil, errInvalidSecretKey
+	}
+	return &SecretKey{sk: sk}, nil
+}
+
+// Bytes returns the canonical representation of the secret key
+func (k *SecretKey) Bytes() []byte {
+	b := make([]byte, SecretKeyLen)
+	skBytes := k.sk.Bytes()
+	copy(b[SecretKeyLen-len(skBytes):], skBytes)
+	return b
+}
+
+// PublicKey returns the public key that corresponds to this secret key. The
+// holder of the secret key possesses it, so it can be aggregated.
+func (k *SecretKey) PublicKey() *PublicKey {
+	return &PublicKey{
+		pk:     new(bn256.G2).ScalarBaseMult(k.sk),
+		proven: 1,
+	}
+}
+
+// Sign returns the signature of [msg]
+func (k *SecretKey) Sign(msg []byte) *Signature {
+	return k.sign(signatureDST, msg)
+}
+
+// SignProofOfPossession returns a proof that the holder of the public key of
+// this secret key also holds the secret key
+func (k *SecretKey) SignProofOfPossession() *Signature {
+	return k.sign(proofOfPossessionDST, k.PublicKey().Bytes())
+}
+
+func (k *SecretKey) sign(dst, msg []byte) *Signature {
+	return &Signature{sig: new(bn256.G1).ScalarMult(hashToG1(dst, msg), k.sk)}
+}
+
+// PublicKey is a BLS public key
+type PublicKey struct {
+	pk *bn256.G2
+	// 1 if the possession of the key's secret key was proven, so the key can
+	// be aggregated. Accessed atomically.
+	proven uint32
+}
+
+// PublicKeyFromBytes parses a public key from the output of Bytes. The point
+// at infinity isn't a valid public key.
+func PublicKeyFromBytes(b []byte) (*PublicKey, error) {
+	pk, ok := new(bn256.G2).Unmarshal(b)
+	if !ok || !bytes.Equal(pk.Marshal(), b) {
+		return nil, errInvalidPublicKey
+	}
+	if isInfinity(pk.Marshal()) {
+		return nil, errInvalidPublicKey
+	}
+	// The twist has a cofactor, so points on it aren't necessarily in G2
+	if !isInfinity(new(bn256.G2).ScalarMult(pk, bn256.Order).Marshal()) {
+		return nil, errInvalidPublicKey
+	}
+	return &PublicKey{pk: pk}, nil
+}
+
+// Bytes returns the canonical representation of the public key
+func (k *PublicKey) Bytes() []byte { return k.pk.Marshal() }
+
+// Signature is a BLS signature
+type Signature struct {
+	sig *bn256.G1
+}
+
+// SignatureFromBytes parses a signature from the output of Bytes. The point
+// at infinity isn't a valid signature.
+func SignatureFromBytes(b []byte) (*Signature, error) {
+	sig, ok := new(bn256.G1).Unmarshal(b)
+	if !ok || !bytes.Equal(sig.Marshal(), b) {
+		return nil, errInvalidSignature
+	}
+	if isInfinity(b) {
+		return nil, errInvalidSignature
+	}
+	return &Signature{sig: sig}, nil
+}
+
+// Bytes returns the canonical representation of the signature
+func (s *Signature) Bytes() []byte { return s.sig.Marshal() }
+
+// Verify returns true if [sig] is a signature of [msg] by the holder of the
+// secret key of [pk]
+func Verify(pk *PublicKey, sig *Signature, msg []byte) bool {
+	return verify(pk, sig, signatureDST, msg)
+}
+
+// VerifyProofOfPossession returns true if [sig] proves that the holder of
+// [pk] holds its secret key. Once it has returned true, [pk] can be
+// aggregated.
+func VerifyProofOfPossession(pk *PublicKey, sig *Signature) bool {
+	if !verify(pk, sig, proofOfPossessionDST, pk.Bytes()) {
+		return false
+	}
+	atomic.StoreUint32(&pk.proven, 1)
+	return true
+}
+
+// verify checks that e(sig, g2) == e(H(msg), pk)
+func verify(pk *PublicKey, sig *Signature, dst, msg []byte) bool {
+	lhs := bn256.Pair(sig.sig, g2Generator).Marshal()
+	rhs := bn256.Pair(hashToG1(dst, msg), pk.pk).Marshal()
+	return bytes.Equal(lhs, rhs)
+}
+
+func isInfinity(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
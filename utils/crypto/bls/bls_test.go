@@ -0,0 +1,134 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bls
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"golang.org/x/crypto/bn256"
+)
+
+func TestSignVerify(t *testing.T) {
+	sk, err := NewSecretKey()
+	assert.NoError(t, err)
+	pk := sk.PublicKey()
+
+	msg := []byte("hello")
+	sig := sk.Sign(msg)
+	assert.True(t, Verify(pk, sig, msg))
+	assert.False(t, Verify(pk, sig, []byte("goodbye")))
+
+	otherSK, err := NewSecretKey()
+	assert.NoError(t, err)
+	assert.False(t, Verify(otherSK.PublicKey(), sig, msg))
+
+	// A signature isn't a proof of possession
+	assert.False(t, VerifyProofOfPossession(pk, sk.Sign(pk.Bytes())))
+	assert.True(t, VerifyProofOfPossession(pk, sk.SignProofOfPossession()))
+	assert.False(t, VerifyProofOfPossession(otherSK.PublicKey(), sk.SignProofOfPossession()))
+}
+
+func TestSerialization(t *testing.T) {
+	sk, err := NewSecretKey()
+	assert.NoError(t, err)
+	pk := sk.PublicKey()
+	sig := sk.Sign([]byte("hello"))
+
+	skBytes := sk.Bytes()
+	assert.Len(t, skBytes, SecretKeyLen)
+	parsedSK, err := SecretKeyFromBytes(skBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, pk.Bytes(), parsedSK.PublicKey().Bytes())
+
+	pkBytes := pk.Bytes()
+	assert.Len(t, pkBytes, PublicKeyLen)
+	parsedPK, err := PublicKeyFromBytes(pkBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, pkBytes, parsedPK.Bytes())
+
+	sigBytes := sig.Bytes()
+	assert.Len(t, sigBytes, SignatureLen)
+	parsedSig, err := SignatureFromBytes(sigBytes)
+	assert.NoError(t, err)
+	assert.True(t, Verify(parsedPK, parsedSig, []byte("hello")))
+
+	_, err = SecretKeyFromBytes(make([]byte, SecretKeyLen))
+	assert.Error(t, err, "zero secret key should be invalid")
+	_, err = PublicKeyFromBytes(make([]byte, PublicKeyLen))
+	assert.Error(t, err, "point at infinity should be an invalid public key")
+	_, err = PublicKeyFromBytes(pkBytes[1:])
+	assert.Error(t, err, "short public key should be invalid")
+
+	badSig := make([]byte, SignatureLen)
+	copy(badSig, sigBytes)
+	badSig[SignatureLen-1] ^= 1
+	_, err = SignatureFromBytes(badSig)
+	assert.Error(t, err, "point not on the curve should be an invalid signature")
+	_, err = SignatureFromBytes(make([]byte, SignatureLen))
+	assert.Error(t, err, "point at infinity should be an invalid signature")
+}
+
+func TestAggregate(t *testing.T) {
+	msg := []byte("hello")
+
+	pks := []*PublicKey(nil)
+	sigs := []*Signature(nil)
+	for i := 0; i < 4; i++ {
+		sk, err := NewSecretKey()
+		assert.NoError(t, err)
+		pks = append(pks, sk.PublicKey())
+		sigs = append(sigs, sk.Sign(msg))
+	}
+	// The same signer may be aggregated more than once
+	pks = append(pks, pks[0])
+	sigs = append(sigs, sigs[0])
+
+	aggregatePK, err := AggregatePublicKeys(pks)
+	assert.NoError(t, err)
+	aggregateSig, err := AggregateSignatures(sigs)
+	assert.NoError(t, err)
+	assert.True(t, Verify(aggregatePK, aggregateSig, msg))
+
+	// Missing a signature
+	partialSig, err := AggregateSignatures(sigs[1:])
+	assert.NoError(t, err)
+	assert.False(t, Verify(aggregatePK, partialSig, msg))
+
+	_, err = AggregatePublicKeys(nil)
+	assert.Error(t, err)
+	_, err = AggregateSignatures(nil)
+	assert.Error(t, err)
+}
+
+func TestAggregateRequiresProofOfPossession(t *testing.T) {
+	sk, err := NewSecretKey()
+	assert.NoError(t, err)
+
+	// A parsed key can't be aggregated until its proof of possession is
+	// verified
+	pk, err := PublicKeyFromBytes(sk.PublicKey().Bytes())
+	assert.NoError(t, err)
+	_, err = AggregatePublicKeys([]*PublicKey{pk})
+	assert.Error(t, err)
+
+	otherSK, err := NewSecretKey()
+	assert.NoError(t, err)
+	assert.False(t, VerifyProofOfPossession(pk, otherSK.SignProofOfPossession()))
+	_, err = AggregatePublicKeys([]*PublicKey{pk})
+	assert.Error(t, err)
+
+	assert.True(t, VerifyProofOfPossession(pk, sk.SignProofOfPossession()))
+	_, err = AggregatePublicKeys([]*PublicKey{pk})
+	assert.NoError(t, err)
+
+	// Keys that cancel each other out aggregate to the point at infinity
+	negSK := &SecretKey{sk: new(big.Int).Sub(bn256.Order, sk.sk)}
+	_, err = AggregatePublicKeys([]*PublicKey{pk, negSK.PublicKey()})
+	assert.Error(t, err)
+	_, err = AggregateSignatures([]*Signature{sk.Sign([]byte("hello")), negSK.Sign([]byte("hello"))})
+	assert.Error(t, err)
+}
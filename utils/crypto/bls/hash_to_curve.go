@@ -0,0 +1,182 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bls
+
+import (
+	"crypto/sha256"
+	"math/big"
+
+	"golang.org/x/crypto/bn256"
+)
+
+const (
+	// Number of bytes hashed into each field element, which is
+	// ceil((ceil(log2(p)) + k) / 8) for a security level of k = 128 bits
+	hashToFieldLen = 48
+)
+
+var (
+	// (fieldModulus - 1) / 2, used to check whether an element is a square
+	legendreExponent = new(big.Int).Rsh(new(big.Int).Sub(fieldModulus, big.NewInt(1)), 1)
+	// fieldModulus - 2, used to invert elements
+	inverseExponent = new(big.Int).Sub(fieldModulus, big.NewInt(2))
+
+	// Constants of the Shallue-van de Woestijne map, which only depend on the
+	// curve
+	svdwZ, svdwC1, svdwC2, svdwC3, svdwC4 = svdwConstants()
+)
+
+// hashToG1 maps [msg] to a point in G1 whose discrete log is unknown with the
+// hash_to_curve construction of RFC 9380, using expand_message_xmd with
+// SHA-256 and the Shallue-van de Woestijne map, which applies to any curve. The
+// same operations are performed for every message. G1 has a cofactor of 1, so
+// the cofactor doesn't need to be cleared.
+func hashToG1(dst, msg []byte) *bn256.G1 {
+	uniform := expandMessageXMD(dst, msg, 2*hashToFieldLen)
+	u0 := new(big.Int).SetBytes(uniform[:hashToFieldLen])
+	u0.Mod(u0, fieldModulus)
+	u1 := new(big.Int).SetBytes(uniform[hashToFieldLen:])
+	u1.Mod(u1, fieldModulus)
+	return new(bn256.G1).Add(mapToG1(u0), mapToG1(u1))
+}
+
+// expandMessageXMD returns [length] uniformly random bytes derived from [msg]
+// as described by section 5.3.1 of RFC 9380. [dst] must be at most 255 bytes
+// and [length] at most 255*32 bytes.
+func expandMessageXMD(dst, msg []byte, length int) []byte {
+	dstPrime := append(append([]byte(nil), dst...), byte(len(dst)))
+
+	hasher := sha256.New()
+	_, _ = hasher.Write(make([]byte, hasher.BlockSize()))
+	_, _ = hasher.Write(msg)
+	_, _ = hasher.Write([]byte{byte(length >> 8), byte(length), 0})
+	_, _ = hasher.Write(dstPrime)
+	b0 := hasher.Sum(nil)
+
+	uniform := make([]byte, 0, length+sha256.Size)
+	bi := make([]byte, sha256.Size)
+	for i := 1; len(uniform) < length; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		hasher.Reset()
+		_, _ = hasher.Write(bi)
+		_, _ = hasher.Write([]byte{byte(i)})
+		_, _ = hasher.Write(dstPrime)
+		bi = hasher.Sum(nil)
+		uniform = append(uniform, bi...)
+	}
+	return uniform[:length]
+}
+
+// mapToG1 maps the field element [u] to a point in G1 with the straight-line
+// Shallue-van de Woestijne map of section 6.6.1 of RFC 9380
+func mapToG1(u *big.Int) *bn256.G1 {
+	tv1 := mul(mul(u, u), svdwC1)
+	tv2 := add(big.NewInt(1), tv1)
+	tv1 = sub(big.NewInt(1), tv1)
+	tv3 := inv0(mul(tv1, tv2))
+	tv4 := mul(mul(mul(u, tv1), tv3), svdwC3)
+
+	x1 := sub(svdwC2, tv4)
+	e1 := isSquare(curveEquation(x1))
+	x2 := add(svdwC2, tv4)
+	e2 := isSquare(curveEquation(x2)) && !e1
+	x3 := mul(tv2, tv2)
+	x3 = mul(x3, tv3)
+	x3 = mul(x3, x3)
+	x3 = add(mul(x3, svdwC4), svdwZ)
+
+	x := cmov(x3, x1, e1)
+	x = cmov(x, x2, e2)
+	y := sqrt(curveEquation(x))
+	y = cmov(sub(big.NewInt(0), y), y, sgn0(u) == sgn0(y))
+
+	point := make([]byte, 2*fieldElementLen)
+	x.FillBytes(point[:fieldElementLen])
+	y.FillBytes(point[fieldElementLen:])
+	g1, _ := new(bn256.G1).Unmarshal(point)
+	return g1
+}
+
+// svdwConstants returns the constants Z, c1, c2, c3 and c4 of the
+// Shallue-van de Woestijne map, where Z is found as described by appendix H.1
+// of RFC 9380
+func svdwConstants() (z, c1, c2, c3, c4 *big.Int) {
+	three := big.NewInt(3)
+	four := big.NewInt(4)
+	// h(Z) = -3Z^2 / 4g(Z), as the curve's A is 0
+	h := func(z *big.Int) *big.Int {
+		return sub(big.NewInt(0), mul(mul(three, mul(z, z)), inv0(mul(four, curveEquation(z)))))
+	}
+	for ctr := int64(1); ; ctr++ {
+		for _, candidate := range []*big.Int{big.NewInt(ctr), sub(big.NewInt(0), big.NewInt(ctr))} {
+			gz := curveEquation(candidate)
+			hz := h(candidate)
+			if gz.Sign() == 0 || hz.Sign() == 0 || !isSquare(hz) {
+				continue
+			}
+			if !isSquare(gz) && !isSquare(curveEquation(mul(sub(big.NewInt(0), candidate), inv0(big.NewInt(2))))) {
+				continue
+			}
+
+			z = candidate
+			c1 = gz
+			c2 = mul(sub(big.NewInt(0), z), inv0(big.NewInt(2)))
+			c3 = sqrt(sub(big.NewInt(0), mul(gz, mul(three, mul(z, z)))))
+			if sgn0(c3) {
+				c3 = sub(big.NewInt(0), c3)
+			}
+			c4 = mul(sub(big.NewInt(0), mul(four, gz)), inv0(mul(three, mul(z, z))))
+			return z, c1, c2, c3, c4
+		}
+	}
+}
+
+// curveEquation returns x^3 + 3, which is y^2 for points on the curve
+func curveEquation(x *big.Int) *big.Int {
+	return add(mul(mul(x, x), x), curveB)
+}
+
+func add(a, b *big.Int) *big.Int {
+	r := new(big.Int).Add(a, b)
+	return r.Mod(r, fieldModulus)
+}
+
+func sub(a, b *big.Int) *big.Int {
+	r := new(big.Int).Sub(a, b)
+	return r.Mod(r, fieldModulus)
+}
+
+func mul(a, b *big.Int) *big.Int {
+	r := new(big.Int).Mul(a, b)
+	return r.Mod(r, fieldModulus)
+}
+
+// inv0 returns the inverse of [a], or 0 if [a] is 0
+func inv0(a *big.Int) *big.Int {
+	return new(big.Int).Exp(a, inverseExponent, fieldModulus)
+}
+
+// sqrt returns a square root of [a], which must be a square
+func sqrt(a *big.Int) *big.Int {
+	return new(big.Int).Exp(a, sqrtExponent, fieldModulus)
+}
+
+func isSquare(a *big.Int) bool {
+	return new(big.Int).Exp(a, legendreExponent, fieldModulus).Cmp(big.NewInt(1)) <= 0
+}
+
+// sgn0 returns the sign of [a] as defined by section 4.1 of RFC 9380
+func sgn0(a *big.Int) bool {
+	return a.Bit(0) == 1
+}
+
+// cmov returns [b] if [c] is true, and otherwise returns [a]
+func cmov(a, b *big.Int, c bool) *big.Int {
+	if c {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,55 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bls
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandMessageXMD(t *testing.T) {
+	// Test vectors from appendix K.1 of RFC 9380
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	tests := []struct {
+		msg      string
+		length   int
+		expected string
+	}{
+		{
+			msg:      "",
+			length:   0x20,
+			expected: "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235",
+		},
+		{
+			msg:      "abc",
+			length:   0x20,
+			expected: "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615",
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, hex.EncodeToString(expandMessageXMD(dst, []byte(test.msg), test.length)))
+	}
+}
+
+func TestMapToG1(t *testing.T) {
+	// The exceptional case of the map, where the inverse is of 0, maps to a
+	// point on the curve
+	for _, u := range []*big.Int{big.NewInt(0), big.NewInt(1), sub(big.NewInt(0), big.NewInt(1))} {
+		point := mapToG1(u).Marshal()
+		x := new(big.Int).SetBytes(point[:fieldElementLen])
+		y := new(big.Int).SetBytes(point[fieldElementLen:])
+		assert.Equal(t, curveEquation(x), mul(y, y))
+	}
+}
+
+func TestHashToG1(t *testing.T) {
+	h1 := hashToG1(signatureDST, []byte("hello")).Marshal()
+	assert.Equal(t, h1, hashToG1(signatureDST, []byte("hello")).Marshal())
+	assert.NotEqual(t, h1, hashToG1(proofOfPossessionDST, []byte("hello")).Marshal())
+	assert.NotEqual(t, h1, hashToG1(signatureDST, []byte("goodbye")).Marshal())
+	assert.False(t, isInfinity(h1))
+}
@@ -0,0 +1,112 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/tyler-smith/go-bip39"
+
+	secp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+const (
+	// AVAXCoinType is the BIP-44 coin type of AVAX
+	AVAXCoinType = 9000
+
+	// MnemonicEntropyBits is the number of bits of entropy of the mnemonics
+	// returned by NewMnemonic, which are 24 words long
+	MnemonicEntropyBits = 256
+
+	// hardenedOffset is added to the index of a hardened BIP-32 child key
+	hardenedOffset = 1 << 31
+)
+
+var (
+	errInvalidMnemonic = errors.New("invalid mnemonic")
+	errInvalidChildKey = errors.New("derived an invalid child key")
+
+	masterKeyHMACKey = []byte("Bitcoin seed")
+)
+
+// NewMnemonic returns a new random BIP-39 mnemonic
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(MnemonicEntropyBits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// MnemonicToPrivateKey derives the private key at [addressIndex] from the
+// BIP-39 [mnemonic], which is protected by [passphrase]. Keys are derived
+// along the BIP-44 path m/44'/9000'/0'/0/[addressIndex], which is the path
+// that Avalanche wallets derive X-Chain and P-Chain keys along.
+func MnemonicToPrivateKey(mnemonic, passphrase string, addressIndex uint32) (*PrivateKeySECP256K1R, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errInvalidMnemonic
+	}
+	seed := bip39.NewSeed(mnemonic, passphrase)
+	return deriveKey(seed, []uint32{
+		44 + hardenedOffset,
+		AVAXCoinType + hardenedOffset,
+		hardenedOffset,
+		0,
+		addressIndex,
+	})
+}
+
+// deriveKey derives the BIP-32 private key at [path] from [seed]. Indices of
+// hardened keys in [path] must include the hardened offset.
+func deriveKey(seed []byte, path []uint32) (*PrivateKeySECP256K1R, error) {
+	mac := hmac.New(sha512.New, masterKeyHMACKey)
+	_, _ = mac.Write(seed)
+	digest := mac.Sum(nil)
+
+	key := &secp256k1.ModNScalar{}
+	if overflow := key.SetByteSlice(digest[:32]); overflow || key.IsZero() {
+		return nil, errInvalidChildKey
+	}
+	chainCode := digest[32:]
+
+	for _, index := range path {
+		// The data of a hardened child is the parent's private key, and the
+		// data of a normal child is the parent's compressed public key
+		data := make([]byte, 0, SECP256K1RPKLen+4)
+		if index >= hardenedOffset {
+			keyBytes := key.Bytes()
+			data = append(data, 0)
+			data = append(data, keyBytes[:]...)
+		} else {
+			data = append(data, secp256k1.NewPrivateKey(key).PubKey().SerializeCompressed()...)
+		}
+		indexBytes := [4]byte{}
+		binary.BigEndian.PutUint32(indexBytes[:], index)
+		data = append(data, indexBytes[:]...)
+
+		mac := hmac.New(sha512.New, chainCode)
+		_, _ = mac.Write(data)
+		digest := mac.Sum(nil)
+
+		tweak := &secp256k1.ModNScalar{}
+		if overflow := tweak.SetByteSlice(digest[:32]); overflow {
+			return nil, fmt.Errorf("%w at index %d", errInvalidChildKey, index)
+		}
+		key = tweak.Add(key)
+		if key.IsZero() {
+			return nil, fmt.Errorf("%w at index %d", errInvalidChildKey, index)
+		}
+		chainCode = digest[32:]
+	}
+
+	keyBytes := key.Bytes()
+	return &PrivateKeySECP256K1R{
+		sk:    secp256k1.PrivKeyFromBytes(keyBytes[:]),
+		bytes: keyBytes[:],
+	}, nil
+}
@@ -0,0 +1,78 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vector 1 of BIP-32
+func TestDeriveKey(t *testing.T) {
+	assert := assert.New(t)
+
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	assert.NoError(err)
+
+	tests := []struct {
+		path        []uint32
+		expectedKey string
+	}{
+		{
+			path:        nil,
+			expectedKey: "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+		},
+		{
+			path:        []uint32{hardenedOffset},
+			expectedKey: "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+		},
+		{
+			path:        []uint32{hardenedOffset, 1},
+			expectedKey: "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+		},
+	}
+	for _, test := range tests {
+		key, err := deriveKey(seed, test.path)
+		assert.NoError(err)
+		assert.Equal(test.expectedKey, hex.EncodeToString(key.Bytes()))
+	}
+}
+
+func TestMnemonicToPrivateKey(t *testing.T) {
+	assert := assert.New(t)
+
+	mnemonic, err := NewMnemonic()
+	assert.NoError(err)
+	assert.Len(strings.Fields(mnemonic), 24)
+
+	key0, err := MnemonicToPrivateKey(mnemonic, "", 0)
+	assert.NoError(err)
+	key0Again, err := MnemonicToPrivateKey(mnemonic, "", 0)
+	assert.NoError(err)
+	assert.Equal(key0.Bytes(), key0Again.Bytes())
+
+	key1, err := MnemonicToPrivateKey(mnemonic, "", 1)
+	assert.NoError(err)
+	assert.NotEqual(key0.Bytes(), key1.Bytes())
+
+	protectedKey0, err := MnemonicToPrivateKey(mnemonic, "passphrase", 0)
+	assert.NoError(err)
+	assert.NotEqual(key0.Bytes(), protectedKey0.Bytes())
+
+	// The key must be usable for signing
+	msg := []byte{1, 2, 3}
+	sig, err := key1.Sign(msg)
+	assert.NoError(err)
+	f := FactorySECP256K1R{}
+	pubKey, err := f.RecoverPublicKey(msg, sig)
+	assert.NoError(err)
+	assert.Equal(key1.PublicKey().Bytes(), pubKey.Bytes())
+
+	// The checksum of this mnemonic is invalid
+	_, err = MnemonicToPrivateKey(strings.Repeat("abandon ", 12), "", 0)
+	assert.Error(err)
+}
@@ -0,0 +1,131 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package address parses and formats the identifiers that users see: the
+// chain-qualified bech32 addresses, node IDs and the IDs of containers. Each
+// format carries a checksum, so that mistyped identifiers are rejected rather
+// than resolved to the wrong thing.
+package address
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/bech32"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+const (
+	addressSep = "-"
+)
+
+var (
+	errNoSeparator         = errors.New("no separator found in address")
+	errBits5To8            = errors.New("unable to convert address from 5-bit to 8-bit formatting")
+	errBits8To5            = errors.New("unable to convert address from 8-bit to 5-bit formatting")
+	errWrongHRP            = errors.New("address is for a different network")
+	errMissingNodeIDPrefix = errors.New("node ID is missing the prefix " + constants.NodeIDPrefix)
+)
+
+// Parse takes in an address string and splits returns the corresponding
+// parts. This returns the chain ID alias, bech32 HRP, address bytes, and an
+// error if it occurs.
+func Parse(addrStr string) (string, string, []byte, error) {
+	addressParts := strings.SplitN(addrStr, addressSep, 2)
+	if len(addressParts) < 2 {
+		return "", "", nil, fmt.Errorf("%w: %q should be of the form <chain>%s<address>", errNoSeparator, addrStr, addressSep)
+	}
+	chainID := addressParts[0]
+	rawAddr := addressParts[1]
+
+	hrp, addr, err := ParseBech32(rawAddr)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+	}
+	return chainID, hrp, addr, nil
+}
+
+// ParseToShortID parses a chain-qualified address whose HRP must be
+// [expectedHRP]. This returns the chain ID alias and the ID of the address.
+func ParseToShortID(addrStr, expectedHRP string) (string, ids.ShortID, error) {
+	chainIDAlias, hrp, addrBytes, err := Parse(addrStr)
+	if err != nil {
+		return "", ids.ShortID{}, err
+	}
+	if hrp != expectedHRP {
+		return "", ids.ShortID{}, fmt.Errorf("%w: expected hrp %q but got %q", errWrongHRP, expectedHRP, hrp)
+	}
+	addr, err := ids.ToShortID(addrBytes)
+	if err != nil {
+		return "", ids.ShortID{}, fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+	}
+	return chainIDAlias, addr, nil
+}
+
+// Format takes in a chain prefix, HRP, and byte slice to produce a string for
+// an address.
+func Format(
+	chainIDAlias string,
+	hrp string,
+	addr []byte,
+) (string, error) {
+	addrStr, err := FormatBech32(hrp, addr)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%s%s", chainIDAlias, addressSep, addrStr), nil
+}
+
+// ParseBech32 takes a bech32 address as input and returns the HRP and data
+// section of a bech32 address
+func ParseBech32(addrStr string) (string, []byte, error) {
+	rawHRP, decoded, err := bech32.Decode(addrStr)
+	if err != nil {
+		return "", nil, err
+	}
+	addrBytes, err := bech32.ConvertBits(decoded, 5, 8, true)
+	if err != nil {
+		return "", nil, errBits5To8
+	}
+	return rawHRP, addrBytes, nil
+}
+
+// FormatBech32 takes an address's bytes as input and returns a bech32 address
+func FormatBech32(hrp string, payload []byte) (string, error) {
+	fiveBits, err := bech32.ConvertBits(payload, 8, 5, true)
+	if err != nil {
+		return "", errBits8To5
+	}
+	return bech32.Encode(hrp, fiveBits)
+}
+
+// ParseNodeID parses a node ID of the form NodeID-<cb58 of the ID>
+func ParseNodeID(nodeIDStr string) (ids.ShortID, error) {
+	if !strings.HasPrefix(nodeIDStr, constants.NodeIDPrefix) {
+		return ids.ShortID{}, fmt.Errorf("%w: %q", errMissingNodeIDPrefix, nodeIDStr)
+	}
+	nodeID, err := ids.ShortFromString(strings.TrimPrefix(nodeIDStr, constants.NodeIDPrefix))
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("couldn't parse node ID %q: %w", nodeIDStr, err)
+	}
+	return nodeID, nil
+}
+
+// FormatNodeID returns the string representation of [nodeID] that
+// ParseNodeID parses
+func FormatNodeID(nodeID ids.ShortID) string {
+	return nodeID.PrefixedString(constants.NodeIDPrefix)
+}
+
+// ParseID parses the cb58 representation of the ID of a container, such as a
+// chain, subnet, block or transaction
+func ParseID(idStr string) (ids.ID, error) {
+	id, err := ids.FromString(idStr)
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("couldn't parse ID %q: %w", idStr, err)
+	}
+	return id, nil
+}
@@ -0,0 +1,83 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package address
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func TestParseToShortID(t *testing.T) {
+	addr := ids.GenerateTestShortID()
+	addrStr, err := Format("X", constants.LocalHRP, addr.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chainIDAlias, parsed, err := ParseToShortID(addrStr, constants.LocalHRP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chainIDAlias != "X" {
+		t.Fatalf("expected chain alias X but got %s", chainIDAlias)
+	}
+	if parsed != addr {
+		t.Fatalf("expected address %s but got %s", addr, parsed)
+	}
+
+	if _, _, err := ParseToShortID(addrStr, constants.MainnetHRP); !errors.Is(err, errWrongHRP) {
+		t.Fatalf("expected %s but got %v", errWrongHRP, err)
+	}
+	if _, _, err := ParseToShortID(addrStr[2:], constants.LocalHRP); !errors.Is(err, errNoSeparator) {
+		t.Fatalf("expected %s but got %v", errNoSeparator, err)
+	}
+
+	// Changing a character breaks the checksum
+	corrupted := []byte(addrStr)
+	if corrupted[len(corrupted)-1] == 'q' {
+		corrupted[len(corrupted)-1] = 'p'
+	} else {
+		corrupted[len(corrupted)-1] = 'q'
+	}
+	if _, _, err := ParseToShortID(string(corrupted), constants.LocalHRP); err == nil {
+		t.Fatal("should have failed to parse an address with a bad checksum")
+	}
+}
+
+func TestNodeID(t *testing.T) {
+	nodeID := ids.GenerateTestShortID()
+	nodeIDStr := FormatNodeID(nodeID)
+
+	parsed, err := ParseNodeID(nodeIDStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != nodeID {
+		t.Fatalf("expected node ID %s but got %s", nodeID, parsed)
+	}
+
+	if _, err := ParseNodeID(nodeID.String()); !errors.Is(err, errMissingNodeIDPrefix) {
+		t.Fatalf("expected %s but got %v", errMissingNodeIDPrefix, err)
+	}
+	if _, err := ParseNodeID(nodeIDStr[:len(nodeIDStr)-1]); err == nil {
+		t.Fatal("should have failed to parse a truncated node ID")
+	}
+}
+
+func TestParseID(t *testing.T) {
+	id := ids.GenerateTestID()
+	parsed, err := ParseID(id.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != id {
+		t.Fatalf("expected ID %s but got %s", id, parsed)
+	}
+	if _, err := ParseID(id.String()[1:]); err == nil {
+		t.Fatal("should have failed to parse a truncated ID")
+	}
+}
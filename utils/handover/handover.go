@@ -0,0 +1,331 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package handover passes the listening sockets of a node to the process that
+// replaces it, so that connections made while the node restarts wait in the
+// sockets' backlogs rather than being refused.
+//
+// Sockets are inherited as described by systemd's socket activation protocol:
+// LISTEN_FDS is the number of sockets, which start at file descriptor 3, and
+// LISTEN_FDNAMES is the colon separated list of their names. The node can
+// therefore also be started by a systemd socket unit, whose sockets are kept
+// open across restarts of the service.
+package handover
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// StakingSocket is the name of the socket that peers connect to
+	StakingSocket = "staking"
+	// HTTPSocket is the name of the socket that the APIs are served on.
+	// Additional API listeners are named HTTPSocket-1, HTTPSocket-2 and so on.
+	HTTPSocket = "http"
+	// GRPCSocket is the name of the socket that the gRPC API is served on
+	GRPCSocket = "grpc"
+
+	listenFDsEnv     = "LISTEN_FDS"
+	listenFDNamesEnv = "LISTEN_FDNAMES"
+	listenPIDEnv     = "LISTEN_PID"
+	// File descriptor that stays open until the process that handed its
+	// sockets over exits
+	handoverFDEnv = "AVALANCHEGO_HANDOVER_FD"
+
+	// File descriptor of the first inherited socket
+	firstFD = 3
+)
+
+var (
+	errWrongPID       = errors.New("sockets were passed to another process")
+	errNamesMismatch  = errors.New("number of socket names doesn't match the number of sockets")
+	errNotTCP         = errors.New("only TCP listeners can be handed over")
+	errHandoverActive = errors.New("sockets were already handed over")
+)
+
+// Sockets are the listening sockets of the node by name
+type Sockets struct {
+	lock sync.Mutex
+	// Sockets inherited from the previous process that haven't been used yet
+	inherited map[string]net.Listener
+	// Sockets the node listens on
+	listeners map[string]*socket
+	names     []string
+
+	// Closed once the previous process has exited. Nil if the sockets weren't
+	// handed over by a previous process.
+	previousExited chan struct{}
+
+	// Kept open until this process exits, which tells the next process that
+	// it can start. Non-nil once the sockets are handed over.
+	handover *os.File
+}
+
+// Inherit returns the sockets that this process was started with, if any.
+// The environment variables that describe them are cleared, so that they
+// aren't passed to subprocesses.
+func Inherit() (*Sockets, error) {
+	defer func() {
+		_ = os.Unsetenv(listenFDsEnv)
+		_ = os.Unsetenv(listenFDNamesEnv)
+		_ = os.Unsetenv(listenPIDEnv)
+		_ = os.Unsetenv(handoverFDEnv)
+	}()
+
+	s := &Sockets{
+		inherited: make(map[string]net.Listener),
+		listeners: make(map[string]*socket),
+	}
+	names, err := parseSocketNames(
+		os.Getenv(listenFDsEnv),
+		os.Getenv(listenFDNamesEnv),
+		os.Getenv(listenPIDEnv),
+		os.Getpid(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range names {
+		file := os.NewFile(uintptr(firstFD+i), name)
+		listener, err := net.FileListener(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("couldn't inherit socket %q: %w", name, err)
+		}
+		s.inherited[name] = listener
+	}
+
+	if handoverFD := os.Getenv(handoverFDEnv); handoverFD != "" {
+		fd, err := strconv.Atoi(handoverFD)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %w", handoverFDEnv, err)
+		}
+		previous := os.NewFile(uintptr(fd), "handover")
+		s.previousExited = make(chan struct{})
+		go func() {
+			// Reads return EOF once the previous process has exited
+			_, _ = io.Copy(ioutil.Discard, previous)
+			_ = previous.Close()
+			close(s.previousExited)
+		}()
+	}
+	return s, nil
+}
+
+// parseSocketNames returns the names of the inherited sockets described by
+// the values of LISTEN_FDS, LISTEN_FDNAMES and LISTEN_PID. If LISTEN_PID is
+// set, it must be [pid]. Unnamed sockets are named by their index.
+func parseSocketNames(listenFDs, listenFDNames, listenPID string, pid int) ([]string, error) {
+	if listenFDs == "" {
+		return nil, nil
+	}
+	if listenPID != "" && listenPID != strconv.Itoa(pid) {
+		return nil, errWrongPID
+	}
+	numFDs, err := strconv.Atoi(listenFDs)
+	if err != nil || numFDs < 0 {
+		return nil, fmt.Errorf("invalid %s %q", listenFDsEnv, listenFDs)
+	}
+
+	names := make([]string, numFDs)
+	if listenFDNames != "" {
+		names = strings.Split(listenFDNames, ":")
+		if len(names) != numFDs {
+			return nil, errNamesMismatch
+		}
+	}
+	for i, name := range names {
+		if name == "" {
+			names[i] = strconv.Itoa(i)
+		}
+	}
+	return names, nil
+}
+
+// WaitForPrevious blocks until the process that handed its sockets over has
+// exited, so that the resources it held, such as the database, can be
+// opened. Returns immediately if the sockets weren't handed over.
+func (s *Sockets) WaitForPrevious(timeout time.Duration) error {
+	if s == nil || s.previousExited == nil {
+		return nil
+	}
+	select {
+	case <-s.previousExited:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("previous process didn't exit within %s", timeout)
+	}
+}
+
+// Listen returns the inherited socket named [name] if there is one, and
+// otherwise listens on [address] over TCP. The socket is handed over to the
+// process that replaces this one. If [s] is nil, always listens on
+// [address].
+func (s *Sockets) Listen(name, address string) (net.Listener, error) {
+	if s == nil {
+		return net.Listen("tcp", address)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	listener, ok := s.inherited[name]
+	if ok {
+		delete(s.inherited, name)
+	} else {
+		var err error
+		listener, err = net.Listen("tcp", address)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tcpListener, ok := listener.(*net.TCPListener)
+	if !ok {
+		_ = listener.Close()
+		return nil, errNotTCP
+	}
+	if _, exists := s.listeners[name]; !exists {
+		s.names = append(s.names, name)
+	}
+	l := &socket{
+		TCPListener: tcpListener,
+		sockets:     s,
+		closed:      make(chan struct{}),
+	}
+	s.listeners[name] = l
+	return l, nil
+}
+
+// handedOver returns true if the sockets were handed over to a new process
+func (s *Sockets) handedOver() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.handover != nil
+}
+
+// setDeadlines sets the deadline of every socket's Accept to [t].
+// Assumes [s.lock] is held.
+func (s *Sockets) setDeadlines(t time.Time) error {
+	for _, name := range s.names {
+		if err := s.listeners[name].SetDeadline(t); err != nil {
+			return fmt.Errorf("couldn't set deadline of socket %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Handover starts [path] with [args] and passes the sockets to it. The new
+// process waits for this one to exit before it starts, so this process should
+// shut down once Handover returns. Once Handover returns, this process stops
+// accepting connections, which wait in the sockets' backlogs for the new
+// process. The sockets stay open until they're closed, since closing them
+// only releases this process' descriptors.
+func (s *Sockets) Handover(path string, args []string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.handover != nil {
+		return errHandoverActive
+	}
+
+	// Wake up pending Accepts, which wait for the handover to finish. If it
+	// fails, they resume accepting connections.
+	handedOver := false
+	defer func() {
+		if !handedOver {
+			_ = s.setDeadlines(time.Time{})
+		}
+	}()
+	if err := s.setDeadlines(time.Unix(1, 0)); err != nil {
+		return err
+	}
+
+	files := make([]*os.File, 0, len(s.names)+1)
+	defer func() {
+		for _, file := range files {
+			_ = file.Close()
+		}
+	}()
+	for _, name := range s.names {
+		file, err := s.listeners[name].File()
+		if err != nil {
+			return fmt.Errorf("couldn't get file of socket %q: %w", name, err)
+		}
+		files = append(files, file)
+	}
+
+	// The new process reads from [previous] until this process exits
+	previous, handover, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	files = append(files, previous)
+
+	// #nosec G204
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	// LISTEN_PID isn't set, since the PID of the new process isn't known
+	// until it has started. The environment variables are cleared by Inherit,
+	// so they aren't mistaken for sockets passed to subprocesses of the new
+	// process.
+	cmd.Env = append(
+		os.Environ(),
+		fmt.Sprintf("%s=%d", listenFDsEnv, len(s.names)),
+		fmt.Sprintf("%s=%s", listenFDNamesEnv, strings.Join(s.names, ":")),
+		fmt.Sprintf("%s=%d", handoverFDEnv, firstFD+len(s.names)),
+	)
+	if err := cmd.Start(); err != nil {
+		_ = handover.Close()
+		return fmt.Errorf("couldn't start new process: %w", err)
+	}
+	if err := cmd.Process.Release(); err != nil {
+		_ = handover.Close()
+		return err
+	}
+	s.handover = handover
+	handedOver = true
+	return nil
+}
+
+// socket is a socket the node listens on. Once the socket is handed over,
+// Accept blocks until the socket is closed, so that connections are left for
+// the new process.
+type socket struct {
+	*net.TCPListener
+	sockets *Sockets
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (l *socket) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.TCPListener.Accept()
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			return conn, err
+		}
+		if l.sockets.handedOver() {
+			<-l.closed
+			return nil, net.ErrClosed
+		}
+	}
+}
+
+func (l *socket) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return l.TCPListener.Close()
+}
@@ -0,0 +1,137 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handover
+
+import (
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSocketNames(t *testing.T) {
+	assert := assert.New(t)
+
+	names, err := parseSocketNames("", "", "", 10)
+	assert.NoError(err)
+	assert.Empty(names)
+
+	names, err = parseSocketNames("2", "staking:http", "10", 10)
+	assert.NoError(err)
+	assert.Equal([]string{StakingSocket, HTTPSocket}, names)
+
+	// Sockets handed over by a node don't name the process they're for
+	names, err = parseSocketNames("2", "staking:", "", 10)
+	assert.NoError(err)
+	assert.Equal([]string{StakingSocket, "1"}, names)
+
+	names, err = parseSocketNames("2", "", "", 10)
+	assert.NoError(err)
+	assert.Equal([]string{"0", "1"}, names)
+
+	_, err = parseSocketNames("2", "staking:http", "11", 10)
+	assert.ErrorIs(err, errWrongPID)
+	_, err = parseSocketNames("2", "staking", "", 10)
+	assert.ErrorIs(err, errNamesMismatch)
+	_, err = parseSocketNames("two", "", "", 10)
+	assert.Error(err)
+}
+
+func TestListen(t *testing.T) {
+	assert := assert.New(t)
+
+	// Without sockets, a new socket is always listened on
+	var none *Sockets
+	listener, err := none.Listen(StakingSocket, "127.0.0.1:0")
+	assert.NoError(err)
+	assert.NoError(listener.Close())
+	assert.NoError(none.WaitForPrevious(time.Second))
+
+	inherited, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	s := &Sockets{
+		inherited: map[string]net.Listener{StakingSocket: inherited},
+		listeners: make(map[string]*socket),
+	}
+
+	// The inherited socket is used rather than the address
+	listener, err = s.Listen(StakingSocket, "127.0.0.1:0")
+	assert.NoError(err)
+	assert.Equal(inherited, listener.(*socket).TCPListener)
+
+	listener, err = s.Listen(HTTPSocket, "127.0.0.1:0")
+	assert.NoError(err)
+	assert.NotEqual(inherited.Addr(), listener.Addr())
+	assert.Equal([]string{StakingSocket, HTTPSocket}, s.names)
+
+	for _, listener := range s.listeners {
+		assert.NoError(listener.Close())
+	}
+}
+
+func TestHandover(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sockets{
+		inherited: make(map[string]net.Listener),
+		listeners: make(map[string]*socket),
+	}
+	for _, name := range []string{StakingSocket, HTTPSocket} {
+		listener, err := s.Listen(name, "127.0.0.1:0")
+		assert.NoError(err)
+		defer listener.Close()
+	}
+	listener := s.listeners[StakingSocket]
+
+	// Sockets are still accepted on if the new process can't be started
+	assert.Error(s.Handover(filepath.Join(t.TempDir(), "missing"), nil))
+	accepted := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			_ = conn.Close()
+		}
+		accepted <- err
+	}()
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	assert.NoError(err)
+	assert.NoError(conn.Close())
+	assert.NoError(<-accepted)
+
+	go func() {
+		_, err := listener.Accept()
+		accepted <- err
+	}()
+
+	// The new process is told which sockets it's passed
+	path := filepath.Join(t.TempDir(), "env")
+	assert.NoError(s.Handover("/bin/sh", []string{"-c", "echo $LISTEN_FDS $LISTEN_FDNAMES $AVALANCHEGO_HANDOVER_FD > " + path}))
+	defer s.handover.Close()
+
+	var env []byte
+	assert.Eventually(func() bool {
+		var err error
+		env, err = ioutil.ReadFile(path)
+		return err == nil && len(env) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal("2 staking:http 5", strings.TrimSpace(string(env)))
+
+	assert.ErrorIs(s.Handover("/bin/sh", nil), errHandoverActive)
+
+	// Connections are left for the new process until the socket is closed
+	conn, err = net.Dial("tcp", listener.Addr().String())
+	assert.NoError(err)
+	defer conn.Close()
+	select {
+	case err := <-accepted:
+		t.Fatalf("handed over socket accepted a connection: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.NoError(listener.Close())
+	assert.ErrorIs(<-accepted, net.ErrClosed)
+}
@@ -0,0 +1,630 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hashing
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// This is a portable implementation of the BLAKE3 hash function, following
+// the reference implementation of its specification. Only the default hash
+// mode with a 256 bit output is supported.
+//
+// The compression function is unrolled, with the message permutation of each
+// round resolved ahead of time, and hashing doesn't allocate. It doesn't use
+// SIMD, so unlike the optimized implementations of BLAKE3 it doesn't compress
+// several chunks at once. See BenchmarkHasher256 for how it compares to
+// SHA-256.
+
+const (
+	blake3BlockLen = 64
+	blake3ChunkLen = 1024
+	// Maximum number of subtrees that are waiting to be merged, which is the
+	// number of bits of the maximum number of chunks of an input
+	blake3MaxDepth = 54
+
+	blake3ChunkStart = 1 << 0
+	blake3ChunkEnd   = 1 << 1
+	blake3Parent     = 1 << 2
+	blake3Root       = 1 << 3
+)
+
+var blake3IV = [8]uint32{
+	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A,
+	0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+}
+
+// ComputeBLAKE3Array computes the 256 bit BLAKE3 hash of [buf]
+func ComputeBLAKE3Array(buf []byte) Hash256 {
+	var (
+		// Chaining values of the completed subtrees, ordered from the largest
+		// to the smallest. The subtrees are merged as chunks are added, so at
+		// most one subtree of each size is kept.
+		stack     [blake3MaxDepth][8]uint32
+		depth     int
+		chunkCtr  uint64
+		remaining = buf
+	)
+	// Every chunk but the last is compressed and merged into the tree. The
+	// last chunk, which may be empty, is finalized with the root flag if it's
+	// the only chunk.
+	for len(remaining) > blake3ChunkLen {
+		out := blake3ChunkOutput(remaining[:blake3ChunkLen], chunkCtr)
+		cv := out.chainingValue()
+		remaining = remaining[blake3ChunkLen:]
+		chunkCtr++
+
+		// Merge every subtree that is complete now. The number of merges is
+		// the number of trailing zeros of the number of chunks so far.
+		for total := chunkCtr; total&1 == 0; total >>= 1 {
+			depth--
+			out := blake3ParentOutput(&stack[depth], &cv)
+			cv = out.chainingValue()
+		}
+		stack[depth] = cv
+		depth++
+	}
+
+	out := blake3ChunkOutput(remaining, chunkCtr)
+	for depth--; depth >= 0; depth-- {
+		cv := out.chainingValue()
+		out = blake3ParentOutput(&stack[depth], &cv)
+	}
+	return out.rootHash()
+}
+
+// blake3Output is the state right before the last compression of a node of
+// the tree. Whether the node is the root decides how it's compressed.
+type blake3Output struct {
+	inputCV  [8]uint32
+	block    [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+func (o *blake3Output) chainingValue() [8]uint32 {
+	return blake3Compress(&o.inputCV, &o.block, o.counter, o.blockLen, o.flags)
+}
+
+func (o *blake3Output) rootHash() Hash256 {
+	cv := blake3Compress(&o.inputCV, &o.block, 0, o.blockLen, o.flags|blake3Root)
+	var hash Hash256
+	for i, word := range cv {
+		binary.LittleEndian.PutUint32(hash[4*i:], word)
+	}
+	return hash
+}
+
+// blake3ChunkOutput compresses every block of [chunk] but the last, which is
+// at most [blake3ChunkLen] bytes long and is the [counter]-th chunk of the
+// input
+func blake3ChunkOutput(chunk []byte, counter uint64) blake3Output {
+	var (
+		cv    = blake3IV
+		block [16]uint32
+		flags = uint32(blake3ChunkStart)
+	)
+	for len(chunk) > blake3BlockLen {
+		blake3Words(&block, chunk[:blake3BlockLen])
+		cv = blake3Compress(&cv, &block, counter, blake3BlockLen, flags)
+		chunk = chunk[blake3BlockLen:]
+		flags = 0
+	}
+	blake3Words(&block, chunk)
+	return blake3Output{
+		inputCV:  cv,
+		block:    block,
+		counter:  counter,
+		blockLen: uint32(len(chunk)),
+		flags:    flags | blake3ChunkEnd,
+	}
+}
+
+func blake3ParentOutput(left, right *[8]uint32) blake3Output {
+	out := blake3Output{
+		inputCV:  blake3IV,
+		blockLen: blake3BlockLen,
+		flags:    blake3Parent,
+	}
+	copy(out.block[:8], left[:])
+	copy(out.block[8:], right[:])
+	return out
+}
+
+// blake3Words sets [words] to the little endian words of [block], which is at
+// most [blake3BlockLen] bytes long, padded with zeros
+func blake3Words(words *[16]uint32, block []byte) {
+	if len(block) == blake3BlockLen {
+		for i := range words {
+			words[i] = binary.LittleEndian.Uint32(block[4*i:])
+		}
+		return
+	}
+	var padded [blake3BlockLen]byte
+	copy(padded[:], block)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(padded[4*i:])
+	}
+}
+
+// blake3Compress returns the chaining value that compressing [block] into
+// [cv] results in, which is also the first 256 bits of the output
+func blake3Compress(cv *[8]uint32, block *[16]uint32, counter uint64, blockLen, flags uint32) [8]uint32 {
+	var (
+		v0, v1, v2, v3     = cv[0], cv[1], cv[2], cv[3]
+		v4, v5, v6, v7     = cv[4], cv[5], cv[6], cv[7]
+		v8, v9, v10, v11   = blake3IV[0], blake3IV[1], blake3IV[2], blake3IV[3]
+		v12, v13, v14, v15 = uint32(counter), uint32(counter >> 32), blockLen, flags
+
+		m0, m1, m2, m3     = block[0], block[1], block[2], block[3]
+		m4, m5, m6, m7     = block[4], block[5], block[6], block[7]
+		m8, m9, m10, m11   = block[8], block[9], block[10], block[11]
+		m12, m13, m14, m15 = block[12], block[13], block[14], block[15]
+	)
+
+	// Each round mixes the columns and then the diagonals of the state. The
+	// message words are permuted between rounds.
+	// Round 1
+	v0 += v4 + m0
+	v12 = bits.RotateLeft32(v12^v0, -16)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -12)
+	v0 += v4 + m1
+	v12 = bits.RotateLeft32(v12^v0, -8)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -7)
+	v1 += v5 + m2
+	v13 = bits.RotateLeft32(v13^v1, -16)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -12)
+	v1 += v5 + m3
+	v13 = bits.RotateLeft32(v13^v1, -8)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -7)
+	v2 += v6 + m4
+	v14 = bits.RotateLeft32(v14^v2, -16)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -12)
+	v2 += v6 + m5
+	v14 = bits.RotateLeft32(v14^v2, -8)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -7)
+	v3 += v7 + m6
+	v15 = bits.RotateLeft32(v15^v3, -16)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -12)
+	v3 += v7 + m7
+	v15 = bits.RotateLeft32(v15^v3, -8)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -7)
+	v0 += v5 + m8
+	v15 = bits.RotateLeft32(v15^v0, -16)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -12)
+	v0 += v5 + m9
+	v15 = bits.RotateLeft32(v15^v0, -8)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -7)
+	v1 += v6 + m10
+	v12 = bits.RotateLeft32(v12^v1, -16)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -12)
+	v1 += v6 + m11
+	v12 = bits.RotateLeft32(v12^v1, -8)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -7)
+	v2 += v7 + m12
+	v13 = bits.RotateLeft32(v13^v2, -16)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -12)
+	v2 += v7 + m13
+	v13 = bits.RotateLeft32(v13^v2, -8)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -7)
+	v3 += v4 + m14
+	v14 = bits.RotateLeft32(v14^v3, -16)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -12)
+	v3 += v4 + m15
+	v14 = bits.RotateLeft32(v14^v3, -8)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -7)
+	// Round 2
+	v0 += v4 + m2
+	v12 = bits.RotateLeft32(v12^v0, -16)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -12)
+	v0 += v4 + m6
+	v12 = bits.RotateLeft32(v12^v0, -8)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -7)
+	v1 += v5 + m3
+	v13 = bits.RotateLeft32(v13^v1, -16)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -12)
+	v1 += v5 + m10
+	v13 = bits.RotateLeft32(v13^v1, -8)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -7)
+	v2 += v6 + m7
+	v14 = bits.RotateLeft32(v14^v2, -16)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -12)
+	v2 += v6 + m0
+	v14 = bits.RotateLeft32(v14^v2, -8)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -7)
+	v3 += v7 + m4
+	v15 = bits.RotateLeft32(v15^v3, -16)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -12)
+	v3 += v7 + m13
+	v15 = bits.RotateLeft32(v15^v3, -8)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -7)
+	v0 += v5 + m1
+	v15 = bits.RotateLeft32(v15^v0, -16)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -12)
+	v0 += v5 + m11
+	v15 = bits.RotateLeft32(v15^v0, -8)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -7)
+	v1 += v6 + m12
+	v12 = bits.RotateLeft32(v12^v1, -16)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -12)
+	v1 += v6 + m5
+	v12 = bits.RotateLeft32(v12^v1, -8)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -7)
+	v2 += v7 + m9
+	v13 = bits.RotateLeft32(v13^v2, -16)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -12)
+	v2 += v7 + m14
+	v13 = bits.RotateLeft32(v13^v2, -8)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -7)
+	v3 += v4 + m15
+	v14 = bits.RotateLeft32(v14^v3, -16)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -12)
+	v3 += v4 + m8
+	v14 = bits.RotateLeft32(v14^v3, -8)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -7)
+	// Round 3
+	v0 += v4 + m3
+	v12 = bits.RotateLeft32(v12^v0, -16)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -12)
+	v0 += v4 + m4
+	v12 = bits.RotateLeft32(v12^v0, -8)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -7)
+	v1 += v5 + m10
+	v13 = bits.RotateLeft32(v13^v1, -16)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -12)
+	v1 += v5 + m12
+	v13 = bits.RotateLeft32(v13^v1, -8)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -7)
+	v2 += v6 + m13
+	v14 = bits.RotateLeft32(v14^v2, -16)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -12)
+	v2 += v6 + m2
+	v14 = bits.RotateLeft32(v14^v2, -8)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -7)
+	v3 += v7 + m7
+	v15 = bits.RotateLeft32(v15^v3, -16)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -12)
+	v3 += v7 + m14
+	v15 = bits.RotateLeft32(v15^v3, -8)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -7)
+	v0 += v5 + m6
+	v15 = bits.RotateLeft32(v15^v0, -16)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -12)
+	v0 += v5 + m5
+	v15 = bits.RotateLeft32(v15^v0, -8)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -7)
+	v1 += v6 + m9
+	v12 = bits.RotateLeft32(v12^v1, -16)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -12)
+	v1 += v6 + m0
+	v12 = bits.RotateLeft32(v12^v1, -8)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -7)
+	v2 += v7 + m11
+	v13 = bits.RotateLeft32(v13^v2, -16)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -12)
+	v2 += v7 + m15
+	v13 = bits.RotateLeft32(v13^v2, -8)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -7)
+	v3 += v4 + m8
+	v14 = bits.RotateLeft32(v14^v3, -16)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -12)
+	v3 += v4 + m1
+	v14 = bits.RotateLeft32(v14^v3, -8)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -7)
+	// Round 4
+	v0 += v4 + m10
+	v12 = bits.RotateLeft32(v12^v0, -16)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -12)
+	v0 += v4 + m7
+	v12 = bits.RotateLeft32(v12^v0, -8)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -7)
+	v1 += v5 + m12
+	v13 = bits.RotateLeft32(v13^v1, -16)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -12)
+	v1 += v5 + m9
+	v13 = bits.RotateLeft32(v13^v1, -8)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -7)
+	v2 += v6 + m14
+	v14 = bits.RotateLeft32(v14^v2, -16)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -12)
+	v2 += v6 + m3
+	v14 = bits.RotateLeft32(v14^v2, -8)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -7)
+	v3 += v7 + m13
+	v15 = bits.RotateLeft32(v15^v3, -16)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -12)
+	v3 += v7 + m15
+	v15 = bits.RotateLeft32(v15^v3, -8)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -7)
+	v0 += v5 + m4
+	v15 = bits.RotateLeft32(v15^v0, -16)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -12)
+	v0 += v5 + m0
+	v15 = bits.RotateLeft32(v15^v0, -8)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -7)
+	v1 += v6 + m11
+	v12 = bits.RotateLeft32(v12^v1, -16)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -12)
+	v1 += v6 + m2
+	v12 = bits.RotateLeft32(v12^v1, -8)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -7)
+	v2 += v7 + m5
+	v13 = bits.RotateLeft32(v13^v2, -16)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -12)
+	v2 += v7 + m8
+	v13 = bits.RotateLeft32(v13^v2, -8)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -7)
+	v3 += v4 + m1
+	v14 = bits.RotateLeft32(v14^v3, -16)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -12)
+	v3 += v4 + m6
+	v14 = bits.RotateLeft32(v14^v3, -8)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -7)
+	// Round 5
+	v0 += v4 + m12
+	v12 = bits.RotateLeft32(v12^v0, -16)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -12)
+	v0 += v4 + m13
+	v12 = bits.RotateLeft32(v12^v0, -8)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -7)
+	v1 += v5 + m9
+	v13 = bits.RotateLeft32(v13^v1, -16)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -12)
+	v1 += v5 + m11
+	v13 = bits.RotateLeft32(v13^v1, -8)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -7)
+	v2 += v6 + m15
+	v14 = bits.RotateLeft32(v14^v2, -16)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -12)
+	v2 += v6 + m10
+	v14 = bits.RotateLeft32(v14^v2, -8)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -7)
+	v3 += v7 + m14
+	v15 = bits.RotateLeft32(v15^v3, -16)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -12)
+	v3 += v7 + m8
+	v15 = bits.RotateLeft32(v15^v3, -8)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -7)
+	v0 += v5 + m7
+	v15 = bits.RotateLeft32(v15^v0, -16)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -12)
+	v0 += v5 + m2
+	v15 = bits.RotateLeft32(v15^v0, -8)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -7)
+	v1 += v6 + m5
+	v12 = bits.RotateLeft32(v12^v1, -16)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -12)
+	v1 += v6 + m3
+	v12 = bits.RotateLeft32(v12^v1, -8)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -7)
+	v2 += v7 + m0
+	v13 = bits.RotateLeft32(v13^v2, -16)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -12)
+	v2 += v7 + m1
+	v13 = bits.RotateLeft32(v13^v2, -8)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -7)
+	v3 += v4 + m6
+	v14 = bits.RotateLeft32(v14^v3, -16)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -12)
+	v3 += v4 + m4
+	v14 = bits.RotateLeft32(v14^v3, -8)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -7)
+	// Round 6
+	v0 += v4 + m9
+	v12 = bits.RotateLeft32(v12^v0, -16)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -12)
+	v0 += v4 + m14
+	v12 = bits.RotateLeft32(v12^v0, -8)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -7)
+	v1 += v5 + m11
+	v13 = bits.RotateLeft32(v13^v1, -16)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -12)
+	v1 += v5 + m5
+	v13 = bits.RotateLeft32(v13^v1, -8)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -7)
+	v2 += v6 + m8
+	v14 = bits.RotateLeft32(v14^v2, -16)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -12)
+	v2 += v6 + m12
+	v14 = bits.RotateLeft32(v14^v2, -8)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -7)
+	v3 += v7 + m15
+	v15 = bits.RotateLeft32(v15^v3, -16)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -12)
+	v3 += v7 + m1
+	v15 = bits.RotateLeft32(v15^v3, -8)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -7)
+	v0 += v5 + m13
+	v15 = bits.RotateLeft32(v15^v0, -16)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -12)
+	v0 += v5 + m3
+	v15 = bits.RotateLeft32(v15^v0, -8)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -7)
+	v1 += v6 + m0
+	v12 = bits.RotateLeft32(v12^v1, -16)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -12)
+	v1 += v6 + m10
+	v12 = bits.RotateLeft32(v12^v1, -8)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -7)
+	v2 += v7 + m2
+	v13 = bits.RotateLeft32(v13^v2, -16)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -12)
+	v2 += v7 + m6
+	v13 = bits.RotateLeft32(v13^v2, -8)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -7)
+	v3 += v4 + m4
+	v14 = bits.RotateLeft32(v14^v3, -16)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -12)
+	v3 += v4 + m7
+	v14 = bits.RotateLeft32(v14^v3, -8)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -7)
+	// Round 7
+	v0 += v4 + m11
+	v12 = bits.RotateLeft32(v12^v0, -16)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -12)
+	v0 += v4 + m15
+	v12 = bits.RotateLeft32(v12^v0, -8)
+	v8 += v12
+	v4 = bits.RotateLeft32(v4^v8, -7)
+	v1 += v5 + m5
+	v13 = bits.RotateLeft32(v13^v1, -16)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -12)
+	v1 += v5 + m0
+	v13 = bits.RotateLeft32(v13^v1, -8)
+	v9 += v13
+	v5 = bits.RotateLeft32(v5^v9, -7)
+	v2 += v6 + m1
+	v14 = bits.RotateLeft32(v14^v2, -16)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -12)
+	v2 += v6 + m9
+	v14 = bits.RotateLeft32(v14^v2, -8)
+	v10 += v14
+	v6 = bits.RotateLeft32(v6^v10, -7)
+	v3 += v7 + m8
+	v15 = bits.RotateLeft32(v15^v3, -16)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -12)
+	v3 += v7 + m6
+	v15 = bits.RotateLeft32(v15^v3, -8)
+	v11 += v15
+	v7 = bits.RotateLeft32(v7^v11, -7)
+	v0 += v5 + m14
+	v15 = bits.RotateLeft32(v15^v0, -16)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -12)
+	v0 += v5 + m10
+	v15 = bits.RotateLeft32(v15^v0, -8)
+	v10 += v15
+	v5 = bits.RotateLeft32(v5^v10, -7)
+	v1 += v6 + m2
+	v12 = bits.RotateLeft32(v12^v1, -16)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -12)
+	v1 += v6 + m12
+	v12 = bits.RotateLeft32(v12^v1, -8)
+	v11 += v12
+	v6 = bits.RotateLeft32(v6^v11, -7)
+	v2 += v7 + m3
+	v13 = bits.RotateLeft32(v13^v2, -16)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -12)
+	v2 += v7 + m4
+	v13 = bits.RotateLeft32(v13^v2, -8)
+	v8 += v13
+	v7 = bits.RotateLeft32(v7^v8, -7)
+	v3 += v4 + m7
+	v14 = bits.RotateLeft32(v14^v3, -16)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -12)
+	v3 += v4 + m13
+	v14 = bits.RotateLeft32(v14^v3, -8)
+	v9 += v14
+	v4 = bits.RotateLeft32(v4^v9, -7)
+
+	return [8]uint32{
+		v0 ^ v8, v1 ^ v9, v2 ^ v10, v3 ^ v11,
+		v4 ^ v12, v5 ^ v13, v6 ^ v14, v7 ^ v15,
+	}
+}
@@ -0,0 +1,71 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hashing
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+// Test vectors of the BLAKE3 specification. The input of each is the
+// repeating sequence 0, 1, ..., 250 of the given length.
+func TestBLAKE3Vectors(t *testing.T) {
+	tests := []struct {
+		length   int
+		expected string
+	}{
+		{length: 0, expected: "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+		{length: 1, expected: "2d3adedff11b61f14c886e35afa036736dcd87a74d27b5c1510225d0f592e213"},
+		{length: 1024, expected: "42214739f095a406f3fc83deb889744ac00df831c10daa55189b5d121c855af7"},
+		{length: 1025, expected: "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444"},
+		{length: 102400, expected: "bc3e3d41a1146b069abffad3c0d44860cf664390afce4d9661f7902e7943e085"},
+	}
+	for _, test := range tests {
+		input := make([]byte, test.length)
+		for i := range input {
+			input[i] = byte(i % 251)
+		}
+		hash := ComputeBLAKE3Array(input)
+		if got := hex.EncodeToString(hash[:]); got != test.expected {
+			t.Fatalf("expected hash of length %d to be %s but got %s", test.length, test.expected, got)
+		}
+	}
+}
+
+func TestHasherRegistry(t *testing.T) {
+	hasher, err := GetHasher256("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasher([]byte{1}) != ComputeHash256Array([]byte{1}) {
+		t.Fatal("SHA-256 should be selected by default")
+	}
+	if _, err := GetHasher256("unknown"); err == nil {
+		t.Fatal("shouldn't have found an unregistered hash function")
+	}
+	if err := RegisterHasher256(BLAKE3, ComputeBLAKE3Array); err == nil {
+		t.Fatal("shouldn't have registered a hash function twice")
+	}
+}
+
+// Compares the hash functions that chains can select. Run with
+// GODEBUG=cpu.sha=off to measure SHA-256 on CPUs without SHA extensions.
+func BenchmarkHasher256(b *testing.B) {
+	for _, name := range []string{SHA256, BLAKE3} {
+		hasher, err := GetHasher256(name)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, size := range []int{64, 1024, 16 * 1024, 1024 * 1024} {
+			buf := make([]byte, size)
+			b.Run(fmt.Sprintf("%s/%d", name, size), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				for n := 0; n < b.N; n++ {
+					hasher(buf)
+				}
+			})
+		}
+	}
+}
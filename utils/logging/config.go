@@ -22,7 +22,21 @@ type Config struct {
 	DisableLogging, DisableDisplaying, DisableContextualDisplaying, DisableFlushOnWrite, Assertions bool
 	LogLevel, DisplayLevel                                                                          Level
 	DisplayHighlight                                                                                Highlight
+	LogFormat, DisplayFormat                                                                        Format
 	Directory, MsgPrefix, LoggerName                                                                string
+
+	// NodeID, Chain and Module are the fields that JSON lines attribute each
+	// message to
+	NodeID, Chain, Module string
+
+	// Compress rotated files with gzip
+	Compress bool
+	// MaxAge after which rotated files are deleted. If 0, rotated files are
+	// only deleted once there are more than RotationSize of them.
+	MaxAge time.Duration
+	// ChainRotations overrides how the logs of chains are rotated, by the
+	// primary alias of the chain
+	ChainRotations map[string]RotationOverride
 }
 
 // DefaultConfig returns a logger configuration with default parameters
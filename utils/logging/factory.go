@@ -3,6 +3,14 @@
 
 package logging
 
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var errUnknownLogger = errors.New("unknown logger")
+
 // Factory creates new instances of different types of Logger
 type Factory interface {
 	// Make creates a new logger with name [name]
@@ -14,64 +22,199 @@ type Factory interface {
 	// MakeChainChild creates a new sublogger for a [name] module of a chain [chainId]
 	MakeChainChild(chainID string, name string) (Logger, error)
 
+	// SetLogLevel sets the log level of the logger named [name]. If [name] is
+	// empty, sets the log level of every logger, including the loggers made
+	// later.
+	SetLogLevel(name string, level Level) error
+
+	// SetDisplayLevel sets the display level of the logger named [name]. If
+	// [name] is empty, sets the display level of every logger, including the
+	// loggers made later.
+	SetDisplayLevel(name string, level Level) error
+
+	// GetLogLevels returns the levels of the logger named [name], by its
+	// name. If [name] is empty, returns the levels of every logger.
+	GetLogLevels(name string) (map[string]LogLevels, error)
+
 	// Close stops and clears all of a Factory's instantiated loggers
 	Close()
 }
 
+// LogLevels are the levels that a logger logs and displays events at
+type LogLevels struct {
+	LogLevel     Level
+	DisplayLevel Level
+}
+
 // factory implements the Factory interface
 type factory struct {
 	config Config
 
-	loggers []Logger
+	lock sync.RWMutex
+	// Logger name --> the loggers with that name, oldest first. Chain loggers
+	// are named after the chain, and their children are named <chain>.<name>.
+	loggers map[string][]Logger
 }
 
 // NewFactory returns a new instance of a Factory producing loggers configured with
 // the values set in the [config] parameter
 func NewFactory(config Config) Factory {
 	return &factory{
-		config: config,
+		config:  config,
+		loggers: make(map[string][]Logger),
+	}
+}
+
+// baseConfig returns the config that new loggers are made from
+func (f *factory) baseConfig() Config {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.config
+}
+
+// makeLogger creates a logger with [config] and tracks it by its name
+func (f *factory) makeLogger(config Config) (Logger, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	log, err := New(config)
+	if err != nil {
+		return nil, err
 	}
+	f.loggers[config.LoggerName] = append(f.loggers[config.LoggerName], log)
+	return log, nil
 }
 
 // Make implements the Factory interface
 func (f *factory) Make(name string) (Logger, error) {
-	config := f.config
+	config := f.baseConfig()
 	config.LoggerName = name
-	l, err := New(config)
-	if err == nil {
-		f.loggers = append(f.loggers, l)
-	}
-	return l, err
+	config.Module = name
+	return f.makeLogger(config)
 }
 
 // MakeChain implements the Factory interface
 func (f *factory) MakeChain(chainID string) (Logger, error) {
-	config := f.config
+	config := f.baseConfig()
 	config.MsgPrefix = chainID + " Chain"
 	config.LoggerName = chainID
-	log, err := New(config)
-	if err == nil {
-		f.loggers = append(f.loggers, log)
+	config.Chain = chainID
+	if err := f.applyChainRotation(&config, chainID); err != nil {
+		return nil, err
 	}
-	return log, err
+	return f.makeLogger(config)
 }
 
 // MakeChainChild implements the Factory interface
 func (f *factory) MakeChainChild(chainID string, name string) (Logger, error) {
-	config := f.config
+	config := f.baseConfig()
 	config.MsgPrefix = chainID + " Chain"
 	config.LoggerName = chainID + "." + name
-	log, err := New(config)
-	if err == nil {
-		f.loggers = append(f.loggers, log)
+	config.Chain = chainID
+	config.Module = name
+	if err := f.applyChainRotation(&config, chainID); err != nil {
+		return nil, err
+	}
+	return f.makeLogger(config)
+}
+
+// applyChainRotation applies the rotation settings of chain [chainID], if it
+// has any, to [config]
+func (f *factory) applyChainRotation(config *Config, chainID string) error {
+	override, ok := f.config.ChainRotations[chainID]
+	if !ok {
+		return nil
+	}
+	if err := override.Apply(config); err != nil {
+		return fmt.Errorf("invalid log rotation of chain %s: %w", chainID, err)
 	}
-	return log, err
+	return nil
+}
+
+// getLoggers returns the loggers named [name], or every logger if [name] is
+// empty. Assumes [f.lock] is held.
+func (f *factory) getLoggers(name string) (map[string][]Logger, error) {
+	if name == "" {
+		return f.loggers, nil
+	}
+	loggers, ok := f.loggers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownLogger, name)
+	}
+	return map[string][]Logger{name: loggers}, nil
+}
+
+// SetLogLevel implements the Factory interface
+func (f *factory) SetLogLevel(name string, level Level) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	loggers, err := f.getLoggers(name)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		f.config.LogLevel = level
+	}
+	for _, named := range loggers {
+		for _, log := range named {
+			log.SetLogLevel(level)
+		}
+	}
+	return nil
+}
+
+// SetDisplayLevel implements the Factory interface
+func (f *factory) SetDisplayLevel(name string, level Level) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	loggers, err := f.getLoggers(name)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		f.config.DisplayLevel = level
+	}
+	for _, named := range loggers {
+		for _, log := range named {
+			log.SetDisplayLevel(level)
+		}
+	}
+	return nil
+}
+
+// GetLogLevels implements the Factory interface
+func (f *factory) GetLogLevels(name string) (map[string]LogLevels, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	loggers, err := f.getLoggers(name)
+	if err != nil {
+		return nil, err
+	}
+	levels := make(map[string]LogLevels, len(loggers))
+	for name, named := range loggers {
+		// Report the most recently created logger with the name
+		log := named[len(named)-1]
+		levels[name] = LogLevels{
+			LogLevel:     log.GetLogLevel(),
+			DisplayLevel: log.GetDisplayLevel(),
+		}
+	}
+	return levels, nil
 }
 
 // Close implements the Factory interface
 func (f *factory) Close() {
-	for _, log := range f.loggers {
-		log.Stop()
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, named := range f.loggers {
+		for _, log := range named {
+			log.Stop()
+		}
 	}
-	f.loggers = nil
+	f.loggers = make(map[string][]Logger)
 }
@@ -0,0 +1,107 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestFactorySetLevels(t *testing.T) {
+	dir, err := ioutil.TempDir("", "factory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config, err := DefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Directory = dir
+	config.LogLevel = Info
+	config.DisplayLevel = Info
+	f := NewFactory(config)
+	defer f.Close()
+
+	network, err := f.Make("network")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := f.MakeChain("X")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A chain that is rebuilt makes a second logger with the same name
+	rebuilt, err := f.MakeChain("X")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.SetLogLevel("X", Debug); err != nil {
+		t.Fatal(err)
+	}
+	if level := network.GetLogLevel(); level != Info {
+		t.Fatalf("network log level changed to %s", level)
+	}
+	if level := chain.GetLogLevel(); level != Debug {
+		t.Fatalf("chain log level is %s, expected %s", level, Debug)
+	}
+	if level := rebuilt.GetLogLevel(); level != Debug {
+		t.Fatalf("rebuilt chain log level is %s, expected %s", level, Debug)
+	}
+
+	if err := f.SetDisplayLevel("", Warn); err != nil {
+		t.Fatal(err)
+	}
+	levels, err := f.GetLogLevels("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]LogLevels{
+		"network": {LogLevel: Info, DisplayLevel: Warn},
+		"X":       {LogLevel: Debug, DisplayLevel: Warn},
+	}
+	if len(levels) != len(expected) {
+		t.Fatalf("got the levels of %d loggers, expected %d", len(levels), len(expected))
+	}
+	for name, expectedLevels := range expected {
+		if levels[name] != expectedLevels {
+			t.Fatalf("logger %s has levels %+v, expected %+v", name, levels[name], expectedLevels)
+		}
+	}
+
+	// Loggers made after the levels of every logger were set use them too
+	later, err := f.Make("later")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level := later.GetDisplayLevel(); level != Warn {
+		t.Fatalf("later display level is %s, expected %s", level, Warn)
+	}
+	if level := later.GetLogLevel(); level != Info {
+		t.Fatalf("later log level is %s, expected %s", level, Info)
+	}
+}
+
+func TestFactoryUnknownLogger(t *testing.T) {
+	config, err := DefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := NewFactory(config)
+	defer f.Close()
+
+	if err := f.SetLogLevel("missing", Debug); !errors.Is(err, errUnknownLogger) {
+		t.Fatalf("expected %s but got %v", errUnknownLogger, err)
+	}
+	if err := f.SetDisplayLevel("missing", Debug); !errors.Is(err, errUnknownLogger) {
+		t.Fatalf("expected %s but got %v", errUnknownLogger, err)
+	}
+	if _, err := f.GetLogLevels("missing"); !errors.Is(err, errUnknownLogger) {
+		t.Fatalf("expected %s but got %v", errUnknownLogger, err)
+	}
+}
@@ -0,0 +1,108 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Format of the lines written to a log output
+type Format int
+
+// Formats available
+const (
+	// Text lines are meant to be read by people
+	Text Format = iota
+	// JSON lines are meant to be ingested by log collectors. Every line is a
+	// JSON object with the same fields.
+	JSON
+)
+
+// ToFormat chooses a format
+func ToFormat(f string) (Format, error) {
+	switch strings.ToUpper(f) {
+	case "TEXT":
+		return Text, nil
+	case "JSON":
+		return JSON, nil
+	default:
+		return Text, fmt.Errorf("unknown log format: %s", f)
+	}
+}
+
+func (f Format) String() string {
+	switch f {
+	case Text:
+		return "text"
+	case JSON:
+		return "json"
+	default:
+		return "unknown"
+	}
+}
+
+// entry is a single message that is logged, before it's formatted
+type entry struct {
+	time   time.Time
+	level  Level
+	caller string
+	msg    string
+}
+
+// jsonEntry is the object that an entry is written as in the JSON format. All
+// fields are always present, so that collectors can rely on them.
+type jsonEntry struct {
+	Time   string `json:"time"`
+	Level  string `json:"level"`
+	Node   string `json:"node"`
+	Chain  string `json:"chain"`
+	Module string `json:"module"`
+	Caller string `json:"caller"`
+	Msg    string `json:"msg"`
+}
+
+// formatText returns [e] as a line of text
+func (e *entry) formatText(config *Config) string {
+	prefix := ""
+	if config.MsgPrefix != "" {
+		prefix = fmt.Sprintf(" <%s>", config.MsgPrefix)
+	}
+
+	return fmt.Sprintf("%s[%s]%s %s: %s\n",
+		e.level,
+		e.time.Format("01-02|15:04:05"),
+		prefix,
+		e.caller,
+		e.msg)
+}
+
+// formatJSON returns [e] as a line holding a JSON object
+func (e *entry) formatJSON(config *Config) string {
+	line, err := json.Marshal(jsonEntry{
+		Time:   e.time.UTC().Format(time.RFC3339Nano),
+		Level:  strings.TrimSpace(e.level.String()),
+		Node:   config.NodeID,
+		Chain:  config.Chain,
+		Module: config.Module,
+		Caller: e.caller,
+		Msg:    e.msg,
+	})
+	if err != nil {
+		// Marshalling strings can't fail, but don't drop the message if it
+		// somehow does
+		return e.formatText(config)
+	}
+	return string(line) + "\n"
+}
+
+// format returns [e] as a line in [format]
+func (e *entry) format(config *Config, format Format) string {
+	if format == JSON {
+		return e.formatJSON(config)
+	}
+	return e.formatText(config)
+}
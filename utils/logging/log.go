@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
@@ -157,9 +156,10 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 
 	args = SanitizeArgs(args)
 
-	output := l.format(level, format, args...)
+	entry := l.newEntry(level, format, args...)
 
 	if shouldLog {
+		output := entry.format(&l.config, l.config.LogFormat)
 		l.flushLock.Lock()
 		l.messages = append(l.messages, output)
 		l.size += len(output)
@@ -170,16 +170,18 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 	if shouldDisplay {
 		switch {
 		case l.config.DisableContextualDisplaying:
-			fmt.Println(fmt.Sprintf(format, args...))
+			fmt.Println(entry.msg)
+		case l.config.DisplayFormat == JSON:
+			fmt.Print(entry.formatJSON(&l.config))
 		case l.config.DisplayHighlight == Plain:
-			fmt.Print(output)
+			fmt.Print(entry.formatText(&l.config))
 		default:
-			fmt.Print(level.Color().Wrap(output))
+			fmt.Print(level.Color().Wrap(entry.formatText(&l.config)))
 		}
 	}
 }
 
-func (l *Log) format(level Level, format string, args ...interface{}) string {
+func (l *Log) newEntry(level Level, format string, args ...interface{}) *entry {
 	loc := "?"
 	if _, file, no, ok := runtime.Caller(3); ok {
 		loc = fmt.Sprintf("%s#%d", file, no)
@@ -187,18 +189,12 @@ func (l *Log) format(level Level, format string, args ...interface{}) string {
 	if i := strings.Index(loc, filePrefix); i != -1 {
 		loc = loc[i+len(filePrefix):]
 	}
-	text := fmt.Sprintf("%s: %s", loc, fmt.Sprintf(format, args...))
-
-	prefix := ""
-	if l.config.MsgPrefix != "" {
-		prefix = fmt.Sprintf(" <%s>", l.config.MsgPrefix)
+	return &entry{
+		time:   time.Now(),
+		level:  level,
+		caller: loc,
+		msg:    fmt.Sprintf(format, args...),
 	}
-
-	return fmt.Sprintf("%s[%s]%s %s\n",
-		level,
-		time.Now().Format("01-02|15:04:05"),
-		prefix,
-		text)
 }
 
 // Fatal implements the Logger interface
@@ -308,6 +304,22 @@ func (l *Log) SetDisplayLevel(lvl Level) {
 	l.config.DisplayLevel = lvl
 }
 
+// GetLogLevel implements the Logger interface
+func (l *Log) GetLogLevel() Level {
+	l.configLock.Lock()
+	defer l.configLock.Unlock()
+
+	return l.config.LogLevel
+}
+
+// GetDisplayLevel implements the Logger interface
+func (l *Log) GetDisplayLevel() Level {
+	l.configLock.Lock()
+	defer l.configLock.Unlock()
+
+	return l.config.DisplayLevel
+}
+
 // SetPrefix implements the Logger interface
 func (l *Log) SetPrefix(prefix string) {
 	l.configLock.Lock()
@@ -371,17 +383,31 @@ func (fw *fileWriter) Close() error {
 // Rotate implements the RotatingWriter interface
 func (fw *fileWriter) Rotate() error {
 	for i := fw.config.RotationSize - 1; i > 0; i-- {
-		sourceFilename := filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log.%d", fw.config.LoggerName, i))
-		destFilename := filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log.%d", fw.config.LoggerName, i+1))
-		if _, err := os.Stat(sourceFilename); !errors.Is(err, os.ErrNotExist) {
-			if err := os.Rename(sourceFilename, destFilename); err != nil {
+		for _, compressed := range []bool{false, true} {
+			sourceFilename := fw.rotatedFilename(i, compressed)
+			if _, err := os.Stat(sourceFilename); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			// Don't keep a file of the other kind at the same position
+			if err := removeIfExists(fw.rotatedFilename(i+1, !compressed)); err != nil {
+				return err
+			}
+			if err := os.Rename(sourceFilename, fw.rotatedFilename(i+1, compressed)); err != nil {
 				return err
 			}
 		}
 	}
-	sourceFilename := filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log", fw.config.LoggerName))
-	destFilename := filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log.1", fw.config.LoggerName))
-	if err := os.Rename(sourceFilename, destFilename); err != nil {
+	if err := removeIfExists(fw.rotatedFilename(1, !fw.config.Compress)); err != nil {
+		return err
+	}
+	if fw.config.Compress {
+		if err := compressFile(fw.filename(), fw.rotatedFilename(1, true)); err != nil {
+			return err
+		}
+	} else if err := os.Rename(fw.filename(), fw.rotatedFilename(1, false)); err != nil {
+		return err
+	}
+	if err := fw.removeExpired(); err != nil {
 		return err
 	}
 	writer, file, err := fw.create()
@@ -395,8 +421,7 @@ func (fw *fileWriter) Rotate() error {
 
 // Creates a file if it does not exist or opens it in append mode if it does
 func (fw *fileWriter) create() (*bufio.Writer, *os.File, error) {
-	filename := filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log", fw.config.LoggerName))
-	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, perms.ReadWrite)
+	file, err := os.OpenFile(fw.filename(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, perms.ReadWrite)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,6 +1,11 @@
 package logging
 
-import "testing"
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestLog(t *testing.T) {
 	config, err := DefaultConfig()
@@ -26,3 +31,65 @@ func TestLog(t *testing.T) {
 		t.Fatalf("Exit function was never called")
 	}
 }
+
+func TestEntryFormat(t *testing.T) {
+	config := Config{
+		MsgPrefix: "X Chain",
+		NodeID:    "NodeID-111111111111111111116DBWJs",
+		Chain:     "X",
+		Module:    "vm",
+	}
+	e := &entry{
+		time:   time.Date(2021, time.March, 4, 5, 6, 7, 0, time.Local),
+		level:  Warn,
+		caller: "vms/avm/vm.go#10",
+		msg:    "line one\nline \"two\"",
+	}
+
+	expectedText := "WARN [03-04|05:06:07] <X Chain> vms/avm/vm.go#10: line one\nline \"two\"\n"
+	if text := e.format(&config, Text); text != expectedText {
+		t.Fatalf("expected %q but got %q", expectedText, text)
+	}
+
+	line := e.format(&config, JSON)
+	if !strings.HasSuffix(line, "\n") || strings.Count(line, "\n") != 1 {
+		t.Fatalf("expected a single line but got %q", line)
+	}
+	parsed := jsonEntry{}
+	if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	expected := jsonEntry{
+		Time:   e.time.UTC().Format(time.RFC3339Nano),
+		Level:  "WARN",
+		Node:   config.NodeID,
+		Chain:  "X",
+		Module: "vm",
+		Caller: "vms/avm/vm.go#10",
+		Msg:    e.msg,
+	}
+	if parsed != expected {
+		t.Fatalf("expected %+v but got %+v", expected, parsed)
+	}
+}
+
+func TestToFormat(t *testing.T) {
+	for _, test := range []struct {
+		str    string
+		format Format
+	}{
+		{"text", Text},
+		{"JSON", JSON},
+	} {
+		format, err := ToFormat(test.str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if format != test.format {
+			t.Fatalf("expected %s but got %s", test.format, format)
+		}
+	}
+	if _, err := ToFormat("xml"); err == nil {
+		t.Fatal("should have failed to parse an unknown format")
+	}
+}
@@ -60,6 +60,10 @@ type Logger interface {
 	SetLogLevel(Level)
 	// Only logged events above or equal to the level set will be logged
 	SetDisplayLevel(Level)
+	// Returns the level that events are logged at
+	GetLogLevel() Level
+	// Returns the level that logged events are displayed at
+	GetDisplayLevel() Level
 	// Add a prefix to all logged messages
 	SetPrefix(string)
 	// Enable or disable logging
@@ -0,0 +1,160 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+const compressedExt = ".gz"
+
+var errNegativeRotation = errors.New("rotation settings can't be negative")
+
+// RotationOverride overrides how the files of the logs of a chain are rotated
+// and retained. Fields that are left empty keep the value of the node's
+// logging config.
+type RotationOverride struct {
+	// Interval after which the log file is rotated, such as "24h"
+	Interval string `json:"interval"`
+
+	// FileSize is the size in bytes that the log file is rotated at
+	FileSize int `json:"fileSize"`
+
+	// Files is the number of rotated files that are kept
+	Files int `json:"files"`
+
+	// MaxAge after which rotated files are deleted, such as "168h"
+	MaxAge string `json:"maxAge"`
+
+	// Compress is whether rotated files are compressed with gzip
+	Compress *bool `json:"compress"`
+}
+
+// Apply the overridden settings to [config]
+func (o *RotationOverride) Apply(config *Config) error {
+	if o.FileSize < 0 || o.Files < 0 {
+		return errNegativeRotation
+	}
+	if o.Interval != "" {
+		interval, err := time.ParseDuration(o.Interval)
+		if err != nil {
+			return fmt.Errorf("couldn't parse rotation interval: %w", err)
+		}
+		if interval <= 0 {
+			return errNegativeRotation
+		}
+		config.RotationInterval = interval
+	}
+	if o.FileSize != 0 {
+		config.FileSize = o.FileSize
+	}
+	if o.Files != 0 {
+		config.RotationSize = o.Files
+	}
+	if o.MaxAge != "" {
+		maxAge, err := time.ParseDuration(o.MaxAge)
+		if err != nil {
+			return fmt.Errorf("couldn't parse max age: %w", err)
+		}
+		if maxAge < 0 {
+			return errNegativeRotation
+		}
+		config.MaxAge = maxAge
+	}
+	if o.Compress != nil {
+		config.Compress = *o.Compress
+	}
+	return nil
+}
+
+// filename returns the path of the log file that is written to
+func (fw *fileWriter) filename() string {
+	return filepath.Join(fw.config.Directory, fmt.Sprintf("%s.log", fw.config.LoggerName))
+}
+
+// rotatedFilename returns the path of the [i]-th most recently rotated file
+func (fw *fileWriter) rotatedFilename(i int, compressed bool) string {
+	filename := fmt.Sprintf("%s.%d", fw.filename(), i)
+	if compressed {
+		filename += compressedExt
+	}
+	return filename
+}
+
+// compressFile writes [src] compressed with gzip to [dst] and removes [src]
+func compressFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perms.ReadWrite)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// removeExpired removes the rotated files that were last written to more than
+// MaxAge ago
+func (fw *fileWriter) removeExpired() error {
+	if fw.config.MaxAge <= 0 {
+		return nil
+	}
+
+	prefix := fw.filename() + "."
+	files, err := filepath.Glob(prefix + "*")
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-fw.config.MaxAge)
+	for _, file := range files {
+		// Only remove the files that this log rotated
+		index := strings.TrimSuffix(strings.TrimPrefix(file, prefix), compressedExt)
+		if _, err := strconv.Atoi(index); err != nil {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if info.ModTime().Before(cutoff) {
+			if err := os.Remove(file); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// removeIfExists removes [filename], if it exists
+func removeIfExists(filename string) error {
+	if err := os.Remove(filename); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func newTestFileWriter(t *testing.T, config Config) *fileWriter {
+	dir, err := ioutil.TempDir("", "rotation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	config.Directory = dir
+	config.LoggerName = "test"
+	fw := &fileWriter{}
+	if _, err := fw.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = fw.Close() })
+	return fw
+}
+
+// writeAndRotate writes [line] to the log file and rotates it
+func writeAndRotate(t *testing.T, fw *fileWriter, line string) {
+	if _, err := fw.WriteString(line); err != nil {
+		t.Fatal(err)
+	}
+	if err := fw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fw.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readCompressed(t *testing.T, filename string) string {
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, err := ioutil.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(contents)
+}
+
+func TestRotateCompressed(t *testing.T) {
+	fw := newTestFileWriter(t, Config{
+		RotationSize: 2,
+		Compress:     true,
+	})
+
+	for i := 0; i < 3; i++ {
+		writeAndRotate(t, fw, fmt.Sprintf("line %d\n", i))
+	}
+
+	// Only the two most recent rotations are kept
+	if contents := readCompressed(t, fw.rotatedFilename(1, true)); contents != "line 2\n" {
+		t.Fatalf("expected %q but got %q", "line 2\n", contents)
+	}
+	if contents := readCompressed(t, fw.rotatedFilename(2, true)); contents != "line 1\n" {
+		t.Fatalf("expected %q but got %q", "line 1\n", contents)
+	}
+	for _, filename := range []string{
+		fw.rotatedFilename(3, true),
+		fw.rotatedFilename(1, false),
+	} {
+		if _, err := os.Stat(filename); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("%s shouldn't exist", filename)
+		}
+	}
+
+	// Rotated files that weren't compressed are shifted along with the
+	// compressed ones
+	fw.config.Compress = false
+	writeAndRotate(t, fw, "line 3\n")
+	contents, err := ioutil.ReadFile(fw.rotatedFilename(1, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(contents) != "line 3\n" {
+		t.Fatalf("expected %q but got %q", "line 3\n", contents)
+	}
+	if contents := readCompressed(t, fw.rotatedFilename(2, true)); contents != "line 2\n" {
+		t.Fatalf("expected %q but got %q", "line 2\n", contents)
+	}
+}
+
+func TestRotateRemovesExpired(t *testing.T) {
+	fw := newTestFileWriter(t, Config{
+		RotationSize: 5,
+		MaxAge:       time.Hour,
+	})
+
+	writeAndRotate(t, fw, "old\n")
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(fw.rotatedFilename(1, false), old, old); err != nil {
+		t.Fatal(err)
+	}
+	writeAndRotate(t, fw, "new\n")
+
+	if _, err := os.Stat(fw.rotatedFilename(1, false)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(fw.rotatedFilename(2, false)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatal("expired file should have been removed")
+	}
+	if _, err := os.Stat(fw.filename()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRotationOverride(t *testing.T) {
+	config := Config{
+		RotationInterval: 24 * time.Hour,
+		FileSize:         1 << 23,
+		RotationSize:     7,
+	}
+	compress := true
+	override := RotationOverride{
+		Interval: "1h",
+		Files:    30,
+		MaxAge:   "720h",
+		Compress: &compress,
+	}
+	if err := override.Apply(&config); err != nil {
+		t.Fatal(err)
+	}
+	expected := Config{
+		RotationInterval: time.Hour,
+		FileSize:         1 << 23,
+		RotationSize:     30,
+		MaxAge:           720 * time.Hour,
+		Compress:         true,
+	}
+	if config.RotationInterval != expected.RotationInterval ||
+		config.FileSize != expected.FileSize ||
+		config.RotationSize != expected.RotationSize ||
+		config.MaxAge != expected.MaxAge ||
+		config.Compress != expected.Compress {
+		t.Fatalf("expected %+v but got %+v", expected, config)
+	}
+
+	if err := (&RotationOverride{Files: -1}).Apply(&config); !errors.Is(err, errNegativeRotation) {
+		t.Fatalf("expected %s but got %v", errNegativeRotation, err)
+	}
+	if err := (&RotationOverride{MaxAge: "forever"}).Apply(&config); err == nil {
+		t.Fatal("should have failed to parse the max age")
+	}
+}
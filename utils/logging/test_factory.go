@@ -15,5 +15,14 @@ func (NoFactory) MakeChain(string) (Logger, error) { return NoLog{}, nil }
 // MakeChainChild ...
 func (NoFactory) MakeChainChild(string, string) (Logger, error) { return NoLog{}, nil }
 
+// SetLogLevel ...
+func (NoFactory) SetLogLevel(string, Level) error { return nil }
+
+// SetDisplayLevel ...
+func (NoFactory) SetDisplayLevel(string, Level) error { return nil }
+
+// GetLogLevels ...
+func (NoFactory) GetLogLevels(string) (map[string]LogLevels, error) { return nil, nil }
+
 // Close ...
 func (NoFactory) Close() {}
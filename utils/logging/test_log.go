@@ -66,6 +66,12 @@ func (NoLog) SetLogLevel(Level) {}
 // SetDisplayLevel ...
 func (NoLog) SetDisplayLevel(Level) {}
 
+// GetLogLevel ...
+func (NoLog) GetLogLevel() Level { return Off }
+
+// GetDisplayLevel ...
+func (NoLog) GetDisplayLevel() Level { return Off }
+
 // SetPrefix ...
 func (NoLog) SetPrefix(string) {}
 
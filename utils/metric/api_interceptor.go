@@ -34,6 +34,7 @@ func NewAPIInterceptor(namespace string, registerer prometheus.Registerer) (APII
 		prometheus.HistogramOpts{
 			Namespace: namespace,
 			Name:      "request_duration_ms",
+			Help:      "Duration of handled API requests in milliseconds",
 			Buckets:   MillisecondsHTTPBuckets,
 		},
 		[]string{"method"},
@@ -42,6 +43,7 @@ func NewAPIInterceptor(namespace string, registerer prometheus.Registerer) (APII
 		prometheus.CounterOpts{
 			Namespace: namespace,
 			Name:      "request_error_count",
+			Help:      "Number of API requests that returned an error",
 		},
 		[]string{"method"},
 	)
@@ -0,0 +1,29 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metric
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// ContainerExemplarLabel is the label of the exemplars that hold the ID of the
+// container an observation was measured on
+const ContainerExemplarLabel = "containerID"
+
+// ObserveContainer adds [value] to [histogram] with an exemplar holding
+// [containerID], so that a latency can be traced back to a container whose
+// lifecycle can be looked up. Exemplars are only exposed in the OpenMetrics
+// format.
+func ObserveContainer(histogram prometheus.Histogram, value float64, containerID ids.ID) {
+	observer, ok := histogram.(prometheus.ExemplarObserver)
+	if !ok {
+		histogram.Observe(value)
+		return
+	}
+	observer.ObserveWithExemplar(value, prometheus.Labels{
+		ContainerExemplarLabel: containerID.String(),
+	})
+}
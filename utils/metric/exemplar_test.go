@@ -0,0 +1,46 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metric
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestObserveContainer(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "latency",
+		Buckets: MillisecondsBuckets,
+	})
+	assert.NoError(registry.Register(histogram))
+
+	// The longest container IDs must fit in an exemplar
+	containerID := ids.ID{}
+	for i := range containerID {
+		containerID[i] = 0xff
+	}
+	ObserveContainer(histogram, 200, containerID)
+
+	metricFamilies, err := registry.Gather()
+	assert.NoError(err)
+	assert.Len(metricFamilies, 1)
+	buckets := metricFamilies[0].GetMetric()[0].GetHistogram().GetBucket()
+
+	// The exemplar is attached to the first bucket the value falls in
+	exemplar := buckets[2].GetExemplar()
+	assert.NotNil(exemplar)
+	assert.Equal(200.0, exemplar.GetValue())
+	labels := exemplar.GetLabel()
+	assert.Len(labels, 1)
+	assert.Equal(ContainerExemplarLabel, labels[0].GetName())
+	assert.Equal(containerID.String(), labels[0].GetValue())
+	assert.Nil(buckets[1].GetExemplar())
+}
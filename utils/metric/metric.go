@@ -5,6 +5,7 @@ package metric
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -17,3 +18,16 @@ func NewNanosecondsLatencyMetric(namespace, name string) prometheus.Histogram {
 		Buckets:   NanosecondsBuckets,
 	})
 }
+
+// SanitizeName replaces every character of [name] that isn't allowed in a
+// prometheus metric name with an underscore
+func SanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == ':':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,16 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metric
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeName(t *testing.T) {
+	assert.Equal(t, "avm", SanitizeName("avm"))
+	assert.Equal(t, "subnet_evm_v2", SanitizeName("subnet-evm.v2"))
+	assert.Equal(t, "a:b_c", SanitizeName("a:b c"))
+}
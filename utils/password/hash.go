@@ -16,15 +16,22 @@ type Hash struct {
 	Salt     [16]byte `serialize:"true"` // The salt
 }
 
-// Set updates the password hash to be of the provided password
+// Set updates the password hash to be of the provided password, with a new
+// salt
 func (h *Hash) Set(password string) error {
 	if _, err := rand.Read(h.Salt[:]); err != nil {
 		return err
 	}
+	h.SetWithSalt(password)
+	return nil
+}
+
+// SetWithSalt updates the password hash to be of the provided password, with
+// the current salt
+func (h *Hash) SetWithSalt(password string) {
 	// pw is the salted, hashed password
 	pw := argon2.IDKey([]byte(password), h.Salt[:], 1, 64*1024, 4, 32)
 	copy(h.Password[:], pw[:32])
-	return nil
 }
 
 // Check returns true iff the provided password was the same as the last
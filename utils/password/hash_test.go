@@ -21,4 +21,10 @@ func TestHash(t *testing.T) {
 	if h.Check("") {
 		t.Fatalf("Shouldn't have verified the password")
 	}
+
+	salted := Hash{Salt: h.Salt}
+	salted.SetWithSalt("heytherepal")
+	if salted != h {
+		t.Fatalf("Should have hashed the password with the same salt")
+	}
 }
@@ -6,10 +6,13 @@ package profiler
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"time"
 
 	"golang.org/x/sync/errgroup"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
 // Config that is used to describe the options of the continuous profiler.
@@ -18,6 +21,8 @@ type Config struct {
 	Enabled     bool
 	Freq        time.Duration
 	MaxNumFiles int
+	// If non-empty, each profile is uploaded to this URL once it's written
+	UploadURL string
 }
 
 // ContinuousProfiler periodically captures CPU, memory, and lock profiles
@@ -27,19 +32,36 @@ type ContinuousProfiler interface {
 }
 
 type continuousProfiler struct {
+	log         logging.Logger
 	profiler    *profiler
 	freq        time.Duration
 	maxNumFiles int
 
+	// If non-nil, profiles are uploaded with uploader
+	uploader Uploader
+	// Holds a value while profiles are being uploaded
+	uploading chan struct{}
+
 	// Dispatch returns when closer is closed
 	closer chan struct{}
 }
 
-func NewContinuous(dir string, freq time.Duration, maxNumFiles int) ContinuousProfiler {
+// NewContinuous returns a profiler that writes profiles to [dir] every
+// [freq]. If [uploader] is non-nil, the profiles are uploaded with it too.
+func NewContinuous(
+	log logging.Logger,
+	dir string,
+	freq time.Duration,
+	maxNumFiles int,
+	uploader Uploader,
+) ContinuousProfiler {
 	return &continuousProfiler{
+		log:         log,
 		profiler:    new(dir),
 		freq:        freq,
 		maxNumFiles: maxNumFiles,
+		uploader:    uploader,
+		uploading:   make(chan struct{}, 1),
 		closer:      make(chan struct{}),
 	}
 }
@@ -49,6 +71,7 @@ func (p *continuousProfiler) Dispatch() error {
 	defer t.Stop()
 
 	for {
+		start := time.Now()
 		if err := p.start(); err != nil {
 			return err
 		}
@@ -62,6 +85,7 @@ func (p *continuousProfiler) Dispatch() error {
 			}
 		}
 
+		p.upload(start, time.Now())
 		if err := p.rotate(); err != nil {
 			return err
 		}
@@ -88,6 +112,46 @@ func (p *continuousProfiler) rotate() error {
 	return g.Wait()
 }
 
+// upload the profiles that were just written, measured from [start] until
+// [end], in the background. Failed uploads are logged rather than returned so
+// that an unavailable endpoint doesn't stop the profiles from being written.
+func (p *continuousProfiler) upload(start, end time.Time) {
+	if p.uploader == nil {
+		return
+	}
+	select {
+	case p.uploading <- struct{}{}:
+	default:
+		p.log.Warn("skipping upload of profiles because the previous upload hasn't finished")
+		return
+	}
+
+	// Read the profiles before they're rotated
+	profiles := make(map[string][]byte, 3)
+	for profileType, name := range map[string]string{
+		CPUProfileType:  p.profiler.cpuProfileName,
+		HeapProfileType: p.profiler.memProfileName,
+		LockProfileType: p.profiler.lockProfileName,
+	} {
+		profile, err := ioutil.ReadFile(name)
+		if err != nil {
+			p.log.Warn("couldn't read %s profile to upload: %s", profileType, err)
+			continue
+		}
+		profiles[profileType] = profile
+	}
+
+	go p.log.RecoverAndPanic(func() {
+		defer func() { <-p.uploading }()
+
+		for profileType, profile := range profiles {
+			if err := p.uploader.Upload(profileType, start, end, profile); err != nil {
+				p.log.Warn("couldn't upload %s profile: %s", profileType, err)
+			}
+		}
+	})
+}
+
 func (p *continuousProfiler) Shutdown() {
 	close(p.closer)
 }
@@ -0,0 +1,172 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package profiler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+const (
+	// MaxGateDuration is the longest that the profiling endpoints can be
+	// enabled for at once
+	MaxGateDuration = time.Hour
+
+	// Fraction of mutex contention events, and rate of blocking events, that
+	// are sampled while the profiling endpoints are enabled
+	gateMutexProfileFraction = 5
+	gateBlockProfileRate     = 5
+)
+
+var (
+	// GateEndpoints are the endpoints served by a Gate
+	GateEndpoints = []string{
+		"/profile",
+		"/trace",
+		"/heap",
+		"/allocs",
+		"/goroutine",
+		"/block",
+		"/mutex",
+		"/threadcreate",
+		"/cmdline",
+		"/symbol",
+	}
+
+	errGateDurationTooLong  = fmt.Errorf("profiling can't be enabled for longer than %s", MaxGateDuration)
+	errGateDurationTooShort = errors.New("profiling must be enabled for a positive duration")
+)
+
+// Gate serves the pprof endpoints, including CPU and heap profiles and
+// execution traces, only while they are enabled. They are only ever enabled
+// for a limited duration, so that a production node can't be left exposed by
+// mistake.
+type Gate struct {
+	log   logging.Logger
+	clock timer.Clock
+
+	lock         sync.Mutex
+	enabledUntil time.Time
+	disableTimer *time.Timer
+	// Mutex profile fraction to restore when the gate is disabled
+	prevMutexProfileFraction int
+}
+
+// NewGate returns a new, disabled, gate
+func NewGate(log logging.Logger) *Gate {
+	return &Gate{log: log}
+}
+
+// Enable the endpoints for [duration]. If the endpoints are already enabled,
+// they are disabled [duration] from now instead. Returns the time that the
+// endpoints will be disabled at.
+func (g *Gate) Enable(duration time.Duration) (time.Time, error) {
+	switch {
+	case duration <= 0:
+		return time.Time{}, errGateDurationTooShort
+	case duration > MaxGateDuration:
+		return time.Time{}, errGateDurationTooLong
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.enabledUntil = g.clock.Time().Add(duration)
+	if g.disableTimer == nil {
+		g.prevMutexProfileFraction = runtime.SetMutexProfileFraction(gateMutexProfileFraction)
+		runtime.SetBlockProfileRate(gateBlockProfileRate)
+		g.disableTimer = time.AfterFunc(duration, g.expire)
+	} else {
+		g.disableTimer.Reset(duration)
+	}
+	g.log.Info("profiling endpoints enabled until %s", g.enabledUntil)
+	return g.enabledUntil, nil
+}
+
+// Disable the endpoints. Does nothing if they are already disabled.
+func (g *Gate) Disable() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.disable()
+}
+
+// expire disables the endpoints if they weren't enabled again since the
+// disable timer was last set
+func (g *Gate) expire() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.clock.Time().Before(g.enabledUntil) {
+		return
+	}
+	g.disable()
+}
+
+// Assumes the lock is held
+func (g *Gate) disable() {
+	if g.disableTimer == nil {
+		return
+	}
+	g.disableTimer.Stop()
+	g.disableTimer = nil
+	g.enabledUntil = time.Time{}
+
+	runtime.SetMutexProfileFraction(g.prevMutexProfileFraction)
+	runtime.SetBlockProfileRate(0)
+	g.log.Info("profiling endpoints disabled")
+}
+
+// EnabledUntil returns the time that the endpoints will be disabled at, or
+// false if they are disabled
+func (g *Gate) EnabledUntil() (time.Time, bool) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return g.enabledUntil, g.enabled()
+}
+
+// Assumes the lock is held
+func (g *Gate) enabled() bool {
+	return g.disableTimer != nil && g.clock.Time().Before(g.enabledUntil)
+}
+
+// Handler returns the handler of [endpoint], which must be one of
+// GateEndpoints. The handler responds with 403 while the gate is disabled.
+func (g *Gate) Handler(endpoint string) http.Handler {
+	var handler http.Handler
+	switch endpoint {
+	case "/profile":
+		handler = http.HandlerFunc(pprof.Profile)
+	case "/trace":
+		handler = http.HandlerFunc(pprof.Trace)
+	case "/cmdline":
+		handler = http.HandlerFunc(pprof.Cmdline)
+	case "/symbol":
+		handler = http.HandlerFunc(pprof.Symbol)
+	default:
+		handler = pprof.Handler(strings.TrimPrefix(endpoint, "/"))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.lock.Lock()
+		enabled := g.enabled()
+		g.lock.Unlock()
+
+		if !enabled {
+			http.Error(w, "profiling is disabled", http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
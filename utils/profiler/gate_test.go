@@ -0,0 +1,55 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package profiler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestGate(t *testing.T) {
+	assert := assert.New(t)
+
+	g := NewGate(logging.NoLog{})
+	handler := g.Handler("/heap")
+
+	serve := func() int {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ext/pprof/heap", nil))
+		return rr.Code
+	}
+
+	// The gate starts disabled
+	assert.Equal(http.StatusForbidden, serve())
+	_, enabled := g.EnabledUntil()
+	assert.False(enabled)
+
+	_, err := g.Enable(0)
+	assert.Error(err)
+	_, err = g.Enable(MaxGateDuration + time.Second)
+	assert.Error(err)
+
+	until, err := g.Enable(time.Minute)
+	assert.NoError(err)
+	assert.True(until.After(time.Now()))
+	assert.Equal(http.StatusOK, serve())
+
+	g.Disable()
+	assert.Equal(http.StatusForbidden, serve())
+
+	// The gate disables itself once the duration passes
+	_, err = g.Enable(10 * time.Millisecond)
+	assert.NoError(err)
+	assert.Equal(http.StatusOK, serve())
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(http.StatusForbidden, serve())
+	_, enabled = g.EnabledUntil()
+	assert.False(enabled)
+}
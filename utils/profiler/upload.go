@@ -0,0 +1,90 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package profiler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// CPUProfileType is the type of uploaded CPU profiles
+	CPUProfileType = "cpu"
+	// HeapProfileType is the type of uploaded heap profiles
+	HeapProfileType = "heap"
+	// LockProfileType is the type of uploaded lock profiles
+	LockProfileType = "lock"
+
+	// Longest that an upload of a profile may take
+	uploadTimeout = time.Minute
+)
+
+var (
+	errInvalidUploadURL = errors.New("profile upload URL must be an http or https URL")
+
+	_ Uploader = &httpUploader{}
+)
+
+// Uploader sends profiles to where they're analyzed
+type Uploader interface {
+	// Upload the pprof encoded [profile] of [profileType] that was measured
+	// from [start] until [end]
+	Upload(profileType string, start, end time.Time, profile []byte) error
+}
+
+// NewUploader returns an uploader that POSTs each profile to [endpoint] as the
+// request body, which is the format that pprof compatible profile stores
+// ingest. The type of the profile and the unix timestamps of the start and end
+// of the measurement are passed as the query parameters "type", "from" and
+// "until". Each of [labels] is passed as a query parameter too, so that the
+// profiles of a node can be told apart.
+func NewUploader(endpoint string, labels map[string]string) (Uploader, error) {
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return nil, fmt.Errorf("%w: %q", errInvalidUploadURL, endpoint)
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse profile upload URL: %w", err)
+	}
+	return &httpUploader{
+		endpoint: endpointURL,
+		labels:   labels,
+		client:   &http.Client{Timeout: uploadTimeout},
+	}, nil
+}
+
+type httpUploader struct {
+	endpoint *url.URL
+	labels   map[string]string
+	client   *http.Client
+}
+
+func (u *httpUploader) Upload(profileType string, start, end time.Time, profile []byte) error {
+	uploadURL := *u.endpoint
+	query := uploadURL.Query()
+	query.Set("type", profileType)
+	query.Set("from", strconv.FormatInt(start.Unix(), 10))
+	query.Set("until", strconv.FormatInt(end.Unix(), 10))
+	for name, value := range u.labels {
+		query.Set(name, value)
+	}
+	uploadURL.RawQuery = query.Encode()
+
+	response, err := u.client.Post(uploadURL.String(), "application/octet-stream", bytes.NewReader(profile))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("profile upload endpoint responded with status %s", response.Status)
+	}
+	return nil
+}
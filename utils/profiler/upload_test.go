@@ -0,0 +1,108 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package profiler
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestUploader(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		query url.Values
+		body  []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(http.MethodPost, r.Method)
+		assert.Equal("/ingest", r.URL.Path)
+		query = r.URL.Query()
+		var err error
+		body, err = ioutil.ReadAll(r.Body)
+		assert.NoError(err)
+	}))
+	defer server.Close()
+
+	uploader, err := NewUploader(server.URL+"/ingest", map[string]string{"nodeID": "NodeID-1"})
+	assert.NoError(err)
+
+	start := time.Unix(100, 0)
+	end := time.Unix(200, 0)
+	assert.NoError(uploader.Upload(CPUProfileType, start, end, []byte("profile")))
+	assert.Equal("profile", string(body))
+	assert.Equal(CPUProfileType, query.Get("type"))
+	assert.Equal("100", query.Get("from"))
+	assert.Equal("200", query.Get("until"))
+	assert.Equal("NodeID-1", query.Get("nodeID"))
+}
+
+func TestUploaderErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	uploader, err := NewUploader(server.URL, nil)
+	assert.NoError(t, err)
+	assert.Error(t, uploader.Upload(HeapProfileType, time.Now(), time.Now(), nil))
+}
+
+func TestNewUploaderInvalidURL(t *testing.T) {
+	_, err := NewUploader("/tmp/profiles", nil)
+	assert.ErrorIs(t, err, errInvalidUploadURL)
+}
+
+type uploadTest struct {
+	lock     sync.Mutex
+	profiles map[string]int
+}
+
+func (u *uploadTest) Upload(profileType string, _, _ time.Time, profile []byte) error {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.profiles[profileType] = len(profile)
+	return nil
+}
+
+func (u *uploadTest) numUploaded() int {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	return len(u.profiles)
+}
+
+func TestContinuousUploads(t *testing.T) {
+	assert := assert.New(t)
+
+	uploader := &uploadTest{profiles: make(map[string]int)}
+	p := NewContinuous(logging.NoLog{}, t.TempDir(), 10*time.Millisecond, 2, uploader)
+
+	errs := make(chan error, 1)
+	go func() { errs <- p.Dispatch() }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for uploader.numUploaded() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	p.Shutdown()
+	assert.NoError(<-errs)
+
+	uploader.lock.Lock()
+	defer uploader.lock.Unlock()
+	assert.Contains(uploader.profiles, CPUProfileType)
+	assert.Contains(uploader.profiles, HeapProfileType)
+	assert.Contains(uploader.profiles, LockProfileType)
+	assert.NotZero(uploader.profiles[HeapProfileType])
+}
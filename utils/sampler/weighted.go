@@ -14,6 +14,22 @@ type Weighted interface {
 	Sample(sampleValue uint64) (int, error)
 }
 
+// IncrementalWeighted is a Weighted sampler whose weights can be modified
+// without being reinitialized
+type IncrementalWeighted interface {
+	Weighted
+
+	// Update sets the weight of [index]. If [index] is the number of weights,
+	// [weight] is appended.
+	Update(index int, weight uint64) error
+	// Truncate removes all the weights after the first [length] weights
+	Truncate(length int)
+	// TotalWeight returns the sum of the weights
+	TotalWeight() uint64
+	// Weight returns the weight of [index]
+	Weight(index int) uint64
+}
+
 // NewWeighted returns a new sampler
 func NewWeighted() Weighted {
 	return &weightedBest{
@@ -27,3 +43,6 @@ func NewWeighted() Weighted {
 		benchmarkIterations: 100,
 	}
 }
+
+// NewIncrementalWeighted returns a new sampler
+func NewIncrementalWeighted() IncrementalWeighted { return &weightedFenwick{} }
@@ -0,0 +1,142 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sampler
+
+import (
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// weightedFenwick implements the IncrementalWeighted interface.
+//
+// Sampling is performed by descending a Fenwick tree, in which the element at
+// index i holds the sum of the weights in (i - lowbit(i), i].
+//
+// Initialization takes O(n) time, where n is the number of elements that can be
+// sampled.
+// Updating, appending, and sampling take O(log(n)) time.
+// Truncating k elements takes O(k) time.
+type weightedFenwick struct {
+	// weights[i] is the weight of index i
+	weights []uint64
+	// tree is 1-indexed, tree[0] is unused
+	tree        []uint64
+	totalWeight uint64
+}
+
+func lowbit(i int) int { return i & -i }
+
+func (s *weightedFenwick) Initialize(weights []uint64) error {
+	totalWeight := uint64(0)
+	for _, weight := range weights {
+		newWeight, err := safemath.Add64(totalWeight, weight)
+		if err != nil {
+			return err
+		}
+		totalWeight = newWeight
+	}
+
+	s.weights = append(s.weights[:0], weights...)
+	s.tree = append(s.tree[:0], 0)
+	s.tree = append(s.tree, weights...)
+	for i := 1; i < len(s.tree); i++ {
+		if parent := i + lowbit(i); parent < len(s.tree) {
+			s.tree[parent] += s.tree[i]
+		}
+	}
+	s.totalWeight = totalWeight
+	return nil
+}
+
+func (s *weightedFenwick) Update(index int, weight uint64) error {
+	switch {
+	case index == len(s.weights):
+		return s.append(weight)
+	case index < 0 || index > len(s.weights):
+		return errOutOfRange
+	}
+
+	oldWeight := s.weights[index]
+	if weight >= oldWeight {
+		diff := weight - oldWeight
+		newTotalWeight, err := safemath.Add64(s.totalWeight, diff)
+		if err != nil {
+			return err
+		}
+		s.totalWeight = newTotalWeight
+		for i := index + 1; i < len(s.tree); i += lowbit(i) {
+			s.tree[i] += diff
+		}
+	} else {
+		diff := oldWeight - weight
+		s.totalWeight -= diff
+		for i := index + 1; i < len(s.tree); i += lowbit(i) {
+			s.tree[i] -= diff
+		}
+	}
+	s.weights[index] = weight
+	return nil
+}
+
+func (s *weightedFenwick) append(weight uint64) error {
+	newTotalWeight, err := safemath.Add64(s.totalWeight, weight)
+	if err != nil {
+		return err
+	}
+	s.totalWeight = newTotalWeight
+
+	// The new element covers (i - lowbit(i), i], which is its own weight plus
+	// the weights in (i - lowbit(i), i - 1].
+	i := len(s.tree)
+	s.tree = append(s.tree, weight+s.prefixWeight(i-1)-s.prefixWeight(i-lowbit(i)))
+	s.weights = append(s.weights, weight)
+	return nil
+}
+
+func (s *weightedFenwick) Truncate(length int) {
+	if length < 0 || length >= len(s.weights) {
+		return
+	}
+	for _, weight := range s.weights[length:] {
+		s.totalWeight -= weight
+	}
+	// Removing elements from the end of the tree doesn't change the ranges
+	// covered by the remaining elements.
+	s.weights = s.weights[:length]
+	s.tree = s.tree[:length+1]
+}
+
+func (s *weightedFenwick) TotalWeight() uint64 { return s.totalWeight }
+
+func (s *weightedFenwick) Weight(index int) uint64 { return s.weights[index] }
+
+// prefixWeight returns the sum of the first [length] weights
+func (s *weightedFenwick) prefixWeight(length int) uint64 {
+	weight := uint64(0)
+	for i := length; i > 0; i -= lowbit(i) {
+		weight += s.tree[i]
+	}
+	return weight
+}
+
+func (s *weightedFenwick) Sample(value uint64) (int, error) {
+	if value >= s.totalWeight {
+		return 0, errOutOfRange
+	}
+
+	step := 1
+	for step<<1 < len(s.tree) {
+		step <<= 1
+	}
+
+	// Find the largest index whose prefix weight is at most [value]. The next
+	// index is the one that [value] falls into.
+	index := 0
+	for ; step > 0; step >>= 1 {
+		if next := index + step; next < len(s.tree) && s.tree[next] <= value {
+			index = next
+			value -= s.tree[next]
+		}
+	}
+	return index, nil
+}
@@ -0,0 +1,197 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sampler
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// weightedFenwickDistribution returns how many of the values in
+// [0, totalWeight) are mapped to each index
+func weightedFenwickDistribution(t *testing.T, s *weightedFenwick) []uint64 {
+	counts := make([]uint64, len(s.weights))
+	for i := uint64(0); i < s.TotalWeight(); i++ {
+		index, err := s.Sample(i)
+		assert.NoError(t, err)
+		counts[index]++
+	}
+	return counts
+}
+
+func TestWeightedFenwickUpdate(t *testing.T) {
+	s := &weightedFenwick{}
+	err := s.Initialize([]uint64{1, 1, 2, 3, 4})
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Update(0, 5))
+	assert.NoError(t, s.Update(3, 0))
+	assert.Equal(t, uint64(12), s.TotalWeight())
+	assert.Equal(t, []uint64{5, 1, 2, 0, 4}, weightedFenwickDistribution(t, s))
+
+	err = s.Update(6, 1)
+	assert.Error(t, err, "should have reported an out of range error")
+
+	err = s.Update(1, math.MaxUint64)
+	assert.Error(t, err, "should have reported an overflow error")
+	assert.Equal(t, []uint64{5, 1, 2, 0, 4}, weightedFenwickDistribution(t, s))
+}
+
+func TestWeightedFenwickAppendAndTruncate(t *testing.T) {
+	s := &weightedFenwick{}
+	err := s.Initialize(nil)
+	assert.NoError(t, err)
+
+	weights := []uint64{3, 0, 1, 4, 1, 5, 9, 2, 6}
+	for i, weight := range weights {
+		assert.NoError(t, s.Update(i, weight))
+		assert.Equal(t, weights[:i+1], weightedFenwickDistribution(t, s))
+	}
+
+	for length := len(weights) - 1; length >= 0; length-- {
+		s.Truncate(length)
+		assert.Equal(t, weights[:length], weightedFenwickDistribution(t, s))
+	}
+	assert.Equal(t, uint64(0), s.TotalWeight())
+
+	// Appending after truncating should rebuild the removed elements
+	for i, weight := range weights {
+		assert.NoError(t, s.Update(i, weight))
+	}
+	assert.Equal(t, weights, weightedFenwickDistribution(t, s))
+}
+
+func TestWeightedWithoutReplacementIncrementalUpdate(t *testing.T) {
+	s := NewIncrementalWeightedWithoutReplacement()
+	err := s.Initialize([]uint64{1})
+	assert.NoError(t, err)
+
+	indices, err := s.Sample(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0}, indices)
+
+	assert.NoError(t, s.Update(0, 0))
+	assert.NoError(t, s.Update(1, 2))
+
+	indices, err = s.Sample(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 1}, indices)
+
+	_, err = s.Sample(3)
+	assert.Error(t, err, "should have reported an out of range error")
+}
+
+func TestWeightedWithoutReplacementIncrementalSampleDistinct(t *testing.T) {
+	s := NewIncrementalWeightedWithoutReplacement()
+	err := s.Initialize([]uint64{1, 100, 0, 1})
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		indices, err := s.SampleDistinct(3)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []int{0, 1, 3}, indices)
+	}
+
+	_, err = s.SampleDistinct(4)
+	assert.Error(t, err, "should have reported an out of range error")
+
+	// The sampled weights should have been restored
+	indices, err := s.Sample(102)
+	assert.NoError(t, err)
+	assert.Len(t, indices, 102)
+	_, err = s.Sample(103)
+	assert.Error(t, err, "should have reported an out of range error")
+}
+
+func TestWeightedWithoutReplacementIncrementalSeed(t *testing.T) {
+	s := NewIncrementalWeightedWithoutReplacement()
+	weights := make([]uint64, 100)
+	for i := range weights {
+		weights[i] = uint64(i + 1)
+	}
+	err := s.Initialize(weights)
+	assert.NoError(t, err)
+
+	s.Seed(0)
+	indices, err := s.Sample(20)
+	assert.NoError(t, err)
+	distinctIndices, err := s.SampleDistinct(20)
+	assert.NoError(t, err)
+
+	// Updating a weight back to its original value shouldn't change the sample
+	assert.NoError(t, s.Update(0, 0))
+	assert.NoError(t, s.Update(0, 1))
+
+	sameIndices, err := s.Sample(20)
+	assert.NoError(t, err)
+	assert.Equal(t, indices, sameIndices)
+	sameDistinctIndices, err := s.SampleDistinct(20)
+	assert.NoError(t, err)
+	assert.Equal(t, distinctIndices, sameDistinctIndices)
+
+	s.Seed(1)
+	otherIndices, err := s.Sample(20)
+	assert.NoError(t, err)
+	assert.NotEqual(t, indices, otherIndices)
+	s.ClearSeed()
+}
+
+func TestWeightedWithoutReplacementIncrementalSampleWithGroupLimit(t *testing.T) {
+	s := NewIncrementalWeightedWithoutReplacement()
+	err := s.Initialize([]uint64{100, 100, 1, 1})
+	assert.NoError(t, err)
+
+	// Indices 0 and 1 are in the same group
+	groups := []int{0, 0, 1, -1}
+	for i := 0; i < 10; i++ {
+		indices, err := s.SampleWithGroupLimit(4, groups, 2, false)
+		assert.NoError(t, err)
+
+		numSampled := make(map[int]int)
+		for _, index := range indices {
+			numSampled[groups[index]]++
+		}
+		assert.LessOrEqual(t, numSampled[0], 2)
+		assert.LessOrEqual(t, numSampled[1], 2)
+	}
+
+	_, err = s.SampleWithGroupLimit(4, []int{0, 0, 0, 0}, 1, false)
+	assert.Error(t, err, "should have reported an out of range error")
+
+	// The removed weights should have been restored
+	indices, err := s.Sample(202)
+	assert.NoError(t, err)
+	assert.Len(t, indices, 202)
+}
+
+func TestWeightedWithoutReplacementIncrementalSampleWithGroupLimitDistinct(t *testing.T) {
+	s := NewIncrementalWeightedWithoutReplacement()
+	err := s.Initialize([]uint64{100, 100, 1, 1})
+	assert.NoError(t, err)
+
+	groups := []int{0, 0, 1, -1}
+	for i := 0; i < 10; i++ {
+		indices, err := s.SampleWithGroupLimit(3, groups, 2, true)
+		assert.NoError(t, err)
+		assert.Len(t, indices, 3)
+
+		sampled := make(map[int]bool)
+		for _, index := range indices {
+			assert.False(t, sampled[index], "should have sampled distinct indices")
+			sampled[index] = true
+		}
+	}
+
+	// Only 3 indices can be sampled without sampling 2 of the first group
+	_, err = s.SampleWithGroupLimit(4, groups, 1, true)
+	assert.Error(t, err, "should have reported an out of range error")
+
+	// The removed weights should have been restored, even though indices
+	// were removed both when they were sampled and when their group was full
+	indices, err := s.Sample(202)
+	assert.NoError(t, err)
+	assert.Len(t, indices, 202)
+}
@@ -11,6 +11,33 @@ type WeightedWithoutReplacement interface {
 	Sample(count int) ([]int, error)
 }
 
+// IncrementalWeightedWithoutReplacement is a WeightedWithoutReplacement sampler
+// whose weights can be modified without being reinitialized
+type IncrementalWeightedWithoutReplacement interface {
+	WeightedWithoutReplacement
+
+	// Update sets the weight of [index]. If [index] is the number of weights,
+	// [weight] is appended.
+	Update(index int, weight uint64) error
+	// Truncate removes all the weights after the first [length] weights
+	Truncate(length int)
+	// SampleDistinct samples [count] distinct indices, weighted by their
+	// weights. Returns an error if fewer than [count] indices have a non-zero
+	// weight.
+	SampleDistinct(count int) ([]int, error)
+	// SampleWithGroupLimit samples [count] indices, weighted by their weights,
+	// such that at most [maxPerGroup] of the sampled indices are in the same
+	// group. [groups] maps each index to its group. Indices with a negative
+	// group, or without a group, aren't limited. If [distinct], the sampled
+	// indices are distinct. Returns an error if the limit can't be met.
+	SampleWithGroupLimit(count int, groups []int, maxPerGroup int, distinct bool) ([]int, error)
+
+	// Seed makes every following sample a deterministic function of the
+	// weights and [seed], until ClearSeed is called.
+	Seed(seed int64)
+	ClearSeed()
+}
+
 // NewWeightedWithoutReplacement returns a new sampler
 func NewWeightedWithoutReplacement() WeightedWithoutReplacement {
 	return &weightedWithoutReplacementGeneric{
@@ -28,3 +55,21 @@ func NewBestWeightedWithoutReplacement(
 		w: NewWeighted(),
 	}
 }
+
+// NewIncrementalWeightedWithoutReplacement returns a new sampler
+func NewIncrementalWeightedWithoutReplacement() IncrementalWeightedWithoutReplacement {
+	return &weightedWithoutReplacementIncremental{
+		u: NewUniform(),
+		w: NewIncrementalWeighted(),
+	}
+}
+
+// NewBestIncrementalWeightedWithoutReplacement returns a new sampler
+func NewBestIncrementalWeightedWithoutReplacement(
+	expectedSampleSize int,
+) IncrementalWeightedWithoutReplacement {
+	return &weightedWithoutReplacementIncremental{
+		u: NewBestUniform(expectedSampleSize),
+		w: NewIncrementalWeighted(),
+	}
+}
@@ -0,0 +1,206 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sampler
+
+import (
+	"math"
+)
+
+// weightedWithoutReplacementIncremental implements the
+// IncrementalWeightedWithoutReplacement interface.
+//
+// Sampling is performed the same way as weightedWithoutReplacementGeneric, but
+// the weighted sampler is updated in place rather than reinitialized when the
+// weights change. The uniform sampler is only reinitialized when the total
+// weight has changed since the last sample.
+//
+// When seeded, the uniform sampler is replaced by a uniformReplacer, so that
+// the samples only depend on the weights and the seed.
+type weightedWithoutReplacementIncremental struct {
+	u           Uniform
+	w           IncrementalWeighted
+	uWeight     uint64
+	initialized bool
+
+	seeded    bool
+	seed      int64
+	seededU   Uniform
+	seededRNG rng
+}
+
+func (s *weightedWithoutReplacementIncremental) Initialize(weights []uint64) error {
+	s.initialized = false
+	return s.w.Initialize(weights)
+}
+
+func (s *weightedWithoutReplacementIncremental) Update(index int, weight uint64) error {
+	return s.w.Update(index, weight)
+}
+
+func (s *weightedWithoutReplacementIncremental) Truncate(length int) {
+	s.w.Truncate(length)
+}
+
+func (s *weightedWithoutReplacementIncremental) Sample(count int) ([]int, error) {
+	u, err := s.uniform()
+	if err != nil {
+		return nil, err
+	}
+	u.Reset()
+
+	indices := make([]int, count)
+	for i := 0; i < count; i++ {
+		weight, err := u.Next()
+		if err != nil {
+			return nil, err
+		}
+		indices[i], err = s.w.Sample(weight)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return indices, nil
+}
+
+// uniform returns the uniform sampler to sample the weights with
+func (s *weightedWithoutReplacementIncremental) uniform() (Uniform, error) {
+	totalWeight := s.w.TotalWeight()
+	if s.seeded {
+		if err := s.seededU.Initialize(totalWeight); err != nil {
+			return nil, err
+		}
+		s.seededU.Seed(s.seed)
+		return s.seededU, nil
+	}
+	if !s.initialized || s.uWeight != totalWeight {
+		if err := s.u.Initialize(totalWeight); err != nil {
+			return nil, err
+		}
+		s.uWeight = totalWeight
+		s.initialized = true
+	}
+	return s.u, nil
+}
+
+// SampleDistinct samples one index at a time, removing the weight of each
+// sampled index before sampling the next. The removed weights are restored
+// before returning.
+func (s *weightedWithoutReplacementIncremental) SampleDistinct(count int) ([]int, error) {
+	indices := make([]int, 0, count)
+	weights := make([]uint64, 0, count)
+	defer func() {
+		for i, index := range indices {
+			// Restoring a weight that was previously set can't overflow
+			_ = s.w.Update(index, weights[i])
+		}
+	}()
+
+	rng := s.sampleRNG()
+	for len(indices) < count {
+		totalWeight := s.w.TotalWeight()
+		if totalWeight == 0 || totalWeight > math.MaxInt64 {
+			return nil, errOutOfRange
+		}
+		index, err := s.w.Sample(uint64(rng.Int63n(int64(totalWeight))))
+		if err != nil {
+			return nil, err
+		}
+		indices = append(indices, index)
+		weights = append(weights, s.w.Weight(index))
+		if err := s.w.Update(index, 0); err != nil {
+			return nil, err
+		}
+	}
+	return indices, nil
+}
+
+func (s *weightedWithoutReplacementIncremental) Seed(seed int64) {
+	if s.seededU == nil {
+		s.seededU = &uniformReplacer{}
+		s.seededRNG = newRNG()
+	}
+	s.seeded = true
+	s.seed = seed
+}
+
+func (s *weightedWithoutReplacementIncremental) ClearSeed() {
+	s.seeded = false
+}
+
+// SampleWithGroupLimit samples one index at a time. Once [maxPerGroup] indices
+// of a group have been sampled, the weights of all the indices in the group are
+// removed. If [distinct], the weight of each sampled index is removed too. The
+// removed weights are restored before returning.
+func (s *weightedWithoutReplacementIncremental) SampleWithGroupLimit(
+	count int,
+	groups []int,
+	maxPerGroup int,
+	distinct bool,
+) ([]int, error) {
+	members := make(map[int][]int)
+	for index, group := range groups {
+		if group >= 0 {
+			members[group] = append(members[group], index)
+		}
+	}
+
+	indices := make([]int, 0, count)
+	removedIndices := []int(nil)
+	removedWeights := []uint64(nil)
+	defer func() {
+		// An index may be removed more than once, so the weights are restored
+		// in reverse order
+		for i := len(removedIndices) - 1; i >= 0; i-- {
+			// Restoring a weight that was previously set can't overflow
+			_ = s.w.Update(removedIndices[i], removedWeights[i])
+		}
+	}()
+
+	rng := s.sampleRNG()
+	numSampled := make(map[int]int)
+	for len(indices) < count {
+		totalWeight := s.w.TotalWeight()
+		if totalWeight == 0 || totalWeight > math.MaxInt64 {
+			return nil, errOutOfRange
+		}
+		index, err := s.w.Sample(uint64(rng.Int63n(int64(totalWeight))))
+		if err != nil {
+			return nil, err
+		}
+		indices = append(indices, index)
+
+		if distinct {
+			removedIndices = append(removedIndices, index)
+			removedWeights = append(removedWeights, s.w.Weight(index))
+			if err := s.w.Update(index, 0); err != nil {
+				return nil, err
+			}
+		}
+		if index >= len(groups) || groups[index] < 0 {
+			continue
+		}
+		group := groups[index]
+		numSampled[group]++
+		if numSampled[group] < maxPerGroup {
+			continue
+		}
+		for _, member := range members[group] {
+			removedIndices = append(removedIndices, member)
+			removedWeights = append(removedWeights, s.w.Weight(member))
+			if err := s.w.Update(member, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return indices, nil
+}
+
+// sampleRNG returns the source of randomness used to sample one index at a time
+func (s *weightedWithoutReplacementIncremental) sampleRNG() rng {
+	if !s.seeded {
+		return globalRNG
+	}
+	s.seededRNG.Seed(s.seed)
+	return s.seededRNG
+}
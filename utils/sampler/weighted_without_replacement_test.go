@@ -33,6 +33,13 @@ var (
 				},
 			},
 		},
+		{
+			name: "incremental with replacer and fenwick tree",
+			sampler: &weightedWithoutReplacementIncremental{
+				u: &uniformReplacer{},
+				w: &weightedFenwick{},
+			},
+		},
 	}
 	weightedWithoutReplacementTests = []struct {
 		name string
@@ -21,12 +21,13 @@ import (
 var errNonPositiveHalflife = errors.New("timeout halflife must be positive")
 
 type adaptiveTimeout struct {
-	index    int               // Index in the wait queue
-	id       ids.ID            // Unique ID of this timeout
-	handler  func()            // Function to execute if timed out
-	duration time.Duration     // How long this timeout was set for
-	deadline time.Time         // When this timeout should be fired
-	msgType  constants.MsgType // Type of this outstanding request
+	index       int               // Index in the wait queue
+	id          ids.ID            // Unique ID of this timeout
+	validatorID ids.ShortID       // Validator the request was sent to
+	handler     func()            // Function to execute if timed out
+	duration    time.Duration     // How long this timeout was set for
+	deadline    time.Time         // When this timeout should be fired
+	msgType     constants.MsgType // Type of this outstanding request
 }
 
 // A timeoutQueue implements heap.Interface and holds adaptiveTimeouts.
@@ -87,9 +88,12 @@ type AdaptiveTimeoutManager struct {
 	minimumTimeout     time.Duration
 	maximumTimeout     time.Duration
 	currentTimeout     time.Duration // Amount of time before a timeout
-	timeoutMap         map[ids.ID]*adaptiveTimeout
-	timeoutQueue       timeoutQueue
-	timer              *Timer // Timer that will fire to clear the timeouts
+	// Estimates the response time of each peer we've heard from. Requests to
+	// a peer that has no estimate use [currentTimeout].
+	peerLatencies map[ids.ShortID]*latencyEstimator
+	timeoutMap    map[ids.ID]*adaptiveTimeout
+	timeoutQueue  timeoutQueue
+	timer         *Timer // Timer that will fire to clear the timeouts
 }
 
 // Initialize this timeout manager with the provided config
@@ -126,6 +130,7 @@ func (tm *AdaptiveTimeoutManager) Initialize(config *AdaptiveTimeoutConfig) erro
 	tm.minimumTimeout = config.MinimumTimeout
 	tm.maximumTimeout = config.MaximumTimeout
 	tm.currentTimeout = config.InitialTimeout
+	tm.peerLatencies = make(map[ids.ShortID]*latencyEstimator)
 	tm.timeoutMap = make(map[ids.ID]*adaptiveTimeout)
 	tm.timer = NewTimer(tm.Timeout)
 
@@ -143,32 +148,60 @@ func (tm *AdaptiveTimeoutManager) TimeoutDuration() time.Duration {
 	return tm.currentTimeout
 }
 
+// PeerTimeoutDuration returns the timeout duration for requests sent to
+// [validatorID]
+func (tm *AdaptiveTimeoutManager) PeerTimeoutDuration(validatorID ids.ShortID) time.Duration {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+	return tm.peerTimeout(validatorID)
+}
+
+// Assumes [tm.lock] is held
+func (tm *AdaptiveTimeoutManager) peerTimeout(validatorID ids.ShortID) time.Duration {
+	if estimator, exists := tm.peerLatencies[validatorID]; exists {
+		return estimator.Timeout(tm.currentTimeout, tm.minimumTimeout, tm.maximumTimeout)
+	}
+	return tm.currentTimeout
+}
+
+// RemovePeer forgets the estimated response time of [validatorID], such as
+// when it disconnects. Requests to it use the current network timeout until
+// its response time is estimated again.
+func (tm *AdaptiveTimeoutManager) RemovePeer(validatorID ids.ShortID) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+	delete(tm.peerLatencies, validatorID)
+}
+
 // Dispatch ...
 func (tm *AdaptiveTimeoutManager) Dispatch() { tm.timer.Dispatch() }
 
 // Stop executing timeouts
 func (tm *AdaptiveTimeoutManager) Stop() { tm.timer.Stop() }
 
-// Put registers a timeout for [id]. If the timeout occurs, [timeoutHandler] is called.
+// Put registers a timeout for [id], which is a request sent to [validatorID].
+// If the timeout occurs, [timeoutHandler] is called.
 // Returns the time at which the timeout will fire if it is not first
 // removed by calling [tm.Remove].
-func (tm *AdaptiveTimeoutManager) Put(id ids.ID, msgType constants.MsgType, timeoutHandler func()) time.Time {
+func (tm *AdaptiveTimeoutManager) Put(id ids.ID, validatorID ids.ShortID, msgType constants.MsgType, timeoutHandler func()) time.Time {
 	tm.lock.Lock()
 	defer tm.lock.Unlock()
-	return tm.put(id, msgType, timeoutHandler)
+	return tm.put(id, validatorID, msgType, timeoutHandler)
 }
 
 // Assumes [tm.lock] is held
-func (tm *AdaptiveTimeoutManager) put(id ids.ID, msgType constants.MsgType, handler func()) time.Time {
+func (tm *AdaptiveTimeoutManager) put(id ids.ID, validatorID ids.ShortID, msgType constants.MsgType, handler func()) time.Time {
 	currentTime := tm.clock.Time()
-	tm.remove(id, currentTime)
+	tm.remove(id, currentTime, false)
 
+	duration := tm.peerTimeout(validatorID)
 	timeout := &adaptiveTimeout{
-		id:       id,
-		handler:  handler,
-		duration: tm.currentTimeout,
-		deadline: currentTime.Add(tm.currentTimeout),
-		msgType:  msgType,
+		id:          id,
+		validatorID: validatorID,
+		handler:     handler,
+		duration:    duration,
+		deadline:    currentTime.Add(duration),
+		msgType:     msgType,
 	}
 	tm.timeoutMap[id] = timeout
 	heap.Push(&tm.timeoutQueue, timeout)
@@ -182,11 +215,13 @@ func (tm *AdaptiveTimeoutManager) put(id ids.ID, msgType constants.MsgType, hand
 func (tm *AdaptiveTimeoutManager) Remove(id ids.ID) {
 	tm.lock.Lock()
 	defer tm.lock.Unlock()
-	tm.remove(id, tm.clock.Time())
+	tm.remove(id, tm.clock.Time(), false)
 }
 
+// Remove the timeout associated with [id]. [timedOut] is true if it's being
+// removed because it fired.
 // Assumes [tm.lock] is held
-func (tm *AdaptiveTimeoutManager) remove(id ids.ID, now time.Time) {
+func (tm *AdaptiveTimeoutManager) remove(id ids.ID, now time.Time, timedOut bool) {
 	timeout, exists := tm.timeoutMap[id]
 	if !exists {
 		return
@@ -196,11 +231,20 @@ func (tm *AdaptiveTimeoutManager) remove(id ids.ID, now time.Time) {
 	// Don't include Get requests in calculation, since an adversary
 	// can cause you to issue a Get request and then cause it to timeout,
 	// increasing your timeout.
+	timeoutRegisteredAt := timeout.deadline.Add(-1 * timeout.duration)
+	latency := now.Sub(timeoutRegisteredAt)
 	if timeout.msgType != constants.GetMsg {
-		timeoutRegisteredAt := timeout.deadline.Add(-1 * timeout.duration)
-		latency := now.Sub(timeoutRegisteredAt)
 		tm.observeLatencyAndUpdateTimeout(latency, now)
 	}
+	// A peer can only lengthen its own timeout, so all of its requests are
+	// included in its estimate. A timeout backs the estimate off rather than
+	// being observed as a latency.
+	estimator := tm.peerEstimator(timeout.validatorID)
+	if timedOut {
+		estimator.TimedOut()
+	} else {
+		estimator.Observe(latency)
+	}
 
 	// Remove the timeout from the map
 	delete(tm.timeoutMap, id)
@@ -261,6 +305,17 @@ func (tm *AdaptiveTimeoutManager) observeLatencyAndUpdateTimeout(latency time.Du
 	tm.avgLatency.Set(avgLatency)
 }
 
+// Returns the estimate of [validatorID]'s response time, creating it if needed
+// Assumes [tm.lock] is held
+func (tm *AdaptiveTimeoutManager) peerEstimator(validatorID ids.ShortID) *latencyEstimator {
+	estimator, exists := tm.peerLatencies[validatorID]
+	if !exists {
+		estimator = &latencyEstimator{}
+		tm.peerLatencies[validatorID] = estimator
+	}
+	return estimator
+}
+
 // Returns the handler function associated with the next timeout.
 // If there are no timeouts, or if the next timeout is after [currentTime],
 // returns nil.
@@ -274,7 +329,7 @@ func (tm *AdaptiveTimeoutManager) getNextTimeoutHandler(currentTime time.Time) f
 	if nextTimeout.deadline.After(currentTime) {
 		return nil
 	}
-	tm.remove(nextTimeout.id, currentTime)
+	tm.remove(nextTimeout.id, currentTime, true)
 	return nextTimeout.handler
 }
 
@@ -135,6 +135,7 @@ func TestAdaptiveTimeoutManager(t *testing.T) {
 	timeoutZeroCalled := utils.AtomicBool{}
 	tm.Put(
 		id0,
+		ids.ShortEmpty,
 		constants.PullQueryMsg,
 		func() { timeoutZeroCalled.SetValue(true) },
 	)
@@ -172,6 +173,7 @@ func TestAdaptiveTimeoutManager(t *testing.T) {
 	// This should overwrite the first Put for id0
 	tm.Put(
 		id0,
+		ids.ShortEmpty,
 		constants.PullQueryMsg,
 		func() { wg.Done() },
 	)
@@ -210,11 +212,13 @@ func TestAdaptiveTimeoutManager(t *testing.T) {
 	wg.Add(2)
 	tm.Put(
 		id1,
+		ids.ShortEmpty,
 		constants.PullQueryMsg,
 		func() { wg.Done() },
 	)
 	tm.Put(
 		id2,
+		ids.ShortEmpty,
 		constants.PullQueryMsg,
 		func() { wg.Done() },
 	)
@@ -274,14 +278,14 @@ func TestAdaptiveTimeoutManager2(t *testing.T) {
 
 		numSuccessful--
 		if numSuccessful > 0 {
-			tm.Put(ids.ID{byte(numSuccessful)}, constants.PullQueryMsg, *callback)
+			tm.Put(ids.ID{byte(numSuccessful)}, ids.ShortEmpty, constants.PullQueryMsg, *callback)
 		}
 		if numSuccessful >= 0 {
 			wg.Done()
 		}
 		if numSuccessful%2 == 0 {
 			tm.Remove(ids.ID{byte(numSuccessful)})
-			tm.Put(ids.ID{byte(numSuccessful)}, constants.PullQueryMsg, *callback)
+			tm.Put(ids.ID{byte(numSuccessful)}, ids.ShortEmpty, constants.PullQueryMsg, *callback)
 		}
 	}
 	(*callback)()
@@ -289,3 +293,85 @@ func TestAdaptiveTimeoutManager2(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestAdaptiveTimeoutManagerPeerTimeouts(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	config := &AdaptiveTimeoutConfig{
+		InitialTimeout:     time.Second,
+		MinimumTimeout:     10 * time.Millisecond,
+		MaximumTimeout:     10 * time.Second,
+		TimeoutHalflife:    5 * time.Minute,
+		TimeoutCoefficient: 1.25,
+		MetricsNamespace:   constants.PlatformName,
+		Registerer:         prometheus.NewRegistry(),
+	}
+	err := tm.Initialize(config)
+	assert.NoError(t, err)
+
+	fastVdr := ids.GenerateTestShortID()
+	slowVdr := ids.GenerateTestShortID()
+
+	// Peers we haven't heard from use the network timeout
+	assert.Equal(t, config.InitialTimeout, tm.PeerTimeoutDuration(fastVdr))
+
+	start := time.Now()
+	tm.clock.Set(start)
+	for i := 0; i < 10; i++ {
+		fastID := ids.GenerateTestID()
+		slowID := ids.GenerateTestID()
+		tm.Put(fastID, fastVdr, constants.PullQueryMsg, func() {})
+		tm.Put(slowID, slowVdr, constants.PullQueryMsg, func() {})
+
+		now := start.Add(20 * time.Millisecond)
+		tm.clock.Set(now)
+		tm.Remove(fastID)
+
+		now = now.Add(500 * time.Millisecond)
+		tm.clock.Set(now)
+		tm.Remove(slowID)
+
+		start = now
+		tm.clock.Set(start)
+	}
+
+	fastTimeout := tm.PeerTimeoutDuration(fastVdr)
+	slowTimeout := tm.PeerTimeoutDuration(slowVdr)
+	assert.Less(t, int64(fastTimeout), int64(100*time.Millisecond))
+	assert.Greater(t, int64(slowTimeout), int64(500*time.Millisecond))
+
+	// Requests are registered with the timeout of the peer they were sent to
+	id := ids.GenerateTestID()
+	deadline := tm.Put(id, fastVdr, constants.PullQueryMsg, func() {})
+	assert.Equal(t, start.Add(fastTimeout), deadline)
+}
+
+func TestAdaptiveTimeoutManagerPeerBackoff(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	config := &AdaptiveTimeoutConfig{
+		InitialTimeout:     time.Second,
+		MinimumTimeout:     10 * time.Millisecond,
+		MaximumTimeout:     10 * time.Second,
+		TimeoutHalflife:    5 * time.Minute,
+		TimeoutCoefficient: 1.25,
+		MetricsNamespace:   constants.PlatformName,
+		Registerer:         prometheus.NewRegistry(),
+	}
+	err := tm.Initialize(config)
+	assert.NoError(t, err)
+
+	vdr := ids.GenerateTestShortID()
+	now := time.Now()
+	tm.clock.Set(now)
+
+	// A request that times out doubles the peer's timeout
+	timedOut := false
+	deadline := tm.Put(ids.GenerateTestID(), vdr, constants.GetMsg, func() { timedOut = true })
+	tm.clock.Set(deadline)
+	tm.Timeout()
+	assert.True(t, timedOut)
+	assert.Equal(t, 2*tm.TimeoutDuration(), tm.PeerTimeoutDuration(vdr))
+
+	// A disconnected peer's estimate is forgotten
+	tm.RemovePeer(vdr)
+	assert.Equal(t, tm.TimeoutDuration(), tm.PeerTimeoutDuration(vdr))
+}
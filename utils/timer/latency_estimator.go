@@ -0,0 +1,82 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Weight given to a new observation in the average latency
+	latencyAverageWeight = 1. / 8
+	// Weight given to a new observation in the latency deviation
+	latencyDeviationWeight = 1. / 4
+	// Number of deviations above the average latency a timeout is set to
+	latencyDeviations = 4
+	// Maximum factor that consecutive timeouts multiply a timeout by
+	maxLatencyBackoff = 64
+)
+
+// latencyEstimator estimates a timeout for requests to a single peer from its
+// observed response latencies. Like TCP's retransmission timer, it keeps an
+// exponentially weighted moving average of the latency along with the average
+// deviation from it, so that peers with consistent latencies get tight timeouts
+// and peers with erratic latencies get lenient ones. Each consecutive timeout
+// doubles the timeout until a response is observed again.
+type latencyEstimator struct {
+	observed  bool
+	average   float64
+	deviation float64
+	// Factor that the timeout is multiplied by, because of consecutive
+	// timeouts
+	backoff float64
+}
+
+// Observe a response latency.
+func (e *latencyEstimator) Observe(latency time.Duration) {
+	e.backoff = 1
+	l := float64(latency)
+	if !e.observed {
+		e.observed = true
+		e.average = l
+		e.deviation = l / 2
+		return
+	}
+	e.deviation = (1-latencyDeviationWeight)*e.deviation + latencyDeviationWeight*math.Abs(e.average-l)
+	e.average = (1-latencyAverageWeight)*e.average + latencyAverageWeight*l
+}
+
+// TimedOut records that a request timed out, which backs the timeout off.
+// Latencies can't be observed from requests that timed out, so without backing
+// off, a peer whose latency grew beyond its timeout would never get a longer
+// one.
+func (e *latencyEstimator) TimedOut() {
+	if e.backoff < 1 {
+		e.backoff = 1
+	}
+	if e.backoff < maxLatencyBackoff {
+		e.backoff *= 2
+	}
+}
+
+// Timeout returns the estimated timeout, bounded by [minimum] and [maximum].
+// If no latency has been observed yet, the estimate is [fallback].
+func (e *latencyEstimator) Timeout(fallback, minimum, maximum time.Duration) time.Duration {
+	estimate := float64(fallback)
+	if e.observed {
+		estimate = e.average + latencyDeviations*e.deviation
+	}
+	if e.backoff > 1 {
+		estimate *= e.backoff
+	}
+	timeout := time.Duration(estimate)
+	switch {
+	case timeout > maximum:
+		timeout = maximum
+	case timeout < minimum:
+		timeout = minimum
+	}
+	return timeout
+}
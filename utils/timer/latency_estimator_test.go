@@ -0,0 +1,71 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyEstimator(t *testing.T) {
+	e := latencyEstimator{}
+
+	// Without observations, the fallback is used
+	timeout := e.Timeout(2*time.Millisecond, time.Millisecond, time.Second)
+	assert.Equal(t, 2*time.Millisecond, timeout)
+
+	e.Observe(100 * time.Millisecond)
+	timeout = e.Timeout(2*time.Millisecond, time.Millisecond, time.Second)
+	assert.Equal(t, 300*time.Millisecond, timeout)
+
+	// Consistent latencies shrink the deviation, and with it the timeout
+	for i := 0; i < 100; i++ {
+		e.Observe(100 * time.Millisecond)
+	}
+	timeout = e.Timeout(2*time.Millisecond, time.Millisecond, time.Second)
+	assert.InDelta(t, float64(100*time.Millisecond), float64(timeout), float64(time.Millisecond))
+
+	// A spike increases the timeout
+	e.Observe(500 * time.Millisecond)
+	spikeTimeout := e.Timeout(2*time.Millisecond, time.Millisecond, time.Second)
+	assert.Greater(t, int64(spikeTimeout), int64(timeout))
+}
+
+func TestLatencyEstimatorBackoff(t *testing.T) {
+	e := latencyEstimator{}
+
+	// Timeouts back off the fallback too
+	e.TimedOut()
+	assert.Equal(t, 20*time.Millisecond, e.Timeout(10*time.Millisecond, time.Millisecond, time.Hour))
+
+	e.Observe(100 * time.Millisecond)
+	timeout := e.Timeout(10*time.Millisecond, time.Millisecond, time.Hour)
+	assert.Equal(t, 300*time.Millisecond, timeout)
+
+	// Each consecutive timeout doubles the timeout, up to a limit
+	e.TimedOut()
+	assert.Equal(t, 2*timeout, e.Timeout(10*time.Millisecond, time.Millisecond, time.Hour))
+	e.TimedOut()
+	assert.Equal(t, 4*timeout, e.Timeout(10*time.Millisecond, time.Millisecond, time.Hour))
+	for i := 0; i < 100; i++ {
+		e.TimedOut()
+	}
+	assert.Equal(t, maxLatencyBackoff*timeout, e.Timeout(10*time.Millisecond, time.Millisecond, time.Hour))
+
+	// A response resets the backoff
+	e.Observe(100 * time.Millisecond)
+	assert.Less(t, int64(e.Timeout(10*time.Millisecond, time.Millisecond, time.Hour)), int64(2*timeout))
+}
+
+func TestLatencyEstimatorBounds(t *testing.T) {
+	e := latencyEstimator{}
+
+	e.Observe(time.Millisecond)
+	assert.Equal(t, time.Second, e.Timeout(time.Second, time.Second, 2*time.Second))
+
+	e.Observe(time.Hour)
+	assert.Equal(t, 2*time.Second, e.Timeout(time.Second, time.Second, 2*time.Second))
+}
@@ -176,6 +176,24 @@ func (p *Packer) UnpackLong() uint64 {
 	return val
 }
 
+// PackLongs append a long slice to the byte array
+func (p *Packer) PackLongs(vals []uint64) {
+	p.PackInt(uint32(len(vals)))
+	for _, val := range vals {
+		p.PackLong(val)
+	}
+}
+
+// UnpackLongs unpacks a long slice from the byte array
+func (p *Packer) UnpackLongs() []uint64 {
+	sliceSize := p.UnpackInt()
+	vals := []uint64(nil)
+	for i := uint32(0); i < sliceSize && !p.Errored(); i++ {
+		vals = append(vals, p.UnpackLong())
+	}
+	return vals
+}
+
 // PackBool packs a bool into the byte array
 func (p *Packer) PackBool(b bool) {
 	if b {
@@ -380,6 +398,20 @@ func TryUnpackLong(packer *Packer) interface{} {
 	return packer.UnpackLong()
 }
 
+// TryPackLongs attempts to pack the value as a list of longs
+func TryPackLongs(packer *Packer, valIntf interface{}) {
+	if val, ok := valIntf.([]uint64); ok {
+		packer.PackLongs(val)
+	} else {
+		packer.Add(errBadType)
+	}
+}
+
+// TryUnpackLongs attempts to unpack the value as a list of longs
+func TryUnpackLongs(packer *Packer) interface{} {
+	return packer.UnpackLongs()
+}
+
 // TryPackHash attempts to pack the value as a 32-byte sequence
 func TryPackHash(packer *Packer, valIntf interface{}) {
 	if val, ok := valIntf.([]byte); ok {
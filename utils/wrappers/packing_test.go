@@ -590,6 +590,32 @@ func TestPacker2DByteSlice(t *testing.T) {
 	}
 }
 
+func TestPackerLongs(t *testing.T) {
+	p := Packer{MaxSize: 1024}
+	vals := []uint64{0, 1, 0x0102030405060708}
+	p.PackLongs(vals)
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+	if size := len(p.Bytes); size != IntLen+3*LongLen {
+		t.Fatalf("Packer.PackLongs wrote %d byte(s) but expected %d byte(s)", size, IntLen+3*LongLen)
+	}
+
+	p = Packer{Bytes: p.Bytes}
+	unpacked := p.UnpackLongs()
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+	assert.Equal(t, vals, unpacked)
+
+	// The slice claims to have more longs than there are bytes
+	p = Packer{Bytes: []byte{0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 1}}
+	p.UnpackLongs()
+	if !p.Errored() {
+		t.Fatal("Packer.UnpackLongs should have set error, due to attempted out of bounds read")
+	}
+}
+
 func TestPackX509Certificate(t *testing.T) {
 	cert, err := staking.NewTLSCert()
 	assert.NoError(t, err)
@@ -66,5 +66,7 @@ func (t *BaseTx) SemanticVerify(vm *VM, tx UnsignedTx, creds []verify.Verifiable
 	return nil
 }
 
-// ExecuteWithSideEffects writes the batch with any additional side effects
-func (t *BaseTx) ExecuteWithSideEffects(_ *VM, batch database.Batch) error { return batch.Write() }
+// ExecuteWithSideEffects writes the batch with any additional side effects.
+// A BaseTx has no side effects, so the batch isn't written here; it's written
+// with the vertex the tx is accepted in.
+func (t *BaseTx) ExecuteWithSideEffects(*VM, database.Batch) error { return nil }
@@ -51,6 +51,16 @@ func (c *Client) GetTxStatus(txID ids.ID) (choices.Status, error) {
 	return res.Status, err
 }
 
+// GetTxStatusDetailed returns the status of [txID] along with how and when it
+// was decided
+func (c *Client) GetTxStatusDetailed(txID ids.ID) (*GetTxStatusDetailedReply, error) {
+	res := &GetTxStatusDetailedReply{}
+	err := c.requester.SendRequest("getTxStatusDetailed", &api.JSONTxID{
+		TxID: txID,
+	}, res)
+	return res, err
+}
+
 // ConfirmTx attempts to confirm [txID] by checking its status [attempts] times
 // with a [delay] in between each attempt. If the transaction has not been decided
 // by the final attempt, it returns the status of the last attempt.
@@ -124,6 +134,19 @@ func (c *Client) GetAtomicUTXOs(addrs []string, sourceChain string, limit uint32
 	return utxos, res.EndIndex, nil
 }
 
+// GetPendingTransfers returns the cross-chain transfers of [addrs] that haven't
+// completed. The UTXOs exported to this chain are returned from
+// [sourceChains].
+func (c *Client) GetPendingTransfers(addrs []string, sourceChains []string) (*api.GetPendingTransfersReply, error) {
+	res := &api.GetPendingTransfersReply{}
+	err := c.requester.SendRequest("getPendingTransfers", &api.GetPendingTransfersArgs{
+		Addresses:    addrs,
+		SourceChains: sourceChains,
+		Encoding:     formatting.Hex,
+	}, res)
+	return res, err
+}
+
 // GetAssetDescription returns a description of [assetID]
 func (c *Client) GetAssetDescription(assetID string) (*GetAssetDescriptionReply, error) {
 	res := &GetAssetDescriptionReply{}
@@ -289,6 +312,19 @@ func (c *Client) ImportKey(user api.UserPass, privateKey string) (string, error)
 	return res.Address, err
 }
 
+// ImportMnemonicKey imports the key at [addressIndex] derived from the BIP-39
+// [mnemonic], which is protected by [passphrase], to [user]
+func (c *Client) ImportMnemonicKey(user api.UserPass, mnemonic, passphrase string, addressIndex uint32) (string, error) {
+	res := &api.JSONAddress{}
+	err := c.requester.SendRequest("importKey", &ImportKeyArgs{
+		UserPass:           user,
+		Mnemonic:           mnemonic,
+		MnemonicPassphrase: passphrase,
+		AddressIndex:       cjson.Uint32(addressIndex),
+	}, res)
+	return res.Address, err
+}
+
 // Send [amount] of [assetID] to address [to]
 func (c *Client) Send(
 	user api.UserPass,
@@ -337,6 +373,53 @@ func (c *Client) SendMultiple(
 	return res.TxID, err
 }
 
+// BuildMultisigSend returns an unsigned transaction that sends [outputs] using
+// UTXOs of [from] that a threshold of [signers] can spend
+func (c *Client) BuildMultisigSend(
+	from []string,
+	signers []string,
+	changeAddr string,
+	outputs []SendOutput,
+	memo string,
+	encoding formatting.Encoding,
+) (*MultisigTxReply, error) {
+	res := &MultisigTxReply{}
+	err := c.requester.SendRequest("buildMultisigSend", &BuildMultisigSendArgs{
+		JSONFromAddrs:  api.JSONFromAddrs{From: from},
+		Signers:        signers,
+		JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		Outputs:        outputs,
+		Memo:           memo,
+		Encoding:       encoding,
+	}, res)
+	return res, err
+}
+
+// SignMultisigTx adds the signatures of [user]'s keys to the partially signed
+// transaction [tx]
+func (c *Client) SignMultisigTx(user api.UserPass, tx string, encoding formatting.Encoding) (*MultisigTxReply, error) {
+	res := &MultisigTxReply{}
+	err := c.requester.SendRequest("signMultisigTx", &SignMultisigTxArgs{
+		UserPass: user,
+		FormattedTx: api.FormattedTx{
+			Tx:       tx,
+			Encoding: encoding,
+		},
+	}, res)
+	return res, err
+}
+
+// CombineMultisigTxs returns the transaction with the signatures of all of the
+// partially signed copies [txs] of it
+func (c *Client) CombineMultisigTxs(txs []string, encoding formatting.Encoding) (*MultisigTxReply, error) {
+	res := &MultisigTxReply{}
+	err := c.requester.SendRequest("combineMultisigTxs", &CombineMultisigTxsArgs{
+		Txs:      txs,
+		Encoding: encoding,
+	}, res)
+	return res, err
+}
+
 // Mint [amount] of [assetID] to be owned by [to]
 func (c *Client) Mint(
 	user api.UserPass,
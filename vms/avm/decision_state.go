@@ -0,0 +1,77 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+)
+
+var _ DecisionState = &decisionState{}
+
+// TxDecision describes how and when a transaction was decided
+type TxDecision struct {
+	Status choices.Status `serialize:"true"`
+
+	// Unix time, in seconds, that the transaction was decided at
+	Timestamp uint64 `serialize:"true"`
+
+	// Vertex that the transaction was accepted in, and the epoch of that
+	// vertex. Empty if the transaction was rejected, or if the vertex hasn't
+	// been accepted yet.
+	VertexID ids.ID `serialize:"true"`
+	Epoch    uint32 `serialize:"true"`
+
+	// Accepted transaction that conflicted with this rejected transaction
+	ConflictID ids.ID `serialize:"true"`
+
+	// Rejected transaction that this rejected transaction depended on
+	DependencyID ids.ID `serialize:"true"`
+}
+
+// DecisionState is a thin wrapper around a database to provide serialization
+// and de-serialization of transaction decisions.
+type DecisionState interface {
+	// GetTxDecision attempts to load the decision of a transaction from
+	// storage.
+	GetTxDecision(txID ids.ID) (*TxDecision, error)
+
+	// PutTxDecision saves the provided decision to storage.
+	PutTxDecision(txID ids.ID, decision *TxDecision) error
+}
+
+type decisionState struct {
+	codec      codec.Manager
+	decisionDB database.Database
+}
+
+func NewDecisionState(db database.Database, codec codec.Manager) DecisionState {
+	return &decisionState{
+		codec:      codec,
+		decisionDB: db,
+	}
+}
+
+func (s *decisionState) GetTxDecision(txID ids.ID) (*TxDecision, error) {
+	decisionBytes, err := s.decisionDB.Get(txID[:])
+	if err != nil {
+		return nil, err
+	}
+
+	decision := &TxDecision{}
+	if _, err := s.codec.Unmarshal(decisionBytes, decision); err != nil {
+		return nil, err
+	}
+	return decision, nil
+}
+
+func (s *decisionState) PutTxDecision(txID ids.ID, decision *TxDecision) error {
+	decisionBytes, err := s.codec.Marshal(codecVersion, decision)
+	if err != nil {
+		return err
+	}
+	return s.decisionDB.Put(txID[:], decisionBytes)
+}
@@ -122,5 +122,9 @@ func (t *ExportTx) ExecuteWithSideEffects(vm *VM, batch database.Batch) error {
 		elems[i] = elem
 	}
 
-	return vm.ctx.SharedMemory.Put(t.DestinationChain, elems, batch)
+	return vm.atomicQueue.Put(&atomic.Operation{
+		TxID:        txID,
+		PeerChainID: t.DestinationChain,
+		Elems:       elems,
+	}, batch)
 }
@@ -5,11 +5,14 @@ package avm
 
 import (
 	"bytes"
+	"errors"
 	"math"
 	"testing"
 
+	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/api/keystore"
 	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
@@ -1303,6 +1306,182 @@ func TestIssueExportTx(t *testing.T) {
 	}
 }
 
+var errSharedMemoryUnavailable = errors.New("shared memory is unavailable")
+
+// unavailableSharedMemory fails to put elements while [unavailable] is set
+type unavailableSharedMemory struct {
+	atomic.SharedMemory
+	unavailable bool
+}
+
+func (sm *unavailableSharedMemory) Put(peerChainID ids.ID, elems []*atomic.Element, batches ...database.Batch) error {
+	if sm.unavailable {
+		return errSharedMemoryUnavailable
+	}
+	return sm.SharedMemory.Put(peerChainID, elems, batches...)
+}
+
+// Test that an export is retried if shared memory is unavailable when it's
+// accepted
+func TestIssueExportTxSharedMemoryUnavailable(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	issuer := make(chan common.Message, 1)
+	baseDBManager := manager.NewDefaultMemDBManager()
+
+	m := &atomic.Memory{}
+	err := m.Initialize(logging.NoLog{}, prefixdb.New([]byte{0}, baseDBManager.Current().Database))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sm := &unavailableSharedMemory{
+		SharedMemory: m.NewSharedMemory(chainID),
+		unavailable:  true,
+	}
+	ctx := NewContext(t)
+	ctx.SharedMemory = sm
+
+	genesisTx := GetAVAXTxFromGenesisTest(genesisBytes, t)
+
+	avaxID := genesisTx.ID()
+
+	ctx.Lock.Lock()
+	vm := &VM{}
+	if err := vm.Initialize(
+		ctx,
+		baseDBManager.NewPrefixDBManager([]byte{1}),
+		genesisBytes,
+		nil,
+		nil,
+		issuer,
+		[]*common.Fx{{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+
+	if err := vm.Bootstrapping(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := vm.Bootstrapped(); err != nil {
+		t.Fatal(err)
+	}
+
+	key := keys[0]
+	addr := key.PublicKey().Address()
+
+	tx := &Tx{UnsignedTx: &ExportTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    networkID,
+			BlockchainID: chainID,
+			Ins: []*avax.TransferableInput{{
+				UTXOID: avax.UTXOID{
+					TxID:        avaxID,
+					OutputIndex: 2,
+				},
+				Asset: avax.Asset{ID: avaxID},
+				In: &secp256k1fx.TransferInput{
+					Amt:   startBalance,
+					Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+				},
+			}},
+		}},
+		DestinationChain: platformChainID,
+		ExportedOuts: []*avax.TransferableOutput{{
+			Asset: avax.Asset{ID: avaxID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: startBalance - vm.txFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{addr},
+				},
+			},
+		}},
+	}}
+	if err := tx.SignSECP256K1Fx(vm.codec, [][]*crypto.PrivateKeySECP256K1R{{key}}); err != nil {
+		t.Fatal(err)
+	}
+
+	parsedTx, err := vm.ParseTx(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parsedTx.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := parsedTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	peerSharedMemory := m.NewSharedMemory(platformChainID)
+	exportedUTXOs := func() int {
+		utxoBytes, _, _, err := peerSharedMemory.Indexed(vm.ctx.ChainID, [][]byte{addr.Bytes()}, nil, nil, math.MaxInt32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(utxoBytes)
+	}
+	if numUTXOs := exportedUTXOs(); numUTXOs != 0 {
+		t.Fatalf("expected no exported utxos but got %d", numUTXOs)
+	}
+
+	addrStr, err := vm.FormatLocalAddress(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Service{vm: vm}
+	pendingArgs := &api.GetPendingTransfersArgs{Addresses: []string{addrStr}}
+	pendingReply := &api.GetPendingTransfersReply{}
+	if err := s.GetPendingTransfers(nil, pendingArgs, pendingReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(pendingReply.Exports) != 1 {
+		t.Fatalf("expected 1 pending export but got %d", len(pendingReply.Exports))
+	}
+	if export := pendingReply.Exports[0]; export.TxID != tx.ID() || export.Status != atomic.Queued.String() || export.Attempts != 1 || export.LastError != errSharedMemoryUnavailable.Error() {
+		t.Fatalf("wrong pending export %+v", export)
+	}
+
+	statusReply := &GetTxStatusDetailedReply{}
+	if err := s.GetTxStatusDetailed(nil, &api.JSONTxID{TxID: tx.ID()}, statusReply); err != nil {
+		t.Fatal(err)
+	}
+	if statusReply.ExportStatus == nil || *statusReply.ExportStatus != atomic.Queued.String() {
+		t.Fatalf("expected export status %s but got %v", atomic.Queued, statusReply.ExportStatus)
+	}
+
+	sm.unavailable = false
+	vm.retryAtomicOperations()
+
+	if numUTXOs := exportedUTXOs(); numUTXOs != 1 {
+		t.Fatalf("expected 1 exported utxo but got %d", numUTXOs)
+	}
+	pendingReply = &api.GetPendingTransfersReply{}
+	if err := s.GetPendingTransfers(nil, pendingArgs, pendingReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(pendingReply.Exports) != 0 {
+		t.Fatalf("expected no pending exports but got %d", len(pendingReply.Exports))
+	}
+	statusReply = &GetTxStatusDetailedReply{}
+	if err := s.GetTxStatusDetailed(nil, &api.JSONTxID{TxID: tx.ID()}, statusReply); err != nil {
+		t.Fatal(err)
+	}
+	if statusReply.ExportStatus == nil || *statusReply.ExportStatus != atomic.Applied.String() {
+		t.Fatalf("expected export status %s but got %v", atomic.Applied, statusReply.ExportStatus)
+	}
+}
+
 // Test force accepting an import transaction.
 func TestClearForceAcceptedExportTx(t *testing.T) {
 	genesisBytes := BuildGenesisTest(t)
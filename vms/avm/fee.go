@@ -0,0 +1,81 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var errProducedMoreThanConsumed = errors.New("transaction produces more of the fee asset than it consumes")
+
+// MinFee returns the smallest fee that a transaction must pay to be accepted.
+// Transactions that create assets must pay the larger creation fee.
+func (vm *VM) MinFee() uint64 { return vm.txFee }
+
+// ContainerFee returns the amount of the fee asset that is burned by the
+// transaction [txBytes]
+func (vm *VM) ContainerFee(txBytes []byte) (uint64, error) {
+	tx, err := vm.parsePrivateTx(txBytes)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't parse transaction: %w", err)
+	}
+
+	var (
+		ins  [][]*avax.TransferableInput
+		outs [][]*avax.TransferableOutput
+	)
+	switch utx := tx.UnsignedTx.(type) {
+	case *BaseTx:
+		ins = [][]*avax.TransferableInput{utx.Ins}
+		outs = [][]*avax.TransferableOutput{utx.Outs}
+	case *CreateAssetTx:
+		ins = [][]*avax.TransferableInput{utx.Ins}
+		outs = [][]*avax.TransferableOutput{utx.Outs}
+	case *OperationTx:
+		ins = [][]*avax.TransferableInput{utx.Ins}
+		outs = [][]*avax.TransferableOutput{utx.Outs}
+	case *ImportTx:
+		ins = [][]*avax.TransferableInput{utx.Ins, utx.ImportedIns}
+		outs = [][]*avax.TransferableOutput{utx.Outs}
+	case *ExportTx:
+		ins = [][]*avax.TransferableInput{utx.Ins}
+		outs = [][]*avax.TransferableOutput{utx.Outs, utx.ExportedOuts}
+	default:
+		return 0, fmt.Errorf("unknown transaction type %T", utx)
+	}
+
+	consumed := uint64(0)
+	for _, inputs := range ins {
+		for _, in := range inputs {
+			if in.AssetID() != vm.feeAssetID {
+				continue
+			}
+			consumed, err = safemath.Add64(consumed, in.In.Amount())
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	produced := uint64(0)
+	for _, outputs := range outs {
+		for _, out := range outputs {
+			if out.AssetID() != vm.feeAssetID {
+				continue
+			}
+			produced, err = safemath.Add64(produced, out.Out.Amount())
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	if produced > consumed {
+		return 0, errProducedMoreThanConsumed
+	}
+	return consumed - produced, nil
+}
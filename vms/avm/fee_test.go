@@ -0,0 +1,29 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerFee(t *testing.T) {
+	_, vm, ctx, txs := setupIssueTx(t)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+
+	assert.Equal(t, vm.txFee, vm.MinFee())
+
+	fee, err := vm.ContainerFee(txs[1].Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, vm.txFee, fee)
+
+	_, err = vm.ContainerFee([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var _ common.FeeReporter = &VM{}
+
+// FeeLevels implements the common.FeeReporter interface. The congestion of
+// the chain is how full the mempool is. Once the mempool is full, a tx must
+// burn more than the tx with the lowest priority in it to be added.
+func (vm *VM) FeeLevels() (common.FeeLevels, error) {
+	levels := common.FeeLevels{
+		Congestion:   float64(vm.mempool.NumTxs()) / maxMempoolSize,
+		BaseFee:      vm.txFee,
+		SuggestedFee: vm.txFee,
+	}
+	if !vm.mempool.Full() {
+		return levels, nil
+	}
+	if lowest, ok := vm.mempool.LowestPriority(); ok && lowest >= levels.SuggestedFee {
+		levels.SuggestedFee = lowest + 1
+	}
+	return levels, nil
+}
+
+// TxPriority implements the common.FeeReporter interface. The priority of a
+// tx is the amount of the fee asset that it burns.
+func (vm *VM) TxPriority(txIntf common.PendingTx) uint64 {
+	tx, ok := txIntf.(*UniqueTx)
+	if !ok {
+		return 0
+	}
+	tx.refresh()
+	if tx.Tx == nil {
+		return 0
+	}
+	return burnedAmount(tx.UnsignedTx, vm.feeAssetID)
+}
+
+// burnedAmount returns how much of [assetID] [utx] consumes but doesn't
+// produce. Returns 0 if the amounts overflow.
+func burnedAmount(utx UnsignedTx, assetID ids.ID) uint64 {
+	var (
+		ins  []*avax.TransferableInput
+		outs []*avax.TransferableOutput
+	)
+	switch utx := utx.(type) {
+	case *BaseTx:
+		ins, outs = utx.Ins, utx.Outs
+	case *CreateAssetTx:
+		ins, outs = utx.Ins, utx.Outs
+	case *OperationTx:
+		ins, outs = utx.Ins, utx.Outs
+	case *ImportTx:
+		ins = make([]*avax.TransferableInput, 0, len(utx.Ins)+len(utx.ImportedIns))
+		ins = append(append(ins, utx.Ins...), utx.ImportedIns...)
+		outs = utx.Outs
+	case *ExportTx:
+		ins = utx.Ins
+		outs = make([]*avax.TransferableOutput, 0, len(utx.Outs)+len(utx.ExportedOuts))
+		outs = append(append(outs, utx.Outs...), utx.ExportedOuts...)
+	}
+
+	consumed := uint64(0)
+	for _, in := range ins {
+		if in.AssetID() != assetID {
+			continue
+		}
+		var err error
+		consumed, err = safemath.Add64(consumed, in.In.Amount())
+		if err != nil {
+			return 0
+		}
+	}
+	produced := uint64(0)
+	for _, out := range outs {
+		if out.AssetID() != assetID {
+			continue
+		}
+		var err error
+		produced, err = safemath.Add64(produced, out.Out.Amount())
+		if err != nil {
+			return 0
+		}
+	}
+	if produced > consumed {
+		return 0
+	}
+	return consumed - produced
+}
@@ -0,0 +1,101 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestBurnedAmount(t *testing.T) {
+	feeAssetID := ids.GenerateTestID()
+	otherAssetID := ids.GenerateTestID()
+	in := func(assetID ids.ID, amount uint64) *avax.TransferableInput {
+		return &avax.TransferableInput{
+			Asset: avax.Asset{ID: assetID},
+			In:    &secp256k1fx.TransferInput{Amt: amount},
+		}
+	}
+	out := func(assetID ids.ID, amount uint64) *avax.TransferableOutput {
+		return &avax.TransferableOutput{
+			Asset: avax.Asset{ID: assetID},
+			Out:   &secp256k1fx.TransferOutput{Amt: amount},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		tx       UnsignedTx
+		expected uint64
+	}{
+		{
+			name: "base tx",
+			tx: &BaseTx{BaseTx: avax.BaseTx{
+				Ins:  []*avax.TransferableInput{in(feeAssetID, 10), in(otherAssetID, 100)},
+				Outs: []*avax.TransferableOutput{out(feeAssetID, 7), out(otherAssetID, 50)},
+			}},
+			expected: 3,
+		},
+		{
+			name: "import tx",
+			tx: &ImportTx{
+				BaseTx: BaseTx{BaseTx: avax.BaseTx{
+					Outs: []*avax.TransferableOutput{out(feeAssetID, 5)},
+				}},
+				ImportedIns: []*avax.TransferableInput{in(feeAssetID, 6)},
+			},
+			expected: 1,
+		},
+		{
+			name: "export tx",
+			tx: &ExportTx{
+				BaseTx: BaseTx{BaseTx: avax.BaseTx{
+					Ins: []*avax.TransferableInput{in(feeAssetID, 10)},
+				}},
+				ExportedOuts: []*avax.TransferableOutput{out(feeAssetID, 8)},
+			},
+			expected: 2,
+		},
+		{
+			name: "overflow",
+			tx: &BaseTx{BaseTx: avax.BaseTx{
+				Ins: []*avax.TransferableInput{in(feeAssetID, math.MaxUint64), in(feeAssetID, 1)},
+			}},
+			expected: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if burned := burnedAmount(test.tx, feeAssetID); burned != test.expected {
+				t.Fatalf("expected %d to be burned but got %d", test.expected, burned)
+			}
+		})
+	}
+}
+
+func TestFeeLevels(t *testing.T) {
+	_, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+
+	levels, err := vm.FeeLevels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if levels.Congestion != 0 {
+		t.Fatalf("expected no congestion but got %f", levels.Congestion)
+	}
+	if levels.BaseFee != vm.txFee || levels.SuggestedFee != vm.txFee {
+		t.Fatalf("expected the fees to be %d but got %+v", vm.txFee, levels)
+	}
+}
@@ -0,0 +1,54 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var _ common.PriorityMempool = &VM{}
+
+// AddTx implements the common.PriorityMempool interface. The engine is
+// notified once [batchSize] txs are in the mempool, or [batchTimeout] after
+// the first tx was added.
+func (vm *VM) AddTx(tx common.PendingTx, priority uint64) error {
+	if _, ok := tx.(snowstorm.Tx); !ok {
+		parsedTx, err := vm.parseTx(tx.Bytes())
+		if err != nil {
+			return err
+		}
+		tx = parsedTx
+	}
+	if err := vm.mempool.AddTx(tx, priority); err != nil {
+		return err
+	}
+
+	switch numTxs := vm.mempool.NumTxs(); {
+	case numTxs >= batchSize:
+		vm.FlushTxs()
+	case numTxs == 1:
+		vm.timer.SetTimeoutIn(vm.batchTimeout)
+	}
+	return nil
+}
+
+// PopTx implements the common.PriorityMempool interface
+func (vm *VM) PopTx() (common.PendingTx, bool) { return vm.mempool.PopTx() }
+
+// NumTxs implements the common.PriorityMempool interface
+func (vm *VM) NumTxs() int { return vm.mempool.NumTxs() }
+
+// MempoolTxs implements the common.Mempool interface
+func (vm *VM) MempoolTxs() []common.MempoolTx { return vm.mempool.MempoolTxs() }
+
+// EvictTx implements the common.Mempool interface
+func (vm *VM) EvictTx(txID ids.ID) bool {
+	if !vm.mempool.EvictTx(txID) {
+		return false
+	}
+	vm.ctx.Log.Info("evicted tx %s from the mempool", txID)
+	return true
+}
@@ -0,0 +1,471 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var (
+	errNoSigners             = errors.New("no signers provided")
+	errNoTxs                 = errors.New("no transactions provided")
+	errNotBaseTx             = errors.New("only base transactions can be signed by multiple parties")
+	errMismatchedTxs         = errors.New("transactions don't have the same unsigned transaction")
+	errMismatchedCredentials = errors.New("credentials don't match the inputs of the transaction")
+	errConflictingSignatures = errors.New("transactions have conflicting signatures")
+	errNoSignaturesAdded     = errors.New("the user doesn't have any of the keys that are missing signatures")
+
+	// An empty signature slot of a partially signed transaction
+	emptySignature = [crypto.SECP256K1RSigLen]byte{}
+)
+
+// BuildMultisigSendArgs are arguments for passing into BuildMultisigSend
+// requests
+type BuildMultisigSendArgs struct {
+	// Addresses whose UTXOs may be spent
+	api.JSONFromAddrs
+
+	// Addresses that will sign the transaction. Only UTXOs that a threshold of
+	// these addresses can spend are spent.
+	Signers []string `json:"signers"`
+
+	// If provided, change is sent to this address. Otherwise, change is sent
+	// to the owners of the first UTXO of the asset that is spent.
+	api.JSONChangeAddr
+
+	// The outputs of the transaction
+	Outputs []SendOutput `json:"outputs"`
+
+	// Memo field
+	Memo string `json:"memo"`
+
+	// Encoding of the returned transaction
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// MultisigTxReply is the response for requests that return a partially signed
+// transaction
+type MultisigTxReply struct {
+	// The partially signed transaction. Its missing signatures are left empty.
+	api.FormattedTx
+
+	// The number of signatures that are still missing from the transaction.
+	// Once it is 0, the transaction can be issued.
+	MissingSignatures json.Uint32 `json:"missingSignatures"`
+}
+
+// BuildMultisigSend returns an unsigned transaction that spends UTXOs owned by
+// multiple addresses to fund the provided outputs. Every input of the returned
+// transaction has a credential with an empty signature slot for each signer
+// that must sign it.
+func (service *Service) BuildMultisigSend(_ *http.Request, args *BuildMultisigSendArgs, reply *MultisigTxReply) error {
+	service.vm.ctx.Log.Info("AVM: BuildMultisigSend called")
+
+	// Validate the memo field
+	memoBytes := []byte(args.Memo)
+	if l := len(memoBytes); l > avax.MaxMemoSize {
+		return fmt.Errorf("max memo length is %d but provided memo field is length %d",
+			avax.MaxMemoSize,
+			l)
+	} else if len(args.Outputs) == 0 {
+		return errNoOutputs
+	}
+
+	fromAddrs, err := service.parseAddrs(args.From)
+	if err != nil {
+		return fmt.Errorf("couldn't parse 'From' addresses: %w", err)
+	}
+	if fromAddrs.Len() == 0 {
+		return errNoAddresses
+	}
+	signers, err := service.parseAddrs(args.Signers)
+	if err != nil {
+		return fmt.Errorf("couldn't parse 'Signers' addresses: %w", err)
+	}
+	if signers.Len() == 0 {
+		return errNoSigners
+	}
+	var changeOwners *secp256k1fx.OutputOwners
+	if args.ChangeAddr != "" {
+		changeAddr, err := service.vm.ParseLocalAddress(args.ChangeAddr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse changeAddr: %w", err)
+		}
+		changeOwners = &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{changeAddr},
+		}
+	}
+
+	// Calculate required input amounts and create the desired outputs
+	// String repr. of asset ID --> asset ID
+	assetIDs := make(map[string]ids.ID)
+	// Asset ID --> amount of that asset being sent
+	amounts := make(map[ids.ID]uint64)
+	// Outputs of our tx
+	outs := []*avax.TransferableOutput{}
+	for _, output := range args.Outputs {
+		if output.Amount == 0 {
+			return errZeroAmount
+		}
+		assetID, ok := assetIDs[output.AssetID] // Asset ID of next output
+		if !ok {
+			assetID, err = service.vm.lookupAssetID(output.AssetID)
+			if err != nil {
+				return fmt.Errorf("couldn't find asset %s", output.AssetID)
+			}
+			assetIDs[output.AssetID] = assetID
+		}
+		newAmount, err := safemath.Add64(amounts[assetID], uint64(output.Amount))
+		if err != nil {
+			return fmt.Errorf("problem calculating required spend amount: %w", err)
+		}
+		amounts[assetID] = newAmount
+
+		// Parse the to address
+		to, err := service.vm.ParseLocalAddress(output.To)
+		if err != nil {
+			return fmt.Errorf("problem parsing to address %q: %w", output.To, err)
+		}
+
+		// Create the Output
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: uint64(output.Amount),
+				OutputOwners: secp256k1fx.OutputOwners{
+					Locktime:  0,
+					Threshold: 1,
+					Addrs:     []ids.ShortID{to},
+				},
+			},
+		})
+	}
+
+	amountWithFee, err := safemath.Add64(amounts[service.vm.feeAssetID], service.vm.txFee)
+	if err != nil {
+		return fmt.Errorf("problem calculating required spend amount: %w", err)
+	}
+	amounts[service.vm.feeAssetID] = amountWithFee
+
+	utxos, err := service.vm.getAllUTXOs(fromAddrs)
+	if err != nil {
+		return fmt.Errorf("problem retrieving UTXOs: %w", err)
+	}
+	sortUTXOsLargestFirst(utxos)
+
+	now := service.vm.clock.Unix()
+	amountsSpent := make(map[ids.ID]uint64, len(amounts))
+	// Asset ID --> owners of the first UTXO of the asset that is spent
+	spentOwners := make(map[ids.ID]*secp256k1fx.OutputOwners, len(amounts))
+	ins := []*avax.TransferableInput{}
+	for _, utxo := range utxos {
+		assetID := utxo.AssetID()
+		amountSpent := amountsSpent[assetID]
+		if amountSpent >= amounts[assetID] {
+			// we already have enough inputs allocated to this asset
+			continue
+		}
+
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || out.Locktime > now {
+			continue
+		}
+		sigIndices, ok := multisigIndices(&out.OutputOwners, signers)
+		if !ok {
+			// the signers can't spend this UTXO
+			continue
+		}
+		newAmountSpent, err := safemath.Add64(amountSpent, out.Amt)
+		if err != nil {
+			return errSpendOverflow
+		}
+		amountsSpent[assetID] = newAmountSpent
+		if _, ok := spentOwners[assetID]; !ok {
+			spentOwners[assetID] = &out.OutputOwners
+		}
+
+		ins = append(ins, &avax.TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  avax.Asset{ID: assetID},
+			In: &secp256k1fx.TransferInput{
+				Amt:   out.Amt,
+				Input: secp256k1fx.Input{SigIndices: sigIndices},
+			},
+		})
+	}
+
+	for assetID, amount := range amounts {
+		amountSpent := amountsSpent[assetID]
+		if amountSpent < amount {
+			return fmt.Errorf("want to spend %d of asset %s but the signers can only spend %d",
+				amount,
+				assetID,
+				amountSpent,
+			)
+		}
+		if amountSpent == amount {
+			continue
+		}
+
+		owners := changeOwners
+		if owners == nil {
+			owners = spentOwners[assetID]
+		}
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amountSpent - amount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Locktime:  0,
+					Threshold: owners.Threshold,
+					Addrs:     owners.Addrs,
+				},
+			},
+		})
+	}
+	avax.SortTransferableOutputs(outs, service.vm.codec)
+	avax.SortTransferableInputs(ins)
+
+	creds := make([]verify.Verifiable, len(ins))
+	for i, in := range ins {
+		creds[i] = &secp256k1fx.Credential{
+			Sigs: make([][crypto.SECP256K1RSigLen]byte, len(in.In.(*secp256k1fx.TransferInput).SigIndices)),
+		}
+	}
+	tx := &Tx{
+		UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    service.vm.ctx.NetworkID,
+			BlockchainID: service.vm.ctx.ChainID,
+			Outs:         outs,
+			Ins:          ins,
+			Memo:         memoBytes,
+		}},
+		Creds: creds,
+	}
+	return service.formatMultisigTx(tx, args.Encoding, reply)
+}
+
+// SignMultisigTxArgs are arguments for passing into SignMultisigTx requests
+type SignMultisigTxArgs struct {
+	// The user whose keys sign the transaction
+	api.UserPass
+
+	// The partially signed transaction
+	api.FormattedTx
+}
+
+// SignMultisigTx adds the signatures of the user's keys to the partially
+// signed transaction. Signatures that are already present aren't modified.
+func (service *Service) SignMultisigTx(_ *http.Request, args *SignMultisigTxArgs, reply *MultisigTxReply) error {
+	service.vm.ctx.Log.Info("AVM: SignMultisigTx called for user '%s'", args.Username)
+
+	tx, baseTx, err := service.parseMultisigTx(args.Tx, args.Encoding)
+	if err != nil {
+		return err
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user %q: %w", args.Username, err)
+	}
+	// Drop any potential error closing the database to report the original
+	// error
+	defer db.Close()
+
+	user := userState{vm: service.vm}
+	kc, err := user.Keychain(db, ids.ShortSet{})
+	if err != nil {
+		return err
+	}
+
+	hash := hashing.ComputeHash256(tx.UnsignedBytes())
+	numSigned := 0
+	for i, in := range baseTx.Ins {
+		input := in.In.(*secp256k1fx.TransferInput)
+		cred := tx.Creds[i].(*secp256k1fx.Credential)
+
+		utxo, err := service.vm.getUTXO(&in.UTXOID)
+		if err != nil {
+			return fmt.Errorf("problem retrieving UTXO %s: %w", in.InputID(), err)
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			return fmt.Errorf("UTXO %s isn't a transfer output", in.InputID())
+		}
+
+		for j, sigIndex := range input.SigIndices {
+			if cred.Sigs[j] != emptySignature {
+				continue
+			}
+			if int(sigIndex) >= len(out.Addrs) {
+				return fmt.Errorf("signature index %d of UTXO %s is out of bounds", sigIndex, in.InputID())
+			}
+			key, ok := kc.Get(out.Addrs[sigIndex])
+			if !ok {
+				continue
+			}
+			sig, err := key.SignHash(hash)
+			if err != nil {
+				return fmt.Errorf("problem signing transaction: %w", err)
+			}
+			copy(cred.Sigs[j][:], sig)
+			numSigned++
+		}
+	}
+	if numSigned == 0 {
+		return errNoSignaturesAdded
+	}
+
+	if err := service.formatMultisigTx(tx, args.Encoding, reply); err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+// CombineMultisigTxsArgs are arguments for passing into CombineMultisigTxs
+// requests
+type CombineMultisigTxsArgs struct {
+	// Partially signed copies of the same transaction
+	Txs []string `json:"txs"`
+
+	// Encoding of [Txs] and of the returned transaction
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// CombineMultisigTxs returns the transaction with the signatures of all of the
+// partially signed copies of it, so that parties can sign it in parallel
+func (service *Service) CombineMultisigTxs(_ *http.Request, args *CombineMultisigTxsArgs, reply *MultisigTxReply) error {
+	service.vm.ctx.Log.Info("AVM: CombineMultisigTxs called with %d transactions", len(args.Txs))
+
+	if len(args.Txs) == 0 {
+		return errNoTxs
+	}
+	combinedTx, _, err := service.parseMultisigTx(args.Txs[0], args.Encoding)
+	if err != nil {
+		return err
+	}
+	for _, txStr := range args.Txs[1:] {
+		tx, _, err := service.parseMultisigTx(txStr, args.Encoding)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(tx.UnsignedBytes(), combinedTx.UnsignedBytes()) {
+			return errMismatchedTxs
+		}
+		for i, credIntf := range tx.Creds {
+			cred := credIntf.(*secp256k1fx.Credential)
+			combinedCred := combinedTx.Creds[i].(*secp256k1fx.Credential)
+			if len(cred.Sigs) != len(combinedCred.Sigs) {
+				return errMismatchedCredentials
+			}
+			for j, sig := range cred.Sigs {
+				switch {
+				case sig == emptySignature:
+				case combinedCred.Sigs[j] == emptySignature:
+					combinedCred.Sigs[j] = sig
+				case combinedCred.Sigs[j] != sig:
+					return errConflictingSignatures
+				}
+			}
+		}
+	}
+	return service.formatMultisigTx(combinedTx, args.Encoding, reply)
+}
+
+// parseMultisigTx parses the partially signed base transaction [txStr], whose
+// credentials must match its inputs
+func (service *Service) parseMultisigTx(txStr string, encoding formatting.Encoding) (*Tx, *BaseTx, error) {
+	txBytes, err := formatting.Decode(encoding, txStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	tx, err := service.vm.parsePrivateTx(txBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem parsing transaction: %w", err)
+	}
+	baseTx, ok := tx.UnsignedTx.(*BaseTx)
+	if !ok {
+		return nil, nil, errNotBaseTx
+	}
+	if len(tx.Creds) != len(baseTx.Ins) {
+		return nil, nil, errMismatchedCredentials
+	}
+	for i, in := range baseTx.Ins {
+		input, ok := in.In.(*secp256k1fx.TransferInput)
+		if !ok {
+			return nil, nil, errMismatchedCredentials
+		}
+		cred, ok := tx.Creds[i].(*secp256k1fx.Credential)
+		if !ok || len(cred.Sigs) != len(input.SigIndices) {
+			return nil, nil, errMismatchedCredentials
+		}
+	}
+	return tx, baseTx, nil
+}
+
+// formatMultisigTx writes the partially signed [tx] to [reply]
+func (service *Service) formatMultisigTx(tx *Tx, encoding formatting.Encoding, reply *MultisigTxReply) error {
+	txBytes, err := service.vm.codec.Marshal(codecVersion, tx)
+	if err != nil {
+		return fmt.Errorf("problem marshalling transaction: %w", err)
+	}
+	reply.Tx, err = formatting.Encode(encoding, txBytes)
+	if err != nil {
+		return fmt.Errorf("problem encoding transaction: %w", err)
+	}
+	reply.Encoding = encoding
+
+	missingSignatures := uint32(0)
+	for _, credIntf := range tx.Creds {
+		for _, sig := range credIntf.(*secp256k1fx.Credential).Sigs {
+			if sig == emptySignature {
+				missingSignatures++
+			}
+		}
+	}
+	reply.MissingSignatures = json.Uint32(missingSignatures)
+	return nil
+}
+
+// parseAddrs returns the set of the addresses in [addrStrs]
+func (service *Service) parseAddrs(addrStrs []string) (ids.ShortSet, error) {
+	addrs := ids.NewShortSet(len(addrStrs))
+	for _, addrStr := range addrStrs {
+		addr, err := service.vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+		}
+		addrs.Add(addr)
+	}
+	return addrs, nil
+}
+
+// multisigIndices returns the indices of the first threshold of [owners]'
+// addresses that are in [signers], or false if fewer than a threshold of them
+// are in [signers]
+func multisigIndices(owners *secp256k1fx.OutputOwners, signers ids.ShortSet) ([]uint32, bool) {
+	sigIndices := make([]uint32, 0, owners.Threshold)
+	for i := 0; i < len(owners.Addrs) && uint32(len(sigIndices)) < owners.Threshold; i++ {
+		if signers.Contains(owners.Addrs[i]) {
+			sigIndices = append(sigIndices, uint32(i))
+		}
+	}
+	return sigIndices, uint32(len(sigIndices)) == owners.Threshold
+}
@@ -0,0 +1,177 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultisigSend(t *testing.T) {
+	_, vm, s, _, genesisTx := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	owners := []ids.ShortID{addrs[0], addrs[1], addrs[2]}
+	ids.SortShortIDs(owners)
+	ownerStrs := make([]string, len(owners))
+	for i, owner := range owners {
+		ownerStr, err := vm.FormatLocalAddress(owner)
+		assert.NoError(t, err)
+		ownerStrs[i] = ownerStr
+	}
+
+	// A UTXO with a 2 out of 3 multisig
+	utxo := &avax.UTXO{
+		UTXOID: avax.UTXOID{
+			TxID:        ids.GenerateTestID(),
+			OutputIndex: 0,
+		},
+		Asset: avax.Asset{ID: genesisTx.ID()},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 100000,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 2,
+				Addrs:     owners,
+			},
+		},
+	}
+	assert.NoError(t, vm.state.PutUTXO(utxo.InputID(), utxo))
+
+	to, err := vm.FormatLocalAddress(ids.GenerateTestShortID())
+	assert.NoError(t, err)
+	buildArgs := &BuildMultisigSendArgs{
+		JSONFromAddrs: api.JSONFromAddrs{From: ownerStrs},
+		Signers:       []string{ownerStrs[0], ownerStrs[2]},
+		Outputs: []SendOutput{{
+			Amount:  500,
+			AssetID: genesisTx.ID().String(),
+			To:      to,
+		}},
+		Encoding: formatting.Hex,
+	}
+	unsignedReply := &MultisigTxReply{}
+	assert.NoError(t, s.BuildMultisigSend(nil, buildArgs, unsignedReply))
+	assert.EqualValues(t, 2, unsignedReply.MissingSignatures)
+
+	// Each signer signs its own copy of the transaction
+	signedTxs := make([]string, 0, 2)
+	for _, ownerIndex := range []int{0, 2} {
+		setUserKey(t, vm, owners[ownerIndex])
+
+		signArgs := &SignMultisigTxArgs{
+			UserPass:    api.UserPass{Username: username, Password: password},
+			FormattedTx: unsignedReply.FormattedTx,
+		}
+		signReply := &MultisigTxReply{}
+		assert.NoError(t, s.SignMultisigTx(nil, signArgs, signReply))
+		assert.EqualValues(t, 1, signReply.MissingSignatures)
+
+		// The user doesn't have any of the missing keys anymore
+		signArgs.FormattedTx = signReply.FormattedTx
+		err := s.SignMultisigTx(nil, signArgs, &MultisigTxReply{})
+		assert.ErrorIs(t, err, errNoSignaturesAdded)
+
+		signedTxs = append(signedTxs, signReply.Tx)
+	}
+
+	// A non-signer can't sign the transaction
+	setUserKey(t, vm, owners[1])
+	err = s.SignMultisigTx(nil, &SignMultisigTxArgs{
+		UserPass:    api.UserPass{Username: username, Password: password},
+		FormattedTx: unsignedReply.FormattedTx,
+	}, &MultisigTxReply{})
+	assert.ErrorIs(t, err, errNoSignaturesAdded)
+
+	// The partially signed transaction can't be issued
+	err = s.IssueTx(nil, &api.FormattedTx{
+		Tx:       signedTxs[0],
+		Encoding: formatting.Hex,
+	}, &api.JSONTxID{})
+	assert.Error(t, err)
+
+	combineReply := &MultisigTxReply{}
+	assert.NoError(t, s.CombineMultisigTxs(nil, &CombineMultisigTxsArgs{
+		Txs:      signedTxs,
+		Encoding: formatting.Hex,
+	}, combineReply))
+	assert.EqualValues(t, 0, combineReply.MissingSignatures)
+
+	issueReply := &api.JSONTxID{}
+	assert.NoError(t, s.IssueTx(nil, &combineReply.FormattedTx, issueReply))
+	assert.NotEqual(t, ids.Empty, issueReply.TxID)
+}
+
+func TestCombineMultisigTxsMismatched(t *testing.T) {
+	_, vm, s, _, genesisTx := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	owner, err := vm.FormatLocalAddress(addrs[0])
+	assert.NoError(t, err)
+	to, err := vm.FormatLocalAddress(ids.GenerateTestShortID())
+	assert.NoError(t, err)
+	assert.NoError(t, vm.state.PutUTXO(ids.Empty, &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: genesisTx.ID()},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 100000,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addrs[0]},
+			},
+		},
+	}))
+
+	txs := make([]string, 0, 2)
+	for _, amount := range []uint64{500, 600} {
+		reply := &MultisigTxReply{}
+		assert.NoError(t, s.BuildMultisigSend(nil, &BuildMultisigSendArgs{
+			JSONFromAddrs: api.JSONFromAddrs{From: []string{owner}},
+			Signers:       []string{owner},
+			Outputs: []SendOutput{{
+				Amount:  json.Uint64(amount),
+				AssetID: genesisTx.ID().String(),
+				To:      to,
+			}},
+			Encoding: formatting.Hex,
+		}, reply))
+		txs = append(txs, reply.Tx)
+	}
+
+	err = s.CombineMultisigTxs(nil, &CombineMultisigTxsArgs{
+		Txs:      txs,
+		Encoding: formatting.Hex,
+	}, &MultisigTxReply{})
+	assert.ErrorIs(t, err, errMismatchedTxs)
+}
+
+// setUserKey replaces the keys of the test user with the key of [addr]
+func setUserKey(t *testing.T, vm *VM, addr ids.ShortID) {
+	user := userState{vm: vm}
+	db, err := vm.ctx.Keystore.GetDatabase(username, password)
+	assert.NoError(t, err)
+	for _, sk := range keys {
+		if sk.PublicKey().Address() == addr {
+			assert.NoError(t, user.SetKey(db, sk))
+		}
+	}
+	assert.NoError(t, user.SetAddresses(db, []ids.ShortID{addr}))
+	assert.NoError(t, db.Close())
+}
@@ -16,9 +16,13 @@ func NewPubSubFilterer(tx *Tx) pubsub.Filterer {
 	return &filterer{tx: tx}
 }
 
-// Apply the filter on the addresses.
+// Apply the filter on the tx ID and the addresses.
 func (f *filterer) Filter(filters []pubsub.Filter) ([]bool, interface{}) {
 	resp := make([]bool, len(filters))
+	txID := f.tx.ID()
+	for i, c := range filters {
+		resp[i] = c.Check(txID[:])
+	}
 	for _, utxo := range f.tx.UTXOs() {
 		addressable, ok := utxo.Out.(avax.Addressable)
 		if !ok {
@@ -35,6 +39,6 @@ func (f *filterer) Filter(filters []pubsub.Filter) ([]bool, interface{}) {
 		}
 	}
 	return resp, api.JSONTxID{
-		TxID: f.tx.ID(),
+		TxID: txID,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/pubsub"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -45,3 +46,19 @@ func TestFilter(t *testing.T) {
 	fr, _ := parser.Filter([]pubsub.Filter{&mockFilter{addr: addrBytes}})
 	assert.Equal([]bool{true}, fr)
 }
+
+func TestFilterTxID(t *testing.T) {
+	assert := assert.New(t)
+
+	tx := Tx{UnsignedTx: &BaseTx{}}
+	tx.Initialize([]byte{1}, []byte{2})
+	txID := tx.ID()
+
+	parser := NewPubSubFilterer(&tx)
+	fr, msg := parser.Filter([]pubsub.Filter{
+		&mockFilter{addr: txID[:]},
+		&mockFilter{addr: []byte{3}},
+	})
+	assert.Equal([]bool{true, false}, fr)
+	assert.Equal(api.JSONTxID{TxID: txID}, msg)
+}
@@ -11,11 +11,13 @@ import (
 	"strings"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
@@ -47,6 +49,7 @@ var (
 	errNilTxID                = errors.New("nil transaction ID")
 	errNoAddresses            = errors.New("no addresses provided")
 	errNoKeys                 = errors.New("from addresses have no keys or funds")
+	errKeyAndMnemonic         = errors.New("only one of 'privateKey' and 'mnemonic' can be provided")
 )
 
 // Service defines the base service for the asset vm
@@ -96,6 +99,79 @@ func (service *Service) GetTxStatus(r *http.Request, args *api.JSONTxID, reply *
 	return nil
 }
 
+// GetTxStatusDetailedReply defines the GetTxStatusDetailed replies returned
+// from the API
+type GetTxStatusDetailedReply struct {
+	Status choices.Status `json:"status"`
+	// Unix time, in seconds, that the tx was decided at
+	DecidedAt *json.Uint64 `json:"decidedAt,omitempty"`
+	// Vertex, and its epoch, that the accepted tx was accepted in
+	VertexID *ids.ID      `json:"vertexID,omitempty"`
+	Epoch    *json.Uint32 `json:"epoch,omitempty"`
+	// Accepted tx that conflicted with the rejected tx
+	ConflictingTxID *ids.ID `json:"conflictingTxID,omitempty"`
+	// Rejected tx that the rejected tx depended on
+	RejectedDependencyID *ids.ID `json:"rejectedDependencyID,omitempty"`
+	// Whether the UTXOs that the accepted tx exports have been written to
+	// shared memory, and the error of the last attempt if they haven't
+	ExportStatus *string `json:"exportStatus,omitempty"`
+	ExportError  string  `json:"exportError,omitempty"`
+}
+
+// GetTxStatusDetailed returns the status of the specified transaction along
+// with how and when it was decided
+func (service *Service) GetTxStatusDetailed(r *http.Request, args *api.JSONTxID, reply *GetTxStatusDetailedReply) error {
+	service.vm.ctx.Log.Info("AVM: GetTxStatusDetailed called with %s", args.TxID)
+
+	if args.TxID == ids.Empty {
+		return errNilTxID
+	}
+
+	tx := UniqueTx{
+		vm:   service.vm,
+		txID: args.TxID,
+	}
+
+	reply.Status = tx.Status()
+	if !reply.Status.Decided() {
+		return nil
+	}
+
+	exportState, err := service.vm.atomicQueue.State(args.TxID)
+	switch {
+	case err == nil:
+		exportStatus := exportState.Status.String()
+		reply.ExportStatus = &exportStatus
+		reply.ExportError = exportState.LastError
+	case err != database.ErrNotFound:
+		return fmt.Errorf("couldn't get the export status of tx %s: %w", args.TxID, err)
+	}
+
+	decision, err := service.vm.state.GetTxDecision(args.TxID)
+	if err == database.ErrNotFound {
+		// The tx was decided before decisions were recorded
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't get the decision of tx %s: %w", args.TxID, err)
+	}
+
+	decidedAt := json.Uint64(decision.Timestamp)
+	reply.DecidedAt = &decidedAt
+	if decision.VertexID != ids.Empty {
+		epoch := json.Uint32(decision.Epoch)
+		reply.VertexID = &decision.VertexID
+		reply.Epoch = &epoch
+	}
+	if decision.ConflictID != ids.Empty {
+		reply.ConflictingTxID = &decision.ConflictID
+	}
+	if decision.DependencyID != ids.Empty {
+		reply.RejectedDependencyID = &decision.DependencyID
+	}
+	return nil
+}
+
 // GetTx returns the specified transaction
 func (service *Service) GetTx(r *http.Request, args *api.GetTxArgs, reply *api.FormattedTx) error {
 	service.vm.ctx.Log.Info("AVM: GetTx called with %s", args.TxID)
@@ -160,7 +236,7 @@ func (service *Service) GetUTXOs(r *http.Request, args *api.GetUTXOsArgs, reply
 		if err != nil {
 			return fmt.Errorf("couldn't parse start index address %q: %w", args.StartIndex.Address, err)
 		}
-		startUTXO, err = ids.FromString(args.StartIndex.UTXO)
+		startUTXO, err = address.ParseID(args.StartIndex.UTXO)
 		if err != nil {
 			return fmt.Errorf("couldn't parse start index utxo: %w", err)
 		}
@@ -216,6 +292,52 @@ func (service *Service) GetUTXOs(r *http.Request, args *api.GetUTXOsArgs, reply
 	return nil
 }
 
+// GetPendingTransfers returns the cross-chain transfers of the given addresses
+// that haven't completed: the exports of this chain that couldn't be written
+// to shared memory yet, and the UTXOs that were exported to this chain from the
+// given source chains and haven't been imported yet
+func (service *Service) GetPendingTransfers(_ *http.Request, args *api.GetPendingTransfersArgs, reply *api.GetPendingTransfersReply) error {
+	service.vm.ctx.Log.Info("AVM: GetPendingTransfers called with %s", args.Addresses)
+
+	if len(args.Addresses) == 0 {
+		return errNoAddresses
+	}
+	if len(args.Addresses) > maxGetUTXOsAddrs {
+		return fmt.Errorf("number of addresses given, %d, exceeds maximum, %d", len(args.Addresses), maxGetUTXOsAddrs)
+	}
+
+	addrSet := ids.ShortSet{}
+	for _, addrStr := range args.Addresses {
+		addr, err := service.vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+		}
+		addrSet.Add(addr)
+	}
+	sourceChains := make([]ids.ID, len(args.SourceChains))
+	for i, chain := range args.SourceChains {
+		chainID, err := service.vm.ctx.BCLookup.Lookup(chain)
+		if err != nil {
+			return fmt.Errorf("problem parsing source chainID %q: %w", chain, err)
+		}
+		sourceChains[i] = chainID
+	}
+
+	transfers, err := avax.GetPendingTransfers(
+		service.vm.atomicQueue,
+		service.vm.AtomicUTXOManager,
+		service.vm.codec,
+		addrSet,
+		sourceChains,
+		args.Encoding,
+	)
+	if err != nil {
+		return err
+	}
+	*reply = *transfers
+	return nil
+}
+
 // GetAssetDescriptionArgs are arguments for passing into GetAssetDescription requests
 type GetAssetDescriptionArgs struct {
 	AssetID string `json:"assetID"`
@@ -821,6 +943,13 @@ func (service *Service) ExportKey(r *http.Request, args *ExportKeyArgs, reply *E
 type ImportKeyArgs struct {
 	api.UserPass
 	PrivateKey string `json:"privateKey"`
+	// If provided instead of [PrivateKey], the imported key is derived from
+	// this BIP-39 mnemonic
+	Mnemonic string `json:"mnemonic"`
+	// Passphrase that protects [Mnemonic], if any
+	MnemonicPassphrase string `json:"mnemonicPassphrase"`
+	// Index of the address whose key is derived from [Mnemonic]
+	AddressIndex json.Uint32 `json:"addressIndex"`
 }
 
 // ImportKeyReply is the response for ImportKey
@@ -846,21 +975,32 @@ func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *a
 		return fmt.Errorf("keystore user has reached its limit of %d addresses", maxKeystoreAddresses)
 	}
 
-	if !strings.HasPrefix(args.PrivateKey, constants.SecretKeyPrefix) {
-		return fmt.Errorf("private key missing %s prefix", constants.SecretKeyPrefix)
-	}
-	trimmedPrivateKey := strings.TrimPrefix(args.PrivateKey, constants.SecretKeyPrefix)
-	privKeyBytes, err := formatting.Decode(formatting.CB58, trimmedPrivateKey)
-	if err != nil {
-		return fmt.Errorf("problem parsing private key: %w", err)
-	}
+	var sk *crypto.PrivateKeySECP256K1R
+	if args.Mnemonic != "" {
+		if args.PrivateKey != "" {
+			return errKeyAndMnemonic
+		}
+		sk, err = crypto.MnemonicToPrivateKey(args.Mnemonic, args.MnemonicPassphrase, uint32(args.AddressIndex))
+		if err != nil {
+			return fmt.Errorf("problem deriving private key: %w", err)
+		}
+	} else {
+		if !strings.HasPrefix(args.PrivateKey, constants.SecretKeyPrefix) {
+			return fmt.Errorf("private key missing %s prefix", constants.SecretKeyPrefix)
+		}
+		trimmedPrivateKey := strings.TrimPrefix(args.PrivateKey, constants.SecretKeyPrefix)
+		privKeyBytes, err := formatting.Decode(formatting.CB58, trimmedPrivateKey)
+		if err != nil {
+			return fmt.Errorf("problem parsing private key: %w", err)
+		}
 
-	factory := crypto.FactorySECP256K1R{}
-	skIntf, err := factory.ToPrivateKey(privKeyBytes)
-	if err != nil {
-		return fmt.Errorf("problem parsing private key: %w", err)
+		factory := crypto.FactorySECP256K1R{}
+		skIntf, err := factory.ToPrivateKey(privKeyBytes)
+		if err != nil {
+			return fmt.Errorf("problem parsing private key: %w", err)
+		}
+		sk = skIntf.(*crypto.PrivateKeySECP256K1R)
 	}
-	sk := skIntf.(*crypto.PrivateKeySECP256K1R)
 
 	if err := user.SetKey(db, sk); err != nil {
 		return fmt.Errorf("problem saving key %w", err)
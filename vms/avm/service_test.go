@@ -15,9 +15,11 @@ import (
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/sampler"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
@@ -240,6 +242,103 @@ func TestServiceGetTxStatus(t *testing.T) {
 	}
 }
 
+func TestServiceGetTxStatusDetailed(t *testing.T) {
+	genesisBytes, vm, s, _, _ := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	now := time.Unix(1000, 0)
+	vm.clock.Set(now)
+
+	tx := NewTx(t, genesisBytes, vm)
+	uniqueTx, err := vm.ParseTx(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statusArgs := &api.JSONTxID{TxID: tx.ID()}
+	statusReply := &GetTxStatusDetailedReply{}
+	if err := s.GetTxStatusDetailed(nil, statusArgs, statusReply); err != nil {
+		t.Fatal(err)
+	}
+	if statusReply.Status != choices.Processing {
+		t.Fatalf("Expected status %s, got %s", choices.Processing, statusReply.Status)
+	}
+	if statusReply.DecidedAt != nil {
+		t.Fatal("Expected a processing tx not to have a decision time")
+	}
+
+	if err := uniqueTx.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := uniqueTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	vtxID := ids.GenerateTestID()
+	if err := uniqueTx.(*UniqueTx).AcceptedInVertex(vtxID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	statusReply = &GetTxStatusDetailedReply{}
+	if err := s.GetTxStatusDetailed(nil, statusArgs, statusReply); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case statusReply.Status != choices.Accepted:
+		t.Fatalf("Expected status %s, got %s", choices.Accepted, statusReply.Status)
+	case statusReply.DecidedAt == nil || uint64(*statusReply.DecidedAt) != uint64(now.Unix()):
+		t.Fatalf("Expected the tx to have been decided at %d", now.Unix())
+	case statusReply.VertexID == nil || *statusReply.VertexID != vtxID:
+		t.Fatalf("Expected the tx to have been accepted in vertex %s", vtxID)
+	case statusReply.ConflictingTxID != nil || statusReply.RejectedDependencyID != nil:
+		t.Fatal("Expected an accepted tx not to have a rejection reason")
+	}
+}
+
+func TestServiceGetTxStatusDetailedRejected(t *testing.T) {
+	genesisBytes, vm, s, _, _ := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	tx := NewTx(t, genesisBytes, vm)
+	uniqueTx, err := vm.ParseTx(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conflictID := ids.GenerateTestID()
+	uniqueTx.(*UniqueTx).RejectedBecause(snowstorm.RejectionReason{ConflictID: conflictID})
+	if err := uniqueTx.Reject(); err != nil {
+		t.Fatal(err)
+	}
+
+	statusArgs := &api.JSONTxID{TxID: tx.ID()}
+	statusReply := &GetTxStatusDetailedReply{}
+	if err := s.GetTxStatusDetailed(nil, statusArgs, statusReply); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case statusReply.Status != choices.Rejected:
+		t.Fatalf("Expected status %s, got %s", choices.Rejected, statusReply.Status)
+	case statusReply.DecidedAt == nil:
+		t.Fatal("Expected a rejected tx to have a decision time")
+	case statusReply.VertexID != nil:
+		t.Fatal("Expected a rejected tx not to have a vertex")
+	case statusReply.ConflictingTxID == nil || *statusReply.ConflictingTxID != conflictID:
+		t.Fatalf("Expected the tx to have been rejected because of %s", conflictID)
+	case statusReply.RejectedDependencyID != nil:
+		t.Fatal("Expected the tx not to have been rejected because of a dependency")
+	}
+}
+
 // Test the GetBalance method when argument Strict is true
 func TestServiceGetBalanceStrict(t *testing.T) {
 	_, vm, s, _, _ := setup(t, true)
@@ -720,7 +819,7 @@ func TestServiceGetUTXOs(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	unknownChainAddr, err := formatting.FormatAddress("R", hrp, rawAddr.Bytes())
+	unknownChainAddr, err := address.Format("R", hrp, rawAddr.Bytes())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1314,6 +1413,52 @@ func TestImportExportKey(t *testing.T) {
 	}
 }
 
+func TestImportMnemonicKey(t *testing.T) {
+	_, vm, s, _, _ := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	mnemonic, err := crypto.NewMnemonic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, err := crypto.MnemonicToPrivateKey(mnemonic, "passphrase", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	importArgs := &ImportKeyArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		Mnemonic:           mnemonic,
+		MnemonicPassphrase: "passphrase",
+		AddressIndex:       3,
+	}
+	importReply := &api.JSONAddress{}
+	if err := s.ImportKey(nil, importArgs, importReply); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedAddress, err := vm.FormatLocalAddress(sk.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if importReply.Address != expectedAddress {
+		t.Fatalf("Reply address: %s did not match expected address: %s", importReply.Address, expectedAddress)
+	}
+
+	importArgs.PrivateKey = constants.SecretKeyPrefix + "ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNN"
+	if err := s.ImportKey(nil, importArgs, &api.JSONAddress{}); err == nil {
+		t.Fatal("should have errored due to both a private key and a mnemonic being provided")
+	}
+}
+
 func TestImportAVMKeyNoDuplicates(t *testing.T) {
 	_, vm, s, _, _ := setup(t, true)
 	ctx := vm.ctx
@@ -1427,7 +1572,7 @@ func TestSend(t *testing.T) {
 		t.Fatalf("expected change address to be %s but got %s", changeAddrStr, reply.ChangeAddr)
 	}
 
-	pendingTxs := vm.txs
+	pendingTxs := vm.PendingTxs()
 	if len(pendingTxs) != 1 {
 		t.Fatalf("Expected to find 1 pending tx after send, but found %d", len(pendingTxs))
 	}
@@ -1491,7 +1636,7 @@ func TestSendMultiple(t *testing.T) {
 				t.Fatalf("expected change address to be %s but got %s", changeAddrStr, reply.ChangeAddr)
 			}
 
-			pendingTxs := vm.txs
+			pendingTxs := vm.PendingTxs()
 			if len(pendingTxs) != 1 {
 				t.Fatalf("Expected to find 1 pending tx after send, but found %d", len(pendingTxs))
 			}
@@ -0,0 +1,93 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+)
+
+// maxRecoveredCreds is the most credentials whose signers are recovered ahead
+// of verifying their txs. The fxs only cache the last 256 public keys that
+// they recovered, so recovering many more would evict the keys before the txs
+// are verified.
+const maxRecoveredCreds = 64
+
+// signerRecoverer is an fx that can recover the signers of a credential
+// before the credential is verified
+type signerRecoverer interface {
+	RecoverSigners(txIntf, credIntf interface{}) error
+}
+
+type recoverSignersJob struct {
+	fx   signerRecoverer
+	tx   UnsignedTx
+	cred verify.Verifiable
+}
+
+// recoverSigners concurrently recovers the signers of the txs at the start of
+// [txs] that have at most [maxRecoveredCreds] credentials between them.
+// Returns how many txs that is, which is at least one. Recovery errors are
+// ignored, since verifying the txs reports them.
+func (vm *VM) recoverSigners(txs []snowstorm.Tx) int {
+	jobs := []recoverSignersJob(nil)
+	numTxs := 0
+	for _, tx := range txs {
+		utx, ok := tx.(*UniqueTx)
+		if !ok {
+			numTxs++
+			continue
+		}
+		utx.refresh()
+		if utx.Tx == nil {
+			numTxs++
+			continue
+		}
+		if numTxs > 0 && len(jobs)+len(utx.Creds) > maxRecoveredCreds {
+			break
+		}
+		numTxs++
+		for _, cred := range utx.Creds {
+			fxIndex, err := vm.getFx(cred)
+			if err != nil {
+				continue
+			}
+			fx, ok := vm.fxs[fxIndex].Fx.(signerRecoverer)
+			if !ok {
+				continue
+			}
+			jobs = append(jobs, recoverSignersJob{
+				fx:   fx,
+				tx:   utx.UnsignedTx,
+				cred: cred,
+			})
+		}
+	}
+
+	numWorkers := runtime.NumCPU()
+	if numWorkers > len(jobs) {
+		numWorkers = len(jobs)
+	}
+	jobChan := make(chan recoverSignersJob, len(jobs))
+	for _, job := range jobs {
+		jobChan <- job
+	}
+	close(jobChan)
+
+	wg := sync.WaitGroup{}
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for job := range jobChan {
+				_ = job.fx.RecoverSigners(job.tx, job.cred)
+			}
+		}()
+	}
+	wg.Wait()
+	return numTxs
+}
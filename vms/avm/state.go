@@ -22,16 +22,18 @@ var (
 	statusStatePrefix          = []byte("status")
 	singletonStatePrefix       = []byte("singleton")
 	txStatePrefix              = []byte("tx")
+	decisionStatePrefix        = []byte("decision")
 	_                    State = &state{}
 )
 
-// State persistently maintains a set of UTXOs, transaction, statuses, and
-// singletons.
+// State persistently maintains a set of UTXOs, transaction, statuses,
+// decisions, and singletons.
 type State interface {
 	avax.UTXOState
 	avax.StatusState
 	avax.SingletonState
 	TxState
+	DecisionState
 
 	DeduplicateTx(tx *UniqueTx) *UniqueTx
 }
@@ -41,6 +43,7 @@ type state struct {
 	avax.StatusState
 	avax.SingletonState
 	TxState
+	DecisionState
 
 	uniqueTxs cache.Deduplicator
 }
@@ -50,12 +53,14 @@ func NewState(db database.Database, genesisCodec, codec codec.Manager) State {
 	statusDB := prefixdb.New(statusStatePrefix, db)
 	singletonDB := prefixdb.New(singletonStatePrefix, db)
 	txDB := prefixdb.New(txStatePrefix, db)
+	decisionDB := prefixdb.New(decisionStatePrefix, db)
 
 	return &state{
 		UTXOState:      avax.NewUTXOState(utxoDB, codec),
 		StatusState:    avax.NewStatusState(statusDB),
 		SingletonState: avax.NewSingletonState(singletonDB),
 		TxState:        NewTxState(txDB, genesisCodec),
+		DecisionState:  NewDecisionState(decisionDB, codec),
 
 		uniqueTxs: &cache.EvictableLRU{
 			Size: txDeduplicatorSize,
@@ -68,6 +73,7 @@ func NewMeteredState(db database.Database, genesisCodec, codec codec.Manager, na
 	statusDB := prefixdb.New(statusStatePrefix, db)
 	singletonDB := prefixdb.New(singletonStatePrefix, db)
 	txDB := prefixdb.New(txStatePrefix, db)
+	decisionDB := prefixdb.New(decisionStatePrefix, db)
 
 	utxoState, err := avax.NewMeteredUTXOState(utxoDB, codec, namespace, metrics)
 	if err != nil {
@@ -85,6 +91,7 @@ func NewMeteredState(db database.Database, genesisCodec, codec codec.Manager, na
 		StatusState:    statusState,
 		SingletonState: avax.NewSingletonState(singletonDB),
 		TxState:        txState,
+		DecisionState:  NewDecisionState(decisionDB, codec),
 
 		uniqueTxs: &cache.EvictableLRU{
 			Size: txDeduplicatorSize,
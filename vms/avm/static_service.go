@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanchego/codec/reflectcodec"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -97,7 +98,7 @@ func (ss *StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, r
 						if err := json.Unmarshal(b, &holder); err != nil {
 							return fmt.Errorf("problem unmarshaling holder: %w", err)
 						}
-						_, addrbuff, err := formatting.ParseBech32(holder.Address)
+						_, addrbuff, err := address.ParseBech32(holder.Address)
 						if err != nil {
 							return fmt.Errorf("problem parsing holder address: %w", err)
 						}
@@ -129,8 +130,8 @@ func (ss *StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, r
 								Threshold: 1,
 							},
 						}
-						for _, address := range owners.Minters {
-							_, addrbuff, err := formatting.ParseBech32(address)
+						for _, minter := range owners.Minters {
+							_, addrbuff, err := address.ParseBech32(minter)
 							if err != nil {
 								return fmt.Errorf("problem parsing minters address: %w", err)
 							}
@@ -8,9 +8,11 @@ import (
 	"fmt"
 
 	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 )
 
@@ -23,8 +25,10 @@ var (
 )
 
 var (
-	_ snowstorm.Tx    = &UniqueTx{}
-	_ cache.Evictable = &UniqueTx{}
+	_ snowstorm.Tx               = &UniqueTx{}
+	_ snowstorm.RejectionTracker = &UniqueTx{}
+	_ vertex.AcceptedInVertexTx  = &UniqueTx{}
+	_ cache.Evictable            = &UniqueTx{}
 )
 
 // UniqueTx provides a de-duplication service for txs. This only provides a
@@ -47,7 +51,8 @@ type TxCachedState struct {
 	utxos      []*avax.UTXO
 	deps       []snowstorm.Tx
 
-	status choices.Status
+	status          choices.Status
+	rejectionReason snowstorm.RejectionReason
 }
 
 func (tx *UniqueTx) refresh() {
@@ -150,7 +155,23 @@ func (tx *UniqueTx) Accept() error {
 
 	txID := tx.ID()
 
-	commitBatch, err := tx.vm.db.CommitBatch()
+	// Record when this tx was accepted. The vertex it was accepted in is
+	// recorded once that vertex is accepted.
+	if err := tx.vm.state.PutTxDecision(txID, &TxDecision{
+		Status:    choices.Accepted,
+		Timestamp: tx.vm.clock.Unix(),
+	}); err != nil {
+		tx.vm.ctx.Log.Error("Failed to record the decision of tx %s due to %s", txID, err)
+		return err
+	}
+
+	// The writes are held back until they're committed with the vertex this
+	// tx is accepted in, unless the tx's side effects need them written now
+	if err := tx.vm.db.Commit(); err != nil {
+		tx.vm.ctx.Log.Error("Failed to commit accept %s due to %s", txID, err)
+		return err
+	}
+	commitBatch, err := tx.vm.pendingDB.CommitBatch()
 	if err != nil {
 		tx.vm.ctx.Log.Error("Failed to calculate CommitBatch for %s due to %s", txID, err)
 		return err
@@ -160,6 +181,14 @@ func (tx *UniqueTx) Accept() error {
 		tx.vm.ctx.Log.Error("Failed to commit accept %s due to %s", txID, err)
 		return err
 	}
+	// While bootstrapping, the tx's job is removed from the queue before the
+	// vertex is accepted, so the writes can't wait for the vertex
+	if !tx.vm.bootstrapped {
+		if err := tx.vm.pendingDB.Commit(); err != nil {
+			tx.vm.ctx.Log.Error("Failed to commit accept %s due to %s", txID, err)
+			return err
+		}
+	}
 
 	tx.vm.ctx.Log.Verbo("Accepted Tx: %s", txID)
 
@@ -183,6 +212,16 @@ func (tx *UniqueTx) Reject() error {
 	txID := tx.ID()
 	tx.vm.ctx.Log.Debug("Rejecting Tx: %s", txID)
 
+	if err := tx.vm.state.PutTxDecision(txID, &TxDecision{
+		Status:       choices.Rejected,
+		Timestamp:    tx.vm.clock.Unix(),
+		ConflictID:   tx.rejectionReason.ConflictID,
+		DependencyID: tx.rejectionReason.DependencyID,
+	}); err != nil {
+		tx.vm.ctx.Log.Error("Failed to record the decision of tx %s due to %s", txID, err)
+		return err
+	}
+
 	if err := tx.vm.db.Commit(); err != nil {
 		tx.vm.ctx.Log.Error("Failed to commit reject %s due to %s", tx.txID, err)
 		return err
@@ -195,6 +234,41 @@ func (tx *UniqueTx) Reject() error {
 	return nil
 }
 
+// RejectedBecause is called with the reason this transaction is being
+// rejected right before Reject is called
+func (tx *UniqueTx) RejectedBecause(reason snowstorm.RejectionReason) {
+	tx.refresh()
+	tx.rejectionReason = reason
+}
+
+// AcceptedInVertex is called when a vertex containing this accepted
+// transaction is accepted. The vertex is recorded in the decision of this
+// transaction, which is written atomically with the vertex by CommitWith.
+func (tx *UniqueTx) AcceptedInVertex(vtxID ids.ID, epoch uint32) error {
+	txID := tx.ID()
+	decision, err := tx.vm.state.GetTxDecision(txID)
+	if err == database.ErrNotFound {
+		// This tx was accepted before decisions were recorded
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if decision.VertexID != ids.Empty {
+		// This tx was already accepted in an earlier vertex
+		return nil
+	}
+
+	defer tx.vm.db.Abort()
+
+	decision.VertexID = vtxID
+	decision.Epoch = epoch
+	if err := tx.vm.state.PutTxDecision(txID, decision); err != nil {
+		return err
+	}
+	return tx.vm.db.Commit()
+}
+
 // Status returns the current status of this transaction
 func (tx *UniqueTx) Status() choices.Status {
 	tx.refresh()
@@ -12,14 +12,15 @@ import (
 	"reflect"
 	"time"
 
-	"github.com/gorilla/rpc/v2"
-
+	"github.com/ava-labs/avalanchego/api/openapi"
 	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
 	"github.com/ava-labs/avalanchego/codec/reflectcodec"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/pubsub"
@@ -33,6 +34,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/mempool"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/nftfx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -46,21 +48,31 @@ const (
 	batchSize          = 30
 	assetToFxCacheSize = 1024
 	maxUTXOsToFetch    = 1024
+	maxMempoolSize     = 4096
+
+	// How often the exports that couldn't be written to shared memory are
+	// retried
+	atomicRetryFrequency = 30 * time.Second
 
 	codecVersion = 0
 )
 
 var (
+	atomicQueuePrefix = []byte("atomicQueue")
+
 	errIncompatibleFx            = errors.New("incompatible feature extension")
 	errUnknownFx                 = errors.New("unknown feature extension")
 	errGenesisAssetMustHaveState = errors.New("genesis asset must have non-empty state")
 	errWrongBlockchainID         = errors.New("wrong blockchain ID")
 	errBootstrapping             = errors.New("chain is currently bootstrapping")
+	errArchival                  = errors.New("node is in archival mode, so it doesn't issue transactions")
 	errInsufficientFunds         = errors.New("insufficient funds")
 
-	_ vertex.DAGVM    = &VM{}
-	_ common.StaticVM = &VM{}
-	_ secp256k1fx.VM  = &VM{}
+	_ vertex.DAGVM           = &VM{}
+	_ vertex.BatchVerifierVM = &VM{}
+	_ vertex.BatchCommitter  = &VM{}
+	_ common.StaticVM        = &VM{}
+	_ secp256k1fx.VM         = &VM{}
 )
 
 // VM implements the avalanche.DAGVM interface
@@ -101,11 +113,19 @@ type VM struct {
 	// Transaction issuing
 	timer        *timer.Timer
 	batchTimeout time.Duration
-	txs          []snowstorm.Tx
+	mempool      *mempool.Mempool
 	toEngine     chan<- common.Message
 
 	baseDB database.Database
-	db     *versiondb.Database
+	// The writes of accepted txs are held in [pendingDB] until the vertex
+	// they're accepted in is accepted, so that they're written atomically with
+	// the vertex
+	pendingDB *versiondb.Database
+	db        *versiondb.Database
+
+	// Exports that are written to shared memory, and retried if that fails
+	atomicQueue   *atomic.Queue
+	atomicRetrier *timer.Repeater
 
 	typeToFxIndex map[reflect.Type]int
 	fxs           []*parsedFx
@@ -147,7 +167,8 @@ func (vm *VM) Initialize(
 	vm.ctx = ctx
 	vm.toEngine = toEngine
 	vm.baseDB = db
-	vm.db = versiondb.New(db)
+	vm.pendingDB = versiondb.New(db)
+	vm.db = versiondb.New(vm.pendingDB)
 	vm.typeToFxIndex = map[reflect.Type]int{}
 	vm.assetToFxCache = &cache.LRU{Size: assetToFxCacheSize}
 
@@ -209,10 +230,16 @@ func (vm *VM) Initialize(
 	}
 	vm.state = state
 
+	vm.atomicQueue, err = atomic.NewQueue(ctx.SharedMemory, prefixdb.New(atomicQueuePrefix, vm.baseDB))
+	if err != nil {
+		return err
+	}
+
 	if err := vm.initGenesis(genesisBytes); err != nil {
 		return err
 	}
 
+	vm.mempool = mempool.New(maxMempoolSize)
 	vm.timer = timer.NewTimer(func() {
 		ctx.Lock.Lock()
 		defer ctx.Lock.Unlock()
@@ -222,11 +249,22 @@ func (vm *VM) Initialize(
 	go ctx.Log.RecoverAndPanic(vm.timer.Dispatch)
 	vm.batchTimeout = batchTimeout
 
+	vm.atomicRetrier = timer.NewRepeater(func() {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		vm.retryAtomicOperations()
+	}, atomicRetryFrequency)
+	go ctx.Log.RecoverAndPanic(vm.atomicRetrier.Dispatch)
+
 	vm.walletService.vm = vm
 	vm.walletService.pendingTxMap = make(map[ids.ID]*list.Element)
 	vm.walletService.pendingTxOrdering = list.New()
 
-	return vm.db.Commit()
+	if err := vm.db.Commit(); err != nil {
+		return err
+	}
+	return vm.pendingDB.Commit()
 }
 
 // Bootstrapping is called by the consensus engine when it starts bootstrapping
@@ -262,8 +300,11 @@ func (vm *VM) Shutdown() error {
 	// So, the lock must be released before stopping the timer.
 	vm.ctx.Lock.Unlock()
 	vm.timer.Stop()
+	vm.atomicRetrier.Stop()
 	vm.ctx.Lock.Lock()
 
+	// The writes held in [vm.pendingDB] are dropped, as if the node had
+	// crashed, because the vertices they're waiting on weren't accepted
 	return vm.baseDB.Close()
 }
 
@@ -271,7 +312,7 @@ func (vm *VM) Shutdown() error {
 func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 	codec := cjson.NewCodec()
 
-	rpcServer := rpc.NewServer()
+	rpcServer := openapi.NewServer()
 	rpcServer.RegisterCodec(codec, "application/json")
 	rpcServer.RegisterCodec(codec, "application/json;charset=UTF-8")
 	rpcServer.RegisterInterceptFunc(vm.metrics.apiRequestMetric.InterceptRequest)
@@ -281,7 +322,7 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 		return nil, err
 	}
 
-	walletServer := rpc.NewServer()
+	walletServer := openapi.NewServer()
 	walletServer.RegisterCodec(codec, "application/json")
 	walletServer.RegisterCodec(codec, "application/json;charset=UTF-8")
 	walletServer.RegisterInterceptFunc(vm.metrics.apiRequestMetric.InterceptRequest)
@@ -298,7 +339,7 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 
 // CreateStaticHandlers implements the common.StaticVM interface
 func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
-	newServer := rpc.NewServer()
+	newServer := openapi.NewServer()
 	codec := cjson.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
@@ -314,9 +355,14 @@ func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
 func (vm *VM) PendingTxs() []snowstorm.Tx {
 	vm.timer.Cancel()
 
-	txs := vm.txs
-	vm.txs = nil
-	return txs
+	txs := make([]snowstorm.Tx, 0, vm.mempool.NumTxs())
+	for {
+		tx, ok := vm.mempool.PopTx()
+		if !ok {
+			return txs
+		}
+		txs = append(txs, tx.(snowstorm.Tx))
+	}
 }
 
 // Parse implements the avalanche.DAGVM interface
@@ -335,6 +381,34 @@ func (vm *VM) GetTx(txID ids.ID) (snowstorm.Tx, error) {
 	return tx, tx.verifyWithoutCacheWrites()
 }
 
+// CommitWith implements the vertex.BatchCommitter interface. The writes of the
+// txs accepted since the last call are written atomically with [batches].
+func (vm *VM) CommitWith(batches ...database.Batch) error {
+	defer vm.pendingDB.Abort()
+
+	batch, err := vm.pendingDB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	return atomic.WriteAll(batch, batches...)
+}
+
+// VerifyTxs implements the vertex.BatchVerifierVM interface. The public keys
+// that signed the txs are recovered concurrently before the txs are verified
+// one at a time, so that verifying a tx finds its signers in the caches of
+// the fxs.
+func (vm *VM) VerifyTxs(txs []snowstorm.Tx) []error {
+	errs := make([]error, len(txs))
+	for start := 0; start < len(txs); {
+		end := vm.recoverSigners(txs[start:]) + start
+		for i := start; i < end; i++ {
+			errs[i] = txs[i].Verify()
+		}
+		start = end
+	}
+	return errs
+}
+
 /*
  ******************************************************************************
  ********************************** JSON API **********************************
@@ -346,6 +420,9 @@ func (vm *VM) GetTx(txID ids.ID) (snowstorm.Tx, error) {
 // either accepted or rejected with the appropriate status. This function will
 // go out of scope when the transaction is removed from memory.
 func (vm *VM) IssueTx(b []byte) (ids.ID, error) {
+	if vm.ctx.Archival {
+		return ids.ID{}, errArchival
+	}
 	if !vm.bootstrapped {
 		return ids.ID{}, errBootstrapping
 	}
@@ -356,7 +433,9 @@ func (vm *VM) IssueTx(b []byte) (ids.ID, error) {
 	if err := tx.verifyWithoutCacheWrites(); err != nil {
 		return ids.ID{}, err
 	}
-	vm.issueTx(tx)
+	if err := vm.issueTx(tx); err != nil {
+		return ids.ID{}, err
+	}
 	return tx.ID(), nil
 }
 
@@ -386,8 +465,7 @@ func (vm *VM) getPaginatedUTXOs(
 	seen := make(ids.Set, limit) // IDs of UTXOs already in the list
 
 	// enforces the same ordering for pagination
-	addrsList := addrs.List()
-	ids.SortShortIDs(addrsList)
+	addrsList := addrs.SortedList()
 
 	for _, addr := range addrsList {
 		start := ids.Empty
@@ -432,8 +510,7 @@ func (vm *VM) getAllUTXOs(addrs ids.ShortSet) ([]*avax.UTXO, error) {
 	utxos := make([]*avax.UTXO, 0, maxUTXOsToFetch)
 
 	// enforces the same ordering for pagination
-	addrsList := addrs.List()
-	ids.SortShortIDs(addrsList)
+	addrsList := addrs.SortedList()
 
 	// iterate over the addresses and get all the utxos
 	for _, addr := range addrsList {
@@ -503,7 +580,7 @@ func (vm *VM) Logger() logging.Logger { return vm.ctx.Log }
 // FlushTxs into consensus
 func (vm *VM) FlushTxs() {
 	vm.timer.Cancel()
-	if len(vm.txs) != 0 {
+	if vm.mempool.NumTxs() != 0 {
 		select {
 		case vm.toEngine <- common.PendingTxs:
 		default:
@@ -513,6 +590,18 @@ func (vm *VM) FlushTxs() {
 	}
 }
 
+// retryAtomicOperations writes the exports that couldn't be written to shared
+// memory when their txs were accepted
+func (vm *VM) retryAtomicOperations() {
+	remaining, err := vm.atomicQueue.Retry()
+	if err != nil {
+		vm.ctx.Log.Error("couldn't retry exports to shared memory: %s", err)
+	}
+	if remaining > 0 {
+		vm.ctx.Log.Warn("%d exports couldn't be written to shared memory, retrying in %s", remaining, atomicRetryFrequency)
+	}
+}
+
 /*
  ******************************************************************************
  ********************************** Helpers ***********************************
@@ -630,14 +719,8 @@ func (vm *VM) parsePrivateTx(txBytes []byte) (*Tx, error) {
 	return tx, nil
 }
 
-func (vm *VM) issueTx(tx snowstorm.Tx) {
-	vm.txs = append(vm.txs, tx)
-	switch {
-	case len(vm.txs) == batchSize:
-		vm.FlushTxs()
-	case len(vm.txs) == 1:
-		vm.timer.SetTimeoutIn(vm.batchTimeout)
-	}
+func (vm *VM) issueTx(tx snowstorm.Tx) error {
+	return vm.AddTx(tx, vm.TxPriority(tx))
 }
 
 func (vm *VM) getUTXO(utxoID *avax.UTXOID) (*avax.UTXO, error) {
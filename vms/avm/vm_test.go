@@ -10,14 +10,19 @@ import (
 
 	"github.com/ava-labs/avalanchego/api/keystore"
 	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/mockdb"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/units"
@@ -146,9 +151,9 @@ func GetAVAXTxFromGenesisTest(genesisBytes []byte, tb testing.TB) *Tx {
 
 // BuildGenesisTest is the common Genesis builder for most tests
 func BuildGenesisTest(tb testing.TB) []byte {
-	addr0Str, _ := formatting.FormatBech32(testHRP, addrs[0].Bytes())
-	addr1Str, _ := formatting.FormatBech32(testHRP, addrs[1].Bytes())
-	addr2Str, _ := formatting.FormatBech32(testHRP, addrs[2].Bytes())
+	addr0Str, _ := address.FormatBech32(testHRP, addrs[0].Bytes())
+	addr1Str, _ := address.FormatBech32(testHRP, addrs[1].Bytes())
+	addr2Str, _ := address.FormatBech32(testHRP, addrs[2].Bytes())
 
 	defaultArgs := &BuildGenesisArgs{
 		Encoding: formatting.Hex,
@@ -700,6 +705,26 @@ func TestIssueTx(t *testing.T) {
 	}
 }
 
+func TestIssueTxArchival(t *testing.T) {
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+	ctx.Archival = true
+
+	newTx := NewTx(t, genesisBytes, vm)
+	if _, err := vm.IssueTx(newTx.Bytes()); err != errArchival {
+		t.Fatalf("IssueTx should have failed with %s but got %v", errArchival, err)
+	}
+	if txs := vm.PendingTxs(); len(txs) != 0 {
+		t.Fatalf("Shouldn't have returned any txs")
+	}
+}
+
 func TestGenesisGetUTXOs(t *testing.T) {
 	_, _, vm, _ := GenesisVM(t)
 	ctx := vm.ctx
@@ -742,9 +767,9 @@ func TestGenesisGetUTXOs(t *testing.T) {
 // - Pagination when the total UTXOs exceed maxUTXOsToFetch (1024)
 // - Fetching all UTXOs when they exceed maxUTXOsToFetch (1024)
 func TestGenesisGetPaginatedUTXOs(t *testing.T) {
-	addr0Str, _ := formatting.FormatBech32(testHRP, addrs[0].Bytes())
-	addr1Str, _ := formatting.FormatBech32(testHRP, addrs[1].Bytes())
-	addr2Str, _ := formatting.FormatBech32(testHRP, addrs[2].Bytes())
+	addr0Str, _ := address.FormatBech32(testHRP, addrs[0].Bytes())
+	addr1Str, _ := address.FormatBech32(testHRP, addrs[1].Bytes())
+	addr2Str, _ := address.FormatBech32(testHRP, addrs[2].Bytes())
 
 	// Create a starting point of 3000 UTXOs on different addresses
 	utxoCount := 2345
@@ -826,6 +851,44 @@ func TestGenesisGetPaginatedUTXOs(t *testing.T) {
 
 // Test issuing a transaction that consumes a currently pending UTXO. The
 // transaction should be issued successfully.
+func TestVerifyTxs(t *testing.T) {
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+
+	validTx := NewTx(t, genesisBytes, vm)
+	wrongSignerTx := NewTx(t, genesisBytes, vm)
+	wrongSignerTx.Creds = nil
+	if err := wrongSignerTx.SignSECP256K1Fx(vm.codec, [][]*crypto.PrivateKeySECP256K1R{{keys[1]}}); err != nil {
+		t.Fatal(err)
+	}
+
+	txs := make([]snowstorm.Tx, 0, 2)
+	for _, tx := range []*Tx{validTx, wrongSignerTx} {
+		parsedTx, err := vm.ParseTx(tx.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, parsedTx)
+	}
+
+	errs := vm.VerifyTxs(txs)
+	if len(errs) != len(txs) {
+		t.Fatalf("expected %d results but got %d", len(txs), len(errs))
+	}
+	if errs[0] != nil {
+		t.Fatalf("valid tx failed verification: %s", errs[0])
+	}
+	if errs[1] == nil {
+		t.Fatalf("tx signed by the wrong key should have failed verification")
+	}
+}
+
 func TestIssueDependentTx(t *testing.T) {
 	issuer, vm, ctx, txs := setupIssueTx(t)
 	defer func() {
@@ -1174,9 +1237,9 @@ func TestIssueProperty(t *testing.T) {
 }
 
 func setupTxFeeAssets(t *testing.T) ([]byte, chan common.Message, *VM, *atomic.Memory) {
-	addr0Str, _ := formatting.FormatBech32(testHRP, addrs[0].Bytes())
-	addr1Str, _ := formatting.FormatBech32(testHRP, addrs[1].Bytes())
-	addr2Str, _ := formatting.FormatBech32(testHRP, addrs[2].Bytes())
+	addr0Str, _ := address.FormatBech32(testHRP, addrs[0].Bytes())
+	addr1Str, _ := address.FormatBech32(testHRP, addrs[1].Bytes())
+	addr2Str, _ := address.FormatBech32(testHRP, addrs[2].Bytes())
 	assetAlias := "asset1"
 	customArgs := &BuildGenesisArgs{
 		Encoding: formatting.Hex,
@@ -1559,3 +1622,94 @@ func TestTxVerifyAfterVerifyAncestorTx(t *testing.T) {
 		t.Fatalf("Should have errored due to a missing UTXO")
 	}
 }
+
+func TestAcceptedTxIsWrittenWithVertex(t *testing.T) {
+	genesisBytes, vm, _, _, _ := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	tx := NewTx(t, genesisBytes, vm)
+	uniqueTx, err := vm.ParseTx(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := uniqueTx.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := uniqueTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	vtxID := ids.GenerateTestID()
+	if err := uniqueTx.(*UniqueTx).AcceptedInVertex(vtxID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reads what was written, rather than what [vm] holds in memory
+	persisted := NewState(versiondb.New(vm.baseDB), vm.genesisCodec, vm.codec)
+	if _, err := persisted.GetTxDecision(tx.ID()); err != database.ErrNotFound {
+		t.Fatalf("The tx shouldn't be written before its vertex but got %v", err)
+	}
+	if status := uniqueTx.Status(); status != choices.Accepted {
+		t.Fatalf("Expected the VM to report the tx as %s but got %s", choices.Accepted, status)
+	}
+
+	// Stands in for the writes of the vertex
+	vtxDB := versiondb.New(prefixdb.New([]byte("vertex"), vm.baseDB))
+	if err := vtxDB.Put(vtxID[:], nil); err != nil {
+		t.Fatal(err)
+	}
+	vtxBatch, err := vtxDB.CommitBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.CommitWith(vtxBatch); err != nil {
+		t.Fatal(err)
+	}
+
+	persisted = NewState(versiondb.New(vm.baseDB), vm.genesisCodec, vm.codec)
+	decision, err := persisted.GetTxDecision(tx.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decision.Status != choices.Accepted || decision.VertexID != vtxID {
+		t.Fatalf("Expected the tx to have been written as accepted in vertex %s", vtxID)
+	}
+	if status, err := persisted.GetStatus(tx.ID()); err != nil || status != choices.Accepted {
+		t.Fatalf("Expected the tx to have been written as %s but got (%s, %v)", choices.Accepted, status, err)
+	}
+	if has, err := prefixdb.New([]byte("vertex"), vm.baseDB).Has(vtxID[:]); err != nil || !has {
+		t.Fatalf("Expected the vertex to have been written but got (%v, %v)", has, err)
+	}
+}
+
+func TestAcceptedTxIsWrittenWhileBootstrapping(t *testing.T) {
+	genesisBytes, vm, _, _, _ := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+	vm.bootstrapped = false
+
+	tx := NewTx(t, genesisBytes, vm)
+	uniqueTx, err := vm.ParseTx(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := uniqueTx.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := uniqueTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	persisted := NewState(versiondb.New(vm.baseDB), vm.genesisCodec, vm.codec)
+	if status, err := persisted.GetStatus(tx.ID()); err != nil || status != choices.Accepted {
+		t.Fatalf("Expected the tx to have been written as %s but got (%s, %v)", choices.Accepted, status, err)
+	}
+}
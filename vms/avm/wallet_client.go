@@ -87,3 +87,14 @@ func (c *WalletClient) SendMultiple(
 	}, res)
 	return res.TxID, err
 }
+
+// GetAllBalances returns the balance of each asset that [user]'s addresses in
+// [from], or all of [user]'s addresses if [from] is empty, can spend
+func (c *WalletClient) GetAllBalances(user api.UserPass, from []string) ([]Balance, error) {
+	res := &GetAllBalancesReply{}
+	err := c.requester.SendRequest("getAllBalances", &WalletBalancesArgs{
+		UserPass:      user,
+		JSONFromAddrs: api.JSONFromAddrs{From: from},
+	}, res)
+	return res.Balances, err
+}
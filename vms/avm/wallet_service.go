@@ -4,9 +4,11 @@
 package avm
 
 import (
+	"bytes"
 	"container/list"
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
@@ -14,6 +16,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
 	safemath "github.com/ava-labs/avalanchego/utils/math"
 )
 
@@ -86,6 +89,105 @@ func (w *WalletService) update(utxos []*avax.UTXO) ([]*avax.UTXO, error) {
 	return newUTXOs, nil
 }
 
+// loadUser returns the UTXOs and keys of the user's addresses in [from], or of
+// all of the user's addresses if [from] is empty. The UTXOs reflect the
+// transactions issued through the wallet that haven't been decided yet.
+func (w *WalletService) loadUser(username, password string, from []string) ([]*avax.UTXO, *secp256k1fx.Keychain, error) {
+	// Parse the from addresses
+	fromAddrs := ids.NewShortSet(len(from))
+	for _, addrStr := range from {
+		addr, err := w.vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("couldn't parse 'From' address %s: %w", addrStr, err)
+		}
+		fromAddrs.Add(addr)
+	}
+
+	// Load user's UTXOs/keys
+	utxos, kc, err := w.vm.LoadUser(username, password, fromAddrs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	utxos, err = w.update(utxos)
+	return utxos, kc, err
+}
+
+// sortUTXOsLargestFirst sorts [utxos] by decreasing amount. UTXOs without an
+// amount are sorted last, and ties are broken by UTXO ID so that the order is
+// deterministic.
+func sortUTXOsLargestFirst(utxos []*avax.UTXO) {
+	sort.Slice(utxos, func(i, j int) bool {
+		amountI := utxoAmount(utxos[i])
+		amountJ := utxoAmount(utxos[j])
+		if amountI != amountJ {
+			return amountI > amountJ
+		}
+		utxoIDI := utxos[i].InputID()
+		utxoIDJ := utxos[j].InputID()
+		return bytes.Compare(utxoIDI[:], utxoIDJ[:]) < 0
+	})
+}
+
+// utxoAmount returns the amount of [utxo], or 0 if it doesn't have an amount
+func utxoAmount(utxo *avax.UTXO) uint64 {
+	if out, ok := utxo.Out.(avax.Amounter); ok {
+		return out.Amount()
+	}
+	return 0
+}
+
+// WalletBalancesArgs are arguments for passing into GetAllBalances requests
+type WalletBalancesArgs struct {
+	api.UserPass
+	api.JSONFromAddrs
+}
+
+// GetAllBalances returns the balance of each asset that the user's addresses in
+// [args.From], or all of the user's addresses if [args.From] is empty, can
+// spend now. The balances include the outputs, and exclude the inputs, of the
+// transactions issued through the wallet that haven't been decided yet.
+func (w *WalletService) GetAllBalances(r *http.Request, args *WalletBalancesArgs, reply *GetAllBalancesReply) error {
+	w.vm.ctx.Log.Info("AVM Wallet: GetAllBalances called with username: %s", args.Username)
+
+	utxos, kc, err := w.loadUser(args.Username, args.Password, args.From)
+	if err != nil {
+		return err
+	}
+
+	now := w.vm.clock.Unix()
+	balances := make(map[ids.ID]uint64)
+	for _, utxo := range utxos {
+		inputIntf, _, err := kc.Spend(utxo.Out, now)
+		if err != nil {
+			// This UTXO can't be spent with the user's keys right now
+			continue
+		}
+		input, ok := inputIntf.(avax.TransferableIn)
+		if !ok {
+			continue
+		}
+		assetID := utxo.AssetID()
+		balance, err := safemath.Add64(balances[assetID], input.Amount())
+		if err != nil {
+			return fmt.Errorf("problem calculating balance of %s: %w", assetID, err)
+		}
+		balances[assetID] = balance
+	}
+
+	reply.Balances = make([]Balance, 0, len(balances))
+	for assetID, balance := range balances {
+		reply.Balances = append(reply.Balances, Balance{
+			AssetID: assetID.String(),
+			Balance: json.Uint64(balance),
+		})
+	}
+	sort.Slice(reply.Balances, func(i, j int) bool {
+		return reply.Balances[i].AssetID < reply.Balances[j].AssetID
+	})
+	return nil
+}
+
 // IssueTx attempts to issue a transaction into consensus
 func (w *WalletService) IssueTx(r *http.Request, args *api.FormattedTx, reply *api.JSONTxID) error {
 	w.vm.ctx.Log.Info("AVM Wallet: IssueTx called with %s", args.Tx)
@@ -122,23 +224,7 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 		return errNoOutputs
 	}
 
-	// Parse the from addresses
-	fromAddrs := ids.NewShortSet(len(args.From))
-	for _, addrStr := range args.From {
-		addr, err := w.vm.ParseLocalAddress(addrStr)
-		if err != nil {
-			return fmt.Errorf("couldn't parse 'From' address %s: %w", addrStr, err)
-		}
-		fromAddrs.Add(addr)
-	}
-
-	// Load user's UTXOs/keys
-	utxos, kc, err := w.vm.LoadUser(args.Username, args.Password, fromAddrs)
-	if err != nil {
-		return err
-	}
-
-	utxos, err = w.update(utxos)
+	utxos, kc, err := w.loadUser(args.Username, args.Password, args.From)
 	if err != nil {
 		return err
 	}
@@ -209,6 +295,9 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	}
 	amountsWithFee[w.vm.feeAssetID] = amountWithFee
 
+	// Spend the largest UTXOs first, so that as few UTXOs as possible are
+	// consumed
+	sortUTXOsLargestFirst(utxos)
 	amountsSpent, ins, keys, err := w.vm.Spend(
 		utxos,
 		kc,
@@ -10,6 +10,8 @@ import (
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
 // Returns:
@@ -117,7 +119,7 @@ func TestWalletService_SendMultiple(t *testing.T) {
 				t.Fatalf("expected change address to be %s but got %s", changeAddrStr, reply.ChangeAddr)
 			}
 
-			pendingTxs := vm.txs
+			pendingTxs := vm.PendingTxs()
 			if len(pendingTxs) != 1 {
 				t.Fatalf("Expected to find 1 pending tx after send, but found %d", len(pendingTxs))
 			}
@@ -132,3 +134,104 @@ func TestWalletService_SendMultiple(t *testing.T) {
 		})
 	}
 }
+
+func TestWalletService_GetAllBalances(t *testing.T) {
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, vm, ws, _, genesisTx := setupWSWithKeys(t, tc.avaxAsset)
+			defer func() {
+				if err := vm.Shutdown(); err != nil {
+					t.Fatal(err)
+				}
+				vm.ctx.Lock.Unlock()
+			}()
+
+			assetID := genesisTx.ID()
+			addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+			if err != nil {
+				t.Fatal(err)
+			}
+			userPass := api.UserPass{
+				Username: username,
+				Password: password,
+			}
+
+			balanceOf := func() uint64 {
+				reply := &GetAllBalancesReply{}
+				if err := ws.GetAllBalances(nil, &WalletBalancesArgs{UserPass: userPass}, reply); err != nil {
+					t.Fatal(err)
+				}
+				for _, balance := range reply.Balances {
+					if balance.AssetID == assetID.String() {
+						return uint64(balance.Balance)
+					}
+				}
+				t.Fatalf("expected a balance of %s", assetID)
+				return 0
+			}
+
+			balanceBefore := balanceOf()
+			// Sending to one of the user's own addresses only costs the fee,
+			// which must be reflected before the transaction is decided
+			vm.timer.Cancel()
+			if err := ws.Send(nil, &SendArgs{
+				JSONSpendHeader: api.JSONSpendHeader{
+					UserPass:       userPass,
+					JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: addrStr},
+				},
+				SendOutput: SendOutput{
+					Amount:  1000,
+					AssetID: assetID.String(),
+					To:      addrStr,
+				},
+			}, &api.JSONTxIDChangeAddr{}); err != nil {
+				t.Fatal(err)
+			}
+
+			expectedBalance := balanceBefore - vm.txFee
+			if balance := balanceOf(); balance != expectedBalance {
+				t.Fatalf("expected balance %d but got %d", expectedBalance, balance)
+			}
+
+			// Addresses that the user doesn't control can't be queried
+			otherAddrStr, err := vm.FormatLocalAddress(testChangeAddr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ws.GetAllBalances(nil, &WalletBalancesArgs{
+				UserPass:      userPass,
+				JSONFromAddrs: api.JSONFromAddrs{From: []string{otherAddrStr}},
+			}, &GetAllBalancesReply{}); err == nil {
+				t.Fatal("should have errored due to the user not controlling the address")
+			}
+		})
+	}
+}
+
+func TestSortUTXOsLargestFirst(t *testing.T) {
+	utxos := []*avax.UTXO{
+		{
+			UTXOID: avax.UTXOID{TxID: ids.ID{1}},
+			Out:    &secp256k1fx.TransferOutput{Amt: 5},
+		},
+		{
+			UTXOID: avax.UTXOID{TxID: ids.ID{2}},
+			Out:    &secp256k1fx.MintOutput{},
+		},
+		{
+			UTXOID: avax.UTXOID{TxID: ids.ID{3}},
+			Out:    &secp256k1fx.TransferOutput{Amt: 10},
+		},
+		{
+			UTXOID: avax.UTXOID{TxID: ids.ID{4}},
+			Out:    &secp256k1fx.TransferOutput{Amt: 5},
+		},
+	}
+	sortUTXOsLargestFirst(utxos)
+
+	for i, expectedTxID := range []ids.ID{{3}, {1}, {4}, {2}} {
+		if utxos[i].TxID != expectedTxID {
+			t.Fatalf("expected UTXO %d to be from tx %s but was from %s", i, expectedTxID, utxos[i].TxID)
+		}
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/utils/constants"
-	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 )
 
 var _ AddressManager = &addressManager{}
@@ -57,7 +57,7 @@ func (a *addressManager) ParseLocalAddress(addrStr string) (ids.ShortID, error)
 }
 
 func (a *addressManager) ParseAddress(addrStr string) (ids.ID, ids.ShortID, error) {
-	chainIDAlias, hrp, addrBytes, err := formatting.ParseAddress(addrStr)
+	chainIDAlias, addr, err := address.ParseToShortID(addrStr, constants.GetHRP(a.ctx.NetworkID))
 	if err != nil {
 		return ids.ID{}, ids.ShortID{}, err
 	}
@@ -66,20 +66,6 @@ func (a *addressManager) ParseAddress(addrStr string) (ids.ID, ids.ShortID, erro
 	if err != nil {
 		return ids.ID{}, ids.ShortID{}, err
 	}
-
-	expectedHRP := constants.GetHRP(a.ctx.NetworkID)
-	if hrp != expectedHRP {
-		return ids.ID{}, ids.ShortID{}, fmt.Errorf(
-			"expected hrp %q but got %q",
-			expectedHRP,
-			hrp,
-		)
-	}
-
-	addr, err := ids.ToShortID(addrBytes)
-	if err != nil {
-		return ids.ID{}, ids.ShortID{}, err
-	}
 	return chainID, addr, nil
 }
 
@@ -93,5 +79,5 @@ func (a *addressManager) FormatAddress(chainID ids.ID, addr ids.ShortID) (string
 		return "", err
 	}
 	hrp := constants.GetHRP(a.ctx.NetworkID)
-	return formatting.FormatAddress(chainIDAlias, hrp, addr.Bytes())
+	return address.Format(chainIDAlias, hrp, addr.Bytes())
 }
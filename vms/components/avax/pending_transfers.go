@@ -0,0 +1,86 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+// GetPendingTransfers returns the cross-chain transfers of [addrs] that haven't
+// completed. Exports are the exports of this chain that [queue] couldn't write
+// to shared memory yet, including the ones it gave up on; exports that were written are pending imports of their
+// destination chain. Imports are the UTXOs that were exported to this chain
+// from [sourceChains] and haven't been imported yet, at most [maxUTXOsToFetch]
+// of each chain.
+func GetPendingTransfers(
+	queue *atomic.Queue,
+	utxoManager AtomicUTXOManager,
+	c codec.Manager,
+	addrs ids.ShortSet,
+	sourceChains []ids.ID,
+	encoding formatting.Encoding,
+) (*api.GetPendingTransfersReply, error) {
+	traits := make([][]byte, 0, addrs.Len())
+	for addr := range addrs {
+		copied := addr
+		traits = append(traits, copied[:])
+	}
+
+	queued, err := queue.Queued(traits)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get queued exports: %w", err)
+	}
+	reply := &api.GetPendingTransfersReply{
+		Exports:  make([]api.PendingExport, len(queued)),
+		Imports:  make([]api.PendingImport, len(sourceChains)),
+		Encoding: encoding,
+	}
+	for i, op := range queued {
+		utxos := make([]string, len(op.Elems))
+		for j, elem := range op.Elems {
+			utxos[j], err = formatting.Encode(encoding, elem.Value)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't encode UTXO as string: %w", err)
+			}
+		}
+		reply.Exports[i] = api.PendingExport{
+			TxID:             op.TxID,
+			DestinationChain: op.PeerChainID,
+			UTXOs:            utxos,
+			Status:           op.State.Status.String(),
+			Attempts:         json.Uint32(op.State.Attempts),
+			LastError:        op.State.LastError,
+		}
+	}
+
+	for i, sourceChain := range sourceChains {
+		utxos, _, _, err := utxoManager.GetAtomicUTXOs(sourceChain, addrs, ids.ShortEmpty, ids.Empty, maxUTXOsToFetch)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get UTXOs exported from %s: %w", sourceChain, err)
+		}
+		encodedUTXOs := make([]string, len(utxos))
+		for j, utxo := range utxos {
+			utxoBytes, err := c.Marshal(codecVersion, utxo)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't serialize UTXO %s: %w", utxo.InputID(), err)
+			}
+			encodedUTXOs[j], err = formatting.Encode(encoding, utxoBytes)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't encode UTXO %s as string: %w", utxo.InputID(), err)
+			}
+		}
+		reply.Imports[i] = api.PendingImport{
+			SourceChain: sourceChain,
+			UTXOs:       encodedUTXOs,
+		}
+	}
+	return reply, nil
+}
@@ -5,6 +5,7 @@ package chain
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/cache/metercacher"
@@ -299,6 +300,19 @@ func (s *State) addBlockOutsideConsensus(blk snowman.Block) (snowman.Block, erro
 	return wrappedBlk, nil
 }
 
+// ProcessingBlocks returns the blocks that were verified and haven't been
+// decided yet, in order of increasing height
+func (s *State) ProcessingBlocks() []*BlockWrapper {
+	blks := make([]*BlockWrapper, 0, len(s.verifiedBlocks))
+	for _, blk := range s.verifiedBlocks {
+		blks = append(blks, blk)
+	}
+	sort.Slice(blks, func(i, j int) bool {
+		return blks[i].Height() < blks[j].Height()
+	})
+	return blks
+}
+
 // LastAccepted ...
 func (s *State) LastAccepted() (ids.ID, error) {
 	return s.lastAcceptedBlock.ID(), nil
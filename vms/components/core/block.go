@@ -29,12 +29,17 @@ type Block struct {
 	VM     *SnowmanVM
 }
 
-// Initialize sets [b.bytes] to [bytes], sets [b.id] to hash([b.bytes])
+// Initialize sets [b.bytes] to [bytes], sets [b.id] to hash([b.bytes]) with
+// the container hash of the chain.
 // Checks if [b]'s status is already stored in state. If so, [b] gets that status.
 // Otherwise [b]'s status is Unknown.
 func (b *Block) Initialize(bytes []byte, vm *SnowmanVM) {
 	b.VM = vm
-	b.Metadata.Initialize(bytes)
+	if vm != nil && vm.Ctx != nil {
+		b.Metadata.initialize(bytes, vm.Ctx.ContainerID(bytes))
+	} else {
+		b.Metadata.Initialize(bytes)
+	}
 	b.SetStatus(choices.Unknown) // don't set status until it is queried
 }
 
@@ -31,7 +31,11 @@ func (i *Metadata) SetStatus(status choices.Status) { i.status = status }
 // Initialize sets [i.bytes] to [bytes], sets [i.id] to a hash of [i.bytes]
 // and sets [i.status] to choices.Processing
 func (i *Metadata) Initialize(bytes []byte) {
+	i.initialize(bytes, hashing.ComputeHash256Array(bytes))
+}
+
+func (i *Metadata) initialize(bytes []byte, id ids.ID) {
 	i.bytes = bytes
-	i.id = hashing.ComputeHash256Array(i.bytes)
+	i.id = id
 	i.status = choices.Processing
 }
@@ -0,0 +1,149 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package extension
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/upgrade"
+)
+
+var (
+	errUnknownUpgrade = errors.New("unknown upgrade")
+	errOutOfOrder     = errors.New("extension version doesn't activate after the previous version")
+)
+
+// Context is what an extension is invoked with
+type Context struct {
+	// Rules that are active for the container that invoked the extension
+	Rules upgrade.Rules
+	// Height and timestamp of the container that invoked the extension
+	Height    uint64
+	Timestamp time.Time
+	// State of the chain, which the extension may modify. Changes are
+	// committed with the container that invoked the extension.
+	State database.Database
+}
+
+// Extension is native functionality that a VM adds to its chain
+type Extension interface {
+	// Run the extension with [input]. Returns the output of the extension.
+	Run(ctx *Context, input []byte) ([]byte, error)
+}
+
+// Registry holds the extensions of a VM. Each extension is invoked at an
+// address or by a type of transaction. The extension at an address or for a
+// type of transaction can change over time: each version of it activates
+// with an upgrade in the chain's upgrade schedule, and stays active until the
+// next version does.
+//
+// Extensions are registered when the VM is initialized. The registry isn't
+// safe to modify while extensions are looked up.
+type Registry struct {
+	// Index of each upgrade in the schedule
+	upgrades map[string]int
+
+	schedule  upgrade.Schedule
+	addresses map[ids.ShortID][]version
+	txTypes   map[uint32][]version
+}
+
+// NewRegistry returns an empty registry of extensions that are activated by
+// the upgrades in [schedule]
+func NewRegistry(schedule upgrade.Schedule) *Registry {
+	upgrades := make(map[string]int, len(schedule))
+	for i, u := range schedule {
+		upgrades[u.Name] = i
+	}
+	return &Registry{
+		upgrades:  upgrades,
+		schedule:  schedule,
+		addresses: make(map[ids.ShortID][]version),
+		txTypes:   make(map[uint32][]version),
+	}
+}
+
+// RegisterAddress registers [ext] as the version of the extension at
+// [address] that activates with the upgrade named [activation]. If
+// [activation] is empty, the version is active from genesis. If [ext] is nil,
+// no extension is at [address] once the version activates. Versions must be
+// registered in the order that they activate.
+func (r *Registry) RegisterAddress(address ids.ShortID, activation string, ext Extension) error {
+	vs, err := r.register(r.addresses[address], activation, ext)
+	if err != nil {
+		return fmt.Errorf("couldn't register extension at %s: %w", address, err)
+	}
+	r.addresses[address] = vs
+	return nil
+}
+
+// RegisterTxType registers [ext] as the version of the extension for txs of
+// type [typeID] that activates with the upgrade named [activation]. It
+// behaves like RegisterAddress.
+func (r *Registry) RegisterTxType(typeID uint32, activation string, ext Extension) error {
+	vs, err := r.register(r.txTypes[typeID], activation, ext)
+	if err != nil {
+		return fmt.Errorf("couldn't register extension for tx type %d: %w", typeID, err)
+	}
+	r.txTypes[typeID] = vs
+	return nil
+}
+
+// AtAddress returns the extension at [address] under [rules]. Returns false
+// if there is none.
+func (r *Registry) AtAddress(rules upgrade.Rules, address ids.ShortID) (Extension, bool) {
+	return r.active(rules, r.addresses[address])
+}
+
+// ForTxType returns the extension for txs of type [typeID] under [rules].
+// Returns false if there is none.
+func (r *Registry) ForTxType(rules upgrade.Rules, typeID uint32) (Extension, bool) {
+	return r.active(rules, r.txTypes[typeID])
+}
+
+// register appends the version of an extension that [ext] is to [vs], the
+// versions of the extension that were already registered
+func (r *Registry) register(vs []version, activation string, ext Extension) ([]version, error) {
+	index := -1
+	if activation != "" {
+		i, exists := r.upgrades[activation]
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", errUnknownUpgrade, activation)
+		}
+		index = i
+	}
+	if len(vs) > 0 && vs[len(vs)-1].upgrade >= index {
+		return nil, errOutOfOrder
+	}
+	return append(vs, version{
+		upgrade: index,
+		ext:     ext,
+	}), nil
+}
+
+// active returns the most recent version in [vs] that is active under
+// [rules]
+func (r *Registry) active(rules upgrade.Rules, vs []version) (Extension, bool) {
+	for i := len(vs) - 1; i >= 0; i-- {
+		v := vs[i]
+		if v.upgrade >= 0 && !rules.IsActive(r.schedule[v.upgrade].Name) {
+			continue
+		}
+		return v.ext, v.ext != nil
+	}
+	return nil, false
+}
+
+// version of an extension. The versions of an extension are kept in the order
+// that they activate.
+type version struct {
+	// Index of the upgrade that activates this version, or -1 if it's active
+	// from genesis
+	upgrade int
+	ext     Extension
+}
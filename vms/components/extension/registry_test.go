@@ -0,0 +1,115 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package extension
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/upgrade"
+)
+
+// testExtension writes its input to the state under its key
+type testExtension struct {
+	key []byte
+}
+
+func (e *testExtension) Run(ctx *Context, input []byte) ([]byte, error) {
+	return e.key, ctx.State.Put(e.key, input)
+}
+
+var testSchedule = upgrade.Schedule{
+	{Name: "first", Height: 10},
+	{Name: "second", Height: 20},
+}
+
+func TestRegistryVersions(t *testing.T) {
+	r := NewRegistry(testSchedule)
+	address := ids.GenerateTestShortID()
+	v0, v1 := &testExtension{key: []byte{0}}, &testExtension{key: []byte{1}}
+	if err := r.RegisterAddress(address, "", v0); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterAddress(address, "first", v1); err != nil {
+		t.Fatal(err)
+	}
+	// The extension is removed by the second upgrade
+	if err := r.RegisterAddress(address, "second", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		height   uint64
+		expected Extension
+	}{
+		{height: 0, expected: v0},
+		{height: 9, expected: v0},
+		{height: 10, expected: v1},
+		{height: 20, expected: nil},
+	}
+	for _, test := range tests {
+		rules := testSchedule.Rules(test.height, time.Time{})
+		ext, ok := r.AtAddress(rules, address)
+		if ok != (test.expected != nil) || ext != test.expected {
+			t.Fatalf("unexpected extension at height %d", test.height)
+		}
+	}
+	if _, ok := r.AtAddress(testSchedule.Rules(10, time.Time{}), ids.GenerateTestShortID()); ok {
+		t.Fatal("shouldn't have an extension at an unregistered address")
+	}
+}
+
+func TestRegistryTxTypes(t *testing.T) {
+	r := NewRegistry(testSchedule)
+	ext := &testExtension{key: []byte{2}}
+	if err := r.RegisterTxType(5, "second", ext); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.ForTxType(testSchedule.Rules(10, time.Time{}), 5); ok {
+		t.Fatal("extension shouldn't be active before its upgrade")
+	}
+
+	rules := testSchedule.Rules(20, time.Time{})
+	active, ok := r.ForTxType(rules, 5)
+	if !ok {
+		t.Fatal("extension should be active after its upgrade")
+	}
+	ctx := &Context{
+		Rules:  rules,
+		Height: 20,
+		State:  memdb.New(),
+	}
+	if _, err := active.Run(ctx, []byte{3}); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := ctx.State.Get([]byte{2}); err != nil || value[0] != 3 {
+		t.Fatal("extension should have written its input to the state")
+	}
+}
+
+func TestRegistryErrors(t *testing.T) {
+	r := NewRegistry(testSchedule)
+	address := ids.GenerateTestShortID()
+	if err := r.RegisterAddress(address, "unknown", &testExtension{}); !errors.Is(err, errUnknownUpgrade) {
+		t.Fatalf("expected %s but got %v", errUnknownUpgrade, err)
+	}
+	if err := r.RegisterAddress(address, "second", &testExtension{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterAddress(address, "first", &testExtension{}); !errors.Is(err, errOutOfOrder) {
+		t.Fatalf("expected %s but got %v", errOutOfOrder, err)
+	}
+	if err := r.RegisterAddress(address, "second", &testExtension{}); !errors.Is(err, errOutOfOrder) {
+		t.Fatalf("expected %s but got %v", errOutOfOrder, err)
+	}
+	if err := r.RegisterTxType(1, "", &testExtension{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterTxType(1, "", &testExtension{}); !errors.Is(err, errOutOfOrder) {
+		t.Fatalf("expected %s but got %v", errOutOfOrder, err)
+	}
+}
@@ -0,0 +1,186 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mempool
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var (
+	errDuplicateTx = errors.New("tx is already in the mempool")
+	errMempoolFull = errors.New("mempool is full of txs with at least the same priority")
+
+	_ common.PriorityMempool = &Mempool{}
+)
+
+// Mempool is a common.PriorityMempool that holds at most [maxSize] txs. Txs
+// with the same priority are popped in the order that they were added. If the
+// mempool is full, adding a tx evicts the tx that would be popped last, as
+// long as the added tx has a higher priority than it.
+type Mempool struct {
+	maxSize int
+	// Number of txs that have been added, used to order txs with the same
+	// priority
+	numAdded uint64
+	txs      txHeap
+	txsByID  map[ids.ID]*entry
+}
+
+// New returns an empty mempool that holds at most [maxSize] txs
+func New(maxSize int) *Mempool {
+	return &Mempool{
+		maxSize: maxSize,
+		txsByID: make(map[ids.ID]*entry),
+	}
+}
+
+// AddTx implements the common.PriorityMempool interface
+func (m *Mempool) AddTx(tx common.PendingTx, priority uint64) error {
+	txID := tx.ID()
+	if _, exists := m.txsByID[txID]; exists {
+		return errDuplicateTx
+	}
+	if m.Full() {
+		last := m.last()
+		if last == nil || last.priority >= priority {
+			return errMempoolFull
+		}
+		m.remove(last)
+	}
+
+	e := &entry{
+		tx:       tx,
+		priority: priority,
+		order:    m.numAdded,
+	}
+	m.numAdded++
+	heap.Push(&m.txs, e)
+	m.txsByID[txID] = e
+	return nil
+}
+
+// PopTx implements the common.PriorityMempool interface
+func (m *Mempool) PopTx() (common.PendingTx, bool) {
+	if len(m.txs) == 0 {
+		return nil, false
+	}
+	e := heap.Pop(&m.txs).(*entry)
+	delete(m.txsByID, e.tx.ID())
+	return e.tx, true
+}
+
+// NumTxs implements the common.PriorityMempool interface
+func (m *Mempool) NumTxs() int { return len(m.txs) }
+
+// MempoolTxs implements the common.Mempool interface. The txs are returned in
+// the order that they would be popped.
+func (m *Mempool) MempoolTxs() []common.MempoolTx {
+	entries := make(txHeap, len(m.txs))
+	copy(entries, m.txs)
+	sort.Slice(entries, entries.Less)
+
+	txs := make([]common.MempoolTx, len(entries))
+	for i, e := range entries {
+		txs[i] = common.MempoolTx{
+			ID:   e.tx.ID(),
+			Size: len(e.tx.Bytes()),
+		}
+	}
+	return txs
+}
+
+// EvictTx implements the common.Mempool interface
+func (m *Mempool) EvictTx(txID ids.ID) bool {
+	e, exists := m.txsByID[txID]
+	if !exists {
+		return false
+	}
+	m.remove(e)
+	return true
+}
+
+// Has returns true if [txID] is in the mempool
+func (m *Mempool) Has(txID ids.ID) bool {
+	_, exists := m.txsByID[txID]
+	return exists
+}
+
+// LowestPriority returns the priority of the tx that would be popped last.
+// Returns false if the mempool is empty.
+func (m *Mempool) LowestPriority() (uint64, bool) {
+	last := m.last()
+	if last == nil {
+		return 0, false
+	}
+	return last.priority, true
+}
+
+// Full returns true if the mempool holds as many txs as it can
+func (m *Mempool) Full() bool { return len(m.txs) >= m.maxSize }
+
+// last returns the entry that would be popped last, or nil if the mempool is
+// empty
+func (m *Mempool) last() *entry {
+	var last *entry
+	for _, e := range m.txs {
+		if last == nil || last.before(e) {
+			last = e
+		}
+	}
+	return last
+}
+
+func (m *Mempool) remove(e *entry) {
+	heap.Remove(&m.txs, e.index)
+	delete(m.txsByID, e.tx.ID())
+}
+
+type entry struct {
+	tx       common.PendingTx
+	priority uint64
+	order    uint64
+	// Index of this entry in the heap
+	index int
+}
+
+// before returns true if [e] is popped before [other]
+func (e *entry) before(other *entry) bool {
+	if e.priority != other.priority {
+		return e.priority > other.priority
+	}
+	return e.order < other.order
+}
+
+// txHeap implements heap.Interface. The entry that is popped first is at the
+// root.
+type txHeap []*entry
+
+func (h txHeap) Len() int           { return len(h) }
+func (h txHeap) Less(i, j int) bool { return h[i].before(h[j]) }
+
+func (h txHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *txHeap) Push(x interface{}) {
+	e := x.(*entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *txHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
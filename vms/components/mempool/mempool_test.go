@@ -0,0 +1,110 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mempool
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+type testTx struct {
+	id    ids.ID
+	bytes []byte
+}
+
+func newTestTx() *testTx {
+	return &testTx{
+		id:    ids.GenerateTestID(),
+		bytes: []byte{1, 2, 3},
+	}
+}
+
+func (tx *testTx) ID() ids.ID    { return tx.id }
+func (tx *testTx) Bytes() []byte { return tx.bytes }
+
+func TestMempoolPopsByPriority(t *testing.T) {
+	m := New(10)
+	low, first, second := newTestTx(), newTestTx(), newTestTx()
+	if err := m.AddTx(low, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddTx(first, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddTx(second, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddTx(first, 3); err == nil {
+		t.Fatal("should have failed to add a duplicate tx")
+	}
+
+	summaries := m.MempoolTxs()
+	for i, tx := range []*testTx{first, second, low} {
+		if summaries[i].ID != tx.ID() {
+			t.Fatalf("expected tx %s at index %d but got %s", tx.ID(), i, summaries[i].ID)
+		}
+		if popped, ok := m.PopTx(); !ok || popped.ID() != tx.ID() {
+			t.Fatalf("expected to pop tx %s", tx.ID())
+		}
+	}
+	if _, ok := m.PopTx(); ok {
+		t.Fatal("shouldn't have popped from an empty mempool")
+	}
+}
+
+func TestMempoolFull(t *testing.T) {
+	m := New(2)
+	high, low, lower := newTestTx(), newTestTx(), newTestTx()
+	if err := m.AddTx(high, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddTx(low, 1); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Full() {
+		t.Fatal("mempool should be full")
+	}
+	if lowest, ok := m.LowestPriority(); !ok || lowest != 1 {
+		t.Fatalf("expected the lowest priority to be 1 but got %d", lowest)
+	}
+	if err := m.AddTx(lower, 1); err == nil {
+		t.Fatal("shouldn't have added a tx that doesn't outrank any tx in a full mempool")
+	}
+
+	higher := newTestTx()
+	if err := m.AddTx(higher, 6); err != nil {
+		t.Fatal(err)
+	}
+	if m.NumTxs() != 2 {
+		t.Fatalf("expected 2 txs but got %d", m.NumTxs())
+	}
+	if m.Has(low.ID()) {
+		t.Fatal("the lowest priority tx should have been evicted")
+	}
+	if popped, _ := m.PopTx(); popped.ID() != higher.ID() {
+		t.Fatal("expected to pop the highest priority tx")
+	}
+}
+
+func TestMempoolEvict(t *testing.T) {
+	m := New(10)
+	txs := []*testTx{newTestTx(), newTestTx(), newTestTx()}
+	for i, tx := range txs {
+		if err := m.AddTx(tx, uint64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !m.EvictTx(txs[1].ID()) {
+		t.Fatal("should have evicted the tx")
+	}
+	if m.EvictTx(txs[1].ID()) {
+		t.Fatal("shouldn't evict a tx that isn't in the mempool")
+	}
+	for _, tx := range []*testTx{txs[2], txs[0]} {
+		if popped, ok := m.PopTx(); !ok || popped.ID() != tx.ID() {
+			t.Fatalf("expected to pop tx %s", tx.ID())
+		}
+	}
+}
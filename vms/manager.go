@@ -43,8 +43,14 @@ type Manager interface {
 	// Return the aliases associated with a VM
 	Aliases(ids.ID) []string
 
+	// Return the first alias of a VM
+	PrimaryAlias(ids.ID) (string, error)
+
 	// Give an alias to a VM
 	Alias(ids.ID, string) error
+
+	// Remove an alias from the VM it was given to
+	RemoveAlias(string) error
 }
 
 // Implements Manager
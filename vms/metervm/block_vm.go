@@ -15,17 +15,22 @@ import (
 	"github.com/ava-labs/avalanchego/utils/timer"
 )
 
-var _ block.ChainVM = &blockVM{}
+var (
+	_ block.ChainVM          = &blockVM{}
+	_ common.StateSyncableVM = &blockVM{}
+)
 
 func NewBlockVM(vm block.ChainVM) block.ChainVM {
 	return &blockVM{
-		ChainVM: vm,
+		ChainVM:     vm,
+		stateSyncer: newStateSyncer(vm),
 	}
 }
 
 type blockVM struct {
 	block.ChainVM
 	blockMetrics
+	stateSyncer
 	clock timer.Clock
 }
 
@@ -38,7 +43,11 @@ func (vm *blockVM) Initialize(
 	toEngine chan<- common.Message,
 	fxs []*common.Fx,
 ) error {
-	if err := vm.blockMetrics.Initialize(fmt.Sprintf("metervm_%s", ctx.Namespace), ctx.Metrics); err != nil {
+	namespace := fmt.Sprintf("metervm_%s", ctx.Namespace)
+	if err := vm.blockMetrics.Initialize(namespace, ctx.Metrics); err != nil {
+		return err
+	}
+	if err := vm.stateSyncer.stateSyncMetrics.Initialize(namespace, ctx.Metrics); err != nil {
 		return err
 	}
 
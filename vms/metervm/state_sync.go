@@ -0,0 +1,137 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metervm
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/metric"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var (
+	errStateSyncUnsupported = errors.New("vm doesn't support state sync")
+
+	_ common.StateSyncableVM = &stateSyncer{}
+)
+
+type stateSyncMetrics struct {
+	stateSyncEnabled,
+	getLastStateSummary,
+	getStateSummary,
+	parseStateSummary,
+	fetchState,
+	commitState prometheus.Histogram
+}
+
+func (m *stateSyncMetrics) Initialize(
+	namespace string,
+	registerer prometheus.Registerer,
+) error {
+	m.stateSyncEnabled = metric.NewNanosecondsLatencyMetric(namespace, "state_sync_enabled")
+	m.getLastStateSummary = metric.NewNanosecondsLatencyMetric(namespace, "get_last_state_summary")
+	m.getStateSummary = metric.NewNanosecondsLatencyMetric(namespace, "get_state_summary")
+	m.parseStateSummary = metric.NewNanosecondsLatencyMetric(namespace, "parse_state_summary")
+	m.fetchState = metric.NewNanosecondsLatencyMetric(namespace, "fetch_state")
+	m.commitState = metric.NewNanosecondsLatencyMetric(namespace, "commit_state")
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.stateSyncEnabled),
+		registerer.Register(m.getLastStateSummary),
+		registerer.Register(m.getStateSummary),
+		registerer.Register(m.parseStateSummary),
+		registerer.Register(m.fetchState),
+		registerer.Register(m.commitState),
+	)
+	return errs.Err
+}
+
+// stateSyncer forwards state sync to the wrapped VM if it supports it. The
+// wrapper always implements StateSyncableVM, so if the wrapped VM doesn't,
+// state sync is reported as disabled and the chain bootstraps as it always
+// did.
+type stateSyncer struct {
+	stateSyncMetrics
+	// Nil if the wrapped VM doesn't support state sync
+	vm    common.StateSyncableVM
+	clock timer.Clock
+}
+
+func newStateSyncer(vm interface{}) stateSyncer {
+	ssVM, _ := vm.(common.StateSyncableVM)
+	return stateSyncer{
+		vm: ssVM,
+	}
+}
+
+func (s *stateSyncer) StateSyncEnabled() (bool, error) {
+	if s.vm == nil {
+		return false, nil
+	}
+	start := s.clock.Time()
+	enabled, err := s.vm.StateSyncEnabled()
+	end := s.clock.Time()
+	s.stateSyncMetrics.stateSyncEnabled.Observe(float64(end.Sub(start)))
+	return enabled, err
+}
+
+func (s *stateSyncer) GetLastStateSummary() (common.Summary, error) {
+	if s.vm == nil {
+		return nil, errStateSyncUnsupported
+	}
+	start := s.clock.Time()
+	summary, err := s.vm.GetLastStateSummary()
+	end := s.clock.Time()
+	s.stateSyncMetrics.getLastStateSummary.Observe(float64(end.Sub(start)))
+	return summary, err
+}
+
+func (s *stateSyncer) GetStateSummary(height uint64) (common.Summary, error) {
+	if s.vm == nil {
+		return nil, errStateSyncUnsupported
+	}
+	start := s.clock.Time()
+	summary, err := s.vm.GetStateSummary(height)
+	end := s.clock.Time()
+	s.stateSyncMetrics.getStateSummary.Observe(float64(end.Sub(start)))
+	return summary, err
+}
+
+func (s *stateSyncer) ParseStateSummary(summaryBytes []byte) (common.Summary, error) {
+	if s.vm == nil {
+		return nil, errStateSyncUnsupported
+	}
+	start := s.clock.Time()
+	summary, err := s.vm.ParseStateSummary(summaryBytes)
+	end := s.clock.Time()
+	s.stateSyncMetrics.parseStateSummary.Observe(float64(end.Sub(start)))
+	return summary, err
+}
+
+func (s *stateSyncer) FetchState(summary common.Summary) error {
+	if s.vm == nil {
+		return errStateSyncUnsupported
+	}
+	start := s.clock.Time()
+	err := s.vm.FetchState(summary)
+	end := s.clock.Time()
+	s.stateSyncMetrics.fetchState.Observe(float64(end.Sub(start)))
+	return err
+}
+
+func (s *stateSyncer) CommitState(summary common.Summary) error {
+	if s.vm == nil {
+		return errStateSyncUnsupported
+	}
+	start := s.clock.Time()
+	err := s.vm.CommitState(summary)
+	end := s.clock.Time()
+	s.stateSyncMetrics.commitState.Observe(float64(end.Sub(start)))
+	return err
+}
@@ -6,6 +6,8 @@ package metervm
 import (
 	"fmt"
 
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
@@ -15,17 +17,23 @@ import (
 	"github.com/ava-labs/avalanchego/utils/timer"
 )
 
-var _ vertex.DAGVM = &vertexVM{}
+var (
+	_ vertex.DAGVM           = &vertexVM{}
+	_ vertex.BatchCommitter  = &vertexVM{}
+	_ common.StateSyncableVM = &vertexVM{}
+)
 
 func NewVertexVM(vm vertex.DAGVM) vertex.DAGVM {
 	return &vertexVM{
-		DAGVM: vm,
+		DAGVM:       vm,
+		stateSyncer: newStateSyncer(vm),
 	}
 }
 
 type vertexVM struct {
 	vertex.DAGVM
 	vertexMetrics
+	stateSyncer
 	clock timer.Clock
 }
 
@@ -38,7 +46,11 @@ func (vm *vertexVM) Initialize(
 	toEngine chan<- common.Message,
 	fxs []*common.Fx,
 ) error {
-	if err := vm.vertexMetrics.Initialize(fmt.Sprintf("metervm_%s", ctx.Namespace), ctx.Metrics); err != nil {
+	namespace := fmt.Sprintf("metervm_%s", ctx.Namespace)
+	if err := vm.vertexMetrics.Initialize(namespace, ctx.Metrics); err != nil {
+		return err
+	}
+	if err := vm.stateSyncer.stateSyncMetrics.Initialize(namespace, ctx.Metrics); err != nil {
 		return err
 	}
 
@@ -68,3 +80,15 @@ func (vm *vertexVM) GetTx(txID ids.ID) (snowstorm.Tx, error) {
 	vm.vertexMetrics.get.Observe(float64(end.Sub(start)))
 	return tx, err
 }
+
+// CommitWith forwards to the wrapped VM if it holds back writes, and otherwise
+// writes [batches] on their own
+func (vm *vertexVM) CommitWith(batches ...database.Batch) error {
+	if committer, ok := vm.DAGVM.(vertex.BatchCommitter); ok {
+		return committer.CommitWith(batches...)
+	}
+	if len(batches) == 0 {
+		return nil
+	}
+	return atomic.WriteAll(batches[0], batches[1:]...)
+}
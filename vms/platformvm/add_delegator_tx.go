@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
@@ -170,7 +171,7 @@ func (tx *UnsignedAddDelegatorTx) SemanticVerify(
 			return nil, nil, nil, nil, tempError{
 				fmt.Errorf(
 					"failed to find whether %s is a validator: %w",
-					tx.Validator.NodeID.PrefixedString(constants.NodeIDPrefix),
+					address.FormatNodeID(tx.Validator.NodeID),
 					err,
 				),
 			}
@@ -201,7 +202,7 @@ func (tx *UnsignedAddDelegatorTx) SemanticVerify(
 				return nil, nil, nil, nil, tempError{
 					fmt.Errorf(
 						"failed to find whether %s is a validator: %w",
-						tx.Validator.NodeID.PrefixedString(constants.NodeIDPrefix),
+						address.FormatNodeID(tx.Validator.NodeID),
 						err,
 					),
 				}
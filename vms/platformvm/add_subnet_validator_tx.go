@@ -12,8 +12,8 @@ import (
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
-	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 )
@@ -143,7 +143,7 @@ func (tx *UnsignedAddSubnetValidatorTx) SemanticVerify(
 			return nil, nil, nil, nil, tempError{
 				fmt.Errorf(
 					"failed to find whether %s is a validator: %w",
-					tx.Validator.NodeID.PrefixedString(constants.NodeIDPrefix),
+					address.FormatNodeID(tx.Validator.NodeID),
 					err,
 				),
 			}
@@ -176,7 +176,7 @@ func (tx *UnsignedAddSubnetValidatorTx) SemanticVerify(
 				return nil, nil, nil, nil, tempError{
 					fmt.Errorf(
 						"failed to find whether %s is a validator: %w",
-						tx.Validator.NodeID.PrefixedString(constants.NodeIDPrefix),
+						address.FormatNodeID(tx.Validator.NodeID),
 						err,
 					),
 				}
@@ -12,8 +12,8 @@ import (
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
-	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -187,7 +187,7 @@ func (tx *UnsignedAddValidatorTx) SemanticVerify(
 			return nil, nil, nil, nil, permError{
 				fmt.Errorf(
 					"%s is already a primary network validator",
-					tx.Validator.NodeID.PrefixedString(constants.NodeIDPrefix),
+					address.FormatNodeID(tx.Validator.NodeID),
 				),
 			}
 		}
@@ -195,7 +195,7 @@ func (tx *UnsignedAddValidatorTx) SemanticVerify(
 			return nil, nil, nil, nil, tempError{
 				fmt.Errorf(
 					"failed to find whether %s is a validator: %w",
-					tx.Validator.NodeID.PrefixedString(constants.NodeIDPrefix),
+					address.FormatNodeID(tx.Validator.NodeID),
 					err,
 				),
 			}
@@ -207,7 +207,7 @@ func (tx *UnsignedAddValidatorTx) SemanticVerify(
 			return nil, nil, nil, nil, permError{
 				fmt.Errorf(
 					"%s is about to become a primary network validator",
-					tx.Validator.NodeID.PrefixedString(constants.NodeIDPrefix),
+					address.FormatNodeID(tx.Validator.NodeID),
 				),
 			}
 		}
@@ -215,7 +215,7 @@ func (tx *UnsignedAddValidatorTx) SemanticVerify(
 			return nil, nil, nil, nil, tempError{
 				fmt.Errorf(
 					"failed to find whether %s is about to become a validator: %w",
-					tx.Validator.NodeID.PrefixedString(constants.NodeIDPrefix),
+					address.FormatNodeID(tx.Validator.NodeID),
 					err,
 				),
 			}
@@ -153,7 +153,7 @@ func (ab *AtomicBlock) Accept() error {
 			err,
 		)
 	}
-	if err := tx.Accept(ab.vm.ctx, batch); err != nil {
+	if err := tx.Accept(ab.vm, batch); err != nil {
 		return fmt.Errorf(
 			"failed to atomically accept tx %s in block %s: %w",
 			tx.ID(),
@@ -301,7 +301,7 @@ func (cs *currentStakerChainStateImpl) primaryValidatorSet() (validators.Set, er
 		if err != nil {
 			return nil, err
 		}
-		if err := vdrs.AddWeight(nodeID, vdrWeight); err != nil {
+		if err := vdrs.AddWeight(ids.NodeIDFromShortID(nodeID), vdrWeight); err != nil {
 			return nil, err
 		}
 	}
@@ -317,7 +317,7 @@ func (cs *currentStakerChainStateImpl) subnetValidatorSet(subnetID ids.ID) (vali
 		if !exists {
 			continue
 		}
-		if err := vdrs.AddWeight(nodeID, subnetVDR.Validator.Wght); err != nil {
+		if err := vdrs.AddWeight(ids.NodeIDFromShortID(nodeID), subnetVDR.Validator.Wght); err != nil {
 			return nil, err
 		}
 	}
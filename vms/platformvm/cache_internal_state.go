@@ -39,7 +39,10 @@ var (
 	utxoPrefix            = []byte("utxo")
 	subnetPrefix          = []byte("subnet")
 	chainPrefix           = []byte("chain")
+	blsKeyPrefix          = []byte("blsKey")
 	singletonPrefix       = []byte("singleton")
+	validatorDiffsPrefix  = []byte("validatorDiffs")
+	timestampHeightPrefix = []byte("timestampHeight")
 
 	timestampKey     = []byte("timestamp")
 	currentSupplyKey = []byte("current supply")
@@ -47,6 +50,9 @@ var (
 	initializedKey   = []byte("initialized")
 	migratedKey      = []byte("migrated")
 
+	historyStartHeightKey = []byte("history start height")
+	historyStartTimeKey   = []byte("history start time")
+
 	errWrongNetworkID = errors.New("tx has wrong network ID")
 
 	_ InternalState = &internalStateImpl{}
@@ -95,6 +101,17 @@ type InternalState interface {
 
 	SetMigrated() error
 	IsMigrated() (bool, error)
+
+	// GetBLSKeys returns the tx that registered the BLS key of each node that
+	// registered one
+	GetBLSKeys() map[ids.ShortID]*Tx
+
+	// GetValidatorWeights returns the weight of each validator of [subnetID]
+	// after the block at [height] was accepted
+	GetValidatorWeights(subnetID ids.ID, height uint64) (map[ids.ShortID]uint64, error)
+	// GetHeightAtTime returns the height of the last accepted block at which
+	// the chain time was at most [timestamp]
+	GetHeightAtTime(timestamp time.Time) (uint64, error)
 }
 
 /*
@@ -137,12 +154,21 @@ type InternalState interface {
  * | '-. subnetID
  * |   '-. list
  * |     '-- txID -> nil
+ * |-. blsKeys
+ * | '-- nodeID -> txID of the last registration of the node's BLS key
+ * |-. validatorDiffs
+ * | '-. subnetID
+ * |   '-- height -> validator weight diffs
+ * |-. timestampHeight
+ * | '-- timestamp -> height of the first block with the timestamp
  * '-. singletons
  *   |-- initializedKey -> nil
  *   |-- migratedKey -> nil
  *   |-- timestampKey -> timestamp
  *   |-- currentSupplyKey -> currentSupply
- *   '-- lastAcceptedKey -> lastAccepted
+ *   |-- lastAcceptedKey -> lastAccepted
+ *   |-- historyStartHeightKey -> first height validator sets are indexed at
+ *   '-- historyStartTimeKey -> first time validator sets are indexed at
  */
 type internalStateImpl struct {
 	vm *VM
@@ -201,10 +227,20 @@ type internalStateImpl struct {
 	chainDBCache cache.Cacher     // cache of subnetID -> linkedDB
 	chainDB      database.Database
 
+	blsKeys      map[ids.ShortID]*Tx    // map of nodeID -> the tx that registered the node's BLS key
+	blsKeyOwners map[string]ids.ShortID // map of BLS public key -> the nodeID that registered it
+	addedBLSKeys map[ids.ShortID]*Tx    // map of nodeID -> the newly registered BLS key
+	blsKeyDB     database.Database
+
 	originalTimestamp, timestamp         time.Time
 	originalCurrentSupply, currentSupply uint64
 	originalLastAccepted, lastAccepted   ids.ID
 	singletonDB                          database.Database
+
+	validatorDiffsDB   database.Database
+	timestampHeightDB  database.Database
+	historyStartHeight uint64
+	historyStartTime   time.Time
 }
 
 type stateTx struct {
@@ -277,7 +313,15 @@ func newInternalStateDatabases(vm *VM, db database.Database) *internalStateImpl
 		addedChains: make(map[ids.ID][]*Tx),
 		chainDB:     prefixdb.New(chainPrefix, baseDB),
 
+		blsKeys:      make(map[ids.ShortID]*Tx),
+		blsKeyOwners: make(map[string]ids.ShortID),
+		addedBLSKeys: make(map[ids.ShortID]*Tx),
+		blsKeyDB:     prefixdb.New(blsKeyPrefix, baseDB),
+
 		singletonDB: prefixdb.New(singletonPrefix, baseDB),
+
+		validatorDiffsDB:  prefixdb.New(validatorDiffsPrefix, baseDB),
+		timestampHeightDB: prefixdb.New(timestampHeightPrefix, baseDB),
 	}
 }
 
@@ -501,6 +545,40 @@ func (st *internalStateImpl) getChainDB(subnetID ids.ID) linkeddb.LinkedDB {
 	return chainDB
 }
 
+func (st *internalStateImpl) GetBLSKey(nodeID ids.ShortID) (*Tx, error) {
+	tx, exists := st.blsKeys[nodeID]
+	if !exists {
+		return nil, database.ErrNotFound
+	}
+	return tx, nil
+}
+
+func (st *internalStateImpl) GetBLSKeyOwner(publicKey []byte) (ids.ShortID, error) {
+	nodeID, exists := st.blsKeyOwners[string(publicKey)]
+	if !exists {
+		return ids.ShortID{}, database.ErrNotFound
+	}
+	return nodeID, nil
+}
+
+func (st *internalStateImpl) AddBLSKey(registerBLSKeyTx *Tx) {
+	nodeID := registerBLSKeyTx.UnsignedTx.(*UnsignedRegisterBLSKeyTx).nodeID
+	st.addedBLSKeys[nodeID] = registerBLSKeyTx
+	st.setBLSKey(nodeID, registerBLSKeyTx)
+}
+
+func (st *internalStateImpl) GetBLSKeys() map[ids.ShortID]*Tx { return st.blsKeys }
+
+// setBLSKey replaces the BLS key of [nodeID] with the key registered by [tx]
+func (st *internalStateImpl) setBLSKey(nodeID ids.ShortID, tx *Tx) {
+	if prevTx, exists := st.blsKeys[nodeID]; exists {
+		prevKey := prevTx.UnsignedTx.(*UnsignedRegisterBLSKeyTx).Registration.PublicKey
+		delete(st.blsKeyOwners, string(prevKey))
+	}
+	st.blsKeys[nodeID] = tx
+	st.blsKeyOwners[string(tx.UnsignedTx.(*UnsignedRegisterBLSKeyTx).Registration.PublicKey)] = nodeID
+}
+
 func (st *internalStateImpl) GetTx(txID ids.ID) (*Tx, Status, error) {
 	if tx, exists := st.addedTxs[txID]; exists {
 		return tx.tx, tx.status, nil
@@ -712,6 +790,9 @@ func (st *internalStateImpl) Commit() error {
 }
 
 func (st *internalStateImpl) CommitBatch() (database.Batch, error) {
+	if err := st.writeValidatorHistory(); err != nil {
+		return nil, err
+	}
 	if err := st.writeCurrentStakers(); err != nil {
 		return nil, err
 	}
@@ -739,6 +820,9 @@ func (st *internalStateImpl) CommitBatch() (database.Batch, error) {
 	if err := st.writeChains(); err != nil {
 		return nil, err
 	}
+	if err := st.writeBLSKeys(); err != nil {
+		return nil, err
+	}
 	if err := st.writeSingletons(); err != nil {
 		return nil, err
 	}
@@ -763,7 +847,10 @@ func (st *internalStateImpl) Close() error {
 		st.utxoDB.Close(),
 		st.subnetBaseDB.Close(),
 		st.chainDB.Close(),
+		st.blsKeyDB.Close(),
 		st.singletonDB.Close(),
+		st.validatorDiffsDB.Close(),
+		st.timestampHeightDB.Close(),
 		st.baseDB.Close(),
 	)
 	return errs.Err
@@ -1015,6 +1102,17 @@ func (st *internalStateImpl) writeChains() error {
 	return nil
 }
 
+func (st *internalStateImpl) writeBLSKeys() error {
+	for nodeID, tx := range st.addedBLSKeys {
+		txID := tx.ID()
+		if err := st.blsKeyDB.Put(nodeID[:], txID[:]); err != nil {
+			return err
+		}
+		delete(st.addedBLSKeys, nodeID)
+	}
+	return nil
+}
+
 func (st *internalStateImpl) writeSingletons() error {
 	if !st.originalTimestamp.Equal(st.timestamp) {
 		if err := database.PutTimestamp(st.singletonDB, timestampKey, st.timestamp); err != nil {
@@ -1047,7 +1145,34 @@ func (st *internalStateImpl) load() error {
 	if err := st.loadPendingValidators(); err != nil {
 		return err
 	}
-	return nil
+	if err := st.loadBLSKeys(); err != nil {
+		return err
+	}
+	return st.loadValidatorHistory()
+}
+
+func (st *internalStateImpl) loadBLSKeys() error {
+	blsKeyIt := st.blsKeyDB.NewIterator()
+	defer blsKeyIt.Release()
+	for blsKeyIt.Next() {
+		nodeID, err := ids.ToShortID(blsKeyIt.Key())
+		if err != nil {
+			return err
+		}
+		txID, err := ids.ToID(blsKeyIt.Value())
+		if err != nil {
+			return err
+		}
+		tx, _, err := st.GetTx(txID)
+		if err != nil {
+			return err
+		}
+		if _, ok := tx.UnsignedTx.(*UnsignedRegisterBLSKeyTx); !ok {
+			return errWrongTxType
+		}
+		st.setBLSKey(nodeID, tx)
+	}
+	return blsKeyIt.Error()
 }
 
 func (st *internalStateImpl) loadSingletons() error {
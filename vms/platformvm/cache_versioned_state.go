@@ -4,6 +4,7 @@
 package platformvm
 
 import (
+	"bytes"
 	"time"
 
 	"github.com/ava-labs/avalanchego/database"
@@ -49,6 +50,14 @@ type MutableState interface {
 	GetChains(subnetID ids.ID) ([]*Tx, error)
 	AddChain(createChainTx *Tx)
 
+	// GetBLSKey returns the tx that registered the BLS key of [nodeID]
+	GetBLSKey(nodeID ids.ShortID) (*Tx, error)
+	// GetBLSKeyOwner returns the node that registered [publicKey]
+	GetBLSKeyOwner(publicKey []byte) (ids.ShortID, error)
+	// AddBLSKey replaces the BLS key of the node that issued
+	// [registerBLSKeyTx], which must have been verified
+	AddBLSKey(registerBLSKeyTx *Tx)
+
 	GetTx(txID ids.ID) (*Tx, Status, error)
 	AddTx(tx *Tx, status Status)
 
@@ -79,6 +88,9 @@ type versionedStateImpl struct {
 	addedChains  map[ids.ID][]*Tx
 	cachedChains map[ids.ID][]*Tx
 
+	// map of nodeID -> the tx that registered the node's BLS key
+	addedBLSKeys map[ids.ShortID]*Tx
+
 	// map of txID -> []*UTXO
 	addedRewardUTXOs map[ids.ID][]*avax.UTXO
 
@@ -211,6 +223,38 @@ func (vs *versionedStateImpl) AddChain(createChainTx *Tx) {
 	vs.cachedChains[tx.SubnetID] = append(cachedChains, createChainTx)
 }
 
+func (vs *versionedStateImpl) GetBLSKey(nodeID ids.ShortID) (*Tx, error) {
+	if tx, exists := vs.addedBLSKeys[nodeID]; exists {
+		return tx, nil
+	}
+	return vs.parentState.GetBLSKey(nodeID)
+}
+
+func (vs *versionedStateImpl) GetBLSKeyOwner(publicKey []byte) (ids.ShortID, error) {
+	for nodeID, tx := range vs.addedBLSKeys {
+		if bytes.Equal(tx.UnsignedTx.(*UnsignedRegisterBLSKeyTx).Registration.PublicKey, publicKey) {
+			return nodeID, nil
+		}
+	}
+	owner, err := vs.parentState.GetBLSKeyOwner(publicKey)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	if _, replaced := vs.addedBLSKeys[owner]; replaced {
+		// The owner registered another key since
+		return ids.ShortID{}, database.ErrNotFound
+	}
+	return owner, nil
+}
+
+func (vs *versionedStateImpl) AddBLSKey(registerBLSKeyTx *Tx) {
+	nodeID := registerBLSKeyTx.UnsignedTx.(*UnsignedRegisterBLSKeyTx).nodeID
+	if vs.addedBLSKeys == nil {
+		vs.addedBLSKeys = make(map[ids.ShortID]*Tx)
+	}
+	vs.addedBLSKeys[nodeID] = registerBLSKeyTx
+}
+
 func (vs *versionedStateImpl) GetTx(txID ids.ID) (*Tx, Status, error) {
 	tx, exists := vs.addedTxs[txID]
 	if !exists {
@@ -309,6 +353,9 @@ func (vs *versionedStateImpl) Apply(is InternalState) {
 			is.AddChain(chain)
 		}
 	}
+	for _, tx := range vs.addedBLSKeys {
+		is.AddBLSKey(tx)
+	}
 	for _, tx := range vs.addedTxs {
 		is.AddTx(tx.tx, tx.status)
 	}
@@ -52,6 +52,19 @@ func (c *Client) ImportKey(user api.UserPass, privateKey string) (string, error)
 	return res.Address, err
 }
 
+// ImportMnemonicKey imports the key at [addressIndex] derived from the BIP-39
+// [mnemonic], which is protected by [passphrase], to [user]'s keystore
+func (c *Client) ImportMnemonicKey(user api.UserPass, mnemonic, passphrase string, addressIndex uint32) (string, error) {
+	res := &api.JSONAddress{}
+	err := c.requester.SendRequest("importKey", &ImportKeyArgs{
+		UserPass:           user,
+		Mnemonic:           mnemonic,
+		MnemonicPassphrase: passphrase,
+		AddressIndex:       cjson.Uint32(addressIndex),
+	}, res)
+	return res.Address, err
+}
+
 // GetBalance returns the balance of [address] on the P Chain
 func (c *Client) GetBalance(address string) (*GetBalanceResponse, error) {
 	res := &GetBalanceResponse{}
@@ -109,6 +122,19 @@ func (c *Client) GetAtomicUTXOs(addrs []string, sourceChain string, limit uint32
 	return utxos, res.EndIndex, nil
 }
 
+// GetPendingTransfers returns the cross-chain transfers of [addrs] that haven't
+// completed. The UTXOs exported to this chain are returned from
+// [sourceChains].
+func (c *Client) GetPendingTransfers(addrs []string, sourceChains []string) (*api.GetPendingTransfersReply, error) {
+	res := &api.GetPendingTransfersReply{}
+	err := c.requester.SendRequest("getPendingTransfers", &api.GetPendingTransfersArgs{
+		Addresses:    addrs,
+		SourceChains: sourceChains,
+		Encoding:     formatting.Hex,
+	}, res)
+	return res, err
+}
+
 // GetSubnets returns information about the specified subnets
 func (c *Client) GetSubnets(ids []ids.ID) ([]APISubnet, error) {
 	res := &GetSubnetsResponse{}
@@ -130,20 +156,83 @@ func (c *Client) GetStakingAssetID(subnetID ids.ID) (ids.ID, error) {
 
 // GetCurrentValidators returns the list of current validators for subnet with ID [subnetID]
 func (c *Client) GetCurrentValidators(subnetID ids.ID) ([]interface{}, error) {
+	validators, _, err := c.GetCurrentValidatorsPage(subnetID, 0, "")
+	return validators, err
+}
+
+// GetCurrentValidatorsPage returns up to [limit] current validators for subnet
+// with ID [subnetID] after [startCursor], and the cursor to fetch the next page
+// from
+func (c *Client) GetCurrentValidatorsPage(subnetID ids.ID, limit uint32, startCursor string) ([]interface{}, string, error) {
 	res := &GetCurrentValidatorsReply{}
 	err := c.requester.SendRequest("getCurrentValidators", &GetCurrentValidatorsArgs{
-		SubnetID: subnetID,
+		SubnetID:    subnetID,
+		Limit:       cjson.Uint32(limit),
+		StartCursor: startCursor,
 	}, res)
-	return res.Validators, err
+	return res.Validators, res.EndCursor, err
+}
+
+// QueryCurrentValidators returns up to [limit] current validators for subnet
+// with ID [subnetID] after [startCursor] that match [query], in the order
+// given by [query], and the cursor to fetch the next page from
+func (c *Client) QueryCurrentValidators(
+	subnetID ids.ID,
+	nodeIDs []string,
+	query APIValidatorQuery,
+	limit uint32,
+	startCursor string,
+) ([]interface{}, string, error) {
+	res := &GetCurrentValidatorsReply{}
+	err := c.requester.SendRequest("getCurrentValidators", &GetCurrentValidatorsArgs{
+		SubnetID:          subnetID,
+		NodeIDs:           nodeIDs,
+		Limit:             cjson.Uint32(limit),
+		StartCursor:       startCursor,
+		APIValidatorQuery: query,
+	}, res)
+	return res.Validators, res.EndCursor, err
 }
 
 // GetPendingValidators returns the list of pending validators for subnet with ID [subnetID]
 func (c *Client) GetPendingValidators(subnetID ids.ID) ([]interface{}, []interface{}, error) {
+	validators, delegators, _, err := c.GetPendingValidatorsPage(subnetID, 0, "")
+	return validators, delegators, err
+}
+
+// GetPendingValidatorsPage returns up to [limit] pending validators and
+// delegators for subnet with ID [subnetID] after [startCursor], and the cursor
+// to fetch the next page from
+func (c *Client) GetPendingValidatorsPage(subnetID ids.ID, limit uint32, startCursor string) ([]interface{}, []interface{}, string, error) {
 	res := &GetPendingValidatorsReply{}
 	err := c.requester.SendRequest("getPendingValidators", &GetPendingValidatorsArgs{
-		SubnetID: subnetID,
+		SubnetID:    subnetID,
+		Limit:       cjson.Uint32(limit),
+		StartCursor: startCursor,
 	}, res)
-	return res.Validators, res.Delegators, err
+	return res.Validators, res.Delegators, res.EndCursor, err
+}
+
+// QueryPendingValidators returns up to [limit] pending validators and
+// delegators for subnet with ID [subnetID] after [startCursor] that match
+// [query], in the order given by [query], and the cursor to fetch the next
+// page from
+func (c *Client) QueryPendingValidators(
+	subnetID ids.ID,
+	nodeIDs []string,
+	query APIValidatorQuery,
+	limit uint32,
+	startCursor string,
+) ([]interface{}, []interface{}, string, error) {
+	res := &GetPendingValidatorsReply{}
+	err := c.requester.SendRequest("getPendingValidators", &GetPendingValidatorsArgs{
+		SubnetID:          subnetID,
+		NodeIDs:           nodeIDs,
+		Limit:             cjson.Uint32(limit),
+		StartCursor:       startCursor,
+		APIValidatorQuery: query,
+	}, res)
+	return res.Validators, res.Delegators, res.EndCursor, err
 }
 
 // GetCurrentSupply returns an upper bound on the supply of AVAX in the system
@@ -163,6 +252,38 @@ func (c *Client) SampleValidators(subnetID ids.ID, sampleSize uint16) ([]string,
 	return res.Validators, err
 }
 
+// GetStakerReward returns the projected reward of the validator or delegator added by [txID]
+func (c *Client) GetStakerReward(txID ids.ID) (*GetStakerRewardReply, error) {
+	res := &GetStakerRewardReply{}
+	err := c.requester.SendRequest("getStakerReward", &GetStakerRewardArgs{
+		TxID: txID,
+	}, res)
+	return res, err
+}
+
+// GetValidatorsAt returns the weights of the validators of [subnetID] after the
+// block at [height] was accepted. If [timestamp] is non-zero, the validators at
+// the unix time [timestamp] are returned instead.
+func (c *Client) GetValidatorsAt(subnetID ids.ID, height, timestamp uint64) (*GetValidatorsAtReply, error) {
+	res := &GetValidatorsAtReply{}
+	err := c.requester.SendRequest("getValidatorsAt", &GetValidatorsAtArgs{
+		SubnetID:  subnetID,
+		Height:    cjson.Uint64(height),
+		Timestamp: cjson.Uint64(timestamp),
+	}, res)
+	return res, err
+}
+
+// GetUptimeHistory returns the uptime that [source] has observed the validator [nodeID] to have
+func (c *Client) GetUptimeHistory(nodeID string, source string) (*GetUptimeHistoryReply, error) {
+	res := &GetUptimeHistoryReply{}
+	err := c.requester.SendRequest("getUptimeHistory", &GetUptimeHistoryArgs{
+		NodeID: nodeID,
+		Source: source,
+	}, res)
+	return res, err
+}
+
 // AddValidator issues a transaction to add a validator to the primary network and returns the txID
 func (c *Client) AddValidator(
 	user api.UserPass,
@@ -275,6 +396,35 @@ func (c *Client) CreateSubnet(
 	return res.TxID, err
 }
 
+// RegisterBLSKey issues a transaction to register a node's BLS key, as
+// described by [registration], and returns the txID
+func (c *Client) RegisterBLSKey(
+	user api.UserPass,
+	from []string,
+	changeAddr string,
+	registration api.BLSKeyRegistration,
+) (ids.ID, error) {
+	res := &api.JSONTxID{}
+	err := c.requester.SendRequest("registerBLSKey", &RegisterBLSKeyArgs{
+		JSONSpendHeader: api.JSONSpendHeader{
+			UserPass:       user,
+			JSONFromAddrs:  api.JSONFromAddrs{From: from},
+			JSONChangeAddr: api.JSONChangeAddr{ChangeAddr: changeAddr},
+		},
+		BLSKeyRegistration: registration,
+	}, res)
+	return res.TxID, err
+}
+
+// GetBLSKey returns the hex encoded BLS key that [nodeID] registered
+func (c *Client) GetBLSKey(nodeID string) (string, error) {
+	res := &GetBLSKeyReply{}
+	err := c.requester.SendRequest("getBLSKey", &GetBLSKeyArgs{
+		NodeID: nodeID,
+	}, res)
+	return res.PublicKey, err
+}
+
 // ExportAVAX issues an ExportAVAX transaction and returns the txID
 func (c *Client) ExportAVAX(
 	user api.UserPass,
@@ -64,6 +64,8 @@ func init() {
 
 			c.RegisterType(&StakeableLockIn{}),
 			c.RegisterType(&StakeableLockOut{}),
+
+			c.RegisterType(&UnsignedRegisterBLSKeyTx{}),
 		)
 	}
 	errs.Add(
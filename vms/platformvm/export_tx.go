@@ -124,7 +124,7 @@ func (tx *UnsignedExportTx) SemanticVerify(
 }
 
 // Accept this transaction.
-func (tx *UnsignedExportTx) Accept(ctx *snow.Context, batch database.Batch) error {
+func (tx *UnsignedExportTx) Accept(vm *VM, batch database.Batch) error {
 	txID := tx.ID()
 
 	elems := make([]*atomic.Element, len(tx.ExportedOutputs))
@@ -154,7 +154,11 @@ func (tx *UnsignedExportTx) Accept(ctx *snow.Context, batch database.Batch) erro
 		elems[i] = elem
 	}
 
-	return ctx.SharedMemory.Put(tx.DestinationChain, elems, batch)
+	return vm.atomicQueue.Put(&atomic.Operation{
+		TxID:        txID,
+		PeerChainID: tx.DestinationChain,
+		Elems:       elems,
+	}, batch)
 }
 
 // Create a new transaction
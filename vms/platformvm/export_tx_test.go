@@ -0,0 +1,147 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var errSharedMemoryUnavailable = errors.New("shared memory is unavailable")
+
+// unavailableSharedMemory fails to put elements while [unavailable] is set
+type unavailableSharedMemory struct {
+	atomic.SharedMemory
+	unavailable bool
+}
+
+func (sm *unavailableSharedMemory) Put(peerChainID ids.ID, elems []*atomic.Element, batches ...database.Batch) error {
+	if sm.unavailable {
+		return errSharedMemoryUnavailable
+	}
+	return sm.SharedMemory.Put(peerChainID, elems, batches...)
+}
+
+// Test that an export is retried if shared memory is unavailable when it's
+// accepted
+func TestExportTxSharedMemoryUnavailable(t *testing.T) {
+	vm, baseDB := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	m := &atomic.Memory{}
+	if err := m.Initialize(logging.NoLog{}, prefixdb.New([]byte{5}, baseDB)); err != nil {
+		t.Fatal(err)
+	}
+	sm := &unavailableSharedMemory{
+		SharedMemory: m.NewSharedMemory(vm.ctx.ChainID),
+		unavailable:  true,
+	}
+	vm.ctx.SharedMemory = sm
+	atomicQueue, err := atomic.NewQueue(sm, prefixdb.New(atomicQueuePrefix, vm.dbManager.Current().Database))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.atomicQueue = atomicQueue
+
+	addr := keys[1].PublicKey().Address()
+	tx, err := vm.newExportTx(
+		defaultTxFee,
+		vm.ctx.XChainID,
+		addr,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.mempool.IssueTx(tx); err != nil {
+		t.Fatal(err)
+	} else if blk, err := vm.BuildBlock(); err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The tx is committed even though its UTXOs aren't in shared memory
+	if _, status, err := vm.internalState.GetTx(tx.ID()); err != nil {
+		t.Fatal(err)
+	} else if status != Committed {
+		t.Fatalf("status should be Committed but is %s", status)
+	}
+
+	peerSharedMemory := m.NewSharedMemory(vm.ctx.XChainID)
+	exportedUTXOs := func() int {
+		utxoBytes, _, _, err := peerSharedMemory.Indexed(vm.ctx.ChainID, [][]byte{addr.Bytes()}, nil, nil, math.MaxInt32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(utxoBytes)
+	}
+	if numUTXOs := exportedUTXOs(); numUTXOs != 0 {
+		t.Fatalf("expected no exported utxos but got %d", numUTXOs)
+	}
+
+	addrStr, err := vm.FormatLocalAddress(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Service{vm: vm}
+	pendingArgs := &api.GetPendingTransfersArgs{Addresses: []string{addrStr}}
+	pendingReply := &api.GetPendingTransfersReply{}
+	if err := s.GetPendingTransfers(nil, pendingArgs, pendingReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(pendingReply.Exports) != 1 {
+		t.Fatalf("expected 1 pending export but got %d", len(pendingReply.Exports))
+	}
+	if export := pendingReply.Exports[0]; export.TxID != tx.ID() || export.Status != atomic.Queued.String() || export.Attempts != 1 || export.LastError != errSharedMemoryUnavailable.Error() {
+		t.Fatalf("wrong pending export %+v", export)
+	}
+
+	statusReply := &GetTxStatusResponse{}
+	if err := s.GetTxStatus(nil, &GetTxStatusArgs{TxID: tx.ID()}, statusReply); err != nil {
+		t.Fatal(err)
+	}
+	if statusReply.ExportStatus == nil || *statusReply.ExportStatus != atomic.Queued.String() {
+		t.Fatalf("expected export status %s but got %v", atomic.Queued, statusReply.ExportStatus)
+	}
+
+	sm.unavailable = false
+	vm.retryAtomicOperations()
+
+	if numUTXOs := exportedUTXOs(); numUTXOs != 1 {
+		t.Fatalf("expected 1 exported utxo but got %d", numUTXOs)
+	}
+	pendingReply = &api.GetPendingTransfersReply{}
+	if err := s.GetPendingTransfers(nil, pendingArgs, pendingReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(pendingReply.Exports) != 0 {
+		t.Fatalf("expected no pending exports but got %d", len(pendingReply.Exports))
+	}
+	statusReply = &GetTxStatusResponse{}
+	if err := s.GetTxStatus(nil, &GetTxStatusArgs{TxID: tx.ID()}, statusReply); err != nil {
+		t.Fatal(err)
+	}
+	if statusReply.ExportStatus == nil || *statusReply.ExportStatus != atomic.Applied.String() {
+		t.Fatalf("expected export status %s but got %v", atomic.Applied, statusReply.ExportStatus)
+	}
+}
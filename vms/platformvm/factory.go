@@ -25,6 +25,21 @@ type Factory struct {
 	// Node's validator set maps subnetID -> validators of the subnet
 	Validators validators.Manager
 
+	// BLS keys that nodes registered on the platform chain
+	BLSKeys validators.BLSKeys
+
+	// Syncs subnets from validator snapshots. Told about the chains of each
+	// subnet so that it can attest to them.
+	SnapshotSyncer validators.SnapshotSyncer
+
+	// Exchanges the uptimes that the primary network's validators observe
+	// each other to have. May be nil.
+	UptimeReports validators.UptimeReports
+
+	// Additional sources of validators' liveness that are sampled into their
+	// uptime histories. May be nil.
+	LivenessSources validators.LivenessSources
+
 	// True if the node is being run with staking enabled
 	StakingEnabled bool
 
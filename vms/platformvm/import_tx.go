@@ -155,13 +155,13 @@ func (tx *UnsignedImportTx) SemanticVerify(
 // we don't want to remove an imported UTXO in semanticVerify
 // only to have the transaction not be Accepted. This would be inconsistent.
 // Recall that imported UTXOs are not kept in a versionDB.
-func (tx *UnsignedImportTx) Accept(ctx *snow.Context, batch database.Batch) error {
+func (tx *UnsignedImportTx) Accept(vm *VM, batch database.Batch) error {
 	utxoIDs := make([][]byte, len(tx.ImportedInputs))
 	for i, in := range tx.ImportedInputs {
 		utxoID := in.InputID()
 		utxoIDs[i] = utxoID[:]
 	}
-	return ctx.SharedMemory.Remove(tx.SourceChain, utxoIDs, batch)
+	return vm.ctx.SharedMemory.Remove(tx.SourceChain, utxoIDs, batch)
 }
 
 // Create a new transaction
@@ -4,12 +4,15 @@
 package platformvm
 
 import (
+	"container/heap"
 	"errors"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/timer"
 )
 
@@ -25,6 +28,9 @@ var (
 	errEndOfTime       = errors.New("program time is suspiciously far in the future. Either this codebase was way more successful than expected, or a critical error has occurred")
 	errNoPendingBlocks = errors.New("no pending blocks")
 	errUnknownTxType   = errors.New("unknown transaction type")
+	errArchival        = errors.New("node is in archival mode, so it doesn't issue transactions")
+
+	_ common.Mempool = &VM{}
 )
 
 // Mempool implements a simple mempool to convert txs into valid blocks
@@ -92,6 +98,9 @@ func (m *Mempool) Initialize(vm *VM) {
 
 // IssueTx enqueues the [tx] to be put into a block
 func (m *Mempool) IssueTx(tx *Tx) error {
+	if m.vm.ctx.Archival {
+		return errArchival
+	}
 	if m.dropIncoming {
 		return nil
 	}
@@ -411,3 +420,74 @@ func (m *Mempool) Shutdown() {
 	m.timer.Stop()
 	m.vm.ctx.Lock.Lock()
 }
+
+// Txs returns the transactions that haven't been put into blocks yet, in the
+// order that they would be put into blocks
+func (m *Mempool) Txs() []*Tx {
+	txs := make([]*Tx, 0, m.unissuedTxIDs.Len())
+	txs = append(txs, m.unissuedDecisionTxs...)
+	txs = append(txs, m.unissuedAtomicTxs...)
+	proposalTxs := append([]*Tx(nil), m.unissuedProposalTxs.Txs...)
+	proposalHeap := &EventHeap{
+		SortByStartTime: m.unissuedProposalTxs.SortByStartTime,
+		Txs:             proposalTxs,
+	}
+	for proposalHeap.Len() > 0 {
+		txs = append(txs, proposalHeap.Remove())
+	}
+	return txs
+}
+
+// Remove [txID] from the mempool. Returns false if [txID] isn't in the
+// mempool.
+func (m *Mempool) Remove(txID ids.ID) bool {
+	if !m.unissuedTxIDs.Contains(txID) {
+		return false
+	}
+	m.unissuedTxIDs.Remove(txID)
+
+	for i, tx := range m.unissuedDecisionTxs {
+		if tx.ID() == txID {
+			m.unissuedDecisionTxs = append(m.unissuedDecisionTxs[:i], m.unissuedDecisionTxs[i+1:]...)
+			return true
+		}
+	}
+	for i, tx := range m.unissuedAtomicTxs {
+		if tx.ID() == txID {
+			m.unissuedAtomicTxs = append(m.unissuedAtomicTxs[:i], m.unissuedAtomicTxs[i+1:]...)
+			return true
+		}
+	}
+	for i, tx := range m.unissuedProposalTxs.Txs {
+		if tx.ID() == txID {
+			heap.Remove(m.unissuedProposalTxs, i)
+			return true
+		}
+	}
+	return true
+}
+
+// MempoolTxs implements the common.Mempool interface
+func (vm *VM) MempoolTxs() []common.MempoolTx {
+	txs := vm.mempool.Txs()
+	summaries := make([]common.MempoolTx, len(txs))
+	for i, tx := range txs {
+		summaries[i] = common.MempoolTx{
+			ID:   tx.ID(),
+			Size: len(tx.Bytes()),
+			Type: reflect.Indirect(reflect.ValueOf(tx.UnsignedTx)).Type().Name(),
+		}
+	}
+	return summaries
+}
+
+// EvictTx implements the common.Mempool interface. The transaction is reported
+// as dropped.
+func (vm *VM) EvictTx(txID ids.ID) bool {
+	if !vm.mempool.Remove(txID) {
+		return false
+	}
+	vm.droppedTxCache.Put(txID, "evicted from the mempool")
+	vm.ctx.Log.Info("evicted tx %s from the mempool", txID)
+	return true
+}
@@ -0,0 +1,73 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+)
+
+func TestMempoolTxsAndEvict(t *testing.T) {
+	assert := assert.New(t)
+	vm, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	startTime := defaultGenesisTime.Add(syncBound).Add(1 * time.Second)
+	endTime := startTime.Add(defaultMinStakingDuration)
+	nodeID := ids.GenerateTestShortID()
+	addValidatorTx, err := vm.newAddValidatorTx(
+		vm.MinValidatorStake,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		nodeID,
+		nodeID,
+		PercentDenominator,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		ids.ShortEmpty, // change addr
+	)
+	assert.NoError(err)
+	createSubnetTx, err := vm.newCreateSubnetTx(
+		1,
+		[]ids.ShortID{keys[1].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[1]},
+		ids.ShortEmpty, // change addr
+	)
+	assert.NoError(err)
+
+	assert.NoError(vm.mempool.IssueTx(addValidatorTx))
+	assert.NoError(vm.mempool.IssueTx(createSubnetTx))
+
+	// Decision txs are put into blocks before proposal txs
+	txs := vm.MempoolTxs()
+	assert.Len(txs, 2)
+	assert.Equal(createSubnetTx.ID(), txs[0].ID)
+	assert.Equal(len(createSubnetTx.Bytes()), txs[0].Size)
+	assert.Equal("UnsignedCreateSubnetTx", txs[0].Type)
+	assert.Equal(addValidatorTx.ID(), txs[1].ID)
+	assert.Equal("UnsignedAddValidatorTx", txs[1].Type)
+
+	assert.False(vm.EvictTx(ids.GenerateTestID()))
+	assert.True(vm.EvictTx(addValidatorTx.ID()))
+	assert.False(vm.EvictTx(addValidatorTx.ID()))
+	assert.True(vm.EvictTx(createSubnetTx.ID()))
+	assert.Empty(vm.MempoolTxs())
+
+	// Evicted txs are reported as dropped
+	reason, ok := vm.droppedTxCache.Get(addValidatorTx.ID())
+	assert.True(ok)
+	assert.Equal("evicted from the mempool", reason)
+
+	// An evicted tx can be issued again
+	assert.NoError(vm.mempool.IssueTx(addValidatorTx))
+	assert.Len(vm.MempoolTxs(), 1)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/version"
 )
 
@@ -86,7 +87,7 @@ func (um *uptimeMigrater1_4_5) migrate(dbV100 *manager.VersionedDatabase) error
 		if err == database.ErrNotFound {
 			continue
 		} else if err != nil {
-			return fmt.Errorf("couldn't get uptime for node %s from database v1.0.0: %s", nodeID.PrefixedString(constants.NodeIDPrefix), err)
+			return fmt.Errorf("couldn't get uptime for node %s from database v1.0.0: %s", address.FormatNodeID(nodeID), err)
 		}
 
 		// only migrate a validator's uptime if the validator is still in the validator set.
@@ -94,7 +95,7 @@ func (um *uptimeMigrater1_4_5) migrate(dbV100 *manager.VersionedDatabase) error
 			// This validator isn't in the current validator set. They must have left the validator set. Ignore.
 			continue
 		} else if err != nil {
-			return fmt.Errorf("couldn't get add validator tx %s for %s: %s", addVdrTx.ID(), nodeID.PrefixedString(constants.NodeIDPrefix), err)
+			return fmt.Errorf("couldn't get add validator tx %s for %s: %s", addVdrTx.ID(), address.FormatNodeID(nodeID), err)
 		}
 
 		// In v1.0.0, up duration is stored in seconds. In v1.4.5, it is stored in nanoseconds.
@@ -110,13 +111,13 @@ func (um *uptimeMigrater1_4_5) migrate(dbV100 *manager.VersionedDatabase) error
 
 		um.vm.ctx.Log.Debug(
 			"migrating uptime for node %s (tx %s) from database v1.0.0 to v1.4.5. Uptime: %s. Last updated: %s",
-			nodeID.PrefixedString(constants.NodeIDPrefix),
+			address.FormatNodeID(nodeID),
 			addVdrTx.ID(),
 			time.Duration(upDuration),
 			lastUpdated,
 		)
 		if err := um.vm.internalState.SetUptime(nodeID, time.Duration(upDuration), lastUpdated); err != nil {
-			return fmt.Errorf("couldn't migrate uptime for node %s: %s", nodeID.PrefixedString(constants.NodeIDPrefix), err)
+			return fmt.Errorf("couldn't migrate uptime for node %s: %s", address.FormatNodeID(nodeID), err)
 		}
 	}
 	if err = stopDBIter.Error(); err != nil {
@@ -25,6 +25,11 @@ func (_m *MockInternalState) Abort() {
 	_m.Called()
 }
 
+// AddBLSKey provides a mock function with given fields: registerBLSKeyTx
+func (_m *MockInternalState) AddBLSKey(registerBLSKeyTx *Tx) {
+	_m.Called(registerBLSKeyTx)
+}
+
 // AddBlock provides a mock function with given fields: block
 func (_m *MockInternalState) AddBlock(block Block) {
 	_m.Called(block)
@@ -147,6 +152,68 @@ func (_m *MockInternalState) DeleteUTXO(utxoID ids.ID) {
 	_m.Called(utxoID)
 }
 
+// GetBLSKey provides a mock function with given fields: nodeID
+func (_m *MockInternalState) GetBLSKey(nodeID ids.ShortID) (*Tx, error) {
+	ret := _m.Called(nodeID)
+
+	var r0 *Tx
+	if rf, ok := ret.Get(0).(func(ids.ShortID) *Tx); ok {
+		r0 = rf(nodeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Tx)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ids.ShortID) error); ok {
+		r1 = rf(nodeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBLSKeyOwner provides a mock function with given fields: publicKey
+func (_m *MockInternalState) GetBLSKeyOwner(publicKey []byte) (ids.ShortID, error) {
+	ret := _m.Called(publicKey)
+
+	var r0 ids.ShortID
+	if rf, ok := ret.Get(0).(func([]byte) ids.ShortID); ok {
+		r0 = rf(publicKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ids.ShortID)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]byte) error); ok {
+		r1 = rf(publicKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBLSKeys provides a mock function with given fields:
+func (_m *MockInternalState) GetBLSKeys() map[ids.ShortID]*Tx {
+	ret := _m.Called()
+
+	var r0 map[ids.ShortID]*Tx
+	if rf, ok := ret.Get(0).(func() map[ids.ShortID]*Tx); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[ids.ShortID]*Tx)
+		}
+	}
+
+	return r0
+}
+
 // GetBlock provides a mock function with given fields: blockID
 func (_m *MockInternalState) GetBlock(blockID ids.ID) (Block, error) {
 	ret := _m.Called(blockID)
@@ -207,6 +274,27 @@ func (_m *MockInternalState) GetCurrentSupply() uint64 {
 	return r0
 }
 
+// GetHeightAtTime provides a mock function with given fields: timestamp
+func (_m *MockInternalState) GetHeightAtTime(timestamp time.Time) (uint64, error) {
+	ret := _m.Called(timestamp)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(time.Time) uint64); ok {
+		r0 = rf(timestamp)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(timestamp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastAccepted provides a mock function with given fields:
 func (_m *MockInternalState) GetLastAccepted() ids.ID {
 	ret := _m.Called()
@@ -364,6 +452,29 @@ func (_m *MockInternalState) GetUptime(nodeID ids.ShortID) (time.Duration, time.
 	return r0, r1, r2
 }
 
+// GetValidatorWeights provides a mock function with given fields: subnetID, height
+func (_m *MockInternalState) GetValidatorWeights(subnetID ids.ID, height uint64) (map[ids.ShortID]uint64, error) {
+	ret := _m.Called(subnetID, height)
+
+	var r0 map[ids.ShortID]uint64
+	if rf, ok := ret.Get(0).(func(ids.ID, uint64) map[ids.ShortID]uint64); ok {
+		r0 = rf(subnetID, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[ids.ShortID]uint64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ids.ID, uint64) error); ok {
+		r1 = rf(subnetID, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsMigrated provides a mock function with given fields:
 func (_m *MockInternalState) IsMigrated() (bool, error) {
 	ret := _m.Called()
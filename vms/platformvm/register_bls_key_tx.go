@@ -0,0 +1,145 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+var (
+	errBLSKeyAlreadyRegistered = errors.New("BLS key is already registered")
+
+	_ UnsignedDecisionTx = &UnsignedRegisterBLSKeyTx{}
+)
+
+// UnsignedRegisterBLSKeyTx is an unsigned transaction that registers the BLS
+// key that a node attests to validator snapshots with. A node that registers
+// another key replaces its previous key.
+type UnsignedRegisterBLSKeyTx struct {
+	// Metadata, inputs and outputs
+	BaseTx `serialize:"true"`
+	// Binds the BLS key to the node
+	Registration validators.BLSKeyRegistration `serialize:"true" json:"registration"`
+
+	// ID of the node that registered the key. Set during verification.
+	nodeID ids.ShortID
+	// The registered key. Set during verification.
+	publicKey *bls.PublicKey
+}
+
+// Verify this transaction is well-formed
+func (tx *UnsignedRegisterBLSKeyTx) Verify(
+	ctx *snow.Context,
+	c codec.Manager,
+	feeAmount uint64,
+	feeAssetID ids.ID,
+) error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.syntacticallyVerified: // already passed syntactic verification
+		return nil
+	}
+
+	if err := tx.BaseTx.Verify(ctx, c); err != nil {
+		return err
+	}
+	nodeID, publicKey, err := tx.Registration.Verify()
+	if err != nil {
+		return err
+	}
+	tx.nodeID = nodeID.ShortID()
+	tx.publicKey = publicKey
+
+	tx.syntacticallyVerified = true
+	return nil
+}
+
+// SemanticVerify returns nil if [tx] is valid given the state in [db]
+func (tx *UnsignedRegisterBLSKeyTx) SemanticVerify(
+	vm *VM,
+	vs VersionedState,
+	stx *Tx,
+) (
+	func() error,
+	TxError,
+) {
+	// Make sure this transaction is well formed.
+	if err := tx.Verify(vm.ctx, vm.codec, vm.TxFee, vm.ctx.AVAXAssetID); err != nil {
+		return nil, permError{err}
+	}
+
+	// A key can only be registered by one node, so that attestations can't be
+	// attributed to another node
+	owner, err := vs.GetBLSKeyOwner(tx.Registration.PublicKey)
+	switch {
+	case err == nil:
+		return nil, permError{fmt.Errorf("%w by %s", errBLSKeyAlreadyRegistered, address.FormatNodeID(owner))}
+	case err != database.ErrNotFound:
+		return nil, tempError{err}
+	}
+
+	// Verify the flowcheck
+	if err := vm.semanticVerifySpend(vs, tx, tx.Ins, tx.Outs, stx.Creds, vm.TxFee, vm.ctx.AVAXAssetID); err != nil {
+		return nil, err
+	}
+
+	// Consume the UTXOS
+	consumeInputs(vs, tx.Ins)
+	// Produce the UTXOS
+	txID := tx.ID()
+	produceOutputs(vs, txID, vm.ctx.AVAXAssetID, tx.Outs)
+	// Replace the node's key
+	vs.AddBLSKey(stx)
+
+	onAccept := func() error { return vm.registerBLSKey(tx) }
+	return onAccept, nil
+}
+
+// registerBLSKey adds the key registered by [tx] to the node's registry of
+// keys registered on the platform chain
+func (vm *VM) registerBLSKey(tx *UnsignedRegisterBLSKeyTx) error {
+	if vm.BLSKeys == nil {
+		return nil
+	}
+	return vm.BLSKeys.Set(ids.NodeIDFromShortID(tx.nodeID), tx.publicKey)
+}
+
+func (vm *VM) newRegisterBLSKeyTx(
+	registration validators.BLSKeyRegistration, // registration of the key
+	keys []*crypto.PrivateKeySECP256K1R, // pay the fee
+	changeAddr ids.ShortID, // Address to send change to, if there is any
+) (*Tx, error) {
+	ins, outs, _, signers, err := vm.stake(keys, 0, vm.TxFee, changeAddr)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
+	}
+
+	// Create the tx
+	utx := &UnsignedRegisterBLSKeyTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    vm.ctx.NetworkID,
+			BlockchainID: vm.ctx.ChainID,
+			Ins:          ins,
+			Outs:         outs,
+		}},
+		Registration: registration,
+	}
+	tx := &Tx{UnsignedTx: utx}
+	if err := tx.Sign(vm.codec, signers); err != nil {
+		return nil, err
+	}
+	return tx, utx.Verify(vm.ctx, vm.codec, vm.TxFee, vm.ctx.AVAXAssetID)
+}
@@ -0,0 +1,106 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	stdcrypto "crypto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+)
+
+func newTestBLSKeyRegistration(t *testing.T) (*validators.BLSKeyRegistration, *bls.SecretKey, func(*bls.SecretKey) *validators.BLSKeyRegistration) {
+	cert, err := staking.NewTLSCert()
+	assert.NoError(t, err)
+	register := func(sk *bls.SecretKey) *validators.BLSKeyRegistration {
+		registration, err := validators.NewBLSKeyRegistration(cert.Leaf, cert.PrivateKey.(stdcrypto.Signer), sk)
+		assert.NoError(t, err)
+		return registration
+	}
+	sk, err := bls.NewSecretKey()
+	assert.NoError(t, err)
+	return register(sk), sk, register
+}
+
+func TestRegisterBLSKeyTx(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+	vm.BLSKeys = validators.NewBLSKeys()
+
+	registration, sk, register := newTestBLSKeyRegistration(t)
+	nodeID, _, err := registration.Verify()
+	assert.NoError(t, err)
+
+	accept := func(registration *validators.BLSKeyRegistration) error {
+		tx, err := vm.newRegisterBLSKeyTx(*registration, []*crypto.PrivateKeySECP256K1R{keys[0]}, keys[0].PublicKey().Address())
+		if err != nil {
+			return err
+		}
+		vs := newVersionedState(
+			vm.internalState,
+			vm.internalState.CurrentStakerChainState(),
+			vm.internalState.PendingStakerChainState(),
+		)
+		onAccept, txErr := tx.UnsignedTx.(UnsignedDecisionTx).SemanticVerify(vm, vs, tx)
+		if txErr != nil {
+			return txErr
+		}
+		vs.AddTx(tx, Committed)
+		vs.Apply(vm.internalState)
+		if err := vm.internalState.Commit(); err != nil {
+			return err
+		}
+		return onAccept()
+	}
+
+	// A registration that isn't signed by the node is invalid
+	forged := *registration
+	forged.Signature = nil
+	_, err = vm.newRegisterBLSKeyTx(forged, []*crypto.PrivateKeySECP256K1R{keys[0]}, ids.ShortEmpty)
+	assert.Error(t, err)
+
+	assert.NoError(t, accept(registration))
+	tx, err := vm.internalState.GetBLSKey(nodeID.ShortID())
+	assert.NoError(t, err)
+	assert.Equal(t, registration.PublicKey, tx.UnsignedTx.(*UnsignedRegisterBLSKeyTx).Registration.PublicKey)
+	pk, ok := vm.BLSKeys.Get(nodeID)
+	assert.True(t, ok)
+	assert.Equal(t, sk.PublicKey().Bytes(), pk.Bytes())
+
+	// Another node can't register the same key
+	_, _, registerOther := newTestBLSKeyRegistration(t)
+	assert.Error(t, accept(registerOther(sk)))
+
+	// The node can replace its key, which frees the previous key
+	newSK, err := bls.NewSecretKey()
+	assert.NoError(t, err)
+	assert.NoError(t, accept(register(newSK)))
+	owner, err := vm.internalState.GetBLSKeyOwner(newSK.PublicKey().Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, nodeID.ShortID(), owner)
+	_, err = vm.internalState.GetBLSKeyOwner(sk.PublicKey().Bytes())
+	assert.Equal(t, database.ErrNotFound, err)
+	pk, ok = vm.BLSKeys.Get(nodeID)
+	assert.True(t, ok)
+	assert.Equal(t, newSK.PublicKey().Bytes(), pk.Bytes())
+
+	// The registered keys are loaded from the database
+	assert.NoError(t, vm.internalState.(*internalStateImpl).loadBLSKeys())
+	tx, err = vm.internalState.GetBLSKey(nodeID.ShortID())
+	assert.NoError(t, err)
+	assert.Equal(t, newSK.PublicKey().Bytes(), tx.UnsignedTx.(*UnsignedRegisterBLSKeyTx).Registration.PublicKey)
+}
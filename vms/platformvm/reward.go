@@ -6,6 +6,8 @@ package platformvm
 import (
 	"math/big"
 	"time"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
 )
 
 var (
@@ -55,3 +57,33 @@ func reward(
 
 	return reward.Uint64()
 }
+
+// splitReward splits the [reward] of a delegator between the delegator and the
+// validator it delegated to, which takes [shares] / PercentDenominator of the
+// reward as a fee.
+func splitReward(reward uint64, shares uint32) (delegatorReward uint64, delegateeReward uint64) {
+	delegatorShares := PercentDenominator - uint64(shares)            // shares <= PercentDenominator so no underflow
+	delegatorReward = delegatorShares * (reward / PercentDenominator) // delegatorShares <= PercentDenominator so no overflow
+	// Delay rounding as long as possible for small numbers
+	if optimisticReward, err := safemath.Mul64(delegatorShares, reward); err == nil {
+		delegatorReward = optimisticReward / PercentDenominator
+	}
+	delegateeReward = reward - delegatorReward // delegatorReward <= reward so no underflow
+	return delegatorReward, delegateeReward
+}
+
+// accruedReward returns the portion of [reward] accrued by [now] by a staker
+// that stakes from [startTime] until [endTime], in proportion to the time
+// staked.
+func accruedReward(reward uint64, startTime, endTime, now time.Time) uint64 {
+	switch {
+	case !now.After(startTime):
+		return 0
+	case !now.Before(endTime):
+		return reward
+	}
+	accrued := new(big.Int).SetUint64(reward)
+	accrued.Mul(accrued, new(big.Int).SetUint64(uint64(now.Sub(startTime))))
+	accrued.Div(accrued, new(big.Int).SetUint64(uint64(endTime.Sub(startTime))))
+	return accrued.Uint64()
+}
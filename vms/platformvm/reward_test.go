@@ -5,6 +5,7 @@ package platformvm
 
 import (
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -119,3 +120,51 @@ func TestRewards(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitReward(t *testing.T) {
+	tests := []struct {
+		reward                  uint64
+		shares                  uint32
+		expectedDelegatorReward uint64
+		expectedDelegateeReward uint64
+	}{
+		{reward: 1000, shares: 0, expectedDelegatorReward: 1000, expectedDelegateeReward: 0},
+		{reward: 1000, shares: PercentDenominator, expectedDelegatorReward: 0, expectedDelegateeReward: 1000},
+		{reward: 1000, shares: 20000, expectedDelegatorReward: 980, expectedDelegateeReward: 20},
+		{reward: 1, shares: 20000, expectedDelegatorReward: 0, expectedDelegateeReward: 1},
+		// Rounds to a multiple of PercentDenominator when the product overflows
+		{reward: math.MaxUint64, shares: 500000, expectedDelegatorReward: 9223372036854500000, expectedDelegateeReward: math.MaxUint64 - 9223372036854500000},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("reward=%d,shares=%d", test.reward, test.shares)
+		t.Run(name, func(t *testing.T) {
+			delegatorReward, delegateeReward := splitReward(test.reward, test.shares)
+			if delegatorReward != test.expectedDelegatorReward {
+				t.Fatalf("expected delegator reward %d but got %d", test.expectedDelegatorReward, delegatorReward)
+			}
+			if delegateeReward != test.expectedDelegateeReward {
+				t.Fatalf("expected delegatee reward %d but got %d", test.expectedDelegateeReward, delegateeReward)
+			}
+		})
+	}
+}
+
+func TestAccruedReward(t *testing.T) {
+	startTime := time.Unix(1000, 0)
+	endTime := startTime.Add(100 * time.Second)
+	tests := []struct {
+		now            time.Time
+		expectedReward uint64
+	}{
+		{now: startTime.Add(-time.Second), expectedReward: 0},
+		{now: startTime, expectedReward: 0},
+		{now: startTime.Add(25 * time.Second), expectedReward: 250},
+		{now: endTime, expectedReward: 1000},
+		{now: endTime.Add(time.Second), expectedReward: 1000},
+	}
+	for _, test := range tests {
+		if accrued := accruedReward(1000, startTime, endTime, test.now); accrued != test.expectedReward {
+			t.Fatalf("expected accrued reward %d at %s but got %d", test.expectedReward, test.now, accrued)
+		}
+	}
+}
@@ -204,13 +204,7 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 
 		// Calculate split of reward between delegator/delegatee
 		// The delegator gives stake to the validatee
-		delegatorShares := PercentDenominator - uint64(vdrTx.Shares)             // parentTx.Shares <= PercentDenominator so no underflow
-		delegatorReward := delegatorShares * (stakerReward / PercentDenominator) // delegatorShares <= PercentDenominator so no overflow
-		// Delay rounding as long as possible for small numbers
-		if optimisticReward, err := safemath.Mul64(delegatorShares, stakerReward); err == nil {
-			delegatorReward = optimisticReward / PercentDenominator
-		}
-		delegateeReward := stakerReward - delegatorReward // delegatorReward <= reward so no underflow
+		delegatorReward, delegateeReward := splitReward(stakerReward, vdrTx.Shares)
 
 		offset := 0
 
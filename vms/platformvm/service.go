@@ -13,14 +13,17 @@ import (
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/avm"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/uptime"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
@@ -47,6 +50,7 @@ var (
 	errInvalidDelegationRate = errors.New("argument 'delegationFeeRate' must be between 0 and 100, inclusive")
 	errNoAddresses           = errors.New("no addresses provided")
 	errNoKeys                = errors.New("user has no keys or funds")
+	errKeyAndMnemonic        = errors.New("only one of 'privateKey' and 'mnemonic' can be provided")
 	errNoPrimaryValidators   = errors.New("no default subnet validators")
 	errCorruptedReason       = errors.New("tx validity corrupted")
 	errStartTimeTooSoon      = fmt.Errorf("start time must be at least %s in the future", minAddStakerDelay)
@@ -121,6 +125,13 @@ func (service *Service) ExportKey(r *http.Request, args *ExportKeyArgs, reply *E
 type ImportKeyArgs struct {
 	api.UserPass
 	PrivateKey string `json:"privateKey"`
+	// If provided instead of [PrivateKey], the imported key is derived from
+	// this BIP-39 mnemonic
+	Mnemonic string `json:"mnemonic"`
+	// Passphrase that protects [Mnemonic], if any
+	MnemonicPassphrase string `json:"mnemonicPassphrase"`
+	// Index of the address whose key is derived from [Mnemonic]
+	AddressIndex json.Uint32 `json:"addressIndex"`
 }
 
 // ImportKey adds a private key to the provided user
@@ -138,21 +149,32 @@ func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *a
 		return fmt.Errorf("keystore user has reached its limit of %d addresses", maxKeystoreAddresses)
 	}
 
-	if !strings.HasPrefix(args.PrivateKey, constants.SecretKeyPrefix) {
-		return fmt.Errorf("private key missing %s prefix", constants.SecretKeyPrefix)
-	}
+	var sk *crypto.PrivateKeySECP256K1R
+	if args.Mnemonic != "" {
+		if args.PrivateKey != "" {
+			return errKeyAndMnemonic
+		}
+		sk, err = crypto.MnemonicToPrivateKey(args.Mnemonic, args.MnemonicPassphrase, uint32(args.AddressIndex))
+		if err != nil {
+			return fmt.Errorf("problem deriving private key: %w", err)
+		}
+	} else {
+		if !strings.HasPrefix(args.PrivateKey, constants.SecretKeyPrefix) {
+			return fmt.Errorf("private key missing %s prefix", constants.SecretKeyPrefix)
+		}
 
-	trimmedPrivateKey := strings.TrimPrefix(args.PrivateKey, constants.SecretKeyPrefix)
-	privKeyBytes, err := formatting.Decode(formatting.CB58, trimmedPrivateKey)
-	if err != nil {
-		return fmt.Errorf("problem parsing private key: %w", err)
-	}
+		trimmedPrivateKey := strings.TrimPrefix(args.PrivateKey, constants.SecretKeyPrefix)
+		privKeyBytes, err := formatting.Decode(formatting.CB58, trimmedPrivateKey)
+		if err != nil {
+			return fmt.Errorf("problem parsing private key: %w", err)
+		}
 
-	skIntf, err := service.vm.factory.ToPrivateKey(privKeyBytes)
-	if err != nil {
-		return fmt.Errorf("problem parsing private key: %w", err)
+		skIntf, err := service.vm.factory.ToPrivateKey(privKeyBytes)
+		if err != nil {
+			return fmt.Errorf("problem parsing private key: %w", err)
+		}
+		sk = skIntf.(*crypto.PrivateKeySECP256K1R)
 	}
-	sk := skIntf.(*crypto.PrivateKeySECP256K1R)
 
 	reply.Address, err = service.vm.FormatLocalAddress(sk.PublicKey().Address())
 	if err != nil {
@@ -413,7 +435,7 @@ func (service *Service) GetUTXOs(_ *http.Request, args *GetUTXOsArgs, response *
 		if err != nil {
 			return fmt.Errorf("couldn't parse start index address %q: %w", args.StartIndex.Address, err)
 		}
-		startUTXO, err = ids.FromString(args.StartIndex.UTXO)
+		startUTXO, err = address.ParseID(args.StartIndex.UTXO)
 		if err != nil {
 			return fmt.Errorf("couldn't parse start index utxo: %w", err)
 		}
@@ -469,6 +491,52 @@ func (service *Service) GetUTXOs(_ *http.Request, args *GetUTXOsArgs, response *
 	return nil
 }
 
+// GetPendingTransfers returns the cross-chain transfers of the given addresses
+// that haven't completed: the exports of this chain that couldn't be written
+// to shared memory yet, and the UTXOs that were exported to this chain from the
+// given source chains and haven't been imported yet
+func (service *Service) GetPendingTransfers(_ *http.Request, args *api.GetPendingTransfersArgs, response *api.GetPendingTransfersReply) error {
+	service.vm.ctx.Log.Info("Platform: GetPendingTransfers called")
+
+	if len(args.Addresses) == 0 {
+		return errNoAddresses
+	}
+	if len(args.Addresses) > maxGetUTXOsAddrs {
+		return fmt.Errorf("number of addresses given, %d, exceeds maximum, %d", len(args.Addresses), maxGetUTXOsAddrs)
+	}
+
+	addrSet := ids.ShortSet{}
+	for _, addrStr := range args.Addresses {
+		addr, err := service.vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+		}
+		addrSet.Add(addr)
+	}
+	sourceChains := make([]ids.ID, len(args.SourceChains))
+	for i, chain := range args.SourceChains {
+		chainID, err := service.vm.ctx.BCLookup.Lookup(chain)
+		if err != nil {
+			return fmt.Errorf("problem parsing source chainID %q: %w", chain, err)
+		}
+		sourceChains[i] = chainID
+	}
+
+	transfers, err := avax.GetPendingTransfers(
+		service.vm.atomicQueue,
+		service.vm.AtomicUTXOManager,
+		service.vm.codec,
+		addrSet,
+		sourceChains,
+		args.Encoding,
+	)
+	if err != nil {
+		return err
+	}
+	*response = *transfers
+	return nil
+}
+
 /*
  ******************************************************
  ******************* Get Subnets **********************
@@ -635,12 +703,22 @@ type GetCurrentValidatorsArgs struct {
 	// some nodeIDs are not currently validators, they
 	// will be omitted from the response.
 	NodeIDs []string `json:"nodeIDs"`
+	// Max number of validators to return. If [Limit] is 0 or more than
+	// [maxStakersToFetch], up to [maxStakersToFetch] are returned.
+	Limit json.Uint32 `json:"limit"`
+	// If provided, only validators after this cursor are returned. Should be the
+	// [EndCursor] of a previous reply.
+	StartCursor string `json:"startCursor"`
+	APIValidatorQuery
 }
 
 // GetCurrentValidatorsReply are the results from calling GetCurrentValidators.
 // Each validator contains a list of delegators to itself.
 type GetCurrentValidatorsReply struct {
 	Validators []interface{} `json:"validators"`
+	// Position of the last validator returned. Pass as [StartCursor] to fetch
+	// the next page.
+	EndCursor string `json:"endCursor"`
 }
 
 // GetCurrentValidators returns current validators and delegators
@@ -655,7 +733,7 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 	// Create set of nodeIDs
 	nodeIDs := ids.ShortSet{}
 	for _, nodeID := range args.NodeIDs {
-		nID, err := ids.ShortFromPrefixedString(nodeID, constants.NodeIDPrefix)
+		nID, err := address.ParseNodeID(nodeID)
 		if err != nil {
 			return err
 		}
@@ -663,9 +741,58 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 	}
 	includeAllNodes := nodeIDs.Len() == 0
 
+	if err := args.verify(); err != nil {
+		return err
+	}
+	limit := int(args.Limit)
+	if limit <= 0 || limit > maxStakersToFetch {
+		limit = maxStakersToFetch
+	}
+	var (
+		startCursor       stakerCursor
+		startOffsetCursor offsetCursor
+	)
+	hasStartCursor := args.StartCursor != ""
+	switch {
+	case args.sorted():
+		// Sorted stakers are paged by their offset in the sorted list
+		cursor, err := parseOffsetCursor(args.StartCursor)
+		if err != nil {
+			return err
+		}
+		startOffsetCursor = cursor
+		hasStartCursor = false
+	case hasStartCursor:
+		cursor, err := parseStakerCursor(args.StartCursor)
+		if err != nil {
+			return err
+		}
+		startCursor = cursor
+	}
+	reply.EndCursor = args.StartCursor
+
 	currentValidators := service.vm.internalState.CurrentStakerChainState()
+	rollups, err := delegationRollups(currentValidators.Stakers())
+	if err != nil {
+		return err
+	}
 
+	numValidators := 0
 	for _, tx := range currentValidators.Stakers() { // Iterates in order of increasing stop time
+		if !args.sorted() && numValidators >= limit {
+			// Delegators always stop before their validator, so the remaining
+			// delegators can't belong to a returned validator
+			break
+		}
+		cursor, err := currentStakerCursor(tx)
+		if err != nil {
+			return err
+		}
+		// Delegators are collected regardless of the cursor, as they may
+		// delegate to a validator after the cursor
+		if _, ok := tx.UnsignedTx.(*UnsignedAddDelegatorTx); !ok && hasStartCursor && !cursor.after(startCursor) {
+			continue
+		}
 		_, reward, err := currentValidators.GetStaker(tx.ID())
 		if err != nil {
 			return err
@@ -704,7 +831,7 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 					StartTime:   json.Uint64(staker.StartTime().Unix()),
 					EndTime:     json.Uint64(staker.EndTime().Unix()),
 					StakeAmount: &weight,
-					NodeID:      staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
+					NodeID:      address.FormatNodeID(staker.Validator.ID()),
 				},
 				RewardOwner:     rewardOwner,
 				PotentialReward: &potentialReward,
@@ -717,6 +844,9 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 			if !includeAllNodes && !nodeIDs.Contains(staker.Validator.ID()) {
 				continue
 			}
+			if !args.matches(uint64(staker.StartTime().Unix()), uint64(staker.EndTime().Unix())) {
+				continue
+			}
 
 			nodeID := staker.Validator.ID()
 			startTime := staker.StartTime()
@@ -747,10 +877,10 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 				}
 			}
 
-			reply.Validators = append(reply.Validators, APIPrimaryValidator{
+			reply.Validators = append(reply.Validators, withDelegationRollup(APIPrimaryValidator{
 				APIStaker: APIStaker{
 					TxID:        tx.ID(),
-					NodeID:      nodeID.PrefixedString(constants.NodeIDPrefix),
+					NodeID:      address.FormatNodeID(nodeID),
 					StartTime:   json.Uint64(startTime.Unix()),
 					EndTime:     json.Uint64(staker.EndTime().Unix()),
 					StakeAmount: &weight,
@@ -760,7 +890,9 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 				PotentialReward: &potentialReward,
 				RewardOwner:     rewardOwner,
 				DelegationFee:   delegationFee,
-			})
+			}, rollups[nodeID]))
+			numValidators++
+			reply.EndCursor = cursor.String()
 		case *UnsignedAddSubnetValidatorTx:
 			if args.SubnetID != staker.Validator.Subnet {
 				continue
@@ -768,15 +900,20 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 			if !includeAllNodes && !nodeIDs.Contains(staker.Validator.ID()) {
 				continue
 			}
+			if !args.matches(uint64(staker.StartTime().Unix()), uint64(staker.EndTime().Unix())) {
+				continue
+			}
 
 			weight := json.Uint64(staker.Validator.Weight())
 			reply.Validators = append(reply.Validators, APIStaker{
 				TxID:      tx.ID(),
-				NodeID:    staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
+				NodeID:    address.FormatNodeID(staker.Validator.ID()),
 				StartTime: json.Uint64(staker.StartTime().Unix()),
 				EndTime:   json.Uint64(staker.EndTime().Unix()),
 				Weight:    &weight,
 			})
+			numValidators++
+			reply.EndCursor = cursor.String()
 		default:
 			return fmt.Errorf("expected validator but got %T", tx.UnsignedTx)
 		}
@@ -787,12 +924,19 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 		if !ok {
 			continue
 		}
-		if delegators, ok := vdrToDelegators[vdr.NodeID]; ok {
+		if delegators, ok := vdrToDelegators[vdr.NodeID]; ok && !args.OmitDelegators {
 			vdr.Delegators = delegators
 		}
 		reply.Validators[i] = vdr
 	}
 
+	if args.sorted() {
+		args.sortStakers(reply.Validators)
+		reply.Validators = page(reply.Validators, startOffsetCursor, limit)
+		if len(reply.Validators) > 0 {
+			reply.EndCursor = (startOffsetCursor + offsetCursor(len(reply.Validators))).String()
+		}
+	}
 	return nil
 }
 
@@ -806,6 +950,13 @@ type GetPendingValidatorsArgs struct {
 	// some requested nodeIDs are not pending validators,
 	// they are omitted from the response.
 	NodeIDs []string `json:"nodeIDs"`
+	// Max number of validators and delegators to return. If [Limit] is 0 or more than
+	// [maxStakersToFetch], up to [maxStakersToFetch] are returned.
+	Limit json.Uint32 `json:"limit"`
+	// If provided, only stakers after this cursor are returned. Should be the
+	// [EndCursor] of a previous reply.
+	StartCursor string `json:"startCursor"`
+	APIValidatorQuery
 }
 
 // GetPendingValidatorsReply are the results from calling GetPendingValidators.
@@ -813,6 +964,9 @@ type GetPendingValidatorsArgs struct {
 type GetPendingValidatorsReply struct {
 	Validators []interface{} `json:"validators"`
 	Delegators []interface{} `json:"delegators"`
+	// Position of the last staker returned. Pass as [StartCursor] to fetch
+	// the next page.
+	EndCursor string `json:"endCursor"`
 }
 
 // GetPendingValidators returns the list of pending validators
@@ -825,7 +979,7 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 	// Create set of nodeIDs
 	nodeIDs := ids.ShortSet{}
 	for _, nodeID := range args.NodeIDs {
-		nID, err := ids.ShortFromPrefixedString(nodeID, constants.NodeIDPrefix)
+		nID, err := address.ParseNodeID(nodeID)
 		if err != nil {
 			return err
 		}
@@ -833,9 +987,54 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 	}
 	includeAllNodes := nodeIDs.Len() == 0
 
+	if err := args.verify(); err != nil {
+		return err
+	}
+	limit := int(args.Limit)
+	if limit <= 0 || limit > maxStakersToFetch {
+		limit = maxStakersToFetch
+	}
+	var (
+		startCursor       stakerCursor
+		startOffsetCursor offsetCursor
+	)
+	hasStartCursor := args.StartCursor != ""
+	switch {
+	case args.sorted():
+		// Sorted stakers are paged by their offset in the sorted list
+		cursor, err := parseOffsetCursor(args.StartCursor)
+		if err != nil {
+			return err
+		}
+		startOffsetCursor = cursor
+		hasStartCursor = false
+	case hasStartCursor:
+		cursor, err := parseStakerCursor(args.StartCursor)
+		if err != nil {
+			return err
+		}
+		startCursor = cursor
+	}
+	reply.EndCursor = args.StartCursor
+
 	pendingValidators := service.vm.internalState.PendingStakerChainState()
+	rollups, err := delegationRollups(pendingValidators.Stakers())
+	if err != nil {
+		return err
+	}
 
+	numStakers := 0
 	for _, tx := range pendingValidators.Stakers() { // Iterates in order of increasing start time
+		if !args.sorted() && numStakers >= limit {
+			break
+		}
+		cursor, err := pendingStakerCursor(tx)
+		if err != nil {
+			return err
+		}
+		if hasStartCursor && !cursor.after(startCursor) {
+			continue
+		}
 		switch staker := tx.UnsignedTx.(type) {
 		case *UnsignedAddDelegatorTx:
 			if args.SubnetID != constants.PrimaryNetworkID {
@@ -844,15 +1043,20 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 			if !includeAllNodes && !nodeIDs.Contains(staker.Validator.ID()) {
 				continue
 			}
+			if args.OmitDelegators || !args.matches(uint64(staker.StartTime().Unix()), uint64(staker.EndTime().Unix())) {
+				continue
+			}
 
 			weight := json.Uint64(staker.Validator.Weight())
 			reply.Delegators = append(reply.Delegators, APIStaker{
 				TxID:        tx.ID(),
-				NodeID:      staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
+				NodeID:      address.FormatNodeID(staker.Validator.ID()),
 				StartTime:   json.Uint64(staker.StartTime().Unix()),
 				EndTime:     json.Uint64(staker.EndTime().Unix()),
 				StakeAmount: &weight,
 			})
+			numStakers++
+			reply.EndCursor = cursor.String()
 		case *UnsignedAddValidatorTx:
 			if args.SubnetID != constants.PrimaryNetworkID {
 				continue
@@ -860,23 +1064,28 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 			if !includeAllNodes && !nodeIDs.Contains(staker.Validator.ID()) {
 				continue
 			}
+			if !args.matches(uint64(staker.StartTime().Unix()), uint64(staker.EndTime().Unix())) {
+				continue
+			}
 
 			nodeID := staker.Validator.ID()
 			weight := json.Uint64(staker.Validator.Weight())
 			delegationFee := json.Float32(100 * float32(staker.Shares) / float32(PercentDenominator))
 
 			connected := service.vm.IsConnected(nodeID)
-			reply.Validators = append(reply.Validators, APIPrimaryValidator{
+			reply.Validators = append(reply.Validators, withDelegationRollup(APIPrimaryValidator{
 				APIStaker: APIStaker{
 					TxID:        tx.ID(),
-					NodeID:      staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
+					NodeID:      address.FormatNodeID(staker.Validator.ID()),
 					StartTime:   json.Uint64(staker.StartTime().Unix()),
 					EndTime:     json.Uint64(staker.EndTime().Unix()),
 					StakeAmount: &weight,
 				},
 				DelegationFee: delegationFee,
 				Connected:     &connected,
-			})
+			}, rollups[nodeID]))
+			numStakers++
+			reply.EndCursor = cursor.String()
 		case *UnsignedAddSubnetValidatorTx:
 			if args.SubnetID != staker.Validator.Subnet {
 				continue
@@ -884,19 +1093,39 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 			if !includeAllNodes && !nodeIDs.Contains(staker.Validator.ID()) {
 				continue
 			}
+			if !args.matches(uint64(staker.StartTime().Unix()), uint64(staker.EndTime().Unix())) {
+				continue
+			}
 
 			weight := json.Uint64(staker.Validator.Weight())
 			reply.Validators = append(reply.Validators, APIStaker{
 				TxID:      tx.ID(),
-				NodeID:    staker.Validator.ID().PrefixedString(constants.NodeIDPrefix),
+				NodeID:    address.FormatNodeID(staker.Validator.ID()),
 				StartTime: json.Uint64(staker.StartTime().Unix()),
 				EndTime:   json.Uint64(staker.EndTime().Unix()),
 				Weight:    &weight,
 			})
+			numStakers++
+			reply.EndCursor = cursor.String()
 		default:
 			return fmt.Errorf("expected validator but got %T", tx.UnsignedTx)
 		}
 	}
+
+	if args.sorted() {
+		// Validators and delegators are sorted and paged separately
+		args.sortStakers(reply.Validators)
+		args.sortStakers(reply.Delegators)
+		reply.Validators = page(reply.Validators, startOffsetCursor, limit)
+		reply.Delegators = page(reply.Delegators, startOffsetCursor, limit)
+		numStakers := len(reply.Validators)
+		if len(reply.Delegators) > numStakers {
+			numStakers = len(reply.Delegators)
+		}
+		if numStakers > 0 {
+			reply.EndCursor = (startOffsetCursor + offsetCursor(numStakers)).String()
+		}
+	}
 	return nil
 }
 
@@ -913,6 +1142,107 @@ func (service *Service) GetCurrentSupply(_ *http.Request, _ *struct{}, reply *Ge
 	return nil
 }
 
+// GetStakerRewardArgs are the arguments for calling GetStakerReward
+type GetStakerRewardArgs struct {
+	// ID of the transaction that added the validator or delegator
+	TxID ids.ID `json:"txID"`
+}
+
+// GetStakerRewardReply are the results from calling GetStakerReward
+type GetStakerRewardReply struct {
+	NodeID      string      `json:"nodeID"`
+	StartTime   json.Uint64 `json:"startTime"`
+	EndTime     json.Uint64 `json:"endTime"`
+	StakeAmount json.Uint64 `json:"stakeAmount"`
+	// Reward the staker will receive at the end of its staking period, if it
+	// is rewarded, after the delegation fee has been paid
+	PotentialReward json.Uint64 `json:"potentialReward"`
+	// Portion of [PotentialReward] accrued so far, in proportion to the time
+	// that has been staked. Rewards are only paid at the end of the staking
+	// period.
+	AccruedReward json.Uint64 `json:"accruedReward"`
+	// Percentage of its delegators' rewards that the validator takes
+	DelegationFee json.Float32 `json:"delegationFee"`
+	// For a delegator, the delegation fee it will pay to the validator. For a
+	// validator, the delegation fees it will receive from its current
+	// delegators.
+	DelegationFeeReward json.Uint64 `json:"delegationFeeReward"`
+}
+
+// GetStakerReward returns the projected reward of a current primary network
+// validator or delegator, and how it is split by the delegation fee
+func (service *Service) GetStakerReward(_ *http.Request, args *GetStakerRewardArgs, reply *GetStakerRewardReply) error {
+	service.vm.ctx.Log.Info("Platform: GetStakerReward called with TxID = %s", args.TxID)
+
+	currentStakers := service.vm.internalState.CurrentStakerChainState()
+	tx, potentialReward, err := currentStakers.GetStaker(args.TxID)
+	if err == database.ErrNotFound {
+		return fmt.Errorf("%s isn't a current staker", args.TxID)
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't get staker: %w", err)
+	}
+
+	var (
+		staker TimedTx
+		nodeID ids.ShortID
+	)
+	switch stakerTx := tx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		staker = stakerTx
+		nodeID = stakerTx.Validator.ID()
+		reply.PotentialReward = json.Uint64(potentialReward)
+		reply.DelegationFee = json.Float32(100 * float32(stakerTx.Shares) / float32(PercentDenominator))
+
+		vdr, err := currentStakers.GetValidator(nodeID)
+		if err != nil {
+			return fmt.Errorf("couldn't get validator: %w", err)
+		}
+		delegationFees := uint64(0)
+		for _, delegator := range vdr.Delegators() {
+			_, delegatorReward, err := currentStakers.GetStaker(delegator.ID())
+			if err != nil {
+				return fmt.Errorf("couldn't get delegator: %w", err)
+			}
+			_, delegateeReward := splitReward(delegatorReward, stakerTx.Shares)
+			delegationFees, err = math.Add64(delegationFees, delegateeReward)
+			if err != nil {
+				return err
+			}
+		}
+		reply.DelegationFeeReward = json.Uint64(delegationFees)
+	case *UnsignedAddDelegatorTx:
+		staker = stakerTx
+		nodeID = stakerTx.Validator.ID()
+
+		vdr, err := currentStakers.GetValidator(nodeID)
+		if err != nil {
+			return fmt.Errorf("couldn't get validator: %w", err)
+		}
+		shares := vdr.AddValidatorTx().Shares
+		delegatorReward, delegateeReward := splitReward(potentialReward, shares)
+		reply.PotentialReward = json.Uint64(delegatorReward)
+		reply.DelegationFee = json.Float32(100 * float32(shares) / float32(PercentDenominator))
+		reply.DelegationFeeReward = json.Uint64(delegateeReward)
+	default:
+		return fmt.Errorf("%s isn't a primary network staker", args.TxID)
+	}
+
+	startTime := staker.StartTime()
+	endTime := staker.EndTime()
+	reply.NodeID = address.FormatNodeID(nodeID)
+	reply.StartTime = json.Uint64(startTime.Unix())
+	reply.EndTime = json.Uint64(endTime.Unix())
+	reply.StakeAmount = json.Uint64(staker.Weight())
+	reply.AccruedReward = json.Uint64(accruedReward(
+		uint64(reply.PotentialReward),
+		startTime,
+		endTime,
+		service.vm.internalState.GetTimestamp(),
+	))
+	return nil
+}
+
 // SampleValidatorsArgs are the arguments for calling SampleValidators
 type SampleValidatorsArgs struct {
 	// Number of validators in the sample
@@ -947,13 +1277,130 @@ func (service *Service) SampleValidators(_ *http.Request, args *SampleValidators
 
 	validatorIDs := make([]ids.ShortID, int(args.Size))
 	for i, vdr := range sample {
-		validatorIDs[i] = vdr.ID()
+		validatorIDs[i] = vdr.ID().ShortID()
 	}
 	ids.SortShortIDs(validatorIDs)
 
 	reply.Validators = make([]string, int(args.Size))
 	for i, vdrID := range validatorIDs {
-		reply.Validators[i] = vdrID.PrefixedString(constants.NodeIDPrefix)
+		reply.Validators[i] = address.FormatNodeID(vdrID)
+	}
+	return nil
+}
+
+// GetValidatorsAtArgs are the arguments for calling GetValidatorsAt
+type GetValidatorsAtArgs struct {
+	// ID of the subnet to get the validators of
+	// If omitted, defaults to the primary network
+	SubnetID ids.ID `json:"subnetID"`
+
+	// Height of the block after which to get the validators
+	Height json.Uint64 `json:"height"`
+
+	// Unix time, in seconds, at which to get the validators
+	// If provided, [Height] is ignored
+	Timestamp json.Uint64 `json:"timestamp"`
+}
+
+// GetValidatorsAtReply are the results from calling GetValidatorsAt
+type GetValidatorsAtReply struct {
+	// Height of the block the validators are from
+	Height json.Uint64 `json:"height"`
+	// Node ID --> Weight of the validator
+	Validators map[string]json.Uint64 `json:"validators"`
+}
+
+// GetValidatorsAt returns the validators, and their weights, of a subnet after
+// the block at a given height was accepted, or at a given time
+func (service *Service) GetValidatorsAt(_ *http.Request, args *GetValidatorsAtArgs, reply *GetValidatorsAtReply) error {
+	service.vm.ctx.Log.Info("Platform: GetValidatorsAt called with SubnetID = %s, Height = %d, Timestamp = %d",
+		args.SubnetID, args.Height, args.Timestamp)
+
+	height := uint64(args.Height)
+	if args.Timestamp != 0 {
+		var err error
+		height, err = service.vm.internalState.GetHeightAtTime(time.Unix(int64(args.Timestamp), 0))
+		if err != nil {
+			return fmt.Errorf("couldn't get height at timestamp: %w", err)
+		}
+	}
+
+	weights, err := service.vm.internalState.GetValidatorWeights(args.SubnetID, height)
+	if err != nil {
+		return fmt.Errorf("couldn't get validators at height %d: %w", height, err)
+	}
+
+	reply.Height = json.Uint64(height)
+	reply.Validators = make(map[string]json.Uint64, len(weights))
+	for nodeID, weight := range weights {
+		reply.Validators[address.FormatNodeID(nodeID)] = json.Uint64(weight)
+	}
+	return nil
+}
+
+// GetUptimeHistoryArgs are the arguments for calling GetUptimeHistory
+type GetUptimeHistoryArgs struct {
+	// ID of the primary network validator to get the uptime history of
+	NodeID string `json:"nodeID"`
+	// Source of the uptime history. Defaults to the time that this node was
+	// connected to the validator.
+	Source string `json:"source"`
+}
+
+// APIUptimeBucket is the uptime of a validator during one bucket of its
+// uptime history
+type APIUptimeBucket struct {
+	// Unix time, in seconds, that the bucket starts at
+	Start json.Uint64 `json:"start"`
+	// Number of seconds the validator was observed to be up during the bucket
+	UpDuration json.Uint64 `json:"upDuration"`
+	// Fraction of the bucket the validator was observed to be up
+	Uptime json.Float32 `json:"uptime"`
+}
+
+// GetUptimeHistoryReply are the results from calling GetUptimeHistory
+type GetUptimeHistoryReply struct {
+	// Source of the uptime history
+	Source string `json:"source"`
+	// Number of seconds covered by each bucket
+	BucketDuration json.Uint64 `json:"bucketDuration"`
+	// Buckets in order of increasing start time. Buckets in which the
+	// validator wasn't observed to be up are omitted.
+	Buckets []APIUptimeBucket `json:"buckets"`
+}
+
+// GetUptimeHistory returns the uptime that this node has observed a validator
+// to have, in buckets of fixed length
+func (service *Service) GetUptimeHistory(_ *http.Request, args *GetUptimeHistoryArgs, reply *GetUptimeHistoryReply) error {
+	service.vm.ctx.Log.Info("Platform: GetUptimeHistory called with NodeID = %s, Source = %s", args.NodeID, args.Source)
+
+	nodeID, err := address.ParseNodeID(args.NodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't parse nodeID: %w", err)
+	}
+
+	source := args.Source
+	if source == "" {
+		source = uptime.ConnectedSource
+	}
+
+	buckets, err := service.vm.UptimeHistory(nodeID, source)
+	if err == database.ErrNotFound {
+		return fmt.Errorf("%s isn't a primary network validator", args.NodeID)
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't get uptime history: %w", err)
+	}
+
+	reply.Source = source
+	reply.BucketDuration = json.Uint64(uptime.DefaultHistoryBucketDuration / time.Second)
+	reply.Buckets = make([]APIUptimeBucket, len(buckets))
+	for i, bucket := range buckets {
+		reply.Buckets[i] = APIUptimeBucket{
+			Start:      json.Uint64(bucket.Start.Unix()),
+			UpDuration: json.Uint64(bucket.UpDuration / time.Second),
+			Uptime:     json.Float32(float64(bucket.UpDuration) / float64(uptime.DefaultHistoryBucketDuration)),
+		}
 	}
 	return nil
 }
@@ -1005,7 +1452,7 @@ func (service *Service) AddValidator(_ *http.Request, args *AddValidatorArgs, re
 	if args.NodeID == "" {
 		nodeID = service.vm.ctx.NodeID // If omitted, use this node's ID
 	} else {
-		nID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+		nID, err := address.ParseNodeID(args.NodeID)
 		if err != nil {
 			return err
 		}
@@ -1130,7 +1577,7 @@ func (service *Service) AddDelegator(_ *http.Request, args *AddDelegatorArgs, re
 	if args.NodeID == "" { // If ID unspecified, use this node's ID
 		nodeID = service.vm.ctx.NodeID
 	} else {
-		nID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+		nID, err := address.ParseNodeID(args.NodeID)
 		if err != nil {
 			return err
 		}
@@ -1251,15 +1698,15 @@ func (service *Service) AddSubnetValidator(_ *http.Request, args *AddSubnetValid
 	}
 
 	// Parse the node ID
-	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	nodeID, err := address.ParseNodeID(args.NodeID)
 	if err != nil {
 		return fmt.Errorf("error parsing nodeID: %q: %w", args.NodeID, err)
 	}
 
 	// Parse the subnet ID
-	subnetID, err := ids.FromString(args.SubnetID)
+	subnetID, err := address.ParseID(args.SubnetID)
 	if err != nil {
-		return fmt.Errorf("problem parsing subnetID %q: %w", args.SubnetID, err)
+		return fmt.Errorf("problem parsing subnetID: %w", err)
 	}
 	if subnetID == constants.PrimaryNetworkID {
 		return errors.New("subnet validator attempts to validate primary network")
@@ -1432,6 +1879,139 @@ func (service *Service) CreateSubnet(_ *http.Request, args *CreateSubnetArgs, re
 	return errs.Err
 }
 
+// RegisterBLSKeyArgs are the arguments to RegisterBLSKey
+type RegisterBLSKeyArgs struct {
+	// User, password, from addrs, change addr
+	api.JSONSpendHeader
+	// Registration of the key, as returned by info.getBLSKeyRegistration
+	api.BLSKeyRegistration
+}
+
+// RegisterBLSKey creates and signs and issues a transaction that registers the
+// BLS key that a node attests to validator snapshots with
+func (service *Service) RegisterBLSKey(_ *http.Request, args *RegisterBLSKeyArgs, response *api.JSONTxIDChangeAddr) error {
+	service.vm.ctx.Log.Info("Platform: RegisterBLSKey called")
+
+	registration := validators.BLSKeyRegistration{}
+	fields := []struct {
+		name  string
+		str   string
+		bytes *[]byte
+	}{
+		{"certificate", args.Certificate, &registration.Certificate},
+		{"publicKey", args.PublicKey, &registration.PublicKey},
+		{"proofOfPossession", args.ProofOfPossession, &registration.ProofOfPossession},
+		{"signature", args.Signature, &registration.Signature},
+	}
+	for _, field := range fields {
+		bytes, err := formatting.Decode(formatting.Hex, field.str)
+		if err != nil {
+			return fmt.Errorf("problem decoding %s: %w", field.name, err)
+		}
+		*field.bytes = bytes
+	}
+
+	// Get the keys controlled by the user
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user %q: %w", args.Username, err)
+	}
+	defer db.Close()
+
+	user := user{db: db}
+	privKeys, err := user.getKeys()
+	if err != nil {
+		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
+	}
+
+	// Parse the change address. Assumes that if the user has no keys,
+	// this operation will fail so the change address can be anything.
+	if len(privKeys) == 0 {
+		return errNoKeys
+	}
+	changeAddr := privKeys[0].PublicKey().Address() // By default, use a key controlled by the user
+	if args.ChangeAddr != "" {
+		changeAddr, err = service.vm.ParseLocalAddress(args.ChangeAddr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse changeAddr: %w", err)
+		}
+	}
+
+	// Parse the from addresses
+	fromAddrs := ids.ShortSet{}
+	for _, addrStr := range args.From {
+		addr, err := service.vm.ParseLocalAddress(addrStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse 'from' address %s: %w", addrStr, err)
+		}
+		fromAddrs.Add(addr)
+	}
+
+	// If fromAddrs given, only use those addrs to pay fee
+	filteredPrivKeys := []*crypto.PrivateKeySECP256K1R{}
+	if fromAddrs.Len() == 0 {
+		filteredPrivKeys = privKeys
+	} else {
+		for _, key := range privKeys {
+			if fromAddrs.Contains(key.PublicKey().Address()) {
+				filteredPrivKeys = append(filteredPrivKeys, key)
+			}
+		}
+	}
+
+	// Create the transaction
+	tx, err := service.vm.newRegisterBLSKeyTx(
+		registration,     // Registration
+		filteredPrivKeys, // Private keys
+		changeAddr,       // Change address
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't create tx: %w", err)
+	}
+
+	response.TxID = tx.ID()
+	response.ChangeAddr, err = service.vm.FormatLocalAddress(changeAddr)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		err,
+		service.vm.mempool.IssueTx(tx),
+		db.Close(),
+	)
+	return errs.Err
+}
+
+// GetBLSKeyArgs are the arguments to GetBLSKey
+type GetBLSKeyArgs struct {
+	NodeID string `json:"nodeID"`
+}
+
+// GetBLSKeyReply is the response from GetBLSKey
+type GetBLSKeyReply struct {
+	// Hex encoded BLS public key that the node registered
+	PublicKey string `json:"publicKey"`
+	// ID of the tx that registered the key
+	TxID ids.ID `json:"txID"`
+}
+
+// GetBLSKey returns the BLS key that a node registered
+func (service *Service) GetBLSKey(_ *http.Request, args *GetBLSKeyArgs, response *GetBLSKeyReply) error {
+	service.vm.ctx.Log.Info("Platform: GetBLSKey called")
+
+	nodeID, err := address.ParseNodeID(args.NodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't parse nodeID: %w", err)
+	}
+	tx, err := service.vm.internalState.GetBLSKey(nodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't get BLS key of %s: %w", args.NodeID, err)
+	}
+	registration := tx.UnsignedTx.(*UnsignedRegisterBLSKeyTx).Registration
+	response.PublicKey, err = formatting.Encode(formatting.Hex, registration.PublicKey)
+	response.TxID = tx.ID()
+	return err
+}
+
 // ExportAVAXArgs are the arguments to ExportAVAX
 type ExportAVAXArgs struct {
 	// User, password, from addrs, change addr
@@ -1789,9 +2369,9 @@ func (service *Service) GetBlockchainStatus(_ *http.Request, args *GetBlockchain
 		reply.Status = Validating
 		return nil
 	}
-	blockchainID, err := ids.FromString(args.BlockchainID)
+	blockchainID, err := address.ParseID(args.BlockchainID)
 	if err != nil {
-		return fmt.Errorf("problem parsing blockchainID %q: %w", args.BlockchainID, err)
+		return fmt.Errorf("problem parsing blockchainID: %w", err)
 	}
 	lastAcceptedID, err := service.vm.LastAccepted()
 	if err != nil {
@@ -2058,6 +2638,10 @@ type GetTxStatusResponse struct {
 	// Reason this tx was dropped.
 	// Only non-empty if Status is dropped
 	Reason string `json:"reason,omitempty"`
+	// Whether the UTXOs that the committed tx exports have been written to
+	// shared memory, and the error of the last attempt if they haven't
+	ExportStatus *string `json:"exportStatus,omitempty"`
+	ExportError  string  `json:"exportError,omitempty"`
 }
 
 // GetTxStatus gets a tx's status
@@ -2067,6 +2651,15 @@ func (service *Service) GetTxStatus(_ *http.Request, args *GetTxStatusArgs, resp
 	_, status, err := service.vm.internalState.GetTx(args.TxID)
 	if err == nil { // Found the status. Report it.
 		response.Status = status
+		exportState, err := service.vm.atomicQueue.State(args.TxID)
+		switch {
+		case err == nil:
+			exportStatus := exportState.Status.String()
+			response.ExportStatus = &exportStatus
+			response.ExportError = exportState.LastError
+		case err != database.ErrNotFound:
+			return fmt.Errorf("couldn't get the export status of tx %s: %w", args.TxID, err)
+		}
 		return nil
 	}
 	if err != database.ErrNotFound {
@@ -2315,7 +2908,7 @@ type GetMaxStakeAmountReply struct {
 // GetMaxStakeAmount returns the maximum amount of nAVAX staking to the named
 // node during the time period.
 func (service *Service) GetMaxStakeAmount(_ *http.Request, args *GetMaxStakeAmountArgs, reply *GetMaxStakeAmountReply) error {
-	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	nodeID, err := address.ParseNodeID(args.NodeID)
 	if err != nil {
 		return fmt.Errorf("failed to parse nodeID %q due to: %w", args.NodeID, err)
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/vms/avm"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
@@ -726,7 +727,7 @@ func TestGetCurrentValidators(t *testing.T) {
 	found := false
 	for i := 0; i < len(response.Validators) && !found; i++ {
 		vdr := response.Validators[i].(APIPrimaryValidator)
-		if vdr.NodeID != validatorNodeID.PrefixedString(constants.NodeIDPrefix) {
+		if vdr.NodeID != address.FormatNodeID(validatorNodeID) {
 			continue
 		}
 		found = true
@@ -749,3 +750,238 @@ func TestGetCurrentValidators(t *testing.T) {
 		t.Fatalf("didnt find delegator")
 	}
 }
+
+// Test paging through GetCurrentValidators
+func TestGetCurrentValidatorsPagination(t *testing.T) {
+	assert := assert.New(t)
+	service := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(service.vm.Shutdown())
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	genesis, _ := defaultGenesis()
+
+	seen := ids.Set{}
+	var (
+		lastEndTime cjson.Uint64
+		cursor      string
+	)
+	for {
+		args := GetCurrentValidatorsArgs{
+			SubnetID:    constants.PrimaryNetworkID,
+			Limit:       2,
+			StartCursor: cursor,
+		}
+		response := GetCurrentValidatorsReply{}
+		assert.NoError(service.GetCurrentValidators(nil, &args, &response))
+		assert.LessOrEqual(len(response.Validators), 2)
+		if len(response.Validators) == 0 {
+			assert.Equal(cursor, response.EndCursor)
+			break
+		}
+		for _, vdrIntf := range response.Validators {
+			vdr, ok := vdrIntf.(APIPrimaryValidator)
+			assert.True(ok)
+			assert.False(seen.Contains(vdr.TxID), "validator returned twice")
+			assert.GreaterOrEqual(uint64(vdr.EndTime), uint64(lastEndTime))
+			seen.Add(vdr.TxID)
+			lastEndTime = vdr.EndTime
+		}
+		assert.NotEqual(cursor, response.EndCursor)
+		cursor = response.EndCursor
+	}
+	assert.Equal(len(genesis.Validators), seen.Len())
+
+	// A malformed cursor should be rejected
+	args := GetCurrentValidatorsArgs{
+		SubnetID:    constants.PrimaryNetworkID,
+		StartCursor: "not a cursor",
+	}
+	response := GetCurrentValidatorsReply{}
+	assert.Error(service.GetCurrentValidators(nil, &args, &response))
+}
+
+// Test filtering and sorting GetCurrentValidators
+func TestGetCurrentValidatorsQuery(t *testing.T) {
+	assert := assert.New(t)
+	service := defaultService(t)
+	defaultAddress(t, service)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		assert.NoError(service.vm.Shutdown())
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	genesis, _ := defaultGenesis()
+
+	// Add a delegator
+	stakeAmt := service.vm.MinDelegatorStake + 12345
+	validatorNodeID := keys[1].PublicKey().Address()
+	tx, err := service.vm.newAddDelegatorTx(
+		stakeAmt,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateStartTime.Add(defaultMinStakingDuration).Unix()),
+		validatorNodeID,
+		ids.GenerateTestShortID(),
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	assert.NoError(err)
+	service.vm.internalState.AddCurrentStaker(tx, 0)
+	service.vm.internalState.AddTx(tx, Committed)
+	assert.NoError(service.vm.internalState.Commit())
+	assert.NoError(service.vm.internalState.(*internalStateImpl).loadCurrentValidators())
+
+	// The validator with the most delegators comes first, with its
+	// delegators summed up
+	args := GetCurrentValidatorsArgs{
+		SubnetID: constants.PrimaryNetworkID,
+		Limit:    1,
+		APIValidatorQuery: APIValidatorQuery{
+			SortBy:         SortByDelegatorCount,
+			SortDescending: true,
+			OmitDelegators: true,
+		},
+	}
+	response := GetCurrentValidatorsReply{}
+	assert.NoError(service.GetCurrentValidators(nil, &args, &response))
+	assert.Len(response.Validators, 1)
+	vdr, ok := response.Validators[0].(APIPrimaryValidator)
+	assert.True(ok)
+	assert.Equal(address.FormatNodeID(validatorNodeID), vdr.NodeID)
+	assert.EqualValues(1, *vdr.DelegatorCount)
+	assert.EqualValues(stakeAmt, *vdr.DelegatorWeight)
+	assert.Empty(vdr.Delegators)
+
+	// Sorted validators are paged by offset
+	seen := ids.Set{}
+	cursor := ""
+	for {
+		args := GetCurrentValidatorsArgs{
+			SubnetID:    constants.PrimaryNetworkID,
+			Limit:       2,
+			StartCursor: cursor,
+			APIValidatorQuery: APIValidatorQuery{
+				SortBy: SortByDelegatorWeight,
+			},
+		}
+		response := GetCurrentValidatorsReply{}
+		assert.NoError(service.GetCurrentValidators(nil, &args, &response))
+		if len(response.Validators) == 0 {
+			break
+		}
+		for _, vdrIntf := range response.Validators {
+			vdr := vdrIntf.(APIPrimaryValidator)
+			assert.False(seen.Contains(vdr.TxID), "validator returned twice")
+			seen.Add(vdr.TxID)
+			// The only delegated validator sorts last
+			if seen.Len() < len(genesis.Validators) {
+				assert.EqualValues(0, *vdr.DelegatorWeight)
+			} else {
+				assert.EqualValues(stakeAmt, *vdr.DelegatorWeight)
+			}
+		}
+		cursor = response.EndCursor
+	}
+	assert.Equal(len(genesis.Validators), seen.Len())
+
+	// No validator ends in the time window
+	args = GetCurrentValidatorsArgs{
+		SubnetID: constants.PrimaryNetworkID,
+		APIValidatorQuery: APIValidatorQuery{
+			EndTimeWindow: &APITimeWindow{End: 1},
+		},
+	}
+	response = GetCurrentValidatorsReply{}
+	assert.NoError(service.GetCurrentValidators(nil, &args, &response))
+	assert.Empty(response.Validators)
+
+	args = GetCurrentValidatorsArgs{
+		SubnetID:          constants.PrimaryNetworkID,
+		APIValidatorQuery: APIValidatorQuery{SortBy: "name"},
+	}
+	assert.ErrorIs(service.GetCurrentValidators(nil, &args, &response), errUnknownSortField)
+}
+
+func TestGetStakerReward(t *testing.T) {
+	service := defaultService(t)
+	defaultAddress(t, service)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		if err := service.vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	validatorNodeID := keys[1].PublicKey().Address()
+	vdr, err := service.vm.internalState.CurrentStakerChainState().GetValidator(validatorNodeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validatorTxID := vdr.AddValidatorTx().ID()
+	shares := vdr.AddValidatorTx().Shares
+
+	// Add a delegator
+	stakeAmt := service.vm.MinDelegatorStake + 12345
+	delegatorStartTime := defaultValidateStartTime
+	delegatorEndTime := defaultValidateStartTime.Add(defaultMinStakingDuration)
+	delegatorReward := uint64(1000000)
+
+	tx, err := service.vm.newAddDelegatorTx(
+		stakeAmt,
+		uint64(delegatorStartTime.Unix()),
+		uint64(delegatorEndTime.Unix()),
+		validatorNodeID,
+		ids.GenerateTestShortID(),
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service.vm.internalState.AddCurrentStaker(tx, delegatorReward)
+	service.vm.internalState.AddTx(tx, Committed)
+	if err := service.vm.internalState.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.vm.internalState.(*internalStateImpl).loadCurrentValidators(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDelegatorReward, expectedFee := splitReward(delegatorReward, shares)
+
+	reply := GetStakerRewardReply{}
+	if err := service.GetStakerReward(nil, &GetStakerRewardArgs{TxID: tx.ID()}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case reply.NodeID != address.FormatNodeID(validatorNodeID):
+		t.Fatal("wrong node ID")
+	case uint64(reply.StakeAmount) != stakeAmt:
+		t.Fatal("wrong stake amount")
+	case uint64(reply.PotentialReward) != expectedDelegatorReward:
+		t.Fatalf("expected potential reward %d but got %d", expectedDelegatorReward, reply.PotentialReward)
+	case uint64(reply.DelegationFeeReward) != expectedFee:
+		t.Fatalf("expected delegation fee %d but got %d", expectedFee, reply.DelegationFeeReward)
+	case uint64(reply.AccruedReward) != 0:
+		t.Fatal("shouldn't have accrued a reward before the staking period started")
+	}
+
+	// The validator receives the delegation fee
+	reply = GetStakerRewardReply{}
+	if err := service.GetStakerReward(nil, &GetStakerRewardArgs{TxID: validatorTxID}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if uint64(reply.DelegationFeeReward) != expectedFee {
+		t.Fatalf("expected delegation fees %d but got %d", expectedFee, reply.DelegationFeeReward)
+	}
+
+	err = service.GetStakerReward(nil, &GetStakerRewardArgs{TxID: ids.GenerateTestID()}, &reply)
+	if err == nil {
+		t.Fatal("should have errored for an unknown staker")
+	}
+}
@@ -0,0 +1,115 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// Max number of stakers that can be returned by a single call to
+	// GetCurrentValidators or GetPendingValidators
+	maxStakersToFetch = 1024
+
+	stakerCursorLen = wrappers.LongLen + wrappers.ByteLen + hashing.HashLen
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// stakerCursor is the position of a staker in the current or pending staker
+// set. Stakers are sorted by time, then by descending priority, then by tx ID,
+// which is the order the staker sets are iterated in.
+type stakerCursor struct {
+	time     uint64
+	priority byte
+	txID     ids.ID
+}
+
+// currentStakerCursor returns the position of [tx] in the current staker set,
+// which is sorted in the order the stakers will be removed.
+func currentStakerCursor(tx *Tx) (stakerCursor, error) {
+	cursor := stakerCursor{txID: tx.ID()}
+	switch staker := tx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		cursor.time = uint64(staker.EndTime().Unix())
+		cursor.priority = lowPriority
+	case *UnsignedAddDelegatorTx:
+		cursor.time = uint64(staker.EndTime().Unix())
+		cursor.priority = mediumPriority
+	case *UnsignedAddSubnetValidatorTx:
+		cursor.time = uint64(staker.EndTime().Unix())
+		cursor.priority = topPriority
+	default:
+		return stakerCursor{}, fmt.Errorf("expected staker tx type but got %T", tx.UnsignedTx)
+	}
+	return cursor, nil
+}
+
+// pendingStakerCursor returns the position of [tx] in the pending staker set,
+// which is sorted in the order the stakers will be added.
+func pendingStakerCursor(tx *Tx) (stakerCursor, error) {
+	cursor := stakerCursor{txID: tx.ID()}
+	switch staker := tx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		cursor.time = uint64(staker.StartTime().Unix())
+		cursor.priority = mediumPriority
+	case *UnsignedAddDelegatorTx:
+		cursor.time = uint64(staker.StartTime().Unix())
+		cursor.priority = topPriority
+	case *UnsignedAddSubnetValidatorTx:
+		cursor.time = uint64(staker.StartTime().Unix())
+		cursor.priority = lowPriority
+	default:
+		return stakerCursor{}, fmt.Errorf("expected staker tx type but got %T", tx.UnsignedTx)
+	}
+	return cursor, nil
+}
+
+// after returns true if [c] is strictly after [other] in the staker set
+func (c stakerCursor) after(other stakerCursor) bool {
+	if c.time != other.time {
+		return c.time > other.time
+	}
+	if c.priority != other.priority {
+		return c.priority < other.priority
+	}
+	return bytes.Compare(c.txID[:], other.txID[:]) > 0
+}
+
+// String returns the opaque string representation of this cursor that is
+// handed out to API clients
+func (c stakerCursor) String() string {
+	p := wrappers.Packer{Bytes: make([]byte, stakerCursorLen)}
+	p.PackLong(c.time)
+	p.PackByte(c.priority)
+	p.PackFixedBytes(c.txID[:])
+	// Encoding a fixed length byte slice with CB58 can't fail
+	str, _ := formatting.Encode(formatting.CB58, p.Bytes)
+	return str
+}
+
+// parseStakerCursor parses a cursor previously returned by String
+func parseStakerCursor(str string) (stakerCursor, error) {
+	b, err := formatting.Decode(formatting.CB58, str)
+	if err != nil {
+		return stakerCursor{}, fmt.Errorf("%w: %s", errInvalidCursor, err)
+	}
+	if len(b) != stakerCursorLen {
+		return stakerCursor{}, fmt.Errorf("%w: expected %d bytes but got %d", errInvalidCursor, stakerCursorLen, len(b))
+	}
+	p := wrappers.Packer{Bytes: b}
+	cursor := stakerCursor{
+		time:     p.UnpackLong(),
+		priority: p.UnpackByte(),
+	}
+	copy(cursor.txID[:], p.UnpackFixedBytes(hashing.HashLen))
+	return cursor, p.Err
+}
@@ -0,0 +1,40 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestStakerCursor(t *testing.T) {
+	assert := assert.New(t)
+
+	cursor := stakerCursor{
+		time:     12345,
+		priority: mediumPriority,
+		txID:     ids.GenerateTestID(),
+	}
+	parsed, err := parseStakerCursor(cursor.String())
+	assert.NoError(err)
+	assert.Equal(cursor, parsed)
+	assert.False(cursor.after(parsed))
+
+	later := cursor
+	later.time++
+	assert.True(later.after(cursor))
+	assert.False(cursor.after(later))
+
+	// Higher priority stakers are iterated first
+	lowerPriority := cursor
+	lowerPriority.priority = lowPriority
+	assert.True(lowerPriority.after(cursor))
+	assert.False(cursor.after(lowerPriority))
+
+	_, err = parseStakerCursor("")
+	assert.ErrorIs(err, errInvalidCursor)
+}
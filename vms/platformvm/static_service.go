@@ -11,8 +11,8 @@ import (
 	"sort"
 
 	"github.com/ava-labs/avalanchego/ids"
-	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -78,6 +78,9 @@ type APIPrimaryValidator struct {
 	Staked             []APIUTXO     `json:"staked,omitempty"`
 	// The delegators delegating to this validator
 	Delegators []APIPrimaryDelegator `json:"delegators"`
+	// Number of delegators delegating to this validator and their total stake
+	DelegatorCount  *json.Uint64 `json:"delegatorCount,omitempty"`
+	DelegatorWeight *json.Uint64 `json:"delegatorWeight,omitempty"`
 }
 
 // APIPrimaryDelegator is the repr. of a primary network delegator sent over APIs.
@@ -170,8 +173,8 @@ func (g *Genesis) Initialize() error {
 }
 
 // beck32ToID takes bech32 address and produces a shortID
-func bech32ToID(address string) (ids.ShortID, error) {
-	_, addr, err := formatting.ParseBech32(address)
+func bech32ToID(addrStr string) (ids.ShortID, error) {
+	_, addr, err := address.ParseBech32(addrStr)
 	if err != nil {
 		return ids.ShortID{}, err
 	}
@@ -266,7 +269,7 @@ func (ss *StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, r
 		if uint64(validator.EndTime) <= uint64(args.Time) {
 			return errValidatorAddsNoValue
 		}
-		nodeID, err := ids.ShortFromPrefixedString(validator.NodeID, constants.NodeIDPrefix)
+		nodeID, err := address.ParseNodeID(validator.NodeID)
 		if err != nil {
 			return err
 		}
@@ -9,14 +9,15 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
 )
 
 func TestBuildGenesisInvalidUTXOBalance(t *testing.T) {
 	id := ids.ShortID{1, 2, 3}
-	nodeID := id.PrefixedString(constants.NodeIDPrefix)
+	nodeID := address.FormatNodeID(id)
 	hrp := constants.NetworkIDToHRP[testNetworkID]
-	addr, err := formatting.FormatBech32(hrp, id.Bytes())
+	addr, err := address.FormatBech32(hrp, id.Bytes())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -61,9 +62,9 @@ func TestBuildGenesisInvalidUTXOBalance(t *testing.T) {
 
 func TestBuildGenesisInvalidAmount(t *testing.T) {
 	id := ids.ShortID{1, 2, 3}
-	nodeID := id.PrefixedString(constants.NodeIDPrefix)
+	nodeID := address.FormatNodeID(id)
 	hrp := constants.NetworkIDToHRP[testNetworkID]
-	addr, err := formatting.FormatBech32(hrp, id.Bytes())
+	addr, err := address.FormatBech32(hrp, id.Bytes())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -108,9 +109,9 @@ func TestBuildGenesisInvalidAmount(t *testing.T) {
 
 func TestBuildGenesisInvalidEndtime(t *testing.T) {
 	id := ids.ShortID{1, 2, 3}
-	nodeID := id.PrefixedString(constants.NodeIDPrefix)
+	nodeID := address.FormatNodeID(id)
 	hrp := constants.NetworkIDToHRP[testNetworkID]
-	addr, err := formatting.FormatBech32(hrp, id.Bytes())
+	addr, err := address.FormatBech32(hrp, id.Bytes())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -156,9 +157,9 @@ func TestBuildGenesisInvalidEndtime(t *testing.T) {
 
 func TestBuildGenesisReturnsSortedValidators(t *testing.T) {
 	id := ids.ShortID{1}
-	nodeID := id.PrefixedString(constants.NodeIDPrefix)
+	nodeID := address.FormatNodeID(id)
 	hrp := constants.NetworkIDToHRP[testNetworkID]
-	addr, err := formatting.FormatBech32(hrp, id.Bytes())
+	addr, err := address.FormatBech32(hrp, id.Bytes())
 	if err != nil {
 		t.Fatal(err)
 	}
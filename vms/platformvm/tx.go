@@ -9,7 +9,6 @@ import (
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
-	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
@@ -60,7 +59,7 @@ type UnsignedAtomicTx interface {
 	SemanticVerify(vm *VM, parentState MutableState, stx *Tx) (VersionedState, TxError)
 
 	// Accept this transaction with the additionally provided state transitions.
-	Accept(ctx *snow.Context, batch database.Batch) error
+	Accept(vm *VM, batch database.Batch) error
 }
 
 // Tx is a signed transaction
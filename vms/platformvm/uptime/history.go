@@ -0,0 +1,227 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package uptime
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// DefaultHistoryBucketDuration is the length of time covered by each
+	// bucket of a validator's uptime history
+	DefaultHistoryBucketDuration = time.Hour
+
+	// DefaultHistoryBuckets is the number of buckets of uptime history kept
+	// for each validator
+	DefaultHistoryBuckets = 7 * 24
+
+	// DefaultHistorySampleInterval is how often validators' liveness is
+	// sampled into their uptime histories
+	DefaultHistorySampleInterval = time.Minute
+)
+
+// Bucket is the amount of time a validator was observed to be up during the
+// bucket starting at [Start].
+type Bucket struct {
+	Start      time.Time
+	UpDuration time.Duration
+}
+
+// history keeps the uptime that one source has observed for each validator in
+// fixed length buckets. If [db] isn't nil, each validator's buckets are loaded
+// from it when they're first needed and written to it on Commit.
+type history struct {
+	db             database.Database
+	bucketDuration time.Duration
+	numBuckets     int
+
+	// Each validator's buckets, in order of increasing start time. Buckets in
+	// which the validator wasn't observed to be up aren't stored.
+	buckets map[ids.ShortID][]Bucket
+	// The validators whose buckets have been loaded from [db]
+	loaded ids.ShortSet
+	// The validators whose buckets have changed since the last Commit
+	dirty ids.ShortSet
+}
+
+func newHistory(db database.Database, bucketDuration time.Duration, numBuckets int) *history {
+	return &history{
+		db:             db,
+		bucketDuration: bucketDuration,
+		numBuckets:     numBuckets,
+		buckets:        make(map[ids.ShortID][]Bucket),
+	}
+}
+
+// Record that [nodeID] was up from [start] until [end]
+func (h *history) Record(nodeID ids.ShortID, start, end time.Time) error {
+	buckets, err := h.load(nodeID)
+	if err != nil {
+		return err
+	}
+	for start.Before(end) {
+		bucketStart := start.Truncate(h.bucketDuration)
+		bucketEnd := bucketStart.Add(h.bucketDuration)
+		if end.Before(bucketEnd) {
+			bucketEnd = end
+		}
+		buckets = h.add(buckets, bucketStart, bucketEnd.Sub(start))
+		start = bucketEnd
+	}
+	h.set(nodeID, h.trim(buckets, end))
+	return nil
+}
+
+// Add [upDuration] to the bucket of [nodeID] that starts at [bucketStart]
+func (h *history) Add(nodeID ids.ShortID, bucketStart time.Time, upDuration time.Duration, now time.Time) error {
+	if upDuration <= 0 {
+		return nil
+	}
+	buckets, err := h.load(nodeID)
+	if err != nil {
+		return err
+	}
+	buckets = h.add(buckets, bucketStart.Truncate(h.bucketDuration), upDuration)
+	h.set(nodeID, h.trim(buckets, now))
+	return nil
+}
+
+// Get returns the buckets of [nodeID] that haven't expired by [now]
+func (h *history) Get(nodeID ids.ShortID, now time.Time) ([]Bucket, error) {
+	buckets, err := h.load(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := h.trim(buckets, now)
+	if len(trimmed) != len(buckets) {
+		h.set(nodeID, trimmed)
+	}
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+	return append([]Bucket(nil), trimmed...), nil
+}
+
+// Commit writes the buckets that have changed to [h.db]
+func (h *history) Commit() error {
+	if h.db == nil {
+		h.dirty.Clear()
+		return nil
+	}
+	for nodeID := range h.dirty {
+		buckets := h.buckets[nodeID]
+		if len(buckets) == 0 {
+			if err := h.db.Delete(nodeID.Bytes()); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := h.db.Put(nodeID.Bytes(), marshalBuckets(buckets)); err != nil {
+			return err
+		}
+	}
+	h.dirty.Clear()
+	return nil
+}
+
+// load returns the buckets of [nodeID], reading them from [h.db] if they
+// haven't been read yet
+func (h *history) load(nodeID ids.ShortID) ([]Bucket, error) {
+	if h.db == nil || h.loaded.Contains(nodeID) {
+		return h.buckets[nodeID], nil
+	}
+	bytes, err := h.db.Get(nodeID.Bytes())
+	switch err {
+	case nil:
+		buckets, err := unmarshalBuckets(bytes)
+		if err != nil {
+			return nil, err
+		}
+		h.buckets[nodeID] = buckets
+	case database.ErrNotFound:
+	default:
+		return nil, err
+	}
+	h.loaded.Add(nodeID)
+	return h.buckets[nodeID], nil
+}
+
+// set replaces the buckets of [nodeID] and marks them as changed
+func (h *history) set(nodeID ids.ShortID, buckets []Bucket) {
+	if len(buckets) == 0 {
+		delete(h.buckets, nodeID)
+	} else {
+		h.buckets[nodeID] = buckets
+	}
+	h.dirty.Add(nodeID)
+}
+
+// add [upDuration] to the bucket in [buckets] that starts at [bucketStart],
+// keeping [buckets] in order and each bucket no longer than [h.bucketDuration]
+func (h *history) add(buckets []Bucket, bucketStart time.Time, upDuration time.Duration) []Bucket {
+	i := len(buckets)
+	for i > 0 && bucketStart.Before(buckets[i-1].Start) {
+		i--
+	}
+	if i > 0 && buckets[i-1].Start.Equal(bucketStart) {
+		buckets[i-1].UpDuration += upDuration
+		if buckets[i-1].UpDuration > h.bucketDuration {
+			buckets[i-1].UpDuration = h.bucketDuration
+		}
+		return buckets
+	}
+	if upDuration > h.bucketDuration {
+		upDuration = h.bucketDuration
+	}
+	buckets = append(buckets, Bucket{})
+	copy(buckets[i+1:], buckets[i:])
+	buckets[i] = Bucket{
+		Start:      bucketStart,
+		UpDuration: upDuration,
+	}
+	return buckets
+}
+
+// trim removes the buckets that have expired by [now]
+func (h *history) trim(buckets []Bucket, now time.Time) []Bucket {
+	oldestStart := now.Truncate(h.bucketDuration).Add(-time.Duration(h.numBuckets-1) * h.bucketDuration)
+	i := 0
+	for i < len(buckets) && buckets[i].Start.Before(oldestStart) {
+		i++
+	}
+	return buckets[i:]
+}
+
+func marshalBuckets(buckets []Bucket) []byte {
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.IntLen+len(buckets)*2*wrappers.LongLen)}
+	p.PackInt(uint32(len(buckets)))
+	for _, bucket := range buckets {
+		p.PackLong(uint64(bucket.Start.Unix()))
+		p.PackLong(uint64(bucket.UpDuration))
+	}
+	return p.Bytes
+}
+
+func unmarshalBuckets(bytes []byte) ([]Bucket, error) {
+	p := wrappers.Packer{Bytes: bytes}
+	numBuckets := p.UnpackInt()
+	if p.Errored() || uint64(numBuckets)*2*wrappers.LongLen > uint64(len(bytes)) {
+		return nil, errCorruptedHistory
+	}
+	buckets := make([]Bucket, numBuckets)
+	for i := range buckets {
+		buckets[i] = Bucket{
+			Start:      time.Unix(int64(p.UnpackLong()), 0),
+			UpDuration: time.Duration(p.UnpackLong()),
+		}
+	}
+	if p.Errored() || p.Offset != len(bytes) {
+		return nil, errCorruptedHistory
+	}
+	return buckets, nil
+}
@@ -0,0 +1,142 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package uptime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryRecordSplitsBuckets(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID := ids.GenerateTestShortID()
+	h := newHistory(nil, time.Hour, 3)
+	start := time.Unix(0, 0).Add(30 * time.Minute)
+
+	assert.NoError(h.Record(nodeID, start, start.Add(time.Hour)))
+	assert.NoError(h.Record(nodeID, start.Add(time.Hour+15*time.Minute), start.Add(time.Hour+20*time.Minute)))
+
+	buckets, err := h.Get(nodeID, start.Add(2*time.Hour))
+	assert.NoError(err)
+	assert.Equal([]Bucket{
+		{Start: time.Unix(0, 0), UpDuration: 30 * time.Minute},
+		{Start: time.Unix(0, 0).Add(time.Hour), UpDuration: 35 * time.Minute},
+	}, buckets)
+}
+
+func TestHistoryExpiresBuckets(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID := ids.GenerateTestShortID()
+	h := newHistory(nil, time.Hour, 2)
+	start := time.Unix(0, 0)
+
+	assert.NoError(h.Record(nodeID, start, start.Add(3*time.Hour-time.Minute)))
+	buckets, err := h.Get(nodeID, start.Add(3*time.Hour-time.Minute))
+	assert.NoError(err)
+	assert.Equal([]Bucket{
+		{Start: start.Add(time.Hour), UpDuration: time.Hour},
+		{Start: start.Add(2 * time.Hour), UpDuration: time.Hour - time.Minute},
+	}, buckets)
+
+	buckets, err = h.Get(nodeID, start.Add(3*time.Hour))
+	assert.NoError(err)
+	assert.Equal([]Bucket{
+		{Start: start.Add(2 * time.Hour), UpDuration: time.Hour - time.Minute},
+	}, buckets)
+
+	buckets, err = h.Get(nodeID, start.Add(4*time.Hour))
+	assert.NoError(err)
+	assert.Empty(buckets)
+	assert.Empty(h.buckets, "expired validators should have been removed")
+}
+
+func TestHistoryIgnoresEmptyIntervals(t *testing.T) {
+	nodeID := ids.GenerateTestShortID()
+	h := newHistory(nil, time.Hour, 2)
+	start := time.Unix(0, 0)
+
+	assert.NoError(t, h.Record(nodeID, start, start))
+	assert.NoError(t, h.Record(nodeID, start.Add(time.Second), start))
+	buckets, err := h.Get(nodeID, start)
+	assert.NoError(t, err)
+	assert.Empty(t, buckets)
+}
+
+func TestHistoryAddKeepsOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID := ids.GenerateTestShortID()
+	h := newHistory(nil, time.Hour, 3)
+	start := time.Unix(0, 0)
+	now := start.Add(2*time.Hour + time.Minute)
+
+	assert.NoError(h.Add(nodeID, start.Add(2*time.Hour), time.Minute, now))
+	assert.NoError(h.Add(nodeID, start, time.Minute, now))
+	assert.NoError(h.Add(nodeID, start, time.Hour, now))
+	assert.NoError(h.Add(nodeID, start.Add(time.Hour), 0, now))
+
+	buckets, err := h.Get(nodeID, now)
+	assert.NoError(err)
+	assert.Equal([]Bucket{
+		{Start: start, UpDuration: time.Hour},
+		{Start: start.Add(2 * time.Hour), UpDuration: time.Minute},
+	}, buckets)
+}
+
+func TestHistoryPersists(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	nodeID0 := ids.GenerateTestShortID()
+	nodeID1 := ids.GenerateTestShortID()
+	start := time.Unix(0, 0)
+
+	h := newHistory(db, time.Hour, 2)
+	assert.NoError(h.Record(nodeID0, start.Add(30*time.Minute), start.Add(90*time.Minute)))
+	assert.NoError(h.Record(nodeID1, start, start.Add(time.Minute)))
+
+	// Nothing is written until the history is committed
+	h = newHistory(db, time.Hour, 2)
+	buckets, err := h.Get(nodeID0, start.Add(90*time.Minute))
+	assert.NoError(err)
+	assert.Empty(buckets)
+
+	h = newHistory(db, time.Hour, 2)
+	assert.NoError(h.Record(nodeID0, start.Add(30*time.Minute), start.Add(90*time.Minute)))
+	assert.NoError(h.Record(nodeID1, start, start.Add(time.Minute)))
+	assert.NoError(h.Commit())
+
+	h = newHistory(db, time.Hour, 2)
+	buckets, err = h.Get(nodeID0, start.Add(90*time.Minute))
+	assert.NoError(err)
+	assert.Equal([]Bucket{
+		{Start: start, UpDuration: 30 * time.Minute},
+		{Start: start.Add(time.Hour), UpDuration: 30 * time.Minute},
+	}, buckets)
+
+	// Expired buckets are removed from the database
+	buckets, err = h.Get(nodeID1, start.Add(2*time.Hour))
+	assert.NoError(err)
+	assert.Empty(buckets)
+	assert.NoError(h.Commit())
+	has, err := db.Has(nodeID1.Bytes())
+	assert.NoError(err)
+	assert.False(has)
+}
+
+func TestHistoryCorrupted(t *testing.T) {
+	db := memdb.New()
+	nodeID := ids.GenerateTestShortID()
+	assert.NoError(t, db.Put(nodeID.Bytes(), []byte{0, 0, 0, 1}))
+
+	h := newHistory(db, time.Hour, 2)
+	_, err := h.Get(nodeID, time.Unix(0, 0))
+	assert.Equal(t, errCorruptedHistory, err)
+}
@@ -4,14 +4,33 @@
 package uptime
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/timer"
 )
 
-var _ TestManager = &manager{}
+const (
+	// ConnectedSource is the uptime history source of the time that this node
+	// was connected to validators
+	ConnectedSource = "connected"
+
+	// PeerReportedSource is the uptime history source of the stake weighted
+	// median of the uptimes that validators report each other to have
+	PeerReportedSource = "peers"
+)
+
+var (
+	errUnknownSource    = errors.New("unknown uptime history source")
+	errCorruptedHistory = errors.New("corrupted uptime history")
+
+	_ TestManager = &manager{}
+)
 
 type State interface {
 	GetUptime(nodeID ids.ShortID) (upDuration time.Duration, lastUpdated time.Time, err error)
@@ -31,6 +50,17 @@ type Manager interface {
 
 	CalculateUptime(nodeID ids.ShortID) (time.Duration, time.Time, error)
 	CalculateUptimePercent(nodeID ids.ShortID, startTime time.Time) (float64, error)
+
+	// Sample records, in each source's uptime history, whether each of
+	// [nodeIDs] has been up since the last sample. When a bucket ends, this
+	// node's report of it is set and the peer reported uptimes of the bucket
+	// before it are recorded.
+	Sample(nodeIDs []ids.ShortID) error
+
+	// UptimeHistory returns the uptime that [source] has observed [nodeID] to
+	// have, in buckets of [DefaultHistoryBucketDuration]. Buckets in which
+	// [nodeID] wasn't observed to be up are omitted.
+	UptimeHistory(nodeID ids.ShortID, source string) ([]Bucket, error)
 }
 
 type TestManager interface {
@@ -45,13 +75,51 @@ type manager struct {
 	state           State
 	connections     map[ids.ShortID]time.Time
 	startedTracking bool
+
+	// The uptime histories are written to [historyDB], if it isn't nil
+	historyDB database.Database
+	// Source --> The uptime history that it has observed
+	histories map[string]*history
+	reports   validators.UptimeReports
+	sources   validators.LivenessSources
+	// The last time that the uptime histories were sampled
+	lastSample time.Time
 }
 
-func NewManager(state State) Manager {
-	return &manager{
+// NewManager returns a new Manager. [historyDB], [reports] and [sources] may
+// be nil, in which case the uptime histories are only kept in memory, peers'
+// reports aren't exchanged and only the connected source is sampled.
+func NewManager(
+	state State,
+	historyDB database.Database,
+	reports validators.UptimeReports,
+	sources validators.LivenessSources,
+) Manager {
+	m := &manager{
 		state:       state,
 		connections: make(map[ids.ShortID]time.Time),
+		historyDB:   historyDB,
+		histories:   make(map[string]*history),
+		reports:     reports,
+		sources:     sources,
 	}
+	m.history(ConnectedSource)
+	m.history(PeerReportedSource)
+	return m
+}
+
+// history returns the uptime history of [source], creating it if needed
+func (m *manager) history(source string) *history {
+	h, exists := m.histories[source]
+	if !exists {
+		var db database.Database
+		if m.historyDB != nil {
+			db = prefixdb.New([]byte(source), m.historyDB)
+		}
+		h = newHistory(db, DefaultHistoryBucketDuration, DefaultHistoryBuckets)
+		m.histories[source] = h
+	}
+	return h
 }
 
 func (m *manager) StartTracking(nodeIDs []ids.ShortID) error {
@@ -75,10 +143,17 @@ func (m *manager) StartTracking(nodeIDs []ids.ShortID) error {
 		}
 	}
 	m.startedTracking = true
+	m.lastSample = currentLocalTime
 	return nil
 }
 
 func (m *manager) Shutdown(nodeIDs []ids.ShortID) error {
+	if m.startedTracking {
+		if err := m.Sample(nodeIDs); err != nil {
+			return err
+		}
+	}
+
 	currentLocalTime := m.clock.Time()
 	for _, nodeID := range nodeIDs {
 		if _, connected := m.connections[nodeID]; connected {
@@ -103,7 +178,7 @@ func (m *manager) Shutdown(nodeIDs []ids.ShortID) error {
 			return err
 		}
 	}
-	return nil
+	return m.commitHistories()
 }
 
 func (m *manager) Connect(nodeID ids.ShortID) error {
@@ -122,6 +197,14 @@ func (m *manager) Disconnect(nodeID ids.ShortID) error {
 		return nil
 	}
 
+	// Record the time connected since the last sample, which the next sample
+	// won't include
+	if _, _, err := m.state.GetUptime(nodeID); err == nil {
+		if err := m.recordConnection(m.history(ConnectedSource), nodeID); err != nil {
+			return err
+		}
+	}
+
 	newDuration, newLastUpdated, err := m.CalculateUptime(nodeID)
 	delete(m.connections, nodeID)
 	if err == database.ErrNotFound {
@@ -134,6 +217,114 @@ func (m *manager) Disconnect(nodeID ids.ShortID) error {
 	return m.state.SetUptime(nodeID, newDuration, newLastUpdated)
 }
 
+// recordConnection records the time [nodeID] has been connected since the
+// last sample in [h].
+func (m *manager) recordConnection(h *history, nodeID ids.ShortID) error {
+	timeConnected, isConnected := m.connections[nodeID]
+	if !isConnected {
+		return nil
+	}
+	if timeConnected.Before(m.lastSample) {
+		timeConnected = m.lastSample
+	}
+	return h.Record(nodeID, timeConnected, m.clock.Time())
+}
+
+func (m *manager) Sample(nodeIDs []ids.ShortID) error {
+	if !m.startedTracking {
+		return nil
+	}
+	now := m.clock.Time()
+	// If we are in a weird reality where time has gone backwards, wait for it
+	// to catch up with the last sample.
+	if now.Before(m.lastSample) {
+		return nil
+	}
+
+	connected := m.history(ConnectedSource)
+	for _, nodeID := range nodeIDs {
+		if err := m.recordConnection(connected, nodeID); err != nil {
+			return err
+		}
+	}
+	if m.sources != nil {
+		for source, liveness := range m.sources.List() {
+			if source == ConnectedSource || source == PeerReportedSource {
+				continue
+			}
+			h := m.history(source)
+			for _, nodeID := range nodeIDs {
+				if !liveness.IsUp(nodeID) {
+					continue
+				}
+				if err := h.Record(nodeID, m.lastSample, now); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	lastBucket := m.lastSample.Truncate(DefaultHistoryBucketDuration)
+	currentBucket := now.Truncate(DefaultHistoryBucketDuration)
+	m.lastSample = now
+	if !currentBucket.After(lastBucket) {
+		return nil
+	}
+
+	// A bucket ended since the last sample
+	if m.reports != nil {
+		if err := m.exchangeReports(nodeIDs, currentBucket.Add(-DefaultHistoryBucketDuration), now); err != nil {
+			return err
+		}
+	}
+	return m.commitHistories()
+}
+
+// exchangeReports sets this node's report of the bucket starting at [ended]
+// and records the peer reported uptimes of the bucket before it, which peers
+// have had a whole bucket to report on.
+func (m *manager) exchangeReports(nodeIDs []ids.ShortID, ended, now time.Time) error {
+	connected := m.history(ConnectedSource)
+	report := validators.UptimeReport{
+		Start:   ended,
+		Uptimes: make(map[ids.NodeID]time.Duration, len(nodeIDs)),
+	}
+	for _, nodeID := range nodeIDs {
+		buckets, err := connected.Get(nodeID, now)
+		if err != nil {
+			return err
+		}
+		upDuration := time.Duration(0)
+		for _, bucket := range buckets {
+			if bucket.Start.Equal(ended) {
+				upDuration = bucket.UpDuration
+				break
+			}
+		}
+		report.Uptimes[ids.NodeIDFromShortID(nodeID)] = upDuration
+	}
+	m.reports.SetLocal(report)
+
+	reportedStart := ended.Add(-DefaultHistoryBucketDuration)
+	peers := m.history(PeerReportedSource)
+	for nodeID, upDuration := range m.reports.Take(reportedStart) {
+		if err := peers.Add(nodeID.ShortID(), reportedStart, upDuration, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commitHistories writes the changes to all of the uptime histories
+func (m *manager) commitHistories() error {
+	for _, h := range m.histories {
+		if err := h.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *manager) CalculateUptime(nodeID ids.ShortID) (time.Duration, time.Time, error) {
 	upDuration, lastUpdated, err := m.state.GetUptime(nodeID)
 	if err != nil {
@@ -184,6 +375,36 @@ func (m *manager) CalculateUptimePercent(nodeID ids.ShortID, startTime time.Time
 	return uptime, nil
 }
 
+func (m *manager) UptimeHistory(nodeID ids.ShortID, source string) ([]Bucket, error) {
+	if _, _, err := m.state.GetUptime(nodeID); err != nil {
+		return nil, err
+	}
+
+	h, exists := m.histories[source]
+	if !exists {
+		if m.sources == nil {
+			return nil, fmt.Errorf("%w: %s", errUnknownSource, source)
+		}
+		if _, registered := m.sources.List()[source]; !registered {
+			return nil, fmt.Errorf("%w: %s", errUnknownSource, source)
+		}
+		h = m.history(source)
+	}
+
+	buckets, err := h.Get(nodeID, m.clock.Time())
+	if err != nil || source != ConnectedSource || !m.startedTracking {
+		return buckets, err
+	}
+
+	// Include the current connection without modifying the recorded history
+	current := newHistory(nil, h.bucketDuration, h.numBuckets)
+	current.buckets[nodeID] = buckets
+	if err := m.recordConnection(current, nodeID); err != nil {
+		return nil, err
+	}
+	return current.buckets[nodeID], nil
+}
+
 func (m *manager) SetTime(newTime time.Time) {
 	m.clock.Set(newTime)
 }
@@ -9,10 +9,18 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/stretchr/testify/assert"
 )
 
+type testLivenessSource struct {
+	up ids.ShortSet
+}
+
+func (s *testLivenessSource) IsUp(nodeID ids.ShortID) bool { return s.up.Contains(nodeID) }
+
 type uptime struct {
 	upDuration  time.Duration
 	lastUpdated time.Time
@@ -63,7 +71,7 @@ func TestStartTracking(t *testing.T) {
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 
 	currentTime := startTime.Add(time.Second)
 	up.clock.Set(currentTime)
@@ -87,7 +95,7 @@ func TestStartTrackingDBError(t *testing.T) {
 	s.dbWriteError = errors.New("err")
 	s.addNode(nodeID0, startTime)
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 
 	currentTime := startTime.Add(time.Second)
 	up.clock.Set(currentTime)
@@ -100,7 +108,7 @@ func TestStartTrackingNonValidator(t *testing.T) {
 	assert := assert.New(t)
 
 	s := newTestState()
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 
 	nodeID0 := ids.GenerateTestShortID()
 	err := up.StartTracking([]ids.ShortID{nodeID0})
@@ -116,7 +124,7 @@ func TestStartTrackingInThePast(t *testing.T) {
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 
 	currentTime := startTime.Add(-time.Second)
 	up.clock.Set(currentTime)
@@ -140,7 +148,7 @@ func TestShutdownDecreasesUptime(t *testing.T) {
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 	up.clock.Set(currentTime)
 
 	err := up.StartTracking([]ids.ShortID{nodeID0})
@@ -152,7 +160,7 @@ func TestShutdownDecreasesUptime(t *testing.T) {
 	err = up.Shutdown([]ids.ShortID{nodeID0})
 	assert.NoError(err)
 
-	up = NewManager(s).(*manager)
+	up = NewManager(s, nil, nil, nil).(*manager)
 	up.clock.Set(currentTime)
 
 	err = up.StartTracking([]ids.ShortID{nodeID0})
@@ -174,7 +182,7 @@ func TestShutdownIncreasesUptime(t *testing.T) {
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 	up.clock.Set(currentTime)
 
 	err := up.StartTracking([]ids.ShortID{nodeID0})
@@ -189,7 +197,7 @@ func TestShutdownIncreasesUptime(t *testing.T) {
 	err = up.Shutdown([]ids.ShortID{nodeID0})
 	assert.NoError(err)
 
-	up = NewManager(s).(*manager)
+	up = NewManager(s, nil, nil, nil).(*manager)
 	up.clock.Set(currentTime)
 
 	err = up.StartTracking([]ids.ShortID{nodeID0})
@@ -207,7 +215,7 @@ func TestShutdownDisconnectedNonValidator(t *testing.T) {
 	nodeID0 := ids.GenerateTestShortID()
 
 	s := newTestState()
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 
 	err := up.StartTracking(nil)
 	assert.NoError(err)
@@ -224,7 +232,7 @@ func TestShutdownConnectedDBError(t *testing.T) {
 
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 
 	err := up.StartTracking(nil)
 	assert.NoError(err)
@@ -246,7 +254,7 @@ func TestShutdownNonConnectedPast(t *testing.T) {
 
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 	up.clock.Set(currentTime)
 
 	err := up.StartTracking([]ids.ShortID{nodeID0})
@@ -273,7 +281,7 @@ func TestShutdownNonConnectedDBError(t *testing.T) {
 
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 	up.clock.Set(currentTime)
 
 	err := up.StartTracking([]ids.ShortID{nodeID0})
@@ -297,7 +305,7 @@ func TestConnectAndDisconnect(t *testing.T) {
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 	up.clock.Set(currentTime)
 
 	connected := up.IsConnected(nodeID0)
@@ -353,7 +361,7 @@ func TestConnectAndDisconnectBeforeTracking(t *testing.T) {
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 	currentTime = currentTime.Add(time.Second)
 	up.clock.Set(currentTime)
 
@@ -386,7 +394,7 @@ func TestUnrelatedNodeDisconnect(t *testing.T) {
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 	up.clock.Set(currentTime)
 
 	err := up.StartTracking([]ids.ShortID{nodeID0})
@@ -433,7 +441,7 @@ func TestCalculateUptimeWhenConnectedBeforeTracking(t *testing.T) {
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 	up.clock.Set(currentTime)
 
 	err := up.Connect(nodeID0)
@@ -464,7 +472,7 @@ func TestCalculateUptimeWhenConnectedInFuture(t *testing.T) {
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 	up.clock.Set(currentTime)
 
 	err := up.StartTracking([]ids.ShortID{nodeID0})
@@ -493,7 +501,7 @@ func TestCalculateUptimeNonValidator(t *testing.T) {
 
 	s := newTestState()
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 
 	_, err := up.CalculateUptimePercent(nodeID0, startTime)
 	assert.Error(err)
@@ -509,7 +517,7 @@ func TestCalculateUptimePercentageDivBy0(t *testing.T) {
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 	up.clock.Set(currentTime)
 
 	uptime, err := up.CalculateUptimePercent(nodeID0, startTime)
@@ -527,7 +535,7 @@ func TestCalculateUptimePercentage(t *testing.T) {
 	s := newTestState()
 	s.addNode(nodeID0, startTime)
 
-	up := NewManager(s).(*manager)
+	up := NewManager(s, nil, nil, nil).(*manager)
 
 	currentTime = currentTime.Add(time.Second)
 	up.clock.Set(currentTime)
@@ -536,3 +544,182 @@ func TestCalculateUptimePercentage(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal(float64(0), uptime)
 }
+
+func TestUptimeHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID0 := ids.GenerateTestShortID()
+	startTime := time.Unix(0, 0)
+
+	s := newTestState()
+	s.addNode(nodeID0, startTime)
+
+	up := NewManager(s, nil, nil, nil).(*manager)
+	up.clock.Set(startTime)
+
+	err := up.StartTracking([]ids.ShortID{nodeID0})
+	assert.NoError(err)
+
+	err = up.Connect(nodeID0)
+	assert.NoError(err)
+
+	up.clock.Set(startTime.Add(10 * time.Minute))
+	err = up.Disconnect(nodeID0)
+	assert.NoError(err)
+
+	up.clock.Set(startTime.Add(50 * time.Minute))
+	err = up.Connect(nodeID0)
+	assert.NoError(err)
+
+	// The current connection is included, but not recorded
+	up.clock.Set(startTime.Add(DefaultHistoryBucketDuration + 5*time.Minute))
+	buckets, err := up.UptimeHistory(nodeID0, ConnectedSource)
+	assert.NoError(err)
+	assert.Equal([]Bucket{
+		{Start: startTime, UpDuration: 20 * time.Minute},
+		{Start: startTime.Add(DefaultHistoryBucketDuration), UpDuration: 5 * time.Minute},
+	}, buckets)
+
+	err = up.Disconnect(nodeID0)
+	assert.NoError(err)
+
+	up.clock.Set(startTime.Add(DefaultHistoryBucketDuration + 30*time.Minute))
+	buckets, err = up.UptimeHistory(nodeID0, ConnectedSource)
+	assert.NoError(err)
+	assert.Equal([]Bucket{
+		{Start: startTime, UpDuration: 20 * time.Minute},
+		{Start: startTime.Add(DefaultHistoryBucketDuration), UpDuration: 5 * time.Minute},
+	}, buckets)
+}
+
+func TestUptimeHistoryNonValidator(t *testing.T) {
+	up := NewManager(newTestState(), nil, nil, nil)
+
+	_, err := up.UptimeHistory(ids.GenerateTestShortID(), ConnectedSource)
+	assert.Equal(t, database.ErrNotFound, err)
+}
+
+func TestUptimeHistoryUnknownSource(t *testing.T) {
+	nodeID0 := ids.GenerateTestShortID()
+	s := newTestState()
+	s.addNode(nodeID0, time.Unix(0, 0))
+
+	up := NewManager(s, nil, nil, validators.NewLivenessSources())
+
+	_, err := up.UptimeHistory(nodeID0, "unknown")
+	assert.True(t, errors.Is(err, errUnknownSource))
+}
+
+func TestSampleSources(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID0 := ids.GenerateTestShortID()
+	nodeID1 := ids.GenerateTestShortID()
+	nodeIDs := []ids.ShortID{nodeID0, nodeID1}
+	startTime := time.Unix(0, 0)
+
+	s := newTestState()
+	s.addNode(nodeID0, startTime)
+	s.addNode(nodeID1, startTime)
+
+	source := &testLivenessSource{}
+	source.up.Add(nodeID1)
+	sources := validators.NewLivenessSources()
+	assert.NoError(sources.Register("chain", source))
+
+	db := memdb.New()
+	up := NewManager(s, db, nil, sources).(*manager)
+	up.clock.Set(startTime)
+	assert.NoError(up.StartTracking(nodeIDs))
+	assert.NoError(up.Connect(nodeID0))
+
+	up.clock.Set(startTime.Add(10 * time.Minute))
+	assert.NoError(up.Sample(nodeIDs))
+
+	source.up.Clear()
+	source.up.Add(nodeID0)
+	up.clock.Set(startTime.Add(DefaultHistoryBucketDuration + 5*time.Minute))
+	assert.NoError(up.Sample(nodeIDs))
+
+	buckets, err := up.UptimeHistory(nodeID1, "chain")
+	assert.NoError(err)
+	assert.Equal([]Bucket{
+		{Start: startTime, UpDuration: 10 * time.Minute},
+	}, buckets)
+
+	buckets, err = up.UptimeHistory(nodeID0, "chain")
+	assert.NoError(err)
+	assert.Equal([]Bucket{
+		{Start: startTime, UpDuration: 50 * time.Minute},
+		{Start: startTime.Add(DefaultHistoryBucketDuration), UpDuration: 5 * time.Minute},
+	}, buckets)
+
+	// The histories were written when the bucket ended
+	up = NewManager(s, db, nil, sources).(*manager)
+	up.clock.Set(startTime.Add(DefaultHistoryBucketDuration + 5*time.Minute))
+	buckets, err = up.UptimeHistory(nodeID0, ConnectedSource)
+	assert.NoError(err)
+	assert.Equal([]Bucket{
+		{Start: startTime, UpDuration: DefaultHistoryBucketDuration},
+		{Start: startTime.Add(DefaultHistoryBucketDuration), UpDuration: 5 * time.Minute},
+	}, buckets)
+	buckets, err = up.UptimeHistory(nodeID1, "chain")
+	assert.NoError(err)
+	assert.Equal([]Bucket{
+		{Start: startTime, UpDuration: 10 * time.Minute},
+	}, buckets)
+}
+
+func TestSampleExchangesReports(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID0 := ids.GenerateTestShortID()
+	nodeID1 := ids.GenerateTestShortID()
+	nodeIDs := []ids.ShortID{nodeID0, nodeID1}
+	startTime := time.Unix(0, 0)
+
+	s := newTestState()
+	s.addNode(nodeID0, startTime)
+	s.addNode(nodeID1, startTime)
+
+	vdrs := validators.NewSet()
+	assert.NoError(vdrs.AddWeight(ids.NodeIDFromShortID(nodeID0), 1))
+	assert.NoError(vdrs.AddWeight(ids.NodeIDFromShortID(nodeID1), 1))
+
+	var local []validators.UptimeReport
+	reports := validators.NewUptimeReports(vdrs, DefaultHistoryBucketDuration, func(report validators.UptimeReport) {
+		local = append(local, report)
+	})
+
+	up := NewManager(s, nil, reports, nil).(*manager)
+	up.clock.Set(startTime)
+	assert.NoError(up.StartTracking(nodeIDs))
+	assert.NoError(up.Connect(nodeID0))
+
+	up.clock.Set(startTime.Add(DefaultHistoryBucketDuration + time.Minute))
+	assert.NoError(up.Sample(nodeIDs))
+	assert.Equal([]validators.UptimeReport{{
+		Start: startTime,
+		Uptimes: map[ids.NodeID]time.Duration{
+			ids.NodeIDFromShortID(nodeID0): DefaultHistoryBucketDuration,
+			ids.NodeIDFromShortID(nodeID1): 0,
+		},
+	}}, local)
+
+	assert.NoError(reports.Add(ids.NodeIDFromShortID(nodeID1), validators.UptimeReport{
+		Start:   startTime,
+		Uptimes: map[ids.NodeID]time.Duration{ids.NodeIDFromShortID(nodeID0): 20 * time.Minute},
+	}))
+
+	// The peer reported uptimes of a bucket are recorded when the bucket after
+	// it ends
+	up.clock.Set(startTime.Add(2*DefaultHistoryBucketDuration + time.Minute))
+	assert.NoError(up.Sample(nodeIDs))
+	assert.Len(local, 2)
+
+	buckets, err := up.UptimeHistory(nodeID0, PeerReportedSource)
+	assert.NoError(err)
+	assert.Equal([]Bucket{
+		{Start: startTime, UpDuration: 20 * time.Minute},
+	}, buckets)
+}
@@ -0,0 +1,306 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var (
+	errHeightNotIndexed = errors.New("validator sets aren't indexed at the requested height")
+	errTimeNotIndexed   = errors.New("validator sets aren't indexed at the requested time")
+)
+
+// validatorWeightDiff is the change in the weight of a validator caused by
+// accepting a block
+type validatorWeightDiff struct {
+	NodeID   ids.ShortID `serialize:"true"`
+	Decrease bool        `serialize:"true"`
+	Amount   uint64      `serialize:"true"`
+}
+
+// validatorWeightDiffs are the changes in the weights of the validators of a
+// subnet caused by accepting a block
+type validatorWeightDiffs struct {
+	Diffs []validatorWeightDiff `serialize:"true"`
+}
+
+// weightChange is the weight added to and removed from a validator
+type weightChange struct {
+	added, removed uint64
+}
+
+// weightChanges maps subnet ID -> node ID -> the change in the weight of the
+// node on the subnet
+type weightChanges map[ids.ID]map[ids.ShortID]*weightChange
+
+func (c weightChanges) add(subnetID ids.ID, nodeID ids.ShortID, amount uint64, increase bool) error {
+	subnetChanges, ok := c[subnetID]
+	if !ok {
+		subnetChanges = make(map[ids.ShortID]*weightChange)
+		c[subnetID] = subnetChanges
+	}
+	change, ok := subnetChanges[nodeID]
+	if !ok {
+		change = &weightChange{}
+		subnetChanges[nodeID] = change
+	}
+
+	var err error
+	if increase {
+		change.added, err = safemath.Add64(change.added, amount)
+	} else {
+		change.removed, err = safemath.Add64(change.removed, amount)
+	}
+	return err
+}
+
+// addStaker adds the change in weight caused by adding or removing the staker
+// [tx] to the current validator set
+func (c weightChanges) addStaker(tx *Tx, increase bool) error {
+	switch tx := tx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		return c.add(constants.PrimaryNetworkID, tx.Validator.NodeID, tx.Validator.Wght, increase)
+	case *UnsignedAddDelegatorTx:
+		return c.add(constants.PrimaryNetworkID, tx.Validator.NodeID, tx.Validator.Wght, increase)
+	case *UnsignedAddSubnetValidatorTx:
+		return c.add(tx.Validator.Subnet, tx.Validator.NodeID, tx.Validator.Wght, increase)
+	default:
+		return errWrongTxType
+	}
+}
+
+// addDiffs adds previously recorded [diffs] of [subnetID]
+func (c weightChanges) addDiffs(subnetID ids.ID, diffs *validatorWeightDiffs) error {
+	for _, diff := range diffs.Diffs {
+		if err := c.add(subnetID, diff.NodeID, diff.Amount, !diff.Decrease); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffs returns the net changes in the weights of the validators of
+// [subnetID], sorted by node ID
+func (c weightChanges) diffs(subnetID ids.ID) *validatorWeightDiffs {
+	diffs := &validatorWeightDiffs{}
+	for nodeID, change := range c[subnetID] {
+		switch {
+		case change.added > change.removed:
+			diffs.Diffs = append(diffs.Diffs, validatorWeightDiff{
+				NodeID: nodeID,
+				Amount: change.added - change.removed,
+			})
+		case change.added < change.removed:
+			diffs.Diffs = append(diffs.Diffs, validatorWeightDiff{
+				NodeID:   nodeID,
+				Decrease: true,
+				Amount:   change.removed - change.added,
+			})
+		}
+	}
+	sort.Slice(diffs.Diffs, func(i, j int) bool {
+		return bytes.Compare(diffs.Diffs[i].NodeID[:], diffs.Diffs[j].NodeID[:]) < 0
+	})
+	return diffs
+}
+
+// lastAcceptedHeight returns the height of the last accepted block
+func (st *internalStateImpl) lastAcceptedHeight() (uint64, error) {
+	blk, err := st.GetBlock(st.lastAccepted)
+	if err != nil {
+		return 0, err
+	}
+	return blk.Height(), nil
+}
+
+// loadValidatorHistory loads the height and time from which the validator sets
+// are indexed. If the validator sets haven't been indexed yet, they are indexed
+// from the last accepted block.
+func (st *internalStateImpl) loadValidatorHistory() error {
+	startHeight, err := database.GetUInt64(st.singletonDB, historyStartHeightKey)
+	if err == nil {
+		startTime, err := database.GetTimestamp(st.singletonDB, historyStartTimeKey)
+		st.historyStartHeight = startHeight
+		st.historyStartTime = startTime
+		return err
+	}
+	if err != database.ErrNotFound {
+		return err
+	}
+
+	startHeight, err = st.lastAcceptedHeight()
+	if err != nil {
+		return err
+	}
+	if err := database.PutUInt64(st.singletonDB, historyStartHeightKey, startHeight); err != nil {
+		return err
+	}
+	if err := database.PutTimestamp(st.singletonDB, historyStartTimeKey, st.timestamp); err != nil {
+		return err
+	}
+	st.historyStartHeight = startHeight
+	st.historyStartTime = st.timestamp
+	return nil
+}
+
+// writeValidatorHistory records the changes in the validator sets, and the
+// change of the chain time, caused by the last accepted block. Must be called
+// before the modified stakers and timestamp are written.
+func (st *internalStateImpl) writeValidatorHistory() error {
+	timestampChanged := !st.originalTimestamp.Equal(st.timestamp)
+	if len(st.addedCurrentStakers) == 0 && len(st.deletedCurrentStakers) == 0 && !timestampChanged {
+		return nil
+	}
+
+	height, err := st.lastAcceptedHeight()
+	if err != nil {
+		return err
+	}
+	heightKey := database.PackUInt64(height)
+
+	if timestampChanged {
+		timestampKey := database.PackUInt64(uint64(st.timestamp.Unix()))
+		if err := database.PutUInt64(st.timestampHeightDB, timestampKey, height); err != nil {
+			return err
+		}
+	}
+
+	changes := make(weightChanges)
+	for _, staker := range st.addedCurrentStakers {
+		if err := changes.addStaker(staker.addStakerTx, true); err != nil {
+			return err
+		}
+	}
+	for _, tx := range st.deletedCurrentStakers {
+		if err := changes.addStaker(tx, false); err != nil {
+			return err
+		}
+	}
+
+	for subnetID := range changes {
+		diffDB := prefixdb.New(subnetID[:], st.validatorDiffsDB)
+
+		// Merge with the changes previously recorded at this height
+		diffBytes, err := diffDB.Get(heightKey)
+		switch err {
+		case nil:
+			prevDiffs := &validatorWeightDiffs{}
+			if _, err := GenesisCodec.Unmarshal(diffBytes, prevDiffs); err != nil {
+				return err
+			}
+			if err := changes.addDiffs(subnetID, prevDiffs); err != nil {
+				return err
+			}
+		case database.ErrNotFound:
+		default:
+			return err
+		}
+
+		diffs := changes.diffs(subnetID)
+		if len(diffs.Diffs) == 0 {
+			if err := diffDB.Delete(heightKey); err != nil {
+				return err
+			}
+			continue
+		}
+		diffBytes, err = GenesisCodec.Marshal(codecVersion, diffs)
+		if err != nil {
+			return err
+		}
+		if err := diffDB.Put(heightKey, diffBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetValidatorWeights returns the weight of each validator of [subnetID] after
+// the block at [height] was accepted.
+func (st *internalStateImpl) GetValidatorWeights(subnetID ids.ID, height uint64) (map[ids.ShortID]uint64, error) {
+	currentHeight, err := st.lastAcceptedHeight()
+	if err != nil {
+		return nil, err
+	}
+	if height > currentHeight {
+		return nil, fmt.Errorf("height %d is after the last accepted height %d", height, currentHeight)
+	}
+	if height < st.historyStartHeight {
+		return nil, fmt.Errorf("%w: validator sets are indexed from height %d", errHeightNotIndexed, st.historyStartHeight)
+	}
+
+	vdrs, err := st.currentStakerChainState.ValidatorSet(subnetID)
+	if err != nil {
+		return nil, err
+	}
+	weights := make(map[ids.ShortID]uint64, vdrs.Len())
+	for _, vdr := range vdrs.List() {
+		weights[vdr.ID().ShortID()] = vdr.Weight()
+	}
+
+	// Undo the changes made by every block after [height]
+	diffDB := prefixdb.New(subnetID[:], st.validatorDiffsDB)
+	it := diffDB.NewIteratorWithStart(database.PackUInt64(height + 1))
+	defer it.Release()
+
+	for it.Next() {
+		diffs := validatorWeightDiffs{}
+		if _, err := GenesisCodec.Unmarshal(it.Value(), &diffs); err != nil {
+			return nil, err
+		}
+		for _, diff := range diffs.Diffs {
+			weight := weights[diff.NodeID]
+			if diff.Decrease {
+				weight, err = safemath.Add64(weight, diff.Amount)
+			} else {
+				weight, err = safemath.Sub64(weight, diff.Amount)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if weight == 0 {
+				delete(weights, diff.NodeID)
+			} else {
+				weights[diff.NodeID] = weight
+			}
+		}
+	}
+	return weights, it.Error()
+}
+
+// GetHeightAtTime returns the height of the last accepted block at which the
+// chain time was at most [timestamp].
+func (st *internalStateImpl) GetHeightAtTime(timestamp time.Time) (uint64, error) {
+	if timestamp.Before(st.historyStartTime) {
+		return 0, fmt.Errorf("%w: validator sets are indexed from %s", errTimeNotIndexed, st.historyStartTime)
+	}
+
+	// Find the first block that advanced the chain time past [timestamp]
+	it := st.timestampHeightDB.NewIteratorWithStart(database.PackUInt64(uint64(timestamp.Unix()) + 1))
+	defer it.Release()
+
+	if !it.Next() {
+		if err := it.Error(); err != nil {
+			return 0, err
+		}
+		return st.lastAcceptedHeight()
+	}
+	height, err := database.ParseUInt64(it.Value())
+	if err != nil {
+		return 0, err
+	}
+	return height - 1, nil
+}
@@ -0,0 +1,90 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+)
+
+func TestValidatorHistory(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		err := vm.Shutdown()
+		assert.NoError(t, err)
+		vm.ctx.Lock.Unlock()
+	}()
+	is := vm.internalState
+
+	genesisWeights, err := is.GetValidatorWeights(constants.PrimaryNetworkID, 0)
+	assert.NoError(t, err)
+	assert.Len(t, genesisWeights, len(keys))
+
+	// Accept a block that adds a validator and advances the chain time
+	nodeID := ids.GenerateTestShortID()
+	startTime := defaultGenesisTime.Add(time.Minute)
+	tx, err := vm.newAddValidatorTx(
+		vm.MinValidatorStake,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(defaultMinStakingDuration).Unix()),
+		nodeID,
+		nodeID,
+		PercentDenominator,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		ids.ShortEmpty, // change addr
+	)
+	assert.NoError(t, err)
+	currentStakers, err := is.CurrentStakerChainState().UpdateStakers(
+		[]*validatorReward{{addStakerTx: tx}},
+		nil,
+		nil,
+		0,
+	)
+	assert.NoError(t, err)
+	blk, err := vm.newCommitBlock(is.GetLastAccepted(), 1)
+	assert.NoError(t, err)
+	is.AddBlock(blk)
+	is.SetLastAccepted(blk.ID())
+	is.SetTimestamp(startTime)
+	currentStakers.Apply(is)
+	assert.NoError(t, is.Commit())
+
+	weights, err := is.GetValidatorWeights(constants.PrimaryNetworkID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, weights, len(keys)+1)
+	assert.Equal(t, vm.MinValidatorStake, weights[nodeID])
+
+	weights, err = is.GetValidatorWeights(constants.PrimaryNetworkID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, genesisWeights, weights)
+
+	_, err = is.GetValidatorWeights(constants.PrimaryNetworkID, 2)
+	assert.Error(t, err, "should have errored on a future height")
+
+	height, err := is.GetHeightAtTime(defaultGenesisTime)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), height)
+
+	height, err = is.GetHeightAtTime(startTime.Add(-time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), height)
+
+	height, err = is.GetHeightAtTime(startTime)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), height)
+
+	height, err = is.GetHeightAtTime(startTime.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), height)
+
+	_, err = is.GetHeightAtTime(defaultGenesisTime.Add(-time.Second))
+	assert.Error(t, err, "should have errored on a time before genesis")
+}
@@ -0,0 +1,223 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// Fields that GetCurrentValidators and GetPendingValidators can sort by
+const (
+	SortByStartTime       = "startTime"
+	SortByEndTime         = "endTime"
+	SortByWeight          = "weight"
+	SortByDelegatorCount  = "delegatorCount"
+	SortByDelegatorWeight = "delegatorWeight"
+	SortByDelegationFee   = "delegationFee"
+)
+
+var errUnknownSortField = errors.New("unknown sort field")
+
+// APITimeWindow is a range of unix times, in seconds, including its bounds
+type APITimeWindow struct {
+	// If 0, the window has no start
+	Start json.Uint64 `json:"start"`
+	// If 0, the window has no end
+	End json.Uint64 `json:"end"`
+}
+
+// contains returns true if [window] is nil or [t] is in [window]
+func (window *APITimeWindow) contains(t uint64) bool {
+	if window == nil {
+		return true
+	}
+	return uint64(window.Start) <= t && (window.End == 0 || t <= uint64(window.End))
+}
+
+// APIValidatorQuery are the filtering and sorting options of
+// GetCurrentValidators and GetPendingValidators
+type APIValidatorQuery struct {
+	// If provided, only stakers that start in this window are returned
+	StartTimeWindow *APITimeWindow `json:"startTimeWindow"`
+	// If provided, only stakers that end in this window are returned
+	EndTimeWindow *APITimeWindow `json:"endTimeWindow"`
+	// If provided, the stakers are sorted by this field rather than in the
+	// order of the staker set, and pages are given by offset. Fields that
+	// don't apply to a staker sort as 0.
+	SortBy string `json:"sortBy"`
+	// If true, the stakers are sorted in descending order of [SortBy]
+	SortDescending bool `json:"sortDescending"`
+	// If true, the delegators of each validator aren't listed, but their
+	// number and total stake still are
+	OmitDelegators bool `json:"omitDelegators"`
+}
+
+func (q *APIValidatorQuery) verify() error {
+	switch q.SortBy {
+	case "", SortByStartTime, SortByEndTime, SortByWeight, SortByDelegatorCount, SortByDelegatorWeight, SortByDelegationFee:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", errUnknownSortField, q.SortBy)
+	}
+}
+
+func (q *APIValidatorQuery) sorted() bool { return q.SortBy != "" }
+
+// matches returns true if a staker from [startTime] to [endTime] is in the
+// time windows of [q]
+func (q *APIValidatorQuery) matches(startTime, endTime uint64) bool {
+	return q.StartTimeWindow.contains(startTime) && q.EndTimeWindow.contains(endTime)
+}
+
+// sortStakers sorts [stakers], which are formatted stakers, by the field of
+// [q]. Stakers that are equal in that field keep their order.
+func (q *APIValidatorQuery) sortStakers(stakers []interface{}) {
+	sort.SliceStable(stakers, func(i, j int) bool {
+		if q.SortDescending {
+			return stakerLess(stakers[j], stakers[i], q.SortBy)
+		}
+		return stakerLess(stakers[i], stakers[j], q.SortBy)
+	})
+}
+
+func stakerLess(a, b interface{}, sortBy string) bool {
+	if sortBy == SortByDelegationFee {
+		return delegationFee(a) < delegationFee(b)
+	}
+	return stakerSortKey(a, sortBy) < stakerSortKey(b, sortBy)
+}
+
+func stakerSortKey(stakerIntf interface{}, sortBy string) uint64 {
+	var (
+		staker    APIStaker
+		validator *APIPrimaryValidator
+	)
+	switch s := stakerIntf.(type) {
+	case APIPrimaryValidator:
+		staker = s.APIStaker
+		validator = &s
+	case APIPrimaryDelegator:
+		staker = s.APIStaker
+	case APIStaker:
+		staker = s
+	}
+
+	switch sortBy {
+	case SortByStartTime:
+		return uint64(staker.StartTime)
+	case SortByEndTime:
+		return uint64(staker.EndTime)
+	case SortByWeight:
+		return staker.weight()
+	case SortByDelegatorCount:
+		if validator != nil && validator.DelegatorCount != nil {
+			return uint64(*validator.DelegatorCount)
+		}
+	case SortByDelegatorWeight:
+		if validator != nil && validator.DelegatorWeight != nil {
+			return uint64(*validator.DelegatorWeight)
+		}
+	}
+	return 0
+}
+
+func delegationFee(stakerIntf interface{}) float32 {
+	if validator, ok := stakerIntf.(APIPrimaryValidator); ok {
+		return float32(validator.DelegationFee)
+	}
+	return 0
+}
+
+// delegationRollup is the total of the delegations to a validator
+type delegationRollup struct {
+	count  uint64
+	weight uint64
+}
+
+// delegationRollups returns the total of the delegations in [stakers] to each
+// validator
+func delegationRollups(stakers []*Tx) (map[ids.ShortID]*delegationRollup, error) {
+	rollups := make(map[ids.ShortID]*delegationRollup)
+	for _, tx := range stakers {
+		delegator, ok := tx.UnsignedTx.(*UnsignedAddDelegatorTx)
+		if !ok {
+			continue
+		}
+		nodeID := delegator.Validator.ID()
+		rollup, ok := rollups[nodeID]
+		if !ok {
+			rollup = &delegationRollup{}
+			rollups[nodeID] = rollup
+		}
+		weight, err := math.Add64(rollup.weight, delegator.Validator.Weight())
+		if err != nil {
+			return nil, err
+		}
+		rollup.count++
+		rollup.weight = weight
+	}
+	return rollups, nil
+}
+
+// withDelegationRollup returns [vdr] with the totals of [rollup]
+func withDelegationRollup(vdr APIPrimaryValidator, rollup *delegationRollup) APIPrimaryValidator {
+	count := json.Uint64(0)
+	weight := json.Uint64(0)
+	if rollup != nil {
+		count = json.Uint64(rollup.count)
+		weight = json.Uint64(rollup.weight)
+	}
+	vdr.DelegatorCount = &count
+	vdr.DelegatorWeight = &weight
+	return vdr
+}
+
+// offsetCursor is the position of a staker in a sorted list of stakers
+type offsetCursor uint32
+
+// String returns the opaque string representation of this cursor that is
+// handed out to API clients
+func (c offsetCursor) String() string {
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.IntLen)}
+	p.PackInt(uint32(c))
+	// Encoding a fixed length byte slice with CB58 can't fail
+	str, _ := formatting.Encode(formatting.CB58, p.Bytes)
+	return str
+}
+
+// parseOffsetCursor parses a cursor previously returned by String
+func parseOffsetCursor(str string) (offsetCursor, error) {
+	if str == "" {
+		return 0, nil
+	}
+	b, err := formatting.Decode(formatting.CB58, str)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", errInvalidCursor, err)
+	}
+	if len(b) != wrappers.IntLen {
+		return 0, fmt.Errorf("%w: expected %d bytes but got %d", errInvalidCursor, wrappers.IntLen, len(b))
+	}
+	p := wrappers.Packer{Bytes: b}
+	return offsetCursor(p.UnpackInt()), p.Err
+}
+
+// page returns the up to [limit] elements of [stakers] after [start]
+func page(stakers []interface{}, start offsetCursor, limit int) []interface{} {
+	if int(start) >= len(stakers) {
+		return []interface{}{}
+	}
+	stakers = stakers[start:]
+	if len(stakers) > limit {
+		stakers = stakers[:limit]
+	}
+	return stakers
+}
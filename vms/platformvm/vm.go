@@ -8,13 +8,14 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/gorilla/rpc/v2"
-
+	"github.com/ava-labs/avalanchego/api/openapi"
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
 	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -24,6 +25,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
@@ -62,9 +64,16 @@ const (
 
 	// Maximum future start time for staking/delegating
 	maxFutureStartTime = 24 * 7 * 2 * time.Hour
+
+	// How often the exports that couldn't be written to shared memory are
+	// retried
+	atomicRetryFrequency = 30 * time.Second
 )
 
 var (
+	atomicQueuePrefix   = []byte("atomicQueue")
+	uptimeHistoryPrefix = []byte("uptimeHistory")
+
 	errInvalidID         = errors.New("invalid ID")
 	errDSCantValidate    = errors.New("new blockchain can't be validated by primary network")
 	errStartTimeTooEarly = errors.New("start time is before the current chain time")
@@ -102,6 +111,13 @@ type VM struct {
 
 	internalState InternalState
 
+	// Exports that are written to shared memory, and retried if that fails
+	atomicQueue   *atomic.Queue
+	atomicRetrier *timer.Repeater
+
+	// Samples validators' liveness into their uptime histories
+	uptimeSampler *timer.Repeater
+
 	// ID of the preferred block
 	preferred ids.ID
 
@@ -177,8 +193,32 @@ func (vm *VM) Initialize(
 	}
 	vm.internalState = is
 
+	vm.atomicQueue, err = atomic.NewQueue(ctx.SharedMemory, prefixdb.New(atomicQueuePrefix, vm.dbManager.Current().Database))
+	if err != nil {
+		return err
+	}
+	vm.atomicRetrier = timer.NewRepeater(func() {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		vm.retryAtomicOperations()
+	}, atomicRetryFrequency)
+	go ctx.Log.RecoverAndPanic(vm.atomicRetrier.Dispatch)
+
 	// Initialize the utility to track validator uptimes
-	vm.Manager = uptime.NewManager(is)
+	vm.Manager = uptime.NewManager(
+		is,
+		prefixdb.New(uptimeHistoryPrefix, vm.dbManager.Current().Database),
+		vm.UptimeReports,
+		vm.LivenessSources,
+	)
+	vm.uptimeSampler = timer.NewRepeater(func() {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		vm.sampleUptimes()
+	}, uptime.DefaultHistorySampleInterval)
+	go ctx.Log.RecoverAndPanic(vm.uptimeSampler.Dispatch)
 
 	if err := vm.updateValidators(true); err != nil {
 		return fmt.Errorf(
@@ -187,6 +227,13 @@ func (vm *VM) Initialize(
 		)
 	}
 
+	if err := vm.initBLSKeys(); err != nil {
+		return fmt.Errorf(
+			"failed to initialize BLS keys: %w",
+			err,
+		)
+	}
+
 	// Create all of the chains that the database says exist
 	if err := vm.initBlockchains(); err != nil {
 		return fmt.Errorf(
@@ -203,6 +250,59 @@ func (vm *VM) Initialize(
 	return vm.SetPreference(vm.lastAcceptedID)
 }
 
+// sampleUptimes records whether the primary network's validators have been up
+// since the last sample
+func (vm *VM) sampleUptimes() {
+	if !vm.bootstrapped {
+		return
+	}
+	primaryValidatorSet, exist := vm.Validators.GetValidators(constants.PrimaryNetworkID)
+	if !exist {
+		vm.ctx.Log.Error("couldn't sample uptimes: %s", errNoPrimaryValidators)
+		return
+	}
+	primaryValidators := primaryValidatorSet.List()
+
+	validatorIDs := make([]ids.ShortID, len(primaryValidators))
+	for i, vdr := range primaryValidators {
+		validatorIDs[i] = vdr.ID().ShortID()
+	}
+
+	if err := vm.Sample(validatorIDs); err != nil {
+		vm.ctx.Log.Error("couldn't sample uptimes: %s", err)
+	}
+}
+
+// retryAtomicOperations writes the exports that couldn't be written to shared
+// memory when their txs were accepted
+func (vm *VM) retryAtomicOperations() {
+	remaining, err := vm.atomicQueue.Retry()
+	if err != nil {
+		vm.ctx.Log.Error("couldn't retry exports to shared memory: %s", err)
+	}
+	if remaining > 0 {
+		vm.ctx.Log.Warn("%d exports couldn't be written to shared memory, retrying in %s", remaining, atomicRetryFrequency)
+	}
+}
+
+// Add the BLS keys registered on the platform chain to the node's registry
+func (vm *VM) initBLSKeys() error {
+	if vm.BLSKeys == nil {
+		return nil
+	}
+	for nodeID, tx := range vm.internalState.GetBLSKeys() {
+		registration := tx.UnsignedTx.(*UnsignedRegisterBLSKeyTx).Registration
+		pk, err := bls.PublicKeyFromBytes(registration.PublicKey)
+		if err != nil {
+			return err
+		}
+		if err := vm.BLSKeys.Set(ids.NodeIDFromShortID(nodeID), pk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Create all chains that exist that this node validates.
 func (vm *VM) initBlockchains() error {
 	chains, err := vm.internalState.GetChains(constants.PrimaryNetworkID)
@@ -226,9 +326,42 @@ func (vm *VM) initBlockchains() error {
 			}
 		}
 	}
+
+	if vm.SnapshotSyncer == nil {
+		return nil
+	}
+	// The node attests to the chains of subnets it doesn't validate too
+	subnets, err := vm.internalState.GetSubnets()
+	if err != nil {
+		return err
+	}
+	for _, subnet := range subnets {
+		chains, err := vm.internalState.GetChains(subnet.ID())
+		if err != nil {
+			return err
+		}
+		for _, chain := range chains {
+			unsignedTx, ok := chain.UnsignedTx.(*UnsignedCreateChainTx)
+			if !ok {
+				return errWrongTxType
+			}
+			vm.SnapshotSyncer.AddChain(unsignedTx.SubnetID, snapshotChain(chain.ID(), unsignedTx))
+		}
+	}
 	return nil
 }
 
+// snapshotChain returns the description of the chain created by [tx] that
+// the node attests to in validator snapshots
+func snapshotChain(chainID ids.ID, tx *UnsignedCreateChainTx) validators.SnapshotChain {
+	return validators.SnapshotChain{
+		ID:          chainID,
+		VMID:        tx.VMID,
+		FxIDs:       tx.FxIDs,
+		GenesisData: tx.GenesisData,
+	}
+}
+
 // Create the blockchain described in [tx], but only if this node is a member of
 // the subnet that validates the chain
 func (vm *VM) createChain(tx *Tx) error {
@@ -237,6 +370,10 @@ func (vm *VM) createChain(tx *Tx) error {
 		return errWrongTxType
 	}
 
+	if vm.SnapshotSyncer != nil {
+		vm.SnapshotSyncer.AddChain(unsignedTx.SubnetID, snapshotChain(tx.ID(), unsignedTx))
+	}
+
 	if vm.StakingEnabled && // Staking is enabled, so nodes might not validate all chains
 		constants.PrimaryNetworkID != unsignedTx.SubnetID && // All nodes must validate the primary network
 		!vm.WhitelistedSubnets.Contains(unsignedTx.SubnetID) { // This node doesn't validate this blockchain
@@ -287,13 +424,21 @@ func (vm *VM) Bootstrapped() error {
 
 	validatorIDs := make([]ids.ShortID, len(primaryValidators))
 	for i, vdr := range primaryValidators {
-		validatorIDs[i] = vdr.ID()
+		validatorIDs[i] = vdr.ID().ShortID()
 	}
 
 	if err := vm.StartTracking(validatorIDs); err != nil {
 		return err
 	}
-	return vm.internalState.Commit()
+	if err := vm.internalState.Commit(); err != nil {
+		return err
+	}
+
+	// The validator sets are now up to date, so the node can attest to them
+	if vm.SnapshotSyncer != nil {
+		vm.SnapshotSyncer.Bootstrapped()
+	}
+	return nil
 }
 
 // Shutdown this blockchain
@@ -303,6 +448,17 @@ func (vm *VM) Shutdown() error {
 	}
 
 	vm.mempool.Shutdown()
+	if vm.atomicRetrier != nil {
+		// See Mempool.Shutdown
+		vm.ctx.Lock.Unlock()
+		vm.atomicRetrier.Stop()
+		vm.ctx.Lock.Lock()
+	}
+	if vm.uptimeSampler != nil {
+		vm.ctx.Lock.Unlock()
+		vm.uptimeSampler.Stop()
+		vm.ctx.Lock.Lock()
+	}
 
 	if vm.bootstrapped {
 		primaryValidatorSet, exist := vm.Validators.GetValidators(constants.PrimaryNetworkID)
@@ -313,7 +469,7 @@ func (vm *VM) Shutdown() error {
 
 		validatorIDs := make([]ids.ShortID, len(primaryValidators))
 		for i, vdr := range primaryValidators {
-			validatorIDs[i] = vdr.ID()
+			validatorIDs[i] = vdr.ID().ShortID()
 		}
 
 		if err := vm.Manager.Shutdown(validatorIDs); err != nil {
@@ -408,7 +564,7 @@ func (vm *VM) NotifyBlockReady() {
 // * keys are API endpoint extensions
 // * values are API handlers
 func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
-	server := rpc.NewServer()
+	server := openapi.NewServer()
 	server.RegisterCodec(json.NewCodec(), "application/json")
 	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
 	server.RegisterInterceptFunc(vm.metrics.apiRequestMetrics.InterceptRequest)
@@ -428,7 +584,7 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 // * keys are API endpoint extensions
 // * values are API handlers
 func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
-	server := rpc.NewServer()
+	server := openapi.NewServer()
 	server.RegisterCodec(json.NewCodec(), "application/json")
 	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
 	if err := server.RegisterService(&StaticService{}, "platform"); err != nil {
@@ -540,7 +696,7 @@ func (vm *VM) getPercentConnected() (float64, error) {
 		err            error
 	)
 	for _, vdr := range vdrs {
-		if !vm.IsConnected(vdr.ID()) {
+		if !vm.IsConnected(vdr.ID().ShortID()) {
 			continue // not connected to us --> don't include
 		}
 		connectedStake, err = safemath.Add64(connectedStake, vdr.Weight())
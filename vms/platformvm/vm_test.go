@@ -34,6 +34,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
@@ -144,7 +145,7 @@ func defaultGenesis() (*BuildGenesisArgs, []byte) {
 	hrp := constants.NetworkIDToHRP[testNetworkID]
 	for i, key := range keys {
 		id := key.PublicKey().Address()
-		addr, err := formatting.FormatBech32(hrp, id.Bytes())
+		addr, err := address.FormatBech32(hrp, id.Bytes())
 		if err != nil {
 			panic(err)
 		}
@@ -157,7 +158,7 @@ func defaultGenesis() (*BuildGenesisArgs, []byte) {
 	genesisValidators := make([]APIPrimaryValidator, len(keys))
 	for i, key := range keys {
 		id := key.PublicKey().Address()
-		addr, err := formatting.FormatBech32(hrp, id.Bytes())
+		addr, err := address.FormatBech32(hrp, id.Bytes())
 		if err != nil {
 			panic(err)
 		}
@@ -165,7 +166,7 @@ func defaultGenesis() (*BuildGenesisArgs, []byte) {
 			APIStaker: APIStaker{
 				StartTime: json.Uint64(defaultValidateStartTime.Unix()),
 				EndTime:   json.Uint64(defaultValidateEndTime.Unix()),
-				NodeID:    id.PrefixedString(constants.NodeIDPrefix),
+				NodeID:    address.FormatNodeID(id),
 			},
 			RewardOwner: &APIOwner{
 				Threshold: 1,
@@ -219,7 +220,7 @@ func BuildGenesisTestWithArgs(t *testing.T, args *BuildGenesisArgs) (*BuildGenes
 	hrp := constants.NetworkIDToHRP[testNetworkID]
 	for i, key := range keys {
 		id := key.PublicKey().Address()
-		addr, err := formatting.FormatBech32(hrp, id.Bytes())
+		addr, err := address.FormatBech32(hrp, id.Bytes())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -232,7 +233,7 @@ func BuildGenesisTestWithArgs(t *testing.T, args *BuildGenesisArgs) (*BuildGenes
 	genesisValidators := make([]APIPrimaryValidator, len(keys))
 	for i, key := range keys {
 		id := key.PublicKey().Address()
-		addr, err := formatting.FormatBech32(hrp, id.Bytes())
+		addr, err := address.FormatBech32(hrp, id.Bytes())
 		if err != nil {
 			panic(err)
 		}
@@ -240,7 +241,7 @@ func BuildGenesisTestWithArgs(t *testing.T, args *BuildGenesisArgs) (*BuildGenes
 			APIStaker: APIStaker{
 				StartTime: json.Uint64(defaultValidateStartTime.Unix()),
 				EndTime:   json.Uint64(defaultValidateEndTime.Unix()),
-				NodeID:    id.PrefixedString(constants.NodeIDPrefix),
+				NodeID:    address.FormatNodeID(id),
 			},
 			RewardOwner: &APIOwner{
 				Threshold: 1,
@@ -440,7 +441,7 @@ func TestGenesis(t *testing.T) {
 	genesisState, _ := defaultGenesis()
 	// Ensure all the genesis UTXOs are there
 	for _, utxo := range genesisState.UTXOs {
-		_, addrBytes, err := formatting.ParseBech32(utxo.Address)
+		_, addrBytes, err := address.ParseBech32(utxo.Address)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -460,7 +461,7 @@ func TestGenesis(t *testing.T) {
 		} else if out.Amount() != uint64(utxo.Amount) {
 			id := keys[0].PublicKey().Address()
 			hrp := constants.NetworkIDToHRP[testNetworkID]
-			addr, err := formatting.FormatBech32(hrp, id.Bytes())
+			addr, err := address.FormatBech32(hrp, id.Bytes())
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -484,7 +485,7 @@ func TestGenesis(t *testing.T) {
 		t.Fatal("vm's current validator set is wrong")
 	}
 	for _, key := range keys {
-		if addr := key.PublicKey().Address(); !vdrSet.Contains(addr) {
+		if addr := key.PublicKey().Address(); !vdrSet.Contains(ids.NodeIDFromShortID(addr)) {
 			t.Fatalf("should have had validator with NodeID %s", addr)
 		}
 	}
@@ -506,9 +507,9 @@ func TestGenesisGetUTXOs(t *testing.T) {
 	addr2 := keys[2].PublicKey().Address()
 	hrp := constants.NetworkIDToHRP[testNetworkID]
 
-	addr0Str, _ := formatting.FormatBech32(hrp, addr0.Bytes())
-	addr1Str, _ := formatting.FormatBech32(hrp, addr1.Bytes())
-	addr2Str, _ := formatting.FormatBech32(hrp, addr2.Bytes())
+	addr0Str, _ := address.FormatBech32(hrp, addr0.Bytes())
+	addr1Str, _ := address.FormatBech32(hrp, addr1.Bytes())
+	addr2Str, _ := address.FormatBech32(hrp, addr2.Bytes())
 
 	// Create a starting point of 2000 UTXOs on different addresses
 	utxoCount := 2345
@@ -2070,7 +2071,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 
 	peerID := ids.ShortID{1, 2, 3, 4, 5, 4, 3, 2, 1}
 	vdrs := validators.NewSet()
-	if err := vdrs.AddWeight(peerID, 1); err != nil {
+	if err := vdrs.AddWeight(ids.NodeIDFromShortID(peerID), 1); err != nil {
 		t.Fatal(err)
 	}
 	beacons := vdrs
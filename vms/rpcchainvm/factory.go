@@ -5,20 +5,32 @@ package rpcchainvm
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/sandbox"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-var errWrongVM = errors.New("wrong vm type")
+var (
+	errWrongVM            = errors.New("wrong vm type")
+	errIncompatiblePlugin = errors.New("incompatible plugin protocol version")
+)
 
 // Factory ...
 type Factory struct {
 	Path string
+	// Limits of the resources of each plugin process
+	Limits sandbox.Limits
 }
 
 // New ...
@@ -29,10 +41,19 @@ func (f *Factory) New(ctx *snow.Context) (interface{}, error) {
 	// #nosec G204
 	cmd := exec.Command(f.Path)
 
+	// The plugin process inherits the write end of a pipe, which is closed
+	// when the process exits. Reading the pipe until it's closed reports the
+	// exit as soon as it happens.
+	exitReader, exitWriter, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create the exit pipe of plugin %s: %w", f.Path, err)
+	}
+	cmd.ExtraFiles = []*os.File{exitWriter}
+
 	config := &plugin.ClientConfig{
-		HandshakeConfig: Handshake,
-		Plugins:         PluginMap,
-		Cmd:             cmd,
+		HandshakeConfig:  Handshake,
+		VersionedPlugins: VersionedPluginMap,
+		Cmd:              cmd,
 		AllowedProtocols: []plugin.Protocol{
 			plugin.ProtocolNetRPC,
 			plugin.ProtocolGRPC,
@@ -66,24 +87,95 @@ func (f *Factory) New(ctx *snow.Context) (interface{}, error) {
 	client := plugin.NewClient(config)
 
 	rpcClient, err := client.Client()
+	// Only the plugin process holds the write end now
+	_ = exitWriter.Close()
 	if err != nil {
+		_ = exitReader.Close()
 		client.Kill()
-		return nil, err
+		return nil, f.protocolError(err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(ioutil.Discard, exitReader)
+		_ = exitReader.Close()
+		close(exited)
+	}()
+	if ctx != nil {
+		ctx.Log.Debug("plugin %s uses protocol version %d", f.Path, client.NegotiatedVersion())
+	}
+
+	// The process was started by the client
+	pid := cmd.Process.Pid
+	// Each chain has its own plugin process. Each VM has one more process
+	// that serves its static API, which has no chain.
+	name := "static-" + filepath.Base(f.Path)
+	if ctx != nil {
+		name = ctx.ChainID.String()
+	}
+	box, err := sandbox.New(f.Limits, name, pid)
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("couldn't limit the resources of plugin %s: %w", f.Path, err)
+	}
+
+	var processMetrics prometheus.Collector
+	if ctx != nil {
+		processMetrics = prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{
+			PidFn:     func() (int, error) { return pid, nil },
+			Namespace: fmt.Sprintf("%s_plugin", ctx.Namespace),
+		})
+		if err := ctx.Metrics.Register(processMetrics); err != nil {
+			client.Kill()
+			cleanup(ctx, box, nil)
+			return nil, err
+		}
 	}
 
 	raw, err := rpcClient.Dispense("vm")
 	if err != nil {
 		client.Kill()
+		cleanup(ctx, box, processMetrics)
 		return nil, err
 	}
 
 	vm, ok := raw.(*VMClient)
 	if !ok {
 		client.Kill()
+		cleanup(ctx, box, processMetrics)
 		return nil, errWrongVM
 	}
 
 	vm.SetProcess(client)
+	vm.exited = exited
+	vm.sandbox = box
+	vm.processMetrics = processMetrics
 	vm.ctx = ctx
+	vm.factory = f
 	return vm, nil
 }
+
+// cleanup releases the sandbox and the metrics of a plugin process that was
+// killed
+func cleanup(ctx *snow.Context, box *sandbox.Sandbox, processMetrics prometheus.Collector) {
+	_ = box.Close()
+	if processMetrics != nil {
+		ctx.Metrics.Unregister(processMetrics)
+	}
+}
+
+// protocolError returns [err], the error of starting the plugin, with a clear
+// description if the plugin doesn't support any of the protocol versions that
+// the node supports. go-plugin doesn't return a typed error in that case.
+func (f *Factory) protocolError(err error) error {
+	if !strings.Contains(err.Error(), "Incompatible API version") {
+		return err
+	}
+	return fmt.Errorf(
+		"%w: the node supports versions %d to %d but %s doesn't support any of them. Upgrade the plugin or the node: %s",
+		errIncompatiblePlugin,
+		MinProtocolVersion,
+		ProtocolVersion,
+		f.Path,
+		err,
+	)
+}
@@ -0,0 +1,135 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/sandbox"
+)
+
+// If set, the test binary serves a plugin over the protocol version in this
+// variable, or over every supported version if it's empty, instead of running
+// the tests
+const testPluginVersionEnv = "RPCCHAINVM_TEST_PLUGIN_VERSION"
+
+// If set, the plugin served by the test binary runs a VM with only a genesis
+// block and one child of it, instead of no VM
+const testPluginVMEnv = "RPCCHAINVM_TEST_PLUGIN_VM"
+
+func TestMain(m *testing.M) {
+	version, isPlugin := os.LookupEnv(testPluginVersionEnv)
+	if !isPlugin {
+		os.Exit(m.Run())
+	}
+	if version == "" {
+		var vm block.ChainVM
+		if _, serveVM := os.LookupEnv(testPluginVMEnv); serveVM {
+			vm = newTestPluginVM()
+		}
+		Serve(vm)
+		return
+	}
+	protocolVersion, err := strconv.Atoi(version)
+	if err != nil {
+		os.Exit(1)
+	}
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: plugin.HandshakeConfig{
+			ProtocolVersion:  uint(protocolVersion),
+			MagicCookieKey:   Handshake.MagicCookieKey,
+			MagicCookieValue: Handshake.MagicCookieValue,
+		},
+		Plugins:    PluginMap,
+		GRPCServer: plugin.DefaultGRPCServer,
+	})
+}
+
+func TestFactoryNegotiatesProtocolVersion(t *testing.T) {
+	defer plugin.CleanupClients()
+
+	tests := []struct {
+		name          string
+		pluginVersion string
+		expectedErr   error
+	}{
+		{
+			name:          "every supported version",
+			pluginVersion: "",
+		},
+		{
+			name:          "oldest supported version",
+			pluginVersion: strconv.Itoa(MinProtocolVersion),
+		},
+		{
+			name:          "unsupported version",
+			pluginVersion: strconv.Itoa(MinProtocolVersion - 1),
+			expectedErr:   errIncompatiblePlugin,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := os.Setenv(testPluginVersionEnv, test.pluginVersion); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Unsetenv(testPluginVersionEnv)
+
+			factory := &Factory{Path: os.Args[0]}
+			vm, err := factory.New(nil)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("expected error %v but got %v", test.expectedErr, err)
+			}
+			if err == nil {
+				vm.(*VMClient).proc.Kill()
+			}
+		})
+	}
+}
+
+func TestFactoryLimitsPluginResources(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("plugin resource limits are only supported on linux")
+	}
+	defer plugin.CleanupClients()
+
+	if err := os.Setenv(testPluginVersionEnv, ""); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv(testPluginVersionEnv)
+
+	factory := &Factory{
+		Path:   os.Args[0],
+		Limits: sandbox.Limits{FileDescriptors: 256},
+	}
+	vm, err := factory.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proc := vm.(*VMClient).proc
+	defer proc.Kill()
+
+	limits, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/limits", proc.ReattachConfig().Pid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(limits), "Max open files            256                  256") {
+		t.Fatalf("the plugin's file descriptors aren't limited:\n%s", limits)
+	}
+
+	// Limits that can't be enforced fail the creation of the plugin
+	factory.Limits = sandbox.Limits{MemoryBytes: 1 << 30}
+	if _, err := factory.New(nil); err == nil {
+		t.Fatal("expected the plugin to fail to start without a cgroup directory")
+	}
+}
@@ -0,0 +1,163 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// metricsHandlerPrefix is the prefix of the handler that serves the
+	// metrics of a plugin to the node. The node doesn't expose this handler
+	// over its API.
+	metricsHandlerPrefix = "/__metrics"
+
+	// metricsTimeout is how long the node waits for a plugin to report its
+	// metrics
+	metricsTimeout = 5 * time.Second
+)
+
+var (
+	_ prometheus.Collector = &pluginCollector{}
+	_ prometheus.Metric    = &pluginMetric{}
+	_ http.ResponseWriter  = &metricsRecorder{}
+)
+
+// pluginCollector is a prometheus.Collector that reports the metrics that a
+// plugin registered with its context. The plugin serves its metrics over
+// [handler]. The name of every metric is prefixed with [namespace], so that
+// the metrics of a plugin are named the same as if the VM were running in
+// the node's process.
+//
+// The metrics that a plugin reports can change between collections, so the
+// collector doesn't describe them and is registered unchecked. Unchecked
+// collectors can't be unregistered, so the collector is stopped instead once
+// the plugin shuts down.
+type pluginCollector struct {
+	namespace string
+	handler   http.Handler
+	log       logging.Logger
+	stopped   utils.AtomicBool
+}
+
+func newPluginCollector(namespace string, handler http.Handler, log logging.Logger) *pluginCollector {
+	return &pluginCollector{
+		namespace: namespace,
+		handler:   handler,
+		log:       log,
+	}
+}
+
+// Describe implements the prometheus.Collector interface. It doesn't describe
+// any metrics, which makes the collector unchecked.
+func (*pluginCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements the prometheus.Collector interface
+func (c *pluginCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.stopped.GetValue() {
+		return
+	}
+	families, err := c.gather()
+	if err != nil {
+		c.log.Debug("failed to gather the metrics of the plugin: %s", err)
+		return
+	}
+	for _, family := range families {
+		name := prometheus.BuildFQName(c.namespace, "", family.GetName())
+		for _, metric := range family.Metric {
+			labels := make(prometheus.Labels, len(metric.Label))
+			for _, label := range metric.Label {
+				labels[label.GetName()] = label.GetValue()
+			}
+			ch <- &pluginMetric{
+				desc:   prometheus.NewDesc(name, family.GetHelp(), nil, labels),
+				metric: metric,
+			}
+		}
+	}
+}
+
+// stop the collector from reporting any more metrics
+func (c *pluginCollector) stop() { c.stopped.SetValue(true) }
+
+// gather requests the metrics of the plugin in the protobuf format
+func (c *pluginCollector) gather() ([]*dto.MetricFamily, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metricsHandlerPrefix, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", string(expfmt.FmtProtoDelim))
+
+	w := &metricsRecorder{
+		header: make(http.Header),
+		code:   http.StatusOK,
+	}
+	c.handler.ServeHTTP(w, req)
+	if w.code != http.StatusOK {
+		return nil, fmt.Errorf("plugin responded with status %d", w.code)
+	}
+
+	decoder := expfmt.NewDecoder(&w.body, expfmt.ResponseFormat(w.header))
+	var families []*dto.MetricFamily
+	for {
+		family := &dto.MetricFamily{}
+		switch err := decoder.Decode(family); err {
+		case nil:
+			families = append(families, family)
+		case io.EOF:
+			return families, nil
+		default:
+			return nil, fmt.Errorf("couldn't decode the metrics of the plugin: %w", err)
+		}
+	}
+}
+
+// pluginMetric is a metric that was reported by a plugin
+type pluginMetric struct {
+	desc   *prometheus.Desc
+	metric *dto.Metric
+}
+
+func (m *pluginMetric) Desc() *prometheus.Desc { return m.desc }
+
+func (m *pluginMetric) Write(out *dto.Metric) error {
+	out.Label = m.metric.Label
+	out.Gauge = m.metric.Gauge
+	out.Counter = m.metric.Counter
+	out.Summary = m.metric.Summary
+	out.Untyped = m.metric.Untyped
+	out.Histogram = m.metric.Histogram
+	out.TimestampMs = m.metric.TimestampMs
+	return nil
+}
+
+// metricsRecorder is the http.ResponseWriter that the response of a plugin's
+// metrics handler is written to
+type metricsRecorder struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (w *metricsRecorder) Header() http.Header { return w.header }
+
+func (w *metricsRecorder) Write(b []byte) (int, error) { return w.body.Write(b) }
+
+func (w *metricsRecorder) WriteHeader(code int) { w.code = code }
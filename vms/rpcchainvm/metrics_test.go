@@ -0,0 +1,71 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestPluginCollector(t *testing.T) {
+	pluginRegistry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "txs_accepted",
+		Help: "Number of txs accepted",
+	}, []string{"kind"})
+	if err := pluginRegistry.Register(counter); err != nil {
+		t.Fatal(err)
+	}
+	counter.WithLabelValues("transfer").Add(3)
+
+	nodeRegistry := prometheus.NewRegistry()
+	chainMetrics := prometheus.WrapRegistererWith(prometheus.Labels{"chain": "X"}, nodeRegistry)
+	collector := newPluginCollector(
+		"avalanche_testvm_vm",
+		promhttp.HandlerFor(pluginRegistry, promhttp.HandlerOpts{}),
+		logging.NoLog{},
+	)
+	if err := chainMetrics.Register(collector); err != nil {
+		t.Fatal(err)
+	}
+
+	families, err := nodeRegistry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) != 1 {
+		t.Fatalf("expected 1 metric family but got %d", len(families))
+	}
+	family := families[0]
+	if name := family.GetName(); name != "avalanche_testvm_vm_txs_accepted" {
+		t.Fatalf("unexpected metric name %q", name)
+	}
+	if len(family.Metric) != 1 {
+		t.Fatalf("expected 1 metric but got %d", len(family.Metric))
+	}
+	metric := family.Metric[0]
+	if value := metric.GetCounter().GetValue(); value != 3 {
+		t.Fatalf("expected the counter to be 3 but got %f", value)
+	}
+	labels := map[string]string{}
+	for _, label := range metric.Label {
+		labels[label.GetName()] = label.GetValue()
+	}
+	if labels["chain"] != "X" || labels["kind"] != "transfer" || len(labels) != 2 {
+		t.Fatalf("unexpected labels %v", labels)
+	}
+
+	collector.stop()
+	families, err = nodeRegistry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) != 0 {
+		t.Fatalf("a stopped collector shouldn't report any metrics but got %d families", len(families))
+	}
+}
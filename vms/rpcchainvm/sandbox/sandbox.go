@@ -0,0 +1,103 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package sandbox limits the resources that plugin processes may use, so that
+// a misbehaving plugin can't starve the node.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// MinCPUShares and MaxCPUShares bound the CPU shares of a plugin
+	MinCPUShares = 1
+	MaxCPUShares = 10000
+)
+
+var (
+	errUnsupported  = errors.New("plugin resource limits aren't supported on this platform")
+	errCPUShares    = fmt.Errorf("CPU shares must be in [%d, %d]", MinCPUShares, MaxCPUShares)
+	errNoCgroupDir  = errors.New("limiting the CPU or memory of plugins requires a cgroup directory")
+	errNotAbsolute  = errors.New("cgroup directory must be an absolute path")
+	errEmptyName    = errors.New("sandbox name can't be empty")
+	errNameHasSlash = errors.New("sandbox name can't contain a slash")
+)
+
+// Limits of the resources of a plugin process. A limit of 0 means that the
+// resource isn't limited.
+type Limits struct {
+	// Share of the CPU time of the plugin relative to the other plugins, when
+	// the CPU is contended. Plugins get 100 shares if they aren't limited.
+	CPUShares uint64
+	// Bytes of memory that the plugin may use. The plugin is killed if it
+	// needs more.
+	MemoryBytes uint64
+	// Maximum number of file descriptors that the plugin may have open
+	FileDescriptors uint64
+	// Directory of a cgroup v2 that the node may manage. Each plugin is put in
+	// a cgroup of its own under this directory to limit its CPU and memory.
+	CgroupDir string
+}
+
+// Enabled returns true if any resource is limited
+func (l Limits) Enabled() bool {
+	return l.CPUShares != 0 || l.MemoryBytes != 0 || l.FileDescriptors != 0
+}
+
+// needsCgroup returns true if a cgroup is needed to enforce the limits
+func (l Limits) needsCgroup() bool {
+	return l.CPUShares != 0 || l.MemoryBytes != 0
+}
+
+// Verify returns an error if the limits can't be enforced
+func (l Limits) Verify() error {
+	switch {
+	case !l.Enabled():
+		return nil
+	case !supported:
+		return errUnsupported
+	case l.CPUShares != 0 && (l.CPUShares < MinCPUShares || l.CPUShares > MaxCPUShares):
+		return errCPUShares
+	case l.needsCgroup() && l.CgroupDir == "":
+		return errNoCgroupDir
+	case l.needsCgroup() && !filepath.IsAbs(l.CgroupDir):
+		return errNotAbsolute
+	default:
+		return nil
+	}
+}
+
+// Sandbox is the set of limits that are applied to a plugin process
+type Sandbox struct {
+	// Directory of the cgroup of the process, or empty if it has none
+	cgroup string
+}
+
+// New applies [limits] to the process [pid]. If the CPU or memory is limited,
+// the process is moved to a new cgroup named [name]. The returned sandbox
+// must be closed once the process exits.
+func New(limits Limits, name string, pid int) (*Sandbox, error) {
+	if err := limits.Verify(); err != nil {
+		return nil, err
+	}
+	switch {
+	case name == "":
+		return nil, errEmptyName
+	case strings.ContainsRune(name, filepath.Separator):
+		return nil, errNameHasSlash
+	}
+	return apply(limits, name, pid)
+}
+
+// Close removes the cgroup of the process, which must have exited. It's a
+// no-op on a nil sandbox.
+func (s *Sandbox) Close() error {
+	if s == nil || s.cgroup == "" {
+		return nil
+	}
+	return removeCgroup(s.cgroup)
+}
@@ -0,0 +1,129 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build linux
+// +build linux
+
+package sandbox
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+const supported = true
+
+func apply(limits Limits, name string, pid int) (*Sandbox, error) {
+	s := &Sandbox{}
+	if limits.needsCgroup() {
+		cgroup, err := createCgroup(limits, name)
+		if err != nil {
+			return nil, err
+		}
+		s.cgroup = cgroup
+		// Moving the process moves all of its threads
+		if err := writeFile(cgroup, "cgroup.procs", pid); err != nil {
+			_ = removeCgroup(cgroup)
+			return nil, err
+		}
+	}
+	if limits.FileDescriptors != 0 {
+		rlimit := syscall.Rlimit{
+			Cur: limits.FileDescriptors,
+			Max: limits.FileDescriptors,
+		}
+		if err := prlimit(pid, syscall.RLIMIT_NOFILE, &rlimit); err != nil {
+			return nil, fmt.Errorf("couldn't limit the file descriptors of process %d: %w", pid, err)
+		}
+	}
+	return s, nil
+}
+
+// createCgroup creates the cgroup [name] under the cgroup directory and sets
+// its limits. Returns the directory of the new cgroup.
+func createCgroup(limits Limits, name string) (string, error) {
+	// The controllers must be enabled in the parent for its children to be
+	// limited
+	controllers := ""
+	if limits.CPUShares != 0 {
+		controllers += " +cpu"
+	}
+	if limits.MemoryBytes != 0 {
+		controllers += " +memory"
+	}
+	if err := writeFile(limits.CgroupDir, "cgroup.subtree_control", controllers[1:]); err != nil {
+		return "", err
+	}
+
+	cgroup := filepath.Join(limits.CgroupDir, name)
+	// The cgroup may be left over from a process that wasn't cleaned up, such
+	// as when the node crashed
+	if err := os.Mkdir(cgroup, perms.ReadWriteExecute); err != nil && !os.IsExist(err) {
+		return "", fmt.Errorf("couldn't create cgroup: %w", err)
+	}
+	if limits.CPUShares != 0 {
+		if err := writeFile(cgroup, "cpu.weight", limits.CPUShares); err != nil {
+			_ = removeCgroup(cgroup)
+			return "", err
+		}
+	}
+	if limits.MemoryBytes != 0 {
+		if err := writeFile(cgroup, "memory.max", limits.MemoryBytes); err != nil {
+			_ = removeCgroup(cgroup)
+			return "", err
+		}
+	}
+	return cgroup, nil
+}
+
+func removeCgroup(cgroup string) error {
+	// A cgroup is removed by removing its directory, which fails if the
+	// cgroup still has processes
+	if err := os.Remove(cgroup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("couldn't remove cgroup: %w", err)
+	}
+	return nil
+}
+
+// writeFile writes [value] to the control file [name] of [cgroup]
+func writeFile(cgroup, name string, value interface{}) error {
+	var b []byte
+	switch value := value.(type) {
+	case string:
+		b = []byte(value)
+	case uint64:
+		b = []byte(strconv.FormatUint(value, 10))
+	case int:
+		b = []byte(strconv.Itoa(value))
+	}
+	path := filepath.Join(cgroup, name)
+	if err := ioutil.WriteFile(path, b, perms.ReadWrite); err != nil {
+		return fmt.Errorf("couldn't write %q to %s: %w", b, path, err)
+	}
+	return nil
+}
+
+// prlimit sets the limit of [resource] of the process [pid]. The syscall
+// package only sets the limits of the calling process.
+func prlimit(pid, resource int, rlimit *syscall.Rlimit) error {
+	_, _, errno := syscall.RawSyscall6(
+		syscall.SYS_PRLIMIT64,
+		uintptr(pid),
+		uintptr(resource),
+		uintptr(unsafe.Pointer(rlimit)),
+		0,
+		0,
+		0,
+	)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
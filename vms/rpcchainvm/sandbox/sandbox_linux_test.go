@@ -0,0 +1,96 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sandbox
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		limits Limits
+		err    error
+	}{
+		{limits: Limits{}},
+		{limits: Limits{FileDescriptors: 128}},
+		{limits: Limits{CPUShares: 50, MemoryBytes: 1 << 30, CgroupDir: "/sys/fs/cgroup/avalanchego"}},
+		{limits: Limits{CPUShares: MaxCPUShares + 1, CgroupDir: "/sys/fs/cgroup/avalanchego"}, err: errCPUShares},
+		{limits: Limits{MemoryBytes: 1 << 30}, err: errNoCgroupDir},
+		{limits: Limits{MemoryBytes: 1 << 30, CgroupDir: "cgroup"}, err: errNotAbsolute},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.err, test.limits.Verify(), "%+v", test.limits)
+	}
+}
+
+func startProcess(t *testing.T) *exec.Cmd {
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("couldn't start a process: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	return cmd
+}
+
+func TestFileDescriptorLimit(t *testing.T) {
+	cmd := startProcess(t)
+
+	s, err := New(Limits{FileDescriptors: 64}, "test", cmd.Process.Pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, s.Close())
+
+	limits, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/limits", cmd.Process.Pid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(string(limits), "\n") {
+		if strings.HasPrefix(line, "Max open files") {
+			assert.Equal(t, []string{"Max", "open", "files", "64", "64", "files"}, strings.Fields(line))
+			return
+		}
+	}
+	t.Fatal("the limits of the process don't include the open files")
+}
+
+func TestCgroup(t *testing.T) {
+	cmd := startProcess(t)
+
+	// A directory that isn't a cgroup is enough to check the control files
+	// that are written
+	dir := t.TempDir()
+	_, err := New(Limits{
+		CPUShares:   200,
+		MemoryBytes: 1 << 30,
+		CgroupDir:   dir,
+	}, "chain", cmd.Process.Pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for file, expected := range map[string]string{
+		"cgroup.subtree_control": "+cpu +memory",
+		"chain/cpu.weight":       "200",
+		"chain/memory.max":       "1073741824",
+		"chain/cgroup.procs":     fmt.Sprint(cmd.Process.Pid),
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, file))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(b), file)
+	}
+
+	_, err = New(Limits{}, "chain/child", cmd.Process.Pid)
+	assert.Equal(t, errNameHasSlash, err)
+}
@@ -0,0 +1,15 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !linux
+// +build !linux
+
+package sandbox
+
+const supported = false
+
+// apply is only called with limits that are verified, which are empty on
+// platforms that aren't supported
+func apply(Limits, string, int) (*Sandbox, error) { return &Sandbox{}, nil }
+
+func removeCgroup(string) error { return nil }
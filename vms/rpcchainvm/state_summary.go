@@ -0,0 +1,47 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var (
+	errStateSyncUnsupported = errors.New("vm doesn't support state sync")
+
+	// Errors that a summary response can carry, so that the client returns
+	// the same error value that the VM did
+	summaryErrCodeToError = map[uint32]error{
+		1: database.ErrNotFound,
+	}
+	summaryErrorToErrCode = map[error]uint32{
+		database.ErrNotFound: 1,
+	}
+
+	_ common.Summary = &SummaryClient{}
+)
+
+// summaryErrorToRPCError returns the error that the server returns for [err],
+// or nil if [err] is sent as an error code in the response
+func summaryErrorToRPCError(err error) error {
+	if _, ok := summaryErrorToErrCode[err]; ok {
+		return nil
+	}
+	return err
+}
+
+// SummaryClient is an implementation of Summary that talks over RPC.
+type SummaryClient struct {
+	id     ids.ID
+	height uint64
+	bytes  []byte
+}
+
+func (s *SummaryClient) ID() ids.ID     { return s.id }
+func (s *SummaryClient) Height() uint64 { return s.height }
+func (s *SummaryClient) Bytes() []byte  { return s.bytes }
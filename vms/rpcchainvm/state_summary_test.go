@@ -0,0 +1,150 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/vmproto"
+)
+
+const bufSize = 1 << 20
+
+type testSummary struct {
+	id     ids.ID
+	height uint64
+	bytes  []byte
+}
+
+func (s *testSummary) ID() ids.ID     { return s.id }
+func (s *testSummary) Height() uint64 { return s.height }
+func (s *testSummary) Bytes() []byte  { return s.bytes }
+
+// stateSyncVM is a ChainVM that can sync its state from [summary]
+type stateSyncVM struct {
+	*block.TestVM
+
+	summary   *testSummary
+	fetched   common.Summary
+	committed common.Summary
+}
+
+func (vm *stateSyncVM) StateSyncEnabled() (bool, error) { return true, nil }
+
+func (vm *stateSyncVM) GetLastStateSummary() (common.Summary, error) {
+	return vm.summary, nil
+}
+
+func (vm *stateSyncVM) GetStateSummary(height uint64) (common.Summary, error) {
+	if height != vm.summary.height {
+		return nil, database.ErrNotFound
+	}
+	return vm.summary, nil
+}
+
+func (vm *stateSyncVM) ParseStateSummary(summaryBytes []byte) (common.Summary, error) {
+	if !bytes.Equal(summaryBytes, vm.summary.bytes) {
+		return nil, errors.New("unknown summary")
+	}
+	return vm.summary, nil
+}
+
+func (vm *stateSyncVM) FetchState(summary common.Summary) error {
+	vm.fetched = summary
+	return nil
+}
+
+func (vm *stateSyncVM) CommitState(summary common.Summary) error {
+	vm.committed = summary
+	return nil
+}
+
+// newTestClient returns a client of a server that serves [vm] in-process
+func newTestClient(t *testing.T, vm block.ChainVM) *VMClient {
+	listener := bufconn.Listen(bufSize)
+	server := grpc.NewServer()
+	vmproto.RegisterVMServer(server, NewServer(vm, nil))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+	conn, err := grpc.DialContext(context.Background(), "", dialer, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return NewClient(vmproto.NewVMClient(conn), nil)
+}
+
+func TestStateSyncForwarded(t *testing.T) {
+	assert := assert.New(t)
+
+	summary := &testSummary{
+		id:     ids.ID{1},
+		height: 5,
+		bytes:  []byte{1, 2, 3},
+	}
+	vm := &stateSyncVM{
+		TestVM:  &block.TestVM{},
+		summary: summary,
+	}
+	client := newTestClient(t, vm)
+
+	enabled, err := client.StateSyncEnabled()
+	assert.NoError(err)
+	assert.True(enabled)
+
+	last, err := client.GetLastStateSummary()
+	assert.NoError(err)
+	assert.Equal(summary.id, last.ID())
+	assert.Equal(summary.height, last.Height())
+	assert.Equal(summary.bytes, last.Bytes())
+
+	_, err = client.GetStateSummary(summary.height + 1)
+	assert.Equal(database.ErrNotFound, err)
+
+	parsed, err := client.ParseStateSummary(summary.bytes)
+	assert.NoError(err)
+	assert.Equal(summary.id, parsed.ID())
+	assert.Equal(summary.height, parsed.Height())
+
+	_, err = client.ParseStateSummary([]byte{4})
+	assert.Error(err)
+
+	assert.NoError(client.FetchState(parsed))
+	assert.Equal(common.Summary(summary), vm.fetched)
+	assert.NoError(client.CommitState(parsed))
+	assert.Equal(common.Summary(summary), vm.committed)
+}
+
+func TestStateSyncUnsupported(t *testing.T) {
+	assert := assert.New(t)
+
+	client := newTestClient(t, &block.TestVM{})
+
+	enabled, err := client.StateSyncEnabled()
+	assert.NoError(err)
+	assert.False(enabled)
+
+	_, err = client.GetLastStateSummary()
+	assert.Error(err)
+	assert.Error(client.FetchState(&testSummary{}))
+}
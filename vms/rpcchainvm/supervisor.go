@@ -0,0 +1,292 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/vmproto"
+)
+
+const (
+	// Bounds of how long the plugin is waited for before it's restarted
+	// again, after it failed to restart
+	minRestartDelay = time.Second
+	maxRestartDelay = 10 * time.Second
+	// Number of times the plugin is restarted after its process exited,
+	// before it's left down
+	maxRestartAttempts = 8
+
+	// exitTimeout is how long a call that failed because the connection to
+	// the plugin broke waits for the plugin process to exit
+	exitTimeout = time.Second
+)
+
+var (
+	errPluginExited         = errors.New("plugin process exited and is being restarted")
+	errPluginFailed         = errors.New("plugin process exited and couldn't be restarted")
+	errShutdown             = errors.New("vm is shut down")
+	errLastAcceptedMismatch = errors.New("last accepted block of the restarted plugin doesn't match the chain")
+
+	_ http.Handler = &pluginHandler{}
+)
+
+// supervise restarts the plugin as soon as its process exits, if no call to
+// the plugin restarted it first, until the client shuts down
+func (vm *VMClient) supervise() {
+	for {
+		vm.ctx.Lock.Lock()
+		exited := vm.exited
+		vm.ctx.Lock.Unlock()
+
+		select {
+		case <-vm.stopSupervisor:
+			return
+		case <-exited:
+		}
+
+		vm.ctx.Lock.Lock()
+		err := vm.awaitPlugin()
+		vm.ctx.Lock.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// call calls the plugin with [f]. If the plugin process exited, the plugin is
+// restarted before [f] is called, and [f] is called again if the process
+// exited during the call. Calls block until the plugin is running, so that
+// the chain doesn't fail because of a crash that the plugin recovers from.
+// Assumes the context lock is held.
+func (vm *VMClient) call(f func(client vmproto.VMClient) error) error {
+	for {
+		if err := vm.awaitPlugin(); err != nil {
+			return err
+		}
+		err := f(vm.client)
+		if err == nil || !vm.crashed(err) {
+			return err
+		}
+	}
+}
+
+// awaitPlugin returns once the plugin is running, after restarting it if its
+// process exited. Returns an error if the plugin can't be restarted. Assumes
+// the context lock is held.
+func (vm *VMClient) awaitPlugin() error {
+	switch {
+	case vm.stopSupervisor == nil:
+		// The plugin isn't supervised before the client is initialized
+		return nil
+	case vm.shutdown:
+		return errShutdown
+	case errors.Is(vm.pluginErr, errPluginFailed):
+		return vm.pluginErr
+	case vm.pluginErr == nil && !vm.processExited():
+		return nil
+	}
+
+	if vm.pluginErr == nil {
+		vm.ctx.Log.Error("plugin of chain %s exited unexpectedly. Restarting it", vm.ctx.ChainID)
+		vm.pluginErr = errPluginExited
+		if err := vm.releaseProcess(); err != nil {
+			vm.ctx.Log.Debug("failed to release the exited plugin process: %s", err)
+		}
+		vm.handlersLock.Lock()
+		if vm.handlers != nil {
+			vm.handlers = make(map[string]http.Handler)
+		}
+		vm.handlersLock.Unlock()
+	}
+
+	delay := minRestartDelay
+	for attempt := 1; ; attempt++ {
+		err := vm.restart()
+		switch {
+		case err == nil:
+			vm.ctx.Log.Info("restarted the plugin of chain %s", vm.ctx.ChainID)
+			return nil
+		case errors.Is(err, errLastAcceptedMismatch):
+			vm.ctx.Log.Error("the plugin of chain %s can't be resynced, so it won't be restarted again: %s", vm.ctx.ChainID, err)
+			vm.pluginErr = fmt.Errorf("%w: %s", errPluginFailed, err)
+			return vm.pluginErr
+		case attempt == maxRestartAttempts:
+			vm.ctx.Log.Error("failed to restart the plugin of chain %s %d times, so it won't be restarted again: %s", vm.ctx.ChainID, attempt, err)
+			vm.pluginErr = fmt.Errorf("%w: %s", errPluginFailed, err)
+			return vm.pluginErr
+		}
+
+		vm.ctx.Log.Warn("failed to restart the plugin of chain %s. Retrying in %s: %s", vm.ctx.ChainID, delay, err)
+		vm.pluginErr = fmt.Errorf("%w: %s", errPluginExited, err)
+		time.Sleep(delay)
+		delay *= 2
+		if delay > maxRestartDelay {
+			delay = maxRestartDelay
+		}
+	}
+}
+
+// processExited returns true if the plugin process exited
+func (vm *VMClient) processExited() bool {
+	select {
+	case <-vm.exited:
+		return true
+	default:
+		return false
+	}
+}
+
+// crashed returns true if [err], the error of a call to the plugin, was
+// caused by the plugin process exiting. Errors returned by the plugin itself
+// don't break the connection to it.
+func (vm *VMClient) crashed(err error) bool {
+	if vm.stopSupervisor == nil || status.Code(err) != codes.Unavailable {
+		return false
+	}
+	select {
+	case <-vm.exited:
+		return true
+	case <-time.After(exitTimeout):
+		return false
+	}
+}
+
+// restart starts a new plugin process, initializes it and brings it up to
+// date with the chain. Assumes the context lock is held.
+func (vm *VMClient) restart() error {
+	restartedIntf, err := vm.factory.New(vm.ctx)
+	if err != nil {
+		return err
+	}
+	restarted := restartedIntf.(*VMClient)
+
+	vm.client = restarted.client
+	vm.broker = restarted.broker
+	vm.proc = restarted.proc
+	vm.exited = restarted.exited
+	vm.sandbox = restarted.sandbox
+	vm.processMetrics = restarted.processMetrics
+	vm.serverCloser = grpcutils.ServerCloser{}
+	vm.generation++
+
+	resp, err := vm.initializePlugin()
+	if err == nil {
+		err = vm.resync(resp)
+	}
+	if err != nil {
+		_ = vm.releaseProcess()
+		return err
+	}
+	vm.restarts++
+	vm.pluginErr = nil
+	return nil
+}
+
+// resync brings a restarted plugin up to date with the chain. The plugin's
+// last accepted block must be the chain's, so that the blocks that are in
+// consensus can be verified by it again. If the plugin exited while a block
+// was being accepted, the plugin's last accepted block may be the parent of
+// that block instead, and the block is accepted again. Assumes the context
+// lock is held.
+func (vm *VMClient) resync(resp *vmproto.InitializeResponse) error {
+	lastAcceptedID, err := ids.ToID(resp.LastAcceptedID)
+	if err != nil {
+		return err
+	}
+	expectedID, err := vm.State.LastAccepted()
+	if err != nil {
+		return err
+	}
+	accepting := vm.accepting
+	switch {
+	case lastAcceptedID == expectedID:
+	case accepting != nil && accepting.id == expectedID && accepting.parentID == lastAcceptedID:
+		if err := accepting.reparse(vm.client); err != nil {
+			return fmt.Errorf("couldn't parse accepted block %s: %w", accepting.id, err)
+		}
+		if err := accepting.verify(vm.client); err != nil {
+			return fmt.Errorf("couldn't verify accepted block %s: %w", accepting.id, err)
+		}
+		if err := accepting.accept(vm.client); err != nil {
+			return fmt.Errorf("couldn't accept block %s: %w", accepting.id, err)
+		}
+	default:
+		return fmt.Errorf("%w: expected %s but got %s", errLastAcceptedMismatch, expectedID, lastAcceptedID)
+	}
+	vm.pluginLastAccepted = expectedID
+
+	if vm.bootstrapping {
+		if _, err := vm.client.Bootstrapping(context.Background(), &vmproto.BootstrappingRequest{}); err != nil {
+			return err
+		}
+	}
+	if vm.bootstrapped {
+		if _, err := vm.client.Bootstrapped(context.Background(), &vmproto.BootstrappedRequest{}); err != nil {
+			return err
+		}
+	}
+
+	// The blocks in consensus are replayed in order of height, so that the
+	// parent of each block is verified before it
+	processing := vm.State.ProcessingBlocks()
+	for _, wrappedBlk := range processing {
+		blk, ok := wrappedBlk.Block.(*BlockClient)
+		if !ok {
+			continue
+		}
+		if err := blk.reparse(vm.client); err != nil {
+			return fmt.Errorf("couldn't parse processing block %s: %w", blk.ID(), err)
+		}
+		if err := blk.verify(vm.client); err != nil {
+			return fmt.Errorf("couldn't verify processing block %s: %w", blk.ID(), err)
+		}
+	}
+	if vm.preferred != ids.Empty {
+		if _, err := vm.client.SetPreference(context.Background(), &vmproto.SetPreferenceRequest{
+			Id: vm.preferred[:],
+		}); err != nil {
+			return err
+		}
+	}
+
+	vm.handlersLock.RLock()
+	handlersCreated := vm.handlers != nil
+	vm.handlersLock.RUnlock()
+	if handlersCreated {
+		if _, err := vm.createPluginHandlers(vm.client); err != nil {
+			return fmt.Errorf("couldn't create the handlers of the plugin: %w", err)
+		}
+	}
+
+	vm.ctx.Log.Info("resynced the plugin of chain %s with %d processing blocks", vm.ctx.ChainID, len(processing))
+	return nil
+}
+
+// pluginHandler routes requests to the handler of the plugin with [prefix],
+// so that handlers keep working when the plugin is restarted
+type pluginHandler struct {
+	vm     *VMClient
+	prefix string
+}
+
+func (h *pluginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.vm.handlersLock.RLock()
+	handler, ok := h.vm.handlers[h.prefix]
+	h.vm.handlersLock.RUnlock()
+	if !ok {
+		http.Error(w, errPluginExited.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
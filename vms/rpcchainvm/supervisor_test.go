@@ -0,0 +1,211 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var (
+	testGenesisID = ids.ID{1}
+	testChildID   = ids.ID{2}
+	testChildByte = []byte{2}
+)
+
+// newTestPluginVM returns the VM that the test binary serves as a plugin. Its
+// child block is only known once it's parsed.
+func newTestPluginVM() block.ChainVM {
+	genesis := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     testGenesisID,
+			StatusV: choices.Accepted,
+		},
+		ParentV: &snowman.TestBlock{},
+		BytesV:  []byte{1},
+	}
+	child := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     testChildID,
+			StatusV: choices.Processing,
+		},
+		ParentV: genesis,
+		HeightV: 1,
+		BytesV:  testChildByte,
+	}
+	parsed := false
+
+	vm := &block.TestVM{}
+	vm.InitializeF = func(*snow.Context, manager.Manager, []byte, []byte, []byte, chan<- common.Message, []*common.Fx) error {
+		return nil
+	}
+	vm.HealthCheckF = func() (interface{}, error) { return nil, nil }
+	vm.LastAcceptedF = func() (ids.ID, error) { return testGenesisID, nil }
+	vm.SetPreferenceF = func(ids.ID) error { return nil }
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		if !bytes.Equal(b, testChildByte) {
+			return nil, errors.New("unknown block")
+		}
+		parsed = true
+		return child, nil
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID == testGenesisID:
+			return genesis, nil
+		case blkID == testChildID && parsed:
+			return child, nil
+		default:
+			return nil, database.ErrNotFound
+		}
+	}
+	return vm
+}
+
+// startTestPlugin starts the test plugin VM and initializes it. Returns with
+// the context lock held.
+func startTestPlugin(t *testing.T) (*snow.Context, *VMClient) {
+	if err := os.Setenv(testPluginVersionEnv, ""); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Unsetenv(testPluginVersionEnv) })
+	if err := os.Setenv(testPluginVMEnv, ""); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Unsetenv(testPluginVMEnv) })
+
+	ctx := snow.DefaultContextTest()
+	factory := &Factory{Path: os.Args[0]}
+	vmIntf, err := factory.New(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := vmIntf.(*VMClient)
+
+	ctx.Lock.Lock()
+	if err := vm.Initialize(ctx, manager.NewDefaultMemDBManager(), nil, nil, nil, make(chan common.Message, 1), nil); err != nil {
+		t.Fatal(err)
+	}
+	return ctx, vm
+}
+
+// killTestPlugin kills the process of the test plugin
+func killTestPlugin(t *testing.T, vm *VMClient) {
+	proc, err := os.FindProcess(vm.proc.ReattachConfig().Pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proc.Kill(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSupervisorRestartsPlugin(t *testing.T) {
+	defer plugin.CleanupClients()
+
+	ctx, vm := startTestPlugin(t)
+	handlers, err := vm.CreateHandlers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(handlers) != 0 {
+		t.Fatalf("expected no handlers but got %d", len(handlers))
+	}
+	child, err := vm.ParseBlock(testChildByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := child.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.SetPreference(child.ID()); err != nil {
+		t.Fatal(err)
+	}
+	pid := vm.proc.ReattachConfig().Pid
+	ctx.Lock.Unlock()
+
+	killTestPlugin(t, vm)
+
+	// The chain is unhealthy until the plugin is restarted and resynced
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		ctx.Lock.Lock()
+		restarts := vm.restarts
+		_, healthErr := vm.HealthCheck()
+		ctx.Lock.Unlock()
+		if restarts == 1 && healthErr == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("plugin wasn't restarted: %v", healthErr)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+	if newPID := vm.proc.ReattachConfig().Pid; newPID == pid {
+		t.Fatal("plugin should be running in a new process")
+	}
+	// The processing block was replayed to the restarted plugin, so it can
+	// be decided
+	if err := child.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCallsWaitForPluginRestart(t *testing.T) {
+	defer plugin.CleanupClients()
+
+	ctx, vm := startTestPlugin(t)
+	defer ctx.Lock.Unlock()
+
+	child, err := vm.ParseBlock(testChildByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := child.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	pid := vm.proc.ReattachConfig().Pid
+
+	// The plugin exits while the chain holds the context lock, so the call
+	// restarts the plugin instead of failing
+	killTestPlugin(t, vm)
+	if err := child.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if child.Status() != choices.Accepted {
+		t.Fatalf("expected the block to be accepted but it's %s", child.Status())
+	}
+	if vm.restarts != 1 {
+		t.Fatalf("expected the plugin to be restarted once but it was restarted %d times", vm.restarts)
+	}
+	if newPID := vm.proc.ReattachConfig().Pid; newPID == pid {
+		t.Fatal("plugin should be running in a new process")
+	}
+	if _, err := vm.HealthCheck(); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -14,9 +14,19 @@ import (
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/vmproto"
 )
 
+const (
+	// ProtocolVersion is the newest version of the protocol between the node
+	// and VM plugins
+	ProtocolVersion = 3
+	// MinProtocolVersion is the oldest version of the protocol that is still
+	// supported, so that plugins don't need to be upgraded together with the
+	// node
+	MinProtocolVersion = 2
+)
+
 // Handshake is a common handshake that is shared by plugin and host.
 var Handshake = plugin.HandshakeConfig{
-	ProtocolVersion:  2,
+	ProtocolVersion:  ProtocolVersion,
 	MagicCookieKey:   "VM_PLUGIN",
 	MagicCookieValue: "dynamic",
 }
@@ -26,6 +36,30 @@ var PluginMap = map[string]plugin.Plugin{
 	"vm": &Plugin{},
 }
 
+// VersionedPluginMap maps each supported version of the protocol to the
+// plugins that are dispensed over it. The node and the plugin use the newest
+// version that both of them support. Versions 2 and 3 share a wire format;
+// a version that changes the wire format must map to plugins of its own.
+var VersionedPluginMap = map[int]plugin.PluginSet{
+	2: PluginMap,
+	3: PluginMap,
+}
+
+// Serve [vm] as a plugin over every supported version of the protocol. This
+// should be called by the main function of VM plugins.
+func Serve(vm block.ChainVM) {
+	versionedPlugins := make(map[int]plugin.PluginSet, len(VersionedPluginMap))
+	for version := range VersionedPluginMap {
+		versionedPlugins[version] = plugin.PluginSet{"vm": New(vm)}
+	}
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig:  Handshake,
+		VersionedPlugins: versionedPlugins,
+		// A non-nil value here enables gRPC serving for this plugin
+		GRPCServer: plugin.DefaultGRPCServer,
+	})
+}
+
 // Plugin is the implementation of plugin.Plugin so we can serve/consume this.
 // We also implement GRPCPlugin so that this plugin can be served over gRPC.
 type Plugin struct {
@@ -7,10 +7,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"sync"
 
 	"google.golang.org/grpc"
 
 	"github.com/hashicorp/go-plugin"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/api/keystore/gkeystore"
 	"github.com/ava-labs/avalanchego/api/keystore/gkeystore/gkeystoreproto"
@@ -37,13 +40,15 @@ import (
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gsubnetlookup/gsubnetlookupproto"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/messenger"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/messenger/messengerproto"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/sandbox"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/vmproto"
 )
 
 var (
 	errUnsupportedFXs = errors.New("unsupported feature extensions")
 
-	_ block.ChainVM = &VMClient{}
+	_ block.ChainVM          = &VMClient{}
+	_ common.StateSyncableVM = &VMClient{}
 )
 
 const (
@@ -58,6 +63,14 @@ type VMClient struct {
 	client vmproto.VMClient
 	broker *plugin.GRPCBroker
 	proc   *plugin.Client
+	// Closed when the plugin process exits
+	exited <-chan struct{}
+
+	// Limits of the resources of the plugin process and its metrics
+	sandbox        *sandbox.Sandbox
+	processMetrics prometheus.Collector
+	// Collects the metrics that the plugin registered with its context
+	pluginMetrics *pluginCollector
 
 	db           *rpcdb.DatabaseServer
 	messenger    *messenger.Server
@@ -70,6 +83,41 @@ type VMClient struct {
 	conns        []*grpc.ClientConn
 
 	ctx *snow.Context
+
+	// Arguments that the plugin was initialized with, so that it can be
+	// initialized again if its process is restarted
+	factory      *Factory
+	dbManager    manager.Manager
+	genesisBytes []byte
+	upgradeBytes []byte
+	configBytes  []byte
+	toEngine     chan<- common.Message
+
+	// State of the chain that a restarted plugin is brought up to date with
+	bootstrapping, bootstrapped bool
+	preferred                   ids.ID
+	// Block that is being accepted, if any
+	accepting *BlockClient
+	// Last accepted block of the plugin when it was last initialized
+	pluginLastAccepted ids.ID
+
+	// Handlers of the plugin by their prefix. The handlers that the client
+	// returns route requests to these, so that they keep working when the
+	// plugin is restarted. Nil until the handlers are created.
+	handlersLock sync.RWMutex
+	handlers     map[string]http.Handler
+
+	// Closed when the client shuts down, which stops the supervisor. Nil
+	// until the client is initialized.
+	stopSupervisor chan struct{}
+	shutdown       bool
+	// Incremented every time the plugin is restarted
+	generation uint64
+	// Number of times the plugin was restarted
+	restarts int
+	// Non-nil while the plugin isn't running. Wraps errPluginFailed if it
+	// won't be restarted again.
+	pluginErr error
 }
 
 // NewClient returns a VM connected to a remote VM
@@ -98,16 +146,77 @@ func (vm *VMClient) Initialize(
 		return errUnsupportedFXs
 	}
 
-	epochFirstTransitionBytes, err := ctx.EpochFirstTransition.MarshalBinary()
+	vm.ctx = ctx
+	vm.dbManager = dbManager
+	vm.genesisBytes = genesisBytes
+	vm.upgradeBytes = upgradeBytes
+	vm.configBytes = configBytes
+	vm.toEngine = toEngine
+
+	resp, err := vm.initializePlugin()
 	if err != nil {
 		return err
 	}
 
-	vm.ctx = ctx
+	id, err := ids.ToID(resp.LastAcceptedID)
+	if err != nil {
+		return err
+	}
+	parentID, err := ids.ToID(resp.LastAcceptedParentID)
+	if err != nil {
+		return err
+	}
+
+	status := choices.Status(resp.Status)
+	vm.ctx.Log.AssertDeferredNoError(status.Valid)
+
+	lastAcceptedBlk := &BlockClient{
+		vm:         vm,
+		id:         id,
+		parentID:   parentID,
+		status:     status,
+		bytes:      resp.Bytes,
+		height:     resp.Height,
+		generation: vm.generation,
+	}
+	vm.pluginLastAccepted = id
+
+	chainState, err := chain.NewMeteredState(
+		ctx.Metrics,
+		fmt.Sprintf("%s_rpcchainvm", ctx.Namespace),
+		&chain.Config{
+			DecidedCacheSize:    decidedCacheSize,
+			MissingCacheSize:    missingCacheSize,
+			UnverifiedCacheSize: unverifiedCacheSize,
+			LastAcceptedBlock:   lastAcceptedBlk,
+			GetBlock:            vm.getBlock,
+			UnmarshalBlock:      vm.parseBlock,
+			BuildBlock:          vm.buildBlock,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	vm.State = chainState
+
+	vm.stopSupervisor = make(chan struct{})
+	go vm.supervise()
+	return nil
+}
+
+// initializePlugin serves the node's services to the plugin and initializes
+// the VM in the plugin with the arguments that the client was initialized
+// with
+func (vm *VMClient) initializePlugin() (*vmproto.InitializeResponse, error) {
+	ctx := vm.ctx
+	epochFirstTransitionBytes, err := ctx.EpochFirstTransition.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
 
 	// Initialize and serve each database and construct the db manager
 	// initialize request parameters
-	versionedDBs := dbManager.GetDatabases()
+	versionedDBs := vm.dbManager.GetDatabases()
 	versionedDBServers := make([]*vmproto.VersionedDBServer, len(versionedDBs))
 	for i, semDB := range versionedDBs {
 		dbBrokerID := vm.broker.NextId()
@@ -119,9 +228,9 @@ func (vm *VMClient) Initialize(
 		}
 	}
 
-	vm.messenger = messenger.NewServer(toEngine)
+	vm.messenger = messenger.NewServer(vm.toEngine)
 	vm.keystore = gkeystore.NewServer(ctx.Keystore, vm.broker)
-	vm.sharedMemory = gsharedmemory.NewServer(ctx.SharedMemory, dbManager.Current().Database)
+	vm.sharedMemory = gsharedmemory.NewServer(ctx.SharedMemory, vm.dbManager.Current().Database)
 	vm.bcLookup = galiaslookup.NewServer(ctx.BCLookup)
 	vm.snLookup = gsubnetlookup.NewServer(ctx.SNLookup)
 
@@ -149,16 +258,16 @@ func (vm *VMClient) Initialize(
 	snLookupBrokerID := vm.broker.NextId()
 	go vm.broker.AcceptAndServe(snLookupBrokerID, vm.startSNLookupServer)
 
-	resp, err := vm.client.Initialize(context.Background(), &vmproto.InitializeRequest{
+	return vm.client.Initialize(context.Background(), &vmproto.InitializeRequest{
 		NetworkID:            ctx.NetworkID,
 		SubnetID:             ctx.SubnetID[:],
 		ChainID:              ctx.ChainID[:],
 		NodeID:               ctx.NodeID.Bytes(),
 		XChainID:             ctx.XChainID[:],
 		AvaxAssetID:          ctx.AVAXAssetID[:],
-		GenesisBytes:         genesisBytes,
-		UpgradeBytes:         upgradeBytes,
-		ConfigBytes:          configBytes,
+		GenesisBytes:         vm.genesisBytes,
+		UpgradeBytes:         vm.upgradeBytes,
+		ConfigBytes:          vm.configBytes,
 		DbServers:            versionedDBServers,
 		EngineServer:         messengerBrokerID,
 		KeystoreServer:       keystoreBrokerID,
@@ -168,50 +277,6 @@ func (vm *VMClient) Initialize(
 		EpochFirstTransition: epochFirstTransitionBytes,
 		EpochDuration:        uint64(ctx.EpochDuration),
 	})
-	if err != nil {
-		return err
-	}
-
-	id, err := ids.ToID(resp.LastAcceptedID)
-	if err != nil {
-		return err
-	}
-	parentID, err := ids.ToID(resp.LastAcceptedParentID)
-	if err != nil {
-		return err
-	}
-
-	status := choices.Status(resp.Status)
-	vm.ctx.Log.AssertDeferredNoError(status.Valid)
-
-	lastAcceptedBlk := &BlockClient{
-		vm:       vm,
-		id:       id,
-		parentID: parentID,
-		status:   status,
-		bytes:    resp.Bytes,
-		height:   resp.Height,
-	}
-
-	chainState, err := chain.NewMeteredState(
-		ctx.Metrics,
-		fmt.Sprintf("%s_rpcchainvm", ctx.Namespace),
-		&chain.Config{
-			DecidedCacheSize:    decidedCacheSize,
-			MissingCacheSize:    missingCacheSize,
-			UnverifiedCacheSize: unverifiedCacheSize,
-			LastAcceptedBlock:   lastAcceptedBlk,
-			GetBlock:            vm.getBlock,
-			UnmarshalBlock:      vm.parseBlock,
-			BuildBlock:          vm.buildBlock,
-		},
-	)
-	if err != nil {
-		return err
-	}
-	vm.State = chainState
-
-	return nil
 }
 
 func (vm *VMClient) startDBServer(opts []grpc.ServerOption) *grpc.Server {
@@ -266,31 +331,88 @@ func (vm *VMClient) startSNLookupServer(opts []grpc.ServerOption) *grpc.Server {
 }
 
 func (vm *VMClient) Bootstrapping() error {
-	_, err := vm.client.Bootstrapping(context.Background(), &vmproto.BootstrappingRequest{})
-	return err
+	vm.bootstrapping = true
+	return vm.call(func(client vmproto.VMClient) error {
+		_, err := client.Bootstrapping(context.Background(), &vmproto.BootstrappingRequest{})
+		return err
+	})
 }
 
 func (vm *VMClient) Bootstrapped() error {
-	_, err := vm.client.Bootstrapped(context.Background(), &vmproto.BootstrappedRequest{})
-	return err
+	vm.bootstrapped = true
+	return vm.call(func(client vmproto.VMClient) error {
+		_, err := client.Bootstrapped(context.Background(), &vmproto.BootstrappedRequest{})
+		return err
+	})
 }
 
 func (vm *VMClient) Shutdown() error {
+	if vm.stopSupervisor != nil {
+		close(vm.stopSupervisor)
+	}
+	vm.shutdown = true
+
 	errs := wrappers.Errs{}
-	_, err := vm.client.Shutdown(context.Background(), &vmproto.ShutdownRequest{})
-	errs.Add(err)
+	if vm.pluginErr == nil && !vm.processExited() {
+		_, err := vm.client.Shutdown(context.Background(), &vmproto.ShutdownRequest{})
+		errs.Add(err)
+	}
+	errs.Add(vm.releaseProcess())
+	return errs.Err
+}
 
+// releaseProcess stops serving the plugin process, kills it and releases its
+// sandbox and metrics
+func (vm *VMClient) releaseProcess() error {
+	errs := wrappers.Errs{}
 	vm.serverCloser.Stop()
 	for _, conn := range vm.conns {
 		errs.Add(conn.Close())
 	}
+	vm.conns = nil
 
 	vm.proc.Kill()
+	errs.Add(vm.sandbox.Close())
+	vm.sandbox = nil
+	if vm.processMetrics != nil {
+		vm.ctx.Metrics.Unregister(vm.processMetrics)
+		vm.processMetrics = nil
+	}
+	if vm.pluginMetrics != nil {
+		vm.pluginMetrics.stop()
+		vm.pluginMetrics = nil
+	}
 	return errs.Err
 }
 
 func (vm *VMClient) CreateHandlers() (map[string]*common.HTTPHandler, error) {
-	resp, err := vm.client.CreateHandlers(context.Background(), &vmproto.CreateHandlersRequest{})
+	var pluginHandlers map[string]*common.HTTPHandler
+	err := vm.call(func(client vmproto.VMClient) error {
+		var err error
+		pluginHandlers, err = vm.createPluginHandlers(client)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	handlers := make(map[string]*common.HTTPHandler, len(pluginHandlers))
+	for prefix, handler := range pluginHandlers {
+		handlers[prefix] = &common.HTTPHandler{
+			LockOptions: handler.LockOptions,
+			Handler: &pluginHandler{
+				vm:     vm,
+				prefix: prefix,
+			},
+		}
+	}
+	return handlers, nil
+}
+
+// createPluginHandlers creates the handlers of the plugin, and routes the
+// requests to the handlers of the client to them
+func (vm *VMClient) createPluginHandlers(client vmproto.VMClient) (map[string]*common.HTTPHandler, error) {
+	resp, err := client.CreateHandlers(context.Background(), &vmproto.CreateHandlersRequest{})
 	if err != nil {
 		return nil, err
 	}
@@ -303,14 +425,41 @@ func (vm *VMClient) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 		}
 
 		vm.conns = append(vm.conns, conn)
+		httpClient := ghttp.NewClient(ghttpproto.NewHTTPClient(conn), vm.broker)
+		if handler.Prefix == metricsHandlerPrefix {
+			if err := vm.registerPluginMetrics(httpClient); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		handlers[handler.Prefix] = &common.HTTPHandler{
 			LockOptions: common.LockOption(handler.LockOptions),
-			Handler:     ghttp.NewClient(ghttpproto.NewHTTPClient(conn), vm.broker),
+			Handler:     httpClient,
 		}
 	}
+
+	vm.handlersLock.Lock()
+	vm.handlers = make(map[string]http.Handler, len(handlers))
+	for prefix, handler := range handlers {
+		vm.handlers[prefix] = handler.Handler
+	}
+	vm.handlersLock.Unlock()
 	return handlers, nil
 }
 
+// registerPluginMetrics registers the metrics that the plugin serves over
+// [handler] with the chain's metrics, so that they're reported by the node
+func (vm *VMClient) registerPluginMetrics(handler http.Handler) error {
+	if vm.pluginMetrics != nil {
+		vm.pluginMetrics.stop()
+	}
+	vm.pluginMetrics = newPluginCollector(vm.ctx.Namespace, handler, vm.ctx.Log)
+	if err := vm.ctx.Metrics.Register(vm.pluginMetrics); err != nil {
+		return fmt.Errorf("couldn't register the metrics of the plugin: %w", err)
+	}
+	return nil
+}
+
 func (vm *VMClient) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
 	resp, err := vm.client.CreateStaticHandlers(context.Background(), &vmproto.CreateStaticHandlersRequest{})
 	if err != nil {
@@ -334,7 +483,12 @@ func (vm *VMClient) CreateStaticHandlers() (map[string]*common.HTTPHandler, erro
 }
 
 func (vm *VMClient) buildBlock() (snowman.Block, error) {
-	resp, err := vm.client.BuildBlock(context.Background(), &vmproto.BuildBlockRequest{})
+	var resp *vmproto.BuildBlockResponse
+	err := vm.call(func(client vmproto.VMClient) error {
+		var err error
+		resp, err = client.BuildBlock(context.Background(), &vmproto.BuildBlockRequest{})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -346,18 +500,24 @@ func (vm *VMClient) buildBlock() (snowman.Block, error) {
 	vm.ctx.Log.AssertNoError(err)
 
 	return &BlockClient{
-		vm:       vm,
-		id:       id,
-		parentID: parentID,
-		status:   choices.Processing,
-		bytes:    resp.Bytes,
-		height:   resp.Height,
+		vm:         vm,
+		id:         id,
+		parentID:   parentID,
+		status:     choices.Processing,
+		bytes:      resp.Bytes,
+		height:     resp.Height,
+		generation: vm.generation,
 	}, nil
 }
 
 func (vm *VMClient) parseBlock(bytes []byte) (snowman.Block, error) {
-	resp, err := vm.client.ParseBlock(context.Background(), &vmproto.ParseBlockRequest{
-		Bytes: bytes,
+	var resp *vmproto.ParseBlockResponse
+	err := vm.call(func(client vmproto.VMClient) error {
+		var err error
+		resp, err = client.ParseBlock(context.Background(), &vmproto.ParseBlockRequest{
+			Bytes: bytes,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -373,20 +533,26 @@ func (vm *VMClient) parseBlock(bytes []byte) (snowman.Block, error) {
 	vm.ctx.Log.AssertDeferredNoError(status.Valid)
 
 	blk := &BlockClient{
-		vm:       vm,
-		id:       id,
-		parentID: parentID,
-		status:   status,
-		bytes:    bytes,
-		height:   resp.Height,
+		vm:         vm,
+		id:         id,
+		parentID:   parentID,
+		status:     status,
+		bytes:      bytes,
+		height:     resp.Height,
+		generation: vm.generation,
 	}
 
 	return blk, nil
 }
 
 func (vm *VMClient) getBlock(id ids.ID) (snowman.Block, error) {
-	resp, err := vm.client.GetBlock(context.Background(), &vmproto.GetBlockRequest{
-		Id: id[:],
+	var resp *vmproto.GetBlockResponse
+	err := vm.call(func(client vmproto.VMClient) error {
+		var err error
+		resp, err = client.GetBlock(context.Background(), &vmproto.GetBlockRequest{
+			Id: id[:],
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -398,31 +564,133 @@ func (vm *VMClient) getBlock(id ids.ID) (snowman.Block, error) {
 	vm.ctx.Log.AssertDeferredNoError(status.Valid)
 
 	blk := &BlockClient{
-		vm:       vm,
-		id:       id,
-		parentID: parentID,
-		status:   status,
-		bytes:    resp.Bytes,
-		height:   resp.Height,
+		vm:         vm,
+		id:         id,
+		parentID:   parentID,
+		status:     status,
+		bytes:      resp.Bytes,
+		height:     resp.Height,
+		generation: vm.generation,
 	}
 
 	return blk, nil
 }
 
 func (vm *VMClient) SetPreference(id ids.ID) error {
-	_, err := vm.client.SetPreference(context.Background(), &vmproto.SetPreferenceRequest{
-		Id: id[:],
+	vm.preferred = id
+	return vm.call(func(client vmproto.VMClient) error {
+		_, err := client.SetPreference(context.Background(), &vmproto.SetPreferenceRequest{
+			Id: id[:],
+		})
+		return err
 	})
-	return err
 }
 
 func (vm *VMClient) HealthCheck() (interface{}, error) {
+	if vm.pluginErr != nil {
+		return nil, vm.pluginErr
+	}
+	if vm.stopSupervisor != nil && vm.processExited() {
+		return nil, errPluginExited
+	}
 	return vm.client.Health(
 		context.Background(),
 		&vmproto.HealthRequest{},
 	)
 }
 
+func (vm *VMClient) StateSyncEnabled() (bool, error) {
+	var resp *vmproto.StateSyncEnabledResponse
+	err := vm.call(func(client vmproto.VMClient) error {
+		var err error
+		resp, err = client.StateSyncEnabled(context.Background(), &vmproto.StateSyncEnabledRequest{})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.Enabled, nil
+}
+
+func (vm *VMClient) GetLastStateSummary() (common.Summary, error) {
+	var resp *vmproto.GetLastStateSummaryResponse
+	err := vm.call(func(client vmproto.VMClient) error {
+		var err error
+		resp, err = client.GetLastStateSummary(context.Background(), &vmproto.GetLastStateSummaryRequest{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := summaryErrCodeToError[resp.Err]; err != nil {
+		return nil, err
+	}
+	return vm.newSummary(resp.Id, resp.Height, resp.Bytes)
+}
+
+func (vm *VMClient) GetStateSummary(height uint64) (common.Summary, error) {
+	var resp *vmproto.GetStateSummaryResponse
+	err := vm.call(func(client vmproto.VMClient) error {
+		var err error
+		resp, err = client.GetStateSummary(context.Background(), &vmproto.GetStateSummaryRequest{
+			Height: height,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := summaryErrCodeToError[resp.Err]; err != nil {
+		return nil, err
+	}
+	return vm.newSummary(resp.Id, resp.Height, resp.Bytes)
+}
+
+func (vm *VMClient) ParseStateSummary(summaryBytes []byte) (common.Summary, error) {
+	var resp *vmproto.ParseStateSummaryResponse
+	err := vm.call(func(client vmproto.VMClient) error {
+		var err error
+		resp, err = client.ParseStateSummary(context.Background(), &vmproto.ParseStateSummaryRequest{
+			Bytes: summaryBytes,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vm.newSummary(resp.Id, resp.Height, summaryBytes)
+}
+
+func (vm *VMClient) FetchState(summary common.Summary) error {
+	return vm.call(func(client vmproto.VMClient) error {
+		_, err := client.FetchState(context.Background(), &vmproto.FetchStateRequest{
+			Bytes: summary.Bytes(),
+		})
+		return err
+	})
+}
+
+func (vm *VMClient) CommitState(summary common.Summary) error {
+	return vm.call(func(client vmproto.VMClient) error {
+		_, err := client.CommitState(context.Background(), &vmproto.CommitStateRequest{
+			Bytes: summary.Bytes(),
+		})
+		return err
+	})
+}
+
+func (vm *VMClient) newSummary(summaryID []byte, height uint64, bytes []byte) (common.Summary, error) {
+	id, err := ids.ToID(summaryID)
+	if err != nil {
+		return nil, err
+	}
+	return &SummaryClient{
+		id:     id,
+		height: height,
+		bytes:  bytes,
+	}, nil
+}
+
 // BlockClient is an implementation of Block that talks over RPC.
 type BlockClient struct {
 	vm *VMClient
@@ -432,24 +700,57 @@ type BlockClient struct {
 	status   choices.Status
 	bytes    []byte
 	height   uint64
+
+	// Generation of the plugin that last parsed this block. A restarted
+	// plugin doesn't know the blocks that its previous process parsed.
+	generation uint64
 }
 
 func (b *BlockClient) ID() ids.ID { return b.id }
 
 func (b *BlockClient) Accept() error {
+	b.vm.accepting = b
+	defer func() { b.vm.accepting = nil }()
+
+	err := b.vm.call(func(client vmproto.VMClient) error {
+		// If the plugin was restarted during the call, it was resynced with
+		// this block accepted
+		if b.vm.pluginLastAccepted == b.id {
+			return nil
+		}
+		return b.accept(client)
+	})
+	if err != nil {
+		return err
+	}
 	b.status = choices.Accepted
-	_, err := b.vm.client.BlockAccept(context.Background(), &vmproto.BlockAcceptRequest{
+	return nil
+}
+
+func (b *BlockClient) accept(client vmproto.VMClient) error {
+	_, err := client.BlockAccept(context.Background(), &vmproto.BlockAcceptRequest{
 		Id: b.id[:],
 	})
 	return err
 }
 
 func (b *BlockClient) Reject() error {
-	b.status = choices.Rejected
-	_, err := b.vm.client.BlockReject(context.Background(), &vmproto.BlockRejectRequest{
-		Id: b.id[:],
+	err := b.vm.call(func(client vmproto.VMClient) error {
+		// A block that the current plugin process didn't parse isn't
+		// processing in it, so there's nothing to reject
+		if b.generation != b.vm.generation {
+			return nil
+		}
+		_, err := client.BlockReject(context.Background(), &vmproto.BlockRejectRequest{
+			Id: b.id[:],
+		})
+		return err
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	b.status = choices.Rejected
+	return nil
 }
 
 func (b *BlockClient) Status() choices.Status { return b.status }
@@ -462,12 +763,35 @@ func (b *BlockClient) Parent() snowman.Block {
 }
 
 func (b *BlockClient) Verify() error {
-	_, err := b.vm.client.BlockVerify(context.Background(), &vmproto.BlockVerifyRequest{
+	return b.vm.call(func(client vmproto.VMClient) error {
+		if err := b.reparse(client); err != nil {
+			return err
+		}
+		return b.verify(client)
+	})
+}
+
+func (b *BlockClient) verify(client vmproto.VMClient) error {
+	_, err := client.BlockVerify(context.Background(), &vmproto.BlockVerifyRequest{
 		Id: b.id[:],
 	})
 	return err
 }
 
+// reparse the block in the current plugin process, if it wasn't parsed by it
+func (b *BlockClient) reparse(client vmproto.VMClient) error {
+	if b.generation == b.vm.generation {
+		return nil
+	}
+	_, err := client.ParseBlock(context.Background(), &vmproto.ParseBlockRequest{
+		Bytes: b.bytes,
+	})
+	if err == nil {
+		b.generation = b.vm.generation
+	}
+	return err
+}
+
 func (b *BlockClient) Bytes() []byte  { return b.bytes }
 func (b *BlockClient) Height() uint64 { return b.height }
 
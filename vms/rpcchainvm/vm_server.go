@@ -11,6 +11,8 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/hashicorp/go-plugin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/ava-labs/avalanchego/api/keystore/gkeystore"
 	"github.com/ava-labs/avalanchego/api/keystore/gkeystore/gkeystoreproto"
@@ -52,6 +54,10 @@ type VMServer struct {
 
 	ctx      *snow.Context
 	toEngine chan common.Message
+
+	// Metrics that the VM registered with its context. The node collects them
+	// over the handler at [metricsHandlerPrefix].
+	metrics *prometheus.Registry
 }
 
 // NewServer returns a vm instance connected to a remote vm instance
@@ -173,6 +179,10 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 		}
 	}()
 
+	// The metrics of the VM are reported to the node, which prefixes their
+	// names with the namespace of the chain. This is why the namespace is left
+	// empty here.
+	vm.metrics = prometheus.NewRegistry()
 	vm.ctx = &snow.Context{
 		NetworkID:            req.NetworkID,
 		SubnetID:             subnetID,
@@ -187,6 +197,7 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 		SharedMemory:         sharedMemoryClient,
 		BCLookup:             bcLookupClient,
 		SNLookup:             snLookupClient,
+		Metrics:              vm.metrics,
 		EpochFirstTransition: epochFirstTransition,
 		EpochDuration:        time.Duration(req.EpochDuration),
 	}
@@ -247,23 +258,8 @@ func (vm *VMServer) CreateStaticHandlers(context.Context, *vmproto.CreateStaticH
 		return nil, err
 	}
 	resp := &vmproto.CreateStaticHandlersResponse{}
-	for prefix, h := range handlers {
-		handler := h
-
-		// start the messenger server
-		serverID := vm.broker.NextId()
-		go vm.broker.AcceptAndServe(serverID, func(opts []grpc.ServerOption) *grpc.Server {
-			server := grpc.NewServer(opts...)
-			vm.serverCloser.Add(server)
-			ghttpproto.RegisterHTTPServer(server, ghttp.NewServer(handler.Handler, vm.broker))
-			return server
-		})
-
-		resp.Handlers = append(resp.Handlers, &vmproto.Handler{
-			Prefix:      prefix,
-			LockOptions: uint32(handler.LockOptions),
-			Server:      serverID,
-		})
+	for prefix, handler := range handlers {
+		resp.Handlers = append(resp.Handlers, vm.serveHandler(prefix, handler))
 	}
 	return resp, nil
 }
@@ -274,27 +270,35 @@ func (vm *VMServer) CreateHandlers(_ context.Context, req *vmproto.CreateHandler
 		return nil, err
 	}
 	resp := &vmproto.CreateHandlersResponse{}
-	for prefix, h := range handlers {
-		handler := h
-
-		// start the messenger server
-		serverID := vm.broker.NextId()
-		go vm.broker.AcceptAndServe(serverID, func(opts []grpc.ServerOption) *grpc.Server {
-			server := grpc.NewServer(opts...)
-			vm.serverCloser.Add(server)
-			ghttpproto.RegisterHTTPServer(server, ghttp.NewServer(handler.Handler, vm.broker))
-			return server
-		})
-
-		resp.Handlers = append(resp.Handlers, &vmproto.Handler{
-			Prefix:      prefix,
-			LockOptions: uint32(handler.LockOptions),
-			Server:      serverID,
-		})
+	for prefix, handler := range handlers {
+		resp.Handlers = append(resp.Handlers, vm.serveHandler(prefix, handler))
+	}
+	if vm.metrics != nil {
+		resp.Handlers = append(resp.Handlers, vm.serveHandler(metricsHandlerPrefix, &common.HTTPHandler{
+			LockOptions: common.NoLock,
+			Handler:     promhttp.HandlerFor(vm.metrics, promhttp.HandlerOpts{}),
+		}))
 	}
 	return resp, nil
 }
 
+// serveHandler serves [handler] to the node and returns how the node reaches
+// it
+func (vm *VMServer) serveHandler(prefix string, handler *common.HTTPHandler) *vmproto.Handler {
+	serverID := vm.broker.NextId()
+	go vm.broker.AcceptAndServe(serverID, func(opts []grpc.ServerOption) *grpc.Server {
+		server := grpc.NewServer(opts...)
+		vm.serverCloser.Add(server)
+		ghttpproto.RegisterHTTPServer(server, ghttp.NewServer(handler.Handler, vm.broker))
+		return server
+	})
+	return &vmproto.Handler{
+		Prefix:      prefix,
+		LockOptions: uint32(handler.LockOptions),
+		Server:      serverID,
+	}
+}
+
 func (vm *VMServer) BuildBlock(_ context.Context, _ *vmproto.BuildBlockRequest) (*vmproto.BuildBlockResponse, error) {
 	blk, err := vm.vm.BuildBlock()
 	if err != nil {
@@ -419,3 +423,98 @@ func (vm *VMServer) BlockReject(_ context.Context, req *vmproto.BlockRejectReque
 	}
 	return &vmproto.BlockRejectResponse{}, nil
 }
+
+func (vm *VMServer) StateSyncEnabled(context.Context, *vmproto.StateSyncEnabledRequest) (*vmproto.StateSyncEnabledResponse, error) {
+	ssVM, ok := vm.vm.(common.StateSyncableVM)
+	if !ok {
+		// A VM that can't sync its state bootstraps as it always did
+		return &vmproto.StateSyncEnabledResponse{}, nil
+	}
+	enabled, err := ssVM.StateSyncEnabled()
+	if err != nil {
+		return nil, err
+	}
+	return &vmproto.StateSyncEnabledResponse{
+		Enabled: enabled,
+	}, nil
+}
+
+func (vm *VMServer) GetLastStateSummary(context.Context, *vmproto.GetLastStateSummaryRequest) (*vmproto.GetLastStateSummaryResponse, error) {
+	ssVM, ok := vm.vm.(common.StateSyncableVM)
+	if !ok {
+		return nil, errStateSyncUnsupported
+	}
+	summary, err := ssVM.GetLastStateSummary()
+	if err != nil {
+		return &vmproto.GetLastStateSummaryResponse{
+			Err: summaryErrorToErrCode[err],
+		}, summaryErrorToRPCError(err)
+	}
+	summaryID := summary.ID()
+	return &vmproto.GetLastStateSummaryResponse{
+		Id:     summaryID[:],
+		Height: summary.Height(),
+		Bytes:  summary.Bytes(),
+	}, nil
+}
+
+func (vm *VMServer) GetStateSummary(_ context.Context, req *vmproto.GetStateSummaryRequest) (*vmproto.GetStateSummaryResponse, error) {
+	ssVM, ok := vm.vm.(common.StateSyncableVM)
+	if !ok {
+		return nil, errStateSyncUnsupported
+	}
+	summary, err := ssVM.GetStateSummary(req.Height)
+	if err != nil {
+		return &vmproto.GetStateSummaryResponse{
+			Err: summaryErrorToErrCode[err],
+		}, summaryErrorToRPCError(err)
+	}
+	summaryID := summary.ID()
+	return &vmproto.GetStateSummaryResponse{
+		Id:     summaryID[:],
+		Height: summary.Height(),
+		Bytes:  summary.Bytes(),
+	}, nil
+}
+
+func (vm *VMServer) ParseStateSummary(_ context.Context, req *vmproto.ParseStateSummaryRequest) (*vmproto.ParseStateSummaryResponse, error) {
+	ssVM, ok := vm.vm.(common.StateSyncableVM)
+	if !ok {
+		return nil, errStateSyncUnsupported
+	}
+	summary, err := ssVM.ParseStateSummary(req.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	summaryID := summary.ID()
+	return &vmproto.ParseStateSummaryResponse{
+		Id:     summaryID[:],
+		Height: summary.Height(),
+	}, nil
+}
+
+func (vm *VMServer) FetchState(_ context.Context, req *vmproto.FetchStateRequest) (*vmproto.FetchStateResponse, error) {
+	ssVM, ok := vm.vm.(common.StateSyncableVM)
+	if !ok {
+		return nil, errStateSyncUnsupported
+	}
+	// Summaries don't cross the process boundary, so the VM parses the
+	// summary that the client holds again
+	summary, err := ssVM.ParseStateSummary(req.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &vmproto.FetchStateResponse{}, ssVM.FetchState(summary)
+}
+
+func (vm *VMServer) CommitState(_ context.Context, req *vmproto.CommitStateRequest) (*vmproto.CommitStateResponse, error) {
+	ssVM, ok := vm.vm.(common.StateSyncableVM)
+	if !ok {
+		return nil, errStateSyncUnsupported
+	}
+	summary, err := ssVM.ParseStateSummary(req.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &vmproto.CommitStateResponse{}, ssVM.CommitState(summary)
+}
@@ -36,6 +36,12 @@ type VMClient interface {
 	BlockVerify(ctx context.Context, in *BlockVerifyRequest, opts ...grpc.CallOption) (*BlockVerifyResponse, error)
 	BlockAccept(ctx context.Context, in *BlockAcceptRequest, opts ...grpc.CallOption) (*BlockAcceptResponse, error)
 	BlockReject(ctx context.Context, in *BlockRejectRequest, opts ...grpc.CallOption) (*BlockRejectResponse, error)
+	StateSyncEnabled(ctx context.Context, in *StateSyncEnabledRequest, opts ...grpc.CallOption) (*StateSyncEnabledResponse, error)
+	GetLastStateSummary(ctx context.Context, in *GetLastStateSummaryRequest, opts ...grpc.CallOption) (*GetLastStateSummaryResponse, error)
+	GetStateSummary(ctx context.Context, in *GetStateSummaryRequest, opts ...grpc.CallOption) (*GetStateSummaryResponse, error)
+	ParseStateSummary(ctx context.Context, in *ParseStateSummaryRequest, opts ...grpc.CallOption) (*ParseStateSummaryResponse, error)
+	FetchState(ctx context.Context, in *FetchStateRequest, opts ...grpc.CallOption) (*FetchStateResponse, error)
+	CommitState(ctx context.Context, in *CommitStateRequest, opts ...grpc.CallOption) (*CommitStateResponse, error)
 }
 
 type vMClient struct {
@@ -172,6 +178,60 @@ func (c *vMClient) BlockReject(ctx context.Context, in *BlockRejectRequest, opts
 	return out, nil
 }
 
+func (c *vMClient) StateSyncEnabled(ctx context.Context, in *StateSyncEnabledRequest, opts ...grpc.CallOption) (*StateSyncEnabledResponse, error) {
+	out := new(StateSyncEnabledResponse)
+	err := c.cc.Invoke(ctx, "/vmproto.VM/StateSyncEnabled", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMClient) GetLastStateSummary(ctx context.Context, in *GetLastStateSummaryRequest, opts ...grpc.CallOption) (*GetLastStateSummaryResponse, error) {
+	out := new(GetLastStateSummaryResponse)
+	err := c.cc.Invoke(ctx, "/vmproto.VM/GetLastStateSummary", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMClient) GetStateSummary(ctx context.Context, in *GetStateSummaryRequest, opts ...grpc.CallOption) (*GetStateSummaryResponse, error) {
+	out := new(GetStateSummaryResponse)
+	err := c.cc.Invoke(ctx, "/vmproto.VM/GetStateSummary", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMClient) ParseStateSummary(ctx context.Context, in *ParseStateSummaryRequest, opts ...grpc.CallOption) (*ParseStateSummaryResponse, error) {
+	out := new(ParseStateSummaryResponse)
+	err := c.cc.Invoke(ctx, "/vmproto.VM/ParseStateSummary", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMClient) FetchState(ctx context.Context, in *FetchStateRequest, opts ...grpc.CallOption) (*FetchStateResponse, error) {
+	out := new(FetchStateResponse)
+	err := c.cc.Invoke(ctx, "/vmproto.VM/FetchState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMClient) CommitState(ctx context.Context, in *CommitStateRequest, opts ...grpc.CallOption) (*CommitStateResponse, error) {
+	out := new(CommitStateResponse)
+	err := c.cc.Invoke(ctx, "/vmproto.VM/CommitState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // VMServer is the server API for VM service.
 // All implementations must embed UnimplementedVMServer
 // for forward compatibility
@@ -190,6 +250,12 @@ type VMServer interface {
 	BlockVerify(context.Context, *BlockVerifyRequest) (*BlockVerifyResponse, error)
 	BlockAccept(context.Context, *BlockAcceptRequest) (*BlockAcceptResponse, error)
 	BlockReject(context.Context, *BlockRejectRequest) (*BlockRejectResponse, error)
+	StateSyncEnabled(context.Context, *StateSyncEnabledRequest) (*StateSyncEnabledResponse, error)
+	GetLastStateSummary(context.Context, *GetLastStateSummaryRequest) (*GetLastStateSummaryResponse, error)
+	GetStateSummary(context.Context, *GetStateSummaryRequest) (*GetStateSummaryResponse, error)
+	ParseStateSummary(context.Context, *ParseStateSummaryRequest) (*ParseStateSummaryResponse, error)
+	FetchState(context.Context, *FetchStateRequest) (*FetchStateResponse, error)
+	CommitState(context.Context, *CommitStateRequest) (*CommitStateResponse, error)
 	mustEmbedUnimplementedVMServer()
 }
 
@@ -239,6 +305,24 @@ func (UnimplementedVMServer) BlockAccept(context.Context, *BlockAcceptRequest) (
 func (UnimplementedVMServer) BlockReject(context.Context, *BlockRejectRequest) (*BlockRejectResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BlockReject not implemented")
 }
+func (UnimplementedVMServer) StateSyncEnabled(context.Context, *StateSyncEnabledRequest) (*StateSyncEnabledResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StateSyncEnabled not implemented")
+}
+func (UnimplementedVMServer) GetLastStateSummary(context.Context, *GetLastStateSummaryRequest) (*GetLastStateSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLastStateSummary not implemented")
+}
+func (UnimplementedVMServer) GetStateSummary(context.Context, *GetStateSummaryRequest) (*GetStateSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStateSummary not implemented")
+}
+func (UnimplementedVMServer) ParseStateSummary(context.Context, *ParseStateSummaryRequest) (*ParseStateSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ParseStateSummary not implemented")
+}
+func (UnimplementedVMServer) FetchState(context.Context, *FetchStateRequest) (*FetchStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchState not implemented")
+}
+func (UnimplementedVMServer) CommitState(context.Context, *CommitStateRequest) (*CommitStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitState not implemented")
+}
 func (UnimplementedVMServer) mustEmbedUnimplementedVMServer() {}
 
 // UnsafeVMServer may be embedded to opt out of forward compatibility for this service.
@@ -504,6 +588,114 @@ func _VM_BlockReject_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VM_StateSyncEnabled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StateSyncEnabledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).StateSyncEnabled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vmproto.VM/StateSyncEnabled",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).StateSyncEnabled(ctx, req.(*StateSyncEnabledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VM_GetLastStateSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLastStateSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).GetLastStateSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vmproto.VM/GetLastStateSummary",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).GetLastStateSummary(ctx, req.(*GetLastStateSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VM_GetStateSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).GetStateSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vmproto.VM/GetStateSummary",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).GetStateSummary(ctx, req.(*GetStateSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VM_ParseStateSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseStateSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).ParseStateSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vmproto.VM/ParseStateSummary",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).ParseStateSummary(ctx, req.(*ParseStateSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VM_FetchState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).FetchState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vmproto.VM/FetchState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).FetchState(ctx, req.(*FetchStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VM_CommitState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).CommitState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vmproto.VM/CommitState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).CommitState(ctx, req.(*CommitStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // VM_ServiceDesc is the grpc.ServiceDesc for VM service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -567,6 +759,30 @@ var VM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BlockReject",
 			Handler:    _VM_BlockReject_Handler,
 		},
+		{
+			MethodName: "StateSyncEnabled",
+			Handler:    _VM_StateSyncEnabled_Handler,
+		},
+		{
+			MethodName: "GetLastStateSummary",
+			Handler:    _VM_GetLastStateSummary_Handler,
+		},
+		{
+			MethodName: "GetStateSummary",
+			Handler:    _VM_GetStateSummary_Handler,
+		},
+		{
+			MethodName: "ParseStateSummary",
+			Handler:    _VM_ParseStateSummary_Handler,
+		},
+		{
+			MethodName: "FetchState",
+			Handler:    _VM_FetchState_Handler,
+		},
+		{
+			MethodName: "CommitState",
+			Handler:    _VM_CommitState_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "vm.proto",
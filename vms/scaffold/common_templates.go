@@ -0,0 +1,249 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scaffold
+
+import (
+	"path"
+)
+
+// commonTemplates returns the templates of the files that VMs of every type
+// have, by their paths
+func commonTemplates(name string) map[string]string {
+	return map[string]string{
+		"go.mod":                      goModTemplate,
+		path.Join(name, "codec.go"):   codecTemplate,
+		path.Join(name, "factory.go"): factoryTemplate,
+		path.Join(name, "mempool.go"): mempoolTemplate,
+		path.Join(name, "service.go"): serviceTemplate,
+		path.Join(name, "tx_test.go"): txTestTemplate,
+	}
+}
+
+const goModTemplate = `module {{.Module}}
+
+go 1.15
+
+require github.com/ava-labs/avalanchego {{.AvalancheGoVersion}}
+{{- if .AvalancheGoDir}}
+
+replace github.com/ava-labs/avalanchego => {{.AvalancheGoDir}}
+{{- end}}
+`
+
+const codecTemplate = `package {{.Name}}
+
+import (
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+)
+
+const (
+	codecVersion = 0
+)
+
+// Codec does serialization and deserialization
+var Codec codec.Manager
+
+func init() {
+	Codec = codec.NewDefaultManager()
+	if err := Codec.RegisterCodec(codecVersion, linearcodec.NewDefault()); err != nil {
+		panic(err)
+	}
+}
+`
+
+const factoryTemplate = `package {{.Name}}
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+)
+
+// ID is a unique identifier for this VM
+var (
+	ID = {{.ID}}
+)
+
+// Factory creates instances of this VM
+type Factory struct{}
+
+// New returns a new instance of this VM
+func (f *Factory) New(*snow.Context) (interface{}, error) { return &VM{}, nil }
+`
+
+const mempoolTemplate = `package {{.Name}}
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	errMempoolFull = errors.New("mempool is full")
+	errDuplicateTx = errors.New("tx is already in the mempool")
+)
+
+// mempool holds the txs that haven't been issued to consensus yet, in the
+// order that they were added
+type mempool struct {
+	maxSize int
+	txs     []*Tx
+	txIDs   ids.Set
+}
+
+func newMempool(maxSize int) *mempool {
+	return &mempool{maxSize: maxSize}
+}
+
+// add [tx] to the mempool
+func (m *mempool) add(tx *Tx) error {
+	if m.txIDs.Contains(tx.ID()) {
+		return errDuplicateTx
+	}
+	if len(m.txs) >= m.maxSize {
+		return errMempoolFull
+	}
+	m.txs = append(m.txs, tx)
+	m.txIDs.Add(tx.ID())
+	return nil
+}
+
+// pop removes the oldest tx from the mempool. Returns nil if the mempool is
+// empty.
+func (m *mempool) pop() *Tx {
+	if len(m.txs) == 0 {
+		return nil
+	}
+	tx := m.txs[0]
+	m.txs = m.txs[1:]
+	m.txIDs.Remove(tx.ID())
+	return tx
+}
+
+// len returns the number of txs in the mempool
+func (m *mempool) len() int { return len(m.txs) }
+`
+
+const serviceTemplate = `package {{.Name}}
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+)
+
+// Service is the API service for this VM
+type Service struct{ vm *VM }
+
+// IssueTxArgs are the arguments to IssueTx
+type IssueTxArgs struct {
+	Key      string              {{tag "json:\"key\""}}
+	Value    string              {{tag "json:\"value\""}}
+	Encoding formatting.Encoding {{tag "json:\"encoding\""}}
+}
+
+// IssueTxReply is the reply from IssueTx
+type IssueTxReply struct {
+	TxID ids.ID {{tag "json:\"txID\""}}
+}
+
+// IssueTx adds a tx that sets the value of a key to the mempool
+func (s *Service) IssueTx(_ *http.Request, args *IssueTxArgs, reply *IssueTxReply) error {
+	s.vm.Ctx.Log.Info("{{.Name}}: IssueTx called")
+
+	key, err := formatting.Decode(args.Encoding, args.Key)
+	if err != nil {
+		return fmt.Errorf("couldn't decode key: %w", err)
+	}
+	value, err := formatting.Decode(args.Encoding, args.Value)
+	if err != nil {
+		return fmt.Errorf("couldn't decode value: %w", err)
+	}
+	tx, err := newTx(key, value)
+	if err != nil {
+		return err
+	}
+	if err := s.vm.issueTx(tx); err != nil {
+		return err
+	}
+	reply.TxID = tx.ID()
+	return nil
+}
+
+// GetValueArgs are the arguments to GetValue
+type GetValueArgs struct {
+	Key      string              {{tag "json:\"key\""}}
+	Encoding formatting.Encoding {{tag "json:\"encoding\""}}
+}
+
+// GetValueReply is the reply from GetValue
+type GetValueReply struct {
+	Value    string              {{tag "json:\"value\""}}
+	Found    bool                {{tag "json:\"found\""}}
+	Encoding formatting.Encoding {{tag "json:\"encoding\""}}
+}
+
+// GetValue returns the value of a key in the accepted state of the chain
+func (s *Service) GetValue(_ *http.Request, args *GetValueArgs, reply *GetValueReply) error {
+	s.vm.Ctx.Log.Info("{{.Name}}: GetValue called")
+
+	key, err := formatting.Decode(args.Encoding, args.Key)
+	if err != nil {
+		return fmt.Errorf("couldn't decode key: %w", err)
+	}
+	reply.Encoding = args.Encoding
+	value, err := s.vm.state.Get(key)
+	switch {
+	case err == database.ErrNotFound:
+		return nil
+	case err != nil:
+		return err
+	}
+	reply.Value, err = formatting.Encode(args.Encoding, value)
+	if err != nil {
+		return fmt.Errorf("couldn't encode value as string: %w", err)
+	}
+	reply.Found = true
+	return nil
+}
+`
+
+const txTestTemplate = `package {{.Name}}
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseTx(t *testing.T) {
+	tx, err := newTx([]byte("key"), []byte("value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedTx, err := parseTx(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsedTx.ID() != tx.ID() {
+		t.Fatalf("expected tx ID %s but got %s", tx.ID(), parsedTx.ID())
+	}
+	if !bytes.Equal(parsedTx.Value, tx.Value) {
+		t.Fatalf("expected value %q but got %q", tx.Value, parsedTx.Value)
+	}
+}
+
+func TestInvalidTx(t *testing.T) {
+	if _, err := newTx(nil, []byte("value")); err == nil {
+		t.Fatal("a tx without a key should be invalid")
+	}
+	if _, err := newTx(make([]byte, maxKeyLen+1), []byte("value")); err == nil {
+		t.Fatal("a tx with a key that is too long should be invalid")
+	}
+}
+`
@@ -0,0 +1,482 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scaffold
+
+import (
+	"path"
+)
+
+// dagTemplates returns the templates of the files of a DAG VM, by their paths
+func dagTemplates(name string) map[string]string {
+	return map[string]string{
+		path.Join(name, "tx.go"):      dagTxTemplate,
+		path.Join(name, "vm.go"):      dagVMTemplate,
+		path.Join(name, "vm_test.go"): dagVMTestTemplate,
+	}
+}
+
+const dagTxTemplate = `package {{.Name}}
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+const (
+	// Maximum lengths of the key and the value of a tx
+	maxKeyLen   = 1024
+	maxValueLen = 64 * 1024
+)
+
+var (
+	errNoKey        = errors.New("tx has no key")
+	errKeyTooLong   = errors.New("key is too long")
+	errValueTooLong = errors.New("value is too long")
+
+	_ snowstorm.Tx = &Tx{}
+)
+
+// Tx sets the value of a key in the state of the chain. Txs that set the same
+// key conflict, so at most one of them is accepted.
+type Tx struct {
+	Key   []byte {{tag "serialize:\"true\""}}
+	Value []byte {{tag "serialize:\"true\""}}
+
+	id     ids.ID
+	bytes  []byte
+	vm     *VM
+	status choices.Status
+}
+
+// newTx returns a tx that sets the value of [key] to [value]
+func newTx(key, value []byte) (*Tx, error) {
+	tx := &Tx{
+		Key:   key,
+		Value: value,
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+	bytes, err := Codec.Marshal(codecVersion, tx)
+	if err != nil {
+		return nil, err
+	}
+	tx.initialize(bytes)
+	return tx, nil
+}
+
+// parseTx parses [bytes] to a tx
+func parseTx(bytes []byte) (*Tx, error) {
+	tx := &Tx{}
+	if _, err := Codec.Unmarshal(bytes, tx); err != nil {
+		return nil, err
+	}
+	tx.initialize(bytes)
+	return tx, nil
+}
+
+func (tx *Tx) initialize(bytes []byte) {
+	tx.bytes = bytes
+	tx.id = hashing.ComputeHash256Array(bytes)
+}
+
+// ID of this tx
+func (tx *Tx) ID() ids.ID { return tx.id }
+
+// Bytes returns the byte representation of this tx
+func (tx *Tx) Bytes() []byte { return tx.bytes }
+
+// SyntacticVerify returns nil iff this tx is well formed. It doesn't access
+// the state of the chain.
+func (tx *Tx) SyntacticVerify() error {
+	switch {
+	case len(tx.Key) == 0:
+		return errNoKey
+	case len(tx.Key) > maxKeyLen:
+		return errKeyTooLong
+	case len(tx.Value) > maxValueLen:
+		return errValueTooLong
+	default:
+		return nil
+	}
+}
+
+// Status of this tx
+func (tx *Tx) Status() choices.Status { return tx.status }
+
+// Dependencies implements the snowstorm.Tx interface. A tx doesn't depend on
+// other txs.
+func (tx *Tx) Dependencies() []snowstorm.Tx { return nil }
+
+// InputIDs implements the snowstorm.Tx interface. The input of a tx is its
+// key.
+func (tx *Tx) InputIDs() []ids.ID {
+	return []ids.ID{hashing.ComputeHash256Array(tx.Key)}
+}
+
+// Verify implements the snowstorm.Tx interface
+func (tx *Tx) Verify() error { return tx.SyntacticVerify() }
+
+// Accept sets the value of the key of this tx in the state of the chain
+func (tx *Tx) Accept() error {
+	if err := tx.vm.state.Put(tx.Key, tx.Value); err != nil {
+		return err
+	}
+	if err := tx.setStatus(choices.Accepted); err != nil {
+		return err
+	}
+	return tx.vm.db.Commit()
+}
+
+// Reject this tx
+func (tx *Tx) Reject() error {
+	if err := tx.setStatus(choices.Rejected); err != nil {
+		return err
+	}
+	return tx.vm.db.Commit()
+}
+
+// setStatus sets and persists the status of this tx
+func (tx *Tx) setStatus(status choices.Status) error {
+	tx.status = status
+	if status.Decided() {
+		delete(tx.vm.processing, tx.id)
+	}
+	return database.PutUInt32(tx.vm.statuses, tx.id[:], uint32(status))
+}
+`
+
+const dagVMTemplate = `package {{.Name}}
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/api/openapi"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+const (
+	// Maximum number of txs in the mempool
+	maxMempoolSize = 4096
+)
+
+var (
+	statePrefix    = []byte("state")
+	txPrefix       = []byte("tx")
+	txStatusPrefix = []byte("status")
+
+	_ vertex.DAGVM    = &VM{}
+	_ common.StaticVM = &VM{}
+)
+
+// VM is a DAG of txs that set the values of keys in a key/value store, the
+// state of the chain.
+//
+// rpcchainvm only serves snowman VMs, so this VM runs in the node. It's
+// registered by adding its Factory, with its ID, to the VM manager of the
+// node.
+type VM struct {
+	Ctx *snow.Context
+
+	db *versiondb.Database
+	// Accepted state of the chain
+	state database.Database
+	// Bytes of the txs that were issued or parsed, by their IDs
+	txs database.Database
+	// Statuses of the txs that were issued or parsed, by their IDs
+	statuses database.Database
+
+	toEngine chan<- common.Message
+	// Txs that haven't been issued to consensus yet
+	mempool *mempool
+	// Txs that aren't decided yet. Consensus gets the same instance of a tx
+	// each time that it's parsed.
+	processing map[ids.ID]*Tx
+}
+
+// Initialize this vm
+// [ctx] is this vm's context
+// [dbManager] is the manager of this vm's database
+// [toEngine] is used to notify the consensus engine that new txs are ready to
+// be added to consensus
+func (vm *VM) Initialize(
+	ctx *snow.Context,
+	dbManager manager.Manager,
+	genesisData []byte,
+	upgradeData []byte,
+	configData []byte,
+	toEngine chan<- common.Message,
+	_ []*common.Fx,
+) error {
+	vm.Ctx = ctx
+	vm.db = versiondb.New(dbManager.Current().Database)
+	vm.state = prefixdb.New(statePrefix, vm.db)
+	vm.txs = prefixdb.New(txPrefix, vm.db)
+	vm.statuses = prefixdb.New(txStatusPrefix, vm.db)
+	vm.toEngine = toEngine
+	vm.mempool = newMempool(maxMempoolSize)
+	vm.processing = make(map[ids.ID]*Tx)
+	return nil
+}
+
+// Bootstrapping implements the common.VM interface
+func (vm *VM) Bootstrapping() error { return nil }
+
+// Bootstrapped implements the common.VM interface
+func (vm *VM) Bootstrapped() error { return nil }
+
+// Shutdown this vm
+func (vm *VM) Shutdown() error {
+	if vm.db == nil {
+		return nil
+	}
+	if err := vm.db.Commit(); err != nil {
+		return err
+	}
+	if err := vm.db.GetDatabase().Close(); err != nil {
+		return err
+	}
+	return vm.db.Close()
+}
+
+// CreateHandlers returns a map where:
+// Keys: The path extension for this VM's API (empty in this case)
+// Values: The handler for the API
+func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
+	server := openapi.NewServer()
+	server.RegisterCodec(json.NewCodec(), "application/json")
+	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
+	if err := server.RegisterService(&Service{vm: vm}, "{{.Name}}"); err != nil {
+		return nil, err
+	}
+	return map[string]*common.HTTPHandler{
+		"": {LockOptions: common.WriteLock, Handler: server},
+	}, nil
+}
+
+// CreateStaticHandlers returns a map where:
+// Keys: The path extension for this VM's static API
+// Values: The handler for that static API
+// We return nil because this VM has no static API
+func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) { return nil, nil }
+
+// HealthCheck implements the common.VM interface
+func (vm *VM) HealthCheck() (interface{}, error) { return nil, nil }
+
+// PendingTxs returns the txs in the mempool and empties it
+func (vm *VM) PendingTxs() []snowstorm.Tx {
+	txs := make([]snowstorm.Tx, 0, vm.mempool.len())
+	for tx := vm.mempool.pop(); tx != nil; tx = vm.mempool.pop() {
+		if err := vm.track(tx); err != nil {
+			vm.Ctx.Log.Error("dropping tx %s: %s", tx.ID(), err)
+			continue
+		}
+		txs = append(txs, vm.processing[tx.ID()])
+	}
+	return txs
+}
+
+// ParseTx parses [bytes] to a snowstorm.Tx
+func (vm *VM) ParseTx(bytes []byte) (snowstorm.Tx, error) {
+	tx, err := parseTx(bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := vm.track(tx); err != nil {
+		return nil, err
+	}
+	if processing, ok := vm.processing[tx.ID()]; ok {
+		return processing, nil
+	}
+	return vm.GetTx(tx.ID())
+}
+
+// GetTx returns the tx whose ID is [txID]
+func (vm *VM) GetTx(txID ids.ID) (snowstorm.Tx, error) {
+	if tx, ok := vm.processing[txID]; ok {
+		return tx, nil
+	}
+	bytes, err := vm.txs.Get(txID[:])
+	if err != nil {
+		return nil, err
+	}
+	tx, err := parseTx(bytes)
+	if err != nil {
+		return nil, err
+	}
+	status, err := database.GetUInt32(vm.statuses, txID[:])
+	if err != nil {
+		return nil, err
+	}
+	tx.vm = vm
+	tx.status = choices.Status(status)
+	return tx, nil
+}
+
+// track persists [tx] as processing if it isn't known yet
+func (vm *VM) track(tx *Tx) error {
+	if _, ok := vm.processing[tx.ID()]; ok {
+		return nil
+	}
+	if has, err := vm.txs.Has(tx.id[:]); err != nil || has {
+		return err
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		return fmt.Errorf("tx %s is invalid: %w", tx.ID(), err)
+	}
+	tx.vm = vm
+	if err := vm.txs.Put(tx.id[:], tx.bytes); err != nil {
+		return err
+	}
+	if err := tx.setStatus(choices.Processing); err != nil {
+		return err
+	}
+	vm.processing[tx.ID()] = tx
+	return vm.db.Commit()
+}
+
+// issueTx adds [tx] to the mempool and notifies the consensus engine that
+// there are txs to issue
+func (vm *VM) issueTx(tx *Tx) error {
+	if err := vm.mempool.add(tx); err != nil {
+		return err
+	}
+	select {
+	case vm.toEngine <- common.PendingTxs:
+	default:
+		vm.Ctx.Log.Debug("dropping message to consensus engine")
+	}
+	return nil
+}
+
+// Connected implements the validators.Connector interface
+func (vm *VM) Connected(id ids.ShortID) error {
+	return nil // noop
+}
+
+// Disconnected implements the validators.Connector interface
+func (vm *VM) Disconnected(id ids.ShortID) error {
+	return nil // noop
+}
+`
+
+const dagVMTestTemplate = `package {{.Name}}
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+func newTestVM(t *testing.T) (*VM, chan common.Message) {
+	msgChan := make(chan common.Message, 1)
+	vm := &VM{}
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.ID{1, 2, 3}
+	if err := vm.Initialize(ctx, manager.NewDefaultMemDBManager(), nil, nil, nil, msgChan, nil); err != nil {
+		t.Fatal(err)
+	}
+	return vm, msgChan
+}
+
+func TestIssueTx(t *testing.T) {
+	vm, msgChan := newTestVM(t)
+
+	tx, err := newTx([]byte("key"), []byte("value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.issueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	if msg := <-msgChan; msg != common.PendingTxs {
+		t.Fatalf("expected %s but got %s", common.PendingTxs, msg)
+	}
+
+	pending := vm.PendingTxs()
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending tx but got %d", len(pending))
+	}
+	if len(vm.PendingTxs()) != 0 {
+		t.Fatal("the mempool should be empty")
+	}
+	issued := pending[0]
+	if status := issued.Status(); status != choices.Processing {
+		t.Fatalf("tx should be processing but is %s", status)
+	}
+
+	// Parse the tx, like a tx that is received from a peer
+	parsed, err := vm.ParseTx(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != issued {
+		t.Fatal("parsing a processing tx should return the same tx")
+	}
+
+	if err := issued.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := issued.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	value, err := vm.state.Get(tx.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, tx.Value) {
+		t.Fatalf("expected value %q but got %q", tx.Value, value)
+	}
+
+	accepted, err := vm.GetTx(tx.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := accepted.Status(); status != choices.Accepted {
+		t.Fatalf("tx should be accepted but is %s", status)
+	}
+}
+
+func TestConflictingTxs(t *testing.T) {
+	tx1, err := newTx([]byte("key"), []byte("value1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx2, err := newTx([]byte("key"), []byte("value2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx3, err := newTx([]byte("other key"), []byte("value1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tx1.InputIDs()[0] != tx2.InputIDs()[0] {
+		t.Fatal("txs that set the same key should conflict")
+	}
+	if tx1.InputIDs()[0] == tx3.InputIDs()[0] {
+		t.Fatal("txs that set different keys shouldn't conflict")
+	}
+}
+`
@@ -0,0 +1,200 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+// VMType is the kind of consensus that a generated VM runs on
+type VMType string
+
+const (
+	// SnowmanVM is a VM whose chain is a linear chain of blocks. It's served
+	// as a plugin over rpcchainvm.
+	SnowmanVM VMType = "snowman"
+	// DAGVM is a VM whose txs are put into vertices of a DAG. rpcchainvm only
+	// serves snowman VMs, so a DAGVM is registered in the node instead.
+	DAGVM VMType = "dag"
+)
+
+// The name of a VM is also the name of its package and is put into its ID,
+// which is 32 bytes long
+var nameRegex = regexp.MustCompile(`^[a-z][a-z0-9]{0,31}$`)
+
+var (
+	errInvalidName   = errors.New("name must be a lowercase letter followed by at most 31 lowercase letters or digits")
+	errUnknownVMType = errors.New("unknown VM type")
+	errNoModule      = errors.New("module path must be set")
+	errNoDir         = errors.New("directory must be set")
+	errDirNotEmpty   = errors.New("directory isn't empty")
+)
+
+// Config of a generated VM
+type Config struct {
+	// Name of the VM. It's the name of the package of the VM.
+	Name string
+	// Type of the VM
+	Type VMType
+	// Path of the Go module of the VM
+	Module string
+	// Directory that the module is generated in. It's created if it doesn't
+	// exist, and must be empty if it does.
+	Dir string
+	// Version of AvalancheGo that the module requires
+	AvalancheGoVersion string
+	// If set, the module uses the AvalancheGo source in this directory
+	// instead of the required version
+	AvalancheGoDir string
+}
+
+// Verify returns an error if the config is invalid
+func (c *Config) Verify() error {
+	switch {
+	case !nameRegex.MatchString(c.Name):
+		return fmt.Errorf("%w: %q", errInvalidName, c.Name)
+	case c.Type != SnowmanVM && c.Type != DAGVM:
+		return fmt.Errorf("%w: %q", errUnknownVMType, c.Type)
+	case c.Module == "":
+		return errNoModule
+	case c.Dir == "":
+		return errNoDir
+	default:
+		return nil
+	}
+}
+
+// VMID returns the ID of the VM, which is its name padded with zeros. The
+// plugin binary of a snowman VM is named by this ID.
+func (c *Config) VMID() ids.ID {
+	vmID := ids.ID{}
+	copy(vmID[:], c.Name)
+	return vmID
+}
+
+// ParseVMType returns the VMType whose name is [s]
+func ParseVMType(s string) (VMType, error) {
+	switch vmType := VMType(strings.ToLower(s)); vmType {
+	case SnowmanVM, DAGVM:
+		return vmType, nil
+	default:
+		return "", fmt.Errorf("%w: %q", errUnknownVMType, s)
+	}
+}
+
+// CurrentAvalancheGoVersion is the module version of this AvalancheGo
+func CurrentAvalancheGoVersion() string {
+	return fmt.Sprintf("v%d.%d.%d",
+		version.Current.Major(),
+		version.Current.Minor(),
+		version.Current.Patch(),
+	)
+}
+
+// templateData is what the templates of the files of a VM are executed with
+type templateData struct {
+	Config
+	// Go literal of the ID of the VM
+	ID string
+}
+
+// Generate writes the module of the VM described by [config] to
+// [config.Dir]. Returns the paths of the written files, relative to
+// [config.Dir].
+func Generate(config Config) ([]string, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	files, err := render(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries, err := ioutil.ReadDir(config.Dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%w: %s", errDirNotEmpty, config.Dir)
+	}
+	paths := make([]string, 0, len(files))
+	for path, contents := range files {
+		fullPath := filepath.Join(config.Dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), perms.ReadWriteExecute); err != nil {
+			return nil, fmt.Errorf("couldn't create directory of %s: %w", path, err)
+		}
+		if err := perms.WriteFile(fullPath, contents, perms.ReadWrite); err != nil {
+			return nil, fmt.Errorf("couldn't write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// render returns the contents of the files of the VM described by [config],
+// by their paths relative to the root of its module
+func render(config Config) (map[string][]byte, error) {
+	data := templateData{
+		Config: config,
+		ID:     idLiteral(config.Name),
+	}
+	templates := commonTemplates(config.Name)
+	switch config.Type {
+	case SnowmanVM:
+		for path, text := range snowmanTemplates(config.Name) {
+			templates[path] = text
+		}
+	case DAGVM:
+		for path, text := range dagTemplates(config.Name) {
+			templates[path] = text
+		}
+	}
+
+	files := make(map[string][]byte, len(templates))
+	for path, text := range templates {
+		tmpl, err := template.New(path).Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse template of %s: %w", path, err)
+		}
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, data); err != nil {
+			return nil, fmt.Errorf("couldn't execute template of %s: %w", path, err)
+		}
+		contents := []byte(sb.String())
+		if strings.HasSuffix(path, ".go") {
+			contents, err = format.Source(contents)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't format %s: %w", path, err)
+			}
+		}
+		files[path] = contents
+	}
+	return files, nil
+}
+
+// templateFuncs are the functions that the templates may call
+var templateFuncs = template.FuncMap{
+	// tag returns a struct tag. The templates are raw strings, so they can't
+	// contain backquotes.
+	"tag": func(s string) string { return "`" + s + "`" },
+}
+
+// idLiteral returns the Go literal of an ids.ID that contains [name]
+func idLiteral(name string) string {
+	chars := make([]string, len(name))
+	for i, c := range name {
+		chars[i] = fmt.Sprintf("'%c'", c)
+	}
+	return fmt.Sprintf("ids.ID{%s}", strings.Join(chars, ", "))
+}
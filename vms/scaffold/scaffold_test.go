@@ -0,0 +1,116 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scaffold
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig(t *testing.T, vmType VMType) Config {
+	return Config{
+		Name:               "kvvm",
+		Type:               vmType,
+		Module:             "example.com/kvvm",
+		Dir:                t.TempDir(),
+		AvalancheGoVersion: CurrentAvalancheGoVersion(),
+	}
+}
+
+func TestGenerateSnowmanVM(t *testing.T) {
+	config := testConfig(t, SnowmanVM)
+
+	paths, err := Generate(config)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"go.mod",
+		"kvvm/block.go",
+		"kvvm/codec.go",
+		"kvvm/factory.go",
+		"kvvm/mempool.go",
+		"kvvm/service.go",
+		"kvvm/tx.go",
+		"kvvm/tx_test.go",
+		"kvvm/vm.go",
+		"kvvm/vm_test.go",
+		"main/main.go",
+	}, paths)
+
+	mainFile, err := ioutil.ReadFile(filepath.Join(config.Dir, "main", "main.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(mainFile), `"example.com/kvvm/kvvm"`)
+	assert.Contains(t, string(mainFile), "rpcchainvm.Serve(&kvvm.VM{})")
+
+	factoryFile, err := ioutil.ReadFile(filepath.Join(config.Dir, "kvvm", "factory.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(factoryFile), "ID = ids.ID{'k', 'v', 'v', 'm'}")
+}
+
+func TestGenerateDAGVM(t *testing.T) {
+	config := testConfig(t, DAGVM)
+
+	paths, err := Generate(config)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"go.mod",
+		"kvvm/codec.go",
+		"kvvm/factory.go",
+		"kvvm/mempool.go",
+		"kvvm/service.go",
+		"kvvm/tx.go",
+		"kvvm/tx_test.go",
+		"kvvm/vm.go",
+		"kvvm/vm_test.go",
+	}, paths)
+}
+
+func TestGenerateGoMod(t *testing.T) {
+	config := testConfig(t, SnowmanVM)
+	config.AvalancheGoDir = "../avalanchego"
+
+	files, err := render(config)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"module example.com/kvvm\n\n"+
+			"go 1.15\n\n"+
+			"require github.com/ava-labs/avalanchego "+CurrentAvalancheGoVersion()+"\n\n"+
+			"replace github.com/ava-labs/avalanchego => ../avalanchego\n",
+		string(files["go.mod"]),
+	)
+
+	config.AvalancheGoDir = ""
+	files, err = render(config)
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(string(files["go.mod"]), "replace"))
+}
+
+func TestGenerateIntoNonEmptyDir(t *testing.T) {
+	config := testConfig(t, SnowmanVM)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(config.Dir, "file"), nil, 0o600))
+
+	_, err := Generate(config)
+	assert.True(t, errors.Is(err, errDirNotEmpty))
+}
+
+func TestConfigVerify(t *testing.T) {
+	for _, name := range []string{"", "KVVM", "1vm", "kv-vm", strings.Repeat("a", 33)} {
+		config := testConfig(t, SnowmanVM)
+		config.Name = name
+		assert.True(t, errors.Is(config.Verify(), errInvalidName), "name %q", name)
+	}
+
+	config := testConfig(t, "linear")
+	assert.True(t, errors.Is(config.Verify(), errUnknownVMType))
+
+	vmType, err := ParseVMType("DAG")
+	assert.NoError(t, err)
+	assert.Equal(t, DAGVM, vmType)
+	_, err = ParseVMType("linear")
+	assert.True(t, errors.Is(err, errUnknownVMType))
+}
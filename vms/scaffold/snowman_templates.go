@@ -0,0 +1,477 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scaffold
+
+import (
+	"path"
+)
+
+// snowmanTemplates returns the templates of the files of a snowman VM, by
+// their paths
+func snowmanTemplates(name string) map[string]string {
+	return map[string]string{
+		path.Join("main", "main.go"):  snowmanMainTemplate,
+		path.Join(name, "tx.go"):      snowmanTxTemplate,
+		path.Join(name, "block.go"):   snowmanBlockTemplate,
+		path.Join(name, "vm.go"):      snowmanVMTemplate,
+		path.Join(name, "vm_test.go"): snowmanVMTestTemplate,
+	}
+}
+
+const snowmanMainTemplate = `package main
+
+import (
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm"
+
+	"{{.Module}}/{{.Name}}"
+)
+
+// main serves the VM as a plugin. The node runs the plugin when it's put in
+// the plugins directory of the node, named after the ID of the VM.
+func main() {
+	rpcchainvm.Serve(&{{.Name}}.VM{})
+}
+`
+
+const snowmanTxTemplate = `package {{.Name}}
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+const (
+	// Maximum lengths of the key and the value of a tx
+	maxKeyLen   = 1024
+	maxValueLen = 64 * 1024
+)
+
+var (
+	errNoKey        = errors.New("tx has no key")
+	errKeyTooLong   = errors.New("key is too long")
+	errValueTooLong = errors.New("value is too long")
+)
+
+// Tx sets the value of a key in the state of the chain
+type Tx struct {
+	Key   []byte {{tag "serialize:\"true\""}}
+	Value []byte {{tag "serialize:\"true\""}}
+
+	id    ids.ID
+	bytes []byte
+}
+
+// newTx returns a tx that sets the value of [key] to [value]
+func newTx(key, value []byte) (*Tx, error) {
+	tx := &Tx{
+		Key:   key,
+		Value: value,
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+	bytes, err := Codec.Marshal(codecVersion, tx)
+	if err != nil {
+		return nil, err
+	}
+	tx.initialize(bytes)
+	return tx, nil
+}
+
+// parseTx parses [bytes] to a tx
+func parseTx(bytes []byte) (*Tx, error) {
+	tx := &Tx{}
+	if _, err := Codec.Unmarshal(bytes, tx); err != nil {
+		return nil, err
+	}
+	tx.initialize(bytes)
+	return tx, nil
+}
+
+func (tx *Tx) initialize(bytes []byte) {
+	tx.bytes = bytes
+	tx.id = hashing.ComputeHash256Array(bytes)
+}
+
+// ID of this tx
+func (tx *Tx) ID() ids.ID { return tx.id }
+
+// Bytes returns the byte representation of this tx
+func (tx *Tx) Bytes() []byte { return tx.bytes }
+
+// SyntacticVerify returns nil iff this tx is well formed. It doesn't access
+// the state of the chain.
+func (tx *Tx) SyntacticVerify() error {
+	switch {
+	case len(tx.Key) == 0:
+		return errNoKey
+	case len(tx.Key) > maxKeyLen:
+		return errKeyTooLong
+	case len(tx.Value) > maxValueLen:
+		return errValueTooLong
+	default:
+		return nil
+	}
+}
+`
+
+const snowmanBlockTemplate = `package {{.Name}}
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/vms/components/core"
+)
+
+var (
+	errNoTxs        = errors.New("block has no txs")
+	errTooManyTxs   = errors.New("block has too many txs")
+	errDatabaseSave = errors.New("error while saving block to the database")
+)
+
+// Block is a block on the chain. Each block contains txs that set the values
+// of keys in the state of the chain.
+type Block struct {
+	*core.Block {{tag "serialize:\"true\""}}
+	Txs         [][]byte {{tag "serialize:\"true\""}}
+
+	vm *VM
+}
+
+// Verify returns nil iff this block is valid
+func (b *Block) Verify() error {
+	if accepted, err := b.Block.Verify(); err != nil || accepted {
+		return err
+	}
+
+	switch {
+	case len(b.Txs) == 0:
+		return errNoTxs
+	case len(b.Txs) > maxBlockTxs:
+		return errTooManyTxs
+	}
+	for i, txBytes := range b.Txs {
+		tx, err := parseTx(txBytes)
+		if err != nil {
+			return fmt.Errorf("couldn't parse tx %d: %w", i, err)
+		}
+		if err := tx.SyntacticVerify(); err != nil {
+			return fmt.Errorf("tx %d is invalid: %w", i, err)
+		}
+	}
+
+	// Persist the block
+	if err := b.vm.SaveBlock(b.vm.DB, b); err != nil {
+		return errDatabaseSave
+	}
+	return b.vm.DB.Commit()
+}
+
+// Accept sets the values of the keys of the txs of this block in the state of
+// the chain
+func (b *Block) Accept() error {
+	for _, txBytes := range b.Txs {
+		tx, err := parseTx(txBytes)
+		if err != nil {
+			return err
+		}
+		if err := b.vm.state.Put(tx.Key, tx.Value); err != nil {
+			return err
+		}
+	}
+	if err := b.Block.Accept(); err != nil {
+		return err
+	}
+	return b.vm.DB.Commit()
+}
+`
+
+const snowmanVMTemplate = `package {{.Name}}
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/vms/components/core"
+)
+
+const (
+	// Maximum number of txs in a block
+	maxBlockTxs = 256
+	// Maximum number of txs in the mempool
+	maxMempoolSize = 4096
+)
+
+var (
+	statePrefix = []byte("state")
+
+	errNoPendingTxs = errors.New("there are no txs to put in a block")
+
+	_ block.ChainVM   = &VM{}
+	_ common.StaticVM = &VM{}
+)
+
+// VM is a chain of blocks whose txs set the values of keys in a key/value
+// store, the state of the chain
+type VM struct {
+	core.SnowmanVM
+
+	// Accepted state of the chain
+	state database.Database
+	// Txs that haven't been put into a block yet
+	mempool *mempool
+}
+
+// Initialize this vm
+// [ctx] is this vm's context
+// [dbManager] is the manager of this vm's database
+// [toEngine] is used to notify the consensus engine that new blocks are
+// ready to be added to consensus
+func (vm *VM) Initialize(
+	ctx *snow.Context,
+	dbManager manager.Manager,
+	genesisData []byte,
+	upgradeData []byte,
+	configData []byte,
+	toEngine chan<- common.Message,
+	_ []*common.Fx,
+) error {
+	if err := vm.SnowmanVM.Initialize(ctx, dbManager.Current().Database, vm.ParseBlock, toEngine); err != nil {
+		return fmt.Errorf("couldn't initialize SnowmanVM: %w", err)
+	}
+	vm.state = prefixdb.New(statePrefix, vm.DB)
+	vm.mempool = newMempool(maxMempoolSize)
+
+	// If database is empty, create it using the provided genesis data
+	if vm.DBInitialized() {
+		return nil
+	}
+
+	// The genesis block has no parent and no txs
+	genesisBlock, err := vm.newBlock(ids.Empty, 0, nil)
+	if err != nil {
+		return fmt.Errorf("couldn't create genesis block: %w", err)
+	}
+	if err := vm.SaveBlock(vm.DB, genesisBlock); err != nil {
+		return fmt.Errorf("couldn't save genesis block: %w", err)
+	}
+	// Sets [vm.LastAcceptedID]
+	if err := genesisBlock.Block.Accept(); err != nil {
+		return fmt.Errorf("couldn't accept genesis block: %w", err)
+	}
+	if err := vm.SetDBInitialized(); err != nil {
+		return fmt.Errorf("couldn't set db to initialized: %w", err)
+	}
+	return vm.DB.Commit()
+}
+
+// CreateHandlers returns a map where:
+// Keys: The path extension for this VM's API (empty in this case)
+// Values: The handler for the API
+func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
+	handler, err := vm.NewHandler("{{.Name}}", &Service{vm: vm})
+	return map[string]*common.HTTPHandler{
+		"": handler,
+	}, err
+}
+
+// CreateStaticHandlers returns a map where:
+// Keys: The path extension for this VM's static API
+// Values: The handler for that static API
+// We return nil because this VM has no static API
+func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) { return nil, nil }
+
+// HealthCheck implements the common.VM interface
+func (vm *VM) HealthCheck() (interface{}, error) { return nil, nil }
+
+// BuildBlock returns a block of the txs in the mempool on top of the
+// preferred block
+func (vm *VM) BuildBlock() (snowman.Block, error) {
+	var txs [][]byte
+	for len(txs) < maxBlockTxs && vm.mempool.len() > 0 {
+		txs = append(txs, vm.mempool.pop().Bytes())
+	}
+	if len(txs) == 0 {
+		return nil, errNoPendingTxs
+	}
+	// Notify consensus engine that there are more pending txs for blocks
+	if vm.mempool.len() > 0 {
+		defer vm.NotifyBlockReady()
+	}
+
+	preferred, err := vm.GetBlock(vm.Preferred())
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get preferred block: %w", err)
+	}
+	return vm.newBlock(preferred.ID(), preferred.Height()+1, txs)
+}
+
+// issueTx adds [tx] to the mempool and notifies the consensus engine that a
+// block can be built
+func (vm *VM) issueTx(tx *Tx) error {
+	if err := vm.mempool.add(tx); err != nil {
+		return err
+	}
+	vm.NotifyBlockReady()
+	return nil
+}
+
+// ParseBlock parses [bytes] to a snowman.Block
+// This function is used by the vm's state to unmarshal blocks saved in state
+func (vm *VM) ParseBlock(bytes []byte) (snowman.Block, error) {
+	block := &Block{}
+	if _, err := Codec.Unmarshal(bytes, block); err != nil {
+		return nil, err
+	}
+	block.Initialize(bytes, &vm.SnowmanVM)
+	block.vm = vm
+	return block, nil
+}
+
+// newBlock returns a new Block with the parent [parentID] and the txs [txs]
+func (vm *VM) newBlock(parentID ids.ID, height uint64, txs [][]byte) (*Block, error) {
+	block := &Block{
+		Block: core.NewBlock(parentID, height),
+		Txs:   txs,
+		vm:    vm,
+	}
+	blockBytes, err := Codec.Marshal(codecVersion, block)
+	if err != nil {
+		return nil, err
+	}
+	block.Initialize(blockBytes, &vm.SnowmanVM)
+	return block, nil
+}
+
+// Connected implements the validators.Connector interface
+func (vm *VM) Connected(id ids.ShortID) error {
+	return nil // noop
+}
+
+// Disconnected implements the validators.Connector interface
+func (vm *VM) Disconnected(id ids.ShortID) error {
+	return nil // noop
+}
+`
+
+const snowmanVMTestTemplate = `package {{.Name}}
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+func newTestVM(t *testing.T) (*VM, chan common.Message) {
+	msgChan := make(chan common.Message, 1)
+	vm := &VM{}
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.ID{1, 2, 3}
+	if err := vm.Initialize(ctx, manager.NewDefaultMemDBManager(), nil, nil, nil, msgChan, nil); err != nil {
+		t.Fatal(err)
+	}
+	lastAcceptedID, err := vm.LastAccepted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.SetPreference(lastAcceptedID); err != nil {
+		t.Fatal(err)
+	}
+	return vm, msgChan
+}
+
+func TestGenesis(t *testing.T) {
+	vm, _ := newTestVM(t)
+
+	if !vm.DBInitialized() {
+		t.Fatal("db should be initialized")
+	}
+	genesisBlock, err := vm.GetBlock(vm.LastAcceptedID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := genesisBlock.Status(); status != choices.Accepted {
+		t.Fatalf("genesis block should be accepted but is %s", status)
+	}
+}
+
+func TestBuildBlock(t *testing.T) {
+	vm, msgChan := newTestVM(t)
+
+	tx, err := newTx([]byte("key"), []byte("value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.issueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	if msg := <-msgChan; msg != common.PendingTxs {
+		t.Fatalf("expected %s but got %s", common.PendingTxs, msg)
+	}
+	if err := vm.issueTx(tx); err != errDuplicateTx {
+		t.Fatalf("expected %s but got %v", errDuplicateTx, err)
+	}
+
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.BuildBlock(); err != errNoPendingTxs {
+		t.Fatalf("expected %s but got %v", errNoPendingTxs, err)
+	}
+
+	// Parse the block, like a block that is received from a peer
+	blk, err = vm.ParseBlock(blk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.state.Get(tx.Key); err == nil {
+		t.Fatal("the state shouldn't change until the block is accepted")
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	value, err := vm.state.Get(tx.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, tx.Value) {
+		t.Fatalf("expected value %q but got %q", tx.Value, value)
+	}
+	if vm.LastAcceptedID != blk.ID() {
+		t.Fatal("the block should be the last accepted block")
+	}
+}
+
+func TestVerifyBlockWithoutTxs(t *testing.T) {
+	vm, _ := newTestVM(t)
+
+	blk, err := vm.newBlock(vm.LastAcceptedID, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != errNoTxs {
+		t.Fatalf("expected %s but got %v", errNoTxs, err)
+	}
+}
+`
@@ -23,6 +23,13 @@ type Credential struct {
 	Sigs [][crypto.SECP256K1RSigLen]byte `serialize:"true" json:"signatures"`
 }
 
+// signedCredential is a Credential or a credential that embeds one
+type signedCredential interface {
+	signatures() [][crypto.SECP256K1RSigLen]byte
+}
+
+func (cr *Credential) signatures() [][crypto.SECP256K1RSigLen]byte { return cr.Sigs }
+
 // MarshalJSON marshals [cr] to JSON
 // The string representation of each signature is created using the hex formatter
 func (cr *Credential) MarshalJSON() ([]byte, error) {
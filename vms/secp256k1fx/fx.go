@@ -212,6 +212,29 @@ func (fx *Fx) VerifyCredentials(tx Tx, in *Input, cred *Credential, out *OutputO
 	return nil
 }
 
+// RecoverSigners recovers the public keys that signed [txIntf] in [credIntf]
+// and caches them, so that verifying the credential doesn't recover them
+// again. [credIntf] may be a Credential or a credential that embeds one. It's
+// safe to call concurrently.
+func (fx *Fx) RecoverSigners(txIntf, credIntf interface{}) error {
+	tx, ok := txIntf.(Tx)
+	if !ok {
+		return errWrongTxType
+	}
+	cred, ok := credIntf.(signedCredential)
+	if !ok {
+		return errWrongCredentialType
+	}
+
+	txHash := hashing.ComputeHash256(tx.UnsignedBytes())
+	for _, sig := range cred.signatures() {
+		if _, err := fx.SECPFactory.RecoverHashPublicKey(txHash, sig[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateOutput creates a new output with the provided control group worth
 // the specified amount
 func (fx *Fx) CreateOutput(amount uint64, ownerIntf interface{}) (interface{}, error) {
@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
@@ -114,6 +115,38 @@ func TestFxVerifyTransfer(t *testing.T) {
 	}
 }
 
+func TestFxRecoverSigners(t *testing.T) {
+	vm := TestVM{
+		Codec: linearcodec.NewDefault(),
+		Log:   logging.NoLog{},
+	}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &TestTx{Bytes: txBytes}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+			sig2Bytes,
+		},
+	}
+
+	if err := fx.RecoverSigners(tx, cred); err != nil {
+		t.Fatal(err)
+	}
+	txHash := hashing.ComputeHash256(txBytes)
+	for _, sig := range cred.Sigs {
+		key := hashing.ComputeHash256Array(append(txHash, sig[:]...))
+		if _, ok := fx.SECPFactory.Cache.Get(key); !ok {
+			t.Fatalf("public key should have been cached")
+		}
+	}
+	if err := fx.RecoverSigners(tx, &Input{}); err == nil {
+		t.Fatalf("Should have failed on the wrong credential type")
+	}
+}
+
 func TestFxVerifyTransferNilTx(t *testing.T) {
 	vm := TestVM{
 		Codec: linearcodec.NewDefault(),
@@ -10,6 +10,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
 )
 
@@ -75,9 +76,9 @@ func (s *Service) GetBlock(_ *http.Request, args *GetBlockArgs, reply *GetBlockR
 			return fmt.Errorf("problem finding the last accepted ID: %s", err)
 		}
 	} else {
-		id, err = ids.FromString(args.ID)
+		id, err = address.ParseID(args.ID)
 		if err != nil {
-			return errors.New("problem parsing ID")
+			return err
 		}
 	}
 
@@ -0,0 +1,116 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasmvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/vms/components/core"
+)
+
+var (
+	errNoTxs         = errors.New("block has no txs")
+	errBlockTooLarge = errors.New("block is too large")
+	errDatabaseSave  = errors.New("error while saving block to the database")
+	errNotVerified   = errors.New("block hasn't been verified")
+	errUnknownParent = errors.New("state of the parent block isn't known")
+	errDecidedParent = errors.New("parent block is decided but isn't the last accepted block")
+)
+
+// Block is a block on the chain. Each block contains txs that are executed by
+// the chain code.
+type Block struct {
+	*core.Block `serialize:"true"`
+	Txs         [][]byte `serialize:"true"`
+
+	vm *VM
+	// The state after the txs of this block are executed. Its changes are
+	// written to the state of the parent block when this block is accepted.
+	// Only set once the block is verified.
+	state *versiondb.Database
+}
+
+// Verify returns nil iff all the txs of this block are valid when executed,
+// in order, against the state of its parent
+func (b *Block) Verify() error {
+	if accepted, err := b.Block.Verify(); err != nil || accepted {
+		return err
+	}
+	if _, ok := b.vm.verifiedBlocks[b.ID()]; ok {
+		return nil
+	}
+
+	if len(b.Txs) == 0 {
+		return errNoTxs
+	}
+	size := 0
+	for _, tx := range b.Txs {
+		size += len(tx)
+	}
+	if size > maxBlockTxsSize {
+		return errBlockTooLarge
+	}
+
+	parentState, err := b.vm.stateAfter(b.ParentID())
+	if err != nil {
+		return err
+	}
+	state := versiondb.New(parentState)
+	for i, tx := range b.Txs {
+		if err := b.vm.runtime.executeTx(state, tx); err != nil {
+			return fmt.Errorf("tx %d is invalid: %w", i, err)
+		}
+	}
+
+	// Persist the block
+	if err := b.vm.SaveBlock(b.vm.DB, b); err != nil {
+		return errDatabaseSave
+	}
+	if err := b.vm.DB.Commit(); err != nil {
+		return err
+	}
+	b.state = state
+	b.vm.verifiedBlocks[b.ID()] = b
+	return nil
+}
+
+// Accept writes the changes of the state of this block to the state of the
+// chain
+func (b *Block) Accept() error {
+	verified, ok := b.vm.verifiedBlocks[b.ID()]
+	if !ok {
+		return errNotVerified
+	}
+	delete(b.vm.verifiedBlocks, b.ID())
+
+	// The parent of this block must be the last accepted block, so the state
+	// of this block is on top of the state of the chain.
+	if err := verified.state.Commit(); err != nil {
+		return err
+	}
+	// The children of this block are now on top of the state of the chain
+	for _, child := range b.vm.verifiedBlocks {
+		if child.ParentID() == b.ID() {
+			if err := child.state.SetDatabase(b.vm.state); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := b.Block.Accept(); err != nil {
+		return err
+	}
+	return b.vm.DB.Commit()
+}
+
+// Reject discards the changes of the state of this block
+func (b *Block) Reject() error {
+	delete(b.vm.verifiedBlocks, b.ID())
+	if err := b.Block.Reject(); err != nil {
+		return err
+	}
+	return b.vm.DB.Commit()
+}
@@ -0,0 +1,38 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasmvm
+
+import (
+	"math"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/codec/reflectcodec"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	codecVersion = 0
+)
+
+// Codecs do serialization and deserialization. The genesis, which contains
+// the chain code, may be larger than the blocks.
+var (
+	Codec        codec.Manager
+	GenesisCodec codec.Manager
+)
+
+func init() {
+	Codec = codec.NewDefaultManager()
+	GenesisCodec = codec.NewManager(math.MaxUint32)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		Codec.RegisterCodec(codecVersion, linearcodec.NewDefault()),
+		GenesisCodec.RegisterCodec(codecVersion, linearcodec.New(reflectcodec.DefaultTagName, math.MaxUint32)),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
+	}
+}
@@ -0,0 +1,20 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasmvm
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+)
+
+// ID is a unique identifier for this VM
+var (
+	ID = ids.ID{'w', 'a', 's', 'm', 'v', 'm'}
+)
+
+// Factory ...
+type Factory struct{}
+
+// New ...
+func (f *Factory) New(*snow.Context) (interface{}, error) { return &VM{}, nil }
@@ -0,0 +1,13 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasmvm
+
+// Genesis of a chain
+type Genesis struct {
+	// Chain code of the chain, a WASM module that implements the ABI
+	Code []byte `serialize:"true"`
+	// Data that the genesis function of the chain code initializes the state
+	// from
+	Data []byte `serialize:"true"`
+}
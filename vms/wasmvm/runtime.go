@@ -0,0 +1,294 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasmvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/wasmvm/wasm"
+)
+
+// The ABI between the VM and the chain code.
+//
+// The chain code is a WASM module that exports its memory as "memory" and the
+// functions:
+//
+//	alloc(size i32) -> i32
+//	  Returns a pointer to [size] bytes of memory that the VM writes the input
+//	  of the next call to.
+//	verify_tx(ptr i32, len i32) -> i32
+//	  Parses and verifies the tx at [ptr] without accessing the state. Returns
+//	  0 if the tx is well formed. Called before a tx is added to the mempool.
+//	execute_tx(ptr i32, len i32) -> i32
+//	  Executes the tx at [ptr] against the state. Returns 0 if the tx is
+//	  valid. If the tx is invalid, its changes to the state are discarded.
+//
+// It may export:
+//
+//	genesis(ptr i32, len i32) -> i32
+//	  Initializes the state from the genesis data at [ptr]. Returns 0 on
+//	  success.
+//
+// The VM provides the host functions in the module "env":
+//
+//	state_get(key_ptr i32, key_len i32, value_ptr i32, value_cap i32) -> i32
+//	  Copies at most [value_cap] bytes of the value of the key to
+//	  [value_ptr]. Returns the length of the value, or -1 if the key isn't
+//	  in the state.
+//	state_put(key_ptr i32, key_len i32, value_ptr i32, value_len i32)
+//	  Sets the value of the key.
+//	state_delete(key_ptr i32, key_len i32)
+//	  Removes the key from the state.
+//	log(ptr i32, len i32)
+//	  Writes the message at [ptr] to the debug log of the chain.
+//
+// A call of verify_tx traps if it accesses the state.
+const (
+	exportMemory    = "memory"
+	exportAlloc     = "alloc"
+	exportVerifyTx  = "verify_tx"
+	exportExecuteTx = "execute_tx"
+	exportGenesis   = "genesis"
+
+	hostModule      = "env"
+	hostStateGet    = "state_get"
+	hostStatePut    = "state_put"
+	hostStateDelete = "state_delete"
+	hostLog         = "log"
+)
+
+const (
+	// Fuel that a call of the chain code may consume
+	callFuel = 10000000
+	// Maximum number of pages of memory of the chain code
+	maxMemoryPages = 256
+	// Fuel that each call of a host function consumes, in addition to a unit
+	// of fuel per byte that it reads or writes
+	hostCallFuel = 100
+	// Maximum lengths of the keys and values of the state
+	maxKeyLen   = 1024
+	maxValueLen = 64 * 1024
+)
+
+var (
+	errMissingExport = errors.New("chain code doesn't export a required function")
+	errExportType    = errors.New("chain code export has the wrong type")
+	errNoState       = errors.New("state isn't accessible from this function")
+	errKeyTooLong    = errors.New("key is too long")
+	errValueTooLong  = errors.New("value is too long")
+	errBadPointer    = errors.New("alloc returned a pointer out of the bounds of the memory")
+
+	ptrLen = []wasm.ValueType{wasm.I32, wasm.I32}
+
+	// Types of the functions of the ABI
+	exportTypes = map[string]wasm.FuncType{
+		exportAlloc:     {Params: []wasm.ValueType{wasm.I32}, Results: []wasm.ValueType{wasm.I32}},
+		exportVerifyTx:  {Params: ptrLen, Results: []wasm.ValueType{wasm.I32}},
+		exportExecuteTx: {Params: ptrLen, Results: []wasm.ValueType{wasm.I32}},
+		exportGenesis:   {Params: ptrLen, Results: []wasm.ValueType{wasm.I32}},
+	}
+)
+
+// runtime runs the chain code of a chain
+type runtime struct {
+	module *wasm.Module
+	log    logging.Logger
+}
+
+// newRuntime decodes the chain code [code] and checks that it implements the
+// ABI
+func newRuntime(code []byte, log logging.Logger) (*runtime, error) {
+	module, err := wasm.Decode(code)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode chain code: %w", err)
+	}
+	if export, ok := module.Exports[exportMemory]; !ok || export.Kind != wasm.ExternMemory {
+		return nil, fmt.Errorf("%w: %s", errMissingExport, exportMemory)
+	}
+	for name, typ := range exportTypes {
+		export, ok := module.Exports[name]
+		switch {
+		case !ok && name == exportGenesis:
+			continue
+		case !ok || export.Kind != wasm.ExternFunc:
+			return nil, fmt.Errorf("%w: %s", errMissingExport, name)
+		}
+		if actual, _ := module.FuncType(export.Index); !actual.Equal(typ) {
+			return nil, fmt.Errorf("%w: %s is %s, not %s", errExportType, name, actual, typ)
+		}
+	}
+	return &runtime{
+		module: module,
+		log:    log,
+	}, nil
+}
+
+// hasGenesis returns true if the chain code initializes the state from the
+// genesis data
+func (r *runtime) hasGenesis() bool {
+	export, ok := r.module.Exports[exportGenesis]
+	return ok && export.Kind == wasm.ExternFunc
+}
+
+// genesis initializes [db] from the genesis data [data]
+func (r *runtime) genesis(db database.Database, data []byte) error {
+	return r.apply(exportGenesis, db, data)
+}
+
+// verifyTx checks that [tx] is well formed
+func (r *runtime) verifyTx(tx []byte) error {
+	return r.call(exportVerifyTx, tx, nil, nil)
+}
+
+// executeTx applies [tx] to [db]. If the tx is invalid, [db] isn't modified.
+func (r *runtime) executeTx(db database.Database, tx []byte) error {
+	return r.apply(exportExecuteTx, db, tx)
+}
+
+// apply calls [export] with [input] and writes its changes of the state to
+// [db] if it succeeds
+func (r *runtime) apply(export string, db database.Database, input []byte) error {
+	changes := versiondb.New(db)
+	if err := r.call(export, input, changes, changes); err != nil {
+		return err
+	}
+	return changes.Commit()
+}
+
+// call [export] with [input] in a new instance of the chain code. The state
+// is read from [reader] and written to [writer], either of which may be nil
+// if [export] may not access the state.
+func (r *runtime) call(
+	export string,
+	input []byte,
+	reader database.KeyValueReader,
+	writer database.KeyValueWriter,
+) error {
+	instance, err := wasm.Instantiate(r.module, r.imports(reader, writer), maxMemoryPages, callFuel)
+	if err != nil {
+		return err
+	}
+	results, err := instance.Invoke(exportAlloc, instance.Fuel(), uint64(len(input)))
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", exportAlloc, err)
+	}
+	ptr := uint32(results[0])
+	if err := instance.Write(ptr, input); err != nil {
+		return fmt.Errorf("%w: %v", errBadPointer, err)
+	}
+	results, err = instance.Invoke(export, instance.Fuel(), uint64(ptr), uint64(len(input)))
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", export, err)
+	}
+	if code := int32(results[0]); code != 0 {
+		return fmt.Errorf("%s returned %d", export, code)
+	}
+	return nil
+}
+
+// imports returns the host functions of a call that reads the state from
+// [reader] and writes it to [writer]
+func (r *runtime) imports(reader database.KeyValueReader, writer database.KeyValueWriter) wasm.Imports {
+	return wasm.Imports{hostModule: {
+		hostStateGet: {
+			Type: wasm.FuncType{
+				Params:  []wasm.ValueType{wasm.I32, wasm.I32, wasm.I32, wasm.I32},
+				Results: []wasm.ValueType{wasm.I32},
+			},
+			Call: func(i *wasm.Instance, args []uint64) ([]uint64, error) {
+				if reader == nil {
+					return nil, errNoState
+				}
+				key, err := readKey(i, args[0], args[1])
+				if err != nil {
+					return nil, err
+				}
+				value, err := reader.Get(key)
+				switch {
+				case err == database.ErrNotFound:
+					return []uint64{uint64(uint32(0xffffffff))}, nil // -1
+				case err != nil:
+					return nil, err
+				}
+				n := uint32(args[3])
+				if uint32(len(value)) < n {
+					n = uint32(len(value))
+				}
+				if err := i.Consume(uint64(n)); err != nil {
+					return nil, err
+				}
+				if err := i.Write(uint32(args[2]), value[:n]); err != nil {
+					return nil, err
+				}
+				return []uint64{uint64(len(value))}, nil
+			},
+		},
+		hostStatePut: {
+			Type: wasm.FuncType{Params: []wasm.ValueType{wasm.I32, wasm.I32, wasm.I32, wasm.I32}},
+			Call: func(i *wasm.Instance, args []uint64) ([]uint64, error) {
+				if writer == nil {
+					return nil, errNoState
+				}
+				key, err := readKey(i, args[0], args[1])
+				if err != nil {
+					return nil, err
+				}
+				if args[3] > maxValueLen {
+					return nil, errValueTooLong
+				}
+				if err := i.Consume(args[3]); err != nil {
+					return nil, err
+				}
+				value, err := i.Read(uint32(args[2]), uint32(args[3]))
+				if err != nil {
+					return nil, err
+				}
+				return nil, writer.Put(key, value)
+			},
+		},
+		hostStateDelete: {
+			Type: wasm.FuncType{Params: ptrLen},
+			Call: func(i *wasm.Instance, args []uint64) ([]uint64, error) {
+				if writer == nil {
+					return nil, errNoState
+				}
+				key, err := readKey(i, args[0], args[1])
+				if err != nil {
+					return nil, err
+				}
+				return nil, writer.Delete(key)
+			},
+		},
+		hostLog: {
+			Type: wasm.FuncType{Params: ptrLen},
+			Call: func(i *wasm.Instance, args []uint64) ([]uint64, error) {
+				if err := i.Consume(hostCallFuel + args[1]); err != nil {
+					return nil, err
+				}
+				msg, err := i.Read(uint32(args[0]), uint32(args[1]))
+				if err != nil {
+					return nil, err
+				}
+				r.log.Debug("chain code: %s", msg)
+				return nil, nil
+			},
+		},
+	}}
+}
+
+// readKey charges for a call of a host function that accesses the state and
+// returns the key of [size] bytes at [ptr]
+func readKey(i *wasm.Instance, ptr, size uint64) ([]byte, error) {
+	if size > maxKeyLen {
+		return nil, errKeyTooLong
+	}
+	if err := i.Consume(hostCallFuel + size); err != nil {
+		return nil, err
+	}
+	return i.Read(uint32(ptr), uint32(size))
+}
@@ -0,0 +1,76 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasmvm
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+)
+
+// Service is the API service for this VM
+type Service struct{ vm *VM }
+
+// IssueTxArgs are the arguments to IssueTx
+type IssueTxArgs struct {
+	Tx       string              `json:"tx"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// IssueTxReply is the reply from IssueTx
+type IssueTxReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// IssueTx adds a tx to the mempool if the chain code considers it well formed
+func (s *Service) IssueTx(_ *http.Request, args *IssueTxArgs, reply *IssueTxReply) error {
+	s.vm.Ctx.Log.Info("wasm: IssueTx called")
+
+	tx, err := formatting.Decode(args.Encoding, args.Tx)
+	if err != nil {
+		return fmt.Errorf("couldn't decode tx: %w", err)
+	}
+	reply.TxID, err = s.vm.issueTx(tx)
+	return err
+}
+
+// GetValueArgs are the arguments to GetValue
+type GetValueArgs struct {
+	Key      string              `json:"key"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetValueReply is the reply from GetValue
+type GetValueReply struct {
+	Value    string              `json:"value"`
+	Found    bool                `json:"found"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetValue returns the value of a key in the accepted state of the chain
+func (s *Service) GetValue(_ *http.Request, args *GetValueArgs, reply *GetValueReply) error {
+	s.vm.Ctx.Log.Info("wasm: GetValue called")
+
+	key, err := formatting.Decode(args.Encoding, args.Key)
+	if err != nil {
+		return fmt.Errorf("couldn't decode key: %w", err)
+	}
+	reply.Encoding = args.Encoding
+	value, err := s.vm.state.Get(key)
+	switch {
+	case err == database.ErrNotFound:
+		return nil
+	case err != nil:
+		return err
+	}
+	reply.Value, err = formatting.Encode(args.Encoding, value)
+	if err != nil {
+		return fmt.Errorf("couldn't encode value as string: %w", err)
+	}
+	reply.Found = true
+	return nil
+}
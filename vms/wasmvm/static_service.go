@@ -0,0 +1,57 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasmvm
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/utils/formatting"
+)
+
+// StaticService defines the static API methods exposed by the VM
+type StaticService struct{}
+
+// BuildGenesisArgs are arguments for BuildGenesis
+type BuildGenesisArgs struct {
+	// Chain code and genesis data, encoded with [Encoding]
+	Code     string              `json:"code"`
+	Data     string              `json:"data"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// BuildGenesisReply is the reply from BuildGenesis
+type BuildGenesisReply struct {
+	Bytes    string              `json:"bytes"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// BuildGenesis returns the genesis of a chain that runs the chain code
+// [args.Code] and initializes its state from [args.Data]
+func (ss *StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, reply *BuildGenesisReply) error {
+	code, err := formatting.Decode(args.Encoding, args.Code)
+	if err != nil {
+		return fmt.Errorf("couldn't decode code: %w", err)
+	}
+	if _, err := newRuntime(code, nil); err != nil {
+		return err
+	}
+	data, err := formatting.Decode(args.Encoding, args.Data)
+	if err != nil {
+		return fmt.Errorf("couldn't decode data: %w", err)
+	}
+	genesisBytes, err := GenesisCodec.Marshal(codecVersion, &Genesis{
+		Code: code,
+		Data: data,
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't marshal genesis: %w", err)
+	}
+	reply.Bytes, err = formatting.Encode(args.Encoding, genesisBytes)
+	if err != nil {
+		return fmt.Errorf("couldn't encode genesis as string: %w", err)
+	}
+	reply.Encoding = args.Encoding
+	return nil
+}
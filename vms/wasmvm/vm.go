@@ -0,0 +1,269 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasmvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/vms/components/core"
+)
+
+const (
+	// Maximum size of a tx
+	maxTxSize = 64 * 1024
+	// Maximum total size of the txs of a block, which leaves room for the
+	// rest of the block within the maximum size of the codec
+	maxBlockTxsSize = 128 * 1024
+	// Maximum number of txs in the mempool
+	maxMempoolSize = 4096
+)
+
+var (
+	statePrefix = []byte("state")
+
+	errNoPendingTxs = errors.New("there are no txs to put in a block")
+	errTxTooLarge   = errors.New("tx is too large")
+	errMempoolFull  = errors.New("mempool is full")
+	errDuplicateTx  = errors.New("tx is already in the mempool")
+
+	_ block.ChainVM   = &VM{}
+	_ common.StaticVM = &VM{}
+)
+
+// VM runs chains whose logic is a WASM module, the chain code, that is set in
+// the genesis of the chain. Each block contains txs that the chain code
+// executes against the state of the chain, a key/value store.
+type VM struct {
+	core.SnowmanVM
+	runtime *runtime
+
+	// Accepted state of the chain
+	state database.Database
+	// Blocks that are verified but not yet accepted or rejected
+	verifiedBlocks map[ids.ID]*Block
+
+	// Txs that haven't been put into a block yet
+	mempool []mempoolTx
+	// IDs of the txs in the mempool
+	mempoolIDs ids.Set
+}
+
+type mempoolTx struct {
+	id ids.ID
+	tx []byte
+}
+
+// Initialize this vm
+// [ctx] is this vm's context
+// [dbManager] is the manager of this vm's database
+// [toEngine] is used to notify the consensus engine that new blocks are
+//
+//	ready to be added to consensus
+//
+// The chain code and the data of the genesis are in [genesisData]
+func (vm *VM) Initialize(
+	ctx *snow.Context,
+	dbManager manager.Manager,
+	genesisData []byte,
+	upgradeData []byte,
+	configData []byte,
+	toEngine chan<- common.Message,
+	_ []*common.Fx,
+) error {
+	if err := vm.SnowmanVM.Initialize(ctx, dbManager.Current().Database, vm.ParseBlock, toEngine); err != nil {
+		ctx.Log.Error("error initializing SnowmanVM: %v", err)
+		return err
+	}
+	vm.state = prefixdb.New(statePrefix, vm.DB)
+	vm.verifiedBlocks = make(map[ids.ID]*Block)
+
+	genesis := &Genesis{}
+	if _, err := GenesisCodec.Unmarshal(genesisData, genesis); err != nil {
+		return fmt.Errorf("couldn't parse genesis: %w", err)
+	}
+	runtime, err := newRuntime(genesis.Code, ctx.Log)
+	if err != nil {
+		return err
+	}
+	vm.runtime = runtime
+
+	// If database is empty, create it using the provided genesis data
+	if vm.DBInitialized() {
+		return nil
+	}
+	if vm.runtime.hasGenesis() {
+		if err := vm.runtime.genesis(vm.state, genesis.Data); err != nil {
+			return fmt.Errorf("couldn't initialize state from genesis: %w", err)
+		}
+	}
+
+	// The genesis block has no parent and no txs
+	genesisBlock, err := vm.newBlock(ids.Empty, 0, nil)
+	if err != nil {
+		return fmt.Errorf("error while creating genesis block: %w", err)
+	}
+	if err := vm.SaveBlock(vm.DB, genesisBlock); err != nil {
+		return fmt.Errorf("error while saving genesis block: %w", err)
+	}
+	// Sets [vm.LastAcceptedID]
+	if err := genesisBlock.Block.Accept(); err != nil {
+		return fmt.Errorf("error accepting genesis block: %w", err)
+	}
+	if err := vm.SetDBInitialized(); err != nil {
+		return fmt.Errorf("error while setting db to initialized: %w", err)
+	}
+	return vm.DB.Commit()
+}
+
+// CreateHandlers returns a map where:
+// Keys: The path extension for this VM's API (empty in this case)
+// Values: The handler for the API
+func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
+	handler, err := vm.NewHandler("wasm", &Service{vm: vm})
+	return map[string]*common.HTTPHandler{
+		"": handler,
+	}, err
+}
+
+// CreateStaticHandlers returns a map where:
+// Keys: The path extension for this VM's static API
+// Values: The handler for that static API
+func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
+	handler, err := vm.NewHandler("wasm", &StaticService{})
+	return map[string]*common.HTTPHandler{
+		"": handler,
+	}, err
+}
+
+// HealthCheck implements the common.VM interface
+func (vm *VM) HealthCheck() (interface{}, error) { return nil, nil }
+
+// BuildBlock returns a block of the txs in the mempool that are valid against
+// the state of the preferred block. Invalid txs are dropped.
+func (vm *VM) BuildBlock() (snowman.Block, error) {
+	preferredID := vm.Preferred()
+	preferredIntf, err := vm.GetBlock(preferredID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get preferred block: %w", err)
+	}
+	preferredState, err := vm.stateAfter(preferredID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The txs are executed against a scratch state, which is discarded. They
+	// are executed again when the block is verified.
+	scratch := versiondb.New(preferredState)
+
+	var (
+		txs  [][]byte
+		size int
+	)
+	for len(vm.mempool) > 0 && size+len(vm.mempool[0].tx) <= maxBlockTxsSize {
+		tx := vm.mempool[0]
+		vm.mempool = vm.mempool[1:]
+		vm.mempoolIDs.Remove(tx.id)
+
+		if err := vm.runtime.executeTx(scratch, tx.tx); err != nil {
+			vm.Ctx.Log.Debug("dropping tx %s: %s", tx.id, err)
+			continue
+		}
+		txs = append(txs, tx.tx)
+		size += len(tx.tx)
+	}
+	if len(vm.mempool) > 0 {
+		defer vm.NotifyBlockReady()
+	}
+	if len(txs) == 0 {
+		return nil, errNoPendingTxs
+	}
+	return vm.newBlock(preferredID, preferredIntf.Height()+1, txs)
+}
+
+// issueTx adds [tx] to the mempool if the chain code considers it well
+// formed. Returns the ID of the tx.
+func (vm *VM) issueTx(tx []byte) (ids.ID, error) {
+	if len(tx) > maxTxSize {
+		return ids.ID{}, errTxTooLarge
+	}
+	txID := ids.ID(hashing.ComputeHash256Array(tx))
+	if vm.mempoolIDs.Contains(txID) {
+		return ids.ID{}, errDuplicateTx
+	}
+	if len(vm.mempool) >= maxMempoolSize {
+		return ids.ID{}, errMempoolFull
+	}
+	if err := vm.runtime.verifyTx(tx); err != nil {
+		return ids.ID{}, err
+	}
+	vm.mempool = append(vm.mempool, mempoolTx{id: txID, tx: tx})
+	vm.mempoolIDs.Add(txID)
+	vm.NotifyBlockReady()
+	return txID, nil
+}
+
+// stateAfter returns the state after the block [blkID] is executed. The block
+// must be verified or be the last accepted block.
+func (vm *VM) stateAfter(blkID ids.ID) (database.Database, error) {
+	if blk, ok := vm.verifiedBlocks[blkID]; ok {
+		return blk.state, nil
+	}
+	if blkID == vm.LastAcceptedID {
+		return vm.state, nil
+	}
+	blk, err := vm.GetBlock(blkID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errUnknownParent, err)
+	}
+	if blk.Status().Decided() {
+		return nil, errDecidedParent
+	}
+	return nil, errUnknownParent
+}
+
+// ParseBlock parses [bytes] to a snowman.Block
+// This function is used by the vm's state to unmarshal blocks saved in state
+func (vm *VM) ParseBlock(bytes []byte) (snowman.Block, error) {
+	block := &Block{}
+	if _, err := Codec.Unmarshal(bytes, block); err != nil {
+		return nil, err
+	}
+	block.Initialize(bytes, &vm.SnowmanVM)
+	block.vm = vm
+	return block, nil
+}
+
+// newBlock returns a new Block with the parent [parentID] and the txs [txs]
+func (vm *VM) newBlock(parentID ids.ID, height uint64, txs [][]byte) (*Block, error) {
+	block := &Block{
+		Block: core.NewBlock(parentID, height),
+		Txs:   txs,
+		vm:    vm,
+	}
+	blockBytes, err := Codec.Marshal(codecVersion, block)
+	if err != nil {
+		return nil, err
+	}
+	block.Initialize(blockBytes, &vm.SnowmanVM)
+	return block, nil
+}
+
+func (vm *VM) Connected(id ids.ShortID) error {
+	return nil // noop
+}
+
+func (vm *VM) Disconnected(id ids.ShortID) error {
+	return nil // noop
+}
@@ -0,0 +1,265 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasmvm
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/vms/wasmvm/wasm"
+)
+
+// testCode is chain code whose txs set a key that isn't set yet. A tx is the
+// length of the key as a byte, followed by the key and the value. It's
+// assembled from:
+//
+// (module
+//
+//	(import "env" "state_get" (func $get (param i32 i32 i32 i32) (result i32)))
+//	(import "env" "state_put" (func $put (param i32 i32 i32 i32)))
+//	(memory (export "memory") 1)
+//	(func (export "alloc") (param i32) (result i32)
+//	  (i32.const 1024))
+//	(func (export "verify_tx") (param $ptr i32) (param $len i32) (result i32)
+//	  (i32.or
+//	    (i32.eqz (i32.load8_u (local.get $ptr)))
+//	    (i32.ge_u (i32.add (i32.load8_u (local.get $ptr)) (i32.const 1)) (local.get $len))))
+//	(func $execute (export "execute_tx") (param $ptr i32) (param $len i32) (result i32)
+//	  (if (result i32)
+//	    (i32.ne
+//	      (call $get
+//	        (i32.add (local.get $ptr) (i32.const 1)) (i32.load8_u (local.get $ptr))
+//	        (i32.const 0) (i32.const 0))
+//	      (i32.const -1))
+//	    (then (i32.const 1))
+//	    (else
+//	      (call $put
+//	        (i32.add (local.get $ptr) (i32.const 1)) (i32.load8_u (local.get $ptr))
+//	        (i32.add (i32.add (local.get $ptr) (i32.const 1)) (i32.load8_u (local.get $ptr)))
+//	        (i32.sub (i32.sub (local.get $len) (i32.load8_u (local.get $ptr))) (i32.const 1)))
+//	      (i32.const 0))))
+//	(func (export "genesis") (param i32 i32) (result i32)
+//	  (call $execute (local.get 0) (local.get 1))))
+var testCode, _ = hex.DecodeString(
+	"0061736d01000000011b0460047f7f7f7f017f60047f7f7f7f0060017f017f60" +
+		"027f7f017f02210203656e760973746174655f676574000003656e7609737461" +
+		"74655f7075740001030504020303030503010001073505066d656d6f72790200" +
+		"05616c6c6f630002097665726966795f747800030a657865637574655f747800" +
+		"040767656e6573697300050a650405004180080b140020002d00004520002d00" +
+		"0041016a20014f720b3f00200041016a20002d0000410041001000417f47047f" +
+		"410105200041016a20002d0000200041016a20002d00006a200120002d00006b" +
+		"41016b100141000b0b08002000200110040b",
+)
+
+func testTx(key, value string) []byte {
+	return append(append([]byte{byte(len(key))}, key...), value...)
+}
+
+func newTestVM(t *testing.T) (*VM, chan common.Message) {
+	genesisBytes, err := GenesisCodec.Marshal(codecVersion, &Genesis{
+		Code: testCode,
+		Data: testTx("genesis", "value"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgChan := make(chan common.Message, 1)
+	vm := &VM{}
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.ID{1, 2, 3}
+	if err := vm.Initialize(ctx, manager.NewDefaultMemDBManager(), genesisBytes, nil, nil, msgChan, nil); err != nil {
+		t.Fatal(err)
+	}
+	lastAcceptedID, err := vm.LastAccepted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.SetPreference(lastAcceptedID); err != nil {
+		t.Fatal(err)
+	}
+	return vm, msgChan
+}
+
+func assertValue(t *testing.T, db database.KeyValueReader, key, expected string) {
+	value, err := db.Get([]byte(key))
+	if expected == "" {
+		assert.Equal(t, database.ErrNotFound, err, "key %q", key)
+		return
+	}
+	assert.NoError(t, err, "key %q", key)
+	assert.Equal(t, expected, string(value), "key %q", key)
+}
+
+func TestGenesis(t *testing.T) {
+	vm, _ := newTestVM(t)
+
+	assert.True(t, vm.DBInitialized())
+	assertValue(t, vm.state, "genesis", "value")
+
+	genesisBlock, err := vm.GetBlock(vm.LastAcceptedID)
+	assert.NoError(t, err)
+	assert.Equal(t, choices.Accepted, genesisBlock.Status())
+	assert.Equal(t, uint64(0), genesisBlock.Height())
+}
+
+func TestIssueTx(t *testing.T) {
+	vm, msgChan := newTestVM(t)
+
+	_, err := vm.issueTx(testTx("a", "1"))
+	assert.NoError(t, err)
+	assert.Equal(t, common.PendingTxs, <-msgChan)
+
+	_, err = vm.issueTx(testTx("a", "1"))
+	assert.Equal(t, errDuplicateTx, err)
+
+	// The key is empty
+	_, err = vm.issueTx(testTx("", "1"))
+	assert.Error(t, err)
+	// There is no value
+	_, err = vm.issueTx(testTx("b", ""))
+	assert.Error(t, err)
+	assert.Len(t, vm.mempool, 1)
+}
+
+func TestBuildBlock(t *testing.T) {
+	vm, _ := newTestVM(t)
+
+	for _, tx := range [][]byte{
+		testTx("a", "1"),
+		testTx("a", "2"),       // Sets a key that an earlier tx in the block sets
+		testTx("genesis", "2"), // Sets a key that is already set
+		testTx("b", "3"),
+	} {
+		if _, err := vm.issueTx(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, [][]byte{testTx("a", "1"), testTx("b", "3")}, blk.(*Block).Txs)
+	assert.Empty(t, vm.mempool)
+
+	// The state of the chain changes when the block is accepted
+	assert.NoError(t, blk.Verify())
+	assertValue(t, vm.state, "a", "")
+	assert.NoError(t, blk.Accept())
+	assertValue(t, vm.state, "a", "1")
+	assertValue(t, vm.state, "b", "3")
+	assertValue(t, vm.state, "genesis", "value")
+
+	if err := vm.SetPreference(blk.ID()); err != nil {
+		t.Fatal(err)
+	}
+	_, err = vm.BuildBlock()
+	assert.Equal(t, errNoPendingTxs, err)
+}
+
+func TestChainOfProcessingBlocks(t *testing.T) {
+	vm, _ := newTestVM(t)
+
+	if _, err := vm.issueTx(testTx("a", "1")); err != nil {
+		t.Fatal(err)
+	}
+	blk1, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk1.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.SetPreference(blk1.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The tx is invalid on top of the processing block
+	if _, err := vm.issueTx(testTx("a", "2")); err != nil {
+		t.Fatal(err)
+	}
+	_, err = vm.BuildBlock()
+	assert.Equal(t, errNoPendingTxs, err)
+
+	if _, err := vm.issueTx(testTx("b", "2")); err != nil {
+		t.Fatal(err)
+	}
+	blk2, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, blk1.ID(), blk2.Parent().ID())
+
+	// A block with the conflicting tx is invalid on top of the first block
+	conflicting, err := vm.newBlock(blk1.ID(), 2, [][]byte{testTx("a", "2")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, conflicting.Verify())
+
+	// Parse the block, like a block that is received from a peer
+	blk2, err = vm.ParseBlock(blk2.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, blk2.Verify())
+	assert.NoError(t, blk1.Accept())
+	assertValue(t, vm.state, "a", "1")
+	assertValue(t, vm.state, "b", "")
+	assert.NoError(t, blk2.Accept())
+	assertValue(t, vm.state, "b", "2")
+	assert.Equal(t, blk2.ID(), vm.LastAcceptedID)
+}
+
+func TestRejectBlock(t *testing.T) {
+	vm, _ := newTestVM(t)
+
+	if _, err := vm.issueTx(testTx("a", "1")); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, blk.Verify())
+	assert.NoError(t, blk.Reject())
+	assert.Equal(t, choices.Rejected, blk.Status())
+	assert.Empty(t, vm.verifiedBlocks)
+	assertValue(t, vm.state, "a", "")
+}
+
+func TestInvalidChainCode(t *testing.T) {
+	_, err := newRuntime([]byte{0, 'a', 's', 'm'}, nil)
+	assert.Error(t, err)
+
+	// The module only has the type and import sections of the test code
+	_, err = newRuntime(testCode[:0x48], nil)
+	assert.True(t, errors.Is(err, errMissingExport))
+}
+
+func TestVerifyTxCantAccessState(t *testing.T) {
+	r, err := newRuntime(testCode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// execute_tx reads the state
+	err = r.call(exportExecuteTx, testTx("a", "1"), nil, nil)
+	assert.True(t, errors.Is(err, errNoState))
+
+	err = r.call(exportVerifyTx, testTx("a", "1"), nil, nil)
+	assert.NoError(t, err)
+	err = r.call(exportVerifyTx, testTx("a", ""), nil, nil)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, wasm.ErrTrap))
+}
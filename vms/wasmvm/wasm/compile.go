@@ -0,0 +1,486 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Opcodes of the supported instructions
+const (
+	opUnreachable  byte = 0x00
+	opNop          byte = 0x01
+	opBlock        byte = 0x02
+	opLoop         byte = 0x03
+	opIf           byte = 0x04
+	opElse         byte = 0x05
+	opEnd          byte = 0x0b
+	opBr           byte = 0x0c
+	opBrIf         byte = 0x0d
+	opBrTable      byte = 0x0e
+	opReturn       byte = 0x0f
+	opCall         byte = 0x10
+	opDrop         byte = 0x1a
+	opSelect       byte = 0x1b
+	opSelectTyped  byte = 0x1c
+	opLocalGet     byte = 0x20
+	opLocalSet     byte = 0x21
+	opLocalTee     byte = 0x22
+	opGlobalGet    byte = 0x23
+	opGlobalSet    byte = 0x24
+	opI32Load      byte = 0x28
+	opI64Load      byte = 0x29
+	opI32Load8S    byte = 0x2c
+	opI32Load8U    byte = 0x2d
+	opI32Load16S   byte = 0x2e
+	opI32Load16U   byte = 0x2f
+	opI64Load8S    byte = 0x30
+	opI64Load8U    byte = 0x31
+	opI64Load16S   byte = 0x32
+	opI64Load16U   byte = 0x33
+	opI64Load32S   byte = 0x34
+	opI64Load32U   byte = 0x35
+	opI32Store     byte = 0x36
+	opI64Store     byte = 0x37
+	opI32Store8    byte = 0x3a
+	opI32Store16   byte = 0x3b
+	opI64Store8    byte = 0x3c
+	opI64Store16   byte = 0x3d
+	opI64Store32   byte = 0x3e
+	opMemorySize   byte = 0x3f
+	opMemoryGrow   byte = 0x40
+	opI32Const     byte = 0x41
+	opI64Const     byte = 0x42
+	opI32Eqz       byte = 0x45
+	opI32GeU       byte = 0x4f
+	opI64Eqz       byte = 0x50
+	opI64GeU       byte = 0x5a
+	opI32Clz       byte = 0x67
+	opI32Rotr      byte = 0x78
+	opI64Clz       byte = 0x79
+	opI64Rotr      byte = 0x8a
+	opI32WrapI64   byte = 0xa7
+	opI64ExtendS   byte = 0xac
+	opI64ExtendU   byte = 0xad
+	opI32Extend8S  byte = 0xc0
+	opI32Extend16S byte = 0xc1
+	opI64Extend8S  byte = 0xc2
+	opI64Extend16S byte = 0xc3
+	opI64Extend32S byte = 0xc4
+	opPrefixFC     byte = 0xfc
+
+	// Sub-opcodes of the 0xfc prefix
+	subMemoryCopy uint32 = 10
+	subMemoryFill uint32 = 11
+
+	// The prefixed instructions are compiled to these opcodes, which don't
+	// collide with any single byte opcode
+	opMemoryCopy byte = 0xf0
+	opMemoryFill byte = 0xf1
+)
+
+const (
+	// Maximum number of locals, including the parameters, of a function
+	maxLocals = 50000
+	// Maximum nesting depth of blocks in a function
+	maxBlockDepth = 1024
+)
+
+var (
+	errBadBlockType   = errors.New("invalid block type")
+	errBadBranchDepth = errors.New("branch to unknown block")
+	errBadElse        = errors.New("else outside of if")
+	errNoMemory       = errors.New("memory instruction without a memory")
+	errBadReserved    = errors.New("reserved byte isn't 0")
+	errTrailingCode   = errors.New("code after the end of the function")
+	errMissingEnd     = errors.New("function doesn't end")
+)
+
+// instr is a compiled instruction. Branch targets are resolved when the
+// function is compiled, so that they needn't be searched for when the
+// function runs.
+type instr struct {
+	op byte
+	// Constant, index, branch depth or memory offset of the instruction
+	imm uint64
+	// Numbers of parameters and results of a block, loop or if
+	params, results uint32
+	// Indices of the else and end instructions of a block, loop or if. elseAt
+	// is -1 if there is no else.
+	elseAt, endAt int
+	// Branch depths of br_table, with the default depth last
+	table []uint32
+}
+
+// compile the body of a function of type [typ] whose locals, including its
+// parameters, have the types [locals]. [funcTypes] are the type indices of
+// the functions defined by the module. The function is validated as it's
+// compiled, so that its code can't misuse the operand stack when it runs.
+func (m *Module) compile(r *reader, typ FuncType, locals []ValueType, funcTypes []uint32) ([]instr, error) {
+	var (
+		code []instr
+		// Indices of the blocks that contain the current instruction
+		blocks []int
+		v      = &validator{}
+	)
+	// The body of the function is a block that returns its results
+	v.pushCtrl(opBlock, nil, typ.Results)
+	for r.len() > 0 {
+		op, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		in := instr{op: op, elseAt: -1}
+		switch {
+		case op == opUnreachable:
+			v.setUnreachable()
+		case op == opNop:
+		case op == opReturn:
+			if _, err := v.popVals(typ.Results); err != nil {
+				return nil, err
+			}
+			v.setUnreachable()
+		case op == opDrop:
+			if _, err := v.popVal(unknownType); err != nil {
+				return nil, err
+			}
+		case op == opSelect, op == opSelectTyped:
+			expected := unknownType
+			if op == opSelectTyped {
+				types, err := r.valueTypes()
+				if err != nil {
+					return nil, err
+				}
+				if len(types) != 1 {
+					return nil, fmt.Errorf("%w: select with %d types", errTypeMismatch, len(types))
+				}
+				expected = types[0]
+				in.op = opSelect
+			}
+			if _, err := v.popVal(I32); err != nil {
+				return nil, err
+			}
+			t1, err := v.popVal(expected)
+			if err != nil {
+				return nil, err
+			}
+			t2, err := v.popVal(t1)
+			if err != nil {
+				return nil, err
+			}
+			v.pushVal(t2)
+		case op == opBlock, op == opLoop, op == opIf:
+			if len(blocks) >= maxBlockDepth {
+				return nil, fmt.Errorf("blocks nested more than %d deep", maxBlockDepth)
+			}
+			params, results, err := m.blockType(r)
+			if err != nil {
+				return nil, err
+			}
+			if op == opIf {
+				if _, err := v.popVal(I32); err != nil {
+					return nil, err
+				}
+			}
+			if _, err := v.popVals(params); err != nil {
+				return nil, err
+			}
+			v.pushCtrl(op, params, results)
+			in.params, in.results = uint32(len(params)), uint32(len(results))
+			blocks = append(blocks, len(code))
+		case op == opElse:
+			if len(blocks) == 0 || code[blocks[len(blocks)-1]].op != opIf || code[blocks[len(blocks)-1]].elseAt != -1 {
+				return nil, errBadElse
+			}
+			frame, err := v.popCtrl()
+			if err != nil {
+				return nil, err
+			}
+			v.pushCtrl(opElse, frame.params, frame.results)
+			code[blocks[len(blocks)-1]].elseAt = len(code)
+		case op == opEnd:
+			frame, err := v.popCtrl()
+			if err != nil {
+				return nil, err
+			}
+			if len(blocks) == 0 {
+				// This is the end of the function
+				if r.len() != 0 {
+					return nil, errTrailingCode
+				}
+				return append(code, in), nil
+			}
+			start := &code[blocks[len(blocks)-1]]
+			if frame.op == opIf && !bytes.Equal(valueTypeBytes(frame.params), valueTypeBytes(frame.results)) {
+				return nil, errIfWithoutElse
+			}
+			v.pushVals(frame.results)
+			start.endAt = len(code)
+			if start.elseAt != -1 {
+				code[start.elseAt].endAt = len(code)
+			}
+			blocks = blocks[:len(blocks)-1]
+		case op == opBr, op == opBrIf:
+			depth, err := r.u32()
+			if err != nil {
+				return nil, err
+			}
+			if depth > uint32(len(blocks)) {
+				return nil, errBadBranchDepth
+			}
+			in.imm = uint64(depth)
+			if op == opBrIf {
+				if _, err := v.popVal(I32); err != nil {
+					return nil, err
+				}
+			}
+			labelTypes := v.label(depth).labelTypes()
+			popped, err := v.popVals(labelTypes)
+			if err != nil {
+				return nil, err
+			}
+			if op == opBr {
+				v.setUnreachable()
+			} else {
+				v.pushVals(popped)
+			}
+		case op == opBrTable:
+			in.table, err = r.u32s()
+			if err != nil {
+				return nil, err
+			}
+			defaultDepth, err := r.u32()
+			if err != nil {
+				return nil, err
+			}
+			in.table = append(in.table, defaultDepth)
+			for _, depth := range in.table {
+				if depth > uint32(len(blocks)) {
+					return nil, errBadBranchDepth
+				}
+			}
+			if _, err := v.popVal(I32); err != nil {
+				return nil, err
+			}
+			arity := len(v.label(defaultDepth).labelTypes())
+			for _, depth := range in.table {
+				labelTypes := v.label(depth).labelTypes()
+				if len(labelTypes) != arity {
+					return nil, errBadArity
+				}
+				popped, err := v.popVals(labelTypes)
+				if err != nil {
+					return nil, err
+				}
+				v.pushVals(popped)
+			}
+			if _, err := v.popVals(v.label(defaultDepth).labelTypes()); err != nil {
+				return nil, err
+			}
+			v.setUnreachable()
+		case op == opCall:
+			index, err := r.u32()
+			if err != nil {
+				return nil, err
+			}
+			if index >= uint32(len(m.Imports)+len(funcTypes)) {
+				return nil, fmt.Errorf("call of unknown function %d", index)
+			}
+			in.imm = uint64(index)
+			callee := m.funcType(index, funcTypes)
+			if err := v.apply(callee.Params, callee.Results); err != nil {
+				return nil, err
+			}
+		case op >= opLocalGet && op <= opLocalTee:
+			index, err := r.u32()
+			if err != nil {
+				return nil, err
+			}
+			if index >= uint32(len(locals)) {
+				return nil, fmt.Errorf("unknown local %d", index)
+			}
+			in.imm = uint64(index)
+			t := []ValueType{locals[index]}
+			switch op {
+			case opLocalGet:
+				err = v.apply(nil, t)
+			case opLocalSet:
+				err = v.apply(t, nil)
+			default:
+				err = v.apply(t, t)
+			}
+			if err != nil {
+				return nil, err
+			}
+		case op == opGlobalGet, op == opGlobalSet:
+			index, err := r.u32()
+			if err != nil {
+				return nil, err
+			}
+			if index >= uint32(len(m.Globals)) {
+				return nil, fmt.Errorf("unknown global %d", index)
+			}
+			if op == opGlobalSet && !m.Globals[index].Mutable {
+				return nil, fmt.Errorf("global %d is immutable", index)
+			}
+			in.imm = uint64(index)
+			t := []ValueType{m.Globals[index].Type}
+			if op == opGlobalGet {
+				err = v.apply(nil, t)
+			} else {
+				err = v.apply(t, nil)
+			}
+			if err != nil {
+				return nil, err
+			}
+		case op >= opI32Load && op <= opI64Store32:
+			if op > opI64Load && op < opI32Load8S {
+				// Floating point loads
+				return nil, fmt.Errorf("%w: opcode 0x%x", ErrUnsupported, op)
+			}
+			if op > opI64Store && op < opI32Store8 {
+				// Floating point stores
+				return nil, fmt.Errorf("%w: opcode 0x%x", ErrUnsupported, op)
+			}
+			if m.Memory == nil {
+				return nil, errNoMemory
+			}
+			align, err := r.u32()
+			if err != nil {
+				return nil, err
+			}
+			// The alignment is only a hint, but it can't exceed the size of
+			// the access
+			if align >= 32 || 1<<align > loadStoreSize(op) {
+				return nil, errBadAlignment
+			}
+			offset, err := r.u32()
+			if err != nil {
+				return nil, err
+			}
+			in.imm = uint64(offset)
+			t := []ValueType{loadStoreType(op)}
+			if op <= opI64Load32U {
+				err = v.apply(i32, t)
+			} else {
+				err = v.apply([]ValueType{I32, t[0]}, nil)
+			}
+			if err != nil {
+				return nil, err
+			}
+		case op == opMemorySize, op == opMemoryGrow:
+			if m.Memory == nil {
+				return nil, errNoMemory
+			}
+			if err := r.reserved(1); err != nil {
+				return nil, err
+			}
+			if op == opMemorySize {
+				err = v.apply(nil, i32)
+			} else {
+				err = v.apply(i32, i32)
+			}
+			if err != nil {
+				return nil, err
+			}
+		case op == opI32Const:
+			value, err := r.s32()
+			if err != nil {
+				return nil, err
+			}
+			in.imm = uint64(uint32(value))
+			v.pushVal(I32)
+		case op == opI64Const:
+			value, err := r.s64()
+			if err != nil {
+				return nil, err
+			}
+			in.imm = uint64(value)
+			v.pushVal(I64)
+		case op >= opI32Eqz && op <= opI64GeU,
+			op >= opI32Clz && op <= opI64Rotr,
+			op == opI32WrapI64, op == opI64ExtendS, op == opI64ExtendU,
+			op >= opI32Extend8S && op <= opI64Extend32S:
+			if err := v.apply(numericSignature(op)); err != nil {
+				return nil, err
+			}
+		case op == opPrefixFC:
+			sub, err := r.u32()
+			if err != nil {
+				return nil, err
+			}
+			switch sub {
+			case subMemoryCopy:
+				in.op = opMemoryCopy
+				err = r.reserved(2)
+			case subMemoryFill:
+				in.op = opMemoryFill
+				err = r.reserved(1)
+			default:
+				return nil, fmt.Errorf("%w: opcode 0xfc %d", ErrUnsupported, sub)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if m.Memory == nil {
+				return nil, errNoMemory
+			}
+			if err := v.apply([]ValueType{I32, I32, I32}, nil); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%w: opcode 0x%x", ErrUnsupported, op)
+		}
+		code = append(code, in)
+	}
+	return nil, errMissingEnd
+}
+
+// funcType returns the signature of the function with index [index], where
+// the imported functions come first and [funcTypes] are the type indices of
+// the functions defined by the module. The index and the type indices must
+// have been checked.
+func (m *Module) funcType(index uint32, funcTypes []uint32) FuncType {
+	if index < uint32(len(m.Imports)) {
+		return m.Types[m.Imports[index].Type]
+	}
+	return m.Types[funcTypes[index-uint32(len(m.Imports))]]
+}
+
+// blockType returns the types of the parameters and results of a block
+func (m *Module) blockType(r *reader) ([]ValueType, []ValueType, error) {
+	t, err := r.s33()
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case t == -0x40: // 0x40 encodes the empty type
+		return nil, nil, nil
+	case t == -0x01: // 0x7f encodes i32
+		return nil, i32, nil
+	case t == -0x02: // 0x7e encodes i64
+		return nil, i64, nil
+	case t >= 0 && t < int64(len(m.Types)):
+		typ := m.Types[t]
+		return typ.Params, typ.Results, nil
+	default:
+		return nil, nil, errBadBlockType
+	}
+}
+
+// reserved reads [n] reserved bytes, which must be 0
+func (r *reader) reserved(n int) error {
+	for i := 0; i < n; i++ {
+		b, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if b != 0 {
+			return errBadReserved
+		}
+	}
+	return nil
+}
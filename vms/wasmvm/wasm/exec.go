@@ -0,0 +1,526 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Opcodes of the i32 comparison and arithmetic instructions. The i64
+// instructions have the same order, so they're handled as their i32
+// counterparts after the opcode is shifted.
+const (
+	opI32Eq     byte = 0x46
+	opI32Ne     byte = 0x47
+	opI32LtS    byte = 0x48
+	opI32LtU    byte = 0x49
+	opI32GtS    byte = 0x4a
+	opI32GtU    byte = 0x4b
+	opI32LeS    byte = 0x4c
+	opI32LeU    byte = 0x4d
+	opI32GeS    byte = 0x4e
+	opI32Ctz    byte = 0x68
+	opI32Popcnt byte = 0x69
+	opI32Add    byte = 0x6a
+	opI32Sub    byte = 0x6b
+	opI32Mul    byte = 0x6c
+	opI32DivS   byte = 0x6d
+	opI32DivU   byte = 0x6e
+	opI32RemS   byte = 0x6f
+	opI32RemU   byte = 0x70
+	opI32And    byte = 0x71
+	opI32Or     byte = 0x72
+	opI32Xor    byte = 0x73
+	opI32Shl    byte = 0x74
+	opI32ShrS   byte = 0x75
+	opI32ShrU   byte = 0x76
+	opI32Rotl   byte = 0x77
+
+	i64CompareShift    = opI64Eqz - opI32Eqz
+	i64ArithmeticShift = opI64Clz - opI32Clz
+
+	// Number of bytes that the bulk memory instructions copy or fill for each
+	// unit of fuel they consume
+	bytesPerFuel = 32
+)
+
+// label is the target of a branch
+type label struct {
+	// Height of the stack below the values of the block
+	height int
+	// Number of values that a branch to the label keeps on the stack
+	arity int
+	// Index of the instruction that a branch to the label continues at
+	cont int
+}
+
+func (i *Instance) call(index uint32, args []uint64) ([]uint64, error) {
+	if index < uint32(len(i.hosts)) {
+		host := i.hosts[index]
+		results, err := host.Call(i, args)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) != len(host.Type.Results) {
+			return nil, fmt.Errorf("%w: host function returned %d results, expected %d", ErrTrap, len(results), len(host.Type.Results))
+		}
+		return results, nil
+	}
+
+	if i.depth >= maxCallDepth {
+		return nil, fmt.Errorf("%w: call stack exhausted", ErrTrap)
+	}
+	i.depth++
+	defer func() { i.depth-- }()
+
+	var (
+		f      = &i.module.funcs[index-uint32(len(i.hosts))]
+		typ    = i.module.Types[f.typ]
+		locals = make([]uint64, len(typ.Params)+len(f.locals))
+		stack  = make([]uint64, 0, 16)
+		labels []label
+		code   = f.code
+	)
+	copy(locals, args)
+
+	// branch to the label [depth] blocks out. Returns the index of the
+	// instruction to continue at, or -1 if the function returns.
+	branch := func(depth int) int {
+		if depth == len(labels) {
+			return -1
+		}
+		l := labels[len(labels)-1-depth]
+		n := copy(stack[l.height:], stack[len(stack)-l.arity:])
+		stack = stack[:l.height+n]
+		labels = labels[:len(labels)-1-depth]
+		return l.cont
+	}
+
+	for pc := 0; pc < len(code); pc++ {
+		if i.fuel == 0 {
+			return nil, ErrOutOfFuel
+		}
+		i.fuel--
+
+		in := &code[pc]
+		top := len(stack) - 1
+		switch op := in.op; {
+		case op == opUnreachable:
+			return nil, fmt.Errorf("%w: unreachable", ErrTrap)
+		case op == opNop:
+		case op == opBlock:
+			labels = append(labels, label{
+				height: len(stack) - int(in.params),
+				arity:  int(in.results),
+				cont:   in.endAt + 1,
+			})
+		case op == opLoop:
+			labels = append(labels, label{
+				height: len(stack) - int(in.params),
+				arity:  int(in.params),
+				cont:   pc,
+			})
+		case op == opIf:
+			c := uint32(stack[top])
+			stack = stack[:top]
+			labels = append(labels, label{
+				height: len(stack) - int(in.params),
+				arity:  int(in.results),
+				cont:   in.endAt + 1,
+			})
+			if c == 0 {
+				if in.elseAt >= 0 {
+					pc = in.elseAt
+				} else {
+					pc = in.endAt - 1
+				}
+			}
+		case op == opElse:
+			// The end of the then branch was reached
+			labels = labels[:len(labels)-1]
+			pc = in.endAt
+		case op == opEnd:
+			if len(labels) == 0 {
+				return stack[len(stack)-len(typ.Results):], nil
+			}
+			labels = labels[:len(labels)-1]
+		case op == opBr:
+			if pc = branch(int(in.imm)); pc < 0 {
+				return stack[len(stack)-len(typ.Results):], nil
+			}
+			pc--
+		case op == opBrIf:
+			c := uint32(stack[top])
+			stack = stack[:top]
+			if c != 0 {
+				if pc = branch(int(in.imm)); pc < 0 {
+					return stack[len(stack)-len(typ.Results):], nil
+				}
+				pc--
+			}
+		case op == opBrTable:
+			j := uint64(uint32(stack[top]))
+			stack = stack[:top]
+			if j >= uint64(len(in.table)) {
+				j = uint64(len(in.table) - 1)
+			}
+			if pc = branch(int(in.table[j])); pc < 0 {
+				return stack[len(stack)-len(typ.Results):], nil
+			}
+			pc--
+		case op == opReturn:
+			return stack[len(stack)-len(typ.Results):], nil
+		case op == opCall:
+			calleeType, _ := i.module.FuncType(uint32(in.imm))
+			n := len(calleeType.Params)
+			callArgs := append([]uint64(nil), stack[len(stack)-n:]...)
+			stack = stack[:len(stack)-n]
+			results, err := i.call(uint32(in.imm), callArgs)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, results...)
+		case op == opDrop:
+			stack = stack[:top]
+		case op == opSelect:
+			c := uint32(stack[top])
+			if c == 0 {
+				stack[top-2] = stack[top-1]
+			}
+			stack = stack[:top-1]
+		case op == opLocalGet:
+			stack = append(stack, locals[in.imm])
+		case op == opLocalSet:
+			locals[in.imm] = stack[top]
+			stack = stack[:top]
+		case op == opLocalTee:
+			locals[in.imm] = stack[top]
+		case op == opGlobalGet:
+			stack = append(stack, i.globals[in.imm])
+		case op == opGlobalSet:
+			i.globals[in.imm] = stack[top]
+			stack = stack[:top]
+		case op >= opI32Load && op <= opI64Load32U:
+			value, err := i.load(op, uint64(uint32(stack[top]))+in.imm)
+			if err != nil {
+				return nil, err
+			}
+			stack[top] = value
+		case op >= opI32Store && op <= opI64Store32:
+			if err := i.store(op, uint64(uint32(stack[top-1]))+in.imm, stack[top]); err != nil {
+				return nil, err
+			}
+			stack = stack[:top-1]
+		case op == opMemorySize:
+			stack = append(stack, uint64(len(i.memory)/PageSize))
+		case op == opMemoryGrow:
+			stack[top] = uint64(i.grow(uint32(stack[top])))
+		case op == opMemoryCopy:
+			dst, src, n := uint64(uint32(stack[top-2])), uint64(uint32(stack[top-1])), uint64(uint32(stack[top]))
+			stack = stack[:top-2]
+			if err := i.Consume(n / bytesPerFuel); err != nil {
+				return nil, err
+			}
+			if src+n > uint64(len(i.memory)) || dst+n > uint64(len(i.memory)) {
+				return nil, fmt.Errorf("%w: memory.copy out of bounds", ErrTrap)
+			}
+			copy(i.memory[dst:dst+n], i.memory[src:src+n])
+		case op == opMemoryFill:
+			dst, value, n := uint64(uint32(stack[top-2])), byte(stack[top-1]), uint64(uint32(stack[top]))
+			stack = stack[:top-2]
+			if err := i.Consume(n / bytesPerFuel); err != nil {
+				return nil, err
+			}
+			if dst+n > uint64(len(i.memory)) {
+				return nil, fmt.Errorf("%w: memory.fill out of bounds", ErrTrap)
+			}
+			for j := dst; j < dst+n; j++ {
+				i.memory[j] = value
+			}
+		case op == opI32Const, op == opI64Const:
+			stack = append(stack, in.imm)
+		case op == opI32Eqz:
+			stack[top] = boolToValue(uint32(stack[top]) == 0)
+		case op == opI64Eqz:
+			stack[top] = boolToValue(stack[top] == 0)
+		case op > opI32Eqz && op <= opI32GeU:
+			stack[top-1] = boolToValue(compare(op, int64(int32(stack[top-1])), int64(int32(stack[top])), uint64(uint32(stack[top-1])), uint64(uint32(stack[top]))))
+			stack = stack[:top]
+		case op > opI64Eqz && op <= opI64GeU:
+			stack[top-1] = boolToValue(compare(op-i64CompareShift, int64(stack[top-1]), int64(stack[top]), stack[top-1], stack[top]))
+			stack = stack[:top]
+		case op >= opI32Clz && op <= opI32Popcnt:
+			stack[top] = uint64(i32Unary(op, uint32(stack[top])))
+		case op >= opI64Clz && op <= opI64Clz+2:
+			stack[top] = i64Unary(op-i64ArithmeticShift, stack[top])
+		case op >= opI32Add && op <= opI32Rotr:
+			value, err := i32Binary(op, uint32(stack[top-1]), uint32(stack[top]))
+			if err != nil {
+				return nil, err
+			}
+			stack[top-1] = uint64(value)
+			stack = stack[:top]
+		case op >= opI32Add+i64ArithmeticShift && op <= opI64Rotr:
+			value, err := i64Binary(op-i64ArithmeticShift, stack[top-1], stack[top])
+			if err != nil {
+				return nil, err
+			}
+			stack[top-1] = value
+			stack = stack[:top]
+		case op == opI32WrapI64:
+			stack[top] = uint64(uint32(stack[top]))
+		case op == opI64ExtendS:
+			stack[top] = uint64(int64(int32(stack[top])))
+		case op == opI64ExtendU:
+			stack[top] = uint64(uint32(stack[top]))
+		case op == opI32Extend8S:
+			stack[top] = uint64(uint32(int32(int8(stack[top]))))
+		case op == opI32Extend16S:
+			stack[top] = uint64(uint32(int32(int16(stack[top]))))
+		case op == opI64Extend8S:
+			stack[top] = uint64(int64(int8(stack[top])))
+		case op == opI64Extend16S:
+			stack[top] = uint64(int64(int16(stack[top])))
+		case op == opI64Extend32S:
+			stack[top] = uint64(int64(int32(stack[top])))
+		default:
+			// The compiler only emits supported opcodes
+			return nil, fmt.Errorf("%w: opcode 0x%x", ErrUnsupported, op)
+		}
+	}
+	return nil, errMissingEnd
+}
+
+func (i *Instance) load(op byte, addr uint64) (uint64, error) {
+	size := uint64(loadStoreSize(op))
+	if addr+size > uint64(len(i.memory)) {
+		return 0, fmt.Errorf("%w: load of %d bytes at %d is out of bounds", ErrTrap, size, addr)
+	}
+	b := i.memory[addr : addr+size]
+	switch op {
+	case opI32Load, opI64Load32U:
+		return uint64(binary.LittleEndian.Uint32(b)), nil
+	case opI64Load:
+		return binary.LittleEndian.Uint64(b), nil
+	case opI32Load8S:
+		return uint64(uint32(int32(int8(b[0])))), nil
+	case opI32Load8U, opI64Load8U:
+		return uint64(b[0]), nil
+	case opI32Load16S:
+		return uint64(uint32(int32(int16(binary.LittleEndian.Uint16(b))))), nil
+	case opI32Load16U, opI64Load16U:
+		return uint64(binary.LittleEndian.Uint16(b)), nil
+	case opI64Load8S:
+		return uint64(int64(int8(b[0]))), nil
+	case opI64Load16S:
+		return uint64(int64(int16(binary.LittleEndian.Uint16(b)))), nil
+	default: // opI64Load32S
+		return uint64(int64(int32(binary.LittleEndian.Uint32(b)))), nil
+	}
+}
+
+func (i *Instance) store(op byte, addr uint64, value uint64) error {
+	size := uint64(loadStoreSize(op))
+	if addr+size > uint64(len(i.memory)) {
+		return fmt.Errorf("%w: store of %d bytes at %d is out of bounds", ErrTrap, size, addr)
+	}
+	b := i.memory[addr : addr+size]
+	switch size {
+	case 1:
+		b[0] = byte(value)
+	case 2:
+		binary.LittleEndian.PutUint16(b, uint16(value))
+	case 4:
+		binary.LittleEndian.PutUint32(b, uint32(value))
+	default:
+		binary.LittleEndian.PutUint64(b, value)
+	}
+	return nil
+}
+
+// loadStoreSize returns the number of bytes that a load or store accesses
+func loadStoreSize(op byte) int {
+	switch op {
+	case opI32Load8S, opI32Load8U, opI64Load8S, opI64Load8U, opI32Store8, opI64Store8:
+		return 1
+	case opI32Load16S, opI32Load16U, opI64Load16S, opI64Load16U, opI32Store16, opI64Store16:
+		return 2
+	case opI32Load, opI64Load32S, opI64Load32U, opI32Store, opI64Store32:
+		return 4
+	default:
+		return 8
+	}
+}
+
+// grow the memory by [delta] pages. Returns the previous number of pages, or
+// -1 if the memory can't grow that much.
+func (i *Instance) grow(delta uint32) uint32 {
+	pages := uint32(len(i.memory) / PageSize)
+	if uint64(pages)+uint64(delta) > uint64(i.maxPages) {
+		return math.MaxUint32
+	}
+	i.memory = append(i.memory, make([]byte, int(delta)*PageSize)...)
+	return pages
+}
+
+func boolToValue(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// compare the signed values [a] and [b] or the unsigned values [ua] and [ub]
+// with the i32 comparison [op]
+func compare(op byte, a, b int64, ua, ub uint64) bool {
+	switch op {
+	case opI32Eq:
+		return ua == ub
+	case opI32Ne:
+		return ua != ub
+	case opI32LtS:
+		return a < b
+	case opI32LtU:
+		return ua < ub
+	case opI32GtS:
+		return a > b
+	case opI32GtU:
+		return ua > ub
+	case opI32LeS:
+		return a <= b
+	case opI32LeU:
+		return ua <= ub
+	case opI32GeS:
+		return a >= b
+	default: // opI32GeU
+		return ua >= ub
+	}
+}
+
+func i32Unary(op byte, a uint32) uint32 {
+	switch op {
+	case opI32Clz:
+		return uint32(bits.LeadingZeros32(a))
+	case opI32Ctz:
+		return uint32(bits.TrailingZeros32(a))
+	default: // opI32Popcnt
+		return uint32(bits.OnesCount32(a))
+	}
+}
+
+// i64Unary applies the i32 counterpart [op] of an i64 instruction
+func i64Unary(op byte, a uint64) uint64 {
+	switch op {
+	case opI32Clz:
+		return uint64(bits.LeadingZeros64(a))
+	case opI32Ctz:
+		return uint64(bits.TrailingZeros64(a))
+	default: // opI32Popcnt
+		return uint64(bits.OnesCount64(a))
+	}
+}
+
+func i32Binary(op byte, a, b uint32) (uint32, error) {
+	switch op {
+	case opI32Add:
+		return a + b, nil
+	case opI32Sub:
+		return a - b, nil
+	case opI32Mul:
+		return a * b, nil
+	case opI32DivS:
+		if b == 0 {
+			return 0, fmt.Errorf("%w: integer divide by zero", ErrTrap)
+		}
+		if int32(a) == math.MinInt32 && int32(b) == -1 {
+			return 0, fmt.Errorf("%w: integer overflow", ErrTrap)
+		}
+		return uint32(int32(a) / int32(b)), nil
+	case opI32DivU:
+		if b == 0 {
+			return 0, fmt.Errorf("%w: integer divide by zero", ErrTrap)
+		}
+		return a / b, nil
+	case opI32RemS:
+		if b == 0 {
+			return 0, fmt.Errorf("%w: integer divide by zero", ErrTrap)
+		}
+		return uint32(int32(a) % int32(b)), nil
+	case opI32RemU:
+		if b == 0 {
+			return 0, fmt.Errorf("%w: integer divide by zero", ErrTrap)
+		}
+		return a % b, nil
+	case opI32And:
+		return a & b, nil
+	case opI32Or:
+		return a | b, nil
+	case opI32Xor:
+		return a ^ b, nil
+	case opI32Shl:
+		return a << (b % 32), nil
+	case opI32ShrS:
+		return uint32(int32(a) >> (b % 32)), nil
+	case opI32ShrU:
+		return a >> (b % 32), nil
+	case opI32Rotl:
+		return bits.RotateLeft32(a, int(b%32)), nil
+	default: // opI32Rotr
+		return bits.RotateLeft32(a, -int(b%32)), nil
+	}
+}
+
+// i64Binary applies the i32 counterpart [op] of an i64 instruction
+func i64Binary(op byte, a, b uint64) (uint64, error) {
+	switch op {
+	case opI32Add:
+		return a + b, nil
+	case opI32Sub:
+		return a - b, nil
+	case opI32Mul:
+		return a * b, nil
+	case opI32DivS:
+		if b == 0 {
+			return 0, fmt.Errorf("%w: integer divide by zero", ErrTrap)
+		}
+		if int64(a) == math.MinInt64 && int64(b) == -1 {
+			return 0, fmt.Errorf("%w: integer overflow", ErrTrap)
+		}
+		return uint64(int64(a) / int64(b)), nil
+	case opI32DivU:
+		if b == 0 {
+			return 0, fmt.Errorf("%w: integer divide by zero", ErrTrap)
+		}
+		return a / b, nil
+	case opI32RemS:
+		if b == 0 {
+			return 0, fmt.Errorf("%w: integer divide by zero", ErrTrap)
+		}
+		return uint64(int64(a) % int64(b)), nil
+	case opI32RemU:
+		if b == 0 {
+			return 0, fmt.Errorf("%w: integer divide by zero", ErrTrap)
+		}
+		return a % b, nil
+	case opI32And:
+		return a & b, nil
+	case opI32Or:
+		return a | b, nil
+	case opI32Xor:
+		return a ^ b, nil
+	case opI32Shl:
+		return a << (b % 64), nil
+	case opI32ShrS:
+		return uint64(int64(a) >> (b % 64)), nil
+	case opI32ShrU:
+		return a >> (b % 64), nil
+	case opI32Rotl:
+		return bits.RotateLeft64(a, int(b%64)), nil
+	default: // opI32Rotr
+		return bits.RotateLeft64(a, -int(b%64)), nil
+	}
+}
@@ -0,0 +1,185 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasm
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// PageSize is the number of bytes of a page of memory
+	PageSize = 1 << 16
+
+	// Number of pages that fill the 32 bit address space
+	maxPages = 1 << 16
+	// Maximum depth of nested calls
+	maxCallDepth = 512
+)
+
+var (
+	// ErrOutOfFuel is returned when a call runs out of fuel
+	ErrOutOfFuel = errors.New("out of fuel")
+	// ErrTrap is returned when a call traps, such as on a division by zero
+	// or an access out of the bounds of the memory
+	ErrTrap = errors.New("trap")
+
+	errMissingImport  = errors.New("missing import")
+	errImportType     = errors.New("import has the wrong type")
+	errMemoryTooLarge = errors.New("memory is larger than allowed")
+	errDataOutOfRange = errors.New("data segment is out of the bounds of the memory")
+	errUnknownExport  = errors.New("unknown exported function")
+	errArgs           = errors.New("wrong number of arguments")
+)
+
+// HostFunction is a function of the host that modules can import
+type HostFunction struct {
+	Type FuncType
+	// Call the function with the arguments [args], which are ordered like the
+	// parameters of [Type]. Returns the results, ordered like the results of
+	// [Type]. If an error is returned, the call of the module traps with it.
+	Call func(instance *Instance, args []uint64) ([]uint64, error)
+}
+
+// Imports maps the names of modules to the functions that they provide
+type Imports map[string]map[string]*HostFunction
+
+// Instance of a module, with its own memory and globals. An instance isn't
+// safe for concurrent use.
+type Instance struct {
+	module   *Module
+	hosts    []*HostFunction
+	memory   []byte
+	maxPages uint32
+	globals  []uint64
+	fuel     uint64
+	depth    int
+}
+
+// Instantiate [module] with the host functions [imports]. The memory of the
+// instance can grow up to [maxMemoryPages] pages. If [module] has a start
+// function, it's called with [fuel]. The fuel that is left is returned by
+// Fuel.
+func Instantiate(module *Module, imports Imports, maxMemoryPages uint32, fuel uint64) (*Instance, error) {
+	i := &Instance{
+		module: module,
+		hosts:  make([]*HostFunction, len(module.Imports)),
+		fuel:   fuel,
+	}
+	for index, imp := range module.Imports {
+		host, ok := imports[imp.Module][imp.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s.%s", errMissingImport, imp.Module, imp.Name)
+		}
+		if typ := module.Types[imp.Type]; !typ.Equal(host.Type) {
+			return nil, fmt.Errorf("%w: %s.%s is %s, not %s", errImportType, imp.Module, imp.Name, host.Type, typ)
+		}
+		i.hosts[index] = host
+	}
+
+	if module.Memory != nil {
+		i.maxPages = maxMemoryPages
+		if module.Memory.HasMax && module.Memory.Max < i.maxPages {
+			i.maxPages = module.Memory.Max
+		}
+		if module.Memory.Min > i.maxPages {
+			return nil, fmt.Errorf("%w: %d pages", errMemoryTooLarge, module.Memory.Min)
+		}
+		i.memory = make([]byte, int(module.Memory.Min)*PageSize)
+	}
+
+	i.globals = make([]uint64, len(module.Globals))
+	for index, global := range module.Globals {
+		i.globals[index] = i.eval(global.init)
+	}
+
+	for index, segment := range module.data {
+		start := uint64(uint32(i.eval(segment.offset)))
+		if start+uint64(len(segment.data)) > uint64(len(i.memory)) {
+			return nil, fmt.Errorf("%w: %d", errDataOutOfRange, index)
+		}
+		copy(i.memory[start:], segment.data)
+	}
+
+	if module.Start != nil {
+		if _, err := i.invoke(*module.Start, nil, fuel); err != nil {
+			return nil, fmt.Errorf("start function failed: %w", err)
+		}
+	}
+	return i, nil
+}
+
+// eval evaluates [expr]. The module was validated, so [expr] only refers to
+// globals that were already initialized.
+func (i *Instance) eval(expr constExpr) uint64 {
+	if expr.op != opGlobalGet {
+		return expr.value
+	}
+	return i.globals[expr.value]
+}
+
+// Invoke the exported function [name] with the arguments [args] and at most
+// [fuel] fuel. Each instruction that is run consumes a unit of fuel. Returns
+// the results of the function.
+func (i *Instance) Invoke(name string, fuel uint64, args ...uint64) ([]uint64, error) {
+	export, ok := i.module.Exports[name]
+	if !ok || export.Kind != ExternFunc {
+		return nil, fmt.Errorf("%w: %s", errUnknownExport, name)
+	}
+	return i.invoke(export.Index, args, fuel)
+}
+
+func (i *Instance) invoke(index uint32, args []uint64, fuel uint64) ([]uint64, error) {
+	typ, _ := i.module.FuncType(index)
+	if len(args) != len(typ.Params) {
+		return nil, fmt.Errorf("%w: got %d, expected %d", errArgs, len(args), len(typ.Params))
+	}
+	args = append([]uint64(nil), args...)
+	for j, t := range typ.Params {
+		if t == I32 {
+			args[j] = uint64(uint32(args[j]))
+		}
+	}
+
+	i.fuel = fuel
+	i.depth = 0
+	return i.call(index, args)
+}
+
+// HasExport returns true if the module of [i] exports a function named [name]
+func (i *Instance) HasExport(name string) bool {
+	export, ok := i.module.Exports[name]
+	return ok && export.Kind == ExternFunc
+}
+
+// Fuel returns the fuel that is left of the current or last call
+func (i *Instance) Fuel() uint64 { return i.fuel }
+
+// Consume [amount] fuel. Host functions can call this to charge for their
+// work. Returns ErrOutOfFuel if there isn't enough fuel left.
+func (i *Instance) Consume(amount uint64) error {
+	if amount > i.fuel {
+		i.fuel = 0
+		return ErrOutOfFuel
+	}
+	i.fuel -= amount
+	return nil
+}
+
+// Read returns a copy of the [size] bytes of memory at [ptr]
+func (i *Instance) Read(ptr, size uint32) ([]byte, error) {
+	if uint64(ptr)+uint64(size) > uint64(len(i.memory)) {
+		return nil, fmt.Errorf("%w: read of %d bytes at %d is out of bounds", ErrTrap, size, ptr)
+	}
+	return append([]byte(nil), i.memory[ptr:ptr+size]...), nil
+}
+
+// Write [b] to the memory at [ptr]
+func (i *Instance) Write(ptr uint32, b []byte) error {
+	if uint64(ptr)+uint64(len(b)) > uint64(len(i.memory)) {
+		return fmt.Errorf("%w: write of %d bytes at %d is out of bounds", ErrTrap, len(b), ptr)
+	}
+	copy(i.memory[ptr:], b)
+	return nil
+}
@@ -0,0 +1,636 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ValueType is the type of a value of a WebAssembly module. Only integer
+// types are supported, so that the results of modules never depend on the
+// floating point behavior of the platform.
+type ValueType byte
+
+// Supported value types
+const (
+	I32 ValueType = 0x7f
+	I64 ValueType = 0x7e
+)
+
+func (t ValueType) String() string {
+	switch t {
+	case I32:
+		return "i32"
+	case I64:
+		return "i64"
+	default:
+		return fmt.Sprintf("unknown(0x%x)", byte(t))
+	}
+}
+
+// Kinds of exports and imports
+const (
+	ExternFunc   byte = 0x00
+	ExternTable  byte = 0x01
+	ExternMemory byte = 0x02
+	ExternGlobal byte = 0x03
+)
+
+// IDs of the sections of a module
+const (
+	sectionCustom    byte = 0
+	sectionType      byte = 1
+	sectionImport    byte = 2
+	sectionFunction  byte = 3
+	sectionTable     byte = 4
+	sectionMemory    byte = 5
+	sectionGlobal    byte = 6
+	sectionExport    byte = 7
+	sectionStart     byte = 8
+	sectionElement   byte = 9
+	sectionCode      byte = 10
+	sectionData      byte = 11
+	sectionDataCount byte = 12
+)
+
+var (
+	magic   = []byte{0x00, 'a', 's', 'm'}
+	version = []byte{0x01, 0x00, 0x00, 0x00}
+
+	errBadHeader      = errors.New("not a WebAssembly module of version 1")
+	errSectionOrder   = errors.New("section is out of order")
+	errSectionSize    = errors.New("section size doesn't match its contents")
+	errMultipleMemory = errors.New("at most one memory is supported")
+	errFunctionCount  = errors.New("numbers of function declarations and bodies differ")
+	errBadConstExpr   = errors.New("unsupported constant expression")
+	errBadLimits      = errors.New("invalid limits")
+
+	// ErrUnsupported is returned when a module uses a feature that isn't
+	// supported by this interpreter
+	ErrUnsupported = errors.New("unsupported feature")
+)
+
+// FuncType is the signature of a function
+type FuncType struct {
+	Params  []ValueType
+	Results []ValueType
+}
+
+// Equal returns true if [t] and [o] are the same signature
+func (t FuncType) Equal(o FuncType) bool {
+	return bytes.Equal(valueTypeBytes(t.Params), valueTypeBytes(o.Params)) &&
+		bytes.Equal(valueTypeBytes(t.Results), valueTypeBytes(o.Results))
+}
+
+func (t FuncType) String() string {
+	return fmt.Sprintf("%v -> %v", t.Params, t.Results)
+}
+
+func valueTypeBytes(types []ValueType) []byte {
+	b := make([]byte, len(types))
+	for i, t := range types {
+		b[i] = byte(t)
+	}
+	return b
+}
+
+// Import of a host function
+type Import struct {
+	Module string
+	Name   string
+	Type   uint32
+}
+
+// Export of a function or of the memory
+type Export struct {
+	Kind  byte
+	Index uint32
+}
+
+// Limits of the size of the memory, in pages
+type Limits struct {
+	Min    uint32
+	Max    uint32
+	HasMax bool
+}
+
+// constExpr is an expression that initializes a global or gives the offset of
+// a data segment
+type constExpr struct {
+	op    byte
+	value uint64
+}
+
+// Global variable of a module
+type Global struct {
+	Type    ValueType
+	Mutable bool
+	init    constExpr
+}
+
+type dataSegment struct {
+	offset constExpr
+	data   []byte
+}
+
+type function struct {
+	typ    uint32
+	locals []ValueType
+	code   []instr
+}
+
+// Module is a decoded WebAssembly module. Modules may only import functions,
+// may have at most one memory, and may only use integer instructions.
+// Instructions that need tables, such as call_indirect, aren't supported.
+type Module struct {
+	Types   []FuncType
+	Imports []Import
+	Memory  *Limits
+	Globals []Global
+	Exports map[string]Export
+	Start   *uint32
+
+	funcs []function
+	data  []dataSegment
+}
+
+// Decode the WebAssembly module in binary format [b]
+func Decode(b []byte) (*Module, error) {
+	r := &reader{b: b}
+	header, err := r.bytes(8)
+	if err != nil || !bytes.Equal(header[:4], magic) || !bytes.Equal(header[4:], version) {
+		return nil, errBadHeader
+	}
+
+	m := &Module{Exports: make(map[string]Export)}
+	var (
+		funcTypes   []uint32
+		lastSection byte
+	)
+	for r.len() > 0 {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		contents, err := r.bytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		if id != sectionCustom {
+			// The data count section comes before the code section, although
+			// its ID is larger
+			order := id
+			switch id {
+			case sectionDataCount:
+				order = sectionCode
+			case sectionCode, sectionData:
+				order = id + 1
+			}
+			if order <= lastSection {
+				return nil, fmt.Errorf("%w: %d", errSectionOrder, id)
+			}
+			lastSection = order
+		}
+
+		s := &reader{b: contents}
+		switch id {
+		case sectionCustom, sectionTable, sectionElement, sectionDataCount:
+			// Tables are only used by call_indirect, which isn't supported,
+			// so they're ignored
+			continue
+		case sectionType:
+			err = m.decodeTypes(s)
+		case sectionImport:
+			err = m.decodeImports(s)
+		case sectionFunction:
+			funcTypes, err = s.u32s()
+		case sectionMemory:
+			err = m.decodeMemory(s)
+		case sectionGlobal:
+			err = m.decodeGlobals(s)
+		case sectionExport:
+			err = m.decodeExports(s)
+		case sectionStart:
+			var start uint32
+			start, err = s.u32()
+			m.Start = &start
+		case sectionCode:
+			err = m.decodeCode(s, funcTypes)
+		case sectionData:
+			err = m.decodeData(s)
+		default:
+			return nil, fmt.Errorf("%w: section %d", ErrUnsupported, id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("couldn't decode section %d: %w", id, err)
+		}
+		if s.len() != 0 {
+			return nil, fmt.Errorf("%w: %d", errSectionSize, id)
+		}
+	}
+	if len(funcTypes) != len(m.funcs) {
+		return nil, errFunctionCount
+	}
+	return m, m.validate()
+}
+
+// validate checks the parts of [m] that aren't checked by the compiler, so
+// that instantiating and running the module can't fail on malformed input
+func (m *Module) validate() error {
+	numFuncs := uint32(len(m.Imports) + len(m.funcs))
+	for name, export := range m.Exports {
+		switch {
+		case export.Kind == ExternFunc && export.Index >= numFuncs:
+			return fmt.Errorf("export %s of unknown function %d", name, export.Index)
+		case export.Kind == ExternMemory && (m.Memory == nil || export.Index != 0):
+			return fmt.Errorf("export %s of unknown memory %d", name, export.Index)
+		}
+	}
+	if m.Start != nil {
+		if *m.Start >= numFuncs {
+			return fmt.Errorf("unknown start function %d", *m.Start)
+		}
+		if typ, _ := m.FuncType(*m.Start); len(typ.Params) != 0 || len(typ.Results) != 0 {
+			return fmt.Errorf("%w: start function has type %s", errTypeMismatch, typ)
+		}
+	}
+	if len(m.data) > 0 && m.Memory == nil {
+		return errors.New("data segments without a memory")
+	}
+	for i, g := range m.Globals {
+		// A global can only be initialized with the globals before it
+		if err := m.validateConstExpr(g.init, g.Type, i); err != nil {
+			return fmt.Errorf("couldn't validate global %d: %w", i, err)
+		}
+	}
+	for i, segment := range m.data {
+		if err := m.validateConstExpr(segment.offset, I32, len(m.Globals)); err != nil {
+			return fmt.Errorf("couldn't validate data segment %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateConstExpr checks that [expr] has the type [typ] and only refers to
+// the first [numGlobals] globals
+func (m *Module) validateConstExpr(expr constExpr, typ ValueType, numGlobals int) error {
+	var actual ValueType
+	switch expr.op {
+	case opI32Const:
+		actual = I32
+	case opI64Const:
+		actual = I64
+	default: // opGlobalGet
+		if expr.value >= uint64(numGlobals) {
+			return fmt.Errorf("unknown global %d", expr.value)
+		}
+		actual = m.Globals[expr.value].Type
+	}
+	if actual != typ {
+		return fmt.Errorf("%w: expected %s, got %s", errTypeMismatch, typ, actual)
+	}
+	return nil
+}
+
+// FuncType returns the signature of the function with index [index], where
+// the imported functions come first
+func (m *Module) FuncType(index uint32) (FuncType, bool) {
+	if index < uint32(len(m.Imports)) {
+		return m.Types[m.Imports[index].Type], true
+	}
+	index -= uint32(len(m.Imports))
+	if index >= uint32(len(m.funcs)) {
+		return FuncType{}, false
+	}
+	return m.Types[m.funcs[index].typ], true
+}
+
+func (m *Module) decodeTypes(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		form, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if form != 0x60 {
+			return fmt.Errorf("%w: type form 0x%x", ErrUnsupported, form)
+		}
+		params, err := r.valueTypes()
+		if err != nil {
+			return err
+		}
+		results, err := r.valueTypes()
+		if err != nil {
+			return err
+		}
+		m.Types = append(m.Types, FuncType{Params: params, Results: results})
+	}
+	return nil
+}
+
+func (m *Module) decodeImports(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		module, err := r.name()
+		if err != nil {
+			return err
+		}
+		name, err := r.name()
+		if err != nil {
+			return err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if kind != ExternFunc {
+			return fmt.Errorf("%w: import %s.%s of kind %d", ErrUnsupported, module, name, kind)
+		}
+		typ, err := r.u32()
+		if err != nil {
+			return err
+		}
+		// The type section comes before the import section, so the type of
+		// the import is known
+		if typ >= uint32(len(m.Types)) {
+			return fmt.Errorf("import %s.%s has unknown type %d", module, name, typ)
+		}
+		m.Imports = append(m.Imports, Import{Module: module, Name: name, Type: typ})
+	}
+	return nil
+}
+
+func (m *Module) decodeMemory(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if count > 1 || (count == 1 && m.Memory != nil) {
+		return errMultipleMemory
+	}
+	if count == 0 {
+		return nil
+	}
+	limits, err := r.limits()
+	if err != nil {
+		return err
+	}
+	m.Memory = &limits
+	return nil
+}
+
+func (m *Module) decodeGlobals(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		typ, err := r.valueType()
+		if err != nil {
+			return err
+		}
+		mutable, err := r.byte()
+		if err != nil {
+			return err
+		}
+		init, err := r.constExpr()
+		if err != nil {
+			return err
+		}
+		m.Globals = append(m.Globals, Global{
+			Type:    typ,
+			Mutable: mutable == 1,
+			init:    init,
+		})
+	}
+	return nil
+}
+
+func (m *Module) decodeExports(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		name, err := r.name()
+		if err != nil {
+			return err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		index, err := r.u32()
+		if err != nil {
+			return err
+		}
+		// Exports of tables and globals aren't used by the host
+		if kind == ExternFunc || kind == ExternMemory {
+			m.Exports[name] = Export{Kind: kind, Index: index}
+		}
+	}
+	return nil
+}
+
+func (m *Module) decodeCode(r *reader, funcTypes []uint32) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if int(count) != len(funcTypes) {
+		return errFunctionCount
+	}
+	for _, typ := range funcTypes {
+		if typ >= uint32(len(m.Types)) {
+			return fmt.Errorf("function has unknown type %d", typ)
+		}
+	}
+	for i := uint32(0); i < count; i++ {
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		body, err := r.bytes(int(size))
+		if err != nil {
+			return err
+		}
+		f := function{typ: funcTypes[i]}
+		b := &reader{b: body}
+		numGroups, err := b.u32()
+		if err != nil {
+			return err
+		}
+		for j := uint32(0); j < numGroups; j++ {
+			n, err := b.u32()
+			if err != nil {
+				return err
+			}
+			typ, err := b.valueType()
+			if err != nil {
+				return err
+			}
+			if uint64(len(f.locals))+uint64(n) > maxLocals {
+				return fmt.Errorf("function %d has more than %d locals", i, maxLocals)
+			}
+			for k := uint32(0); k < n; k++ {
+				f.locals = append(f.locals, typ)
+			}
+		}
+		typ := m.Types[f.typ]
+		locals := append(append([]ValueType(nil), typ.Params...), f.locals...)
+		f.code, err = m.compile(b, typ, locals, funcTypes)
+		if err != nil {
+			return fmt.Errorf("couldn't compile function %d: %w", i, err)
+		}
+		m.funcs = append(m.funcs, f)
+	}
+	return nil
+}
+
+func (m *Module) decodeData(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		flags, err := r.u32()
+		if err != nil {
+			return err
+		}
+		switch flags {
+		case 0:
+		case 2:
+			memory, err := r.u32()
+			if err != nil {
+				return err
+			}
+			if memory != 0 {
+				return errMultipleMemory
+			}
+		default:
+			return fmt.Errorf("%w: passive data segments", ErrUnsupported)
+		}
+		offset, err := r.constExpr()
+		if err != nil {
+			return err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		data, err := r.bytes(int(size))
+		if err != nil {
+			return err
+		}
+		m.data = append(m.data, dataSegment{offset: offset, data: data})
+	}
+	return nil
+}
+
+func (r *reader) valueType() (ValueType, error) {
+	b, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	switch t := ValueType(b); t {
+	case I32, I64:
+		return t, nil
+	default:
+		return 0, fmt.Errorf("%w: value type 0x%x", ErrUnsupported, b)
+	}
+}
+
+func (r *reader) valueTypes() ([]ValueType, error) {
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	if int(count) > r.len() {
+		return nil, errUnexpectedEnd
+	}
+	types := make([]ValueType, count)
+	for i := range types {
+		types[i], err = r.valueType()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return types, nil
+}
+
+func (r *reader) limits() (Limits, error) {
+	flags, err := r.byte()
+	if err != nil {
+		return Limits{}, err
+	}
+	var limits Limits
+	limits.Min, err = r.u32()
+	if err != nil {
+		return Limits{}, err
+	}
+	switch flags {
+	case 0:
+	case 1:
+		limits.HasMax = true
+		limits.Max, err = r.u32()
+		if err != nil {
+			return Limits{}, err
+		}
+		if limits.Max < limits.Min {
+			return Limits{}, errBadLimits
+		}
+	default:
+		return Limits{}, fmt.Errorf("%w: limits flags 0x%x", ErrUnsupported, flags)
+	}
+	if limits.Min > maxPages || (limits.HasMax && limits.Max > maxPages) {
+		return Limits{}, errBadLimits
+	}
+	return limits, nil
+}
+
+func (r *reader) constExpr() (constExpr, error) {
+	op, err := r.byte()
+	if err != nil {
+		return constExpr{}, err
+	}
+	expr := constExpr{op: op}
+	switch op {
+	case opI32Const:
+		v, err := r.s32()
+		if err != nil {
+			return constExpr{}, err
+		}
+		expr.value = uint64(uint32(v))
+	case opI64Const:
+		v, err := r.s64()
+		if err != nil {
+			return constExpr{}, err
+		}
+		expr.value = uint64(v)
+	case opGlobalGet:
+		v, err := r.u32()
+		if err != nil {
+			return constExpr{}, err
+		}
+		expr.value = uint64(v)
+	default:
+		return constExpr{}, errBadConstExpr
+	}
+	if end, err := r.byte(); err != nil || end != opEnd {
+		return constExpr{}, errBadConstExpr
+	}
+	return expr, nil
+}
@@ -0,0 +1,148 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasm
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+var (
+	errUnexpectedEnd = errors.New("unexpected end of input")
+	errBadLEB128     = errors.New("invalid LEB128 integer")
+	errBadName       = errors.New("name isn't valid UTF-8")
+)
+
+// reader decodes the primitive values of the binary format
+type reader struct {
+	b   []byte
+	pos int
+}
+
+func (r *reader) len() int { return len(r.b) - r.pos }
+
+func (r *reader) byte() (byte, error) {
+	if r.pos >= len(r.b) {
+		return 0, errUnexpectedEnd
+	}
+	b := r.b[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > r.len() {
+		return nil, errUnexpectedEnd
+	}
+	b := r.b[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *reader) name() (string, error) {
+	size, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(int(size))
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", errBadName
+	}
+	return string(b), nil
+}
+
+// unsigned reads an unsigned LEB128 integer of at most [bits] bits
+func (r *reader) unsigned(bits uint) (uint64, error) {
+	var result uint64
+	for shift := uint(0); ; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		if shift+7 >= bits {
+			// The last byte may not have bits beyond [bits]
+			remaining := bits - shift
+			if b&0x80 != 0 || (remaining < 7 && b>>remaining != 0) {
+				return 0, errBadLEB128
+			}
+			return result | uint64(b)<<shift, nil
+		}
+		result |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return result, nil
+		}
+	}
+}
+
+// signed reads a signed LEB128 integer of at most [bits] bits
+func (r *reader) signed(bits uint) (int64, error) {
+	var result int64
+	for shift := uint(0); ; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		if shift+7 >= bits {
+			// The bits of the last byte beyond [bits] must be the sign
+			// extension of the value
+			if b&0x80 != 0 {
+				return 0, errBadLEB128
+			}
+			v := int64(int8(b<<1) >> 1)
+			if remaining := bits - shift; remaining < 7 {
+				if sign := v >> (remaining - 1); sign != 0 && sign != -1 {
+					return 0, errBadLEB128
+				}
+			}
+			return result | v<<shift, nil
+		}
+		result |= int64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			if b&0x40 != 0 {
+				result |= -1 << (shift + 7)
+			}
+			return result, nil
+		}
+	}
+}
+
+func (r *reader) u32() (uint32, error) {
+	v, err := r.unsigned(32)
+	return uint32(v), err
+}
+
+func (r *reader) s32() (int32, error) {
+	v, err := r.signed(32)
+	return int32(v), err
+}
+
+func (r *reader) s64() (int64, error) {
+	return r.signed(64)
+}
+
+// s33 reads the signed 33 bit integers of block types
+func (r *reader) s33() (int64, error) {
+	return r.signed(33)
+}
+
+func (r *reader) u32s() ([]uint32, error) {
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	if int(count) > r.len() {
+		return nil, errUnexpectedEnd
+	}
+	values := make([]uint32, count)
+	for i := range values {
+		values[i], err = r.u32()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,182 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// unknownType is the type of an operand that is popped from the stack in
+// unreachable code, which matches any type
+const unknownType ValueType = 0
+
+var (
+	errTypeMismatch   = errors.New("type mismatch")
+	errStackUnderflow = errors.New("operand stack underflow")
+	errStackHeight    = errors.New("operand stack height doesn't match the block type")
+	errIfWithoutElse  = errors.New("if without else must have matching parameters and results")
+	errBadAlignment   = errors.New("alignment is larger than the natural alignment")
+	errBadArity       = errors.New("br_table targets have different arities")
+)
+
+// ctrlFrame is a block that encloses the code being validated
+type ctrlFrame struct {
+	op              byte
+	params, results []ValueType
+	height          int
+	unreachable     bool
+}
+
+// labelTypes returns the types of the values that a branch to [f] keeps on
+// the stack
+func (f *ctrlFrame) labelTypes() []ValueType {
+	if f.op == opLoop {
+		return f.params
+	}
+	return f.results
+}
+
+// validator checks the types of the operands of a function's instructions as
+// described by the validation algorithm in the appendix of the WebAssembly
+// specification. Once a function has been validated, every instruction finds
+// the operands it expects on the stack, so the interpreter needn't check them.
+type validator struct {
+	vals  []ValueType
+	ctrls []ctrlFrame
+}
+
+func (v *validator) pushVal(t ValueType) {
+	v.vals = append(v.vals, t)
+}
+
+func (v *validator) pushVals(types []ValueType) {
+	v.vals = append(v.vals, types...)
+}
+
+func (v *validator) popVal(expected ValueType) (ValueType, error) {
+	frame := &v.ctrls[len(v.ctrls)-1]
+	if len(v.vals) == frame.height {
+		if frame.unreachable {
+			return expected, nil
+		}
+		return 0, errStackUnderflow
+	}
+	actual := v.vals[len(v.vals)-1]
+	v.vals = v.vals[:len(v.vals)-1]
+	if actual != expected && actual != unknownType && expected != unknownType {
+		return 0, fmt.Errorf("%w: expected %s, got %s", errTypeMismatch, expected, actual)
+	}
+	if actual == unknownType {
+		return expected, nil
+	}
+	return actual, nil
+}
+
+func (v *validator) popVals(types []ValueType) ([]ValueType, error) {
+	popped := make([]ValueType, len(types))
+	for i := len(types) - 1; i >= 0; i-- {
+		t, err := v.popVal(types[i])
+		if err != nil {
+			return nil, err
+		}
+		popped[i] = t
+	}
+	return popped, nil
+}
+
+func (v *validator) pushCtrl(op byte, params, results []ValueType) {
+	v.ctrls = append(v.ctrls, ctrlFrame{
+		op:      op,
+		params:  params,
+		results: results,
+		height:  len(v.vals),
+	})
+	v.pushVals(params)
+}
+
+func (v *validator) popCtrl() (ctrlFrame, error) {
+	frame := v.ctrls[len(v.ctrls)-1]
+	if _, err := v.popVals(frame.results); err != nil {
+		return ctrlFrame{}, err
+	}
+	if len(v.vals) != frame.height {
+		return ctrlFrame{}, errStackHeight
+	}
+	v.ctrls = v.ctrls[:len(v.ctrls)-1]
+	return frame, nil
+}
+
+// setUnreachable marks the rest of the current block as unreachable, so that
+// its operands may have any type
+func (v *validator) setUnreachable() {
+	frame := &v.ctrls[len(v.ctrls)-1]
+	v.vals = v.vals[:frame.height]
+	frame.unreachable = true
+}
+
+// label returns the frame that a branch [depth] blocks out targets. The
+// compiler checks that [depth] is in range.
+func (v *validator) label(depth uint32) *ctrlFrame {
+	return &v.ctrls[len(v.ctrls)-1-int(depth)]
+}
+
+// apply pops the operands [params] and pushes the results [results] of an
+// instruction
+func (v *validator) apply(params, results []ValueType) error {
+	if _, err := v.popVals(params); err != nil {
+		return err
+	}
+	v.pushVals(results)
+	return nil
+}
+
+var (
+	i32    = []ValueType{I32}
+	i64    = []ValueType{I64}
+	i32i32 = []ValueType{I32, I32}
+	i64i64 = []ValueType{I64, I64}
+)
+
+// numericSignature returns the operands and results of the numeric
+// instruction [op]
+func numericSignature(op byte) ([]ValueType, []ValueType) {
+	switch {
+	case op == opI32Eqz:
+		return i32, i32
+	case op > opI32Eqz && op <= opI32GeU:
+		return i32i32, i32
+	case op == opI64Eqz:
+		return i64, i32
+	case op > opI64Eqz && op <= opI64GeU:
+		return i64i64, i32
+	case op >= opI32Clz && op <= opI32Popcnt:
+		return i32, i32
+	case op >= opI32Add && op <= opI32Rotr:
+		return i32i32, i32
+	case op >= opI64Clz && op <= opI64Clz+2:
+		return i64, i64
+	case op >= opI32Add+i64ArithmeticShift && op <= opI64Rotr:
+		return i64i64, i64
+	case op == opI32WrapI64:
+		return i64, i32
+	case op == opI64ExtendS, op == opI64ExtendU:
+		return i32, i64
+	case op == opI32Extend8S, op == opI32Extend16S:
+		return i32, i32
+	default: // opI64Extend8S, opI64Extend16S, opI64Extend32S
+		return i64, i64
+	}
+}
+
+// loadStoreType returns the type of the value that a load or store accesses
+func loadStoreType(op byte) ValueType {
+	switch op {
+	case opI32Load, opI32Load8S, opI32Load8U, opI32Load16S, opI32Load16U,
+		opI32Store, opI32Store8, opI32Store16:
+		return I32
+	default:
+		return I64
+	}
+}
@@ -0,0 +1,469 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wasm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Helpers that encode modules in the binary format
+
+func uleb(v uint64) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if v == 0 {
+			return b
+		}
+	}
+}
+
+func sleb(v int64) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		done := (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0)
+		if !done {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if done {
+			return b
+		}
+	}
+}
+
+func cat(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+
+func vec(items ...[]byte) []byte { return cat(uleb(uint64(len(items))), cat(items...)) }
+
+func name(s string) []byte { return cat(uleb(uint64(len(s))), []byte(s)) }
+
+func section(id byte, items ...[]byte) []byte {
+	contents := vec(items...)
+	return cat([]byte{id}, uleb(uint64(len(contents))), contents)
+}
+
+func funcType(params, results []ValueType) []byte {
+	return cat([]byte{0x60}, vec(valueTypes(params)...), vec(valueTypes(results)...))
+}
+
+func valueTypes(types []ValueType) [][]byte {
+	b := make([][]byte, len(types))
+	for i, t := range types {
+		b[i] = []byte{byte(t)}
+	}
+	return b
+}
+
+func body(locals []ValueType, code ...[]byte) []byte {
+	var groups [][]byte
+	for _, t := range locals {
+		groups = append(groups, []byte{1, byte(t)})
+	}
+	b := cat(vec(groups...), cat(code...), []byte{opEnd})
+	return cat(uleb(uint64(len(b))), b)
+}
+
+func i32Const(v int32) []byte { return cat([]byte{opI32Const}, sleb(int64(v))) }
+
+func i64Const(v int64) []byte { return cat([]byte{opI64Const}, sleb(v)) }
+
+func op(code byte, immediates ...uint32) []byte {
+	b := []byte{code}
+	for _, imm := range immediates {
+		b = append(b, uleb(uint64(imm))...)
+	}
+	return b
+}
+
+// testModule has a single function of type [typ] with the code [code], which
+// is exported as "f", and a memory of one page that is exported as "memory"
+func testModule(typ []byte, locals []ValueType, code ...[]byte) []byte {
+	return cat(
+		magic,
+		version,
+		section(sectionType, typ),
+		section(sectionFunction, uleb(0)),
+		section(sectionMemory, []byte{0x00, 0x01}),
+		section(sectionExport,
+			cat(name("f"), []byte{ExternFunc}, uleb(0)),
+			cat(name("memory"), []byte{ExternMemory}, uleb(0)),
+		),
+		section(sectionCode, body(locals, code...)),
+	)
+}
+
+func instantiate(t *testing.T, b []byte, imports Imports) *Instance {
+	m, err := Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, err := Instantiate(m, imports, 16, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return i
+}
+
+func TestLoop(t *testing.T) {
+	// Sums the numbers from 1 to the argument
+	i := instantiate(t, testModule(
+		funcType([]ValueType{I32}, []ValueType{I32}),
+		[]ValueType{I32},
+		op(opBlock, 0x40),
+		op(opLoop, 0x40),
+		op(opLocalGet, 0), op(opI32Eqz), op(opBrIf, 1),
+		op(opLocalGet, 1), op(opLocalGet, 0), op(opI32Add), op(opLocalSet, 1),
+		op(opLocalGet, 0), i32Const(1), op(opI32Sub), op(opLocalSet, 0),
+		op(opBr, 0),
+		op(opEnd),
+		op(opEnd),
+		op(opLocalGet, 1),
+	), nil)
+
+	results, err := i.Invoke("f", 10000, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{5050}, results)
+
+	_, err = i.Invoke("f", 100, 100)
+	assert.Equal(t, ErrOutOfFuel, err)
+}
+
+func TestRecursion(t *testing.T) {
+	// Factorial of the argument
+	i := instantiate(t, testModule(
+		funcType([]ValueType{I64}, []ValueType{I64}),
+		nil,
+		op(opLocalGet, 0), op(opI64Eqz),
+		op(opIf, uint32(I64)),
+		i64Const(1),
+		op(opElse),
+		op(opLocalGet, 0),
+		op(opLocalGet, 0), i64Const(1), op(opI32Sub+i64ArithmeticShift),
+		op(opCall, 0),
+		op(opI32Mul+i64ArithmeticShift),
+		op(opEnd),
+	), nil)
+
+	results, err := i.Invoke("f", 1000, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2432902008176640000}, results)
+}
+
+func TestBrTable(t *testing.T) {
+	// Returns 10 for 0, 20 for 1 and 30 otherwise
+	i := instantiate(t, testModule(
+		funcType([]ValueType{I32}, []ValueType{I32}),
+		nil,
+		op(opBlock, 0x40),
+		op(opBlock, 0x40),
+		op(opBlock, 0x40),
+		op(opLocalGet, 0),
+		cat([]byte{opBrTable}, vec(uleb(0), uleb(1)), uleb(2)),
+		op(opEnd),
+		i32Const(10), op(opReturn),
+		op(opEnd),
+		i32Const(20), op(opReturn),
+		op(opEnd),
+		i32Const(30),
+	), nil)
+
+	for arg, expected := range []uint64{10, 20, 30, 30} {
+		results, err := i.Invoke("f", 1000, uint64(arg))
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{expected}, results)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	// Stores the argument at address 8 and loads it back sign extended from
+	// its lowest byte. Then grows the memory by one page.
+	i := instantiate(t, testModule(
+		funcType([]ValueType{I32}, []ValueType{I64, I32}),
+		nil,
+		i32Const(8), op(opLocalGet, 0), op(opI32Store, 2, 0),
+		i32Const(0), op(opI64Load8S, 0, 8),
+		i32Const(1), op(opMemoryGrow, 0),
+	), nil)
+
+	results, err := i.Invoke("f", 1000, 0x1ff)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{0xffffffffffffffff, 1}, results)
+	stored, err := i.Read(8, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0x1ff), binary.LittleEndian.Uint32(stored))
+	assert.Len(t, i.memory, 2*PageSize)
+
+	// Accesses out of bounds trap
+	_, err = i.Read(2*PageSize-3, 4)
+	assert.True(t, errors.Is(err, ErrTrap))
+}
+
+func TestOutOfBoundsStoreTraps(t *testing.T) {
+	i := instantiate(t, testModule(
+		funcType(nil, nil),
+		nil,
+		i32Const(PageSize-2), i32Const(1), op(opI32Store, 2, 0),
+	), nil)
+
+	_, err := i.Invoke("f", 1000)
+	assert.True(t, errors.Is(err, ErrTrap))
+}
+
+func TestDivideByZeroTraps(t *testing.T) {
+	i := instantiate(t, testModule(
+		funcType([]ValueType{I32, I32}, []ValueType{I32}),
+		nil,
+		op(opLocalGet, 0), op(opLocalGet, 1), op(opI32DivS),
+	), nil)
+
+	results, err := i.Invoke("f", 1000, uint64(uint32(0xfffffff9)), 2) // -7 / 2
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{uint64(uint32(0xfffffffd))}, results) // -3
+
+	_, err = i.Invoke("f", 1000, 1, 0)
+	assert.True(t, errors.Is(err, ErrTrap))
+}
+
+func TestHostFunction(t *testing.T) {
+	typ := FuncType{Params: []ValueType{I32, I32}, Results: []ValueType{I32}}
+	var read []byte
+	imports := Imports{"env": {"read": &HostFunction{
+		Type: typ,
+		Call: func(i *Instance, args []uint64) ([]uint64, error) {
+			var err error
+			read, err = i.Read(uint32(args[0]), uint32(args[1]))
+			return []uint64{uint64(len(read))}, err
+		},
+	}}}
+	b := cat(
+		magic,
+		version,
+		section(sectionType, funcType(typ.Params, typ.Results), funcType(nil, []ValueType{I32})),
+		section(sectionImport, cat(name("env"), name("read"), []byte{ExternFunc}, uleb(0))),
+		section(sectionFunction, uleb(1)),
+		section(sectionMemory, []byte{0x00, 0x01}),
+		section(sectionExport, cat(name("f"), []byte{ExternFunc}, uleb(1))),
+		section(sectionCode, body(nil, i32Const(16), i32Const(5), op(opCall, 0))),
+		section(sectionData, cat([]byte{0x00}, i32Const(16), []byte{opEnd}, name("hello"))),
+	)
+
+	i := instantiate(t, b, imports)
+	results, err := i.Invoke("f", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{5}, results)
+	assert.Equal(t, []byte("hello"), read)
+
+	m, err := Decode(b)
+	assert.NoError(t, err)
+	_, err = Instantiate(m, nil, 16, 1000)
+	assert.True(t, errors.Is(err, errMissingImport))
+	imports["env"]["read"].Type = FuncType{}
+	_, err = Instantiate(m, imports, 16, 1000)
+	assert.True(t, errors.Is(err, errImportType))
+}
+
+func TestUnsupported(t *testing.T) {
+	// f32.const
+	_, err := Decode(testModule(funcType(nil, nil), nil, []byte{0x43, 0, 0, 0, 0}, op(opDrop)))
+	assert.True(t, errors.Is(err, ErrUnsupported))
+
+	// f64 parameter
+	_, err = Decode(testModule(funcType([]ValueType{0x7c}, nil), nil))
+	assert.True(t, errors.Is(err, ErrUnsupported))
+}
+
+func TestInvalidCodeFailsToDecode(t *testing.T) {
+	i32Results := funcType(nil, []ValueType{I32})
+	tests := []struct {
+		name string
+		typ  []byte
+		code [][]byte
+		err  error
+	}{
+		{
+			name: "add without operands",
+			typ:  funcType(nil, nil),
+			code: [][]byte{op(opI32Add), op(opDrop)},
+			err:  errStackUnderflow,
+		},
+		{
+			name: "i32 add of i64 operands",
+			typ:  i32Results,
+			code: [][]byte{i64Const(1), i64Const(2), op(opI32Add)},
+			err:  errTypeMismatch,
+		},
+		{
+			name: "missing result",
+			typ:  i32Results,
+			code: nil,
+			err:  errStackUnderflow,
+		},
+		{
+			name: "extra result",
+			typ:  funcType(nil, nil),
+			code: [][]byte{i32Const(1)},
+			err:  errStackHeight,
+		},
+		{
+			name: "block leaves the wrong type",
+			typ:  i32Results,
+			code: [][]byte{op(opBlock, 0x7f), i64Const(1), op(opEnd)},
+			err:  errTypeMismatch,
+		},
+		{
+			name: "branch without the label's value",
+			typ:  i32Results,
+			code: [][]byte{op(opBlock, 0x7f), op(opBr, 0), op(opEnd)},
+			err:  errStackUnderflow,
+		},
+		{
+			name: "if without else that returns a value",
+			typ:  i32Results,
+			code: [][]byte{i32Const(1), op(opIf, 0x7f), i32Const(1), op(opEnd)},
+			err:  errIfWithoutElse,
+		},
+		{
+			name: "br_table targets of different arities",
+			typ:  funcType(nil, nil),
+			code: [][]byte{
+				op(opBlock, 0x40),
+				op(opBlock, 0x7f),
+				i32Const(0),
+				op(opBrTable, 1, 0, 1),
+				op(opEnd),
+				op(opDrop),
+				op(opEnd),
+			},
+			err: errBadArity,
+		},
+		{
+			name: "select of different types",
+			typ:  i32Results,
+			code: [][]byte{i32Const(1), i64Const(2), i32Const(0), op(opSelect)},
+			err:  errTypeMismatch,
+		},
+		{
+			name: "store of the wrong type",
+			typ:  funcType(nil, nil),
+			code: [][]byte{i32Const(0), i64Const(1), op(opI32Store, 2, 0)},
+			err:  errTypeMismatch,
+		},
+		{
+			name: "alignment larger than the access",
+			typ:  i32Results,
+			code: [][]byte{i32Const(0), op(opI32Load, 3, 0)},
+			err:  errBadAlignment,
+		},
+	}
+	for _, test := range tests {
+		_, err := Decode(testModule(test.typ, nil, test.code...))
+		assert.True(t, errors.Is(err, test.err), "%s: %v", test.name, err)
+	}
+}
+
+func TestUnreachableCodeIsPolymorphic(t *testing.T) {
+	// The operands of the add after the branch can have any type, as the add
+	// is never run
+	i := instantiate(t, testModule(
+		funcType(nil, []ValueType{I32}),
+		nil,
+		op(opBlock, 0x7f), i32Const(7), op(opBr, 0), op(opI32Add), op(opEnd),
+	), nil)
+	results, err := i.Invoke("f", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{7}, results)
+}
+
+func TestInvalidModuleFailsToDecode(t *testing.T) {
+	// i64 global initialized with an i32
+	_, err := Decode(cat(
+		magic,
+		version,
+		section(sectionGlobal, cat([]byte{byte(I64), 0}, i32Const(1), []byte{opEnd})),
+	))
+	assert.True(t, errors.Is(err, errTypeMismatch))
+
+	// Start function with parameters
+	_, err = Decode(cat(
+		magic,
+		version,
+		section(sectionType, funcType([]ValueType{I32}, nil)),
+		section(sectionFunction, uleb(0)),
+		[]byte{sectionStart, 1, 0},
+		section(sectionCode, body(nil)),
+	))
+	assert.True(t, errors.Is(err, errTypeMismatch))
+}
+
+func TestHostErrorIsReturned(t *testing.T) {
+	errHost := errors.New("host error")
+	typ := FuncType{}
+	imports := Imports{"env": {"fail": &HostFunction{
+		Type: typ,
+		Call: func(*Instance, []uint64) ([]uint64, error) { return nil, errHost },
+	}}}
+	b := cat(
+		magic,
+		version,
+		section(sectionType, funcType(nil, nil)),
+		section(sectionImport, cat(name("env"), name("fail"), []byte{ExternFunc}, uleb(0))),
+		section(sectionFunction, uleb(0)),
+		section(sectionExport, cat(name("f"), []byte{ExternFunc}, uleb(1))),
+		section(sectionCode, body(nil, op(opCall, 0))),
+	)
+	i := instantiate(t, b, imports)
+	_, err := i.Invoke("f", 1000)
+	assert.Equal(t, errHost, err)
+}
+
+func TestLEB128(t *testing.T) {
+	tests := []struct {
+		b        []byte
+		bits     uint
+		signed   bool
+		expected int64
+		err      error
+	}{
+		{b: []byte{0xe5, 0x8e, 0x26}, bits: 32, expected: 624485},
+		{b: []byte{0xc0, 0xbb, 0x78}, bits: 32, signed: true, expected: -123456},
+		{b: []byte{0x7f}, bits: 33, signed: true, expected: -1},
+		{b: []byte{0xff, 0xff, 0xff, 0xff, 0x0f}, bits: 32, expected: 0xffffffff},
+		{b: []byte{0xff, 0xff, 0xff, 0xff, 0x1f}, bits: 32, err: errBadLEB128},
+		{b: []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, bits: 32, err: errBadLEB128},
+		{b: []byte{0xff, 0xff, 0xff, 0xff, 0x7f}, bits: 32, signed: true, expected: -1},
+		{b: []byte{0xff, 0xff, 0xff, 0xff, 0x4f}, bits: 32, signed: true, err: errBadLEB128},
+		{b: []byte{0x80}, bits: 32, err: errUnexpectedEnd},
+	}
+	for _, test := range tests {
+		r := &reader{b: test.b}
+		var (
+			value int64
+			err   error
+		)
+		if test.signed {
+			value, err = r.signed(test.bits)
+		} else {
+			var u uint64
+			u, err = r.unsigned(test.bits)
+			value = int64(u)
+		}
+		assert.Equal(t, test.err, err, "%x", test.b)
+		if test.err == nil {
+			assert.Equal(t, test.expected, value, "%x", test.b)
+		}
+	}
+}